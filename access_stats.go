@@ -0,0 +1,467 @@
+/*
+Access Log Statistics Module
+============================
+
+개인정보 보호가 필요한 사이트를 위한 웹 접근 로그 통계 전용 모드 (-access-stats)
+
+웹 접근 로그(Apache/Nginx) 줄은 요청 수, 상태 코드 분포, 응답 시간, 전송량 같은 집계만 남기고
+원본 줄은 출력, 분석, 저장, 알림 어디로도 넘기지 않습니다. 웹 접근 로그가 아닌 줄(syslog, DB 로그 등)은 평소대로 처리합니다.
+
+주요 기능:
+  - 윈도우(기본 5분, 시각 경계 기준)별 집계: 요청 수, 상태 코드 클래스(1xx-5xx), 메서드, 전송량,
+    응답 시간 분포 (p50/p95/p99 추정, 응답 시간이 기록된 요청만), 공격 패턴별 일치 횟수
+  - 오류율 알림: 요청이 100건 이상인 윈도우의 5xx 비율이 -access-error-rate (기본 5%) 이상이면 warning,
+    임계값의 2배 이상이면 critical, 이후 임계값 아래로 내려가면 복구 알림
+  - 공격 패턴 알림: SQL 인젝션, XSS, 경로 탐색, 민감 파일 탐색, 스캐너 User-Agent 일치 횟수가
+    윈도우에 -access-attack-threshold (기본 10) 이상이면 warning (패턴 이름과 횟수만)
+  - 조회: 관리 API GET /access-stats (진행 중인 윈도우, 최근 12개 윈도우, 시작 후 누적), GET /metrics (syslog_monitor_access_*)
+
+URL 과 User-Agent 는 공격 패턴 검사에만 쓰고 버리며, 클라이언트 IP 와 요청 경로는 집계에도 남기지 않습니다.
+응답 크기 이상(-exfil-watch), SLO, SQL 인젝션 확인, ModSecurity 상관 분석, AI 분석은 웹 접근 로그를 받지 않습니다.
+*/
+package main
+
+import (
+	"context" // 종료 신호
+	"fmt"     // 형식화된 I/O
+	"net/url" // 요청 URL 디코딩
+	"os"      // 호스트 이름
+	"regexp"  // 공격 패턴
+	"sort"    // 패턴/메서드 정렬
+	"strconv" // 알림 필드
+	"strings" // 문자열 처리
+	"sync"    // 동기화 (뮤텍스)
+	"time"    // 윈도우 처리
+)
+
+// 접근 로그 통계 기본값
+const (
+	DefaultAccessStatsWindow     = 5   // 집계 윈도우 (분)
+	DefaultAccessErrorRate       = 5.0 // 5xx 오류율 알림 임계값 (%)
+	DefaultAccessAttackThreshold = 10  // 윈도우당 공격 패턴 일치 횟수 알림 임계값
+	accessStatsMinRequests       = 100 // 오류율을 판정할 최소 요청 수
+	accessStatsHistory           = 12  // 보관할 지난 윈도우 수
+	maxAccessMethods             = 16  // 따로 집계할 메서드 종류 수 (나머지는 OTHER)
+)
+
+// 접근 로그 통계 알림 종류
+const (
+	AccessEventErrorRate = "error_rate"
+	AccessEventResolved  = "resolved"
+	AccessEventAttack    = "attack"
+)
+
+// accessAttackPattern 웹 공격 패턴 (디코딩한 URL 또는 User-Agent 에 적용)
+type accessAttackPattern struct {
+	Name      string
+	Pattern   *regexp.Regexp
+	UserAgent bool // User-Agent 에 적용 (false 면 URL)
+}
+
+// accessAttackPatterns 집계할 공격 패턴
+var accessAttackPatterns = []accessAttackPattern{
+	{Name: "sql_injection", Pattern: regexp.MustCompile(`(?i)(union(\s|/\*.*?\*/)+(all\s+)?select|\bor\s+1\s*=\s*1|'\s*or\s*'[^']*'\s*=\s*'|\b(sleep|benchmark|pg_sleep)\s*\(|information_schema|;\s*drop\s+table)`)},
+	{Name: "xss", Pattern: regexp.MustCompile(`(?i)(<\s*script|javascript:|\bon(error|load|mouseover)\s*=|<\s*(iframe|svg|img)[^>]*\bon\w+\s*=)`)},
+	{Name: "path_traversal", Pattern: regexp.MustCompile(`(?i)(\.\./|\.\.\\|/etc/(passwd|shadow)|\bwin\.ini\b|/proc/self/)`)},
+	{Name: "sensitive_file", Pattern: regexp.MustCompile(`(?i)/(\.env|\.git/|\.svn/|\.aws/|\.ssh/|wp-config\.php|phpmyadmin|server-status|\.htpasswd)`)},
+	{Name: "scanner", Pattern: regexp.MustCompile(`(?i)(sqlmap|nikto|nmap|masscan|zgrab|acunetix|nessus|wpscan|dirbuster|gobuster|nuclei)`), UserAgent: true},
+}
+
+// accessWindow 집계 중인 윈도우 (원본 줄, URL, 클라이언트 정보는 보관하지 않음)
+type accessWindow struct {
+	start    time.Time
+	requests int64
+	status   map[string]int64 // "2xx" → 요청 수
+	methods  map[string]int64
+	bytes    int64
+	latency  *latencyHistogram // 응답 시간 (초)
+	attacks  map[string]int64  // 공격 패턴 이름 → 일치 횟수
+}
+
+func newAccessWindow(start time.Time) *accessWindow {
+	return &accessWindow{
+		start:   start,
+		status:  make(map[string]int64),
+		methods: make(map[string]int64),
+		latency: newLatencyHistogram(),
+		attacks: make(map[string]int64),
+	}
+}
+
+// AccessWindowStats 윈도우 집계 결과
+type AccessWindowStats struct {
+	Start        time.Time        `json:"start"`
+	End          time.Time        `json:"end"`
+	Requests     int64            `json:"requests"`
+	Status       map[string]int64 `json:"status"`
+	Methods      map[string]int64 `json:"methods"`
+	Bytes        int64            `json:"bytes"`
+	ErrorRate    float64          `json:"error_rate"`     // 5xx 비율 (%)
+	TimedCount   uint64           `json:"timed_requests"` // 응답 시간이 기록된 요청 수
+	LatencyAvgMS float64          `json:"latency_avg_ms"` // 평균 응답 시간
+	LatencyP50MS float64          `json:"latency_p50_ms"` // 히스토그램 버킷 상한으로 추정
+	LatencyP95MS float64          `json:"latency_p95_ms"`
+	LatencyP99MS float64          `json:"latency_p99_ms"`
+	Attacks      map[string]int64 `json:"attacks,omitempty"`
+	AttackCount  int64            `json:"attack_count"`
+}
+
+// stats 윈도우 집계 결과 (end 는 윈도우 끝 또는 현재 시각)
+func (w *accessWindow) stats(end time.Time) AccessWindowStats {
+	stats := AccessWindowStats{
+		Start:      w.start,
+		End:        end,
+		Requests:   w.requests,
+		Status:     copyCounts(w.status),
+		Methods:    copyCounts(w.methods),
+		Bytes:      w.bytes,
+		TimedCount: w.latency.count,
+		Attacks:    copyCounts(w.attacks),
+	}
+	if w.requests > 0 {
+		stats.ErrorRate = float64(w.status["5xx"]) * 100 / float64(w.requests)
+	}
+	if w.latency.count > 0 {
+		stats.LatencyAvgMS = w.latency.sum * 1000 / float64(w.latency.count)
+		stats.LatencyP50MS = w.latency.quantile(0.50) * 1000
+		stats.LatencyP95MS = w.latency.quantile(0.95) * 1000
+		stats.LatencyP99MS = w.latency.quantile(0.99) * 1000
+	}
+	for _, count := range w.attacks {
+		stats.AttackCount += count
+	}
+	return stats
+}
+
+// copyCounts 집계 맵 복사
+func copyCounts(counts map[string]int64) map[string]int64 {
+	copied := make(map[string]int64, len(counts))
+	for key, count := range counts {
+		copied[key] = count
+	}
+	return copied
+}
+
+// AccessStatsEvent 윈도우를 닫으며 판정한 알림
+type AccessStatsEvent struct {
+	Kind      string            // error_rate, resolved, attack
+	Severity  string            // 알림 심각도
+	Window    AccessWindowStats // 닫은 윈도우
+	Threshold float64           // 오류율 임계값 (%) 또는 공격 횟수 임계값
+}
+
+// AccessStatsSnapshot 관리 API 응답
+type AccessStatsSnapshot struct {
+	Window          string              `json:"window"`
+	ErrorRate       float64             `json:"error_rate_threshold"`
+	AttackThreshold int                 `json:"attack_threshold"`
+	Erroring        bool                `json:"erroring"` // 오류율 알림 진행 중
+	Current         AccessWindowStats   `json:"current"`
+	Recent          []AccessWindowStats `json:"recent"` // 최근 윈도우 (최신 순)
+	Total           AccessWindowStats   `json:"total"`  // 시작 후 누적
+}
+
+// AccessStats 웹 접근 로그 통계 집계기
+type AccessStats struct {
+	mutex           sync.Mutex
+	window          time.Duration
+	errorRate       float64
+	attackThreshold int
+	current         *accessWindow
+	total           *accessWindow
+	history         []AccessWindowStats // 오래된 순
+	erroring        bool
+}
+
+// NewAccessStats 윈도우(분), 오류율 임계값(%), 공격 횟수 임계값으로 집계기 생성 (0 이하 값은 기본값 사용)
+func NewAccessStats(windowMinutes int, errorRate float64, attackThreshold int) *AccessStats {
+	if windowMinutes <= 0 {
+		windowMinutes = DefaultAccessStatsWindow
+	}
+	if errorRate <= 0 {
+		errorRate = DefaultAccessErrorRate
+	}
+	if attackThreshold <= 0 {
+		attackThreshold = DefaultAccessAttackThreshold
+	}
+	window := time.Duration(windowMinutes) * time.Minute
+	now := time.Now()
+	return &AccessStats{
+		window:          window,
+		errorRate:       errorRate,
+		attackThreshold: attackThreshold,
+		current:         newAccessWindow(now.Truncate(window)),
+		total:           newAccessWindow(now),
+	}
+}
+
+// Window 집계 윈도우
+func (as *AccessStats) Window() time.Duration {
+	return as.window
+}
+
+// isAccessLog 상태 코드가 있는 웹 접근 로그인지 (Apache/Nginx 접근 로그, JSON/사용자 정의 log_format 포함)
+func isAccessLog(parsedLog *ParsedLog) bool {
+	return parsedLog != nil && parsedLog.HTTPDetails != nil && parsedLog.HTTPDetails.StatusCode > 0
+}
+
+// Observe 파싱 결과가 웹 접근 로그이면 집계하고 true 반환 (호출자는 원본 줄을 더 처리하지 않음)
+func (as *AccessStats) Observe(parsedLog *ParsedLog) bool {
+	if !isAccessLog(parsedLog) {
+		return false
+	}
+	http := parsedLog.HTTPDetails
+
+	// 공격 패턴은 집계 전에 확인하고 URL/User-Agent 는 보관하지 않음
+	target := http.URL
+	if decoded, err := url.QueryUnescape(target); err == nil {
+		target = decoded
+	}
+	var attacks []string
+	for _, pattern := range accessAttackPatterns {
+		text := target
+		if pattern.UserAgent {
+			text = http.UserAgent
+		}
+		if text != "" && pattern.Pattern.MatchString(text) {
+			attacks = append(attacks, pattern.Name)
+		}
+	}
+
+	status := fmt.Sprintf("%dxx", http.StatusCode/100)
+	if http.StatusCode < 100 || http.StatusCode > 599 {
+		status = "other"
+	}
+	method := strings.ToUpper(http.Method)
+	if method == "" {
+		method = "-"
+	}
+
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	for _, w := range []*accessWindow{as.current, as.total} {
+		w.requests++
+		w.status[status]++
+		if _, ok := w.methods[method]; ok || len(w.methods) < maxAccessMethods {
+			w.methods[method]++
+		} else {
+			w.methods["OTHER"]++
+		}
+		if http.ResponseSize > 0 {
+			w.bytes += http.ResponseSize
+		}
+		if http.ResponseTime > 0 {
+			w.latency.observe(float64(http.ResponseTime) / 1000)
+		}
+		for _, name := range attacks {
+			w.attacks[name]++
+		}
+	}
+	return true
+}
+
+// Start 윈도우 경계마다 집계를 닫고 알림 판정 결과를 handler 로 전달 (ctx 가 끝나면 중단)
+func (as *AccessStats) Start(ctx context.Context, handler func(*AccessStatsEvent)) {
+	go func() {
+		for {
+			now := time.Now()
+			timer := time.NewTimer(now.Truncate(as.window).Add(as.window).Sub(now))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case now := <-timer.C:
+				for _, event := range as.Close(now) {
+					handler(event)
+				}
+			}
+		}
+	}()
+}
+
+// Close 진행 중인 윈도우를 닫고 새 윈도우 시작, 오류율/공격 패턴 알림 판정
+func (as *AccessStats) Close(now time.Time) []*AccessStatsEvent {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	end := now.Truncate(as.window)
+	if !end.After(as.current.start) {
+		end = now
+	}
+	closed := as.current.stats(end)
+	as.current = newAccessWindow(end)
+	as.history = append(as.history, closed)
+	if len(as.history) > accessStatsHistory {
+		as.history = as.history[len(as.history)-accessStatsHistory:]
+	}
+
+	var events []*AccessStatsEvent
+	switch {
+	case closed.Requests >= accessStatsMinRequests && closed.ErrorRate >= as.errorRate:
+		severity := AlertSeverityWarning
+		if closed.ErrorRate >= as.errorRate*2 {
+			severity = AlertSeverityCritical
+		}
+		as.erroring = true
+		events = append(events, &AccessStatsEvent{Kind: AccessEventErrorRate, Severity: severity, Window: closed, Threshold: as.errorRate})
+	case as.erroring && (closed.Requests >= accessStatsMinRequests || closed.Status["5xx"] == 0):
+		// 요청이 적은 윈도우는 5xx 가 없을 때만 복구로 봄
+		as.erroring = false
+		events = append(events, &AccessStatsEvent{Kind: AccessEventResolved, Severity: AlertSeverityInfo, Window: closed, Threshold: as.errorRate})
+	}
+	if closed.AttackCount >= int64(as.attackThreshold) {
+		events = append(events, &AccessStatsEvent{Kind: AccessEventAttack, Severity: AlertSeverityWarning, Window: closed, Threshold: float64(as.attackThreshold)})
+	}
+	return events
+}
+
+// Snapshot 진행 중인 윈도우, 최근 윈도우, 누적 집계
+func (as *AccessStats) Snapshot(now time.Time) AccessStatsSnapshot {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	recent := make([]AccessWindowStats, 0, len(as.history))
+	for i := len(as.history) - 1; i >= 0; i-- {
+		recent = append(recent, as.history[i])
+	}
+	return AccessStatsSnapshot{
+		Window:          as.window.String(),
+		ErrorRate:       as.errorRate,
+		AttackThreshold: as.attackThreshold,
+		Erroring:        as.erroring,
+		Current:         as.current.stats(now),
+		Recent:          recent,
+		Total:           as.total.stats(now),
+	}
+}
+
+// Metrics Prometheus 메트릭용 누적 집계와 응답 시간 히스토그램
+func (as *AccessStats) Metrics() (AccessWindowStats, LatencyHistogramSnapshot) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	now := time.Now()
+	return as.total.stats(now), as.total.latency.snapshot()
+}
+
+// sortedCounts "이름 횟수" 목록 (횟수 많은 순)
+func sortedCounts(counts map[string]int64) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d", name, counts[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// accessStatsAlert 오류율/공격 패턴 알림 (집계 값만, 원본 줄/URL/클라이언트 없음)
+func accessStatsAlert(event *AccessStatsEvent) Alert {
+	host, _ := os.Hostname()
+	window := event.Window
+	period := fmt.Sprintf("%s - %s", window.Start.Format("2006-01-02 15:04"), window.End.Format("15:04"))
+
+	fields := []AlertField{
+		{Label: "윈도우", Value: period, Short: true},
+		{Label: "요청 수", Value: strconv.FormatInt(window.Requests, 10), Short: true},
+		{Label: "5xx 오류율", Value: fmt.Sprintf("%.2f%% (%d건)", window.ErrorRate, window.Status["5xx"]), Short: true},
+		{Label: "상태 코드", Value: sortedCounts(window.Status), Short: true},
+	}
+	if window.TimedCount > 0 {
+		fields = append(fields, AlertField{Label: "응답 시간", Value: fmt.Sprintf("평균 %.0fms, p95 %.0fms, p99 %.0fms", window.LatencyAvgMS, window.LatencyP95MS, window.LatencyP99MS), Short: true})
+	}
+
+	alertType, title, headline := AlertTypeAccessErrorRate, "", ""
+	switch event.Kind {
+	case AccessEventErrorRate:
+		title = fmt.Sprintf("[%s ACCESS ERROR RATE] %s - %.1f%% 5xx", AppName, host, window.ErrorRate)
+		headline = fmt.Sprintf("🔥 웹 요청의 %.1f%% 가 5xx 오류로 끝났습니다 (임계값 %g%%)", window.ErrorRate, event.Threshold)
+		fields = append(fields, AlertField{Label: "임계값", Value: fmt.Sprintf("%g%% (최소 %d건)", event.Threshold, accessStatsMinRequests), Short: true})
+	case AccessEventResolved:
+		title = fmt.Sprintf("[%s ACCESS ERROR RATE RESOLVED] %s - %.1f%% 5xx", AppName, host, window.ErrorRate)
+		headline = fmt.Sprintf("✅ 웹 요청 5xx 오류율이 %.1f%% 로 내려갔습니다 (임계값 %g%%)", window.ErrorRate, event.Threshold)
+	case AccessEventAttack:
+		alertType = AlertTypeWebAttack
+		title = fmt.Sprintf("[%s ACCESS ATTACK PATTERNS] %s - %d requests", AppName, host, window.AttackCount)
+		headline = fmt.Sprintf("🛡️ %s 동안 공격 패턴이 일치한 웹 요청이 %d건 있었습니다", period, window.AttackCount)
+		fields = append(fields,
+			AlertField{Label: "공격 패턴", Value: sortedCounts(window.Attacks)},
+			AlertField{Label: "임계값", Value: fmt.Sprintf("윈도우당 %g건", event.Threshold), Short: true},
+		)
+	}
+	fields = append(fields, AlertField{Label: "개인정보 보호", Value: "접근 로그 통계 모드 (-access-stats): 원본 요청, URL, 클라이언트 IP 는 기록하지 않습니다"})
+
+	alertFields := map[string]string{
+		"kind":       event.Kind,
+		"requests":   strconv.FormatInt(window.Requests, 10),
+		"error_rate": strconv.FormatFloat(window.ErrorRate, 'f', 2, 64),
+		"window":     period,
+	}
+	for name, count := range window.Attacks {
+		alertFields["attack_"+name] = strconv.FormatInt(count, 10)
+	}
+
+	return Alert{
+		Type:     alertType,
+		Severity: event.Severity,
+		Title:    title,
+		Headline: headline,
+		Sections: []AlertSection{{Fields: fields, Summary: true}},
+		Host:     host,
+		Thread:   alertThreadKey(alertType, host, "access-stats"),
+		Fields:   alertFields,
+	}
+}
+
+// TenantAccessStats 테넌트별 접근 로그 누적 통계 (운영자 모니터는 Tenant 가 빈 값)
+type TenantAccessStats struct {
+	Tenant  string
+	Stats   AccessWindowStats
+	Latency LatencyHistogramSnapshot
+}
+
+// WriteAccessStatsMetrics 접근 로그 통계 Prometheus 메트릭 (시작 후 누적)
+func WriteAccessStatsMetrics(builder *strings.Builder, scopes []TenantAccessStats) {
+	if len(scopes) == 0 {
+		return
+	}
+
+	builder.WriteString("# HELP syslog_monitor_access_requests_total Web access log requests by status class (access statistics mode).\n")
+	builder.WriteString("# TYPE syslog_monitor_access_requests_total counter\n")
+	for _, scope := range scopes {
+		classes := make([]string, 0, len(scope.Stats.Status))
+		for class := range scope.Stats.Status {
+			classes = append(classes, class)
+		}
+		sort.Strings(classes)
+		for _, class := range classes {
+			fmt.Fprintf(builder, "syslog_monitor_access_requests_total%s %d\n", prometheusLabels(scope.Tenant, "status", class), scope.Stats.Status[class])
+		}
+	}
+
+	builder.WriteString("# HELP syslog_monitor_access_response_bytes_total Response bytes sent (access statistics mode).\n")
+	builder.WriteString("# TYPE syslog_monitor_access_response_bytes_total counter\n")
+	for _, scope := range scopes {
+		fmt.Fprintf(builder, "syslog_monitor_access_response_bytes_total%s %d\n", prometheusLabels(scope.Tenant), scope.Stats.Bytes)
+	}
+
+	builder.WriteString("# HELP syslog_monitor_access_attacks_total Web requests matching an attack pattern (access statistics mode).\n")
+	builder.WriteString("# TYPE syslog_monitor_access_attacks_total counter\n")
+	for _, scope := range scopes {
+		for _, pattern := range accessAttackPatterns {
+			fmt.Fprintf(builder, "syslog_monitor_access_attacks_total%s %d\n", prometheusLabels(scope.Tenant, "pattern", pattern.Name), scope.Stats.Attacks[pattern.Name])
+		}
+	}
+
+	builder.WriteString("# HELP syslog_monitor_access_response_seconds Response time of web requests that log it (access statistics mode).\n")
+	builder.WriteString("# TYPE syslog_monitor_access_response_seconds histogram\n")
+	for _, scope := range scopes {
+		writeLatencyHistogram(builder, "syslog_monitor_access_response_seconds", scope.Latency, scope.Tenant)
+	}
+}
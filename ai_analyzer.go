@@ -34,6 +34,7 @@ import (
 	"net/http"      // HTTP 클라이언트
 	"encoding/json" // JSON 인코딩/디코딩
 	"io"            // I/O 원시 기능
+	"sync"          // 로그 버퍼 보호
 )
 
 // AIAnalyzer AI 기반 로그 분석 및 이상 탐지 엔진
@@ -45,6 +46,10 @@ type AIAnalyzer struct {
 	maxBufferSize   int              // 버퍼 최대 크기 (메모리 사용량 제한, 기본 1000개)
 	alertThreshold  float64          // 알림 임계값 (이상 점수가 이 값 이상이면 알림 발송)
 	baselineMetrics BaselineMetrics  // 동적으로 학습되는 정상 상태 기준선 메트릭
+	bufferMutex     sync.Mutex       // 분석 워커가 동시에 AnalyzeLog 를 호출할 때 logBuffer 보호
+	geoMapper       *GeoMapper       // ASN 조회 캐시 (nil 이면 IP마다 직접 조회)
+	model           *AnomalyModel    // ONNX 이상 점수 모델 (nil 이면 규칙 기반 점수만 사용)
+	baselines       *LogBaselines    // 서비스별 로그 볼륨/에러율/고유 IP 통계 기준선
 }
 
 // LogEntry 개별 로그 항목을 나타내는 구조체
@@ -106,6 +111,22 @@ type AIAnalysisResult struct {
 	Timestamp       time.Time
 	SystemInfo      SystemInfo  // 시스템 정보 추가
 	ExpertDiagnosis ExpertDiagnosis // 전문가 진단 결과
+	MatchedRules    []MatchedRule   // 일치한 이상 패턴 규칙 (심각도 높은 순)
+	Model           string          // 점수에 반영한 ONNX 모델 이름 (없으면 빈 문자열)
+	ModelScore      float64         // 모델 이상 점수 (0-10)
+	RuleScore       float64         // 모델 점수와 결합하기 전 규칙/빈도/시간 기반 점수
+	BaselineScore   float64           // 서비스 통계 기준선 이탈 점수 (0-10)
+	Baseline        []BaselineAnomaly // 이 로그 서비스의 진행 중인 기준선 이탈
+	BaselineEvents  []BaselineAnomaly // 이 로그에서 윈도우가 닫히며 새로 시작된 기준선 이탈 (모든 서비스, 알림 대상)
+}
+
+// MatchedRule 로그와 일치한 이상 패턴 규칙
+type MatchedRule struct {
+	Name        string  `json:"name"`
+	Severity    float64 `json:"severity"`
+	Category    string  `json:"category"`
+	Action      string  `json:"action"`
+	Description string  `json:"description"`
 }
 
 // Prediction 예측 결과
@@ -164,11 +185,12 @@ type ExpertDiagnosis struct {
 	CriticalIssues  []string                 // 긴급 이슈 목록
 	MaintenanceTips []string                 // 유지보수 팁
 	PerformanceScore float64                 // 성능 점수 (0-100)
+	PromptVersion   string                   // LLM 진단에 사용한 프롬프트 버전 (내장 규칙 진단은 빈 문자열)
 }
 
-// NewAIAnalyzer AI 분석기 생성
-func NewAIAnalyzer() *AIAnalyzer {
-	patterns := []AnomalyPattern{
+// defaultAnomalyPatterns 내장 이상 패턴 (규칙 파일에서 이름으로 비활성화/덮어쓰기 가능)
+func defaultAnomalyPatterns() []AnomalyPattern {
+	return []AnomalyPattern{
 		{
 			Name:        "SQL_Injection_Attempt",
 			Pattern:     regexp.MustCompile(`(?i)(union\s+select|or\s+1=1|drop\s+table|insert\s+into|delete\s+from|\'\s+or\s+\'\w+=\'\w+)`),
@@ -225,10 +247,42 @@ func NewAIAnalyzer() *AIAnalyzer {
 			Category:    "Security",
 			Action:      "immediate_alert",
 		},
+		{
+			// auditd SYSCALL: 일반 사용자(uid≠0)가 실행한 프로세스가 실효 root(euid=0)로 권한 관련 감사 규칙(key)에 걸린 경우
+			Name:        "Auditd_Privilege_Escalation",
+			Pattern:     regexp.MustCompile(`type=SYSCALL\b.*\bsuccess=yes\b.*\buid=[1-9]\d*\b.*\beuid=0\b.*\bkey="?(?:priv_esc|privilege_escalation|privileged|power_abuse|rootcmd|setuid|setgid|actions)`),
+			Severity:    9.5,
+			Description: "감사 로그 권한 상승 (일반 사용자 → root)",
+			Category:    "Security",
+			Action:      "immediate_alert",
+		},
+		{
+			// auditd EXECVE: chmod u+s / 4755 등 setuid/setgid 비트 설정
+			Name:        "Auditd_Setuid_Chmod",
+			Pattern:     regexp.MustCompile(`type=EXECVE\b.*\ba0="(?:/usr)?(?:/bin/)?chmod".*\ba\d+="(?:[ugoa]*\+[rwxt]*s[rwxt]*|[2-7][0-7]{3})"`),
+			Severity:    9.0,
+			Description: "setuid/setgid 비트 설정",
+			Category:    "Security",
+			Action:      "immediate_alert",
+		},
+		{
+			// auditd USER_LOGIN: root 계정 직접 로그인 성공
+			Name:        "Auditd_Root_Login",
+			Pattern:     regexp.MustCompile(`type=USER_LOGIN\b.*\b(?:acct="root"|id=0\b).*\bres=success`),
+			Severity:    8.5,
+			Description: "감사 로그 root 직접 로그인",
+			Category:    "Security",
+			Action:      "immediate_alert",
+		},
 	}
+}
 
+// NewAIAnalyzer AI 분석기 생성
+func NewAIAnalyzer() *AIAnalyzer {
+	baselines, _ := NewLogBaselines(LogBaselineConfig{}) // 기본 설정은 항상 유효
 	return &AIAnalyzer{
-		patterns:       patterns,
+		baselines:      baselines,
+		patterns:       defaultAnomalyPatterns(),
 		timeWindow:     time.Minute * 5,
 		maxBufferSize:  1000,
 		alertThreshold: 7.0,
@@ -248,18 +302,35 @@ func (ai *AIAnalyzer) AnalyzeLog(logLine string, parsed map[string]string) *AIAn
 	// 로그 항목 생성
 	entry := ai.createLogEntry(logLine, parsed)
 	
+	// 특성 추출 (외부 ASN 조회를 포함하고 버퍼를 쓰지 않으므로 잠금 없이 수행)
+	features := ai.extractFeatures(entry)
+	
+	ai.bufferMutex.Lock()
 	// 버퍼에 추가
 	ai.addToBuffer(entry)
-	
-	// 특성 추출
-	features := ai.extractFeatures(entry)
 	entry.Features = features
 	
 	// 이상 패턴 감지
-	anomalyScore := ai.detectAnomalies(entry)
+	anomalyScore, matchedRules := ai.detectAnomalies(entry)
 	
 	// 예측 수행
 	predictions := ai.makePredictions(entry, features)
+	ai.bufferMutex.Unlock()
+	
+	// 서비스별 통계 기준선 (볼륨/에러율/고유 IP z-점수)
+	baselineScore, baseline, baselineEvents := ai.baselines.Observe(entry.Service, entry.Level, features.IPAddresses, entry.Timestamp)
+	anomalyScore = math.Max(anomalyScore, baselineScore)
+	
+	// ONNX 모델 점수와 결합 (추론 실패 시 규칙 점수 유지)
+	ruleScore := anomalyScore
+	var modelName string
+	var modelScore float64
+	if ai.model != nil {
+		if score, err := ai.model.Score(logLine); err == nil {
+			modelName, modelScore = ai.model.Name(), score
+			anomalyScore = ai.model.Combine(ruleScore, modelScore)
+		}
+	}
 	
 	// 추천사항 생성
 	recommendations := ai.generateRecommendations(entry, anomalyScore)
@@ -280,6 +351,13 @@ func (ai *AIAnalyzer) AnalyzeLog(logLine string, parsed map[string]string) *AIAn
 		Timestamp:       time.Now(),
 		SystemInfo:      features.SystemInfo,
 		ExpertDiagnosis: expertDiagnosis,
+		MatchedRules:    matchedRules,
+		Model:           modelName,
+		ModelScore:      modelScore,
+		RuleScore:       ruleScore,
+		BaselineScore:   baselineScore,
+		Baseline:        baseline,
+		BaselineEvents:  baselineEvents,
 	}
 }
 
@@ -379,9 +457,10 @@ func (ai *AIAnalyzer) extractFeatures(entry LogEntry) LogFeatures {
 	return features
 }
 
-// detectAnomalies 이상 패턴 감지
-func (ai *AIAnalyzer) detectAnomalies(entry LogEntry) float64 {
+// detectAnomalies 이상 패턴 감지 (종합 점수와 일치한 규칙 반환)
+func (ai *AIAnalyzer) detectAnomalies(entry LogEntry) (float64, []MatchedRule) {
 	var maxScore float64 = 0.0
+	var matched []MatchedRule
 	
 	// 패턴 매칭
 	for _, pattern := range ai.patterns {
@@ -389,8 +468,16 @@ func (ai *AIAnalyzer) detectAnomalies(entry LogEntry) float64 {
 			if pattern.Severity > maxScore {
 				maxScore = pattern.Severity
 			}
+			matched = append(matched, MatchedRule{
+				Name:        pattern.Name,
+				Severity:    pattern.Severity,
+				Category:    pattern.Category,
+				Action:      pattern.Action,
+				Description: pattern.Description,
+			})
 		}
 	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].Severity > matched[j].Severity })
 	
 	// 빈도 기반 이상 감지
 	frequencyScore := ai.analyzeFrequency(entry)
@@ -401,7 +488,7 @@ func (ai *AIAnalyzer) detectAnomalies(entry LogEntry) float64 {
 	// 종합 점수 계산
 	finalScore := math.Max(maxScore, math.Max(frequencyScore, timeScore))
 	
-	return finalScore
+	return finalScore, matched
 }
 
 // analyzeFrequency 빈도 기반 분석
@@ -661,6 +748,38 @@ func (ai *AIAnalyzer) UpdateBaseline() {
 	ai.baselineMetrics.BaselineUpdatedAt = time.Now()
 }
 
+// SetAlertThreshold 알림 임계값 설정 (0 이하 값은 무시)
+func (ai *AIAnalyzer) SetAlertThreshold(threshold float64) {
+	if threshold > 0 {
+		ai.alertThreshold = threshold
+	}
+}
+
+// SetBaselineConfig 통계 기준선 설정 (시작/설정 재로드 시 호출, 잘못된 값이면 기존 설정 유지)
+func (ai *AIAnalyzer) SetBaselineConfig(config LogBaselineConfig) error {
+	return ai.baselines.Configure(config)
+}
+
+// BaselineZThreshold 기준선 이탈 판정 z-점수 (알림 심각도 판단)
+func (ai *AIAnalyzer) BaselineZThreshold() float64 {
+	return ai.baselines.ZThreshold()
+}
+
+// SetModel ONNX 이상 점수 모델 설정 (시작 시 호출, nil 이면 규칙 기반 점수만 사용)
+func (ai *AIAnalyzer) SetModel(model *AnomalyModel) {
+	ai.model = model
+}
+
+// Model 설정된 ONNX 이상 점수 모델
+func (ai *AIAnalyzer) Model() *AnomalyModel {
+	return ai.model
+}
+
+// SetPatterns 이상 패턴 목록 교체 (규칙 파일 적용/재로드, 처리 고루틴에서 호출)
+func (ai *AIAnalyzer) SetPatterns(patterns []AnomalyPattern) {
+	ai.patterns = patterns
+}
+
 // GetAnalysisReport 분석 보고서 생성
 func (ai *AIAnalyzer) GetAnalysisReport() string {
 	report := fmt.Sprintf(`
@@ -678,6 +797,8 @@ func (ai *AIAnalyzer) GetAnalysisReport() string {
   - 시간 윈도우: %v
   - 알림 임계값: %.1f
 
+📐 통계 기준선 (서비스별 EWMA):
+%s
 🔍 감지 패턴 수: %d개
 `,
 		ai.baselineMetrics.AvgErrorRate*100,
@@ -688,12 +809,37 @@ func (ai *AIAnalyzer) GetAnalysisReport() string {
 		len(ai.logBuffer),
 		ai.timeWindow,
 		ai.alertThreshold,
+		ai.formatBaselineStatus(),
 		len(ai.patterns),
 	)
 	
 	return report
 }
 
+// formatBaselineStatus 분석 보고서용 통계 기준선 요약 (볼륨 상위 서비스)
+func (ai *AIAnalyzer) formatBaselineStatus() string {
+	statuses := ai.baselines.Status()
+	if len(statuses) == 0 {
+		return fmt.Sprintf("  - 학습 중인 서비스 없음 (윈도우 %v)\n", ai.baselines.Window())
+	}
+	var builder strings.Builder
+	for i, status := range statuses {
+		if i == 10 {
+			builder.WriteString(fmt.Sprintf("  - ... 외 %d개 서비스\n", len(statuses)-i))
+			break
+		}
+		state := ""
+		if status.Learning {
+			state = " (학습 중)"
+		} else if len(status.Active) > 0 {
+			state = fmt.Sprintf(" ⚠️ 이탈 %d건", len(status.Active))
+		}
+		builder.WriteString(fmt.Sprintf("  - %s: %.1f줄/%v, 에러율 %.1f%%, 고유 IP %.1f%s\n",
+			status.Service, status.Volume, ai.baselines.Window(), status.ErrorRate*100, status.UniqueIPs, state))
+	}
+	return builder.String()
+}
+
 // collectSystemInfo 시스템 정보 수집
 func (ai *AIAnalyzer) collectSystemInfo(ipAddresses []string) SystemInfo {
 	systemInfo := SystemInfo{}
@@ -763,9 +909,37 @@ func (ai *AIAnalyzer) classifyIPs(ipAddresses []string) ([]string, []string) {
 	return internalIPs, externalIPs
 }
 
+// SetGeoMapper ASN 조회에 사용할 지리정보 서비스 설정 (로그인 위치 조회와 캐시 공유)
+func (ai *AIAnalyzer) SetGeoMapper(gm *GeoMapper) {
+	ai.geoMapper = gm
+}
+
 // getASNInfo ASN 정보 조회 (외부 API 사용)
+// 지리정보 서비스가 설정되어 있으면 캐시에 없는 IP만 일괄 조회
 func (ai *AIAnalyzer) getASNInfo(externalIPs []string) []ASNInfo {
 	var asnData []ASNInfo
+
+	if ai.geoMapper != nil {
+		locations := ai.geoMapper.GetLocationInfoBatch(externalIPs)
+		for _, ip := range externalIPs {
+			location, found := locations[ip]
+			if !found {
+				if ip != "" {
+					asnData = append(asnData, ASNInfo{IP: ip, ASN: "Unknown", Organization: "Query Failed"})
+				}
+				continue
+			}
+			asnData = append(asnData, ASNInfo{
+				IP:           location.IP,
+				ASN:          location.ASN,
+				Organization: location.Organization,
+				Country:      location.Country,
+				Region:       location.Region,
+				City:         location.City,
+			})
+		}
+		return asnData
+	}
 	
 	for _, ip := range externalIPs {
 		if ip == "" {
@@ -787,7 +961,8 @@ func (ai *AIAnalyzer) queryASNInfo(ip string) ASNInfo {
 	// 무료 API 사용: ip-api.com
 	url := fmt.Sprintf("http://ip-api.com/json/%s?fields=status,message,country,regionName,city,org,as,query", ip)
 	
-	resp, err := http.Get(url)
+	client := &http.Client{Timeout: 5 * time.Second} // 응답 없는 조회가 분석을 무한정 막지 않도록
+	resp, err := client.Get(url)
 	if err != nil {
 		return ASNInfo{IP: ip, ASN: "Unknown", Organization: "Query Failed"}
 	}
@@ -0,0 +1,296 @@
+/*
+AI Prompt Redaction Module
+==========================
+
+Gemini 로 프롬프트를 보내기 전 호스트 밖으로 나가는 값을 가림 (설정 파일 ai_analysis.redaction, opt-in)
+
+주요 기능:
+  - 비밀 값 가림 (표본 추출과 같은 규칙: 비밀번호/토큰/API 키/Authorization 헤더/URL 자격 증명/개인 키 → [REDACTED])
+  - IP 주소, 사용자명, 호스트명을 프롬프트 안에서 일관된 자리표시자로 치환 (ip-1, user-1, host-1)
+    같은 값은 같은 자리표시자가 되어 AI 가 상관관계는 분석할 수 있음
+  - 필드 허용 목록 (allow_fields): 목록에 없는 필드는 프롬프트에서 통째로 제외
+  - 가림을 적용했으면 자리표시자의 의미를 프롬프트 끝에 안내
+
+필드 이름:
+- 시스템 진단: hostname, ip_addresses, cpu, memory, temperature, processes, connections
+- 로그 분석: log_line, 컨텍스트 키 이름 (예: user, source_ip)
+- 보안 위협 분석: 위협 데이터 키 이름
+- 일일 요약: hostname, alerts, logins, anomalies, system_trends
+*/
+package main
+
+import (
+	"encoding/json" // 위협 데이터 값 인코딩
+	"fmt"           // 형식화된 I/O
+	"net"           // IP 검증
+	"regexp"        // 패턴 매칭
+	"sort"          // 결과 정렬
+	"strings"       // 문자열 처리
+)
+
+// AI 프롬프트 필드 이름 (시스템 진단)
+const (
+	AIFieldHostname    = "hostname"
+	AIFieldIPAddresses = "ip_addresses"
+	AIFieldCPU         = "cpu"
+	AIFieldMemory      = "memory"
+	AIFieldTemperature = "temperature"
+	AIFieldProcesses   = "processes"
+	AIFieldConnections = "connections"
+	AIFieldLogLine     = "log_line"
+)
+
+// AI 프롬프트 필드 이름 (일일 요약, daily_digest.go)
+const (
+	AIFieldDigestAlerts    = "alerts"
+	AIFieldDigestLogins    = "logins"
+	AIFieldDigestAnomalies = "anomalies"
+	AIFieldDigestSystem    = "system_trends"
+)
+
+// AI 프롬프트 자리표시자 접두사
+const (
+	aiPlaceholderIP   = "ip"
+	aiPlaceholderUser = "user"
+	aiPlaceholderHost = "host"
+)
+
+// 프롬프트 안의 IP/사용자명 후보 패턴
+var (
+	promptIPv4Regex = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	promptIPv6Regex = regexp.MustCompile(`[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`)
+	// sshd/sudo/PAM/su 형식의 사용자명 (첫 번째 그룹이 사용자명)
+	promptUserRegexes = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(?:invalid|illegal) user ([^\s(]+)`),
+		regexp.MustCompile(`(?i)\bfor (?:invalid user |illegal user )?([^\s(]+) from\b`),
+		regexp.MustCompile(`(?i)\bfor user ([^\s(]+)`),
+		regexp.MustCompile(`(?i)\b(?:user|ruser|logname|acct)=["']?([^\s"',;()]+)`),
+		regexp.MustCompile(`\bsudo:\s+(\S+) :`),
+		regexp.MustCompile(`(?i)\bsu(?:\[\d+\])?: .*?\bby ([^\s(]+)`),
+	}
+	promptUserFieldRegex = regexp.MustCompile(`(?i)^(user|username|user_name|login|account|ruser|target_user)$`)
+	promptHostFieldRegex = regexp.MustCompile(`(?i)^(host|hostname|host_name|server|node)$`)
+)
+
+// AIRedactionConfig Gemini 프롬프트 가림 설정
+type AIRedactionConfig struct {
+	Enabled       bool     `json:"enabled"`        // 가림 사용 (비활성화 시 프롬프트를 그대로 전송)
+	MaskIPs       bool     `json:"mask_ips"`       // IP 주소를 ip-N 으로 치환
+	MaskUsernames bool     `json:"mask_usernames"` // 사용자명을 user-N 으로 치환
+	MaskHostnames bool     `json:"mask_hostnames"` // 호스트명을 host-N 으로 치환
+	AllowFields   []string `json:"allow_fields"`   // 호스트 밖으로 보낼 수 있는 필드 (비우면 모든 필드, 가림은 그대로 적용)
+}
+
+// PromptRedactor 프롬프트 하나를 만드는 동안 쓰는 가림 상태 (nil 이면 가림 없음)
+type PromptRedactor struct {
+	config       AIRedactionConfig
+	allow        map[string]bool
+	placeholders map[string]string // 원래 값 → 자리표시자
+	counts       map[string]int    // 자리표시자 종류별 번호
+	users        []string          // 이미 본 사용자명 (이후 등장하는 같은 이름도 치환)
+	hosts        []string          // 이미 본 호스트명
+	omitted      []string          // 허용 목록에 없어 제외한 필드
+	masked       bool              // 값을 하나라도 가렸는지
+}
+
+// NewRedactor 프롬프트용 가림 상태 생성 (가림을 사용하지 않으면 nil)
+func (c AIRedactionConfig) NewRedactor() *PromptRedactor {
+	if !c.Enabled {
+		return nil
+	}
+	redactor := &PromptRedactor{
+		config:       c,
+		placeholders: make(map[string]string),
+		counts:       make(map[string]int),
+	}
+	if len(c.AllowFields) > 0 {
+		redactor.allow = make(map[string]bool, len(c.AllowFields))
+		for _, field := range c.AllowFields {
+			redactor.allow[strings.ToLower(strings.TrimSpace(field))] = true
+		}
+	}
+	return redactor
+}
+
+// Allows 필드를 프롬프트에 넣어도 되는지 (허용 목록에 없으면 제외한 필드로 기록)
+func (r *PromptRedactor) Allows(field string) bool {
+	if r == nil || r.allow == nil || r.allow[strings.ToLower(field)] {
+		return true
+	}
+	r.omitted = append(r.omitted, field)
+	return false
+}
+
+// placeholder 값의 자리표시자 (처음 보는 값이면 새 번호)
+func (r *PromptRedactor) placeholder(kind, value string) string {
+	key := kind + "\x00" + value
+	if existing, ok := r.placeholders[key]; ok {
+		return existing
+	}
+	r.counts[kind]++
+	placeholder := fmt.Sprintf("%s-%d", kind, r.counts[kind])
+	r.placeholders[key] = placeholder
+	r.masked = true
+	return placeholder
+}
+
+// IP IP 주소 하나 가림 (mask_ips 가 꺼져 있으면 그대로)
+func (r *PromptRedactor) IP(ip string) string {
+	if r == nil || !r.config.MaskIPs || ip == "" {
+		return ip
+	}
+	return r.placeholder(aiPlaceholderIP, ip)
+}
+
+// IPs IP 주소 목록 가림
+func (r *PromptRedactor) IPs(ips []string) []string {
+	if r == nil || !r.config.MaskIPs {
+		return ips
+	}
+	masked := make([]string, len(ips))
+	for i, ip := range ips {
+		masked[i] = r.IP(ip)
+	}
+	return masked
+}
+
+// Username 사용자명 하나 가림 (mask_usernames 가 꺼져 있으면 그대로)
+func (r *PromptRedactor) Username(user string) string {
+	if r == nil || !r.config.MaskUsernames || user == "" {
+		return user
+	}
+	if !containsString(r.users, user) {
+		r.users = append(r.users, user)
+	}
+	return r.placeholder(aiPlaceholderUser, user)
+}
+
+// Hostname 호스트명 하나 가림 (mask_hostnames 가 꺼져 있으면 그대로)
+func (r *PromptRedactor) Hostname(host string) string {
+	if r == nil || !r.config.MaskHostnames || host == "" {
+		return host
+	}
+	if !containsString(r.hosts, host) {
+		r.hosts = append(r.hosts, host)
+	}
+	return r.placeholder(aiPlaceholderHost, host)
+}
+
+// Text 자유 형식 문자열 가림 (비밀 → IP → 사용자명 → 이미 본 호스트명 순서)
+func (r *PromptRedactor) Text(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+	if redacted := redactSecrets(text); redacted != text {
+		text = redacted
+		r.masked = true
+	}
+	if r.config.MaskIPs {
+		text = promptIPv4Regex.ReplaceAllStringFunc(text, r.maskIPCandidate)
+		text = promptIPv6Regex.ReplaceAllStringFunc(text, r.maskIPCandidate)
+	}
+	if r.config.MaskUsernames {
+		for _, pattern := range promptUserRegexes {
+			for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+				r.Username(strings.Trim(match[1], `"'`))
+			}
+		}
+		text = r.replaceKnown(text, r.users, aiPlaceholderUser)
+	}
+	if r.config.MaskHostnames {
+		text = r.replaceKnown(text, r.hosts, aiPlaceholderHost)
+	}
+	return text
+}
+
+// maskIPCandidate 패턴에 맞은 후보가 실제 IP 일 때만 자리표시자로 치환 (시각 10:15:32 등 제외)
+func (r *PromptRedactor) maskIPCandidate(candidate string) string {
+	if net.ParseIP(candidate) == nil {
+		return candidate
+	}
+	return r.IP(candidate)
+}
+
+// replaceKnown 이미 본 값을 단어 경계 기준으로 자리표시자로 치환 (긴 값부터)
+func (r *PromptRedactor) replaceKnown(text string, values []string, kind string) string {
+	sorted := append([]string(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	for _, value := range sorted {
+		pattern := regexp.MustCompile(`(^|[^\w.-])` + regexp.QuoteMeta(value) + `($|[^\w-])`)
+		placeholder := r.placeholder(kind, value)
+		text = pattern.ReplaceAllString(text, "${1}"+placeholder+"${2}")
+	}
+	return text
+}
+
+// Fields 필드 맵 가림 (허용 목록에 없는 필드 제외, 이름이 비밀/사용자/호스트처럼 보이는 필드는 값 전체 치환)
+func (r *PromptRedactor) Fields(fields map[string]string) map[string]string {
+	if r == nil || fields == nil {
+		return fields
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names) // 자리표시자 번호가 실행마다 같도록
+	redacted := make(map[string]string, len(fields))
+	for _, name := range names {
+		if !r.Allows(name) {
+			continue
+		}
+		value := fields[name]
+		switch {
+		case secretFieldNameRegex.MatchString(name) && value != "":
+			redacted[name] = sampleRedacted
+			r.masked = true
+		case promptUserFieldRegex.MatchString(name) && r.config.MaskUsernames:
+			redacted[name] = r.Username(value)
+		case promptHostFieldRegex.MatchString(name) && r.config.MaskHostnames:
+			redacted[name] = r.Hostname(value)
+		default:
+			redacted[name] = r.Text(value)
+		}
+	}
+	return redacted
+}
+
+// Notice 프롬프트 끝에 붙일 가림 안내 (가린 값/제외한 필드가 없으면 빈 문자열)
+func (r *PromptRedactor) Notice() string {
+	if r == nil || (!r.masked && len(r.omitted) == 0) {
+		return ""
+	}
+	var notes []string
+	if r.masked {
+		notes = append(notes, "ip-N/user-N/host-N 은 보안 정책에 따라 가린 IP 주소/사용자명/호스트명이며 같은 번호는 같은 값입니다. [REDACTED] 는 가린 비밀 값입니다.")
+	}
+	if len(r.omitted) > 0 {
+		notes = append(notes, fmt.Sprintf("보안 정책에 따라 제외한 항목: %s", strings.Join(r.omitted, ", ")))
+	}
+	return "\n참고: " + strings.Join(notes, " ") + "\n"
+}
+
+// Values 보안 위협 데이터처럼 값이 문자열이 아닐 수 있는 맵 가림 (문자열이 아닌 값은 JSON 으로 바꿔 가린 뒤 그대로 포함)
+func (r *PromptRedactor) Values(values map[string]interface{}) map[string]interface{} {
+	if r == nil || values == nil {
+		return values
+	}
+	texts := make(map[string]string, len(values))
+	for name, value := range values {
+		if text, ok := value.(string); ok {
+			texts[name] = text
+		} else if encoded, err := json.Marshal(value); err == nil {
+			texts[name] = string(encoded)
+		} else {
+			texts[name] = fmt.Sprint(value)
+		}
+	}
+	redacted := make(map[string]interface{}, len(texts))
+	for name, text := range r.Fields(texts) {
+		if _, isString := values[name].(string); !isString && json.Valid([]byte(text)) {
+			redacted[name] = json.RawMessage(text)
+		} else {
+			redacted[name] = text
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,377 @@
+/*
+Alert Actions Module
+====================
+
+알림 이메일의 서명된 원클릭 링크로 알림 끄기(snooze)/확인(ack) (alerts.actions)
+
+수신자가 셸 접속 없이 메일 안의 링크로 반복 알림을 줄일 수 있도록, 관리 API 의
+/alerts/action 엔드포인트를 가리키는 HMAC 서명 링크를 이메일 본문 끝에 붙입니다.
+링크는 Bearer 토큰 대신 서명과 만료 시각으로 검증하므로 외부에 공개하는 HTTPS 주소
+(관리 API -api-tls-cert 또는 TLS 를 종료하는 리버스 프록시)를 base_url 로 지정해야 합니다.
+
+주요 기능:
+- 링크: "1h/4h/24h 동안 끄기"(alerts.actions.snooze), "확인(ack)" - 유효 기간 link_hours(기본 72시간)
+- 서명 키: 설정 파일 secret 또는 SYSLOG_ALERT_ACTION_KEY / 키체인 alert-action-key (16자 이상)
+- 링크를 열면 확인 페이지만 표시하고 버튼(POST)을 눌러야 적용 (메일 보안 검사기의 링크 미리 열기 방지)
+- 끄기: 같은 알림(유형 + 인시던트 키, 키가 없으면 제목)을 지정한 시간 동안 채널로 보내지 않음
+- 확인: 복구(info) 알림이 오거나 24시간이 지날 때까지 같은 알림을 보내지 않음 (복구 알림은 전송)
+- 유형 전체 끄기: Slack /sysmon silence <유형> <시간> 으로 한 유형의 모든 알림을 지정한 시간 동안 보내지 않음 (slack_commands.go)
+- 꺼진 알림도 최근 알림/대시보드에는 남고, GET /alerts/snoozes 로 현재 끈 알림과 생략 횟수 조회
+*/
+package main
+
+import (
+	"crypto/hmac"     // 링크 서명
+	"crypto/sha256"   // HMAC 해시
+	"encoding/base64" // 서명 인코딩
+	"fmt"             // 형식화된 I/O
+	"net/url"         // 링크 쿼리
+	"reflect"         // 설정 비교
+	"sort"            // 끈 알림 정렬
+	"strconv"         // 만료 시각
+	"strings"         // 문자열 처리
+	"sync"            // 동시성 제어
+	"time"            // 끄기 시간, 링크 만료
+)
+
+// 알림 동작 설정 기본값
+const (
+	AlertActionPath        = "/alerts/action" // 관리 API 의 링크 엔드포인트
+	AlertActionSnooze      = "snooze"
+	AlertActionAck         = "ack"
+	AlertActionSilence     = "silence"          // 유형 전체 끄기 (Slack /sysmon silence)
+	AlertActionUnsilence   = "unsilence"        // 유형 전체 끄기 해제
+	DefaultAlertLinkTTL    = 72 * time.Hour     // 링크 유효 기간
+	DefaultAlertAckTTL     = 24 * time.Hour     // 복구 알림이 없을 때 확인 상태 유지 시간
+	MaxAlertSnooze         = 7 * 24 * time.Hour // 끄기 최대 시간
+	MinAlertActionKeyLen   = 16                 // 서명 키 최소 길이
+	maxAlertActionTitleLen = 100                // 링크에 넣는 제목 최대 길이 (문자)
+)
+
+// DefaultAlertSnoozeDurations 기본 끄기 시간 링크
+var DefaultAlertSnoozeDurations = []string{"1h", "4h", "24h"}
+
+// AlertActionConfig 알림 이메일 끄기/확인 링크 설정 (alerts.actions)
+type AlertActionConfig struct {
+	BaseURL   string   `json:"base_url"`   // 외부에서 관리 API 에 접근하는 HTTPS 주소 (비어 있으면 링크 없음)
+	Secret    string   `json:"secret"`     // 링크 서명 키 (비우면 SYSLOG_ALERT_ACTION_KEY / 키체인 alert-action-key)
+	Snooze    []string `json:"snooze"`     // 끄기 시간 링크 (비우면 1h, 4h, 24h)
+	LinkHours int      `json:"link_hours"` // 링크 유효 시간 (0 이면 72시간)
+}
+
+// AlertActionLink 이메일에 넣을 동작 링크
+type AlertActionLink struct {
+	Label string
+	URL   string
+}
+
+// AlertAction 서명을 검증한 링크 동작
+type AlertAction struct {
+	Key      string        // 끄기 대상 알림 키 (alertSnoozeKey)
+	Action   string        // snooze, ack
+	Duration time.Duration // 끄기 시간 (ack 는 DefaultAlertAckTTL)
+	Title    string        // 링크를 만든 알림 제목 (확인 페이지 표시용)
+	By       string        // 적용한 사용자 (Slack 버튼, 이메일 링크는 비어 있음)
+}
+
+// AlertActionLinks 링크 서명/검증기 (base_url 을 설정하기 전에는 링크를 만들지 않음)
+type AlertActionLinks struct {
+	baseURL string
+	key     []byte
+	snooze  []time.Duration
+	ttl     time.Duration
+	mutex   sync.RWMutex
+}
+
+// NewAlertActionLinks 링크 서명기 생성 (SetConfig 로 base_url 을 설정하면 사용)
+func NewAlertActionLinks() *AlertActionLinks {
+	return &AlertActionLinks{}
+}
+
+// SetConfig 주소/서명 키/끄기 시간 교체 (base_url 이 비어 있으면 사용 안 함, 잘못된 설정이면 기존 설정 유지)
+// 서명 키가 바뀌면 이미 보낸 링크는 더 이상 동작하지 않음
+func (al *AlertActionLinks) SetConfig(config AlertActionConfig, secret string) error {
+	if config.BaseURL == "" {
+		al.mutex.Lock()
+		defer al.mutex.Unlock()
+		al.baseURL, al.key = "", nil
+		return nil
+	}
+
+	base, err := url.Parse(strings.TrimSuffix(config.BaseURL, "/"))
+	if err != nil || base.Host == "" {
+		return fmt.Errorf("invalid alert action base_url %q", config.BaseURL)
+	}
+	if base.Scheme != "https" {
+		return fmt.Errorf("alert action base_url must use https (links carry no bearer token): %s", config.BaseURL)
+	}
+	if len(secret) < MinAlertActionKeyLen {
+		return fmt.Errorf("alert action secret must be at least %d characters (set alerts.actions.secret or SYSLOG_ALERT_ACTION_KEY)", MinAlertActionKeyLen)
+	}
+	if config.LinkHours < 0 {
+		return fmt.Errorf("invalid alert action link_hours: %d", config.LinkHours)
+	}
+
+	names := config.Snooze
+	if len(names) == 0 {
+		names = DefaultAlertSnoozeDurations
+	}
+	snooze := make([]time.Duration, 0, len(names))
+	for _, name := range names {
+		duration, err := time.ParseDuration(name)
+		if err != nil || duration <= 0 || duration > MaxAlertSnooze {
+			return fmt.Errorf("invalid alert snooze duration %q (use e.g. 1h, 30m, up to %s)", name, MaxAlertSnooze)
+		}
+		snooze = append(snooze, duration)
+	}
+	ttl := DefaultAlertLinkTTL
+	if config.LinkHours > 0 {
+		ttl = time.Duration(config.LinkHours) * time.Hour
+	}
+
+	al.mutex.Lock()
+	defer al.mutex.Unlock()
+	al.baseURL = base.String()
+	al.key = []byte(secret)
+	al.snooze = snooze
+	al.ttl = ttl
+	return nil
+}
+
+// Enabled 링크 사용 여부 (base_url 과 서명 키가 설정됨)
+func (al *AlertActionLinks) Enabled() bool {
+	if al == nil {
+		return false
+	}
+	al.mutex.RLock()
+	defer al.mutex.RUnlock()
+	return al.baseURL != ""
+}
+
+// Links 알림의 끄기/확인 링크 (now 부터 유효 기간 동안 동작, 사용하지 않으면 nil)
+func (al *AlertActionLinks) Links(alert Alert, now time.Time) []AlertActionLink {
+	if al == nil {
+		return nil
+	}
+	al.mutex.RLock()
+	defer al.mutex.RUnlock()
+	if al.baseURL == "" {
+		return nil
+	}
+
+	key := alertSnoozeKey(alert)
+	title := truncateRunes(alert.Title, maxAlertActionTitleLen)
+	expires := strconv.FormatInt(now.Add(al.ttl).Unix(), 10)
+
+	links := make([]AlertActionLink, 0, len(al.snooze)+1)
+	for _, duration := range al.snooze {
+		links = append(links, AlertActionLink{
+			Label: fmt.Sprintf("%s 동안 이 알림 끄기", formatSnoozeDuration(duration)),
+			URL:   al.link(key, AlertActionSnooze, formatSnoozeDuration(duration), title, expires),
+		})
+	}
+	links = append(links, AlertActionLink{
+		Label: "확인(ack) - 복구될 때까지 이 알림 끄기",
+		URL:   al.link(key, AlertActionAck, "", title, expires),
+	})
+	return links
+}
+
+// link 서명한 동작 링크 (호출자가 RLock 보유)
+func (al *AlertActionLinks) link(key, action, duration, title, expires string) string {
+	query := url.Values{}
+	query.Set("k", key)
+	query.Set("a", action)
+	if duration != "" {
+		query.Set("d", duration)
+	}
+	query.Set("t", title)
+	query.Set("e", expires)
+	query.Set("s", al.sign(key, action, duration, title, expires))
+	return al.baseURL + AlertActionPath + "?" + query.Encode()
+}
+
+// sign 링크 값의 HMAC-SHA256 서명 (URL 안전 base64)
+func (al *AlertActionLinks) sign(parts ...string) string {
+	mac := hmac.New(sha256.New, al.key)
+	mac.Write([]byte(strings.Join(parts, "\n")))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 링크 쿼리의 서명과 만료 시각을 확인하고 동작 반환
+func (al *AlertActionLinks) Verify(query url.Values, now time.Time) (AlertAction, error) {
+	key, action, duration := query.Get("k"), query.Get("a"), query.Get("d")
+	title, expires := query.Get("t"), query.Get("e")
+
+	al.mutex.RLock()
+	enabled := al.baseURL != ""
+	expected := al.sign(key, action, duration, title, expires)
+	al.mutex.RUnlock()
+	if !enabled {
+		return AlertAction{}, fmt.Errorf("alert action links are not configured")
+	}
+	if !hmac.Equal([]byte(expected), []byte(query.Get("s"))) {
+		return AlertAction{}, fmt.Errorf("invalid link signature")
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return AlertAction{}, fmt.Errorf("invalid link expiry")
+	}
+	if now.After(time.Unix(unix, 0)) {
+		return AlertAction{}, fmt.Errorf("link expired at %s", time.Unix(unix, 0).Format("2006-01-02 15:04"))
+	}
+
+	result := AlertAction{Key: key, Action: action, Title: title}
+	switch action {
+	case AlertActionSnooze:
+		result.Duration, err = time.ParseDuration(duration)
+		if err != nil || result.Duration <= 0 || result.Duration > MaxAlertSnooze {
+			return AlertAction{}, fmt.Errorf("invalid snooze duration %q", duration)
+		}
+	case AlertActionAck:
+		result.Duration = DefaultAlertAckTTL
+	default:
+		return AlertAction{}, fmt.Errorf("unknown action %q", action)
+	}
+	return result, nil
+}
+
+// Describe 동작 설명 (확인 페이지/로그용)
+func (a AlertAction) Describe() string {
+	if a.Action == AlertActionAck {
+		return "확인(ack) - 복구 알림이 오거나 24시간이 지날 때까지 끄기"
+	}
+	return fmt.Sprintf("%s 동안 끄기", formatSnoozeDuration(a.Duration))
+}
+
+// equalAlertActionConfig 링크 설정이 같은지 비교 (설정 재로드 시 바뀐 경우에만 적용)
+func equalAlertActionConfig(a, b AlertActionConfig) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// alertSnoozeKey 끄기 대상 알림 키 (유형 + 인시던트 키, 인시던트 키가 없으면 제목)
+func alertSnoozeKey(alert Alert) string {
+	if alert.Thread != "" {
+		return alert.Type + "|" + alert.Thread
+	}
+	return alert.Type + "|" + alert.Title
+}
+
+// alertSilenceKey 유형 전체 끄기 키 (인시던트/제목과 관계없이 같은 유형의 모든 알림)
+func alertSilenceKey(alertType string) string {
+	return alertType + "|*"
+}
+
+// formatSnoozeDuration 끄기 시간 표시 (1h, 30m, 1h30m)
+func formatSnoozeDuration(duration time.Duration) string {
+	text := duration.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+// truncateRunes 문자 단위로 최대 길이까지 자르기
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// appendAlertActionLinks 이메일 본문 끝에 동작 링크 목록 추가
+func appendAlertActionLinks(body string, links []AlertActionLink) string {
+	if len(links) == 0 {
+		return body
+	}
+	var text strings.Builder
+	text.WriteString(strings.TrimRight(body, "\n"))
+	text.WriteString("\n\n🔕 이 알림 관리 (링크를 열고 버튼을 눌러 적용)\n")
+	for _, link := range links {
+		fmt.Fprintf(&text, "- %s:\n  %s\n", link.Label, link.URL)
+	}
+	return text.String()
+}
+
+// alertSnooze 끈 알림 상태
+type alertSnooze struct {
+	Key        string    `json:"key"`
+	Title      string    `json:"title"`
+	Action     string    `json:"action"` // snooze, ack
+	Until      time.Time `json:"until"`
+	By         string    `json:"by,omitempty"` // 적용한 사용자 (Slack 버튼/명령)
+	Suppressed int       `json:"suppressed"`   // 끈 뒤 보내지 않은 알림 수
+}
+
+// AlertSnoozer 링크로 끈 알림 목록
+type AlertSnoozer struct {
+	entries map[string]*alertSnooze
+	mutex   sync.Mutex
+}
+
+// NewAlertSnoozer 새로운 알림 끄기 목록 생성
+func NewAlertSnoozer() *AlertSnoozer {
+	return &AlertSnoozer{entries: make(map[string]*alertSnooze)}
+}
+
+// Apply 검증한 동작을 적용하고 끄기 종료 시각 반환 (같은 키는 새 동작으로 교체)
+func (as *AlertSnoozer) Apply(action AlertAction, now time.Time) time.Time {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	until := now.Add(action.Duration)
+	as.entries[action.Key] = &alertSnooze{Key: action.Key, Title: action.Title, Action: action.Action, Until: until, By: action.By}
+	return until
+}
+
+// Remove 끈 알림 해제 (없거나 이미 끝났으면 false)
+func (as *AlertSnoozer) Remove(key string, now time.Time) bool {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	entry, ok := as.entries[key]
+	if !ok {
+		return false
+	}
+	delete(as.entries, key)
+	return now.Before(entry.Until)
+}
+
+// Suppressed 알림을 채널로 보내지 않아야 하면 true (알림별 끄기, 그다음 유형 전체 끄기 확인)
+// 확인(ack)한 알림은 복구(info) 알림이 오면 확인 상태를 풀고 복구 알림은 전송
+func (as *AlertSnoozer) Suppressed(alert Alert, now time.Time) bool {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	for _, key := range []string{alertSnoozeKey(alert), alertSilenceKey(alert.Type)} {
+		entry, ok := as.entries[key]
+		if !ok {
+			continue
+		}
+		if !now.Before(entry.Until) || (entry.Action == AlertActionAck && alert.Severity == AlertSeverityInfo) {
+			delete(as.entries, key)
+			continue
+		}
+		entry.Suppressed++
+		return true
+	}
+	return false
+}
+
+// Active 현재 끈 알림 목록 (끝나는 시각 순, 만료된 항목 정리)
+func (as *AlertSnoozer) Active(now time.Time) []alertSnooze {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+	active := make([]alertSnooze, 0, len(as.entries))
+	for key, entry := range as.entries {
+		if !now.Before(entry.Until) {
+			delete(as.entries, key)
+			continue
+		}
+		active = append(active, *entry)
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].Until.Before(active[j].Until) })
+	return active
+}
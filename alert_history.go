@@ -0,0 +1,108 @@
+/*
+Alert History Module
+====================
+
+알림 제한(throttling)용 시간순 알림 히스토리
+
+주요 기능:
+- 키별 마지막 알림 시각 조회/갱신 (O(1) 조회, O(log n) 갱신)
+- 최소 힙(min-heap)으로 가장 오래된 항목부터 정리 (정렬 불필요)
+- 보존 기간 초과 및 최대 크기 초과 항목 제거
+*/
+package main
+
+import (
+	"container/heap" // 최소 힙
+	"time"           // 시간 처리
+)
+
+// historyEntry 알림 히스토리 항목
+type historyEntry struct {
+	key       string
+	timestamp time.Time
+	index     int // 힙 내 위치 (heap.Fix 용)
+}
+
+// historyHeap 타임스탬프 기준 최소 힙 (가장 오래된 항목이 루트)
+type historyHeap []*historyEntry
+
+func (h historyHeap) Len() int           { return len(h) }
+func (h historyHeap) Less(i, j int) bool { return h[i].timestamp.Before(h[j].timestamp) }
+func (h historyHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *historyHeap) Push(x interface{}) {
+	entry := x.(*historyEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *historyHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.index = -1
+	*h = old[:n-1]
+	return entry
+}
+
+// AlertHistory 키별 마지막 알림 시각을 시간순으로 관리
+// 동시성 보호는 호출자(LoginDetector.alertMutex)가 담당
+type AlertHistory struct {
+	entries map[string]*historyEntry
+	order   historyHeap
+}
+
+// NewAlertHistory 새로운 알림 히스토리 생성
+func NewAlertHistory() *AlertHistory {
+	return &AlertHistory{
+		entries: make(map[string]*historyEntry),
+	}
+}
+
+// Get 키의 마지막 알림 시각 조회
+func (ah *AlertHistory) Get(key string) (time.Time, bool) {
+	entry, exists := ah.entries[key]
+	if !exists {
+		return time.Time{}, false
+	}
+	return entry.timestamp, true
+}
+
+// Touch 키의 마지막 알림 시각 갱신 (없으면 추가)
+func (ah *AlertHistory) Touch(key string, at time.Time) {
+	if entry, exists := ah.entries[key]; exists {
+		entry.timestamp = at
+		heap.Fix(&ah.order, entry.index)
+		return
+	}
+
+	entry := &historyEntry{key: key, timestamp: at}
+	heap.Push(&ah.order, entry)
+	ah.entries[key] = entry
+}
+
+// Prune cutoff 이전 항목과 maxSize를 초과하는 가장 오래된 항목 제거
+// 제거된 항목 수 반환
+func (ah *AlertHistory) Prune(cutoff time.Time, maxSize int) int {
+	removed := 0
+	for ah.order.Len() > 0 {
+		oldest := ah.order[0]
+		if !oldest.timestamp.Before(cutoff) && (maxSize <= 0 || ah.order.Len() <= maxSize) {
+			break
+		}
+		heap.Pop(&ah.order)
+		delete(ah.entries, oldest.key)
+		removed++
+	}
+	return removed
+}
+
+// Len 히스토리 항목 수
+func (ah *AlertHistory) Len() int {
+	return ah.order.Len()
+}
@@ -0,0 +1,171 @@
+/*
+Alert Rendering Module
+======================
+
+공통 알림 모델을 채널별 메시지로 변환하는 렌더러
+
+주요 기능:
+- AlertSection / AlertField: 알림 내용을 한 번만 만들어 모든 채널이 공유하는 구조화 모델
+- 채널별 상세 수준: summary (요약 섹션만) / full (전체 섹션)
+- 텍스트 렌더링 (이메일 본문, 웹훅/PagerDuty 본문)
+- Slack 첨부 메시지 렌더링 (섹션당 첨부 블록 하나)
+
+섹션이 없는 알림(재부팅, 정기 보고서 등)은 기존 Body / Slack 서식을 그대로 사용
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"strings" // 문자열 처리
+)
+
+// 알림 상세 수준
+const (
+	AlertDetailSummary = "summary" // 요약 섹션만 표시
+	AlertDetailFull    = "full"    // 모든 섹션 표시
+)
+
+// DefaultAlertDetail 채널별 기본 상세 수준 (목록에 없는 채널은 full)
+var DefaultAlertDetail = map[string]string{
+	"email":    AlertDetailFull,
+	"slack":    AlertDetailSummary,
+	"telegram": AlertDetailSummary,
+	"webhook":  AlertDetailFull,
+}
+
+// alertSectionRule 텍스트 본문의 섹션 제목 아래 구분선
+const alertSectionRule = "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
+
+// AlertField 섹션 안의 "라벨: 값" 항목
+type AlertField struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+	Short bool   `json:"-"` // Slack 에서 두 열로 나란히 표시
+	Code  bool   `json:"-"` // 명령어, 원본 로그 등 코드 서식으로 표시
+}
+
+// AlertSection 알림 본문의 한 섹션
+type AlertSection struct {
+	Title   string       `json:"title,omitempty"` // 비어 있으면 제목 없이 필드만 표시
+	Fields  []AlertField `json:"fields,omitempty"`
+	Text    string       `json:"text,omitempty"` // 여러 줄 자유 형식 내용 (필드 뒤에 표시)
+	Summary bool         `json:"summary"`        // 요약 수준에서도 표시
+}
+
+// ParseAlertDetail 상세 수준 문자열 검증 (summary, full)
+func ParseAlertDetail(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case AlertDetailSummary:
+		return AlertDetailSummary, nil
+	case AlertDetailFull:
+		return AlertDetailFull, nil
+	}
+	return "", fmt.Errorf("unknown alert detail level %q (expected summary or full)", value)
+}
+
+// headline 알림 첫 줄 (Headline 이 없으면 Title)
+func (a Alert) headline() string {
+	if a.Headline != "" {
+		return a.Headline
+	}
+	return a.Title
+}
+
+// visibleSections 상세 수준에 맞는 섹션 목록과 생략된 섹션 수
+func (a Alert) visibleSections(detail string) ([]AlertSection, int) {
+	if detail != AlertDetailSummary {
+		return a.Sections, 0
+	}
+	sections := make([]AlertSection, 0, len(a.Sections))
+	for _, section := range a.Sections {
+		if section.Summary {
+			sections = append(sections, section)
+		}
+	}
+	return sections, len(a.Sections) - len(sections)
+}
+
+// alertSummaryNote 요약 수준에서 생략된 섹션 안내 문구
+func alertSummaryNote(omitted int) string {
+	return fmt.Sprintf("요약 알림입니다 (상세 섹션 %d개 생략, 전체 내용은 상세 수준 full 채널에서 확인)", omitted)
+}
+
+// RenderText 알림을 일반 텍스트로 렌더링 (이메일 본문 등)
+// 섹션이 없는 알림은 Body 를 그대로 반환
+func (a Alert) RenderText(detail string) string {
+	if len(a.Sections) == 0 {
+		return a.Body
+	}
+
+	var sb strings.Builder
+	sb.WriteString(a.headline() + "\n==============================\n")
+
+	sections, omitted := a.visibleSections(detail)
+	for _, section := range sections {
+		sb.WriteString("\n")
+		if section.Title != "" {
+			sb.WriteString(section.Title + ":\n" + alertSectionRule + "\n")
+		}
+		for _, field := range section.Fields {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", field.Label, field.Value))
+		}
+		if section.Text != "" {
+			sb.WriteString(strings.TrimRight(section.Text, "\n") + "\n")
+		}
+	}
+	if omitted > 0 {
+		sb.WriteString("\n" + alertSummaryNote(omitted) + "\n")
+	}
+	return sb.String()
+}
+
+// RenderSlack 알림을 Slack 메시지로 렌더링 (섹션당 첨부 블록 하나)
+// 섹션이 없는 알림은 Slack 전용 서식이 있으면 그대로, 없으면 기본 메시지로 변환
+func (a Alert) RenderSlack(detail string) SlackMessage {
+	if len(a.Sections) == 0 {
+		if a.Slack != nil {
+			return *a.Slack
+		}
+		return createGenericSlackAlert(a)
+	}
+
+	color, icon := SlackColorGood, ":robot_face:"
+	switch a.Severity {
+	case AlertSeverityWarning:
+		color, icon = SlackColorWarning, DefaultSlackIcon
+	case AlertSeverityCritical:
+		color, icon = SlackColorDanger, ":rotating_light:"
+	}
+
+	sections, omitted := a.visibleSections(detail)
+	attachments := make([]SlackAttachment, 0, len(sections)+1)
+	for _, section := range sections {
+		fields := make([]SlackField, 0, len(section.Fields))
+		for _, field := range section.Fields {
+			value := field.Value
+			if field.Code {
+				value = "`" + strings.ReplaceAll(value, "`", "'") + "`"
+			}
+			fields = append(fields, SlackField{Title: field.Label, Value: value, Short: field.Short})
+		}
+		attachments = append(attachments, SlackAttachment{
+			Color:  color,
+			Title:  section.Title,
+			Text:   strings.TrimRight(section.Text, "\n"),
+			Fields: fields,
+		})
+	}
+	if omitted > 0 {
+		attachments = append(attachments, SlackAttachment{Color: color, Text: "_" + alertSummaryNote(omitted) + "_"})
+	}
+	if len(attachments) > 0 {
+		attachments[len(attachments)-1].Timestamp = a.Timestamp.Unix()
+	}
+
+	return SlackMessage{
+		Text:        fmt.Sprintf("*%s*", a.headline()),
+		IconEmoji:   icon,
+		Username:    DefaultSlackUsername,
+		Attachments: attachments,
+	}
+}
@@ -0,0 +1,277 @@
+/*
+Alert Routes Module
+===================
+
+알림 유형/심각도/호스트/키워드/레이블별 전송 채널 지정 (설정 파일 routes)
+
+주요 기능:
+  - 규칙은 순서대로 평가하여 처음 일치한 규칙의 대상으로만 전송 ("continue": true 면 다음 규칙도 평가하여 대상 합침)
+  - 일치하는 규칙이 없으면 기존처럼 모든 채널로 전송
+  - 대상은 채널 이름 (email, slack, telegram, webhook, pagerduty, kafka) 이며
+    "채널:대상" 으로 받는 곳을 바꿀 수 있음 (email:수신자[,수신자], slack:#채널, telegram:채팅ID)
+  - 대상 목록이 비어 있는 규칙은 일치한 알림을 어느 채널로도 보내지 않음 (최근 알림에는 기록)
+  - 레이블은 알림 필드 값을 glob 으로 비교 (예: CI/CD 알림의 {"team": "payments"} 로 담당 팀에 전송)
+
+예:
+
+	"routes": [
+	    {"name": "login-failures", "match": {"type": ["login"], "keyword": "failed"}, "destinations": ["slack:#security"]},
+	    {"name": "disk", "match": {"type": ["system"], "keyword": "disk"}, "destinations": ["email:ops@example.com"]},
+	    {"name": "critical-ai", "match": {"type": ["ai"], "level": ["critical"]}, "destinations": ["pagerduty", "slack"]},
+	    {"name": "payments-ci", "match": {"type": ["cicd"], "labels": {"team": "payments"}}, "destinations": ["slack:#payments-dev"]}
+	]
+*/
+package main
+
+import (
+	"fmt"           // 형식화된 I/O
+	"path/filepath" // 호스트 glob 매칭
+	"strings"       // 문자열 처리
+)
+
+// routeTargetSinks 받는 곳 지정("채널:대상")을 지원하는 채널
+var routeTargetSinks = map[string]bool{"email": true, "slack": true, "telegram": true}
+
+// AlertRoute 알림 라우팅 규칙 (설정 파일 routes 항목)
+type AlertRoute struct {
+	Name         string          `json:"name"`         // 규칙 이름 (로그/오류 메시지 표시용)
+	Match        AlertRouteMatch `json:"match"`        // 일치 조건 (모든 조건을 만족해야 일치, 비어 있으면 모든 알림)
+	Destinations []string        `json:"destinations"` // 전송 대상 ("slack", "slack:#security", "email:ops@example.com")
+	Continue     bool            `json:"continue"`     // 일치해도 다음 규칙을 계속 평가
+}
+
+// AlertRouteMatch 라우팅 규칙 일치 조건
+type AlertRouteMatch struct {
+	Type    []string `json:"type"`    // 알림 유형 (login, ai, system, web_attack 등)
+	Level   []string `json:"level"`   // 심각도 (info, warning, critical)
+	Host    string   `json:"host"`    // 호스트 이름 glob (예: "web-*")
+	Keyword string   `json:"keyword"` // 제목/본문/필드 값에 포함된 문자열 (대소문자 무시)
+
+	Labels map[string]string `json:"labels"` // 알림 필드 이름 → 값 glob (대소문자 무시, 모두 일치해야 함, 예: {"team": "payments"})
+}
+
+// AlertDestination 라우팅 결과 전송 대상
+type AlertDestination struct {
+	Sink   string // 채널 이름
+	Target string // 채널별 받는 곳 (비어 있으면 채널 기본값)
+}
+
+// compiledAlertRoute 검증한 라우팅 규칙
+type compiledAlertRoute struct {
+	name         string
+	types        map[string]bool
+	levels       map[string]bool
+	host         string
+	keyword      string            // 소문자
+	labels       map[string]string // 필드 이름 → 값 glob (소문자)
+	destinations []AlertDestination
+	next         bool
+}
+
+// AlertRouter 알림 라우팅 규칙 목록 (nil 이면 모든 알림을 모든 채널로 전송)
+type AlertRouter struct {
+	routes []compiledAlertRoute
+}
+
+// CompileAlertRoutes 라우팅 규칙 검증 (규칙이 없으면 nil)
+func CompileAlertRoutes(routes []AlertRoute) (*AlertRouter, error) {
+	if len(routes) == 0 {
+		return nil, nil
+	}
+	router := &AlertRouter{}
+	for i, route := range routes {
+		name := route.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		compiled, err := compileAlertRouteMatch("route "+name, route.Match)
+		if err != nil {
+			return nil, err
+		}
+		compiled.name = name
+		compiled.next = route.Continue
+		for _, spec := range route.Destinations {
+			destination, err := parseAlertDestination(spec)
+			if err != nil {
+				return nil, fmt.Errorf("route %s: %v", name, err)
+			}
+			compiled.destinations = append(compiled.destinations, destination)
+		}
+		router.routes = append(router.routes, compiled)
+	}
+	return router, nil
+}
+
+// compileAlertRouteMatch 일치 조건 검증 (label 은 오류 메시지 앞에 붙는 규칙 이름)
+// 서비스 의존 관계 구성 요소도 같은 조건 형식을 사용
+func compileAlertRouteMatch(label string, match AlertRouteMatch) (compiledAlertRoute, error) {
+	compiled := compiledAlertRoute{
+		host:    strings.ToLower(strings.TrimSpace(match.Host)),
+		keyword: strings.ToLower(strings.TrimSpace(match.Keyword)),
+	}
+	if compiled.host != "" {
+		if _, err := filepath.Match(compiled.host, ""); err != nil {
+			return compiled, fmt.Errorf("%s: invalid host pattern %q: %v", label, match.Host, err)
+		}
+	}
+	for name, pattern := range match.Labels {
+		name, pattern = strings.TrimSpace(name), strings.ToLower(strings.TrimSpace(pattern))
+		if name == "" {
+			return compiled, fmt.Errorf("%s: empty label name", label)
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return compiled, fmt.Errorf("%s: invalid label pattern %s=%q: %v", label, name, match.Labels[name], err)
+		}
+		if compiled.labels == nil {
+			compiled.labels = make(map[string]string)
+		}
+		compiled.labels[name] = pattern
+	}
+	for _, alertType := range match.Type {
+		if alertType = strings.ToLower(strings.TrimSpace(alertType)); alertType != "" {
+			if compiled.types == nil {
+				compiled.types = make(map[string]bool)
+			}
+			compiled.types[alertType] = true
+		}
+	}
+	for _, level := range match.Level {
+		level = strings.ToLower(strings.TrimSpace(level))
+		switch level {
+		case "":
+			continue
+		case AlertSeverityInfo, AlertSeverityWarning, AlertSeverityCritical:
+		default:
+			return compiled, fmt.Errorf("%s: unknown level %q (use info, warning, critical)", label, level)
+		}
+		if compiled.levels == nil {
+			compiled.levels = make(map[string]bool)
+		}
+		compiled.levels[level] = true
+	}
+	return compiled, nil
+}
+
+// parseAlertDestination "채널" 또는 "채널:대상" 파싱
+func parseAlertDestination(spec string) (AlertDestination, error) {
+	sink, target, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	destination := AlertDestination{Sink: strings.ToLower(strings.TrimSpace(sink)), Target: strings.TrimSpace(target)}
+	if destination.Sink == "" {
+		return destination, fmt.Errorf("empty destination")
+	}
+	if destination.Target != "" && !routeTargetSinks[destination.Sink] {
+		return destination, fmt.Errorf("destination %q: only email, slack and telegram accept a target", spec)
+	}
+	return destination, nil
+}
+
+// Route 알림의 전송 대상 (일치한 규칙이 없으면 matched=false 로 모든 채널에 전송)
+func (r *AlertRouter) Route(alert Alert) (destinations []AlertDestination, rules []string, matched bool) {
+	if r == nil {
+		return nil, nil, false
+	}
+	seen := make(map[AlertDestination]bool)
+	for _, route := range r.routes {
+		if !route.matches(alert) {
+			continue
+		}
+		matched = true
+		rules = append(rules, route.name)
+		for _, destination := range route.destinations {
+			if !seen[destination] {
+				seen[destination] = true
+				destinations = append(destinations, destination)
+			}
+		}
+		if !route.next {
+			break
+		}
+	}
+	return destinations, rules, matched
+}
+
+// Sinks 규칙에서 사용하는 채널 이름 목록 (중복 제외, 시작 시 설정되지 않은 채널 확인용)
+func (r *AlertRouter) Sinks() []string {
+	if r == nil {
+		return nil
+	}
+	var sinks []string
+	for _, route := range r.routes {
+		for _, destination := range route.destinations {
+			if !containsString(sinks, destination.Sink) {
+				sinks = append(sinks, destination.Sink)
+			}
+		}
+	}
+	return sinks
+}
+
+// matches 알림이 규칙의 모든 조건을 만족하는지 확인
+func (route compiledAlertRoute) matches(alert Alert) bool {
+	if route.types != nil && !route.types[strings.ToLower(alert.Type)] {
+		return false
+	}
+	if route.levels != nil && !route.levels[strings.ToLower(alert.Severity)] {
+		return false
+	}
+	if route.host != "" {
+		if ok, _ := filepath.Match(route.host, strings.ToLower(alert.Host)); !ok {
+			return false
+		}
+	}
+	if route.keyword != "" && !alertContainsKeyword(alert, route.keyword) {
+		return false
+	}
+	for name, pattern := range route.labels {
+		if ok, _ := filepath.Match(pattern, strings.ToLower(alert.Fields[name])); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// alertContainsKeyword 제목/헤드라인/본문/필드 값에 키워드(소문자)가 있는지 확인
+func alertContainsKeyword(alert Alert, keyword string) bool {
+	for _, text := range []string{alert.Title, alert.Headline, alert.Body} {
+		if strings.Contains(strings.ToLower(text), keyword) {
+			return true
+		}
+	}
+	for _, value := range alert.Fields {
+		if strings.Contains(strings.ToLower(value), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalAlertRoutes 두 라우팅 규칙 목록이 같은지 확인 (설정 재로드)
+func equalAlertRoutes(a, b []AlertRoute) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Continue != b[i].Continue ||
+			a[i].Match.Host != b[i].Match.Host || a[i].Match.Keyword != b[i].Match.Keyword ||
+			strings.Join(a[i].Match.Type, "\x00") != strings.Join(b[i].Match.Type, "\x00") ||
+			strings.Join(a[i].Match.Level, "\x00") != strings.Join(b[i].Match.Level, "\x00") ||
+			strings.Join(a[i].Destinations, "\x00") != strings.Join(b[i].Destinations, "\x00") ||
+			!equalStringMaps(a[i].Match.Labels, b[i].Match.Labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalStringMaps 두 문자열 맵이 같은지 확인
+func equalStringMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, ok := b[key]; !ok || other != value {
+			return false
+		}
+	}
+	return true
+}
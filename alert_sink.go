@@ -0,0 +1,758 @@
+/*
+Alert Sink Module
+=================
+
+알림 전송 채널(Sink) 추상화 및 중앙 디스패처
+
+주요 기능:
+- AlertSink 인터페이스: 이메일, Slack, 웹훅 등 알림 채널 공통 규약
+- AlertDispatcher: 설정된 모든 채널로 알림 팬아웃 (비동기 전송, 채널별 오류 기록, 채널별 상세 수준)
+- 종료 시 Drain 으로 전송 중인 알림이 끝날 때까지 대기 (최대 대기 시간은 context 로 지정)
+- 최근 전송한 알림 보관 (관리 API 의 /alerts/recent)
+- AlertThrottler: 알림 유형별 중복 알림 제한 및 반복 횟수 요약 (alert_throttle.go)
+- AlertRouter: 유형/심각도/호스트/키워드별 전송 채널 지정 (alert_routes.go)
+- AlertSnoozer: 이메일 링크/Slack 버튼으로 끈(snooze/ack) 알림은 채널로 보내지 않음 (alert_actions.go)
+- MaintenanceSchedule: 외부 캘린더의 점검 일정 중 알림 심각도 낮추기/묶기 (maintenance.go)
+- QuietHours: 조용한 시간 / 고정 점검 창 동안 채널별로 중요하지 않은 알림 보류 후 요약 (quiet_hours.go)
+- DailyDigest: 들어온 모든 알림을 일일 요약용으로 집계 (daily_digest.go)
+- AlertResolver: 조건 해소 시 인시던트를 자동 해결하는 채널용 선택 인터페이스 (PagerDuty)
+- Probe: 제한/라우팅 없이 모든 채널로 바로 보내고 채널별 결과 확인 (알림 채널 카나리, canary.go)
+- WebhookSink: 임의의 HTTP 엔드포인트로 JSON 알림 전송 (-webhook-url)
+
+알림 유형:
+- login, ai, system, error, critical, boot, report, test
+*/
+package main
+
+import (
+	"bytes"         // 요청 본문 버퍼
+	"context"       // 종료 시 전송 대기 시간
+	"encoding/json" // JSON 인코딩
+	"fmt"           // 형식화된 I/O
+	"io"            // 웹훅 응답 읽기
+	"net/http"      // HTTP 클라이언트
+	"os"            // 호스트명 조회
+	"strings"       // 채널 이름 정규화
+	"sync"          // 동기화 (뮤텍스)
+	"time"          // 시간 처리
+)
+
+// 알림 유형
+const (
+	AlertTypeLogin           = "login"
+	AlertTypeAI              = "ai"
+	AlertTypeSystem          = "system"
+	AlertTypeError           = "error"
+	AlertTypeCritical        = "critical"
+	AlertTypeBoot            = "boot"
+	AlertTypeReport          = "report"
+	AlertTypeTest            = "test"
+	AlertTypeBruteForce      = "brute_force"
+	AlertTypeQuota           = "quota"
+	AlertTypeDBPrivilege     = "db_privilege"
+	AlertTypeSQLInjection    = "sql_injection"
+	AlertTypeWebAttack       = "web_attack"
+	AlertTypeExfiltration    = "exfiltration"
+	AlertTypeSLO             = "slo"
+	AlertTypeOutputBuffer    = "output_buffer"
+	AlertTypeParserUnknown   = "parser_unknown"
+	AlertTypeBaseline        = "baseline"
+	AlertTypeIncident        = "incident"
+	AlertTypeMaintenance     = "maintenance"
+	AlertTypeDigest          = "digest"
+	AlertTypeQuietHours      = "quiet_hours"
+	AlertTypeMonitorError    = "monitor_error"     // 모니터 자신의 오류 (-output-self-watch)
+	AlertTypeCanary          = "canary"            // 알림 채널 점검 메시지와 점검 실패 (-canary)
+	AlertTypeAccessErrorRate = "access_error_rate" // 접근 로그 통계 모드의 5xx 오류율 (-access-stats)
+	AlertTypeCICD            = "cicd"              // CI/CD 반복 작업 실패, 예상 밖 러너 등록, 빌드 출력 비밀 노출 (-cicd-watch)
+)
+
+// AlertTypes 모든 알림 유형 (Slack /sysmon silence 의 유형 확인용)
+var AlertTypes = []string{
+	AlertTypeLogin, AlertTypeAI, AlertTypeSystem, AlertTypeError, AlertTypeCritical,
+	AlertTypeBoot, AlertTypeReport, AlertTypeTest, AlertTypeBruteForce, AlertTypeQuota,
+	AlertTypeDBPrivilege, AlertTypeSQLInjection, AlertTypeWebAttack, AlertTypeExfiltration, AlertTypeSLO,
+	AlertTypeOutputBuffer, AlertTypeParserUnknown, AlertTypeBaseline, AlertTypeIncident, AlertTypeMaintenance,
+	AlertTypeDigest, AlertTypeQuietHours, AlertTypeMonitorError, AlertTypeCanary,
+	AlertTypeAccessErrorRate, AlertTypeCICD,
+}
+
+// RecentAlertLimit 최근 알림 조회용으로 메모리에 보관하는 알림 수
+const RecentAlertLimit = 100
+
+// 알림 심각도
+const (
+	AlertSeverityInfo     = "info"
+	AlertSeverityWarning  = "warning"
+	AlertSeverityCritical = "critical"
+)
+
+// Alert 모든 알림 채널에 전달되는 공통 알림
+type Alert struct {
+	Type      string            `json:"type"`     // 알림 유형 (login, ai, system 등)
+	Severity  string            `json:"severity"` // 심각도 (info, warning, critical)
+	Title     string            `json:"title"`    // 제목 (이메일 제목)
+	Body      string            `json:"body"`     // 본문 (이메일 본문)
+	Host      string            `json:"host"`
+	Fields    map[string]string `json:"fields,omitempty"` // 구조화된 상세 정보
+	Timestamp time.Time         `json:"timestamp"`
+
+	// 구조화된 본문 (alert_render.go) - 채널마다 상세 수준에 맞춰 렌더링
+	// 섹션이 있으면 Body 는 디스패처가 전체(full) 텍스트로 채움
+	Headline string         `json:"headline,omitempty"` // 본문/메시지 첫 줄 (비어 있으면 Title)
+	Sections []AlertSection `json:"sections,omitempty"`
+
+	// 인시던트 키 - 같은 키의 후속/복구 알림은 이메일에서 하나의 스레드로 묶임 (비어 있으면 단독 메시지)
+	Thread string `json:"thread,omitempty"`
+
+	// 알림 고유 ID - 디스패처가 설정 (전송 저널 중복 방지, 웹훅 받는 쪽 중복 제거용)
+	ID string `json:"id,omitempty"`
+
+	// 이 알림을 받는 채널의 상세 수준 (summary, full) - 디스패처가 채널별로 설정
+	Detail string `json:"-"`
+
+	// 라우팅 규칙이 지정한 받는 곳 (email 수신자, Slack 채널, Telegram 채팅 ID) - 비어 있으면 채널 기본값
+	Target string `json:"-"`
+
+	// Slack 전용 서식 메시지 (섹션이 없는 알림용, nil이면 Title/Body/Fields로 기본 메시지 생성)
+	Slack *SlackMessage `json:"-"`
+}
+
+// alertThreadKey 인시던트 키 생성 (빈 값은 건너뛰고 "/" 로 연결)
+func alertThreadKey(parts ...string) string {
+	nonEmpty := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, "/")
+}
+
+// AlertSink 알림 전송 채널 인터페이스
+type AlertSink interface {
+	Send(alert Alert) error
+}
+
+// AlertResolver 알림 조건이 해소되었을 때 열린 인시던트를 해결할 수 있는 채널
+// AlertSink 중 이 인터페이스를 구현한 채널만 AlertDispatcher.Resolve 호출을 전달받음
+type AlertResolver interface {
+	Resolve(alertType, host string) error
+}
+
+// namedSink 로그 출력을 위해 이름이 붙은 알림 채널
+type namedSink struct {
+	name string
+	sink AlertSink
+}
+
+// AlertDispatcher 설정된 모든 알림 채널로 알림을 전달하는 중앙 디스패처
+type AlertDispatcher struct {
+	sinks       []namedSink
+	detail      map[string]string     // 채널 이름별 상세 수준 (summary, full)
+	recent      []Alert               // 최근 전송한 알림 (최대 RecentAlertLimit, 오래된 순)
+	quota       *TenantQuotaTracker   // 테넌트 알림 한도 (nil 이면 제한 없음)
+	throttler   *AlertThrottler       // 유형별 중복 알림 제한
+	router      *AlertRouter          // 알림 라우팅 규칙 (nil 이면 모든 채널로 전송)
+	incidents   *DependencyCorrelator // 서비스 의존 관계 근본 원인 추정 (nil 이면 사용 안 함)
+	snoozes     *AlertSnoozer         // 이메일 링크/Slack 버튼으로 끈(snooze/ack) 알림
+	maintenance *MaintenanceSchedule  // 점검 일정 중 심각도 낮추기/묶기 (nil 이면 사용 안 함)
+	quietHours  *QuietHours           // 조용한 시간 중 채널별 알림 보류 (nil 이면 사용 안 함)
+	digest      *DailyDigest          // 일일 요약 집계 (nil 이면 사용 안 함)
+	observer    func(Alert)           // 전송 판단을 마친 알림 관찰자 (재처리 요약 보고서)
+	suppress    bool                  // true 면 관찰자에만 전달하고 채널로 보내지 않음 (재처리 드라이런)
+	gate        func() bool           // false 를 반환하면 채널로 보내지 않음 (고가용성 대기 인스턴스, nil 이면 항상 전송)
+	journal     *DeliveryJournal      // 전송 선기록 저널 (nil 이면 기록 없이 한 번만 전송)
+	scope       string                // 저널 기록 범위 (테넌트 ID, 운영자는 비어 있음)
+	sending     inFlight              // 전송 중인 비동기 알림/해결 요청 (종료 시 Drain 으로 대기)
+	mutex       sync.RWMutex
+	logger      Logger
+}
+
+// NewAlertDispatcher 새로운 알림 디스패처 생성
+func NewAlertDispatcher(logger Logger) *AlertDispatcher {
+	ad := &AlertDispatcher{detail: DefaultAlertDetail, snoozes: NewAlertSnoozer(), logger: logger}
+	ad.throttler = NewAlertThrottler(ad.deliver, logger)
+	return ad
+}
+
+// SetDetailLevels 채널별 알림 상세 수준 설정 (지정하지 않은 채널은 기본값 유지)
+// 잘못된 값이 있으면 기존 설정을 그대로 유지
+func (ad *AlertDispatcher) SetDetailLevels(levels map[string]string) error {
+	detail := make(map[string]string, len(DefaultAlertDetail)+len(levels))
+	for name, level := range DefaultAlertDetail {
+		detail[name] = level
+	}
+	for name, level := range levels {
+		parsed, err := ParseAlertDetail(level)
+		if err != nil {
+			return fmt.Errorf("invalid detail level for %s: %v", name, err)
+		}
+		detail[strings.ToLower(name)] = parsed
+	}
+
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+	ad.detail = detail
+	return nil
+}
+
+// SetThrottleIntervals 알림 유형별 중복 제한 간격(분) 설정 (지정하지 않은 유형은 기본값, 0 이면 제한 안 함)
+func (ad *AlertDispatcher) SetThrottleIntervals(intervals map[string]int) error {
+	return ad.throttler.SetIntervals(intervals)
+}
+
+// SetRoutes 알림 라우팅 규칙 교체 (잘못된 규칙이 있으면 기존 규칙을 그대로 유지)
+func (ad *AlertDispatcher) SetRoutes(routes []AlertRoute) error {
+	router, err := CompileAlertRoutes(routes)
+	if err != nil {
+		return err
+	}
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+	ad.router = router
+	return nil
+}
+
+// SetDependencies 서비스 의존 관계 교체 (잘못된 설정이면 기존 의존 관계를 그대로 유지)
+func (ad *AlertDispatcher) SetDependencies(config ServiceDependencyConfig) error {
+	correlator, err := NewDependencyCorrelator(config)
+	if err != nil {
+		return err
+	}
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+	ad.incidents = correlator
+	return nil
+}
+
+// SetMaintenance 점검 일정 교체 (nil 이면 점검 일정 없이 전송)
+func (ad *AlertDispatcher) SetMaintenance(schedule *MaintenanceSchedule) {
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+	ad.maintenance = schedule
+}
+
+// Maintenance 현재 점검 일정 (관리 API 조회용, 없으면 nil)
+func (ad *AlertDispatcher) Maintenance() *MaintenanceSchedule {
+	ad.mutex.RLock()
+	defer ad.mutex.RUnlock()
+	return ad.maintenance
+}
+
+// SetQuietHours 조용한 시간 교체 (이전 창에 모아 둔 알림은 호출자가 Stop 으로 요약 전송)
+func (ad *AlertDispatcher) SetQuietHours(quiet *QuietHours) {
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+	ad.quietHours = quiet
+}
+
+// QuietHours 현재 조용한 시간 설정 (관리 API 조회, 종료 시 요약 전송용, 없으면 nil)
+func (ad *AlertDispatcher) QuietHours() *QuietHours {
+	ad.mutex.RLock()
+	defer ad.mutex.RUnlock()
+	return ad.quietHours
+}
+
+// SetDigest 일일 요약 집계 설정 (중복 제한/점검 중 보류 전의 모든 알림을 기록)
+func (ad *AlertDispatcher) SetDigest(digest *DailyDigest) {
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+	ad.digest = digest
+}
+
+// Snoozes 이메일 링크/Slack 버튼으로 끈 알림 목록 (링크 동작 적용, GET /alerts/snoozes)
+func (ad *AlertDispatcher) Snoozes() *AlertSnoozer {
+	return ad.snoozes
+}
+
+// Observe 제한/중복 판단을 마친 알림마다 fn 호출 (suppress 면 채널로 보내지 않고 fn 에만 전달)
+func (ad *AlertDispatcher) Observe(fn func(Alert), suppress bool) {
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+	ad.observer = fn
+	ad.suppress = suppress
+}
+
+// SetDeliveryGate 채널 전송 여부 판단 함수 설정 (고가용성 리더 선출)
+// 전송하지 않는 알림도 제한/중복 판단과 최근 알림 기록은 그대로 거치므로 리더가 바뀌어도 상태가 이어짐
+func (ad *AlertDispatcher) SetDeliveryGate(gate func() bool) {
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+	ad.gate = gate
+}
+
+// SetJournal 전송 선기록 저널 설정 (채널 전송을 먼저 기록하고 확인될 때까지 재시도, 재시작 시 RecoverJournal 로 다시 전송)
+func (ad *AlertDispatcher) SetJournal(journal *DeliveryJournal, scope string) {
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+	ad.journal = journal
+	ad.scope = scope
+}
+
+// RecoverJournal 이전 실행에서 확인되지 않은 이 디스패처 범위의 전송을 다시 보냄 (다시 보낸 수)
+func (ad *AlertDispatcher) RecoverJournal() int {
+	ad.mutex.RLock()
+	journal, scope := ad.journal, ad.scope
+	sinks := append([]namedSink(nil), ad.sinks...)
+	ad.mutex.RUnlock()
+	if journal == nil {
+		return 0
+	}
+	return journal.Recover(scope, func(name string) AlertSink {
+		for _, s := range sinks {
+			if s.name == name {
+				return s.sink
+			}
+		}
+		return nil
+	})
+}
+
+// MissingRouteSinks 라우팅 규칙이 사용하지만 설정되지 않은 채널 이름
+func (ad *AlertDispatcher) MissingRouteSinks() []string {
+	ad.mutex.RLock()
+	defer ad.mutex.RUnlock()
+	var missing []string
+	for _, name := range ad.router.Sinks() {
+		found := false
+		for _, s := range ad.sinks {
+			if s.name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// AddSink 알림 채널 추가
+func (ad *AlertDispatcher) AddSink(name string, sink AlertSink) {
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+	ad.sinks = append(ad.sinks, namedSink{name: name, sink: sink})
+}
+
+// SetQuota 테넌트 알림 한도 설정 (한도 초과 알림 자체는 제한하지 않음)
+func (ad *AlertDispatcher) SetQuota(quota *TenantQuotaTracker) {
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+	ad.quota = quota
+}
+
+// HasSinks 알림 채널이 하나 이상 설정되어 있는지 확인 (관찰자가 있으면 채널이 없어도 알림을 만들도록 true)
+func (ad *AlertDispatcher) HasSinks() bool {
+	ad.mutex.RLock()
+	defer ad.mutex.RUnlock()
+	return len(ad.sinks) > 0 || ad.observer != nil
+}
+
+// SinkNames 설정된 알림 채널 이름 목록 (드라이런이면 "dry-run")
+func (ad *AlertDispatcher) SinkNames() []string {
+	ad.mutex.RLock()
+	defer ad.mutex.RUnlock()
+	if ad.suppress {
+		return []string{"dry-run"}
+	}
+	names := make([]string, 0, len(ad.sinks))
+	for _, s := range ad.sinks {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// Dispatch 모든 알림 채널로 알림을 비동기 전송
+// 제한 간격 안의 중복 알림은 보내지 않고 간격이 끝날 때 요약 알림으로 전송
+// 채널별 전송 실패는 다른 채널에 영향을 주지 않고 로그로만 기록
+func (ad *AlertDispatcher) Dispatch(alert Alert) {
+	if alert.Timestamp.IsZero() {
+		alert.Timestamp = time.Now()
+	}
+	if alert.Host == "" {
+		alert.Host, _ = os.Hostname()
+	}
+
+	// 중복 알림 제한 전에 장애를 기록해야 제한 중인 구성 요소의 장애도 인시던트에 반영됨
+	ad.mutex.RLock()
+	incidents, maintenance, digest := ad.incidents, ad.maintenance, ad.digest
+	ad.mutex.RUnlock()
+	incident := incidents.Observe(alert, time.Now())
+	digest.RecordAlert(alert)
+
+	// 점검 중이면 심각도를 낮추거나 점검이 끝날 때 요약 알림으로 묶음
+	alert, held := maintenance.Apply(alert, time.Now())
+	if held {
+		ad.logger.Infof("🛠️  Maintenance window, %s alert held for summary: %s", alert.Type, alert.Title)
+	} else if alert, allowed := ad.throttler.Allow(alert, time.Now()); allowed {
+		ad.deliver(alert)
+	}
+	if incident != nil {
+		ad.Dispatch(*incident)
+	}
+}
+
+// deliver 제한 판단을 마친 알림을 최근 알림에 기록하고 채널로 전송
+func (ad *AlertDispatcher) deliver(alert Alert) {
+	if alert.Body == "" && len(alert.Sections) > 0 {
+		alert.Body = alert.RenderText(AlertDetailFull)
+	}
+	if alert.ID == "" {
+		alert.ID = alertID(alert)
+	}
+
+	ad.mutex.Lock()
+	ad.recent = append(ad.recent, alert)
+	if len(ad.recent) > RecentAlertLimit {
+		ad.recent = ad.recent[len(ad.recent)-RecentAlertLimit:]
+	}
+	sinks := append([]namedSink(nil), ad.sinks...)
+	detail := ad.detail
+	quota := ad.quota
+	router := ad.router
+	quiet := ad.quietHours
+	observer, suppress := ad.observer, ad.suppress
+	gate := ad.gate
+	journal, scope := ad.journal, ad.scope
+	ad.mutex.Unlock()
+
+	if observer != nil {
+		observer(alert)
+	}
+	if suppress {
+		return
+	}
+	// 이메일 링크로 끈 알림은 최근 알림에만 남기고 채널로 보내지 않음
+	if ad.snoozes.Suppressed(alert, time.Now()) {
+		ad.logger.Infof("🔕 Snoozed %s alert not sent: %s", alert.Type, alert.Title)
+		return
+	}
+	if gate != nil && !gate() {
+		ad.logger.Infof("🕒 Standby instance, %s alert not sent: %s", alert.Type, alert.Title)
+		return
+	}
+
+	// 한도를 넘은 알림은 최근 알림에만 남기고 채널로 보내지 않음
+	metered := quota != nil && alert.Type != AlertTypeQuota
+	if metered && !quota.AllowNotification() {
+		ad.logger.Infof("🚫 Notification quota exceeded, %s alert not sent: %s", alert.Type, alert.Title)
+		return
+	}
+
+	for _, delivery := range routeDeliveries(router, alert, sinks) {
+		s := delivery.sink
+		// 조용한 시간 창이면 이 채널로는 보내지 않고 창이 끝날 때 요약
+		if quiet.Hold(s.name, delivery.target, alert, time.Now()) {
+			ad.logger.Infof("🌙 Quiet hours, %s alert held for %s summary: %s", alert.Type, s.name, alert.Title)
+			continue
+		}
+		if metered && !quota.AllowChannel(s.name) {
+			continue
+		}
+		ad.send(s, delivery.target, alert, detail, journal, scope)
+	}
+}
+
+// deliverTo 채널 하나로 바로 전송 (조용한 시간 요약 알림, 라우팅/중복 제한/조용한 시간 없이 최근 알림에 기록)
+func (ad *AlertDispatcher) deliverTo(name, target string, alert Alert) {
+	if alert.Timestamp.IsZero() {
+		alert.Timestamp = time.Now()
+	}
+	if alert.Host == "" {
+		alert.Host, _ = os.Hostname()
+	}
+	if alert.Body == "" && len(alert.Sections) > 0 {
+		alert.Body = alert.RenderText(AlertDetailFull)
+	}
+	if alert.ID == "" {
+		alert.ID = alertID(alert)
+	}
+
+	ad.mutex.Lock()
+	ad.recent = append(ad.recent, alert)
+	if len(ad.recent) > RecentAlertLimit {
+		ad.recent = ad.recent[len(ad.recent)-RecentAlertLimit:]
+	}
+	sinks := append([]namedSink(nil), ad.sinks...)
+	detail := ad.detail
+	journal, scope := ad.journal, ad.scope
+	ad.mutex.Unlock()
+
+	for _, s := range sinks {
+		if s.name == name {
+			ad.send(s, target, alert, detail, journal, scope)
+		}
+	}
+}
+
+// send 채널별 받는 곳/상세 수준을 적용해 전송 (저널이 있으면 선기록 후 재시도, 없으면 비동기 한 번)
+func (ad *AlertDispatcher) send(s namedSink, target string, alert Alert, detail map[string]string, journal *DeliveryJournal, scope string) {
+	alert.Target = target
+	alert.Detail = AlertDetailFull
+	if level, ok := detail[s.name]; ok {
+		alert.Detail = level
+	}
+	if journal != nil {
+		journal.Send(scope, s.name, s.sink, alert)
+		return
+	}
+	ad.sending.Add()
+	go func() {
+		defer ad.sending.Done()
+		if err := s.sink.Send(alert); err != nil {
+			ad.logger.Errorf("❌ Failed to send %s alert via %s: %v", alert.Type, s.name, err)
+		}
+	}()
+}
+
+// Probe 모든 채널로 알림을 바로 보내고 채널별 결과 반환 (알림 채널 카나리, 채널마다 동시에 send 호출)
+// 채널 자체가 동작하는지 확인하므로 라우팅, 중복 제한, 점검/조용한 시간, 알림 끄기, 저널을 거치지 않음
+// 드라이런이거나 고가용성 대기 인스턴스면 보내지 않고 false
+func (ad *AlertDispatcher) Probe(alert Alert, send func(name string, sink AlertSink, alert Alert) error) (map[string]error, bool) {
+	if alert.Timestamp.IsZero() {
+		alert.Timestamp = time.Now()
+	}
+	if alert.Host == "" {
+		alert.Host, _ = os.Hostname()
+	}
+	if alert.Body == "" && len(alert.Sections) > 0 {
+		alert.Body = alert.RenderText(AlertDetailFull)
+	}
+	if alert.ID == "" {
+		alert.ID = alertID(alert)
+	}
+
+	ad.mutex.RLock()
+	sinks := append([]namedSink(nil), ad.sinks...)
+	detail := ad.detail
+	suppress, gate := ad.suppress, ad.gate
+	ad.mutex.RUnlock()
+	if suppress || (gate != nil && !gate()) {
+		return nil, false
+	}
+
+	results := make(map[string]error, len(sinks))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, s := range sinks {
+		probe := alert
+		probe.Detail = AlertDetailFull
+		if level, ok := detail[s.name]; ok {
+			probe.Detail = level
+		}
+		wg.Add(1)
+		go func(s namedSink) {
+			defer wg.Done()
+			err := send(s.name, s.sink, probe)
+			mutex.Lock()
+			results[s.name] = err
+			mutex.Unlock()
+		}(s)
+	}
+	wg.Wait()
+	return results, true
+}
+
+// Drain 전송 중인 알림이 모두 끝날 때까지 대기 (종료 시, ctx 가 끝나면 남은 건수와 함께 에러)
+// 저널을 사용하면 저널의 전송 시도도 기다림 (끝나지 않은 전송은 다음 시작 시 다시 전송)
+func (ad *AlertDispatcher) Drain(ctx context.Context) error {
+	if err := ad.sending.Wait(ctx); err != nil {
+		return err
+	}
+	ad.mutex.RLock()
+	journal := ad.journal
+	ad.mutex.RUnlock()
+	if journal != nil {
+		return journal.Drain(ctx)
+	}
+	return nil
+}
+
+// sinkDelivery 라우팅 결과 채널별 전송 (target 이 비어 있으면 채널 기본 받는 곳)
+type sinkDelivery struct {
+	sink   namedSink
+	target string
+}
+
+// routeDeliveries 라우팅 규칙에 따른 전송 목록 (일치한 규칙이 없으면 모든 채널, 설정되지 않은 대상 채널은 건너뜀)
+func routeDeliveries(router *AlertRouter, alert Alert, sinks []namedSink) []sinkDelivery {
+	destinations, _, matched := router.Route(alert)
+	if !matched {
+		deliveries := make([]sinkDelivery, 0, len(sinks))
+		for _, s := range sinks {
+			deliveries = append(deliveries, sinkDelivery{sink: s})
+		}
+		return deliveries
+	}
+
+	var deliveries []sinkDelivery
+	for _, destination := range destinations {
+		for _, s := range sinks {
+			if s.name == destination.Sink {
+				deliveries = append(deliveries, sinkDelivery{sink: s, target: destination.Target})
+			}
+		}
+	}
+	return deliveries
+}
+
+// Recent 최근 전송한 알림 (최신 순, alertType 이 비어 있지 않으면 해당 유형만, limit 이하)
+func (ad *AlertDispatcher) Recent(alertType string, limit int) []Alert {
+	ad.mutex.RLock()
+	defer ad.mutex.RUnlock()
+
+	alerts := make([]Alert, 0, limit)
+	for i := len(ad.recent) - 1; i >= 0 && len(alerts) < limit; i-- {
+		if alertType == "" || ad.recent[i].Type == alertType {
+			alerts = append(alerts, ad.recent[i])
+		}
+	}
+	return alerts
+}
+
+// Resolve 알림 유형/호스트의 조건 해소를 AlertResolver 채널로 비동기 전달
+// 열린 인시던트가 없으면 각 채널이 무시하므로 조건이 정상일 때마다 호출해도 됨
+func (ad *AlertDispatcher) Resolve(alertType, host string) {
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+
+	ad.mutex.RLock()
+	sinks := append([]namedSink(nil), ad.sinks...)
+	suppress, gate := ad.suppress, ad.gate
+	ad.mutex.RUnlock()
+	if suppress || (gate != nil && !gate()) {
+		return
+	}
+
+	for _, s := range sinks {
+		resolver, ok := s.sink.(AlertResolver)
+		if !ok {
+			continue
+		}
+		ad.sending.Add()
+		go func(name string, resolver AlertResolver) {
+			defer ad.sending.Done()
+			if err := resolver.Resolve(alertType, host); err != nil {
+				ad.logger.Errorf("❌ Failed to resolve %s alert via %s: %v", alertType, name, err)
+			}
+		}(s.name, resolver)
+	}
+}
+
+// inFlight 진행 중인 비동기 작업 수 (대기 중에도 새 작업을 추가할 수 있어 sync.WaitGroup 대신 사용)
+type inFlight struct {
+	count int
+	idle  chan struct{} // count 가 0 이 되면 닫힘 (작업이 있을 때만 생성)
+	mutex sync.Mutex
+}
+
+// Add 작업 시작
+func (f *inFlight) Add() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.count == 0 {
+		f.idle = make(chan struct{})
+	}
+	f.count++
+}
+
+// Done 작업 완료
+func (f *inFlight) Done() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.count--
+	if f.count == 0 {
+		close(f.idle)
+	}
+}
+
+// Wait 진행 중인 작업이 모두 끝날 때까지 대기 (ctx 가 먼저 끝나면 남은 건수와 함께 에러)
+func (f *inFlight) Wait(ctx context.Context) error {
+	for {
+		f.mutex.Lock()
+		count, idle := f.count, f.idle
+		f.mutex.Unlock()
+		if count == 0 {
+			return nil
+		}
+		select {
+		case <-idle:
+			// 기다리는 동안 시작한 작업이 있을 수 있으므로 다시 확인
+		case <-ctx.Done():
+			return fmt.Errorf("%d in flight: %v", count, ctx.Err())
+		}
+	}
+}
+
+// WebhookSink 범용 JSON 웹훅 알림 채널
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// webhookResponseLimit 읽는 웹훅 응답 본문 최대 크기
+const webhookResponseLimit = 64 << 10
+
+// NewWebhookSink 새로운 웹훅 알림 채널 생성
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// webhookPayload 웹훅으로 전송되는 JSON 본문
+type webhookPayload struct {
+	Source  string `json:"source"`
+	Version string `json:"version"`
+	Alert
+}
+
+// Send 알림을 JSON으로 POST 전송 (body 는 채널 상세 수준에 맞춰 렌더링)
+func (ws *WebhookSink) Send(alert Alert) error {
+	_, err := ws.post(alert)
+	return err
+}
+
+// SendEcho 전송 후 응답 본문에 알림 ID 가 있는지 확인 (알림 카나리 canary.webhook_echo, 받은 본문을 돌려주는 수신기용)
+func (ws *WebhookSink) SendEcho(alert Alert) error {
+	body, err := ws.post(alert)
+	if err != nil {
+		return err
+	}
+	if !bytes.Contains(body, []byte(alert.ID)) {
+		return fmt.Errorf("webhook response did not echo id %s", alert.ID)
+	}
+	return nil
+}
+
+// post 알림 본문을 POST 전송하고 응답 본문 반환 (2xx 가 아니면 에러)
+func (ws *WebhookSink) post(alert Alert) ([]byte, error) {
+	alert.Body = alert.RenderText(alert.Detail)
+	payload, err := json.Marshal(webhookPayload{
+		Source:  AppName,
+		Version: AppVersion,
+		Alert:   alert,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %v", err)
+	}
+
+	resp, err := ws.client.Post(ws.url, "application/json", bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to send webhook request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("webhook returned status: %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook response: %v", err)
+	}
+	return body, nil
+}
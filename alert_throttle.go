@@ -0,0 +1,211 @@
+/*
+Alert Throttle Module
+=====================
+
+알림 유형별 중복 알림 제한 및 반복 알림 요약
+
+같은 알림(유형, 인시던트 키, 제목이 같은 알림)이 간격 안에 반복되면 개별 전송 대신
+개수를 세어 두었다가 간격이 끝날 때 "N occurrences in the last X minutes" 요약 알림 한 건으로 보냅니다.
+
+주요 기능:
+- 알림 유형별 제한 간격 (기본: error 5분, critical 2분, ai 10분, system 30분, 0 이면 제한 안 함)
+- 간격 안의 중복 알림은 채널로 보내지 않고 발생 횟수만 기록
+- 간격이 끝나면 마지막 알림 내용에 반복 요약 섹션을 붙여 한 번 전송
+- 설정 파일 alerts.intervals 로 유형별 간격 변경 (재로드 시 즉시 적용)
+
+로그인 알림은 LoginDetector 가 사용자/IP/상태별로 별도 제한하므로 기본 목록에 없음
+*/
+package main
+
+import (
+	"fmt"  // 형식화된 I/O
+	"sync" // 동기화 (뮤텍스)
+	"time" // 시간 처리
+)
+
+// DefaultAlertIntervals 알림 유형별 기본 중복 제한 간격 (분, 목록에 없는 유형은 제한 안 함)
+var DefaultAlertIntervals = map[string]int{
+	AlertTypeError:    5,
+	AlertTypeCritical: 2,
+	AlertTypeAI:       10,
+	AlertTypeSystem:   30,
+}
+
+// maxAlertThrottleKeys 기록을 정리하기 시작하는 알림 키 수
+const maxAlertThrottleKeys = 1000
+
+// alertThrottleRecord 알림 키별 전송/생략 기록
+type alertThrottleRecord struct {
+	alertType  string
+	sent       time.Time   // 마지막으로 채널에 전송한 시각 (요약 포함)
+	suppressed int         // 마지막 전송 이후 생략한 알림 수
+	first      time.Time   // 생략한 첫 알림 시각
+	last       Alert       // 생략한 마지막 알림 (요약 알림의 본문)
+	timer      *time.Timer // 간격 종료 시 요약 전송 타이머 (생략한 알림이 없으면 nil)
+}
+
+// AlertThrottler 알림 유형별 간격으로 중복 알림을 제한하고 반복 횟수를 요약
+type AlertThrottler struct {
+	intervals map[string]time.Duration
+	records   map[string]*alertThrottleRecord
+	flush     func(Alert) // 간격 종료 시 요약 알림 전송
+	mutex     sync.Mutex
+	logger    Logger
+}
+
+// NewAlertThrottler 기본 간격으로 제한기 생성 (요약 알림은 flush 로 전송)
+func NewAlertThrottler(flush func(Alert), logger Logger) *AlertThrottler {
+	at := &AlertThrottler{
+		records: make(map[string]*alertThrottleRecord),
+		flush:   flush,
+		logger:  logger,
+	}
+	at.SetIntervals(nil)
+	return at
+}
+
+// SetIntervals 알림 유형별 제한 간격(분) 설정 (지정하지 않은 유형은 기본값 유지)
+// 음수가 있으면 기존 설정을 그대로 유지
+func (at *AlertThrottler) SetIntervals(minutes map[string]int) error {
+	intervals := make(map[string]time.Duration, len(DefaultAlertIntervals)+len(minutes))
+	for alertType, value := range DefaultAlertIntervals {
+		intervals[alertType] = time.Duration(value) * time.Minute
+	}
+	for alertType, value := range minutes {
+		if value < 0 {
+			return fmt.Errorf("invalid throttle interval for %s: %d (must be 0 or more minutes)", alertType, value)
+		}
+		intervals[alertType] = time.Duration(value) * time.Minute
+	}
+
+	at.mutex.Lock()
+	defer at.mutex.Unlock()
+	at.intervals = intervals
+	return nil
+}
+
+// Interval 알림 유형의 제한 간격 (0 이면 제한 안 함)
+func (at *AlertThrottler) Interval(alertType string) time.Duration {
+	at.mutex.Lock()
+	defer at.mutex.Unlock()
+	return at.intervals[alertType]
+}
+
+// Allow 알림 전송 여부 판단
+// 간격 안의 중복 알림은 false 를 반환하고 간격 종료 시 요약으로 전송
+// 요약이 나가기 전에 간격이 끝나 새 알림이 허용되면 생략한 횟수를 그 알림에 붙여 반환
+func (at *AlertThrottler) Allow(alert Alert, now time.Time) (Alert, bool) {
+	at.mutex.Lock()
+	defer at.mutex.Unlock()
+
+	interval := at.intervals[alert.Type]
+	if interval <= 0 {
+		return alert, true
+	}
+
+	key := alertThrottleKey(alert)
+	record, ok := at.records[key]
+	if !ok {
+		at.prune(now)
+		at.records[key] = &alertThrottleRecord{alertType: alert.Type, sent: now}
+		return alert, true
+	}
+
+	if elapsed := now.Sub(record.sent); elapsed < interval {
+		if record.suppressed == 0 {
+			record.first = now
+			record.timer = time.AfterFunc(interval-elapsed, func() { at.expire(key) })
+			at.logger.Infof("🔕 Throttling repeated %s alert for %v: %s", alert.Type, interval, alert.Title)
+		}
+		record.suppressed++
+		record.last = alert
+		return alert, false
+	}
+
+	if record.suppressed > 0 {
+		record.timer.Stop()
+		alert = summarizeAlert(alert, record.suppressed+1, record.first, now.Sub(record.sent))
+	}
+	*record = alertThrottleRecord{alertType: alert.Type, sent: now}
+	return alert, true
+}
+
+// expire 간격이 끝난 알림 키의 생략 횟수를 요약 알림으로 전송
+func (at *AlertThrottler) expire(key string) {
+	at.mutex.Lock()
+	record, ok := at.records[key]
+	if !ok || record.suppressed == 0 {
+		at.mutex.Unlock()
+		return
+	}
+	now := time.Now()
+	summary := summarizeAlert(record.last, record.suppressed, record.first, now.Sub(record.sent))
+	*record = alertThrottleRecord{alertType: record.alertType, sent: now}
+	at.mutex.Unlock()
+
+	at.flush(summary)
+}
+
+// prune 기록이 많아지면 간격이 지나고 생략한 알림이 없는 항목 정리 (잠금 상태에서 호출)
+func (at *AlertThrottler) prune(now time.Time) {
+	if len(at.records) < maxAlertThrottleKeys {
+		return
+	}
+	for key, record := range at.records {
+		if record.suppressed == 0 && now.Sub(record.sent) >= at.intervals[record.alertType] {
+			delete(at.records, key)
+		}
+	}
+}
+
+// alertThrottleKey 같은 알림으로 취급하는 키 (유형, 인시던트 키, 제목)
+func alertThrottleKey(alert Alert) string {
+	return alert.Type + "|" + alert.Thread + "|" + alert.Title
+}
+
+// summarizeAlert 알림에 반복 발생 요약을 붙인 알림 생성
+// occurrences 는 window 동안 발생한 같은 알림 수
+func summarizeAlert(alert Alert, occurrences int, first time.Time, window time.Duration) Alert {
+	minutes := alertWindowMinutes(window)
+	windowText := fmt.Sprintf("%d minutes", minutes)
+	if minutes == 1 {
+		windowText = "1 minute"
+	}
+	summary := fmt.Sprintf("%d occurrences in the last %s", occurrences, windowText)
+
+	alert.Headline = "🔁 " + alert.headline()
+	alert.Title = fmt.Sprintf("%s (%s)", alert.Title, summary)
+	alert.Timestamp = time.Now()
+
+	fields := make(map[string]string, len(alert.Fields)+2)
+	for name, value := range alert.Fields {
+		fields[name] = value
+	}
+	fields["occurrences"] = fmt.Sprintf("%d", occurrences)
+	fields["window"] = windowText
+	alert.Fields = fields
+
+	if len(alert.Sections) == 0 {
+		alert.Body = fmt.Sprintf("🔁 %s\n\n%s", summary, alert.Body)
+		return alert
+	}
+	section := AlertSection{
+		Title: "🔁 반복 알림 요약",
+		Fields: []AlertField{
+			{Label: "발생 횟수", Value: fmt.Sprintf("%d회 (최근 %d분)", occurrences, minutes), Short: true},
+			{Label: "첫 생략 시간", Value: first.Format("2006-01-02 15:04:05"), Short: true},
+		},
+		Text:    "같은 알림이 반복되어 개별 알림 대신 마지막 알림 내용과 발생 횟수를 요약해 보냅니다.",
+		Summary: true,
+	}
+	alert.Sections = append([]AlertSection{section}, alert.Sections...)
+	return alert
+}
+
+// alertWindowMinutes 요약 기간을 분 단위로 반올림 (1분 미만은 1분)
+func alertWindowMinutes(window time.Duration) int {
+	if minutes := int(window.Round(time.Minute) / time.Minute); minutes > 1 {
+		return minutes
+	}
+	return 1
+}
@@ -0,0 +1,268 @@
+/*
+Anomaly Model Module
+====================
+
+ONNX 분류 모델 이상 점수 (-onnx-model, ai_analysis.model_file)
+
+-sample-export 로 내보낸 표본으로 오프라인에서 학습한 작은 분류 모델을 불러와, 규칙 기반 이상 패턴
+(AnomalyPatterns) 점수와 함께 로그마다 점수를 계산하고 가중 평균으로 결합합니다. 외부 API 호출 없이
+onnx_model.go 의 순수 Go 추론기로 실행합니다.
+
+주요 기능:
+- 특성 벡터: 원본 로그 라인에서 숫자 특성 8개 + 토큰 해싱 (FNV-1a 32비트) 빈도 (L2 정규화)
+- -onnx-featurize: 내보낸 표본 JSONL 을 같은 특성 벡터 CSV 로 변환 (학습/추론 특성 일치 보장)
+- 모델 출력: 확률 하나면 이상 확률, 여러 클래스면 1 - P(normal) (normal 레이블이 없으면 클래스 0)
+- 결합 점수 = (1 - 가중치) × 규칙 점수 + 가중치 × 모델 점수 (-onnx-weight, 모델 점수는 0-10 으로 환산)
+
+특성 벡터 (크기 N = 모델 입력 크기):
+
+	[0] 레벨 (critical/fatal 1.0, error/err 0.75, warn 0.5, info 0.25, 그 외 0)
+	[1] 길이 / 2000 (최대 1)
+	[2] 숫자 비율  [3] 대문자 비율  [4] 영숫자/공백이 아닌 문자 비율
+	[5] IPv4 주소 수 / 5 (최대 1)
+	[6] 가장 큰 HTTP 상태 코드 (100-599) / 600
+	[7] 토큰 수 / 100 (최대 1)
+	[8..N-1] 소문자 토큰 (글자/숫자/_ 연속, 숫자만이면 "0") 의 FNV-1a 해시 % (N-8) 위치에 빈도 누적 후 L2 정규화
+*/
+package main
+
+import (
+	"bufio"         // 표본 JSONL 읽기
+	"encoding/csv"  // 특성 CSV 출력
+	"encoding/json" // 표본 레코드 파싱
+	"fmt"           // 형식화된 I/O
+	"hash/fnv"      // 토큰 해싱
+	"io"            // 출력 대상
+	"math"          // 정규화
+	"os"            // 파일 읽기
+	"path/filepath" // 모델 이름
+	"regexp"        // IP/상태 코드 추출
+	"strconv"       // 숫자 변환
+	"strings"       // 문자열 처리
+	"unicode"       // 문자 분류
+)
+
+// 이상 점수 모델 기본값
+const (
+	DefaultModelWeight        = 0.5 // 결합 점수에서 모델 점수 비중
+	DefaultModelFeatures      = 256 // -onnx-featurize 기본 특성 벡터 크기
+	ModelNumericFeatures      = 8   // 해싱 앞의 숫자 특성 수
+	ModelNormalClass          = "normal"
+	modelMaxLineLength        = 2000
+	modelProbabilityTolerance = 0.01
+)
+
+var (
+	modelIPv4Regex   = regexp.MustCompile(`\b(?:[0-9]{1,3}\.){3}[0-9]{1,3}\b`)
+	modelStatusRegex = regexp.MustCompile(`\b[1-5]\d{2}\b`)
+)
+
+// AnomalyModel 규칙 점수와 결합하는 ONNX 이상 점수 모델
+type AnomalyModel struct {
+	name        string
+	model       *ONNXModel
+	weight      float64
+	normalClass int // 여러 클래스 출력에서 정상 클래스 위치
+}
+
+// LoadAnomalyModel 모델을 불러오고 빈 입력으로 한 번 실행해 확인 (weight 는 0~1)
+func LoadAnomalyModel(path string, weight float64) (*AnomalyModel, error) {
+	if weight < 0 || weight > 1 {
+		return nil, fmt.Errorf("model weight must be between 0 and 1, got %g", weight)
+	}
+	model, err := LoadONNXModel(path)
+	if err != nil {
+		return nil, err
+	}
+	if model.Features() <= ModelNumericFeatures {
+		return nil, fmt.Errorf("%s: model input has %d features, need more than %d", path, model.Features(), ModelNumericFeatures)
+	}
+	am := &AnomalyModel{name: filepath.Base(path), model: model, weight: weight}
+	for i, label := range model.ClassLabels {
+		if strings.EqualFold(label, ModelNormalClass) || label == "0" {
+			am.normalClass = i
+			break
+		}
+	}
+	if _, err := am.Score(""); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return am, nil
+}
+
+// Name 모델 파일 이름
+func (am *AnomalyModel) Name() string {
+	return am.name
+}
+
+// Weight 결합 점수에서 모델 점수 비중
+func (am *AnomalyModel) Weight() float64 {
+	return am.weight
+}
+
+// Describe 시작 로그용 요약
+func (am *AnomalyModel) Describe() string {
+	return fmt.Sprintf("%s: %s, weight %.2f", am.name, describeONNXModel(am.model), am.weight)
+}
+
+// Score 로그 라인의 모델 이상 점수 (0-10)
+func (am *AnomalyModel) Score(raw string) (float64, error) {
+	output, err := am.model.Run(ModelFeatures(raw, am.model.Features()))
+	if err != nil {
+		return 0, err
+	}
+	var probability float64
+	switch len(output) {
+	case 0:
+		return 0, fmt.Errorf("model produced no output")
+	case 1:
+		probability = output[0]
+		if probability < 0 || probability > 1 {
+			probability = onnxSigmoid(probability) // 로짓 출력
+		}
+	default:
+		if !isProbabilityVector(output) {
+			softmax, _ := onnxSoftmax(&onnxTensor{shape: []int{len(output)}, data: output}, 0)
+			output = softmax.data
+		}
+		normal := am.normalClass
+		if normal >= len(output) {
+			normal = 0
+		}
+		probability = 1 - output[normal]
+	}
+	return math.Min(math.Max(probability, 0), 1) * MaxAnomalyScore, nil
+}
+
+// Combine 규칙 점수와 모델 점수의 가중 평균
+func (am *AnomalyModel) Combine(ruleScore, modelScore float64) float64 {
+	return (1-am.weight)*ruleScore + am.weight*modelScore
+}
+
+// isProbabilityVector 값이 모두 0~1 이고 합이 1 인지 (아니면 로짓으로 보고 소프트맥스 적용)
+func isProbabilityVector(values []float64) bool {
+	sum := 0.0
+	for _, v := range values {
+		if v < 0 || v > 1 {
+			return false
+		}
+		sum += v
+	}
+	return math.Abs(sum-1) <= modelProbabilityTolerance
+}
+
+// ModelFeatures 로그 라인의 특성 벡터 (size 는 ModelNumericFeatures 보다 커야 함)
+func ModelFeatures(raw string, size int) []float64 {
+	features := make([]float64, size)
+	lower := strings.ToLower(raw)
+
+	switch {
+	case strings.Contains(lower, "critical") || strings.Contains(lower, "fatal"):
+		features[0] = 1
+	case strings.Contains(lower, "err"):
+		features[0] = 0.75
+	case strings.Contains(lower, "warn"):
+		features[0] = 0.5
+	case strings.Contains(lower, "info"):
+		features[0] = 0.25
+	}
+
+	var digits, upper, symbols, total int
+	for _, r := range raw {
+		total++
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case unicode.IsUpper(r):
+			upper++
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			symbols++
+		}
+	}
+	if total > 0 {
+		features[1] = math.Min(float64(total)/modelMaxLineLength, 1)
+		features[2] = float64(digits) / float64(total)
+		features[3] = float64(upper) / float64(total)
+		features[4] = float64(symbols) / float64(total)
+	}
+	features[5] = math.Min(float64(len(modelIPv4Regex.FindAllString(raw, -1)))/5, 1)
+	for _, match := range modelStatusRegex.FindAllString(raw, -1) {
+		if code, err := strconv.Atoi(match); err == nil {
+			features[6] = math.Max(features[6], float64(code)/600)
+		}
+	}
+
+	tokens := strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	features[7] = math.Min(float64(len(tokens))/100, 1)
+
+	buckets := features[ModelNumericFeatures:]
+	for _, token := range tokens {
+		if strings.Trim(token, "0123456789") == "" {
+			token = "0"
+		}
+		hash := fnv.New32a()
+		hash.Write([]byte(token))
+		buckets[hash.Sum32()%uint32(len(buckets))]++
+	}
+	norm := 0.0
+	for _, v := range buckets {
+		norm += v * v
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for i := range buckets {
+			buckets[i] /= norm
+		}
+	}
+	return features
+}
+
+// WriteModelFeatures 표본 JSONL (-sample-export) 을 특성 CSV 로 변환
+// 열: stratum, pattern, level, weight, f0..f(size-1)
+func WriteModelFeatures(samplePath string, size int, out io.Writer) (int, error) {
+	if size <= ModelNumericFeatures {
+		return 0, fmt.Errorf("feature size must be greater than %d", ModelNumericFeatures)
+	}
+	file, err := os.Open(samplePath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(out)
+	header := []string{"stratum", "pattern", "level", "weight"}
+	for i := 0; i < size; i++ {
+		header = append(header, fmt.Sprintf("f%d", i))
+	}
+	if err := writer.Write(header); err != nil {
+		return 0, err
+	}
+
+	rows := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var event SampledEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || event.LogRecord == nil {
+			return rows, fmt.Errorf("%s:%d: not a sample record", samplePath, line)
+		}
+		record := []string{event.Stratum, event.Pattern, event.Level, strconv.FormatFloat(event.Weight, 'g', -1, 64)}
+		for _, value := range ModelFeatures(event.Raw, size) {
+			record = append(record, strconv.FormatFloat(value, 'g', 6, 64))
+		}
+		if err := writer.Write(record); err != nil {
+			return rows, err
+		}
+		rows++
+	}
+	if err := scanner.Err(); err != nil {
+		return rows, err
+	}
+	writer.Flush()
+	return rows, writer.Error()
+}
@@ -0,0 +1,216 @@
+/*
+Anomaly Rules Module
+====================
+
+사용자 정의 이상 패턴 규칙 파일 (-rules, ai_analysis.rules_file)
+
+주요 기능:
+- YAML (.yaml/.yml, simple_yaml.go 부분 집합) 또는 JSON 규칙 파일
+- 규칙 필드: name, regex, severity (0-10 또는 low/medium/high/critical), category, action, description
+- 내장 패턴 비활성화 (disable_builtin: [이름...] 또는 all)
+- 내장 패턴과 같은 이름의 규칙은 내장 패턴을 대체
+- 설정 재로드(파일 변경 감지/SIGHUP) 시 다시 읽고, 오류가 있으면 기존 규칙 유지
+
+규칙 파일 예시:
+
+	disable_builtin: [File_System_Error]
+	rules:
+	  - name: Nginx_Upstream_Failure
+	    regex: '(?i)upstream (timed out|prematurely closed)'
+	    severity: high
+	    category: Network
+	    action: check_backend
+*/
+package main
+
+import (
+	"encoding/json" // JSON 규칙 파일 / YAML 결과 변환
+	"fmt"           // 형식화된 I/O
+	"os"            // 파일 읽기
+	"path/filepath" // 확장자 확인
+	"regexp"        // 규칙 정규식
+	"strconv"       // 심각도 숫자 변환
+	"strings"       // 문자열 처리
+)
+
+// 규칙 심각도 키워드 → 이상 점수
+var anomalySeverityLevels = map[string]float64{
+	"low":      3.0,
+	"medium":   5.0,
+	"high":     7.5,
+	"critical": 9.0,
+}
+
+// anomalyRuleDisableAll disable_builtin 에서 모든 내장 패턴을 끄는 값
+const anomalyRuleDisableAll = "all"
+
+// AnomalyRule 규칙 파일의 규칙 한 개
+type AnomalyRule struct {
+	Name        string       `json:"name"`
+	Regex       string       `json:"regex"`
+	Severity    ruleSeverity `json:"severity"`
+	Category    string       `json:"category"`
+	Action      string       `json:"action"`
+	Description string       `json:"description"`
+}
+
+// anomalyRulesFile 규칙 파일 구조
+type anomalyRulesFile struct {
+	DisableBuiltin stringList    `json:"disable_builtin"`
+	Rules          []AnomalyRule `json:"rules"`
+}
+
+// ruleSeverity 숫자, 숫자 문자열, 키워드(low/medium/high/critical)를 받는 심각도
+type ruleSeverity float64
+
+// UnmarshalJSON 숫자 또는 문자열 심각도 해석
+func (s *ruleSeverity) UnmarshalJSON(data []byte) error {
+	var number float64
+	if err := json.Unmarshal(data, &number); err == nil {
+		*s = ruleSeverity(number)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("severity must be a number or low/medium/high/critical")
+	}
+	text = strings.ToLower(strings.TrimSpace(text))
+	if level, ok := anomalySeverityLevels[text]; ok {
+		*s = ruleSeverity(level)
+		return nil
+	}
+	number, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return fmt.Errorf("invalid severity %q (expected 0-10 or low/medium/high/critical)", text)
+	}
+	*s = ruleSeverity(number)
+	return nil
+}
+
+// stringList 문자열 하나 또는 문자열 목록
+type stringList []string
+
+// UnmarshalJSON 문자열 또는 목록 해석
+func (l *stringList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = stringList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("expected a string or a list of strings")
+	}
+	*l = list
+	return nil
+}
+
+// LoadAnomalyRules 규칙 파일을 읽어 내장 패턴과 합친 이상 패턴 목록 반환
+func LoadAnomalyRules(path string) ([]AnomalyPattern, error) {
+	data, err := os.ReadFile(expandHomePath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %v", err)
+	}
+
+	var file anomalyRulesFile
+	if isJSONRulesFile(path, data) {
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse rules file: %v", err)
+		}
+	} else {
+		document, err := parseSimpleYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rules file: %v", err)
+		}
+		// YAML 결과를 JSON 으로 변환해 같은 구조체/검증 사용
+		encoded, err := json.Marshal(document)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rules file: %v", err)
+		}
+		if document != nil {
+			if err := json.Unmarshal(encoded, &file); err != nil {
+				return nil, fmt.Errorf("failed to parse rules file: %v", err)
+			}
+		}
+	}
+
+	return buildAnomalyPatterns(file)
+}
+
+// isJSONRulesFile 확장자가 .json 이거나 내용이 '{' 로 시작하면 JSON
+func isJSONRulesFile(path string, data []byte) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return true
+	case ".yaml", ".yml":
+		return false
+	}
+	return strings.HasPrefix(strings.TrimSpace(string(data)), "{")
+}
+
+// buildAnomalyPatterns 규칙 검증 후 내장 패턴(비활성화/대체 반영) 뒤에 사용자 규칙 추가
+func buildAnomalyPatterns(file anomalyRulesFile) ([]AnomalyPattern, error) {
+	builtin := defaultAnomalyPatterns()
+	builtinNames := make(map[string]bool, len(builtin))
+	for _, pattern := range builtin {
+		builtinNames[pattern.Name] = true
+	}
+
+	disabled := make(map[string]bool)
+	for _, name := range file.DisableBuiltin {
+		name = strings.TrimSpace(name)
+		if name != anomalyRuleDisableAll && !builtinNames[name] {
+			return nil, fmt.Errorf("disable_builtin: unknown built-in pattern %q", name)
+		}
+		disabled[name] = true
+	}
+
+	custom := make([]AnomalyPattern, 0, len(file.Rules))
+	names := make(map[string]bool)
+	for i, rule := range file.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d: name is required", i+1)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("rule %s: duplicate name", rule.Name)
+		}
+		names[rule.Name] = true
+		if rule.Regex == "" {
+			return nil, fmt.Errorf("rule %s: regex is required", rule.Name)
+		}
+		compiled, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: invalid regex: %v", rule.Name, err)
+		}
+		severity := float64(rule.Severity)
+		if severity <= 0 || severity > MaxAnomalyScore {
+			return nil, fmt.Errorf("rule %s: severity must be between 0 and %.0f", rule.Name, MaxAnomalyScore)
+		}
+
+		pattern := AnomalyPattern{
+			Name:        rule.Name,
+			Pattern:     compiled,
+			Severity:    severity,
+			Description: rule.Description,
+			Category:    rule.Category,
+			Action:      rule.Action,
+		}
+		if pattern.Description == "" {
+			pattern.Description = rule.Name
+		}
+		if pattern.Category == "" {
+			pattern.Category = "Custom"
+		}
+		custom = append(custom, pattern)
+	}
+
+	var patterns []AnomalyPattern
+	if !disabled[anomalyRuleDisableAll] {
+		for _, pattern := range builtin {
+			if !disabled[pattern.Name] && !names[pattern.Name] {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return append(patterns, custom...), nil
+}
@@ -0,0 +1,272 @@
+/*
+Anonymizer Detection Module
+===========================
+
+Tor 출구 노드 및 VPN/프록시 출처 탐지
+
+주요 기능:
+- Tor 출구 노드 목록 주기적 갱신 (기본 6시간, check.torproject.org 벌크 목록)
+- 마지막으로 받은 목록을 ~/.syslog-monitor/tor_exit_nodes.txt 에 캐시 (재시작/오프라인 시 사용)
+- 정적 VPN/프록시 CIDR 데이터셋 파일 로드 (login.vpn_list_files, 예: X4BNet lists_vpn)
+- ip-api.com 의 proxy 필드(상용 VPN/프록시 판별)와 함께 IPLocationInfo.Anonymizer 로 표시
+
+데이터셋 파일 형식:
+- 한 줄에 CIDR 또는 IP 하나, "#" 이후는 주석
+- Tor 목록은 IP 목록 또는 exit-addresses 형식 ("ExitAddress 1.2.3.4 ...") 지원
+*/
+package main
+
+import (
+	"bufio"         // 목록 라인 읽기
+	"context"       // 종료 신호
+	"fmt"           // 형식화된 I/O
+	"io"            // I/O 인터페이스
+	"net"           // IP 파싱
+	"net/http"      // 목록 다운로드
+	"os"            // 파일 처리
+	"path/filepath" // 경로 처리
+	"strings"       // 문자열 처리
+	"sync"          // 동기화
+	"time"          // 시간 처리
+)
+
+// 익명화 출처 유형
+const (
+	AnonymizerTor   = "tor"
+	AnonymizerVPN   = "vpn"
+	AnonymizerProxy = "proxy"
+)
+
+// Tor 출구 노드 목록 설정
+const (
+	DefaultTorExitListURL = "https://check.torproject.org/torbulkexitlist" // Tor 출구 노드 벌크 목록
+	TorExitListRefresh    = 6 * time.Hour                                  // 목록 갱신 주기
+	TorExitListRetry      = 10 * time.Minute                               // 다운로드 실패 시 재시도 간격
+)
+
+// AnonymizerDetector Tor 출구 노드 / VPN 데이터셋 기반 익명화 출처 탐지기
+type AnonymizerDetector struct {
+	torExits    map[string]bool  // Tor 출구 노드 IP
+	torUpdated  time.Time        // Tor 목록 마지막 갱신 시각
+	vpnNetworks *TrustedNetworks // VPN/프록시 CIDR 데이터셋 (CIDR 매칭은 신뢰 네트워크 구현 재사용)
+	torListURL  string           // 빈 문자열이면 Tor 목록 다운로드 비활성화
+	torManaged  bool             // 데이터 업데이트(data_updates)가 Tor 목록을 관리하면 내장 다운로드 중단
+	cachePath   string
+	client      *http.Client
+	logger      Logger
+	mutex       sync.RWMutex
+	refreshOnce sync.Once
+}
+
+// NewAnonymizerDetector 새로운 익명화 출처 탐지기 생성 (캐시된 Tor 목록이 있으면 로드)
+func NewAnonymizerDetector(logger Logger) *AnonymizerDetector {
+	ad := &AnonymizerDetector{
+		torExits:   make(map[string]bool),
+		torListURL: DefaultTorExitListURL,
+		cachePath:  filepath.Join(getDataDir(), TorExitCacheFile),
+		client:     &http.Client{Timeout: 30 * time.Second},
+		logger:     logger,
+	}
+
+	if file, err := os.Open(ad.cachePath); err == nil {
+		exits := parseTorExitList(file)
+		file.Close()
+		if info, err := os.Stat(ad.cachePath); err == nil {
+			ad.torUpdated = info.ModTime()
+		}
+		ad.torExits = exits
+	}
+	return ad
+}
+
+// Configure Tor 목록 URL과 VPN/프록시 데이터셋 파일 설정
+// torListURL 이 "off" 이면 Tor 목록 다운로드 비활성화, 빈 값은 기본 URL 유지
+func (ad *AnonymizerDetector) Configure(torListURL string, vpnListFiles []string) error {
+	var specs []string
+	for _, path := range vpnListFiles {
+		entries, err := loadCIDRFile(expandHomePath(path))
+		if err != nil {
+			return err
+		}
+		specs = append(specs, entries...)
+	}
+	vpnNetworks, err := ParseTrustedNetworks(specs)
+	if err != nil {
+		return fmt.Errorf("invalid vpn list: %v", err)
+	}
+
+	ad.mutex.Lock()
+	defer ad.mutex.Unlock()
+	ad.vpnNetworks = vpnNetworks
+	switch torListURL {
+	case "":
+	case "off":
+		ad.torListURL = ""
+	default:
+		ad.torListURL = torListURL
+	}
+	return nil
+}
+
+// StartRefresh Tor 목록 주기적 갱신 시작 (캐시가 갱신 주기보다 오래되었으면 즉시 다운로드, ctx 가 끝나면 중단)
+func (ad *AnonymizerDetector) StartRefresh(ctx context.Context) {
+	ad.refreshOnce.Do(func() {
+		go func() {
+			for {
+				ad.mutex.RLock()
+				url, updated := ad.torListURL, ad.torUpdated
+				if ad.torManaged {
+					url = ""
+				}
+				ad.mutex.RUnlock()
+
+				wait := TorExitListRefresh - time.Since(updated)
+				if url != "" && wait <= 0 {
+					if err := ad.refreshTorList(url); err != nil {
+						ad.logger.Errorf("Failed to refresh Tor exit list: %v", err)
+						wait = TorExitListRetry
+					} else {
+						wait = TorExitListRefresh
+					}
+				}
+				if wait <= 0 {
+					wait = TorExitListRefresh
+				}
+				if !sleepContext(ctx, wait) {
+					return
+				}
+			}
+		}()
+	})
+}
+
+// refreshTorList Tor 출구 노드 목록 다운로드 후 교체 및 캐시 저장
+func (ad *AnonymizerDetector) refreshTorList(url string) error {
+	resp, err := ad.client.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to download tor exit list: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("tor exit list returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read tor exit list: %v", err)
+	}
+	exits := parseTorExitList(strings.NewReader(string(body)))
+	if len(exits) == 0 {
+		return fmt.Errorf("tor exit list is empty")
+	}
+
+	ad.mutex.Lock()
+	ad.torExits = exits
+	ad.torUpdated = time.Now()
+	ad.mutex.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(ad.cachePath), ConfigPermissions); err == nil {
+		if err := os.WriteFile(ad.cachePath, body, 0644); err != nil {
+			ad.logger.Errorf("Failed to cache Tor exit list: %v", err)
+		}
+	}
+	ad.logger.Infof("🧅 Tor exit list updated: %d exit nodes", len(exits))
+	return nil
+}
+
+// ManageTorList 데이터 업데이트가 Tor 목록을 관리하도록 내장 다운로드 중단, 기본 목록 파일 (캐시) 경로 반환
+func (ad *AnonymizerDetector) ManageTorList() string {
+	ad.mutex.Lock()
+	ad.torManaged = true
+	ad.mutex.Unlock()
+	return ad.cachePath
+}
+
+// LoadTorListFile 파일의 Tor 출구 노드 목록으로 교체 (데이터 업데이트 적용)
+func (ad *AnonymizerDetector) LoadTorListFile(path string) (int, error) {
+	exits, err := readTorExitListFile(path)
+	if err != nil {
+		return 0, err
+	}
+	ad.mutex.Lock()
+	ad.torExits = exits
+	ad.torUpdated = time.Now()
+	ad.mutex.Unlock()
+	return len(exits), nil
+}
+
+// readTorExitListFile 파일에서 Tor 출구 노드 목록 읽기 (항목이 없으면 에러)
+func readTorExitListFile(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	exits := parseTorExitList(file)
+	if len(exits) == 0 {
+		return nil, fmt.Errorf("tor exit list is empty")
+	}
+	return exits, nil
+}
+
+// Match IP가 Tor 출구 노드 또는 VPN/프록시 데이터셋에 속하면 유형 반환 (해당 없으면 빈 문자열)
+func (ad *AnonymizerDetector) Match(ip string) string {
+	if ad == nil {
+		return ""
+	}
+	ad.mutex.RLock()
+	defer ad.mutex.RUnlock()
+
+	if ad.torExits[ip] {
+		return AnonymizerTor
+	}
+	if _, matched := ad.vpnNetworks.Match(ip); matched {
+		return AnonymizerVPN
+	}
+	return ""
+}
+
+// parseTorExitList IP 목록 또는 exit-addresses 형식의 Tor 출구 노드 목록 파싱
+func parseTorExitList(reader io.Reader) map[string]bool {
+	exits := make(map[string]bool)
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		candidate := fields[0]
+		if candidate == "ExitAddress" && len(fields) > 1 {
+			candidate = fields[1]
+		}
+		if ip := net.ParseIP(candidate); ip != nil {
+			exits[ip.String()] = true
+		}
+	}
+	return exits
+}
+
+// loadCIDRFile 한 줄에 CIDR/IP 하나인 데이터셋 파일 로드 ("#" 이후 주석 무시)
+func loadCIDRFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vpn list %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var entries []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			entries = append(entries, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read vpn list %s: %v", path, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,139 @@
+/*
+Anthropic Provider Module
+=========================
+
+Anthropic Messages API LLM 백엔드 (ai_analysis.provider: anthropic)
+
+주요 기능:
+- POST {base_url}/messages (기본 https://api.anthropic.com/v1, anthropic-version 2023-06-01)
+- API 키: 설정 파일 api_key 또는 ANTHROPIC_API_KEY (x-api-key 헤더)
+- 시스템 진단은 진단 스키마를 입력으로 받는 도구 호출을 강제해 구조화 응답으로 받음
+*/
+package main
+
+import (
+	"encoding/json" // 도구 입력 직렬화
+	"fmt"           // 형식화된 I/O
+	"strings"       // 응답 텍스트 합치기
+)
+
+// anthropicAPIVersion Messages API 버전 헤더
+const anthropicAPIVersion = "2023-06-01"
+
+// anthropicDiagnosisTool 구조화 진단을 받는 도구 이름
+const anthropicDiagnosisTool = "report_expert_diagnosis"
+
+// anthropicRequest Messages 요청
+type anthropicRequest struct {
+	Model       string               `json:"model"`
+	MaxTokens   int                  `json:"max_tokens"`
+	Temperature float64              `json:"temperature"`
+	Messages    []anthropicMessage   `json:"messages"`
+	Tools       []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice  *anthropicToolChoice `json:"tool_choice,omitempty"`
+}
+
+// anthropicMessage 대화 메시지
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// anthropicTool 도구 정의 (입력 스키마 = 응답 형식)
+type anthropicTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"input_schema"`
+}
+
+// anthropicToolChoice 호출할 도구 지정
+type anthropicToolChoice struct {
+	Type string `json:"type"` // tool
+	Name string `json:"name"`
+}
+
+// anthropicResponse Messages 응답
+type anthropicResponse struct {
+	Content []struct {
+		Type  string          `json:"type"` // text, tool_use
+		Text  string          `json:"text"`
+		Name  string          `json:"name"`
+		Input json.RawMessage `json:"input"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+}
+
+// AnthropicProvider Anthropic 백엔드
+type AnthropicProvider struct {
+	llmBase
+}
+
+// NewAnthropicProvider Anthropic 백엔드 생성 (config 는 withDefaults 를 거친 설정)
+func NewAnthropicProvider(config *LLMConfig) *AnthropicProvider {
+	return &AnthropicProvider{llmBase: newLLMBase(config)}
+}
+
+// AnalyzeSystemDiagnosis 시스템 진단 분석
+func (ap *AnthropicProvider) AnalyzeSystemDiagnosis(metrics SystemMetrics) (string, error) {
+	return ap.analyzeSystemDiagnosis(metrics, ap.complete)
+}
+
+// AnalyzeLogPattern 로그 패턴 분석
+func (ap *AnthropicProvider) AnalyzeLogPattern(logLine string, context map[string]string) (string, error) {
+	return ap.analyzeLogPattern(logLine, context, ap.complete)
+}
+
+// AnalyzeLogBatch 관련 로그 묶음 분석
+func (ap *AnthropicProvider) AnalyzeLogBatch(lines []string, context map[string]string) (string, error) {
+	return ap.analyzeLogBatch(lines, context, ap.complete)
+}
+
+// AnalyzeSecurityThreat 보안 위협 분석
+func (ap *AnthropicProvider) AnalyzeSecurityThreat(threatData map[string]interface{}) (string, error) {
+	return ap.analyzeSecurityThreat(threatData, ap.complete)
+}
+
+// SummarizeDigest 일일 요약 보고서 작성
+func (ap *AnthropicProvider) SummarizeDigest(summary *DigestSummary) (string, error) {
+	return ap.summarizeDigest(summary, ap.complete)
+}
+
+// complete Messages 호출 (schema 가 있으면 도구 호출 입력을 JSON 응답으로 반환)
+func (ap *AnthropicProvider) complete(prompt string, schema *GeminiSchema) (string, error) {
+	config := ap.currentConfig()
+	request := anthropicRequest{
+		Model:       config.Model,
+		MaxTokens:   config.MaxTokens,
+		Temperature: config.Temperature,
+		Messages:    []anthropicMessage{{Role: "user", Content: prompt}},
+	}
+	if schema != nil {
+		request.Tools = []anthropicTool{{
+			Name:        anthropicDiagnosisTool,
+			Description: "시스템 진단 결과를 구조화된 형식으로 보고",
+			InputSchema: schema.jsonSchema(),
+		}}
+		request.ToolChoice = &anthropicToolChoice{Type: "tool", Name: anthropicDiagnosisTool}
+	}
+
+	headers := map[string]string{"x-api-key": config.APIKey, "anthropic-version": anthropicAPIVersion}
+	var response anthropicResponse
+	if err := ap.postJSON("Anthropic", config.BaseURL+"/messages", headers, request, &response); err != nil {
+		return "", err
+	}
+
+	var texts []string
+	for _, block := range response.Content {
+		switch {
+		case schema != nil && block.Type == "tool_use" && block.Name == anthropicDiagnosisTool:
+			return string(block.Input), nil
+		case block.Type == "text" && block.Text != "":
+			texts = append(texts, block.Text)
+		}
+	}
+	if len(texts) == 0 {
+		return "", fmt.Errorf("no content in response (stop reason: %s)", response.StopReason)
+	}
+	// 도구를 호출하지 않았으면 텍스트 응답을 그대로 검증 (JSON 이 아니면 기본 진단으로 대체됨)
+	return strings.Join(texts, "\n"), nil
+}
@@ -0,0 +1,919 @@
+/*
+Management API Module
+=====================
+
+실행 중인 모니터를 조회하고 재설정하는 내장 REST API (-api-port)
+
+주요 기능:
+- GET  /status          버전, 가동 시간, 입력, 활성 기능, 알림 채널, 키워드/필터
+- GET  /metrics/current 현재 시스템 메트릭 (시스템 모니터링 활성화 시)
+- GET  /alerts/recent   최근 전송한 알림 (?limit=20&type=login)
+- GET  /alerts/snoozes  이메일 링크/Slack 버튼으로 끈(snooze/ack) 알림과 끈 뒤 생략한 알림 수
+- GET/POST /alerts/action 알림 이메일의 서명된 끄기/확인 링크 (GET 은 확인 페이지, POST 로 적용, alert_actions.go)
+- POST /slack/actions   Slack 알림의 확인/1시간 끄기 버튼 (Slack 앱 Interactivity Request URL, slack_actions.go)
+- POST /slack/commands  Slack 슬래시 명령 /sysmon status|metrics|recent-logins|silence (slack_commands.go)
+- POST /thresholds      시스템 모니터링 임계값 변경 (지정한 값만, 0 이하는 유지)
+- POST /filters         필터/키워드 교체
+- POST /test-alert      모든 알림 채널로 테스트 알림 전송
+- GET  /slo             엔드포인트 SLO 오류 예산 / burn rate (-slo 또는 설정 파일 "slos")
+- GET  /access-stats    접근 로그 통계 전용 모드의 윈도우별 요청 수, 상태 코드, 응답 시간, 공격 패턴 횟수 (-access-stats, access_stats.go)
+- GET  /parsers         파서별 처리 줄 수, 소스별 형식 미인식(unknown) 비율 (parser_stats.go)
+- GET  /usage           소스/파서별 줄 수, 바이트, 단계별 처리 시간과 CPU 추정치 (source_usage.go)
+- GET  /metrics         Prometheus 텍스트 형식 파서/처리 비용/처리 지연 시간 지표 (운영자 요청은 테넌트 지표도 tenant 라벨로 포함)
+- GET  /tenants         테넌트 목록 (멀티 테넌트 모드, 운영자 토큰 전용)
+- GET  /maintenance     점검 일정 캘린더 상태, 진행 중/다가오는 점검, 조용한 시간 창별 보류 건수 (운영자 토큰 전용, maintenance.go, quiet_hours.go)
+- GET  /digest          일일 요약 집계 미리 보기 (LLM 호출 없음, 운영자 토큰 전용, daily_digest.go)
+- POST /digest/send     일일 요약을 지금 전송 (운영자 토큰 전용)
+- /dashboard/           웹 대시보드 (-dashboard, dashboard.go)
+
+보안:
+  - 기본적으로 127.0.0.1 에만 바인딩 (-api-bind), -api-tls-cert/-api-tls-key 지정 시 HTTPS
+  - -api-token 지정 시 "Authorization: Bearer <token>" 헤더 필요
+    (헤더를 보낼 수 없는 브라우저 WebSocket 은 ?token=<token> 쿼리 사용)
+  - 멀티 테넌트 모드 (-tenants): 테넌트 토큰은 자기 테넌트의 GET 요청만 가능 (읽기 전용),
+    운영자 토큰(-api-token)은 ?tenant=<id> 로 특정 테넌트를 조회/설정
+  - /alerts/action 은 토큰 대신 링크의 HMAC 서명과 만료 시각으로 검증 (운영자 모니터의 알림만)
+  - /slack/actions, /slack/commands 는 토큰 대신 Slack 요청 서명(Signing Secret)과 타임스탬프로 검증
+*/
+package main
+
+import (
+	"context"       // 서버 종료, 요청 범위 전달
+	"crypto/subtle" // 토큰 비교
+	"crypto/tls"    // HTTPS (-api-tls-cert)
+	"encoding/json" // JSON 인코딩/디코딩
+	"fmt"           // 형식화된 I/O
+	"html/template" // 알림 링크 확인 페이지
+	"io"            // Slack 요청 본문 (서명 검증)
+	"net"           // 리스너
+	"net/http"      // HTTP 서버
+	"os"            // 호스트명
+	"runtime"       // OS 정보
+	"strconv"       // 쿼리 파라미터 파싱
+	"strings"       // 문자열 처리
+	"time"          // 시간 처리
+)
+
+// 관리 API 설정
+const (
+	DefaultAPIBind      = "127.0.0.1"     // 기본 바인딩 주소 (로컬 전용)
+	DefaultRecentAlerts = 20              // /alerts/recent 기본 조회 수
+	APIRequestBodyLimit = 1 << 20         // 요청 본문 최대 크기 (1MB)
+	APIShutdownTimeout  = 5 * time.Second // 종료 시 처리 중인 요청 대기 시간
+)
+
+// APIServer 관리 REST API 서버
+type APIServer struct {
+	monitor  *SyslogMonitor
+	tenants  *TenantManager // 멀티 테넌트 모드가 아니면 nil
+	token    string
+	server   *http.Server
+	listener net.Listener
+	certFile string // HTTPS 인증서 (비어 있으면 HTTP)
+	keyFile  string
+}
+
+// apiStatus GET /status 응답
+type apiStatus struct {
+	App          string             `json:"app"`
+	Version      string             `json:"version"`
+	Host         string             `json:"host"`
+	OS           string             `json:"os"`
+	StartedAt    time.Time          `json:"started_at"`
+	Uptime       string             `json:"uptime"`
+	Tenant       string             `json:"tenant,omitempty"`
+	Input        string             `json:"input"`
+	Features     map[string]bool    `json:"features"`
+	Sinks        []string           `json:"sinks"`
+	Keywords     []string           `json:"keywords"`
+	Filters      []string           `json:"filters"`
+	Thresholds   *SystemThresholds  `json:"thresholds,omitempty"`
+	Quota        []QuotaStatus      `json:"quota,omitempty"`
+	HealthChecks *HealthCheckStats  `json:"health_checks,omitempty"`
+	Pipeline     *PipelineStats     `json:"pipeline,omitempty"`
+	Buffers      []BufferStats      `json:"output_buffers,omitempty"`
+	Tenants      []string           `json:"tenants,omitempty"`
+	HA           *LeaderStatus      `json:"ha,omitempty"`
+	LLM          *LLMSchedulerStats `json:"llm,omitempty"`
+	Canary       *CanaryResult      `json:"canary,omitempty"`
+}
+
+// apiTenant GET /tenants 응답 항목
+type apiTenant struct {
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Sources    []string      `json:"sources"`
+	Sinks      []string      `json:"sinks"`
+	EventStore string        `json:"event_store,omitempty"`
+	APITokens  int           `json:"api_tokens"`
+	Quota      []QuotaStatus `json:"quota,omitempty"`
+}
+
+// apiScope 요청을 처리할 대상 (운영자 모니터 또는 테넌트)
+type apiScope struct {
+	tenant      *Tenant // nil 이면 운영자 모니터
+	tenantToken bool    // 테넌트 토큰으로 인증 (읽기 전용)
+}
+
+// apiScopeKey 요청 컨텍스트의 apiScope 키
+type apiScopeKey struct{}
+
+// apiFiltersRequest POST /filters 요청 (생략한 목록은 유지, 빈 배열은 비움)
+type apiFiltersRequest struct {
+	Filters  *[]string `json:"filters"`
+	Keywords *[]string `json:"keywords"`
+}
+
+// apiTestAlertRequest POST /test-alert 요청 (모두 선택)
+type apiTestAlertRequest struct {
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+// NewAPIServer 관리 API 서버 생성
+// token 이 비어 있지 않으면 모든 요청에 Bearer 토큰 인증 적용
+func NewAPIServer(monitor *SyslogMonitor, bind string, port int, token string) *APIServer {
+	api := &APIServer{monitor: monitor, tenants: monitor.tenants, token: token}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", api.handle(http.MethodGet, api.handleStatus))
+	mux.HandleFunc("/metrics/current", api.handle(http.MethodGet, api.handleCurrentMetrics))
+	mux.HandleFunc("/alerts/recent", api.handle(http.MethodGet, api.handleRecentAlerts))
+	mux.HandleFunc("/alerts/snoozes", api.handle(http.MethodGet, api.handleSnoozes))
+	mux.HandleFunc(AlertActionPath, api.handleAlertAction)
+	mux.HandleFunc(SlackActionPath, api.handleSlackAction)
+	mux.HandleFunc(SlackCommandPath, api.handleSlackCommand)
+	mux.HandleFunc("/thresholds", api.handle(http.MethodPost, api.handleThresholds))
+	mux.HandleFunc("/filters", api.handle(http.MethodPost, api.handleFilters))
+	mux.HandleFunc("/test-alert", api.handle(http.MethodPost, api.handleTestAlert))
+	mux.HandleFunc("/slo", api.handle(http.MethodGet, api.handleSLO))
+	mux.HandleFunc("/access-stats", api.handle(http.MethodGet, api.handleAccessStats))
+	mux.HandleFunc("/parsers", api.handle(http.MethodGet, api.handleParsers))
+	mux.HandleFunc("/usage", api.handle(http.MethodGet, api.handleUsage))
+	mux.HandleFunc("/metrics", api.handle(http.MethodGet, api.handlePrometheusMetrics))
+	mux.HandleFunc("/tenants", api.handle(http.MethodGet, api.handleTenants))
+	mux.HandleFunc("/maintenance", api.handle(http.MethodGet, api.handleMaintenance))
+	mux.HandleFunc("/digest", api.handle(http.MethodGet, api.handleDigest))
+	mux.HandleFunc("/digest/send", api.handle(http.MethodPost, api.handleDigestSend))
+	if monitor.dashboard != nil {
+		registerDashboard(mux, api.dashboardRoute)
+	}
+
+	api.server = &http.Server{
+		Addr:              net.JoinHostPort(bind, strconv.Itoa(port)),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return api
+}
+
+// SetTLS HTTPS 인증서/개인 키 파일 설정 (Start 전에 호출)
+func (api *APIServer) SetTLS(certFile, keyFile string) {
+	api.certFile = certFile
+	api.keyFile = keyFile
+}
+
+// Scheme 수신 프로토콜 (http, https)
+func (api *APIServer) Scheme() string {
+	if api.certFile != "" {
+		return "https"
+	}
+	return "http"
+}
+
+// Start 포트를 열고 백그라운드에서 요청 처리
+func (api *APIServer) Start() error {
+	if api.certFile != "" {
+		certificate, err := tls.LoadX509KeyPair(api.certFile, api.keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load management API certificate: %v", err)
+		}
+		api.server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+	}
+	listener, err := net.Listen("tcp", api.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to start management API: %v", err)
+	}
+	api.listener = listener
+
+	go func() {
+		serve := api.server.Serve
+		if api.server.TLSConfig != nil {
+			serve = func(listener net.Listener) error { return api.server.ServeTLS(listener, "", "") }
+		}
+		if err := serve(listener); err != nil && err != http.ErrServerClosed {
+			api.monitor.logger.Errorf("❌ Management API stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Addr 실제 수신 주소 (포트 0 지정 시 할당된 포트 포함)
+func (api *APIServer) Addr() string {
+	if api.listener != nil {
+		return api.listener.Addr().String()
+	}
+	return api.server.Addr
+}
+
+// Close 처리 중인 요청을 마친 뒤 서버 종료
+func (api *APIServer) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), APIShutdownTimeout)
+	defer cancel()
+	api.server.Shutdown(ctx)
+}
+
+// handle 메서드 확인, 토큰 인증, 본문 크기 제한을 적용한 핸들러
+// 테넌트 토큰이면 해당 테넌트로 범위를 고정하고 GET 만 허용
+func (api *APIServer) handle(method string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" && r.Method == http.MethodGet {
+			token = r.URL.Query().Get("token")
+		}
+
+		var scope apiScope
+		if tenant := api.tenants.Authenticate(token); tenant != nil {
+			scope = apiScope{tenant: tenant, tenantToken: true}
+		} else {
+			if api.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(api.token)) != 1 {
+				writeAPIError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+				return
+			}
+			if id := r.URL.Query().Get("tenant"); id != "" {
+				if scope.tenant = api.tenants.Get(id); scope.tenant == nil {
+					writeAPIError(w, http.StatusNotFound, fmt.Sprintf("unknown tenant %q", id))
+					return
+				}
+			}
+		}
+
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			writeAPIError(w, http.StatusMethodNotAllowed, fmt.Sprintf("use %s", method))
+			return
+		}
+		if scope.tenantToken && method != http.MethodGet {
+			writeAPIError(w, http.StatusForbidden, "tenant tokens are read-only")
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, APIRequestBodyLimit)
+		handler(w, r.WithContext(context.WithValue(r.Context(), apiScopeKey{}, scope)))
+	}
+}
+
+// scope 요청 범위 (handle 을 거친 요청에서만 사용)
+func (api *APIServer) scope(r *http.Request) apiScope {
+	scope, _ := r.Context().Value(apiScopeKey{}).(apiScope)
+	return scope
+}
+
+// monitorFor 요청 범위의 모니터 (테넌트 요청이면 테넌트 모니터)
+func (api *APIServer) monitorFor(r *http.Request) *SyslogMonitor {
+	if tenant := api.scope(r).tenant; tenant != nil {
+		return tenant.monitor
+	}
+	return api.monitor
+}
+
+// dashboardRoute 요청 범위의 대시보드로 연결하는 GET 핸들러
+func (api *APIServer) dashboardRoute(handler func(*Dashboard, http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return api.handle(http.MethodGet, func(w http.ResponseWriter, r *http.Request) {
+		dashboard := api.monitorFor(r).dashboard
+		if dashboard == nil {
+			writeAPIError(w, http.StatusNotFound, "dashboard is disabled")
+			return
+		}
+		handler(dashboard, w, r)
+	})
+}
+
+// handleStatus GET /status
+func (api *APIServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	sm := api.monitorFor(r)
+	tenant := api.scope(r).tenant
+	host, _ := os.Hostname()
+
+	status := apiStatus{
+		App:       AppName,
+		Version:   AppVersion,
+		Host:      host,
+		OS:        runtime.GOOS,
+		StartedAt: sm.startedAt,
+		Uptime:    time.Since(sm.startedAt).Round(time.Second).String(),
+		Input:     sm.statusInput(),
+		Features:  sm.statusFeatures(),
+		Sinks:     sm.alertDispatcher.SinkNames(),
+	}
+	if tenant != nil {
+		status.Tenant = tenant.ID
+		status.Input = strings.Join(tenant.Sources(), ",")
+		status.Quota = tenant.QuotaUsage()
+		delete(status.Features, "config_reload")
+	} else {
+		// 출력 버퍼 경로와 용량은 수집 서버 자원이므로 운영자에게만 (테넌트 버퍼 포함)
+		status.Buffers = sm.outputBufferStats()
+		for _, tenant := range api.tenants.Tenants() {
+			status.Tenants = append(status.Tenants, tenant.ID)
+			status.Buffers = append(status.Buffers, tenant.monitor.outputBufferStats()...)
+		}
+		if sm.leaderElection != nil {
+			status.HA = sm.leaderElection.Status()
+		}
+		// LLM 호출 한도는 모든 테넌트가 공유하므로 운영자에게만
+		if currentLLMProvider() != nil {
+			stats := llmScheduler.Stats()
+			status.LLM = &stats
+		}
+		status.Canary = sm.canary.Last()
+	}
+
+	if sm.pipeline != nil {
+		stats := sm.pipeline.Stats()
+		status.Pipeline = &stats
+	}
+
+	// 키워드/필터/임계값은 처리 고루틴에서 변경되므로 같은 고루틴에서 읽음
+	sm.runOnLoop(func() {
+		status.Keywords = sm.keywords.Keywords()
+		status.Filters = sm.filter.Patterns()
+		if sm.healthChecks != nil {
+			stats := sm.healthChecks.Stats()
+			status.HealthChecks = &stats
+		}
+		if sm.systemMonitor != nil {
+			thresholds := sm.systemMonitor.GetThresholds()
+			status.Thresholds = &thresholds
+		}
+	})
+
+	writeAPIJSON(w, http.StatusOK, status)
+}
+
+// statusInput 입력 소스 설명 (GET /status, Slack /sysmon status)
+func (sm *SyslogMonitor) statusInput() string {
+	input := sm.logFile
+	if len(sm.replayPaths) > 0 {
+		input = "replay:" + strings.Join(sm.replayPaths, ",")
+	}
+	if sm.journaldInput {
+		input = "journald"
+		if len(sm.journaldUnits) > 0 {
+			input += ":" + strings.Join(sm.journaldUnits, ",")
+		}
+	}
+	if sm.kubeOptions != nil {
+		input = "kubernetes:" + sm.kubeOptions.Describe()
+	}
+	if len(sm.listenTargets) > 0 {
+		input = "listen:" + strings.Join(sm.listenTargets, ",")
+	}
+	return input
+}
+
+// statusFeatures 기능별 사용 여부 (GET /status, Slack /sysmon status)
+func (sm *SyslogMonitor) statusFeatures() map[string]bool {
+	return map[string]bool{
+		"ai_analysis":     sm.aiEnabled,
+		"system_monitor":  sm.systemEnabled,
+		"login_watch":     sm.loginWatch,
+		"periodic_report": sm.periodicReport,
+		"event_store":     sm.eventStore != nil,
+		"elasticsearch":   sm.esOutput != nil,
+		"syslog_forward":  sm.forwarder != nil,
+		"event_sampling":  sm.eventSampler != nil,
+		"onnx_model":      sm.aiAnalyzer != nil && sm.aiAnalyzer.Model() != nil,
+		"config_reload":   sm.configWatcher != nil,
+		"ha_leader":       sm.leaderElection != nil,
+		"alert_journal":   sm.deliveryJournal != nil,
+		"output_buffer":   len(sm.outputBufferStats()) > 0,
+		"data_updates":    sm.dataUpdater != nil,
+		"unknown_format":  sm.parserStats.Threshold() > 0,
+		"maintenance":     sm.alertDispatcher.Maintenance() != nil,
+		"quiet_hours":     sm.alertDispatcher.QuietHours() != nil,
+		"daily_digest":    sm.digest.Enabled(),
+		"slack_actions":   sm.slackActions.Enabled(),
+		"self_watch":      sm.selfWatch != nil,
+		"slack_commands":  sm.slackCommands.Enabled(),
+		"canary":          sm.canary != nil,
+		"access_stats":    sm.accessStats != nil,
+		"severity_policy": sm.severityPolicy.Len() > 0,
+		"cicd_watch":      sm.cicdDetector != nil,
+	}
+}
+
+// handleCurrentMetrics GET /metrics/current
+func (api *APIServer) handleCurrentMetrics(w http.ResponseWriter, r *http.Request) {
+	if api.scope(r).tenant != nil {
+		writeAPIError(w, http.StatusNotFound, "host metrics are not collected per tenant")
+		return
+	}
+	if api.monitor.systemMonitor == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "system monitoring is disabled (start with -system-monitor)")
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, api.monitor.systemMonitor.GetCurrentMetrics())
+}
+
+// handleSLO GET /slo
+func (api *APIServer) handleSLO(w http.ResponseWriter, r *http.Request) {
+	monitor := api.monitorFor(r)
+	if monitor.sloTracker == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "no SLOs defined (start with -slo or add \"slos\" to the config file)")
+		return
+	}
+	statuses := monitor.sloTracker.Status(time.Now())
+	writeAPIJSON(w, http.StatusOK, map[string]interface{}{"count": len(statuses), "slos": statuses})
+}
+
+// handleAccessStats GET /access-stats - 접근 로그 통계 전용 모드의 윈도우별 집계
+func (api *APIServer) handleAccessStats(w http.ResponseWriter, r *http.Request) {
+	monitor := api.monitorFor(r)
+	if monitor.accessStats == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "access statistics mode is off (start with -access-stats)")
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, monitor.accessStats.Snapshot(time.Now()))
+}
+
+// handleParsers GET /parsers
+func (api *APIServer) handleParsers(w http.ResponseWriter, r *http.Request) {
+	writeAPIJSON(w, http.StatusOK, api.monitorFor(r).parserStats.Snapshot())
+}
+
+// handleUsage GET /usage - CPU 추정치는 운영자와 모든 테넌트 모니터의 처리 시간을 합쳐 나눔
+// (테넌트 토큰 요청에는 프로세스 전체 CPU/메모리 값을 넣지 않음)
+func (api *APIServer) handleUsage(w http.ResponseWriter, r *http.Request) {
+	monitors := []*SyslogMonitor{api.monitor}
+	for _, tenant := range api.tenants.Tenants() {
+		monitors = append(monitors, tenant.monitor)
+	}
+	snapshots := make([]*SourceUsageSnapshot, len(monitors))
+	var current *SourceUsageSnapshot
+	for i, monitor := range monitors {
+		snapshot := monitor.sourceUsage.Snapshot()
+		snapshots[i] = &snapshot
+		if monitor == api.monitorFor(r) {
+			current = snapshots[i]
+		}
+	}
+	AttributeProcessUsage(snapshots)
+
+	if api.scope(r).tenantToken {
+		current.ProcessCPUSeconds, current.ProcessRSSBytes, current.GoHeapBytes = 0, 0, 0
+	}
+	writeAPIJSON(w, http.StatusOK, current)
+}
+
+// handlePrometheusMetrics GET /metrics (Prometheus 텍스트 형식)
+func (api *APIServer) handlePrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+	var scopes []TenantParserStats
+	var usage []TenantSourceUsage
+	var latency []TenantLatencyStats
+	var access []TenantAccessStats
+	addScope := func(tenant string, monitor *SyslogMonitor) {
+		scopes = append(scopes, TenantParserStats{Tenant: tenant, Stats: monitor.parserStats.Snapshot()})
+		usage = append(usage, TenantSourceUsage{Tenant: tenant, Stats: monitor.sourceUsage.Snapshot()})
+		if monitor.latencyTracer != nil {
+			latency = append(latency, TenantLatencyStats{Tenant: tenant, Stats: monitor.latencyTracer.Snapshot()})
+		}
+		if monitor.accessStats != nil {
+			stats, histogram := monitor.accessStats.Metrics()
+			access = append(access, TenantAccessStats{Tenant: tenant, Stats: stats, Latency: histogram})
+		}
+	}
+	if tenant := api.scope(r).tenant; tenant != nil {
+		addScope(tenant.ID, tenant.monitor)
+	} else {
+		addScope("", api.monitor)
+		for _, tenant := range api.tenants.Tenants() {
+			addScope(tenant.ID, tenant.monitor)
+		}
+	}
+
+	var builder strings.Builder
+	WriteParserMetrics(&builder, scopes)
+	WriteSourceUsageMetrics(&builder, usage)
+	WriteLatencyMetrics(&builder, latency)
+	WriteAccessStatsMetrics(&builder, access)
+	if api.scope(r).tenant == nil && currentLLMProvider() != nil {
+		WriteLLMMetrics(&builder, llmScheduler.Stats())
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(builder.String()))
+}
+
+// handleRecentAlerts GET /alerts/recent?limit=20&type=login
+func (api *APIServer) handleRecentAlerts(w http.ResponseWriter, r *http.Request) {
+	limit := DefaultRecentAlerts
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid limit %q", value))
+			return
+		}
+		limit = parsed
+	}
+	if limit > RecentAlertLimit {
+		limit = RecentAlertLimit
+	}
+
+	alerts := api.monitorFor(r).alertDispatcher.Recent(r.URL.Query().Get("type"), limit)
+	writeAPIJSON(w, http.StatusOK, map[string]interface{}{"count": len(alerts), "alerts": alerts})
+}
+
+// handleSnoozes GET /alerts/snoozes - 이메일 링크/Slack 버튼으로 끈 알림
+func (api *APIServer) handleSnoozes(w http.ResponseWriter, r *http.Request) {
+	snoozes := api.monitorFor(r).alertDispatcher.Snoozes().Active(time.Now())
+	writeAPIJSON(w, http.StatusOK, map[string]interface{}{"count": len(snoozes), "snoozes": snoozes})
+}
+
+// alertActionPage 알림 링크 확인/결과 페이지
+var alertActionPage = template.Must(template.New("alert-action").Parse(`<!DOCTYPE html>
+<html lang="ko"><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex"><title>{{.App}} 알림 관리</title>
+<style>body{font-family:sans-serif;max-width:36em;margin:3em auto;padding:0 1em;color:#222}
+button{font-size:1em;padding:.6em 1.4em;cursor:pointer}.muted{color:#666}</style></head>
+<body><h2>{{.Heading}}</h2>{{if .Title}}<p><strong>{{.Title}}</strong></p>{{end}}<p>{{.Message}}</p>
+{{if .Confirm}}<form method="post"><button type="submit">{{.Confirm}}</button></form>
+<p class="muted">메일 보안 검사기가 링크를 미리 열어도 적용되지 않도록 버튼을 눌러야 적용됩니다.</p>{{end}}
+</body></html>
+`))
+
+// alertActionView 확인/결과 페이지 값
+type alertActionView struct {
+	App     string
+	Heading string
+	Title   string
+	Message string
+	Confirm string // 비어 있지 않으면 적용 버튼 표시
+}
+
+// handleAlertAction GET/POST /alerts/action - 알림 이메일의 서명된 끄기/확인 링크
+// Bearer 토큰 대신 링크 서명으로 검증하며, GET 은 확인 페이지만 보여주고 POST 로 적용
+func (api *APIServer) handleAlertAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		writeAPIError(w, http.StatusMethodNotAllowed, "use GET or POST")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, APIRequestBodyLimit)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	view := alertActionView{App: AppName}
+	action, err := api.monitor.alertActions.Verify(r.URL.Query(), time.Now())
+	if err != nil {
+		view.Heading = "⚠️ 링크를 사용할 수 없습니다"
+		view.Message = err.Error()
+		writeAlertActionPage(w, http.StatusForbidden, view)
+		return
+	}
+
+	view.Title = action.Title
+	if r.Method == http.MethodGet {
+		view.Heading = "🔕 알림 끄기 확인"
+		view.Message = action.Describe()
+		view.Confirm = "적용"
+		writeAlertActionPage(w, http.StatusOK, view)
+		return
+	}
+
+	until := api.monitor.alertDispatcher.Snoozes().Apply(action, time.Now())
+	api.monitor.logger.Infof("🔕 Alert %s applied via email link until %s: %s", action.Action, until.Format("2006-01-02 15:04"), action.Title)
+	if api.monitor.eventStore != nil {
+		api.monitor.eventStore.RecordAlertAction(action, "email", until)
+	}
+	view.Heading = "✅ 적용했습니다"
+	view.Message = fmt.Sprintf("%s 까지 이 알림을 보내지 않습니다.", until.Format("2006-01-02 15:04"))
+	if action.Action == AlertActionAck {
+		view.Message = fmt.Sprintf("복구 알림이 오거나 %s 이 될 때까지 이 알림을 보내지 않습니다.", until.Format("2006-01-02 15:04"))
+	}
+	writeAlertActionPage(w, http.StatusOK, view)
+}
+
+// handleSlackAction POST /slack/actions - Slack 알림 확인/끄기 버튼 (Bearer 토큰 대신 Slack 요청 서명으로 검증)
+func (api *APIServer) handleSlackAction(w http.ResponseWriter, r *http.Request) {
+	sm := api.monitor
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeAPIError(w, http.StatusMethodNotAllowed, "use POST")
+		return
+	}
+	if !sm.slackActions.Enabled() {
+		writeAPIError(w, http.StatusNotFound, "slack interactivity is not configured (set slack.signing_secret)")
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, APIRequestBodyLimit))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("failed to read body: %v", err))
+		return
+	}
+	if err := sm.slackActions.Verify(r.Header, body, time.Now()); err != nil {
+		writeAPIError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	request, err := ParseSlackAction(body)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	until := sm.alertDispatcher.Snoozes().Apply(request.Action, time.Now())
+	sm.logger.Infof("🔕 Alert %s applied via Slack by %s until %s: %s", request.Action.Action, request.Action.By, until.Format("2006-01-02 15:04"), request.Action.Title)
+	if sm.eventStore != nil {
+		sm.eventStore.RecordAlertAction(request.Action, "slack", until)
+	}
+	// Slack 은 3초 안에 응답을 받아야 하므로 채널 메시지는 따로 전송
+	go func() {
+		if err := sm.slackActions.Respond(request, until); err != nil {
+			sm.logger.Errorf("Slack action response failed: %v", err)
+		}
+	}()
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleSlackCommand POST /slack/commands - Slack 슬래시 명령 (Bearer 토큰 대신 Slack 요청 서명으로 검증)
+// Slack 은 응답 본문을 명령 결과 메시지로 표시하므로 권한/명령 오류도 200 으로 응답
+func (api *APIServer) handleSlackCommand(w http.ResponseWriter, r *http.Request) {
+	sm := api.monitor
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeAPIError(w, http.StatusMethodNotAllowed, "use POST")
+		return
+	}
+	if !sm.slackCommands.Enabled() {
+		writeAPIError(w, http.StatusNotFound, "slack commands are not configured (set slack.signing_secret)")
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, APIRequestBodyLimit))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("failed to read body: %v", err))
+		return
+	}
+	if err := sm.slackCommands.Verify(r.Header, body, time.Now()); err != nil {
+		writeAPIError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	request, err := ParseSlackCommand(body)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !sm.slackCommands.Allowed(request) {
+		sm.logger.Infof("⛔ Slack command %s %q refused for %s (%s): not in slack.command_users", request.Command, request.Text, request.UserName, request.UserID)
+		writeAPIJSON(w, http.StatusOK, slackCommandError("이 명령을 사용할 권한이 없습니다 (slack.command_users)."))
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, sm.runSlackCommand(request, time.Now()))
+}
+
+// writeAlertActionPage 알림 링크 HTML 페이지 응답 작성
+func writeAlertActionPage(w http.ResponseWriter, status int, view alertActionView) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	alertActionPage.Execute(w, view)
+}
+
+// handleThresholds POST /thresholds - 지정한 임계값만 변경 (0 이하는 기존 값 유지)
+func (api *APIServer) handleThresholds(w http.ResponseWriter, r *http.Request) {
+	sm := api.monitor
+	if api.scope(r).tenant != nil {
+		writeAPIError(w, http.StatusNotFound, "host metrics are not collected per tenant")
+		return
+	}
+	if sm.systemMonitor == nil {
+		writeAPIError(w, http.StatusServiceUnavailable, "system monitoring is disabled (start with -system-monitor)")
+		return
+	}
+
+	var request SystemThresholds
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %v", err))
+		return
+	}
+
+	var updated SystemThresholds
+	sm.runOnLoop(func() {
+		thresholds := sm.systemMonitor.GetThresholds()
+		mergeThreshold(&thresholds.CPUPercent, request.CPUPercent)
+		mergeThreshold(&thresholds.MemoryPercent, request.MemoryPercent)
+		mergeThreshold(&thresholds.DiskPercent, request.DiskPercent)
+		mergeThreshold(&thresholds.CPUTemp, request.CPUTemp)
+		mergeThreshold(&thresholds.LoadPerCore, request.LoadPerCore)
+		mergeThreshold(&thresholds.SwapPercent, request.SwapPercent)
+		mergeThreshold(&thresholds.MemoryPressure, request.MemoryPressure)
+		mergeThreshold(&thresholds.CPUPressure, request.CPUPressure)
+		mergeThreshold(&thresholds.IOPressure, request.IOPressure)
+		mergeThreshold(&thresholds.InodePercent, request.InodePercent)
+		mergeThreshold(&thresholds.ConnectionSpike, request.ConnectionSpike)
+		mergeThreshold(&thresholds.ConnectionMin, request.ConnectionMin)
+		mergeThreshold(&thresholds.DiskBusy, request.DiskBusy)
+		mergeThreshold(&thresholds.DiskIOPS, request.DiskIOPS)
+		mergeThreshold(&thresholds.DiskMBps, request.DiskMBps)
+		mergeThreshold(&thresholds.NetworkPercent, request.NetworkPercent)
+		mergeThreshold(&thresholds.NetworkMbps, request.NetworkMbps)
+		mergeThreshold(&thresholds.NetworkErrors, request.NetworkErrors)
+		sm.systemMonitor.SetThresholds(thresholds)
+		updated = sm.systemMonitor.GetThresholds()
+	})
+
+	sm.logger.Infof("🛰️  Thresholds updated via API from %s", r.RemoteAddr)
+	writeAPIJSON(w, http.StatusOK, updated)
+}
+
+// mergeThreshold 0보다 큰 값만 반영
+func mergeThreshold(current *float64, value float64) {
+	if value > 0 {
+		*current = value
+	}
+}
+
+// handleFilters POST /filters - 필터(정규식)/키워드 교체
+func (api *APIServer) handleFilters(w http.ResponseWriter, r *http.Request) {
+	sm := api.monitorFor(r)
+
+	var request apiFiltersRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %v", err))
+		return
+	}
+	if request.Filters == nil && request.Keywords == nil {
+		writeAPIError(w, http.StatusBadRequest, "specify filters and/or keywords")
+		return
+	}
+	var filter *LogFilter
+	if request.Filters != nil {
+		compiled, err := CompileLogFilter(*request.Filters)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter = compiled
+	}
+
+	var filters, keywords []string
+	sm.runOnLoop(func() {
+		if filter != nil {
+			sm.filter = filter
+		}
+		if request.Keywords != nil {
+			keywords := append([]string{}, *request.Keywords...)
+			if sm.loginWatch {
+				keywords = appendLoginKeywords(keywords)
+			}
+			sm.keywords = NewKeywordMatcher(keywords)
+		}
+		filters = sm.filter.Patterns()
+		keywords = sm.keywords.Keywords()
+	})
+
+	sm.logger.Infof("🛰️  Filters/keywords updated via API from %s", r.RemoteAddr)
+	writeAPIJSON(w, http.StatusOK, map[string][]string{"filters": filters, "keywords": keywords})
+}
+
+// handleTestAlert POST /test-alert - 설정된 모든 알림 채널로 테스트 알림 전송
+func (api *APIServer) handleTestAlert(w http.ResponseWriter, r *http.Request) {
+	sm := api.monitorFor(r)
+	if !sm.alertDispatcher.HasSinks() {
+		writeAPIError(w, http.StatusServiceUnavailable, "no alert channels are configured")
+		return
+	}
+
+	request := apiTestAlertRequest{Severity: AlertSeverityInfo}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %v", err))
+			return
+		}
+	}
+	switch request.Severity {
+	case AlertSeverityInfo, AlertSeverityWarning, AlertSeverityCritical:
+	default:
+		writeAPIError(w, http.StatusBadRequest, fmt.Sprintf("invalid severity %q (expected info, warning or critical)", request.Severity))
+		return
+	}
+	if request.Message == "" {
+		request.Message = "관리 API 에서 요청한 테스트 알림입니다."
+	}
+
+	sinks := sm.alertDispatcher.SinkNames()
+	sm.alertDispatcher.Dispatch(Alert{
+		Type:     AlertTypeTest,
+		Severity: request.Severity,
+		Title:    fmt.Sprintf("[%s TEST] Management API test alert", AppName),
+		Headline: "🧪 테스트 알림",
+		Sections: []AlertSection{{
+			Fields: []AlertField{
+				{Label: "메시지", Value: request.Message},
+				{Label: "요청 주소", Value: r.RemoteAddr, Short: true},
+				{Label: "알림 채널", Value: strings.Join(sinks, ", "), Short: true},
+			},
+			Summary: true,
+		}},
+		Fields: map[string]string{"message": request.Message},
+	})
+
+	sm.logger.Infof("🛰️  Test alert requested via API from %s", r.RemoteAddr)
+	writeAPIJSON(w, http.StatusAccepted, map[string]interface{}{"sent": true, "sinks": sinks})
+}
+
+// handleTenants GET /tenants - 테넌트 목록 (운영자 토큰 전용, 토큰 값은 노출하지 않음)
+func (api *APIServer) handleTenants(w http.ResponseWriter, r *http.Request) {
+	if api.scope(r).tenantToken {
+		writeAPIError(w, http.StatusForbidden, "tenant tokens cannot list tenants")
+		return
+	}
+
+	tenants := []apiTenant{}
+	for _, tenant := range api.tenants.Tenants() {
+		entry := apiTenant{
+			ID:        tenant.ID,
+			Name:      tenant.Name,
+			Sources:   tenant.Sources(),
+			Sinks:     tenant.monitor.alertDispatcher.SinkNames(),
+			APITokens: len(tenant.tokens),
+			Quota:     tenant.QuotaUsage(),
+		}
+		if tenant.monitor.eventStore != nil {
+			entry.EventStore = tenant.monitor.eventStore.Path()
+		}
+		tenants = append(tenants, entry)
+	}
+	writeAPIJSON(w, http.StatusOK, map[string]interface{}{"count": len(tenants), "tenants": tenants})
+}
+
+// handleMaintenance GET /maintenance - 점검 일정 캘린더 상태와 진행 중/다가오는 점검, 조용한 시간 창 상태 (운영자 토큰 전용)
+func (api *APIServer) handleMaintenance(w http.ResponseWriter, r *http.Request) {
+	if api.scope(r).tenant != nil {
+		writeAPIError(w, http.StatusForbidden, "maintenance calendars are only available to the operator")
+		return
+	}
+	schedule := api.monitor.alertDispatcher.Maintenance()
+	quiet := api.monitor.alertDispatcher.QuietHours()
+	if schedule == nil && quiet == nil {
+		writeAPIError(w, http.StatusNotFound, "no maintenance calendars or quiet hours configured")
+		return
+	}
+	now := time.Now()
+	active, upcoming := schedule.Windows(now, DefaultRecentAlerts)
+	writeAPIJSON(w, http.StatusOK, map[string]interface{}{
+		"calendars":   schedule.Status(),
+		"active":      active,
+		"upcoming":    upcoming,
+		"quiet_hours": quiet.Status(now),
+	})
+}
+
+// handleDigest GET /digest - 지금까지의 일일 요약 집계 (LLM 호출 없이, 운영자 토큰 전용)
+func (api *APIServer) handleDigest(w http.ResponseWriter, r *http.Request) {
+	if api.scope(r).tenant != nil {
+		writeAPIError(w, http.StatusForbidden, "the daily digest is only available to the operator")
+		return
+	}
+	digest := api.monitor.digest
+	if !digest.Enabled() {
+		writeAPIError(w, http.StatusNotFound, "daily digest is not enabled (set reports.digest.schedule or -digest-schedule)")
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, map[string]interface{}{
+		"schedule": digest.Schedule().String(),
+		"next":     digest.Schedule().Next(time.Now()),
+		"summary":  digest.Summarize(time.Now()),
+	})
+}
+
+// handleDigestSend POST /digest/send - 일일 요약을 지금 LLM 으로 작성해 전송 (비동기, 운영자 토큰 전용)
+func (api *APIServer) handleDigestSend(w http.ResponseWriter, r *http.Request) {
+	if api.scope(r).tenant != nil {
+		writeAPIError(w, http.StatusForbidden, "the daily digest is only available to the operator")
+		return
+	}
+	digest := api.monitor.digest
+	if !digest.Enabled() {
+		writeAPIError(w, http.StatusNotFound, "daily digest is not enabled (set reports.digest.schedule or -digest-schedule)")
+		return
+	}
+	if !api.monitor.alertDispatcher.HasSinks() {
+		writeAPIError(w, http.StatusServiceUnavailable, "no alert channels are configured")
+		return
+	}
+	go digest.Send(time.Now())
+	writeAPIJSON(w, http.StatusAccepted, map[string]string{"status": "sending"})
+}
+
+// writeAPIJSON JSON 응답 작성
+func writeAPIJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(value)
+}
+
+// writeAPIError {"error": "..."} 응답 작성
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPIJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,199 @@
+/*
+ASN History Module
+==================
+
+사용자별 로그인 ASN 히스토리와 ASN 변경 탐지
+
+주요 기능:
+- 성공한 로그인의 출처 ASN을 사용자별로 기록 (평소 로그인 ASN 기준선)
+- 평소와 다른 호스팅 사업자 ASN(OVH, Hetzner, DigitalOcean 등)에서 인증하면 계정 탈취 의심으로 판정
+- 이벤트 저장소(-db-path)가 설정되면 히스토리를 저장하고 재시작 시 복원
+
+판정 기준:
+- 해당 사용자의 기록된 로그인이 기준선 횟수(기본 3회) 이상
+- 이번 로그인 ASN이 사용자의 히스토리에 없음
+- 이번 로그인 ASN이 호스팅 사업자 목록(기본 목록 + login.hosting_asns)에 포함
+*/
+package main
+
+import (
+	"sort"    // ASN 목록 정렬
+	"strings" // 문자열 처리
+	"sync"    // 동기화 (뮤텍스)
+	"time"    // 시간 처리
+)
+
+// DefaultHostingASNs 호스팅/클라우드 사업자 ASN 기본 목록 (ASN -> 사업자명)
+var DefaultHostingASNs = map[string]string{
+	"AS16276":  "OVH",
+	"AS24940":  "Hetzner",
+	"AS213230": "Hetzner Cloud",
+	"AS14061":  "DigitalOcean",
+	"AS63949":  "Linode (Akamai)",
+	"AS20473":  "Vultr (Choopa)",
+	"AS51167":  "Contabo",
+	"AS12876":  "Scaleway",
+	"AS60781":  "Leaseweb",
+	"AS16509":  "Amazon AWS",
+	"AS14618":  "Amazon AWS",
+	"AS396982": "Google Cloud",
+	"AS8075":   "Microsoft Azure",
+	"AS31898":  "Oracle Cloud",
+	"AS45102":  "Alibaba Cloud",
+	"AS132203": "Tencent Cloud",
+}
+
+// UserASN 사용자의 로그인 ASN 기록
+type UserASN struct {
+	User      string
+	ASN       string // "AS16509" 형식
+	Org       string
+	FirstSeen time.Time
+	LastSeen  time.Time
+	Logins    int
+}
+
+// ASNHistoryStore 사용자별 ASN 히스토리 영구 저장소 (EventStore 구현)
+type ASNHistoryStore interface {
+	LoadUserASNs() ([]UserASN, error)
+	RecordUserASN(entry UserASN)
+}
+
+// ASNChange 평소와 다른 호스팅 사업자 ASN에서의 인증 판정 결과
+type ASNChange struct {
+	ASN       string   // 이번 로그인 ASN
+	Provider  string   // 호스팅 사업자명
+	UsualASNs []string // 사용자가 평소 로그인하던 ASN 목록
+}
+
+// ASNTracker 사용자별 로그인 ASN 히스토리 관리 및 ASN 변경 탐지
+type ASNTracker struct {
+	history        map[string]map[string]*UserASN // 사용자 -> ASN -> 기록
+	hostingASNs    map[string]string              // 호스팅 사업자 ASN -> 사업자명
+	baselineLogins int                            // 판정에 필요한 최소 기록 로그인 수
+	store          ASNHistoryStore                // 영구 저장소 (nil이면 메모리에만 보관)
+	mutex          sync.Mutex
+}
+
+// NewASNTracker 새로운 ASN 히스토리 관리자 생성
+func NewASNTracker() *ASNTracker {
+	hostingASNs := make(map[string]string, len(DefaultHostingASNs))
+	for asn, provider := range DefaultHostingASNs {
+		hostingASNs[asn] = provider
+	}
+	return &ASNTracker{
+		history:        make(map[string]map[string]*UserASN),
+		hostingASNs:    hostingASNs,
+		baselineLogins: DefaultASNBaselineLogins,
+	}
+}
+
+// Configure 추가 호스팅 사업자 ASN과 기준선 로그인 수 설정 (0 이하 값은 기존 값 유지)
+func (tr *ASNTracker) Configure(extraHostingASNs []string, baselineLogins int) {
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+
+	for _, spec := range extraHostingASNs {
+		if asn := normalizeASN(spec); asn != "" {
+			if _, exists := tr.hostingASNs[asn]; !exists {
+				tr.hostingASNs[asn] = asn
+			}
+		}
+	}
+	if baselineLogins > 0 {
+		tr.baselineLogins = baselineLogins
+	}
+}
+
+// SetStore 영구 저장소 설정 후 저장된 히스토리 복원 (메모리 기록과 병합)
+func (tr *ASNTracker) SetStore(store ASNHistoryStore) error {
+	entries, err := store.LoadUserASNs()
+	if err != nil {
+		return err
+	}
+
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+
+	tr.store = store
+	for i := range entries {
+		entry := entries[i]
+		known := tr.userHistory(entry.User)
+		if existing, exists := known[entry.ASN]; exists {
+			existing.Logins += entry.Logins
+			continue
+		}
+		known[entry.ASN] = &entry
+	}
+	return nil
+}
+
+// Observe 성공한 로그인의 ASN을 기록하고, 평소와 다른 호스팅 사업자 ASN이면 판정 결과 반환
+// asField는 ip-api.com 의 "as" 필드 (예: "AS14061 DigitalOcean, LLC")
+func (tr *ASNTracker) Observe(user, asField, org string, at time.Time) *ASNChange {
+	asn := normalizeASN(asField)
+	if user == "" || asn == "" {
+		return nil
+	}
+
+	tr.mutex.Lock()
+	known := tr.userHistory(user)
+	totalLogins := 0
+	for _, entry := range known {
+		totalLogins += entry.Logins
+	}
+
+	var change *ASNChange
+	provider, hosting := tr.hostingASNs[asn]
+	if _, seen := known[asn]; !seen && hosting && totalLogins >= tr.baselineLogins {
+		change = &ASNChange{ASN: asn, Provider: provider}
+		for usual := range known {
+			change.UsualASNs = append(change.UsualASNs, usual)
+		}
+		sort.Strings(change.UsualASNs)
+	}
+
+	entry, exists := known[asn]
+	if !exists {
+		entry = &UserASN{User: user, ASN: asn, FirstSeen: at}
+		known[asn] = entry
+	}
+	entry.Org = org
+	entry.LastSeen = at
+	entry.Logins++
+	store := tr.store
+	tr.mutex.Unlock()
+
+	if store != nil {
+		store.RecordUserASN(UserASN{User: user, ASN: asn, Org: org, FirstSeen: at, LastSeen: at})
+	}
+	return change
+}
+
+// userHistory 사용자의 ASN 기록 맵 (없으면 생성, 호출 시 잠금 필요)
+func (tr *ASNTracker) userHistory(user string) map[string]*UserASN {
+	known, exists := tr.history[user]
+	if !exists {
+		known = make(map[string]*UserASN)
+		tr.history[user] = known
+	}
+	return known
+}
+
+// normalizeASN "AS14061 DigitalOcean, LLC", "as14061", "14061" 형식을 "AS14061" 로 변환
+func normalizeASN(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return ""
+	}
+	number := strings.TrimPrefix(strings.ToUpper(fields[0]), "AS")
+	if number == "" {
+		return ""
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	return "AS" + number
+}
@@ -0,0 +1,228 @@
+/*
+Auditd Log Parser Module
+========================
+
+Linux 감사 로그(/var/log/audit/audit.log) 파서
+
+auditd 가 직접 기록한 줄과 audisp/syslog 로 전달된 줄
+("Oct 16 10:00:00 host audispd: node=host type=SYSCALL msg=audit(...): ...") 을 모두 인식합니다.
+
+주요 기능:
+- type=SYSCALL: uid/auid/euid, exe, comm, success, exit, syscall (x86_64 는 이름 포함), key
+- type=EXECVE: argc, 인자 a0..aN 을 이어 붙인 command
+- type=USER_LOGIN, USER_AUTH, USER_START 등 사용자 레코드: msg='...' 안의 op, acct, id(uid), exe, addr, terminal, res(success)
+- 16진수로 인코딩된 값(공백이 있는 exe/comm/proctitle/인자, 여러 개의 key) 복원
+- log_format=ENRICHED 의 이름 필드 (UID="root" → uid_name)
+- 실패(success=no, res=failed)는 WARNING 레벨
+
+권한 상승 이상 패턴은 AI 분석기 기본 패턴 (Auditd_*) 으로 제공됩니다.
+*/
+package main
+
+import (
+	"encoding/hex" // 인코딩된 값 복원
+	"fmt"          // 형식화된 I/O
+	"regexp"       // 레코드 헤더 매칭
+	"strconv"      // 숫자 변환
+	"strings"      // 문자열 처리
+	"time"         // 이벤트 시간
+)
+
+// auditdRecordRegex 레코드 헤더: type=<유형> msg=audit(<초>.<밀리초>:<일련번호>): <필드>
+var auditdRecordRegex = regexp.MustCompile(`(?:^|\s)type=([A-Z_]+) msg=audit\((\d+)\.(\d+):(\d+)\):\s?(.*)$`)
+
+// auditdEncodedFields 값에 공백/특수 문자가 있으면 따옴표 없이 16진수로 기록되는 필드
+var auditdEncodedFields = map[string]bool{
+	"exe": true, "comm": true, "proctitle": true, "cmd": true, "key": true,
+	"name": true, "cwd": true, "acct": true, "data": true,
+}
+
+// auditdSyscallNames x86_64 (arch=c000003e) 주요 시스템 호출 번호 → 이름
+var auditdSyscallNames = map[string]string{
+	"2": "open", "59": "execve", "90": "chmod", "91": "fchmod", "92": "chown", "101": "ptrace",
+	"105": "setuid", "106": "setgid", "113": "setreuid", "114": "setregid", "117": "setresuid",
+	"119": "setresgid", "165": "mount", "175": "init_module", "176": "delete_module",
+	"257": "openat", "268": "fchmodat", "313": "finit_module", "322": "execveat",
+}
+
+// AuditdLogParser Linux 감사 로그 파서
+type AuditdLogParser struct{}
+
+// NewAuditdLogParser 감사 로그 파서 생성
+func NewAuditdLogParser() *AuditdLogParser {
+	return &AuditdLogParser{}
+}
+
+// Parse 감사 로그 레코드 파싱
+func (p *AuditdLogParser) Parse(line string) (*ParsedLog, error) {
+	matches := auditdRecordRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, fmt.Errorf("not an audit record")
+	}
+	recordType, body := matches[1], matches[5]
+
+	parsed := &ParsedLog{
+		LogType: "auditd",
+		Level:   "INFO",
+		Source:  "auditd",
+		RawLog:  line,
+		Fields: map[string]string{
+			"record_type": recordType,
+			"audit_id":    matches[4],
+		},
+	}
+	seconds, _ := strconv.ParseInt(matches[2], 10, 64)
+	millis, _ := strconv.ParseInt(matches[3], 10, 64)
+	parsed.Timestamp = time.Unix(seconds, millis*int64(time.Millisecond))
+
+	// ENRICHED 형식은 원본 필드 뒤에 0x1d 로 구분한 이름 필드를 붙임 (UID="root" AUID="alice")
+	body, enriched, _ := strings.Cut(body, "\x1d")
+	fields := parseAuditdFields(body, recordType == "EXECVE")
+	// 사용자 레코드는 실제 내용이 msg='...' 안에 있음
+	if inner, ok := fields["msg"]; ok {
+		delete(fields, "msg")
+		for key, value := range parseAuditdFields(inner, false) {
+			if _, exists := fields[key]; !exists {
+				fields[key] = value
+			}
+		}
+	}
+	for key, value := range fields {
+		if value != "" && value != "?" && value != "(null)" {
+			parsed.Fields[key] = value
+		}
+	}
+	for key, value := range parseAuditdFields(enriched, false) {
+		if value != "" && value != "?" && value != "unset" {
+			parsed.Fields[strings.ToLower(key)+"_name"] = value
+		}
+	}
+
+	// 사용자 레코드의 id 는 대상 계정 uid (바깥 uid 는 sshd/login 등 기록한 프로세스), res 는 성공 여부
+	if id := parsed.Fields["id"]; id != "" {
+		parsed.Fields["uid"] = id
+	}
+	if res := parsed.Fields["res"]; res != "" && parsed.Fields["success"] == "" {
+		if res == "success" || res == "1" {
+			parsed.Fields["success"] = "yes"
+		} else {
+			parsed.Fields["success"] = "no"
+		}
+	}
+	if parsed.Fields["success"] == "no" {
+		parsed.Level = "WARNING"
+	}
+	if name := auditdSyscallNames[parsed.Fields["syscall"]]; name != "" && parsed.Fields["arch"] == "c000003e" {
+		parsed.Fields["syscall_name"] = name
+	}
+
+	switch recordType {
+	case "SYSCALL":
+		call := parsed.Fields["syscall_name"]
+		if call == "" {
+			call = "syscall " + parsed.Fields["syscall"]
+		}
+		parsed.Message = fmt.Sprintf("%s by uid %s (auid %s, euid %s) exe=%s success=%s",
+			call, parsed.Fields["uid"], parsed.Fields["auid"], parsed.Fields["euid"], parsed.Fields["exe"], parsed.Fields["success"])
+	case "EXECVE":
+		argc, _ := strconv.Atoi(parsed.Fields["argc"])
+		args := make([]string, 0, argc)
+		for i := 0; i < argc; i++ {
+			args = append(args, parsed.Fields["a"+strconv.Itoa(i)])
+		}
+		parsed.Fields["command"] = strings.Join(args, " ")
+		parsed.Message = "execve: " + parsed.Fields["command"]
+	default:
+		if op := parsed.Fields["op"]; op != "" {
+			parsed.Message = fmt.Sprintf("%s %s acct=%s uid=%s exe=%s addr=%s res=%s", recordType, op,
+				parsed.Fields["acct"], parsed.Fields["uid"], parsed.Fields["exe"], parsed.Fields["addr"], parsed.Fields["res"])
+		} else {
+			parsed.Message = recordType + " " + strings.TrimSpace(body)
+		}
+	}
+	if key := parsed.Fields["key"]; key != "" {
+		parsed.Message += " key=" + key
+	}
+
+	if parsed.Level == "WARNING" {
+		parsed.ErrorDetails = &ErrorDetails{ErrorType: "AUDIT_FAILURE", ErrorCode: parsed.Fields["exit"], Module: recordType}
+	}
+	return parsed, nil
+}
+
+// parseAuditdFields 공백으로 구분된 name=value 목록 파싱 (따옴표 "..." '...' 값 허용, 인코딩된 값 복원)
+// decodeArgs 는 EXECVE 인자(a0, a1, ...) 도 복원 (SYSCALL 의 a0~a3 는 16진수 레지스터 값이므로 그대로 둠)
+func parseAuditdFields(text string, decodeArgs bool) map[string]string {
+	fields := make(map[string]string)
+	for i := 0; i < len(text); {
+		for i < len(text) && text[i] == ' ' {
+			i++
+		}
+		eq := strings.IndexByte(text[i:], '=')
+		if eq <= 0 {
+			break
+		}
+		name := text[i : i+eq]
+		if space := strings.IndexByte(name, ' '); space >= 0 {
+			// 값 없는 토큰은 건너뜀
+			i += space + 1
+			continue
+		}
+		i += eq + 1
+
+		var value string
+		quoted := i < len(text) && (text[i] == '"' || text[i] == '\'')
+		if quoted {
+			quote := text[i]
+			end := strings.IndexByte(text[i+1:], quote)
+			if end < 0 {
+				value, i = text[i+1:], len(text)
+			} else {
+				value, i = text[i+1:i+1+end], i+end+2
+			}
+		} else {
+			end := strings.IndexByte(text[i:], ' ')
+			if end < 0 {
+				end = len(text) - i
+			}
+			value, i = text[i:i+end], i+end
+		}
+
+		if !quoted && (auditdEncodedFields[name] || decodeArgs && isAuditdArgName(name)) {
+			value = decodeAuditdValue(value)
+		}
+		fields[name] = value
+	}
+	return fields
+}
+
+// isAuditdArgName EXECVE 인자 필드 (a0, a1, ...) 인지 확인
+func isAuditdArgName(name string) bool {
+	if len(name) < 2 || name[0] != 'a' {
+		return false
+	}
+	_, err := strconv.Atoi(name[1:])
+	return err == nil
+}
+
+// decodeAuditdValue 따옴표 없는 16진수 값 복원 (key 의 여러 값 구분자 0x01 은 쉼표로)
+func decodeAuditdValue(value string) string {
+	if value == "" || value == "(null)" || len(value)%2 != 0 {
+		return value
+	}
+	decoded, err := hex.DecodeString(value)
+	if err != nil {
+		return value
+	}
+	return strings.ReplaceAll(strings.TrimRight(string(decoded), "\x00"), "\x01", ",")
+}
+
+// GetLogType 로그 타입 반환
+func (p *AuditdLogParser) GetLogType() string {
+	return "auditd"
+}
+
+// DetectFormat 포맷 감지 (auditd 직접 기록, audisp/syslog 전달)
+func (p *AuditdLogParser) DetectFormat(line string) bool {
+	return strings.Contains(line, "msg=audit(") && auditdRecordRegex.MatchString(line)
+}
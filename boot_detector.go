@@ -0,0 +1,522 @@
+/*
+Boot Detection Module
+=====================
+
+시스템 재부팅 감지 및 부팅 보고서 서비스
+
+주요 기능:
+  - 부팅 ID 변경 및 가동 시간(uptime) 초기화로 재부팅 감지
+  - 이전 부팅의 로그를 바탕으로 재부팅 원인 추정
+    (커널 패닉, 예정된 종료/재부팅, 전원 차단 또는 비정상 종료)
+  - 부팅 후 감시 대상 서비스 복구 여부 확인
+  - 이메일/Slack 부팅 보고서 전송
+
+지원 플랫폼:
+- Linux: /proc/sys/kernel/random/boot_id, /proc/stat btime, journalctl -b -1, systemctl
+- macOS: kern.bootsessionuuid, kern.boottime, DiagnosticReports *.panic, launchctl
+
+상태 파일(~/.syslog-monitor/boot_state.json)에 마지막 부팅 정보와
+실행 중 관찰한 종료 관련 로그를 저장하여 재시작 이후에도 비교합니다.
+*/
+package main
+
+import (
+	"context"       // 종료 신호
+	"encoding/json" // 상태 파일 직렬화
+	"fmt"           // 형식화된 I/O
+	"io/ioutil"     // 파일 I/O 유틸리티
+	"os"            // OS 인터페이스
+	"os/exec"       // 외부 명령 실행
+	"path/filepath" // 파일 경로 처리
+	"runtime"       // Go 런타임 정보
+	"strconv"       // 문자열-숫자 변환
+	"strings"       // 문자열 처리
+	"sync"          // 동기화
+	"time"          // 시간 처리
+)
+
+// 재부팅 원인 분류
+const (
+	RebootReasonPanic     = "커널 패닉"
+	RebootReasonScheduled = "예정된 종료/재부팅"
+	RebootReasonClean     = "정상 종료 (원인 미상)"
+	RebootReasonPowerLoss = "전원 차단 또는 비정상 종료 추정"
+)
+
+// maxBootEvidence 상태 파일에 보관할 종료 관련 로그 최대 개수
+const maxBootEvidence = 5
+
+// panicPatterns 커널 패닉 관련 로그 패턴 (소문자 비교)
+var panicPatterns = []string{
+	"kernel panic",
+	"panic(cpu",
+	"oops:",
+	"watchdog: bug: soft lockup",
+	"hard lockup",
+}
+
+// shutdownPatterns 예정된 종료/재부팅 관련 로그 패턴 (소문자 비교)
+var shutdownPatterns = []string{
+	"systemd-shutdown",
+	"reached target shutdown",
+	"reached target reboot",
+	"reached target power-off",
+	"reboot: restarting system",
+	"reboot: power down",
+	"the system is going down",
+	"shutdown scheduled",
+	"com.apple.shutdown",
+	"shutdown cause",
+}
+
+// BootInfo 현재 부팅 정보
+type BootInfo struct {
+	BootID   string    `json:"boot_id"`
+	BootTime time.Time `json:"boot_time"`
+}
+
+// BootState 재시작 간에 유지되는 부팅 감지 상태
+type BootState struct {
+	Boot          BootInfo  `json:"boot"`
+	LastSeen      time.Time `json:"last_seen"`      // 마지막으로 살아있음을 확인한 시각
+	CleanShutdown bool      `json:"clean_shutdown"` // 모니터가 종료 시그널로 정상 종료되었는지
+	PanicSeen     bool      `json:"panic_seen"`
+	ShutdownSeen  bool      `json:"shutdown_seen"`
+	Evidence      []string  `json:"evidence"` // 원인 추정 근거 로그
+}
+
+// BootReport 재부팅 보고서
+type BootReport struct {
+	Hostname          string
+	PreviousBoot      BootInfo
+	CurrentBoot       BootInfo
+	LastSeen          time.Time
+	EstimatedDowntime time.Duration
+	Reason            string
+	Evidence          []string
+	ServiceStatus     map[string]string // 서비스명 -> 상태
+	FailedServices    []string
+}
+
+// BootDetector 재부팅 감지 서비스
+type BootDetector struct {
+	logger          Logger
+	alertDispatcher *AlertDispatcher
+	statePath       string
+	state           BootState
+	stateMutex      sync.Mutex
+	watchedServices []string
+	checkInterval   time.Duration
+}
+
+// NewBootDetector 새로운 재부팅 감지 서비스 생성
+func NewBootDetector(logger Logger, alertDispatcher *AlertDispatcher) *BootDetector {
+	return &BootDetector{
+		logger:          logger,
+		alertDispatcher: alertDispatcher,
+		statePath:       filepath.Join(getDataDir(), BootStateFile),
+		checkInterval:   DefaultMonitoringInterval,
+	}
+}
+
+// SetWatchedServices 부팅 후 복구 여부를 확인할 서비스 설정
+func (bd *BootDetector) SetWatchedServices(services []string) {
+	bd.watchedServices = services
+}
+
+// Start 재부팅 감지 시작 (즉시 1회 확인 후 주기적으로 확인, ctx 가 끝나면 중단)
+func (bd *BootDetector) Start(ctx context.Context) {
+	bd.loadState()
+
+	go func() {
+		bd.check()
+
+		ticker := time.NewTicker(bd.checkInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				bd.check()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// ObserveLine 로그 라인에서 패닉/종료 징후를 기록
+func (bd *BootDetector) ObserveLine(line string) {
+	lower := strings.ToLower(line)
+	isPanic := containsAny(lower, panicPatterns)
+	isShutdown := !isPanic && containsAny(lower, shutdownPatterns)
+	if !isPanic && !isShutdown {
+		return
+	}
+
+	bd.stateMutex.Lock()
+	defer bd.stateMutex.Unlock()
+
+	if isPanic {
+		bd.state.PanicSeen = true
+	} else {
+		bd.state.ShutdownSeen = true
+	}
+	bd.state.Evidence = appendEvidence(bd.state.Evidence, line)
+	bd.saveStateLocked()
+}
+
+// MarkCleanShutdown 모니터 정상 종료 기록 (SIGINT/SIGTERM 수신 시)
+func (bd *BootDetector) MarkCleanShutdown() {
+	bd.stateMutex.Lock()
+	defer bd.stateMutex.Unlock()
+
+	bd.state.CleanShutdown = true
+	bd.state.LastSeen = time.Now()
+	bd.saveStateLocked()
+}
+
+// check 현재 부팅 정보를 저장된 상태와 비교
+func (bd *BootDetector) check() {
+	current, err := readBootInfo()
+	if err != nil {
+		bd.logger.Errorf("Failed to read boot info: %v", err)
+		return
+	}
+
+	bd.stateMutex.Lock()
+	previous := bd.state
+	rebooted := !previous.Boot.BootTime.IsZero() && isDifferentBoot(previous.Boot, current)
+	if previous.Boot.BootTime.IsZero() || rebooted {
+		// 새 부팅 기준으로 상태 초기화
+		bd.state = BootState{Boot: current}
+	}
+	bd.state.LastSeen = time.Now()
+	bd.state.CleanShutdown = false
+	bd.saveStateLocked()
+	bd.stateMutex.Unlock()
+
+	if rebooted {
+		bd.logger.Infof("🔄 System reboot detected (boot time: %s)", current.BootTime.Format("2006-01-02 15:04:05"))
+		go bd.reportReboot(previous, current)
+	}
+}
+
+// isDifferentBoot 두 부팅 정보가 서로 다른 부팅인지 판단
+func isDifferentBoot(previous, current BootInfo) bool {
+	if previous.BootID != "" && current.BootID != "" {
+		return previous.BootID != current.BootID
+	}
+	diff := current.BootTime.Sub(previous.BootTime)
+	return diff > BootTimeTolerance || diff < -BootTimeTolerance
+}
+
+// reportReboot 부팅 보고서 생성 및 전송
+func (bd *BootDetector) reportReboot(previous BootState, current BootInfo) {
+	// 서비스가 기동될 시간을 확보한 뒤 확인
+	if wait := BootServiceCheckDelay - time.Since(current.BootTime); wait > 0 {
+		time.Sleep(wait)
+	}
+
+	report := bd.buildReport(previous, current)
+	subject := fmt.Sprintf("[%s] 🔄 시스템 재부팅 감지 - %s (%s)", AppName, report.Hostname, report.Reason)
+	body := report.Format()
+
+	severity := AlertSeverityWarning
+	if report.Reason == RebootReasonPanic || report.Reason == RebootReasonPowerLoss || len(report.FailedServices) > 0 {
+		severity = AlertSeverityCritical
+	}
+
+	slackMsg := report.SlackMessage()
+	bd.alertDispatcher.Dispatch(Alert{
+		Type:     AlertTypeBoot,
+		Severity: severity,
+		Title:    subject,
+		Body:     body,
+		Host:     report.Hostname,
+		Fields: map[string]string{
+			"reason":          report.Reason,
+			"failed_services": strings.Join(report.FailedServices, ", "),
+		},
+		Slack: &slackMsg,
+	})
+}
+
+// buildReport 이전 부팅 상태와 로그를 바탕으로 재부팅 원인 추정
+func (bd *BootDetector) buildReport(previous BootState, current BootInfo) *BootReport {
+	hostname, _ := os.Hostname()
+	report := &BootReport{
+		Hostname:      hostname,
+		PreviousBoot:  previous.Boot,
+		CurrentBoot:   current,
+		LastSeen:      previous.LastSeen,
+		Evidence:      previous.Evidence,
+		ServiceStatus: make(map[string]string),
+	}
+	if !previous.LastSeen.IsZero() && current.BootTime.After(previous.LastSeen) {
+		report.EstimatedDowntime = current.BootTime.Sub(previous.LastSeen)
+	}
+
+	// 모니터가 놓친 종료 직전 로그 보강
+	panicSeen, shutdownSeen := previous.PanicSeen, previous.ShutdownSeen
+	for _, line := range previousBootLogs(previous.Boot) {
+		lower := strings.ToLower(line)
+		if containsAny(lower, panicPatterns) {
+			panicSeen = true
+			report.Evidence = appendEvidence(report.Evidence, line)
+		} else if containsAny(lower, shutdownPatterns) {
+			shutdownSeen = true
+			report.Evidence = appendEvidence(report.Evidence, line)
+		}
+	}
+
+	switch {
+	case panicSeen:
+		report.Reason = RebootReasonPanic
+	case shutdownSeen:
+		report.Reason = RebootReasonScheduled
+	case previous.CleanShutdown:
+		report.Reason = RebootReasonClean
+	default:
+		report.Reason = RebootReasonPowerLoss
+	}
+
+	for _, service := range bd.watchedServices {
+		status := checkServiceStatus(service)
+		report.ServiceStatus[service] = status
+		if status != "active" {
+			report.FailedServices = append(report.FailedServices, service)
+		}
+	}
+
+	return report
+}
+
+// Format 이메일용 보고서 본문
+func (br *BootReport) Format() string {
+	body := fmt.Sprintf(`🔄 시스템 재부팅 보고서
+======================
+
+🖥️  호스트명: %s
+🕐 현재 부팅 시각: %s
+🕐 이전 부팅 시각: %s
+👁️  마지막 확인 시각: %s
+⏱️  추정 중단 시간: %s
+
+🔍 추정 원인: %s
+`,
+		br.Hostname,
+		br.CurrentBoot.BootTime.Format("2006-01-02 15:04:05"),
+		br.PreviousBoot.BootTime.Format("2006-01-02 15:04:05"),
+		br.LastSeen.Format("2006-01-02 15:04:05"),
+		br.EstimatedDowntime.Round(time.Second),
+		br.Reason,
+	)
+
+	if len(br.Evidence) > 0 {
+		body += "\n📋 근거 로그:\n"
+		for _, line := range br.Evidence {
+			body += fmt.Sprintf("   %s\n", line)
+		}
+	}
+
+	if len(br.ServiceStatus) > 0 {
+		body += "\n🧩 감시 서비스 상태:\n"
+		for service, status := range br.ServiceStatus {
+			icon := "✅"
+			if status != "active" {
+				icon = "❌"
+			}
+			body += fmt.Sprintf("   %s %s: %s\n", icon, service, status)
+		}
+		if len(br.FailedServices) > 0 {
+			body += fmt.Sprintf("\n⚠️  복구되지 않은 서비스 %d개: %s\n", len(br.FailedServices), strings.Join(br.FailedServices, ", "))
+		}
+	}
+
+	return body
+}
+
+// SlackMessage Slack용 보고서 메시지
+func (br *BootReport) SlackMessage() SlackMessage {
+	color := SlackColorWarning
+	if br.Reason == RebootReasonPanic || br.Reason == RebootReasonPowerLoss || len(br.FailedServices) > 0 {
+		color = SlackColorDanger
+	}
+
+	services := "감시 서비스 없음"
+	if len(br.ServiceStatus) > 0 {
+		services = fmt.Sprintf("%d/%d 정상", len(br.ServiceStatus)-len(br.FailedServices), len(br.ServiceStatus))
+		if len(br.FailedServices) > 0 {
+			services += fmt.Sprintf(" (실패: %s)", strings.Join(br.FailedServices, ", "))
+		}
+	}
+
+	fields := []SlackField{
+		{Title: "호스트", Value: br.Hostname, Short: true},
+		{Title: "추정 원인", Value: br.Reason, Short: true},
+		{Title: "부팅 시각", Value: br.CurrentBoot.BootTime.Format("2006-01-02 15:04:05"), Short: true},
+		{Title: "추정 중단 시간", Value: br.EstimatedDowntime.Round(time.Second).String(), Short: true},
+		{Title: "서비스 상태", Value: services, Short: false},
+	}
+	if len(br.Evidence) > 0 {
+		fields = append(fields, SlackField{Title: "근거 로그", Value: strings.Join(br.Evidence, "\n"), Short: false})
+	}
+
+	return SlackMessage{
+		Text:      fmt.Sprintf(":arrows_counterclockwise: *시스템 재부팅 감지*: %s", br.Hostname),
+		IconEmoji: ":robot_face:",
+		Username:  DefaultSlackUsername,
+		Attachments: []SlackAttachment{{
+			Color:     color,
+			Title:     "🔄 부팅 보고서",
+			Fields:    fields,
+			Timestamp: time.Now().Unix(),
+		}},
+	}
+}
+
+// loadState 상태 파일 로드 (없으면 빈 상태로 시작)
+func (bd *BootDetector) loadState() {
+	bd.stateMutex.Lock()
+	defer bd.stateMutex.Unlock()
+
+	data, err := ioutil.ReadFile(bd.statePath)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, &bd.state); err != nil {
+		bd.logger.Errorf("Failed to parse boot state file: %v", err)
+	}
+}
+
+// saveStateLocked 상태 파일 저장 (stateMutex 보유 상태에서 호출)
+func (bd *BootDetector) saveStateLocked() {
+	if err := os.MkdirAll(filepath.Dir(bd.statePath), ConfigPermissions); err != nil {
+		bd.logger.Errorf("Failed to create state directory: %v", err)
+		return
+	}
+
+	data, err := json.MarshalIndent(bd.state, "", "    ")
+	if err != nil {
+		bd.logger.Errorf("Failed to marshal boot state: %v", err)
+		return
+	}
+	if err := ioutil.WriteFile(bd.statePath, data, 0644); err != nil {
+		bd.logger.Errorf("Failed to write boot state file: %v", err)
+	}
+}
+
+// readBootInfo 현재 부팅 ID 및 부팅 시각 조회
+func readBootInfo() (BootInfo, error) {
+	var info BootInfo
+
+	if runtime.GOOS == "linux" {
+		if data, err := ioutil.ReadFile(hostProcPath("sys", "kernel", "random", "boot_id")); err == nil {
+			info.BootID = strings.TrimSpace(string(data))
+		}
+
+		// /proc/stat 의 btime (Unix 초)
+		data, err := ioutil.ReadFile(hostProcPath("stat"))
+		if err != nil {
+			return info, fmt.Errorf("failed to read /proc/stat: %v", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(line, "btime ") {
+				if sec, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "btime ")), 10, 64); err == nil {
+					info.BootTime = time.Unix(sec, 0)
+				}
+				break
+			}
+		}
+	} else if runtime.GOOS == "darwin" {
+		if output, err := exec.Command("sysctl", "-n", "kern.bootsessionuuid").Output(); err == nil {
+			info.BootID = strings.TrimSpace(string(output))
+		}
+
+		// { sec = 1700000000, usec = 123456 } Tue Nov 14 22:13:20 2023
+		output, err := exec.Command("sysctl", "-n", "kern.boottime").Output()
+		if err != nil {
+			return info, fmt.Errorf("failed to read kern.boottime: %v", err)
+		}
+		fields := strings.Fields(strings.NewReplacer("{", " ", "}", " ", ",", " ").Replace(string(output)))
+		for i := 0; i+2 < len(fields); i++ {
+			if fields[i] == "sec" && fields[i+1] == "=" {
+				if sec, err := strconv.ParseInt(fields[i+2], 10, 64); err == nil {
+					info.BootTime = time.Unix(sec, 0)
+				}
+				break
+			}
+		}
+	}
+
+	if info.BootTime.IsZero() {
+		return info, fmt.Errorf("boot time not available on %s", runtime.GOOS)
+	}
+	return info, nil
+}
+
+// previousBootLogs 이전 부팅의 종료 직전 로그 조회 (지원되는 경우)
+func previousBootLogs(previous BootInfo) []string {
+	if runtime.GOOS == "linux" {
+		output, err := exec.Command("journalctl", "-b", "-1", "-n", "300", "--no-pager", "-o", "short").Output()
+		if err != nil {
+			return nil // 영구 저널이 비활성화된 경우
+		}
+		return strings.Split(strings.TrimSpace(string(output)), "\n")
+	}
+
+	if runtime.GOOS == "darwin" {
+		// 이전 부팅 이후 생성된 패닉 리포트
+		var lines []string
+		matches, _ := filepath.Glob("/Library/Logs/DiagnosticReports/*.panic")
+		for _, path := range matches {
+			if info, err := os.Stat(path); err == nil && info.ModTime().After(previous.BootTime) {
+				lines = append(lines, fmt.Sprintf("kernel panic report: %s", filepath.Base(path)))
+			}
+		}
+		return lines
+	}
+
+	return nil
+}
+
+// checkServiceStatus 서비스 실행 상태 조회 (active 이면 정상)
+func checkServiceStatus(service string) string {
+	if runtime.GOOS == "darwin" {
+		output, err := exec.Command("launchctl", "list", service).Output()
+		if err != nil {
+			return "not loaded"
+		}
+		if strings.Contains(string(output), "\"PID\"") {
+			return "active"
+		}
+		return "not running"
+	}
+
+	// systemctl is-active 는 비활성 시 종료 코드가 0이 아니지만 상태 문자열은 출력함
+	output, _ := exec.Command("systemctl", "is-active", service).Output()
+	status := strings.TrimSpace(string(output))
+	if status == "" {
+		return "unknown"
+	}
+	return status
+}
+
+// containsAny 문자열이 패턴 중 하나라도 포함하는지 확인
+func containsAny(s string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(s, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendEvidence 근거 로그 추가 (최근 maxBootEvidence 개 유지)
+func appendEvidence(evidence []string, line string) []string {
+	evidence = append(evidence, strings.TrimSpace(line))
+	if len(evidence) > maxBootEvidence {
+		evidence = evidence[len(evidence)-maxBootEvidence:]
+	}
+	return evidence
+}
@@ -0,0 +1,144 @@
+/*
+Brute-Force Detection Module
+============================
+
+# IP 단위 로그인 실패 슬라이딩 윈도우 카운터
+
+주요 기능:
+- 출발지 IP 별 최근 로그인 실패 기록 (사용자명과 무관하게 합산)
+- 윈도우(기본 5분) 내 실패가 임계값(기본 10회) 이상이면 무차별 대입 공격으로 판정
+- 같은 IP 는 윈도우 동안 한 번만 보고 (공격이 계속되면 윈도우가 지난 뒤 다시 보고)
+
+login_correlation.go 의 실패 → 성공 상관 분석은 "공격 성공" 을,
+이 모듈은 성공 여부와 무관한 "공격 진행 중" 을 탐지
+*/
+package main
+
+import (
+	"sort" // 시도된 사용자명 정렬
+	"sync" // 동기화 (뮤텍스)
+	"time" // 시간 처리
+)
+
+// BruteForceAttack IP 단위 무차별 대입 공격 탐지 결과
+type BruteForceAttack struct {
+	IP        string        // 공격 출발지 IP
+	Failures  int           // 윈도우 내 실패 횟수
+	Users     []string      // 시도된 사용자명 (정렬, 중복 제거)
+	FirstSeen time.Time     // 윈도우 내 첫 실패 시각
+	Window    time.Duration // 판정 윈도우
+}
+
+// bruteForceAttempt 로그인 실패 한 건
+type bruteForceAttempt struct {
+	at   time.Time
+	user string
+}
+
+// BruteForceDetector IP 단위 로그인 실패 슬라이딩 윈도우 카운터
+type BruteForceDetector struct {
+	attempts  map[string][]bruteForceAttempt // IP -> 윈도우 내 실패 (오래된 순)
+	reported  map[string]time.Time           // IP -> 마지막 보고 시각 (윈도우 동안 재보고 억제)
+	mutex     sync.Mutex
+	window    time.Duration
+	threshold int
+}
+
+// NewBruteForceDetector 새로운 무차별 대입 탐지기 생성 (0 이하 값은 기본값 사용)
+func NewBruteForceDetector(window time.Duration, threshold int) *BruteForceDetector {
+	if window <= 0 {
+		window = DefaultBruteForceWindow
+	}
+	if threshold <= 0 {
+		threshold = DefaultBruteForceThreshold
+	}
+	return &BruteForceDetector{
+		attempts:  make(map[string][]bruteForceAttempt),
+		reported:  make(map[string]time.Time),
+		window:    window,
+		threshold: threshold,
+	}
+}
+
+// Configure 판정 기준 변경 (0 이하 값은 기존 값 유지, 실패 기록은 보존)
+func (bd *BruteForceDetector) Configure(window time.Duration, threshold int) {
+	bd.mutex.Lock()
+	defer bd.mutex.Unlock()
+	if window > 0 {
+		bd.window = window
+	}
+	if threshold > 0 {
+		bd.threshold = threshold
+	}
+}
+
+// RecordFailure 로그인 실패 기록, 임계값에 도달하면 탐지 결과 반환 (아니면 nil)
+func (bd *BruteForceDetector) RecordFailure(user, ip string, at time.Time) *BruteForceAttack {
+	if ip == "" {
+		return nil
+	}
+
+	bd.mutex.Lock()
+	defer bd.mutex.Unlock()
+
+	// 판정에는 최근 threshold 건만 필요하므로 공격이 계속돼도 IP 당 기록 수는 고정
+	attempts := append(bd.prune(bd.attempts[ip], at), bruteForceAttempt{at: at, user: user})
+	if len(attempts) > bd.threshold {
+		attempts = attempts[len(attempts)-bd.threshold:]
+	}
+	bd.attempts[ip] = attempts
+	if len(attempts) < bd.threshold {
+		return nil
+	}
+	if last, ok := bd.reported[ip]; ok && at.Sub(last) < bd.window {
+		return nil
+	}
+	bd.reported[ip] = at
+
+	seen := make(map[string]bool)
+	var users []string
+	for _, attempt := range attempts {
+		if attempt.user != "" && !seen[attempt.user] {
+			seen[attempt.user] = true
+			users = append(users, attempt.user)
+		}
+	}
+	sort.Strings(users)
+
+	return &BruteForceAttack{
+		IP:        ip,
+		Failures:  len(attempts),
+		Users:     users,
+		FirstSeen: attempts[0].at,
+		Window:    bd.window,
+	}
+}
+
+// Cleanup 윈도우를 벗어난 실패/보고 기록 정리
+func (bd *BruteForceDetector) Cleanup(now time.Time) {
+	bd.mutex.Lock()
+	defer bd.mutex.Unlock()
+
+	for ip, attempts := range bd.attempts {
+		if recent := bd.prune(attempts, now); len(recent) == 0 {
+			delete(bd.attempts, ip)
+		} else {
+			bd.attempts[ip] = recent
+		}
+	}
+	for ip, last := range bd.reported {
+		if now.Sub(last) >= bd.window {
+			delete(bd.reported, ip)
+		}
+	}
+}
+
+// prune 윈도우 밖의 실패 제거 (attempts는 오래된 순으로 정렬되어 있음)
+func (bd *BruteForceDetector) prune(attempts []bruteForceAttempt, now time.Time) []bruteForceAttempt {
+	cutoff := now.Add(-bd.window)
+	start := 0
+	for start < len(attempts) && attempts[start].at.Before(cutoff) {
+		start++
+	}
+	return attempts[start:]
+}
@@ -0,0 +1,485 @@
+/*
+Notification Canary Module
+==========================
+
+알림 채널 카나리 (-canary)
+
+알림 채널의 자격 증명(SMTP 비밀번호, Slack 웹훅, Telegram 봇 토큰 등)이 만료되거나 바뀌어도
+실제 장애가 나기 전까지는 알 수 없습니다. 카나리는 하루 한 번 모든 채널로 info 수준의 점검 메시지를
+보내고 실제로 전달되었는지 확인해, 실패하면 나머지 채널로 CRITICAL 알림을 보냅니다.
+
+주요 기능:
+  - 스케줄: canary.schedule 또는 -canary-schedule (보고서 스케줄/cron 형식, 기본 "09:00 daily")
+  - 모든 채널로 바로 전송: 라우팅, 중복 제한, 점검/조용한 시간, 알림 끄기를 거치지 않음 (고가용성 대기 인스턴스는 보내지 않음)
+  - 확인 방법: 채널 API 의 성공 응답 (sent), 웹훅 응답 본문의 점검 ID (echo, canary.webhook_echo),
+    받은 편지함에서 점검 ID 가 들어간 제목 검색 (mailbox, canary.imap 설정 시 IMAP over TLS, 기본 10분 대기)
+  - 실패 시: 채널별 오류 로그와 함께 "알림 채널 점검 실패" CRITICAL 알림, 다음 점검에서 모두 성공하면 복구 알림
+  - 마지막 결과: 관리 API GET /status 의 canary
+  - IMAP 비밀번호: canary.imap.password 또는 SYSLOG_CANARY_IMAP_PASSWORD / 키체인 canary-imap-password (없으면 SMTP 계정)
+*/
+package main
+
+import (
+	"bufio"        // IMAP 응답 읽기
+	"context"      // 종료 신호
+	"crypto/rand"  // 점검 ID
+	"crypto/tls"   // IMAP TLS 연결
+	"encoding/hex" // 점검 ID 문자열
+	"errors"       // IMAP 에러
+	"fmt"          // 형식화된 I/O
+	"io"           // IMAP 연결 쓰기
+	"net"          // IMAP 연결
+	"sort"         // 채널 이름 정렬
+	"strconv"      // IMAP 포트
+	"strings"      // 문자열 처리
+	"sync"         // 동시성 제어
+	"time"         // 스케줄, 대기 시간
+)
+
+// 알림 카나리 기본값
+const (
+	DefaultCanarySchedule  = "09:00 daily"    // 기본 점검 스케줄
+	DefaultCanaryEmailWait = 10               // 이메일 도착 확인 시간 (분)
+	MaxCanaryEmailWait     = 60               // 이메일 도착 확인 최대 시간 (분)
+	DefaultIMAPPort        = 993              // IMAP over TLS
+	DefaultIMAPMailbox     = "INBOX"          // 검색할 편지함
+	canaryMailboxPoll      = 30 * time.Second // 받은 편지함 확인 간격
+	canaryIMAPTimeout      = 30 * time.Second // IMAP 연결 하나의 제한 시간
+)
+
+// 카나리 채널 확인 방법
+const (
+	CanaryCheckSent    = "sent"    // 채널 API 가 성공 응답
+	CanaryCheckEcho    = "echo"    // 웹훅 응답 본문에 점검 ID
+	CanaryCheckMailbox = "mailbox" // 받은 편지함에서 점검 메일 확인
+)
+
+// CanaryConfig 알림 카나리 설정 (설정 파일 canary)
+type CanaryConfig struct {
+	Schedule    string           `json:"schedule"`     // 점검 스케줄 (보고서 스케줄 또는 cron 형식, 비우면 "09:00 daily")
+	WebhookEcho bool             `json:"webhook_echo"` // 웹훅 응답 본문에 점검 ID 가 있어야 성공 (받은 본문을 돌려주는 수신기)
+	EmailWait   int              `json:"email_wait"`   // 이메일 도착 확인 시간 (분, 0 이면 10)
+	IMAP        CanaryIMAPConfig `json:"imap"`         // 이메일 도착 확인용 IMAP 계정 (server 가 비어 있으면 SMTP 전송 성공만 확인)
+}
+
+// CanaryIMAPConfig 이메일 도착 확인용 IMAP 계정
+type CanaryIMAPConfig struct {
+	Server   string `json:"server"`   // IMAP 서버 (예: imap.gmail.com)
+	Port     int    `json:"port"`     // 포트 (0 이면 993, TLS)
+	Username string `json:"username"` // 사용자 (비우면 SMTP 사용자)
+	Password string `json:"password"` // 비밀번호 (비우면 SYSLOG_CANARY_IMAP_PASSWORD / 키체인 canary-imap-password / SMTP 비밀번호)
+	Mailbox  string `json:"mailbox"`  // 검색할 편지함 (비우면 INBOX)
+}
+
+// CanaryChannelResult 채널 하나의 점검 결과
+type CanaryChannelResult struct {
+	Channel  string `json:"channel"`
+	OK       bool   `json:"ok"`
+	Check    string `json:"check"` // sent, echo, mailbox
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// CanaryResult 점검 한 번의 결과
+type CanaryResult struct {
+	ID       string                `json:"id"`
+	Time     time.Time             `json:"time"`
+	OK       bool                  `json:"ok"`
+	Channels []CanaryChannelResult `json:"channels"`
+}
+
+// Failed 실패한 채널 이름
+func (r *CanaryResult) Failed() []string {
+	var failed []string
+	for _, channel := range r.Channels {
+		if !channel.OK {
+			failed = append(failed, channel.Channel)
+		}
+	}
+	return failed
+}
+
+// Canary 알림 채널 카나리
+type Canary struct {
+	config     CanaryConfig
+	schedule   *ReportSchedule
+	dispatcher *AlertDispatcher
+	logger     Logger
+	dial       func(config CanaryIMAPConfig) (net.Conn, error) // IMAP 연결 (기본 TLS)
+	last       *CanaryResult
+	reset      chan struct{} // 스케줄 변경 시 다음 점검 시각 다시 계산
+	mutex      sync.Mutex
+}
+
+// NewCanary 알림 카나리 생성 (SetConfig 로 스케줄을 정한 뒤 Start)
+func NewCanary(dispatcher *AlertDispatcher, logger Logger) *Canary {
+	schedule, _ := ParseReportSchedule(DefaultCanarySchedule)
+	return &Canary{
+		config:     CanaryConfig{EmailWait: DefaultCanaryEmailWait},
+		schedule:   schedule,
+		dispatcher: dispatcher,
+		logger:     logger,
+		dial:       dialIMAP,
+		reset:      make(chan struct{}, 1),
+	}
+}
+
+// SetConfig 스케줄/확인 방법 적용 (IMAP 사용자/비밀번호는 호출자가 채움, 오류면 기존 설정 유지)
+func (c *Canary) SetConfig(config CanaryConfig) error {
+	spec := strings.TrimSpace(config.Schedule)
+	if spec == "" {
+		spec = DefaultCanarySchedule
+	}
+	schedule, err := ParseReportSchedule(spec)
+	if err != nil {
+		return err
+	}
+	if config.EmailWait < 0 || config.EmailWait > MaxCanaryEmailWait {
+		return fmt.Errorf("canary email_wait must be between 1 and %d minutes", MaxCanaryEmailWait)
+	}
+	if config.EmailWait == 0 {
+		config.EmailWait = DefaultCanaryEmailWait
+	}
+	if config.IMAP.Server != "" {
+		if config.IMAP.Port == 0 {
+			config.IMAP.Port = DefaultIMAPPort
+		}
+		if config.IMAP.Mailbox == "" {
+			config.IMAP.Mailbox = DefaultIMAPMailbox
+		}
+		if config.IMAP.Username == "" || config.IMAP.Password == "" {
+			return fmt.Errorf("canary imap needs a username and password (canary.imap or the SMTP account)")
+		}
+	}
+
+	c.mutex.Lock()
+	c.config = config
+	c.schedule = schedule
+	c.mutex.Unlock()
+	select {
+	case c.reset <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Schedule 점검 스케줄
+func (c *Canary) Schedule() *ReportSchedule {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.schedule
+}
+
+// Last 마지막 점검 결과 (아직 점검하지 않았으면 nil)
+func (c *Canary) Last() *CanaryResult {
+	if c == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.last
+}
+
+// Start 스케줄마다 점검 (ctx 가 끝나면 중단)
+func (c *Canary) Start(ctx context.Context) {
+	go c.run(ctx)
+}
+
+// run 다음 점검 시각까지 기다렸다가 점검 (스케줄이 바뀌면 다시 계산)
+func (c *Canary) run(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(c.Schedule().Next(time.Now())))
+		select {
+		case <-timer.C:
+			c.Run(ctx)
+		case <-c.reset:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+		timer.Stop()
+	}
+}
+
+// Run 모든 채널로 점검 메시지를 보내고 도착을 확인한 뒤 실패하면 알림 (결과 반환, 보내지 않았으면 nil)
+func (c *Canary) Run(ctx context.Context) *CanaryResult {
+	c.mutex.Lock()
+	config := c.config
+	c.mutex.Unlock()
+
+	id := newCanaryID()
+	alert := Alert{
+		Type:     AlertTypeCanary,
+		Severity: AlertSeverityInfo,
+		Title:    fmt.Sprintf("[%s CANARY] Notification channel check %s", AppName, id),
+		Headline: "🐤 알림 채널 점검",
+		Sections: []AlertSection{{
+			Text: "알림 채널이 동작하는지 확인하는 정기 점검 메시지입니다. 조치할 필요가 없습니다.",
+			Fields: []AlertField{
+				{Label: "점검 ID", Value: id, Short: true},
+				{Label: "시간", Value: time.Now().Format("2006-01-02 15:04:05"), Short: true},
+			},
+			Summary: true,
+		}},
+		Fields:    map[string]string{"canary_id": id},
+		ID:        id,
+		Timestamp: time.Now(),
+	}
+
+	checks := make(map[string]string)
+	durations := make(map[string]time.Duration)
+	var mutex sync.Mutex
+	errs, sent := c.dispatcher.Probe(alert, func(name string, sink AlertSink, alert Alert) error {
+		started := time.Now()
+		check, err := c.probe(ctx, config, name, sink, alert)
+		mutex.Lock()
+		checks[name], durations[name] = check, time.Since(started)
+		mutex.Unlock()
+		return err
+	})
+	if !sent {
+		c.logger.Infof("🐤 Notification canary skipped (dry run or standby instance)")
+		return nil
+	}
+
+	result := &CanaryResult{ID: id, Time: alert.Timestamp, OK: true}
+	names := make([]string, 0, len(errs))
+	for name := range errs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		channel := CanaryChannelResult{Channel: name, OK: errs[name] == nil, Check: checks[name], Duration: durations[name].Round(time.Millisecond).String()}
+		if err := errs[name]; err != nil {
+			channel.Error = err.Error()
+			result.OK = false
+			c.logger.Errorf("❌ Notification canary failed for %s (%s check): %v", name, channel.Check, err)
+		}
+		result.Channels = append(result.Channels, channel)
+	}
+
+	c.mutex.Lock()
+	previous := c.last
+	c.last = result
+	c.mutex.Unlock()
+
+	switch {
+	case len(result.Channels) == 0:
+		c.logger.Infof("🐤 Notification canary %s: no alert channels are configured", id)
+	case !result.OK:
+		c.dispatcher.Dispatch(canaryFailureAlert(result))
+	case previous != nil && !previous.OK:
+		c.logger.Infof("🐤 Notification canary %s: all %d channel(s) recovered", id, len(result.Channels))
+		c.dispatcher.Dispatch(canaryRecoveryAlert(result, previous))
+	default:
+		c.logger.Infof("🐤 Notification canary %s: %d channel(s) OK", id, len(result.Channels))
+	}
+	return result
+}
+
+// probe 채널 하나로 점검 메시지를 보내고 확인 (확인 방법 반환)
+func (c *Canary) probe(ctx context.Context, config CanaryConfig, name string, sink AlertSink, alert Alert) (string, error) {
+	if echo, ok := sink.(interface{ SendEcho(Alert) error }); ok && config.WebhookEcho {
+		return CanaryCheckEcho, echo.SendEcho(alert)
+	}
+	if err := sink.Send(alert); err != nil {
+		return CanaryCheckSent, err
+	}
+	if _, ok := sink.(*EmailService); !ok || config.IMAP.Server == "" {
+		return CanaryCheckSent, nil
+	}
+	return CanaryCheckMailbox, c.waitMailbox(ctx, config, alert.ID)
+}
+
+// waitMailbox 받은 편지함에 점검 ID 가 들어간 메일이 올 때까지 확인 (email_wait 분 동안)
+func (c *Canary) waitMailbox(ctx context.Context, config CanaryConfig, id string) error {
+	deadline := time.Now().Add(time.Duration(config.EmailWait) * time.Minute)
+	var lastErr error
+	for {
+		if !sleepContext(ctx, canaryMailboxPoll) {
+			return ctx.Err()
+		}
+		found, err := c.searchMailbox(config.IMAP, id)
+		if err == nil && found > 0 {
+			return nil
+		}
+		if err != nil {
+			lastErr = err
+		}
+		if time.Now().After(deadline) {
+			if lastErr != nil {
+				return fmt.Errorf("not found in %s after %d minutes (last IMAP error: %v)", config.IMAP.Mailbox, config.EmailWait, lastErr)
+			}
+			return fmt.Errorf("not found in %s after %d minutes", config.IMAP.Mailbox, config.EmailWait)
+		}
+	}
+}
+
+// searchMailbox IMAP 에 연결해 제목에 text 가 들어간 메일 수 조회
+func (c *Canary) searchMailbox(config CanaryIMAPConfig, text string) (int, error) {
+	conn, err := c.dial(config)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(canaryIMAPTimeout))
+	return imapSearchSubject(conn, config, text)
+}
+
+// canaryFailureAlert 점검 실패 알림
+func canaryFailureAlert(result *CanaryResult) Alert {
+	failed := result.Failed()
+	fields := make([]AlertField, 0, len(failed))
+	for _, channel := range result.Channels {
+		if !channel.OK {
+			fields = append(fields, AlertField{Label: channel.Channel, Value: fmt.Sprintf("%s 확인 실패: %s", channel.Check, channel.Error)})
+		}
+	}
+	return Alert{
+		Type:     AlertTypeCanary,
+		Severity: AlertSeverityCritical,
+		Title:    fmt.Sprintf("[%s CANARY FAILED] %d notification channel(s) failed: %s", AppName, len(failed), strings.Join(failed, ", ")),
+		Headline: "🚨 알림 채널 점검 실패",
+		Sections: []AlertSection{{
+			Text:    fmt.Sprintf("점검 메시지 %s 가 아래 채널로 전달되지 않았습니다. 실제 장애 알림도 받지 못할 수 있으니 자격 증명과 웹훅 주소를 확인하세요.", result.ID),
+			Fields:  fields,
+			Summary: true,
+		}},
+		Fields: map[string]string{"canary_id": result.ID, "failed": strings.Join(failed, ",")},
+		Thread: alertThreadKey(AlertTypeCanary),
+	}
+}
+
+// canaryRecoveryAlert 이전 점검에서 실패한 채널이 모두 복구되었음을 알리는 알림
+func canaryRecoveryAlert(result, previous *CanaryResult) Alert {
+	recovered := previous.Failed()
+	return Alert{
+		Type:     AlertTypeCanary,
+		Severity: AlertSeverityInfo,
+		Title:    fmt.Sprintf("[%s CANARY RECOVERED] Notification channels recovered: %s", AppName, strings.Join(recovered, ", ")),
+		Headline: "✅ 알림 채널 점검 복구",
+		Sections: []AlertSection{{
+			Text: fmt.Sprintf("점검 메시지 %s 가 모든 채널로 전달되었습니다.", result.ID),
+			Fields: []AlertField{
+				{Label: "복구된 채널", Value: strings.Join(recovered, ", "), Short: true},
+				{Label: "실패 점검", Value: previous.Time.Format("2006-01-02 15:04"), Short: true},
+			},
+			Summary: true,
+		}},
+		Fields: map[string]string{"canary_id": result.ID, "recovered": strings.Join(recovered, ",")},
+		Thread: alertThreadKey(AlertTypeCanary),
+	}
+}
+
+// newCanaryID 점검 메시지 ID (메일 제목 검색, 웹훅 응답 확인용)
+func newCanaryID() string {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("canary-%x", time.Now().UnixNano())
+	}
+	return "canary-" + hex.EncodeToString(buf)
+}
+
+// dialIMAP IMAP 서버에 TLS 로 연결
+func dialIMAP(config CanaryIMAPConfig) (net.Conn, error) {
+	address := net.JoinHostPort(config.Server, strconv.Itoa(config.Port))
+	dialer := &net.Dialer{Timeout: canaryIMAPTimeout}
+	return tls.DialWithDialer(dialer, "tcp", address, &tls.Config{ServerName: config.Server, MinVersion: tls.VersionTLS12})
+}
+
+// imapSearchSubject 로그인 후 편지함을 읽기 전용으로 열고 제목에 text 가 들어간 메일 수 조회 (LOGIN, EXAMINE, SEARCH)
+func imapSearchSubject(conn io.ReadWriter, config CanaryIMAPConfig, text string) (int, error) {
+	session := &imapSession{reader: bufio.NewReader(conn), writer: conn}
+	greeting, err := session.readLine()
+	if err != nil {
+		return 0, err
+	}
+	if !strings.HasPrefix(greeting, "* OK") {
+		return 0, fmt.Errorf("unexpected IMAP greeting: %s", truncateRunes(greeting, 80))
+	}
+	defer session.command("LOGOUT")
+
+	username, err := imapQuote(config.Username)
+	if err != nil {
+		return 0, fmt.Errorf("IMAP username: %v", err)
+	}
+	password, err := imapQuote(config.Password)
+	if err != nil {
+		return 0, fmt.Errorf("IMAP password: %v", err)
+	}
+	if _, err := session.command("LOGIN " + username + " " + password); err != nil {
+		return 0, fmt.Errorf("IMAP login failed: %v", err)
+	}
+	mailbox, err := imapQuote(config.Mailbox)
+	if err != nil {
+		return 0, fmt.Errorf("IMAP mailbox: %v", err)
+	}
+	if _, err := session.command("EXAMINE " + mailbox); err != nil {
+		return 0, fmt.Errorf("IMAP mailbox %s: %v", config.Mailbox, err)
+	}
+	query, err := imapQuote(text)
+	if err != nil {
+		return 0, err
+	}
+	lines, err := session.command("SEARCH SUBJECT " + query)
+	if err != nil {
+		return 0, fmt.Errorf("IMAP search failed: %v", err)
+	}
+	found := 0
+	for _, line := range lines {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "*" && strings.EqualFold(fields[1], "SEARCH") {
+			found += len(fields) - 2
+		}
+	}
+	return found, nil
+}
+
+// imapSession 태그를 붙인 IMAP 명령 주고받기
+type imapSession struct {
+	reader *bufio.Reader
+	writer io.Writer
+	tag    int
+}
+
+// command 명령을 보내고 태그가 붙은 응답까지 읽어 그 전의 응답 줄 반환 (OK 가 아니면 에러)
+func (s *imapSession) command(command string) ([]string, error) {
+	s.tag++
+	tag := fmt.Sprintf("a%d", s.tag)
+	if _, err := fmt.Fprintf(s.writer, "%s %s\r\n", tag, command); err != nil {
+		return nil, err
+	}
+	var lines []string
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if rest, ok := strings.CutPrefix(line, tag+" "); ok {
+			if !strings.HasPrefix(strings.ToUpper(rest), "OK") {
+				return nil, errors.New(truncateRunes(rest, 200))
+			}
+			return lines, nil
+		}
+		lines = append(lines, line)
+	}
+}
+
+// readLine 응답 한 줄 (CRLF 제외)
+func (s *imapSession) readLine() (string, error) {
+	line, err := s.reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("IMAP connection: %v", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// imapQuote IMAP 따옴표 문자열 (7비트 ASCII 만, 줄바꿈 불가)
+func imapQuote(value string) (string, error) {
+	for _, r := range value {
+		if r == '\r' || r == '\n' || r > 126 {
+			return "", errors.New("only printable ASCII characters are supported")
+		}
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`, nil
+}
@@ -0,0 +1,555 @@
+/*
+CI/CD Detection Module
+======================
+
+Jenkins / GitLab Runner / GitHub Actions 러너 로그의 반복 빌드 실패, 예상 밖 러너 등록, 빌드 출력의 비밀 노출 감지 (-cicd-watch)
+
+주요 기능:
+  - 반복 작업 실패: 같은 작업(프로젝트)이 cicd.failure_window 분 안에 cicd.failure_threshold 번 실패하면 warning
+    성공하면 실패 횟수 초기화, 알린 뒤에는 창이 지나야 다시 알림
+    Jenkins "Run#execute: <작업> #N main build action completed: FAILURE" (빌드 콘솔 로그의 "Finished: FAILURE" 는 경로의 jobs/<작업>/builds)
+    GitLab Runner "Job failed ... project=N job=N runner=..." / "Job succeeded"
+    GitHub Actions 러너 "Job <작업> completed with result: Failed" (저장소는 actions.runner.<저장소>.<러너> 유닛 이름)
+  - 예상 밖 러너 등록: Jenkins 에이전트 연결 ("Accepted ... connection #N from /IP:포트"),
+    GitLab 러너 등록 API (POST /api/v4/runners, /api/v4/user/runners - api_json.log 또는 nginx 접근 로그) 의
+    출처가 cicd.runner_networks 밖이면 critical (비어 있으면 사설/루프백 주소만 허용)
+  - 빌드 출력 비밀 노출: cicd.sources (서비스 이름, 입력 경로, "*" 접미사는 접두사 일치) 의 줄에서
+    표본 추출 가림 규칙 (비밀번호/토큰 키-값, Authorization 헤더, URL 자격 증명, 클라우드/SaaS 토큰, 개인 키) 에 걸리는 값이 있으면 critical
+    CI 가 이미 가린 값 (***, [MASKED], $변수) 은 제외, 같은 작업/종류는 cicd.secret_interval 분 간격으로 제한
+  - 담당 팀: cicd.teams 의 프로젝트 glob 으로 team 필드 지정 (routes 의 labels 로 팀 채널에 전송)
+
+알림의 원본 로그는 비밀을 가린 뒤 보냄
+*/
+package main
+
+import (
+	"fmt"           // 형식화된 I/O
+	"net"           // 러너 출처 주소 확인
+	"path/filepath" // 팀 프로젝트 glob 매칭
+	"regexp"        // 로그 형식 패턴
+	"strings"       // 문자열 처리
+	"time"          // 실패 창 / 알림 간격
+)
+
+// CI/CD 이벤트 종류
+const (
+	CICDEventJobFailures      = "job_failures"
+	CICDEventUnexpectedRunner = "unexpected_runner"
+	CICDEventSecretExposed    = "secret_exposed"
+)
+
+// CI 시스템 이름
+const (
+	CICDJenkins       = "jenkins"
+	CICDGitLab        = "gitlab"
+	CICDGitHubActions = "github_actions"
+)
+
+// CI/CD 감지 기본값
+const (
+	DefaultCICDFailureThreshold = 3  // 반복 실패로 볼 실패 횟수
+	DefaultCICDFailureWindow    = 60 // 실패 횟수를 세는 창 (분)
+	DefaultCICDSecretInterval   = 10 // 같은 작업/비밀 종류의 노출 알림 간격 (분)
+	maxCICDJobHistory           = 1000
+)
+
+// DefaultCICDSources 비밀 노출을 확인할 빌드 출력 (서비스 이름/입력 경로, cicd.sources 로 변경)
+var DefaultCICDSources = []string{"jenkins*", "gitlab-runner*", "actions.runner*", "runner.worker", "runner.listener", "/var/lib/jenkins/*", "/var/log/jenkins/*", "/var/log/gitlab-runner/*"}
+
+// CICDWatchKeywords 키워드 필터를 사용할 때 CI/CD 감지를 위해 추가하는 키워드 (비밀 노출은 키워드를 통과한 줄만 확인)
+var CICDWatchKeywords = []string{"build action completed", "Finished:", "Job failed", "Job succeeded", "completed with result", "connection #", "/api/v4/runners", "/api/v4/user/runners"}
+
+var (
+	cicdJenkinsResultRegex  = regexp.MustCompile(`Run#execute: (.+?) #(\d+) main build action completed: ([A-Z_]+)`)
+	cicdJenkinsFinishRegex  = regexp.MustCompile(`^(?:\S+\s+)?Finished: ([A-Z_]+)\s*$`)
+	cicdJenkinsJobPathRegex = regexp.MustCompile(`/jobs/(.+?)/builds/(\d+)/`)
+	cicdJenkinsAgentRegex   = regexp.MustCompile(`Accepted (.+?) connection #\d+ from /?\[?([0-9A-Fa-f.:]+?)\]?:\d+\b`)
+
+	cicdGitLabJobRegex     = regexp.MustCompile(`\bJob (failed|succeeded)\b(?:: ([^"]*?)(?:"|\s{2,}|\s+[\w-]+=|$))?`)
+	cicdGitLabFieldRegex   = regexp.MustCompile(`\b(job|project|runner|name)=("[^"]*"|\S+)`)
+	cicdGitLabRegisterPath = regexp.MustCompile(`/api/v4/(?:user/)?runners(?:[?"\s]|$)`)
+	cicdGitLabJSONMethod   = regexp.MustCompile(`"method"\s*:\s*"POST"`)
+	cicdGitLabJSONPath     = regexp.MustCompile(`"path"\s*:\s*"/api/v4/(?:user/)?runners"`)
+	cicdGitLabJSONIP       = regexp.MustCompile(`"remote_ip"\s*:\s*"([^"]+)"`)
+	cicdGitLabJSONStatus   = regexp.MustCompile(`"status"\s*:\s*(\d{3})`)
+	cicdAccessRegisterLine = regexp.MustCompile(`^(\S+) .*?"POST /api/v4/(?:user/)?runners(?:\?\S*)? HTTP/[\d.]+" (\d{3})`)
+
+	cicdGitHubResultRegex = regexp.MustCompile(`\bJob (.+?) completed with result: (\w+)`)
+
+	cicdMaskedValueRegex = regexp.MustCompile(`^(?:\*+|\[MASKED\]|\[REDACTED\]|x{3,}|\$\{?[A-Za-z_][A-Za-z0-9_.]*\}?|%[A-Za-z_]+%)$`)
+)
+
+// CICDConfig CI/CD 감지 설정 (설정 파일 cicd, -cicd-watch 로 켜기)
+type CICDConfig struct {
+	FailureThreshold int        `json:"failure_threshold"` // 반복 실패로 볼 실패 횟수 (기본 3)
+	FailureWindow    int        `json:"failure_window"`    // 실패 횟수를 세는 창 (분, 기본 60)
+	RunnerNetworks   []string   `json:"runner_networks"`   // 러너 등록/에이전트 연결을 허용할 CIDR ("이름=CIDR", 비어 있으면 사설/루프백 주소)
+	Sources          []string   `json:"sources"`           // 비밀 노출을 확인할 빌드 출력 서비스 이름/입력 경로 (비어 있으면 기본 목록)
+	SecretInterval   int        `json:"secret_interval"`   // 같은 작업/비밀 종류의 노출 알림 간격 (분, 기본 10)
+	Teams            []CICDTeam `json:"teams"`             // 프로젝트별 담당 팀 (처음 일치한 팀을 team 필드로)
+	DefaultTeam      string     `json:"default_team"`      // 일치하는 팀이 없을 때의 team 필드 (비어 있으면 필드 없음)
+}
+
+// CICDTeam 담당 팀과 프로젝트 glob
+type CICDTeam struct {
+	Team     string   `json:"team"`     // 팀 이름 (알림 team 필드)
+	Projects []string `json:"projects"` // 작업/프로젝트 glob (Jenkins 작업 경로, GitLab 프로젝트 ID, GitHub 저장소, 대소문자 무시)
+}
+
+// CICDEvent CI/CD 감지 결과
+type CICDEvent struct {
+	Kind        string // job_failures, unexpected_runner, secret_exposed
+	System      string // jenkins, gitlab, github_actions (비밀 노출은 알 수 없으면 빈 값)
+	Project     string // Jenkins 작업, GitLab 프로젝트 ID, GitHub 저장소 (알 수 없으면 빈 값)
+	Job         string // 빌드 번호, GitLab 작업 ID, GitHub 작업 이름
+	Result      string // 작업 결과 (FAILURE, failed, Failed ...), 에이전트 연결 방식 (JNLP4-connect ...)
+	Runner      string // 러너/에이전트 (알 수 있는 경우)
+	IP          string // 러너 등록/에이전트 연결 출처
+	Status      string // 러너 등록 API 응답 코드
+	Failures    int    // 창 안의 실패 횟수
+	SecretKinds []string
+	Team        string
+	Severity    string // warning, critical
+	ShouldAlert bool   // 반복 기준/알림 간격 통과 여부
+}
+
+// cicdJobHistory 작업별 실패 기록
+type cicdJobHistory struct {
+	failures  []time.Time
+	lastAlert time.Time
+}
+
+// cicdTeam 컴파일된 담당 팀
+type cicdTeam struct {
+	name     string
+	projects []string // 소문자 glob
+}
+
+// CICDDetector CI/CD 로그 감지기 (처리 고루틴에서만 사용)
+type CICDDetector struct {
+	threshold      int
+	window         time.Duration
+	secretInterval time.Duration
+	runnerNetworks *TrustedNetworks
+	sources        []string
+	teams          []cicdTeam
+	defaultTeam    string
+	jobs           map[string]*cicdJobHistory
+	secrets        map[string]time.Time // 작업|비밀 종류 → 마지막 알림
+}
+
+// NewCICDDetector 설정으로 감지기 생성 (러너 네트워크가 잘못되면 오류)
+func NewCICDDetector(config CICDConfig) (*CICDDetector, error) {
+	detector := &CICDDetector{jobs: make(map[string]*cicdJobHistory), secrets: make(map[string]time.Time)}
+	if err := detector.SetConfig(config); err != nil {
+		return nil, err
+	}
+	return detector, nil
+}
+
+// newConfiguredCICDDetector 설정 파일의 cicd 섹션으로 감지기 생성 (설정 서비스가 없으면 기본값)
+func newConfiguredCICDDetector() (*CICDDetector, error) {
+	if configService == nil {
+		return NewCICDDetector(CICDConfig{})
+	}
+	return NewCICDDetector(configService.GetConfig().CICD)
+}
+
+// SetConfig 설정 적용 (0 이하 값은 기본값, 오류면 기존 설정 유지)
+func (d *CICDDetector) SetConfig(config CICDConfig) error {
+	runnerNetworks, err := ParseTrustedNetworks(config.RunnerNetworks)
+	if err != nil {
+		return fmt.Errorf("cicd.runner_networks: %v", err)
+	}
+	var teams []cicdTeam
+	for i, team := range config.Teams {
+		name := strings.TrimSpace(team.Team)
+		if name == "" {
+			return fmt.Errorf("cicd.teams #%d: team is empty", i+1)
+		}
+		compiled := cicdTeam{name: name}
+		for _, project := range team.Projects {
+			if project = strings.ToLower(strings.TrimSpace(project)); project != "" {
+				if _, err := filepath.Match(project, ""); err != nil {
+					return fmt.Errorf("cicd.teams %s: invalid project pattern %q: %v", name, project, err)
+				}
+				compiled.projects = append(compiled.projects, project)
+			}
+		}
+		teams = append(teams, compiled)
+	}
+
+	d.threshold, d.window, d.secretInterval = config.FailureThreshold, time.Duration(config.FailureWindow)*time.Minute, time.Duration(config.SecretInterval)*time.Minute
+	if d.threshold <= 0 {
+		d.threshold = DefaultCICDFailureThreshold
+	}
+	if d.window <= 0 {
+		d.window = DefaultCICDFailureWindow * time.Minute
+	}
+	if d.secretInterval <= 0 {
+		d.secretInterval = DefaultCICDSecretInterval * time.Minute
+	}
+	if runnerNetworks.Len() > 0 {
+		d.runnerNetworks = runnerNetworks
+	} else {
+		d.runnerNetworks = nil
+	}
+	d.sources = nil
+	sources := config.Sources
+	if len(sources) == 0 {
+		sources = DefaultCICDSources
+	}
+	for _, source := range sources {
+		if source = strings.ToLower(strings.TrimSpace(source)); source != "" {
+			d.sources = append(d.sources, source)
+		}
+	}
+	d.teams, d.defaultTeam = teams, strings.TrimSpace(config.DefaultTeam)
+	return nil
+}
+
+// Detect 로그 한 줄에서 CI/CD 이벤트 감지 (해당 없으면 nil)
+// services 는 extractionServices 의 서비스 이름 후보, source 는 입력 이름 (파일 경로, journald:유닛)
+func (d *CICDDetector) Detect(line, source string, services []string) *CICDEvent {
+	now := time.Now()
+	if event := d.detectJobResult(line, source, services, now); event != nil {
+		return event
+	}
+	if event := d.detectRunnerRegistration(line); event != nil {
+		return event
+	}
+	candidates := append(append([]string{}, services...), strings.ToLower(source))
+	if matchesServices(d.sources, candidates) {
+		return d.detectSecret(line, source, services, now)
+	}
+	return nil
+}
+
+// detectJobResult 작업 결과 줄 파싱 후 실패 횟수 기록 (작업 결과가 아니면 nil)
+func (d *CICDDetector) detectJobResult(line, source string, services []string, now time.Time) *CICDEvent {
+	event := &CICDEvent{Kind: CICDEventJobFailures, Severity: AlertSeverityWarning}
+	failed := false
+	if matches := cicdJenkinsResultRegex.FindStringSubmatch(line); matches != nil {
+		event.System, event.Project, event.Job, event.Result = CICDJenkins, matches[1], "#"+matches[2], matches[3]
+		failed = matches[3] == "FAILURE"
+	} else if matches := cicdJenkinsFinishRegex.FindStringSubmatch(line); matches != nil {
+		path := cicdJenkinsJobPathRegex.FindStringSubmatch(source)
+		if path == nil {
+			return nil
+		}
+		event.System, event.Project, event.Job, event.Result = CICDJenkins, strings.ReplaceAll(path[1], "/jobs/", "/"), "#"+path[2], matches[1]
+		failed = matches[1] == "FAILURE"
+	} else if matches := cicdGitLabJobRegex.FindStringSubmatch(line); matches != nil && strings.Contains(line, "project=") {
+		event.System, event.Result = CICDGitLab, matches[1]
+		if matches[2] != "" {
+			event.Result += ": " + strings.TrimSpace(matches[2])
+		}
+		for _, field := range cicdGitLabFieldRegex.FindAllStringSubmatch(line, -1) {
+			value := strings.Trim(field[2], `"`)
+			switch field[1] {
+			case "project":
+				event.Project = value
+			case "job":
+				event.Job = value
+			case "runner", "name":
+				if event.Runner == "" {
+					event.Runner = value
+				}
+			}
+		}
+		failed = matches[1] == "failed"
+	} else if matches := cicdGitHubResultRegex.FindStringSubmatch(line); matches != nil {
+		event.System, event.Job, event.Result = CICDGitHubActions, matches[1], matches[2]
+		event.Project, event.Runner = githubRunnerRepository(services)
+		failed = strings.EqualFold(matches[2], "Failed")
+	} else {
+		return nil
+	}
+	event.Team = d.team(event.Project, event.Job)
+
+	key := event.System + "|" + event.Project
+	if event.System == CICDGitHubActions {
+		key += "|" + event.Job // 저장소 안의 작업 이름별로
+	}
+	history, ok := d.jobs[key]
+	if !failed {
+		if ok {
+			history.failures = history.failures[:0]
+		}
+		return event
+	}
+	if !ok {
+		d.pruneJobs(now)
+		history = &cicdJobHistory{}
+		d.jobs[key] = history
+	}
+	cutoff := now.Add(-d.window)
+	kept := history.failures[:0]
+	for _, failure := range history.failures {
+		if failure.After(cutoff) {
+			kept = append(kept, failure)
+		}
+	}
+	history.failures = append(kept, now)
+	event.Failures = len(history.failures)
+	if event.Failures >= d.threshold && now.Sub(history.lastAlert) >= d.window {
+		event.ShouldAlert = true
+		history.lastAlert = now
+	}
+	return event
+}
+
+// detectRunnerRegistration Jenkins 에이전트 연결 / GitLab 러너 등록 API 요청의 출처 확인
+func (d *CICDDetector) detectRunnerRegistration(line string) *CICDEvent {
+	event := &CICDEvent{Kind: CICDEventUnexpectedRunner, Severity: AlertSeverityCritical}
+	if matches := cicdJenkinsAgentRegex.FindStringSubmatch(line); matches != nil {
+		event.System, event.Result, event.IP = CICDJenkins, matches[1], matches[2]
+	} else if cicdGitLabJSONPath.MatchString(line) && cicdGitLabJSONMethod.MatchString(line) {
+		matches := cicdGitLabJSONIP.FindStringSubmatch(line)
+		if matches == nil {
+			return nil
+		}
+		event.System, event.IP = CICDGitLab, matches[1]
+		if status := cicdGitLabJSONStatus.FindStringSubmatch(line); status != nil {
+			event.Status = status[1]
+		}
+	} else if cicdGitLabRegisterPath.MatchString(line) {
+		matches := cicdAccessRegisterLine.FindStringSubmatch(line)
+		if matches == nil {
+			return nil
+		}
+		event.System, event.IP, event.Status = CICDGitLab, matches[1], matches[2]
+	} else {
+		return nil
+	}
+
+	ip := net.ParseIP(event.IP)
+	if ip == nil {
+		return nil
+	}
+	if d.runnerNetworks != nil {
+		if _, ok := d.runnerNetworks.Match(event.IP); ok {
+			return event
+		}
+	} else if ip.IsPrivate() || ip.IsLoopback() {
+		return event
+	}
+	event.Team = d.defaultTeam
+	event.ShouldAlert = true
+	return event
+}
+
+// detectSecret 빌드 출력 줄의 가려지지 않은 비밀 확인 (없으면 nil)
+func (d *CICDDetector) detectSecret(line, source string, services []string, now time.Time) *CICDEvent {
+	kinds := cicdSecretKinds(line)
+	if len(kinds) == 0 {
+		return nil
+	}
+	event := &CICDEvent{Kind: CICDEventSecretExposed, SecretKinds: kinds, Severity: AlertSeverityCritical}
+	if path := cicdJenkinsJobPathRegex.FindStringSubmatch(source); path != nil {
+		event.System, event.Project, event.Job = CICDJenkins, strings.ReplaceAll(path[1], "/jobs/", "/"), "#"+path[2]
+	} else if repository, runner := githubRunnerRepository(services); repository != "" {
+		event.System, event.Project, event.Runner = CICDGitHubActions, repository, runner
+	} else if len(services) > 0 {
+		event.Project = services[0]
+	} else {
+		event.Project = source
+	}
+	event.Team = d.team(event.Project, event.Job)
+
+	key := event.Project + "|" + strings.Join(kinds, ",")
+	if last, ok := d.secrets[key]; ok && now.Sub(last) < d.secretInterval {
+		return event
+	}
+	if len(d.secrets) >= maxCICDJobHistory {
+		for key, last := range d.secrets {
+			if now.Sub(last) >= d.secretInterval {
+				delete(d.secrets, key)
+			}
+		}
+	}
+	d.secrets[key] = now
+	event.ShouldAlert = true
+	return event
+}
+
+// team 프로젝트/작업의 담당 팀 (처음 일치한 팀, 없으면 기본 팀)
+func (d *CICDDetector) team(project, job string) string {
+	candidates := []string{strings.ToLower(project), strings.ToLower(job)}
+	for _, team := range d.teams {
+		for _, pattern := range team.projects {
+			for _, candidate := range candidates {
+				if candidate == "" {
+					continue
+				}
+				if ok, _ := filepath.Match(pattern, candidate); ok {
+					return team.name
+				}
+			}
+		}
+	}
+	return d.defaultTeam
+}
+
+// pruneJobs 기록이 많아지면 창이 지난 작업 정리
+func (d *CICDDetector) pruneJobs(now time.Time) {
+	if len(d.jobs) < maxCICDJobHistory {
+		return
+	}
+	for key, history := range d.jobs {
+		if len(history.failures) == 0 || now.Sub(history.failures[len(history.failures)-1]) >= d.window {
+			delete(d.jobs, key)
+		}
+	}
+}
+
+// equalCICDConfig 두 CI/CD 감지 설정이 같은지 확인 (설정 재로드)
+func equalCICDConfig(a, b CICDConfig) bool {
+	if a.FailureThreshold != b.FailureThreshold || a.FailureWindow != b.FailureWindow || a.SecretInterval != b.SecretInterval ||
+		a.DefaultTeam != b.DefaultTeam || len(a.Teams) != len(b.Teams) ||
+		strings.Join(a.RunnerNetworks, "\x00") != strings.Join(b.RunnerNetworks, "\x00") ||
+		strings.Join(a.Sources, "\x00") != strings.Join(b.Sources, "\x00") {
+		return false
+	}
+	for i := range a.Teams {
+		if a.Teams[i].Team != b.Teams[i].Team || strings.Join(a.Teams[i].Projects, "\x00") != strings.Join(b.Teams[i].Projects, "\x00") {
+			return false
+		}
+	}
+	return true
+}
+
+// githubRunnerRepository actions.runner.<저장소>.<러너> 유닛 이름에서 저장소와 러너 이름
+func githubRunnerRepository(services []string) (repository, runner string) {
+	for _, service := range services {
+		if rest := strings.TrimPrefix(service, "actions.runner."); rest != service {
+			if i := strings.LastIndexByte(rest, '.'); i > 0 {
+				return rest[:i], rest[i+1:]
+			}
+			return rest, ""
+		}
+	}
+	return "", ""
+}
+
+// cicdSecretKinds 줄에서 가려지지 않은 비밀 종류 (표본 추출 가림 규칙, CI 가 가린 값 제외)
+func cicdSecretKinds(line string) []string {
+	var kinds []string
+	for _, matches := range secretKeyValueRegex.FindAllStringSubmatch(line, -1) {
+		if !cicdMaskedValueRegex.MatchString(matches[2]) {
+			kinds = append(kinds, "key_value")
+			break
+		}
+	}
+	if secretAuthHeaderRegex.MatchString(line) {
+		kinds = append(kinds, "auth_header")
+	}
+	for _, matches := range secretURLCredRegex.FindAllStringSubmatch(line, -1) {
+		if password := matches[0][len(matches[1]) : len(matches[0])-1]; !cicdMaskedValueRegex.MatchString(password) {
+			kinds = append(kinds, "url_credential")
+			break
+		}
+	}
+	if secretTokenRegex.MatchString(line) {
+		kinds = append(kinds, "token")
+	}
+	if secretPrivateKeyRegex.MatchString(line) {
+		kinds = append(kinds, "private_key")
+	}
+	return kinds
+}
+
+// cicdSystemLabel 알림 표시용 CI 시스템 이름
+func cicdSystemLabel(system string) string {
+	switch system {
+	case CICDJenkins:
+		return "Jenkins"
+	case CICDGitLab:
+		return "GitLab"
+	case CICDGitHubActions:
+		return "GitHub Actions"
+	}
+	return "CI/CD"
+}
+
+// cicdAlert CI/CD 이벤트 알림 (team 필드로 routes 의 labels 라우팅)
+func cicdAlert(event *CICDEvent, parsed map[string]string, line string) Alert {
+	host, system := parsed["host"], cicdSystemLabel(event.System)
+	project := event.Project
+	if project == "" {
+		project = "-"
+	}
+	fields := []AlertField{
+		{Label: "CI", Value: system, Short: true},
+		{Label: "호스트", Value: host, Short: true},
+	}
+	var title, headline string
+	switch event.Kind {
+	case CICDEventJobFailures:
+		title = fmt.Sprintf("[%s CI FAILURES] %s - %s %s failed %d times", AppName, host, system, project, event.Failures)
+		headline = fmt.Sprintf("🔁 %s 작업 %s 반복 실패 (%d회)", system, project, event.Failures)
+		fields = append(fields,
+			AlertField{Label: "작업", Value: project, Short: true},
+			AlertField{Label: "최근 실행", Value: event.Job, Short: true},
+			AlertField{Label: "결과", Value: event.Result, Short: true},
+			AlertField{Label: "창 안의 실패", Value: fmt.Sprintf("%d회", event.Failures), Short: true},
+		)
+	case CICDEventUnexpectedRunner:
+		title = fmt.Sprintf("[%s CI RUNNER] %s - %s runner registration from %s", AppName, host, system, event.IP)
+		headline = fmt.Sprintf("🚨 예상 밖 주소 %s 에서 %s 러너 등록", event.IP, system)
+		fields = append(fields, AlertField{Label: "출처", Value: event.IP, Short: true})
+		if event.Result != "" {
+			fields = append(fields, AlertField{Label: "연결 방식", Value: event.Result, Short: true})
+		}
+		if event.Status != "" {
+			fields = append(fields, AlertField{Label: "응답 코드", Value: event.Status, Short: true})
+		}
+	default:
+		title = fmt.Sprintf("[%s CI SECRET] %s - secret printed in %s build output", AppName, host, project)
+		headline = fmt.Sprintf("🔑 %s 빌드 출력에 비밀 노출 (%s)", project, strings.Join(event.SecretKinds, ", "))
+		fields = append(fields,
+			AlertField{Label: "작업", Value: project, Short: true},
+			AlertField{Label: "비밀 종류", Value: strings.Join(event.SecretKinds, ", "), Short: true},
+		)
+	}
+	if event.Runner != "" {
+		fields = append(fields, AlertField{Label: "러너", Value: event.Runner, Short: true})
+	}
+	if event.Team != "" {
+		fields = append(fields, AlertField{Label: "담당 팀", Value: event.Team, Short: true})
+	}
+
+	alertFields := map[string]string{
+		"kind":    event.Kind,
+		"ci":      event.System,
+		"project": event.Project,
+		"job":     event.Job,
+		"runner":  event.Runner,
+		"ip":      event.IP,
+	}
+	if event.Team != "" {
+		alertFields["team"] = event.Team
+	}
+	if event.Kind == CICDEventSecretExposed {
+		alertFields["secret_kinds"] = strings.Join(event.SecretKinds, ",")
+	}
+	return Alert{
+		Type:     AlertTypeCICD,
+		Severity: event.Severity,
+		Title:    title,
+		Headline: headline,
+		Sections: []AlertSection{
+			{Fields: fields, Summary: true},
+			{
+				Title: "📄 원본 로그",
+				Fields: []AlertField{
+					{Label: "시간", Value: parsed["timestamp"], Short: true},
+					{Label: "원본 로그", Value: redactSecrets(line), Code: true},
+				},
+			},
+		},
+		Host:   host,
+		Thread: alertThreadKey(AlertTypeCICD, host, event.Kind, event.Project),
+		Fields: alertFields,
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Config File Mode Module
+=======================
+
+설정 파일만으로 실행 (syslog-monitor -config=<path>)
+
+서비스 유닛(systemd, LaunchAgent, Windows 서비스)에 옵션을 순서대로 넣어 두지 않아도 되도록,
+입력 소스, 알림 채널, 임계값, 기능 켜기 등 모든 명령줄 옵션을 설정 파일의 "options" 에서 읽습니다.
+서비스는 -config 하나로 설치하고, 옵션을 바꿀 때는 설정 파일만 고친 뒤 서비스를 재시작합니다.
+
+주요 기능:
+- -config=<path>: 설정 파일 경로 (SYSLOG_CONFIG_PATH 보다 우선, 파일이 없거나 읽을 수 없으면 시작 거부)
+- "options": 옵션 이름(앞의 - 제외) → 값, 예: {"file": "/var/log/auth.log", "login-watch": true, "api-port": 8080}
+- 값: 문자열, 숫자, true/false, 문자열 배열 (쉼표 목록 옵션, 예: "email-to": ["a@example.com", "b@example.com"])
+- 우선순위: 명령줄 옵션 → SYSLOG_<OPTION> 환경 변수 (컨테이너 모드) → 설정 파일 options → 기본값
+- 알 수 없는 옵션, 서비스 관리/일회성 명령 옵션(-install-service, -test-email 등)은 시작 시 오류
+- options 변경은 재시작 후 적용 (SIGHUP/설정 감시는 알림 채널/임계값 등 재로드 가능한 설정만 적용)
+*/
+package main
+
+import (
+	"flag"    // 옵션 목록
+	"fmt"     // 형식화된 I/O
+	"reflect" // 설정 비교
+	"sort"    // 적용한 옵션 정렬
+	"strconv" // 숫자/불리언 값 변환
+	"strings" // 문자열 처리
+)
+
+// ConfigFlagName 설정 파일 경로 옵션 이름
+const ConfigFlagName = "config"
+
+// configOptionExcluded 설정 파일 options 에 쓸 수 없는 옵션 (설정 파일 경로, 서비스 관리, 한 번 실행하고 끝나는 명령)
+var configOptionExcluded = map[string]bool{
+	ConfigFlagName:    true,
+	"help":            true,
+	"show-config":     true,
+	"gemini-api-key":  true,
+	"test-email":      true,
+	"test-slack":      true,
+	"test-telegram":   true,
+	"prompt-export":   true,
+	"onnx-featurize":  true,
+	"install-service": true,
+	"remove-service":  true,
+	"start-service":   true,
+	"stop-service":    true,
+	"status-service":  true,
+}
+
+// configPathFromArgs 명령줄 인수에서 -config 값 찾기 (설정을 읽은 뒤에 옵션을 정의하므로 flag.Parse 전에 확인)
+// -config=path, --config=path, -config path 형식, "--" 이후 인수는 무시
+func configPathFromArgs(args []string) (string, bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		if value, ok := strings.CutPrefix(name, ConfigFlagName+"="); ok {
+			return value, true
+		}
+		if name == ConfigFlagName && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+	return "", false
+}
+
+// applyConfigOptions 명령줄(과 환경 변수)에서 지정하지 않은 옵션을 설정 파일 options 값으로 설정 (적용한 옵션 이름 반환)
+func applyConfigOptions(fs *flag.FlagSet, options map[string]interface{}) ([]string, error) {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var applied []string
+	for _, name := range names {
+		if configOptionExcluded[name] {
+			return applied, fmt.Errorf("option %q cannot be set in the config file", name)
+		}
+		if fs.Lookup(name) == nil {
+			return applied, fmt.Errorf("unknown option %q (use option names without the leading -, e.g. \"login-watch\")", name)
+		}
+		if set[name] {
+			continue
+		}
+		value, err := configOptionValue(options[name])
+		if err != nil {
+			return applied, fmt.Errorf("option %q: %v", name, err)
+		}
+		if err := fs.Set(name, value); err != nil {
+			return applied, fmt.Errorf("option %q=%q: %v", name, value, err)
+		}
+		applied = append(applied, name)
+	}
+	return applied, nil
+}
+
+// configOptionValue JSON 값을 옵션 문자열로 변환 (배열은 쉼표로 연결)
+func configOptionValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			text, ok := item.(string)
+			if !ok {
+				return "", fmt.Errorf("list items must be strings, got %v", item)
+			}
+			items = append(items, text)
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v (use a string, number, true/false or a list of strings)", value)
+	}
+}
+
+// equalConfigOptions 설정 파일 options 가 같은지 비교 (재로드 시 재시작 안내용)
+func equalConfigOptions(a, b map[string]interface{}) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -9,6 +9,8 @@ Configuration Service
 - Gemini API 키 관리
 - 환경변수 기반 설정
 - 설정 검증 및 기본값 처리
+- 실행 중 설정 파일 재로드 (설정 객체를 통째로 교체)
+- 명령줄 옵션을 설정 파일 "options" 로 지정 (-config 만으로 실행, config_mode.go)
 
 작성자: Lambda-X AI Team
 버전: 1.0.0
@@ -20,6 +22,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Config 전체 설정 구조체
@@ -28,8 +34,19 @@ type Config struct {
 		Enabled         bool    `json:"enabled"`
 		GeminiAPIKey   string  `json:"gemini_api_key"`
 		GeminiModel    string  `json:"gemini_model"`
+		Provider        string  `json:"provider"` // LLM 백엔드: gemini (기본), openai, anthropic, ollama
+		APIKey          string  `json:"api_key"`  // gemini 외 백엔드 API 키 (비우면 OPENAI_API_KEY / ANTHROPIC_API_KEY)
+		Model           string  `json:"model"`    // 모델 (비우면 백엔드 기본값, gemini 는 gemini_model)
+		BaseURL         string  `json:"base_url"` // API 엔드포인트 (OpenAI 호환 서버, 원격 Ollama 등)
 		AlertThreshold  float64 `json:"alert_threshold"`
 		AnalysisInterval int    `json:"analysis_interval"`
+		RulesFile       string  `json:"rules_file"` // 사용자 정의 이상 패턴 규칙 파일 (YAML/JSON, -rules 플래그가 우선)
+		ModelFile       string  `json:"model_file"`   // ONNX 이상 점수 모델 (-onnx-model 플래그가 우선)
+		ModelWeight     float64 `json:"model_weight"` // 결합 점수에서 모델 점수 비중 (0~1, 0 이면 기본값)
+		Redaction       AIRedactionConfig `json:"redaction"` // Gemini 프롬프트 가림 (IP/사용자명/호스트명/비밀 값, 필드 허용 목록)
+		Baseline        LogBaselineConfig `json:"baseline"`  // 서비스별 로그 볼륨/에러율/고유 IP 통계 기준선 (0 이면 기본값)
+		Prompts         PromptConfig      `json:"prompts"`   // Gemini 프롬프트 템플릿 디렉터리와 변수 (비우면 내장 프롬프트)
+		Scheduler       LLMSchedulerConfig `json:"scheduler"` // LLM 호출 캐시/시간당 호출 수/일일 토큰 예산, 이상 로그 묶음 분석
 	} `json:"ai_analysis"`
 
 	SystemMonitoring struct {
@@ -38,6 +55,21 @@ type Config struct {
 		MemoryThreshold     float64 `json:"memory_threshold"`
 		DiskThreshold       float64 `json:"disk_threshold"`
 		TemperatureThreshold float64 `json:"temperature_threshold"`
+		LoadPerCoreThreshold float64 `json:"load_per_core_threshold"` // 코어당 로드 임계값 (코어 수 * 값)
+		SwapThreshold       float64 `json:"swap_threshold"`
+		MemoryPressureThreshold float64 `json:"memory_pressure_threshold"` // Linux PSI avg60 / macOS 압박 비율 (%)
+		CPUPressureThreshold float64 `json:"cpu_pressure_threshold"` // Linux PSI CPU some avg60 (%)
+		IOPressureThreshold  float64 `json:"io_pressure_threshold"`  // Linux PSI I/O some avg60 (%)
+		ConnectionSpikeThreshold float64 `json:"connection_spike_threshold"` // 포트별 연결 수가 최근 평균의 몇 배 이상이면 경고
+		ConnectionMinimum   float64 `json:"connection_minimum"` // 연결 급증으로 판단할 최소 연결 수
+		ExpectedPorts       []int   `json:"expected_ports"`     // 시작 시 열려 있지 않아도 알림하지 않을 수신 대기 포트
+		DiskBusyThreshold   float64 `json:"disk_busy_threshold"` // 디스크 사용 중 시간 비율 (%)
+		DiskIOPSThreshold   float64 `json:"disk_iops_threshold"` // 디스크별 읽기+쓰기 IOPS (0 = 사용 안 함)
+		DiskThroughputThreshold float64 `json:"disk_throughput_threshold"` // 디스크별 읽기+쓰기 처리량 (MB/s, 0 = 사용 안 함)
+		NetworkUtilizationThreshold float64 `json:"network_utilization_threshold"` // 링크 속도 대비 인터페이스 사용률 (%)
+		NetworkThroughputThreshold float64 `json:"network_throughput_threshold"` // 인터페이스 수신/송신 처리량 (Mbps, 0 = 사용 안 함)
+		NetworkErrorThreshold float64 `json:"network_error_threshold"` // 인터페이스 초당 에러+드롭
+		WatchedServices     []string `json:"watched_services"` // 재부팅 후 복구 여부를 확인할 서비스 (systemd unit / launchd label)
 		MonitoringInterval  int     `json:"monitoring_interval"`
 	} `json:"system_monitoring"`
 
@@ -49,22 +81,103 @@ type Config struct {
 		Password   string   `json:"password"`
 		To         []string `json:"to"`
 		From       string   `json:"from"`
+		OAuth2     EmailOAuth2Config `json:"oauth2"` // Gmail/Microsoft 365 XOAUTH2 인증 (refresh_token 이 있으면 비밀번호 대신 사용)
 	} `json:"email"`
 
 	Slack struct {
-		Enabled     bool   `json:"enabled"`
-		WebhookURL  string `json:"webhook_url"`
-		Channel     string `json:"channel"`
-		Username    string `json:"username"`
+		Enabled     bool                  `json:"enabled"`
+		WebhookURL  string                `json:"webhook_url"`
+		Channel     string                `json:"channel"`
+		Username    string                `json:"username"`
+		IconEmoji   string                `json:"icon_emoji"`  // 봇 아이콘 이모지 (비우면 알림 심각도별 기본 아이콘)
+		IconURL     string                `json:"icon_url"`    // 봇 아이콘 이미지 URL
+		Emoji       map[string]string     `json:"emoji"`       // 심각도별 아이콘 이모지 (예: {"critical": ":fire:"})
+		Colors      map[string]string     `json:"colors"`      // 기본 색상 대체 (예: {"danger": "#E01E5A"})
+		Channels    map[string]SlackStyle `json:"channels"`    // 채널별 표시 방식 (예: {"#security": {"username": "SecBot"}})
+		AlertTypes  map[string]SlackStyle `json:"alert_types"` // 알림 유형별 표시 방식 (예: {"login": {"icon_emoji": ":key:"}})
+		SigningSecret string              `json:"signing_secret"` // Slack 앱 Signing Secret (확인/끄기 버튼, /sysmon 명령, 비우면 SYSLOG_SLACK_SIGNING_SECRET / 키체인 slack-signing-secret)
+		CommandUsers  []string            `json:"command_users"`  // /sysmon 명령을 쓸 수 있는 Slack 사용자 ID/이름 (비우면 워크스페이스 전체)
 	} `json:"slack"`
 
+	Alerts struct {
+		Detail    map[string]string `json:"detail"`    // 채널별 알림 상세 수준: summary, full (예: {"email": "full", "slack": "summary"})
+		Intervals map[string]int    `json:"intervals"` // 알림 유형별 중복 알림 제한 간격 (분, 0 이면 제한 안 함, 예: {"error": 5, "system": 30})
+		Actions   AlertActionConfig `json:"actions"`   // 이메일 끄기/확인 링크 (base_url 은 관리 API 의 외부 HTTPS 주소)
+	} `json:"alerts"`
+
 	Logging struct {
 		LogFile    string `json:"log_file"`
 		OutputFile string `json:"output_file"`
 		Keywords   string `json:"keywords"`
 		Filters    string `json:"filters"`
+		NginxLogFormats []string `json:"nginx_log_formats"` // 사용자 정의 nginx log_format 문자열 (예: "$remote_addr - [$time_local] \"$request\" $status $request_time")
+		HealthCheckPaths      []string `json:"health_check_paths"`       // 제외할 헬스 체크 요청 경로 (비어 있으면 기본 목록: /healthz, /readyz 등)
+		HealthCheckUserAgents []string `json:"health_check_user_agents"` // 제외할 헬스 체크 User-Agent 부분 문자열 (비어 있으면 기본 목록: ELB-HealthChecker, kube-probe 등)
+		ExtractionRules       []ExtractionRule `json:"extraction_rules"`   // 사용자 정의 필드 추출 규칙 (이름 있는 캡처 그룹 정규식 → ParsedLog.Fields)
+		CustomParsers         []CustomParserConfig `json:"custom_parsers"` // 사용자 정의 로그 파서 (grok/정규식 → log_type, 자동 감지보다 먼저 시도)
+		LookupTables          []LookupTable    `json:"lookup_tables"`      // CSV/JSON 조회 테이블 (IP → 담당자, 사용자 → 부서 등 필드 보강)
 	} `json:"logging"`
 
+	Login struct {
+		SudoDenyPatterns       []string       `json:"sudo_deny_patterns"`       // sudo 위험 명령 정규식 (비어 있으면 기본 패턴 사용)
+		FailureBurstWindow     int            `json:"failure_burst_window"`     // 실패 합산 윈도우 (분)
+		FailureBurstThreshold  int            `json:"failure_burst_threshold"`  // 성공 로그인을 의심하기 위한 최소 실패 횟수
+		BruteForceWindow       int            `json:"brute_force_window"`       // IP 단위 무차별 대입 판정 윈도우 (분)
+		BruteForceThreshold    int            `json:"brute_force_threshold"`    // 윈도우 내 IP 별 최소 실패 횟수
+		ThrottleKey            string         `json:"throttle_key"`             // 알림 제한 키: user, ip, user_ip(기본), subnet
+		StatusIntervals        map[string]int `json:"status_intervals"`         // 상태별 알림 간격 (분, 예: {"failed": 1, "accepted": 30})
+		AlertInterval          int            `json:"alert_interval"`           // 기본 로그인 알림 간격 (분, -alert-interval 플래그가 우선)
+		CriticalInterval       int            `json:"critical_interval"`        // 실패/sudo 등 중요 이벤트 알림 간격 (분)
+		MaxAlertHistory        int            `json:"max_alert_history"`        // 알림 히스토리 최대 항목 수
+		HistoryRetention       int            `json:"history_retention"`        // 알림 히스토리 보존 기간 (분)
+		HistoryCleanupInterval int            `json:"history_cleanup_interval"` // 알림 히스토리 정리 작업 간격 (분)
+		TrustedNetworks        []string       `json:"trusted_networks"`         // 신뢰 네트워크 CIDR (예: "10.8.0.0/16", "office=203.0.113.0/24")
+		HostingASNs            []string       `json:"hosting_asns"`             // 기본 목록에 추가할 호스팅 사업자 ASN (예: "AS12345")
+		ASNBaselineLogins      int            `json:"asn_baseline_logins"`      // ASN 변경 판정 전 필요한 사용자의 최소 로그인 수
+		TorExitListURL         string         `json:"tor_exit_list_url"`        // Tor 출구 노드 목록 URL (비어 있으면 기본 URL, "off" 면 비활성화)
+		VPNListFiles           []string       `json:"vpn_list_files"`           // VPN/프록시 CIDR 데이터셋 파일 (한 줄에 CIDR 하나)
+		AnonymizerHighRisk     bool           `json:"anonymizer_high_risk"`     // Tor/VPN/프록시 출처 로그인을 자동으로 HIGH 위험으로 처리
+		AbuseIPDBAPIKey        string         `json:"abuseipdb_api_key"`        // AbuseIPDB API 키 (비어 있으면 ABUSEIPDB_API_KEY 환경 변수, 둘 다 없으면 조회 안 함)
+		AbuseIPDBMinScore      int            `json:"abuseipdb_min_score"`      // 알려진 악성 IP로 판정할 AbuseIPDB 신뢰 점수 (0-100)
+		ThreatBlocklistFiles   []string       `json:"threat_blocklist_files"`   // 위협 블록리스트 파일 (Spamhaus DROP, FireHOL netset 등)
+	} `json:"login"`
+
+	Block struct {
+		Action         string   `json:"action"`          // 무차별 대입 공격 IP 자동 차단: ""(비활성화), auto, iptables, nftables, pf, ipfw, custom
+		BlockCommand   string   `json:"block_command"`   // custom 차단 명령 ({ip} 자리표시자, 예: "firewall-cmd --add-rich-rule=... {ip}")
+		UnblockCommand string   `json:"unblock_command"` // custom 해제 명령 ({ip} 자리표시자)
+		Duration       int      `json:"duration"`        // 차단 유지 시간 (분, 0 이면 자동 해제 안 함)
+		Allowlist      []string `json:"allowlist"`       // 차단하지 않을 CIDR/IP (신뢰 네트워크와 루프백은 항상 제외)
+	} `json:"block"`
+
+	Database struct {
+		Superusers          []string `json:"superusers"`            // 인증 실패를 알릴 DB 관리자 계정 (비어 있으면 root, admin, postgres, rdsadmin, azure_superuser)
+		AuthFailureInterval int      `json:"auth_failure_interval"` // 같은 계정/출처의 관리자 인증 실패 알림 간격 (분)
+	} `json:"database"`
+
+	Reports struct {
+		Schedule string `json:"schedule"` // 시간대 기반 보고서 스케줄 (예: "08:00 Asia/Seoul daily"), 비어 있으면 고정 간격
+		ArchiveDir     string   `json:"archive_dir"`     // 보고서 파일 저장 디렉토리 (비어 있으면 저장 안 함)
+		ArchiveFormats []string `json:"archive_formats"` // markdown, html
+		GitPush        bool     `json:"git_push"`        // 저장 디렉토리가 git 저장소이면 커밋 후 푸시
+		Digest         DigestConfig `json:"digest"`      // LLM 일일 요약 (알림/로그인/이상 패턴/시스템 추이, -digest-schedule 이 우선)
+	} `json:"reports"`
+
+	SLOs []SLODefinition `json:"slos"` // 엔드포인트 SLO 오류 예산 (-slo 플래그가 우선)
+
+	Routes []AlertRoute `json:"routes"` // 알림 유형/심각도/호스트/키워드별 전송 채널 (일치하는 규칙이 없으면 모든 채널)
+
+	Dependencies ServiceDependencyConfig `json:"dependencies"` // 서비스 의존 관계 (연쇄 장애의 근본 원인 추정)
+	Maintenance MaintenanceConfig `json:"maintenance"` // 외부 iCal 캘린더의 점검 일정 (점검 중 알림 심각도 낮추기/묶기)
+	QuietHours []QuietHoursRule `json:"quiet_hours"` // 조용한 시간 / 고정 점검 창 (채널별로 critical 이 아닌 알림 보류 후 요약)
+	Canary CanaryConfig `json:"canary"` // 알림 채널 카나리 스케줄과 도착 확인 방법 (-canary 로 켜기)
+	SeverityPolicy SeverityPolicyConfig `json:"severity_policy"` // 로그 줄의 레벨과 알림 동작 규칙 (일치하지 않으면 내장 키워드 판정)
+	CICD CICDConfig `json:"cicd"` // CI/CD 로그 감지 기준, 러너 허용 네트워크, 빌드 출력 입력, 담당 팀 (-cicd-watch 로 켜기)
+
+	Options map[string]interface{} `json:"options"` // 명령줄 옵션 (이름 → 값, 예: {"login-watch": true}, 명령줄이 우선, 변경은 재시작 후 적용)
+
+	DataUpdates []DataUpdateSource `json:"data_updates"` // 규칙/Tor/GeoIP 데이터 자동 업데이트 (변경은 재시작 후 적용)
+
 	Features struct {
 		ComputerNameDetection bool `json:"computer_name_detection"`
 		IPClassification     bool `json:"ip_classification"`
@@ -78,6 +191,8 @@ type Config struct {
 type ConfigService struct {
 	configPath string
 	config     *Config
+	readOnly   bool         // 설정 파일을 쓰지 않음 (컨테이너 모드의 읽기 전용 설정 볼륨)
+	mutex      sync.RWMutex // 재로드 시 설정 교체 보호
 }
 
 // NewConfigService 설정 서비스 생성자
@@ -88,6 +203,23 @@ func NewConfigService(configPath string) *ConfigService {
 	}
 }
 
+// SetReadOnly 설정 파일을 쓰지 않도록 설정 (파일이 없어도 기본 설정 파일을 만들지 않음)
+func (cs *ConfigService) SetReadOnly() {
+	cs.readOnly = true
+}
+
+// getDataDir 상태 파일 저장 디렉토리 반환 (~/.syslog-monitor, SYSLOG_DATA_DIR 로 변경)
+func getDataDir() string {
+	if dir := os.Getenv(DataDirEnv); dir != "" {
+		return expandHomePath(dir)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return DefaultConfigDir
+	}
+	return filepath.Join(home, DefaultConfigDir)
+}
+
 // LoadConfig 설정 파일 로드
 func (cs *ConfigService) LoadConfig() error {
 	// 설정 파일이 없으면 기본 설정 생성
@@ -107,13 +239,38 @@ func (cs *ConfigService) LoadConfig() error {
 	}
 
 	// 환경변수에서 API 키 읽기
-	cs.loadFromEnvironment()
+	cs.loadFromEnvironment(cs.config)
 
 	return nil
 }
 
+// Reload 설정 파일을 다시 읽어 새 설정 객체로 교체
+// 파싱에 실패하면 기존 설정을 그대로 유지하며, 교체 전 설정과 새 설정을 함께 반환
+func (cs *ConfigService) Reload() (previous, config *Config, err error) {
+	data, err := os.ReadFile(cs.configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	config = &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse config file: %v", err)
+	}
+	cs.loadFromEnvironment(config)
+
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	previous = cs.config
+	cs.config = config
+	return previous, config, nil
+}
+
 // SaveConfig 설정 파일 저장
 func (cs *ConfigService) SaveConfig() error {
+	if cs.readOnly {
+		return fmt.Errorf("config file %s is read-only in container mode", cs.configPath)
+	}
+
 	// 디렉토리 생성
 	dir := filepath.Dir(cs.configPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -141,14 +298,30 @@ func (cs *ConfigService) createDefaultConfig() error {
 			Enabled         bool    `json:"enabled"`
 			GeminiAPIKey   string  `json:"gemini_api_key"`
 			GeminiModel    string  `json:"gemini_model"`
+			Provider        string  `json:"provider"` // LLM 백엔드: gemini (기본), openai, anthropic, ollama
+			APIKey          string  `json:"api_key"`  // gemini 외 백엔드 API 키 (비우면 OPENAI_API_KEY / ANTHROPIC_API_KEY)
+			Model           string  `json:"model"`    // 모델 (비우면 백엔드 기본값, gemini 는 gemini_model)
+			BaseURL         string  `json:"base_url"` // API 엔드포인트 (OpenAI 호환 서버, 원격 Ollama 등)
 			AlertThreshold  float64 `json:"alert_threshold"`
 			AnalysisInterval int    `json:"analysis_interval"`
+			RulesFile       string  `json:"rules_file"` // 사용자 정의 이상 패턴 규칙 파일 (YAML/JSON, -rules 플래그가 우선)
+			ModelFile       string  `json:"model_file"`   // ONNX 이상 점수 모델 (-onnx-model 플래그가 우선)
+			ModelWeight     float64 `json:"model_weight"` // 결합 점수에서 모델 점수 비중 (0~1, 0 이면 기본값)
+			Redaction       AIRedactionConfig `json:"redaction"` // Gemini 프롬프트 가림 (IP/사용자명/호스트명/비밀 값, 필드 허용 목록)
+			Baseline        LogBaselineConfig `json:"baseline"`  // 서비스별 로그 볼륨/에러율/고유 IP 통계 기준선 (0 이면 기본값)
+			Prompts         PromptConfig      `json:"prompts"`   // Gemini 프롬프트 템플릿 디렉터리와 변수 (비우면 내장 프롬프트)
+		Scheduler       LLMSchedulerConfig `json:"scheduler"` // LLM 호출 캐시/시간당 호출 수/일일 토큰 예산, 이상 로그 묶음 분석
 		}{
 			Enabled:         true,
 			GeminiAPIKey:   "",
 			GeminiModel:    "gemini-1.5-flash",
+			Provider:        LLMProviderGemini,
 			AlertThreshold:  7.0,
 			AnalysisInterval: 30,
+			RulesFile:       "",
+			ModelFile:       "",
+			ModelWeight:     DefaultModelWeight,
+			Redaction:       AIRedactionConfig{MaskIPs: true, MaskUsernames: true, MaskHostnames: true},
 		},
 		SystemMonitoring: struct {
 			Enabled             bool    `json:"enabled"`
@@ -156,6 +329,21 @@ func (cs *ConfigService) createDefaultConfig() error {
 			MemoryThreshold     float64 `json:"memory_threshold"`
 			DiskThreshold       float64 `json:"disk_threshold"`
 			TemperatureThreshold float64 `json:"temperature_threshold"`
+			LoadPerCoreThreshold float64 `json:"load_per_core_threshold"`
+			SwapThreshold       float64 `json:"swap_threshold"`
+			MemoryPressureThreshold float64 `json:"memory_pressure_threshold"`
+			CPUPressureThreshold float64 `json:"cpu_pressure_threshold"`
+			IOPressureThreshold  float64 `json:"io_pressure_threshold"`
+			ConnectionSpikeThreshold float64 `json:"connection_spike_threshold"`
+			ConnectionMinimum   float64 `json:"connection_minimum"`
+			ExpectedPorts       []int   `json:"expected_ports"`
+			DiskBusyThreshold   float64 `json:"disk_busy_threshold"`
+			DiskIOPSThreshold   float64 `json:"disk_iops_threshold"`
+			DiskThroughputThreshold float64 `json:"disk_throughput_threshold"`
+			NetworkUtilizationThreshold float64 `json:"network_utilization_threshold"`
+			NetworkThroughputThreshold float64 `json:"network_throughput_threshold"`
+			NetworkErrorThreshold float64 `json:"network_error_threshold"`
+			WatchedServices     []string `json:"watched_services"`
 			MonitoringInterval  int     `json:"monitoring_interval"`
 		}{
 			Enabled:             true,
@@ -163,6 +351,17 @@ func (cs *ConfigService) createDefaultConfig() error {
 			MemoryThreshold:     85.0,
 			DiskThreshold:       90.0,
 			TemperatureThreshold: 75.0,
+			LoadPerCoreThreshold: DefaultLoadThreshold,
+			SwapThreshold:       DefaultSwapThreshold,
+			MemoryPressureThreshold: DefaultMemoryPressureThreshold,
+			CPUPressureThreshold: DefaultCPUPressureThreshold,
+			IOPressureThreshold:  DefaultIOPressureThreshold,
+			ConnectionSpikeThreshold: DefaultConnectionSpike,
+			ConnectionMinimum:   DefaultConnectionMin,
+			DiskBusyThreshold:   DefaultDiskBusyThreshold,
+			NetworkUtilizationThreshold: DefaultNetworkUtilizationThreshold,
+			NetworkErrorThreshold: DefaultNetworkErrorThreshold,
+			WatchedServices:     []string{},
 			MonitoringInterval:  300,
 		},
 		Email: struct {
@@ -173,37 +372,170 @@ func (cs *ConfigService) createDefaultConfig() error {
 			Password   string   `json:"password"`
 			To         []string `json:"to"`
 			From       string   `json:"from"`
+			OAuth2     EmailOAuth2Config `json:"oauth2"`
 		}{
 			Enabled:    true,
 			SMTPServer: "smtp.gmail.com",
 			SMTPPort:   587,
-			Username:   "enfn2001@gmail.com",
+			Username:   "",
 			Password:   "",
-			To:         []string{"robot@lambda-x.ai", "enfn2001@gmail.com"},
-			From:       "security@lambda-x.ai",
+			To:         []string{},
+			From:       "",
+			OAuth2:     EmailOAuth2Config{Provider: OAuth2ProviderGoogle},
 		},
 		Slack: struct {
-			Enabled     bool   `json:"enabled"`
-			WebhookURL  string `json:"webhook_url"`
-			Channel     string `json:"channel"`
-			Username    string `json:"username"`
+			Enabled     bool                  `json:"enabled"`
+			WebhookURL  string                `json:"webhook_url"`
+			Channel     string                `json:"channel"`
+			Username    string                `json:"username"`
+		IconEmoji   string                `json:"icon_emoji"`  // 봇 아이콘 이모지 (비우면 알림 심각도별 기본 아이콘)
+		IconURL     string                `json:"icon_url"`    // 봇 아이콘 이미지 URL
+		Emoji       map[string]string     `json:"emoji"`       // 심각도별 아이콘 이모지 (예: {"critical": ":fire:"})
+		Colors      map[string]string     `json:"colors"`      // 기본 색상 대체 (예: {"danger": "#E01E5A"})
+		Channels    map[string]SlackStyle `json:"channels"`    // 채널별 표시 방식 (예: {"#security": {"username": "SecBot"}})
+		AlertTypes  map[string]SlackStyle `json:"alert_types"` // 알림 유형별 표시 방식 (예: {"login": {"icon_emoji": ":key:"}})
+		SigningSecret string              `json:"signing_secret"` // Slack 앱 Signing Secret (확인/끄기 버튼, /sysmon 명령, 비우면 SYSLOG_SLACK_SIGNING_SECRET / 키체인 slack-signing-secret)
+		CommandUsers  []string            `json:"command_users"`  // /sysmon 명령을 쓸 수 있는 Slack 사용자 ID/이름 (비우면 워크스페이스 전체)
 		}{
 			Enabled:    false,
 			WebhookURL: "",
 			Channel:    "#security",
 			Username:   "AI Security Monitor",
 		},
+		Alerts: struct {
+			Detail    map[string]string `json:"detail"`
+			Intervals map[string]int    `json:"intervals"`
+			Actions   AlertActionConfig `json:"actions"`
+		}{
+			Detail: map[string]string{
+				"email":    AlertDetailFull,
+				"slack":    AlertDetailSummary,
+				"telegram": AlertDetailSummary,
+				"webhook":  AlertDetailFull,
+			},
+			Intervals: map[string]int{
+				AlertTypeError:    DefaultAlertIntervals[AlertTypeError],
+				AlertTypeCritical: DefaultAlertIntervals[AlertTypeCritical],
+				AlertTypeAI:       DefaultAlertIntervals[AlertTypeAI],
+				AlertTypeSystem:   DefaultAlertIntervals[AlertTypeSystem],
+			},
+		},
 		Logging: struct {
 			LogFile    string `json:"log_file"`
 			OutputFile string `json:"output_file"`
 			Keywords   string `json:"keywords"`
 			Filters    string `json:"filters"`
+			NginxLogFormats []string `json:"nginx_log_formats"`
+			HealthCheckPaths      []string `json:"health_check_paths"`
+			HealthCheckUserAgents []string `json:"health_check_user_agents"`
+			ExtractionRules       []ExtractionRule `json:"extraction_rules"`
+			CustomParsers         []CustomParserConfig `json:"custom_parsers"`
+			LookupTables          []LookupTable    `json:"lookup_tables"`
 		}{
 			LogFile:    "/var/log/system.log",
 			OutputFile: "",
 			Keywords:   "",
 			Filters:    "",
+			NginxLogFormats: []string{},
+			HealthCheckPaths:      []string{},
+			HealthCheckUserAgents: []string{},
+			ExtractionRules:       []ExtractionRule{},
+			CustomParsers:         []CustomParserConfig{},
+			LookupTables:          []LookupTable{},
+		},
+		Login: struct {
+			SudoDenyPatterns       []string       `json:"sudo_deny_patterns"`
+			FailureBurstWindow     int            `json:"failure_burst_window"`
+			FailureBurstThreshold  int            `json:"failure_burst_threshold"`
+			BruteForceWindow       int            `json:"brute_force_window"`
+			BruteForceThreshold    int            `json:"brute_force_threshold"`
+			ThrottleKey            string         `json:"throttle_key"`
+			StatusIntervals        map[string]int `json:"status_intervals"`
+			AlertInterval          int            `json:"alert_interval"`
+			CriticalInterval       int            `json:"critical_interval"`
+			MaxAlertHistory        int            `json:"max_alert_history"`
+			HistoryRetention       int            `json:"history_retention"`
+			HistoryCleanupInterval int            `json:"history_cleanup_interval"`
+			TrustedNetworks        []string       `json:"trusted_networks"`
+			HostingASNs            []string       `json:"hosting_asns"`
+			ASNBaselineLogins      int            `json:"asn_baseline_logins"`
+			TorExitListURL         string         `json:"tor_exit_list_url"`
+			VPNListFiles           []string       `json:"vpn_list_files"`
+			AnonymizerHighRisk     bool           `json:"anonymizer_high_risk"`
+			AbuseIPDBAPIKey        string         `json:"abuseipdb_api_key"`
+			AbuseIPDBMinScore      int            `json:"abuseipdb_min_score"`
+			ThreatBlocklistFiles   []string       `json:"threat_blocklist_files"`
+		}{
+			SudoDenyPatterns:       DefaultSudoDenyPatterns,
+			FailureBurstWindow:     int(DefaultFailureBurstWindow / time.Minute),
+			FailureBurstThreshold:  DefaultFailureBurstThreshold,
+			BruteForceWindow:       int(DefaultBruteForceWindow / time.Minute),
+			BruteForceThreshold:    DefaultBruteForceThreshold,
+			ThrottleKey:            ThrottleKeyUserIP,
+			AlertInterval:          int(DefaultLoginAlertInterval / time.Minute),
+			CriticalInterval:       int(CriticalAlertInterval / time.Minute),
+			MaxAlertHistory:        MaxAlertHistorySize,
+			HistoryRetention:       int(AlertHistoryCleanupInterval / time.Minute),
+			HistoryCleanupInterval: int(AlertHistoryJanitorInterval / time.Minute),
+			TrustedNetworks:        []string{},
+			HostingASNs:            []string{},
+			ASNBaselineLogins:      DefaultASNBaselineLogins,
+			TorExitListURL:         DefaultTorExitListURL,
+			VPNListFiles:           []string{},
+			AnonymizerHighRisk:     false,
+			AbuseIPDBAPIKey:        "",
+			AbuseIPDBMinScore:      DefaultAbuseIPDBMinScore,
+			ThreatBlocklistFiles:   []string{},
+		},
+		Block: struct {
+			Action         string   `json:"action"`
+			BlockCommand   string   `json:"block_command"`
+			UnblockCommand string   `json:"unblock_command"`
+			Duration       int      `json:"duration"`
+			Allowlist      []string `json:"allowlist"`
+		}{
+			Action:    "",
+			Duration:  int(DefaultBlockDuration / time.Minute),
+			Allowlist: []string{},
+		},
+		Database: struct {
+			Superusers          []string `json:"superusers"`
+			AuthFailureInterval int      `json:"auth_failure_interval"`
+		}{
+			Superusers:          append([]string{}, DefaultDBSuperusers...),
+			AuthFailureInterval: DefaultDBAuthFailureInterval,
+		},
+		Reports: struct {
+			Schedule       string   `json:"schedule"`
+			ArchiveDir     string   `json:"archive_dir"`
+			ArchiveFormats []string `json:"archive_formats"`
+			GitPush        bool     `json:"git_push"`
+			Digest         DigestConfig `json:"digest"`
+		}{
+			Schedule:       "",
+			ArchiveDir:     "",
+			ArchiveFormats: []string{ReportFormatMarkdown},
+			GitPush:        false,
+			Digest:         DigestConfig{Schedule: "", Hours: DefaultDigestHours, Top: DefaultDigestTop},
+		},
+		SLOs: []SLODefinition{},
+		Routes: []AlertRoute{},
+		Dependencies: ServiceDependencyConfig{
+			WindowSeconds: 300,
+			Components:    []ServiceComponent{},
 		},
+		Maintenance: MaintenanceConfig{Calendars: []MaintenanceCalendarConfig{}},
+		QuietHours: []QuietHoursRule{},
+		SeverityPolicy: SeverityPolicyConfig{Rules: []SeverityRule{}},
+		CICD: CICDConfig{
+			FailureThreshold: DefaultCICDFailureThreshold,
+			FailureWindow:    DefaultCICDFailureWindow,
+			RunnerNetworks:   []string{},
+			Sources:          []string{},
+			SecretInterval:   DefaultCICDSecretInterval,
+			Teams:            []CICDTeam{},
+		},
+		DataUpdates: []DataUpdateSource{},
 		Features: struct {
 			ComputerNameDetection bool `json:"computer_name_detection"`
 			IPClassification     bool `json:"ip_classification"`
@@ -220,52 +552,86 @@ func (cs *ConfigService) createDefaultConfig() error {
 	}
 
 	// 환경변수에서 API 키 읽기
-	cs.loadFromEnvironment()
+	cs.loadFromEnvironment(cs.config)
 
+	// 읽기 전용이면 기본 설정은 메모리에서만 사용
+	if cs.readOnly {
+		return nil
+	}
 	return cs.SaveConfig()
 }
 
 // loadFromEnvironment 환경변수에서 설정 로드
-func (cs *ConfigService) loadFromEnvironment() {
+func (cs *ConfigService) loadFromEnvironment(config *Config) {
 	// Gemini API 키
 	if apiKey := os.Getenv("GEMINI_API_KEY"); apiKey != "" {
-		cs.config.AI.GeminiAPIKey = apiKey
+		config.AI.GeminiAPIKey = apiKey
+	}
+	// OpenAI / Anthropic API 키 (설정 파일 api_key 가 비어 있을 때 선택한 백엔드의 환경변수)
+	if provider, err := normalizeLLMProvider(config.AI.Provider); err == nil && provider != LLMProviderGemini && config.AI.APIKey == "" {
+		if keyEnv := llmProviderDefaults[provider].KeyEnv; keyEnv != "" {
+			config.AI.APIKey = os.Getenv(keyEnv)
+		}
 	}
 
 	// 이메일 설정
 	if emailTo := os.Getenv("SYSLOG_EMAIL_TO"); emailTo != "" {
-		cs.config.Email.To = []string{emailTo}
+		config.Email.To = []string{emailTo}
 	}
 	if smtpUser := os.Getenv("SYSLOG_SMTP_USER"); smtpUser != "" {
-		cs.config.Email.Username = smtpUser
+		config.Email.Username = smtpUser
 	}
 	if smtpPassword := os.Getenv("SYSLOG_SMTP_PASSWORD"); smtpPassword != "" {
-		cs.config.Email.Password = smtpPassword
+		config.Email.Password = smtpPassword
+	}
+	if clientID := os.Getenv("SYSLOG_SMTP_OAUTH2_CLIENT_ID"); clientID != "" {
+		config.Email.OAuth2.ClientID = clientID
+	}
+	if clientSecret := os.Getenv("SYSLOG_SMTP_OAUTH2_CLIENT_SECRET"); clientSecret != "" {
+		config.Email.OAuth2.ClientSecret = clientSecret
+	}
+	if refreshToken := os.Getenv("SYSLOG_SMTP_OAUTH2_REFRESH_TOKEN"); refreshToken != "" {
+		config.Email.OAuth2.RefreshToken = refreshToken
 	}
 
 	// Slack 설정
 	if webhookURL := os.Getenv("SYSLOG_SLACK_WEBHOOK"); webhookURL != "" {
-		cs.config.Slack.WebhookURL = webhookURL
-		cs.config.Slack.Enabled = true
+		config.Slack.WebhookURL = webhookURL
+		config.Slack.Enabled = true
 	}
 	if channel := os.Getenv("SYSLOG_SLACK_CHANNEL"); channel != "" {
-		cs.config.Slack.Channel = channel
+		config.Slack.Channel = channel
 	}
 }
 
-// GetGeminiConfig Gemini 설정 반환
-func (cs *ConfigService) GetGeminiConfig() *GeminiConfig {
-	return &GeminiConfig{
-		APIKey:     cs.config.AI.GeminiAPIKey,
-		Model:      cs.config.AI.GeminiModel,
-		MaxTokens:  2048,
+// GetLLMConfig LLM 백엔드 설정 반환 (gemini 는 gemini_api_key / gemini_model 사용)
+func (cs *ConfigService) GetLLMConfig() *LLMConfig {
+	config := cs.GetConfig()
+	llmConfig := &LLMConfig{
+		Provider:    config.AI.Provider,
+		APIKey:      config.AI.APIKey,
+		Model:       config.AI.Model,
+		BaseURL:     config.AI.BaseURL,
+		MaxTokens:   2048,
 		Temperature: 0.7,
-		Enabled:    cs.config.AI.Enabled,
+		Enabled:     config.AI.Enabled,
+		Redaction:   config.AI.Redaction,
 	}
+	if provider, _ := normalizeLLMProvider(config.AI.Provider); provider == LLMProviderGemini {
+		if llmConfig.APIKey == "" {
+			llmConfig.APIKey = config.AI.GeminiAPIKey
+		}
+		if llmConfig.Model == "" {
+			llmConfig.Model = config.AI.GeminiModel
+		}
+	}
+	return llmConfig
 }
 
-// GetConfig 전체 설정 반환
+// GetConfig 전체 설정 반환 (재로드 후에는 새 설정 객체)
 func (cs *ConfigService) GetConfig() *Config {
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
 	return cs.config
 }
 
@@ -290,6 +656,39 @@ func (cs *ConfigService) ValidateGeminiAPIKey() error {
 	return nil
 }
 
+// UpdateEmailRefreshToken 순환된 OAuth2 refresh token 을 설정 파일의 email.oauth2.refresh_token 에 저장
+// 환경변수로 덮어쓴 값이 파일에 기록되지 않도록 파일을 다시 읽어 해당 필드만 교체
+// (refresh token 이 환경변수로 지정되어 파일에 없으면 저장하지 않음)
+func (cs *ConfigService) UpdateEmailRefreshToken(refreshToken string) error {
+	info, err := os.Stat(cs.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat config file: %v", err)
+	}
+	data, err := os.ReadFile(cs.configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	// 환경변수를 적용하지 않은 파일 내용 그대로 사용
+	fileConfig := &Config{}
+	if err := json.Unmarshal(data, fileConfig); err != nil {
+		return fmt.Errorf("failed to parse config file: %v", err)
+	}
+	if fileConfig.Email.OAuth2.RefreshToken == "" {
+		return nil
+	}
+	fileConfig.Email.OAuth2.RefreshToken = refreshToken
+
+	updated, err := json.MarshalIndent(fileConfig, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %v", err)
+	}
+	if err := os.WriteFile(cs.configPath, updated, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
+	return nil
+}
+
 // GetConfigPath 설정 파일 경로 반환
 func (cs *ConfigService) GetConfigPath() string {
 	return cs.configPath
@@ -297,15 +696,45 @@ func (cs *ConfigService) GetConfigPath() string {
 
 // ShowConfigInfo 설정 정보 표시
 func (cs *ConfigService) ShowConfigInfo() {
+	promptDir := cs.config.AI.Prompts.Dir
+	if promptDir == "" {
+		promptDir = "내장"
+	}
+	backend := fmt.Sprintf("알 수 없음 (%s)", cs.config.AI.Provider)
+	if llmConfig, err := cs.GetLLMConfig().withDefaults(); err == nil {
+		backend = fmt.Sprintf("%s (모델: %s)", llmConfig.Provider, llmConfig.Model)
+		if llmConfig.Enabled && !llmConfig.configured() {
+			backend += " - API 키 없음, 기본 분석 사용"
+		}
+	}
+	scheduler := cs.config.AI.Scheduler
+	limits := "제한 없음"
+	if scheduler.MaxRequestsPerHour > 0 || scheduler.MaxTokensPerDay > 0 {
+		limits = fmt.Sprintf("시간당 %d회, 일일 %d토큰 (0 = 제한 없음)", scheduler.MaxRequestsPerHour, scheduler.MaxTokensPerDay)
+	}
+	options := "없음"
+	if len(cs.config.Options) > 0 {
+		names := make([]string, 0, len(cs.config.Options))
+		for name := range cs.config.Options {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		options = strings.Join(names, ", ") // 값에 비밀이 있을 수 있어 이름만 표시
+	}
 	fmt.Printf(`
 🔧 설정 정보
 ============
 📁 설정 파일: %s
 🤖 AI 분석: %t
+🧠 AI 백엔드: %s
+💰 AI 호출 한도: %s
 🔑 Gemini API 키: %s
+🛡️  AI 프롬프트 가림: %t
+📝 AI 프롬프트 템플릿: %s
 📊 시스템 모니터링: %t
 📧 이메일 알림: %t
 💬 Slack 알림: %t
+⚙️  실행 옵션 (options): %s
 
 💡 Gemini API 키 설정 방법:
 1. https://makersuite.google.com/app/apikey 에서 API 키 생성
@@ -314,10 +743,15 @@ func (cs *ConfigService) ShowConfigInfo() {
 `,
 		cs.configPath,
 		cs.config.AI.Enabled,
+		backend,
+		limits,
 		cs.getMaskedAPIKey(),
+		cs.config.AI.Redaction.Enabled,
+		promptDir,
 		cs.config.SystemMonitoring.Enabled,
 		cs.config.Email.Enabled,
 		cs.config.Slack.Enabled,
+		options,
 		cs.configPath)
 }
 
@@ -0,0 +1,127 @@
+/*
+Config Watcher Module
+=====================
+
+설정 파일 변경 감지 및 재로드 요청
+
+주요 기능:
+- 설정 파일의 수정 시각/크기를 주기적으로 비교 (기본 5초, 편집기의 교체 저장도 감지)
+- 규칙 파일 등 함께 재로드할 파일 추가 감시 (Watch)
+- SIGHUP 수신 시 즉시 재로드 요청 (kill -HUP <pid>, systemctl reload)
+- 재로드 요청은 채널로 전달되어 tail/journald 처리 루프에서 적용 (처리 루프 재시작 없음)
+- 처리 중 요청이 겹치면 하나로 합침
+*/
+package main
+
+import (
+	"context"   // 종료 신호
+	"os"        // 파일 상태 확인
+	"os/signal" // SIGHUP 수신
+	"sync"      // 동기화
+	"syscall"   // 시그널 상수
+	"time"      // 시간 처리
+)
+
+// ConfigWatcher 설정 파일 변경 / SIGHUP 감시자
+type ConfigWatcher struct {
+	path      string
+	interval  time.Duration  // 파일 변경 확인 주기 (0이면 SIGHUP 만 처리)
+	reloads   chan string    // 재로드 요청 (요청 사유)
+	files     []*watchedFile // 설정 파일과 추가 감시 파일
+	logger    Logger
+	startOnce sync.Once
+}
+
+// watchedFile 변경을 비교할 파일 상태
+type watchedFile struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// NewConfigWatcher 새로운 설정 파일 감시자 생성 (현재 파일 상태를 기준으로 변경 판단)
+func NewConfigWatcher(path string, interval time.Duration, logger Logger) *ConfigWatcher {
+	cw := &ConfigWatcher{
+		path:     path,
+		interval: interval,
+		reloads:  make(chan string, 1),
+		logger:   logger,
+	}
+	cw.Watch(path)
+	return cw
+}
+
+// Watch 변경 시 재로드를 요청할 파일 추가 (Start 전에 호출)
+func (cw *ConfigWatcher) Watch(path string) {
+	file := &watchedFile{path: path}
+	if info, err := os.Stat(path); err == nil {
+		file.modTime = info.ModTime()
+		file.size = info.Size()
+	}
+	cw.files = append(cw.files, file)
+}
+
+// Reloads 재로드 요청 채널
+func (cw *ConfigWatcher) Reloads() <-chan string {
+	return cw.reloads
+}
+
+// Start SIGHUP 수신 및 파일 변경 확인 시작 (ctx 가 끝나면 중단)
+func (cw *ConfigWatcher) Start(ctx context.Context) {
+	cw.startOnce.Do(func() {
+		hupChan := make(chan os.Signal, 1)
+		signal.Notify(hupChan, syscall.SIGHUP)
+
+		var tick <-chan time.Time
+		var ticker *time.Ticker
+		if cw.interval > 0 {
+			ticker = time.NewTicker(cw.interval)
+			tick = ticker.C
+		}
+
+		go func() {
+			defer signal.Stop(hupChan)
+			if ticker != nil {
+				defer ticker.Stop()
+			}
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-hupChan:
+					cw.logger.Infof("🔄 SIGHUP received, reloading config: %s", cw.path)
+					cw.request("SIGHUP")
+				case <-tick:
+					for _, file := range cw.files {
+						if file.changed() {
+							cw.logger.Infof("🔄 Config file changed, reloading: %s", file.path)
+							cw.request("file changed")
+						}
+					}
+				}
+			}
+		}()
+	})
+}
+
+// changed 마지막 확인 이후 파일이 바뀌었는지 확인 (파일이 없으면 변경 없음으로 처리)
+func (file *watchedFile) changed() bool {
+	info, err := os.Stat(file.path)
+	if err != nil {
+		return false
+	}
+	if info.ModTime().Equal(file.modTime) && info.Size() == file.size {
+		return false
+	}
+	file.modTime = info.ModTime()
+	file.size = info.Size()
+	return true
+}
+
+// request 재로드 요청 전달 (이미 대기 중인 요청이 있으면 합침)
+func (cw *ConfigWatcher) request(reason string) {
+	select {
+	case cw.reloads <- reason:
+	default:
+	}
+}
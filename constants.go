@@ -32,20 +32,6 @@ const (
 	SMTPPortTLS       = "587"            // STARTTLS 포트 (동일)
 )
 
-// Default email recipients 기본 이메일 수신자 목록
-// 긴급 알림을 받을 이메일 주소들 (여러 명에게 동시 전송)
-var DefaultEmailRecipients = []string{
-	"robot@lambda-x.ai", // Lambda-X AI 팀 메인 주소
-	"enfn2001@gmail.com", // 개발자 개인 주소
-}
-
-// Default SMTP credentials 기본 SMTP 인증 정보
-// Gmail 앱 패스워드 사용 (2단계 인증 필수)
-const (
-	DefaultSMTPUser     = "enfn2001@gmail.com"    // Gmail 계정
-	DefaultSMTPPassword = "kwev eavp nrbi mtrj"   // Gmail 앱 패스워드 (16자리)
-)
-
 // Time intervals 시간 간격 관련 설정값
 const (
 	DefaultMonitoringInterval = time.Minute * 5 // 시스템 모니터링 주기 (5분마다 메트릭 수집)
@@ -56,7 +42,37 @@ const (
 	DefaultLoginAlertInterval   = time.Minute * 10 // 기본 로그인 알림 간격 (10분)
 	CriticalAlertInterval       = time.Minute * 2  // 중요 알림 간격 (실패한 로그인 등, 2분)
 	MaxAlertHistorySize         = 100              // 알림 히스토리 최대 크기
-	AlertHistoryCleanupInterval = time.Hour * 1    // 알림 히스토리 정리 간격 (1시간)
+	AlertHistoryCleanupInterval = time.Hour * 1    // 알림 히스토리 보존 기간 (1시간 지난 항목 정리)
+	AlertHistoryJanitorInterval = time.Minute * 5  // 알림 히스토리 정리 작업 실행 간격 (5분)
+
+	// Login failure correlation 실패 후 성공 로그인 상관 분석 설정
+	DefaultFailureBurstWindow    = time.Minute * 10 // 실패 횟수를 합산하는 시간 윈도우 (10분)
+	DefaultFailureBurstThreshold = 5                // 성공 로그인을 의심하기 위한 최소 실패 횟수
+
+	// Brute-force detection IP 단위 무차별 대입 공격 탐지 설정
+	DefaultBruteForceWindow    = time.Minute * 5 // IP 별 실패 횟수를 합산하는 시간 윈도우 (5분)
+	DefaultBruteForceThreshold = 10              // 공격으로 판정하는 윈도우 내 최소 실패 횟수
+
+	// Login ASN change detection 로그인 ASN 변경 탐지 설정
+	DefaultASNBaselineLogins = 3 // ASN 변경 판정 전 필요한 사용자의 최소 기록 로그인 수
+
+	// Config reload 설정 파일 재로드 설정
+	ConfigPollInterval = time.Second * 5 // 설정 파일 변경 확인 주기 (수정 시각/크기 비교)
+
+	// Boot detection 재부팅 감지 설정
+	BootServiceCheckDelay = time.Minute * 2 // 부팅 후 감시 서비스 상태 확인까지 대기 시간
+	BootTimeTolerance     = time.Minute * 1 // 부팅 시각 비교 허용 오차 (NTP 보정 등)
+
+	// Graceful shutdown 종료 설정
+	ShutdownDrainTimeout = time.Second * 10 // 종료 시 전송 중인 알림을 기다리는 최대 시간 (남은 저널 기록은 다음 시작 시 다시 전송)
+)
+
+// Login alert throttle keys 로그인 알림 제한 키 (설정 파일 login.throttle_key)
+const (
+	ThrottleKeyUser   = "user"    // 사용자 단위 (VPN 출구가 바뀌어도 한 번만 알림)
+	ThrottleKeyIP     = "ip"      // IP 단위 (사용자명을 바꿔가며 시도하는 공격 묶음)
+	ThrottleKeyUserIP = "user_ip" // 사용자@IP 단위 (기본값)
+	ThrottleKeySubnet = "subnet"  // 서브넷 단위 (IPv4 /24, IPv6 /64, IP 순환 공격 묶음)
 )
 
 // AI Analysis thresholds AI 분석 및 이상 탐지 임계값
@@ -72,8 +88,36 @@ const (
 	DefaultCPUThreshold    = 80.0 // CPU 사용률 경고 임계값 (80% 이상)
 	DefaultMemoryThreshold = 85.0 // 메모리 사용률 경고 임계값 (85% 이상)
 	DefaultDiskThreshold   = 90.0 // 디스크 사용률 경고 임계값 (90% 이상)
-	DefaultLoadThreshold   = 2.0  // 로드 평균 경고 임계값 (CPU 코어 수 * 2)
+	DefaultLoadThreshold   = 2.0  // 코어당 로드 평균 경고 임계값 (실제 임계값 = 코어 수 * 2.0)
+	CriticalLoadPerCore    = 3.0  // 코어당 로드 평균 위험 임계값 (실제 임계값 = 코어 수 * 3.0)
 	DefaultTempThreshold   = 70.0 // CPU 온도 경고 임계값 (70°C 이상)
+	DefaultSwapThreshold   = 50.0 // 스왑 사용률 경고 임계값 (50% 이상)
+
+	// 메모리 압박 임계값 (Linux: PSI memory some avg60, macOS: 100 - 여유 메모리 비율)
+	DefaultMemoryPressureThreshold = 10.0
+
+	// PSI (Pressure Stall Information) 임계값 - some avg60 기준 지연 시간 비율 (%)
+	DefaultCPUPressureThreshold = 20.0
+	DefaultIOPressureThreshold  = 20.0
+
+	// 수신 대기 포트별 연결 수 급증 (최근 평균의 3배 이상이면서 100개 이상)
+	DefaultConnectionSpike = 3.0
+	DefaultConnectionMin   = 100.0
+
+	// 디스크 I/O / 네트워크 포화 임계값 (IOPS, MB/s, Mbps 절대값 임계값은 장비마다 달라 기본값 없음)
+	DefaultDiskBusyThreshold           = 90.0 // 디스크 사용 중 시간 비율 (%)
+	DefaultNetworkUtilizationThreshold = 80.0 // 링크 속도 대비 사용률 (%)
+	DefaultNetworkErrorThreshold       = 10.0 // 인터페이스 초당 에러+드롭
+
+	// 첫 CPU 사용률 측정 구간 (이후에는 수집 주기 사이의 변화량 사용)
+	CPUSampleWindow = 500 * time.Millisecond
+)
+
+// Memory pressure levels 메모리 압박 단계 (macOS kern.memorystatus_vm_pressure_level 기준)
+const (
+	MemoryPressureNormal   = "NORMAL"   // 정상
+	MemoryPressureWarn     = "WARN"     // 경고 (메모리 회수 진행 중)
+	MemoryPressureCritical = "CRITICAL" // 위험 (프로세스 강제 종료 가능)
 )
 
 // Log file paths by OS 운영체제별 기본 로그 파일 경로
@@ -158,4 +202,8 @@ const (
 	DefaultConfigDir  = ".syslog-monitor" // 설정 파일 디렉토리 (~/.syslog-monitor)
 	DefaultConfigFile = "config.json"     // 설정 파일명
 	ConfigPermissions = 0755              // 설정 디렉토리 권한 (rwxr-xr-x)
+	BootStateFile     = "boot_state.json" // 재부팅 감지 상태 파일명
+	EventDBFile       = "events.db"       // 알림/이벤트 히스토리 SQLite 파일명
+	TorExitCacheFile  = "tor_exit_nodes.txt" // Tor 출구 노드 목록 캐시 파일명
+	GeoCacheFile      = "geo_cache.json"     // IP 지리정보 조회 캐시 파일명
 ) 
\ No newline at end of file
@@ -0,0 +1,136 @@
+/*
+Container Mode Module
+=====================
+
+컨테이너 진입점 모드 (syslog-monitor container [옵션])
+
+최소 권한 컨테이너 이미지(Dockerfile)에서 읽기 전용 루트 파일시스템과 비root UID 로 실행할 수 있도록
+설정/상태 경로와 옵션 전달 방식을 컨테이너에 맞춥니다.
+
+주요 기능:
+- 설정 파일 기본 경로 /config/config.json (읽기 전용 볼륨, 없으면 기본값으로 시작하고 파일을 만들지 않음)
+- 상태 디렉토리 기본 경로 /data (SYSLOG_DATA_DIR, 쓸 수 없으면 경고 후 상태 파일 없이 동작)
+- 모든 옵션을 환경 변수로 지정: -flag-name → SYSLOG_FLAG_NAME (명령줄 옵션이 우선)
+- 서비스 설치/데몬 모드 거부 (프로세스 관리는 컨테이너 런타임이 담당)
+- 켜진 기능이 필요로 하는 외부 명령(sqlite3, journalctl 등)이 이미지에 없으면 시작 시 경고
+
+호스트 메트릭은 호스트의 /proc, /sys, / 를 마운트하고 HOST_PROC, HOST_SYS, HOST_ROOT 로 경로를 지정합니다
+(gopsutil 과 같은 환경 변수, 부팅 감지와 PSI/링크 속도/디스크 장치/디스크 사용량 조회에도 적용).
+*/
+package main
+
+import (
+	"flag"          // 옵션 목록
+	"fmt"           // 형식화된 I/O
+	"os"            // 환경 변수, 파일 시스템
+	"os/exec"       // 외부 명령 존재 확인 (실행하지 않음)
+	"path/filepath" // 경로 처리
+	"sort"          // 경고 정렬
+	"strings"       // 문자열 처리
+)
+
+// 컨테이너 모드 기본값
+const (
+	ContainerCommand    = "container"           // 컨테이너 진입점 하위 명령
+	ContainerConfigPath = "/config/config.json" // 설정 파일 기본 경로 (SYSLOG_CONFIG_PATH 로 변경)
+	ContainerDataDir    = "/data"               // 상태 디렉토리 기본 경로 (SYSLOG_DATA_DIR 로 변경)
+	DataDirEnv          = "SYSLOG_DATA_DIR"     // 상태 디렉토리 환경 변수 (컨테이너 모드가 아니어도 적용)
+	FlagEnvPrefix       = "SYSLOG_"             // 옵션 환경 변수 접두사
+)
+
+// containerMode 컨테이너 진입점 모드로 실행 중
+var containerMode bool
+
+// enterContainerMode 컨테이너 모드 시작 (설정 로드 전에 호출, 지정하지 않은 경로 환경 변수를 기본값으로)
+func enterContainerMode() {
+	containerMode = true
+	if os.Getenv("SYSLOG_CONFIG_PATH") == "" {
+		os.Setenv("SYSLOG_CONFIG_PATH", ContainerConfigPath)
+	}
+	if os.Getenv(DataDirEnv) == "" {
+		os.Setenv(DataDirEnv, ContainerDataDir)
+	}
+}
+
+// flagEnvName 옵션의 환경 변수 이름 (-report-schedule → SYSLOG_REPORT_SCHEDULE)
+func flagEnvName(name string) string {
+	return FlagEnvPrefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
+
+// applyFlagEnvironment 명령줄에서 지정하지 않은 옵션을 환경 변수 값으로 설정 (적용한 옵션 이름 반환)
+func applyFlagEnvironment(fs *flag.FlagSet) ([]string, error) {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	var applied []string
+	var failed error
+	fs.VisitAll(func(f *flag.Flag) {
+		if set[f.Name] || failed != nil {
+			return
+		}
+		value, ok := os.LookupEnv(flagEnvName(f.Name))
+		if !ok {
+			return
+		}
+		if err := fs.Set(f.Name, value); err != nil {
+			failed = fmt.Errorf("%s=%q: %v", flagEnvName(f.Name), value, err)
+			return
+		}
+		applied = append(applied, f.Name)
+	})
+	return applied, failed
+}
+
+// checkDataDirWritable 상태 디렉토리에 파일을 만들 수 있는지 확인
+func checkDataDirWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	probe, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// missingCommands 켜진 기능이 필요로 하는 외부 명령 중 PATH 에 없는 것 ("기능: 명령" 목록, 실행하지 않고 확인만)
+func missingCommands(required map[string]string) []string {
+	var missing []string
+	for feature, command := range required {
+		if _, err := exec.LookPath(command); err != nil {
+			missing = append(missing, fmt.Sprintf("%s: %s", feature, command))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// hostProcPath 호스트 /proc 아래 경로 (HOST_PROC 로 마운트 위치 변경, 컨테이너에서 호스트 메트릭 조회)
+func hostProcPath(elem ...string) string {
+	return hostPath("HOST_PROC", "/proc", elem...)
+}
+
+// hostSysPath 호스트 /sys 아래 경로 (HOST_SYS 로 마운트 위치 변경)
+func hostSysPath(elem ...string) string {
+	return hostPath("HOST_SYS", "/sys", elem...)
+}
+
+// hostRootPath 호스트 루트 파일시스템 아래 경로 (HOST_ROOT 로 마운트 위치 변경, 호스트 디스크 사용량 조회)
+// HOST_ROOT 가 없으면 경로를 그대로 반환 (Windows 드라이브 경로 포함)
+func hostRootPath(path string) string {
+	root := os.Getenv("HOST_ROOT")
+	if root == "" {
+		return path
+	}
+	return filepath.Join(root, path)
+}
+
+// hostPath 환경 변수로 바꿀 수 있는 호스트 경로
+func hostPath(env, fallback string, elem ...string) string {
+	root := os.Getenv(env)
+	if root == "" {
+		root = fallback
+	}
+	return filepath.Join(append([]string{root}, elem...)...)
+}
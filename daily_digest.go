@@ -0,0 +1,698 @@
+/*
+Daily Digest Module
+===================
+
+지난 24시간의 알림, 로그인 활동, 이상 패턴, 시스템 추이를 LLM 이 쓴 보고서 한 건으로 요약 (설정 파일 reports.digest)
+
+개별 알림은 사건마다 전송되므로 하루 동안 무슨 일이 있었는지 한눈에 보기 어렵습니다.
+요약 보고서는 시간별 집계를 모아 두었다가 정해진 시각에 한 번 LLM 으로 서술형 보고서를 만들어
+이메일/Slack 등 알림 채널로 보냅니다 (알림 유형 digest, 라우팅 규칙으로 채널 지정 가능).
+
+주요 기능:
+- 디스패처로 들어온 모든 알림 (중복 제한/점검 중 보류 전) 을 유형/심각도/제목별로 집계
+- 로그인 성공/실패/sudo 건수, 실패가 많은 계정과 출처 IP
+- 알림 임계값 이상 AI 이상 로그의 일치 규칙/서비스별 건수, 최고 이상 점수
+- 시스템 모니터 히스토리의 CPU/메모리/로드 평균/최대와 디스크 사용률 변화
+- schedule: 보고서 스케줄 형식 ("08:00 Asia/Seoul daily") 또는 cron 형식 ("0 8 * * *")
+- LLM 백엔드가 없거나 호출 한도에 걸리면 집계만으로 만든 기본 요약 전송
+- 집계는 시간별 버킷으로 메모리에만 보관 (재시작하면 처음부터)
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"sort"    // 상위 항목 정렬
+	"strings" // 문자열 처리
+	"sync"    // 동시성 제어
+	"time"    // 스케줄/집계 기간
+)
+
+// 일일 요약 기본값
+const (
+	DefaultDigestHours   = 24
+	DefaultDigestTop     = 10
+	MaxDigestHours       = 7 * 24 // 보관하는 최대 집계 기간
+	digestBucketKeyLimit = 500    // 버킷별 항목 수 (넘으면 digestOtherKey 로 합산)
+	digestOtherKey       = "(기타)"
+)
+
+// DigestConfig 일일 요약 설정
+type DigestConfig struct {
+	Schedule string `json:"schedule"` // 전송 스케줄 (보고서 스케줄 또는 cron 형식), 비우면 사용 안 함
+	Hours    int    `json:"hours"`    // 요약 기간 (시간, 0 이면 24)
+	Top      int    `json:"top"`      // 항목별 상위 개수 (0 이면 10)
+}
+
+// DigestCount 이름별 건수
+type DigestCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// DigestTrend 기간 동안의 메트릭 추이
+type DigestTrend struct {
+	Avg   float64 `json:"avg"`
+	Max   float64 `json:"max"`
+	First float64 `json:"first"`
+	Last  float64 `json:"last"`
+}
+
+// DigestDiskTrend 마운트 지점별 디스크 사용률 변화
+type DigestDiskTrend struct {
+	MountPoint string  `json:"mount_point"`
+	First      float64 `json:"first"`
+	Last       float64 `json:"last"`
+}
+
+// DigestSummary 요약 기간의 집계 결과
+type DigestSummary struct {
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+	Hosts []string  `json:"hosts,omitempty"` // 알림이 발생한 호스트
+
+	Alerts struct {
+		Total      int            `json:"total"`
+		BySeverity map[string]int `json:"by_severity"`
+		ByType     map[string]int `json:"by_type"`
+		Top        []DigestCount  `json:"top"`                   // "[심각도] 유형: 제목" 별 건수
+		PeakHour   time.Time      `json:"peak_hour,omitempty"`   // 알림이 가장 많았던 시간대
+		PeakAlerts int            `json:"peak_alerts,omitempty"` // 그 시간대의 알림 수
+	} `json:"alerts"`
+
+	Logins struct {
+		Success     int           `json:"success"`
+		Failed      int           `json:"failed"`
+		Sudo        int           `json:"sudo"`
+		Users       []DigestCount `json:"users"`        // 로그인에 성공한 계정
+		FailedUsers []DigestCount `json:"failed_users"` // 실패한 계정
+		FailedIPs   []DigestCount `json:"failed_ips"`   // 실패가 많은 출처 IP
+	} `json:"logins"`
+
+	Anomalies struct {
+		Count    int           `json:"count"` // 알림 임계값 이상 이상 로그 수
+		MaxScore float64       `json:"max_score"`
+		Rules    []DigestCount `json:"rules"`
+		Services []DigestCount `json:"services"` // "호스트/서비스" 별 건수
+	} `json:"anomalies"`
+
+	System *DigestSystemTrend `json:"system,omitempty"` // 시스템 모니터링을 사용하지 않으면 nil
+}
+
+// DigestSystemTrend 요약 기간의 시스템 메트릭 추이
+type DigestSystemTrend struct {
+	Samples int               `json:"samples"`
+	CPU     DigestTrend       `json:"cpu_percent"`
+	Memory  DigestTrend       `json:"memory_percent"`
+	Load    DigestTrend       `json:"load_per_core"`
+	Disks   []DigestDiskTrend `json:"disks"`
+}
+
+// digestBucket 한 시간 동안의 집계
+type digestBucket struct {
+	hour        time.Time
+	alerts      map[string]int // 심각도\x00유형\x00제목
+	hosts       map[string]int
+	loginOK     int
+	loginFailed int
+	sudo        int
+	users       map[string]int
+	failedUsers map[string]int
+	failedIPs   map[string]int
+	anomalies   int
+	maxScore    float64
+	rules       map[string]int
+	services    map[string]int
+}
+
+// newDigestBucket 빈 시간별 버킷
+func newDigestBucket(hour time.Time) *digestBucket {
+	return &digestBucket{
+		hour:        hour,
+		alerts:      make(map[string]int),
+		hosts:       make(map[string]int),
+		users:       make(map[string]int),
+		failedUsers: make(map[string]int),
+		failedIPs:   make(map[string]int),
+		rules:       make(map[string]int),
+		services:    make(map[string]int),
+	}
+}
+
+// countDigestKey 버킷 항목 건수 증가 (항목 수 한도를 넘으면 digestOtherKey 로 합산)
+func countDigestKey(counts map[string]int, key string) {
+	if _, exists := counts[key]; !exists && len(counts) >= digestBucketKeyLimit {
+		key = digestOtherKey
+	}
+	counts[key]++
+}
+
+// DailyDigest 일일 요약 집계와 전송 스케줄
+type DailyDigest struct {
+	config   DigestConfig
+	schedule *ReportSchedule // nil 이면 집계/전송하지 않음
+	buckets  []*digestBucket // 시간순 (최대 MaxDigestHours)
+	metrics  func() []SystemMetrics
+	dispatch func(Alert)
+	logger   Logger
+	reset    chan struct{} // 스케줄 변경 시 다음 전송 시각 다시 계산
+	stop     chan struct{}
+	once     sync.Once
+	mutex    sync.Mutex
+}
+
+// NewDailyDigest 새로운 일일 요약 생성 (SetConfig 로 스케줄을 정하기 전에는 집계하지 않음)
+func NewDailyDigest(dispatch func(Alert), logger Logger) *DailyDigest {
+	return &DailyDigest{
+		config:   DigestConfig{Hours: DefaultDigestHours, Top: DefaultDigestTop},
+		dispatch: dispatch,
+		logger:   logger,
+		reset:    make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+}
+
+// SetConfig 스케줄/기간/상위 개수 적용 (스케줄 오류면 기존 설정 유지, 빈 스케줄이면 사용 안 함)
+func (dd *DailyDigest) SetConfig(config DigestConfig) error {
+	var schedule *ReportSchedule
+	if strings.TrimSpace(config.Schedule) != "" {
+		parsed, err := ParseReportSchedule(config.Schedule)
+		if err != nil {
+			return err
+		}
+		schedule = parsed
+	}
+	if config.Hours < 0 || config.Hours > MaxDigestHours {
+		return fmt.Errorf("digest hours must be between 1 and %d", MaxDigestHours)
+	}
+	if config.Hours == 0 {
+		config.Hours = DefaultDigestHours
+	}
+	if config.Top < 0 {
+		return fmt.Errorf("digest top must not be negative")
+	}
+	if config.Top == 0 {
+		config.Top = DefaultDigestTop
+	}
+
+	dd.mutex.Lock()
+	dd.config = config
+	dd.schedule = schedule
+	if schedule == nil {
+		dd.buckets = nil
+	}
+	dd.mutex.Unlock()
+
+	select {
+	case dd.reset <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// SetMetricsSource 시스템 추이에 쓸 메트릭 히스토리 (시스템 모니터링 사용 시)
+func (dd *DailyDigest) SetMetricsSource(history func() []SystemMetrics) {
+	dd.mutex.Lock()
+	defer dd.mutex.Unlock()
+	dd.metrics = history
+}
+
+// Enabled 스케줄이 설정되어 집계/전송 중인지
+func (dd *DailyDigest) Enabled() bool {
+	if dd == nil {
+		return false
+	}
+	dd.mutex.Lock()
+	defer dd.mutex.Unlock()
+	return dd.schedule != nil
+}
+
+// Schedule 현재 전송 스케줄 (사용하지 않으면 nil)
+func (dd *DailyDigest) Schedule() *ReportSchedule {
+	dd.mutex.Lock()
+	defer dd.mutex.Unlock()
+	return dd.schedule
+}
+
+// bucket 시각이 속한 시간별 버킷 (호출자가 Lock 보유, 사용하지 않으면 nil)
+func (dd *DailyDigest) bucket(at time.Time) *digestBucket {
+	if dd.schedule == nil {
+		return nil
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	hour := at.Truncate(time.Hour)
+	for i := len(dd.buckets) - 1; i >= 0; i-- {
+		if dd.buckets[i].hour.Equal(hour) {
+			return dd.buckets[i]
+		}
+		if dd.buckets[i].hour.Before(hour) {
+			break
+		}
+	}
+
+	// 늦게 도착한 (이미 지난 시간대) 이벤트는 가장 최근 버킷에 합산
+	if n := len(dd.buckets); n > 0 && dd.buckets[n-1].hour.After(hour) {
+		return dd.buckets[n-1]
+	}
+	bucket := newDigestBucket(hour)
+	dd.buckets = append(dd.buckets, bucket)
+	cutoff := hour.Add(-MaxDigestHours * time.Hour)
+	for len(dd.buckets) > 0 && !dd.buckets[0].hour.After(cutoff) {
+		dd.buckets = dd.buckets[1:]
+	}
+	return bucket
+}
+
+// RecordAlert 디스패처로 들어온 알림 집계 (보고서/요약/테스트 알림 제외)
+func (dd *DailyDigest) RecordAlert(alert Alert) {
+	if dd == nil {
+		return
+	}
+	switch alert.Type {
+	case AlertTypeReport, AlertTypeDigest, AlertTypeTest:
+		return
+	}
+	dd.mutex.Lock()
+	defer dd.mutex.Unlock()
+	bucket := dd.bucket(alert.Timestamp)
+	if bucket == nil {
+		return
+	}
+	countDigestKey(bucket.alerts, alert.Severity+"\x00"+alert.Type+"\x00"+alert.Title)
+	if alert.Host != "" {
+		countDigestKey(bucket.hosts, alert.Host)
+	}
+}
+
+// RecordLogin 로그인 이벤트 집계 (알림 간격 제한과 무관하게 모든 이벤트)
+func (dd *DailyDigest) RecordLogin(info *LoginInfo) {
+	if dd == nil || info == nil {
+		return
+	}
+	dd.mutex.Lock()
+	defer dd.mutex.Unlock()
+	bucket := dd.bucket(info.Timestamp)
+	if bucket == nil {
+		return
+	}
+	switch {
+	case strings.HasPrefix(info.Status, "sudo"):
+		bucket.sudo++
+	case info.Success:
+		bucket.loginOK++
+		countDigestKey(bucket.users, info.User)
+	default:
+		bucket.loginFailed++
+		if info.User != "" {
+			countDigestKey(bucket.failedUsers, info.User)
+		}
+		if info.IP != "" {
+			countDigestKey(bucket.failedIPs, info.IP)
+		}
+	}
+}
+
+// RecordAnomaly 알림 임계값 이상 AI 이상 로그 집계
+func (dd *DailyDigest) RecordAnomaly(host, service string, result *AIAnalysisResult) {
+	if dd == nil || result == nil {
+		return
+	}
+	dd.mutex.Lock()
+	defer dd.mutex.Unlock()
+	bucket := dd.bucket(result.Timestamp)
+	if bucket == nil {
+		return
+	}
+	bucket.anomalies++
+	if result.AnomalyScore > bucket.maxScore {
+		bucket.maxScore = result.AnomalyScore
+	}
+	for _, rule := range result.MatchedRules {
+		countDigestKey(bucket.rules, rule.Name)
+	}
+	if service == "" {
+		service = "-"
+	}
+	countDigestKey(bucket.services, host+"/"+service)
+}
+
+// Summarize now 기준 요약 기간의 집계
+func (dd *DailyDigest) Summarize(now time.Time) *DigestSummary {
+	dd.mutex.Lock()
+	hours, top := dd.config.Hours, dd.config.Top
+	from := now.Add(-time.Duration(hours) * time.Hour)
+	var buckets []*digestBucket
+	for _, bucket := range dd.buckets {
+		if bucket.hour.Add(time.Hour).After(from) && !bucket.hour.After(now) {
+			buckets = append(buckets, bucket)
+		}
+	}
+
+	summary := &DigestSummary{From: from, To: now}
+	summary.Alerts.BySeverity = make(map[string]int)
+	summary.Alerts.ByType = make(map[string]int)
+	alerts, hosts := make(map[string]int), make(map[string]int)
+	users, failedUsers, failedIPs := make(map[string]int), make(map[string]int), make(map[string]int)
+	rules, services := make(map[string]int), make(map[string]int)
+	for _, bucket := range buckets {
+		hourAlerts := 0
+		for key, count := range bucket.alerts {
+			parts := strings.SplitN(key, "\x00", 3)
+			if len(parts) < 3 {
+				parts = []string{"", "", key} // digestOtherKey
+			}
+			severity, alertType, title := parts[0], parts[1], parts[2]
+			if severity != "" {
+				summary.Alerts.BySeverity[severity] += count
+			}
+			if alertType != "" {
+				summary.Alerts.ByType[alertType] += count
+			}
+			label := title
+			if alertType != "" {
+				label = fmt.Sprintf("[%s] %s: %s", strings.ToUpper(severity), alertType, title)
+			}
+			alerts[label] += count
+			hourAlerts += count
+		}
+		summary.Alerts.Total += hourAlerts
+		if hourAlerts > summary.Alerts.PeakAlerts {
+			summary.Alerts.PeakHour, summary.Alerts.PeakAlerts = bucket.hour, hourAlerts
+		}
+		mergeDigestCounts(hosts, bucket.hosts)
+
+		summary.Logins.Success += bucket.loginOK
+		summary.Logins.Failed += bucket.loginFailed
+		summary.Logins.Sudo += bucket.sudo
+		mergeDigestCounts(users, bucket.users)
+		mergeDigestCounts(failedUsers, bucket.failedUsers)
+		mergeDigestCounts(failedIPs, bucket.failedIPs)
+
+		summary.Anomalies.Count += bucket.anomalies
+		if bucket.maxScore > summary.Anomalies.MaxScore {
+			summary.Anomalies.MaxScore = bucket.maxScore
+		}
+		mergeDigestCounts(rules, bucket.rules)
+		mergeDigestCounts(services, bucket.services)
+	}
+	metrics := dd.metrics
+	dd.mutex.Unlock()
+
+	summary.Alerts.Top = topDigestCounts(alerts, top)
+	for _, host := range topDigestCounts(hosts, top) {
+		summary.Hosts = append(summary.Hosts, host.Name)
+	}
+	summary.Logins.Users = topDigestCounts(users, top)
+	summary.Logins.FailedUsers = topDigestCounts(failedUsers, top)
+	summary.Logins.FailedIPs = topDigestCounts(failedIPs, top)
+	summary.Anomalies.Rules = topDigestCounts(rules, top)
+	summary.Anomalies.Services = topDigestCounts(services, top)
+	if metrics != nil {
+		summary.System = digestSystemTrend(metrics(), from, now)
+	}
+	return summary
+}
+
+// mergeDigestCounts 버킷 건수를 합계에 더하기
+func mergeDigestCounts(total, counts map[string]int) {
+	for key, count := range counts {
+		total[key] += count
+	}
+}
+
+// topDigestCounts 건수 내림차순 (같으면 이름순) 상위 limit 개
+func topDigestCounts(counts map[string]int, limit int) []DigestCount {
+	list := make([]DigestCount, 0, len(counts))
+	for name, count := range counts {
+		list = append(list, DigestCount{Name: name, Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Name < list[j].Name
+	})
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
+
+// digestSystemTrend 기간 안의 메트릭 히스토리로 추이 계산 (표본이 없으면 nil)
+func digestSystemTrend(history []SystemMetrics, from, to time.Time) *DigestSystemTrend {
+	trend := &DigestSystemTrend{}
+	var cpu, memory, load []float64
+	disks := make(map[string]*DigestDiskTrend)
+	var mounts []string
+	for _, metrics := range history {
+		if metrics.Timestamp.Before(from) || metrics.Timestamp.After(to) {
+			continue
+		}
+		trend.Samples++
+		cpu = append(cpu, metrics.CPU.UsagePercent)
+		memory = append(memory, metrics.Memory.UsagePercent)
+		load = append(load, metrics.LoadAverage.Load1MinPerCore)
+		for _, disk := range metrics.Disk {
+			if existing, ok := disks[disk.MountPoint]; ok {
+				existing.Last = disk.UsagePercent
+				continue
+			}
+			disks[disk.MountPoint] = &DigestDiskTrend{MountPoint: disk.MountPoint, First: disk.UsagePercent, Last: disk.UsagePercent}
+			mounts = append(mounts, disk.MountPoint)
+		}
+	}
+	if trend.Samples == 0 {
+		return nil
+	}
+	trend.CPU, trend.Memory, trend.Load = newDigestTrend(cpu), newDigestTrend(memory), newDigestTrend(load)
+	for _, mount := range mounts {
+		trend.Disks = append(trend.Disks, *disks[mount])
+	}
+	return trend
+}
+
+// newDigestTrend 값 목록의 평균/최대/처음/마지막
+func newDigestTrend(values []float64) DigestTrend {
+	trend := DigestTrend{First: values[0], Last: values[len(values)-1]}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+		if value > trend.Max {
+			trend.Max = value
+		}
+	}
+	trend.Avg = sum / float64(len(values))
+	return trend
+}
+
+// Start 스케줄에 따라 요약 전송 시작
+func (dd *DailyDigest) Start() {
+	go dd.run()
+}
+
+// Stop 요약 전송 중지
+func (dd *DailyDigest) Stop() {
+	dd.once.Do(func() { close(dd.stop) })
+}
+
+// run 다음 전송 시각까지 기다렸다가 요약 전송 (스케줄이 바뀌면 다시 계산)
+func (dd *DailyDigest) run() {
+	for {
+		var fire <-chan time.Time
+		var timer *time.Timer
+		if schedule := dd.Schedule(); schedule != nil {
+			timer = time.NewTimer(time.Until(schedule.Next(time.Now())))
+			fire = timer.C
+		}
+
+		select {
+		case <-fire:
+			dd.Send(time.Now())
+		case <-dd.reset:
+		case <-dd.stop:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// Send 요약 기간을 LLM 으로 요약해 알림 채널로 전송 (LLM 을 쓸 수 없으면 기본 요약)
+func (dd *DailyDigest) Send(now time.Time) {
+	summary := dd.Summarize(now)
+
+	narrative, source := "", "기본 요약"
+	if provider := currentLLMProvider(); provider != nil {
+		text, err := provider.SummarizeDigest(summary)
+		if err != nil {
+			dd.logger.Errorf("LLM digest summary failed, sending basic digest: %v", err)
+		} else {
+			narrative, source = text, fmt.Sprintf("%s (%s)", provider.Name(), provider.Model())
+		}
+	}
+	if narrative == "" {
+		narrative = basicDigestNarrative(summary)
+	}
+
+	dd.dispatch(dailyDigestAlert(summary, narrative, source))
+	dd.logger.Infof("📰 Daily digest sent (%d alerts, %d logins, %d anomalies, summary: %s)",
+		summary.Alerts.Total, summary.Logins.Success+summary.Logins.Failed, summary.Anomalies.Count, source)
+}
+
+// dailyDigestAlert 일일 요약 알림 (요약 수준 채널에는 주요 건수와 AI 요약만)
+func dailyDigestAlert(summary *DigestSummary, narrative, source string) Alert {
+	period := fmt.Sprintf("%s ~ %s", summary.From.Format("01-02 15:04"), summary.To.Format("01-02 15:04"))
+	fields := []AlertField{
+		{Label: "🕐 기간", Value: period, Short: true},
+		{Label: "🚨 알림", Value: digestAlertCounts(summary), Short: true},
+		{Label: "🔐 로그인", Value: fmt.Sprintf("성공 %d, 실패 %d, sudo %d", summary.Logins.Success, summary.Logins.Failed, summary.Logins.Sudo), Short: true},
+		{Label: "🔍 이상 로그", Value: fmt.Sprintf("%d건 (최고 점수 %.1f)", summary.Anomalies.Count, summary.Anomalies.MaxScore), Short: true},
+		{Label: "🧠 요약", Value: source, Short: true},
+	}
+
+	return Alert{
+		Type:     AlertTypeDigest,
+		Severity: AlertSeverityInfo,
+		Title:    fmt.Sprintf("[%s] 📰 일일 요약 - %s", AppName, summary.To.Format("2006-01-02")),
+		Headline: fmt.Sprintf("📰 지난 %s 동안의 요약", formatDigestPeriod(summary.To.Sub(summary.From))),
+		Sections: []AlertSection{
+			{Fields: fields, Summary: true},
+			{Title: "🧠 AI 요약", Text: narrative, Summary: true},
+			{Title: "📋 집계", Text: summary.Facts(nil)},
+		},
+		Fields: map[string]string{
+			"alerts":    fmt.Sprintf("%d", summary.Alerts.Total),
+			"logins":    fmt.Sprintf("%d", summary.Logins.Success+summary.Logins.Failed),
+			"anomalies": fmt.Sprintf("%d", summary.Anomalies.Count),
+			"summary":   source,
+		},
+		Timestamp: summary.To,
+	}
+}
+
+// digestAlertCounts "12건 (critical 2, warning 10)" 형식
+func digestAlertCounts(summary *DigestSummary) string {
+	if summary.Alerts.Total == 0 {
+		return "0건"
+	}
+	return fmt.Sprintf("%d건 (%s)", summary.Alerts.Total, formatCountMap(summary.Alerts.BySeverity))
+}
+
+// formatDigestPeriod 요약 기간 표시 ("24시간", "7일")
+func formatDigestPeriod(period time.Duration) string {
+	hours := int(period.Round(time.Hour) / time.Hour)
+	if hours >= 48 && hours%24 == 0 {
+		return fmt.Sprintf("%d일", hours/24)
+	}
+	return fmt.Sprintf("%d시간", hours)
+}
+
+// Facts 요약 집계를 LLM 프롬프트/알림 본문용 텍스트로 (redactor 가 있으면 허용된 항목만, 식별 값은 자리표시자로)
+func (s *DigestSummary) Facts(redactor *PromptRedactor) string {
+	var facts strings.Builder
+	fmt.Fprintf(&facts, "기간: %s ~ %s (%s)\n", s.From.Format("2006-01-02 15:04"), s.To.Format("2006-01-02 15:04"), formatDigestPeriod(s.To.Sub(s.From)))
+	if len(s.Hosts) > 0 && redactor.Allows(AIFieldHostname) {
+		hosts := make([]string, len(s.Hosts))
+		for i, host := range s.Hosts {
+			hosts[i] = redactor.Hostname(host)
+		}
+		fmt.Fprintf(&facts, "알림 발생 호스트: %s\n", strings.Join(hosts, ", "))
+	}
+
+	if redactor.Allows(AIFieldDigestAlerts) {
+		fmt.Fprintf(&facts, "\n알림: %s\n", digestAlertCounts(s))
+		if len(s.Alerts.ByType) > 0 {
+			fmt.Fprintf(&facts, "- 유형별: %s\n", formatCountMap(s.Alerts.ByType))
+		}
+		if s.Alerts.PeakAlerts > 0 {
+			fmt.Fprintf(&facts, "- 가장 많았던 시간대: %s (%d건)\n", s.Alerts.PeakHour.Format("01-02 15:00"), s.Alerts.PeakAlerts)
+		}
+		if len(s.Alerts.Top) > 0 {
+			facts.WriteString("- 자주 발생한 알림:\n")
+			for _, alert := range s.Alerts.Top {
+				fmt.Fprintf(&facts, "  - %s (%d회)\n", redactor.Text(alert.Name), alert.Count)
+			}
+		}
+	}
+
+	if redactor.Allows(AIFieldDigestLogins) {
+		fmt.Fprintf(&facts, "\n로그인: 성공 %d, 실패 %d, sudo %d\n", s.Logins.Success, s.Logins.Failed, s.Logins.Sudo)
+		writeDigestCounts(&facts, "- 성공한 계정", s.Logins.Users, redactor.Username)
+		writeDigestCounts(&facts, "- 실패한 계정", s.Logins.FailedUsers, redactor.Username)
+		writeDigestCounts(&facts, "- 실패가 많은 출처 IP", s.Logins.FailedIPs, redactor.IP)
+	}
+
+	if redactor.Allows(AIFieldDigestAnomalies) {
+		fmt.Fprintf(&facts, "\n이상 로그: %d건 (최고 이상 점수 %.1f/%.0f)\n", s.Anomalies.Count, s.Anomalies.MaxScore, MaxAnomalyScore)
+		writeDigestCounts(&facts, "- 일치한 규칙", s.Anomalies.Rules, nil)
+		writeDigestCounts(&facts, "- 호스트/서비스", s.Anomalies.Services, func(name string) string {
+			host, service, _ := strings.Cut(name, "/")
+			return redactor.Hostname(host) + "/" + service
+		})
+	}
+
+	if s.System != nil && redactor.Allows(AIFieldDigestSystem) {
+		system := s.System
+		fmt.Fprintf(&facts, "\n시스템 추이 (표본 %d개, 평균/최대, 처음 → 마지막):\n", system.Samples)
+		fmt.Fprintf(&facts, "- CPU: %.1f%% / %.1f%%, %.1f%% → %.1f%%\n", system.CPU.Avg, system.CPU.Max, system.CPU.First, system.CPU.Last)
+		fmt.Fprintf(&facts, "- 메모리: %.1f%% / %.1f%%, %.1f%% → %.1f%%\n", system.Memory.Avg, system.Memory.Max, system.Memory.First, system.Memory.Last)
+		fmt.Fprintf(&facts, "- 코어당 로드: %.2f / %.2f, %.2f → %.2f\n", system.Load.Avg, system.Load.Max, system.Load.First, system.Load.Last)
+		for _, disk := range system.Disks {
+			fmt.Fprintf(&facts, "- 디스크 %s: %.1f%% → %.1f%%\n", disk.MountPoint, disk.First, disk.Last)
+		}
+	}
+	return facts.String()
+}
+
+// writeDigestCounts "- 제목: a 3회, b 1회" 한 줄 (목록이 비어 있으면 생략, mask 로 이름 가림)
+func writeDigestCounts(facts *strings.Builder, title string, counts []DigestCount, mask func(string) string) {
+	if len(counts) == 0 {
+		return
+	}
+	parts := make([]string, len(counts))
+	for i, count := range counts {
+		name := count.Name
+		if mask != nil && name != digestOtherKey {
+			name = mask(name)
+		}
+		parts[i] = fmt.Sprintf("%s %d회", name, count.Count)
+	}
+	fmt.Fprintf(facts, "%s: %s\n", title, strings.Join(parts, ", "))
+}
+
+// basicDigestNarrative LLM 없이 집계로 만든 기본 요약
+func basicDigestNarrative(s *DigestSummary) string {
+	var lines []string
+	switch critical := s.Alerts.BySeverity[AlertSeverityCritical]; {
+	case s.Alerts.Total == 0:
+		lines = append(lines, "📌 기간 동안 알림이 없었습니다.")
+	case critical > 0:
+		lines = append(lines, fmt.Sprintf("📌 알림 %d건 중 CRITICAL %d건이 발생했습니다.", s.Alerts.Total, critical))
+	default:
+		lines = append(lines, fmt.Sprintf("📌 알림 %d건이 발생했으며 CRITICAL 알림은 없었습니다.", s.Alerts.Total))
+	}
+	if len(s.Alerts.Top) > 0 {
+		lines = append(lines, fmt.Sprintf("🚨 가장 많이 발생한 알림: %s (%d회)", s.Alerts.Top[0].Name, s.Alerts.Top[0].Count))
+	}
+	if s.Logins.Failed > 0 && len(s.Logins.FailedIPs) > 0 {
+		lines = append(lines, fmt.Sprintf("🔐 로그인 실패 %d건, 가장 많은 출처: %s (%d회)", s.Logins.Failed, s.Logins.FailedIPs[0].Name, s.Logins.FailedIPs[0].Count))
+	}
+	if s.Anomalies.Count > 0 && len(s.Anomalies.Rules) > 0 {
+		lines = append(lines, fmt.Sprintf("🔍 이상 로그 %d건, 가장 많이 일치한 규칙: %s (%d회)", s.Anomalies.Count, s.Anomalies.Rules[0].Name, s.Anomalies.Rules[0].Count))
+	}
+	if s.System != nil {
+		lines = append(lines, fmt.Sprintf("📈 CPU 평균 %.1f%% (최대 %.1f%%), 메모리 평균 %.1f%% (최대 %.1f%%)", s.System.CPU.Avg, s.System.CPU.Max, s.System.Memory.Avg, s.System.Memory.Max))
+	}
+	lines = append(lines, "", "💡 AI 백엔드(Gemini/OpenAI/Anthropic/Ollama)와 -ai-analysis 를 설정하면 AI 가 작성한 요약을 받을 수 있습니다.")
+	return strings.Join(lines, "\n")
+}
+
+// equalDigestConfig 일일 요약 설정이 같은지 비교 (설정 재로드 시 바뀐 경우에만 적용)
+func equalDigestConfig(a, b DigestConfig) bool {
+	return a == b
+}
@@ -0,0 +1,313 @@
+/*
+Web Dashboard Module
+====================
+
+관리 API 서버에서 제공하는 내장 웹 대시보드 (-dashboard)
+
+주요 기능:
+- 단일 바이너리: HTML/JS/CSS 를 실행 파일에 포함 (go:embed)
+- 필터를 통과한 로그 실시간 tail (WebSocket, 새 연결에는 최근 로그 먼저 전송)
+- 현재 시스템 메트릭 및 추이 차트 (SystemMetrics 히스토리)
+- 최근 로그인 이벤트와 출발지 지도 (GeoMapper 위치/마커)
+- AI 이상 점수 추이
+
+경로:
+- GET /dashboard/              대시보드 페이지 (정적 파일, 인증 불필요)
+- GET /dashboard/api/metrics   현재 메트릭 + 추이
+- GET /dashboard/api/logins    최근 로그인 이벤트 + 지도 마커
+- GET /dashboard/api/anomalies AI 이상 점수 추이
+- GET /dashboard/ws/logs       실시간 로그 WebSocket
+*/
+package main
+
+import (
+	"embed"         // 대시보드 정적 파일 포함
+	"encoding/json" // WebSocket 메시지 인코딩
+	"io/fs"         // 포함된 파일 하위 디렉터리
+	"net/http"      // HTTP 핸들러
+	"sync"          // 버퍼/클라이언트 목록 보호
+	"time"          // 이벤트 시각
+)
+
+// 대시보드 설정
+const (
+	DefaultDashboardPort   = 8080 // -dashboard 만 지정했을 때 관리 API 포트
+	DashboardLogBacklog    = 200  // 새 WebSocket 연결에 먼저 보내는 최근 로그 수
+	DashboardLoginLimit    = 100  // 보관하는 최근 로그인 이벤트 수
+	DashboardAnomalyLimit  = 500  // 보관하는 AI 이상 점수 수
+	DashboardMetricsPoints = 288  // 메트릭 추이 차트 포인트 수 (5분 간격 24시간)
+	DashboardClientBuffer  = 256  // 클라이언트별 전송 대기 로그 수 (넘치면 해당 클라이언트 로그 누락)
+)
+
+//go:embed dashboard
+var dashboardAssets embed.FS
+
+// dashboardLogLine 실시간 로그 한 줄
+type dashboardLogLine struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Host    string    `json:"host"`
+	Service string    `json:"service"`
+	Message string    `json:"message"`
+}
+
+// dashboardLogin 최근 로그인 이벤트 (위치/마커는 조회 시 GeoMapper 로 채움)
+type dashboardLogin struct {
+	Time     time.Time        `json:"time"`
+	Host     string           `json:"host"`
+	User     string           `json:"user"`
+	IP       string           `json:"ip"`
+	Status   string           `json:"status"`
+	Method   string           `json:"method"`
+	Success  bool             `json:"success"`
+	Alerted  bool             `json:"alerted"`
+	Location *GeoLocationInfo `json:"location,omitempty"`
+	Marker   *MapMarker       `json:"marker,omitempty"`
+}
+
+// dashboardAnomaly AI 이상 점수 한 건
+type dashboardAnomaly struct {
+	Time        time.Time `json:"time"`
+	Score       float64   `json:"score"`
+	ThreatLevel string    `json:"threat_level"`
+	Confidence  float64   `json:"confidence"`
+	Alerted     bool      `json:"alerted"` // 알림 임계값 이상 여부
+}
+
+// dashboardMetricPoint 메트릭 추이 차트 포인트
+type dashboardMetricPoint struct {
+	Time        time.Time `json:"time"`
+	CPU         float64   `json:"cpu"`
+	Memory      float64   `json:"memory"`
+	LoadPerCore float64   `json:"load_per_core"`
+	CPUTemp     float64   `json:"cpu_temp"`
+}
+
+// dashboardClient 실시간 로그 WebSocket 클라이언트
+type dashboardClient struct {
+	conn *wsConn
+	send chan []byte
+}
+
+// Dashboard 웹 대시보드 상태 (최근 이벤트 버퍼와 WebSocket 클라이언트)
+type Dashboard struct {
+	monitor   *SyslogMonitor
+	logs      [][]byte // 인코딩된 최근 로그 (새 연결에 재전송)
+	logins    []dashboardLogin
+	anomalies []dashboardAnomaly
+	clients   map[*dashboardClient]struct{}
+	mutex     sync.Mutex
+}
+
+// NewDashboard 새로운 웹 대시보드 생성
+func NewDashboard(monitor *SyslogMonitor) *Dashboard {
+	return &Dashboard{
+		monitor: monitor,
+		clients: make(map[*dashboardClient]struct{}),
+	}
+}
+
+// registerDashboard 대시보드 경로를 관리 API 라우터에 등록
+// route 는 관리 API 의 토큰 인증 래퍼로, 요청 범위(운영자/테넌트)의 대시보드를 골라 핸들러에 전달
+// (정적 파일에는 인증을 적용하지 않음)
+func registerDashboard(mux *http.ServeMux, route func(handler func(*Dashboard, http.ResponseWriter, *http.Request)) http.HandlerFunc) {
+	assets, _ := fs.Sub(dashboardAssets, "dashboard")
+	mux.Handle("/dashboard/", http.StripPrefix("/dashboard/", http.FileServer(http.FS(assets))))
+	mux.HandleFunc("/dashboard/api/metrics", route((*Dashboard).handleMetrics))
+	mux.HandleFunc("/dashboard/api/logins", route((*Dashboard).handleLogins))
+	mux.HandleFunc("/dashboard/api/anomalies", route((*Dashboard).handleAnomalies))
+	mux.HandleFunc("/dashboard/ws/logs", route((*Dashboard).handleLogStream))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		http.Redirect(w, r, "/dashboard/", http.StatusFound)
+	})
+}
+
+// PublishLog 필터를 통과한 로그를 버퍼에 넣고 연결된 클라이언트로 전송
+func (d *Dashboard) PublishLog(parsed map[string]string, level string) {
+	message, err := json.Marshal(dashboardLogLine{
+		Time:    time.Now(),
+		Level:   level,
+		Host:    parsed["host"],
+		Service: parsed["service"],
+		Message: parsed["message"],
+	})
+	if err != nil {
+		return
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.logs = append(d.logs, message)
+	if len(d.logs) > DashboardLogBacklog {
+		d.logs = d.logs[len(d.logs)-DashboardLogBacklog:]
+	}
+	for client := range d.clients {
+		// 느린 클라이언트 때문에 로그 처리가 막히지 않도록 가득 차면 버림
+		select {
+		case client.send <- message:
+		default:
+		}
+	}
+}
+
+// RecordLogin 로그인 이벤트 기록
+func (d *Dashboard) RecordLogin(info *LoginInfo, host string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.logins = append(d.logins, dashboardLogin{
+		Time:    info.Timestamp,
+		Host:    host,
+		User:    info.User,
+		IP:      info.IP,
+		Status:  info.Status,
+		Method:  info.Method,
+		Success: info.Success,
+		Alerted: info.ShouldAlert,
+	})
+	if len(d.logins) > DashboardLoginLimit {
+		d.logins = d.logins[len(d.logins)-DashboardLoginLimit:]
+	}
+}
+
+// RecordAnomaly AI 분석 결과의 이상 점수 기록
+func (d *Dashboard) RecordAnomaly(result *AIAnalysisResult, threshold float64) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.anomalies = append(d.anomalies, dashboardAnomaly{
+		Time:        result.Timestamp,
+		Score:       result.AnomalyScore,
+		ThreatLevel: result.ThreatLevel,
+		Confidence:  result.Confidence,
+		Alerted:     result.AnomalyScore >= threshold,
+	})
+	if len(d.anomalies) > DashboardAnomalyLimit {
+		d.anomalies = d.anomalies[len(d.anomalies)-DashboardAnomalyLimit:]
+	}
+}
+
+// Close 연결된 WebSocket 클라이언트 모두 종료 (하이재킹한 연결은 HTTP 서버 종료로 닫히지 않음)
+func (d *Dashboard) Close() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for client := range d.clients {
+		client.conn.Close()
+	}
+}
+
+// handleMetrics GET /dashboard/api/metrics
+func (d *Dashboard) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	systemMonitor := d.monitor.systemMonitor
+	if systemMonitor == nil {
+		writeAPIJSON(w, http.StatusOK, map[string]interface{}{"enabled": false})
+		return
+	}
+
+	history := systemMonitor.GetMetricsHistory()
+	if len(history) > DashboardMetricsPoints {
+		history = history[len(history)-DashboardMetricsPoints:]
+	}
+	points := make([]dashboardMetricPoint, 0, len(history))
+	for _, metrics := range history {
+		points = append(points, dashboardMetricPoint{
+			Time:        metrics.Timestamp,
+			CPU:         metrics.CPU.UsagePercent,
+			Memory:      metrics.Memory.UsagePercent,
+			LoadPerCore: metrics.LoadAverage.Load1MinPerCore,
+			CPUTemp:     metrics.Temperature.CPUTemp,
+		})
+	}
+
+	writeAPIJSON(w, http.StatusOK, map[string]interface{}{
+		"enabled":    true,
+		"current":    systemMonitor.GetCurrentMetrics(),
+		"thresholds": systemMonitor.GetThresholds(),
+		"history":    points,
+	})
+}
+
+// handleLogins GET /dashboard/api/logins - 최근 로그인 (최신 순) 과 출발지 위치
+func (d *Dashboard) handleLogins(w http.ResponseWriter, r *http.Request) {
+	d.mutex.Lock()
+	logins := make([]dashboardLogin, len(d.logins))
+	for i, login := range d.logins {
+		logins[len(logins)-1-i] = login
+	}
+	d.mutex.Unlock()
+
+	// 위치 조회는 캐시 우선 일괄 조회 (로그 처리 고루틴을 막지 않도록 요청 시점에 수행)
+	var ips []string
+	seen := make(map[string]bool)
+	for _, login := range logins {
+		if login.IP != "" && !seen[login.IP] {
+			seen[login.IP] = true
+			ips = append(ips, login.IP)
+		}
+	}
+	locations := d.monitor.geoMapper.GetLocationInfoBatch(ips)
+	for i := range logins {
+		if location := locations[logins[i].IP]; location != nil {
+			logins[i].Location = location
+			logins[i].Marker = d.monitor.geoMapper.CreateMapMarker(location)
+		}
+	}
+
+	writeAPIJSON(w, http.StatusOK, map[string]interface{}{"count": len(logins), "logins": logins})
+}
+
+// handleAnomalies GET /dashboard/api/anomalies
+func (d *Dashboard) handleAnomalies(w http.ResponseWriter, r *http.Request) {
+	d.mutex.Lock()
+	anomalies := append([]dashboardAnomaly{}, d.anomalies...)
+	d.mutex.Unlock()
+
+	writeAPIJSON(w, http.StatusOK, map[string]interface{}{
+		"enabled": d.monitor.aiEnabled,
+		"points":  anomalies,
+	})
+}
+
+// handleLogStream GET /dashboard/ws/logs - 최근 로그를 보낸 뒤 새 로그를 실시간 전송
+func (d *Dashboard) handleLogStream(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	client := &dashboardClient{conn: conn, send: make(chan []byte, DashboardClientBuffer)}
+	d.mutex.Lock()
+	backlog := append([][]byte{}, d.logs...)
+	d.clients[client] = struct{}{}
+	d.mutex.Unlock()
+
+	defer func() {
+		d.mutex.Lock()
+		delete(d.clients, client)
+		d.mutex.Unlock()
+		// 목록에서 빠진 뒤에는 PublishLog 가 보내지 않으므로 닫아도 안전 (전송 고루틴 종료)
+		close(client.send)
+		conn.Close()
+	}()
+
+	go func() {
+		for _, message := range backlog {
+			if conn.WriteText(message) != nil {
+				conn.Close()
+				return
+			}
+		}
+		for message := range client.send {
+			if conn.WriteText(message) != nil {
+				conn.Close()
+				return
+			}
+		}
+	}()
+
+	// 클라이언트가 끊거나 close 를 보낼 때까지 ping 응답
+	conn.ReadLoop()
+}
@@ -0,0 +1,370 @@
+// AI-Powered Syslog Monitor 웹 대시보드
+// 관리 API 토큰은 URL 해시로 전달 (#token=SECRET, 서버 로그에 남지 않음)
+// 운영자 토큰으로 특정 테넌트를 볼 때는 /dashboard/?tenant=acme#token=ADMIN
+'use strict';
+
+const MAX_LOG_LINES = 1000;       // 화면에 유지하는 로그 줄 수
+const METRICS_REFRESH = 10000;    // 메트릭/이상 점수 갱신 주기 (ms)
+const LOGINS_REFRESH = 30000;     // 로그인 목록 갱신 주기 (ms)
+const RECONNECT_DELAY = 3000;     // WebSocket 재연결 대기 (ms)
+
+const token = (() => {
+	const match = location.hash.match(/token=([^&]+)/);
+	if (match) {
+		sessionStorage.setItem('syslog-monitor-token', decodeURIComponent(match[1]));
+		history.replaceState(null, '', location.pathname + location.search);
+	}
+	return sessionStorage.getItem('syslog-monitor-token') || '';
+})();
+
+const tenant = new URLSearchParams(location.search).get('tenant') || '';
+
+// scoped 테넌트 범위를 쿼리에 붙인 API 경로
+function scoped(path, params) {
+	const query = new URLSearchParams(params || {});
+	if (tenant) query.set('tenant', tenant);
+	const text = query.toString();
+	return text ? path + '?' + text : path;
+}
+
+const $ = (id) => document.getElementById(id);
+
+function el(tag, className, text) {
+	const node = document.createElement(tag);
+	if (className) node.className = className;
+	if (text !== undefined) node.textContent = text;
+	return node;
+}
+
+function setStatus(text, state) {
+	const status = $('status');
+	status.textContent = text;
+	status.className = 'status ' + (state || '');
+}
+
+async function api(path) {
+	const headers = token ? { Authorization: 'Bearer ' + token } : {};
+	const response = await fetch(scoped(path), { headers });
+	if (response.status === 401) {
+		setStatus('인증 필요 (#token=... 으로 접속)', 'down');
+		throw new Error('unauthorized');
+	}
+	if (!response.ok) throw new Error(path + ': ' + response.status);
+	return response.json();
+}
+
+function formatTime(value) {
+	const date = new Date(value);
+	return date.toLocaleTimeString('ko-KR', { hour12: false });
+}
+
+// ---------------------------------------------------------------- 실시간 로그
+
+function matchesSearch(node) {
+	const query = $('log-search').value.toLowerCase();
+	return !query || node.textContent.toLowerCase().includes(query);
+}
+
+function appendLog(entry) {
+	const logs = $('logs');
+	const line = el('div', 'log ' + entry.level);
+	line.append(
+		el('span', 'time', formatTime(entry.time) + ' '),
+		el('span', 'level', '[' + entry.level + '] '),
+		el('span', 'host', entry.host + ' '),
+		el('span', 'service', entry.service + ' '),
+		el('span', 'message', entry.message),
+	);
+	line.hidden = !matchesSearch(line);
+
+	const follow = logs.scrollTop + logs.clientHeight >= logs.scrollHeight - 5;
+	logs.append(line);
+	while (logs.childElementCount > MAX_LOG_LINES) logs.firstElementChild.remove();
+	if (follow && !$('log-pause').checked) logs.scrollTop = logs.scrollHeight;
+}
+
+function connectLogs() {
+	const protocol = location.protocol === 'https:' ? 'wss:' : 'ws:';
+	const socket = new WebSocket(protocol + '//' + location.host + scoped('/dashboard/ws/logs', token ? { token } : {}));
+	const paused = [];
+
+	socket.onopen = () => setStatus('● 실시간', 'live');
+	socket.onmessage = (event) => {
+		const entry = JSON.parse(event.data);
+		if ($('log-pause').checked) {
+			paused.push(entry);
+			return;
+		}
+		paused.splice(0).forEach(appendLog);
+		appendLog(entry);
+	};
+	socket.onclose = () => {
+		setStatus('연결 끊김 - 재연결 중...', 'down');
+		setTimeout(connectLogs, RECONNECT_DELAY);
+	};
+}
+
+$('log-search').addEventListener('input', () => {
+	for (const line of $('logs').children) line.hidden = !matchesSearch(line);
+});
+$('log-clear').addEventListener('click', () => $('logs').replaceChildren());
+
+// ---------------------------------------------------------------- 차트
+
+// drawChart 시계열 선 그래프 (series: [{label, color, points: [{time, value, mark}]}])
+function drawChart(canvas, max, series, thresholds) {
+	const ratio = window.devicePixelRatio || 1;
+	const width = canvas.clientWidth;
+	const height = canvas.clientHeight;
+	canvas.width = width * ratio;
+	canvas.height = height * ratio;
+	const ctx = canvas.getContext('2d');
+	ctx.scale(ratio, ratio);
+	ctx.clearRect(0, 0, width, height);
+
+	const pad = { left: 34, right: 8, top: 8, bottom: 20 };
+	const plotW = width - pad.left - pad.right;
+	const plotH = height - pad.top - pad.bottom;
+	const all = series.flatMap((s) => s.points);
+	if (all.length === 0) {
+		ctx.fillStyle = '#6e7681';
+		ctx.font = '13px sans-serif';
+		ctx.fillText('데이터 없음', width / 2 - 30, height / 2);
+		return;
+	}
+	const times = all.map((p) => new Date(p.time).getTime());
+	const minT = Math.min(...times);
+	const maxT = Math.max(...times, minT + 1);
+	const x = (time) => pad.left + ((new Date(time).getTime() - minT) / (maxT - minT)) * plotW;
+	const y = (value) => pad.top + plotH - (Math.min(value, max) / max) * plotH;
+
+	// 눈금
+	ctx.strokeStyle = '#21262d';
+	ctx.fillStyle = '#6e7681';
+	ctx.font = '10px sans-serif';
+	for (let i = 0; i <= 4; i++) {
+		const value = (max / 4) * i;
+		ctx.beginPath();
+		ctx.moveTo(pad.left, y(value));
+		ctx.lineTo(width - pad.right, y(value));
+		ctx.stroke();
+		ctx.fillText(String(Math.round(value * 10) / 10), 4, y(value) + 3);
+	}
+	ctx.fillText(formatTime(minT), pad.left, height - 5);
+	ctx.fillText(formatTime(maxT), width - pad.right - 48, height - 5);
+
+	// 임계값 (점선)
+	ctx.setLineDash([4, 4]);
+	for (const threshold of thresholds || []) {
+		ctx.strokeStyle = threshold.color;
+		ctx.beginPath();
+		ctx.moveTo(pad.left, y(threshold.value));
+		ctx.lineTo(width - pad.right, y(threshold.value));
+		ctx.stroke();
+	}
+	ctx.setLineDash([]);
+
+	for (const s of series) {
+		ctx.strokeStyle = s.color;
+		ctx.lineWidth = 1.5;
+		ctx.beginPath();
+		s.points.forEach((p, i) => (i === 0 ? ctx.moveTo(x(p.time), y(p.value)) : ctx.lineTo(x(p.time), y(p.value))));
+		ctx.stroke();
+		for (const p of s.points.filter((p) => p.mark)) {
+			ctx.fillStyle = '#ff4d4d';
+			ctx.beginPath();
+			ctx.arc(x(p.time), y(p.value), 3, 0, Math.PI * 2);
+			ctx.fill();
+		}
+	}
+}
+
+function legend(target, items) {
+	$(target).replaceChildren(...items.map(([color, text]) => {
+		const item = el('span', '', '■ ' + text);
+		item.style.color = color;
+		return item;
+	}));
+}
+
+// ---------------------------------------------------------------- 시스템 메트릭
+
+function gauge(label, value, unit, warn) {
+	const box = el('div', 'gauge' + (warn ? ' warn' : ''));
+	box.append(el('div', 'value', value + unit), el('div', 'label', label));
+	return box;
+}
+
+async function refreshMetrics() {
+	const data = await api('/dashboard/api/metrics');
+	if (!data.enabled) {
+		$('metrics-current').replaceChildren(el('div', 'empty', '시스템 모니터링 비활성화 (-system-monitor)'));
+		return;
+	}
+	const current = data.current;
+	const limits = data.thresholds;
+	$('metrics-current').replaceChildren(
+		gauge('CPU', current.cpu.usage_percent.toFixed(1), '%', current.cpu.usage_percent >= limits.cpu_percent),
+		gauge('메모리', current.memory.usage_percent.toFixed(1), '%', current.memory.usage_percent >= limits.memory_percent),
+		gauge('로드/코어', current.load_average.load_1min_per_core.toFixed(2), '', current.load_average.load_1min_per_core >= limits.load_per_core),
+		gauge('CPU 온도', current.temperature.cpu_temp ? current.temperature.cpu_temp.toFixed(0) : '-', current.temperature.cpu_temp ? '°C' : '', current.temperature.cpu_temp >= limits.cpu_temp),
+	);
+
+	const history = data.history;
+	drawChart($('metrics-chart'), 100, [
+		{ color: '#58a6ff', points: history.map((p) => ({ time: p.time, value: p.cpu })) },
+		{ color: '#d2a8ff', points: history.map((p) => ({ time: p.time, value: p.memory })) },
+		{ color: '#e3b341', points: history.map((p) => ({ time: p.time, value: p.load_per_core * 100 })) },
+	], [
+		{ value: limits.cpu_percent, color: '#58a6ff88' },
+		{ value: limits.memory_percent, color: '#d2a8ff88' },
+	]);
+	legend('metrics-legend', [['#58a6ff', 'CPU %'], ['#d2a8ff', '메모리 %'], ['#e3b341', '로드/코어 ×100'], ['#6e7681', '점선: 임계값']]);
+
+	$('disks').replaceChildren(...(current.disk || []).map((disk) => {
+		const row = el('div', 'disk');
+		const bar = el('div', 'bar');
+		const fill = el('div', 'fill' + (disk.usage_percent >= limits.disk_percent ? ' warn' : ''));
+		fill.style.width = Math.min(disk.usage_percent, 100) + '%';
+		bar.append(fill);
+		row.append(el('span', '', disk.mount_point), bar, el('span', '', disk.usage_percent.toFixed(1) + '%'));
+		return row;
+	}));
+}
+
+// ---------------------------------------------------------------- AI 이상 점수
+
+async function refreshAnomalies() {
+	const data = await api('/dashboard/api/anomalies');
+	if (!data.enabled) {
+		$('anomaly-summary').textContent = 'AI 분석 비활성화 (-ai-analysis)';
+		drawChart($('anomaly-chart'), 10, [], []);
+		return;
+	}
+	const points = data.points.map((p) => ({ time: p.time, value: p.score, mark: p.alerted }));
+	drawChart($('anomaly-chart'), 10, [{ color: '#ff7b72', points }], []);
+
+	const alerted = data.points.filter((p) => p.alerted).length;
+	const last = data.points[data.points.length - 1];
+	$('anomaly-summary').textContent = last
+		? `최근 점수 ${last.score.toFixed(1)} (${last.threat_level}) · 분석 ${data.points.length}건 · 알림 ${alerted}건 (빨간 점)`
+		: '아직 분석된 로그가 없습니다';
+}
+
+// ---------------------------------------------------------------- 로그인 / 지도
+
+const map = {
+	leaflet: null,
+	layer: null,
+};
+
+function locationText(login) {
+	const location = login.location;
+	if (!location) return '-';
+	return [location.city, location.country].filter(Boolean).join(', ');
+}
+
+function markerPopup(login) {
+	const popup = el('div');
+	popup.append(
+		el('strong', '', login.marker.title),
+		el('div', '', login.user + ' · ' + login.status),
+		el('div', '', locationText(login)),
+		el('div', '', login.location.organization || ''),
+		el('div', '', '위험도: ' + login.marker.threat),
+	);
+	return popup;
+}
+
+// drawFallbackMap 지도 라이브러리를 불러오지 못했을 때 위경도 격자에 마커 표시
+function drawFallbackMap(logins) {
+	let canvas = $('map').querySelector('canvas');
+	if (!canvas) {
+		canvas = el('canvas');
+		canvas.style.height = '100%';
+		$('map').append(canvas);
+	}
+	const ratio = window.devicePixelRatio || 1;
+	const width = canvas.clientWidth;
+	const height = canvas.clientHeight;
+	canvas.width = width * ratio;
+	canvas.height = height * ratio;
+	const ctx = canvas.getContext('2d');
+	ctx.scale(ratio, ratio);
+	ctx.strokeStyle = '#21262d';
+	for (let lng = -180; lng <= 180; lng += 30) {
+		const x = ((lng + 180) / 360) * width;
+		ctx.beginPath(); ctx.moveTo(x, 0); ctx.lineTo(x, height); ctx.stroke();
+	}
+	for (let lat = -90; lat <= 90; lat += 30) {
+		const y = ((90 - lat) / 180) * height;
+		ctx.beginPath(); ctx.moveTo(0, y); ctx.lineTo(width, y); ctx.stroke();
+	}
+	for (const login of logins) {
+		ctx.fillStyle = login.marker.color;
+		ctx.beginPath();
+		ctx.arc(((login.marker.lng + 180) / 360) * width, ((90 - login.marker.lat) / 180) * height, 5, 0, Math.PI * 2);
+		ctx.fill();
+	}
+}
+
+function drawMap(logins) {
+	const located = logins.filter((login) => login.marker);
+	if (!window.L) {
+		drawFallbackMap(located);
+		return;
+	}
+	if (!map.leaflet) {
+		map.leaflet = L.map('map', { worldCopyJump: true }).setView([20, 0], 2);
+		L.tileLayer('https://{s}.tile.openstreetmap.org/{z}/{x}/{y}.png', {
+			maxZoom: 10,
+			attribution: '© OpenStreetMap',
+		}).addTo(map.leaflet);
+		map.layer = L.layerGroup().addTo(map.leaflet);
+	}
+	map.layer.clearLayers();
+	for (const login of located) {
+		L.circleMarker([login.marker.lat, login.marker.lng], {
+			radius: 7,
+			color: login.marker.color,
+			fillOpacity: 0.7,
+		}).bindPopup(markerPopup(login)).addTo(map.layer);
+	}
+}
+
+async function refreshLogins() {
+	const data = await api('/dashboard/api/logins');
+	const rows = data.logins.map((login) => {
+		const row = el('tr');
+		row.append(
+			el('td', '', formatTime(login.time)),
+			el('td', '', login.user),
+			el('td', '', login.ip || '-'),
+			el('td', '', locationText(login)),
+			el('td', login.success ? 'ok' : 'fail', login.status + (login.alerted ? ' 🔔' : '')),
+		);
+		return row;
+	});
+	if (rows.length === 0) {
+		const row = el('tr');
+		const cell = el('td', 'empty', '감지된 로그인이 없습니다 (-login-watch)');
+		cell.colSpan = 5;
+		row.append(cell);
+		rows.push(row);
+	}
+	$('logins').replaceChildren(...rows);
+	drawMap(data.logins);
+}
+
+// ---------------------------------------------------------------- 시작
+
+function every(interval, refresh) {
+	const run = () => refresh().catch((err) => console.warn(err));
+	run();
+	setInterval(run, interval);
+}
+
+connectLogs();
+every(METRICS_REFRESH, refreshMetrics);
+every(METRICS_REFRESH, refreshAnomalies);
+every(LOGINS_REFRESH, refreshLogins);
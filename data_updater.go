@@ -0,0 +1,644 @@
+/*
+Data Updater Module
+===================
+
+탐지 규칙 팩, Tor 출구 노드 목록, MaxMind GeoIP DB 자동 업데이트 (설정 파일 data_updates)
+
+배포된 데이터 파일을 주기적으로 내려받아 체크섬/서명을 확인한 뒤 임시 파일에서 먼저 파싱해 보고,
+문제가 없을 때만 기존 파일과 교체합니다. 교체 후 실행 중인 모니터에 적용하다 실패하면 이전 파일로
+되돌리고, 결과는 정기 보고서의 데이터 업데이트 섹션에 표시합니다.
+
+주요 기능:
+- 종류: rules (-rules 이상 패턴 규칙 파일), tor (Tor 출구 노드 목록), geoip (-geoip-db 의 .mmdb 파일)
+- 검증: checksum_url 의 SHA-256 ("<hex>  <파일명>" 형식, MaxMind .sha256 등) 과 Ed25519 서명 (signature_url + public_key), 최소 하나 필수
+- gzip 과 tar.gz 는 압축을 풀어 대상 파일 이름 (geoip 는 첫 .mmdb) 항목 사용
+- 단계적 적용: 임시 파일에 내려받기 → 검증 → 파싱 확인 → 기존 파일을 <파일>.previous 로 보관 후 교체 → 모니터에 적용
+- 적용 실패 시 <파일>.previous 로 되돌리고 이전 데이터를 다시 적용
+- 체크섬이 마지막으로 적용한 버전과 같으면 내려받지 않음 (상태는 ~/.syslog-monitor/data_updates.json)
+*/
+package main
+
+import (
+	"archive/tar"     // tar.gz 압축 해제
+	"bufio"           // 압축 형식 확인
+	"bytes"           // 서명 파일 처리
+	"compress/gzip"   // gzip 압축 해제
+	"crypto/ed25519"  // 서명 검증
+	"crypto/sha256"   // 체크섬 검증
+	"encoding/base64" // 공개 키/서명 디코딩
+	"encoding/hex"    // 체크섬 인코딩
+	"encoding/json"   // 상태 파일
+	"fmt"             // 형식화된 I/O
+	"io"              // 스트림 복사
+	"net/http"        // 다운로드
+	"net/url"         // URL 검증
+	"os"              // 파일 처리
+	"path/filepath"   // 경로 처리
+	"strings"         // 문자열 처리
+	"sync"            // 동기화
+	"time"            // 확인 주기
+)
+
+// 데이터 업데이트 설정
+const (
+	DataUpdateRules         = "rules"             // 이상 패턴 규칙 파일
+	DataUpdateTor           = "tor"               // Tor 출구 노드 목록
+	DataUpdateGeoIP         = "geoip"             // MaxMind DB 파일
+	DefaultDataUpdateHours  = 24                  // 기본 확인 주기 (시간)
+	DataUpdateRetry         = 30 * time.Minute    // 실패 후 재시도 간격
+	DataUpdateMaxSize       = 512 << 20           // 내려받을 파일 최대 크기
+	DataUpdateStateFile     = "data_updates.json" // 마지막으로 적용한 버전 상태 파일
+	dataUpdateCheckInterval = time.Minute         // 확인할 항목이 있는지 살펴보는 간격
+)
+
+// 데이터 업데이트 결과
+const (
+	DataUpdateUpdated    = "updated"     // 새 버전 적용
+	DataUpdateUnchanged  = "unchanged"   // 이미 최신 버전
+	DataUpdateFailed     = "failed"      // 다운로드/검증/파싱 실패 (기존 파일 유지)
+	DataUpdateRolledBack = "rolled_back" // 적용 실패로 이전 파일 복원
+)
+
+// DataUpdateSource 자동 업데이트할 데이터 파일 (설정 파일 data_updates 항목)
+type DataUpdateSource struct {
+	Name         string `json:"name"`                     // 표시 이름 (비어 있으면 종류:파일 이름)
+	Kind         string `json:"kind"`                     // rules, tor, geoip
+	URL          string `json:"url"`                      // 다운로드 URL (http/https)
+	Path         string `json:"path,omitempty"`           // 교체할 로컬 파일 (비어 있으면 종류별 기본 파일)
+	ChecksumURL  string `json:"checksum_url,omitempty"`   // SHA-256 체크섬 파일 URL
+	SignatureURL string `json:"signature_url,omitempty"`  // 내려받은 파일의 Ed25519 서명 URL (64바이트 원본 또는 base64)
+	PublicKey    string `json:"public_key,omitempty"`     // Ed25519 공개 키 (base64)
+	Interval     int    `json:"interval_hours,omitempty"` // 확인 주기 (시간, 기본 24)
+}
+
+// DataUpdateStatus 데이터 파일 업데이트 상태 (정기 보고서)
+type DataUpdateStatus struct {
+	Name    string    `json:"name"`
+	Kind    string    `json:"kind"`
+	Path    string    `json:"path"`
+	Result  string    `json:"result,omitempty"`  // 마지막 확인 결과 (아직 확인하지 않았으면 빈 문자열)
+	Error   string    `json:"error,omitempty"`   // 실패/복원 원인
+	Version string    `json:"version,omitempty"` // 적용 중인 버전 (SHA-256 앞 12자리)
+	Checked time.Time `json:"checked,omitempty"` // 마지막 확인 시각
+	Updated time.Time `json:"updated,omitempty"` // 마지막으로 새 버전을 적용한 시각
+}
+
+// dataUpdateHandler 종류별 파싱 확인/적용 함수
+type dataUpdateHandler struct {
+	validate func(path string) error // 교체 전 임시 파일 파싱 확인
+	apply    func(path string) error // 교체한 파일을 실행 중인 모니터에 적용
+}
+
+// dataUpdateState 마지막으로 적용한 버전 (재시작 후 같은 버전을 다시 받지 않기 위함)
+type dataUpdateState struct {
+	Checksum string    `json:"checksum"`
+	Updated  time.Time `json:"updated"`
+}
+
+// dataUpdateEntry 업데이트 항목과 상태
+type dataUpdateEntry struct {
+	source    DataUpdateSource
+	publicKey ed25519.PublicKey
+	interval  time.Duration
+	status    DataUpdateStatus
+	next      time.Time
+}
+
+// DataUpdater 데이터 파일 자동 업데이트
+type DataUpdater struct {
+	entries   []*dataUpdateEntry
+	handlers  map[string]dataUpdateHandler
+	statePath string
+	client    *http.Client
+	logger    Logger
+
+	mutex   sync.Mutex // 상태 보호
+	state   map[string]dataUpdateState
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	started bool
+}
+
+// NewDataUpdater 설정 검증 후 업데이트 서비스 생성 (경로 기본값은 Handle 에서 채움)
+func NewDataUpdater(sources []DataUpdateSource, logger Logger) (*DataUpdater, error) {
+	du := &DataUpdater{
+		handlers:  make(map[string]dataUpdateHandler),
+		statePath: filepath.Join(getDataDir(), DataUpdateStateFile),
+		client:    &http.Client{Timeout: 10 * time.Minute},
+		logger:    logger,
+		state:     make(map[string]dataUpdateState),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	names := make(map[string]bool)
+	for i, source := range sources {
+		switch source.Kind {
+		case DataUpdateRules, DataUpdateTor, DataUpdateGeoIP:
+		default:
+			return nil, fmt.Errorf("data update #%d: unknown kind %q (use %s, %s or %s)", i+1, source.Kind, DataUpdateRules, DataUpdateTor, DataUpdateGeoIP)
+		}
+		for j, raw := range []string{source.URL, source.ChecksumURL, source.SignatureURL} {
+			if raw == "" && j > 0 {
+				continue
+			}
+			if parsed, err := url.Parse(raw); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return nil, fmt.Errorf("data update #%d: invalid url %q", i+1, raw)
+			}
+		}
+		if source.ChecksumURL == "" && source.SignatureURL == "" {
+			return nil, fmt.Errorf("data update #%d: checksum_url or signature_url is required", i+1)
+		}
+
+		entry := &dataUpdateEntry{source: source, interval: DefaultDataUpdateHours * time.Hour}
+		if source.SignatureURL != "" {
+			key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(source.PublicKey))
+			if err != nil || len(key) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("data update #%d: public_key must be a base64 Ed25519 public key", i+1)
+			}
+			entry.publicKey = ed25519.PublicKey(key)
+		}
+		if source.Interval > 0 {
+			entry.interval = time.Duration(source.Interval) * time.Hour
+		}
+		if source.Path != "" {
+			entry.source.Path = expandHomePath(source.Path)
+		}
+		du.entries = append(du.entries, entry)
+		if source.Name != "" {
+			if names[source.Name] {
+				return nil, fmt.Errorf("data update #%d: duplicate name %q", i+1, source.Name)
+			}
+			names[source.Name] = true
+		}
+	}
+
+	if data, err := os.ReadFile(du.statePath); err == nil {
+		if err := json.Unmarshal(data, &du.state); err != nil {
+			logger.Errorf("Failed to parse data update state, checking all sources: %v", err)
+			du.state = make(map[string]dataUpdateState)
+		}
+	}
+	return du, nil
+}
+
+// Handle 종류별 기본 경로와 파싱 확인/적용 함수 등록 (Start 전에 호출)
+func (du *DataUpdater) Handle(kind, defaultPath string, validate, apply func(path string) error) {
+	du.handlers[kind] = dataUpdateHandler{validate: validate, apply: apply}
+	for _, entry := range du.entries {
+		if entry.source.Kind == kind && entry.source.Path == "" {
+			entry.source.Path = defaultPath
+		}
+	}
+}
+
+// Kinds 설정된 업데이트 종류 목록 (중복 제거)
+func (du *DataUpdater) Kinds() []string {
+	var kinds []string
+	seen := make(map[string]bool)
+	for _, entry := range du.entries {
+		if !seen[entry.source.Kind] {
+			seen[entry.source.Kind] = true
+			kinds = append(kinds, entry.source.Kind)
+		}
+	}
+	return kinds
+}
+
+// Check 항목마다 처리 함수와 교체할 경로가 있는지 확인 (Handle 등록 후, Start 전에 호출)
+func (du *DataUpdater) Check() error {
+	for _, entry := range du.entries {
+		if _, ok := du.handlers[entry.source.Kind]; !ok {
+			return fmt.Errorf("data update %s: %s updates are not available in this mode", entry.source.URL, entry.source.Kind)
+		}
+		if entry.source.Path == "" {
+			return fmt.Errorf("data update %s: path is required for %s updates", entry.source.URL, entry.source.Kind)
+		}
+		if entry.source.Name == "" {
+			entry.source.Name = entry.source.Kind + ":" + filepath.Base(entry.source.Path)
+		}
+		entry.status = DataUpdateStatus{Name: entry.source.Name, Kind: entry.source.Kind, Path: entry.source.Path}
+		if state, ok := du.state[entry.source.Name]; ok {
+			entry.status.Version = shortChecksum(state.Checksum)
+			entry.status.Updated = state.Updated
+			// 재시작 직후에는 마지막 적용 시각 기준으로 다음 확인 시각 계산
+			entry.next = state.Updated.Add(entry.interval)
+		}
+	}
+	return nil
+}
+
+// Start 백그라운드 업데이트 시작 (확인 시각이 지난 항목은 바로 확인)
+func (du *DataUpdater) Start() {
+	du.started = true
+	go du.run()
+}
+
+// Stop 백그라운드 업데이트 중단 (진행 중인 업데이트는 끝날 때까지 대기)
+func (du *DataUpdater) Stop() {
+	du.once.Do(func() {
+		close(du.stop)
+		if du.started {
+			<-du.done
+		}
+	})
+}
+
+// Status 항목별 업데이트 상태 (설정 순서)
+func (du *DataUpdater) Status() []DataUpdateStatus {
+	du.mutex.Lock()
+	defer du.mutex.Unlock()
+	statuses := make([]DataUpdateStatus, len(du.entries))
+	for i, entry := range du.entries {
+		statuses[i] = entry.status
+	}
+	return statuses
+}
+
+// run 확인 시각이 된 항목을 차례로 업데이트
+func (du *DataUpdater) run() {
+	defer close(du.done)
+	ticker := time.NewTicker(dataUpdateCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, entry := range du.entries {
+			select {
+			case <-du.stop:
+				return
+			default:
+			}
+			if time.Now().Before(entry.next) {
+				continue
+			}
+			du.update(entry)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-du.stop:
+			return
+		}
+	}
+}
+
+// update 항목 하나 확인/교체/적용 후 상태 기록
+func (du *DataUpdater) update(entry *dataUpdateEntry) {
+	result, checksum, err := du.fetchAndApply(entry)
+
+	du.mutex.Lock()
+	now := time.Now()
+	entry.status.Checked = now
+	entry.status.Result = result
+	entry.status.Error = ""
+	if err != nil {
+		entry.status.Error = err.Error()
+	}
+	entry.next = now.Add(entry.interval)
+	switch result {
+	case DataUpdateUpdated:
+		entry.status.Version = shortChecksum(checksum)
+		entry.status.Updated = now
+		du.state[entry.source.Name] = dataUpdateState{Checksum: checksum, Updated: now}
+	case DataUpdateFailed, DataUpdateRolledBack:
+		entry.next = now.Add(DataUpdateRetry)
+	}
+	state := make(map[string]dataUpdateState, len(du.state))
+	for name, value := range du.state {
+		state[name] = value
+	}
+	du.mutex.Unlock()
+
+	switch result {
+	case DataUpdateUpdated:
+		du.logger.Infof("📦 Data update %s applied: %s (sha256 %s)", entry.source.Name, entry.source.Path, shortChecksum(checksum))
+		du.saveState(state)
+	case DataUpdateFailed:
+		du.logger.Errorf("❌ Data update %s failed, keeping current file: %v", entry.source.Name, err)
+	case DataUpdateRolledBack:
+		du.logger.Errorf("❌ Data update %s could not be applied, rolled back to previous file: %v", entry.source.Name, err)
+	}
+}
+
+// fetchAndApply 새 버전을 내려받아 검증/파싱 확인 후 교체하고 적용 (결과, 체크섬, 에러)
+func (du *DataUpdater) fetchAndApply(entry *dataUpdateEntry) (string, string, error) {
+	source := entry.source
+	handler := du.handlers[source.Kind]
+
+	du.mutex.Lock()
+	current := du.state[source.Name].Checksum
+	du.mutex.Unlock()
+	_, statErr := os.Stat(source.Path)
+
+	// 체크섬 파일만 먼저 받아 이미 적용한 버전이면 내려받지 않음
+	expected := ""
+	if source.ChecksumURL != "" {
+		body, err := du.get(source.ChecksumURL, 64<<10)
+		if err != nil {
+			return DataUpdateFailed, "", fmt.Errorf("checksum: %v", err)
+		}
+		expected, err = parseChecksumFile(body)
+		if err != nil {
+			return DataUpdateFailed, "", err
+		}
+		if expected == current && statErr == nil {
+			return DataUpdateUnchanged, expected, nil
+		}
+	}
+
+	// 임시 파일에 내려받으며 체크섬 계산
+	dir := filepath.Dir(source.Path)
+	if err := os.MkdirAll(dir, ConfigPermissions); err != nil {
+		return DataUpdateFailed, "", fmt.Errorf("failed to create directory: %v", err)
+	}
+	download := filepath.Join(dir, ".download-"+filepath.Base(source.Path))
+	staged := filepath.Join(dir, ".staged-"+filepath.Base(source.Path))
+	defer os.Remove(download)
+	defer os.Remove(staged)
+
+	checksum, err := du.download(source.URL, download)
+	if err != nil {
+		return DataUpdateFailed, "", err
+	}
+	if expected != "" && checksum != expected {
+		return DataUpdateFailed, "", fmt.Errorf("checksum mismatch: expected %s, got %s", shortChecksum(expected), shortChecksum(checksum))
+	}
+	if entry.publicKey != nil {
+		if err := du.verifySignature(source.SignatureURL, entry.publicKey, download); err != nil {
+			return DataUpdateFailed, "", err
+		}
+	}
+	if checksum == current && statErr == nil {
+		return DataUpdateUnchanged, checksum, nil
+	}
+
+	// 압축을 풀어 임시 파일에서 파싱 확인
+	if err := unpackDataFile(download, staged, source.Kind, filepath.Base(source.Path)); err != nil {
+		return DataUpdateFailed, "", err
+	}
+	if err := handler.validate(staged); err != nil {
+		return DataUpdateFailed, "", fmt.Errorf("new file rejected: %v", err)
+	}
+
+	// 기존 파일을 보관하고 교체
+	previous := source.Path + ".previous"
+	hadPrevious := statErr == nil
+	if hadPrevious {
+		if err := copyFile(source.Path, previous); err != nil {
+			return DataUpdateFailed, "", fmt.Errorf("failed to back up current file: %v", err)
+		}
+	}
+	if err := os.Rename(staged, source.Path); err != nil {
+		return DataUpdateFailed, "", fmt.Errorf("failed to replace file: %v", err)
+	}
+
+	// 실행 중인 모니터에 적용, 실패하면 이전 파일로 되돌림
+	if err := handler.apply(source.Path); err != nil {
+		if !hadPrevious {
+			os.Remove(source.Path)
+			return DataUpdateRolledBack, "", err
+		}
+		if restoreErr := os.Rename(previous, source.Path); restoreErr != nil {
+			return DataUpdateRolledBack, "", fmt.Errorf("%v (restore failed: %v)", err, restoreErr)
+		}
+		if restoreErr := handler.apply(source.Path); restoreErr != nil {
+			return DataUpdateRolledBack, "", fmt.Errorf("%v (previous file also failed: %v)", err, restoreErr)
+		}
+		return DataUpdateRolledBack, "", err
+	}
+	return DataUpdateUpdated, checksum, nil
+}
+
+// get 작은 파일 (체크섬/서명) 내려받기
+func (du *DataUpdater) get(rawURL string, limit int64) ([]byte, error) {
+	resp, err := du.client.Get(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", redactURL(rawURL), resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, limit))
+}
+
+// download URL 을 path 에 저장하고 SHA-256 반환
+func (du *DataUpdater) download(rawURL, path string) (string, error) {
+	resp, err := du.client.Get(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("download failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s returned status %d", redactURL(rawURL), resp.StatusCode)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to create download file: %v", err)
+	}
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(file, hash), io.LimitReader(resp.Body, DataUpdateMaxSize+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("download failed: %v", err)
+	}
+	if written > DataUpdateMaxSize {
+		return "", fmt.Errorf("download exceeds %d MB", DataUpdateMaxSize>>20)
+	}
+	if written == 0 {
+		return "", fmt.Errorf("downloaded file is empty")
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// verifySignature 내려받은 파일의 Ed25519 서명 확인
+func (du *DataUpdater) verifySignature(signatureURL string, publicKey ed25519.PublicKey, path string) error {
+	body, err := du.get(signatureURL, 4<<10)
+	if err != nil {
+		return fmt.Errorf("signature: %v", err)
+	}
+	signature := body
+	if len(signature) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(body)))
+		if err != nil || len(decoded) != ed25519.SignatureSize {
+			return fmt.Errorf("signature: expected %d raw bytes or base64", ed25519.SignatureSize)
+		}
+		signature = decoded
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("signature: %v", err)
+	}
+	if !ed25519.Verify(publicKey, content, signature) {
+		return fmt.Errorf("signature verification failed")
+	}
+	return nil
+}
+
+// saveState 마지막으로 적용한 버전 저장
+func (du *DataUpdater) saveState(state map[string]dataUpdateState) {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(du.statePath), ConfigPermissions); err == nil {
+			err = os.WriteFile(du.statePath, data, 0644)
+		}
+	}
+	if err != nil {
+		du.logger.Errorf("Failed to save data update state: %v", err)
+	}
+}
+
+// parseChecksumFile "<hex>  <파일명>" 또는 "<hex>" 형식의 SHA-256 체크섬 파일 해석
+func parseChecksumFile(body []byte) (string, error) {
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("checksum file is empty")
+	}
+	checksum := strings.ToLower(fields[0])
+	if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("checksum file does not start with a SHA-256 hex digest")
+	}
+	return checksum, nil
+}
+
+// unpackDataFile gzip/tar.gz 이면 압축을 풀어, 아니면 그대로 target 에 저장
+// tar 에서는 name 과 같은 이름의 항목 (geoip 는 없으면 첫 .mmdb) 을 사용
+func unpackDataFile(source, target, kind, name string) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	if magic, _ := reader.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return fmt.Errorf("invalid gzip file: %v", err)
+		}
+		defer gz.Close()
+		reader = bufio.NewReader(gz)
+
+		// ustar 헤더 (257 번째 바이트) 가 있으면 tar 압축 파일
+		if header, _ := reader.Peek(263); len(header) == 263 && string(header[257:262]) == "ustar" {
+			archive := tar.NewReader(reader)
+			for {
+				entry, err := archive.Next()
+				if err == io.EOF {
+					return fmt.Errorf("archive does not contain %s", name)
+				}
+				if err != nil {
+					return fmt.Errorf("invalid tar archive: %v", err)
+				}
+				if entry.Typeflag != tar.TypeReg {
+					continue
+				}
+				base := filepath.Base(entry.Name)
+				if base == name || (kind == DataUpdateGeoIP && strings.HasSuffix(base, ".mmdb")) {
+					return writeDataFile(target, archive)
+				}
+			}
+		}
+	}
+	return writeDataFile(target, reader)
+}
+
+// writeDataFile 크기 제한을 두고 파일 저장
+func writeDataFile(path string, reader io.Reader) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	written, err := io.Copy(file, io.LimitReader(reader, DataUpdateMaxSize+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to extract file: %v", err)
+	}
+	if written > DataUpdateMaxSize {
+		return fmt.Errorf("extracted file exceeds %d MB", DataUpdateMaxSize>>20)
+	}
+	return nil
+}
+
+// copyFile 파일 복사 (권한 유지)
+func copyFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// shortChecksum 보고서/로그용 체크섬 앞 12자리
+func shortChecksum(checksum string) string {
+	if len(checksum) > 12 {
+		return checksum[:12]
+	}
+	return checksum
+}
+
+// redactURL 로그에 남길 URL (쿼리의 license_key 등 비밀 값 제거)
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "url"
+	}
+	parsed.RawQuery = ""
+	parsed.User = nil
+	return parsed.String()
+}
+
+// FormatDataUpdateStatus 정기 보고서 본문의 데이터 업데이트 섹션 (항목이 없으면 빈 문자열)
+func FormatDataUpdateStatus(statuses []DataUpdateStatus) string {
+	if len(statuses) == 0 {
+		return ""
+	}
+	var text strings.Builder
+	text.WriteString("\n📦 데이터 업데이트:\n")
+	for _, status := range statuses {
+		text.WriteString(fmt.Sprintf("   %s: %s\n", status.Name, describeDataUpdate(status)))
+	}
+	return text.String()
+}
+
+// describeDataUpdate 항목 상태 한 줄 요약
+func describeDataUpdate(status DataUpdateStatus) string {
+	version := ""
+	if status.Version != "" {
+		version = fmt.Sprintf(" (sha256 %s", status.Version)
+		if !status.Updated.IsZero() {
+			version += ", " + status.Updated.Format("2006-01-02 15:04") + " 적용"
+		}
+		version += ")"
+	}
+	switch status.Result {
+	case DataUpdateUpdated:
+		return "새 버전 적용" + version
+	case DataUpdateUnchanged:
+		return "최신 버전" + version
+	case DataUpdateFailed:
+		return fmt.Sprintf("업데이트 실패, 기존 파일 유지%s - %s", version, status.Error)
+	case DataUpdateRolledBack:
+		return fmt.Sprintf("적용 실패로 이전 파일 복원%s - %s", version, status.Error)
+	}
+	return "아직 확인하지 않음" + version
+}
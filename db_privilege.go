@@ -0,0 +1,369 @@
+/*
+Database Privilege Module
+=========================
+
+MySQL/PostgreSQL 로그의 권한 변경과 관리자 계정 인증 실패 감지 (-db-watch)
+
+주요 기능:
+  - 권한 변경 문장 감지: GRANT, REVOKE, CREATE/ALTER/DROP USER, CREATE/ALTER/DROP ROLE, RENAME USER, SET PASSWORD
+    (PostgreSQL log_statement "statement:", MySQL general log "Query", MySQL audit "sqltext" 등 SQL 문장 위치만 인식)
+  - 전체 권한/슈퍼유저 부여 (ALL, SUPER, SUPERUSER, CREATEROLE, WITH GRANT OPTION 등)나 관리자 계정 대상 변경은 critical
+  - 오류로 실패한 문장 (PostgreSQL 오류 뒤의 "STATEMENT:") 은 실패한 시도로 표시
+  - 관리자 계정 (database.superusers, 기본 root/admin/postgres 등) 인증 실패 감지
+    (MySQL "Access denied for user", PostgreSQL "authentication failed for user", "no pg_hba.conf entry")
+  - 인증 실패 알림은 계정/출처별 database.auth_failure_interval 분 간격으로 제한
+  - 알림의 문장은 비밀번호 (IDENTIFIED BY, PASSWORD '...') 를 가림
+
+일반 DB 에러 알림과 별개의 db_privilege 알림으로 전송
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"regexp"  // 문장/계정 패턴
+	"strings" // 문자열 처리
+	"time"    // 인증 실패 알림 간격
+)
+
+// DB 보안 이벤트 종류
+const (
+	DBEventPrivilegeChange = "privilege_change"
+	DBEventSuperuserAuth   = "superuser_auth_failure"
+)
+
+// DB 보안 이벤트 기본값
+const (
+	DefaultDBAuthFailureInterval = 10  // 관리자 계정 인증 실패 알림 간격 (분)
+	MaxDBStatementLength         = 500 // 알림에 넣는 문장 최대 길이
+	maxDBAuthFailureHistory      = 1000
+)
+
+// DefaultDBSuperusers 기본 관리자 계정 (database.superusers 로 변경)
+var DefaultDBSuperusers = []string{"root", "admin", "postgres", "rdsadmin", "azure_superuser"}
+
+// DBWatchKeywords 키워드 필터를 사용할 때 DB 감지를 위해 추가하는 키워드
+var DBWatchKeywords = []string{"GRANT", "REVOKE", "USER", "ROLE", "PASSWORD", "Access denied", "authentication failed", "pg_hba.conf"}
+
+// 권한 변경 문장 (SQL 문장 시작 위치에서만 인식해 오류 메시지의 단어와 구분)
+// 그룹: 1=PostgreSQL 오류 문맥(STATEMENT:), 2=MySQL audit JSON, 3=문장 종류, 4=나머지
+var dbPrivilegeStatementRegex = regexp.MustCompile(`(?:^|(STATEMENT:)\s*|statement:\s*|\bQuery\s+|("sqltext"\s*:\s*")|;\s*|\t)` +
+	`(?i:(GRANT|REVOKE|CREATE\s+(?:USER|ROLE)|ALTER\s+(?:USER|ROLE)|DROP\s+(?:USER|ROLE)|RENAME\s+USER|SET\s+PASSWORD))\s+(.+)`)
+
+// dbAccount 'user'@'host', "role", `user`, user 형태의 계정
+const dbAccount = `((?:'[^']*'|"[^"]*"|` + "`[^`]*`" + `|[^\s,;@'"]+)(?:@(?:'[^']*'|"[^"]*"|` + "`[^`]*`" + `|[^\s,;'"]+))?)`
+
+var (
+	dbGrantTargetRegex   = regexp.MustCompile(`(?i)\bTO\s+` + dbAccount)
+	dbRevokeTargetRegex  = regexp.MustCompile(`(?i)\bFROM\s+` + dbAccount)
+	dbPasswordForRegex   = regexp.MustCompile(`(?i)^FOR\s+` + dbAccount)
+	dbIfExistsRegex      = regexp.MustCompile(`(?i)^IF\s+(?:NOT\s+)?EXISTS\s+`)
+	dbLeadingAccount     = regexp.MustCompile(`^` + dbAccount)
+	dbGrantAllRegex      = regexp.MustCompile(`(?i)^ALL\b`)
+	dbElevatedRegex      = regexp.MustCompile(`(?i)\bSUPER\b|\bSUPERUSER\b|\bCREATEROLE\b|\bBYPASSRLS\b|\bWITH\s+(?:GRANT|ADMIN)\s+OPTION\b|\bpg_(?:read|write)_server_files\b|\bpg_execute_server_program\b`)
+	dbIdentifiedRegex    = regexp.MustCompile(`(?i)(\bIDENTIFIED\s+(?:WITH\s+\S+\s+)?(?:BY|AS)\s+)('(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.)*"|\S+)`)
+	dbPasswordValueRegex = regexp.MustCompile(`(?i)(\bPASSWORD\s*(?:=\s*)?(?:PASSWORD\s*)?\(?\s*)('(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.)*")`)
+	dbSetPasswordRegex   = regexp.MustCompile(`(?i)(\bSET\s+PASSWORD\b[^=]*=\s*(?:PASSWORD\s*\(\s*)?)('(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.)*")`)
+
+	dbMySQLAccessDenied = regexp.MustCompile(`Access denied for user '([^']*)'@'([^']*)'`)
+	dbPostgresAuthFail  = regexp.MustCompile(`authentication failed for user "([^"]+)"`)
+	dbPostgresNoHBA     = regexp.MustCompile(`no pg_hba\.conf entry for host "([^"]+)", user "([^"]+)"`)
+	dbPostgresHostField = regexp.MustCompile(`\bhost=([0-9A-Fa-f.:]+)`)
+)
+
+// DBSecurityEvent 데이터베이스 권한 변경 또는 관리자 계정 인증 실패
+type DBSecurityEvent struct {
+	Kind        string // privilege_change, superuser_auth_failure
+	Engine      string // mysql, postgresql, database (판별 불가)
+	Statement   string // GRANT, REVOKE, CREATE USER, ALTER ROLE ... (인증 실패는 빈 값)
+	Query       string // 비밀번호를 가린 문장
+	Account     string // 대상 계정 (인증 실패는 로그인 시도 계정)
+	Client      string // 접속 출처 (알 수 있는 경우)
+	Elevated    bool   // 전체 권한/슈퍼유저 부여 또는 관리자 계정 대상
+	Failed      bool   // 오류로 실패한 문장
+	Suppressed  int    // 이전 알림 이후 간격 제한으로 알리지 않은 인증 실패 수
+	Severity    string // warning, critical
+	ShouldAlert bool   // 알림 간격 제한 통과 여부
+}
+
+// dbAuthFailure 계정/출처별 인증 실패 알림 기록
+type dbAuthFailure struct {
+	lastAlert  time.Time
+	suppressed int
+}
+
+// DBPrivilegeDetector DB 권한 변경/관리자 인증 실패 감지기 (처리 고루틴에서만 사용)
+type DBPrivilegeDetector struct {
+	superusers   map[string]bool
+	interval     time.Duration
+	authFailures map[string]*dbAuthFailure
+}
+
+// NewDBPrivilegeDetector 관리자 계정 목록과 인증 실패 알림 간격(분)으로 감지기 생성
+func NewDBPrivilegeDetector(superusers []string, intervalMinutes int) *DBPrivilegeDetector {
+	detector := &DBPrivilegeDetector{authFailures: make(map[string]*dbAuthFailure)}
+	detector.SetSuperusers(superusers)
+	detector.SetAuthFailureInterval(intervalMinutes)
+	return detector
+}
+
+// newConfiguredDBPrivilegeDetector 설정 파일의 database 섹션으로 감지기 생성 (설정 서비스가 없으면 기본값)
+func newConfiguredDBPrivilegeDetector() *DBPrivilegeDetector {
+	if configService == nil {
+		return NewDBPrivilegeDetector(nil, 0)
+	}
+	config := configService.GetConfig()
+	return NewDBPrivilegeDetector(config.Database.Superusers, config.Database.AuthFailureInterval)
+}
+
+// SetSuperusers 관리자 계정 목록 교체 (비어 있으면 기본 목록)
+func (d *DBPrivilegeDetector) SetSuperusers(superusers []string) {
+	if len(superusers) == 0 {
+		superusers = DefaultDBSuperusers
+	}
+	d.superusers = make(map[string]bool, len(superusers))
+	for _, user := range superusers {
+		if user = strings.ToLower(strings.TrimSpace(user)); user != "" {
+			d.superusers[user] = true
+		}
+	}
+}
+
+// SetAuthFailureInterval 인증 실패 알림 간격(분) 설정 (0 이하는 기본값)
+func (d *DBPrivilegeDetector) SetAuthFailureInterval(minutes int) {
+	if minutes <= 0 {
+		minutes = DefaultDBAuthFailureInterval
+	}
+	d.interval = time.Duration(minutes) * time.Minute
+}
+
+// ApplyConfig 설정 파일의 database 섹션 적용
+func (d *DBPrivilegeDetector) ApplyConfig(config *Config) {
+	d.SetSuperusers(config.Database.Superusers)
+	d.SetAuthFailureInterval(config.Database.AuthFailureInterval)
+}
+
+// Detect 로그 한 줄에서 권한 변경 또는 관리자 계정 인증 실패 감지 (해당 없으면 nil)
+func (d *DBPrivilegeDetector) Detect(line string) *DBSecurityEvent {
+	if event := d.detectPrivilegeChange(line); event != nil {
+		return event
+	}
+	return d.detectSuperuserAuthFailure(line, time.Now())
+}
+
+// detectPrivilegeChange 권한 변경 문장 감지 (항상 알림)
+func (d *DBPrivilegeDetector) detectPrivilegeChange(line string) *DBSecurityEvent {
+	matches := dbPrivilegeStatementRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return nil
+	}
+	statement := strings.ToUpper(strings.Join(strings.Fields(matches[3]), " "))
+	rest := matches[4]
+	if matches[2] != "" {
+		// JSON 문자열 값은 닫는 따옴표까지
+		if end := strings.Index(rest, `"`); end >= 0 {
+			rest = rest[:end]
+		}
+	}
+	rest = strings.TrimSpace(rest)
+
+	event := &DBSecurityEvent{
+		Kind:        DBEventPrivilegeChange,
+		Engine:      dbEngine(line),
+		Statement:   statement,
+		Query:       maskDBPasswords(statement + " " + rest),
+		Account:     dbStatementAccount(statement, rest),
+		Failed:      matches[1] != "",
+		ShouldAlert: true,
+	}
+	if len(event.Query) > MaxDBStatementLength {
+		event.Query = event.Query[:MaxDBStatementLength] + "..."
+	}
+
+	switch statement {
+	case "GRANT":
+		event.Elevated = dbGrantAllRegex.MatchString(rest) || dbElevatedRegex.MatchString(rest)
+	case "CREATE USER", "CREATE ROLE", "ALTER USER", "ALTER ROLE":
+		event.Elevated = dbElevatedRegex.MatchString(rest)
+	}
+	if d.isSuperuser(event.Account) && statement != "REVOKE" {
+		event.Elevated = true
+	}
+
+	event.Severity = AlertSeverityWarning
+	if event.Elevated && !event.Failed {
+		event.Severity = AlertSeverityCritical
+	}
+	return event
+}
+
+// detectSuperuserAuthFailure 관리자 계정 인증 실패 감지 (계정/출처별 알림 간격 제한)
+func (d *DBPrivilegeDetector) detectSuperuserAuthFailure(line string, now time.Time) *DBSecurityEvent {
+	var account, client, engine string
+	if matches := dbMySQLAccessDenied.FindStringSubmatch(line); matches != nil {
+		account, client, engine = matches[1], matches[2], "mysql"
+	} else if matches := dbPostgresNoHBA.FindStringSubmatch(line); matches != nil {
+		account, client, engine = matches[2], matches[1], "postgresql"
+	} else if matches := dbPostgresAuthFail.FindStringSubmatch(line); matches != nil {
+		account, engine = matches[1], "postgresql"
+		if host := dbPostgresHostField.FindStringSubmatch(line); host != nil {
+			client = host[1]
+		}
+	} else {
+		return nil
+	}
+	if !d.isSuperuser(account) {
+		return nil
+	}
+
+	event := &DBSecurityEvent{
+		Kind:     DBEventSuperuserAuth,
+		Engine:   engine,
+		Account:  account,
+		Client:   client,
+		Elevated: true,
+		Severity: AlertSeverityCritical,
+	}
+
+	key := engine + "|" + strings.ToLower(account) + "|" + client
+	record, ok := d.authFailures[key]
+	if !ok {
+		d.pruneAuthFailures(now)
+		record = &dbAuthFailure{}
+		d.authFailures[key] = record
+	}
+	if now.Sub(record.lastAlert) < d.interval {
+		record.suppressed++
+		return event
+	}
+	event.ShouldAlert = true
+	event.Suppressed = record.suppressed
+	record.lastAlert, record.suppressed = now, 0
+	return event
+}
+
+// pruneAuthFailures 기록이 많아지면 알림 간격이 지난 항목 정리
+func (d *DBPrivilegeDetector) pruneAuthFailures(now time.Time) {
+	if len(d.authFailures) < maxDBAuthFailureHistory {
+		return
+	}
+	for key, record := range d.authFailures {
+		if now.Sub(record.lastAlert) >= d.interval {
+			delete(d.authFailures, key)
+		}
+	}
+}
+
+// isSuperuser 계정 이름 (@호스트 제외) 이 관리자 계정인지 확인
+func (d *DBPrivilegeDetector) isSuperuser(account string) bool {
+	if i := strings.IndexByte(account, '@'); i >= 0 {
+		account = account[:i]
+	}
+	return account != "" && d.superusers[strings.ToLower(account)]
+}
+
+// dbStatementAccount 문장의 대상 계정 (따옴표 제거, 알 수 없으면 빈 값)
+func dbStatementAccount(statement, rest string) string {
+	var matches []string
+	switch statement {
+	case "GRANT":
+		matches = dbGrantTargetRegex.FindStringSubmatch(rest)
+	case "REVOKE":
+		matches = dbRevokeTargetRegex.FindStringSubmatch(rest)
+	case "SET PASSWORD":
+		matches = dbPasswordForRegex.FindStringSubmatch(rest)
+	default:
+		matches = dbLeadingAccount.FindStringSubmatch(dbIfExistsRegex.ReplaceAllString(rest, ""))
+	}
+	if matches == nil {
+		return ""
+	}
+	return strings.NewReplacer("'", "", `"`, "", "`", "").Replace(matches[1])
+}
+
+// maskDBPasswords 문장의 비밀번호 값 가림
+func maskDBPasswords(query string) string {
+	query = dbIdentifiedRegex.ReplaceAllString(query, "${1}'***'")
+	query = dbSetPasswordRegex.ReplaceAllString(query, "${1}'***'")
+	return dbPasswordValueRegex.ReplaceAllString(query, "${1}'***'")
+}
+
+// dbEngine 로그 라인의 데이터베이스 종류 추정
+func dbEngine(line string) string {
+	lower := strings.ToLower(line)
+	switch {
+	case strings.Contains(lower, "postgres") || strings.Contains(line, "statement:") || strings.Contains(line, "STATEMENT:") || strings.Contains(line, "pg_hba"):
+		return "postgresql"
+	case strings.Contains(lower, "mysql") || strings.Contains(lower, "mariadb") || strings.Contains(line, "Query") || strings.Contains(line, "sqltext"):
+		return "mysql"
+	}
+	return "database"
+}
+
+// dbSecurityAlert DB 보안 이벤트 알림 (일반 DB 에러 알림과 별도 유형)
+func dbSecurityAlert(event *DBSecurityEvent, parsed map[string]string, line string) Alert {
+	host := parsed["host"]
+	fields := []AlertField{
+		{Label: "DB", Value: event.Engine, Short: true},
+		{Label: "호스트", Value: host, Short: true},
+	}
+	var title, headline string
+	if event.Kind == DBEventSuperuserAuth {
+		title = fmt.Sprintf("[%s DB AUTH FAILURE] %s - %s superuser %s", AppName, host, event.Engine, event.Account)
+		if event.Client != "" {
+			title += " from " + event.Client
+		}
+		headline = fmt.Sprintf("🔑 %s 관리자 계정 %s 인증 실패", event.Engine, event.Account)
+		fields = append(fields, AlertField{Label: "계정", Value: event.Account, Short: true})
+		if event.Client != "" {
+			fields = append(fields, AlertField{Label: "출처", Value: event.Client, Short: true})
+		}
+		if event.Suppressed > 0 {
+			fields = append(fields, AlertField{Label: "직전 알림 이후 추가 실패", Value: fmt.Sprintf("%d회", event.Suppressed)})
+		}
+	} else {
+		status := "실행됨"
+		if event.Failed {
+			status = "실패한 시도 (오류)"
+		}
+		title = fmt.Sprintf("[%s DB PRIVILEGE] %s - %s %s", AppName, host, event.Statement, event.Account)
+		headline = fmt.Sprintf("🛡️ %s 권한 변경 감지: %s", event.Engine, event.Statement)
+		if event.Elevated {
+			headline = fmt.Sprintf("🚨 %s 관리자 권한 변경 감지: %s", event.Engine, event.Statement)
+		}
+		fields = append(fields,
+			AlertField{Label: "문장", Value: event.Statement, Short: true},
+			AlertField{Label: "상태", Value: status, Short: true},
+		)
+		if event.Account != "" {
+			fields = append(fields, AlertField{Label: "대상 계정", Value: event.Account, Short: true})
+		}
+		fields = append(fields, AlertField{Label: "SQL", Value: event.Query, Code: true})
+	}
+
+	return Alert{
+		Type:     AlertTypeDBPrivilege,
+		Severity: event.Severity,
+		Title:    title,
+		Headline: headline,
+		Sections: []AlertSection{
+			{Fields: fields, Summary: true},
+			{
+				Title: "📄 원본 로그",
+				Fields: []AlertField{
+					{Label: "시간", Value: parsed["timestamp"], Short: true},
+					{Label: "원본 로그", Value: maskDBPasswords(line), Code: true},
+				},
+			},
+		},
+		Host:   host,
+		Thread: alertThreadKey(AlertTypeDBPrivilege, host, event.Kind, event.Account),
+		Fields: map[string]string{
+			"kind":      event.Kind,
+			"engine":    event.Engine,
+			"statement": event.Statement,
+			"account":   event.Account,
+			"client":    event.Client,
+			"elevated":  fmt.Sprintf("%t", event.Elevated),
+		},
+	}
+}
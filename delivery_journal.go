@@ -0,0 +1,464 @@
+/*
+Delivery Journal Module
+=======================
+
+알림 전송 저널 (최소 한 번 전송, -delivery-journal)
+
+채널로 보내기 전에 알림을 추가 전용 JSONL 로그 (WAL) 에 먼저 기록하고 fsync 한 뒤 전송하고,
+전송이 성공하면 확인 기록을 남깁니다. 감지와 전송 사이에 프로세스가 죽거나 재시작되어도 다음
+시작 시 확인되지 않은 알림을 다시 보내므로 CRITICAL 알림이 조용히 사라지지 않습니다.
+
+주요 기능:
+- 기록 단위: 알림 × 채널 × 받는 곳 (한 채널만 실패하면 그 채널로만 다시 전송)
+- 전송 실패 시 30초부터 두 배씩 (최대 10분) 간격으로 재시도, 24시간이 지나면 만료 처리
+- 중복 방지: 같은 알림 ID 의 같은 채널 전송은 대기 중이거나 확인된 경우 다시 보내지 않음
+- 알림 ID (유형/제목/호스트/시각/스레드 해시) 를 웹훅 본문 id 로 전달해 받는 쪽에서도 중복 제거 가능
+- 재시작 시 복구: 채널 설정이 그대로면 다시 전송, 없어진 채널이나 만료된 기록은 로그 후 정리
+- 마지막 줄이 잘린 기록 (쓰는 도중 종료) 은 건너뛰고, 확인된 기록이 쌓이면 대기 기록만 남기고 압축
+- 종료 시 진행 중인 전송 시도가 끝날 때까지 대기 (예약된 재시도는 기다리지 않고 다음 시작 시 다시 전송)
+*/
+package main
+
+import (
+	"bufio"         // 저널 읽기
+	"context"       // 종료 시 전송 대기 시간
+	"crypto/sha256" // 알림 ID
+	"encoding/hex"  // 알림 ID 문자열
+	"encoding/json" // 저널 레코드
+	"fmt"           // 형식화된 I/O
+	"os"            // 저널 파일
+	"path/filepath" // 저널 디렉토리
+	"sort"          // 복구 순서
+	"strconv"       // 알림 ID 시각
+	"sync"          // 저널 보호
+	"time"          // 재시도 간격
+)
+
+// 전송 저널 관련 상수
+const (
+	DeliveryJournalMaxAge = 24 * time.Hour // 이보다 오래 확인되지 않은 전송은 만료 (재시작이 늦어도 오래된 알림은 보내지 않음)
+
+	deliveryRetryInitial  = 30 * time.Second
+	deliveryRetryMax      = 10 * time.Minute
+	deliveryCompactAfter  = 1000 // 확인/만료 기록이 이만큼 쌓이면 대기 기록만 남기고 다시 씀
+	deliveryJournalMaxLen = 16 * 1024 * 1024
+
+	deliveryOpPending = "pending"
+	deliveryOpDone    = "done"
+	deliveryOpExpired = "expired"
+)
+
+// deliveryRecord 저널 한 줄 (pending 은 알림 전체, done/expired 는 키만)
+type deliveryRecord struct {
+	Op     string        `json:"op"`
+	Key    string        `json:"key"`
+	Time   time.Time     `json:"time"`
+	Scope  string        `json:"scope,omitempty"` // 테넌트 ID (운영자 채널은 비어 있음)
+	Sink   string        `json:"sink,omitempty"`
+	Target string        `json:"target,omitempty"`
+	Detail string        `json:"detail,omitempty"`
+	Alert  *journalAlert `json:"alert,omitempty"`
+	Reason string        `json:"reason,omitempty"` // 만료 이유
+}
+
+// journalAlert 저널에 저장하는 알림 (JSON 출력에서 빠지는 Slack 메시지와 필드 서식까지 보존)
+type journalAlert struct {
+	Alert
+	Sections []journalSection `json:"sections,omitempty"`
+	Slack    *SlackMessage    `json:"slack,omitempty"`
+}
+
+// journalSection 필드 서식(Short/Code)을 포함한 알림 섹션
+type journalSection struct {
+	Title   string         `json:"title,omitempty"`
+	Fields  []journalField `json:"fields,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Summary bool           `json:"summary,omitempty"`
+}
+
+// journalField 서식 정보를 포함한 알림 필드
+type journalField struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+	Short bool   `json:"short,omitempty"`
+	Code  bool   `json:"code,omitempty"`
+}
+
+// newJournalAlert 저장용 알림으로 변환
+func newJournalAlert(alert Alert) *journalAlert {
+	ja := &journalAlert{Alert: alert, Slack: alert.Slack}
+	for _, section := range alert.Sections {
+		js := journalSection{Title: section.Title, Text: section.Text, Summary: section.Summary}
+		for _, field := range section.Fields {
+			js.Fields = append(js.Fields, journalField{Label: field.Label, Value: field.Value, Short: field.Short, Code: field.Code})
+		}
+		ja.Sections = append(ja.Sections, js)
+	}
+	return ja
+}
+
+// alert 저장된 알림을 채널로 보낼 알림으로 복원
+func (ja *journalAlert) alert(target, detail string) Alert {
+	alert := ja.Alert
+	alert.Slack = ja.Slack
+	alert.Target = target
+	alert.Detail = detail
+	alert.Sections = nil
+	for _, js := range ja.Sections {
+		section := AlertSection{Title: js.Title, Text: js.Text, Summary: js.Summary}
+		for _, field := range js.Fields {
+			section.Fields = append(section.Fields, AlertField{Label: field.Label, Value: field.Value, Short: field.Short, Code: field.Code})
+		}
+		alert.Sections = append(alert.Sections, section)
+	}
+	return alert
+}
+
+// deliveryEntry 확인되지 않은 전송
+type deliveryEntry struct {
+	record   deliveryRecord
+	sink     AlertSink
+	attempts int
+}
+
+// DeliveryJournal 알림 전송 선기록 저널
+type DeliveryJournal struct {
+	path     string
+	file     *os.File
+	pending  map[string]*deliveryEntry
+	done     map[string]time.Time // 확인된 전송 키 (중복 방지, DeliveryJournalMaxAge 동안 보관)
+	obsolete int                  // 마지막 압축 이후 확인/만료 기록 수
+	closed   bool
+	attempts inFlight // 진행 중인 전송 시도 (종료 시 Drain 으로 대기)
+	mutex    sync.Mutex
+	logger   Logger
+}
+
+// OpenDeliveryJournal 저널 파일을 열고 확인되지 않은 전송을 읽어 둠 (복구는 Recover 에서 채널별로)
+func OpenDeliveryJournal(path string, logger Logger) (*DeliveryJournal, error) {
+	path = expandHomePath(path)
+	if err := os.MkdirAll(filepath.Dir(path), ConfigPermissions); err != nil {
+		return nil, err
+	}
+	dj := &DeliveryJournal{
+		path:    path,
+		pending: make(map[string]*deliveryEntry),
+		done:    make(map[string]time.Time),
+		logger:  logger,
+	}
+	if err := dj.load(); err != nil {
+		return nil, err
+	}
+	if err := dj.rewrite(); err != nil {
+		return nil, err
+	}
+	return dj, nil
+}
+
+// load 저널 재생 (pending 다음 done/expired 가 없으면 확인되지 않은 전송)
+func (dj *DeliveryJournal) load() error {
+	file, err := os.Open(dj.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), deliveryJournalMaxLen)
+	for line := 1; scanner.Scan(); line++ {
+		var record deliveryRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || record.Key == "" {
+			dj.logger.Errorf("❌ Skipping unreadable delivery journal record %s:%d", dj.path, line)
+			continue
+		}
+		switch record.Op {
+		case deliveryOpPending:
+			if record.Alert != nil {
+				dj.pending[record.Key] = &deliveryEntry{record: record}
+			}
+		case deliveryOpDone:
+			delete(dj.pending, record.Key)
+			dj.done[record.Key] = record.Time
+		case deliveryOpExpired:
+			delete(dj.pending, record.Key)
+		}
+	}
+	return scanner.Err()
+}
+
+// Path 저널 파일 경로
+func (dj *DeliveryJournal) Path() string {
+	return dj.path
+}
+
+// Pending 확인되지 않은 전송 수
+func (dj *DeliveryJournal) Pending() int {
+	dj.mutex.Lock()
+	defer dj.mutex.Unlock()
+	return len(dj.pending)
+}
+
+// Send 전송을 먼저 기록하고 비동기 전송 (이미 대기 중이거나 확인된 같은 전송은 건너뜀)
+func (dj *DeliveryJournal) Send(scope, name string, sink AlertSink, alert Alert) {
+	key := deliveryKey(scope, name, alert)
+
+	dj.mutex.Lock()
+	if _, exists := dj.pending[key]; exists {
+		dj.mutex.Unlock()
+		return
+	}
+	if _, exists := dj.done[key]; exists {
+		dj.mutex.Unlock()
+		return
+	}
+	entry := &deliveryEntry{
+		record: deliveryRecord{
+			Op:     deliveryOpPending,
+			Key:    key,
+			Time:   time.Now(),
+			Scope:  scope,
+			Sink:   name,
+			Target: alert.Target,
+			Detail: alert.Detail,
+			Alert:  newJournalAlert(alert),
+		},
+		sink: sink,
+	}
+	dj.pending[key] = entry
+	err := dj.append(entry.record)
+	dj.mutex.Unlock()
+
+	// 기록에 실패해도 전송은 진행 (저널이 없던 때와 같은 동작)
+	if err != nil {
+		dj.logger.Errorf("❌ Failed to write delivery journal %s: %v", dj.path, err)
+	}
+	dj.start(entry)
+}
+
+// Recover 범위(scope)의 확인되지 않은 전송을 다시 보냄 (채널이 없어졌거나 만료된 기록은 정리)
+// 고가용성 대기 인스턴스라도 다시 보냄 (감지 당시 이 인스턴스가 리더였으므로 다른 인스턴스는 보내지 않았음)
+func (dj *DeliveryJournal) Recover(scope string, lookup func(name string) AlertSink) int {
+	dj.mutex.Lock()
+	var entries []*deliveryEntry
+	for _, entry := range dj.pending {
+		if entry.record.Scope == scope && entry.sink == nil {
+			entries = append(entries, entry)
+		}
+	}
+	dj.mutex.Unlock()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].record.Time.Before(entries[j].record.Time) })
+
+	resent := 0
+	for _, entry := range entries {
+		record := entry.record
+		if age := time.Since(record.Time); age > DeliveryJournalMaxAge {
+			dj.expire(entry, fmt.Sprintf("unconfirmed for %v", age.Round(time.Minute)))
+			continue
+		}
+		sink := lookup(record.Sink)
+		if sink == nil {
+			dj.expire(entry, "channel is no longer configured")
+			continue
+		}
+		dj.logger.Infof("🔁 Resending unconfirmed %s alert via %s (queued %s): %s",
+			record.Alert.Type, record.Sink, record.Time.Format("2006-01-02 15:04:05"), record.Alert.Title)
+		dj.mutex.Lock()
+		entry.sink = sink
+		dj.mutex.Unlock()
+		dj.start(entry)
+		resent++
+	}
+	return resent
+}
+
+// ExpireUnclaimed 모든 범위의 복구를 마친 뒤에도 채널을 찾지 못한 전송 정리 (설정에서 빠진 테넌트)
+func (dj *DeliveryJournal) ExpireUnclaimed() {
+	dj.mutex.Lock()
+	var entries []*deliveryEntry
+	for _, entry := range dj.pending {
+		if entry.sink == nil {
+			entries = append(entries, entry)
+		}
+	}
+	dj.mutex.Unlock()
+	for _, entry := range entries {
+		dj.expire(entry, "tenant is no longer configured")
+	}
+}
+
+// attempt 한 번 전송하고 성공하면 확인, 실패하면 재시도 예약 (최대 보관 기간이 지나면 만료)
+func (dj *DeliveryJournal) attempt(entry *deliveryEntry) {
+	dj.mutex.Lock()
+	closed := dj.closed
+	dj.mutex.Unlock()
+	if closed {
+		return
+	}
+	record := entry.record
+	err := entry.sink.Send(record.Alert.alert(record.Target, record.Detail))
+
+	dj.mutex.Lock()
+	if dj.closed {
+		dj.mutex.Unlock()
+		return
+	}
+	if err == nil {
+		delete(dj.pending, record.Key)
+		dj.done[record.Key] = time.Now()
+		dj.finish(deliveryRecord{Op: deliveryOpDone, Key: record.Key, Time: time.Now()})
+		dj.mutex.Unlock()
+		return
+	}
+	entry.attempts++
+	attempts := entry.attempts
+	dj.mutex.Unlock()
+
+	if time.Since(record.Time) > DeliveryJournalMaxAge {
+		dj.logger.Errorf("❌ Failed to send %s alert via %s: %v", record.Alert.Type, record.Sink, err)
+		dj.expire(entry, err.Error())
+		return
+	}
+	delay := deliveryRetryInitial << uint(min(attempts-1, 5))
+	if delay > deliveryRetryMax {
+		delay = deliveryRetryMax
+	}
+	dj.logger.Errorf("❌ Failed to send %s alert via %s (attempt %d, retrying in %v): %v", record.Alert.Type, record.Sink, attempts, delay, err)
+	time.AfterFunc(delay, func() { dj.start(entry) })
+}
+
+// start 전송 시도를 백그라운드로 시작 (Drain 이 기다릴 수 있도록 시작 전에 기록)
+func (dj *DeliveryJournal) start(entry *deliveryEntry) {
+	dj.attempts.Add()
+	go func() {
+		defer dj.attempts.Done()
+		dj.attempt(entry)
+	}()
+}
+
+// Drain 진행 중인 전송 시도가 끝날 때까지 대기 (종료 시, Close 전에 호출)
+func (dj *DeliveryJournal) Drain(ctx context.Context) error {
+	return dj.attempts.Wait(ctx)
+}
+
+// expire 더 이상 보내지 않을 전송 정리
+func (dj *DeliveryJournal) expire(entry *deliveryEntry, reason string) {
+	dj.mutex.Lock()
+	defer dj.mutex.Unlock()
+	if dj.closed {
+		return
+	}
+	record := entry.record
+	delete(dj.pending, record.Key)
+	dj.finish(deliveryRecord{Op: deliveryOpExpired, Key: record.Key, Time: time.Now(), Reason: reason})
+	dj.logger.Errorf("❌ Giving up on %s alert via %s (%s): %s", record.Alert.Type, record.Sink, reason, record.Alert.Title)
+}
+
+// finish 확인/만료 기록 추가 후 필요하면 압축 (mutex 보유 상태에서 호출)
+func (dj *DeliveryJournal) finish(record deliveryRecord) {
+	if err := dj.append(record); err != nil {
+		dj.logger.Errorf("❌ Failed to write delivery journal %s: %v", dj.path, err)
+		return
+	}
+	dj.obsolete++
+	if dj.obsolete < deliveryCompactAfter {
+		return
+	}
+	cutoff := time.Now().Add(-DeliveryJournalMaxAge)
+	for key, at := range dj.done {
+		if at.Before(cutoff) {
+			delete(dj.done, key)
+		}
+	}
+	if err := dj.rewrite(); err != nil {
+		dj.logger.Errorf("❌ Failed to compact delivery journal %s: %v", dj.path, err)
+	}
+}
+
+// append 레코드 한 줄 기록 후 디스크에 반영 (mutex 보유 상태에서 호출)
+func (dj *DeliveryJournal) append(record deliveryRecord) error {
+	if dj.file == nil {
+		return fmt.Errorf("journal is closed")
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := dj.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return dj.file.Sync()
+}
+
+// rewrite 대기 기록만 담은 새 저널로 교체 (임시 파일에 쓴 뒤 이름 변경)
+func (dj *DeliveryJournal) rewrite() error {
+	tmpPath := dj.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range dj.pending {
+		if err := encoder.Encode(entry.record); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	tmp.Close()
+	if err := os.Rename(tmpPath, dj.path); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(dj.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if dj.file != nil {
+		dj.file.Close()
+	}
+	dj.file = file
+	dj.obsolete = 0
+	return nil
+}
+
+// Close 저널 닫기 (진행 중인 전송과 재시도는 확인되지 않은 채 남아 다음 시작 시 다시 보냄)
+func (dj *DeliveryJournal) Close() error {
+	dj.mutex.Lock()
+	defer dj.mutex.Unlock()
+	if dj.closed {
+		return nil
+	}
+	dj.closed = true
+	if dj.file == nil {
+		return nil
+	}
+	err := dj.file.Close()
+	dj.file = nil
+	return err
+}
+
+// alertID 알림 고유 ID (유형, 제목, 호스트, 시각, 스레드 해시)
+func alertID(alert Alert) string {
+	hash := sha256.New()
+	for _, part := range []string{alert.Type, alert.Title, alert.Host, strconv.FormatInt(alert.Timestamp.UnixNano(), 10), alert.Thread} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:24]
+}
+
+// deliveryKey 전송 단위 키 (알림 ID, 범위, 채널, 받는 곳)
+func deliveryKey(scope, name string, alert Alert) string {
+	return alert.ID + "/" + scope + "/" + name + "/" + alert.Target
+}
@@ -0,0 +1,221 @@
+/*
+DKIM Signing Module
+===================
+
+발신 이메일 DKIM 서명 (RFC 6376, RFC 8463)
+
+주요 기능:
+- PEM 개인 키 로드 (RSA PKCS#1/PKCS#8, Ed25519 PKCS#8)
+- relaxed/relaxed 정규화 후 rsa-sha256 / ed25519-sha256 서명
+- 메시지 맨 앞에 DKIM-Signature 헤더 추가
+
+DNS 설정:
+- <selector>._domainkey.<domain> TXT "v=DKIM1; k=rsa; p=<공개 키 base64>"
+*/
+package main
+
+import (
+	"bytes"           // 메시지 버퍼
+	"crypto"          // 서명 인터페이스
+	"crypto/ed25519"  // Ed25519 서명
+	"crypto/rand"     // RSA 서명 난수
+	"crypto/rsa"      // RSA 서명
+	"crypto/sha256"   // 본문/헤더 해시
+	"crypto/x509"     // 개인 키 파싱
+	"encoding/base64" // 서명/해시 인코딩
+	"encoding/pem"    // PEM 디코딩
+	"fmt"             // 형식화된 I/O
+	"os"              // 키 파일 읽기
+	"strings"         // 문자열 처리
+	"time"            // 서명 시각
+)
+
+// DKIMSignedHeaders 서명에 포함하는 헤더 (메시지에 있는 것만 사용)
+var DKIMSignedHeaders = []string{"From", "To", "Subject", "Date", "Message-ID", "In-Reply-To", "References", "MIME-Version", "Content-Type", "Content-Transfer-Encoding"}
+
+// DKIMSigner 발신 메시지 DKIM 서명기
+type DKIMSigner struct {
+	domain    string
+	selector  string
+	key       crypto.Signer
+	algorithm string // rsa-sha256, ed25519-sha256
+}
+
+// NewDKIMSigner 도메인/셀렉터와 PEM 개인 키 파일로 서명기 생성
+func NewDKIMSigner(domain, selector, keyFile string) (*DKIMSigner, error) {
+	if domain == "" || selector == "" {
+		return nil, fmt.Errorf("DKIM domain and selector are required")
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DKIM private key: %v", err)
+	}
+	key, err := parseDKIMPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	signer := &DKIMSigner{domain: domain, selector: selector, key: key}
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		signer.algorithm = "rsa-sha256"
+	case ed25519.PrivateKey:
+		signer.algorithm = "ed25519-sha256"
+	}
+	return signer, nil
+}
+
+// parseDKIMPrivateKey PEM 개인 키 파싱 (RSA PKCS#1/PKCS#8, Ed25519 PKCS#8)
+func parseDKIMPrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("DKIM private key is not PEM encoded")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DKIM RSA private key: %v", err)
+		}
+		return key, nil
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DKIM private key: %v", err)
+		}
+		switch key := key.(type) {
+		case *rsa.PrivateKey:
+			return key, nil
+		case ed25519.PrivateKey:
+			return key, nil
+		}
+		return nil, fmt.Errorf("unsupported DKIM key type %T (expected RSA or Ed25519)", key)
+	}
+	return nil, fmt.Errorf("unsupported DKIM PEM block %q", block.Type)
+}
+
+// Domain 서명 도메인 (d=)
+func (ds *DKIMSigner) Domain() string {
+	return ds.domain
+}
+
+// Selector 셀렉터 (s=)
+func (ds *DKIMSigner) Selector() string {
+	return ds.selector
+}
+
+// Sign CRLF 줄바꿈 메시지에 DKIM-Signature 헤더를 추가해 반환
+func (ds *DKIMSigner) Sign(message []byte) ([]byte, error) {
+	end := bytes.Index(message, []byte("\r\n\r\n"))
+	if end < 0 {
+		return nil, fmt.Errorf("message has no header/body separator")
+	}
+	headers := splitDKIMHeaders(string(message[:end+2]))
+	body := message[end+4:]
+
+	bodyHash := sha256.Sum256(dkimRelaxedBody(body))
+
+	// 메시지에 있는 서명 대상 헤더만 h= 에 포함
+	var names []string
+	var signed strings.Builder
+	for _, name := range DKIMSignedHeaders {
+		if header, ok := findDKIMHeader(headers, name); ok {
+			names = append(names, strings.ToLower(name))
+			signed.WriteString(dkimRelaxedHeader(header) + "\r\n")
+		}
+	}
+
+	header := fmt.Sprintf("DKIM-Signature: v=1; a=%s; c=relaxed/relaxed; d=%s; s=%s;\r\n t=%d; h=%s;\r\n bh=%s;\r\n b=",
+		ds.algorithm, ds.domain, ds.selector, time.Now().Unix(),
+		strings.Join(names, ":"), base64.StdEncoding.EncodeToString(bodyHash[:]))
+	signed.WriteString(dkimRelaxedHeader(header))
+
+	hash := sha256.Sum256([]byte(signed.String()))
+	var signature []byte
+	var err error
+	switch key := ds.key.(type) {
+	case ed25519.PrivateKey:
+		// RFC 8463: SHA-256 해시를 Ed25519 로 서명
+		signature = ed25519.Sign(key, hash[:])
+	default:
+		signature, err = ds.key.Sign(rand.Reader, hash[:], crypto.SHA256)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %v", err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString(header + foldDKIMValue(base64.StdEncoding.EncodeToString(signature)) + "\r\n")
+	out.Write(message)
+	return out.Bytes(), nil
+}
+
+// splitDKIMHeaders 헤더 블록을 헤더 단위로 분리 (접힌 줄 포함)
+func splitDKIMHeaders(block string) []string {
+	var headers []string
+	for _, line := range strings.SplitAfter(block, "\r\n") {
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(headers) > 0 {
+			headers[len(headers)-1] += line
+			continue
+		}
+		headers = append(headers, line)
+	}
+	for i := range headers {
+		headers[i] = strings.TrimSuffix(headers[i], "\r\n")
+	}
+	return headers
+}
+
+// findDKIMHeader 이름이 일치하는 헤더 (대소문자 무시)
+func findDKIMHeader(headers []string, name string) (string, bool) {
+	for _, header := range headers {
+		colon := strings.IndexByte(header, ':')
+		if colon > 0 && strings.EqualFold(strings.TrimSpace(header[:colon]), name) {
+			return header, true
+		}
+	}
+	return "", false
+}
+
+// dkimRelaxedHeader relaxed 헤더 정규화 (이름 소문자, 접힘 해제, 공백 축약)
+func dkimRelaxedHeader(header string) string {
+	colon := strings.IndexByte(header, ':')
+	name := strings.ToLower(strings.TrimSpace(header[:colon]))
+	value := strings.NewReplacer("\r\n", "").Replace(header[colon+1:])
+	return name + ":" + strings.Join(strings.Fields(value), " ")
+}
+
+// dkimRelaxedBody relaxed 본문 정규화 (줄 끝 공백 제거, 공백 축약, 끝의 빈 줄 제거)
+func dkimRelaxedBody(body []byte) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		lines[i] = strings.Join(strings.FieldsFunc(line, func(r rune) bool { return r == ' ' || r == '\t' }), " ")
+		if line != "" && (line[0] == ' ' || line[0] == '\t') {
+			lines[i] = " " + lines[i]
+		}
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+// foldDKIMValue 긴 서명 값을 76자 단위로 접기 (relaxed 정규화에서 무시되는 공백)
+func foldDKIMValue(value string) string {
+	var sb strings.Builder
+	for len(value) > 76 {
+		sb.WriteString(value[:76] + "\r\n ")
+		value = value[76:]
+	}
+	sb.WriteString(value)
+	return sb.String()
+}
@@ -0,0 +1,229 @@
+/*
+Email OAuth2 Module
+===================
+
+Gmail / Microsoft 365 SMTP XOAUTH2 인증 (email.oauth2)
+
+앱 비밀번호(basic auth) 없이 OAuth2 액세스 토큰으로 SMTP 인증합니다.
+액세스 토큰은 설정 파일에 저장한 refresh token 으로 발급받아 만료 전까지 재사용합니다.
+
+주요 기능:
+  - XOAUTH2 SASL 메커니즘 (smtp.Auth 구현, TLS 연결에서만 사용)
+  - 제공자별 기본값: google (oauth2.googleapis.com, smtp.gmail.com),
+    microsoft (login.microsoftonline.com/<tenant>, smtp.office365.com, SMTP.Send 스코프)
+  - 만료 1분 전 자동 갱신, 인증 실패 시 다음 전송에서 토큰 재발급
+  - 제공자가 새 refresh token 을 돌려주면 (Microsoft 토큰 순환) 설정 파일의 값을 교체
+*/
+package main
+
+import (
+	"encoding/json" // 토큰 응답 파싱
+	"errors"        // 인증 실패 에러
+	"fmt"           // 형식화된 I/O
+	"io"            // 응답 본문 읽기
+	"net/http"      // 토큰 엔드포인트
+	"net/smtp"      // smtp.Auth 인터페이스
+	"net/url"       // 폼 인코딩
+	"strings"       // 문자열 처리
+	"sync"          // 토큰 캐시 보호
+	"time"          // 토큰 만료
+)
+
+// OAuth2 제공자
+const (
+	OAuth2ProviderGoogle    = "google"
+	OAuth2ProviderMicrosoft = "microsoft"
+)
+
+// OAuth2 엔드포인트 기본값
+const (
+	GoogleOAuth2TokenURL    = "https://oauth2.googleapis.com/token"
+	MicrosoftOAuth2TokenURL = "https://login.microsoftonline.com/%s/oauth2/v2.0/token" // %s: 테넌트 (기본 common)
+	MicrosoftSMTPScope      = "https://outlook.office.com/SMTP.Send offline_access"
+	MicrosoftSMTPServer     = "smtp.office365.com"
+	oauth2TokenRefreshSkew  = time.Minute // 만료 전 미리 갱신하는 여유 시간
+)
+
+// EmailOAuth2Config SMTP XOAUTH2 설정 (설정 파일 email.oauth2)
+type EmailOAuth2Config struct {
+	Provider     string `json:"provider"`      // google, microsoft
+	ClientID     string `json:"client_id"`     // OAuth2 클라이언트 ID
+	ClientSecret string `json:"client_secret"` // OAuth2 클라이언트 시크릿 (공개 클라이언트는 비움)
+	RefreshToken string `json:"refresh_token"` // 오프라인 액세스 refresh token (비어 있으면 XOAUTH2 비활성화)
+	Tenant       string `json:"tenant"`        // Microsoft 테넌트 ID/도메인 (기본 common)
+	TokenURL     string `json:"token_url"`     // 토큰 엔드포인트 (비어 있으면 제공자 기본값)
+	Scope        string `json:"scope"`         // 요청 스코프 (비어 있으면 제공자 기본값)
+}
+
+// Enabled refresh token 이 설정되어 XOAUTH2 를 사용하는지 확인
+func (c *EmailOAuth2Config) Enabled() bool {
+	return c != nil && strings.TrimSpace(c.RefreshToken) != ""
+}
+
+// Validate 제공자/클라이언트 ID 확인
+func (c *EmailOAuth2Config) Validate() error {
+	switch c.Provider {
+	case OAuth2ProviderGoogle, OAuth2ProviderMicrosoft:
+	case "":
+		if c.TokenURL == "" {
+			return fmt.Errorf("email oauth2: provider (google, microsoft) or token_url is required")
+		}
+	default:
+		if c.TokenURL == "" {
+			return fmt.Errorf("email oauth2: unknown provider %q (use google, microsoft or set token_url)", c.Provider)
+		}
+	}
+	if strings.TrimSpace(c.ClientID) == "" {
+		return fmt.Errorf("email oauth2: client_id is required")
+	}
+	return nil
+}
+
+// tokenURL 토큰 엔드포인트 (설정값 → 제공자 기본값)
+func (c *EmailOAuth2Config) tokenURL() string {
+	if c.TokenURL != "" {
+		return c.TokenURL
+	}
+	if c.Provider == OAuth2ProviderMicrosoft {
+		tenant := c.Tenant
+		if tenant == "" {
+			tenant = "common"
+		}
+		return fmt.Sprintf(MicrosoftOAuth2TokenURL, url.PathEscape(tenant))
+	}
+	return GoogleOAuth2TokenURL
+}
+
+// scope 요청 스코프 (Google 은 refresh token 발급 시 정한 스코프를 그대로 사용하므로 비움)
+func (c *EmailOAuth2Config) scope() string {
+	if c.Scope != "" || c.Provider != OAuth2ProviderMicrosoft {
+		return c.Scope
+	}
+	return MicrosoftSMTPScope
+}
+
+// OAuth2TokenSource refresh token 으로 액세스 토큰을 발급/캐시
+type OAuth2TokenSource struct {
+	config      EmailOAuth2Config
+	accessToken string
+	expiry      time.Time
+	onRotate    func(refreshToken string) // 제공자가 새 refresh token 을 돌려줬을 때 호출 (설정 파일 저장)
+	client      *http.Client
+	mutex       sync.Mutex
+}
+
+// NewOAuth2TokenSource 새로운 토큰 소스 생성
+func NewOAuth2TokenSource(config EmailOAuth2Config, onRotate func(string)) *OAuth2TokenSource {
+	return &OAuth2TokenSource{
+		config:   config,
+		onRotate: onRotate,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Token 유효한 액세스 토큰 반환 (없거나 곧 만료되면 갱신)
+func (ts *OAuth2TokenSource) Token() (string, error) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	if ts.accessToken != "" && time.Until(ts.expiry) > oauth2TokenRefreshSkew {
+		return ts.accessToken, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", ts.config.RefreshToken)
+	form.Set("client_id", ts.config.ClientID)
+	if ts.config.ClientSecret != "" {
+		form.Set("client_secret", ts.config.ClientSecret)
+	}
+	if scope := ts.config.scope(); scope != "" {
+		form.Set("scope", scope)
+	}
+
+	resp, err := ts.client.PostForm(ts.config.tokenURL(), form)
+	if err != nil {
+		return "", fmt.Errorf("oauth2 token refresh failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("oauth2 token refresh failed: %v", err)
+	}
+
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		RefreshToken     string `json:"refresh_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", fmt.Errorf("oauth2 token endpoint returned status %d: %v", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || token.AccessToken == "" {
+		if token.Error != "" {
+			return "", fmt.Errorf("oauth2 token refresh failed: %s: %s", token.Error, token.ErrorDescription)
+		}
+		return "", fmt.Errorf("oauth2 token endpoint returned status %d", resp.StatusCode)
+	}
+
+	ts.accessToken = token.AccessToken
+	ts.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	if token.RefreshToken != "" && token.RefreshToken != ts.config.RefreshToken {
+		ts.config.RefreshToken = token.RefreshToken
+		if ts.onRotate != nil {
+			ts.onRotate(token.RefreshToken)
+		}
+	}
+	return ts.accessToken, nil
+}
+
+// Invalidate 캐시된 액세스 토큰 폐기 (서버가 토큰을 거부했을 때)
+func (ts *OAuth2TokenSource) Invalidate() {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	ts.accessToken = ""
+}
+
+// RefreshToken 현재 refresh token (순환된 값 포함)
+func (ts *OAuth2TokenSource) RefreshToken() string {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+	return ts.config.RefreshToken
+}
+
+// xoauth2Auth XOAUTH2 SASL 메커니즘 (smtp.Auth 구현)
+type xoauth2Auth struct {
+	username string
+	host     string
+	source   *OAuth2TokenSource
+}
+
+// XOAuth2Auth XOAUTH2 smtp.Auth 생성 (username 은 메일박스 주소)
+func XOAuth2Auth(username, host string, source *OAuth2TokenSource) smtp.Auth {
+	return &xoauth2Auth{username: username, host: host, source: source}
+}
+
+// Start 초기 응답 "user=<주소>^Aauth=Bearer <토큰>^A^A" 전송 (PlainAuth 와 같이 TLS 연결에서만 허용)
+func (a *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && server.Name != "localhost" && server.Name != "127.0.0.1" && server.Name != "::1" {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	token, err := a.source.Token()
+	if err != nil {
+		return "", nil, err
+	}
+	return "XOAUTH2", []byte("user=" + a.username + "\x01auth=Bearer " + token + "\x01\x01"), nil
+}
+
+// Next 서버가 오류 JSON 을 챌린지로 보내면 토큰을 폐기하고 빈 응답으로 인증 실패를 마무리
+func (a *xoauth2Auth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if more {
+		a.source.Invalidate()
+		return []byte{}, nil
+	}
+	return nil, nil
+}
@@ -8,8 +8,12 @@ Gmail SMTP 최적화 이메일 알림 서비스
 - Gmail SMTP 서버 자동 감지 및 최적화
 - 다중 수신자 지원 (여러 명에게 동시 전송)
 - STARTTLS 및 SSL/TLS 연결 지원
-- SMTP 인증 및 보안 설정
+- SMTP 인증 및 보안 설정 (PLAIN 또는 Gmail/Microsoft 365 XOAUTH2, email_oauth2.go)
 - 이메일 전송 실패 시 상세 에러 처리
+- Date / Message-ID 헤더 및 선택적 DKIM 서명 (dkim.go)
+- 같은 인시던트 알림의 이메일 스레드 묶음 (email_thread.go)
+- 알림 본문 끝에 서명된 끄기/확인 링크 (alerts.actions, alert_actions.go)
+- AlertSink 인터페이스 구현 (AlertDispatcher 연동)
 
 지원 SMTP 설정:
 - Gmail: smtp.gmail.com:587 (STARTTLS)
@@ -21,14 +25,24 @@ package main
 import (
 	"crypto/tls" // TLS/SSL 암호화 연결
 	"fmt"        // 형식화된 I/O
+	"mime"       // 제목 인코딩 (RFC 2047)
+	"net/mail"   // 발신자 주소 파싱
 	"net/smtp"   // SMTP 클라이언트
 	"strings"    // 문자열 처리
+	"sync"       // 동기화 (설정 재로드)
+	"time"       // Date 헤더
 )
 
 // EmailService 이메일 전송 서비스
 type EmailService struct {
 	config *EmailConfig
-	logger Logger
+	dkim    *DKIMSigner    // DKIM 서명기 (nil이면 서명하지 않음)
+	threads *EmailThreader // 인시던트별 스레드 헤더 추적
+	oauth2  *OAuth2TokenSource // XOAUTH2 액세스 토큰 (nil 이면 PLAIN 인증)
+	actions *AlertActionLinks  // 본문 끝에 붙일 끄기/확인 링크 (nil 이면 붙이지 않음)
+	storeRefreshToken func(string) error // 순환된 refresh token 저장 (설정 파일)
+	logger  Logger
+	mutex   sync.RWMutex // 설정 교체 보호 (설정 재로드)
 }
 
 // Logger 인터페이스 정의
@@ -38,84 +52,223 @@ type Logger interface {
 }
 
 // NewEmailService 새로운 이메일 서비스 생성
+// DKIM 셀렉터와 키 파일이 설정되어 있으면 발신 메시지에 DKIM 서명 (키 로드 실패 시 서명 없이 전송)
 func NewEmailService(config *EmailConfig, logger Logger) *EmailService {
-	return &EmailService{
-		config: config,
-		logger: logger,
+	es := &EmailService{
+		config:  config,
+		threads: NewEmailThreader(),
+		logger:  logger,
 	}
+
+	if config.OAuth2.Enabled() {
+		es.oauth2 = NewOAuth2TokenSource(*config.OAuth2, es.rotateRefreshToken)
+		logger.Infof("🔐 SMTP XOAUTH2 authentication enabled (%s)", config.OAuth2.Provider)
+	}
+
+	if config.DKIMSelector != "" && config.DKIMKeyFile != "" {
+		domain := config.DKIMDomain
+		if domain == "" {
+			domain = emailDomain(config.From)
+		}
+		signer, err := NewDKIMSigner(domain, config.DKIMSelector, config.DKIMKeyFile)
+		if err != nil {
+			logger.Errorf("❌ DKIM signing disabled: %v", err)
+		} else {
+			es.dkim = signer
+			logger.Infof("✍️  DKIM signing enabled: %s._domainkey.%s", signer.Selector(), signer.Domain())
+		}
+	}
+	return es
+}
+
+// emailDomain 이메일 주소의 도메인 부분 ("Name <user@example.com>" 형식 포함)
+func emailDomain(address string) string {
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		address = parsed.Address
+	}
+	if at := strings.LastIndex(address, "@"); at >= 0 {
+		return strings.ToLower(strings.TrimSpace(address[at+1:]))
+	}
+	return ""
+}
+
+// currentConfig 현재 이메일 설정 반환 (재로드 시 통째로 교체되므로 전송 중에는 같은 값 사용)
+func (es *EmailService) currentConfig() *EmailConfig {
+	es.mutex.RLock()
+	defer es.mutex.RUnlock()
+	return es.config
+}
+
+// SetOAuth2 XOAUTH2 설정 교체 (설정 재로드, refresh token 이 비어 있으면 PLAIN 인증으로 전환)
+func (es *EmailService) SetOAuth2(oauth2 *EmailOAuth2Config) {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+	config := *es.config
+	config.OAuth2 = oauth2
+	es.config = &config
+	if !oauth2.Enabled() {
+		es.oauth2 = nil
+		return
+	}
+	// 방금 저장한 순환 토큰이 다시 읽힌 경우 캐시된 액세스 토큰 유지
+	if es.oauth2 != nil && es.oauth2.RefreshToken() == oauth2.RefreshToken && es.oauth2.config.ClientID == oauth2.ClientID {
+		return
+	}
+	es.oauth2 = NewOAuth2TokenSource(*oauth2, es.rotateRefreshToken)
+}
+
+// SetRefreshTokenStore 제공자가 새 refresh token 을 돌려줬을 때 저장할 함수 설정
+func (es *EmailService) SetRefreshTokenStore(store func(string) error) {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+	es.storeRefreshToken = store
+}
+
+// SetActionLinks 알림 본문에 붙일 끄기/확인 링크 서명기 설정
+func (es *EmailService) SetActionLinks(links *AlertActionLinks) {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+	es.actions = links
+}
+
+// rotateRefreshToken 순환된 refresh token 저장 (실패해도 메모리의 새 토큰으로 계속 전송)
+func (es *EmailService) rotateRefreshToken(refreshToken string) {
+	es.mutex.RLock()
+	store := es.storeRefreshToken
+	es.mutex.RUnlock()
+	if store == nil {
+		return
+	}
+	if err := store(refreshToken); err != nil {
+		es.logger.Errorf("❌ Failed to store rotated OAuth2 refresh token: %v", err)
+		return
+	}
+	es.logger.Infof("🔐 OAuth2 refresh token rotated and saved to config file")
+}
+
+// smtpAuth SMTP 인증 방식 (XOAUTH2 설정 시 XOAUTH2, 사용자/비밀번호가 있으면 PLAIN, 없으면 nil)
+func (es *EmailService) smtpAuth(config *EmailConfig, host string) smtp.Auth {
+	es.mutex.RLock()
+	source := es.oauth2
+	es.mutex.RUnlock()
+	if source != nil {
+		return XOAuth2Auth(config.Username, host, source)
+	}
+	if config.Username != "" && config.Password != "" {
+		return smtp.PlainAuth("", config.Username, config.Password, host)
+	}
+	return nil
+}
+
+// SetRecipients 수신자 목록 교체 (설정 재로드 시 사용)
+func (es *EmailService) SetRecipients(to []string) {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+	config := *es.config
+	config.To = append([]string(nil), to...)
+	es.config = &config
+}
+
+// Send AlertSink 구현 - 알림 제목과 상세 수준에 맞춰 렌더링한 본문을 이메일로 전송
+// Thread 키가 같은 알림은 In-Reply-To / References 헤더로 하나의 스레드에 묶음
+// 끄기/확인 링크가 설정되어 있으면 본문 끝에 붙임
+func (es *EmailService) Send(alert Alert) error {
+	config := es.currentConfig()
+	if alert.Target != "" {
+		// 라우팅 규칙이 지정한 수신자로 전송
+		routed := *config
+		routed.To = parseCommaList(alert.Target)
+		config = &routed
+	}
+	subject, headers := es.threads.Next(alert.Thread, alert.Title, es.messageDomain())
+	es.mutex.RLock()
+	actions := es.actions
+	es.mutex.RUnlock()
+	body := appendAlertActionLinks(alert.RenderText(alert.Detail), actions.Links(alert, time.Now()))
+	return es.sendEmail(config, subject, body, headers)
 }
 
 // SendEmail 이메일 전송 (Gmail 자동 감지)
 func (es *EmailService) SendEmail(subject, body string) error {
-	if !es.config.Enabled {
+	return es.sendEmail(es.currentConfig(), subject, body, emailHeaders{MessageID: newMessageID(es.messageDomain())})
+}
+
+// sendEmail 헤더를 지정해 이메일 전송 (config 의 수신자에게)
+func (es *EmailService) sendEmail(config *EmailConfig, subject, body string, headers emailHeaders) error {
+	if !config.Enabled {
 		return nil
 	}
 
 	// Gmail SMTP 서버 자동 감지 및 최적화된 전송
-	if es.config.SMTPServer == DefaultSMTPServer {
-		return es.sendGmailEmail(subject, body)
+	if config.SMTPServer == DefaultSMTPServer {
+		return es.sendGmailEmail(config, subject, body, headers)
 	}
 
 	// 일반 SMTP 서버 전송
-	return es.sendGenericEmail(subject, body)
+	return es.sendGenericEmail(config, subject, body, headers)
+}
+
+// messageDomain Message-ID 도메인 (DKIM 도메인 → 발신자 도메인 → 호스트명)
+func (es *EmailService) messageDomain() string {
+	if es.dkim != nil {
+		return es.dkim.Domain()
+	}
+	return emailDomain(es.currentConfig().From)
 }
 
 // sendGmailEmail Gmail SMTP 최적화 전송
-func (es *EmailService) sendGmailEmail(subject, body string) error {
+func (es *EmailService) sendGmailEmail(config *EmailConfig, subject, body string, headers emailHeaders) error {
 	// Gmail SMTP 서버로 전송 (포트 587, STARTTLS)
 	serverName := DefaultSMTPServer + ":" + DefaultSMTPPort
 
-	// 인증 설정
-	auth := smtp.PlainAuth("", es.config.Username, es.config.Password, DefaultSMTPServer)
+	// 인증 설정 (PLAIN 또는 XOAUTH2)
+	auth := es.smtpAuth(config, DefaultSMTPServer)
 
 	// 이메일 메시지 구성
-	message := es.buildEmailMessage(subject, body)
+	message := es.buildEmailMessage(config, subject, body, headers)
 
 	// Gmail SMTP 전송
-	err := smtp.SendMail(serverName, auth, es.config.From, es.config.To, []byte(message))
+	err := smtp.SendMail(serverName, auth, config.From, config.To, []byte(message))
 	if err != nil {
 		return fmt.Errorf("%s: %v", ErrEmailSendFailed, err)
 	}
 
-	es.logger.Infof("✅ Gmail email sent successfully to: %s", strings.Join(es.config.To, ", "))
+	es.logger.Infof("✅ Gmail email sent successfully to: %s", strings.Join(config.To, ", "))
 	return nil
 }
 
 // sendGenericEmail 범용 SMTP 서버 전송
-func (es *EmailService) sendGenericEmail(subject, body string) error {
-	message := es.buildEmailMessage(subject, body)
-	serverName := es.config.SMTPServer + ":" + es.config.SMTPPort
+func (es *EmailService) sendGenericEmail(config *EmailConfig, subject, body string, headers emailHeaders) error {
+	message := es.buildEmailMessage(config, subject, body, headers)
+	serverName := config.SMTPServer + ":" + config.SMTPPort
 
-	// 인증 설정
-	var auth smtp.Auth
-	if es.config.Username != "" && es.config.Password != "" {
-		auth = smtp.PlainAuth("", es.config.Username, es.config.Password, es.config.SMTPServer)
-	}
+	// 인증 설정 (PLAIN 또는 XOAUTH2)
+	auth := es.smtpAuth(config, config.SMTPServer)
 
 	// TLS 설정
 	tlsConfig := &tls.Config{
 		InsecureSkipVerify: false,
-		ServerName:         es.config.SMTPServer,
+		ServerName:         config.SMTPServer,
 	}
 
 	// 포트에 따라 다른 연결 방식 사용
-	if es.config.SMTPPort == SMTPPortSSL {
-		return es.sendWithSSL(serverName, auth, message, tlsConfig)
+	if config.SMTPPort == SMTPPortSSL {
+		return es.sendWithSSL(config, serverName, auth, message, tlsConfig)
 	}
 
 	// STARTTLS 연결 (포트 587)
-	return es.sendWithSTARTTLS(serverName, auth, message, tlsConfig)
+	return es.sendWithSTARTTLS(config, serverName, auth, message, tlsConfig)
 }
 
 // sendWithSSL SSL/TLS 직접 연결 (포트 465)
-func (es *EmailService) sendWithSSL(serverName string, auth smtp.Auth, message string, tlsConfig *tls.Config) error {
+func (es *EmailService) sendWithSSL(config *EmailConfig, serverName string, auth smtp.Auth, message string, tlsConfig *tls.Config) error {
 	conn, err := tls.Dial("tcp", serverName, tlsConfig)
 	if err != nil {
 		return fmt.Errorf("failed to connect to SMTP server (SSL): %v", err)
 	}
 	defer conn.Close()
 
-	client, err := smtp.NewClient(conn, es.config.SMTPServer)
+	client, err := smtp.NewClient(conn, config.SMTPServer)
 	if err != nil {
 		return fmt.Errorf("failed to create SMTP client: %v", err)
 	}
@@ -127,11 +280,11 @@ func (es *EmailService) sendWithSSL(serverName string, auth smtp.Auth, message s
 		}
 	}
 
-	return es.sendEmailMessage(client, message)
+	return es.sendEmailMessage(config, client, message)
 }
 
 // sendWithSTARTTLS STARTTLS 연결 (포트 587)
-func (es *EmailService) sendWithSTARTTLS(serverName string, auth smtp.Auth, message string, tlsConfig *tls.Config) error {
+func (es *EmailService) sendWithSTARTTLS(config *EmailConfig, serverName string, auth smtp.Auth, message string, tlsConfig *tls.Config) error {
 	client, err := smtp.Dial(serverName)
 	if err != nil {
 		return fmt.Errorf("failed to connect to SMTP server: %v", err)
@@ -152,18 +305,18 @@ func (es *EmailService) sendWithSTARTTLS(serverName string, auth smtp.Auth, mess
 		}
 	}
 
-	return es.sendEmailMessage(client, message)
+	return es.sendEmailMessage(config, client, message)
 }
 
 // sendEmailMessage SMTP 클라이언트를 통한 메시지 전송
-func (es *EmailService) sendEmailMessage(client *smtp.Client, message string) error {
+func (es *EmailService) sendEmailMessage(config *EmailConfig, client *smtp.Client, message string) error {
 	// 발신자 설정
-	if err := client.Mail(es.config.From); err != nil {
+	if err := client.Mail(config.From); err != nil {
 		return fmt.Errorf("failed to set sender: %v", err)
 	}
 
 	// 수신자 설정
-	for _, to := range es.config.To {
+	for _, to := range config.To {
 		if err := client.Rcpt(to); err != nil {
 			return fmt.Errorf("failed to set recipient %s: %v", to, err)
 		}
@@ -180,23 +333,46 @@ func (es *EmailService) sendEmailMessage(client *smtp.Client, message string) er
 		return fmt.Errorf("failed to write message: %v", err)
 	}
 
-	es.logger.Infof("✅ Email sent successfully to: %s", strings.Join(es.config.To, ", "))
+	es.logger.Infof("✅ Email sent successfully to: %s", strings.Join(config.To, ", "))
 	return nil
 }
 
-// buildEmailMessage 이메일 메시지 구성
-func (es *EmailService) buildEmailMessage(subject, body string) string {
-	message := fmt.Sprintf("From: %s\r\n", es.config.From)
-	message += fmt.Sprintf("To: %s\r\n", strings.Join(es.config.To, ","))
-	message += fmt.Sprintf("Subject: %s\r\n", subject)
+// buildEmailMessage 이메일 메시지 구성 (Date/Message-ID/스레드 헤더 포함, DKIM 설정 시 서명)
+func (es *EmailService) buildEmailMessage(config *EmailConfig, subject, body string, headers emailHeaders) string {
+
+	message := fmt.Sprintf("From: %s\r\n", config.From)
+	message += fmt.Sprintf("To: %s\r\n", strings.Join(config.To, ","))
+	message += fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", subject))
+	message += fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message += fmt.Sprintf("Message-ID: %s\r\n", headers.MessageID)
+	if headers.InReplyTo != "" {
+		message += fmt.Sprintf("In-Reply-To: %s\r\n", headers.InReplyTo)
+	}
+	if len(headers.References) > 0 {
+		// 긴 References 는 메시지 ID 단위로 접어서 줄 길이 제한(998자) 유지
+		message += fmt.Sprintf("References: %s\r\n", strings.Join(headers.References, "\r\n "))
+	}
+	message += "MIME-Version: 1.0\r\n"
 	message += "Content-Type: text/plain; charset=UTF-8\r\n"
+	message += "Content-Transfer-Encoding: 8bit\r\n"
 	message += "\r\n"
-	message += body
-	return message
+	// 서명한 본문이 전송 중 바뀌지 않도록 줄바꿈을 CRLF 로 통일
+	message += strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n")
+
+	if es.dkim == nil {
+		return message
+	}
+	signed, err := es.dkim.Sign([]byte(message))
+	if err != nil {
+		es.logger.Errorf("❌ DKIM signing failed, sending unsigned: %v", err)
+		return message
+	}
+	return string(signed)
 }
 
 // SendTestEmail 테스트 이메일 전송
 func (es *EmailService) SendTestEmail() error {
+	config := es.currentConfig()
 	subject := fmt.Sprintf("[TEST] %s - Test Email", AppName)
 	body := fmt.Sprintf(`📧 테스트 이메일
 ==============
@@ -213,10 +389,10 @@ func (es *EmailService) SendTestEmail() error {
 `,
 		AppName,
 		AppVersion,
-		fmt.Sprintf("%s", strings.Join(es.config.To, ", ")),
-		strings.Join(es.config.To, ", "),
-		es.config.SMTPServer,
-		es.config.SMTPPort,
+		fmt.Sprintf("%s", strings.Join(config.To, ", ")),
+		strings.Join(config.To, ", "),
+		config.SMTPServer,
+		config.SMTPPort,
 	)
 
 	return es.SendEmail(subject, body)
@@ -224,15 +400,15 @@ func (es *EmailService) SendTestEmail() error {
 
 // GetRecipientsCount 수신자 수 반환
 func (es *EmailService) GetRecipientsCount() int {
-	return len(es.config.To)
+	return len(es.currentConfig().To)
 }
 
 // GetRecipientsList 수신자 목록 반환
 func (es *EmailService) GetRecipientsList() string {
-	return strings.Join(es.config.To, ", ")
+	return strings.Join(es.currentConfig().To, ", ")
 }
 
 // IsEnabled 이메일 서비스 활성화 여부 확인
 func (es *EmailService) IsEnabled() bool {
-	return es.config.Enabled
+	return es.currentConfig().Enabled
 } 
\ No newline at end of file
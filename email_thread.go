@@ -0,0 +1,123 @@
+/*
+Email Threading Module
+======================
+
+같은 인시던트의 알림을 메일 클라이언트에서 하나의 스레드로 묶는 헤더 관리
+
+주요 기능:
+- 알림의 Thread 키(인시던트 키)별 첫 메시지와 최근 Message-ID 추적
+- 후속 알림/복구 알림에 In-Reply-To / References 헤더 추가 (RFC 5322)
+- 후속 알림 제목을 "Re: <첫 알림 제목>" 으로 통일 (Gmail 은 제목이 같아야 스레드로 묶음)
+- 일정 시간 알림이 없던 인시던트는 새 스레드로 시작
+*/
+package main
+
+import (
+	"crypto/rand" // Message-ID 난수
+	"fmt"         // 형식화된 I/O
+	"os"          // 호스트명 (Message-ID 도메인)
+	"sync"        // 동시 전송 보호
+	"time"        // 스레드 만료
+)
+
+// 이메일 스레드 설정
+const (
+	EmailThreadTTL           = 24 * time.Hour // 마지막 알림 후 이 시간이 지나면 새 스레드 시작
+	EmailThreadMaxReferences = 10             // References 헤더에 유지할 Message-ID 수 (첫 메시지 + 최근 메시지)
+	EmailThreadMaxThreads    = 1000           // 동시에 추적하는 스레드 수 상한
+)
+
+// emailHeaders 메시지별 식별/스레드 헤더
+type emailHeaders struct {
+	MessageID  string   // 이 메시지의 Message-ID ("<...>" 포함)
+	InReplyTo  string   // 직전 메시지 Message-ID (비어 있으면 생략)
+	References []string // 스레드의 이전 메시지 Message-ID 목록
+}
+
+// emailThread 인시던트 하나의 스레드 상태
+type emailThread struct {
+	subject    string    // 첫 알림 제목
+	references []string  // 첫 메시지 + 최근 메시지의 Message-ID
+	lastSent   time.Time // 마지막 알림 시각
+}
+
+// EmailThreader Thread 키별 이메일 스레드 추적기
+type EmailThreader struct {
+	threads map[string]*emailThread
+	mutex   sync.Mutex
+}
+
+// NewEmailThreader 새로운 스레드 추적기 생성
+func NewEmailThreader() *EmailThreader {
+	return &EmailThreader{threads: make(map[string]*emailThread)}
+}
+
+// Next 다음 메시지의 제목과 헤더 반환
+// key 가 비어 있거나 새 인시던트면 원래 제목과 새 Message-ID 만, 진행 중인 인시던트면 스레드 헤더까지 반환
+func (et *EmailThreader) Next(key, subject, domain string) (string, emailHeaders) {
+	headers := emailHeaders{MessageID: newMessageID(domain)}
+	if key == "" {
+		return subject, headers
+	}
+
+	et.mutex.Lock()
+	defer et.mutex.Unlock()
+
+	now := time.Now()
+	et.expire(now)
+
+	thread, ok := et.threads[key]
+	if !ok {
+		if len(et.threads) >= EmailThreadMaxThreads {
+			et.evictOldest()
+		}
+		et.threads[key] = &emailThread{
+			subject:    subject,
+			references: []string{headers.MessageID},
+			lastSent:   now,
+		}
+		return subject, headers
+	}
+
+	headers.InReplyTo = thread.references[len(thread.references)-1]
+	headers.References = append([]string(nil), thread.references...)
+
+	// 첫 메시지는 항상 유지하고 나머지는 최근 것만 남김
+	thread.references = append(thread.references, headers.MessageID)
+	if len(thread.references) > EmailThreadMaxReferences {
+		thread.references = append(thread.references[:1], thread.references[len(thread.references)-EmailThreadMaxReferences+1:]...)
+	}
+	thread.lastSent = now
+	return "Re: " + thread.subject, headers
+}
+
+// expire 만료된 스레드 제거 (호출자가 잠금 보유)
+func (et *EmailThreader) expire(now time.Time) {
+	for key, thread := range et.threads {
+		if now.Sub(thread.lastSent) > EmailThreadTTL {
+			delete(et.threads, key)
+		}
+	}
+}
+
+// evictOldest 가장 오래 조용한 스레드 제거 (호출자가 잠금 보유)
+func (et *EmailThreader) evictOldest() {
+	var oldestKey string
+	var oldest time.Time
+	for key, thread := range et.threads {
+		if oldestKey == "" || thread.lastSent.Before(oldest) {
+			oldestKey, oldest = key, thread.lastSent
+		}
+	}
+	delete(et.threads, oldestKey)
+}
+
+// newMessageID 전역적으로 유일한 Message-ID 생성 (도메인이 없으면 호스트명 사용)
+func newMessageID(domain string) string {
+	if domain == "" {
+		domain, _ = os.Hostname()
+	}
+	random := make([]byte, 8)
+	rand.Read(random)
+	return fmt.Sprintf("<%d.%x@%s>", time.Now().UnixNano(), random, domain)
+}
@@ -0,0 +1,231 @@
+/*
+Event Sampler Module
+====================
+
+이상 탐지 모델 학습/평가용 파싱 이벤트 층화 표본 추출 (-sample-export)
+
+필터를 통과한 이벤트를 레벨 × 이상 패턴 층(stratum)으로 나누고, 층마다 같은 수까지 저수지 표본 추출
+(reservoir sampling)로 골라 종료 시 JSONL 로 저장합니다. 드문 ERROR/공격 패턴 이벤트가 대량의 INFO
+이벤트에 묻히지 않도록 층별 표본 수를 맞춥니다.
+
+주요 기능:
+- 층: 레벨 (INFO, WARNING, ERROR, CRITICAL) × AI 분석에서 가장 심각한 일치 패턴 (없으면 normal)
+- 재현 가능: 같은 입력과 같은 시드(-sample-seed)면 같은 표본 (-replay 로 보관 로그에서 추출 권장)
+- 비밀 가림: 비밀번호/토큰/API 키/Authorization 헤더/URL 자격 증명/개인 키/JWT/SQL 비밀번호 값을 [REDACTED] 로 바꿈
+- 레코드마다 층 가중치 (층에서 본 이벤트 수 / 표본 수) 포함 — 원래 분포로 되돌려 평가할 때 사용
+*/
+package main
+
+import (
+	"encoding/json" // JSONL 인코딩
+	"math/rand"     // 시드 고정 표본 추출
+	"os"            // 파일 저장
+	"path/filepath" // 임시 파일 경로
+	"regexp"        // 비밀 패턴
+	"sort"          // 출력 순서
+	"strings"       // 문자열 처리
+	"sync"          // 동기화
+)
+
+// 표본 추출 기본값
+const (
+	DefaultSamplePerStratum = 500 // 층마다 최대 표본 수
+	DefaultSampleSeed       = 1   // 기본 시드 (재현 가능하도록 고정)
+	SamplePatternNormal     = "normal"
+	sampleRedacted          = "[REDACTED]"
+)
+
+// 비밀 값 패턴 (값 부분만 가림)
+var (
+	secretKeyValueRegex   = regexp.MustCompile(`(?i)\b((?:password|passwd|pwd|secret|token|api[_-]?key|apikey|access[_-]?key|secret[_-]?key|client[_-]?secret|auth[_-]?token|session[_-]?id|private[_-]?key)["']?\s*[:=]\s*["']?)([^\s"'&,;]+)`)
+	secretAuthHeaderRegex = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]{8,}`)
+	secretURLCredRegex    = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@]+:)[^@\s/]+@`)
+	secretTokenRegex      = regexp.MustCompile(`\b(?:(?:AKIA|ASIA)[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{36,}|xox[abpr]-[A-Za-z0-9-]{10,}|sk-[A-Za-z0-9]{20,}|eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,})`)
+	secretPrivateKeyRegex = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----(?s:.*?)(?:-----END [A-Z ]*PRIVATE KEY-----|$)`)
+	secretFieldNameRegex  = regexp.MustCompile(`(?i)(pass|secret|token|api_?key|authorization|cookie|session)`)
+)
+
+// SampledEvent 표본 레코드 (구조화 출력 레코드 + 층 정보)
+type SampledEvent struct {
+	Stratum  string   `json:"stratum"` // "레벨/패턴"
+	Pattern  string   `json:"pattern"` // 가장 심각한 일치 패턴 (없으면 normal)
+	Rules    []string `json:"rules,omitempty"`
+	Sequence int64    `json:"seq"`    // 관찰한 이벤트 중 순번 (0부터)
+	Weight   float64  `json:"weight"` // 층에서 본 이벤트 수 / 층 표본 수
+	*LogRecord
+}
+
+// sampleStratum 층 하나의 저수지
+type sampleStratum struct {
+	seen  int64
+	items []*SampledEvent
+}
+
+// EventSampler 층화 저수지 표본 추출기
+type EventSampler struct {
+	path       string
+	perStratum int
+	seed       int64
+	rng        *rand.Rand
+	strata     map[string]*sampleStratum
+	observed   int64
+	logger     Logger
+	mutex      sync.Mutex
+}
+
+// NewEventSampler 표본 추출기 생성 (perStratum 이 1 미만이면 기본값)
+func NewEventSampler(path string, perStratum int, seed int64, logger Logger) *EventSampler {
+	if perStratum < 1 {
+		perStratum = DefaultSamplePerStratum
+	}
+	return &EventSampler{
+		path:       path,
+		perStratum: perStratum,
+		seed:       seed,
+		rng:        rand.New(rand.NewSource(seed)),
+		strata:     make(map[string]*sampleStratum),
+		logger:     logger,
+	}
+}
+
+// Path 표본 파일 경로
+func (es *EventSampler) Path() string {
+	return es.path
+}
+
+// Observe 이벤트 하나를 층의 저수지에 반영 (알림 단계에서 입력 순서대로 호출되어야 재현 가능)
+func (es *EventSampler) Observe(record *LogRecord, aiResult *AIAnalysisResult) {
+	pattern := SamplePatternNormal
+	var rules []string
+	if aiResult != nil && len(aiResult.MatchedRules) > 0 {
+		pattern = aiResult.MatchedRules[0].Name
+		for _, rule := range aiResult.MatchedRules {
+			rules = append(rules, rule.Name)
+		}
+	}
+	key := record.Level + "/" + pattern
+
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	sequence := es.observed
+	es.observed++
+	stratum := es.strata[key]
+	if stratum == nil {
+		stratum = &sampleStratum{}
+		es.strata[key] = stratum
+	}
+	stratum.seen++
+
+	slot := len(stratum.items)
+	if slot >= es.perStratum {
+		// 저수지가 차면 seen 개 중 perStratum 개가 남을 확률로 교체 (Algorithm R)
+		slot = int(es.rng.Int63n(stratum.seen))
+		if slot >= es.perStratum {
+			return
+		}
+	}
+	event := &SampledEvent{Stratum: key, Pattern: pattern, Rules: rules, Sequence: sequence, LogRecord: redactLogRecord(record)}
+	if slot == len(stratum.items) {
+		stratum.items = append(stratum.items, event)
+	} else {
+		stratum.items[slot] = event
+	}
+}
+
+// Close 표본을 층 이름, 순번 순서로 JSONL 파일에 저장 (임시 파일에 쓴 뒤 교체)
+func (es *EventSampler) Close() error {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	keys := make([]string, 0, len(es.strata))
+	for key := range es.strata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	temp, err := os.CreateTemp(filepath.Dir(es.path), "."+filepath.Base(es.path)+".*")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(temp)
+	encoder.SetEscapeHTML(false)
+	written := 0
+	for _, key := range keys {
+		stratum := es.strata[key]
+		sort.Slice(stratum.items, func(i, j int) bool { return stratum.items[i].Sequence < stratum.items[j].Sequence })
+		weight := float64(stratum.seen) / float64(len(stratum.items))
+		for _, event := range stratum.items {
+			event.Weight = weight
+			if err := encoder.Encode(event); err != nil {
+				temp.Close()
+				os.Remove(temp.Name())
+				return err
+			}
+			written++
+		}
+	}
+	if err := temp.Close(); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	if err := os.Rename(temp.Name(), es.path); err != nil {
+		os.Remove(temp.Name())
+		return err
+	}
+	es.logger.Infof("🧪 Sampled %d of %d events in %d strata (seed %d) → %s", written, es.observed, len(keys), es.seed, es.path)
+	return nil
+}
+
+// redactLogRecord 비밀 값을 가린 레코드 사본
+func redactLogRecord(record *LogRecord) *LogRecord {
+	redacted := *record
+	redacted.Raw = redactSecrets(record.Raw)
+	redacted.Message = redactSecrets(record.Message)
+	redacted.Fields = redactSecretFields(record.Fields)
+	if record.Login != nil {
+		login := *record.Login
+		login.Command = redactSecrets(login.Command)
+		login.Enrichment = redactSecretFields(login.Enrichment)
+		redacted.Login = &login
+	}
+	return &redacted
+}
+
+// redactSecretFields 이름이 비밀처럼 보이는 필드는 통째로, 나머지는 값 안의 비밀만 가림
+func redactSecretFields(fields map[string]string) map[string]string {
+	if fields == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(fields))
+	for name, value := range fields {
+		if secretFieldNameRegex.MatchString(name) && value != "" {
+			redacted[name] = sampleRedacted
+		} else {
+			redacted[name] = redactSecrets(value)
+		}
+	}
+	return redacted
+}
+
+// redactSecrets 문자열 안의 비밀 값 가림
+func redactSecrets(text string) string {
+	if text == "" {
+		return text
+	}
+	text = secretPrivateKeyRegex.ReplaceAllString(text, sampleRedacted)
+	text = secretURLCredRegex.ReplaceAllString(text, "${1}"+sampleRedacted+"@")
+	text = secretAuthHeaderRegex.ReplaceAllString(text, "${1} "+sampleRedacted)
+	text = secretTokenRegex.ReplaceAllString(text, sampleRedacted)
+	text = secretKeyValueRegex.ReplaceAllStringFunc(text, func(match string) string {
+		parts := secretKeyValueRegex.FindStringSubmatch(match)
+		if parts[2] == sampleRedacted {
+			return match
+		}
+		return parts[1] + sampleRedacted
+	})
+	if strings.Contains(strings.ToUpper(text), "PASSWORD") {
+		text = maskDBPasswords(text)
+	}
+	return text
+}
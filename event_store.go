@@ -0,0 +1,511 @@
+/*
+Event Store Module
+==================
+
+SQLite 기반 알림/이벤트 히스토리 저장소
+
+전송 후 사라지던 로그인 이벤트, 시스템 알림, AI 분석 결과를 SQLite 파일에 보관하고
+시간 범위, 사용자, IP, 심각도로 조회할 수 있게 합니다.
+
+주요 기능:
+- LoginInfo, SystemAlert, AIAnalysisResult 기록 (원본 레코드는 JSON 컬럼에 보관)
+- 알림 확인/끄기 기록 (누가 어떤 경로로 어떤 알림을 언제까지 껐는지)
+- 백그라운드 배치 저장 (최대 100건 또는 5초마다 한 트랜잭션, 실패하면 한 건씩 다시 저장)
+- 보존 기간이 지난 이벤트 삭제 (-db-retention-days, 1시간마다)
+- 시간 범위/종류/사용자/IP/심각도 조건 조회 (history 하위 명령)
+- 사용자별 로그인 ASN 히스토리 보관 (ASN 변경 탐지 기준선, 재시작 후에도 유지)
+
+구현 참고:
+  - cgo 드라이버 의존성 없이 시스템 sqlite3 CLI 를 하위 프로세스로 사용
+    (macOS 기본 포함, Linux 는 sqlite3 패키지 설치 필요, JSON 출력을 위해 3.33 이상)
+*/
+package main
+
+import (
+	"bytes"         // 명령 출력 버퍼
+	"encoding/json" // 레코드 JSON 직렬화
+	"fmt"           // 형식화된 I/O
+	"os"            // 디렉토리 생성
+	"os/exec"       // sqlite3 실행
+	"path/filepath" // 경로 처리
+	"strconv"       // 삭제 건수 파싱
+	"strings"       // 문자열 처리
+	"sync"          // 동기화
+	"time"          // 시간 처리
+)
+
+// 이벤트 종류
+const (
+	EventKindLogin  = "login"
+	EventKindSystem = "system"
+	EventKindAI     = "ai"
+	EventKindAction = "alert_action" // 알림 확인/끄기 (Slack 버튼, 이메일 링크)
+)
+
+// 이벤트 저장소 설정
+const (
+	EventStoreBatchSize     = 100             // 한 트랜잭션에 저장할 최대 레코드 수
+	EventStoreFlushInterval = 5 * time.Second // 배치 저장 간격
+	EventStoreQueueSize     = 1000            // 저장 대기 큐 크기 (가득 차면 레코드 버림)
+	EventStoreBusyTimeout   = 5000            // 다른 프로세스가 잠근 경우 대기 시간 (ms)
+	EventStorePruneInterval = 1 * time.Hour   // 보존 기간이 지난 이벤트 삭제 간격
+)
+
+// eventStoreSchema 테이블 및 조회 조건별 인덱스
+const eventStoreSchema = `
+CREATE TABLE IF NOT EXISTS events (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	kind      TEXT    NOT NULL,
+	timestamp INTEGER NOT NULL,
+	severity  TEXT    NOT NULL,
+	host      TEXT    NOT NULL DEFAULT '',
+	user      TEXT    NOT NULL DEFAULT '',
+	ip        TEXT    NOT NULL DEFAULT '',
+	summary   TEXT    NOT NULL DEFAULT '',
+	data      TEXT    NOT NULL DEFAULT '{}'
+);
+CREATE INDEX IF NOT EXISTS idx_events_timestamp ON events(timestamp);
+CREATE INDEX IF NOT EXISTS idx_events_user ON events(user);
+CREATE INDEX IF NOT EXISTS idx_events_ip ON events(ip);
+CREATE INDEX IF NOT EXISTS idx_events_severity ON events(severity);
+CREATE TABLE IF NOT EXISTS user_asns (
+	user       TEXT    NOT NULL,
+	asn        TEXT    NOT NULL,
+	org        TEXT    NOT NULL DEFAULT '',
+	first_seen INTEGER NOT NULL,
+	last_seen  INTEGER NOT NULL,
+	logins     INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (user, asn)
+);
+`
+
+// EventRecord 저장된 알림/이벤트 레코드
+type EventRecord struct {
+	ID        int64           `json:"id"`
+	Kind      string          `json:"kind"` // login, system, ai, alert_action
+	Timestamp time.Time       `json:"timestamp"`
+	Severity  string          `json:"severity"` // info, warning, critical
+	Host      string          `json:"host,omitempty"`
+	User      string          `json:"user,omitempty"`
+	IP        string          `json:"ip,omitempty"`
+	Summary   string          `json:"summary"`
+	Data      json.RawMessage `json:"data,omitempty"` // 원본 레코드 JSON
+}
+
+// EventFilter 히스토리 조회 조건 (빈 값은 조건 없음)
+type EventFilter struct {
+	Since    time.Time
+	Until    time.Time
+	Kind     string
+	User     string
+	IP       string
+	Severity string
+	Limit    int
+}
+
+// EventStore sqlite3 CLI 기반 이벤트 저장소
+type EventStore struct {
+	path    string
+	sqlite  string // sqlite3 실행 파일 경로
+	logger  Logger
+	pending chan string   // 저장 대기 중인 SQL 문
+	retain  time.Duration // 이벤트 보존 기간 (0 이면 삭제하지 않음)
+	done    chan struct{}
+	wg      sync.WaitGroup
+	closed  sync.Once
+}
+
+// OpenEventStore 이벤트 저장소 열기 (파일과 테이블이 없으면 생성)
+func OpenEventStore(path string, logger Logger) (*EventStore, error) {
+	sqlite, err := exec.LookPath("sqlite3")
+	if err != nil {
+		return nil, fmt.Errorf("sqlite3 command not found (install sqlite3 to use -db-path): %v", err)
+	}
+
+	path = expandHomePath(path)
+	if err := os.MkdirAll(filepath.Dir(path), ConfigPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create event store directory: %v", err)
+	}
+
+	store := &EventStore{
+		path:    path,
+		sqlite:  sqlite,
+		logger:  logger,
+		pending: make(chan string, EventStoreQueueSize),
+		done:    make(chan struct{}),
+	}
+	if _, err := store.exec(eventStoreSchema); err != nil {
+		return nil, fmt.Errorf("failed to initialize event store: %v", err)
+	}
+	return store, nil
+}
+
+// Path 저장소 파일 경로
+func (es *EventStore) Path() string {
+	return es.path
+}
+
+// SetRetention 이벤트 보존 기간 설정 (Start 전에 호출, 0 이면 삭제하지 않음)
+// 사용자별 ASN 히스토리는 탐지 기준선이므로 삭제하지 않음
+func (es *EventStore) SetRetention(retention time.Duration) {
+	es.retain = retention
+}
+
+// Start 백그라운드 배치 저장 시작
+func (es *EventStore) Start() {
+	es.wg.Add(1)
+	go es.writeLoop()
+}
+
+// Close 대기 중인 레코드를 모두 저장하고 종료
+func (es *EventStore) Close() {
+	es.closed.Do(func() {
+		close(es.done)
+		es.wg.Wait()
+	})
+}
+
+// RecordLogin 로그인 이벤트 기록
+func (es *EventStore) RecordLogin(info *LoginInfo, host, severity string) {
+	summary := fmt.Sprintf("%s %s", info.Status, info.User)
+	if info.IP != "" {
+		summary += " from " + info.IP
+	}
+	if info.Command != "" {
+		summary += ": " + info.Command
+	}
+	if info.BruteForceSuspected {
+		summary += fmt.Sprintf(" (after %d failed attempts)", info.PriorFailures)
+	}
+	if info.ASNChange != nil {
+		summary += fmt.Sprintf(" (new hosting ASN %s %s)", info.ASNChange.ASN, info.ASNChange.Provider)
+	}
+
+	es.enqueue(EventRecord{
+		Kind:      EventKindLogin,
+		Timestamp: info.Timestamp,
+		Severity:  severity,
+		Host:      host,
+		User:      info.User,
+		IP:        info.IP,
+		Summary:   summary,
+	}, info.ToMap())
+}
+
+// RecordSystemAlert 시스템 알림 기록
+func (es *EventStore) RecordSystemAlert(alert SystemAlert, severity string) {
+	es.enqueue(EventRecord{
+		Kind:      EventKindSystem,
+		Timestamp: alert.Timestamp,
+		Severity:  severity,
+		Host:      alert.Metrics.IPInfo.Hostname,
+		Summary:   fmt.Sprintf("[%s] %s", alert.Type, alert.Message),
+	}, alert)
+}
+
+// RecordAIResult AI 분석 결과 기록
+func (es *EventStore) RecordAIResult(result *AIAnalysisResult, severity string) {
+	es.enqueue(EventRecord{
+		Kind:      EventKindAI,
+		Timestamp: result.Timestamp,
+		Severity:  severity,
+		Host:      result.SystemInfo.ComputerName,
+		Summary:   fmt.Sprintf("%s anomaly score %.1f (confidence %.0f%%)", result.ThreatLevel, result.AnomalyScore, result.Confidence*100),
+	}, result)
+}
+
+// RecordAlertAction 알림 확인/끄기 기록 (via: slack, email)
+func (es *EventStore) RecordAlertAction(action AlertAction, via string, until time.Time) {
+	es.enqueue(EventRecord{
+		Kind:     EventKindAction,
+		Severity: AlertSeverityInfo,
+		User:     action.By,
+		Summary:  fmt.Sprintf("%s via %s until %s: %s", action.Action, via, until.Format("2006-01-02 15:04"), action.Title),
+	}, map[string]interface{}{
+		"key": action.Key, "action": action.Action, "title": action.Title,
+		"by": action.By, "via": via, "until": until,
+	})
+}
+
+// RecordUserASN 사용자의 로그인 ASN 기록 (처음 보는 ASN은 추가, 기존 ASN은 마지막 관측 시각/횟수 갱신)
+func (es *EventStore) RecordUserASN(entry UserASN) {
+	es.queue(fmt.Sprintf(
+		"INSERT INTO user_asns (user, asn, org, first_seen, last_seen, logins) VALUES (%s, %s, %s, %d, %d, 1) "+
+			"ON CONFLICT(user, asn) DO UPDATE SET org = excluded.org, last_seen = MAX(last_seen, excluded.last_seen), logins = logins + 1;",
+		sqlQuote(entry.User), sqlQuote(entry.ASN), sqlQuote(entry.Org), entry.FirstSeen.Unix(), entry.LastSeen.Unix()), "user ASN")
+}
+
+// LoadUserASNs 저장된 사용자별 로그인 ASN 히스토리 조회
+func (es *EventStore) LoadUserASNs() ([]UserASN, error) {
+	output, err := es.exec("SELECT user, asn, org, first_seen, last_seen, logins FROM user_asns;", "-json")
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+
+	var rows []struct {
+		User      string `json:"user"`
+		ASN       string `json:"asn"`
+		Org       string `json:"org"`
+		FirstSeen int64  `json:"first_seen"`
+		LastSeen  int64  `json:"last_seen"`
+		Logins    int    `json:"logins"`
+	}
+	if err := json.Unmarshal(output, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse user ASN history: %v", err)
+	}
+
+	entries := make([]UserASN, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, UserASN{
+			User:      row.User,
+			ASN:       row.ASN,
+			Org:       row.Org,
+			FirstSeen: time.Unix(row.FirstSeen, 0),
+			LastSeen:  time.Unix(row.LastSeen, 0),
+			Logins:    row.Logins,
+		})
+	}
+	return entries, nil
+}
+
+// enqueue 원본 레코드를 JSON으로 붙여 저장 대기 큐에 추가
+func (es *EventStore) enqueue(record EventRecord, data interface{}) {
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now()
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		es.logger.Errorf("Failed to encode %s event for history: %v", record.Kind, err)
+		encoded = []byte("{}")
+	}
+
+	es.queue(fmt.Sprintf(
+		"INSERT INTO events (kind, timestamp, severity, host, user, ip, summary, data) VALUES (%s, %d, %s, %s, %s, %s, %s, %s);",
+		sqlQuote(record.Kind), record.Timestamp.Unix(), sqlQuote(record.Severity), sqlQuote(record.Host),
+		sqlQuote(record.User), sqlQuote(record.IP), sqlQuote(record.Summary), sqlQuote(string(encoded))), record.Kind+" event")
+}
+
+// queue SQL 문을 저장 대기 큐에 추가 (큐가 가득 차면 버림)
+func (es *EventStore) queue(statement, what string) {
+	select {
+	case es.pending <- statement:
+	default:
+		es.logger.Errorf("⚠️  Event history queue full, dropping %s", what)
+	}
+}
+
+// writeLoop 배치 크기 또는 저장 간격마다 대기 중인 레코드를 한 트랜잭션으로 저장
+func (es *EventStore) writeLoop() {
+	defer es.wg.Done()
+
+	ticker := time.NewTicker(EventStoreFlushInterval)
+	defer ticker.Stop()
+
+	// 보존 기간이 없으면 nil 채널 (삭제하지 않음)
+	var pruneTick <-chan time.Time
+	prune := func() {
+		if deleted, err := es.Prune(time.Now().Add(-es.retain)); err != nil {
+			es.logger.Errorf("Failed to delete expired history events: %v", err)
+		} else if deleted > 0 {
+			es.logger.Infof("🗄️  보존 기간이 지난 이벤트 %d건을 삭제했습니다", deleted)
+		}
+	}
+	if es.retain > 0 {
+		pruneTicker := time.NewTicker(EventStorePruneInterval)
+		defer pruneTicker.Stop()
+		pruneTick = pruneTicker.C
+		prune()
+	}
+
+	batch := make([]string, 0, EventStoreBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := es.writeBatch(batch); err != nil {
+			es.logger.Errorf("Failed to store records in history: %v", err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case statement := <-es.pending:
+			batch = append(batch, statement)
+			if len(batch) >= EventStoreBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-pruneTick:
+			prune()
+		case <-es.done:
+			for {
+				select {
+				case statement := <-es.pending:
+					batch = append(batch, statement)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// writeBatch 대기 중인 SQL 문을 한 트랜잭션으로 실행
+// 한 문이라도 실패하면 -bail 로 트랜잭션 전체가 취소되므로 한 건씩 다시 실행해 나머지는 저장
+func (es *EventStore) writeBatch(statements []string) error {
+	var sql strings.Builder
+	sql.WriteString("BEGIN;\n")
+	for _, statement := range statements {
+		sql.WriteString(statement)
+		sql.WriteString("\n")
+	}
+	sql.WriteString("COMMIT;\n")
+
+	_, err := es.exec(sql.String())
+	if err == nil {
+		return nil
+	}
+	if len(statements) == 1 {
+		return fmt.Errorf("1 of 1 records failed: %v", err)
+	}
+
+	failed := 0
+	var lastErr error
+	for _, statement := range statements {
+		if _, err := es.exec(statement); err != nil {
+			failed++
+			lastErr = err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d records failed: %v", failed, len(statements), lastErr)
+	}
+	return nil
+}
+
+// Prune cutoff 이전 이벤트 삭제, 삭제한 수 반환
+func (es *EventStore) Prune(cutoff time.Time) (int, error) {
+	output, err := es.exec(fmt.Sprintf("DELETE FROM events WHERE timestamp < %d;\nSELECT changes();\n", cutoff.Unix()))
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected sqlite3 output %q", output)
+	}
+	return deleted, nil
+}
+
+// Query 조건에 맞는 레코드를 최신순으로 조회
+func (es *EventStore) Query(filter EventFilter) ([]EventRecord, error) {
+	var conditions []string
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("timestamp >= %d", filter.Since.Unix()))
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("timestamp <= %d", filter.Until.Unix()))
+	}
+	if filter.Kind != "" {
+		conditions = append(conditions, "kind = "+sqlQuote(filter.Kind))
+	}
+	if filter.User != "" {
+		conditions = append(conditions, "user = "+sqlQuote(filter.User))
+	}
+	if filter.IP != "" {
+		conditions = append(conditions, "ip = "+sqlQuote(filter.IP))
+	}
+	if filter.Severity != "" {
+		conditions = append(conditions, "severity = "+sqlQuote(strings.ToLower(filter.Severity)))
+	}
+
+	query := "SELECT id, kind, timestamp, severity, host, user, ip, summary, data FROM events"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY timestamp DESC, id DESC"
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+	query += ";"
+
+	output, err := es.exec(query, "-json")
+	if err != nil {
+		return nil, err
+	}
+	// 결과가 없으면 sqlite3 는 아무것도 출력하지 않음
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+
+	var rows []struct {
+		ID        int64  `json:"id"`
+		Kind      string `json:"kind"`
+		Timestamp int64  `json:"timestamp"`
+		Severity  string `json:"severity"`
+		Host      string `json:"host"`
+		User      string `json:"user"`
+		IP        string `json:"ip"`
+		Summary   string `json:"summary"`
+		Data      string `json:"data"`
+	}
+	if err := json.Unmarshal(output, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse event history: %v", err)
+	}
+
+	records := make([]EventRecord, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, EventRecord{
+			ID:        row.ID,
+			Kind:      row.Kind,
+			Timestamp: time.Unix(row.Timestamp, 0),
+			Severity:  row.Severity,
+			Host:      row.Host,
+			User:      row.User,
+			IP:        row.IP,
+			Summary:   row.Summary,
+			Data:      json.RawMessage(row.Data),
+		})
+	}
+	return records, nil
+}
+
+// exec SQL을 표준 입력으로 sqlite3 에 전달하고 출력 반환
+func (es *EventStore) exec(sql string, args ...string) ([]byte, error) {
+	cmdArgs := append([]string{"-bail", "-cmd", fmt.Sprintf(".timeout %d", EventStoreBusyTimeout)}, args...)
+	cmdArgs = append(cmdArgs, es.path)
+
+	cmd := exec.Command(es.sqlite, cmdArgs...)
+	cmd.Stdin = strings.NewReader(sql)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("sqlite3 failed: %v (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// sqlQuote SQL 문자열 리터럴로 변환 (작은따옴표 이스케이프, NUL 제거)
+func sqlQuote(value string) string {
+	value = strings.ReplaceAll(value, "\x00", "")
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// expandHomePath "~/" 로 시작하는 경로를 홈 디렉토리 기준으로 변환
+func expandHomePath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
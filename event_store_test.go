@@ -0,0 +1,152 @@
+package main
+
+import (
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// openTestEventStore 임시 디렉토리의 이벤트 저장소 (sqlite3 가 없으면 건너뜀)
+func openTestEventStore(t *testing.T) *EventStore {
+	t.Helper()
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 command not installed")
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	store, err := OpenEventStore(filepath.Join(t.TempDir(), "events.db"), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+// TestEventStoreInsertQuery 배치 저장 후 조건별 조회
+func TestEventStoreInsertQuery(t *testing.T) {
+	store := openTestEventStore(t)
+	base := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	store.Start()
+	store.enqueue(EventRecord{Kind: EventKindLogin, Timestamp: base, Severity: "info", Host: "web1", User: "alice", IP: "203.0.113.10", Summary: "success alice"},
+		map[string]string{"method": "publickey"})
+	store.enqueue(EventRecord{Kind: EventKindLogin, Timestamp: base.Add(time.Hour), Severity: "critical", Host: "web1", User: "O'Brien", IP: "198.51.100.7", Summary: "failed O'Brien; DROP TABLE events;"},
+		map[string]string{"note": "it's quoted"})
+	store.enqueue(EventRecord{Kind: EventKindSystem, Timestamp: base.Add(2 * time.Hour), Severity: "warning", Host: "db1", Summary: "[cpu] high"}, nil)
+	store.enqueue(EventRecord{Kind: EventKindLogin, Timestamp: base.Add(3 * time.Hour), Severity: "info", User: "alice", IP: "203.0.113.10", Summary: "success alice"}, nil)
+	store.Close() // 대기 중인 레코드를 모두 저장
+
+	summaries := func(records []EventRecord) string {
+		var result []string
+		for _, record := range records {
+			result = append(result, record.Summary)
+		}
+		return strings.Join(result, "|")
+	}
+
+	tests := []struct {
+		name   string
+		filter EventFilter
+		want   string
+	}{
+		{"all newest first", EventFilter{}, "success alice|[cpu] high|failed O'Brien; DROP TABLE events;|success alice"},
+		{"kind", EventFilter{Kind: EventKindSystem}, "[cpu] high"},
+		{"user with quote", EventFilter{User: "O'Brien"}, "failed O'Brien; DROP TABLE events;"},
+		{"ip", EventFilter{IP: "203.0.113.10", Limit: 1}, "success alice"},
+		{"severity case insensitive", EventFilter{Severity: "CRITICAL"}, "failed O'Brien; DROP TABLE events;"},
+		{"time range", EventFilter{Since: base.Add(time.Hour), Until: base.Add(2 * time.Hour)}, "[cpu] high|failed O'Brien; DROP TABLE events;"},
+		{"no match", EventFilter{User: "mallory"}, ""},
+	}
+	for _, test := range tests {
+		records, err := store.Query(test.filter)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got := summaries(records); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+
+	records, err := store.Query(EventFilter{User: "O'Brien"})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Query(O'Brien) = %v, %v", records, err)
+	}
+	record := records[0]
+	if record.ID == 0 || record.Host != "web1" || record.IP != "198.51.100.7" || !record.Timestamp.Equal(base.Add(time.Hour)) {
+		t.Errorf("record = %+v", record)
+	}
+	if string(record.Data) != `{"note":"it's quoted"}` {
+		t.Errorf("data = %s", record.Data)
+	}
+}
+
+// TestEventStoreBatchFallback 실패한 문이 있는 배치는 한 건씩 다시 저장해 나머지를 보존
+func TestEventStoreBatchFallback(t *testing.T) {
+	store := openTestEventStore(t)
+	insert := func(summary string) string {
+		return "INSERT INTO events (kind, timestamp, severity, summary) VALUES ('login', 1, 'info', " + sqlQuote(summary) + ");"
+	}
+
+	err := store.writeBatch([]string{insert("first"), "INSERT INTO missing_table VALUES (1);", insert("second"), insert("third")})
+	if err == nil || !strings.Contains(err.Error(), "1 of 4 records failed") {
+		t.Errorf("writeBatch error = %v, want 1 of 4 records failed", err)
+	}
+	records, err := store.Query(EventFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Errorf("stored %d records, want 3", len(records))
+	}
+
+	if err := store.writeBatch([]string{insert("fourth"), insert("fifth")}); err != nil {
+		t.Errorf("valid batch: %v", err)
+	}
+	if err := store.writeBatch([]string{"not sql"}); err == nil || !strings.Contains(err.Error(), "1 of 1 records failed") {
+		t.Errorf("single bad statement error = %v", err)
+	}
+}
+
+// TestEventStoreRetention 보존 기간이 지난 이벤트만 삭제하고 ASN 히스토리는 유지
+func TestEventStoreRetention(t *testing.T) {
+	store := openTestEventStore(t)
+	now := time.Now()
+	store.Start()
+	for _, age := range []time.Duration{100 * 24 * time.Hour, 40 * 24 * time.Hour, 2 * time.Hour} {
+		store.enqueue(EventRecord{Kind: EventKindSystem, Timestamp: now.Add(-age), Severity: "info", Summary: age.String()}, nil)
+	}
+	store.RecordUserASN(UserASN{User: "alice", ASN: "AS64500", FirstSeen: now.Add(-200 * 24 * time.Hour), LastSeen: now.Add(-200 * 24 * time.Hour)})
+	store.Close()
+
+	deleted, err := store.Prune(now.Add(-90 * 24 * time.Hour))
+	if err != nil || deleted != 1 {
+		t.Fatalf("Prune(90d) = %d, %v; want 1", deleted, err)
+	}
+	if deleted, err = store.Prune(now.Add(-90 * 24 * time.Hour)); err != nil || deleted != 0 {
+		t.Errorf("second Prune(90d) = %d, %v; want 0", deleted, err)
+	}
+
+	// 보존 기간을 설정하고 다시 열면 Start 시 바로 삭제
+	store, err = OpenEventStore(store.Path(), store.logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.SetRetention(30 * 24 * time.Hour)
+	store.Start()
+	store.Close()
+	records, err := store.Query(EventFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Summary != (2*time.Hour).String() {
+		t.Errorf("records after 30 day retention = %+v", records)
+	}
+
+	asns, err := store.LoadUserASNs()
+	if err != nil || len(asns) != 1 || asns[0].Logins != 1 {
+		t.Errorf("user ASN history = %+v, %v (must survive retention)", asns, err)
+	}
+}
@@ -0,0 +1,269 @@
+/*
+Windows Event Log Input Module
+==============================
+
+Windows 이벤트 로그 입력 지원 (Windows)
+
+syslog 파일이 없는 Windows 에서 `wevtutil qe` 로 System/Security 등의 채널을 주기적으로
+조회하고, 마지막으로 읽은 EventRecordID 이후의 이벤트만 기존 처리 파이프라인에 전달합니다.
+
+주요 기능:
+- 채널별 EventRecordID 북마크로 새 이벤트만 조회 (시작 시점 이후 이벤트부터)
+- RenderedXml 파싱: Provider → 서비스, Level → 로그 레벨, Computer → 호스트
+- 감사 실패(Audit Failure) 키워드는 경고 레벨로 승격
+- 기존 정규식 기반 감지기와 호환되도록 syslog 형식 라인 재구성
+- EventData 필드를 ParsedLog.Fields 로 전달
+*/
+package main
+
+import (
+	"bytes"        // wevtutil 출력 버퍼
+	"encoding/xml" // 이벤트 XML 파싱
+	"fmt"          // 형식화된 I/O
+	"os/exec"      // wevtutil 실행
+	"strconv"      // 문자열-숫자 변환
+	"strings"      // 문자열 처리
+	"sync"         // 종료 동기화
+	"time"         // 시간 처리
+)
+
+const (
+	// DefaultEventLogChannels 기본 구독 채널 (Security 는 관리자 권한 필요)
+	DefaultEventLogChannels = "System,Security"
+
+	eventLogPollInterval = 2 * time.Second // 채널 조회 간격
+	eventLogBatchSize    = 500             // 한 번에 조회할 최대 이벤트 수
+
+	// eventLogAuditFailure Keywords 의 감사 실패 비트 (0x0010000000000000)
+	eventLogAuditFailure = 0x0010000000000000
+)
+
+// EventLogReader wevtutil 폴링 기반 Windows 이벤트 로그 리더
+type EventLogReader struct {
+	channels  []string
+	bookmarks map[string]uint64 // 채널별 마지막으로 읽은 EventRecordID
+	failing   map[string]bool   // 조회 실패 중인 채널 (오류 로그 반복 방지)
+	entries   chan JournalEntry
+	stop      chan struct{}
+	stopOnce  sync.Once
+	logger    Logger
+}
+
+// eventLogXML wevtutil /e:Events 출력 루트
+type eventLogXML struct {
+	Events []eventLogRecord `xml:"Event"`
+}
+
+// eventLogRecord RenderedXml 이벤트 한 건
+type eventLogRecord struct {
+	System struct {
+		Provider struct {
+			Name string `xml:"Name,attr"`
+		} `xml:"Provider"`
+		EventID     string `xml:"EventID"`
+		Level       string `xml:"Level"`
+		Keywords    string `xml:"Keywords"`
+		TimeCreated struct {
+			SystemTime string `xml:"SystemTime,attr"`
+		} `xml:"TimeCreated"`
+		EventRecordID uint64 `xml:"EventRecordID"`
+		Execution     struct {
+			ProcessID string `xml:"ProcessID,attr"`
+		} `xml:"Execution"`
+		Channel  string `xml:"Channel"`
+		Computer string `xml:"Computer"`
+	} `xml:"System"`
+	EventData struct {
+		Data []struct {
+			Name  string `xml:"Name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"Data"`
+	} `xml:"EventData"`
+	RenderingInfo struct {
+		Message string `xml:"Message"`
+	} `xml:"RenderingInfo"`
+}
+
+// NewEventLogReader 채널별 현재 위치를 기록하고 폴링 시작
+// 시작 이전에 기록된 이벤트는 전달하지 않음
+func NewEventLogReader(channels []string, logger Logger) (*EventLogReader, error) {
+	if _, err := exec.LookPath("wevtutil"); err != nil {
+		return nil, fmt.Errorf("wevtutil not found (Windows Event Log input requires Windows): %v", err)
+	}
+
+	reader := &EventLogReader{
+		channels:  channels,
+		bookmarks: make(map[string]uint64),
+		failing:   make(map[string]bool),
+		entries:   make(chan JournalEntry, 100),
+		stop:      make(chan struct{}),
+		logger:    logger,
+	}
+
+	for _, channel := range channels {
+		latest, err := queryEventLog(channel, "/c:1", "/rd:true")
+		if err != nil {
+			return nil, fmt.Errorf("failed to open event log channel %s: %v", channel, err)
+		}
+		if len(latest) > 0 {
+			reader.bookmarks[channel] = latest[0].System.EventRecordID
+		}
+	}
+
+	go reader.poll()
+	return reader, nil
+}
+
+// Entries 이벤트 채널 (Stop 호출 시 닫힘)
+func (er *EventLogReader) Entries() <-chan JournalEntry {
+	return er.entries
+}
+
+// Stop 폴링 중지
+func (er *EventLogReader) Stop() {
+	er.stopOnce.Do(func() { close(er.stop) })
+}
+
+// poll 주기적으로 각 채널의 새 이벤트를 조회해 전달
+func (er *EventLogReader) poll() {
+	defer close(er.entries)
+
+	ticker := time.NewTicker(eventLogPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-er.stop:
+			return
+		case <-ticker.C:
+		}
+
+		for _, channel := range er.channels {
+			if !er.pollChannel(channel) {
+				return
+			}
+		}
+	}
+}
+
+// pollChannel 채널의 북마크 이후 이벤트를 조회해 전달 (중지 요청 시 false)
+func (er *EventLogReader) pollChannel(channel string) bool {
+	query := fmt.Sprintf("/q:*[System[(EventRecordID>%d)]]", er.bookmarks[channel])
+	records, err := queryEventLog(channel, query, fmt.Sprintf("/c:%d", eventLogBatchSize))
+	if err != nil {
+		if !er.failing[channel] {
+			er.logger.Errorf("Failed to read event log channel %s: %v", channel, err)
+			er.failing[channel] = true
+		}
+		return true
+	}
+	if er.failing[channel] {
+		er.logger.Infof("Event log channel %s is readable again", channel)
+		er.failing[channel] = false
+	}
+
+	for _, record := range records {
+		if record.System.EventRecordID > er.bookmarks[channel] {
+			er.bookmarks[channel] = record.System.EventRecordID
+		}
+		select {
+		case er.entries <- eventLogEntry(record):
+		case <-er.stop:
+			return false
+		}
+	}
+	return true
+}
+
+// queryEventLog wevtutil qe 실행 후 RenderedXml 출력 파싱
+func queryEventLog(channel string, args ...string) ([]eventLogRecord, error) {
+	cmdArgs := append([]string{"qe", channel, "/f:RenderedXml", "/e:Events"}, args...)
+	cmd := exec.Command("wevtutil", cmdArgs...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%v: %s", err, msg)
+		}
+		return nil, err
+	}
+	return parseEventLogXML(output)
+}
+
+// parseEventLogXML wevtutil /e:Events 출력을 이벤트 목록으로 변환
+func parseEventLogXML(data []byte) ([]eventLogRecord, error) {
+	var events eventLogXML
+	if err := xml.Unmarshal(data, &events); err != nil {
+		return nil, fmt.Errorf("failed to parse event XML: %v", err)
+	}
+	return events.Events, nil
+}
+
+// eventLogEntry 이벤트를 syslog 형식 라인과 ParsedLog 로 변환
+func eventLogEntry(record eventLogRecord) JournalEntry {
+	system := record.System
+
+	timestamp := time.Now()
+	if parsed, err := time.Parse(time.RFC3339Nano, system.TimeCreated.SystemTime); err == nil {
+		timestamp = parsed.Local()
+	}
+
+	// 다중 라인 메시지는 한 줄로 합침 (메시지 렌더링 실패 시 EventData 사용)
+	message := strings.Join(strings.Fields(record.RenderingInfo.Message), " ")
+	fields := map[string]string{
+		"channel":   system.Channel,
+		"event_id":  system.EventID,
+		"record_id": strconv.FormatUint(system.EventRecordID, 10),
+		"provider":  system.Provider.Name,
+		"hostname":  system.Computer,
+		"pid":       system.Execution.ProcessID,
+		"keywords":  system.Keywords,
+	}
+	var data []string
+	for _, item := range record.EventData.Data {
+		if item.Name == "" || item.Value == "" || item.Value == "-" {
+			continue
+		}
+		fields[item.Name] = item.Value
+		data = append(data, fmt.Sprintf("%s=%s", item.Name, item.Value))
+	}
+	if message == "" {
+		message = strings.Join(data, " ")
+	}
+
+	// 기존 감지기(에러 키워드, 서비스별 규칙)가 그대로 동작하도록 syslog 형식 재구성
+	service := fmt.Sprintf("%s[%s]", system.Provider.Name, system.EventID)
+	line := fmt.Sprintf("%s %s %s: %s", timestamp.Format(time.Stamp), system.Computer, service, message)
+
+	parsed := &ParsedLog{
+		Timestamp: timestamp,
+		LogType:   "eventlog",
+		Level:     eventLogLevel(system.Level, system.Keywords),
+		Source:    system.Provider.Name,
+		Message:   message,
+		RawLog:    line,
+		Fields:    fields,
+	}
+
+	return JournalEntry{Line: line, Parsed: parsed}
+}
+
+// eventLogLevel 이벤트 Level(1-5)을 로그 레벨로 변환
+// 보안 감사 이벤트는 Level 0 이므로 Keywords 의 감사 실패 비트로 판단
+func eventLogLevel(level, keywords string) string {
+	switch level {
+	case "1": // Critical
+		return LogLevelCritical
+	case "2": // Error
+		return LogLevelError
+	case "3": // Warning
+		return LogLevelWarning
+	case "5": // Verbose
+		return LogLevelDebug
+	}
+	if value, err := strconv.ParseUint(strings.TrimPrefix(keywords, "0x"), 16, 64); err == nil && value&eventLogAuditFailure != 0 {
+		return LogLevelWarning
+	}
+	return LogLevelInfo
+}
@@ -0,0 +1,335 @@
+/*
+Response Size Anomaly Module
+============================
+
+웹 응답 크기 이상 탐지 (데이터 유출 징후, -exfil-watch)
+
+주요 기능:
+- 파싱된 웹 접근 로그(HTTPDetails)의 성공 응답 크기를 엔드포인트별 분포로 학습 (로그 스케일 평균/표준편차)
+- 클라이언트 × 엔드포인트별 윈도우(기본 10분) 누적 다운로드량 집계
+- volume: 한 클라이언트가 한 엔드포인트에서 윈도우 안에 임계값(기본 1GB) 이상 내려받음
+- outlier: 충분히 학습된 엔드포인트(50건 이상)의 응답 하나가 평소 크기에서 크게 벗어남 (로그 스케일 z-점수 4 이상, 최소 10MB)
+- 같은 클라이언트 × 엔드포인트는 윈도우마다 한 번만 보고 (나머지는 다음 알림에 횟수로 표시)
+
+엔드포인트는 쿼리 문자열을 떼고 숫자/UUID/긴 hex 경로 조각을 {id} 로 바꿔 묶음
+(/api/users/123/export?fmt=csv → GET /api/users/{id}/export)
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"math"    // 로그 스케일 분포
+	"os"      // 호스트 이름
+	"regexp"  // 경로 조각 정규화
+	"strconv" // 알림 필드
+	"strings" // 문자열 처리
+	"sync"    // 동기화 (뮤텍스)
+	"time"    // 윈도우 처리
+)
+
+// 응답 크기 이상 종류
+const (
+	ExfilKindVolume  = "volume"
+	ExfilKindOutlier = "outlier"
+)
+
+// 응답 크기 이상 탐지 기본값
+const (
+	DefaultExfilVolumeMB    = 1024             // 클라이언트 × 엔드포인트 윈도우 누적 임계값 (MB)
+	DefaultExfilWindow      = 10               // 누적 윈도우 (분)
+	exfilOutlierZScore      = 4.0              // 로그 스케일 z-점수 임계값
+	exfilOutlierMinBytes    = 10 * 1024 * 1024 // 이보다 작은 응답은 outlier 로 보지 않음
+	exfilBaselineMinSamples = 50               // outlier 판정 전 필요한 엔드포인트 응답 수
+	maxExfilEndpoints       = 5000             // 분포를 학습할 최대 엔드포인트 수
+	maxExfilClients         = 20000            // 누적량을 추적할 최대 클라이언트 × 엔드포인트 수
+)
+
+var exfilIDSegmentRegex = regexp.MustCompile(`^(?:\d+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{16,})$`)
+
+// ExfiltrationEvent 응답 크기 이상 탐지 결과
+type ExfiltrationEvent struct {
+	Kind         string        // volume, outlier
+	Client       string        // 클라이언트 IP
+	Endpoint     string        // 정규화한 "메서드 경로"
+	Method       string        // 마지막 요청 메서드
+	URL          string        // 마지막 요청 URL
+	Status       int           // 마지막 요청 상태 코드
+	Bytes        int64         // 이번 응답 크기
+	WindowBytes  int64         // 윈도우 내 누적 다운로드량
+	Requests     int           // 윈도우 내 요청 수
+	TypicalBytes int64         // 엔드포인트의 평소 응답 크기 (로그 스케일 평균, 학습 전이면 0)
+	ZScore       float64       // 로그 스케일 z-점수 (outlier)
+	Window       time.Duration // 누적 윈도우
+	FirstSeen    time.Time     // 윈도우 내 첫 요청 시각
+	Suppressed   int           // 이전 알림 이후 알리지 않은 이상 수
+	Line         string        // 원본 접근 로그
+}
+
+// exfilSizeStats 엔드포인트 응답 크기 분포 (log1p(바이트) 의 Welford 평균/분산)
+type exfilSizeStats struct {
+	count int64
+	mean  float64
+	m2    float64
+}
+
+// exfilTransfer 윈도우 내 응답 한 건
+type exfilTransfer struct {
+	at    time.Time
+	bytes int64
+}
+
+// exfilClientWindow 클라이언트 × 엔드포인트 윈도우 누적량
+type exfilClientWindow struct {
+	transfers  []exfilTransfer // 오래된 순
+	total      int64
+	reported   time.Time // 마지막 보고 시각 (윈도우 동안 재보고 억제)
+	suppressed int
+}
+
+// ExfiltrationDetector 클라이언트/엔드포인트별 응답 크기 이상 탐지기
+type ExfiltrationDetector struct {
+	mutex       sync.Mutex
+	window      time.Duration
+	volumeBytes int64
+	endpoints   map[string]*exfilSizeStats    // 엔드포인트 -> 응답 크기 분포
+	clients     map[string]*exfilClientWindow // 클라이언트|엔드포인트 -> 윈도우 누적량
+}
+
+// NewExfiltrationDetector 누적 임계값(MB)과 윈도우(분)로 탐지기 생성 (0 이하 값은 기본값 사용)
+func NewExfiltrationDetector(volumeMB, windowMinutes int) *ExfiltrationDetector {
+	if volumeMB <= 0 {
+		volumeMB = DefaultExfilVolumeMB
+	}
+	if windowMinutes <= 0 {
+		windowMinutes = DefaultExfilWindow
+	}
+	return &ExfiltrationDetector{
+		window:      time.Duration(windowMinutes) * time.Minute,
+		volumeBytes: int64(volumeMB) * 1024 * 1024,
+		endpoints:   make(map[string]*exfilSizeStats),
+		clients:     make(map[string]*exfilClientWindow),
+	}
+}
+
+// Window 누적 윈도우
+func (ed *ExfiltrationDetector) Window() time.Duration {
+	return ed.window
+}
+
+// VolumeBytes 클라이언트 × 엔드포인트 윈도우 누적 임계값 (바이트)
+func (ed *ExfiltrationDetector) VolumeBytes() int64 {
+	return ed.volumeBytes
+}
+
+// Observe 파싱된 웹 접근 로그의 응답 크기 기록, 이상이면 탐지 결과 반환 (아니면 nil)
+// 성공(2xx) 응답만 다운로드로 집계
+func (ed *ExfiltrationDetector) Observe(parsedLog *ParsedLog, line string, at time.Time) *ExfiltrationEvent {
+	if parsedLog == nil || parsedLog.HTTPDetails == nil {
+		return nil
+	}
+	http := parsedLog.HTTPDetails
+	if http.ClientIP == "" || http.ResponseSize <= 0 || http.StatusCode < 200 || http.StatusCode >= 300 {
+		return nil
+	}
+	endpoint := exfilEndpoint(http.Method, http.URL)
+
+	ed.mutex.Lock()
+	defer ed.mutex.Unlock()
+
+	// 학습된 분포와 비교한 뒤 이번 응답을 분포에 반영
+	var typical int64
+	var zScore float64
+	stats := ed.endpoints[endpoint]
+	if stats == nil && len(ed.endpoints) < maxExfilEndpoints {
+		stats = &exfilSizeStats{}
+		ed.endpoints[endpoint] = stats
+	}
+	if stats != nil {
+		typical, zScore = stats.score(http.ResponseSize)
+		stats.add(http.ResponseSize)
+	}
+
+	key := http.ClientIP + "|" + endpoint
+	client := ed.clients[key]
+	if client == nil {
+		if len(ed.clients) >= maxExfilClients {
+			ed.sweep(at)
+		}
+		client = &exfilClientWindow{}
+		ed.clients[key] = client
+	}
+	client.prune(at, ed.window)
+	client.transfers = append(client.transfers, exfilTransfer{at: at, bytes: http.ResponseSize})
+	client.total += http.ResponseSize
+
+	kind := ""
+	switch {
+	case client.total >= ed.volumeBytes:
+		kind = ExfilKindVolume
+	case typical > 0 && zScore >= exfilOutlierZScore && http.ResponseSize >= exfilOutlierMinBytes:
+		kind = ExfilKindOutlier
+	default:
+		return nil
+	}
+
+	if !client.reported.IsZero() && at.Sub(client.reported) < ed.window {
+		client.suppressed++
+		return nil
+	}
+	client.reported = at
+	suppressed := client.suppressed
+	client.suppressed = 0
+
+	return &ExfiltrationEvent{
+		Kind:         kind,
+		Client:       http.ClientIP,
+		Endpoint:     endpoint,
+		Method:       http.Method,
+		URL:          http.URL,
+		Status:       http.StatusCode,
+		Bytes:        http.ResponseSize,
+		WindowBytes:  client.total,
+		Requests:     len(client.transfers),
+		TypicalBytes: typical,
+		ZScore:       zScore,
+		Window:       ed.window,
+		FirstSeen:    client.transfers[0].at,
+		Suppressed:   suppressed,
+		Line:         line,
+	}
+}
+
+// sweep 윈도우가 지난 클라이언트 누적량 정리 (추적 수가 한도에 도달했을 때)
+func (ed *ExfiltrationDetector) sweep(now time.Time) {
+	for key, client := range ed.clients {
+		client.prune(now, ed.window)
+		if len(client.transfers) == 0 && (client.reported.IsZero() || now.Sub(client.reported) >= ed.window) {
+			delete(ed.clients, key)
+		}
+	}
+}
+
+// prune 윈도우 밖의 응답 제거 (transfers 는 오래된 순으로 정렬되어 있음)
+func (cw *exfilClientWindow) prune(now time.Time, window time.Duration) {
+	cutoff := now.Add(-window)
+	start := 0
+	for start < len(cw.transfers) && cw.transfers[start].at.Before(cutoff) {
+		cw.total -= cw.transfers[start].bytes
+		start++
+	}
+	cw.transfers = cw.transfers[start:]
+}
+
+// add 응답 크기를 분포에 반영
+func (s *exfilSizeStats) add(size int64) {
+	value := math.Log1p(float64(size))
+	s.count++
+	delta := value - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (value - s.mean)
+}
+
+// score 학습된 분포 기준 평소 응답 크기와 z-점수 (학습이 부족하면 0, 0)
+func (s *exfilSizeStats) score(size int64) (int64, float64) {
+	if s.count < exfilBaselineMinSamples {
+		return 0, 0
+	}
+	typical := int64(math.Expm1(s.mean))
+	stddev := math.Sqrt(s.m2 / float64(s.count-1))
+	// 모든 응답 크기가 같은 엔드포인트는 표준편차가 0 이므로 하한을 둠 (e 배 차이 = z 1)
+	if stddev < 1 {
+		stddev = 1
+	}
+	return typical, (math.Log1p(float64(size)) - s.mean) / stddev
+}
+
+// exfilEndpoint 요청을 "메서드 경로" 엔드포인트로 정규화 (쿼리 문자열 제거, ID 조각은 {id})
+func exfilEndpoint(method, rawURL string) string {
+	path := rawURL
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if exfilIDSegmentRegex.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	if method == "" {
+		method = "GET"
+	}
+	return method + " " + strings.Join(segments, "/")
+}
+
+// formatByteSize 바이트 수를 사람이 읽기 쉬운 단위로 표시 (1024 단위)
+func formatByteSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value, exp := float64(bytes)/unit, 0
+	for value >= unit && exp < 4 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", value, "KMGTP"[exp])
+}
+
+// exfiltrationAlert 응답 크기 이상 알림 (volume 은 critical, outlier 는 warning)
+func exfiltrationAlert(event *ExfiltrationEvent) Alert {
+	host, _ := os.Hostname()
+
+	severity := AlertSeverityWarning
+	headline := fmt.Sprintf("📦 %s 가 %s 에서 평소보다 큰 응답(%s)을 받았습니다", event.Client, event.Endpoint, formatByteSize(event.Bytes))
+	if event.Kind == ExfilKindVolume {
+		severity = AlertSeverityCritical
+		headline = fmt.Sprintf("🚨 %s 가 %v 동안 %s 에서 %s 를 내려받았습니다 (데이터 유출 의심)", event.Client, event.Window, event.Endpoint, formatByteSize(event.WindowBytes))
+	}
+
+	typical := "학습 중"
+	if event.TypicalBytes > 0 {
+		typical = formatByteSize(event.TypicalBytes)
+	}
+	fields := []AlertField{
+		{Label: "유형", Value: event.Kind, Short: true},
+		{Label: "클라이언트", Value: event.Client, Short: true},
+		{Label: "엔드포인트", Value: event.Endpoint, Short: true},
+		{Label: "윈도우 누적", Value: fmt.Sprintf("%s / %d건 (%v)", formatByteSize(event.WindowBytes), event.Requests, event.Window), Short: true},
+		{Label: "이번 응답", Value: formatByteSize(event.Bytes), Short: true},
+		{Label: "평소 응답 크기", Value: typical, Short: true},
+		{Label: "첫 요청", Value: event.FirstSeen.Format("2006-01-02 15:04:05"), Short: true},
+	}
+	if event.Kind == ExfilKindOutlier {
+		fields = append(fields, AlertField{Label: "z-점수 (로그 스케일)", Value: fmt.Sprintf("%.1f", event.ZScore), Short: true})
+	}
+	if event.Suppressed > 0 {
+		fields = append(fields, AlertField{Label: "직전 알림 이후 추가 탐지", Value: fmt.Sprintf("%d회", event.Suppressed)})
+	}
+
+	return Alert{
+		Type:     AlertTypeExfiltration,
+		Severity: severity,
+		Title:    fmt.Sprintf("[%s EXFILTRATION %s] %s - %s %s", AppName, strings.ToUpper(event.Kind), host, event.Client, formatByteSize(event.WindowBytes)),
+		Headline: headline,
+		Sections: []AlertSection{
+			{Fields: fields, Summary: true},
+			{
+				Title: "🌐 마지막 요청",
+				Fields: []AlertField{
+					{Label: "요청", Value: fmt.Sprintf("%s %s (HTTP %d)", event.Method, event.URL, event.Status), Code: true},
+					{Label: "접근 로그", Value: event.Line, Code: true},
+				},
+			},
+		},
+		Host:   host,
+		Thread: alertThreadKey(AlertTypeExfiltration, host, event.Client),
+		Fields: map[string]string{
+			"kind":         event.Kind,
+			"client":       event.Client,
+			"endpoint":     event.Endpoint,
+			"bytes":        strconv.FormatInt(event.Bytes, 10),
+			"window_bytes": strconv.FormatInt(event.WindowBytes, 10),
+			"requests":     strconv.Itoa(event.Requests),
+		},
+	}
+}
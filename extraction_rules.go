@@ -0,0 +1,210 @@
+/*
+Extraction Rules Module
+=======================
+
+설정 파일의 사용자 정의 필드 추출 규칙 (logging.extraction_rules)
+
+Go 파서를 작성하지 않고도 자체 애플리케이션 로그를 구조화할 수 있도록
+이름 있는 캡처 그룹 정규식으로 ParsedLog.Fields 를 채웁니다.
+
+주요 기능:
+- 이름 있는 캡처 그룹 (?P<필드>...) 의 값을 Fields 에 저장 (빈 값은 제외)
+- 서비스 제한: syslog 태그 (sshd[123]: → sshd), journald 유닛/식별자, "*" 접미사는 접두사 일치
+- 특수 그룹: level → ParsedLog.Level, message → ParsedLog.Message
+- log_type 지정 시 자동 감지에 실패한(unknown) 로그의 유형 지정
+- 규칙은 순서대로 모두 적용 (뒤의 규칙이 같은 필드를 덮어씀)
+- pattern 대신 Logstash grok 식 (grok, pattern_definitions) 사용 가능 (grok.go)
+
+예:
+
+	{"name": "payment", "services": ["payment-api"], "log_type": "payment",
+	 "pattern": "order=(?P<order_id>\\d+) amount=(?P<amount>[\\d.]+) result=(?P<result>\\w+)"}
+	{"name": "nginx", "services": ["nginx"], "grok": "%{COMBINEDAPACHELOG}"}
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"regexp"  // 추출 정규식
+	"strings" // 문자열 처리
+)
+
+// extractionSyslogTagRegex syslog 형식 줄의 서비스 태그 ("Jan  2 15:04:05 host sshd[123]: ..." → sshd)
+var extractionSyslogTagRegex = regexp.MustCompile(`^(?:\w{3}\s+\d{1,2}\s+\d{2}:\d{2}:\d{2}|\d{4}-\d{2}-\d{2}T\S+)\s+\S+\s+([^\s\[:]+)(?:\[[^\]]*\])?:`)
+
+// ExtractionRule 사용자 정의 필드 추출 규칙 (설정 파일 logging.extraction_rules 항목)
+type ExtractionRule struct {
+	Name     string   `json:"name"`     // 규칙 이름 (오류 메시지 표시용)
+	Services []string `json:"services"` // 적용할 서비스 (비어 있으면 모든 로그, "*" 접미사는 접두사 일치)
+	Pattern  string   `json:"pattern"`  // 이름 있는 캡처 그룹 정규식 (줄 전체에서 검색)
+	LogType  string   `json:"log_type"` // 일치 시 unknown 로그에 지정할 유형 (선택)
+
+	Grok               string            `json:"grok,omitempty"`                // pattern 대신 사용할 Logstash grok 식 (%{PATTERN:field})
+	PatternDefinitions map[string]string `json:"pattern_definitions,omitempty"` // grok 사용자 패턴 (기본 패턴 라이브러리에 추가/재정의)
+}
+
+// compiledExtractionRule 컴파일된 추출 규칙
+type compiledExtractionRule struct {
+	name     string
+	services []string // 소문자
+	regex    *regexp.Regexp
+	fields   map[string]string // 그룹 이름 → 필드 이름 (grok 규칙, 없으면 그룹 이름 그대로)
+	logType  string
+}
+
+// CompileExtractionRules 추출 규칙 검증/컴파일 (이름 있는 캡처 그룹이 없으면 에러)
+func CompileExtractionRules(rules []ExtractionRule) ([]*compiledExtractionRule, error) {
+	compiled := make([]*compiledExtractionRule, 0, len(rules))
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		pattern, fields := rule.Pattern, map[string]string(nil)
+		switch {
+		case strings.TrimSpace(rule.Grok) != "" && strings.TrimSpace(rule.Pattern) != "":
+			return nil, fmt.Errorf("extraction rule %s: pattern and grok are mutually exclusive", name)
+		case strings.TrimSpace(rule.Grok) != "":
+			var err error
+			if pattern, fields, err = CompileGrok(rule.Grok, rule.PatternDefinitions); err != nil {
+				return nil, fmt.Errorf("extraction rule %s: %v", name, err)
+			}
+		case strings.TrimSpace(rule.Pattern) == "":
+			return nil, fmt.Errorf("extraction rule %s: pattern is empty", name)
+		}
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("extraction rule %s: %v", name, err)
+		}
+		named := false
+		for _, group := range regex.SubexpNames() {
+			if group != "" {
+				named = true
+				break
+			}
+		}
+		if !named {
+			return nil, fmt.Errorf("extraction rule %s: pattern has no named capture groups (?P<field>... or %%{PATTERN:field})", name)
+		}
+
+		entry := &compiledExtractionRule{name: name, regex: regex, fields: fields, logType: strings.TrimSpace(rule.LogType)}
+		for _, service := range rule.Services {
+			if service = strings.ToLower(strings.TrimSpace(service)); service != "" {
+				entry.services = append(entry.services, service)
+			}
+		}
+		compiled = append(compiled, entry)
+	}
+	return compiled, nil
+}
+
+// equalExtractionRules 두 추출 규칙 목록이 같은지 확인 (설정 재로드)
+func equalExtractionRules(a, b []ExtractionRule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Pattern != b[i].Pattern || a[i].LogType != b[i].LogType ||
+			a[i].Grok != b[i].Grok || strings.Join(a[i].Services, "\x00") != strings.Join(b[i].Services, "\x00") ||
+			len(a[i].PatternDefinitions) != len(b[i].PatternDefinitions) {
+			return false
+		}
+		for name, definition := range a[i].PatternDefinitions {
+			if other, ok := b[i].PatternDefinitions[name]; !ok || other != definition {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// applyExtractionRules 서비스가 일치하는 규칙을 순서대로 적용 (하나라도 일치하면 true)
+func applyExtractionRules(rules []*compiledExtractionRule, parsed *ParsedLog, line string) bool {
+	if len(rules) == 0 || parsed == nil {
+		return false
+	}
+	services := extractionServices(parsed, line)
+
+	applied := false
+	for _, rule := range rules {
+		if !rule.appliesTo(services) {
+			continue
+		}
+		matches := rule.regex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		if parsed.Fields == nil {
+			parsed.Fields = make(map[string]string)
+		}
+		for i, group := range rule.regex.SubexpNames() {
+			value := strings.TrimSpace(matches[i])
+			if group == "" || value == "" {
+				continue
+			}
+			if field, ok := rule.fields[group]; ok {
+				group = field
+			}
+			switch group {
+			case "level":
+				parsed.Level = strings.ToUpper(value)
+			case "message":
+				parsed.Message = value
+			default:
+				parsed.Fields[group] = value
+			}
+		}
+		if rule.logType != "" && parsed.LogType == "unknown" {
+			parsed.LogType = rule.logType
+		}
+		parsed.Fields["extraction_rule"] = rule.name
+		applied = true
+	}
+	return applied
+}
+
+// appliesTo 규칙의 서비스 제한 확인 (제한이 없으면 모든 로그)
+func (r *compiledExtractionRule) appliesTo(services []string) bool {
+	return matchesServices(r.services, services)
+}
+
+// matchesServices 서비스 제한 확인 (제한이 없으면 모든 로그, "*" 접미사는 접두사 일치)
+// 추출 규칙과 심각도 규칙이 같이 사용
+func matchesServices(wants, services []string) bool {
+	if len(wants) == 0 {
+		return true
+	}
+	for _, want := range wants {
+		prefix := strings.TrimSuffix(want, "*")
+		for _, service := range services {
+			if service == want || (prefix != want && strings.HasPrefix(service, prefix)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extractionServices 로그의 서비스 이름 후보 (syslog 태그, journald 유닛/식별자, 이벤트 로그 공급자)
+// 고급 파싱을 하지 않아 parsed 가 nil 이면 syslog 태그만 사용
+func extractionServices(parsed *ParsedLog, line string) []string {
+	var services []string
+	add := func(name string) {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			services = append(services, name)
+		}
+	}
+	if matches := extractionSyslogTagRegex.FindStringSubmatch(line); matches != nil {
+		add(matches[1])
+	}
+	if parsed == nil {
+		return services
+	}
+	add(parsed.Source)
+	if unit := parsed.Fields["unit"]; unit != "" {
+		add(unit)
+		add(strings.TrimSuffix(unit, ".service"))
+	}
+	add(parsed.Fields["identifier"])
+	return services
+}
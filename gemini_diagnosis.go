@@ -0,0 +1,297 @@
+/*
+Gemini Structured Diagnosis Module
+==================================
+
+# Gemini 시스템 진단을 JSON 스키마 기반 구조화 응답으로 받아 ExpertDiagnosis 로 변환
+
+주요 기능:
+- generationConfig.responseSchema / responseMimeType=application/json 으로 응답 형식 고정
+- 다른 LLM 백엔드는 같은 스키마를 JSON Schema 로 변환해 사용 (OpenAI response_format, Anthropic 도구 입력, Ollama format)
+- 응답 JSON 파싱 후 검증 (건강도/위험도 값, 점수 범위 0~100, 필수 항목)
+- 검증을 통과하지 못하면 오류를 반환하여 호출 측이 기본 진단으로 대체
+- 구조화된 진단을 보고서 텍스트로 렌더링 (정기 보고서/이메일의 AI 전문가 진단 섹션)
+*/
+package main
+
+import (
+	"encoding/json" // 응답 JSON 파싱
+	"fmt"           // 형식화된 I/O
+	"strings"       // 문자열 처리
+)
+
+// 진단 등급 값 (AIAnalyzer 전문가 진단과 같은 값)
+var (
+	diagnosisHealthLevels = []string{"Excellent", "Good", "Fair", "Poor", "Critical"}
+	diagnosisRiskLevels   = []string{"Low", "Medium", "High", "Critical"}
+)
+
+// GeminiSchema Gemini 응답 스키마 (OpenAPI 스키마 부분집합)
+type GeminiSchema struct {
+	Type        string                   `json:"type"`
+	Description string                   `json:"description,omitempty"`
+	Enum        []string                 `json:"enum,omitempty"`
+	Properties  map[string]*GeminiSchema `json:"properties,omitempty"`
+	Required    []string                 `json:"required,omitempty"`
+	Items       *GeminiSchema            `json:"items,omitempty"`
+}
+
+// geminiDiagnosis 구조화 진단 응답 (스키마와 같은 필드)
+type geminiDiagnosis struct {
+	OverallHealth    string   `json:"overall_health"`
+	PerformanceScore *float64 `json:"performance_score"`
+	CriticalIssues   []string `json:"critical_issues"`
+	MaintenanceTips  []string `json:"maintenance_tips"`
+	ServerExpert     *struct {
+		ServerHealth     string   `json:"server_health"`
+		PerformanceScore *float64 `json:"performance_score"`
+		SecurityStatus   string   `json:"security_status"`
+		NetworkHealth    string   `json:"network_health"`
+		Issues           []string `json:"issues"`
+		Recommendations  []string `json:"recommendations"`
+		RiskLevel        string   `json:"risk_level"`
+	} `json:"server_expert"`
+	ComputerExpert *struct {
+		HardwareHealth    string   `json:"hardware_health"`
+		SoftwareStatus    string   `json:"software_status"`
+		SystemStability   string   `json:"system_stability"`
+		ResourceUsage     string   `json:"resource_usage"`
+		Issues            []string `json:"issues"`
+		Recommendations   []string `json:"recommendations"`
+		MaintenanceNeeded *bool    `json:"maintenance_needed"`
+	} `json:"computer_expert"`
+}
+
+// expertDiagnosisSchema 시스템 진단 응답 스키마
+func expertDiagnosisSchema() *GeminiSchema {
+	text := func(description string) *GeminiSchema {
+		return &GeminiSchema{Type: "STRING", Description: description}
+	}
+	list := func(description string) *GeminiSchema {
+		return &GeminiSchema{Type: "ARRAY", Description: description, Items: &GeminiSchema{Type: "STRING"}}
+	}
+	score := func(description string) *GeminiSchema {
+		return &GeminiSchema{Type: "NUMBER", Description: description + " (0-100)"}
+	}
+	health := func(description string) *GeminiSchema {
+		return &GeminiSchema{Type: "STRING", Description: description, Enum: diagnosisHealthLevels}
+	}
+
+	return &GeminiSchema{
+		Type: "OBJECT",
+		Properties: map[string]*GeminiSchema{
+			"overall_health":    health("전체 시스템 건강도"),
+			"performance_score": score("전체 성능 점수"),
+			"critical_issues":   list("즉시 조치가 필요한 긴급 이슈 (없으면 빈 배열)"),
+			"maintenance_tips":  list("유지보수/성능 최적화 팁과 즉시 실행 가능한 명령어"),
+			"server_expert": {
+				Type: "OBJECT",
+				Properties: map[string]*GeminiSchema{
+					"server_health":     health("서버 건강도"),
+					"performance_score": score("서버 성능 점수"),
+					"security_status":   text("보안 상태 요약"),
+					"network_health":    text("네트워크 건강도 요약"),
+					"issues":            list("서버 관점에서 발견된 문제점"),
+					"recommendations":   list("서버 전문가 권장사항"),
+					"risk_level":        {Type: "STRING", Description: "서버 위험도", Enum: diagnosisRiskLevels},
+				},
+				Required: []string{"server_health", "performance_score", "security_status", "network_health", "issues", "recommendations", "risk_level"},
+			},
+			"computer_expert": {
+				Type: "OBJECT",
+				Properties: map[string]*GeminiSchema{
+					"hardware_health":    health("하드웨어 건강도"),
+					"software_status":    text("소프트웨어 상태 요약"),
+					"system_stability":   text("시스템 안정성 요약"),
+					"resource_usage":     text("리소스 사용량 상태 요약"),
+					"issues":             list("컴퓨터 관점에서 발견된 문제점"),
+					"recommendations":    list("컴퓨터 전문가 권장사항"),
+					"maintenance_needed": {Type: "BOOLEAN", Description: "유지보수 필요 여부"},
+				},
+				Required: []string{"hardware_health", "software_status", "system_stability", "resource_usage", "issues", "recommendations", "maintenance_needed"},
+			},
+		},
+		Required: []string{"overall_health", "performance_score", "critical_issues", "maintenance_tips", "server_expert", "computer_expert"},
+	}
+}
+
+// diagnoseSystem 구조화된 시스템 진단 (API 호출/응답 검증 실패 또는 호출 한도 시 오류, complete 는 백엔드별 API 호출)
+func (lb *llmBase) diagnoseSystem(metrics SystemMetrics, complete llmCompletion) (ExpertDiagnosis, error) {
+	if !lb.currentConfig().configured() {
+		return ExpertDiagnosis{}, fmt.Errorf("%s is not configured", lb.Name())
+	}
+	prompt, version, err := lb.buildSystemDiagnosisPrompt(metrics)
+	if err != nil {
+		return ExpertDiagnosis{}, err
+	}
+	response, err := lb.call(prompt, expertDiagnosisSchema(), complete)
+	if err != nil {
+		return ExpertDiagnosis{}, err
+	}
+	diagnosis, err := parseExpertDiagnosis(response)
+	if err != nil {
+		return ExpertDiagnosis{}, fmt.Errorf("invalid structured diagnosis (%s): %v", version, err)
+	}
+	diagnosis.PromptVersion = version
+	return diagnosis, nil
+}
+
+// parseExpertDiagnosis 구조화 진단 응답 파싱 및 검증
+func parseExpertDiagnosis(text string) (ExpertDiagnosis, error) {
+	text = strings.TrimSpace(text)
+	// responseMimeType 을 지원하지 않는 모델은 코드 블록으로 감싸 응답하기도 함
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(strings.TrimPrefix(text, "```json"), "```")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	}
+
+	var response geminiDiagnosis
+	if err := json.Unmarshal([]byte(text), &response); err != nil {
+		return ExpertDiagnosis{}, fmt.Errorf("response is not valid JSON: %v", err)
+	}
+	if response.ServerExpert == nil || response.ComputerExpert == nil {
+		return ExpertDiagnosis{}, fmt.Errorf("server_expert and computer_expert are required")
+	}
+	server, computer := response.ServerExpert, response.ComputerExpert
+
+	var err error
+	level := func(field, value string, allowed []string) string {
+		for _, candidate := range allowed {
+			if strings.EqualFold(strings.TrimSpace(value), candidate) {
+				return candidate
+			}
+		}
+		if err == nil {
+			err = fmt.Errorf("%s must be one of %s, got %q", field, strings.Join(allowed, "/"), value)
+		}
+		return ""
+	}
+	score := func(field string, value *float64) float64 {
+		if value == nil || *value < 0 || *value > 100 {
+			if err == nil {
+				err = fmt.Errorf("%s must be a number between 0 and 100", field)
+			}
+			return 0
+		}
+		return *value
+	}
+	required := func(field, value string) string {
+		value = strings.TrimSpace(value)
+		if value == "" && err == nil {
+			err = fmt.Errorf("%s is required", field)
+		}
+		return value
+	}
+
+	diagnosis := ExpertDiagnosis{
+		ServerExpert: ServerExpertDiagnosis{
+			ServerHealth:     level("server_expert.server_health", server.ServerHealth, diagnosisHealthLevels),
+			PerformanceScore: score("server_expert.performance_score", server.PerformanceScore),
+			SecurityStatus:   required("server_expert.security_status", server.SecurityStatus),
+			NetworkHealth:    required("server_expert.network_health", server.NetworkHealth),
+			Issues:           compactDiagnosisList(server.Issues),
+			Recommendations:  compactDiagnosisList(server.Recommendations),
+			RiskLevel:        level("server_expert.risk_level", server.RiskLevel, diagnosisRiskLevels),
+		},
+		ComputerExpert: ComputerExpertDiagnosis{
+			HardwareHealth:  level("computer_expert.hardware_health", computer.HardwareHealth, diagnosisHealthLevels),
+			SoftwareStatus:  required("computer_expert.software_status", computer.SoftwareStatus),
+			SystemStability: required("computer_expert.system_stability", computer.SystemStability),
+			ResourceUsage:   required("computer_expert.resource_usage", computer.ResourceUsage),
+			Issues:          compactDiagnosisList(computer.Issues),
+			Recommendations: compactDiagnosisList(computer.Recommendations),
+		},
+		OverallHealth:    level("overall_health", response.OverallHealth, diagnosisHealthLevels),
+		CriticalIssues:   compactDiagnosisList(response.CriticalIssues),
+		MaintenanceTips:  compactDiagnosisList(response.MaintenanceTips),
+		PerformanceScore: score("performance_score", response.PerformanceScore),
+	}
+	if computer.MaintenanceNeeded == nil {
+		if err == nil {
+			err = fmt.Errorf("computer_expert.maintenance_needed is required")
+		}
+	} else {
+		diagnosis.ComputerExpert.MaintenanceNeeded = *computer.MaintenanceNeeded
+	}
+	if err != nil {
+		return ExpertDiagnosis{}, err
+	}
+	return diagnosis, nil
+}
+
+// compactDiagnosisList 빈 항목을 뺀 목록
+func compactDiagnosisList(items []string) []string {
+	compacted := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			compacted = append(compacted, item)
+		}
+	}
+	return compacted
+}
+
+// formatExpertDiagnosisReport 구조화된 진단을 보고서 텍스트로 렌더링
+func formatExpertDiagnosisReport(diagnosis ExpertDiagnosis) string {
+	server, computer := diagnosis.ServerExpert, diagnosis.ComputerExpert
+
+	var issues []string
+	for _, group := range [][]string{diagnosis.CriticalIssues, server.Issues, computer.Issues} {
+		for _, issue := range group {
+			if !containsString(issues, issue) {
+				issues = append(issues, issue)
+			}
+		}
+	}
+	recommendations := append(append([]string{}, server.Recommendations...), computer.Recommendations...)
+
+	return fmt.Sprintf(`
+
+🔬 AI 전문가 진단 결과
+=====================
+📊 전반적인 시스템 건강도: %s (성능 점수 %.1f/100)
+⚠️  발견된 문제점:
+%s
+
+🖥️  서버 전문가 진단:
+  🏥 서버 건강도: %s (성능 점수 %.1f/100)
+  🔒 보안 상태: %s
+  🌐 네트워크 건강도: %s
+  ⚠️  위험도: %s
+
+💻 컴퓨터 전문가 진단:
+  🔧 하드웨어 건강도: %s
+  💾 소프트웨어 상태: %s
+  ⚖️  시스템 안정성: %s
+  📈 리소스 사용량: %s
+  🔧 유지보수 필요: %s
+
+💡 전문가 권장사항:
+==================
+%s
+
+📈 유지보수 팁:
+==================
+%s
+%s`,
+		strings.ToUpper(diagnosis.OverallHealth), diagnosis.PerformanceScore,
+		strings.TrimRight(formatCriticalIssues(issues), "\n"),
+		server.ServerHealth, server.PerformanceScore,
+		server.SecurityStatus,
+		server.NetworkHealth,
+		server.RiskLevel,
+		computer.HardwareHealth,
+		computer.SoftwareStatus,
+		computer.SystemStability,
+		computer.ResourceUsage,
+		formatMaintenanceNeeded(computer.MaintenanceNeeded),
+		strings.TrimRight(formatMaintenanceTips(recommendations), "\n"),
+		strings.TrimRight(formatMaintenanceTips(diagnosis.MaintenanceTips), "\n"),
+		formatDiagnosisPromptVersion(diagnosis.PromptVersion))
+}
+
+// formatDiagnosisPromptVersion 진단 보고서 끝의 프롬프트 버전 줄 (기본 진단이면 빈 문자열)
+func formatDiagnosisPromptVersion(version string) string {
+	if version == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n🧾 프롬프트 버전: %s\n", version)
+}
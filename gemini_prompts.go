@@ -0,0 +1,250 @@
+/*
+Gemini Prompt Templates Module
+==============================
+
+Gemini 프롬프트를 외부 템플릿 파일로 관리 (설정 파일 ai_analysis.prompts)
+
+내장 프롬프트는 prompts/*.tmpl 로 실행 파일에 포함되며, 프롬프트 디렉터리에 같은 이름의 파일을 두면
+그 프롬프트만 대체합니다. 팀마다 어조, 언어, 필수 섹션을 코드 수정 없이 바꿀 수 있습니다.
+
+주요 기능:
+- 프롬프트 종류: system_diagnosis, log_analysis, log_batch_analysis, security_analysis, daily_digest (파일 이름 <종류>.tmpl)
+- Go text/template 문법, 종류별 데이터 ({{.Facts}}, {{.LogLine}}, {{.Lines}}, {{.Context}}, {{.ThreatData}}, {{.Notice}})
+- 설정 파일 variables 를 {{.Vars.이름}} 으로 사용 (정의하지 않은 변수는 불러올 때 오류)
+- 파일 앞머리(front matter)의 version 으로 프롬프트 버전 관리 (없으면 내용 해시 sha256:앞 12자리)
+- 분석 결과에 사용한 프롬프트 버전 기록 (system_diagnosis@2024-06 등)
+- 불러올 때 모든 템플릿을 표본 데이터로 실행해 검증, 오류가 있으면 기존 프롬프트 유지
+- -prompt-export 로 내장 템플릿을 디렉터리에 내보내 수정 시작점으로 사용
+
+파일 형식:
+
+	---
+	version: 2024-06-ops
+	description: 운영팀 어조
+	---
+	당신은 {{.Vars.team}} 의 보안 분석가입니다. ...
+*/
+package main
+
+import (
+	"bytes"         // 템플릿 실행 결과
+	"crypto/sha256" // 버전 없는 템플릿의 내용 해시
+	"embed"         // 내장 프롬프트 포함
+	"encoding/hex"  // 해시 표기
+	"fmt"           // 형식화된 I/O
+	"os"            // 파일 읽기/쓰기
+	"path/filepath" // 경로 처리
+	"strings"       // 문자열 처리
+	"text/template" // 프롬프트 템플릿
+)
+
+// 프롬프트 종류
+const (
+	PromptSystemDiagnosis  = "system_diagnosis"
+	PromptLogAnalysis      = "log_analysis"
+	PromptLogBatchAnalysis = "log_batch_analysis"
+	PromptSecurityAnalysis = "security_analysis"
+	PromptDailyDigest      = "daily_digest"
+)
+
+// promptNames 지원하는 프롬프트 종류
+var promptNames = []string{PromptSystemDiagnosis, PromptLogAnalysis, PromptLogBatchAnalysis, PromptSecurityAnalysis, PromptDailyDigest}
+
+// promptTemplateExt 프롬프트 템플릿 파일 확장자
+const promptTemplateExt = ".tmpl"
+
+//go:embed prompts/*.tmpl
+var builtinPrompts embed.FS
+
+// PromptConfig 프롬프트 템플릿 설정
+type PromptConfig struct {
+	Dir       string            `json:"dir"`       // 프롬프트 템플릿 디렉터리 (비우면 내장 프롬프트)
+	Variables map[string]string `json:"variables"` // 템플릿 변수 ({{.Vars.이름}})
+}
+
+// PromptData 템플릿에 전달하는 값 (종류별로 해당하는 필드만 채움)
+type PromptData struct {
+	Facts      string            // system_diagnosis: 시스템 메트릭, daily_digest: 기간 집계 (허용된 항목, 가림 적용)
+	LogLine    string            // log_analysis: 로그 라인 (가림 적용)
+	Context    map[string]string // log_analysis, log_batch_analysis: 파싱 필드 (가림 적용)
+	Lines      []string          // log_batch_analysis: 관련 로그 라인 목록 (가림 적용)
+	ThreatData string            // security_analysis: 위협 데이터 JSON (가림 적용)
+	Notice     string            // 가림 안내 (가린 값이 없으면 빈 문자열)
+	Vars       map[string]string // 설정 파일 variables
+}
+
+// PromptTemplate 불러온 프롬프트 템플릿 하나
+type PromptTemplate struct {
+	Name        string
+	Version     string
+	Description string
+	Source      string // 파일 경로 (내장이면 builtin)
+	template    *template.Template
+}
+
+// PromptSet 프롬프트 종류별 템플릿과 변수
+type PromptSet struct {
+	templates map[string]*PromptTemplate
+	variables map[string]string
+}
+
+// LoadPromptSet 내장 프롬프트를 불러온 뒤 디렉터리의 템플릿으로 대체하고 표본 데이터로 검증
+func LoadPromptSet(config PromptConfig) (*PromptSet, error) {
+	set := &PromptSet{templates: make(map[string]*PromptTemplate), variables: config.Variables}
+	if set.variables == nil {
+		set.variables = map[string]string{}
+	}
+
+	for _, name := range promptNames {
+		content, err := builtinPrompts.ReadFile("prompts/" + name + promptTemplateExt)
+		if err != nil {
+			return nil, fmt.Errorf("built-in prompt %s: %v", name, err)
+		}
+		if set.templates[name], err = parsePromptTemplate(name, "builtin", string(content)); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Dir != "" {
+		if _, err := os.Stat(config.Dir); err != nil {
+			return nil, fmt.Errorf("prompt directory: %v", err)
+		}
+		paths, err := filepath.Glob(filepath.Join(config.Dir, "*"+promptTemplateExt))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			name := strings.TrimSuffix(filepath.Base(path), promptTemplateExt)
+			if !containsString(promptNames, name) {
+				return nil, fmt.Errorf("%s: unknown prompt %q (expected %s)", path, name, strings.Join(promptNames, ", "))
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if set.templates[name], err = parsePromptTemplate(name, path, string(content)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 정의하지 않은 변수/필드를 쓰는 템플릿은 분석 시점이 아니라 불러올 때 오류
+	sample := PromptData{Facts: "-", LogLine: "-", Lines: []string{"-"}, Context: map[string]string{}, ThreatData: "{}"}
+	for _, name := range promptNames {
+		if _, _, err := set.Render(name, sample); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// parsePromptTemplate 앞머리(version, description)와 템플릿 본문 파싱
+func parsePromptTemplate(name, source, content string) (*PromptTemplate, error) {
+	prompt := &PromptTemplate{Name: name, Source: source}
+	body := strings.ReplaceAll(content, "\r\n", "\n")
+
+	if strings.HasPrefix(body, "---\n") {
+		end := strings.Index(body[4:], "\n---\n")
+		if end < 0 {
+			return nil, fmt.Errorf("%s: front matter is not closed with ---", source)
+		}
+		for i, line := range strings.Split(body[4:4+end], "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				return nil, fmt.Errorf("%s: front matter line %d: expected key: value", source, i+2)
+			}
+			switch value = strings.Trim(strings.TrimSpace(value), `"'`); strings.TrimSpace(key) {
+			case "version":
+				prompt.Version = value
+			case "description":
+				prompt.Description = value
+			default:
+				return nil, fmt.Errorf("%s: unknown front matter key %q (expected version, description)", source, key)
+			}
+		}
+		body = body[4+end+5:]
+	}
+	body = strings.TrimRight(body, "\n")
+	if strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("%s: prompt is empty", source)
+	}
+	if prompt.Version == "" {
+		sum := sha256.Sum256([]byte(body))
+		prompt.Version = "sha256:" + hex.EncodeToString(sum[:])[:12]
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+	prompt.template = tmpl
+	return prompt, nil
+}
+
+// Render 프롬프트 생성 (반환: 프롬프트, 버전 "<종류>@<version>")
+func (ps *PromptSet) Render(name string, data PromptData) (string, string, error) {
+	prompt, ok := ps.templates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown prompt %q", name)
+	}
+	data.Vars = ps.variables
+	var buffer bytes.Buffer
+	if err := prompt.template.Execute(&buffer, data); err != nil {
+		return "", "", fmt.Errorf("%s: %v", prompt.Source, err)
+	}
+	return buffer.String(), prompt.ID(), nil
+}
+
+// ID 분석 결과에 기록하는 프롬프트 식별자 ("<종류>@<version>")
+func (pt *PromptTemplate) ID() string {
+	return pt.Name + "@" + pt.Version
+}
+
+// Templates 프롬프트 종류 순서의 템플릿 목록 (시작/재로드 로그, 설정 정보)
+func (ps *PromptSet) Templates() []*PromptTemplate {
+	templates := make([]*PromptTemplate, 0, len(ps.templates))
+	for _, name := range promptNames {
+		templates = append(templates, ps.templates[name])
+	}
+	return templates
+}
+
+// Summary 사용 중인 프롬프트 버전 요약 ("system_diagnosis@builtin-1, log_analysis@2024-06 (file)")
+func (ps *PromptSet) Summary() string {
+	var parts []string
+	for _, prompt := range ps.Templates() {
+		part := prompt.ID()
+		if prompt.Source != "builtin" {
+			part += " (" + prompt.Source + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ExportBuiltinPrompts 내장 프롬프트 템플릿을 디렉터리에 내보내기 (이미 있는 파일은 건너뜀)
+func ExportBuiltinPrompts(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	var written []string
+	for _, name := range promptNames {
+		content, err := builtinPrompts.ReadFile("prompts/" + name + promptTemplateExt)
+		if err != nil {
+			return written, err
+		}
+		path := filepath.Join(dir, name+promptTemplateExt)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
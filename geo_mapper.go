@@ -7,13 +7,19 @@ IP 주소의 지리적 위치 정보를 수집하고 지도 데이터와 매핑
 
 주요 기능:
 - IP 주소 지리정보 실시간 조회
+- 로컬 MaxMind GeoLite2/GeoIP2 데이터베이스 조회 (-geoip-db, 설정하면 외부 API 대신 사용)
+- 비동기 조회 (결과 콜백, 같은 IP 동시 조회는 한 번만 요청, 동시 조회 수 제한)
+- 여러 IP 일괄 조회 (ip-api.com batch, 캐시 우선)
+- 조회 결과 디스크 캐시 (~/.syslog-monitor/geo_cache.json, TTL 이 지난 항목은 로드/저장 시 제외)
 - ASN 정보 및 조직 정보 수집
 - 위험도 기반 색상 코딩
 - 지도 좌표 변환 및 매핑
 - 정기적 위치 보고서 생성
+- 지도 스냅샷 링크 생성 (geojson.io)
 
 지원 API:
-- ip-api.com (무료 IP 지리정보)
+- MaxMind GeoLite2/GeoIP2 .mmdb (City, ASN, ISP, Anonymous IP)
+- ip-api.com (무료 IP 지리정보, 로컬 데이터베이스가 없을 때)
 - ipinfo.io (상세 ASN 정보)
 - Google Maps API (지도 시각화)
 */
@@ -21,14 +27,36 @@ IP 주소의 지리적 위치 정보를 수집하고 지도 데이터와 매핑
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
+// 지리정보 일괄 조회 및 지도 스냅샷 설정
+const (
+	GeoBatchAPIURL        = "http://ip-api.com/batch"                         // ip-api.com 일괄 조회 엔드포인트
+	GeoBatchMaxSize       = 100                                               // 일괄 조회 1회당 최대 IP 수 (ip-api.com 제한)
+	GeoSnapshotBaseURL    = "https://geojson.io/#data=data:application/json," // 지도 스냅샷 뷰어
+	GeoSnapshotMaxMarkers = 50                                                // 스냅샷 링크에 포함할 최대 마커 수 (URL 길이 제한)
+	GeoAPIFields          = "status,country,regionName,city,lat,lon,org,as,timezone,isp,proxy,query"
+)
+
+// 지리정보 캐시 및 비동기 조회 설정
+const (
+	GeoCacheTTL          = 24 * time.Hour  // 캐시 항목 유효 시간 (메모리/디스크 공통)
+	GeoCacheSaveInterval = 5 * time.Minute // 변경된 캐시를 디스크에 저장하는 주기
+	GeoLookupConcurrency = 4               // 동시에 진행하는 비동기 조회 수
+)
+
 // GeoLocationInfo 지리적 위치 정보
 type GeoLocationInfo struct {
 	IP           string  `json:"ip"`           // IP 주소
@@ -43,7 +71,8 @@ type GeoLocationInfo struct {
 	Threat       string  `json:"threat"`       // 위험도 평가
 	Timezone     string  `json:"timezone"`     // 시간대
 	ISP          string  `json:"isp"`          // 인터넷 서비스 제공업체
-	LastSeen     time.Time `json:"last_seen"`  // 마지막 감지 시각
+	Proxy        bool    `json:"proxy"`        // VPN/프록시/Tor 출구 여부 (ip-api.com 판별)
+	LastSeen     time.Time `json:"last_seen"`  // 마지막 감지 시각 (캐시 저장 시각)
 }
 
 // MapMarker 지도 마커 정보
@@ -62,18 +91,82 @@ type MapMarker struct {
 type GeoMapper struct {
 	logger        Logger
 	locationCache map[string]*GeoLocationInfo // 위치 정보 캐시
+	cacheMutex    sync.Mutex                  // 캐시/조회 대기 목록 동시 접근 보호
 	cacheTimeout  time.Duration              // 캐시 만료 시간
 	apiTimeout    time.Duration              // API 요청 타임아웃
+	cachePath     string                     // 디스크 캐시 파일 (빈 문자열이면 저장 안 함)
+	cacheDirty    bool                       // 마지막 저장 이후 캐시 변경 여부
+
+	pending     map[string][]func(*GeoLocationInfo) // 조회 중인 IP -> 결과를 기다리는 콜백
+	lookupSlots chan struct{}                       // 동시 비동기 조회 수 제한
+	lookups     sync.WaitGroup                      // 진행 중인 비동기 조회
+	saveOnce    sync.Once                           // 주기적 저장 고루틴 1회 시작 보장
+	closeOnce   sync.Once
+	stop        chan struct{}
+
+	geoipDBs   []*GeoIPDatabase // 로컬 MaxMind DB (설정하면 ip-api.com 을 사용하지 않음)
+	geoipMutex sync.RWMutex     // 데이터 업데이트가 DB 를 교체할 때 조회와의 동시 접근 보호
 }
 
-// NewGeoMapper 새로운 지리정보 매핑 서비스 생성
+// NewGeoMapper 새로운 지리정보 매핑 서비스 생성 (디스크 캐시가 있으면 만료되지 않은 항목 로드)
 func NewGeoMapper(logger Logger) *GeoMapper {
-	return &GeoMapper{
+	gm := &GeoMapper{
 		logger:        logger,
 		locationCache: make(map[string]*GeoLocationInfo),
-		cacheTimeout:  30 * time.Minute, // 30분 캐시
+		cacheTimeout:  GeoCacheTTL,
 		apiTimeout:    10 * time.Second, // 10초 타임아웃
+		cachePath:     filepath.Join(getDataDir(), GeoCacheFile),
+		pending:       make(map[string][]func(*GeoLocationInfo)),
+		lookupSlots:   make(chan struct{}, GeoLookupConcurrency),
+		stop:          make(chan struct{}),
+	}
+	if err := gm.loadCache(); err != nil {
+		logger.Errorf("Failed to load geolocation cache: %v", err)
 	}
+	return gm
+}
+
+// SetGeoIPDatabases 로컬 MaxMind DB 파일 설정 (City + ASN 처럼 여러 파일의 필드를 합쳐 사용)
+// 설정 후에는 외부 API 를 호출하지 않으므로 시작 전에 호출해야 함
+func (gm *GeoMapper) SetGeoIPDatabases(paths []string) error {
+	var databases []*GeoIPDatabase
+	for _, path := range paths {
+		db, err := OpenGeoIPDatabase(path)
+		if err != nil {
+			return err
+		}
+		databases = append(databases, db)
+	}
+	gm.geoipMutex.Lock()
+	gm.geoipDBs = databases
+	gm.geoipMutex.Unlock()
+	return nil
+}
+
+// ReplaceGeoIPDatabase 같은 경로의 로컬 MaxMind DB 를 다시 열어 교체 (데이터 업데이트)
+func (gm *GeoMapper) ReplaceGeoIPDatabase(path string) (*GeoIPDatabase, error) {
+	db, err := OpenGeoIPDatabase(path)
+	if err != nil {
+		return nil, err
+	}
+	gm.geoipMutex.Lock()
+	defer gm.geoipMutex.Unlock()
+	databases := append([]*GeoIPDatabase(nil), gm.geoipDBs...)
+	for i, current := range databases {
+		if current.Path == path {
+			databases[i] = db
+			gm.geoipDBs = databases
+			return db, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not a configured GeoIP database", path)
+}
+
+// GeoIPDatabases 사용 중인 로컬 MaxMind DB 목록
+func (gm *GeoMapper) GeoIPDatabases() []*GeoIPDatabase {
+	gm.geoipMutex.RLock()
+	defer gm.geoipMutex.RUnlock()
+	return gm.geoipDBs
 }
 
 // GetLocationInfo IP 주소의 지리정보 조회 (캐시 포함)
@@ -94,29 +187,327 @@ func (gm *GeoMapper) GetLocationInfo(ip string) *GeoLocationInfo {
 		}
 	}
 
+	// 로컬 데이터베이스 (조회 비용이 작아 캐시하지 않음)
+	if len(gm.GeoIPDatabases()) > 0 {
+		return gm.lookupGeoIPDB(ip)
+	}
+
 	// 캐시 확인
-	if cached, exists := gm.locationCache[ip]; exists {
-		if time.Since(cached.LastSeen) < gm.cacheTimeout {
-			return cached
-		}
-		// 캐시 만료된 경우 삭제
-		delete(gm.locationCache, ip)
+	if cached := gm.getCached(ip); cached != nil {
+		return cached
 	}
 
 	// API로 지리정보 조회
 	locationInfo := gm.fetchLocationFromAPI(ip)
 	if locationInfo != nil {
-		locationInfo.LastSeen = time.Now()
-		gm.locationCache[ip] = locationInfo
+		gm.putCached(locationInfo)
 	}
 
 	return locationInfo
 }
 
+// LookupAsync IP 주소의 지리정보를 조회하여 done 으로 전달 (조회 실패 시 nil)
+// 사설 IP, 캐시된 IP, 로컬 데이터베이스 조회는 호출한 고루틴에서 즉시 done 을 실행하고 false 를 반환
+// 외부 조회가 필요하면 조회 고루틴에서 done 을 실행하고 true 를 반환 (같은 IP 조회가 진행 중이면 그 결과를 함께 받음)
+func (gm *GeoMapper) LookupAsync(ip string, done func(*GeoLocationInfo)) bool {
+	if ip == "" || gm.isPrivateIP(ip) || len(gm.GeoIPDatabases()) > 0 {
+		done(gm.GetLocationInfo(ip))
+		return false
+	}
+	if cached := gm.getCached(ip); cached != nil {
+		done(cached)
+		return false
+	}
+
+	gm.cacheMutex.Lock()
+	waiting, inflight := gm.pending[ip]
+	gm.pending[ip] = append(waiting, done)
+	gm.cacheMutex.Unlock()
+	if inflight {
+		return true
+	}
+
+	gm.lookups.Add(1)
+	go func() {
+		defer gm.lookups.Done()
+
+		gm.lookupSlots <- struct{}{}
+		info := gm.fetchLocationFromAPI(ip)
+		<-gm.lookupSlots
+		if info != nil {
+			gm.putCached(info)
+		}
+
+		gm.cacheMutex.Lock()
+		callbacks := gm.pending[ip]
+		delete(gm.pending, ip)
+		gm.cacheMutex.Unlock()
+		for _, callback := range callbacks {
+			callback(info)
+		}
+	}()
+	return true
+}
+
+// GetLocationInfoBatch 여러 IP 주소의 지리정보 일괄 조회
+// 캐시에 없는 공인 IP만 모아 batch API로 한 번에 조회 (최대 100개 단위)
+func (gm *GeoMapper) GetLocationInfoBatch(ips []string) map[string]*GeoLocationInfo {
+	locations := make(map[string]*GeoLocationInfo, len(ips))
+	var missing []string
+
+	for _, ip := range ips {
+		if ip == "" {
+			continue
+		}
+		if _, seen := locations[ip]; seen {
+			continue
+		}
+		if gm.isPrivateIP(ip) || len(gm.GeoIPDatabases()) > 0 {
+			locations[ip] = gm.GetLocationInfo(ip)
+			continue
+		}
+		if cached := gm.getCached(ip); cached != nil {
+			locations[ip] = cached
+			continue
+		}
+		locations[ip] = nil
+		missing = append(missing, ip)
+	}
+
+	for start := 0; start < len(missing); start += GeoBatchMaxSize {
+		end := start + GeoBatchMaxSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+		for _, info := range gm.fetchLocationBatchFromAPI(missing[start:end]) {
+			gm.putCached(info)
+			locations[info.IP] = info
+		}
+	}
+
+	// 조회에 실패한 IP 제거
+	for ip, info := range locations {
+		if info == nil {
+			delete(locations, ip)
+		}
+	}
+	return locations
+}
+
+// getCached 캐시된 위치 정보 조회 (만료된 항목은 삭제)
+func (gm *GeoMapper) getCached(ip string) *GeoLocationInfo {
+	gm.cacheMutex.Lock()
+	defer gm.cacheMutex.Unlock()
+
+	cached, exists := gm.locationCache[ip]
+	if !exists {
+		return nil
+	}
+	if time.Since(cached.LastSeen) >= gm.cacheTimeout {
+		delete(gm.locationCache, ip)
+		return nil
+	}
+	return cached
+}
+
+// putCached 위치 정보 캐시 저장
+func (gm *GeoMapper) putCached(info *GeoLocationInfo) {
+	info.LastSeen = time.Now()
+	gm.cacheMutex.Lock()
+	gm.locationCache[info.IP] = info
+	gm.cacheDirty = true
+	gm.cacheMutex.Unlock()
+}
+
+// loadCache 디스크 캐시에서 만료되지 않은 항목 로드 (파일이 없으면 무시)
+func (gm *GeoMapper) loadCache() error {
+	data, err := os.ReadFile(gm.cachePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var entries []*GeoLocationInfo
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("invalid cache file %s: %v", gm.cachePath, err)
+	}
+
+	gm.cacheMutex.Lock()
+	defer gm.cacheMutex.Unlock()
+	for _, info := range entries {
+		if info != nil && info.IP != "" && time.Since(info.LastSeen) < gm.cacheTimeout {
+			gm.locationCache[info.IP] = info
+		}
+	}
+	return nil
+}
+
+// saveCache 만료되지 않은 캐시 항목을 디스크에 저장 (마지막 저장 이후 변경이 없으면 생략)
+func (gm *GeoMapper) saveCache() error {
+	if gm.cachePath == "" {
+		return nil
+	}
+
+	gm.cacheMutex.Lock()
+	if !gm.cacheDirty {
+		gm.cacheMutex.Unlock()
+		return nil
+	}
+	entries := make([]*GeoLocationInfo, 0, len(gm.locationCache))
+	for ip, info := range gm.locationCache {
+		if time.Since(info.LastSeen) >= gm.cacheTimeout {
+			delete(gm.locationCache, ip)
+			continue
+		}
+		entries = append(entries, info)
+	}
+	data, err := json.Marshal(entries)
+	gm.cacheDirty = false
+	gm.cacheMutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(gm.cachePath), ConfigPermissions); err != nil {
+		return err
+	}
+	tmpPath := gm.cachePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, gm.cachePath)
+}
+
+// StartCachePersistence 변경된 캐시를 주기적으로 디스크에 저장하는 고루틴 시작
+func (gm *GeoMapper) StartCachePersistence() {
+	gm.saveOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(GeoCacheSaveInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					if err := gm.saveCache(); err != nil {
+						gm.logger.Errorf("Failed to save geolocation cache: %v", err)
+					}
+				case <-gm.stop:
+					return
+				}
+			}
+		}()
+	})
+}
+
+// Close 진행 중인 비동기 조회(콜백 포함)를 기다린 뒤 캐시를 디스크에 저장
+func (gm *GeoMapper) Close() {
+	gm.closeOnce.Do(func() {
+		close(gm.stop)
+		gm.lookups.Wait()
+		if err := gm.saveCache(); err != nil {
+			gm.logger.Errorf("Failed to save geolocation cache: %v", err)
+		}
+	})
+}
+
+// lookupGeoIPDB 로컬 MaxMind DB 로 지리정보 조회 (모든 데이터베이스에 없으면 nil)
+func (gm *GeoMapper) lookupGeoIPDB(ip string) *GeoLocationInfo {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil
+	}
+
+	info := &GeoLocationInfo{IP: ip, LastSeen: time.Now()}
+	found := false
+	for _, db := range gm.GeoIPDatabases() {
+		record, err := db.Lookup(parsed)
+		if err != nil {
+			gm.logger.Errorf("Failed to look up %s in GeoIP database: %v", ip, err)
+			continue
+		}
+		if record != nil {
+			applyGeoIPRecord(info, record)
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	info.Threat = gm.assessThreatLevel(info.Country, info.Organization)
+	return info
+}
+
+// fetchLocationBatchFromAPI ip-api.com batch API로 여러 IP 지리정보 조회
+func (gm *GeoMapper) fetchLocationBatchFromAPI(ips []string) []*GeoLocationInfo {
+	queries := make([]map[string]string, 0, len(ips))
+	for _, ip := range ips {
+		queries = append(queries, map[string]string{
+			"query":  ip,
+			"fields": GeoAPIFields,
+		})
+	}
+
+	payload, err := json.Marshal(queries)
+	if err != nil {
+		gm.logger.Errorf("Failed to marshal IP location batch request: %v", err)
+		return nil
+	}
+
+	client := &http.Client{Timeout: gm.apiTimeout}
+	resp, err := client.Post(GeoBatchAPIURL, "application/json", bytes.NewBuffer(payload))
+	if err != nil {
+		gm.logger.Errorf("Failed to query IP location batch (%d IPs): %v", len(ips), err)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	var results []struct {
+		Status     string  `json:"status"`
+		Country    string  `json:"country"`
+		RegionName string  `json:"regionName"`
+		City       string  `json:"city"`
+		Lat        float64 `json:"lat"`
+		Lon        float64 `json:"lon"`
+		Org        string  `json:"org"`
+		AS         string  `json:"as"`
+		Timezone   string  `json:"timezone"`
+		ISP        string  `json:"isp"`
+		Proxy      bool    `json:"proxy"`
+		Query      string  `json:"query"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		gm.logger.Errorf("Failed to parse IP location batch response: %v", err)
+		return nil
+	}
+
+	locations := make([]*GeoLocationInfo, 0, len(results))
+	for _, result := range results {
+		if result.Status != "success" {
+			continue
+		}
+		locations = append(locations, &GeoLocationInfo{
+			IP:           result.Query,
+			Country:      result.Country,
+			Region:       result.RegionName,
+			City:         result.City,
+			Latitude:     result.Lat,
+			Longitude:    result.Lon,
+			Organization: result.Org,
+			ASN:          result.AS,
+			Timezone:     result.Timezone,
+			ISP:          result.ISP,
+			Proxy:        result.Proxy,
+			Threat:       gm.assessThreatLevel(result.Country, result.Org),
+		})
+	}
+	return locations
+}
+
 // fetchLocationFromAPI 외부 API로 지리정보 조회
 func (gm *GeoMapper) fetchLocationFromAPI(ip string) *GeoLocationInfo {
 	// ip-api.com 사용 (무료, 상세 정보 제공)
-	url := fmt.Sprintf("http://ip-api.com/json/%s?fields=status,country,regionName,city,lat,lon,org,as,timezone,isp,query", ip)
+	url := fmt.Sprintf("http://ip-api.com/json/%s?fields=%s", ip, GeoAPIFields)
 	
 	client := &http.Client{Timeout: gm.apiTimeout}
 	resp, err := client.Get(url)
@@ -143,6 +534,7 @@ func (gm *GeoMapper) fetchLocationFromAPI(ip string) *GeoLocationInfo {
 		AS         string  `json:"as"`
 		Timezone   string  `json:"timezone"`
 		ISP        string  `json:"isp"`
+		Proxy      bool    `json:"proxy"`
 		Query      string  `json:"query"`
 	}
 
@@ -163,6 +555,7 @@ func (gm *GeoMapper) fetchLocationFromAPI(ip string) *GeoLocationInfo {
 			ASN:          result.AS,
 			Timezone:     result.Timezone,
 			ISP:          result.ISP,
+			Proxy:        result.Proxy,
 			IsPrivate:    false,
 			Threat:       gm.assessThreatLevel(result.Country, result.Org),
 		}
@@ -389,7 +782,78 @@ func (gm *GeoMapper) GenerateLocationReport() string {
 		location.Latitude, location.Longitude, location.Organization,
 		location.ASN, location.ISP, location.Timezone, location.Threat,
 		location.LastSeen.Format("2006-01-02 15:04:05"),
-		len(gm.locationCache))
+		gm.CacheSize())
 
 	return report
-} 
\ No newline at end of file
+} 
+
+// CacheSize 캐시된 위치 정보 수
+func (gm *GeoMapper) CacheSize() int {
+	gm.cacheMutex.Lock()
+	defer gm.cacheMutex.Unlock()
+	return len(gm.locationCache)
+}
+
+// GenerateMapSnapshotURL 위치 목록을 GeoJSON으로 인코딩한 지도 스냅샷 링크 생성
+// API 키 없이 브라우저에서 바로 열 수 있도록 geojson.io 에 데이터를 URL로 전달
+func (gm *GeoMapper) GenerateMapSnapshotURL(locations []*GeoLocationInfo, counts map[string]int) string {
+	type feature struct {
+		Type     string `json:"type"`
+		Geometry struct {
+			Type        string    `json:"type"`
+			Coordinates []float64 `json:"coordinates"`
+		} `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+
+	features := make([]feature, 0, len(locations))
+	for _, location := range locations {
+		if location == nil || location.IsPrivate {
+			continue
+		}
+		if len(features) >= GeoSnapshotMaxMarkers {
+			break
+		}
+
+		f := feature{Type: "Feature"}
+		f.Geometry.Type = "Point"
+		f.Geometry.Coordinates = []float64{location.Longitude, location.Latitude}
+		f.Properties = map[string]interface{}{
+			"ip":           location.IP,
+			"country":      location.Country,
+			"city":         location.City,
+			"threat":       location.Threat,
+			"logins":       counts[location.IP],
+			"marker-color": snapshotMarkerColor(location.Threat),
+		}
+		features = append(features, f)
+	}
+
+	if len(features) == 0 {
+		return ""
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"type":     "FeatureCollection",
+		"features": features,
+	})
+	if err != nil {
+		gm.logger.Errorf("Failed to build map snapshot: %v", err)
+		return ""
+	}
+	return GeoSnapshotBaseURL + url.PathEscape(string(data))
+}
+
+// snapshotMarkerColor 위험도별 GeoJSON 마커 색상 (simplestyle 16진수 색상)
+func snapshotMarkerColor(threat string) string {
+	switch threat {
+	case "HIGH":
+		return "#e01e5a"
+	case "MEDIUM":
+		return "#ecb22e"
+	case "LOW":
+		return "#2eb67d"
+	default:
+		return "#808080"
+	}
+}
@@ -0,0 +1,446 @@
+/*
+GeoIP DB - MaxMind DB (.mmdb) 리더
+===================================
+
+GeoLite2/GeoIP2 데이터베이스 파일을 직접 읽어 외부 API 없이
+IP 지리정보를 조회하는 모듈 (폐쇄망 환경, ip-api.com 요청 한도 회피)
+
+주요 기능:
+- MaxMind DB 포맷 v2 메타데이터/검색 트리/데이터 섹션 해석
+- 24/28/32 비트 레코드 크기, IPv4 전용 및 IPv6 (IPv4 매핑 포함) 트리 지원
+- City (국가/지역/도시/좌표/시간대), ASN, ISP, Anonymous IP 데이터베이스 필드 변환
+
+포맷 명세: https://maxmind.github.io/MaxMind-DB/
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"os"
+	"time"
+)
+
+// MaxMind DB 포맷 상수
+const (
+	GeoIPDBLanguage = "en" // 국가/도시 이름 언어 (ip-api.com 응답과 동일하게 영어)
+
+	mmdbMetadataMaxSize = 128 * 1024 // 파일 끝에서 메타데이터 표식을 찾는 범위
+	mmdbDataSeparator   = 16         // 검색 트리와 데이터 섹션 사이의 0 바이트 수
+	mmdbMaxDepth        = 32         // 포인터/중첩 자료 해석 최대 깊이
+)
+
+var mmdbMetadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// MaxMind DB 데이터 필드 타입
+const (
+	mmdbTypeExtended  = 0
+	mmdbTypePointer   = 1
+	mmdbTypeString    = 2
+	mmdbTypeDouble    = 3
+	mmdbTypeBytes     = 4
+	mmdbTypeUint16    = 5
+	mmdbTypeUint32    = 6
+	mmdbTypeMap       = 7
+	mmdbTypeInt32     = 8
+	mmdbTypeUint64    = 9
+	mmdbTypeUint128   = 10
+	mmdbTypeArray     = 11
+	mmdbTypeContainer = 12
+	mmdbTypeEndMarker = 13
+	mmdbTypeBoolean   = 14
+	mmdbTypeFloat     = 15
+)
+
+// GeoIPDatabase 메모리에 읽어 들인 MaxMind DB 파일
+type GeoIPDatabase struct {
+	Path         string    // 파일 경로
+	DatabaseType string    // 예: GeoLite2-City, GeoLite2-ASN
+	BuildTime    time.Time // 데이터베이스 생성 시각
+
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	ipv4Start  uint        // IPv6 트리에서 ::/96 (IPv4 주소) 이 시작되는 노드
+	tree       []byte      // 검색 트리
+	data       mmdbDecoder // 데이터 섹션
+}
+
+// mmdbDecoder 데이터/메타데이터 섹션 해석기 (포인터는 섹션 시작 기준 오프셋)
+type mmdbDecoder struct {
+	buf []byte
+}
+
+// OpenGeoIPDatabase MaxMind DB 파일을 읽고 메타데이터 검증
+func OpenGeoIPDatabase(path string) (*GeoIPDatabase, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	searchFrom := 0
+	if len(content) > mmdbMetadataMaxSize {
+		searchFrom = len(content) - mmdbMetadataMaxSize
+	}
+	markerIndex := bytes.LastIndex(content[searchFrom:], mmdbMetadataMarker)
+	if markerIndex < 0 {
+		return nil, fmt.Errorf("%s: not a MaxMind DB file (metadata marker not found)", path)
+	}
+	markerIndex += searchFrom
+
+	raw, _, err := mmdbDecoder{buf: content[markerIndex+len(mmdbMetadataMarker):]}.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid metadata: %v", path, err)
+	}
+	metadata, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: invalid metadata", path)
+	}
+
+	db := &GeoIPDatabase{Path: path}
+	db.DatabaseType, _ = metadata["database_type"].(string)
+	if epoch, ok := metadata["build_epoch"].(uint64); ok {
+		db.BuildTime = time.Unix(int64(epoch), 0)
+	}
+	if major, _ := metadata["binary_format_major_version"].(uint64); major != 2 {
+		return nil, fmt.Errorf("%s: unsupported MaxMind DB format version %d", path, major)
+	}
+	nodeCount, _ := metadata["node_count"].(uint64)
+	recordSize, _ := metadata["record_size"].(uint64)
+	ipVersion, _ := metadata["ip_version"].(uint64)
+	db.nodeCount, db.recordSize, db.ipVersion = uint(nodeCount), uint(recordSize), uint(ipVersion)
+
+	switch db.recordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("%s: unsupported record size %d", path, db.recordSize)
+	}
+	if db.ipVersion != 4 && db.ipVersion != 6 {
+		return nil, fmt.Errorf("%s: unsupported IP version %d", path, db.ipVersion)
+	}
+
+	// 노드 하나는 최소 6 바이트이므로 파일보다 큰 node_count 는 곱셈 전에 거부 (오버플로 방지)
+	if nodeCount > uint64(markerIndex) {
+		return nil, fmt.Errorf("%s: search tree exceeds file size", path)
+	}
+	treeSize := db.nodeCount * db.recordSize / 4
+	if treeSize+mmdbDataSeparator > uint(markerIndex) {
+		return nil, fmt.Errorf("%s: search tree exceeds file size", path)
+	}
+	db.tree = content[:treeSize]
+	db.data = mmdbDecoder{buf: content[treeSize+mmdbDataSeparator : markerIndex]}
+
+	if db.ipVersion == 6 {
+		node := uint(0)
+		for i := 0; i < 96 && node < db.nodeCount; i++ {
+			node = db.readNode(node, 0)
+		}
+		db.ipv4Start = node
+	}
+	return db, nil
+}
+
+// Lookup IP 주소의 레코드 조회 (데이터베이스에 없으면 nil, nil)
+func (db *GeoIPDatabase) Lookup(ip net.IP) (map[string]interface{}, error) {
+	node, bits := uint(0), 128
+	if v4 := ip.To4(); v4 != nil {
+		ip, bits = v4, 32
+		if db.ipVersion == 6 {
+			node = db.ipv4Start
+		}
+	} else if db.ipVersion == 4 {
+		return nil, nil
+	} else if ip = ip.To16(); ip == nil {
+		return nil, fmt.Errorf("invalid IP address")
+	}
+
+	for i := 0; i < bits && node < db.nodeCount; i++ {
+		bit := uint(ip[i>>3]>>(7-uint(i&7))) & 1
+		node = db.readNode(node, bit)
+	}
+
+	switch {
+	case node == db.nodeCount:
+		return nil, nil
+	case node < db.nodeCount:
+		return nil, fmt.Errorf("%s: search tree too deep", db.Path)
+	}
+
+	raw, _, err := db.data.decode(node-db.nodeCount-mmdbDataSeparator, 0)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", db.Path, err)
+	}
+	record, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: unexpected record type %T", db.Path, raw)
+	}
+	return record, nil
+}
+
+// Describe 로그 표시용 데이터베이스 설명
+func (db *GeoIPDatabase) Describe() string {
+	if db.BuildTime.IsZero() {
+		return fmt.Sprintf("%s (%s)", db.DatabaseType, db.Path)
+	}
+	return fmt.Sprintf("%s %s (%s)", db.DatabaseType, db.BuildTime.Format("2006-01-02"), db.Path)
+}
+
+// readNode 검색 트리 노드의 왼쪽(0)/오른쪽(1) 레코드 값
+func (db *GeoIPDatabase) readNode(node, bit uint) uint {
+	base := node * db.recordSize / 4
+	b := db.tree
+	switch db.recordSize {
+	case 24:
+		offset := base + bit*3
+		return uint(b[offset])<<16 | uint(b[offset+1])<<8 | uint(b[offset+2])
+	case 28:
+		if bit == 0 {
+			return uint(b[base+3]&0xF0)<<20 | uint(b[base])<<16 | uint(b[base+1])<<8 | uint(b[base+2])
+		}
+		return uint(b[base+3]&0x0F)<<24 | uint(b[base+4])<<16 | uint(b[base+5])<<8 | uint(b[base+6])
+	default:
+		offset := base + bit*4
+		return uint(binary.BigEndian.Uint32(b[offset : offset+4]))
+	}
+}
+
+// decode offset 위치의 값을 해석하여 값과 다음 필드 오프셋 반환
+func (d mmdbDecoder) decode(offset uint, depth int) (interface{}, uint, error) {
+	if depth > mmdbMaxDepth {
+		return nil, 0, fmt.Errorf("data nested too deeply")
+	}
+	if offset >= uint(len(d.buf)) {
+		return nil, 0, fmt.Errorf("data offset %d out of range", offset)
+	}
+	ctrl := d.buf[offset]
+	offset++
+
+	typeNum := uint(ctrl >> 5)
+	if typeNum == mmdbTypePointer {
+		pointer, next, err := d.decodePointer(ctrl, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+	if typeNum == mmdbTypeExtended {
+		if offset >= uint(len(d.buf)) {
+			return nil, 0, fmt.Errorf("truncated extended type")
+		}
+		typeNum = 7 + uint(d.buf[offset])
+		offset++
+	}
+
+	size, offset, err := d.decodeSize(ctrl, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 맵 항목/배열 원소는 최소 1 바이트씩 차지하므로 남은 크기보다 많으면 손상된 파일 (과도한 메모리 할당 방지)
+	if (typeNum == mmdbTypeMap || typeNum == mmdbTypeArray) && size > uint(len(d.buf))-offset {
+		return nil, 0, fmt.Errorf("container of %d entries exceeds data section", size)
+	}
+
+	switch typeNum {
+	case mmdbTypeMap:
+		values := make(map[string]interface{}, size)
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, 0, fmt.Errorf("map key is %T, not string", key)
+			}
+			value, next, err := d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			values[name] = value
+			offset = next
+		}
+		return values, offset, nil
+	case mmdbTypeArray:
+		values := make([]interface{}, 0, size)
+		for i := uint(0); i < size; i++ {
+			value, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			values = append(values, value)
+			offset = next
+		}
+		return values, offset, nil
+	case mmdbTypeBoolean:
+		return size != 0, offset, nil
+	}
+
+	if offset+size > uint(len(d.buf)) {
+		return nil, 0, fmt.Errorf("field of type %d exceeds data section", typeNum)
+	}
+	payload := d.buf[offset : offset+size]
+	next := offset + size
+
+	switch typeNum {
+	case mmdbTypeString:
+		return string(payload), next, nil
+	case mmdbTypeBytes:
+		return append([]byte(nil), payload...), next, nil
+	case mmdbTypeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(payload)), next, nil
+	case mmdbTypeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size %d", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(payload))), next, nil
+	case mmdbTypeUint16, mmdbTypeUint32, mmdbTypeUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("invalid unsigned integer size %d", size)
+		}
+		var value uint64
+		for _, c := range payload {
+			value = value<<8 | uint64(c)
+		}
+		return value, next, nil
+	case mmdbTypeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("invalid int32 size %d", size)
+		}
+		var value uint32
+		for _, c := range payload {
+			value = value<<8 | uint32(c)
+		}
+		return int64(int32(value)), next, nil
+	case mmdbTypeUint128:
+		if size > 16 {
+			return nil, 0, fmt.Errorf("invalid uint128 size %d", size)
+		}
+		return new(big.Int).SetBytes(payload), next, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported data type %d", typeNum)
+	}
+}
+
+// decodePointer 포인터 필드의 대상 오프셋과 포인터 다음 오프셋
+func (d mmdbDecoder) decodePointer(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint((ctrl>>3)&0x3) + 1
+	if offset+size > uint(len(d.buf)) {
+		return 0, 0, fmt.Errorf("truncated pointer")
+	}
+	var pointer uint
+	if size != 4 {
+		pointer = uint(ctrl & 0x7)
+	}
+	for _, c := range d.buf[offset : offset+size] {
+		pointer = pointer<<8 | uint(c)
+	}
+	switch size {
+	case 2:
+		pointer += 2048
+	case 3:
+		pointer += 526336
+	}
+	return pointer, offset + size, nil
+}
+
+// decodeSize 제어 바이트의 크기 필드 (29 이상이면 뒤따르는 바이트로 확장)
+func (d mmdbDecoder) decodeSize(ctrl byte, offset uint) (uint, uint, error) {
+	size := uint(ctrl & 0x1f)
+	if size < 29 {
+		return size, offset, nil
+	}
+	extra := size - 28
+	if offset+extra > uint(len(d.buf)) {
+		return 0, 0, fmt.Errorf("truncated size field")
+	}
+	var value uint
+	for _, c := range d.buf[offset : offset+extra] {
+		value = value<<8 | uint(c)
+	}
+	switch size {
+	case 29:
+		value += 29
+	case 30:
+		value += 285
+	default:
+		value += 65821
+	}
+	return value, offset + extra, nil
+}
+
+// mmdbField 레코드의 중첩 필드 조회 (문자열 키는 맵, 정수는 배열 인덱스)
+func mmdbField(record map[string]interface{}, path ...interface{}) interface{} {
+	var current interface{} = record
+	for _, key := range path {
+		switch k := key.(type) {
+		case string:
+			values, ok := current.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			current = values[k]
+		case int:
+			values, ok := current.([]interface{})
+			if !ok || k >= len(values) {
+				return nil
+			}
+			current = values[k]
+		}
+	}
+	return current
+}
+
+// applyGeoIPRecord City/ASN/ISP/Anonymous IP 레코드의 필드를 위치 정보에 반영 (이미 채워진 필드는 유지)
+func applyGeoIPRecord(info *GeoLocationInfo, record map[string]interface{}) {
+	setString := func(target *string, path ...interface{}) {
+		if *target != "" {
+			return
+		}
+		if value, ok := mmdbField(record, path...).(string); ok {
+			*target = value
+		}
+	}
+
+	setString(&info.Country, "country", "names", GeoIPDBLanguage)
+	setString(&info.Country, "registered_country", "names", GeoIPDBLanguage)
+	setString(&info.Region, "subdivisions", 0, "names", GeoIPDBLanguage)
+	setString(&info.City, "city", "names", GeoIPDBLanguage)
+	setString(&info.Timezone, "location", "time_zone")
+	if latitude, ok := mmdbField(record, "location", "latitude").(float64); ok && info.Latitude == 0 {
+		info.Latitude = latitude
+	}
+	if longitude, ok := mmdbField(record, "location", "longitude").(float64); ok && info.Longitude == 0 {
+		info.Longitude = longitude
+	}
+
+	// ASN / ISP 데이터베이스 (ip-api.com 과 같은 "AS15169 Google LLC" 형식)
+	organization, _ := mmdbField(record, "autonomous_system_organization").(string)
+	if number, ok := mmdbField(record, "autonomous_system_number").(uint64); ok && info.ASN == "" {
+		info.ASN = fmt.Sprintf("AS%d %s", number, organization)
+	}
+	setString(&info.Organization, "organization")
+	setString(&info.Organization, "autonomous_system_organization")
+	setString(&info.ISP, "isp")
+	setString(&info.ISP, "autonomous_system_organization")
+
+	// 익명화 서비스 (City 의 traits, Anonymous IP 데이터베이스)
+	for _, path := range [][]interface{}{
+		{"traits", "is_anonymous_proxy"},
+		{"traits", "is_anonymous"},
+		{"is_anonymous"},
+	} {
+		if anonymous, ok := mmdbField(record, path...).(bool); ok && anonymous {
+			info.Proxy = true
+		}
+	}
+}
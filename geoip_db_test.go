@@ -0,0 +1,306 @@
+package main
+
+import (
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// openTestGeoIPDatabase testdata 의 MaxMind DB 파일 열기 (gen_mmdb.py 로 생성)
+func openTestGeoIPDatabase(t *testing.T, name string) *GeoIPDatabase {
+	t.Helper()
+	db, err := OpenGeoIPDatabase(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("OpenGeoIPDatabase(%s): %v", name, err)
+	}
+	return db
+}
+
+// TestGeoIPDatabaseLookup 레코드 크기/IP 버전별 조회와 위치 정보 변환
+func TestGeoIPDatabaseLookup(t *testing.T) {
+	london := GeoLocationInfo{Country: "United Kingdom", Region: "England", City: "London", Latitude: 51.5142, Longitude: -0.0931, Timezone: "Europe/London"}
+
+	tests := []struct {
+		file string
+		ip   string
+		want *GeoLocationInfo // nil 이면 데이터베이스에 없음
+	}{
+		{"city-ipv6-24.mmdb", "81.2.69.160", &london},
+		{"city-ipv6-24.mmdb", "81.2.69.191", &london},
+		{"city-ipv6-24.mmdb", "81.2.69.192", nil},
+		{"city-ipv6-24.mmdb", "81.2.69.159", nil},
+		{"city-ipv6-24.mmdb", "::ffff:81.2.69.170", &london}, // IPv4 매핑 주소도 IPv4 트리로
+		{"city-ipv6-24.mmdb", "2a02:d280::1", &london},       // 다른 네트워크가 같은 레코드 공유
+		{"city-ipv6-24.mmdb", "2a02:d287:ffff::1", &london},
+		{"city-ipv6-24.mmdb", "2a02:d288::1", nil},
+		{"city-ipv6-24.mmdb", "2.125.160.220", &GeoLocationInfo{Country: "United Kingdom", Region: "England", City: "Boxford", Latitude: 51.75, Longitude: -1.25, Timezone: "Europe/London"}},
+		{"city-ipv6-24.mmdb", "89.160.20.127", &GeoLocationInfo{Country: "Sweden", Latitude: 62, Longitude: 15, Timezone: "Europe/Stockholm", Proxy: true}},
+		{"city-ipv6-24.mmdb", "10.0.0.1", nil},
+		{"asn-ipv4-28.mmdb", "8.8.8.8", &GeoLocationInfo{ASN: "AS15169 Google LLC", Organization: "Google LLC", ISP: "Google LLC"}},
+		{"asn-ipv4-28.mmdb", "1.159.255.255", &GeoLocationInfo{ASN: "AS1221 Telstra Pty Ltd", Organization: "Telstra Pty Ltd", ISP: "Telstra Pty Ltd"}},
+		{"asn-ipv4-28.mmdb", "1.160.0.0", nil},
+		{"asn-ipv4-28.mmdb", "12.81.95.1", &GeoLocationInfo{ASN: "AS7018 AT&T Services", Organization: "AT&T Services", ISP: "AT&T Services"}},
+		{"asn-ipv4-28.mmdb", "2001:db8::1", nil}, // IPv4 전용 데이터베이스에 IPv6 조회
+		{"anon-ipv6-32.mmdb", "1.2.3.4", &GeoLocationInfo{Proxy: true}},
+		{"anon-ipv6-32.mmdb", "81.2.69.1", &GeoLocationInfo{Proxy: true}},
+		{"anon-ipv6-32.mmdb", "1.124.213.1", &GeoLocationInfo{}},
+		{"anon-ipv6-32.mmdb", "abcd:1000::ffff", &GeoLocationInfo{Proxy: true}},
+		{"anon-ipv6-32.mmdb", "abcd:1000::1:0", nil},
+	}
+
+	databases := map[string]*GeoIPDatabase{}
+	for _, test := range tests {
+		db := databases[test.file]
+		if db == nil {
+			db = openTestGeoIPDatabase(t, test.file)
+			databases[test.file] = db
+		}
+		record, err := db.Lookup(net.ParseIP(test.ip))
+		if err != nil {
+			t.Errorf("%s %s: %v", test.file, test.ip, err)
+			continue
+		}
+		if test.want == nil {
+			if record != nil {
+				t.Errorf("%s %s: got %v, want not found", test.file, test.ip, record)
+			}
+			continue
+		}
+		if record == nil {
+			t.Errorf("%s %s: not found", test.file, test.ip)
+			continue
+		}
+		var info GeoLocationInfo
+		applyGeoIPRecord(&info, record)
+		if info != *test.want {
+			t.Errorf("%s %s:\n got %+v\nwant %+v", test.file, test.ip, info, *test.want)
+		}
+	}
+
+	db := databases["city-ipv6-24.mmdb"]
+	if db.DatabaseType != "GeoIP2-City" || db.BuildTime.Unix() != 1760000000 || db.recordSize != 24 || db.ipVersion != 6 {
+		t.Errorf("metadata = %q %v record %d ip %d", db.DatabaseType, db.BuildTime, db.recordSize, db.ipVersion)
+	}
+	if !strings.HasPrefix(db.Describe(), "GeoIP2-City 2025-10-") {
+		t.Errorf("Describe() = %q", db.Describe())
+	}
+}
+
+// TestGeoIPApplyKeepsFilledFields 이미 채워진 필드는 덮어쓰지 않음 (City + ASN 순서로 적용)
+func TestGeoIPApplyKeepsFilledFields(t *testing.T) {
+	info := GeoLocationInfo{Country: "Preset", Latitude: 1}
+	applyGeoIPRecord(&info, map[string]interface{}{
+		"country":            map[string]interface{}{"names": map[string]interface{}{"en": "United Kingdom"}},
+		"registered_country": map[string]interface{}{"names": map[string]interface{}{"en": "Ignored"}},
+		"city":               map[string]interface{}{"names": map[string]interface{}{"de": "Nur Deutsch"}},
+		"location":           map[string]interface{}{"latitude": 51.5, "longitude": -0.1},
+		"organization":       "Example Org",
+		"traits":             map[string]interface{}{"is_anonymous": false},
+		"subdivisions":       []interface{}{},
+	})
+	applyGeoIPRecord(&info, map[string]interface{}{
+		"autonomous_system_number":       uint64(64500),
+		"autonomous_system_organization": "Example ASN",
+	})
+	want := GeoLocationInfo{Country: "Preset", Latitude: 1, Longitude: -0.1, Organization: "Example Org", ASN: "AS64500 Example ASN", ISP: "Example ASN"}
+	if info != want {
+		t.Errorf("got %+v\nwant %+v", info, want)
+	}
+}
+
+// TestGeoIPDatabaseDecoder 모든 데이터 타입과 크기 확장 (MaxMind-DB-test-decoder 와 같은 레코드)
+func TestGeoIPDatabaseDecoder(t *testing.T) {
+	db := openTestGeoIPDatabase(t, "decoder-ipv4-24.mmdb")
+
+	record, err := db.Lookup(net.ParseIP("1.1.1.1"))
+	if err != nil || record == nil {
+		t.Fatalf("Lookup(1.1.1.1) = %v, %v", record, err)
+	}
+	longArray := make([]interface{}, 40)
+	for i := range longArray {
+		longArray[i] = uint64(i)
+	}
+	want := map[string]interface{}{
+		"array":         []interface{}{uint64(1), uint64(2), uint64(3)},
+		"boolean":       true,
+		"bytes":         []byte{0, 0, 0, 42},
+		"double":        42.123456,
+		"float":         float64(float32(1.1)),
+		"int32":         int64(-268435456),
+		"map":           map[string]interface{}{"mapX": map[string]interface{}{"arrayX": []interface{}{uint64(7), uint64(8), uint64(9)}, "utf8_stringX": "hello"}},
+		"uint16":        uint64(100),
+		"uint32":        uint64(268435456),
+		"uint64":        uint64(1152921504606846976),
+		"uint128":       new(big.Int).Lsh(big.NewInt(1), 120),
+		"utf8_string":   "unicode! ☯ - ♫",
+		"long_string":   strings.Repeat("x", 300),
+		"medium_string": strings.Repeat("y", 100),
+		"long_array":    longArray,
+	}
+	if !reflect.DeepEqual(record, want) {
+		for key, value := range want {
+			if !reflect.DeepEqual(record[key], value) {
+				t.Errorf("%s = %#v, want %#v", key, record[key], value)
+			}
+		}
+		if len(record) != len(want) {
+			t.Errorf("record has %d keys, want %d", len(record), len(want))
+		}
+	}
+
+	record, err = db.Lookup(net.ParseIP("0.0.0.0"))
+	if err != nil || record == nil {
+		t.Fatalf("Lookup(0.0.0.0) = %v, %v", record, err)
+	}
+	zeros := map[string]interface{}{
+		"boolean": false, "double": 0.0, "float": 0.0, "int32": int64(0), "uint16": uint64(0), "uint32": uint64(0),
+		"uint64": uint64(0), "uint128": new(big.Int), "utf8_string": "", "array": []interface{}{}, "map": map[string]interface{}{},
+	}
+	if !reflect.DeepEqual(record, zeros) {
+		t.Errorf("zero record = %#v", record)
+	}
+}
+
+// TestMMDBDecoderFields 직접 구성한 바이트로 포인터 크기별 오프셋, 크기 확장 31, 오류 처리 확인
+func TestMMDBDecoderFields(t *testing.T) {
+	// 0x44 'a'... : 길이 4 문자열, 오프셋 0
+	text := append([]byte{0x44}, "abcd"...)
+
+	tests := []struct {
+		name    string
+		buf     []byte
+		offset  uint
+		want    interface{}
+		next    uint
+		wantErr string
+	}{
+		{"string", text, 0, "abcd", 5, ""},
+		{"pointer size 1", append(append([]byte{}, text...), 0x20, 0x00), 5, "abcd", 7, ""},
+		{"pointer size 2 adds 2048", pointerTestBuffer(2048, []byte{0x28, 0x00, 0x00}), 0, "abcd", 3, ""},
+		{"pointer size 3 adds 526336", pointerTestBuffer(526336, []byte{0x30, 0x00, 0x00, 0x00}), 0, "abcd", 4, ""},
+		{"pointer size 4", pointerTestBuffer(70000, []byte{0x38, 0x00, 0x01, 0x11, 0x70}), 0, "abcd", 5, ""},
+		{"size extension 31", append([]byte{0x5f, 0x00, 0x00, 0x01}, strings.Repeat("z", 65822)...), 0, strings.Repeat("z", 65822), 65826, ""},
+		{"uint16 empty is zero", []byte{0xa0}, 0, uint64(0), 1, ""},
+		{"int32 short", []byte{0x01, 0x01, 0xff}, 0, int64(255), 3, ""},
+		{"uint64 max", []byte{0x08, 0x02, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 0, uint64(1<<64 - 1), 10, ""},
+		{"uint32 too long", []byte{0xc9, 1, 2, 3, 4, 5, 6, 7, 8, 9}, 0, nil, 0, "invalid unsigned integer size 9"},
+		{"int32 too long", []byte{0x05, 0x01, 1, 2, 3, 4, 5}, 0, nil, 0, "invalid int32 size 5"},
+		{"double wrong size", []byte{0x64, 1, 2, 3, 4}, 0, nil, 0, "invalid double size 4"},
+		{"float wrong size", []byte{0x08, 0x08, 1, 2, 3, 4, 5, 6, 7, 8}, 0, nil, 0, "invalid float size 8"},
+		{"map key not string", []byte{0xe1, 0xa1, 0x01, 0x41, 'x'}, 0, nil, 0, "map key is uint64, not string"},
+		{"map size past end", []byte{0xfe, 0xff, 0xff, 0x41, 'k', 0x41, 'v'}, 0, nil, 0, "container of 65820 entries exceeds data section"},
+		{"array size past end", []byte{0x1f, 0x04, 0xff, 0xff, 0xff, 0xa0}, 0, nil, 0, "exceeds data section"},
+		{"string past end", []byte{0x45, 'a'}, 0, nil, 0, "exceeds data section"},
+		{"truncated size", []byte{0x5e, 0x01}, 0, nil, 0, "truncated size field"},
+		{"truncated pointer", []byte{0x30, 0x00}, 0, nil, 0, "truncated pointer"},
+		{"truncated extended type", []byte{0x00}, 0, nil, 0, "truncated extended type"},
+		{"end marker type", []byte{0x00, 0x06}, 0, nil, 0, "unsupported data type 13"},
+		{"pointer out of range", []byte{0x20, 0x10}, 0, nil, 0, "data offset 16 out of range"},
+		{"pointer loop", []byte{0x20, 0x00}, 0, nil, 0, "nested too deeply"},
+		{"offset out of range", text, 5, nil, 0, "out of range"},
+	}
+
+	for _, test := range tests {
+		value, next, err := mmdbDecoder{buf: test.buf}.decode(test.offset, 0)
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%s: error = %v, want %q", test.name, err, test.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(value, test.want) || next != test.next {
+			t.Errorf("%s: got %#v next %d, want %#v next %d", test.name, value, next, test.want, test.next)
+		}
+	}
+}
+
+// pointerTestBuffer 앞에 포인터를 두고 target 오프셋에 "abcd" 문자열을 둔 데이터 섹션
+func pointerTestBuffer(target int, pointer []byte) []byte {
+	buf := make([]byte, target+5)
+	copy(buf, pointer)
+	copy(buf[target:], append([]byte{0x44}, "abcd"...))
+	return buf
+}
+
+// TestGeoIPDatabaseInvalid 잘못된 파일은 오류로 거부하고 손상된 파일도 패닉 없이 처리
+func TestGeoIPDatabaseInvalid(t *testing.T) {
+	valid, err := os.ReadFile(filepath.Join("testdata", "city-ipv6-24.mmdb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	markerIndex := strings.LastIndex(string(valid), string(mmdbMetadataMarker))
+	metadataAt := markerIndex + len(mmdbMetadataMarker)
+
+	// 메타데이터의 인코딩된 필드 하나 바꾸기
+	replaceMetadata := func(old, new string) []byte {
+		content := append([]byte(nil), valid...)
+		metadata := strings.Replace(string(content[metadataAt:]), old, new, 1)
+		if metadata == string(content[metadataAt:]) {
+			t.Fatalf("metadata does not contain %q", old)
+		}
+		return append(content[:metadataAt], metadata...)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		wantErr string
+	}{
+		{"empty", nil, "metadata marker not found"},
+		{"not mmdb", []byte("plain text file"), "metadata marker not found"},
+		{"metadata truncated", valid[:metadataAt+20], "invalid metadata"},
+		{"metadata not map", append(append([]byte(nil), mmdbMetadataMarker...), 0x41, 'x'), "invalid metadata"},
+		{"format version 3", replaceMetadata("binary_format_major_version\xa1\x02", "binary_format_major_version\xa1\x03"), "unsupported MaxMind DB format version 3"},
+		{"record size 26", replaceMetadata("record_size\xa1\x18", "record_size\xa1\x1a"), "unsupported record size 26"},
+		{"ip version 5", replaceMetadata("ip_version\xa1\x06", "ip_version\xa1\x05"), "unsupported IP version 5"},
+		{"tree truncated", valid[len(valid)/2:], "search tree exceeds file size"},
+		{"node count larger than file", replaceMetadata("node_count\xc1\xc7", "node_count\xc2\x10\x00"), "search tree exceeds file size"},
+		{"node count overflows tree size", replaceMetadata("node_count\xc1\xc7", "node_count\x08\x02\x40\x00\x00\x00\x00\x00\x00\x01"), "search tree exceeds file size"}, // 2^62+1 노드 * 24 비트 = 6 바이트로 겹침
+	}
+
+	dir := t.TempDir()
+	for _, test := range tests {
+		path := filepath.Join(dir, strings.ReplaceAll(test.name, " ", "-")+".mmdb")
+		if err := os.WriteFile(path, test.content, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := OpenGeoIPDatabase(path); err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("%s: error = %v, want %q", test.name, err, test.wantErr)
+		}
+	}
+
+	// 검색 트리/데이터 섹션 바이트를 손상시켜도 패닉 없이 오류 또는 결과 반환
+	path := filepath.Join(dir, "corrupt.mmdb")
+	ips := []net.IP{net.ParseIP("81.2.69.160"), net.ParseIP("2.125.160.216"), net.ParseIP("2a02:d280::1"), net.ParseIP("89.160.20.112"), net.ParseIP("10.0.0.1")}
+	for i := 0; i < markerIndex; i++ {
+		for _, value := range []byte{0x00, 0x7f, 0xff} {
+			content := append([]byte(nil), valid...)
+			content[i] = value
+			if err := os.WriteFile(path, content, 0644); err != nil {
+				t.Fatal(err)
+			}
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("byte %d = %#x: panic %v", i, value, r)
+					}
+				}()
+				db, err := OpenGeoIPDatabase(path)
+				if err != nil {
+					return
+				}
+				for _, ip := range ips {
+					db.Lookup(ip)
+				}
+			}()
+		}
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Grok Pattern Module
+===================
+
+Logstash grok 패턴 호환 계층 (logging.extraction_rules 의 grok 항목)
+
+기존 ELK 파이프라인의 grok 필터를 그대로 옮겨 쓸 수 있도록
+%{PATTERN:field} 구문을 Go 정규식(RE2)으로 변환합니다.
+
+주요 기능:
+- Logstash 기본 패턴 라이브러리 (grok-patterns: 숫자/IP/호스트/경로/URI/날짜·시간/syslog/Apache 등) 내장
+- %{PATTERN}, %{PATTERN:field}, %{PATTERN:field:int} (타입 변환 접미사는 무시, 필드 값은 문자열)
+- [source][address] 같은 중첩 필드 참조는 source.address 필드로 저장
+- 규칙별 pattern_definitions 로 사용자 패턴 추가/기본 패턴 재정의
+- Oniguruma 인라인 캡처 (?<field>...) 지원
+
+RE2 호환:
+Go 정규식은 전후방 탐색/원자 그룹을 지원하지 않아 기본 패턴은 \b 단어 경계를 사용하도록 옮겨 적었습니다.
+IPV4, TIME 등은 Logstash 와 경계 판정이 조금 다를 수 있습니다.
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"regexp"  // 패턴 참조 파싱
+	"strings" // 문자열 처리
+)
+
+// grokMaxDepth 패턴 참조 최대 중첩 깊이 (순환 참조 방지)
+const grokMaxDepth = 32
+
+// grokReferenceRegex %{PATTERN}, %{PATTERN:field}, %{PATTERN:field:type} 참조
+var grokReferenceRegex = regexp.MustCompile(`%\{([A-Za-z0-9_]+)(?::([A-Za-z0-9_@.\[\]-]+))?(?::(?:int|float|string))?\}`)
+
+// grokInlineCaptureRegex Oniguruma 인라인 이름 있는 캡처 (?<field>...) (Go 의 (?P<...>) 와 구분)
+var grokInlineCaptureRegex = regexp.MustCompile(`\(\?<([A-Za-z0-9_@.\[\]-]+)>`)
+
+// grokIPv4Octet IPv4 옥텟 (0-255)
+const grokIPv4Octet = `(?:25[0-5]|2[0-4][0-9]|[0-1]?[0-9]{1,2})`
+
+// grokIPv6Pattern Logstash 기본 IPV6 패턴 (RE2 호환)
+const grokIPv6Pattern = `((([0-9A-Fa-f]{1,4}:){7}([0-9A-Fa-f]{1,4}|:))|(([0-9A-Fa-f]{1,4}:){6}(:[0-9A-Fa-f]{1,4}|((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3})|:))|(([0-9A-Fa-f]{1,4}:){5}(((:[0-9A-Fa-f]{1,4}){1,2})|:((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3})|:))|(([0-9A-Fa-f]{1,4}:){4}(((:[0-9A-Fa-f]{1,4}){1,3})|((:[0-9A-Fa-f]{1,4})?:((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}))|:))|(([0-9A-Fa-f]{1,4}:){3}(((:[0-9A-Fa-f]{1,4}){1,4})|((:[0-9A-Fa-f]{1,4}){0,2}:((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}))|:))|(([0-9A-Fa-f]{1,4}:){2}(((:[0-9A-Fa-f]{1,4}){1,5})|((:[0-9A-Fa-f]{1,4}){0,3}:((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}))|:))|(([0-9A-Fa-f]{1,4}:){1}(((:[0-9A-Fa-f]{1,4}){1,6})|((:[0-9A-Fa-f]{1,4}){0,4}:((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}))|:))|(:(((:[0-9A-Fa-f]{1,4}){1,7})|((:[0-9A-Fa-f]{1,4}){0,5}:((25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(\.(25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}))|:)))(%[0-9A-Za-z]+)?`
+
+// GrokPatterns 내장 Logstash 기본 패턴 라이브러리 (grok-patterns)
+var GrokPatterns = map[string]string{
+	// 기본 토큰
+	"USERNAME":       `[a-zA-Z0-9._-]+`,
+	"USER":           `%{USERNAME}`,
+	"EMAILLOCALPART": "[a-zA-Z0-9!#$%&'*+\\-/=?^_`{|}~]+(?:\\.[a-zA-Z0-9!#$%&'*+\\-/=?^_`{|}~]+)*",
+	"EMAILADDRESS":   `%{EMAILLOCALPART}@%{HOSTNAME}`,
+	"INT":            `(?:[+-]?(?:[0-9]+))`,
+	"BASE10NUM":      `(?:[+-]?(?:[0-9]+(?:\.[0-9]+)?|\.[0-9]+))`,
+	"NUMBER":         `(?:%{BASE10NUM})`,
+	"BASE16NUM":      `(?:[+-]?(?:0x)?(?:[0-9A-Fa-f]+))`,
+	"BASE16FLOAT":    `\b(?:[+-]?(?:0x)?(?:(?:[0-9A-Fa-f]+(?:\.[0-9A-Fa-f]*)?)|(?:\.[0-9A-Fa-f]+)))\b`,
+	"POSINT":         `\b(?:[1-9][0-9]*)\b`,
+	"NONNEGINT":      `\b(?:[0-9]+)\b`,
+	"WORD":           `\b\w+\b`,
+	"NOTSPACE":       `\S+`,
+	"SPACE":          `\s*`,
+	"DATA":           `.*?`,
+	"GREEDYDATA":     `.*`,
+	"QUOTEDSTRING":   "(?:\"(?:[^\"\\\\]|\\\\.)*\"|'(?:[^'\\\\]|\\\\.)*'|`(?:[^`\\\\]|\\\\.)*`)",
+	"QS":             `%{QUOTEDSTRING}`,
+	"UUID":           `[A-Fa-f0-9]{8}-(?:[A-Fa-f0-9]{4}-){3}[A-Fa-f0-9]{12}`,
+	"URN":            `urn:[0-9A-Za-z][0-9A-Za-z-]{0,31}:(?:%[0-9a-fA-F]{2}|[0-9A-Za-z()+,.:=@;$_!*'/?#-])+`,
+
+	// 네트워크
+	"CISCOMAC":   `(?:(?:[A-Fa-f0-9]{4}\.){2}[A-Fa-f0-9]{4})`,
+	"WINDOWSMAC": `(?:(?:[A-Fa-f0-9]{2}-){5}[A-Fa-f0-9]{2})`,
+	"COMMONMAC":  `(?:(?:[A-Fa-f0-9]{2}:){5}[A-Fa-f0-9]{2})`,
+	"MAC":        `(?:%{CISCOMAC}|%{WINDOWSMAC}|%{COMMONMAC})`,
+	"IPV6":       grokIPv6Pattern,
+	"IPV4":       `\b` + grokIPv4Octet + `[.]` + grokIPv4Octet + `[.]` + grokIPv4Octet + `[.]` + grokIPv4Octet + `\b`,
+	"IP":         `(?:%{IPV6}|%{IPV4})`,
+	"HOSTNAME":   `\b(?:[0-9A-Za-z][0-9A-Za-z-]{0,62})(?:\.(?:[0-9A-Za-z][0-9A-Za-z-]{0,62}))*(?:\.?|\b)`,
+	"IPORHOST":   `(?:%{IP}|%{HOSTNAME})`,
+	"HOSTPORT":   `%{IPORHOST}:%{POSINT}`,
+
+	// 경로 / URI
+	"PATH":         `(?:%{UNIXPATH}|%{WINPATH})`,
+	"UNIXPATH":     `(?:/[\w_%!$@:.,+~-]*)+`,
+	"TTY":          `(?:/dev/(?:pts|tty(?:[pq])?)(?:\w+)?/?(?:[0-9]+))`,
+	"WINPATH":      `(?:[A-Za-z]+:|\\)(?:\\[^\\?*]*)+`,
+	"URIPROTO":     `[A-Za-z](?:[A-Za-z0-9+\-.]+)+`,
+	"URIHOST":      `%{IPORHOST}(?::%{POSINT})?`,
+	"URIPATH":      `(?:/[A-Za-z0-9$.+!*'(){},~:;=@#%&_\-]*)+`,
+	"URIQUERY":     `[A-Za-z0-9$.+!*'|(){},~@#%&/=:;_?\-\[\]<>]*`,
+	"URIPARAM":     `\?%{URIQUERY}`,
+	"URIPATHPARAM": `%{URIPATH}(?:%{URIPARAM})?`,
+	"URI":          `%{URIPROTO}://(?:%{USER}(?::[^@]*)?@)?(?:%{URIHOST})?(?:%{URIPATHPARAM})?`,
+
+	// 날짜 / 시간
+	"MONTH":              `\b(?:[Jj]an(?:uary|uar)?|[Ff]eb(?:ruary|ruar)?|[Mm](?:a|ä)?r(?:ch|z)?|[Aa]pr(?:il)?|[Mm]a(?:y|i)?|[Jj]un(?:e|i)?|[Jj]ul(?:y|i)?|[Aa]ug(?:ust)?|[Ss]ep(?:tember)?|[Oo](?:c|k)?t(?:ober)?|[Nn]ov(?:ember)?|[Dd]e(?:c|z)(?:ember)?)\b`,
+	"MONTHNUM":           `(?:0?[1-9]|1[0-2])`,
+	"MONTHNUM2":          `(?:0[1-9]|1[0-2])`,
+	"MONTHDAY":           `(?:(?:0[1-9])|(?:[12][0-9])|(?:3[01])|[1-9])`,
+	"DAY":                `(?:Mon(?:day)?|Tue(?:sday)?|Wed(?:nesday)?|Thu(?:rsday)?|Fri(?:day)?|Sat(?:urday)?|Sun(?:day)?)`,
+	"YEAR":               `(?:\d\d){1,2}`,
+	"HOUR":               `(?:2[0123]|[01]?[0-9])`,
+	"MINUTE":             `(?:[0-5][0-9])`,
+	"SECOND":             `(?:(?:[0-5]?[0-9]|60)(?:[:.,][0-9]+)?)`,
+	"TIME":               `\b%{HOUR}:%{MINUTE}(?::%{SECOND})\b`,
+	"DATE_US":            `%{MONTHNUM}[/-]%{MONTHDAY}[/-]%{YEAR}`,
+	"DATE_EU":            `%{MONTHDAY}[./-]%{MONTHNUM}[./-]%{YEAR}`,
+	"ISO8601_TIMEZONE":   `(?:Z|[+-]%{HOUR}(?::?%{MINUTE}))`,
+	"ISO8601_SECOND":     `%{SECOND}`,
+	"TIMESTAMP_ISO8601":  `%{YEAR}-%{MONTHNUM}-%{MONTHDAY}[T ]%{HOUR}:?%{MINUTE}(?::?%{SECOND})?%{ISO8601_TIMEZONE}?`,
+	"DATE":               `%{DATE_US}|%{DATE_EU}`,
+	"DATESTAMP":          `%{DATE}[- ]%{TIME}`,
+	"TZ":                 `(?:[APMCE][SD]T|UTC)`,
+	"DATESTAMP_RFC822":   `%{DAY} %{MONTH} %{MONTHDAY} %{YEAR} %{TIME} %{TZ}`,
+	"DATESTAMP_RFC2822":  `%{DAY}, %{MONTHDAY} %{MONTH} %{YEAR} %{TIME} %{ISO8601_TIMEZONE}`,
+	"DATESTAMP_OTHER":    `%{DAY} %{MONTH} %{MONTHDAY} %{TIME} %{TZ} %{YEAR}`,
+	"DATESTAMP_EVENTLOG": `%{YEAR}%{MONTHNUM2}%{MONTHDAY}%{HOUR}%{MINUTE}%{SECOND}`,
+	"HTTPDATE":           `%{MONTHDAY}/%{MONTH}/%{YEAR}:%{TIME} %{INT}`,
+
+	// syslog
+	"SYSLOGTIMESTAMP": `%{MONTH} +%{MONTHDAY} %{TIME}`,
+	"PROG":            `[\x21-\x5a\x5c\x5e-\x7e]+`,
+	"SYSLOGPROG":      `%{PROG:program}(?:\[%{POSINT:pid}\])?`,
+	"SYSLOGHOST":      `%{IPORHOST}`,
+	"SYSLOGFACILITY":  `<%{NONNEGINT:facility}.%{NONNEGINT:priority}>`,
+	"SYSLOGBASE":      `%{SYSLOGTIMESTAMP:timestamp} (?:%{SYSLOGFACILITY} )?%{SYSLOGHOST:logsource} %{SYSLOGPROG}:`,
+	"SYSLOGBASE2":     `(?:%{SYSLOGTIMESTAMP:timestamp}|%{TIMESTAMP_ISO8601:timestamp8601}) (?:%{SYSLOGFACILITY} )?%{SYSLOGHOST:logsource}+(?: %{SYSLOGPROG}:|)`,
+	"SYSLOGLINE":      `%{SYSLOGBASE2} %{GREEDYDATA:message}`,
+
+	// 웹 서버
+	"HTTPDUSER":         `%{EMAILADDRESS}|%{USER}`,
+	"HTTPDERROR_DATE":   `%{DAY} %{MONTH} %{MONTHDAY} %{TIME} %{YEAR}`,
+	"COMMONAPACHELOG":   `%{IPORHOST:clientip} %{HTTPDUSER:ident} %{USER:auth} \[%{HTTPDATE:timestamp}\] "(?:%{WORD:verb} %{NOTSPACE:request}(?: HTTP/%{NUMBER:httpversion})?|%{DATA:rawrequest})" %{NUMBER:response} (?:%{NUMBER:bytes}|-)`,
+	"COMBINEDAPACHELOG": `%{COMMONAPACHELOG} %{QS:referrer} %{QS:agent}`,
+
+	// 로그 레벨
+	"LOGLEVEL": `(?:[Aa]lert|ALERT|[Tt]race|TRACE|[Dd]ebug|DEBUG|[Nn]otice|NOTICE|[Ii]nfo?(?:rmation)?|INFO?(?:RMATION)?|[Ww]arn?(?:ing)?|WARN?(?:ING)?|[Ee]rr?(?:or)?|ERR?(?:OR)?|[Cc]rit?(?:ical)?|CRIT?(?:ICAL)?|[Ff]atal|FATAL|[Ss]evere|SEVERE|EMERG(?:ENCY)?|[Ee]merg(?:ency)?)`,
+
+	// 사용자 정의 파서 약식 패턴 (pattern_parser.go, 이름 없이 쓰면 timestamp/level/message 로 캡처)
+	"TIMESTAMP": `(?:%{TIMESTAMP_ISO8601}|%{YEAR}/%{MONTHNUM}/%{MONTHDAY} %{TIME}|%{SYSLOGTIMESTAMP}|%{HTTPDATE}|%{DATESTAMP})`,
+	"LEVEL":     `%{LOGLEVEL}`,
+	"MSG":       `%{GREEDYDATA}`,
+}
+
+// grokCompiler grok 식 하나를 정규식으로 펼치는 상태 (캡처 그룹 이름 → 필드 이름)
+type grokCompiler struct {
+	definitions map[string]string // 규칙별 사용자 패턴 (기본 패턴보다 우선)
+	fields      map[string]string // 정규식 그룹 이름 (grok1, grok2, ...) → 필드 이름
+	err         error
+}
+
+// CompileGrok grok 식을 Go 정규식으로 변환 (정규식 문자열, 그룹 이름 → 필드 이름 반환)
+// definitions 는 Logstash pattern_definitions 처럼 기본 패턴에 추가/재정의할 패턴
+func CompileGrok(expression string, definitions map[string]string) (string, map[string]string, error) {
+	compiler := &grokCompiler{definitions: definitions, fields: make(map[string]string)}
+	pattern := compiler.expand(expression, 0)
+	if compiler.err != nil {
+		return "", nil, compiler.err
+	}
+	return pattern, compiler.fields, nil
+}
+
+// expand %{...} 참조와 인라인 캡처를 재귀적으로 펼침
+func (gc *grokCompiler) expand(expression string, depth int) string {
+	if depth > grokMaxDepth {
+		gc.err = fmt.Errorf("grok patterns nested too deeply (recursive definition?)")
+		return ""
+	}
+
+	expression = grokInlineCaptureRegex.ReplaceAllStringFunc(expression, func(match string) string {
+		field := grokInlineCaptureRegex.FindStringSubmatch(match)[1]
+		return "(?P<" + gc.group(field) + ">"
+	})
+	return grokReferenceRegex.ReplaceAllStringFunc(expression, func(match string) string {
+		if gc.err != nil {
+			return ""
+		}
+		parts := grokReferenceRegex.FindStringSubmatch(match)
+		name, field := parts[1], parts[2]
+
+		definition, ok := gc.definitions[name]
+		if !ok {
+			definition, ok = GrokPatterns[name]
+		}
+		if !ok {
+			gc.err = fmt.Errorf("unknown grok pattern %%{%s}", name)
+			return ""
+		}
+
+		expanded := gc.expand(definition, depth+1)
+		if field == "" {
+			return "(?:" + expanded + ")"
+		}
+		return "(?P<" + gc.group(field) + ">" + expanded + ")"
+	})
+}
+
+// group 필드에 정규식에서 쓸 수 있는 그룹 이름 부여 ([source][address] → source.address)
+func (gc *grokCompiler) group(field string) string {
+	if strings.HasPrefix(field, "[") {
+		field = strings.Trim(strings.ReplaceAll(field, "][", "."), "[]")
+	}
+	name := fmt.Sprintf("grok%d", len(gc.fields)+1)
+	gc.fields[name] = field
+	return name
+}
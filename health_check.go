@@ -0,0 +1,149 @@
+/*
+Health Check Suppression Module
+===============================
+
+로드밸런서/오케스트레이터 헬스 체크 요청 제외
+
+ALB/ELB, Kubernetes, GCP 로드밸런서 등이 몇 초마다 보내는 헬스 체크 요청이 웹 액세스 로그의
+대부분을 차지하여 AI 분석 기준선과 통계를 왜곡하므로, 처리 파이프라인 앞단에서 제외하고 건수만 집계합니다.
+
+주요 기능:
+- 요청 경로(쿼리 제외) 정확히 일치 또는 User-Agent 부분 문자열 일치로 헬스 체크 판정
+- 기본 경로/User-Agent 내장, 설정 파일(logging.health_check_paths / health_check_user_agents)로 교체
+- 부분 문자열 사전 검사로 헬스 체크가 아닌 줄은 파싱하지 않음
+- 실패 응답(4xx/5xx)은 장애 신호이므로 제외하지 않음
+- 일치 규칙별 제외 건수 집계 (상태 API, 정기 보고서)
+*/
+package main
+
+import (
+	"strings" // 문자열 처리
+	"sync"    // 집계 보호
+	"time"    // 집계 시작 시각
+)
+
+// DefaultHealthCheckPaths 기본 헬스 체크 경로
+var DefaultHealthCheckPaths = []string{
+	"/health", "/healthz", "/healthcheck", "/health-check", "/_health",
+	"/readyz", "/livez", "/ping", "/elb-status",
+}
+
+// DefaultHealthCheckUserAgents 기본 헬스 체크 User-Agent (부분 문자열, 대소문자 구분)
+var DefaultHealthCheckUserAgents = []string{
+	"ELB-HealthChecker", // AWS ALB/ELB
+	"kube-probe",        // Kubernetes liveness/readiness
+	"GoogleHC",          // GCP 로드밸런서
+	"Consul Health Check",
+	"Envoy/HC",
+	"UptimeRobot",
+	"Pingdom",
+}
+
+// HealthCheckStats 헬스 체크 제외 집계
+type HealthCheckStats struct {
+	Suppressed int64            `json:"suppressed"` // 제외한 요청 수
+	ByMatch    map[string]int64 `json:"by_match"`   // 일치 규칙별 건수 (예: "path /healthz", "user_agent kube-probe")
+	Since      time.Time        `json:"since"`      // 집계 시작 시각
+}
+
+// HealthCheckFilter 헬스 체크 요청 판정 및 제외 건수 집계
+// 판정은 처리 고루틴에서만 호출되지만 집계는 보고서 고루틴에서도 읽으므로 뮤텍스로 보호
+type HealthCheckFilter struct {
+	paths      []string
+	userAgents []string
+
+	mutex sync.Mutex
+	stats HealthCheckStats
+}
+
+// NewHealthCheckFilter 헬스 체크 필터 생성 (목록이 비어 있으면 기본 목록 사용)
+func NewHealthCheckFilter(paths, userAgents []string) *HealthCheckFilter {
+	if len(paths) == 0 {
+		paths = DefaultHealthCheckPaths
+	}
+	if len(userAgents) == 0 {
+		userAgents = DefaultHealthCheckUserAgents
+	}
+	return &HealthCheckFilter{
+		paths:      append([]string{}, paths...),
+		userAgents: append([]string{}, userAgents...),
+		stats:      HealthCheckStats{ByMatch: make(map[string]int64), Since: time.Now()},
+	}
+}
+
+// Suppress 헬스 체크 요청이면 집계 후 true 반환
+// parse 는 사전 검사를 통과한 줄에서만 호출되어 요청 경로/User-Agent/상태 코드 확인에 사용
+func (f *HealthCheckFilter) Suppress(line string, parse func() *ParsedLog) bool {
+	if f == nil || !f.mayMatch(line) {
+		return false
+	}
+
+	parsed := parse()
+	if parsed == nil || parsed.HTTPDetails == nil {
+		return false
+	}
+	details := parsed.HTTPDetails
+	if details.StatusCode >= 400 {
+		return false // 실패한 헬스 체크는 그대로 분석/알림
+	}
+
+	match := f.match(details)
+	if match == "" {
+		return false
+	}
+	f.mutex.Lock()
+	f.stats.Suppressed++
+	f.stats.ByMatch[match]++
+	f.mutex.Unlock()
+	return true
+}
+
+// mayMatch 경로나 User-Agent 문자열이 줄에 포함되어 있는지 빠르게 확인
+func (f *HealthCheckFilter) mayMatch(line string) bool {
+	for _, path := range f.paths {
+		if strings.Contains(line, path) {
+			return true
+		}
+	}
+	for _, userAgent := range f.userAgents {
+		if strings.Contains(line, userAgent) {
+			return true
+		}
+	}
+	return false
+}
+
+// match 파싱된 요청이 일치한 규칙 이름 (일치하지 않으면 "")
+func (f *HealthCheckFilter) match(details *HTTPLogDetails) string {
+	path, _, _ := strings.Cut(details.URL, "?")
+	for _, candidate := range f.paths {
+		if path == candidate {
+			return "path " + candidate
+		}
+	}
+	for _, userAgent := range f.userAgents {
+		if details.UserAgent != "" && strings.Contains(details.UserAgent, userAgent) {
+			return "user_agent " + userAgent
+		}
+	}
+	return ""
+}
+
+// Stats 현재까지의 제외 집계 (복사본)
+func (f *HealthCheckFilter) Stats() HealthCheckStats {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	stats := f.stats
+	stats.ByMatch = make(map[string]int64, len(f.stats.ByMatch))
+	for match, count := range f.stats.ByMatch {
+		stats.ByMatch[match] = count
+	}
+	return stats
+}
+
+// Update 재로드된 설정의 경로/User-Agent 목록 적용 (집계는 유지)
+func (f *HealthCheckFilter) Update(paths, userAgents []string) {
+	updated := NewHealthCheckFilter(paths, userAgents)
+	f.paths = updated.paths
+	f.userAgents = updated.userAgents
+}
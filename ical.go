@@ -0,0 +1,538 @@
+/*
+iCalendar Module
+================
+
+점검 일정 가져오기용 iCalendar (RFC 5545) 부분 집합 파서 (외부 의존성 없음)
+
+지원 범위:
+  - VEVENT 의 UID, SUMMARY, DESCRIPTION, LOCATION, CATEGORIES, STATUS, DTSTART, DTEND, DURATION
+  - 시각: UTC (…Z), TZID 매개변수 (IANA 이름, 알 수 없으면 로컬 시간대), 부동 시각 (로컬), 종일 일정 (VALUE=DATE)
+  - 반복: RRULE FREQ=DAILY/WEEKLY/MONTHLY/YEARLY, INTERVAL, COUNT, UNTIL (Z 가 없으면 DTSTART 시간대), BYDAY (WEEKLY 요일, MONTHLY 는 "2TU", "-1FR" 같은 n번째 요일),
+    BYMONTHDAY (MONTHLY), EXDATE, RECURRENCE-ID 로 바뀐 회차
+  - 줄 접기 (공백/탭으로 시작하는 줄), 텍스트 이스케이프 (\n \, \; \\)
+
+미지원 (해당 일정은 건너뛰고 개수만 보고):
+- FREQ=HOURLY/MINUTELY/SECONDLY, BYSETPOS, BYWEEKNO, BYYEARDAY, RDATE
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"sort"    // 회차 정렬
+	"strconv" // 숫자 파싱
+	"strings" // 문자열 처리
+	"time"    // 일정 시각
+)
+
+// icalMaxPeriods 반복 일정 하나를 펼칠 때 살펴볼 최대 주기 수 (무한 반복 방지)
+const icalMaxPeriods = 20000
+
+// icalEvent VEVENT 한 건
+type icalEvent struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Categories   []string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	Cancelled    bool
+	Rule         *icalRule
+	Exdates      map[int64]bool // 제외한 회차 시작 시각 (Unix 초)
+	RecurrenceID time.Time      // 반복 일정의 바뀐 회차 (비어 있으면 원본 일정)
+	duration     time.Duration  // DURATION (DTEND 가 없을 때 finish 에서 적용)
+}
+
+// icalRule RRULE
+type icalRule struct {
+	Freq       string // DAILY, WEEKLY, MONTHLY, YEARLY
+	Interval   int
+	Count      int       // 0 이면 제한 없음
+	Until      time.Time // 비어 있으면 제한 없음
+	ByDay      []icalWeekday
+	ByMonthDay []int
+	untilLocal bool // Z 없는 UNTIL (finish 에서 DTSTART 시간대로 다시 해석)
+}
+
+// icalWeekday BYDAY 항목 (Ordinal 0 이면 모든 해당 요일)
+type icalWeekday struct {
+	Ordinal int
+	Day     time.Weekday
+}
+
+// icalOccurrence 펼친 일정 회차
+type icalOccurrence struct {
+	Event *icalEvent
+	Start time.Time
+	End   time.Time
+}
+
+// icalProperty 한 줄 (이름;매개변수:값)
+type icalProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// icalWeekdays 요일 약어
+var icalWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseICalendar iCalendar 문서의 일정 목록과 지원하지 않아 건너뛴 일정 수
+// RECURRENCE-ID 로 바뀐 회차는 원본 반복 일정의 해당 회차를 대신함
+func parseICalendar(data []byte) ([]icalEvent, int, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\n ", "")
+	text = strings.ReplaceAll(text, "\n\t", "")
+	if !strings.Contains(text, "BEGIN:VCALENDAR") {
+		return nil, 0, fmt.Errorf("not an iCalendar document (BEGIN:VCALENDAR missing)")
+	}
+
+	var events []icalEvent
+	var current *icalEvent
+	var currentErr error
+	skipped := 0
+	depth := 0 // VEVENT 안의 VALARM 등 하위 구성 요소
+	for number, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		property, err := parseICalProperty(line)
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %v", number+1, err)
+		}
+
+		switch {
+		case property.Name == "BEGIN" && property.Value == "VEVENT" && current == nil:
+			current = &icalEvent{Exdates: make(map[int64]bool)}
+			currentErr = nil
+			depth = 0
+		case current == nil:
+		case property.Name == "BEGIN":
+			depth++
+		case property.Name == "END" && depth > 0:
+			depth--
+		case property.Name == "END" && property.Value == "VEVENT":
+			if currentErr == nil {
+				currentErr = current.finish()
+			}
+			if currentErr != nil {
+				skipped++
+			} else {
+				events = append(events, *current)
+			}
+			current = nil
+		case depth > 0 || currentErr != nil:
+		default:
+			currentErr = current.set(property)
+		}
+	}
+	return applyICalOverrides(events), skipped, nil
+}
+
+// parseICalProperty "NAME;PARAM=value:VALUE" 한 줄 파싱 (따옴표 안의 ':' ';' 는 값의 일부)
+func parseICalProperty(line string) (icalProperty, error) {
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icalProperty{}, fmt.Errorf("missing ':' in %q", line)
+	}
+
+	head := strings.Split(line[:colon], ";")
+	property := icalProperty{Name: strings.ToUpper(head[0]), Params: make(map[string]string), Value: line[colon+1:]}
+	for _, param := range head[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			property.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return property, nil
+}
+
+// set 속성 하나 반영 (지원하지 않는 반복 규칙이면 에러)
+func (e *icalEvent) set(property icalProperty) error {
+	var err error
+	switch property.Name {
+	case "UID":
+		e.UID = property.Value
+	case "SUMMARY":
+		e.Summary = unescapeICalText(property.Value)
+	case "DESCRIPTION":
+		e.Description = unescapeICalText(property.Value)
+	case "LOCATION":
+		e.Location = unescapeICalText(property.Value)
+	case "CATEGORIES":
+		for _, category := range splitICalList(property.Value) {
+			e.Categories = append(e.Categories, unescapeICalText(category))
+		}
+	case "STATUS":
+		e.Cancelled = strings.EqualFold(property.Value, "CANCELLED")
+	case "DTSTART":
+		e.Start, e.AllDay, err = parseICalTime(property.Value, property.Params)
+	case "DTEND":
+		e.End, _, err = parseICalTime(property.Value, property.Params)
+	case "DURATION":
+		e.duration, err = parseICalDuration(property.Value)
+	case "RRULE":
+		e.Rule, err = parseICalRule(property.Value, property.Params)
+	case "EXDATE":
+		for _, value := range strings.Split(property.Value, ",") {
+			exdate, _, parseErr := parseICalTime(value, property.Params)
+			if parseErr != nil {
+				return parseErr
+			}
+			e.Exdates[exdate.Unix()] = true
+		}
+	case "RECURRENCE-ID":
+		e.RecurrenceID, _, err = parseICalTime(property.Value, property.Params)
+	case "RDATE":
+		return fmt.Errorf("RDATE is not supported")
+	}
+	return err
+}
+
+// finish 일정 검증 (종료 시각이 없으면 종일 일정은 하루, 시각 일정은 건너뜀)
+func (e *icalEvent) finish() error {
+	if e.Start.IsZero() {
+		return fmt.Errorf("event %q has no DTSTART", e.Summary)
+	}
+	if e.End.IsZero() && e.duration != 0 {
+		e.End = e.Start.Add(e.duration)
+	}
+	if e.End.IsZero() && e.AllDay {
+		e.End = e.Start.AddDate(0, 0, 1)
+	}
+	if !e.End.After(e.Start) {
+		return fmt.Errorf("event %q has no duration", e.Summary)
+	}
+	if e.Rule != nil && e.Rule.untilLocal {
+		// RRULE 에는 TZID 가 없으므로 Z 없는 UNTIL (종일 일정, 또는 RFC 와 달리 UTC 로 쓰지 않은 TZID 일정) 은 DTSTART 시간대의 벽시계 시각
+		until := e.Rule.Until
+		e.Rule.Until = time.Date(until.Year(), until.Month(), until.Day(), until.Hour(), until.Minute(), until.Second(), 0, e.Start.Location())
+	}
+	return nil
+}
+
+// parseICalTime DATE-TIME / DATE 값 (종일 일정이면 true)
+func parseICalTime(value string, params map[string]string) (time.Time, bool, error) {
+	location := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	value = strings.TrimSpace(value)
+	switch {
+	case params["VALUE"] == "DATE" || len(value) == 8:
+		parsed, err := time.ParseInLocation("20060102", value, location)
+		return parsed, true, err
+	case strings.HasSuffix(value, "Z"):
+		parsed, err := time.Parse("20060102T150405Z", value)
+		return parsed, false, err
+	default:
+		parsed, err := time.ParseInLocation("20060102T150405", value, location)
+		return parsed, false, err
+	}
+}
+
+// parseICalDuration ISO 8601 기간 (P1D, PT2H30M, P1W, -PT15M)
+func parseICalDuration(value string) (time.Duration, error) {
+	text := strings.ToUpper(strings.TrimSpace(value))
+	sign := time.Duration(1)
+	if strings.HasPrefix(text, "-") {
+		sign = -1
+	}
+	text = strings.TrimLeft(text, "+-")
+	if !strings.HasPrefix(text, "P") || len(text) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	var total time.Duration
+	inTime := false
+	number := ""
+	for _, r := range text[1:] {
+		switch {
+		case r == 'T':
+			inTime = true
+			continue
+		case r >= '0' && r <= '9':
+			number += string(r)
+			continue
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		number = ""
+		switch {
+		case r == 'W' && !inTime:
+			total += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			total += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			total += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			total += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			total += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+	}
+	if number != "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return sign * total, nil
+}
+
+// parseICalRule RRULE 값 파싱 (지원하지 않는 규칙이면 에러)
+func parseICalRule(value string, params map[string]string) (*icalRule, error) {
+	rule := &icalRule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(val)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(val)
+			if err == nil && rule.Interval < 1 {
+				err = fmt.Errorf("INTERVAL must be positive")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(val)
+		case "UNTIL":
+			rule.Until, _, err = parseICalTime(val, params)
+			rule.untilLocal = !strings.HasSuffix(val, "Z")
+			if err == nil && len(val) == 8 {
+				// 날짜만 지정한 UNTIL 은 그날 끝까지 포함
+				rule.Until = rule.Until.AddDate(0, 0, 1).Add(-time.Second)
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(val, ",") {
+				day = strings.ToUpper(strings.TrimSpace(day))
+				if len(day) < 2 {
+					return nil, fmt.Errorf("invalid BYDAY %q", val)
+				}
+				weekday, ok := icalWeekdays[day[len(day)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY %q", val)
+				}
+				ordinal := 0
+				if prefix := day[:len(day)-2]; prefix != "" {
+					if ordinal, err = strconv.Atoi(prefix); err != nil || ordinal == 0 || ordinal < -5 || ordinal > 5 {
+						return nil, fmt.Errorf("invalid BYDAY %q", val)
+					}
+				}
+				rule.ByDay = append(rule.ByDay, icalWeekday{Ordinal: ordinal, Day: weekday})
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(val, ",") {
+				n, convErr := strconv.Atoi(strings.TrimSpace(day))
+				if convErr != nil || n < 1 || n > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY %q", val)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "WKST", "":
+		default:
+			return nil, fmt.Errorf("RRULE %s is not supported", strings.ToUpper(key))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid RRULE %s: %v", key, err)
+		}
+	}
+
+	switch rule.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("RRULE FREQ=%s is not supported", rule.Freq)
+	}
+	for _, day := range rule.ByDay {
+		if day.Ordinal != 0 && rule.Freq != "MONTHLY" {
+			return nil, fmt.Errorf("RRULE BYDAY with ordinal is only supported for FREQ=MONTHLY")
+		}
+	}
+	if rule.Freq == "DAILY" || rule.Freq == "YEARLY" {
+		if len(rule.ByDay) > 0 || len(rule.ByMonthDay) > 0 {
+			return nil, fmt.Errorf("RRULE BYDAY/BYMONTHDAY is not supported for FREQ=%s", rule.Freq)
+		}
+	}
+	return rule, nil
+}
+
+// applyICalOverrides RECURRENCE-ID 로 바뀐 회차를 원본 반복 일정에서 제외 (바뀐 회차는 단독 일정으로 남김)
+func applyICalOverrides(events []icalEvent) []icalEvent {
+	masters := make(map[string]*icalEvent)
+	for i := range events {
+		if events[i].Rule != nil && events[i].RecurrenceID.IsZero() {
+			masters[events[i].UID] = &events[i]
+		}
+	}
+	for _, event := range events {
+		if event.RecurrenceID.IsZero() {
+			continue
+		}
+		if master := masters[event.UID]; master != nil {
+			master.Exdates[event.RecurrenceID.Unix()] = true
+		}
+	}
+	return events
+}
+
+// Occurrences from~to 와 겹치는 회차 (취소된 일정과 EXDATE 회차 제외, 시작 시각 순)
+func (e *icalEvent) Occurrences(from, to time.Time) []icalOccurrence {
+	if e.Cancelled {
+		return nil
+	}
+	duration := e.End.Sub(e.Start)
+	days := int((duration + 12*time.Hour) / (24 * time.Hour)) // 종일 일정 길이 (서머타임 전환일은 23/25시간)
+	var occurrences []icalOccurrence
+	add := func(start time.Time) {
+		if e.Exdates[start.Unix()] {
+			return
+		}
+		end := start.Add(duration)
+		if e.AllDay {
+			end = start.AddDate(0, 0, days)
+		}
+		if end.After(from) && start.Before(to) {
+			occurrences = append(occurrences, icalOccurrence{Event: e, Start: start, End: end})
+		}
+	}
+	if e.Rule == nil {
+		add(e.Start)
+		return occurrences
+	}
+
+	count := 0
+	for period := 0; period < icalMaxPeriods; period++ {
+		starts := e.Rule.periodStarts(e.Start, period)
+		for _, start := range starts {
+			if start.Before(e.Start) {
+				continue
+			}
+			if !e.Rule.Until.IsZero() && start.After(e.Rule.Until) {
+				return occurrences
+			}
+			count++
+			if e.Rule.Count > 0 && count > e.Rule.Count {
+				return occurrences
+			}
+			if !start.Before(to) {
+				return occurrences
+			}
+			add(start)
+		}
+	}
+	return occurrences
+}
+
+// periodStarts n번째 반복 주기 안의 회차 시작 시각 (DTSTART 의 시각/시간대 유지, 시간 순)
+func (r *icalRule) periodStarts(first time.Time, period int) []time.Time {
+	hour, minute, second := first.Clock()
+	location := first.Location()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, location)
+	}
+	step := period * r.Interval
+
+	var starts []time.Time
+	switch r.Freq {
+	case "DAILY":
+		starts = append(starts, at(first.Year(), first.Month(), first.Day()+step))
+	case "WEEKLY":
+		// DTSTART 가 속한 주의 월요일 기준
+		monday := first.Day() - (int(first.Weekday())+6)%7 + step*7
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []icalWeekday{{Day: first.Weekday()}}
+		}
+		for _, day := range days {
+			starts = append(starts, at(first.Year(), first.Month(), monday+(int(day.Day)+6)%7))
+		}
+	case "MONTHLY":
+		month := time.Date(first.Year(), first.Month()+time.Month(step), 1, 0, 0, 0, 0, location)
+		year, monthOf := month.Year(), month.Month()
+		last := daysInMonth(year, monthOf)
+		switch {
+		case len(r.ByDay) > 0:
+			for _, day := range r.ByDay {
+				for _, d := range nthWeekdays(year, monthOf, day, location) {
+					starts = append(starts, at(year, monthOf, d))
+				}
+			}
+		case len(r.ByMonthDay) > 0:
+			for _, d := range r.ByMonthDay {
+				if d <= last {
+					starts = append(starts, at(year, monthOf, d))
+				}
+			}
+		case first.Day() <= last:
+			starts = append(starts, at(year, monthOf, first.Day()))
+		}
+	case "YEARLY":
+		year := first.Year() + step
+		if first.Day() <= daysInMonth(year, first.Month()) {
+			starts = append(starts, at(year, first.Month(), first.Day()))
+		}
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	return starts
+}
+
+// daysInMonth 달의 날짜 수
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nthWeekdays 달 안에서 BYDAY 항목에 해당하는 날짜 (Ordinal 0 이면 모든 해당 요일, 음수면 끝에서부터)
+func nthWeekdays(year int, month time.Month, day icalWeekday, location *time.Location) []int {
+	var days []int
+	for d := 1; d <= daysInMonth(year, month); d++ {
+		if time.Date(year, month, d, 0, 0, 0, 0, location).Weekday() == day.Day {
+			days = append(days, d)
+		}
+	}
+	switch {
+	case day.Ordinal == 0:
+		return days
+	case day.Ordinal > 0 && day.Ordinal <= len(days):
+		return days[day.Ordinal-1 : day.Ordinal]
+	case day.Ordinal < 0 && -day.Ordinal <= len(days):
+		return days[len(days)+day.Ordinal : len(days)+day.Ordinal+1]
+	}
+	return nil
+}
+
+// unescapeICalText TEXT 값의 이스케이프 해제
+func unescapeICalText(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return replacer.Replace(value)
+}
+
+// splitICalList 이스케이프하지 않은 ',' 로 나눈 목록
+func splitICalList(value string) []string {
+	var items []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			items = append(items, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, value[start:])
+}
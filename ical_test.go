@@ -0,0 +1,216 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// icalTestOccurrences VEVENT 들을 감싼 문서를 파싱해 창 안의 회차를 "시작/종료" (일정 시간대 벽시계) 로 반환
+func icalTestOccurrences(t *testing.T, events string, from, to time.Time) []string {
+	t.Helper()
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.ReplaceAll(strings.TrimSpace(events), "\n", "\r\n") + "\r\nEND:VCALENDAR\r\n"
+	parsed, skipped, err := parseICalendar([]byte(data))
+	if err != nil || skipped != 0 {
+		t.Fatalf("parseICalendar: %v (skipped %d)", err, skipped)
+	}
+	var occurrences []icalOccurrence
+	for i := range parsed {
+		occurrences = append(occurrences, parsed[i].Occurrences(from, to)...)
+	}
+	sort.Slice(occurrences, func(i, j int) bool { return occurrences[i].Start.Before(occurrences[j].Start) })
+
+	var result []string
+	for _, occurrence := range occurrences {
+		result = append(result, occurrence.Start.Format("2006-01-02T15:04")+"/"+occurrence.End.Format("2006-01-02T15:04"))
+	}
+	return result
+}
+
+// TestICalRecurrence RRULE 펼치기 (UNTIL/TZID, COUNT, EXDATE, 종일 일정)
+func TestICalRecurrence(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		events string
+		from   time.Time // 비어 있으면 2026 년 전체
+		want   []string
+	}{
+		{"until utc with tzid across dst end", `
+BEGIN:VEVENT
+UID:until-utc
+DTSTART;TZID=America/New_York:20261030T090000
+DTEND;TZID=America/New_York:20261030T100000
+RRULE:FREQ=DAILY;UNTIL=20261102T140000Z
+END:VEVENT`, time.Time{}, []string{
+			"2026-10-30T09:00/2026-10-30T10:00", "2026-10-31T09:00/2026-10-31T10:00",
+			"2026-11-01T09:00/2026-11-01T10:00", "2026-11-02T09:00/2026-11-02T10:00", // 11-02 09:00 EST = UNTIL (포함)
+		}},
+		{"until utc excludes later start", `
+BEGIN:VEVENT
+UID:until-exclusive
+DTSTART;TZID=America/New_York:20261030T090000
+DURATION:PT30M
+RRULE:FREQ=DAILY;UNTIL=20261101T135959Z
+END:VEVENT`, time.Time{}, []string{
+			"2026-10-30T09:00/2026-10-30T09:30", "2026-10-31T09:00/2026-10-31T09:30", // 11-01 09:00 EST = 14:00Z
+		}},
+		{"floating until uses dtstart zone", `
+BEGIN:VEVENT
+UID:until-floating
+RRULE:FREQ=DAILY;UNTIL=20261102T090000
+DTSTART;TZID=America/Los_Angeles:20261030T090000
+DTEND;TZID=America/Los_Angeles:20261030T093000
+END:VEVENT`, time.Time{}, []string{
+			"2026-10-30T09:00/2026-10-30T09:30", "2026-10-31T09:00/2026-10-31T09:30",
+			"2026-11-01T09:00/2026-11-01T09:30", "2026-11-02T09:00/2026-11-02T09:30",
+		}},
+		{"monthly last friday until", `
+BEGIN:VEVENT
+UID:monthly
+DTSTART;TZID=Asia/Seoul:20261030T230000
+DTEND;TZID=Asia/Seoul:20261031T010000
+RRULE:FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20261231T000000Z
+END:VEVENT`, time.Time{}, []string{
+			"2026-10-30T23:00/2026-10-31T01:00", "2026-11-27T23:00/2026-11-28T01:00", "2026-12-25T23:00/2026-12-26T01:00",
+		}},
+		{"count weekly byday", `
+BEGIN:VEVENT
+UID:count
+DTSTART:20261005T020000Z
+DTEND:20261005T030000Z
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=5
+END:VEVENT`, time.Time{}, []string{
+			"2026-10-05T02:00/2026-10-05T03:00", "2026-10-07T02:00/2026-10-07T03:00", "2026-10-12T02:00/2026-10-12T03:00",
+			"2026-10-14T02:00/2026-10-14T03:00", "2026-10-19T02:00/2026-10-19T03:00",
+		}},
+		{"count includes occurrences before the window", `
+BEGIN:VEVENT
+UID:count-window
+DTSTART:20261001T120000Z
+DTEND:20261001T130000Z
+RRULE:FREQ=DAILY;COUNT=3
+END:VEVENT`, time.Date(2026, 10, 2, 12, 30, 0, 0, time.UTC), []string{
+			"2026-10-02T12:00/2026-10-02T13:00", "2026-10-03T12:00/2026-10-03T13:00",
+		}},
+		{"exdate counts toward count", `
+BEGIN:VEVENT
+UID:exdate-count
+DTSTART:20261001T120000Z
+DTEND:20261001T130000Z
+RRULE:FREQ=DAILY;COUNT=4
+EXDATE:20261002T120000Z
+END:VEVENT`, time.Time{}, []string{
+			"2026-10-01T12:00/2026-10-01T13:00", "2026-10-03T12:00/2026-10-03T13:00", "2026-10-04T12:00/2026-10-04T13:00",
+		}},
+		{"exdate list with tzid", `
+BEGIN:VEVENT
+UID:exdate-tzid
+DTSTART;TZID=Europe/Berlin:20261005T080000
+DTEND;TZID=Europe/Berlin:20261005T083000
+RRULE:FREQ=DAILY;COUNT=5
+EXDATE;TZID=Europe/Berlin:20261006T080000,20261008T080000
+END:VEVENT`, time.Time{}, []string{
+			"2026-10-05T08:00/2026-10-05T08:30", "2026-10-07T08:00/2026-10-07T08:30", "2026-10-09T08:00/2026-10-09T08:30",
+		}},
+		{"exdate in utc for tzid event", `
+BEGIN:VEVENT
+UID:exdate-utc
+DTSTART;TZID=Asia/Seoul:20261005T090000
+DTEND;TZID=Asia/Seoul:20261005T100000
+RRULE:FREQ=DAILY;COUNT=3
+EXDATE:20261006T000000Z
+END:VEVENT`, time.Time{}, []string{
+			"2026-10-05T09:00/2026-10-05T10:00", "2026-10-07T09:00/2026-10-07T10:00",
+		}},
+		{"recurrence-id override", `
+BEGIN:VEVENT
+UID:override
+DTSTART:20261005T090000Z
+DTEND:20261005T100000Z
+RRULE:FREQ=DAILY;COUNT=3
+END:VEVENT
+BEGIN:VEVENT
+UID:override
+RECURRENCE-ID:20261006T090000Z
+DTSTART:20261006T150000Z
+DTEND:20261006T170000Z
+END:VEVENT`, time.Time{}, []string{
+			"2026-10-05T09:00/2026-10-05T10:00", "2026-10-06T15:00/2026-10-06T17:00", "2026-10-07T09:00/2026-10-07T10:00",
+		}},
+		{"all-day without dtend", `
+BEGIN:VEVENT
+UID:all-day
+DTSTART;VALUE=DATE:20261020
+END:VEVENT`, time.Time{}, []string{"2026-10-20T00:00/2026-10-21T00:00"}},
+		{"all-day weekly until date", `
+BEGIN:VEVENT
+UID:all-day-weekly
+DTSTART;VALUE=DATE:20261001
+DTEND;VALUE=DATE:20261003
+RRULE:FREQ=WEEKLY;UNTIL=20261015
+END:VEVENT`, time.Time{}, []string{
+			"2026-10-01T00:00/2026-10-03T00:00", "2026-10-08T00:00/2026-10-10T00:00", "2026-10-15T00:00/2026-10-17T00:00",
+		}},
+		{"all-day exdate", `
+BEGIN:VEVENT
+UID:all-day-exdate
+DTSTART;VALUE=DATE:20261001
+RRULE:FREQ=DAILY;COUNT=4
+EXDATE;VALUE=DATE:20261003
+END:VEVENT`, time.Time{}, []string{
+			"2026-10-01T00:00/2026-10-02T00:00", "2026-10-02T00:00/2026-10-03T00:00", "2026-10-04T00:00/2026-10-05T00:00",
+		}},
+		{"all-day across dst start", `
+BEGIN:VEVENT
+UID:all-day-dst
+DTSTART;TZID=America/New_York;VALUE=DATE:20260307
+RRULE:FREQ=DAILY;COUNT=3
+END:VEVENT`, time.Time{}, []string{
+			"2026-03-07T00:00/2026-03-08T00:00", "2026-03-08T00:00/2026-03-09T00:00", "2026-03-09T00:00/2026-03-10T00:00",
+		}},
+		{"cancelled", `
+BEGIN:VEVENT
+UID:cancelled
+DTSTART:20261005T090000Z
+DTEND:20261005T100000Z
+STATUS:CANCELLED
+END:VEVENT`, time.Time{}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := from
+			if !test.from.IsZero() {
+				start = test.from
+			}
+			got := icalTestOccurrences(t, test.events, start, to)
+			if strings.Join(got, " ") != strings.Join(test.want, " ") {
+				t.Errorf("occurrences\n got %v\nwant %v", got, test.want)
+			}
+		})
+	}
+}
+
+// TestICalUnsupported 지원하지 않는 규칙의 일정은 건너뛰고 개수만 보고
+func TestICalUnsupported(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT", "UID:a", "DTSTART:20261005T090000Z", "DTEND:20261005T100000Z", "RRULE:FREQ=HOURLY", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:b", "DTSTART:20261005T090000Z", "DTEND:20261005T100000Z", "RRULE:FREQ=MONTHLY;BYSETPOS=1;BYDAY=MO", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:c", "DTSTART:20261005T090000Z", "RDATE:20261010T090000Z", "DTEND:20261005T100000Z", "END:VEVENT",
+		"BEGIN:VEVENT", "UID:d", "DTSTART:20261005T090000Z", "END:VEVENT", // 종료 시각 없음
+		"BEGIN:VEVENT", "UID:e", "DTSTART:20261005T090000Z", "DURATION:PT1H", "END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	events, skipped, err := parseICalendar([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped != 4 || len(events) != 1 || events[0].UID != "e" {
+		t.Errorf("events=%d skipped=%d, want only e with 4 skipped", len(events), skipped)
+	}
+}
@@ -159,10 +159,10 @@ create_default_config() {
     "enabled": true,
     "smtp_server": "smtp.gmail.com",
     "smtp_port": "587",
-    "username": "enfn2001@gmail.com",
+    "username": "",
     "password": "",
-    "to": ["robot@lambda-x.ai", "enfn2001@gmail.com"],
-    "from": "enfn2001@gmail.com"
+    "to": [],
+    "from": ""
   },
   "slack": {
     "enabled": false,
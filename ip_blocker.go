@@ -0,0 +1,253 @@
+/*
+IP Blocker Module
+=================
+
+무차별 대입 공격 출발지 IP 자동 차단
+
+주요 기능:
+- 방화벽 프리셋: iptables/ip6tables, nftables (Linux), pf (macOS/BSD), ipfw (FreeBSD)
+- 사용자 정의 차단/해제 명령 ({ip} 자리표시자, 셸을 거치지 않고 실행)
+- 허용 목록 (CIDR/IP, 루프백은 항상 허용)
+- 자동 해제 타이머 (0 이면 해제하지 않음), 종료 시 타이머가 걸린 차단 해제
+
+사전 준비가 필요한 프리셋:
+  - nftables: nft add set inet filter syslog_monitor_block '{ type ipv4_addr; }'
+    nft add set inet filter syslog_monitor_block6 '{ type ipv6_addr; }'
+    nft add rule inet filter input ip saddr @syslog_monitor_block drop
+    nft add rule inet filter input ip6 saddr @syslog_monitor_block6 drop
+  - pf:       pf.conf 에 table <syslog_monitor_block> persist
+    block drop in quick from <syslog_monitor_block>
+  - ipfw:     ipfw table syslog_monitor_block create
+    ipfw add deny ip from 'table(syslog_monitor_block)' to any
+*/
+package main
+
+import (
+	"context" // 명령 실행 타임아웃
+	"fmt"     // 형식화된 I/O
+	"net"     // IP 검증
+	"os/exec" // 방화벽 명령 실행
+	"runtime" // 플랫폼별 기본 프리셋
+	"strings" // 명령 템플릿 처리
+	"sync"    // 차단 목록 보호
+	"time"    // 자동 해제 타이머
+)
+
+// IP 차단 설정
+const (
+	BlockActionAuto      = "auto"           // 플랫폼 기본 프리셋 (Linux: iptables, macOS: pf, FreeBSD: ipfw)
+	BlockActionCustom    = "custom"         // 사용자 정의 차단/해제 명령
+	DefaultBlockDuration = time.Hour        // 기본 차단 유지 시간
+	BlockCommandTimeout  = 10 * time.Second // 방화벽 명령 실행 타임아웃
+	blockIPPlaceholder   = "{ip}"           // 명령 템플릿의 IP 자리표시자
+
+	nftablesBlockSet  = "syslog_monitor_block"  // nftables 프리셋의 IPv4 주소 세트
+	nftablesBlockSet6 = "syslog_monitor_block6" // nftables 프리셋의 IPv6 주소 세트
+)
+
+// blockCommands 차단/해제 명령 템플릿
+type blockCommands struct {
+	block   string
+	unblock string
+}
+
+// BlockActionPresets 방화벽별 차단/해제 명령
+var BlockActionPresets = map[string]blockCommands{
+	"iptables": {"iptables -I INPUT -s {ip} -j DROP", "iptables -D INPUT -s {ip} -j DROP"},
+	"nftables": {"nft add element inet filter syslog_monitor_block { {ip} }", "nft delete element inet filter syslog_monitor_block { {ip} }"},
+	"pf":       {"pfctl -t syslog_monitor_block -T add {ip}", "pfctl -t syslog_monitor_block -T delete {ip}"},
+	"ipfw":     {"ipfw table syslog_monitor_block add {ip}", "ipfw table syslog_monitor_block delete {ip}"},
+}
+
+// blockedIP 차단 중인 IP
+type blockedIP struct {
+	until time.Time   // 자동 해제 시각 (zero 면 해제하지 않음)
+	timer *time.Timer // 자동 해제 타이머
+}
+
+// IPBlocker 방화벽 명령으로 IP 를 차단하고 일정 시간 뒤 해제
+type IPBlocker struct {
+	action    string
+	commands  blockCommands
+	duration  time.Duration
+	allowlist *TrustedNetworks
+	blocked   map[string]*blockedIP
+	mutex     sync.Mutex
+	logger    Logger
+}
+
+// NewIPBlocker IP 차단기 생성
+// action 은 프리셋 이름, "auto" 또는 "custom" (custom 이면 blockCommand/unblockCommand 필수)
+func NewIPBlocker(action, blockCommand, unblockCommand string, duration time.Duration, allowlist []string, logger Logger) (*IPBlocker, error) {
+	if action == BlockActionAuto {
+		switch runtime.GOOS {
+		case "linux":
+			action = "iptables"
+		case "darwin", "openbsd", "netbsd":
+			action = "pf"
+		case "freebsd":
+			action = "ipfw"
+		default:
+			return nil, fmt.Errorf("no default block action for %s (use -block-action=custom)", runtime.GOOS)
+		}
+	}
+
+	var commands blockCommands
+	if action == BlockActionCustom {
+		if !strings.Contains(blockCommand, blockIPPlaceholder) || !strings.Contains(unblockCommand, blockIPPlaceholder) {
+			return nil, fmt.Errorf("custom block and unblock commands must contain %s", blockIPPlaceholder)
+		}
+		commands = blockCommands{block: blockCommand, unblock: unblockCommand}
+	} else {
+		preset, ok := BlockActionPresets[action]
+		if !ok {
+			return nil, fmt.Errorf("unknown block action %q (expected auto, iptables, nftables, pf, ipfw or custom)", action)
+		}
+		commands = preset
+	}
+
+	allowed, err := ParseTrustedNetworks(allowlist)
+	if err != nil {
+		return nil, fmt.Errorf("invalid block allowlist: %v", err)
+	}
+	if duration < 0 {
+		duration = DefaultBlockDuration
+	}
+
+	return &IPBlocker{
+		action:    action,
+		commands:  commands,
+		duration:  duration,
+		allowlist: allowed,
+		blocked:   make(map[string]*blockedIP),
+		logger:    logger,
+	}, nil
+}
+
+// Action 사용 중인 차단 방식 이름
+func (b *IPBlocker) Action() string {
+	return b.action
+}
+
+// Command 차단 명령 실행 파일 이름 (컨테이너 이미지에 명령이 있는지 확인용)
+func (b *IPBlocker) Command() string {
+	if args := strings.Fields(b.commands.block); len(args) > 0 {
+		return args[0]
+	}
+	return ""
+}
+
+// Allowed 차단하지 않는 IP 인지 확인 (루프백 또는 허용 목록), 일치한 항목 이름 반환
+func (b *IPBlocker) Allowed(ip string) (string, bool) {
+	if parsed := net.ParseIP(ip); parsed != nil && (parsed.IsLoopback() || parsed.IsUnspecified()) {
+		return "loopback", true
+	}
+	return b.allowlist.Match(ip)
+}
+
+// Block IP 차단, 자동 해제 시각 반환 (zero 면 해제하지 않음)
+// 이미 차단 중이면 기존 해제 시각을 그대로 반환
+func (b *IPBlocker) Block(ip string) (time.Time, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return time.Time{}, fmt.Errorf("invalid IP address %q", ip)
+	}
+	if name, ok := b.Allowed(ip); ok {
+		return time.Time{}, fmt.Errorf("%s is allowlisted (%s)", ip, name)
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if existing, ok := b.blocked[ip]; ok {
+		return existing.until, nil
+	}
+	if err := b.run(b.commands.block, parsed); err != nil {
+		return time.Time{}, err
+	}
+
+	entry := &blockedIP{}
+	if b.duration > 0 {
+		entry.until = time.Now().Add(b.duration)
+		entry.timer = time.AfterFunc(b.duration, func() {
+			if err := b.Unblock(ip); err != nil {
+				b.logger.Errorf("❌ Failed to unblock %s: %v", ip, err)
+			}
+		})
+	}
+	b.blocked[ip] = entry
+	b.logger.Infof("🚫 Blocked %s via %s", ip, b.action)
+	return entry.until, nil
+}
+
+// Unblock IP 차단 해제
+func (b *IPBlocker) Unblock(ip string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	entry, ok := b.blocked[ip]
+	if !ok {
+		return nil
+	}
+	if entry.timer != nil {
+		entry.timer.Stop()
+	}
+	delete(b.blocked, ip)
+	if err := b.run(b.commands.unblock, net.ParseIP(ip)); err != nil {
+		return err
+	}
+	b.logger.Infof("✅ Unblocked %s", ip)
+	return nil
+}
+
+// Blocked 차단 중인 IP 와 해제 시각
+func (b *IPBlocker) Blocked() map[string]time.Time {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	blocked := make(map[string]time.Time, len(b.blocked))
+	for ip, entry := range b.blocked {
+		blocked[ip] = entry.until
+	}
+	return blocked
+}
+
+// Close 자동 해제 예정인 차단을 모두 해제 (해제하지 않도록 설정한 차단은 유지)
+func (b *IPBlocker) Close() {
+	for ip, until := range b.Blocked() {
+		if until.IsZero() {
+			continue
+		}
+		if err := b.Unblock(ip); err != nil {
+			b.logger.Errorf("❌ Failed to unblock %s: %v", ip, err)
+		}
+	}
+}
+
+// run 명령 템플릿의 {ip} 를 치환해 실행 (셸을 거치지 않으므로 검증된 IP 만 인자로 전달됨)
+func (b *IPBlocker) run(template string, ip net.IP) error {
+	args := strings.Fields(strings.ReplaceAll(template, blockIPPlaceholder, ip.String()))
+	if len(args) == 0 {
+		return fmt.Errorf("empty block command")
+	}
+	// iptables 프리셋은 IPv6 주소에 ip6tables, nftables 프리셋은 IPv6 세트 사용
+	if ip.To4() == nil {
+		switch b.action {
+		case "iptables":
+			args[0] = "ip6tables"
+		case "nftables":
+			for i, arg := range args {
+				if arg == nftablesBlockSet {
+					args[i] = nftablesBlockSet6
+				}
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), BlockCommandTimeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %v (%s)", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+/*
+Journald Input Module
+=====================
+
+systemd-journald 로그 입력 지원 (Linux)
+
+/var/log/syslog 가 없는 최신 배포판을 위해 `journalctl -f -o json` 하위 프로세스로
+저널을 실시간 구독하고, 각 엔트리를 기존 처리 파이프라인에 전달합니다.
+
+주요 기능:
+- journalctl JSON 출력 스트리밍 파싱
+- 저널 필드 매핑: PRIORITY → 로그 레벨, _SYSTEMD_UNIT → 서비스, _HOSTNAME → 호스트
+- 기존 정규식 기반 감지기와 호환되도록 syslog 형식 라인 재구성
+- 유닛 단위 필터링 (journalctl -u)
+*/
+package main
+
+import (
+	"bufio"         // 스트림 라인 읽기
+	"encoding/json" // 저널 JSON 파싱
+	"fmt"           // 형식화된 I/O
+	"os/exec"       // journalctl 실행
+	"strconv"       // 문자열-숫자 변환
+	"time"          // 시간 처리
+)
+
+// journalMaxLineSize 저널 엔트리 JSON 한 줄 최대 크기 (긴 스택 트레이스 대비)
+const journalMaxLineSize = 1024 * 1024
+
+// JournalEntry 기존 파이프라인에 전달되는 저널 엔트리
+type JournalEntry struct {
+	Line   string     // syslog 형식으로 재구성한 라인
+	Parsed *ParsedLog // 저널 필드가 매핑된 파싱 결과
+	Source string     // 소스 이름 (비어 있으면 Parsed 의 유닛/공급자/워크로드, 네트워크 수신은 syslog:<host>)
+}
+
+// JournaldReader journalctl 하위 프로세스 기반 저널 리더
+type JournaldReader struct {
+	cmd     *exec.Cmd
+	entries chan JournalEntry
+	logger  Logger
+}
+
+// NewJournaldReader journalctl 프로세스를 시작하고 저널 구독
+// units가 지정되면 해당 systemd 유닛의 로그만 수신
+func NewJournaldReader(units []string, logger Logger) (*JournaldReader, error) {
+	args := []string{"-f", "-o", "json", "-n", "0", "--no-pager"}
+	for _, unit := range units {
+		args = append(args, "-u", unit)
+	}
+
+	cmd := exec.Command("journalctl", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journalctl output: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start journalctl: %v", err)
+	}
+
+	reader := &JournaldReader{
+		cmd:     cmd,
+		entries: make(chan JournalEntry, 100),
+		logger:  logger,
+	}
+
+	go func() {
+		defer close(reader.entries)
+
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), journalMaxLineSize)
+		for scanner.Scan() {
+			entry, err := parseJournalJSON(scanner.Bytes())
+			if err != nil {
+				logger.Errorf("Failed to parse journal entry: %v", err)
+				continue
+			}
+			reader.entries <- entry
+		}
+		if err := scanner.Err(); err != nil {
+			logger.Errorf("Journal stream error: %v", err)
+		}
+		if err := cmd.Wait(); err != nil {
+			logger.Errorf("journalctl exited: %v", err)
+		}
+	}()
+
+	return reader, nil
+}
+
+// Entries 저널 엔트리 채널 (journalctl 종료 시 닫힘)
+func (jr *JournaldReader) Entries() <-chan JournalEntry {
+	return jr.entries
+}
+
+// Stop journalctl 프로세스 종료
+func (jr *JournaldReader) Stop() {
+	if jr.cmd.Process != nil {
+		jr.cmd.Process.Kill()
+	}
+}
+
+// parseJournalJSON journalctl -o json 한 줄을 JournalEntry로 변환
+func parseJournalJSON(data []byte) (JournalEntry, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return JournalEntry{}, err
+	}
+
+	field := func(name string) string {
+		value, ok := raw[name]
+		if !ok {
+			return ""
+		}
+		// 일반 필드는 문자열
+		var s string
+		if err := json.Unmarshal(value, &s); err == nil {
+			return s
+		}
+		// 출력 불가능한 바이트가 포함된 필드는 바이트 배열로 인코딩됨
+		var b []byte
+		var ints []int
+		if err := json.Unmarshal(value, &ints); err == nil {
+			for _, i := range ints {
+				b = append(b, byte(i))
+			}
+			return string(b)
+		}
+		return ""
+	}
+
+	timestamp := time.Now()
+	if usec, err := strconv.ParseInt(field("__REALTIME_TIMESTAMP"), 10, 64); err == nil {
+		timestamp = time.Unix(0, usec*int64(time.Microsecond))
+	}
+
+	hostname := field("_HOSTNAME")
+	unit := field("_SYSTEMD_UNIT")
+	identifier := field("SYSLOG_IDENTIFIER")
+	if identifier == "" {
+		identifier = field("_COMM")
+	}
+	if identifier == "" {
+		identifier = unit
+	}
+	pid := field("_PID")
+	message := field("MESSAGE")
+	priority := field("PRIORITY")
+
+	// 기존 감지기(로그인, 부팅, 에러 키워드)가 그대로 동작하도록 syslog 형식 재구성
+	service := identifier
+	if pid != "" {
+		service = fmt.Sprintf("%s[%s]", identifier, pid)
+	}
+	line := fmt.Sprintf("%s %s %s: %s", timestamp.Format(time.Stamp), hostname, service, message)
+
+	parsed := &ParsedLog{
+		Timestamp: timestamp,
+		LogType:   "journald",
+		Level:     journalPriorityToLevel(priority),
+		Source:    identifier,
+		Message:   message,
+		RawLog:    line,
+		Fields: map[string]string{
+			"priority":   priority,
+			"unit":       unit,
+			"hostname":   hostname,
+			"identifier": identifier,
+			"pid":        pid,
+			"boot_id":    field("_BOOT_ID"),
+			"transport":  field("_TRANSPORT"),
+		},
+	}
+
+	return JournalEntry{Line: line, Parsed: parsed}, nil
+}
+
+// journalPriorityToLevel syslog PRIORITY(0-7)를 로그 레벨로 변환
+func journalPriorityToLevel(priority string) string {
+	switch priority {
+	case "0", "1", "2": // emerg, alert, crit
+		return LogLevelCritical
+	case "3": // err
+		return LogLevelError
+	case "4": // warning
+		return LogLevelWarning
+	case "7": // debug
+		return LogLevelDebug
+	default: // notice, info
+		return LogLevelInfo
+	}
+}
@@ -0,0 +1,908 @@
+/*
+Kafka Output Module
+===================
+
+# Kafka 토픽으로 파싱된 로그 또는 알림을 JSON 메시지로 발행
+
+외부 클라이언트 라이브러리 없이 Kafka 와이어 프로토콜(Metadata v4, Produce v3, RecordBatch v2)을
+직접 구현한 최소 프로듀서입니다. 기존 스트리밍 파이프라인(Kafka Connect, Flink, ksqlDB 등)에서
+syslog-monitor 의 파싱 결과를 그대로 소비할 수 있게 합니다.
+
+주요 기능:
+- 모든 ParsedLog 발행 (기본) 또는 알림만 발행 (-kafka-alerts-only)
+- 호스트 기준 파티셔닝: 메시지 키 = 호스트, Java 클라이언트와 같은 murmur2 해시로 파티션 선택 (같은 호스트의 로그는 순서 보장)
+- 키 없는 round-robin 파티셔닝 선택 가능
+- 백그라운드 배치 전송 (최대 500건/512KB 또는 1초마다, 파티션 리더 브로커별 Produce 요청 1회)
+- 리더 변경/연결 실패 등 재시도 가능한 오류는 메타데이터를 갱신하고 지수 백오프로 재시도 (최대 5회)
+- acks=1 (리더 기록 확인), 압축 없음, 평문 연결 (SASL/TLS 미지원)
+- -output-buffer 지정 시 재시도 후에도 보내지 못한 메시지는 디스크에 버퍼링했다가 복구 후 재전송 (output_buffer.go)
+
+설정 방법:
+- -kafka-brokers=kafka1:9092,kafka2:9092 -kafka-topic=syslog-monitor
+- -kafka-partition-by=host|round-robin, -kafka-alerts-only
+*/
+package main
+
+import (
+	"bytes"           // 요청 버퍼
+	"encoding/binary" // 빅엔디언/가변 길이 정수 인코딩
+	"encoding/json"   // 메시지 JSON 인코딩
+	"fmt"             // 형식화된 I/O
+	"hash/crc32"      // RecordBatch CRC-32C
+	"io"              // 응답 읽기
+	"net"             // 브로커 TCP 연결
+	"os"              // 호스트명 조회
+	"regexp"          // 토픽 이름 검증
+	"sort"            // 파티션 정렬
+	"strings"         // 문자열 처리
+	"sync"            // 동기화
+	"sync/atomic"     // 버린 메시지 수 집계
+	"time"            // 시간 처리
+)
+
+// Kafka 출력 설정
+const (
+	DefaultKafkaTopic        = "syslog-monitor" // 기본 토픽
+	KafkaPartitionHost       = "host"           // 호스트 키 해시로 파티션 선택
+	KafkaPartitionRoundRobin = "round-robin"    // 키 없이 파티션 순환
+	KafkaBatchSize           = 500              // 한 번에 전송할 최대 메시지 수
+	KafkaMaxBatchBytes       = 512 * 1024       // 한 번에 전송할 최대 메시지 바이트 (브로커 기본 message.max.bytes 1MB 미만)
+	KafkaFlushInterval       = 1 * time.Second  // 배치 전송 간격
+	KafkaQueueSize           = 10000            // 전송 대기 큐 크기 (가득 차면 메시지 버림)
+	KafkaMaxRetries          = 5                // 전송 최대 재시도 횟수
+	KafkaInitialBackoff      = 1 * time.Second  // 첫 재시도 대기 시간 (재시도마다 2배)
+	KafkaMaxBackoff          = 30 * time.Second // 재시도 대기 시간 상한
+
+	kafkaClientID        = "syslog-monitor"
+	kafkaDialTimeout     = 10 * time.Second
+	kafkaRequestTimeout  = 30 * time.Second
+	kafkaMetadataRefresh = 5 * time.Minute // 오류가 없어도 파티션 리더 정보를 다시 조회하는 간격
+)
+
+// Kafka API 키/버전 및 오류 코드
+const (
+	kafkaAPIProduce         = 0
+	kafkaAPIMetadata        = 3
+	kafkaProduceVersion     = 3 // RecordBatch v2 를 사용하는 가장 낮은 버전 (Kafka 0.11 ~ 4.x 지원)
+	kafkaMetadataVersion    = 4 // allow_auto_topic_creation 지원
+	kafkaAcksLeader         = 1
+	kafkaErrMessageTooLarge = 10
+)
+
+// kafkaRetriableErrors 메타데이터 갱신 후 재시도하면 성공할 수 있는 오류 코드
+var kafkaRetriableErrors = map[int16]string{
+	3:  "UNKNOWN_TOPIC_OR_PARTITION",
+	5:  "LEADER_NOT_AVAILABLE",
+	6:  "NOT_LEADER_OR_FOLLOWER",
+	7:  "REQUEST_TIMED_OUT",
+	13: "NETWORK_EXCEPTION",
+	19: "NOT_ENOUGH_REPLICAS",
+	20: "NOT_ENOUGH_REPLICAS_AFTER_APPEND",
+}
+
+// kafkaTopicRegex 허용되는 토픽 이름 (영숫자, '.', '_', '-', 최대 249자)
+var kafkaTopicRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+
+// kafkaCRCTable RecordBatch 체크섬용 CRC-32C (Castagnoli) 테이블
+var kafkaCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+// kafkaMessage 전송 대기 중인 메시지
+type kafkaMessage struct {
+	key       []byte // 파티션 키 (round-robin 이면 nil)
+	value     []byte
+	timestamp time.Time
+}
+
+// kafkaBufferedMessage 디스크 버퍼에 기록하는 메시지
+type kafkaBufferedMessage struct {
+	Key       []byte          `json:"key,omitempty"`
+	Value     json.RawMessage `json:"value"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// kafkaLogMessage ParsedLog 메시지 본문
+type kafkaLogMessage struct {
+	EventTimestamp time.Time `json:"@timestamp"`
+	Host           string    `json:"host,omitempty"`
+	*ParsedLog
+}
+
+// kafkaPartition 토픽 파티션과 리더 브로커
+type kafkaPartition struct {
+	id     int32
+	leader int32
+}
+
+// KafkaOutput Kafka 프로듀서 출력
+type KafkaOutput struct {
+	brokers     []string // 부트스트랩 브로커 (host:port)
+	topic       string
+	partitionBy string
+	alertsOnly  bool
+	logger      Logger
+	pending     chan kafkaMessage
+	dropped     int64 // 큐가 가득 차 버린 메시지 수 (다음 전송 시 보고)
+	done        chan struct{}
+	wg          sync.WaitGroup
+	closed      sync.Once
+	buffer      *OutputBuffer // 장애 시 디스크 버퍼 (-output-buffer 미지정 시 nil)
+
+	// 아래 필드는 writeLoop 고루틴에서만 사용
+	partitions    []kafkaPartition
+	brokerAddrs   map[int32]string     // 브로커 ID → host:port
+	conns         map[int32]*kafkaConn // 리더 브로커 연결
+	metadataAt    time.Time            // 마지막 메타데이터 조회 시각 (0 이면 다시 조회)
+	roundRobin    int
+	correlationID int32
+}
+
+// NewKafkaOutput 새로운 Kafka 출력 생성 (브로커 연결은 첫 전송 시)
+func NewKafkaOutput(brokers []string, topic, partitionBy string, alertsOnly bool, logger Logger) (*KafkaOutput, error) {
+	var addrs []string
+	for _, broker := range brokers {
+		broker = strings.TrimSpace(broker)
+		if broker == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(broker); err != nil {
+			return nil, fmt.Errorf("invalid kafka broker %q: expected host:port", broker)
+		}
+		addrs = append(addrs, broker)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no kafka brokers given")
+	}
+	if topic == "" {
+		topic = DefaultKafkaTopic
+	}
+	if !kafkaTopicRegex.MatchString(topic) {
+		return nil, fmt.Errorf("invalid kafka topic %q: use letters, digits, '.', '_' or '-'", topic)
+	}
+	switch partitionBy {
+	case "":
+		partitionBy = KafkaPartitionHost
+	case KafkaPartitionHost, KafkaPartitionRoundRobin:
+	default:
+		return nil, fmt.Errorf("unknown kafka partitioning %q (use %s or %s)", partitionBy, KafkaPartitionHost, KafkaPartitionRoundRobin)
+	}
+
+	return &KafkaOutput{
+		brokers:     addrs,
+		topic:       topic,
+		partitionBy: partitionBy,
+		alertsOnly:  alertsOnly,
+		logger:      logger,
+		pending:     make(chan kafkaMessage, KafkaQueueSize),
+		done:        make(chan struct{}),
+		brokerAddrs: make(map[int32]string),
+		conns:       make(map[int32]*kafkaConn),
+	}, nil
+}
+
+// Start 백그라운드 배치 전송 시작
+func (ko *KafkaOutput) Start() {
+	ko.wg.Add(1)
+	go ko.writeLoop()
+}
+
+// SetBuffer 재시도 후에도 보내지 못한 메시지를 기록할 디스크 버퍼 설정 (Start 전에 호출)
+func (ko *KafkaOutput) SetBuffer(buffer *OutputBuffer) {
+	ko.buffer = buffer
+}
+
+// Buffer 디스크 버퍼 (설정하지 않았으면 nil)
+func (ko *KafkaOutput) Buffer() *OutputBuffer {
+	return ko.buffer
+}
+
+// Close 대기 중인 메시지를 전송하고 종료 (종료 중에는 재시도하지 않음, 보내지 못한 메시지는 버퍼에 남김)
+func (ko *KafkaOutput) Close() {
+	ko.closed.Do(func() {
+		close(ko.done)
+		ko.wg.Wait()
+		if ko.buffer != nil {
+			ko.buffer.Close()
+		}
+	})
+}
+
+// PublishParsedLog 파싱된 로그 발행 (알림만 발행하는 모드에서는 무시)
+func (ko *KafkaOutput) PublishParsedLog(parsedLog *ParsedLog, host string) {
+	if parsedLog == nil || ko.alertsOnly {
+		return
+	}
+	timestamp := parsedLog.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	ko.enqueue(host, timestamp, kafkaLogMessage{
+		EventTimestamp: timestamp,
+		Host:           host,
+		ParsedLog:      parsedLog,
+	})
+}
+
+// Send 알림 발행 (AlertSink, 웹훅과 같은 JSON 본문)
+func (ko *KafkaOutput) Send(alert Alert) error {
+	alert.Body = alert.RenderText(alert.Detail)
+	ko.enqueue(alert.Host, alert.Timestamp, webhookPayload{
+		Source:  AppName,
+		Version: AppVersion,
+		Alert:   alert,
+	})
+	return nil
+}
+
+// enqueue 메시지를 직렬화하여 전송 대기 큐에 추가 (큐가 가득 차면 버림)
+func (ko *KafkaOutput) enqueue(host string, timestamp time.Time, doc interface{}) {
+	value, err := json.Marshal(doc)
+	if err != nil {
+		ko.logger.Errorf("Failed to encode kafka message: %v", err)
+		return
+	}
+
+	message := kafkaMessage{value: value, timestamp: timestamp}
+	if ko.partitionBy == KafkaPartitionHost {
+		if host == "" {
+			host, _ = os.Hostname()
+		}
+		message.key = []byte(host)
+	}
+
+	select {
+	case ko.pending <- message:
+	default:
+		atomic.AddInt64(&ko.dropped, 1)
+	}
+}
+
+// writeLoop 배치 크기 또는 전송 간격마다 대기 중인 메시지를 전송
+func (ko *KafkaOutput) writeLoop() {
+	defer ko.wg.Done()
+	defer ko.closeConns()
+
+	ticker := time.NewTicker(KafkaFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]kafkaMessage, 0, KafkaBatchSize)
+	batchBytes := 0
+	flush := func() {
+		if dropped := atomic.SwapInt64(&ko.dropped, 0); dropped > 0 {
+			ko.logger.Errorf("⚠️  Kafka queue full, dropped %d messages", dropped)
+		}
+		if len(batch) > 0 {
+			if ko.buffer != nil && ko.buffer.Len() > 0 {
+				// 버퍼에 밀린 메시지가 있으면 순서를 지키기 위해 뒤에 추가
+				ko.spill(batch)
+			} else {
+				ko.sendWithRetry(batch)
+			}
+			batch = make([]kafkaMessage, 0, KafkaBatchSize)
+			batchBytes = 0
+		}
+		ko.drainBuffer()
+	}
+	add := func(message kafkaMessage) {
+		batch = append(batch, message)
+		batchBytes += len(message.key) + len(message.value)
+		if len(batch) >= KafkaBatchSize || batchBytes >= KafkaMaxBatchBytes {
+			flush()
+		}
+	}
+
+	for {
+		select {
+		case message := <-ko.pending:
+			add(message)
+		case <-ticker.C:
+			flush()
+		case <-ko.done:
+			for {
+				select {
+				case message := <-ko.pending:
+					add(message)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// sendWithRetry 배치 전송 후 재시도 가능한 실패 메시지만 메타데이터를 갱신하여 지수 백오프로 재전송
+func (ko *KafkaOutput) sendWithRetry(messages []kafkaMessage) {
+	backoff := KafkaInitialBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := ko.produce(messages)
+		if err != nil {
+			ko.logger.Errorf("❌ Kafka produce failed (attempt %d/%d): %v", attempt+1, KafkaMaxRetries+1, err)
+		}
+		if len(retry) == 0 {
+			return
+		}
+		// 리더 변경, 연결 끊김 등은 메타데이터를 다시 조회해야 해결됨
+		ko.metadataAt = time.Time{}
+		if attempt >= KafkaMaxRetries {
+			if ko.buffer != nil {
+				ko.spill(retry)
+				return
+			}
+			ko.logger.Errorf("❌ Dropping %d messages after %d kafka retries", len(retry), KafkaMaxRetries)
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ko.done:
+			if ko.buffer != nil {
+				ko.spill(retry)
+				return
+			}
+			ko.logger.Errorf("❌ Dropping %d messages not sent to kafka before shutdown", len(retry))
+			return
+		}
+		messages = retry
+		if backoff *= 2; backoff > KafkaMaxBackoff {
+			backoff = KafkaMaxBackoff
+		}
+	}
+}
+
+// spill 보내지 못한 메시지를 디스크 버퍼에 기록
+func (ko *KafkaOutput) spill(messages []kafkaMessage) {
+	ko.buffer.Append(encodeKafkaBufferRecords(messages))
+}
+
+// encodeKafkaBufferRecords 메시지를 디스크 버퍼 레코드(JSON)로 변환
+func encodeKafkaBufferRecords(messages []kafkaMessage) [][]byte {
+	records := make([][]byte, 0, len(messages))
+	for _, message := range messages {
+		record, err := json.Marshal(kafkaBufferedMessage{Key: message.key, Value: message.value, Timestamp: message.timestamp})
+		if err == nil {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// drainBuffer 버퍼에 밀린 메시지를 재전송 (종료 중에는 다음 시작 때로 미룸)
+func (ko *KafkaOutput) drainBuffer() {
+	if ko.buffer == nil {
+		return
+	}
+	select {
+	case <-ko.done:
+		return
+	default:
+	}
+	ko.buffer.Drain(KafkaBatchSize, KafkaMaxBatchBytes, KafkaFlushInterval, func(records [][]byte) ([][]byte, error) {
+		messages := make([]kafkaMessage, 0, len(records))
+		for _, record := range records {
+			var buffered kafkaBufferedMessage
+			if err := json.Unmarshal(record, &buffered); err == nil {
+				messages = append(messages, kafkaMessage{key: buffered.Key, value: buffered.Value, timestamp: buffered.Timestamp})
+			}
+		}
+		retry, err := ko.produce(messages)
+		if len(retry) > 0 {
+			ko.metadataAt = time.Time{}
+		}
+		if len(messages) > 0 && len(retry) == len(messages) {
+			return records, err
+		}
+		return encodeKafkaBufferRecords(retry), err
+	})
+}
+
+// produce 메시지를 파티션 리더 브로커별로 묶어 Produce 요청 1회씩 전송, 재시도할 메시지 목록 반환
+func (ko *KafkaOutput) produce(messages []kafkaMessage) ([]kafkaMessage, error) {
+	if ko.metadataAt.IsZero() || time.Since(ko.metadataAt) > kafkaMetadataRefresh {
+		if err := ko.refreshMetadata(); err != nil {
+			return messages, err
+		}
+	}
+
+	// 리더 브로커 → 파티션 → 메시지 (리더가 없는 파티션의 메시지는 재시도)
+	var retry []kafkaMessage
+	var errs []string
+	byLeader := make(map[int32]map[int32][]kafkaMessage)
+	for _, message := range messages {
+		partition := ko.partitionFor(message.key)
+		if partition.leader < 0 {
+			retry = append(retry, message)
+			continue
+		}
+		if byLeader[partition.leader] == nil {
+			byLeader[partition.leader] = make(map[int32][]kafkaMessage)
+		}
+		byLeader[partition.leader][partition.id] = append(byLeader[partition.leader][partition.id], message)
+	}
+	if len(retry) > 0 {
+		errs = append(errs, fmt.Sprintf("%d messages for partitions without a leader", len(retry)))
+	}
+
+	for leader, partitions := range byLeader {
+		failed, err := ko.produceToBroker(leader, partitions)
+		retry = append(retry, failed...)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return retry, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return retry, nil
+}
+
+// produceToBroker 한 리더 브로커에 Produce 요청 전송
+// 연결 오류는 전체 재시도, 파티션별 재시도 가능 오류는 해당 파티션만 재시도, 그 외 오류는 버림
+func (ko *KafkaOutput) produceToBroker(leader int32, partitions map[int32][]kafkaMessage) ([]kafkaMessage, error) {
+	var all []kafkaMessage
+	for _, messages := range partitions {
+		all = append(all, messages...)
+	}
+
+	conn, err := ko.connFor(leader)
+	if err != nil {
+		return all, err
+	}
+
+	var body kafkaEncoder
+	body.putInt16(-1) // transactional_id (null)
+	body.putInt16(kafkaAcksLeader)
+	body.putInt32(int32(kafkaRequestTimeout / time.Millisecond))
+	body.putInt32(1) // 토픽 수
+	body.putString(ko.topic)
+	body.putInt32(int32(len(partitions)))
+	for id, messages := range partitions {
+		body.putInt32(id)
+		body.putBytes(encodeKafkaRecordBatch(messages))
+	}
+
+	response, err := ko.roundTrip(conn, kafkaAPIProduce, kafkaProduceVersion, body.Bytes())
+	if err != nil {
+		ko.dropConn(leader)
+		return all, fmt.Errorf("broker %s: %v", ko.brokerAddrs[leader], err)
+	}
+
+	// 응답: [topic [partition error_code base_offset log_append_time]] throttle_time
+	decoder := kafkaDecoder{data: response}
+	var retry []kafkaMessage
+	var errs []string
+	for topics := decoder.int32(); topics > 0 && decoder.err == nil; topics-- {
+		decoder.string()
+		for count := decoder.int32(); count > 0 && decoder.err == nil; count-- {
+			id := decoder.int32()
+			code := decoder.int16()
+			decoder.int64()
+			decoder.int64()
+			if code == 0 {
+				continue
+			}
+			if name, ok := kafkaRetriableErrors[code]; ok {
+				retry = append(retry, partitions[id]...)
+				errs = append(errs, fmt.Sprintf("partition %d: %s", id, name))
+				continue
+			}
+			reason := fmt.Sprintf("error code %d", code)
+			if code == kafkaErrMessageTooLarge {
+				reason = "MESSAGE_TOO_LARGE"
+			}
+			errs = append(errs, fmt.Sprintf("partition %d: %s, dropped %d messages", id, reason, len(partitions[id])))
+		}
+	}
+	if decoder.err != nil {
+		return nil, fmt.Errorf("failed to parse produce response: %v", decoder.err)
+	}
+	if len(errs) > 0 {
+		return retry, fmt.Errorf("%s", strings.Join(errs, ", "))
+	}
+	return retry, nil
+}
+
+// partitionFor 메시지 키로 파티션 선택
+// 키가 있으면 전체 파티션 수 기준 murmur2 해시 (리더가 없어도 같은 파티션 유지), 없으면 리더가 있는 파티션 순환
+func (ko *KafkaOutput) partitionFor(key []byte) kafkaPartition {
+	if key == nil {
+		for range ko.partitions {
+			ko.roundRobin = (ko.roundRobin + 1) % len(ko.partitions)
+			if ko.partitions[ko.roundRobin].leader >= 0 {
+				break
+			}
+		}
+		return ko.partitions[ko.roundRobin]
+	}
+	return ko.partitions[int(kafkaMurmur2(key)&0x7fffffff)%len(ko.partitions)]
+}
+
+// refreshMetadata 부트스트랩/알려진 브로커 중 응답하는 곳에서 토픽의 파티션 리더 조회
+// 토픽이 없으면 브로커 설정(auto.create.topics.enable)에 따라 자동 생성 요청
+func (ko *KafkaOutput) refreshMetadata() error {
+	var body kafkaEncoder
+	body.putInt32(1) // 토픽 수
+	body.putString(ko.topic)
+	body.putInt8(1) // allow_auto_topic_creation
+
+	candidates := append([]string(nil), ko.brokers...)
+	for _, addr := range ko.brokerAddrs {
+		candidates = append(candidates, addr)
+	}
+
+	var lastErr error
+	for _, addr := range candidates {
+		conn, err := dialKafka(addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		response, err := ko.roundTrip(conn, kafkaAPIMetadata, kafkaMetadataVersion, body.Bytes())
+		conn.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("broker %s: %v", addr, err)
+			continue
+		}
+		if err := ko.applyMetadata(response); err != nil {
+			return err
+		}
+		ko.metadataAt = time.Now()
+		return nil
+	}
+	return fmt.Errorf("failed to fetch kafka metadata: %v", lastErr)
+}
+
+// applyMetadata Metadata v4 응답에서 브로커 주소와 토픽 파티션 리더 반영
+func (ko *KafkaOutput) applyMetadata(response []byte) error {
+	decoder := kafkaDecoder{data: response}
+	decoder.int32() // throttle_time_ms
+
+	brokerAddrs := make(map[int32]string)
+	for count := decoder.int32(); count > 0 && decoder.err == nil; count-- {
+		id := decoder.int32()
+		host := decoder.string()
+		port := decoder.int32()
+		decoder.nullableString() // rack
+		brokerAddrs[id] = net.JoinHostPort(host, fmt.Sprintf("%d", port))
+	}
+	decoder.nullableString() // cluster_id
+	decoder.int32()          // controller_id
+
+	var partitions []kafkaPartition
+	available := 0
+	topicError := int16(0)
+	for count := decoder.int32(); count > 0 && decoder.err == nil; count-- {
+		code := decoder.int16()
+		name := decoder.string()
+		decoder.int8() // is_internal
+		for partitionCount := decoder.int32(); partitionCount > 0 && decoder.err == nil; partitionCount-- {
+			decoder.int16() // 파티션 오류 (리더가 없으면 leader_id 가 -1)
+			id := decoder.int32()
+			leader := decoder.int32()
+			decoder.int32Array() // replica_nodes
+			decoder.int32Array() // isr_nodes
+			if name == ko.topic {
+				partitions = append(partitions, kafkaPartition{id: id, leader: leader})
+				if leader >= 0 {
+					available++
+				}
+			}
+		}
+		if name == ko.topic {
+			topicError = code
+		}
+	}
+	if decoder.err != nil {
+		return fmt.Errorf("failed to parse metadata response: %v", decoder.err)
+	}
+	if available == 0 {
+		if name, ok := kafkaRetriableErrors[topicError]; ok {
+			return fmt.Errorf("topic %s is not available yet (%s)", ko.topic, name)
+		}
+		return fmt.Errorf("topic %s has no available partitions (error code %d)", ko.topic, topicError)
+	}
+
+	// 파티션 번호 순으로 정렬해야 키 해시 → 파티션 대응이 다른 클라이언트와 같음
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i].id < partitions[j].id })
+
+	// 주소가 바뀐 브로커 연결은 다시 맺음
+	for id, addr := range brokerAddrs {
+		if ko.brokerAddrs[id] != addr {
+			ko.dropConn(id)
+		}
+	}
+	ko.brokerAddrs = brokerAddrs
+	ko.partitions = partitions
+	return nil
+}
+
+// connFor 리더 브로커 연결 (없으면 새로 연결)
+func (ko *KafkaOutput) connFor(broker int32) (*kafkaConn, error) {
+	if conn, ok := ko.conns[broker]; ok {
+		return conn, nil
+	}
+	addr, ok := ko.brokerAddrs[broker]
+	if !ok {
+		return nil, fmt.Errorf("unknown kafka broker id %d", broker)
+	}
+	conn, err := dialKafka(addr)
+	if err != nil {
+		return nil, err
+	}
+	ko.conns[broker] = conn
+	return conn, nil
+}
+
+// dropConn 브로커 연결 닫기 (다음 전송 시 다시 연결)
+func (ko *KafkaOutput) dropConn(broker int32) {
+	if conn, ok := ko.conns[broker]; ok {
+		conn.Close()
+		delete(ko.conns, broker)
+	}
+}
+
+// closeConns 모든 브로커 연결 닫기
+func (ko *KafkaOutput) closeConns() {
+	for broker := range ko.conns {
+		ko.dropConn(broker)
+	}
+}
+
+// roundTrip 요청 헤더(v1)를 붙여 전송하고 같은 correlation ID 의 응답 본문 반환
+func (ko *KafkaOutput) roundTrip(conn *kafkaConn, apiKey, apiVersion int16, body []byte) ([]byte, error) {
+	ko.correlationID++
+	var request kafkaEncoder
+	request.putInt16(apiKey)
+	request.putInt16(apiVersion)
+	request.putInt32(ko.correlationID)
+	request.putString(kafkaClientID)
+	request.buf.Write(body)
+
+	response, err := conn.roundTrip(request.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if len(response) < 4 {
+		return nil, fmt.Errorf("short response from broker")
+	}
+	if id := int32(binary.BigEndian.Uint32(response)); id != ko.correlationID {
+		return nil, fmt.Errorf("unexpected correlation id %d (expected %d)", id, ko.correlationID)
+	}
+	return response[4:], nil
+}
+
+// kafkaConn 브로커 TCP 연결
+type kafkaConn struct {
+	conn net.Conn
+}
+
+// dialKafka 브로커에 TCP 연결
+func dialKafka(addr string) (*kafkaConn, error) {
+	conn, err := net.DialTimeout("tcp", addr, kafkaDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to kafka broker %s: %v", addr, err)
+	}
+	return &kafkaConn{conn: conn}, nil
+}
+
+// roundTrip 길이 접두사를 붙여 요청을 쓰고 응답 하나를 읽음
+func (kc *kafkaConn) roundTrip(request []byte) ([]byte, error) {
+	kc.conn.SetDeadline(time.Now().Add(kafkaRequestTimeout + kafkaDialTimeout))
+
+	frame := make([]byte, 4+len(request))
+	binary.BigEndian.PutUint32(frame, uint32(len(request)))
+	copy(frame[4:], request)
+	if _, err := kc.conn.Write(frame); err != nil {
+		return nil, err
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(kc.conn, size[:]); err != nil {
+		return nil, err
+	}
+	response := make([]byte, binary.BigEndian.Uint32(size[:]))
+	if _, err := io.ReadFull(kc.conn, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// Close 연결 닫기
+func (kc *kafkaConn) Close() {
+	kc.conn.Close()
+}
+
+// encodeKafkaRecordBatch 메시지를 압축 없는 RecordBatch(magic 2) 하나로 인코딩
+func encodeKafkaRecordBatch(messages []kafkaMessage) []byte {
+	baseTimestamp := messages[0].timestamp.UnixMilli()
+	maxTimestamp := baseTimestamp
+
+	var records kafkaEncoder
+	for i, message := range messages {
+		timestamp := message.timestamp.UnixMilli()
+		if timestamp > maxTimestamp {
+			maxTimestamp = timestamp
+		}
+
+		var record kafkaEncoder
+		record.putInt8(0) // attributes
+		record.putVarint(timestamp - baseTimestamp)
+		record.putVarint(int64(i)) // offset delta
+		if message.key == nil {
+			record.putVarint(-1)
+		} else {
+			record.putVarint(int64(len(message.key)))
+			record.buf.Write(message.key)
+		}
+		record.putVarint(int64(len(message.value)))
+		record.buf.Write(message.value)
+		record.putVarint(0) // headers
+
+		records.putVarint(int64(record.buf.Len()))
+		records.buf.Write(record.Bytes())
+	}
+
+	// CRC 는 attributes 부터 끝까지 계산
+	var tail kafkaEncoder
+	tail.putInt16(0) // attributes (압축 없음, CreateTime)
+	tail.putInt32(int32(len(messages) - 1))
+	tail.putInt64(baseTimestamp)
+	tail.putInt64(maxTimestamp)
+	tail.putInt64(-1) // producer_id
+	tail.putInt16(-1) // producer_epoch
+	tail.putInt32(-1) // base_sequence
+	tail.putInt32(int32(len(messages)))
+	tail.buf.Write(records.Bytes())
+
+	var batch kafkaEncoder
+	batch.putInt64(0)                                 // base_offset (브로커가 할당)
+	batch.putInt32(int32(4 + 1 + 4 + tail.buf.Len())) // batch_length: partition_leader_epoch 이후 길이
+	batch.putInt32(-1)                                // partition_leader_epoch
+	batch.putInt8(2)                                  // magic
+	batch.putInt32(int32(crc32.Checksum(tail.Bytes(), kafkaCRCTable)))
+	batch.buf.Write(tail.Bytes())
+	return batch.Bytes()
+}
+
+// kafkaMurmur2 Kafka Java 클라이언트 기본 파티셔너와 같은 murmur2 해시
+func kafkaMurmur2(data []byte) int32 {
+	const (
+		seed = 0x9747b28c
+		m    = 0x5bd1e995
+		r    = 24
+	)
+	length := len(data)
+	h := uint32(seed) ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := length &^ 3
+	switch length % 4 {
+	case 3:
+		h ^= uint32(data[tail+2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(data[tail+1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(data[tail])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return int32(h)
+}
+
+// kafkaEncoder Kafka 프로토콜 빅엔디언 인코더
+type kafkaEncoder struct {
+	buf bytes.Buffer
+}
+
+// Bytes 인코딩된 바이트
+func (e *kafkaEncoder) Bytes() []byte { return e.buf.Bytes() }
+
+func (e *kafkaEncoder) putInt8(v int8) { e.buf.WriteByte(byte(v)) }
+
+func (e *kafkaEncoder) putInt16(v int16) {
+	e.buf.Write(binary.BigEndian.AppendUint16(nil, uint16(v)))
+}
+
+func (e *kafkaEncoder) putInt32(v int32) {
+	e.buf.Write(binary.BigEndian.AppendUint32(nil, uint32(v)))
+}
+
+func (e *kafkaEncoder) putInt64(v int64) {
+	e.buf.Write(binary.BigEndian.AppendUint64(nil, uint64(v)))
+}
+
+// putVarint 지그재그 가변 길이 정수 (RecordBatch 레코드 필드)
+func (e *kafkaEncoder) putVarint(v int64) {
+	e.buf.Write(binary.AppendVarint(nil, v))
+}
+
+// putString int16 길이 접두사 문자열
+func (e *kafkaEncoder) putString(s string) {
+	e.putInt16(int16(len(s)))
+	e.buf.WriteString(s)
+}
+
+// putBytes int32 길이 접두사 바이트열
+func (e *kafkaEncoder) putBytes(b []byte) {
+	e.putInt32(int32(len(b)))
+	e.buf.Write(b)
+}
+
+// kafkaDecoder Kafka 프로토콜 응답 디코더 (첫 오류 이후 읽기는 모두 0 값)
+type kafkaDecoder struct {
+	data []byte
+	off  int
+	err  error
+}
+
+// next n 바이트 읽기
+func (d *kafkaDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || d.off+n > len(d.data) {
+		d.err = fmt.Errorf("response truncated at offset %d", d.off)
+		return nil
+	}
+	b := d.data[d.off : d.off+n]
+	d.off += n
+	return b
+}
+
+func (d *kafkaDecoder) int8() int8 {
+	if b := d.next(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int16() int16 {
+	if b := d.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int32() int32 {
+	if b := d.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int64() int64 {
+	if b := d.next(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) string() string {
+	return string(d.next(int(d.int16())))
+}
+
+// nullableString 길이 -1 은 null (빈 문자열)
+func (d *kafkaDecoder) nullableString() string {
+	length := d.int16()
+	if length < 0 {
+		return ""
+	}
+	return string(d.next(int(length)))
+}
+
+func (d *kafkaDecoder) int32Array() {
+	for count := d.int32(); count > 0 && d.err == nil; count-- {
+		d.int32()
+	}
+}
@@ -0,0 +1,487 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// kafkaTestHex 필드별로 나눠 적은 와이어 바이트 (공백 무시)
+func kafkaTestHex(t *testing.T, lines ...string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(strings.Join(strings.Fields(strings.Join(lines, " ")), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// decodeTestRecordBatch RecordBatch v2 디코딩 (헤더 필드, 길이, CRC 검증)
+func decodeTestRecordBatch(t *testing.T, data []byte) []kafkaMessage {
+	t.Helper()
+	d := kafkaDecoder{data: data}
+	if offset := d.int64(); offset != 0 {
+		t.Errorf("base_offset = %d, want 0", offset)
+	}
+	if length := d.int32(); int(length) != len(data)-12 {
+		t.Errorf("batch_length = %d, want %d", length, len(data)-12)
+	}
+	if epoch := d.int32(); epoch != -1 {
+		t.Errorf("partition_leader_epoch = %d, want -1", epoch)
+	}
+	if magic := d.int8(); magic != 2 {
+		t.Fatalf("magic = %d, want 2", magic)
+	}
+	crc := uint32(d.int32())
+	if want := crc32.Checksum(data[d.off:], crc32.MakeTable(crc32.Castagnoli)); crc != want {
+		t.Errorf("crc = %08x, want %08x", crc, want)
+	}
+	if attributes := d.int16(); attributes != 0 {
+		t.Errorf("attributes = %d, want 0", attributes)
+	}
+	lastOffsetDelta := d.int32()
+	baseTimestamp, maxTimestamp := d.int64(), d.int64()
+	if producerID, epoch, sequence := d.int64(), d.int16(), d.int32(); producerID != -1 || epoch != -1 || sequence != -1 {
+		t.Errorf("producer = %d/%d/%d, want -1/-1/-1", producerID, epoch, sequence)
+	}
+	count := d.int32()
+	if d.err != nil {
+		t.Fatal(d.err)
+	}
+	if lastOffsetDelta != count-1 {
+		t.Errorf("last_offset_delta = %d for %d records", lastOffsetDelta, count)
+	}
+
+	varint := func(data []byte) ([]byte, int64) {
+		value, n := binary.Varint(data)
+		if n <= 0 {
+			t.Fatalf("invalid varint at % x", data)
+		}
+		return data[n:], value
+	}
+	rest := data[d.off:]
+	var messages []kafkaMessage
+	latest := baseTimestamp
+	for i := 0; i < int(count); i++ {
+		var length, delta, offsetDelta, keyLength, valueLength, headers int64
+		rest, length = varint(rest)
+		record, next := rest[:length], rest[length:]
+		if record[0] != 0 {
+			t.Errorf("record %d attributes = %d", i, record[0])
+		}
+		record, delta = varint(record[1:])
+		if record, offsetDelta = varint(record); offsetDelta != int64(i) {
+			t.Errorf("record %d offset delta = %d", i, offsetDelta)
+		}
+		message := kafkaMessage{timestamp: time.UnixMilli(baseTimestamp + delta)}
+		if record, keyLength = varint(record); keyLength >= 0 {
+			message.key, record = record[:keyLength], record[keyLength:]
+		}
+		record, valueLength = varint(record)
+		message.value, record = record[:valueLength], record[valueLength:]
+		if record, headers = varint(record); headers != 0 || len(record) != 0 {
+			t.Errorf("record %d: %d headers, %d trailing bytes", i, headers, len(record))
+		}
+		latest = max(latest, baseTimestamp+delta)
+		messages = append(messages, message)
+		rest = next
+	}
+	if len(rest) != 0 {
+		t.Errorf("%d bytes after the last record", len(rest))
+	}
+	if maxTimestamp != latest {
+		t.Errorf("max_timestamp = %d, want %d", maxTimestamp, latest)
+	}
+	return messages
+}
+
+// TestKafkaRecordBatchRoundTrip 인코딩한 RecordBatch 를 다시 읽어 같은 메시지인지
+func TestKafkaRecordBatchRoundTrip(t *testing.T) {
+	base := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		messages []kafkaMessage
+	}{
+		{"single", []kafkaMessage{{key: []byte("web1"), value: []byte(`{"message":"ok"}`), timestamp: base}}},
+		{"null key", []kafkaMessage{{value: []byte("v"), timestamp: base}}},
+		{"empty key and value", []kafkaMessage{{key: []byte{}, value: []byte{}, timestamp: base}}},
+		{"out of order timestamps", []kafkaMessage{
+			{key: []byte("a"), value: []byte("1"), timestamp: base.Add(time.Second)},
+			{key: []byte("b"), value: []byte("2"), timestamp: base},
+			{key: []byte("c"), value: []byte("3"), timestamp: base.Add(-time.Minute)},
+		}},
+		{"large record", []kafkaMessage{
+			{key: []byte("db1"), value: bytes.Repeat([]byte("x"), 300), timestamp: base},
+			{value: bytes.Repeat([]byte("한"), 5000), timestamp: base.Add(time.Hour)},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := decodeTestRecordBatch(t, encodeKafkaRecordBatch(test.messages))
+			if len(got) != len(test.messages) {
+				t.Fatalf("decoded %d records, want %d", len(got), len(test.messages))
+			}
+			for i, want := range test.messages {
+				if (got[i].key == nil) != (want.key == nil) || !bytes.Equal(got[i].key, want.key) ||
+					!bytes.Equal(got[i].value, want.value) || !got[i].timestamp.Equal(want.timestamp) {
+					t.Errorf("record %d = %q/%q/%s, want %q/%q/%s", i,
+						got[i].key, got[i].value, got[i].timestamp, want.key, want.value, want.timestamp)
+				}
+			}
+		})
+	}
+}
+
+// TestKafkaRecordBatchBytes 알려진 레코드 하나의 전체 바이트와 CRC-32C
+func TestKafkaRecordBatchBytes(t *testing.T) {
+	// CRC-32C 표준 검사 값
+	if got := crc32.Checksum([]byte("123456789"), kafkaCRCTable); got != 0xe3069283 {
+		t.Fatalf("crc32c(123456789) = %08x, want e3069283", got)
+	}
+
+	timestamp := time.UnixMilli(0x0192_0000_0000)
+	got := encodeKafkaRecordBatch([]kafkaMessage{{key: []byte("k"), value: []byte("v"), timestamp: timestamp}})
+	want := kafkaTestHex(t,
+		"0000000000000000",           // base_offset
+		"0000003a",                   // batch_length
+		"ffffffff",                   // partition_leader_epoch
+		"02",                         // magic
+		"43dc1b6a",                   // crc (비트 단위 CRC-32C 로 따로 계산한 값)
+		"0000",                       // attributes
+		"00000000",                   // last_offset_delta
+		"0000019200000000",           // base_timestamp
+		"0000019200000000",           // max_timestamp
+		"ffffffffffffffff",           // producer_id
+		"ffff",                       // producer_epoch
+		"ffffffff",                   // base_sequence
+		"00000001",                   // records
+		"10 00 00 00 02 6b 02 76 00", // length 8, attributes, timestamp/offset delta, key "k", value "v", headers
+	)
+	if !bytes.Equal(got, want) {
+		t.Errorf("batch =\n% x\nwant\n% x", got, want)
+	}
+}
+
+// TestKafkaVarint RecordBatch 레코드 필드의 지그재그 가변 길이 정수
+func TestKafkaVarint(t *testing.T) {
+	tests := []struct {
+		value int64
+		want  string
+	}{
+		{0, "00"},
+		{-1, "01"},
+		{1, "02"},
+		{-64, "7f"},
+		{64, "8001"},
+		{300, "d804"},
+		{-301, "d904"},
+		{1 << 31, "8080808010"},
+		{-1 << 63, "ffffffffffffffffff01"},
+	}
+	for _, test := range tests {
+		var e kafkaEncoder
+		e.putVarint(test.value)
+		if got := hex.EncodeToString(e.Bytes()); got != test.want {
+			t.Errorf("putVarint(%d) = %s, want %s", test.value, got, test.want)
+		}
+		if decoded, n := binary.Varint(e.Bytes()); decoded != test.value || n != len(e.Bytes()) {
+			t.Errorf("Varint(%s) = %d, %d", test.want, decoded, n)
+		}
+	}
+}
+
+// TestKafkaMurmur2 Kafka Java 클라이언트 (Utils.murmur2) 와 같은 해시
+func TestKafkaMurmur2(t *testing.T) {
+	tests := map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+		"abc": 479470107,
+	}
+	for input, want := range tests {
+		if got := kafkaMurmur2([]byte(input)); got != want {
+			t.Errorf("kafkaMurmur2(%q) = %d, want %d", input, got, want)
+		}
+	}
+}
+
+// kafkaTestMetadata Metadata v4 응답 (throttle 이후, 브로커 2대, 다른 토픽 하나와 파티션 3개 토픽 하나)
+func kafkaTestMetadata(t *testing.T) []byte {
+	return kafkaTestHex(t,
+		"00000000", // throttle_time_ms
+		"00000002", // brokers
+		"00000001 0006 6b61666b6131 00002384 ffff",              // 1 kafka1:9092 rack=null
+		"00000002 0006 6b61666b6132 00002385 0006 7261636b2d62", // 2 kafka2:9093 rack=rack-b
+		"0002 6331",                        // cluster_id "c1"
+		"00000001",                         // controller_id
+		"00000002",                         // topics
+		"0000 0005 6f74686572 00 00000001", // "other"
+		"  0000 00000000 00000002 00000001 00000002 00000001 00000002",                   //   p0 leader 2
+		"0000 000e 7379736c6f672d6d6f6e69746f72 00 00000003",                             // "syslog-monitor", 파티션 번호 순서가 아님
+		"  0000 00000002 00000001 00000001 00000001 00000001 00000001",                   //   p2 leader 1
+		"  0000 00000000 00000002 00000002 00000002 00000001 00000002 00000002 00000001", // p0 leader 2
+		"  0005 00000001 ffffffff 00000000 00000000",                                     //   p1 LEADER_NOT_AVAILABLE, leader -1
+	)
+}
+
+// kafkaTestMetadataTopic 토픽 하나만 있는 Metadata v4 응답 (파티션마다 리더 지정)
+func kafkaTestMetadataTopic(topicError int16, leaders ...int32) []byte {
+	var e kafkaEncoder
+	e.putInt32(0) // throttle_time_ms
+	e.putInt32(1)
+	e.putInt32(1)
+	e.putString("kafka1")
+	e.putInt32(9092)
+	e.putInt16(-1)
+	e.putInt16(-1) // cluster_id
+	e.putInt32(1)
+	e.putInt32(1)
+	e.putInt16(topicError)
+	e.putString("syslog-monitor")
+	e.putInt8(0)
+	e.putInt32(int32(len(leaders)))
+	for id, leader := range leaders {
+		code := int16(0)
+		if leader < 0 {
+			code = 5
+		}
+		e.putInt16(code)
+		e.putInt32(int32(id))
+		e.putInt32(leader)
+		e.putInt32(0)
+		e.putInt32(0)
+	}
+	return e.Bytes()
+}
+
+// newTestKafkaOutput 브로커에 연결하지 않는 출력
+func newTestKafkaOutput(t *testing.T) *KafkaOutput {
+	t.Helper()
+	ko, err := NewKafkaOutput([]string{"kafka1:9092"}, "syslog-monitor", KafkaPartitionHost, false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ko
+}
+
+// TestKafkaApplyMetadata 브로커 주소, 파티션 정렬, 리더 없는 파티션
+func TestKafkaApplyMetadata(t *testing.T) {
+	ko := newTestKafkaOutput(t)
+	client, server := net.Pipe()
+	defer server.Close()
+	ko.brokerAddrs[2] = "old-kafka2:9093"
+	ko.conns[2] = &kafkaConn{conn: client}
+
+	if err := ko.applyMetadata(kafkaTestMetadata(t)); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(ko.brokerAddrs); got != "map[1:kafka1:9092 2:kafka2:9093]" {
+		t.Errorf("brokerAddrs = %s", got)
+	}
+	if got := fmt.Sprint(ko.partitions); got != "[{0 2} {1 -1} {2 1}]" {
+		t.Errorf("partitions = %s, want [{0 2} {1 -1} {2 1}]", got)
+	}
+	if _, ok := ko.conns[2]; ok {
+		t.Error("connection to a broker whose address changed was kept")
+	}
+
+	// 키가 있으면 리더가 없어도 같은 파티션, 키가 없으면 리더가 있는 파티션만 순환
+	key := []byte("web1")
+	if got := ko.partitionFor(key); got.id != int32(int(kafkaMurmur2(key)&0x7fffffff)%3) {
+		t.Errorf("partitionFor(web1) = %v", got)
+	}
+	for i := 0; i < 6; i++ {
+		if got := ko.partitionFor(nil); got.leader < 0 {
+			t.Errorf("round-robin picked partition %d without a leader", got.id)
+		}
+	}
+}
+
+// TestKafkaApplyMetadataErrors 토픽 오류 코드와 잘린 응답
+func TestKafkaApplyMetadataErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []byte
+		want     string
+	}{
+		{"unknown topic", kafkaTestMetadataTopic(3), "not available yet (UNKNOWN_TOPIC_OR_PARTITION)"},
+		{"leader election", kafkaTestMetadataTopic(5, -1, -1), "not available yet (LEADER_NOT_AVAILABLE)"},
+		{"not authorized", kafkaTestMetadataTopic(29), "no available partitions (error code 29)"},
+		{"empty", nil, "failed to parse metadata response"},
+	}
+	for _, test := range tests {
+		err := newTestKafkaOutput(t).applyMetadata(test.response)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error %v, want %q", test.name, err, test.want)
+		}
+	}
+
+	ko := newTestKafkaOutput(t)
+	if err := ko.applyMetadata(kafkaTestMetadataTopic(0, 1, -1)); err != nil {
+		t.Errorf("partially available topic: %v", err)
+	}
+
+	response := kafkaTestMetadata(t)
+	for length := 0; length < len(response); length++ {
+		if err := newTestKafkaOutput(t).applyMetadata(response[:length]); err == nil {
+			t.Errorf("metadata truncated to %d bytes parsed without error", length)
+		}
+	}
+}
+
+// kafkaTestRequest 가짜 브로커가 받은 요청
+type kafkaTestRequest struct {
+	apiKey, apiVersion int16
+	clientID           string
+	body               []byte
+}
+
+// fakeKafkaBroker 요청 하나를 읽고 같은 correlation ID 로 response 본문을 돌려주는 브로커 (response 가 nil 이면 연결 끊기)
+func fakeKafkaBroker(t *testing.T, response []byte) (*kafkaConn, <-chan kafkaTestRequest) {
+	t.Helper()
+	client, server := net.Pipe()
+	requests := make(chan kafkaTestRequest, 1)
+	go func() {
+		defer server.Close()
+		var size [4]byte
+		if _, err := io.ReadFull(server, size[:]); err != nil {
+			return
+		}
+		frame := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(server, frame); err != nil {
+			return
+		}
+		d := kafkaDecoder{data: frame}
+		request := kafkaTestRequest{apiKey: d.int16(), apiVersion: d.int16()}
+		correlationID := d.int32()
+		request.clientID = d.string()
+		request.body = frame[d.off:]
+		requests <- request
+		if response == nil {
+			return
+		}
+		var reply kafkaEncoder
+		reply.putInt32(int32(4 + len(response)))
+		reply.putInt32(correlationID)
+		reply.buf.Write(response)
+		server.Write(reply.Bytes())
+	}()
+	t.Cleanup(func() { client.Close() })
+	return &kafkaConn{conn: client}, requests
+}
+
+// kafkaTestProduceMessages 파티션별 전송 메시지 (파티션 번호를 값에 기록)
+func kafkaTestProduceMessages() map[int32][]kafkaMessage {
+	partitions := make(map[int32][]kafkaMessage)
+	for partition, count := range []int{1, 2, 2} {
+		for i := 0; i < count; i++ {
+			partitions[int32(partition)] = append(partitions[int32(partition)], kafkaMessage{
+				key:       []byte("web1"),
+				value:     []byte(fmt.Sprintf("p%d-%d", partition, i)),
+				timestamp: time.UnixMilli(1_700_000_000_000 + int64(i)),
+			})
+		}
+	}
+	return partitions
+}
+
+// TestKafkaProduceResponse Produce v3 요청 형식과 응답 오류 코드 처리
+func TestKafkaProduceResponse(t *testing.T) {
+	topic := "000e 7379736c6f672d6d6f6e69746f72 00000003" // 토픽 1개 "syslog-monitor", 파티션 3개
+	tests := []struct {
+		name      string
+		response  []byte
+		wantRetry []string
+		wantErr   string
+	}{
+		{"all written", kafkaTestHex(t,
+			"00000001", topic,
+			"00000000 0000 000000000000002a ffffffffffffffff",
+			"00000001 0000 0000000000000007 ffffffffffffffff",
+			"00000002 0000 0000000000000000 ffffffffffffffff",
+			"00000000", // throttle_time_ms
+		), nil, ""},
+		{"not leader and too large", kafkaTestHex(t,
+			"00000001", topic,
+			"00000000 0000 000000000000002a ffffffffffffffff",
+			"00000001 0006 ffffffffffffffff ffffffffffffffff", // NOT_LEADER_OR_FOLLOWER → 재시도
+			"00000002 000a ffffffffffffffff ffffffffffffffff", // MESSAGE_TOO_LARGE → 버림
+			"00000000",
+		), []string{"p1-0", "p1-1"}, "partition 1: NOT_LEADER_OR_FOLLOWER, partition 2: MESSAGE_TOO_LARGE, dropped 2 messages"},
+		{"other error dropped", kafkaTestHex(t,
+			"00000001", topic,
+			"00000000 0057 ffffffffffffffff ffffffffffffffff", // INVALID_RECORD (87)
+			"00000001 0000 0000000000000000 ffffffffffffffff",
+			"00000002 0013 ffffffffffffffff ffffffffffffffff", // NOT_ENOUGH_REPLICAS → 재시도
+			"00000000",
+		), []string{"p2-0", "p2-1"}, "partition 0: error code 87, dropped 1 messages, partition 2: NOT_ENOUGH_REPLICAS"},
+		{"truncated", kafkaTestHex(t, "00000001", topic, "00000000 00"), nil, "failed to parse produce response"},
+		{"connection closed", nil, []string{"p0-0", "p1-0", "p1-1", "p2-0", "p2-1"}, "broker kafka1:9092"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ko := newTestKafkaOutput(t)
+			ko.brokerAddrs[1] = "kafka1:9092"
+			conn, requests := fakeKafkaBroker(t, test.response)
+			ko.conns[1] = conn
+			partitions := kafkaTestProduceMessages()
+
+			retry, err := ko.produceToBroker(1, partitions)
+			if test.wantErr == "" && err != nil || test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Errorf("error = %v, want %q", err, test.wantErr)
+			}
+			var values []string
+			for _, message := range retry {
+				values = append(values, string(message.value))
+			}
+			if test.response == nil {
+				sort.Strings(values) // 연결 오류는 파티션 순서와 관계없이 전체 재시도
+				if _, ok := ko.conns[1]; ok {
+					t.Error("connection kept after a failed round trip")
+				}
+			}
+			if strings.Join(values, ",") != strings.Join(test.wantRetry, ",") {
+				t.Errorf("retry = %v, want %v", values, test.wantRetry)
+			}
+
+			// 요청: Produce v3, acks=1, 파티션마다 RecordBatch 하나
+			request := <-requests
+			if request.apiKey != kafkaAPIProduce || request.apiVersion != 3 || request.clientID != kafkaClientID {
+				t.Fatalf("request header = %d v%d %s", request.apiKey, request.apiVersion, request.clientID)
+			}
+			d := kafkaDecoder{data: request.body}
+			if transactionalID, acks, timeout := d.int16(), d.int16(), d.int32(); transactionalID != -1 || acks != 1 || timeout != 30000 {
+				t.Errorf("transactional_id=%d acks=%d timeout=%d", transactionalID, acks, timeout)
+			}
+			if topics, name, count := d.int32(), d.string(), d.int32(); topics != 1 || name != "syslog-monitor" || count != 3 {
+				t.Fatalf("topics=%d name=%s partitions=%d", topics, name, count)
+			}
+			for i := 0; i < 3; i++ {
+				id := d.int32()
+				batch := d.next(int(d.int32()))
+				if d.err != nil {
+					t.Fatal(d.err)
+				}
+				messages := decodeTestRecordBatch(t, batch)
+				if len(messages) != len(partitions[id]) || string(messages[0].value) != string(partitions[id][0].value) {
+					t.Errorf("partition %d batch = %d records", id, len(messages))
+				}
+			}
+			if d.off != len(d.data) {
+				t.Errorf("%d bytes after the last partition", len(d.data)-d.off)
+			}
+		})
+	}
+}
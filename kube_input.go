@@ -0,0 +1,562 @@
+/*
+Kubernetes Input Module
+=======================
+
+쿠버네티스 파드 로그 입력 (-kube-namespace, -kube-selector)
+
+Kubernetes API 로 네임스페이스/레이블 셀렉터에 맞는 파드를 list + watch 하고, 실행 중인 컨테이너마다
+로그 스트림(pods/log?follow=true)을 열어 각 줄을 기존 처리 파이프라인에 전달합니다.
+client-go 없이 REST API 를 직접 호출합니다.
+
+주요 기능:
+- API 접근: 클러스터 안에서는 서비스 계정 토큰/CA 자동 사용, 밖에서는 -kube-api (예: kubectl proxy 주소)
+- 파드 변동 자동 처리: 새 파드/재시작한 컨테이너는 스트림 시작, 종료·삭제된 파드는 남은 로그를 끝까지 읽고 정리
+- 끊긴 스트림은 마지막 타임스탬프 이후부터 다시 연결 (중복 줄 건너뜀), watch 가 끊기거나 만료되면 다시 list
+- 시작 시 이미 실행 중인 컨테이너는 새 로그부터, 이후 나타난 컨테이너는 처음부터 읽음
+- 파싱된 로그에 namespace, pod, container, node, workload (Deployment/StatefulSet/DaemonSet 등) 필드 추가
+- 기존 감지기와 호환되도록 "시간 노드 네임스페이스/파드/컨테이너: 메시지" syslog 형식 라인 재구성
+
+필요 권한 (RBAC): pods 의 get/list/watch, pods/log 의 get
+*/
+package main
+
+import (
+	"bufio"         // 로그 스트림 라인 읽기
+	"context"       // 요청 취소
+	"crypto/tls"    // API 서버 TLS
+	"crypto/x509"   // CA 인증서
+	"encoding/json" // API 응답 파싱
+	"fmt"           // 형식화된 I/O
+	"io"            // 응답 본문
+	"net"           // 서비스 호스트 주소
+	"net/http"      // REST API
+	"net/url"       // 쿼리 인코딩
+	"os"            // 토큰/CA 파일, 환경변수
+	"strings"       // 문자열 처리
+	"sync"          // 스트림 관리
+	"time"          // 재연결 간격
+)
+
+// 쿠버네티스 입력 관련 상수
+const (
+	KubeLogType           = "kubernetes"                                    // 입력 소스가 전달하는 ParsedLog 표시 (파이프라인에서 본문을 다시 파싱)
+	KubeAllNamespaces     = "*"                                             // -kube-namespace=* 이면 모든 네임스페이스
+	KubeServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount" // 클러스터 안 서비스 계정 자격 증명
+
+	kubeRetryDelay     = 5 * time.Second  // 스트림/watch 재연결 간격
+	kubeWatchTimeout   = 300              // watch 요청 서버 측 제한 (초)
+	kubeMaxLineSize    = 1024 * 1024      // 컨테이너 로그 한 줄 최대 크기
+	kubeRequestTimeout = 30 * time.Second // list 요청 제한
+)
+
+// KubeOptions 쿠버네티스 입력 설정
+type KubeOptions struct {
+	Namespace string // 네임스페이스 ("*" 이면 전체, 비어 있으면 서비스 계정 네임스페이스 또는 default)
+	Selector  string // 레이블 셀렉터 (예: app=web,tier!=cache)
+	APIServer string // API 서버 주소 (비어 있으면 클러스터 안 서비스 계정 사용)
+	TokenFile string // Bearer 토큰 파일 (요청마다 다시 읽어 토큰 교체 반영)
+	CAFile    string // API 서버 인증서를 검증할 CA 파일
+}
+
+// Describe 로그 표시용 대상 ("namespace/selector")
+func (o KubeOptions) Describe() string {
+	target := o.Namespace
+	switch target {
+	case KubeAllNamespaces:
+		target = "all namespaces"
+	case "":
+		target = "service account namespace"
+	}
+	if o.Selector != "" {
+		target += " (" + o.Selector + ")"
+	}
+	return target
+}
+
+// kubePod API 응답의 파드 (사용하는 필드만)
+type kubePod struct {
+	Metadata struct {
+		Name              string            `json:"name"`
+		Namespace         string            `json:"namespace"`
+		Labels            map[string]string `json:"labels"`
+		DeletionTimestamp *string           `json:"deletionTimestamp"`
+		OwnerReferences   []struct {
+			Kind string `json:"kind"`
+			Name string `json:"name"`
+		} `json:"ownerReferences"`
+	} `json:"metadata"`
+	Spec struct {
+		NodeName string `json:"nodeName"`
+	} `json:"spec"`
+	Status struct {
+		Phase             string `json:"phase"`
+		ContainerStatuses []struct {
+			Name  string `json:"name"`
+			State struct {
+				Running *struct{} `json:"running"`
+			} `json:"state"`
+		} `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+// kubePodList 파드 목록 응답
+type kubePodList struct {
+	Metadata struct {
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+	Items []kubePod `json:"items"`
+}
+
+// kubeWatchEvent watch 스트림 이벤트
+type kubeWatchEvent struct {
+	Type   string          `json:"type"` // ADDED, MODIFIED, DELETED, ERROR
+	Object json.RawMessage `json:"object"`
+}
+
+// kubeContainer 로그를 읽을 컨테이너와 워크로드 정보
+type kubeContainer struct {
+	Namespace    string
+	Pod          string
+	Container    string
+	Node         string
+	Workload     string
+	WorkloadKind string
+}
+
+// key 스트림 식별자
+func (c kubeContainer) key() string {
+	return c.Namespace + "/" + c.Pod + "/" + c.Container
+}
+
+// kubeStream 컨테이너 하나의 로그 스트림 상태
+type kubeStream struct {
+	retired bool // 파드가 끝나거나 삭제됨: 현재 스트림이 끝나면 다시 연결하지 않음
+	done    bool // 고루틴 종료
+}
+
+// KubeLogReader 파드 로그 리더
+type KubeLogReader struct {
+	options KubeOptions
+	baseURL string
+	client  *http.Client
+	entries chan JournalEntry
+	streams map[string]*kubeStream
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mutex   sync.Mutex
+	logger  Logger
+}
+
+// NewKubeLogReader API 접근을 설정하고 파드 watch 와 로그 스트림 시작
+func NewKubeLogReader(options KubeOptions, logger Logger) (*KubeLogReader, error) {
+	baseURL := strings.TrimRight(options.APIServer, "/")
+	if baseURL == "" {
+		// 클러스터 안: 서비스 계정 자격 증명 (kubelet 이 주입)
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, fmt.Errorf("not running in a cluster (KUBERNETES_SERVICE_HOST unset); use -kube-api (e.g. http://127.0.0.1:8001 with kubectl proxy)")
+		}
+		baseURL = "https://" + net.JoinHostPort(host, port)
+		if options.TokenFile == "" {
+			options.TokenFile = KubeServiceAccountDir + "/token"
+		}
+		if options.CAFile == "" {
+			options.CAFile = KubeServiceAccountDir + "/ca.crt"
+		}
+	}
+	if options.Namespace == "" {
+		options.Namespace = "default"
+		if data, err := os.ReadFile(KubeServiceAccountDir + "/namespace"); err == nil && strings.TrimSpace(string(data)) != "" {
+			options.Namespace = strings.TrimSpace(string(data))
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if options.CAFile != "" {
+		pem, err := os.ReadFile(options.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Kubernetes CA: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", options.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reader := &KubeLogReader{
+		options: options,
+		baseURL: baseURL,
+		// 로그/watch 는 오래 열려 있는 스트림이므로 전체 시간 제한 없이 컨텍스트로 취소
+		client:  &http.Client{Transport: transport},
+		entries: make(chan JournalEntry, 100),
+		streams: make(map[string]*kubeStream),
+		ctx:     ctx,
+		cancel:  cancel,
+		logger:  logger,
+	}
+
+	// 권한/주소 오류는 시작할 때 바로 알림
+	list, err := reader.listPods()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	reader.wg.Add(1)
+	go reader.run(list)
+	return reader, nil
+}
+
+// Entries 로그 엔트리 채널
+func (kr *KubeLogReader) Entries() <-chan JournalEntry {
+	return kr.entries
+}
+
+// Stop 모든 watch/로그 스트림 종료
+func (kr *KubeLogReader) Stop() {
+	kr.cancel()
+	kr.wg.Wait()
+}
+
+// run 파드 목록을 반영하고 watch, 끊기면 다시 list (처음 목록의 컨테이너는 새 로그부터)
+func (kr *KubeLogReader) run(list *kubePodList) {
+	defer kr.wg.Done()
+	initial := true
+	for {
+		kr.reconcile(list.Items, initial)
+		initial = false
+
+		if err := kr.watchPods(list.Metadata.ResourceVersion); err != nil && kr.ctx.Err() == nil {
+			kr.logger.Errorf("Kubernetes pod watch failed: %v (retrying in %v)", err, kubeRetryDelay)
+		}
+		for {
+			select {
+			case <-kr.ctx.Done():
+				return
+			case <-time.After(kubeRetryDelay):
+			}
+			var err error
+			if list, err = kr.listPods(); err == nil {
+				break
+			}
+			kr.logger.Errorf("Kubernetes pod list failed: %v", err)
+		}
+	}
+}
+
+// podsPath 네임스페이스에 맞는 파드 API 경로
+func (kr *KubeLogReader) podsPath() string {
+	if kr.options.Namespace == KubeAllNamespaces {
+		return "/api/v1/pods"
+	}
+	return "/api/v1/namespaces/" + url.PathEscape(kr.options.Namespace) + "/pods"
+}
+
+// listPods 셀렉터에 맞는 파드 목록
+func (kr *KubeLogReader) listPods() (*kubePodList, error) {
+	query := url.Values{}
+	if kr.options.Selector != "" {
+		query.Set("labelSelector", kr.options.Selector)
+	}
+	ctx, cancel := context.WithTimeout(kr.ctx, kubeRequestTimeout)
+	defer cancel()
+	resp, err := kr.get(ctx, kr.podsPath(), query)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var list kubePodList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("invalid pod list: %v", err)
+	}
+	return &list, nil
+}
+
+// watchPods resourceVersion 이후 파드 변경을 받아 반영 (스트림이 끝나거나 만료되면 반환)
+func (kr *KubeLogReader) watchPods(resourceVersion string) error {
+	query := url.Values{"watch": {"1"}, "resourceVersion": {resourceVersion}, "timeoutSeconds": {fmt.Sprint(kubeWatchTimeout)}}
+	if kr.options.Selector != "" {
+		query.Set("labelSelector", kr.options.Selector)
+	}
+	resp, err := kr.get(kr.ctx, kr.podsPath(), query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var event kubeWatchEvent
+		if err := decoder.Decode(&event); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if event.Type == "ERROR" {
+			// 410 Gone 등: resourceVersion 이 만료되어 다시 list 필요
+			return fmt.Errorf("watch error: %s", strings.TrimSpace(string(event.Object)))
+		}
+		var pod kubePod
+		if err := json.Unmarshal(event.Object, &pod); err != nil {
+			continue
+		}
+		if event.Type == "DELETED" {
+			pod.Status.ContainerStatuses = nil
+		}
+		kr.reconcilePod(pod, false)
+	}
+}
+
+// reconcile 목록 전체 반영 (목록에 없는 파드의 스트림은 정리)
+func (kr *KubeLogReader) reconcile(pods []kubePod, fromNow bool) {
+	present := make(map[string]bool)
+	for _, pod := range pods {
+		for _, container := range kr.reconcilePod(pod, fromNow) {
+			present[container] = true
+		}
+	}
+	kr.mutex.Lock()
+	defer kr.mutex.Unlock()
+	for key, stream := range kr.streams {
+		if !present[key] {
+			stream.retired = true
+		}
+	}
+}
+
+// reconcilePod 파드 하나의 실행 중인 컨테이너 스트림 시작/정리 (실행 중인 컨테이너 키 반환)
+func (kr *KubeLogReader) reconcilePod(pod kubePod, fromNow bool) []string {
+	running := make(map[string]bool)
+	if pod.Metadata.DeletionTimestamp == nil {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.State.Running != nil {
+				running[status.Name] = true
+			}
+		}
+	}
+
+	workloadKind, workload := kubeWorkload(pod)
+	prefix := pod.Metadata.Namespace + "/" + pod.Metadata.Name + "/"
+	var keys []string
+
+	kr.mutex.Lock()
+	defer kr.mutex.Unlock()
+	for key, stream := range kr.streams {
+		if strings.HasPrefix(key, prefix) && !running[strings.TrimPrefix(key, prefix)] {
+			stream.retired = true
+		}
+	}
+	for name := range running {
+		container := kubeContainer{
+			Namespace:    pod.Metadata.Namespace,
+			Pod:          pod.Metadata.Name,
+			Container:    name,
+			Node:         pod.Spec.NodeName,
+			Workload:     workload,
+			WorkloadKind: workloadKind,
+		}
+		key := container.key()
+		keys = append(keys, key)
+		if stream, ok := kr.streams[key]; ok && !stream.done {
+			stream.retired = false
+			continue
+		}
+		stream := &kubeStream{}
+		kr.streams[key] = stream
+		kr.wg.Add(1)
+		go kr.follow(container, stream, fromNow)
+	}
+	return keys
+}
+
+// follow 컨테이너 로그 스트림을 읽고, 끊기면 마지막 타임스탬프 이후부터 다시 연결
+func (kr *KubeLogReader) follow(container kubeContainer, stream *kubeStream, fromNow bool) {
+	defer kr.wg.Done()
+	defer func() {
+		kr.mutex.Lock()
+		stream.done = true
+		if kr.streams[container.key()] == stream {
+			delete(kr.streams, container.key())
+		}
+		kr.mutex.Unlock()
+	}()
+
+	var last time.Time
+	failing := false
+	for {
+		query := url.Values{"container": {container.Container}, "follow": {"true"}, "timestamps": {"true"}}
+		switch {
+		case !last.IsZero():
+			query.Set("sinceTime", last.UTC().Format(time.RFC3339))
+		case fromNow:
+			query.Set("tailLines", "0")
+		}
+		path := "/api/v1/namespaces/" + url.PathEscape(container.Namespace) + "/pods/" + url.PathEscape(container.Pod) + "/log"
+		resp, err := kr.get(kr.ctx, path, query)
+		if err == nil {
+			if failing {
+				kr.logger.Infof("☸️  Log stream reconnected: %s", container.key())
+				failing = false
+			}
+			last = kr.readLogStream(resp.Body, container, last)
+			resp.Body.Close()
+		} else if kr.ctx.Err() == nil && !failing {
+			kr.logger.Errorf("Kubernetes log stream %s failed: %v", container.key(), err)
+			failing = true
+		}
+		if last.IsZero() {
+			// 아무 줄도 읽지 않았으면 다음 연결은 지금 이후부터 (재연결 때 과거 로그를 다시 읽지 않도록)
+			last = time.Now()
+		}
+
+		select {
+		case <-kr.ctx.Done():
+			return
+		case <-time.After(kubeRetryDelay):
+		}
+		kr.mutex.Lock()
+		retired := stream.retired
+		kr.mutex.Unlock()
+		if retired {
+			return
+		}
+	}
+}
+
+// readLogStream "타임스탬프 메시지" 줄을 엔트리로 전달 (after 이전/같은 시간 줄은 재연결 중복이므로 건너뜀)
+func (kr *KubeLogReader) readLogStream(body io.Reader, container kubeContainer, after time.Time) time.Time {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), kubeMaxLineSize)
+	for scanner.Scan() {
+		stamp, message, _ := strings.Cut(scanner.Text(), " ")
+		timestamp, err := time.Parse(time.RFC3339Nano, stamp)
+		if err != nil {
+			timestamp, message = time.Now(), scanner.Text()
+		} else if !timestamp.After(after) {
+			continue
+		}
+		after = timestamp
+		select {
+		case kr.entries <- newKubeEntry(container, timestamp, message):
+		case <-kr.ctx.Done():
+			return after
+		}
+	}
+	return after
+}
+
+// get API 요청 (토큰 파일은 요청마다 읽어 교체된 토큰 반영)
+func (kr *KubeLogReader) get(ctx context.Context, path string, query url.Values) (*http.Response, error) {
+	target := kr.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if kr.options.TokenFile != "" {
+		token, err := os.ReadFile(kr.options.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	resp, err := kr.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: HTTP %d: %s", path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp, nil
+}
+
+// kubeWorkload 파드를 만든 워크로드 (ReplicaSet 은 pod-template-hash 를 떼어 Deployment 로 표시)
+func kubeWorkload(pod kubePod) (kind, name string) {
+	if len(pod.Metadata.OwnerReferences) == 0 {
+		return "Pod", pod.Metadata.Name
+	}
+	owner := pod.Metadata.OwnerReferences[0]
+	if owner.Kind == "ReplicaSet" {
+		if hash := pod.Metadata.Labels["pod-template-hash"]; hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+			return "Deployment", strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+	}
+	if owner.Kind == "Job" {
+		// CronJob 이 만든 Job 이름은 "<cronjob>-<스케줄 시각>"
+		if index := strings.LastIndex(owner.Name, "-"); index > 0 && len(owner.Name)-index-1 >= 8 && strings.Trim(owner.Name[index+1:], "0123456789") == "" {
+			return "CronJob", owner.Name[:index]
+		}
+	}
+	return owner.Kind, owner.Name
+}
+
+// newKubeEntry 컨테이너 로그 한 줄을 파이프라인 엔트리로 변환
+func newKubeEntry(container kubeContainer, timestamp time.Time, message string) JournalEntry {
+	node := container.Node
+	if node == "" {
+		node = "-"
+	}
+	// 기존 감지기(로그인, 에러 키워드)가 그대로 동작하도록 syslog 형식 재구성 (호스트=노드, 서비스=네임스페이스/파드/컨테이너)
+	line := fmt.Sprintf("%s %s %s: %s", timestamp.Local().Format(time.Stamp), node, container.key(), message)
+	return JournalEntry{
+		Line: line,
+		Parsed: &ParsedLog{
+			Timestamp: timestamp,
+			LogType:   KubeLogType,
+			Level:     LogLevelInfo,
+			Source:    container.Workload,
+			Message:   message,
+			RawLog:    line,
+			Fields:    container.fields(),
+		},
+	}
+}
+
+// fields 파싱된 로그에 덧붙일 워크로드 필드
+func (c kubeContainer) fields() map[string]string {
+	return map[string]string{
+		"namespace":     c.Namespace,
+		"pod":           c.Pod,
+		"container":     c.Container,
+		"node":          c.Node,
+		"workload":      c.Workload,
+		"workload_kind": c.WorkloadKind,
+	}
+}
+
+// annotateKubeLog 컨테이너 로그 본문의 형식별 파싱 결과에 파드 정보 필드와 원본 라인/시간 반영
+func annotateKubeLog(parsed, source *ParsedLog) *ParsedLog {
+	if parsed.Fields == nil {
+		parsed.Fields = make(map[string]string)
+	}
+	for name, value := range source.Fields {
+		if value != "" {
+			parsed.Fields[name] = value
+		}
+	}
+	if parsed.LogType == "unknown" {
+		parsed.LogType = KubeLogType
+		parsed.Timestamp = source.Timestamp
+	}
+	if parsed.Source == "" {
+		parsed.Source = source.Source
+	}
+	parsed.RawLog = source.RawLog
+	return parsed
+}
+
+// kubeWorkloadLabel 알림 표시용 워크로드 ("namespace/Deployment/web"), 쿠버네티스 로그가 아니면 빈 문자열
+func kubeWorkloadLabel(parsedLog *ParsedLog) string {
+	if parsedLog == nil || parsedLog.Fields["pod"] == "" {
+		return ""
+	}
+	return parsedLog.Fields["namespace"] + "/" + parsedLog.Fields["workload_kind"] + "/" + parsedLog.Fields["workload"]
+}
@@ -0,0 +1,333 @@
+/*
+Latency Trace Module
+====================
+
+표본 이벤트의 처리 단계별 지연 시간 추적 (-trace-sample, -slow-event)
+
+필터를 통과한 이벤트 중 일부를 골라 파싱 → AI 분석 → 위치 조회 → 알림 단계별 소요 시간을 기록하고,
+Prometheus 히스토그램으로 노출합니다. 운영 중 파이프라인의 병목 단계를 찾는 데 사용합니다.
+
+주요 기능:
+- 표본 추출: N 번째 이벤트마다 추적 (비율 0.01 = 100개 중 1개, 1 = 전부)
+- 단계: parse (syslog/형식별 파싱), ai (AI 분석, ASN 조회 포함), geo (로그인 IP 위치 조회), notify (출력/감지/알림)
+- 전체 지연: 처리 루프에서 제출한 시점부터 모든 단계(비동기 위치 조회 포함)가 끝날 때까지 (큐 대기 포함)
+- 예산을 넘은 느린 이벤트는 단계별 소요 시간과 함께 로그로 기록
+- GET /metrics: syslog_monitor_event_stage_seconds, syslog_monitor_event_seconds 히스토그램, 느린 이벤트 수
+*/
+package main
+
+import (
+	"fmt"         // 형식화된 I/O
+	"math"        // 표본 간격 계산
+	"strconv"     // 숫자 형식
+	"strings"     // 문자열 처리
+	"sync"        // 동기화
+	"sync/atomic" // 단계 시간 누적, 표본 카운터
+	"time"        // 시간 처리
+)
+
+// DefaultSlowEventBudget 느린 이벤트 로그 기본 기준 시간
+const DefaultSlowEventBudget = 2 * time.Second
+
+// LatencyStage 처리 단계
+type LatencyStage int
+
+// 처리 단계 (latencyStageNames 와 같은 순서)
+const (
+	LatencyStageParse LatencyStage = iota
+	LatencyStageAI
+	LatencyStageGeo
+	LatencyStageNotify
+	latencyStageCount
+)
+
+// latencyStageNames Prometheus stage 라벨 / 로그 필드 이름
+var latencyStageNames = [latencyStageCount]string{"parse", "ai", "geo", "notify"}
+
+// latencyBuckets 히스토그램 버킷 상한 (초)
+var latencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// latencyHistogram Prometheus 누적 히스토그램 (버킷별 개수는 le 이하 누적이 아닌 구간 개수로 저장)
+type latencyHistogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{buckets: make([]uint64, len(latencyBuckets))}
+}
+
+// observe 관찰값 기록 (버킷 범위를 넘으면 +Inf 에만 포함)
+func (h *latencyHistogram) observe(seconds float64) {
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			h.buckets[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// LatencyHistogramSnapshot 히스토그램 사본 (Buckets 는 latencyBuckets 상한별 누적 개수)
+type LatencyHistogramSnapshot struct {
+	Buckets []uint64
+	Count   uint64
+	Sum     float64
+}
+
+func (h *latencyHistogram) snapshot() LatencyHistogramSnapshot {
+	cumulative := make([]uint64, len(h.buckets))
+	var running uint64
+	for i, n := range h.buckets {
+		running += n
+		cumulative[i] = running
+	}
+	return LatencyHistogramSnapshot{Buckets: cumulative, Count: h.count, Sum: h.sum}
+}
+
+// quantile 분위수 추정 (해당 요청이 들어간 버킷의 상한, 마지막 버킷을 넘으면 마지막 상한)
+func (h *latencyHistogram) quantile(q float64) float64 {
+	if h.count == 0 {
+		return 0
+	}
+	rank := uint64(q * float64(h.count))
+	if rank == 0 {
+		rank = 1
+	}
+	var running uint64
+	for i, count := range h.buckets {
+		running += count
+		if running >= rank {
+			return latencyBuckets[i]
+		}
+	}
+	return latencyBuckets[len(latencyBuckets)-1]
+}
+
+// LatencySnapshot 지연 시간 추적 지표 사본
+type LatencySnapshot struct {
+	Stages  [latencyStageCount]LatencyHistogramSnapshot
+	Total   LatencyHistogramSnapshot
+	Sampled int64
+	Slow    int64
+}
+
+// TenantLatencyStats 테넌트별 지연 시간 지표 (Tenant 가 비어 있으면 수집 서버 자체)
+type TenantLatencyStats struct {
+	Tenant string
+	Stats  LatencySnapshot
+}
+
+// LatencyTracer 표본 이벤트의 단계별 지연 시간 집계
+type LatencyTracer struct {
+	every  uint64        // N 번째 이벤트마다 추적
+	budget time.Duration // 이 시간을 넘으면 느린 이벤트 (0 이면 기록 안 함)
+	onSlow func(*EventTrace)
+
+	seen  uint64 // 표본 추출 카운터 (atomic)
+	mutex sync.Mutex
+
+	stages  [latencyStageCount]*latencyHistogram
+	total   *latencyHistogram
+	sampled int64
+	slow    int64
+}
+
+// NewLatencyTracer 추적기 생성 (rate 는 0 초과 1 이하의 표본 비율)
+func NewLatencyTracer(rate float64, budget time.Duration) (*LatencyTracer, error) {
+	if rate <= 0 || rate > 1 {
+		return nil, fmt.Errorf("trace sample rate must be within (0, 1]: %g", rate)
+	}
+	if budget < 0 {
+		return nil, fmt.Errorf("slow event budget must not be negative: %v", budget)
+	}
+	tracer := &LatencyTracer{
+		every:  uint64(math.Round(1 / rate)),
+		budget: budget,
+		total:  newLatencyHistogram(),
+	}
+	for i := range tracer.stages {
+		tracer.stages[i] = newLatencyHistogram()
+	}
+	return tracer, nil
+}
+
+// Rate 표본 비율 (실제 간격 기준)
+func (t *LatencyTracer) Rate() float64 {
+	return 1 / float64(t.every)
+}
+
+// Budget 느린 이벤트 기준 시간
+func (t *LatencyTracer) Budget() time.Duration {
+	return t.budget
+}
+
+// Sample 이벤트를 추적할지 결정 (추적 대상이 아니거나 추적기가 nil 이면 nil)
+func (t *LatencyTracer) Sample(line string) *EventTrace {
+	if t == nil || (atomic.AddUint64(&t.seen, 1)-1)%t.every != 0 {
+		return nil
+	}
+	return &EventTrace{tracer: t, Line: line, Started: time.Now(), pending: 1}
+}
+
+// finish 완료된 추적을 히스토그램에 반영하고 예산을 넘었으면 onSlow 호출
+func (t *LatencyTracer) finish(trace *EventTrace) {
+	total := trace.Total()
+
+	t.mutex.Lock()
+	t.sampled++
+	for stage := LatencyStage(0); stage < latencyStageCount; stage++ {
+		if duration, ok := trace.Stage(stage); ok {
+			t.stages[stage].observe(duration.Seconds())
+		}
+	}
+	t.total.observe(total.Seconds())
+	slow := t.budget > 0 && total > t.budget
+	if slow {
+		t.slow++
+	}
+	onSlow := t.onSlow
+	t.mutex.Unlock()
+
+	if slow && onSlow != nil {
+		onSlow(trace)
+	}
+}
+
+// Snapshot 현재 지표 사본
+func (t *LatencyTracer) Snapshot() LatencySnapshot {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	snapshot := LatencySnapshot{Total: t.total.snapshot(), Sampled: t.sampled, Slow: t.slow}
+	for i, histogram := range t.stages {
+		snapshot.Stages[i] = histogram.snapshot()
+	}
+	return snapshot
+}
+
+// EventTrace 추적 중인 이벤트 한 개 (단계는 서로 다른 고루틴에서 기록될 수 있어 atomic 사용)
+// 모든 메서드는 nil 수신자에서 아무것도 하지 않음 (추적 대상이 아닌 이벤트)
+type EventTrace struct {
+	tracer   *LatencyTracer
+	Line     string
+	Started  time.Time
+	stages   [latencyStageCount]int64 // 단계별 누적 나노초
+	recorded [latencyStageCount]int32 // 단계 실행 여부
+	pending  int32                    // 끝나지 않은 흐름 수 (처리 흐름 1 + 비동기 단계)
+	finished int64                    // 완료 시각 (UnixNano)
+}
+
+// Add 단계 소요 시간 누적
+func (tr *EventTrace) Add(stage LatencyStage, duration time.Duration) {
+	if tr == nil {
+		return
+	}
+	atomic.AddInt64(&tr.stages[stage], int64(duration))
+	atomic.StoreInt32(&tr.recorded[stage], 1)
+}
+
+// Hold 비동기 단계 시작 (해당 단계가 Release 할 때까지 완료를 미룸)
+func (tr *EventTrace) Hold() {
+	if tr == nil {
+		return
+	}
+	atomic.AddInt32(&tr.pending, 1)
+}
+
+// Release 처리 흐름 또는 비동기 단계 종료 (마지막 흐름이면 추적 완료)
+func (tr *EventTrace) Release() {
+	if tr == nil {
+		return
+	}
+	if atomic.AddInt32(&tr.pending, -1) == 0 {
+		atomic.StoreInt64(&tr.finished, time.Now().UnixNano())
+		tr.tracer.finish(tr)
+	}
+}
+
+// Stage 단계 소요 시간 (실행되지 않은 단계는 false)
+func (tr *EventTrace) Stage(stage LatencyStage) (time.Duration, bool) {
+	if atomic.LoadInt32(&tr.recorded[stage]) == 0 {
+		return 0, false
+	}
+	return time.Duration(atomic.LoadInt64(&tr.stages[stage])), true
+}
+
+// Total 제출부터 완료까지 걸린 시간 (완료 전이면 지금까지)
+func (tr *EventTrace) Total() time.Duration {
+	if finished := atomic.LoadInt64(&tr.finished); finished != 0 {
+		return time.Unix(0, finished).Sub(tr.Started)
+	}
+	return time.Since(tr.Started)
+}
+
+// Queued 단계 밖에서 보낸 시간 (파이프라인 큐 대기 등, 비동기 단계와 겹치면 0)
+func (tr *EventTrace) Queued() time.Duration {
+	queued := tr.Total()
+	for stage := LatencyStage(0); stage < latencyStageCount; stage++ {
+		duration, _ := tr.Stage(stage)
+		queued -= duration
+	}
+	if queued < 0 {
+		return 0
+	}
+	return queued
+}
+
+// Breakdown 단계별 소요 시간 요약 ("parse=1.2ms ai=830ms notify=4ms queue=12ms")
+func (tr *EventTrace) Breakdown() string {
+	var parts []string
+	for stage := LatencyStage(0); stage < latencyStageCount; stage++ {
+		if duration, ok := tr.Stage(stage); ok {
+			parts = append(parts, latencyStageNames[stage]+"="+formatLatency(duration))
+		}
+	}
+	parts = append(parts, "queue="+formatLatency(tr.Queued()))
+	return strings.Join(parts, " ")
+}
+
+// formatLatency 지연 시간을 밀리초 단위로 표시
+func formatLatency(duration time.Duration) string {
+	return strconv.FormatFloat(float64(duration)/float64(time.Millisecond), 'f', 1, 64) + "ms"
+}
+
+// WriteLatencyMetrics Prometheus 텍스트 형식 지연 시간 지표 기록 (추적기가 없는 범위는 scopes 에서 제외)
+func WriteLatencyMetrics(builder *strings.Builder, scopes []TenantLatencyStats) {
+	if len(scopes) == 0 {
+		return
+	}
+
+	builder.WriteString("# HELP syslog_monitor_event_stage_seconds Processing time of sampled events per pipeline stage.\n")
+	builder.WriteString("# TYPE syslog_monitor_event_stage_seconds histogram\n")
+	for _, scope := range scopes {
+		for stage, histogram := range scope.Stats.Stages {
+			writeLatencyHistogram(builder, "syslog_monitor_event_stage_seconds", histogram, scope.Tenant, "stage", latencyStageNames[stage])
+		}
+	}
+
+	builder.WriteString("# HELP syslog_monitor_event_seconds End-to-end processing time of sampled events, including queue waits.\n")
+	builder.WriteString("# TYPE syslog_monitor_event_seconds histogram\n")
+	for _, scope := range scopes {
+		writeLatencyHistogram(builder, "syslog_monitor_event_seconds", scope.Stats.Total, scope.Tenant)
+	}
+
+	builder.WriteString("# HELP syslog_monitor_slow_events_total Sampled events that exceeded the slow event budget.\n")
+	builder.WriteString("# TYPE syslog_monitor_slow_events_total counter\n")
+	for _, scope := range scopes {
+		fmt.Fprintf(builder, "syslog_monitor_slow_events_total%s %d\n", prometheusLabels(scope.Tenant), scope.Stats.Slow)
+	}
+}
+
+// writeLatencyHistogram 히스토그램 한 개의 _bucket/_sum/_count 줄 기록
+func writeLatencyHistogram(builder *strings.Builder, name string, histogram LatencyHistogramSnapshot, tenant string, labels ...string) {
+	for i, bound := range latencyBuckets {
+		bucketLabels := append(append([]string{}, labels...), "le", strconv.FormatFloat(bound, 'g', -1, 64))
+		fmt.Fprintf(builder, "%s_bucket%s %d\n", name, prometheusLabels(tenant, bucketLabels...), histogram.Buckets[i])
+	}
+	fmt.Fprintf(builder, "%s_bucket%s %d\n", name, prometheusLabels(tenant, append(append([]string{}, labels...), "le", "+Inf")...), histogram.Count)
+	fmt.Fprintf(builder, "%s_sum%s %s\n", name, prometheusLabels(tenant, labels...), strconv.FormatFloat(histogram.Sum, 'f', 6, 64))
+	fmt.Fprintf(builder, "%s_count%s %d\n", name, prometheusLabels(tenant, labels...), histogram.Count)
+}
@@ -0,0 +1,572 @@
+/*
+Leader Election Module
+======================
+
+고가용성 대기 인스턴스용 리더 선출 (-ha-lock)
+
+집계 서버 두 대가 같은 로그를 함께 수집하고, 잠금을 가진 리더 인스턴스만 알림 채널로 전송합니다.
+대기(standby) 인스턴스도 파싱/분석/저장/최근 알림 기록은 그대로 수행하므로, 리더가 멈추면 잠금
+TTL 안에 대기 인스턴스가 리더가 되어 공백 없이 알림을 이어서 보냅니다.
+
+주요 기능:
+- 파일 잠금 (file:/경로): 같은 호스트 또는 잠금을 지원하는 공유 파일 시스템, 프로세스가 죽으면 OS 가 즉시 해제
+- etcd (etcd://호스트:2379/키): v3 JSON 게이트웨이, TTL 리스에 묶인 키를 create_revision=0 트랜잭션으로 생성
+- Consul (consul://호스트:8500/키): TTL 세션 + KV acquire, CONSUL_HTTP_TOKEN 환경변수 토큰 사용
+- https 는 etcd+https:// / consul+https://, 키를 생략하면 syslog-monitor/leader
+- TTL/3 마다 잠금 갱신, 백엔드 오류 시 리더는 마지막 갱신 후 TTL 이 지날 때까지만 유지 (이후 대기로 전환)
+- 역할 변경 로그, 관리 API 상태(ha)에 역할/현재 리더 표시
+*/
+package main
+
+import (
+	"bytes"           // 요청 본문
+	"encoding/base64" // etcd 키/값 인코딩
+	"encoding/json"   // API 요청/응답
+	"fmt"             // 형식화된 I/O
+	"io"              // 응답 본문
+	"net/http"        // etcd/Consul REST API
+	"net/url"         // 백엔드 주소 파싱
+	"os"              // 잠금 파일, 호스트 이름
+	"strconv"         // 리스 TTL 변환
+	"strings"         // 문자열 처리
+	"sync"            // 상태 보호
+	"time"            // 갱신 주기
+)
+
+// 리더 선출 관련 상수
+const (
+	DefaultHATTL     = 15 * time.Second        // 기본 잠금 TTL (리더가 멈춘 뒤 대기 인스턴스가 넘겨받기까지 최대 시간)
+	DefaultHALockKey = "syslog-monitor/leader" // etcd/Consul 주소에 키가 없을 때 사용
+	HARoleLeader     = "leader"
+	HARoleStandby    = "standby"
+
+	haMinTTL         = 3 * time.Second
+	haConsulMinTTL   = 10 * time.Second // Consul 세션 TTL 하한
+	haRequestTimeout = 5 * time.Second
+)
+
+// LeaderLock 리더 잠금 백엔드
+type LeaderLock interface {
+	// TryAcquire 잠금을 얻거나 갱신 (leader 가 false 면 holder 는 현재 리더 ID, 알 수 없으면 빈 문자열)
+	TryAcquire() (leader bool, holder string, err error)
+	// Release 가진 잠금 해제 (종료 시 대기 인스턴스가 TTL 을 기다리지 않고 넘겨받도록)
+	Release() error
+	// Describe 로그/상태 표시용 백엔드 설명
+	Describe() string
+}
+
+// LeaderStatus 관리 API 상태에 표시하는 리더 선출 상태
+type LeaderStatus struct {
+	Backend string    `json:"backend"`
+	ID      string    `json:"id"`
+	Role    string    `json:"role"`
+	Leader  string    `json:"leader,omitempty"` // 현재 리더 ID (알 수 없으면 생략)
+	Since   time.Time `json:"since"`            // 현재 역할이 된 시각
+	Error   string    `json:"error,omitempty"`  // 마지막 백엔드 오류
+}
+
+// LeaderElection 잠금 백엔드로 리더 여부를 주기적으로 판단
+type LeaderElection struct {
+	lock      LeaderLock
+	id        string
+	ttl       time.Duration
+	leader    bool
+	holder    string
+	since     time.Time
+	renewed   time.Time // 리더로서 마지막으로 잠금을 갱신한 시각
+	checked   bool      // 첫 판단을 마쳤는지 (시작 역할 로그용)
+	started   bool      // 갱신 고루틴 실행 여부
+	lastError string
+	mutex     sync.RWMutex
+	stop      chan struct{}
+	done      chan struct{}
+	logger    Logger
+}
+
+// NewLeaderElection spec (file:/경로, etcd://호스트/키, consul://호스트/키) 백엔드로 리더 선출 생성
+func NewLeaderElection(spec, id string, ttl time.Duration, logger Logger) (*LeaderElection, error) {
+	if ttl < haMinTTL {
+		return nil, fmt.Errorf("HA lock TTL must be at least %v, got %v", haMinTTL, ttl)
+	}
+	if id == "" {
+		id = DefaultHAInstanceID()
+	}
+	lock, err := ParseLeaderLock(spec, id, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &LeaderElection{
+		lock:   lock,
+		id:     id,
+		ttl:    ttl,
+		since:  time.Now(),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+		logger: logger,
+	}, nil
+}
+
+// DefaultHAInstanceID 기본 인스턴스 ID (호스트 이름-PID)
+func DefaultHAInstanceID() string {
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "syslog-monitor"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// ParseLeaderLock 잠금 백엔드 지정 파싱 (접두어가 없으면 파일 경로)
+func ParseLeaderLock(spec, id string, ttl time.Duration) (LeaderLock, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("HA lock is empty")
+	}
+	scheme := ""
+	if i := strings.Index(spec, "://"); i > 0 {
+		scheme = strings.ToLower(spec[:i])
+	}
+	switch scheme {
+	case "", "file":
+		return newFileLeaderLock(strings.TrimPrefix(strings.TrimPrefix(spec, "file://"), "file:"), id), nil
+	case "etcd", "etcd+http", "etcd+https":
+		endpoint, key, err := parseHAEndpoint(spec)
+		if err != nil {
+			return nil, err
+		}
+		return &etcdLeaderLock{endpoint: endpoint, key: key, id: id, ttl: ttl, client: &http.Client{Timeout: haRequestTimeout}}, nil
+	case "consul", "consul+http", "consul+https":
+		if ttl < haConsulMinTTL {
+			return nil, fmt.Errorf("Consul session TTL must be at least %v, got %v", haConsulMinTTL, ttl)
+		}
+		endpoint, key, err := parseHAEndpoint(spec)
+		if err != nil {
+			return nil, err
+		}
+		return &consulLeaderLock{endpoint: endpoint, key: key, id: id, ttl: ttl, token: os.Getenv("CONSUL_HTTP_TOKEN"), client: &http.Client{Timeout: haRequestTimeout}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported HA lock backend %q (use file:/path, etcd://host:2379/key or consul://host:8500/key)", scheme)
+	}
+}
+
+// parseHAEndpoint etcd/consul 주소에서 HTTP 기본 주소와 키 분리
+func parseHAEndpoint(spec string) (string, string, error) {
+	u, err := url.Parse(spec)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid HA lock address %q: %v", spec, err)
+	}
+	if u.Host == "" {
+		return "", "", fmt.Errorf("HA lock address %q has no host", spec)
+	}
+	protocol := "http"
+	if strings.HasSuffix(u.Scheme, "+https") {
+		protocol = "https"
+	}
+	key := strings.Trim(u.Path, "/")
+	if key == "" {
+		key = DefaultHALockKey
+	}
+	return protocol + "://" + u.Host, key, nil
+}
+
+// ID 이 인스턴스 ID
+func (le *LeaderElection) ID() string {
+	return le.id
+}
+
+// Start 첫 판단을 바로 수행하고 (알림 전에 역할 확정) 주기적 갱신 시작
+func (le *LeaderElection) Start() {
+	le.check()
+	le.started = true
+	go le.run()
+}
+
+// run TTL/3 마다 잠금 갱신
+func (le *LeaderElection) run() {
+	defer close(le.done)
+	ticker := time.NewTicker(le.ttl / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			le.check()
+		case <-le.stop:
+			return
+		}
+	}
+}
+
+// check 잠금을 얻거나 갱신하고 역할이 바뀌면 로그 기록
+func (le *LeaderElection) check() {
+	leader, holder, err := le.lock.TryAcquire()
+	now := time.Now()
+
+	le.mutex.Lock()
+	if err != nil {
+		le.lastError = err.Error()
+		// 일시적인 백엔드 오류로 알림이 끊기지 않도록 리더는 마지막 갱신 후 TTL 동안 유지
+		// (TTL 이 지나면 다른 인스턴스가 잠금을 얻을 수 있으므로 중복 전송을 막기 위해 대기로 전환)
+		if le.leader && now.Sub(le.renewed) < le.ttl {
+			le.mutex.Unlock()
+			le.logger.Errorf("❌ HA lock renewal failed (%s), staying leader until the lease expires: %v", le.lock.Describe(), err)
+			return
+		}
+		leader, holder = false, ""
+	} else {
+		le.lastError = ""
+	}
+	if leader {
+		le.renewed = now
+		holder = le.id
+	}
+	changed := leader != le.leader || !le.checked
+	if changed {
+		le.since = now
+	}
+	le.leader = leader
+	le.holder = holder
+	le.checked = true
+	le.mutex.Unlock()
+
+	switch {
+	case changed && leader:
+		le.logger.Infof("👑 This instance (%s) is now the HA leader (%s), sending notifications", le.id, le.lock.Describe())
+	case changed:
+		if holder == "" {
+			holder = "unknown"
+		}
+		if err != nil {
+			le.logger.Errorf("❌ HA lock unavailable (%s): %v", le.lock.Describe(), err)
+		}
+		le.logger.Infof("🕒 This instance (%s) is on standby (leader: %s), ingesting without sending notifications", le.id, holder)
+	}
+}
+
+// IsLeader 이 인스턴스가 리더인지 (알림 전송 여부)
+func (le *LeaderElection) IsLeader() bool {
+	le.mutex.RLock()
+	defer le.mutex.RUnlock()
+	return le.leader
+}
+
+// Status 관리 API 상태용 리더 선출 상태
+func (le *LeaderElection) Status() *LeaderStatus {
+	le.mutex.RLock()
+	defer le.mutex.RUnlock()
+	role := HARoleStandby
+	if le.leader {
+		role = HARoleLeader
+	}
+	return &LeaderStatus{
+		Backend: le.lock.Describe(),
+		ID:      le.id,
+		Role:    role,
+		Leader:  le.holder,
+		Since:   le.since,
+		Error:   le.lastError,
+	}
+}
+
+// Stop 갱신을 멈추고 잠금 해제 (대기 인스턴스가 바로 넘겨받음)
+func (le *LeaderElection) Stop() {
+	if !le.started {
+		return
+	}
+	le.started = false
+	close(le.stop)
+	<-le.done
+
+	le.mutex.Lock()
+	wasLeader := le.leader
+	le.leader = false
+	le.mutex.Unlock()
+	if err := le.lock.Release(); err != nil {
+		le.logger.Errorf("❌ Failed to release HA lock (%s): %v", le.lock.Describe(), err)
+	} else if wasLeader {
+		le.logger.Infof("👑 HA leadership released (%s)", le.lock.Describe())
+	}
+}
+
+// fileLeaderLock 잠금 파일의 배타적 잠금 (리더는 파일을 열어 둔 채 잠금 유지, 파일 내용은 리더 ID)
+type fileLeaderLock struct {
+	path string
+	id   string
+	file *os.File // 잠금을 가진 동안 열린 파일 (nil 이면 대기)
+}
+
+// newFileLeaderLock 파일 잠금 백엔드 생성
+func newFileLeaderLock(path, id string) *fileLeaderLock {
+	return &fileLeaderLock{path: expandHomePath(path), id: id}
+}
+
+// TryAcquire 잠금 파일 잠금 시도 (이미 가진 잠금은 프로세스가 살아 있는 동안 유지되므로 갱신 불필요)
+func (fl *fileLeaderLock) TryAcquire() (bool, string, error) {
+	if fl.file != nil {
+		return true, fl.id, nil
+	}
+	file, err := os.OpenFile(fl.path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return false, "", err
+	}
+	locked, err := tryLockFile(file)
+	if err != nil || !locked {
+		holder := ""
+		if data, readErr := io.ReadAll(io.LimitReader(file, 256)); readErr == nil {
+			holder = strings.TrimSpace(string(data))
+		}
+		file.Close()
+		return false, holder, err
+	}
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(fl.id+"\n"), 0)
+		file.Sync()
+	}
+	fl.file = file
+	return true, fl.id, nil
+}
+
+// Release 잠금 해제 후 파일 닫기
+func (fl *fileLeaderLock) Release() error {
+	if fl.file == nil {
+		return nil
+	}
+	file := fl.file
+	fl.file = nil
+	file.Truncate(0)
+	err := unlockFile(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Describe 백엔드 설명
+func (fl *fileLeaderLock) Describe() string {
+	return "file:" + fl.path
+}
+
+// etcdLeaderLock etcd v3 JSON 게이트웨이 리스 기반 잠금
+type etcdLeaderLock struct {
+	endpoint string
+	key      string
+	id       string
+	ttl      time.Duration
+	lease    string // 현재 리스 ID (비어 있으면 새로 발급)
+	client   *http.Client
+}
+
+// etcdKeyValue etcd 응답의 키/값 (base64)
+type etcdKeyValue struct {
+	Value string `json:"value"`
+	Lease string `json:"lease"`
+}
+
+// TryAcquire 리스 갱신 후 키가 없으면 리스에 묶어 생성, 있으면 소유자 확인
+func (el *etcdLeaderLock) TryAcquire() (bool, string, error) {
+	if err := el.keepAlive(); err != nil {
+		return false, "", err
+	}
+	key := base64.StdEncoding.EncodeToString([]byte(el.key))
+	request := map[string]interface{}{
+		"compare": []map[string]interface{}{{"key": key, "result": "EQUAL", "target": "CREATE", "create_revision": "0"}},
+		"success": []map[string]interface{}{{"request_put": map[string]interface{}{
+			"key": key, "value": base64.StdEncoding.EncodeToString([]byte(el.id)), "lease": el.lease,
+		}}},
+		"failure": []map[string]interface{}{{"request_range": map[string]interface{}{"key": key}}},
+	}
+	var response struct {
+		Succeeded bool `json:"succeeded"`
+		Responses []struct {
+			ResponseRange struct {
+				Kvs []etcdKeyValue `json:"kvs"`
+			} `json:"response_range"`
+		} `json:"responses"`
+	}
+	if err := el.call("/v3/kv/txn", request, &response); err != nil {
+		return false, "", err
+	}
+	if response.Succeeded {
+		return true, el.id, nil
+	}
+	for _, r := range response.Responses {
+		for _, kv := range r.ResponseRange.Kvs {
+			value, _ := base64.StdEncoding.DecodeString(kv.Value)
+			if kv.Lease == el.lease {
+				return true, el.id, nil // 이미 이 리스로 가진 잠금
+			}
+			return false, string(value), nil
+		}
+	}
+	return false, "", nil
+}
+
+// keepAlive 리스 갱신 (만료되었거나 없으면 새로 발급)
+func (el *etcdLeaderLock) keepAlive() error {
+	if el.lease != "" {
+		var response struct {
+			Result struct {
+				TTL string `json:"TTL"`
+			} `json:"result"`
+		}
+		if err := el.call("/v3/lease/keepalive", map[string]string{"ID": el.lease}, &response); err != nil {
+			return err
+		}
+		if ttl, _ := strconv.ParseInt(response.Result.TTL, 10, 64); ttl > 0 {
+			return nil
+		}
+		el.lease = "" // 만료된 리스 (키도 함께 삭제됨)
+	}
+	var response struct {
+		ID    string `json:"ID"`
+		Error string `json:"error"`
+	}
+	if err := el.call("/v3/lease/grant", map[string]string{"TTL": strconv.Itoa(int(el.ttl.Seconds()))}, &response); err != nil {
+		return err
+	}
+	if response.ID == "" {
+		return fmt.Errorf("etcd lease grant failed: %s", response.Error)
+	}
+	el.lease = response.ID
+	return nil
+}
+
+// Release 리스 취소 (리스에 묶인 키 삭제)
+func (el *etcdLeaderLock) Release() error {
+	if el.lease == "" {
+		return nil
+	}
+	lease := el.lease
+	el.lease = ""
+	return el.call("/v3/lease/revoke", map[string]string{"ID": lease}, nil)
+}
+
+// Describe 백엔드 설명
+func (el *etcdLeaderLock) Describe() string {
+	return "etcd " + el.endpoint + "/" + el.key
+}
+
+// call etcd JSON 게이트웨이 POST 요청
+func (el *etcdLeaderLock) call(path string, request, response interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	resp, err := el.client.Post(el.endpoint+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("etcd %s: HTTP %d: %s", path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if response == nil {
+		return nil
+	}
+	return json.Unmarshal(data, response)
+}
+
+// consulLeaderLock Consul 세션 + KV acquire 기반 잠금
+type consulLeaderLock struct {
+	endpoint string
+	key      string
+	id       string
+	ttl      time.Duration
+	token    string
+	session  string // 현재 세션 ID (비어 있으면 새로 생성)
+	client   *http.Client
+}
+
+// TryAcquire 세션 갱신 후 키 acquire, 실패하면 현재 값(리더 ID) 조회
+func (cl *consulLeaderLock) TryAcquire() (bool, string, error) {
+	if err := cl.renewSession(); err != nil {
+		return false, "", err
+	}
+	status, data, err := cl.call(http.MethodPut, "/v1/kv/"+cl.key+"?acquire="+cl.session, []byte(cl.id))
+	if err != nil {
+		return false, "", err
+	}
+	if status != http.StatusOK {
+		return false, "", fmt.Errorf("consul acquire: HTTP %d: %s", status, strings.TrimSpace(string(data)))
+	}
+	if strings.TrimSpace(string(data)) == "true" {
+		return true, cl.id, nil
+	}
+	status, data, err = cl.call(http.MethodGet, "/v1/kv/"+cl.key+"?raw", nil)
+	if err != nil || status != http.StatusOK {
+		return false, "", nil // 리더는 알 수 없지만 잠금은 얻지 못함
+	}
+	return false, strings.TrimSpace(string(data)), nil
+}
+
+// renewSession 세션 갱신 (무효화되었거나 없으면 새로 생성)
+func (cl *consulLeaderLock) renewSession() error {
+	if cl.session != "" {
+		status, data, err := cl.call(http.MethodPut, "/v1/session/renew/"+cl.session, nil)
+		if err != nil {
+			return err
+		}
+		switch status {
+		case http.StatusOK:
+			return nil
+		case http.StatusNotFound:
+			cl.session = "" // 만료된 세션 (키도 함께 삭제됨)
+		default:
+			return fmt.Errorf("consul session renew: HTTP %d: %s", status, strings.TrimSpace(string(data)))
+		}
+	}
+	request, _ := json.Marshal(map[string]string{
+		"Name":      "syslog-monitor leader " + cl.id,
+		"TTL":       fmt.Sprintf("%ds", int(cl.ttl.Seconds())),
+		"Behavior":  "delete",
+		"LockDelay": "0s",
+	})
+	status, data, err := cl.call(http.MethodPut, "/v1/session/create", request)
+	if err != nil {
+		return err
+	}
+	var response struct {
+		ID string `json:"ID"`
+	}
+	if status != http.StatusOK || json.Unmarshal(data, &response) != nil || response.ID == "" {
+		return fmt.Errorf("consul session create: HTTP %d: %s", status, strings.TrimSpace(string(data)))
+	}
+	cl.session = response.ID
+	return nil
+}
+
+// Release 키 해제 후 세션 삭제
+func (cl *consulLeaderLock) Release() error {
+	if cl.session == "" {
+		return nil
+	}
+	session := cl.session
+	cl.session = ""
+	if _, _, err := cl.call(http.MethodPut, "/v1/kv/"+cl.key+"?release="+session, nil); err != nil {
+		return err
+	}
+	_, _, err := cl.call(http.MethodPut, "/v1/session/destroy/"+session, nil)
+	return err
+}
+
+// Describe 백엔드 설명
+func (cl *consulLeaderLock) Describe() string {
+	return "consul " + cl.endpoint + "/" + cl.key
+}
+
+// call Consul HTTP API 요청 (상태 코드와 본문 반환)
+func (cl *consulLeaderLock) call(method, path string, body []byte) (int, []byte, error) {
+	req, err := http.NewRequest(method, cl.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	if cl.token != "" {
+		req.Header.Set("X-Consul-Token", cl.token)
+	}
+	resp, err := cl.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, data, nil
+}
@@ -0,0 +1,30 @@
+//go:build !windows
+
+/*
+Leader Lock (Unix)
+==================
+
+리더 선출 파일 잠금의 Unix 구현 (flock)
+
+잠금은 열린 파일에 묶여 있어 프로세스가 비정상 종료해도 OS 가 즉시 해제합니다.
+*/
+package main
+
+import (
+	"os"      // 잠금 파일
+	"syscall" // flock
+)
+
+// tryLockFile 배타적 잠금을 기다리지 않고 시도 (다른 프로세스가 가지고 있으면 false)
+func tryLockFile(file *os.File) (bool, error) {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// unlockFile 잠금 해제
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,37 @@
+//go:build windows
+
+/*
+Leader Lock (Windows)
+=====================
+
+리더 선출 파일 잠금의 Windows 구현 (LockFileEx)
+
+파일 끝 너머의 바이트 범위를 잠가 대기 인스턴스가 파일 내용(리더 ID)은 읽을 수 있도록 합니다.
+잠금은 핸들에 묶여 있어 프로세스가 비정상 종료해도 OS 가 해제합니다.
+*/
+package main
+
+import (
+	"os" // 잠금 파일
+
+	"golang.org/x/sys/windows" // LockFileEx
+)
+
+// leaderLockOffset 잠그는 바이트 범위 시작 위치 (파일 내용과 겹치지 않도록 큰 값)
+const leaderLockOffset = 0x7fffffff
+
+// tryLockFile 배타적 잠금을 기다리지 않고 시도 (다른 프로세스가 가지고 있으면 false)
+func tryLockFile(file *os.File) (bool, error) {
+	overlapped := &windows.Overlapped{Offset: leaderLockOffset}
+	err := windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, overlapped)
+	if err == windows.ERROR_LOCK_VIOLATION {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// unlockFile 잠금 해제
+func unlockFile(file *os.File) error {
+	overlapped := &windows.Overlapped{Offset: leaderLockOffset}
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, overlapped)
+}
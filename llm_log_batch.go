@@ -0,0 +1,236 @@
+/*
+LLM Log Batch Module
+====================
+
+AI 이상 탐지 로그를 호스트/서비스별로 묶어 한 번의 LLM 호출로 분석 (ai_analysis.scheduler.log_analysis)
+
+짧은 시간에 같은 서비스에서 이상 로그가 여러 줄 나오면 줄마다 API 를 호출하지 않고 묶음 하나의
+프롬프트로 보내 호출 수와 토큰을 줄이고, 로그 사이의 연관성까지 함께 분석합니다.
+
+주요 기능:
+- 이상 점수가 알림 임계값 이상인 로그를 호스트/서비스별 묶음에 추가 (같은 로그는 한 번만, 반복 횟수 기록)
+- batch_seconds(기본 60초)가 지나거나 batch_max_lines(기본 20줄)가 차면 묶음 분석
+- 분석 결과는 AI 알림 (유형 ai, 필드 analysis=llm) 으로 전송
+- 호출 한도에 걸리거나 API 가 설정되지 않았으면 기본 분석 결과로 알림
+- 테넌트 모니터는 llm_tokens_per_day 한도를 묶음 추가 전과 API 호출 전에 확인 (tenant_quota.go)
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"strings" // 문자열 처리
+	"sync"    // 동시성 제어
+	"time"    // 묶음 대기 시간
+)
+
+// LLM 로그 묶음 기본값
+const (
+	DefaultLLMBatchWindow   = time.Minute
+	DefaultLLMBatchMaxLines = 20
+)
+
+// llmLogBatch 호스트/서비스별 로그 묶음
+type llmLogBatch struct {
+	Host     string
+	Service  string
+	Lines    []string       // 서로 다른 로그 (처음 나온 순)
+	Repeats  map[string]int // 로그별 반복 횟수
+	Total    int            // 묶음에 추가된 전체 로그 수
+	Severity string         // 가장 높은 AI 알림 심각도
+	MaxScore float64        // 가장 높은 이상 점수
+	Rules    []string       // 일치한 이상 패턴 규칙 이름
+	First    time.Time
+	Last     time.Time
+	timer    *time.Timer
+}
+
+// LLMLogBatcher 이상 로그 묶음 분석기
+type LLMLogBatcher struct {
+	enabled  bool
+	window   time.Duration
+	maxLines int
+	batches  map[string]*llmLogBatch
+	dispatch func(Alert)         // 분석 결과 알림 전송
+	quota    *TenantQuotaTracker // 테넌트 LLM 토큰 한도 (nil 이면 제한 없음)
+	logger   Logger
+	mutex    sync.Mutex
+}
+
+// NewLLMLogBatcher 새로운 로그 묶음 분석기 생성 (SetConfig 로 켜기 전에는 로그를 모으지 않음)
+func NewLLMLogBatcher(dispatch func(Alert), logger Logger) *LLMLogBatcher {
+	return &LLMLogBatcher{
+		window:   DefaultLLMBatchWindow,
+		maxLines: DefaultLLMBatchMaxLines,
+		batches:  make(map[string]*llmLogBatch),
+		dispatch: dispatch,
+		logger:   logger,
+	}
+}
+
+// SetConfig 묶음 분석 사용 여부와 대기 시간/최대 줄 수 적용 (모으는 중인 묶음은 기존 시간대로 분석, nil 이면 무시)
+func (lb *LLMLogBatcher) SetConfig(config LLMSchedulerConfig) {
+	if lb == nil {
+		return
+	}
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	lb.enabled = config.LogAnalysis
+	lb.window = DefaultLLMBatchWindow
+	if config.BatchSeconds > 0 {
+		lb.window = time.Duration(config.BatchSeconds) * time.Second
+	}
+	lb.maxLines = DefaultLLMBatchMaxLines
+	if config.BatchMaxLines > 0 {
+		lb.maxLines = config.BatchMaxLines
+	}
+}
+
+// SetQuota 테넌트 사용량 한도 설정 (llm_tokens_per_day, nil 이면 무시)
+func (lb *LLMLogBatcher) SetQuota(quota *TenantQuotaTracker) {
+	if lb == nil {
+		return
+	}
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	lb.quota = quota
+}
+
+// Add 알림 임계값을 넘은 이상 로그를 묶음에 추가 (테넌트 LLM 토큰을 오늘 다 썼으면 추가하지 않음)
+func (lb *LLMLogBatcher) Add(host, service, line string, result *AIAnalysisResult) {
+	if lb == nil || result == nil {
+		return
+	}
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	if !lb.enabled || !lb.quota.AllowLLMLine() {
+		return
+	}
+
+	key := host + "\x00" + service
+	batch := lb.batches[key]
+	if batch == nil {
+		batch = &llmLogBatch{Host: host, Service: service, Repeats: make(map[string]int), First: result.Timestamp}
+		lb.batches[key] = batch
+		batch.timer = time.AfterFunc(lb.window, func() { lb.flush(key, batch) })
+	}
+	if batch.Repeats[line] == 0 {
+		batch.Lines = append(batch.Lines, line)
+	}
+	batch.Repeats[line]++
+	batch.Total++
+	batch.Last = result.Timestamp
+	if result.AnomalyScore > batch.MaxScore {
+		batch.MaxScore = result.AnomalyScore
+	}
+	if batch.Severity != AlertSeverityCritical {
+		batch.Severity = aiAlertSeverity(result)
+	}
+	for _, rule := range result.MatchedRules {
+		if !containsString(batch.Rules, rule.Name) {
+			batch.Rules = append(batch.Rules, rule.Name)
+		}
+	}
+
+	if len(batch.Lines) >= lb.maxLines {
+		batch.timer.Stop()
+		delete(lb.batches, key)
+		go lb.analyze(batch)
+	}
+}
+
+// flush 대기 시간이 지난 묶음 분석 (최대 줄 수로 먼저 분석된 묶음은 무시)
+func (lb *LLMLogBatcher) flush(key string, batch *llmLogBatch) {
+	lb.mutex.Lock()
+	if lb.batches[key] != batch {
+		lb.mutex.Unlock()
+		return
+	}
+	delete(lb.batches, key)
+	lb.mutex.Unlock()
+	lb.analyze(batch)
+}
+
+// analyze 묶음을 현재 LLM 백엔드로 분석하고 알림 전송 (API 오류나 테넌트 토큰 한도 초과면 기록만)
+func (lb *LLMLogBatcher) analyze(batch *llmLogBatch) {
+	provider := currentLLMProvider()
+	if provider == nil {
+		return
+	}
+	// 테넌트 토큰 한도는 스케줄러로 보내기 전에 확인 (프롬프트는 로그 줄로 추정, 응답은 받은 뒤 기록)
+	lb.mutex.Lock()
+	quota := lb.quota
+	lb.mutex.Unlock()
+	if !quota.AllowLLMTokens(estimateLLMTokens(strings.Join(batch.Lines, "\n")), batch.Total) {
+		lb.logger.Infof("🚫 LLM token quota exceeded, %s/%s batch (%d lines) not analyzed", batch.Host, batch.Service, batch.Total)
+		return
+	}
+	context := map[string]string{
+		"host":      batch.Host,
+		"service":   batch.Service,
+		"count":     fmt.Sprintf("%d", batch.Total),
+		"period":    fmt.Sprintf("%s ~ %s", batch.First.Format("15:04:05"), batch.Last.Format("15:04:05")),
+		"max_score": fmt.Sprintf("%.1f", batch.MaxScore),
+	}
+	if len(batch.Rules) > 0 {
+		context["rules"] = strings.Join(batch.Rules, ", ")
+	}
+
+	analysis, err := provider.AnalyzeLogBatch(batch.Lines, context)
+	if err != nil {
+		lb.logger.Errorf("LLM log analysis failed for %s/%s (%d lines): %v", batch.Host, batch.Service, len(batch.Lines), err)
+		return
+	}
+	quota.AddLLMTokens(estimateLLMTokens(analysis))
+	lb.dispatch(llmLogAnalysisAlert(batch, analysis, provider))
+}
+
+// llmLogAnalysisAlert 로그 묶음 LLM 분석 알림
+func llmLogAnalysisAlert(batch *llmLogBatch, analysis string, provider LLMProvider) Alert {
+	service := batch.Service
+	if service == "" {
+		service = "-"
+	}
+
+	var lines strings.Builder
+	for _, line := range batch.Lines {
+		if repeats := batch.Repeats[line]; repeats > 1 {
+			fmt.Fprintf(&lines, "(%d회) ", repeats)
+		}
+		lines.WriteString(line)
+		lines.WriteString("\n")
+	}
+
+	fields := []AlertField{
+		{Label: "📍 호스트", Value: batch.Host, Short: true},
+		{Label: "🧩 서비스", Value: service, Short: true},
+		{Label: "📄 로그", Value: fmt.Sprintf("%d건 (서로 다른 로그 %d줄)", batch.Total, len(batch.Lines)), Short: true},
+		{Label: "📊 최고 이상 점수", Value: fmt.Sprintf("%.1f/%.0f", batch.MaxScore, MaxAnomalyScore), Short: true},
+		{Label: "🕐 기간", Value: fmt.Sprintf("%s ~ %s", batch.First.Format("2006-01-02 15:04:05"), batch.Last.Format("15:04:05")), Short: true},
+		{Label: "🧠 AI 백엔드", Value: fmt.Sprintf("%s (%s)", provider.Name(), provider.Model()), Short: true},
+	}
+	if len(batch.Rules) > 0 {
+		fields = append(fields, AlertField{Label: "📐 일치한 규칙", Value: strings.Join(batch.Rules, ", ")})
+	}
+
+	return Alert{
+		Type:     AlertTypeAI,
+		Severity: batch.Severity,
+		Title:    fmt.Sprintf("[%s AI 로그 분석] %s - %s", AppName, batch.Host, service),
+		Headline: fmt.Sprintf("🧠 %s 의 이상 로그 %d건을 묶어 분석했습니다", service, batch.Total),
+		Sections: []AlertSection{
+			{Fields: fields, Summary: true},
+			{Title: "🧠 AI 분석", Text: analysis, Summary: true},
+			{Title: "📋 분석한 로그", Text: lines.String()},
+		},
+		Host:   batch.Host,
+		Thread: alertThreadKey(AlertTypeAI, batch.Host, "llm", batch.Service),
+		Fields: map[string]string{
+			"analysis": "llm",
+			"service":  batch.Service,
+			"lines":    fmt.Sprintf("%d", batch.Total),
+			"provider": provider.Name(),
+			"model":    provider.Model(),
+		},
+		Timestamp: batch.Last,
+	}
+}
@@ -0,0 +1,679 @@
+/*
+LLM Provider Module
+===================
+
+# AI 전문가 분석(시스템 진단, 로그 패턴 분석, 보안 위협 분석)을 수행하는 LLM 백엔드 공통 부분
+
+설정 파일 ai_analysis.provider 로 백엔드를 고르며, 프롬프트 생성(템플릿, 가림)과 API 미설정 시
+기본 분석, 구조화 진단 검증은 모든 백엔드가 같은 코드를 사용합니다. 백엔드는 프롬프트를 보내고
+응답 텍스트를 받는 호출만 구현합니다.
+
+주요 기능:
+  - LLMProvider 인터페이스 (AnalyzeSystemDiagnosis, AnalyzeLogPattern, AnalyzeLogBatch, AnalyzeSecurityThreat)
+  - 백엔드: gemini (기본, gemini_service.go), openai (OpenAI 호환 API, openai_provider.go),
+    anthropic (anthropic_provider.go), ollama (로컬 모델, ollama_provider.go)
+  - 백엔드별 기본 모델/엔드포인트/API 키 환경 변수 (GEMINI_API_KEY, OPENAI_API_KEY, ANTHROPIC_API_KEY)
+  - 구조화 진단 스키마를 백엔드 형식(JSON Schema)으로 변환
+  - 설정 재로드 시 백엔드 교체 (분석 중인 호출은 이전 백엔드로 끝남)
+  - API 호출은 공유 스케줄러(llm_scheduler.go)를 거치며 호출 한도에 걸리면 기본 분석으로 대체
+*/
+package main
+
+import (
+	"bytes"         // 요청 본문
+	"encoding/json" // 요청/응답 인코딩
+	"errors"        // 호출 한도 오류 확인
+	"fmt"           // 형식화된 I/O
+	"io"            // 응답 읽기
+	"net/http"      // API 호출
+	"strings"       // 문자열 처리
+	"sync"          // 동기화 (설정/프롬프트 교체)
+	"time"          // 요청 타임아웃
+)
+
+// LLM 백엔드 이름 (설정 파일 ai_analysis.provider)
+const (
+	LLMProviderGemini    = "gemini"
+	LLMProviderOpenAI    = "openai"
+	LLMProviderAnthropic = "anthropic"
+	LLMProviderOllama    = "ollama"
+)
+
+// llmProviderNames 지원하는 백엔드
+var llmProviderNames = []string{LLMProviderGemini, LLMProviderOpenAI, LLMProviderAnthropic, LLMProviderOllama}
+
+// llmProviderDefault 백엔드별 기본값
+type llmProviderDefault struct {
+	Model   string
+	BaseURL string
+	KeyEnv  string        // API 키 환경 변수 (설정 파일 api_key 가 비어 있을 때, 없으면 키 불필요)
+	Timeout time.Duration // 요청 타임아웃 (로컬 모델은 응답이 느림)
+}
+
+// llmProviderDefaults 백엔드별 기본 모델, 엔드포인트, API 키 환경 변수
+var llmProviderDefaults = map[string]llmProviderDefault{
+	LLMProviderGemini:    {Model: "gemini-1.5-flash", BaseURL: "https://generativelanguage.googleapis.com/v1beta/models", KeyEnv: "GEMINI_API_KEY", Timeout: 30 * time.Second},
+	LLMProviderOpenAI:    {Model: "gpt-4o-mini", BaseURL: "https://api.openai.com/v1", KeyEnv: "OPENAI_API_KEY", Timeout: 30 * time.Second},
+	LLMProviderAnthropic: {Model: "claude-3-5-haiku-latest", BaseURL: "https://api.anthropic.com/v1", KeyEnv: "ANTHROPIC_API_KEY", Timeout: 30 * time.Second},
+	LLMProviderOllama:    {Model: "llama3.1", BaseURL: "http://localhost:11434", Timeout: 2 * time.Minute},
+}
+
+// LLMConfig LLM 백엔드 설정 (설정 파일 ai_analysis 에서 만듦)
+type LLMConfig struct {
+	Provider    string            `json:"provider"` // gemini, openai, anthropic, ollama (비우면 gemini)
+	APIKey      string            `json:"api_key"`
+	Model       string            `json:"model"`
+	BaseURL     string            `json:"base_url"` // API 엔드포인트 (OpenAI 호환 서버, 프록시, 원격 Ollama)
+	MaxTokens   int               `json:"max_tokens"`
+	Temperature float64           `json:"temperature"`
+	Enabled     bool              `json:"enabled"`
+	Redaction   AIRedactionConfig `json:"redaction"` // 프롬프트 가림 설정 (ai_analysis.redaction)
+}
+
+// normalizeLLMProvider 백엔드 이름 확인 (비우면 gemini)
+func normalizeLLMProvider(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return LLMProviderGemini, nil
+	}
+	if !containsString(llmProviderNames, name) {
+		return "", fmt.Errorf("unknown AI provider %q (expected %s)", name, strings.Join(llmProviderNames, ", "))
+	}
+	return name, nil
+}
+
+// withDefaults 백엔드 기본 모델/엔드포인트를 채운 설정
+func (config LLMConfig) withDefaults() (*LLMConfig, error) {
+	provider, err := normalizeLLMProvider(config.Provider)
+	if err != nil {
+		return nil, err
+	}
+	defaults := llmProviderDefaults[provider]
+	config.Provider = provider
+	if config.Model == "" {
+		config.Model = defaults.Model
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = defaults.BaseURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.MaxTokens <= 0 {
+		config.MaxTokens = 2048
+	}
+	return &config, nil
+}
+
+// configured API 를 호출할 수 있는지 (비활성화되었거나 필요한 API 키가 없으면 기본 분석)
+func (config *LLMConfig) configured() bool {
+	if !config.Enabled {
+		return false
+	}
+	defaults := llmProviderDefaults[config.Provider]
+	switch {
+	case config.APIKey != "":
+		return true
+	case config.Provider == LLMProviderOllama:
+		return true
+	case config.Provider == LLMProviderOpenAI && config.BaseURL != defaults.BaseURL:
+		return true // 로컬 OpenAI 호환 서버 (vLLM, LM Studio 등) 는 키 없이도 동작
+	default:
+		return false
+	}
+}
+
+// LLMProvider AI 전문가 분석 백엔드
+type LLMProvider interface {
+	Name() string // 백엔드 이름 (gemini, openai, anthropic, ollama)
+	Model() string
+	AnalyzeSystemDiagnosis(metrics SystemMetrics) (string, error)
+	AnalyzeLogPattern(logLine string, context map[string]string) (string, error)
+	AnalyzeLogBatch(lines []string, context map[string]string) (string, error) // 같은 호스트/서비스의 관련 로그 묶음
+	AnalyzeSecurityThreat(threatData map[string]interface{}) (string, error)
+	SummarizeDigest(summary *DigestSummary) (string, error) // 일일 요약 보고서 (daily_digest.go)
+	SetPrompts(prompts *PromptSet)
+	Prompts() *PromptSet
+}
+
+// NewLLMProvider 설정의 백엔드 생성 (알 수 없는 백엔드면 오류)
+func NewLLMProvider(config *LLMConfig) (LLMProvider, error) {
+	resolved, err := config.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	switch resolved.Provider {
+	case LLMProviderOpenAI:
+		return NewOpenAIProvider(resolved), nil
+	case LLMProviderAnthropic:
+		return NewAnthropicProvider(resolved), nil
+	case LLMProviderOllama:
+		return NewOllamaProvider(resolved), nil
+	default:
+		return NewGeminiService(resolved), nil
+	}
+}
+
+// 현재 LLM 백엔드 (시작 시 설정, 설정 재로드 시 교체)
+var (
+	llmProvider      LLMProvider
+	llmProviderMutex sync.RWMutex
+)
+
+// currentLLMProvider 현재 LLM 백엔드 (설정되지 않았으면 nil)
+func currentLLMProvider() LLMProvider {
+	llmProviderMutex.RLock()
+	defer llmProviderMutex.RUnlock()
+	return llmProvider
+}
+
+// setLLMProvider LLM 백엔드 교체
+func setLLMProvider(provider LLMProvider) {
+	llmProviderMutex.Lock()
+	defer llmProviderMutex.Unlock()
+	llmProvider = provider
+}
+
+// llmCompletion 백엔드별 API 호출: 프롬프트를 보내고 응답 텍스트를 받음 (schema 가 있으면 그 형식의 JSON)
+type llmCompletion func(prompt string, schema *GeminiSchema) (string, error)
+
+// llmBase 백엔드 공통 상태와 분석 흐름 (설정, 프롬프트 템플릿, HTTP 클라이언트)
+type llmBase struct {
+	name       string
+	config     *LLMConfig
+	prompts    *PromptSet // 프롬프트 템플릿 (설정 파일 ai_analysis.prompts)
+	httpClient *http.Client
+	mutex      sync.RWMutex
+}
+
+// newLLMBase 백엔드 공통 상태 생성 (config 는 withDefaults 를 거친 설정)
+func newLLMBase(config *LLMConfig) llmBase {
+	prompts, _ := LoadPromptSet(PromptConfig{}) // 내장 프롬프트는 항상 유효
+	return llmBase{
+		name:       config.Provider,
+		config:     config,
+		prompts:    prompts,
+		httpClient: &http.Client{Timeout: llmProviderDefaults[config.Provider].Timeout},
+	}
+}
+
+// Name 백엔드 이름
+func (lb *llmBase) Name() string {
+	return lb.name
+}
+
+// Model 사용하는 모델
+func (lb *llmBase) Model() string {
+	return lb.currentConfig().Model
+}
+
+// currentConfig 현재 설정 반환
+func (lb *llmBase) currentConfig() *LLMConfig {
+	lb.mutex.RLock()
+	defer lb.mutex.RUnlock()
+	return lb.config
+}
+
+// SetPrompts 프롬프트 템플릿 교체 (시작/설정 재로드 시 호출)
+func (lb *llmBase) SetPrompts(prompts *PromptSet) {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	lb.prompts = prompts
+}
+
+// Prompts 현재 프롬프트 템플릿
+func (lb *llmBase) Prompts() *PromptSet {
+	lb.mutex.RLock()
+	defer lb.mutex.RUnlock()
+	return lb.prompts
+}
+
+// analyzeSystemDiagnosis 시스템 진단 분석 (구조화 응답을 보고서 텍스트로 렌더링, 응답이 잘못되면 오류)
+func (lb *llmBase) analyzeSystemDiagnosis(metrics SystemMetrics, complete llmCompletion) (string, error) {
+	if !lb.currentConfig().configured() {
+		return lb.generateBasicDiagnosis(metrics), nil
+	}
+
+	diagnosis, err := lb.diagnoseSystem(metrics, complete)
+	var budget *LLMBudgetError
+	if errors.As(err, &budget) {
+		return lb.generateBasicDiagnosis(metrics) + budget.Notice(), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return formatExpertDiagnosisReport(diagnosis), nil
+}
+
+// analyzeLogPattern 로그 패턴 분석
+func (lb *llmBase) analyzeLogPattern(logLine string, context map[string]string, complete llmCompletion) (string, error) {
+	if !lb.currentConfig().configured() {
+		return lb.generateBasicLogAnalysis(logLine, context), nil
+	}
+
+	prompt, version, err := lb.buildLogAnalysisPrompt(logLine, context)
+	if err != nil {
+		return "", err
+	}
+	analysis, err := lb.call(prompt, nil, complete)
+	var budget *LLMBudgetError
+	if errors.As(err, &budget) {
+		return lb.generateBasicLogAnalysis(logLine, context) + budget.Notice(), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return analysis + formatPromptVersion(version), nil
+}
+
+// analyzeLogBatch 관련 로그 묶음 분석 (한 번의 호출로 여러 줄을 함께 분석)
+func (lb *llmBase) analyzeLogBatch(lines []string, context map[string]string, complete llmCompletion) (string, error) {
+	if !lb.currentConfig().configured() {
+		return lb.generateBasicLogBatchAnalysis(lines), nil
+	}
+
+	prompt, version, err := lb.buildLogBatchPrompt(lines, context)
+	if err != nil {
+		return "", err
+	}
+	analysis, err := lb.call(prompt, nil, complete)
+	var budget *LLMBudgetError
+	if errors.As(err, &budget) {
+		return lb.generateBasicLogBatchAnalysis(lines) + budget.Notice(), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return analysis + formatPromptVersion(version), nil
+}
+
+// analyzeSecurityThreat 보안 위협 분석
+func (lb *llmBase) analyzeSecurityThreat(threatData map[string]interface{}, complete llmCompletion) (string, error) {
+	if !lb.currentConfig().configured() {
+		return lb.generateBasicSecurityAnalysis(threatData), nil
+	}
+
+	prompt, version, err := lb.buildSecurityAnalysisPrompt(threatData)
+	if err != nil {
+		return "", err
+	}
+	analysis, err := lb.call(prompt, nil, complete)
+	var budget *LLMBudgetError
+	if errors.As(err, &budget) {
+		return lb.generateBasicSecurityAnalysis(threatData) + budget.Notice(), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return analysis + formatPromptVersion(version), nil
+}
+
+// summarizeDigest 일일 요약 보고서 작성 (API 가 없거나 한도에 걸리면 집계 기반 기본 요약)
+func (lb *llmBase) summarizeDigest(summary *DigestSummary, complete llmCompletion) (string, error) {
+	if !lb.currentConfig().configured() {
+		return basicDigestNarrative(summary), nil
+	}
+
+	prompt, version, err := lb.buildDigestPrompt(summary)
+	if err != nil {
+		return "", err
+	}
+	narrative, err := lb.call(prompt, nil, complete)
+	var budget *LLMBudgetError
+	if errors.As(err, &budget) {
+		return basicDigestNarrative(summary) + budget.Notice(), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return narrative + formatPromptVersion(version), nil
+}
+
+// call 공유 스케줄러(캐시, 호출 한도)를 거쳐 백엔드 API 호출 (한도에 걸리면 *LLMBudgetError)
+func (lb *llmBase) call(prompt string, schema *GeminiSchema, complete llmCompletion) (string, error) {
+	return llmScheduler.Do(lb.name+"/"+lb.Model(), prompt, schema, complete)
+}
+
+// formatPromptVersion 분석 결과 끝에 붙이는 프롬프트 버전
+func formatPromptVersion(version string) string {
+	return fmt.Sprintf("\n\n🧾 프롬프트 버전: %s", version)
+}
+
+// postJSON JSON 요청을 보내고 200 응답을 response 로 디코딩 (label 은 오류 메시지의 API 이름)
+func (lb *llmBase) postJSON(label, url string, headers map[string]string, request, response interface{}) error {
+	jsonData, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %v", err)
+	}
+	httpRequest, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		httpRequest.Header.Set(name, value)
+	}
+
+	resp, err := lb.httpClient.Do(httpRequest)
+	if err != nil {
+		return fmt.Errorf("failed to call %s API: %v", label, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s API error: %s - %s", label, resp.Status, string(body))
+	}
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+	return nil
+}
+
+// jsonSchema 구조화 응답 스키마를 JSON Schema 로 변환 (OpenAI response_format, Anthropic 도구 입력, Ollama format)
+func (schema *GeminiSchema) jsonSchema() map[string]interface{} {
+	converted := map[string]interface{}{"type": strings.ToLower(schema.Type)}
+	if schema.Description != "" {
+		converted["description"] = schema.Description
+	}
+	if len(schema.Enum) > 0 {
+		converted["enum"] = schema.Enum
+	}
+	if schema.Items != nil {
+		converted["items"] = schema.Items.jsonSchema()
+	}
+	if len(schema.Properties) > 0 {
+		properties := make(map[string]interface{}, len(schema.Properties))
+		for name, property := range schema.Properties {
+			properties[name] = property.jsonSchema()
+		}
+		converted["properties"] = properties
+	}
+	if len(schema.Required) > 0 {
+		converted["required"] = schema.Required
+	}
+	return converted
+}
+
+// buildSystemDiagnosisPrompt 시스템 진단 프롬프트 생성 (가림 설정 시 허용된 항목만, 식별 값은 자리표시자로)
+// 반환: 프롬프트, 프롬프트 버전
+func (lb *llmBase) buildSystemDiagnosisPrompt(metrics SystemMetrics) (string, string, error) {
+	redactor := lb.currentConfig().Redaction.NewRedactor()
+
+	var facts strings.Builder
+	showHostname, showIPs := redactor.Allows(AIFieldHostname), redactor.Allows(AIFieldIPAddresses)
+	if showHostname || showIPs {
+		facts.WriteString("시스템 정보:\n")
+		if showHostname {
+			fmt.Fprintf(&facts, "- 호스트명: %s\n", redactor.Hostname(metrics.IPInfo.Hostname))
+		}
+		if showIPs {
+			fmt.Fprintf(&facts, "- 사설 IP: %s\n", formatIPListForReport(redactor.IPs(metrics.IPInfo.PrivateIPs)))
+			fmt.Fprintf(&facts, "- 공인 IP: %s\n", formatIPListForReport(redactor.IPs(metrics.IPInfo.PublicIPs)))
+		}
+		facts.WriteString("\n")
+	}
+	if redactor.Allows(AIFieldCPU) {
+		fmt.Fprintf(&facts, "CPU 정보:\n- 사용률: %.1f%%\n- 사용자: %.1f%%, 시스템: %.1f%%, 대기: %.1f%%\n- 코어 수: %d개\n\n",
+			metrics.CPU.UsagePercent,
+			metrics.CPU.UserPercent, metrics.CPU.SystemPercent, metrics.CPU.IdlePercent,
+			metrics.CPU.Cores)
+	}
+	if redactor.Allows(AIFieldMemory) {
+		fmt.Fprintf(&facts, "메모리 정보:\n- 사용률: %.1f%%\n- 총 메모리: %.1f GB\n- 사용 중: %.1f GB\n- 사용 가능: %.1f GB\n\n",
+			metrics.Memory.UsagePercent,
+			metrics.Memory.TotalMB/1024,
+			metrics.Memory.UsedMB/1024,
+			metrics.Memory.AvailableMB/1024)
+	}
+	if redactor.Allows(AIFieldTemperature) {
+		fmt.Fprintf(&facts, "온도 정보:\n- CPU 온도: %.1f°C\n\n", metrics.Temperature.CPUTemp)
+	}
+	if redactor.Allows(AIFieldProcesses) {
+		fmt.Fprintf(&facts, "프로세스 정보:\n- 총 프로세스 수: %d개\n", metrics.ProcessCount.Total)
+	}
+	if redactor.Allows(AIFieldConnections) {
+		facts.WriteString(redactor.Text(lb.buildConnectionPromptSection(metrics.Connections)))
+	}
+
+	return lb.Prompts().Render(PromptSystemDiagnosis, PromptData{Facts: facts.String(), Notice: redactor.Notice()})
+}
+
+// buildConnectionPromptSection 진단 프롬프트의 네트워크 연결 섹션 (수집하지 못했으면 빈 문자열)
+func (lb *llmBase) buildConnectionPromptSection(connections ConnectionMetrics) string {
+	summary := formatConnectionSummary(connections, "- ")
+	if summary == "" {
+		return ""
+	}
+	return "\n네트워크 연결 (예상하지 않은 수신 대기 포트는 침해 가능성도 함께 평가):\n" + summary
+}
+
+// buildLogAnalysisPrompt 로그 분석 프롬프트 생성 (반환: 프롬프트, 프롬프트 버전)
+func (lb *llmBase) buildLogAnalysisPrompt(logLine string, context map[string]string) (string, string, error) {
+	redactor := lb.currentConfig().Redaction.NewRedactor()
+	context = redactor.Fields(context) // 사용자/호스트 필드를 먼저 가려 로그 라인의 같은 값도 치환
+	if redactor.Allows(AIFieldLogLine) {
+		logLine = redactor.Text(logLine)
+	} else {
+		logLine = "(보안 정책에 따라 제외)"
+	}
+
+	return lb.Prompts().Render(PromptLogAnalysis, PromptData{LogLine: logLine, Context: context, Notice: redactor.Notice()})
+}
+
+// buildLogBatchPrompt 로그 묶음 분석 프롬프트 생성 (반환: 프롬프트, 프롬프트 버전)
+func (lb *llmBase) buildLogBatchPrompt(lines []string, context map[string]string) (string, string, error) {
+	redactor := lb.currentConfig().Redaction.NewRedactor()
+	context = redactor.Fields(context) // 사용자/호스트 필드를 먼저 가려 로그 라인의 같은 값도 치환
+	redacted := make([]string, len(lines))
+	for i, line := range lines {
+		if redactor.Allows(AIFieldLogLine) {
+			redacted[i] = redactor.Text(line)
+		} else {
+			redacted[i] = "(보안 정책에 따라 제외)"
+		}
+	}
+
+	return lb.Prompts().Render(PromptLogBatchAnalysis, PromptData{Lines: redacted, Context: context, Notice: redactor.Notice()})
+}
+
+// buildSecurityAnalysisPrompt 보안 분석 프롬프트 생성 (반환: 프롬프트, 프롬프트 버전)
+func (lb *llmBase) buildSecurityAnalysisPrompt(threatData map[string]interface{}) (string, string, error) {
+	redactor := lb.currentConfig().Redaction.NewRedactor()
+	threatJSON, _ := json.Marshal(redactor.Values(threatData))
+
+	return lb.Prompts().Render(PromptSecurityAnalysis, PromptData{ThreatData: string(threatJSON), Notice: redactor.Notice()})
+}
+
+// buildDigestPrompt 일일 요약 프롬프트 생성 (가림 설정 시 허용된 항목만, 식별 값은 자리표시자로)
+// 반환: 프롬프트, 프롬프트 버전
+func (lb *llmBase) buildDigestPrompt(summary *DigestSummary) (string, string, error) {
+	redactor := lb.currentConfig().Redaction.NewRedactor()
+	facts := summary.Facts(redactor)
+	return lb.Prompts().Render(PromptDailyDigest, PromptData{Facts: facts, Notice: redactor.Notice()})
+}
+
+// generateBasicDiagnosis 기본 진단 생성 (API 없을 때)
+func (lb *llmBase) generateBasicDiagnosis(metrics SystemMetrics) string {
+	return fmt.Sprintf(`🔬 AI 전문가 진단 결과 (기본 모드)
+=====================
+📊 전반적인 시스템 건강도: %s
+⚠️  발견된 문제점:
+%s
+
+💡 전문가 권장사항:
+==================
+%s
+
+🔧 즉시 실행 가능한 명령어:
+==========================
+• 시스템 상태 확인: `+"`top -l 1`"+`
+• 메모리 사용량: `+"`vm_stat`"+`
+• 디스크 사용량: `+"`df -h`"+`
+• 네트워크 상태: `+"`ifconfig`"+`
+• 프로세스 확인: `+"`ps aux --sort=-%%cpu | head -10`"+`
+
+📈 성능 최적화 팁:
+==================
+• 정기적인 시스템 재부팅으로 메모리 정리
+• 불필요한 시작 프로그램 비활성화
+• 디스크 정리 및 최적화
+• 네트워크 연결 상태 모니터링
+
+💡 AI 백엔드(Gemini/OpenAI/Anthropic/Ollama)를 설정하면 더 정교한 AI 진단을 받을 수 있습니다.`,
+		lb.getOverallHealth(metrics),
+		lb.getIssues(metrics),
+		lb.getRecommendations(metrics))
+}
+
+// generateBasicLogAnalysis 기본 로그 분석 생성
+func (lb *llmBase) generateBasicLogAnalysis(logLine string, context map[string]string) string {
+	return fmt.Sprintf(`🔍 로그 분석 결과 (기본 모드)
+=================
+📊 위협 레벨: %s
+🎯 위협 유형: %s
+💡 분석: 기본 패턴 매칭을 통한 분석
+🚨 권장사항: 로그 모니터링 강화
+
+💡 AI 백엔드(Gemini/OpenAI/Anthropic/Ollama)를 설정하면 더 정교한 AI 분석을 받을 수 있습니다.`,
+		lb.getThreatLevel(logLine),
+		lb.getThreatType(logLine))
+}
+
+// generateBasicLogBatchAnalysis 기본 로그 묶음 분석 생성 (가장 높은 위협 레벨의 로그 기준)
+func (lb *llmBase) generateBasicLogBatchAnalysis(lines []string) string {
+	worst := ""
+	for _, line := range lines {
+		if worst == "" || threatLevelRank(lb.getThreatLevel(line)) > threatLevelRank(lb.getThreatLevel(worst)) {
+			worst = line
+		}
+	}
+	return fmt.Sprintf(`🔍 로그 묶음 분석 결과 (기본 모드)
+=================
+📄 로그: %d줄
+📊 위협 레벨: %s
+🎯 위협 유형: %s
+💡 분석: 기본 패턴 매칭을 통한 분석 (가장 위협 레벨이 높은 로그 기준)
+🚨 권장사항: 로그 모니터링 강화
+
+💡 AI 백엔드(Gemini/OpenAI/Anthropic/Ollama)를 설정하면 더 정교한 AI 분석을 받을 수 있습니다.`,
+		len(lines),
+		lb.getThreatLevel(worst),
+		lb.getThreatType(worst))
+}
+
+// threatLevelRank 기본 분석 위협 레벨 순위 (LOW < MEDIUM < CRITICAL)
+func threatLevelRank(level string) int {
+	switch {
+	case strings.Contains(level, "CRITICAL"):
+		return 2
+	case strings.Contains(level, "MEDIUM"):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// generateBasicSecurityAnalysis 기본 보안 분석 생성
+func (lb *llmBase) generateBasicSecurityAnalysis(threatData map[string]interface{}) string {
+	return fmt.Sprintf(`🚨 보안 위협 분석 (기본 모드)
+=================
+📊 위협 등급: MEDIUM
+🎯 공격 유형: 패턴 기반 감지
+💥 잠재적 영향: 시스템 보안 위험
+🛡️  대응 방안: 즉시 보안팀에 알림
+📈 예방 조치: 로그 모니터링 강화
+
+💡 AI 백엔드(Gemini/OpenAI/Anthropic/Ollama)를 설정하면 더 정교한 AI 분석을 받을 수 있습니다.`)
+}
+
+// getOverallHealth 전반적인 건강도 평가
+func (lb *llmBase) getOverallHealth(metrics SystemMetrics) string {
+	if metrics.CPU.UsagePercent > 80 || metrics.Memory.UsagePercent > 90 || len(metrics.Connections.Unexpected) > 0 {
+		return "🔴 CRITICAL"
+	} else if metrics.CPU.UsagePercent > 60 || metrics.Memory.UsagePercent > 80 || len(metrics.Connections.Spikes) > 0 {
+		return "🟡 FAIR"
+	} else {
+		return "🟢 EXCELLENT"
+	}
+}
+
+// getIssues 발견된 문제점
+func (lb *llmBase) getIssues(metrics SystemMetrics) string {
+	var issues []string
+
+	if metrics.CPU.UsagePercent > 80 {
+		issues = append(issues, "  🔴 CPU 사용률이 매우 높습니다")
+	} else if metrics.CPU.UsagePercent > 60 {
+		issues = append(issues, "  🟡 CPU 사용률이 높습니다")
+	}
+
+	if metrics.Memory.UsagePercent > 90 {
+		issues = append(issues, "  🔴 메모리 사용률이 매우 높습니다")
+	} else if metrics.Memory.UsagePercent > 80 {
+		issues = append(issues, "  🟡 메모리 사용률이 높습니다")
+	}
+
+	connectionIssues, _ := connectionFindings(metrics.Connections)
+	for _, issue := range connectionIssues {
+		issues = append(issues, "  "+issue)
+	}
+
+	if len(issues) == 0 {
+		return "  ✅ 특별한 문제점이 발견되지 않았습니다"
+	}
+
+	return strings.Join(issues, "\n")
+}
+
+// getRecommendations 권장사항
+func (lb *llmBase) getRecommendations(metrics SystemMetrics) string {
+	var recommendations []string
+
+	if metrics.CPU.UsagePercent > 60 {
+		recommendations = append(recommendations, "• CPU 집약적 프로세스 모니터링")
+	} else {
+		recommendations = append(recommendations, "✅ CPU 상태 양호")
+	}
+
+	if metrics.Memory.UsagePercent > 80 {
+		recommendations = append(recommendations, "• 메모리 누수 확인: `ps aux --sort=-%mem`")
+		recommendations = append(recommendations, "• 스왑 사용량 확인: `vm_stat`")
+	} else {
+		recommendations = append(recommendations, "✅ 메모리 상태 양호")
+	}
+
+	_, connectionRecommendations := connectionFindings(metrics.Connections)
+	recommendations = append(recommendations, connectionRecommendations...)
+
+	return strings.Join(recommendations, "\n")
+}
+
+// getThreatLevel 위협 레벨 평가
+func (lb *llmBase) getThreatLevel(logLine string) string {
+	lowLine := strings.ToLower(logLine)
+
+	if strings.Contains(lowLine, "error") || strings.Contains(lowLine, "critical") {
+		return "🔴 CRITICAL"
+	} else if strings.Contains(lowLine, "warning") || strings.Contains(lowLine, "failed") {
+		return "🟡 MEDIUM"
+	} else {
+		return "🟢 LOW"
+	}
+}
+
+// getThreatType 위협 유형 평가
+func (lb *llmBase) getThreatType(logLine string) string {
+	lowLine := strings.ToLower(logLine)
+
+	if strings.Contains(lowLine, "sql") || strings.Contains(lowLine, "injection") {
+		return "SQL 인젝션 공격"
+	} else if strings.Contains(lowLine, "login") || strings.Contains(lowLine, "auth") {
+		return "인증 실패"
+	} else if strings.Contains(lowLine, "error") {
+		return "시스템 오류"
+	} else {
+		return "일반 로그"
+	}
+}
@@ -0,0 +1,309 @@
+/*
+LLM Scheduler Module
+====================
+
+LLM API 호출 앞단의 요청 스케줄러 (설정 파일 ai_analysis.scheduler)
+
+모든 백엔드(gemini, openai, anthropic, ollama)의 API 호출이 이 스케줄러를 거치며, 설정 재로드로
+백엔드를 바꿔도 호출 한도와 캐시는 그대로 이어집니다.
+
+주요 기능:
+- 같은 프롬프트(백엔드/모델/응답 스키마 포함) 응답 캐시 (기본 60분, 최대 256개)
+- 시간당 최대 API 호출 수 (max_requests_per_hour, 최근 1시간 기준)
+- 일일 토큰 예산 (max_tokens_per_day, 최근 24시간 추정 토큰 기준)
+- 한도에 걸리면 API 를 호출하지 않고 LLMBudgetError 를 반환하여 호출 측이 기본 분석으로 대체
+- 호출/캐시 적중/한도 초과/추정 토큰 통계 (/status, /metrics)
+
+토큰 수는 백엔드마다 토크나이저가 달라 프롬프트와 응답 텍스트 길이로 추정합니다 (UTF-8 4바이트당 1토큰).
+*/
+package main
+
+import (
+	"crypto/sha256" // 캐시 키
+	"encoding/hex"  // 캐시 키 표기
+	"encoding/json" // 응답 스키마 직렬화 (캐시 키)
+	"fmt"           // 형식화된 I/O
+	"strings"       // Prometheus 출력
+	"sync"          // 동시성 제어
+	"time"          // 한도 기간/캐시 만료
+)
+
+// LLM 스케줄러 기본값
+const (
+	DefaultLLMCacheMinutes = 60  // 같은 프롬프트 응답 캐시 시간 (분)
+	llmCacheLimit          = 256 // 캐시 최대 항목 수 (넘으면 만료가 가장 이른 항목부터 제거)
+	llmRequestWindow       = time.Hour
+	llmTokenWindow         = 24 * time.Hour
+)
+
+// 한도 종류 (LLMBudgetError.Limit)
+const (
+	LLMLimitRequests = "requests_per_hour"
+	LLMLimitTokens   = "tokens_per_day"
+)
+
+// LLMSchedulerConfig LLM 호출 한도/캐시/로그 묶음 분석 설정 (설정 파일 ai_analysis.scheduler)
+type LLMSchedulerConfig struct {
+	MaxRequestsPerHour int  `json:"max_requests_per_hour"` // 최근 1시간 최대 API 호출 수 (0 이면 제한 없음)
+	MaxTokensPerDay    int  `json:"max_tokens_per_day"`    // 최근 24시간 추정 토큰 예산 (0 이면 제한 없음)
+	CacheMinutes       int  `json:"cache_minutes"`         // 같은 프롬프트 응답 캐시 시간 (0 이면 60분, 음수면 캐시 안 함)
+	LogAnalysis        bool `json:"log_analysis"`          // AI 이상 탐지 로그를 호스트/서비스별로 묶어 LLM 로그 분석 알림 전송
+	BatchSeconds       int  `json:"batch_seconds"`         // 로그 묶음을 모으는 시간 (0 이면 60초)
+	BatchMaxLines      int  `json:"batch_max_lines"`       // 묶음당 최대 로그 수 (0 이면 20줄, 차면 바로 분석)
+}
+
+// Validate 음수 한도/묶음 값 확인
+func (config LLMSchedulerConfig) Validate() error {
+	switch {
+	case config.MaxRequestsPerHour < 0:
+		return fmt.Errorf("max_requests_per_hour must not be negative")
+	case config.MaxTokensPerDay < 0:
+		return fmt.Errorf("max_tokens_per_day must not be negative")
+	case config.BatchSeconds < 0:
+		return fmt.Errorf("batch_seconds must not be negative")
+	case config.BatchMaxLines < 0:
+		return fmt.Errorf("batch_max_lines must not be negative")
+	}
+	return nil
+}
+
+// cacheTTL 응답 캐시 시간 (0 이면 캐시 안 함)
+func (config LLMSchedulerConfig) cacheTTL() time.Duration {
+	switch {
+	case config.CacheMinutes < 0:
+		return 0
+	case config.CacheMinutes == 0:
+		return DefaultLLMCacheMinutes * time.Minute
+	default:
+		return time.Duration(config.CacheMinutes) * time.Minute
+	}
+}
+
+// LLMBudgetError 호출 한도에 걸려 API 를 호출하지 않음
+type LLMBudgetError struct {
+	Limit string    // LLMLimitRequests, LLMLimitTokens
+	Until time.Time // 한도가 다시 생기는 시각 (가장 오래된 기록이 기간을 벗어나는 시각)
+}
+
+func (err *LLMBudgetError) Error() string {
+	return fmt.Sprintf("LLM %s limit reached until %s", err.Limit, err.Until.Format("15:04:05"))
+}
+
+// Notice 분석 결과에 붙이는 한도 안내
+func (err *LLMBudgetError) Notice() string {
+	limit := "시간당 AI 호출 수"
+	if err.Limit == LLMLimitTokens {
+		limit = "일일 AI 토큰 예산"
+	}
+	return fmt.Sprintf("\n\n⏸️  %s 한도에 도달하여 기본 분석으로 대체했습니다 (%s 이후 재개)", limit, err.Until.Format("01-02 15:04"))
+}
+
+// LLMSchedulerStats 스케줄러 통계 (시작 후 누적, 최근 기간 사용량)
+type LLMSchedulerStats struct {
+	Requests           int64 `json:"requests"`              // API 호출 수
+	Errors             int64 `json:"errors"`                // 실패한 API 호출 수
+	CacheHits          int64 `json:"cache_hits"`            // 캐시 응답 수 (API 호출 안 함)
+	RateLimited        int64 `json:"rate_limited"`          // 시간당 호출 한도로 기본 분석 대체
+	BudgetExhausted    int64 `json:"budget_exhausted"`      // 토큰 예산 소진으로 기본 분석 대체
+	Tokens             int64 `json:"tokens"`                // 누적 추정 토큰 (프롬프트 + 응답)
+	RequestsLastHour   int   `json:"requests_last_hour"`    // 최근 1시간 API 호출 수
+	TokensLastDay      int   `json:"tokens_last_day"`       // 최근 24시간 추정 토큰
+	MaxRequestsPerHour int   `json:"max_requests_per_hour"` // 설정 한도 (0 이면 제한 없음)
+	MaxTokensPerDay    int   `json:"max_tokens_per_day"`    // 설정 예산 (0 이면 제한 없음)
+	CachedResponses    int   `json:"cached_responses"`      // 캐시에 있는 응답 수
+}
+
+// llmTokenUsage 토큰 사용 기록
+type llmTokenUsage struct {
+	at     time.Time
+	tokens int
+}
+
+// llmCacheEntry 캐시된 응답
+type llmCacheEntry struct {
+	response string
+	expires  time.Time
+}
+
+// LLMScheduler LLM API 호출 캐시와 호출 한도
+type LLMScheduler struct {
+	config   LLMSchedulerConfig
+	requests []time.Time     // 최근 1시간 API 호출 시각 (오래된 순)
+	usage    []llmTokenUsage // 최근 24시간 추정 토큰 사용 (오래된 순)
+	cache    map[string]llmCacheEntry
+	stats    LLMSchedulerStats
+	mutex    sync.Mutex
+}
+
+// NewLLMScheduler 새로운 스케줄러 생성 (기본값: 한도 없음, 60분 캐시)
+func NewLLMScheduler() *LLMScheduler {
+	return &LLMScheduler{cache: make(map[string]llmCacheEntry)}
+}
+
+// llmScheduler 모든 LLM 백엔드가 공유하는 스케줄러 (백엔드를 바꿔도 한도가 이어지도록 전역)
+var llmScheduler = NewLLMScheduler()
+
+// SetConfig 한도/캐시 설정 교체 (잘못된 값이면 기존 설정 유지, 사용 기록은 유지)
+func (ls *LLMScheduler) SetConfig(config LLMSchedulerConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	ls.mutex.Lock()
+	defer ls.mutex.Unlock()
+	ls.config = config
+	if config.cacheTTL() == 0 {
+		ls.cache = make(map[string]llmCacheEntry)
+	}
+	return nil
+}
+
+// Do 캐시를 확인하고 한도 안이면 complete 로 API 호출 (scope 는 백엔드/모델, 캐시 키에 포함)
+func (ls *LLMScheduler) Do(scope, prompt string, schema *GeminiSchema, complete llmCompletion) (string, error) {
+	now := time.Now()
+	key := llmCacheKey(scope, prompt, schema)
+
+	ls.mutex.Lock()
+	ls.prune(now)
+	if entry, ok := ls.cache[key]; ok {
+		ls.stats.CacheHits++
+		ls.mutex.Unlock()
+		return entry.response, nil
+	}
+	if err := ls.reserve(now, estimateLLMTokens(prompt)); err != nil {
+		ls.mutex.Unlock()
+		return "", err
+	}
+	ttl := ls.config.cacheTTL()
+	ls.mutex.Unlock()
+
+	response, err := complete(prompt, schema)
+
+	ls.mutex.Lock()
+	defer ls.mutex.Unlock()
+	if err != nil {
+		ls.stats.Errors++
+		return "", err
+	}
+	tokens := estimateLLMTokens(response)
+	ls.usage = append(ls.usage, llmTokenUsage{at: now, tokens: tokens})
+	ls.stats.Tokens += int64(tokens)
+	if ttl > 0 {
+		ls.store(key, llmCacheEntry{response: response, expires: now.Add(ttl)})
+	}
+	return response, nil
+}
+
+// reserve 한도를 확인하고 호출과 프롬프트 토큰을 기록 (한도에 걸리면 LLMBudgetError)
+func (ls *LLMScheduler) reserve(now time.Time, promptTokens int) error {
+	if limit := ls.config.MaxRequestsPerHour; limit > 0 && len(ls.requests) >= limit {
+		ls.stats.RateLimited++
+		return &LLMBudgetError{Limit: LLMLimitRequests, Until: ls.requests[0].Add(llmRequestWindow)}
+	}
+	if budget := ls.config.MaxTokensPerDay; budget > 0 {
+		if used := ls.tokensUsed(); used+promptTokens > budget {
+			ls.stats.BudgetExhausted++
+			until := now.Add(llmTokenWindow)
+			if len(ls.usage) > 0 {
+				until = ls.usage[0].at.Add(llmTokenWindow)
+			}
+			return &LLMBudgetError{Limit: LLMLimitTokens, Until: until}
+		}
+	}
+	ls.requests = append(ls.requests, now)
+	ls.usage = append(ls.usage, llmTokenUsage{at: now, tokens: promptTokens})
+	ls.stats.Requests++
+	ls.stats.Tokens += int64(promptTokens)
+	return nil
+}
+
+// tokensUsed 최근 24시간 추정 토큰 합계
+func (ls *LLMScheduler) tokensUsed() int {
+	used := 0
+	for _, usage := range ls.usage {
+		used += usage.tokens
+	}
+	return used
+}
+
+// prune 기간이 지난 호출/토큰 기록과 만료된 캐시 제거
+func (ls *LLMScheduler) prune(now time.Time) {
+	for len(ls.requests) > 0 && now.Sub(ls.requests[0]) >= llmRequestWindow {
+		ls.requests = ls.requests[1:]
+	}
+	for len(ls.usage) > 0 && now.Sub(ls.usage[0].at) >= llmTokenWindow {
+		ls.usage = ls.usage[1:]
+	}
+	for key, entry := range ls.cache {
+		if !now.Before(entry.expires) {
+			delete(ls.cache, key)
+		}
+	}
+}
+
+// store 응답 캐시 (가득 차면 만료가 가장 이른 항목 제거)
+func (ls *LLMScheduler) store(key string, entry llmCacheEntry) {
+	if len(ls.cache) >= llmCacheLimit {
+		var oldest string
+		for candidate, cached := range ls.cache {
+			if oldest == "" || cached.expires.Before(ls.cache[oldest].expires) {
+				oldest = candidate
+			}
+		}
+		delete(ls.cache, oldest)
+	}
+	ls.cache[key] = entry
+}
+
+// Stats 스케줄러 통계
+func (ls *LLMScheduler) Stats() LLMSchedulerStats {
+	ls.mutex.Lock()
+	defer ls.mutex.Unlock()
+	ls.prune(time.Now())
+	stats := ls.stats
+	stats.RequestsLastHour = len(ls.requests)
+	stats.TokensLastDay = ls.tokensUsed()
+	stats.MaxRequestsPerHour = ls.config.MaxRequestsPerHour
+	stats.MaxTokensPerDay = ls.config.MaxTokensPerDay
+	stats.CachedResponses = len(ls.cache)
+	return stats
+}
+
+// llmCacheKey 백엔드/모델, 응답 스키마, 프롬프트의 해시
+func llmCacheKey(scope, prompt string, schema *GeminiSchema) string {
+	hash := sha256.New()
+	hash.Write([]byte(scope + "\x00" + prompt + "\x00"))
+	if schema != nil {
+		encoded, _ := json.Marshal(schema)
+		hash.Write(encoded)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// estimateLLMTokens 텍스트의 추정 토큰 수 (UTF-8 4바이트당 1토큰, 최소 1)
+func estimateLLMTokens(text string) int {
+	return len(text)/4 + 1
+}
+
+// WriteLLMMetrics LLM 호출 통계를 Prometheus 텍스트 형식으로 기록
+func WriteLLMMetrics(builder *strings.Builder, stats LLMSchedulerStats) {
+	builder.WriteString("# HELP syslog_monitor_llm_requests_total LLM analysis requests by result (sent to the API, served from cache, or replaced by the basic analyzer).\n")
+	builder.WriteString("# TYPE syslog_monitor_llm_requests_total counter\n")
+	for _, result := range []struct {
+		name  string
+		count int64
+	}{
+		{"sent", stats.Requests},
+		{"error", stats.Errors},
+		{"cached", stats.CacheHits},
+		{"rate_limited", stats.RateLimited},
+		{"budget_exhausted", stats.BudgetExhausted},
+	} {
+		fmt.Fprintf(builder, "syslog_monitor_llm_requests_total%s %d\n", prometheusLabels("", "result", result.name), result.count)
+	}
+	builder.WriteString("# HELP syslog_monitor_llm_tokens_estimated_total Estimated LLM tokens (prompt and response) sent since start.\n")
+	builder.WriteString("# TYPE syslog_monitor_llm_tokens_estimated_total counter\n")
+	fmt.Fprintf(builder, "syslog_monitor_llm_tokens_estimated_total %d\n", stats.Tokens)
+	builder.WriteString("# HELP syslog_monitor_llm_tokens_last_day Estimated LLM tokens used in the last 24 hours.\n")
+	builder.WriteString("# TYPE syslog_monitor_llm_tokens_last_day gauge\n")
+	fmt.Fprintf(builder, "syslog_monitor_llm_tokens_last_day %d\n", stats.TokensLastDay)
+}
@@ -0,0 +1,444 @@
+/*
+Log Baseline Module
+===================
+
+서비스별 스트리밍 통계 기준선 (AIAnalyzer 통계 계층, 설정 파일 ai_analysis.baseline)
+
+정규식 규칙은 알려진 공격 문자열만 찾으므로, 평소와 다른 양상(sshd 로그가 갑자기 8배, 에러율 급등,
+접속 IP 수 급증)은 놓칩니다. 서비스마다 윈도우(기본 1분) 단위 지표의 지수 가중 이동 평균(EWMA)과
+분산을 유지하고, 윈도우가 끝날 때 z-점수로 기준선 이탈을 판정합니다.
+
+주요 기능:
+- 서비스별 지표: 로그 볼륨(윈도우당 줄 수), 에러율(ERROR/CRITICAL 비율), 고유 IP 수
+- 지표별 EWMA 평균/분산 (alpha 기본 0.1), 학습 윈도우(기본 15개) 이후부터 판정
+- z-점수 임계값(기본 4)과 최소 배수(기본 3배)를 모두 넘으면 이상으로 판정 (작은 값의 흔들림 무시)
+- 이상이 시작될 때 한 번 알림 ("log volume 8.0x above baseline for sshd"), 평소 수준으로 돌아오면 해제
+- 이상 윈도우는 기준선에 천천히 반영 (공격 트래픽이 곧바로 정상으로 학습되지 않도록)
+- 진행 중인 이상은 해당 서비스 로그의 이상 점수에 반영 (z-점수가 임계값의 2배면 알림 임계값 수준)
+- 로그가 없던 윈도우는 0 으로 반영
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"math"    // 표준편차 계산
+	"os"      // 호스트 이름
+	"sort"    // 서비스 정렬
+	"strconv" // 알림 필드
+	"strings" // 문자열 처리
+	"sync"    // 동기화 (분석 워커 동시 호출)
+	"time"    // 윈도우 처리
+)
+
+// 통계 기준선 기본값
+const (
+	DefaultBaselineWindow    = time.Minute // 지표 집계 윈도우
+	DefaultBaselineAlpha     = 0.1         // EWMA 가중치 (클수록 최근 윈도우 비중이 큼)
+	DefaultBaselineZScore    = 4.0         // 이상 판정 z-점수
+	DefaultBaselineMinRatio  = 3.0         // 이상 판정 최소 배수 (기준선 대비)
+	DefaultBaselineWarmup    = 15          // 판정 전 학습 윈도우 수
+	baselineMinLines         = 20          // 볼륨/에러율 판정에 필요한 윈도우 최소 줄 수
+	baselineMinIPs           = 10          // 고유 IP 수 판정에 필요한 최소 IP 수
+	baselineMinErrorIncrease = 0.1         // 에러율 판정에 필요한 최소 증가폭 (10%p)
+	baselineErrorRateFloor   = 0.02        // 에러율 표준편차 하한
+	baselineAnomalousWeight  = 0.1         // 이상 윈도우를 기준선에 반영하는 비중 (alpha 대비)
+	baselineMaxServices      = 500         // 추적할 서비스 수 상한
+	baselineMaxIPsPerWindow  = 10000       // 윈도우당 서비스별 고유 IP 집계 상한
+	baselineMaxMissedWindows = 60          // 한 번에 0 으로 반영할 빈 윈도우 수 상한
+	baselineUnknownService   = "unknown"   // 서비스를 알 수 없는 로그
+)
+
+// 통계 기준선 지표
+const (
+	BaselineMetricVolume    = "volume"
+	BaselineMetricErrorRate = "error_rate"
+	BaselineMetricUniqueIPs = "unique_ips"
+)
+
+// LogBaselineConfig 통계 기준선 설정 (0 이면 기본값)
+type LogBaselineConfig struct {
+	Disabled      bool    `json:"disabled"`       // 통계 기준선 비활성화
+	WindowSeconds int     `json:"window_seconds"` // 지표 집계 윈도우 (초)
+	Alpha         float64 `json:"alpha"`          // EWMA 가중치 (0~1)
+	ZThreshold    float64 `json:"z_threshold"`    // 이상 판정 z-점수
+	MinRatio      float64 `json:"min_ratio"`      // 이상 판정 최소 배수
+	WarmupWindows int     `json:"warmup_windows"` // 판정 전 학습 윈도우 수
+}
+
+// withDefaults 0 인 값을 기본값으로 채운 설정 (범위를 벗어나면 오류)
+func (c LogBaselineConfig) withDefaults() (LogBaselineConfig, error) {
+	if c.WindowSeconds < 0 || c.Alpha < 0 || c.Alpha >= 1 || c.ZThreshold < 0 || c.MinRatio < 0 || c.WarmupWindows < 0 {
+		return c, fmt.Errorf("baseline settings must be positive (alpha below 1): %+v", c)
+	}
+	if c.WindowSeconds == 0 {
+		c.WindowSeconds = int(DefaultBaselineWindow / time.Second)
+	}
+	if c.Alpha == 0 {
+		c.Alpha = DefaultBaselineAlpha
+	}
+	if c.ZThreshold == 0 {
+		c.ZThreshold = DefaultBaselineZScore
+	}
+	if c.MinRatio == 0 {
+		c.MinRatio = DefaultBaselineMinRatio
+	}
+	if c.WarmupWindows == 0 {
+		c.WarmupWindows = DefaultBaselineWarmup
+	}
+	return c, nil
+}
+
+// BaselineAnomaly 서비스 지표의 기준선 이탈
+type BaselineAnomaly struct {
+	Service     string        `json:"service"`
+	Metric      string        `json:"metric"`   // volume, error_rate, unique_ips
+	Value       float64       `json:"value"`    // 윈도우 값 (에러율은 0~1)
+	Baseline    float64       `json:"baseline"` // 기준선 평균
+	StdDev      float64       `json:"stddev"`   // 기준선 표준편차 (하한 적용)
+	ZScore      float64       `json:"z_score"`
+	Ratio       float64       `json:"ratio"` // 값 / 기준선
+	Lines       int           `json:"lines"` // 윈도우 줄 수
+	Window      time.Duration `json:"window"`
+	WindowStart time.Time     `json:"window_start"`
+}
+
+// Describe 로그/알림 제목용 요약 ("log volume 8.0x above baseline for sshd (...)")
+func (a BaselineAnomaly) Describe() string {
+	switch a.Metric {
+	case BaselineMetricErrorRate:
+		return fmt.Sprintf("error rate %.1f%% vs baseline %.1f%% for %s (%d lines in %v, z=%.1f)", a.Value*100, a.Baseline*100, a.Service, a.Lines, a.Window, a.ZScore)
+	case BaselineMetricUniqueIPs:
+		return fmt.Sprintf("unique source IPs %.1fx above baseline for %s (%.0f IPs in %v, baseline %.1f ± %.1f, z=%.1f)", a.Ratio, a.Service, a.Value, a.Window, a.Baseline, a.StdDev, a.ZScore)
+	default:
+		return fmt.Sprintf("log volume %.1fx above baseline for %s (%.0f lines in %v, baseline %.1f ± %.1f, z=%.1f)", a.Ratio, a.Service, a.Value, a.Window, a.Baseline, a.StdDev, a.ZScore)
+	}
+}
+
+// describeKorean 알림 본문용 요약
+func (a BaselineAnomaly) describeKorean() string {
+	switch a.Metric {
+	case BaselineMetricErrorRate:
+		return fmt.Sprintf("%s 에러율이 %.1f%% 로 기준선 %.1f%% 보다 높습니다 (%v 동안 %d줄, z=%.1f)", a.Service, a.Value*100, a.Baseline*100, a.Window, a.Lines, a.ZScore)
+	case BaselineMetricUniqueIPs:
+		return fmt.Sprintf("%s 접속 IP 수가 기준선의 %.1f배입니다 (%v 동안 %.0f개, 기준선 %.1f±%.1f, z=%.1f)", a.Service, a.Ratio, a.Window, a.Value, a.Baseline, a.StdDev, a.ZScore)
+	default:
+		return fmt.Sprintf("%s 로그 볼륨이 기준선의 %.1f배입니다 (%v 동안 %.0f줄, 기준선 %.1f±%.1f, z=%.1f)", a.Service, a.Ratio, a.Window, a.Value, a.Baseline, a.StdDev, a.ZScore)
+	}
+}
+
+// ewmaStat 지수 가중 이동 평균/분산
+type ewmaStat struct {
+	mean     float64
+	variance float64
+	samples  int
+}
+
+// update 값 반영 (첫 값은 평균으로 그대로 사용)
+func (s *ewmaStat) update(value, alpha float64) {
+	if s.samples == 0 {
+		s.mean, s.variance = value, 0
+	} else {
+		diff := value - s.mean
+		increment := alpha * diff
+		s.mean += increment
+		s.variance = (1 - alpha) * (s.variance + diff*increment)
+	}
+	s.samples++
+}
+
+// serviceBaseline 서비스 하나의 기준선과 현재 윈도우 집계
+type serviceBaseline struct {
+	volume    ewmaStat
+	errorRate ewmaStat
+	uniqueIPs ewmaStat
+	lines     int
+	errors    int
+	ips       map[string]bool
+	alerting  map[string]bool   // 지표별 이상 진행 중 여부
+	active    []BaselineAnomaly // 마지막으로 닫힌 윈도우의 이상 (진행 중 포함)
+}
+
+// LogBaselines 서비스별 통계 기준선 (분석 워커에서 동시에 호출)
+type LogBaselines struct {
+	mutex       sync.Mutex
+	config      LogBaselineConfig
+	window      time.Duration
+	windowStart time.Time
+	windows     int // 닫힌 윈도우 수
+	services    map[string]*serviceBaseline
+	dropped     int64 // 서비스 수 상한으로 집계하지 못한 줄 수
+}
+
+// NewLogBaselines 통계 기준선 생성
+func NewLogBaselines(config LogBaselineConfig) (*LogBaselines, error) {
+	baselines := &LogBaselines{}
+	if err := baselines.Configure(config); err != nil {
+		return nil, err
+	}
+	return baselines, nil
+}
+
+// Configure 설정 교체 (윈도우 길이가 바뀌면 학습한 기준선을 버리고 다시 학습)
+func (lb *LogBaselines) Configure(config LogBaselineConfig) error {
+	config, err := config.withDefaults()
+	if err != nil {
+		return err
+	}
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	window := time.Duration(config.WindowSeconds) * time.Second
+	if window != lb.window {
+		lb.window, lb.windowStart, lb.windows = window, time.Time{}, 0
+		lb.services = make(map[string]*serviceBaseline)
+	}
+	lb.config = config
+	return nil
+}
+
+// Observe 로그 한 줄 반영
+// 반환: 이 서비스 로그의 통계 이상 점수, 이 서비스의 진행 중 이상, 이 줄에서 윈도우가 닫히며 새로 시작된 이상 (모든 서비스)
+func (lb *LogBaselines) Observe(service, level string, ips []string, now time.Time) (float64, []BaselineAnomaly, []BaselineAnomaly) {
+	if lb == nil {
+		return 0, nil, nil
+	}
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	if lb.config.Disabled {
+		return 0, nil, nil
+	}
+
+	started := lb.roll(now)
+
+	if service == "" {
+		service = baselineUnknownService
+	}
+	svc := lb.services[service]
+	if svc == nil {
+		if len(lb.services) >= baselineMaxServices {
+			lb.dropped++
+			return 0, nil, started
+		}
+		svc = &serviceBaseline{ips: make(map[string]bool), alerting: make(map[string]bool)}
+		lb.services[service] = svc
+	}
+	svc.lines++
+	if level == "ERROR" || level == "CRITICAL" {
+		svc.errors++
+	}
+	for _, ip := range ips {
+		if len(svc.ips) < baselineMaxIPsPerWindow {
+			svc.ips[ip] = true
+		}
+	}
+
+	var score float64
+	for _, anomaly := range svc.active {
+		score = math.Max(score, math.Min(MaxAnomalyScore, DefaultAlertThreshold/2*anomaly.ZScore/lb.config.ZThreshold))
+	}
+	return score, svc.active, started
+}
+
+// roll 지난 윈도우를 닫고 새로 시작된 이상 반환 (로그가 없던 윈도우는 0 으로 반영)
+func (lb *LogBaselines) roll(now time.Time) []BaselineAnomaly {
+	if lb.windowStart.IsZero() {
+		lb.windowStart = now.Truncate(lb.window)
+		return nil
+	}
+	var started []BaselineAnomaly
+	for closed := 0; !now.Before(lb.windowStart.Add(lb.window)); closed++ {
+		if closed == baselineMaxMissedWindows {
+			lb.windowStart = now.Truncate(lb.window)
+			break
+		}
+		started = append(started, lb.closeWindow()...)
+		lb.windowStart = lb.windowStart.Add(lb.window)
+	}
+	return started
+}
+
+// closeWindow 현재 윈도우를 기준선과 비교한 뒤 기준선 갱신
+func (lb *LogBaselines) closeWindow() []BaselineAnomaly {
+	var started []BaselineAnomaly
+	services := make([]string, 0, len(lb.services))
+	for service := range lb.services {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	for _, service := range services {
+		svc := lb.services[service]
+		svc.active = nil
+		lines := float64(svc.lines)
+
+		lb.evaluate(service, svc, BaselineMetricVolume, &svc.volume, lines, svc.lines >= baselineMinLines, &started)
+		lb.evaluate(service, svc, BaselineMetricUniqueIPs, &svc.uniqueIPs, float64(len(svc.ips)), len(svc.ips) >= baselineMinIPs, &started)
+		if svc.lines >= baselineMinLines {
+			lb.evaluate(service, svc, BaselineMetricErrorRate, &svc.errorRate, float64(svc.errors)/lines, true, &started)
+		} else if svc.lines == 0 {
+			svc.alerting[BaselineMetricErrorRate] = false
+		}
+
+		svc.lines, svc.errors = 0, 0
+		svc.ips = make(map[string]bool)
+	}
+	lb.windows++
+	return started
+}
+
+// evaluate 지표 하나의 이상 판정과 기준선 갱신 (eligible 이 false 면 판정 없이 갱신)
+func (lb *LogBaselines) evaluate(service string, svc *serviceBaseline, metric string, stat *ewmaStat, value float64, eligible bool, started *[]BaselineAnomaly) {
+	anomalous := false
+	if eligible && stat.samples >= lb.config.WarmupWindows {
+		// 표준편차/배수 하한: 건수 지표는 포아송 분포 기준, 평소 거의 없던 값은 1건(에러율은 2%) 기준
+		stddev, floor := math.Sqrt(stat.variance), 1.0
+		switch metric {
+		case BaselineMetricErrorRate:
+			floor = baselineErrorRateFloor
+			stddev = math.Max(stddev, floor)
+		default:
+			stddev = math.Max(stddev, math.Sqrt(math.Max(stat.mean, floor)))
+		}
+		z := (value - stat.mean) / stddev
+		ratio := value / math.Max(stat.mean, floor)
+
+		anomalous = z >= lb.config.ZThreshold
+		if metric == BaselineMetricErrorRate {
+			anomalous = anomalous && value-stat.mean >= baselineMinErrorIncrease
+		} else {
+			anomalous = anomalous && ratio >= lb.config.MinRatio
+		}
+		if anomalous {
+			anomaly := BaselineAnomaly{
+				Service:     service,
+				Metric:      metric,
+				Value:       value,
+				Baseline:    stat.mean,
+				StdDev:      stddev,
+				ZScore:      z,
+				Ratio:       ratio,
+				Lines:       svc.lines,
+				Window:      lb.window,
+				WindowStart: lb.windowStart,
+			}
+			svc.active = append(svc.active, anomaly)
+			if !svc.alerting[metric] {
+				*started = append(*started, anomaly)
+			}
+		}
+	}
+	svc.alerting[metric] = anomalous
+
+	alpha := lb.config.Alpha
+	if anomalous {
+		alpha *= baselineAnomalousWeight
+	}
+	stat.update(value, alpha)
+}
+
+// LogBaselineStatus 서비스 하나의 기준선 상태 (보고서)
+type LogBaselineStatus struct {
+	Service   string            `json:"service"`
+	Volume    float64           `json:"volume"`     // 윈도우당 평균 줄 수
+	ErrorRate float64           `json:"error_rate"` // 평균 에러율 (0~1)
+	UniqueIPs float64           `json:"unique_ips"` // 윈도우당 평균 고유 IP 수
+	Learning  bool              `json:"learning"`   // 아직 학습 중 (판정 전)
+	Active    []BaselineAnomaly `json:"active,omitempty"`
+}
+
+// Status 서비스별 기준선 상태 (볼륨이 큰 순서)
+func (lb *LogBaselines) Status() []LogBaselineStatus {
+	if lb == nil {
+		return nil
+	}
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	statuses := make([]LogBaselineStatus, 0, len(lb.services))
+	for service, svc := range lb.services {
+		statuses = append(statuses, LogBaselineStatus{
+			Service:   service,
+			Volume:    svc.volume.mean,
+			ErrorRate: svc.errorRate.mean,
+			UniqueIPs: svc.uniqueIPs.mean,
+			Learning:  svc.volume.samples < lb.config.WarmupWindows,
+			Active:    append([]BaselineAnomaly(nil), svc.active...),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Volume != statuses[j].Volume {
+			return statuses[i].Volume > statuses[j].Volume
+		}
+		return statuses[i].Service < statuses[j].Service
+	})
+	return statuses
+}
+
+// ZThreshold 이상 판정 z-점수
+func (lb *LogBaselines) ZThreshold() float64 {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	return lb.config.ZThreshold
+}
+
+// Window 지표 집계 윈도우
+func (lb *LogBaselines) Window() time.Duration {
+	lb.mutex.Lock()
+	defer lb.mutex.Unlock()
+	return lb.window
+}
+
+// baselineAnomalyAlert 기준선 이탈 알림 생성
+func baselineAnomalyAlert(anomaly BaselineAnomaly, zThreshold float64) Alert {
+	host, _ := os.Hostname()
+
+	severity := AlertSeverityWarning
+	if anomaly.ZScore >= 2*zThreshold {
+		severity = AlertSeverityCritical
+	}
+
+	value := fmt.Sprintf("%.0f", anomaly.Value)
+	baseline := fmt.Sprintf("%.1f ± %.1f", anomaly.Baseline, anomaly.StdDev)
+	if anomaly.Metric == BaselineMetricErrorRate {
+		value = fmt.Sprintf("%.1f%%", anomaly.Value*100)
+		baseline = fmt.Sprintf("%.1f%% ± %.1f%%p", anomaly.Baseline*100, anomaly.StdDev*100)
+	}
+
+	sections := []AlertSection{{
+		Fields: []AlertField{
+			{Label: "서비스", Value: anomaly.Service, Short: true},
+			{Label: "지표", Value: anomaly.Metric, Short: true},
+			{Label: "현재 윈도우", Value: fmt.Sprintf("%s (%v, %d줄)", value, anomaly.Window, anomaly.Lines), Short: true},
+			{Label: "기준선 (EWMA)", Value: baseline, Short: true},
+			{Label: "배수", Value: fmt.Sprintf("%.1fx", anomaly.Ratio), Short: true},
+			{Label: "z-점수", Value: fmt.Sprintf("%.1f (임계값 %g)", anomaly.ZScore, zThreshold), Short: true},
+			{Label: "윈도우 시작", Value: anomaly.WindowStart.Format("2006-01-02 15:04:05"), Short: true},
+		},
+		Summary: true,
+	}}
+
+	return Alert{
+		Type:     AlertTypeBaseline,
+		Severity: severity,
+		Title:    fmt.Sprintf("[%s BASELINE] %s - %s", AppName, host, anomaly.Describe()),
+		Headline: "📈 " + anomaly.describeKorean(),
+		Sections: sections,
+		Host:     host,
+		Thread:   alertThreadKey(AlertTypeBaseline, host, anomaly.Service, anomaly.Metric),
+		Fields: map[string]string{
+			"service":  anomaly.Service,
+			"metric":   anomaly.Metric,
+			"value":    strconv.FormatFloat(anomaly.Value, 'f', -1, 64),
+			"baseline": strconv.FormatFloat(anomaly.Baseline, 'f', 2, 64),
+			"z_score":  strconv.FormatFloat(anomaly.ZScore, 'f', 1, 64),
+			"ratio":    strconv.FormatFloat(anomaly.Ratio, 'f', 1, 64),
+		},
+	}
+}
+
+// formatBaselineAnomalies AI 알림 본문용 진행 중 이상 목록
+func formatBaselineAnomalies(anomalies []BaselineAnomaly) string {
+	var builder strings.Builder
+	for _, anomaly := range anomalies {
+		builder.WriteString("• " + anomaly.describeKorean() + "\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,149 @@
+/*
+Log Filter Module
+=================
+
+필터(제외 정규식)와 키워드(포함 문자열) 매칭 엔진
+
+고용량 nginx 액세스 로그처럼 초당 수천 줄이 들어와도 줄마다 정규식을 다시 컴파일하지 않도록
+시작 시(및 설정 변경 시) 한 번만 컴파일해 두고 재사용합니다.
+
+주요 기능:
+- 필터 정규식 사전 컴파일 및 잘못된 패턴 보고 (잘못된 패턴만 건너뛰고 나머지는 적용)
+- 메타 문자가 없는 필터는 정규식 대신 부분 문자열 검색 (strings.Contains)
+- "(?i)문자열" 형태의 대소문자 무시 필터는 소문자 변환 후 부분 문자열 검색
+- 키워드는 미리 소문자로 변환해 두고 줄마다 한 번만 소문자 변환
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"regexp"  // 필터 정규식
+	"strings" // 문자열 처리
+)
+
+// caseInsensitivePrefix 대소문자 무시 플래그 접두사
+const caseInsensitivePrefix = "(?i)"
+
+// LogFilter 사전 컴파일된 제외 필터 목록
+// 리터럴 필터 → 대소문자 무시 리터럴 필터 → 정규식 순으로 검사
+type LogFilter struct {
+	patterns     []string         // 적용된 원본 패턴 (잘못된 패턴 제외)
+	literals     []string         // 메타 문자가 없는 패턴
+	foldLiterals []string         // (?i) 뒤에 메타 문자가 없는 패턴 (소문자)
+	regexes      []*regexp.Regexp // 나머지 정규식 패턴
+}
+
+// CompileLogFilter 필터 패턴 목록을 컴파일
+// 잘못된 패턴이 있으면 그 패턴만 건너뛴 필터와 함께 모든 오류를 모은 error 반환
+func CompileLogFilter(patterns []string) (*LogFilter, error) {
+	filter := &LogFilter{}
+	var invalid []string
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		if err := filter.add(pattern); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%q: %v", pattern, err))
+			continue
+		}
+		filter.patterns = append(filter.patterns, pattern)
+	}
+	if len(invalid) > 0 {
+		return filter, fmt.Errorf("invalid filter patterns: %s", strings.Join(invalid, "; "))
+	}
+	return filter, nil
+}
+
+// add 패턴 하나를 가장 빠른 매칭 방식으로 분류해 추가
+func (f *LogFilter) add(pattern string) error {
+	if regexp.QuoteMeta(pattern) == pattern {
+		f.literals = append(f.literals, pattern)
+		return nil
+	}
+	if literal := strings.TrimPrefix(pattern, caseInsensitivePrefix); literal != pattern && literal != "" && regexp.QuoteMeta(literal) == literal {
+		f.foldLiterals = append(f.foldLiterals, strings.ToLower(literal))
+		return nil
+	}
+
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	f.regexes = append(f.regexes, regex)
+	return nil
+}
+
+// Match 로그 라인이 필터 중 하나와 일치하는지 확인 (nil 이거나 비어 있으면 false)
+func (f *LogFilter) Match(line string) bool {
+	if f == nil {
+		return false
+	}
+	for _, literal := range f.literals {
+		if strings.Contains(line, literal) {
+			return true
+		}
+	}
+	if len(f.foldLiterals) > 0 {
+		lowLine := strings.ToLower(line)
+		for _, literal := range f.foldLiterals {
+			if strings.Contains(lowLine, literal) {
+				return true
+			}
+		}
+	}
+	for _, regex := range f.regexes {
+		if regex.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// Patterns 적용된 필터 패턴 목록 (복사본)
+func (f *LogFilter) Patterns() []string {
+	if f == nil {
+		return nil
+	}
+	return append([]string{}, f.patterns...)
+}
+
+// KeywordMatcher 대소문자를 무시하는 키워드 포함 검사기
+type KeywordMatcher struct {
+	keywords []string // 원본 키워드
+	lowered  []string // 소문자로 변환한 키워드
+}
+
+// NewKeywordMatcher 키워드 목록으로 검사기 생성 (빈 키워드는 무시)
+func NewKeywordMatcher(keywords []string) *KeywordMatcher {
+	matcher := &KeywordMatcher{}
+	for _, keyword := range keywords {
+		if keyword == "" {
+			continue
+		}
+		matcher.keywords = append(matcher.keywords, keyword)
+		matcher.lowered = append(matcher.lowered, strings.ToLower(keyword))
+	}
+	return matcher
+}
+
+// Keywords 키워드 목록 (복사본)
+func (m *KeywordMatcher) Keywords() []string {
+	if m == nil {
+		return nil
+	}
+	return append([]string{}, m.keywords...)
+}
+
+// Match 로그 라인에 키워드 중 하나가 포함되어 있는지 확인 (키워드가 없으면 항상 true)
+func (m *KeywordMatcher) Match(line string) bool {
+	if m == nil || len(m.lowered) == 0 {
+		return true
+	}
+	lowLine := strings.ToLower(line) // 대문자가 없는 ASCII 줄은 복사 없이 그대로 반환됨
+	for _, keyword := range m.lowered {
+		if strings.Contains(lowLine, keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// legacyFilterMatch 사전 컴파일 전의 필터 검사 (줄마다 regexp.MatchString)
+func legacyFilterMatch(filters []string, line string) bool {
+	for _, filter := range filters {
+		if matched, _ := regexp.MatchString(filter, line); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyKeywordMatch 사전 변환 전의 키워드 검사 (줄마다 키워드도 소문자 변환)
+func legacyKeywordMatch(keywords []string, line string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	lowLine := strings.ToLower(line)
+	for _, keyword := range keywords {
+		if strings.Contains(lowLine, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+var filterTestLines = []string{
+	`10.0.0.7 - - [16/Oct/2026:10:00:00 +0900] "GET /healthz HTTP/1.1" 200 2 "-" "kube-probe/1.29"`,
+	`10.0.0.8 - - [16/Oct/2026:10:00:01 +0900] "GET / HTTP/1.1" 200 512 "-" "ELB-HealthChecker/2.0"`,
+	`10.0.0.9 - - [16/Oct/2026:10:00:02 +0900] "GET / HTTP/1.1" 200 512 "-" "elb-healthchecker/2.0"`,
+	`203.0.113.5 - - [16/Oct/2026:10:00:03 +0900] "POST /login HTTP/1.1" 401 64 "-" "Mozilla/5.0"`,
+	`Oct 16 10:00:04 web1 sshd[123]: Failed password for root from 198.51.100.7 port 22 ssh2`,
+	`Oct 16 10:00:05 web1 CRON[456]: (root) CMD (run-parts /etc/cron.hourly)`,
+	`Oct 16 10:00:06 db1 postgres[789]: FATAL:  password authentication failed for user "admin"`,
+	`Oct 16 10:00:07 web1 app[42]: 사용자 로그인 실패 (ERROR)`,
+	``,
+}
+
+// TestLogFilterMatchesLegacy 컴파일된 필터가 줄마다 정규식을 검사하던 결과와 같은지
+func TestLogFilterMatchesLegacy(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters []string
+	}{
+		{"none", nil},
+		{"literal", []string{"kube-probe", "/healthz"}},
+		{"literal with dot", []string{"run-parts /etc/cron.hourly"}},
+		{"case folded", []string{"(?i)ELB-HealthChecker"}},
+		{"case folded korean", []string{"(?i)로그인 실패"}},
+		{"regex", []string{`^Oct \d+ \S+ CRON\[`, `"POST /login[^"]*" 40[13]`}},
+		{"anchored literal", []string{"^Oct"}},
+		{"case flag with regex", []string{"(?i)fatal:\\s+password"}},
+		{"mixed", []string{"kube-probe", "(?i)elb-healthchecker", `sshd\[\d+\]: Failed`}},
+		{"invalid pattern skipped", []string{"[", "CRON"}},
+		{"empty pattern skipped", []string{"", "postgres"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter, _ := CompileLogFilter(test.filters)
+			var legacyFilters []string
+			for _, pattern := range test.filters {
+				if pattern != "" { // 빈 패턴은 예전에도 설정에서 걸러짐
+					legacyFilters = append(legacyFilters, pattern)
+				}
+			}
+			for _, line := range filterTestLines {
+				if got, want := filter.Match(line), legacyFilterMatch(legacyFilters, line); got != want {
+					t.Errorf("Match(%q) = %v, legacy = %v", line, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestCompileLogFilterInvalid 잘못된 패턴만 건너뛰고 오류에 모두 보고
+func TestCompileLogFilterInvalid(t *testing.T) {
+	filter, err := CompileLogFilter([]string{"[", "CRON", "(?i)(", "kube-probe"})
+	if err == nil {
+		t.Fatal("expected an error for invalid patterns")
+	}
+	if !strings.Contains(err.Error(), `"["`) || !strings.Contains(err.Error(), `"(?i)("`) {
+		t.Errorf("error %q does not name every invalid pattern", err)
+	}
+	if got := strings.Join(filter.Patterns(), ","); got != "CRON,kube-probe" {
+		t.Errorf("Patterns() = %s, want CRON,kube-probe", got)
+	}
+}
+
+// TestKeywordMatcherMatchesLegacy 미리 소문자로 바꾼 키워드가 예전 키워드 검사와 같은지
+func TestKeywordMatcherMatchesLegacy(t *testing.T) {
+	tests := []struct {
+		name     string
+		keywords []string
+	}{
+		{"none", nil},
+		{"lowercase", []string{"failed", "fatal"}},
+		{"uppercase", []string{"ERROR", "CRON"}},
+		{"mixed case", []string{"HealthChecker"}},
+		{"korean", []string{"로그인"}},
+		{"no match", []string{"segfault", "oom-killer"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matcher := NewKeywordMatcher(test.keywords)
+			for _, line := range filterTestLines {
+				if got, want := matcher.Match(line), legacyKeywordMatch(test.keywords, line); got != want {
+					t.Errorf("Match(%q) = %v, legacy = %v", line, got, want)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkLogFilter 필터 종류별 컴파일된 매칭과 예전 매칭 비교
+func BenchmarkLogFilter(b *testing.B) {
+	line := filterTestLines[3] // 어떤 필터에도 걸리지 않는 줄 (모든 패턴을 검사)
+
+	filters := []struct {
+		name     string
+		patterns []string
+	}{
+		{"literal", []string{"kube-probe", "/healthz", "ELB-HealthChecker"}},
+		{"fold", []string{"(?i)kube-probe", "(?i)/healthz", "(?i)ELB-HealthChecker"}},
+		{"regex", []string{`kube-probe/\d+`, `GET /healthz\b`, `(?i)elb-health\w+`}},
+	}
+	for _, test := range filters {
+		filter, err := CompileLogFilter(test.patterns)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(test.name+"/compiled", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				filter.Match(line)
+			}
+		})
+		b.Run(test.name+"/legacy", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				legacyFilterMatch(test.patterns, line)
+			}
+		})
+	}
+
+	keywords := []string{"Failed", "FATAL", "error", "segfault"}
+	matcher := NewKeywordMatcher(keywords)
+	b.Run("keywords/compiled", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			matcher.Match(line)
+		}
+	})
+	b.Run("keywords/legacy", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			legacyKeywordMatch(keywords, line)
+		}
+	})
+}
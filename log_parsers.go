@@ -6,13 +6,18 @@ Multi-Format Log Parser Module
 
 지원 로그 포맷:
 - Apache HTTP Server (Common Log Format, Combined Log Format, Error Log)
-- Nginx (Access Log, Error Log)
-- MySQL (Error Log, Slow Query Log, General Log)
-- PostgreSQL (Standard Log, Error Log, Slow Query)
+- Nginx (Access Log, Error Log, escape=json JSON Access Log, 사용자 정의 log_format)
+- MySQL (Error Log, MySQL 8 JSON Error Log, Slow Query Log + Percona 확장 필드, General Log)
+- PostgreSQL (Standard Log, Error Log, Slow Query, csvlog, jsonlog)
+- ModSecurity 감사 로그 (Native, JSON)
+- Linux auditd 감사 로그 (SYSCALL, EXECVE, USER_LOGIN 등)
 - Application Logs (JSON, Structured Text)
 
 주요 기능:
 - 자동 로그 포맷 감지
+- 설정 파일의 사용자 정의 grok/정규식 파서를 자동 감지보다 먼저 시도 (pattern_parser.go)
+- 사용자 정의 정규식 추출 규칙으로 Fields 보강 (extraction_rules.go)
+- CSV/JSON 조회 테이블로 Fields 보강 (lookup_tables.go)
 - 구조화된 로그 데이터 추출
 - HTTP 요청/응답 메트릭 파싱
 - 데이터베이스 쿼리 분석
@@ -29,11 +34,13 @@ Multi-Format Log Parser Module
 package main
 
 import (
-	"fmt"     // 형식화된 I/O
-	"regexp"  // 정규식 패턴 매칭
-	"strconv" // 문자열-숫자 변환
-	"strings" // 문자열 처리
-	"time"    // 시간 파싱 및 처리
+	"encoding/csv"  // PostgreSQL csvlog 파싱
+	"encoding/json" // PostgreSQL jsonlog 파싱
+	"fmt"           // 형식화된 I/O
+	"regexp"        // 정규식 패턴 매칭
+	"strconv"       // 문자열-숫자 변환
+	"strings"       // 문자열 처리
+	"time"          // 시간 파싱 및 처리
 )
 
 // LogParser 로그 파서 인터페이스
@@ -82,6 +89,8 @@ type DBLogDetails struct {
 	Connection     string  `json:"connection"`
 	ErrorCode      string  `json:"error_code"`
 	SlowQuery      bool    `json:"slow_query"`
+	User           string  `json:"user"`
+	SessionID      string  `json:"session_id"`
 }
 
 // ErrorDetails 에러 상세 정보
@@ -105,23 +114,70 @@ type ApacheLogParser struct {
 type NginxLogParser struct {
 	accessLogRegex *regexp.Regexp
 	errorLogRegex  *regexp.Regexp
+	customFormats  []*NginxLogFormat // 설정 파일의 사용자 정의 log_format (기본 형식보다 먼저 시도)
 }
 
 // MySQLLogParser MySQL 로그 파서
 type MySQLLogParser struct {
 	errorLogRegex     *regexp.Regexp
 	slowQueryRegex    *regexp.Regexp
+	slowFieldRegex    *regexp.Regexp
 	generalLogRegex   *regexp.Regexp
 	binlogRegex       *regexp.Regexp
 }
 
+// mysqlJSONLogRecord MySQL 8 JSON 에러 로그 (log_sink_json) 한 줄
+type mysqlJSONLogRecord struct {
+	Prio       *int   `json:"prio"`
+	ErrCode    int    `json:"err_code"`
+	ErrSymbol  string `json:"err_symbol"`
+	SQLState   string `json:"SQL_state"`
+	Subsystem  string `json:"subsystem"`
+	Label      string `json:"label"`
+	Msg        string `json:"msg"`
+	Time       string `json:"time"`
+	TS         int64  `json:"ts"`
+	Thread     int64  `json:"thread"`
+	SourceFile string `json:"source_file"`
+	SourceLine int    `json:"source_line"`
+	Function   string `json:"function"`
+}
+
+// mysqlSlowLogHeaders 한 줄만 들어와도 슬로우 쿼리 로그로 인식하는 헤더 (Thread_id 는 Percona 확장)
+var mysqlSlowLogHeaders = []string{"# Time:", "# User@Host:", "# Query_time:", "# Thread_id:"}
+
 // PostgreSQLLogParser PostgreSQL 로그 파서
 type PostgreSQLLogParser struct {
 	logRegex      *regexp.Regexp
 	errorRegex    *regexp.Regexp
 	slowQueryRegex *regexp.Regexp
+	csvLogRegex   *regexp.Regexp
 }
 
+// postgresLogRecord csvlog/jsonlog 에서 공통으로 사용하는 필드
+type postgresLogRecord struct {
+	Timestamp       string `json:"timestamp"`
+	User            string `json:"user"`
+	Database        string `json:"dbname"`
+	PID             int64  `json:"pid"`
+	RemoteHost      string `json:"remote_host"`
+	RemotePort      int64  `json:"remote_port"`
+	SessionID       string `json:"session_id"`
+	CommandTag      string `json:"ps"`
+	Severity        string `json:"error_severity"`
+	StateCode       string `json:"state_code"`
+	Message         string `json:"message"`
+	Detail          string `json:"detail"`
+	Hint            string `json:"hint"`
+	Statement       string `json:"statement"`
+	ApplicationName string `json:"application_name"`
+	BackendType     string `json:"backend_type"`
+	connectionFrom  string // csvlog 의 connection_from (host:port)
+}
+
+// postgresCSVMinColumns csvlog 최소 컬럼 수 (PostgreSQL 9.0~12 는 23개, 13 은 24개, 14+ 는 26개)
+const postgresCSVMinColumns = 23
+
 // ApplicationLogParser 애플리케이션 로그 파서
 type ApplicationLogParser struct {
 	jsonLogRegex    *regexp.Regexp
@@ -289,6 +345,17 @@ func (p *NginxLogParser) Parse(line string) (*ParsedLog, error) {
 		return parsed, nil
 	}
 
+	// JSON access log (escape=json) 시도
+	if values, ok := parseNginxJSONLog(line); ok {
+		applyNginxAccessValues(parsed, values, "json")
+		return parsed, nil
+	}
+
+	// 사용자 정의 log_format 시도
+	if p.parseCustomFormat(parsed, line) {
+		return parsed, nil
+	}
+
 	// Access log 시도
 	if matches := p.accessLogRegex.FindStringSubmatch(line); matches != nil {
 		timestamp, _ := time.Parse("02/Jan/2006:15:04:05 -0700", matches[2])
@@ -346,16 +413,54 @@ func (p *NginxLogParser) GetLogType() string {
 
 // DetectFormat 포맷 감지
 func (p *NginxLogParser) DetectFormat(line string) bool {
-	return p.accessLogRegex.MatchString(line) || p.errorLogRegex.MatchString(line)
+	if p.accessLogRegex.MatchString(line) || p.errorLogRegex.MatchString(line) {
+		return true
+	}
+	if _, ok := parseNginxJSONLog(line); ok {
+		return true
+	}
+	for _, format := range p.customFormats {
+		if format.regex.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCustomFormat 사용자 정의 log_format 중 일치하는 형식으로 파싱
+func (p *NginxLogParser) parseCustomFormat(parsed *ParsedLog, line string) bool {
+	for _, format := range p.customFormats {
+		if values, ok := format.Match(line); ok {
+			applyNginxAccessValues(parsed, values, "custom")
+			return true
+		}
+	}
+	return false
+}
+
+// SetCustomFormats 사용자 정의 log_format 목록 설정 (하나라도 컴파일에 실패하면 기존 목록 유지)
+func (p *NginxLogParser) SetCustomFormats(formats []string) error {
+	compiled := make([]*NginxLogFormat, 0, len(formats))
+	for _, format := range formats {
+		template, err := CompileNginxLogFormat(format)
+		if err != nil {
+			return err
+		}
+		compiled = append(compiled, template)
+	}
+	p.customFormats = compiled
+	return nil
 }
 
 // NewMySQLLogParser MySQL 로그 파서 생성
 func NewMySQLLogParser() *MySQLLogParser {
 	return &MySQLLogParser{
-		// MySQL error log: timestamp [level] message
-		errorLogRegex: regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d+Z|\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}) \[([^\]]+)\] (.+)`),
-		// Slow query log: # Time: timestamp # User@Host: user[user] @ host [IP] # Query_time: time Lock_time: time Rows_sent: num Rows_examined: num
-		slowQueryRegex: regexp.MustCompile(`# Time: (.+)|# User@Host: (.+)|# Query_time: (\d+\.\d+)\s+Lock_time: (\d+\.\d+)\s+Rows_sent: (\d+)\s+Rows_examined: (\d+)|^(SELECT|INSERT|UPDATE|DELETE|CREATE|DROP|ALTER)`),
+		// MySQL error log: timestamp [thread] [level] [code] [subsystem] message (MySQL 8 은 스레드 ID, 에러 코드, 서브시스템 포함)
+		errorLogRegex: regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d+(?:Z|[+-]\d{2}:\d{2})|\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})(?: (\d+))? \[([^\]]+)\] (?:\[(MY-\d+)\] )?(?:\[(\w+)\] )?(.+)`),
+		// Slow query log: # Time: timestamp # User@Host: user[user] @ host [IP]  Id: num
+		slowQueryRegex: regexp.MustCompile(`^# Time: (.+)|^# User@Host: (.+)`),
+		// 그 밖의 헤더 줄: "Name: value" 쌍 (# Query_time: 1.5  Lock_time: 0.0 ..., Percona 의 # Thread_id/Bytes_sent/InnoDB_* 등)
+		slowFieldRegex: regexp.MustCompile(`(\w+): (\S+)`),
 		// General log: timestamp ID Command Argument
 		generalLogRegex: regexp.MustCompile(`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})\s+(\d+)\s+(\w+)\s+(.+)`),
 	}
@@ -369,16 +474,27 @@ func (p *MySQLLogParser) Parse(line string) (*ParsedLog, error) {
 		Fields:  make(map[string]string),
 	}
 
+	// MySQL 8 JSON 에러 로그 시도
+	if record, ok := parseMySQLJSONLog(line); ok {
+		applyMySQLJSONLog(parsed, record)
+		return parsed, nil
+	}
+
 	// Error log 시도
 	if matches := p.errorLogRegex.FindStringSubmatch(line); matches != nil {
-		timestamp, _ := time.Parse("2006-01-02 15:04:05", matches[1])
-		parsed.Timestamp = timestamp
-		parsed.Level = strings.ToUpper(matches[2])
-		parsed.Message = matches[3]
+		parsed.Timestamp = parseMySQLTimestamp(matches[1])
+		parsed.Level = strings.ToUpper(matches[3])
+		parsed.Message = appendContinuation(matches[6], line)
+		for key, value := range map[string]string{"thread": matches[2], "err_code": matches[4], "subsystem": matches[5]} {
+			if value != "" {
+				parsed.Fields[key] = value
+			}
+		}
 		
 		if strings.Contains(parsed.Level, "ERROR") {
 			parsed.ErrorDetails = &ErrorDetails{
 				ErrorType: parsed.Level,
+				ErrorCode: matches[4],
 				Module:    "mysql",
 			}
 		}
@@ -401,11 +517,11 @@ func (p *MySQLLogParser) Parse(line string) (*ParsedLog, error) {
 		parsed.Level = "INFO"
 		parsed.Fields["connection_id"] = matches[2]
 		parsed.Fields["command"] = matches[3]
-		parsed.Message = matches[4]
+		parsed.Message = appendContinuation(matches[4], line)
 		
 		command := strings.ToUpper(matches[3])
 		if command == "QUERY" {
-			query := matches[4]
+			query := parsed.Message
 			queryType := "SELECT"
 			if strings.HasPrefix(strings.ToUpper(query), "INSERT") {
 				queryType = "INSERT"
@@ -425,14 +541,9 @@ func (p *MySQLLogParser) Parse(line string) (*ParsedLog, error) {
 		return parsed, nil
 	}
 
-	// Slow query log는 여러 줄에 걸쳐 있어서 별도 처리 필요
-	if strings.HasPrefix(line, "# Time:") || strings.HasPrefix(line, "# User@Host:") {
-		parsed.Timestamp = time.Now()
-		parsed.Level = "WARNING"
-		parsed.Message = line
-		parsed.DBDetails = &DBLogDetails{
-			SlowQuery: true,
-		}
+	// Slow query log는 여러 줄에 걸쳐 있음 (-multiline-continue=hash 로 조립하면 블록 전체가 한 엔트리)
+	if isMySQLSlowLogHeader(line) {
+		p.parseSlowQueryBlock(parsed, line)
 		return parsed, nil
 	}
 
@@ -443,17 +554,202 @@ func (p *MySQLLogParser) Parse(line string) (*ParsedLog, error) {
 	return parsed, nil
 }
 
+// parseSlowQueryBlock 슬로우 쿼리 블록(# 헤더 줄 + use/SET timestamp/쿼리 본문) 파싱
+// 헤더 한 줄만 들어와도 동작하며, 블록 전체가 들어오면 실행 시간/사용자/DB/쿼리까지 하나의 DBDetails 로 채움
+// Percona Server 확장 헤더(Thread_id, Schema, Rows_affected, Bytes_sent, Full_scan, InnoDB_* 등)는 소문자 이름으로 Fields 에 기록
+func (p *MySQLLogParser) parseSlowQueryBlock(parsed *ParsedLog, block string) {
+	parsed.Timestamp = time.Now()
+	parsed.Level = "WARNING"
+	parsed.DBDetails = &DBLogDetails{
+		SlowQuery: true,
+	}
+
+	var query []string
+	for _, line := range strings.Split(block, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "# ") {
+			lower := strings.ToLower(line)
+			switch {
+			case strings.HasPrefix(lower, "use "):
+				parsed.DBDetails.Database = strings.Trim(strings.TrimSpace(line[4:]), "`;")
+			case strings.HasPrefix(lower, "set timestamp="):
+				if unix, err := strconv.ParseInt(strings.TrimSuffix(line[len("set timestamp="):], ";"), 10, 64); err == nil {
+					parsed.Timestamp = time.Unix(unix, 0)
+				}
+			default:
+				query = append(query, line)
+			}
+			continue
+		}
+
+		matches := p.slowQueryRegex.FindStringSubmatch(line)
+		switch {
+		case matches == nil:
+			// # Query_time: 1.5  Lock_time: 0.0  Rows_sent: 1  Rows_examined: 1000 및 Percona 확장 헤더
+			for _, field := range p.slowFieldRegex.FindAllStringSubmatch(line, -1) {
+				parsed.Fields[strings.ToLower(field[1])] = field[2]
+			}
+		case matches[1] != "":
+			parsed.Timestamp = parseMySQLTimestamp(strings.TrimSpace(matches[1]))
+		case matches[2] != "":
+			// app[app] @ localhost [127.0.0.1]  Id:    12
+			userHost := matches[2]
+			if index := strings.Index(userHost, "Id:"); index >= 0 {
+				parsed.DBDetails.Connection = strings.TrimSpace(userHost[index+3:])
+				userHost = userHost[:index]
+			}
+			parsed.Fields["user_host"] = strings.TrimSpace(userHost)
+			if index := strings.Index(userHost, "["); index > 0 {
+				parsed.Fields["user"] = userHost[:index]
+				parsed.DBDetails.User = userHost[:index]
+			}
+		}
+	}
+
+	if queryTime, err := strconv.ParseFloat(parsed.Fields["query_time"], 64); err == nil {
+		parsed.DBDetails.ExecutionTime = queryTime * 1000 // 초 → 밀리초
+	}
+	rowsSent, _ := strconv.ParseInt(parsed.Fields["rows_sent"], 10, 64)
+	parsed.DBDetails.RowsAffected = rowsSent
+	if rowsAffected, err := strconv.ParseInt(parsed.Fields["rows_affected"], 10, 64); err == nil && rowsAffected > 0 {
+		parsed.DBDetails.RowsAffected = rowsAffected
+	}
+	// 반환 행 대비 검사 행 비율 (인덱스를 타지 못한 쿼리일수록 큼, 반환 행이 없으면 검사 행 수 그대로)
+	if rowsExamined, err := strconv.ParseInt(parsed.Fields["rows_examined"], 10, 64); err == nil && rowsExamined > 0 {
+		ratio := float64(rowsExamined)
+		if rowsSent > 0 {
+			ratio /= float64(rowsSent)
+		}
+		parsed.Fields["rows_examined_ratio"] = strconv.FormatFloat(ratio, 'f', 1, 64)
+	}
+	if parsed.DBDetails.Database == "" {
+		parsed.DBDetails.Database = parsed.Fields["schema"]
+	}
+	if parsed.DBDetails.Connection == "" {
+		parsed.DBDetails.Connection = parsed.Fields["thread_id"]
+	}
+
+	parsed.DBDetails.Query = strings.Join(query, " ")
+	if fields := strings.Fields(parsed.DBDetails.Query); len(fields) > 0 {
+		parsed.DBDetails.QueryType = strings.ToUpper(fields[0])
+	}
+	parsed.Message = block
+	if parsed.DBDetails.Query != "" {
+		parsed.Message = fmt.Sprintf("Slow query (%.0f ms): %s", parsed.DBDetails.ExecutionTime, parsed.DBDetails.Query)
+	}
+}
+
+// isMySQLSlowLogHeader 슬로우 쿼리 로그 헤더 줄(또는 헤더로 시작하는 블록)인지 확인
+func isMySQLSlowLogHeader(line string) bool {
+	for _, header := range mysqlSlowLogHeaders {
+		if strings.HasPrefix(line, header) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMySQLJSONLog MySQL 8 JSON 에러 로그 한 줄 파싱 (err_code 와 msg 가 있어야 MySQL 로그로 인정)
+func parseMySQLJSONLog(line string) (mysqlJSONLogRecord, bool) {
+	var record mysqlJSONLogRecord
+	if !strings.HasPrefix(line, "{") || !strings.Contains(line, `"err_code"`) {
+		return record, false
+	}
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return record, false
+	}
+	return record, record.Msg != "" && (record.Label != "" || record.Prio != nil)
+}
+
+// applyMySQLJSONLog JSON 에러 로그 레코드를 ParsedLog 로 변환
+// 레벨은 텍스트 에러 로그와 같게 label(System/Error/Warning/Note) 을 대문자로 사용
+func applyMySQLJSONLog(parsed *ParsedLog, record mysqlJSONLogRecord) {
+	parsed.Timestamp = parseMySQLTimestamp(record.Time)
+	if record.Time == "" && record.TS > 0 {
+		parsed.Timestamp = time.UnixMilli(record.TS)
+	}
+	parsed.Level = strings.ToUpper(record.Label)
+	if parsed.Level == "" {
+		switch *record.Prio {
+		case 0:
+			parsed.Level = "SYSTEM"
+		case 1:
+			parsed.Level = "ERROR"
+		case 2:
+			parsed.Level = "WARNING"
+		default:
+			parsed.Level = "NOTE"
+		}
+	}
+	parsed.Message = record.Msg
+
+	errCode := ""
+	if record.ErrCode > 0 {
+		errCode = fmt.Sprintf("MY-%06d", record.ErrCode)
+	}
+	thread := ""
+	if record.Thread > 0 {
+		thread = strconv.FormatInt(record.Thread, 10)
+	}
+	for key, value := range map[string]string{
+		"thread":      thread,
+		"err_code":    errCode,
+		"err_symbol":  record.ErrSymbol,
+		"sql_state":   record.SQLState,
+		"subsystem":   record.Subsystem,
+		"source_file": record.SourceFile,
+		"function":    record.Function,
+	} {
+		if value != "" {
+			parsed.Fields[key] = value
+		}
+	}
+
+	parsed.DBDetails = &DBLogDetails{
+		Connection: thread,
+		ErrorCode:  errCode,
+	}
+	if parsed.Level == "ERROR" {
+		parsed.ErrorDetails = &ErrorDetails{
+			ErrorType:  parsed.Level,
+			ErrorCode:  errCode,
+			Module:     "mysql",
+			Function:   record.Function,
+			LineNumber: record.SourceLine,
+		}
+	}
+}
+
+// parseMySQLTimestamp 에러/슬로우 쿼리 로그 시각 파싱 (MySQL 8 RFC3339, 5.x "2006-01-02 15:04:05", 5.5 "060102 15:04:05")
+// 실패 시 현재 시각
+func parseMySQLTimestamp(value string) time.Time {
+	if timestamp, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return timestamp
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "060102 15:04:05", "060102  15:04:05"} {
+		if timestamp, err := time.Parse(layout, value); err == nil {
+			return timestamp
+		}
+	}
+	return time.Now()
+}
+
 // GetLogType 로그 타입 반환
 func (p *MySQLLogParser) GetLogType() string {
 	return "mysql"
 }
 
-// DetectFormat 포맷 감지
+// DetectFormat 포맷 감지 (에러 로그, MySQL 8 JSON 에러 로그, 일반 로그, 슬로우 쿼리 로그)
 func (p *MySQLLogParser) DetectFormat(line string) bool {
+	if _, ok := parseMySQLJSONLog(line); ok {
+		return true
+	}
 	return p.errorLogRegex.MatchString(line) || 
 	       p.generalLogRegex.MatchString(line) ||
-	       strings.HasPrefix(line, "# Time:") ||
-	       strings.HasPrefix(line, "# User@Host:")
+	       isMySQLSlowLogHeader(line)
 }
 
 // NewPostgreSQLLogParser PostgreSQL 로그 파서 생성
@@ -464,7 +760,9 @@ func NewPostgreSQLLogParser() *PostgreSQLLogParser {
 		// Error pattern
 		errorRegex: regexp.MustCompile(`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d+) [A-Z]+ \[(\d+)\] (ERROR|FATAL|PANIC):\s+(.+)`),
 		// Slow query detection
-		slowQueryRegex: regexp.MustCompile(`duration: (\d+\.\d+) ms\s+statement: (.+)`),
+		slowQueryRegex: regexp.MustCompile(`(?s)duration: (\d+\.\d+) ms\s+statement: (.+)`),
+		// csvlog: log_time,user_name,database_name,process_id,... (log_time 은 밀리초와 시간대 포함)
+		csvLogRegex: regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{3} [^,]+,`),
 	}
 }
 
@@ -476,13 +774,25 @@ func (p *PostgreSQLLogParser) Parse(line string) (*ParsedLog, error) {
 		Fields:  make(map[string]string),
 	}
 
+	// jsonlog (PostgreSQL 15+) 시도
+	if record, ok := parsePostgresJSONLog(line); ok {
+		p.applyRecord(parsed, record)
+		return parsed, nil
+	}
+
+	// csvlog 시도
+	if record, ok := p.parseCSVLog(line); ok {
+		p.applyRecord(parsed, record)
+		return parsed, nil
+	}
+
 	// Error log 시도
 	if matches := p.errorRegex.FindStringSubmatch(line); matches != nil {
 		timestamp, _ := time.Parse("2006-01-02 15:04:05.000", matches[1])
 		parsed.Timestamp = timestamp
 		parsed.Level = strings.ToUpper(matches[3])
 		parsed.Fields["pid"] = matches[2]
-		parsed.Message = matches[4]
+		parsed.Message = appendContinuation(matches[4], line)
 		
 		parsed.ErrorDetails = &ErrorDetails{
 			ErrorType: parsed.Level,
@@ -497,27 +807,16 @@ func (p *PostgreSQLLogParser) Parse(line string) (*ParsedLog, error) {
 		parsed.Timestamp = timestamp
 		parsed.Level = strings.ToUpper(matches[3])
 		parsed.Fields["pid"] = matches[2]
-		parsed.Message = matches[4]
+		parsed.Message = appendContinuation(matches[4], line)
 		
-		// Slow query 체크
-		if slowMatches := p.slowQueryRegex.FindStringSubmatch(matches[4]); slowMatches != nil {
+		// Slow query 체크 (여러 줄 문장은 조립된 전체 문장 사용)
+		if slowMatches := p.slowQueryRegex.FindStringSubmatch(parsed.Message); slowMatches != nil {
 			duration, _ := strconv.ParseFloat(slowMatches[1], 64)
 			parsed.DBDetails = &DBLogDetails{
 				ExecutionTime: duration,
 				Query:         slowMatches[2],
 				SlowQuery:     duration > 1000, // 1초 이상은 slow query
-			}
-			
-			// Query type 추출
-			queryUpper := strings.ToUpper(strings.TrimSpace(slowMatches[2]))
-			if strings.HasPrefix(queryUpper, "SELECT") {
-				parsed.DBDetails.QueryType = "SELECT"
-			} else if strings.HasPrefix(queryUpper, "INSERT") {
-				parsed.DBDetails.QueryType = "INSERT"
-			} else if strings.HasPrefix(queryUpper, "UPDATE") {
-				parsed.DBDetails.QueryType = "UPDATE"
-			} else if strings.HasPrefix(queryUpper, "DELETE") {
-				parsed.DBDetails.QueryType = "DELETE"
+				QueryType:     postgresQueryType(slowMatches[2]),
 			}
 		}
 		
@@ -531,14 +830,166 @@ func (p *PostgreSQLLogParser) Parse(line string) (*ParsedLog, error) {
 	return parsed, nil
 }
 
+// parsePostgresJSONLog jsonlog 한 줄 파싱 (error_severity 와 backend_type/session_id 가 있어야 PostgreSQL 로그로 인정)
+func parsePostgresJSONLog(line string) (postgresLogRecord, bool) {
+	var record postgresLogRecord
+	if !strings.HasPrefix(line, "{") || !strings.Contains(line, `"error_severity"`) {
+		return record, false
+	}
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return record, false
+	}
+	if record.Severity == "" || (record.SessionID == "" && record.BackendType == "") {
+		return record, false
+	}
+	if record.RemoteHost != "" && record.RemotePort > 0 {
+		record.connectionFrom = fmt.Sprintf("%s:%d", record.RemoteHost, record.RemotePort)
+	} else {
+		record.connectionFrom = record.RemoteHost
+	}
+	return record, true
+}
+
+// parseCSVLog csvlog 한 레코드 파싱 (따옴표 안의 줄바꿈은 여러 줄 조립 시 한 엔트리로 들어옴)
+func (p *PostgreSQLLogParser) parseCSVLog(line string) (postgresLogRecord, bool) {
+	var record postgresLogRecord
+	if !p.csvLogRegex.MatchString(line) {
+		return record, false
+	}
+	reader := csv.NewReader(strings.NewReader(line))
+	reader.FieldsPerRecord = -1
+	columns, err := reader.Read()
+	if err != nil || len(columns) < postgresCSVMinColumns {
+		return record, false
+	}
+
+	// 컬럼 순서: log_time, user_name, database_name, process_id, connection_from, session_id,
+	// session_line_num, command_tag, session_start_time, virtual_transaction_id, transaction_id,
+	// error_severity, sql_state_code, message, detail, hint, internal_query, internal_query_pos,
+	// context, query, query_pos, location, application_name[, backend_type, leader_pid, query_id]
+	record.Timestamp = columns[0]
+	record.User = columns[1]
+	record.Database = columns[2]
+	record.PID, _ = strconv.ParseInt(columns[3], 10, 64)
+	record.connectionFrom = columns[4]
+	record.RemoteHost = columns[4]
+	if index := strings.LastIndex(columns[4], ":"); index > 0 {
+		record.RemoteHost = columns[4][:index]
+	}
+	record.SessionID = columns[5]
+	record.CommandTag = columns[7]
+	record.Severity = columns[11]
+	record.StateCode = columns[12]
+	record.Message = columns[13]
+	record.Detail = columns[14]
+	record.Hint = columns[15]
+	record.Statement = columns[19]
+	record.ApplicationName = columns[22]
+	if len(columns) > 23 {
+		record.BackendType = columns[23]
+	}
+	return record, true
+}
+
+// applyRecord csvlog/jsonlog 레코드를 ParsedLog 로 변환 (세션/사용자/DB 는 DBDetails 에 기록)
+func (p *PostgreSQLLogParser) applyRecord(parsed *ParsedLog, record postgresLogRecord) {
+	parsed.Timestamp = parsePostgresTimestamp(record.Timestamp)
+	parsed.Level = strings.ToUpper(record.Severity)
+	parsed.Message = record.Message
+	if record.Detail != "" {
+		parsed.Message += " DETAIL: " + record.Detail
+	}
+
+	parsed.DBDetails = &DBLogDetails{
+		Query:      record.Statement,
+		Database:   record.Database,
+		User:       record.User,
+		SessionID:  record.SessionID,
+		ErrorCode:  record.StateCode,
+		QueryType:  postgresQueryType(record.Statement),
+	}
+	if record.PID > 0 {
+		parsed.DBDetails.Connection = strconv.FormatInt(record.PID, 10)
+		parsed.Fields["pid"] = parsed.DBDetails.Connection
+	}
+
+	// log_min_duration_statement 로 기록된 느린 문장은 메시지에 실행 시간과 문장이 함께 있음
+	if slowMatches := p.slowQueryRegex.FindStringSubmatch(record.Message); slowMatches != nil {
+		duration, _ := strconv.ParseFloat(slowMatches[1], 64)
+		parsed.DBDetails.ExecutionTime = duration
+		parsed.DBDetails.SlowQuery = duration > 1000 // 1초 이상은 slow query
+		if parsed.DBDetails.Query == "" {
+			parsed.DBDetails.Query = slowMatches[2]
+			parsed.DBDetails.QueryType = postgresQueryType(slowMatches[2])
+		}
+	}
+
+	// 유닉스 소켓 접속은 "[local]" 로 기록되므로 IP 로 취급하지 않음
+	clientIP := record.RemoteHost
+	if strings.HasPrefix(clientIP, "[") {
+		clientIP = ""
+	}
+	for key, value := range map[string]string{
+		"session_id":       record.SessionID,
+		"client":           record.connectionFrom,
+		"client_ip":        clientIP,
+		"command_tag":      record.CommandTag,
+		"sql_state":        record.StateCode,
+		"hint":             record.Hint,
+		"application_name": record.ApplicationName,
+		"backend_type":     record.BackendType,
+	} {
+		if value != "" {
+			parsed.Fields[key] = value
+		}
+	}
+
+	switch parsed.Level {
+	case "ERROR", "FATAL", "PANIC":
+		parsed.ErrorDetails = &ErrorDetails{
+			ErrorType: parsed.Level,
+			ErrorCode: record.StateCode,
+			Module:    "postgresql",
+		}
+	}
+}
+
+// parsePostgresTimestamp csvlog/jsonlog 의 "2006-01-02 15:04:05.000 UTC" 형식 시각 파싱 (실패 시 현재 시각)
+func parsePostgresTimestamp(value string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04:05.000 MST", "2006-01-02 15:04:05.000 -07", "2006-01-02 15:04:05.000 -0700"} {
+		if timestamp, err := time.Parse(layout, value); err == nil {
+			return timestamp
+		}
+	}
+	return time.Now()
+}
+
+// postgresQueryType 문장의 첫 키워드로 쿼리 유형 판단 (SELECT/INSERT/UPDATE/DELETE 외에는 빈 문자열)
+func postgresQueryType(query string) string {
+	queryUpper := strings.ToUpper(strings.TrimSpace(query))
+	for _, queryType := range []string{"SELECT", "INSERT", "UPDATE", "DELETE"} {
+		if strings.HasPrefix(queryUpper, queryType) {
+			return queryType
+		}
+	}
+	return ""
+}
+
 // GetLogType 로그 타입 반환
 func (p *PostgreSQLLogParser) GetLogType() string {
 	return "postgresql"
 }
 
-// DetectFormat 포맷 감지
+// DetectFormat 포맷 감지 (stderr 형식, csvlog, jsonlog)
 func (p *PostgreSQLLogParser) DetectFormat(line string) bool {
-	return p.logRegex.MatchString(line) || p.errorRegex.MatchString(line)
+	if p.logRegex.MatchString(line) || p.errorRegex.MatchString(line) {
+		return true
+	}
+	if _, ok := parsePostgresJSONLog(line); ok {
+		return true
+	}
+	_, ok := p.parseCSVLog(line)
+	return ok
 }
 
 // NewApplicationLogParser 애플리케이션 로그 파서 생성
@@ -581,17 +1032,21 @@ func (p *ApplicationLogParser) Parse(line string) (*ParsedLog, error) {
 		if matches[3] != "" {
 			parsed.Fields["module"] = matches[3]
 		}
-		parsed.Message = matches[4]
+		parsed.Message = appendContinuation(matches[4], line)
 		
-		// 에러 패턴 체크
+		// 에러 패턴 체크 (여러 줄 엔트리면 첫 줄 이후가 스택 트레이스)
 		if p.errorRegex.MatchString(parsed.Message) {
 			if parsed.Level == "INFO" {
 				parsed.Level = "ERROR"
 			}
+			stackTrace := parsed.Message
+			if _, rest := splitMultiline(line); rest != "" {
+				stackTrace = rest
+			}
 			parsed.ErrorDetails = &ErrorDetails{
 				ErrorType: "APPLICATION_ERROR",
 				Module:    matches[3],
-				StackTrace: parsed.Message,
+				StackTrace: stackTrace,
 			}
 		}
 		
@@ -615,26 +1070,103 @@ func (p *ApplicationLogParser) DetectFormat(line string) bool {
 	return p.jsonLogRegex.MatchString(line) || p.structuredRegex.MatchString(line)
 }
 
+// appendContinuation 첫 줄에서 추출한 메시지에 여러 줄 엔트리의 나머지 줄을 이어 붙임
+// 헤더 정규식은 첫 줄에서만 일치하므로 조립된 엔트리의 나머지가 메시지에서 빠지지 않도록 함
+func appendContinuation(message, entry string) string {
+	if _, rest := splitMultiline(entry); rest != "" {
+		return message + "\n" + rest
+	}
+	return message
+}
+
 // LogParserManager 로그 파서 관리자
 type LogParserManager struct {
 	parsers []LogParser
+	nginx   *NginxLogParser     // 사용자 정의 log_format 을 자동 감지보다 먼저 시도하기 위한 참조
+	custom  []*PatternLogParser // 설정 파일의 사용자 정의 파서 (내장 파서보다 먼저 시도)
+
+	extractionRules []*compiledExtractionRule // 설정 파일의 사용자 정의 필드 추출 규칙 (파싱 후 적용)
+	lookupTables    *LookupTables             // 설정 파일의 조회 테이블 (추출 규칙 다음에 적용)
 }
 
 // NewLogParserManager 로그 파서 관리자 생성
 func NewLogParserManager() *LogParserManager {
+	nginx := NewNginxLogParser()
 	return &LogParserManager{
 		parsers: []LogParser{
 			NewApacheLogParser(),
-			NewNginxLogParser(),
+			nginx,
 			NewMySQLLogParser(),
 			NewPostgreSQLLogParser(),
+			NewModSecurityLogParser(),
+			NewAuditdLogParser(),
 			NewApplicationLogParser(),
 		},
+		nginx: nginx,
 	}
 }
 
-// ParseLog 로그 파싱 (자동 감지)
+// ParseLog 로그 파싱 (자동 감지 후 사용자 정의 추출 규칙, 조회 테이블 보강 적용)
 func (lpm *LogParserManager) ParseLog(line string) *ParsedLog {
+	parsed := lpm.detectAndParse(line)
+	applyExtractionRules(lpm.extractionRules, parsed, line)
+	lpm.lookupTables.EnrichParsedLog(parsed)
+	return parsed
+}
+
+// ApplyExtractionRules journald/이벤트 로그처럼 미리 파싱된 로그에 사용자 정의 추출 규칙과 조회 테이블 보강 적용
+func (lpm *LogParserManager) ApplyExtractionRules(parsed *ParsedLog, line string) {
+	applyExtractionRules(lpm.extractionRules, parsed, line)
+	lpm.lookupTables.EnrichParsedLog(parsed)
+}
+
+// SetLookupTables 설정 파일의 조회 테이블 적용 (nil 이면 보강 안 함)
+func (lpm *LogParserManager) SetLookupTables(tables *LookupTables) {
+	lpm.lookupTables = tables
+}
+
+// LookupTables 현재 조회 테이블 (로그인 이벤트 보강용, 없으면 nil)
+func (lpm *LogParserManager) LookupTables() *LookupTables {
+	return lpm.lookupTables
+}
+
+// SetExtractionRules 설정 파일의 사용자 정의 필드 추출 규칙 적용 (컴파일 실패 시 기존 규칙 유지)
+func (lpm *LogParserManager) SetExtractionRules(rules []ExtractionRule) error {
+	compiled, err := CompileExtractionRules(rules)
+	if err != nil {
+		return err
+	}
+	lpm.extractionRules = compiled
+	return nil
+}
+
+// SetCustomParsers 설정 파일의 사용자 정의 파서 적용 (컴파일 실패 시 기존 파서 유지)
+func (lpm *LogParserManager) SetCustomParsers(configs []CustomParserConfig) error {
+	parsers, err := CompileCustomParsers(configs)
+	if err != nil {
+		return err
+	}
+	lpm.custom = parsers
+	return nil
+}
+
+// detectAndParse 사용자 정의 파서, 사용자 정의 nginx log_format, 포맷 자동 감지 순서로 파싱
+func (lpm *LogParserManager) detectAndParse(line string) *ParsedLog {
+	// 사용자 정의 파서는 줄 전체와 일치해야 하므로 내장 파서보다 먼저 시도
+	for _, parser := range lpm.custom {
+		if parsed, err := parser.Parse(line); err == nil {
+			return parsed
+		}
+	}
+
+	// 사용자가 선언한 nginx log_format 은 Apache Combined 형식과 겹칠 수 있으므로 먼저 시도
+	if len(lpm.nginx.customFormats) > 0 {
+		parsed := &ParsedLog{LogType: "nginx", RawLog: line, Fields: make(map[string]string)}
+		if lpm.nginx.parseCustomFormat(parsed, line) {
+			return parsed
+		}
+	}
+
 	// 각 파서로 포맷 감지 시도
 	for _, parser := range lpm.parsers {
 		if parser.DetectFormat(line) {
@@ -657,6 +1189,13 @@ func (lpm *LogParserManager) ParseLog(line string) *ParsedLog {
 
 // ParseLogWithType 특정 타입으로 로그 파싱
 func (lpm *LogParserManager) ParseLogWithType(line string, logType string) *ParsedLog {
+	for _, parser := range lpm.custom {
+		if parser.GetLogType() == logType {
+			if parsed, err := parser.Parse(line); err == nil {
+				return parsed
+			}
+		}
+	}
 	for _, parser := range lpm.parsers {
 		if parser.GetLogType() == logType {
 			if parsed, err := parser.Parse(line); err == nil {
@@ -676,11 +1215,28 @@ func (lpm *LogParserManager) ParseLogWithType(line string, logType string) *Pars
 	}
 }
 
+// SetNginxLogFormats 설정 파일의 사용자 정의 nginx log_format 적용
+func (lpm *LogParserManager) SetNginxLogFormats(formats []string) error {
+	return lpm.nginx.SetCustomFormats(formats)
+}
+
 // GetSupportedTypes 지원하는 로그 타입 반환
 func (lpm *LogParserManager) GetSupportedTypes() []string {
-	types := make([]string, len(lpm.parsers))
-	for i, parser := range lpm.parsers {
-		types[i] = parser.GetLogType()
+	types := make([]string, 0, len(lpm.parsers)+len(lpm.custom))
+	for _, parser := range lpm.parsers {
+		types = append(types, parser.GetLogType())
+	}
+	for _, parser := range lpm.custom {
+		found := false
+		for _, existing := range types {
+			if existing == parser.GetLogType() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			types = append(types, parser.GetLogType())
+		}
 	}
 	return types
 } 
\ No newline at end of file
@@ -0,0 +1,142 @@
+/*
+Login Failure Correlation Module
+================================
+
+로그인 실패 이후 성공한 로그인 시퀀스 상관 분석
+
+주요 기능:
+- 사용자@IP 단위 최근 로그인 실패 기록
+- 실패 버스트 직후 성공한 로그인 탐지 (무차별 대입 공격 성공 지표)
+- 시간 윈도우 밖의 실패 기록 자동 정리
+
+판정 기준:
+- 윈도우(기본 10분) 내 동일 사용자@IP 실패 횟수가 임계값(기본 5회) 이상인 상태에서 로그인 성공
+- 동일 IP 에서 사용자명 없이 기록된 실패(preauth 종료 등)도 해당 IP 의 실패로 합산
+*/
+package main
+
+import (
+	"fmt"  // 형식화된 I/O
+	"sync" // 동기화 (뮤텍스)
+	"time" // 시간 처리
+)
+
+// FailureCorrelator 로그인 실패 → 성공 시퀀스 상관 분석기
+type FailureCorrelator struct {
+	failures  map[string][]time.Time // 사용자@IP -> 윈도우 내 실패 시각 (오래된 순)
+	mutex     sync.Mutex
+	window    time.Duration // 실패를 합산하는 시간 윈도우
+	threshold int           // 성공 로그인을 의심하기 위한 최소 실패 횟수
+}
+
+// NewFailureCorrelator 새로운 실패 상관 분석기 생성
+func NewFailureCorrelator(window time.Duration, threshold int) *FailureCorrelator {
+	if window <= 0 {
+		window = DefaultFailureBurstWindow
+	}
+	if threshold <= 0 {
+		threshold = DefaultFailureBurstThreshold
+	}
+	return &FailureCorrelator{
+		failures:  make(map[string][]time.Time),
+		window:    window,
+		threshold: threshold,
+	}
+}
+
+// Configure 판정 기준 변경 (0 이하 값은 기존 값 유지, 실패 기록은 보존)
+func (fc *FailureCorrelator) Configure(window time.Duration, threshold int) {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+	if window > 0 {
+		fc.window = window
+	}
+	if threshold > 0 {
+		fc.threshold = threshold
+	}
+}
+
+// failureKey 실패 기록 키 생성
+func failureKey(user, ip string) string {
+	return fmt.Sprintf("%s@%s", user, ip)
+}
+
+// RecordFailure 로그인 실패 기록
+func (fc *FailureCorrelator) RecordFailure(user, ip string, at time.Time) {
+	if ip == "" {
+		return
+	}
+	if user == "" {
+		user = "unknown"
+	}
+
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	key := failureKey(user, ip)
+	fc.failures[key] = append(fc.prune(fc.failures[key], at), at)
+}
+
+// CheckSuccess 성공한 로그인 직전의 실패 횟수 반환
+// 임계값 이상이면 suspicious가 true이며, 판정에 사용된 실패 기록은 초기화
+func (fc *FailureCorrelator) CheckSuccess(user, ip string, at time.Time) (failures int, suspicious bool) {
+	if ip == "" {
+		return 0, false
+	}
+
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	keys := []string{failureKey(user, ip), failureKey("unknown", ip)}
+	for _, key := range keys {
+		recent := fc.prune(fc.failures[key], at)
+		failures += len(recent)
+		if len(recent) == 0 {
+			delete(fc.failures, key)
+		} else {
+			fc.failures[key] = recent
+		}
+	}
+
+	if failures < fc.threshold {
+		return failures, false
+	}
+
+	// 같은 실패 버스트로 중복 판정되지 않도록 기록 초기화
+	for _, key := range keys {
+		delete(fc.failures, key)
+	}
+	return failures, true
+}
+
+// Cleanup 윈도우를 벗어난 실패 기록 정리
+func (fc *FailureCorrelator) Cleanup(now time.Time) {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+
+	for key, times := range fc.failures {
+		recent := fc.prune(times, now)
+		if len(recent) == 0 {
+			delete(fc.failures, key)
+		} else {
+			fc.failures[key] = recent
+		}
+	}
+}
+
+// prune 윈도우 밖의 실패 시각 제거 (times는 오래된 순으로 정렬되어 있음)
+func (fc *FailureCorrelator) prune(times []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-fc.window)
+	start := 0
+	for start < len(times) && times[start].Before(cutoff) {
+		start++
+	}
+	return times[start:]
+}
+
+// Window 실패 합산 시간 윈도우
+func (fc *FailureCorrelator) Window() time.Duration {
+	fc.mutex.Lock()
+	defer fc.mutex.Unlock()
+	return fc.window
+}
@@ -6,11 +6,15 @@ Login Pattern Detection Module
 
 주요 기능:
 - SSH 로그인 성공/실패 감지
-- Sudo 명령 실행 감지
+- Sudo/su 명령 실행 감지 (runas, TTY, 작업 디렉토리, 대화형 셸, 위험 명령 정책)
 - 웹 로그인 패턴 인식
 - 무차별 대입 공격(Brute Force) 탐지
+- 연속 실패 직후 성공한 로그인 상관 분석 (무차별 대입 성공 의심)
+- 평소와 다른 호스팅 사업자 ASN에서의 인증 탐지 (사용자별 ASN 히스토리)
+- Tor 출구 노드 / VPN·프록시 출처 표시 (설정 시 자동으로 HIGH 위험 처리)
+- 위협 인텔리전스 평판 조회 (AbuseIPDB 신뢰 점수, Spamhaus DROP/FireHOL 블록리스트, 알려진 악성 IP는 HIGH 위험)
 - 비정상적인 로그인 시도 분석
-- IP 주소 기반 지리적 위치 추적
+- IP 주소 기반 지리적 위치 추적 (GeoMapper 캐시 또는 로컬 GeoIP DB, 캐시에 없으면 비동기 조회 후 알림 보강)
 
 감지 패턴:
 - SSH 인증 성공: "Accepted password/publickey for user from IP"
@@ -27,15 +31,13 @@ Login Pattern Detection Module
 package main
 
 import (
-	"encoding/json" // JSON 파싱
-	"fmt"           // 문자열 포맷팅
-	"io"            // I/O 인터페이스
-	"net"           // 네트워크 처리
-	"net/http"      // HTTP 클라이언트
-	"regexp"        // 정규식 패턴 매칭
-	"strings"       // 문자열 처리 및 검색
-	"sync"          // 동기화 (뮤텍스)
-	"time"          // 시간 처리
+	"context" // 종료 신호
+	"fmt"     // 문자열 포맷팅
+	"net"     // 네트워크 처리
+	"regexp"  // 정규식 패턴 매칭
+	"strings" // 문자열 처리 및 검색
+	"sync"    // 동기화 (뮤텍스)
+	"time"    // 시간 처리
 )
 
 // LoginDetector 로그인 패턴 감지 서비스
@@ -46,11 +48,37 @@ type LoginDetector struct {
 	systemMonitor *SystemMonitor // 시스템 메트릭 수집기 (선택적)
 	
 	// Alert throttling 알림 제한 관련 필드
-	alertHistory  map[string]time.Time // 알림 히스토리 (사용자@IP -> 마지막 알림 시간)
-	alertMutex    sync.RWMutex         // 알림 히스토리 동시 접근 보호
+	alertHistory  *AlertHistory        // 알림 히스토리 (제한 키 -> 마지막 알림 시간, 시간순 힙)
+	alertMutex    sync.RWMutex         // 알림 히스토리 및 알림 설정 동시 접근 보호
 	alertInterval time.Duration        // 알림 간격 설정 (기본 10분)
+	criticalInterval time.Duration     // 중요 이벤트 알림 간격 (기본 2분)
+	maxHistorySize   int               // 알림 히스토리 최대 크기
+	historyRetention time.Duration     // 알림 히스토리 보존 기간 (기본 1시간)
+	janitorInterval  time.Duration     // 히스토리 정리 작업 실행 간격 (기본 5분)
+	janitorOnce      sync.Once         // 정리 작업 고루틴 1회 시작 보장
+	throttleKey     string                   // 알림 제한 키 (user, ip, user_ip, subnet)
+	statusIntervals map[string]time.Duration // 상태별 알림 간격 (설정 시 기본 간격보다 우선)
+	trustedNetworks *TrustedNetworks         // 신뢰 네트워크 (GeoIP 조회/위험도 평가 생략, 알림 우선순위 낮춤)
+
+	sudoDenyPatterns   []*regexp.Regexp    // sudo 위험 명령 정책 패턴
+	failureCorrelator  *FailureCorrelator  // 실패 → 성공 로그인 상관 분석기
+	bruteForce         *BruteForceDetector // IP 단위 무차별 대입 공격 탐지기
+	asnTracker         *ASNTracker         // 사용자별 로그인 ASN 히스토리
+	anonymizers        *AnonymizerDetector // Tor 출구 노드 / VPN·프록시 데이터셋
+	threatIntel        *ThreatIntel        // AbuseIPDB / 블록리스트 IP 평판
+	geoMapper          *GeoMapper          // IP 지리정보 캐시/비동기 조회 (nil 이면 위치 정보 UNKNOWN)
+	reverseDNS         *ReverseDNS         // 출처 IP PTR 조회 (nil 이면 조회하지 않음)
+	anonymizerHighRisk bool                // 익명화 출처를 HIGH 위험으로 처리할지 여부
+
+	metricsMutex      sync.Mutex    // 시스템 모니터가 없을 때 수집한 메트릭 스냅샷 보호
+	metricsSnapshot   SystemMetrics // 마지막으로 직접 수집한 메트릭
+	metricsSnapshotAt time.Time     // 스냅샷 수집 시각
 }
 
+// loginMetricsReuse 시스템 모니터 없이 직접 수집한 메트릭을 재사용하는 시간
+// 수집에 공인 IP 조회(외부 서비스)가 포함되어 로그인 줄마다 수집하면 로그 처리가 막힘
+const loginMetricsReuse = 30 * time.Second
+
 // LoginInfo 로그인 정보 구조체 (시스템 리소스 정보 포함)
 type LoginInfo struct {
 	Status       string           // 로그인 상태 (accepted, failed, sudo 등)
@@ -58,6 +86,19 @@ type LoginInfo struct {
 	IP           string           // 접속 IP 주소
 	Method       string           // 인증 방법 (ssh, password, publickey 등)
 	Command      string           // 실행된 명령어 (sudo의 경우)
+	RunAs        string           // sudo/su 대상 사용자 (USER=)
+	TTY          string           // 터미널 (TTY=)
+	CWD          string           // 작업 디렉토리 (PWD=)
+	Shell        bool             // 대화형 루트 셸 진입 여부 (sudo -i, su - 등)
+	DenyReason   string           // sudo 거부 사유 (비밀번호 오류, sudoers 미등록 등)
+	PolicyViolation string        // 일치한 위험 명령 패턴 (정책 위반 시)
+	PriorFailures   int           // 성공 직전 윈도우 내 동일 사용자@IP 실패 횟수
+	BruteForceSuspected bool      // 연속 실패 직후 성공한 로그인 (무차별 대입 성공 의심)
+	BruteForceAttack *BruteForceAttack // 이 실패로 IP 단위 실패 임계값 도달 (무차별 대입 공격 진행 중, 없으면 nil)
+	TrustedNetwork  string        // 일치한 신뢰 네트워크 이름 (비어 있으면 신뢰 네트워크 아님)
+	ASNChange       *ASNChange    // 평소와 다른 호스팅 사업자 ASN에서 인증 (계정 탈취 의심, 없으면 nil)
+	Enrichment      map[string]string // 조회 테이블 보강 필드 (예: user.department, asset.owner)
+	ReverseDNS      PTRRecord     // 출처 IP 의 PTR 호스트 이름 (-reverse-dns, 없으면 빈 레코드)
 	Success      bool             // 로그인 성공 여부
 	SystemInfo   SystemMetrics    // 로그인 시점의 시스템 리소스 정보
 	IPDetails    *IPLocationInfo  // IP 주소 상세 정보 (지리적 위치 등)
@@ -75,17 +116,158 @@ type IPLocationInfo struct {
 	ASN          string `json:"asn"`          // ASN 번호
 	IsPrivate    bool   `json:"is_private"`   // 사설 IP 여부
 	Threat       string `json:"threat"`       // 위험도 평가
+	Anonymizer   string `json:"anonymizer,omitempty"` // 익명화 출처 유형 (tor, vpn, proxy)
+	AbuseScore   int    `json:"abuse_score,omitempty"`   // AbuseIPDB 신뢰 점수 (0-100)
+	AbuseReports int    `json:"abuse_reports,omitempty"` // AbuseIPDB 신고 건수
+	Blocklist    string `json:"blocklist,omitempty"`     // 일치한 위협 블록리스트 이름
+	KnownBad     bool   `json:"known_bad,omitempty"`     // 알려진 악성 IP (블록리스트 일치 또는 신뢰 점수 임계값 이상)
 }
 
 // NewLoginDetector 새로운 로그인 감지 서비스 생성
 // 10분 간격 알림 제한 기능이 포함된 고급 로그인 모니터링 서비스
 func NewLoginDetector(logger Logger) *LoginDetector {
+	denyPatterns, _ := compileDenyPatterns(DefaultSudoDenyPatterns)
 	return &LoginDetector{
 		logger:        logger,
 		systemMonitor: nil, // 나중에 SetSystemMonitor로 설정 가능
-		alertHistory:  make(map[string]time.Time), // 알림 히스토리 초기화
+		alertHistory:  NewAlertHistory(),           // 알림 히스토리 초기화
 		alertInterval: DefaultLoginAlertInterval,   // 기본 10분 간격
+		criticalInterval: CriticalAlertInterval,    // 기본 2분 간격
+		maxHistorySize:   MaxAlertHistorySize,      // 기본 100개
+		historyRetention: AlertHistoryCleanupInterval, // 기본 1시간
+		janitorInterval:  AlertHistoryJanitorInterval, // 기본 5분
+		throttleKey:   ThrottleKeyUserIP,           // 기본 사용자@IP 단위
+		sudoDenyPatterns: denyPatterns,             // 기본 위험 명령 패턴
+		failureCorrelator: NewFailureCorrelator(DefaultFailureBurstWindow, DefaultFailureBurstThreshold),
+		bruteForce:        NewBruteForceDetector(DefaultBruteForceWindow, DefaultBruteForceThreshold),
+		asnTracker:        NewASNTracker(),
+		anonymizers:       NewAnonymizerDetector(logger),
+		threatIntel:       NewThreatIntel(logger),
+	}
+}
+
+// SetSudoDenyPatterns sudo 위험 명령 정책 패턴 설정
+func (ld *LoginDetector) SetSudoDenyPatterns(patterns []string) error {
+	compiled, err := compileDenyPatterns(patterns)
+	if err != nil {
+		return err
+	}
+	ld.alertMutex.Lock()
+	ld.sudoDenyPatterns = compiled
+	ld.alertMutex.Unlock()
+	return nil
+}
+
+// SetTrustedNetworks 신뢰 네트워크 CIDR 목록 설정 ("이름=CIDR" 형식 지원)
+func (ld *LoginDetector) SetTrustedNetworks(specs []string) error {
+	trusted, err := ParseTrustedNetworks(specs)
+	if err != nil {
+		return err
+	}
+	ld.alertMutex.Lock()
+	ld.trustedNetworks = trusted
+	ld.alertMutex.Unlock()
+	return nil
+}
+
+// matchTrustedNetwork IP가 속한 신뢰 네트워크 이름 조회
+func (ld *LoginDetector) matchTrustedNetwork(ip string) (string, bool) {
+	ld.alertMutex.RLock()
+	trusted := ld.trustedNetworks
+	ld.alertMutex.RUnlock()
+	return trusted.Match(ip)
+}
+
+// SetASNHistoryStore 사용자별 ASN 히스토리 영구 저장소 설정 (저장된 히스토리 복원)
+func (ld *LoginDetector) SetASNHistoryStore(store ASNHistoryStore) error {
+	return ld.asnTracker.SetStore(store)
+}
+
+// SetFailureCorrelation 실패 후 성공 로그인 판정 기준 설정 (윈도우, 최소 실패 횟수)
+// 기존 실패 기록은 유지
+func (ld *LoginDetector) SetFailureCorrelation(window time.Duration, threshold int) {
+	ld.failureCorrelator.Configure(window, threshold)
+}
+
+// SetBruteForceDetection IP 단위 무차별 대입 판정 기준 설정 (윈도우, 최소 실패 횟수)
+// 기존 실패 기록은 유지
+func (ld *LoginDetector) SetBruteForceDetection(window time.Duration, threshold int) {
+	ld.bruteForce.Configure(window, threshold)
+}
+
+// ApplyConfig 설정 파일의 로그인 감지 설정 적용
+// 0 또는 빈 값은 현재 설정을 유지하며, 설정 재적용(reload) 시에도 알림 히스토리는 보존
+func (ld *LoginDetector) ApplyConfig(config *Config) error {
+	if config == nil {
+		return nil
+	}
+	loginConfig := config.Login
+
+	if len(loginConfig.SudoDenyPatterns) > 0 {
+		if err := ld.SetSudoDenyPatterns(loginConfig.SudoDenyPatterns); err != nil {
+			return err
+		}
+	}
+
+	if len(loginConfig.TrustedNetworks) > 0 {
+		if err := ld.SetTrustedNetworks(loginConfig.TrustedNetworks); err != nil {
+			return err
+		}
+	}
+
+	ld.SetFailureCorrelation(
+		time.Duration(loginConfig.FailureBurstWindow)*time.Minute,
+		loginConfig.FailureBurstThreshold,
+	)
+	ld.SetBruteForceDetection(
+		time.Duration(loginConfig.BruteForceWindow)*time.Minute,
+		loginConfig.BruteForceThreshold,
+	)
+	ld.asnTracker.Configure(loginConfig.HostingASNs, loginConfig.ASNBaselineLogins)
+	if err := ld.anonymizers.Configure(loginConfig.TorExitListURL, loginConfig.VPNListFiles); err != nil {
+		return err
+	}
+	if err := ld.threatIntel.Configure(loginConfig.ThreatBlocklistFiles, loginConfig.AbuseIPDBAPIKey, loginConfig.AbuseIPDBMinScore); err != nil {
+		return err
+	}
+
+	statusIntervals := make(map[string]time.Duration)
+	for status, minutes := range loginConfig.StatusIntervals {
+		statusIntervals[status] = time.Duration(minutes) * time.Minute
+	}
+	if err := ld.SetThrottlePolicy(loginConfig.ThrottleKey, statusIntervals); err != nil {
+		return err
+	}
+
+	ld.alertMutex.Lock()
+	defer ld.alertMutex.Unlock()
+	ld.anonymizerHighRisk = loginConfig.AnonymizerHighRisk
+	if loginConfig.AlertInterval > 0 {
+		ld.alertInterval = time.Duration(loginConfig.AlertInterval) * time.Minute
 	}
+	if loginConfig.CriticalInterval > 0 {
+		ld.criticalInterval = time.Duration(loginConfig.CriticalInterval) * time.Minute
+	}
+	if loginConfig.MaxAlertHistory > 0 {
+		ld.maxHistorySize = loginConfig.MaxAlertHistory
+	}
+	if loginConfig.HistoryRetention > 0 {
+		ld.historyRetention = time.Duration(loginConfig.HistoryRetention) * time.Minute
+	}
+	if loginConfig.HistoryCleanupInterval > 0 {
+		ld.janitorInterval = time.Duration(loginConfig.HistoryCleanupInterval) * time.Minute
+	}
+	return nil
+}
+
+// SetReverseDNS 출처 IP 역방향 DNS 조회기 설정 (nil 이면 조회하지 않음)
+func (ld *LoginDetector) SetReverseDNS(rd *ReverseDNS) {
+	ld.reverseDNS = rd
+}
+
+// SetGeoMapper IP 위치 조회에 사용할 지리정보 서비스 설정 (캐시 공유)
+func (ld *LoginDetector) SetGeoMapper(gm *GeoMapper) {
+	ld.geoMapper = gm
 }
 
 // SetSystemMonitor 시스템 모니터 설정 (리소스 정보 수집용)
@@ -100,90 +282,180 @@ func (ld *LoginDetector) SetAlertInterval(interval time.Duration) {
 	ld.alertInterval = interval
 }
 
+// AlertInterval 현재 기본 로그인 알림 간격 반환
+func (ld *LoginDetector) AlertInterval() time.Duration {
+	ld.alertMutex.RLock()
+	defer ld.alertMutex.RUnlock()
+	return ld.alertInterval
+}
+
+// SetThrottlePolicy 알림 제한 키와 상태별 알림 간격 설정
+// key가 비어 있으면 사용자@IP 단위, intervals에 없는 상태는 기본 간격 사용
+func (ld *LoginDetector) SetThrottlePolicy(key string, intervals map[string]time.Duration) error {
+	switch key {
+	case "":
+		key = ThrottleKeyUserIP
+	case ThrottleKeyUser, ThrottleKeyIP, ThrottleKeyUserIP, ThrottleKeySubnet:
+	default:
+		return fmt.Errorf("unsupported throttle key: %s (use user, ip, user_ip or subnet)", key)
+	}
+
+	ld.alertMutex.Lock()
+	defer ld.alertMutex.Unlock()
+	ld.throttleKey = key
+	ld.statusIntervals = intervals
+	return nil
+}
+
+// alertIntervalFor 이벤트 상태에 적용할 알림 간격
+// 신뢰 네트워크 출처는 기본 간격보다 자주 알리지 않음
+func (ld *LoginDetector) alertIntervalFor(loginInfo *LoginInfo) time.Duration {
+	interval := ld.alertInterval // 기본 10분 간격
+	if configured, exists := ld.statusIntervals[loginInfo.Status]; exists {
+		interval = configured
+	} else if !loginInfo.Success || loginInfo.Status == "sudo" {
+		// 중요한 이벤트는 더 짧은 간격으로 알림 (실패한 로그인, sudo 등)
+		interval = ld.criticalInterval // 기본 2분 간격
+	}
+
+	if loginInfo.TrustedNetwork != "" && interval < ld.alertInterval {
+		interval = ld.alertInterval
+	}
+	return interval
+}
+
+// alertKeyFor 설정된 제한 키 기준으로 알림 히스토리 키 생성
+// 상태별 간격이 서로 간섭하지 않도록 상태를 키에 포함
+func (ld *LoginDetector) alertKeyFor(loginInfo *LoginInfo) string {
+	var subject string
+	switch ld.throttleKey {
+	case ThrottleKeyUser:
+		subject = loginInfo.User
+	case ThrottleKeyIP:
+		subject = loginInfo.IP
+	case ThrottleKeySubnet:
+		subject = subnetOf(loginInfo.IP)
+	default:
+		subject = fmt.Sprintf("%s@%s", loginInfo.User, loginInfo.IP)
+	}
+
+	// IP가 없는 이벤트(sudo 등)는 사용자 단위로 대체
+	if subject == "" {
+		subject = loginInfo.User
+	}
+	return loginInfo.Status + "|" + subject
+}
+
+// subnetOf IP가 속한 서브넷 (IPv4 /24, IPv6 /64), 파싱 실패 시 원본 반환
+func subnetOf(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: parsed.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+}
+
 // shouldSendAlert 알림 전송 여부 확인 (10분 간격 제한 적용)
-// 동일한 사용자@IP 조합에 대해 설정된 간격 내에는 중복 알림 방지
+// 동일한 제한 키(기본 사용자@IP)에 대해 설정된 간격 내에는 중복 알림 방지
 func (ld *LoginDetector) shouldSendAlert(loginInfo *LoginInfo) bool {
-	// 중요한 이벤트는 더 짧은 간격으로 알림 (실패한 로그인, sudo 등)
-	var checkInterval time.Duration
-	if !loginInfo.Success || loginInfo.Status == "sudo" {
-		checkInterval = CriticalAlertInterval // 2분 간격
-	} else {
-		checkInterval = ld.alertInterval // 기본 10분 간격
-	}
-	
-	// 사용자@IP 조합으로 고유 키 생성
-	alertKey := fmt.Sprintf("%s@%s", loginInfo.User, loginInfo.IP)
-	
-	ld.alertMutex.RLock()
-	lastAlert, exists := ld.alertHistory[alertKey]
-	ld.alertMutex.RUnlock()
-	
+	ld.alertMutex.Lock()
+	defer ld.alertMutex.Unlock()
+
+	checkInterval := ld.alertIntervalFor(loginInfo)
+	alertKey := ld.alertKeyFor(loginInfo)
+	lastAlert, exists := ld.alertHistory.Get(alertKey)
+
 	now := time.Now()
-	
+
 	// 첫 번째 알림이거나 간격이 지난 경우 알림 전송
 	if !exists || now.Sub(lastAlert) >= checkInterval {
-		// 알림 히스토리 업데이트
-		ld.alertMutex.Lock()
-		ld.alertHistory[alertKey] = now
-		ld.alertMutex.Unlock()
-		
-		// 주기적으로 오래된 히스토리 정리
-		go ld.cleanupAlertHistory()
-		
+		// 알림 히스토리 업데이트 (최대 크기 초과 시 가장 오래된 항목 제거)
+		ld.alertHistory.Touch(alertKey, now)
+		ld.alertHistory.Prune(time.Time{}, ld.maxHistorySize)
 		return true
 	}
-	
+
 	return false
 }
 
+// StartHistoryJanitor 알림 히스토리 정리 작업 시작 (단일 백그라운드 고루틴)
+// 간격은 매 실행마다 다시 읽으므로 설정 변경이 다음 실행부터 반영됨, ctx 가 끝나면 중단
+func (ld *LoginDetector) StartHistoryJanitor(ctx context.Context) {
+	ld.janitorOnce.Do(func() {
+		go func() {
+			for {
+				ld.alertMutex.RLock()
+				interval := ld.janitorInterval
+				ld.alertMutex.RUnlock()
+
+				if !sleepContext(ctx, interval) {
+					return
+				}
+				ld.cleanupAlertHistory()
+			}
+		}()
+	})
+}
+
+// StartAnonymizerRefresh Tor 출구 노드 목록 주기적 갱신 시작 (ctx 가 끝나면 중단)
+func (ld *LoginDetector) StartAnonymizerRefresh(ctx context.Context) {
+	ld.anonymizers.StartRefresh(ctx)
+}
+
+// ManageTorList 데이터 업데이트가 Tor 목록을 관리하도록 내장 다운로드 중단, 기본 목록 파일 경로 반환
+func (ld *LoginDetector) ManageTorList() string {
+	return ld.anonymizers.ManageTorList()
+}
+
+// LoadTorListFile 파일의 Tor 출구 노드 목록으로 교체 (데이터 업데이트 적용)
+func (ld *LoginDetector) LoadTorListFile(path string) (int, error) {
+	return ld.anonymizers.LoadTorListFile(path)
+}
+
+// ShareAnonymizers 다른 감지기의 Tor/VPN 목록과 IP 평판 캐시를 함께 사용 (멀티 테넌트 모드에서 목록을 한 번만 갱신)
+func (ld *LoginDetector) ShareAnonymizers(other *LoginDetector) {
+	ld.anonymizers = other.anonymizers
+	ld.threatIntel = other.threatIntel
+}
+
+// ThreatIntelEnabled AbuseIPDB 조회 또는 위협 블록리스트가 설정되어 있는지 확인
+func (ld *LoginDetector) ThreatIntelEnabled() bool {
+	return ld.threatIntel.Enabled()
+}
+
+// WaitThreatChecks 진행 중인 AbuseIPDB 조회(로그인 기록/알림 포함)가 끝날 때까지 대기
+func (ld *LoginDetector) WaitThreatChecks() {
+	ld.threatIntel.Wait()
+}
+
 // cleanupAlertHistory 오래된 알림 히스토리 정리 (메모리 사용량 최적화)
 func (ld *LoginDetector) cleanupAlertHistory() {
+	now := time.Now()
+
+	// 윈도우를 벗어난 로그인 실패 기록도 함께 정리
+	ld.failureCorrelator.Cleanup(now)
+	ld.bruteForce.Cleanup(now)
+
 	ld.alertMutex.Lock()
 	defer ld.alertMutex.Unlock()
-	
-	now := time.Now()
-	cutoffTime := now.Add(-AlertHistoryCleanupInterval) // 1시간 이전 항목 삭제
-	
-	for key, timestamp := range ld.alertHistory {
-		if timestamp.Before(cutoffTime) {
-			delete(ld.alertHistory, key)
-		}
-	}
-	
-	// 히스토리 크기가 최대 크기를 초과하면 가장 오래된 항목들 삭제
-	if len(ld.alertHistory) > MaxAlertHistorySize {
-		// 타임스탬프 기준으로 정렬하여 오래된 항목부터 삭제
-		type alertEntry struct {
-			key       string
-			timestamp time.Time
-		}
-		
-		var entries []alertEntry
-		for key, timestamp := range ld.alertHistory {
-			entries = append(entries, alertEntry{key, timestamp})
-		}
-		
-		// 타임스탬프 순으로 정렬 (오래된 것부터)
-		for i := 0; i < len(entries)-1; i++ {
-			for j := i + 1; j < len(entries); j++ {
-				if entries[i].timestamp.After(entries[j].timestamp) {
-					entries[i], entries[j] = entries[j], entries[i]
-				}
-			}
-		}
-		
-		// 최대 크기를 초과하는 오래된 항목들 삭제
-		deleteCount := len(entries) - MaxAlertHistorySize
-		for i := 0; i < deleteCount; i++ {
-			delete(ld.alertHistory, entries[i].key)
-		}
-	}
+
+	// 보존 기간(기본 1시간) 이전 항목과 최대 크기 초과 항목을 오래된 순으로 삭제
+	ld.alertHistory.Prune(now.Add(-ld.historyRetention), ld.maxHistorySize)
 }
 
 // DetectLoginPattern 로그인 패턴 감지
 func (ld *LoginDetector) DetectLoginPattern(line string) (bool, *LoginInfo) {
 	line = strings.TrimSpace(line)
 
+	// Sudo/su 명령 실행 패턴 감지
+	// su 의 "session opened for user" 로그가 SSH 로그인으로 오인되지 않도록 먼저 검사
+	if loginInfo := ld.detectSudoCommand(line); loginInfo != nil {
+		return true, loginInfo
+	}
+
 	// SSH 로그인 성공 패턴 감지
 	if loginInfo := ld.detectSSHAccepted(line); loginInfo != nil {
 		return true, loginInfo
@@ -194,11 +466,6 @@ func (ld *LoginDetector) DetectLoginPattern(line string) (bool, *LoginInfo) {
 		return true, loginInfo
 	}
 
-	// Sudo 명령 실행 패턴 감지
-	if loginInfo := ld.detectSudoCommand(line); loginInfo != nil {
-		return true, loginInfo
-	}
-
 	// 웹 로그인 패턴 감지
 	if loginInfo := ld.detectWebLogin(line); loginInfo != nil {
 		return true, loginInfo
@@ -294,38 +561,40 @@ func (ld *LoginDetector) detectSSHFailed(line string) *LoginInfo {
 	return nil
 }
 
-// detectSudoCommand Sudo 명령 실행 패턴 감지
+// detectSudoCommand Sudo/su 명령 실행 패턴 감지
+// runas 사용자, TTY, 작업 디렉토리, 전체 명령어를 파싱하고 위험 명령 정책을 검사
 func (ld *LoginDetector) detectSudoCommand(line string) *LoginInfo {
-	patterns := []string{
-		`(\w+) : TTY=\w+ ; PWD=.* ; USER=\w+ ; COMMAND=(.*)`,
-		`sudo: (\w+) : (.*)`,
-		`su: pam_unix.*session opened for user (\w+)`,
+	entry := parseSudoLine(line)
+	if entry == nil {
+		return nil
 	}
 
-	for _, pattern := range patterns {
-		re := regexp.MustCompile(pattern)
-		matches := re.FindStringSubmatch(line)
-		if len(matches) >= 2 {
-			loginInfo := &LoginInfo{
-				Status:  "sudo",
-				Success: true,
-				Method:  "sudo",
-			}
-
-			if len(matches) >= 3 {
-				loginInfo.User = matches[1]
-				loginInfo.Command = matches[2]
-			} else {
-				loginInfo.User = matches[1]
-			}
-
-			// 시스템 메트릭과 IP 정보 추가
-			ld.enhanceLoginInfo(loginInfo)
-			return loginInfo
-		}
+	loginInfo := &LoginInfo{
+		Status:     "sudo",
+		Success:    !entry.Denied,
+		Method:     "sudo",
+		User:       entry.User,
+		Command:    entry.Command,
+		RunAs:      entry.RunAs,
+		TTY:        entry.TTY,
+		CWD:        entry.CWD,
+		Shell:      entry.Shell,
+		DenyReason: entry.Reason,
 	}
+	if entry.IsSu {
+		loginInfo.Method = "su"
+	}
+	if entry.Denied {
+		loginInfo.Status = "sudo_denied"
+	}
+	ld.alertMutex.RLock()
+	denyPatterns := ld.sudoDenyPatterns
+	ld.alertMutex.RUnlock()
+	loginInfo.PolicyViolation = matchDenyPattern(entry.Command, denyPatterns)
 
-	return nil
+	// 시스템 메트릭과 IP 정보 추가
+	ld.enhanceLoginInfo(loginInfo)
+	return loginInfo
 }
 
 // detectWebLogin 웹 로그인 패턴 감지
@@ -447,84 +716,134 @@ func (ld *LoginDetector) collectSystemMetrics() SystemMetrics {
 		return ld.systemMonitor.GetCurrentMetrics()
 	}
 	
-	// 시스템 모니터가 없으면 임시 모니터 생성하여 즉시 수집
+	// 시스템 모니터가 없으면 임시 모니터 생성하여 즉시 수집 (loginMetricsReuse 동안 재사용)
+	ld.metricsMutex.Lock()
+	defer ld.metricsMutex.Unlock()
+	if !ld.metricsSnapshotAt.IsZero() && time.Since(ld.metricsSnapshotAt) < loginMetricsReuse {
+		return ld.metricsSnapshot
+	}
 	tempMonitor := NewSystemMonitor(time.Second) // 즉시 수집용
 	tempMonitor.collectMetrics()
-	return tempMonitor.GetCurrentMetrics()
+	ld.metricsSnapshot = tempMonitor.GetCurrentMetrics()
+	ld.metricsSnapshotAt = time.Now()
+	return ld.metricsSnapshot
 }
 
-// getIPLocationInfo IP 주소의 지리적 위치 및 상세 정보 조회
-// 무료 IP 지리정보 API를 사용하여 실시간 조회
-func (ld *LoginDetector) getIPLocationInfo(ip string) *IPLocationInfo {
-	if ip == "" {
-		return nil
+// ResolveLocation 로그인 IP 위치 정보를 GeoMapper 캐시/비동기 조회로 채우고 ASN 변경을 확인한 뒤 done 호출
+// 캐시 적중, 사설 IP, 신뢰 네트워크 출처, IP 없는 이벤트는 호출한 고루틴에서 즉시 done 을 실행하고 false 반환
+// 외부 조회가 필요하면 조회 고루틴에서 done 을 실행하고 true 반환 (그동안 loginInfo 를 수정하면 안 됨)
+// AbuseIPDB 조회가 필요한 공인 IP는 평판 조회를 마친 뒤 위치 조회 진행
+func (ld *LoginDetector) ResolveLocation(loginInfo *LoginInfo, done func(*LoginInfo)) bool {
+	if loginInfo.IP != "" && loginInfo.TrustedNetwork == "" && !ld.isPrivateIP(loginInfo.IP) {
+		async := false
+		pending := ld.threatIntel.CheckAsync(loginInfo.IP, func() {
+			async = ld.resolveHostname(loginInfo, done)
+		})
+		return pending || async
 	}
-	
-	// 사설 IP 주소 체크
-	isPrivate := ld.isPrivateIP(ip)
-	
-	ipInfo := &IPLocationInfo{
-		IP:        ip,
-		IsPrivate: isPrivate,
+	return ld.resolveHostname(loginInfo, done)
+}
+
+// resolveHostname 출처 IP 의 PTR 호스트 이름을 채운 뒤 위치 조회 (신뢰/사설 네트워크 포함, ResolveLocation 과 같은 반환 규칙)
+func (ld *LoginDetector) resolveHostname(loginInfo *LoginInfo, done func(*LoginInfo)) bool {
+	async := false
+	pending := ld.reverseDNS.LookupAsync(loginInfo.IP, func(record PTRRecord) {
+		loginInfo.ReverseDNS = record
+		async = ld.resolveGeoLocation(loginInfo, done)
+	})
+	return pending || async
+}
+
+// resolveGeoLocation GeoMapper 캐시/비동기 조회로 위치 정보를 채운 뒤 done 호출 (ResolveLocation 과 같은 반환 규칙)
+func (ld *LoginDetector) resolveGeoLocation(loginInfo *LoginInfo, done func(*LoginInfo)) bool {
+	if loginInfo.IP == "" || loginInfo.TrustedNetwork != "" || ld.geoMapper == nil {
+		if loginInfo.IP != "" && loginInfo.TrustedNetwork == "" {
+			ld.applyLocation(loginInfo, nil)
+		}
+		done(loginInfo)
+		return false
 	}
-	
-	// 사설 IP는 지리정보 조회 생략
-	if isPrivate {
+
+	return ld.geoMapper.LookupAsync(loginInfo.IP, func(geo *GeoLocationInfo) {
+		ld.applyLocation(loginInfo, geo)
+		done(loginInfo)
+	})
+}
+
+// applyLocation 조회한 지리정보(nil 이면 조회 실패)로 IPDetails 를 채우고 위험도 조정 후 ASN 변경 확인
+func (ld *LoginDetector) applyLocation(loginInfo *LoginInfo, geo *GeoLocationInfo) {
+	ipInfo := &IPLocationInfo{IP: loginInfo.IP}
+
+	switch {
+	case geo == nil:
+		ipInfo.Threat = "UNKNOWN"
+		ld.flagAnonymizer(ipInfo, false)
+		ld.flagThreatIntel(loginInfo, ipInfo)
+	case geo.IsPrivate:
+		// 사설 IP는 지리정보 조회 생략
+		ipInfo.IsPrivate = true
 		ipInfo.Country = "Private Network"
 		ipInfo.Organization = "Private IP Range"
 		ipInfo.Threat = "LOW"
-		return ipInfo
+	default:
+		ipInfo.Country = geo.Country
+		ipInfo.Region = geo.Region
+		ipInfo.City = geo.City
+		ipInfo.Organization = geo.Organization
+		ipInfo.ASN = geo.ASN
+		ipInfo.Threat = geo.Threat
+		ld.flagAnonymizer(ipInfo, geo.Proxy)
+		ld.flagThreatIntel(loginInfo, ipInfo)
 	}
-	
-	// 외부 API로 지리정보 조회 (5초 타임아웃)
-	client := &http.Client{Timeout: 5 * time.Second}
-	url := fmt.Sprintf("http://ip-api.com/json/%s?fields=status,country,regionName,city,org,as,query", ip)
-	
-	resp, err := client.Get(url)
-	if err != nil {
-		ld.logger.Errorf("Failed to query IP location for %s: %v", ip, err)
-		ipInfo.Threat = "UNKNOWN"
-		return ipInfo
+
+	// 연속 실패 직후 성공한 로그인은 위치와 무관하게 HIGH
+	if loginInfo.BruteForceSuspected {
+		ipInfo.Threat = "HIGH"
 	}
-	defer resp.Body.Close()
-	
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		ld.logger.Errorf("Failed to read IP location response: %v", err)
-		ipInfo.Threat = "UNKNOWN"
-		return ipInfo
+	loginInfo.IPDetails = ipInfo
+
+	// 사용자별 ASN 히스토리 대비 호스팅 사업자 ASN 변경 확인 (변경 시 알림 간격 제한과 무관하게 알림)
+	ld.checkASNChange(loginInfo)
+	if loginInfo.ASNChange != nil {
+		loginInfo.ShouldAlert = true
 	}
-	
-	var result struct {
-		Status     string `json:"status"`
-		Country    string `json:"country"`
-		RegionName string `json:"regionName"`
-		City       string `json:"city"`
-		Org        string `json:"org"`
-		AS         string `json:"as"`
-		Query      string `json:"query"`
+}
+
+// flagAnonymizer Tor 출구 노드 / VPN 데이터셋 확인 후 익명화 출처 표시
+// 목록에 없으면 ip-api 의 proxy 판별을 사용하고, anonymizer_high_risk 설정 시 위험도를 HIGH로 상향
+func (ld *LoginDetector) flagAnonymizer(ipInfo *IPLocationInfo, apiProxy bool) {
+	ipInfo.Anonymizer = ld.anonymizers.Match(ipInfo.IP)
+	if ipInfo.Anonymizer == "" && apiProxy {
+		ipInfo.Anonymizer = AnonymizerProxy
 	}
-	
-	if err := json.Unmarshal(body, &result); err != nil {
-		ld.logger.Errorf("Failed to parse IP location response: %v", err)
-		ipInfo.Threat = "UNKNOWN"
-		return ipInfo
+	if ipInfo.Anonymizer == "" {
+		return
 	}
-	
-	if result.Status == "success" {
-		ipInfo.Country = result.Country
-		ipInfo.Region = result.RegionName
-		ipInfo.City = result.City
-		ipInfo.Organization = result.Org
-		ipInfo.ASN = result.AS
-		
-		// 간단한 위험도 평가
-		ipInfo.Threat = ld.assessThreatLevel(result.Country, result.Org)
-	} else {
-		ipInfo.Threat = "UNKNOWN"
+
+	ld.alertMutex.RLock()
+	highRisk := ld.anonymizerHighRisk
+	ld.alertMutex.RUnlock()
+	if highRisk {
+		ipInfo.Threat = "HIGH"
+	}
+}
+
+// flagThreatIntel 블록리스트/AbuseIPDB 평판을 IPDetails 에 기록하고 알려진 악성 IP는 위험도를 HIGH로 상향
+// 알려진 악성 IP에서 성공한 로그인은 알림 간격 제한과 무관하게 알림
+func (ld *LoginDetector) flagThreatIntel(loginInfo *LoginInfo, ipInfo *IPLocationInfo) {
+	reputation := ld.threatIntel.Reputation(ipInfo.IP)
+	ipInfo.AbuseScore = reputation.AbuseScore
+	ipInfo.AbuseReports = reputation.AbuseReports
+	ipInfo.Blocklist = reputation.Blocklist
+	ipInfo.KnownBad = reputation.KnownBad
+	if !reputation.KnownBad {
+		return
+	}
+
+	ipInfo.Threat = "HIGH"
+	if loginInfo.Success {
+		loginInfo.ShouldAlert = true
 	}
-	
-	return ipInfo
 }
 
 // isPrivateIP IP 주소가 사설 IP인지 확인
@@ -556,34 +875,6 @@ func (ld *LoginDetector) isPrivateIP(ipStr string) bool {
 	return false
 }
 
-// assessThreatLevel 국가와 조직 정보를 바탕으로 위험도 평가
-func (ld *LoginDetector) assessThreatLevel(country, org string) string {
-	// 한국 내부 IP는 LOW
-	if country == "South Korea" || country == "Korea" {
-		return "LOW"
-	}
-	
-	// 알려진 클라우드 서비스는 MEDIUM
-	cloudProviders := []string{"Amazon", "Google", "Microsoft", "Azure", "AWS"}
-	orgLower := strings.ToLower(org)
-	for _, provider := range cloudProviders {
-		if strings.Contains(orgLower, strings.ToLower(provider)) {
-			return "MEDIUM"
-		}
-	}
-	
-	// 일반적으로 의심스러운 국가들
-	suspiciousCountries := []string{"China", "Russia", "North Korea"}
-	for _, suspicious := range suspiciousCountries {
-		if country == suspicious {
-			return "HIGH"
-		}
-	}
-	
-	// 기본적으로 해외 IP는 MEDIUM
-	return "MEDIUM"
-}
-
 // enhanceLoginInfo 로그인 정보에 시스템 메트릭과 IP 정보 추가
 // 10분 간격 알림 제한 로직도 적용
 func (ld *LoginDetector) enhanceLoginInfo(loginInfo *LoginInfo) {
@@ -593,13 +884,72 @@ func (ld *LoginDetector) enhanceLoginInfo(loginInfo *LoginInfo) {
 	// 시스템 리소스 정보 수집
 	loginInfo.SystemInfo = ld.collectSystemMetrics()
 	
-	// IP 위치 정보 조회 (비동기로 처리하지 않고 즉시 처리)
+	// 신뢰 네트워크 출처는 외부 API 조회와 위험도 평가 생략
+	// 그 밖의 IP 위치 정보와 ASN 변경 확인은 ResolveLocation 에서 처리 (로그 처리가 외부 조회를 기다리지 않도록)
 	if loginInfo.IP != "" {
-		loginInfo.IPDetails = ld.getIPLocationInfo(loginInfo.IP)
+		if name, trusted := ld.matchTrustedNetwork(loginInfo.IP); trusted {
+			loginInfo.TrustedNetwork = name
+			loginInfo.IPDetails = &IPLocationInfo{
+				IP:           loginInfo.IP,
+				Country:      "Trusted Network",
+				Organization: name,
+				IsPrivate:    ld.isPrivateIP(loginInfo.IP),
+				Threat:       "TRUSTED",
+			}
+		}
 	}
 	
-	// 알림 전송 여부 확인 (10분 간격 제한 적용)
-	loginInfo.ShouldAlert = ld.shouldSendAlert(loginInfo)
+	// 실패 → 성공 시퀀스 상관 분석
+	ld.correlateFailures(loginInfo)
+
+	// 알림 전송 여부 확인 (10분 간격 제한 적용, 정책 위반/무차별 대입 성공 의심은 항상 알림)
+	loginInfo.ShouldAlert = ld.shouldSendAlert(loginInfo) || loginInfo.PolicyViolation != "" ||
+		loginInfo.BruteForceSuspected
+}
+
+// checkASNChange 성공한 로그인의 ASN을 사용자 히스토리에 기록하고,
+// 평소와 다른 호스팅 사업자 ASN이면 위험도를 HIGH로 상향 (신뢰 네트워크 출처는 제외)
+func (ld *LoginDetector) checkASNChange(loginInfo *LoginInfo) {
+	if !loginInfo.Success || loginInfo.TrustedNetwork != "" || loginInfo.IPDetails == nil {
+		return
+	}
+
+	change := ld.asnTracker.Observe(loginInfo.User, loginInfo.IPDetails.ASN, loginInfo.IPDetails.Organization, loginInfo.Timestamp)
+	if change == nil {
+		return
+	}
+
+	loginInfo.ASNChange = change
+	loginInfo.IPDetails.Threat = "HIGH"
+	ld.logger.Errorf("🚨 %s authenticated from hosting provider %s (%s), usual ASNs: %s",
+		loginInfo.User, change.ASN, change.Provider, strings.Join(change.UsualASNs, ", "))
+}
+
+// correlateFailures 로그인 실패를 기록하고, 성공한 로그인이 실패 버스트 직후인지 판정
+// 판정되면 위험도를 HIGH로 상향
+func (ld *LoginDetector) correlateFailures(loginInfo *LoginInfo) {
+	switch loginInfo.Status {
+	case "failed":
+		ld.failureCorrelator.RecordFailure(loginInfo.User, loginInfo.IP, loginInfo.Timestamp)
+		if attack := ld.bruteForce.RecordFailure(loginInfo.User, loginInfo.IP, loginInfo.Timestamp); attack != nil {
+			loginInfo.BruteForceAttack = attack
+			ld.logger.Errorf("🚨 Brute-force attack from %s: %d failed logins within %v (users: %s)",
+				attack.IP, attack.Failures, attack.Window, strings.Join(attack.Users, ", "))
+		}
+	case "accepted", "web_login":
+		failures, suspicious := ld.failureCorrelator.CheckSuccess(loginInfo.User, loginInfo.IP, loginInfo.Timestamp)
+		loginInfo.PriorFailures = failures
+		if !suspicious {
+			return
+		}
+
+		loginInfo.BruteForceSuspected = true
+		if loginInfo.IPDetails != nil {
+			loginInfo.IPDetails.Threat = "HIGH"
+		}
+		ld.logger.Errorf("🚨 Successful login for %s from %s after %d failed attempts within %v",
+			loginInfo.User, loginInfo.IP, failures, ld.failureCorrelator.Window())
+	}
 }
 
 // ConvertToMap LoginInfo를 map으로 변환 (기존 코드 호환성)
@@ -613,6 +963,39 @@ func (li *LoginInfo) ToMap() map[string]string {
 		"command":   li.Command,
 		"timestamp": li.Timestamp.Format("2006-01-02 15:04:05"),
 	}
+
+	// sudo/su 상세 정보 추가
+	if li.RunAs != "" {
+		result["runas"] = li.RunAs
+	}
+	if li.TTY != "" {
+		result["tty"] = li.TTY
+	}
+	if li.CWD != "" {
+		result["cwd"] = li.CWD
+	}
+	if li.Shell {
+		result["shell"] = "true"
+	}
+	if li.DenyReason != "" {
+		result["deny_reason"] = li.DenyReason
+	}
+	if li.PolicyViolation != "" {
+		result["policy_violation"] = li.PolicyViolation
+	}
+	if li.PriorFailures > 0 {
+		result["prior_failures"] = fmt.Sprintf("%d", li.PriorFailures)
+	}
+	if li.BruteForceSuspected {
+		result["brute_force_suspected"] = "true"
+	}
+	if li.TrustedNetwork != "" {
+		result["trusted_network"] = li.TrustedNetwork
+	}
+	if li.ASNChange != nil {
+		result["asn_change"] = fmt.Sprintf("%s (%s)", li.ASNChange.ASN, li.ASNChange.Provider)
+		result["usual_asns"] = strings.Join(li.ASNChange.UsualASNs, ", ")
+	}
 	
 	// 시스템 정보 추가
 	result["cpu_usage"] = fmt.Sprintf("%.1f%%", li.SystemInfo.CPU.UsagePercent)
@@ -641,6 +1024,15 @@ func (li *LoginInfo) ToMap() map[string]string {
 		result["ip_org"] = li.IPDetails.Organization
 		result["ip_threat"] = li.IPDetails.Threat
 		result["ip_private"] = fmt.Sprintf("%t", li.IPDetails.IsPrivate)
+		if li.IPDetails.Anonymizer != "" {
+			result["ip_anonymizer"] = li.IPDetails.Anonymizer
+		}
+		if li.IPDetails.AbuseScore > 0 {
+			result["ip_abuse_score"] = fmt.Sprintf("%d", li.IPDetails.AbuseScore)
+		}
+		if li.IPDetails.Blocklist != "" {
+			result["ip_blocklist"] = li.IPDetails.Blocklist
+		}
 	}
 	
 	return result
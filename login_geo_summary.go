@@ -0,0 +1,303 @@
+/*
+Login Geo Summary Module
+========================
+
+정기 보고서용 로그인 출처 위치 요약
+
+주요 기능:
+- 보고 주기 동안 감지된 로그인 출처 IP 수집 (시도/실패 횟수, 사용자)
+- 캐시된 GeoIP 정보로 일괄 위치 조회 (GeoMapper.GetLocationInfoBatch)
+- 신뢰 네트워크 출처는 위치 조회 없이 "Trusted Network" 로 집계
+- 국가/도시별 집계 및 직전 주기 대비 새로운 위치 탐지
+- 지도 스냅샷 링크 생성 (geojson.io)
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"sort"    // 집계 결과 정렬
+	"strings" // 문자열 처리
+	"sync"    // 동기화 (뮤텍스)
+	"time"    // 시간 처리
+)
+
+// 보고서 표시 개수 제한
+const (
+	LoginGeoTopCountries = 10 // 보고서에 표시할 최대 국가 수
+	LoginGeoTopCities    = 10 // 보고서에 표시할 최대 도시 수
+)
+
+// loginSource 보고 주기 동안 한 IP에서 발생한 로그인 통계
+type loginSource struct {
+	attempts int
+	failures int
+	users    map[string]bool
+	trusted  string // 신뢰 네트워크 이름 (비어 있으면 위치 조회 대상)
+}
+
+// LoginGeoTracker 보고 주기별 로그인 출처 수집기
+type LoginGeoTracker struct {
+	sources       map[string]*loginSource // IP -> 이번 주기 로그인 통계
+	lastLocations map[string]bool         // 직전 주기에 관측된 위치 (도시, 국가)
+	hasBaseline   bool                    // 비교할 직전 주기가 있는지 여부
+	periodStart   time.Time
+	mutex         sync.Mutex
+}
+
+// GeoCount 위치별 로그인 집계
+type GeoCount struct {
+	Name     string
+	Logins   int
+	Failures int
+}
+
+// LoginGeoSummary 보고 주기의 로그인 출처 위치 요약
+type LoginGeoSummary struct {
+	PeriodStart  time.Time
+	PeriodEnd    time.Time
+	TotalLogins  int
+	UniqueIPs    int
+	Countries    []GeoCount // 로그인 수 내림차순
+	Cities       []GeoCount // 로그인 수 내림차순
+	NewLocations []string   // 직전 주기에 없던 위치
+	HasBaseline  bool       // 직전 주기 비교 가능 여부 (첫 주기는 false)
+	Unresolved   int        // 위치 조회에 실패한 IP 수
+	MapURL       string     // 지도 스냅샷 링크 (공인 IP가 없으면 빈 문자열)
+}
+
+// NewLoginGeoTracker 새로운 로그인 출처 수집기 생성
+func NewLoginGeoTracker() *LoginGeoTracker {
+	return &LoginGeoTracker{
+		sources:     make(map[string]*loginSource),
+		periodStart: time.Now(),
+	}
+}
+
+// Record 로그인 이벤트 기록 (IP가 없는 로컬 이벤트는 제외)
+func (lt *LoginGeoTracker) Record(info *LoginInfo) {
+	if info == nil || info.IP == "" {
+		return
+	}
+
+	lt.mutex.Lock()
+	defer lt.mutex.Unlock()
+
+	source, exists := lt.sources[info.IP]
+	if !exists {
+		source = &loginSource{users: make(map[string]bool)}
+		lt.sources[info.IP] = source
+	}
+	source.attempts++
+	if !info.Success {
+		source.failures++
+	}
+	if info.User != "" {
+		source.users[info.User] = true
+	}
+	if info.TrustedNetwork != "" {
+		source.trusted = info.TrustedNetwork
+	}
+}
+
+// Summarize 이번 주기 요약 생성 후 새 주기 시작
+// 위치 조회는 캐시 우선 일괄 조회로 처리하며, 조회 중에는 잠금을 잡지 않음
+func (lt *LoginGeoTracker) Summarize(geoMapper *GeoMapper) *LoginGeoSummary {
+	now := time.Now()
+
+	lt.mutex.Lock()
+	sources := lt.sources
+	lastLocations := lt.lastLocations
+	hasBaseline := lt.hasBaseline
+	summary := &LoginGeoSummary{
+		PeriodStart: lt.periodStart,
+		PeriodEnd:   now,
+		HasBaseline: hasBaseline,
+	}
+	lt.sources = make(map[string]*loginSource)
+	lt.periodStart = now
+	lt.mutex.Unlock()
+
+	ips := make([]string, 0, len(sources))
+	var lookupIPs []string
+	for ip, source := range sources {
+		ips = append(ips, ip)
+		summary.TotalLogins += source.attempts
+		if source.trusted == "" {
+			lookupIPs = append(lookupIPs, ip)
+		}
+	}
+	sort.Strings(ips)
+	summary.UniqueIPs = len(ips)
+
+	// 신뢰 네트워크 출처는 외부 API 조회 생략
+	var locations map[string]*GeoLocationInfo
+	if geoMapper != nil && len(lookupIPs) > 0 {
+		locations = geoMapper.GetLocationInfoBatch(lookupIPs)
+	}
+
+	countries := make(map[string]*GeoCount)
+	cities := make(map[string]*GeoCount)
+	seen := make(map[string]bool)
+	loginCounts := make(map[string]int, len(ips))
+	var mapped []*GeoLocationInfo
+
+	for _, ip := range ips {
+		source := sources[ip]
+		loginCounts[ip] = source.attempts
+
+		if source.trusted != "" {
+			addGeoCount(countries, "Trusted Network", source)
+			place := "Trusted Network: " + source.trusted
+			addGeoCount(cities, place, source)
+			seen[place] = true
+			continue
+		}
+
+		location := locations[ip]
+		if location == nil || location.Country == "" {
+			summary.Unresolved++
+			continue
+		}
+		mapped = append(mapped, location)
+
+		addGeoCount(countries, location.Country, source)
+		place := locationName(location)
+		addGeoCount(cities, place, source)
+		seen[place] = true
+	}
+
+	summary.Countries = sortedGeoCounts(countries)
+	summary.Cities = sortedGeoCounts(cities)
+
+	if hasBaseline {
+		for place := range seen {
+			if !lastLocations[place] {
+				summary.NewLocations = append(summary.NewLocations, place)
+			}
+		}
+		sort.Strings(summary.NewLocations)
+	}
+
+	if geoMapper != nil {
+		summary.MapURL = geoMapper.GenerateMapSnapshotURL(mapped, loginCounts)
+	}
+
+	lt.mutex.Lock()
+	lt.lastLocations = seen
+	lt.hasBaseline = true
+	lt.mutex.Unlock()
+
+	return summary
+}
+
+// addGeoCount 위치별 집계에 로그인 통계 합산
+func addGeoCount(counts map[string]*GeoCount, name string, source *loginSource) {
+	count, exists := counts[name]
+	if !exists {
+		count = &GeoCount{Name: name}
+		counts[name] = count
+	}
+	count.Logins += source.attempts
+	count.Failures += source.failures
+}
+
+// sortedGeoCounts 로그인 수 내림차순(동률이면 이름순) 정렬
+func sortedGeoCounts(counts map[string]*GeoCount) []GeoCount {
+	result := make([]GeoCount, 0, len(counts))
+	for _, count := range counts {
+		result = append(result, *count)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Logins != result[j].Logins {
+			return result[i].Logins > result[j].Logins
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// locationName 도시 단위 위치 이름 ("도시, 국가", 도시 정보가 없으면 국가)
+func locationName(location *GeoLocationInfo) string {
+	if location.City == "" {
+		return location.Country
+	}
+	return fmt.Sprintf("%s, %s", location.City, location.Country)
+}
+
+// CountryList 상위 국가 목록 문자열 (예: "South Korea(12), United States(3)")
+func (s *LoginGeoSummary) CountryList() string {
+	return formatGeoCounts(s.Countries, LoginGeoTopCountries)
+}
+
+// FormatText 보고서 본문용 로그인 출처 위치 섹션
+func (s *LoginGeoSummary) FormatText() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("🌍 로그인 출처 위치 (%s ~ %s):\n",
+		s.PeriodStart.Format("01-02 15:04"), s.PeriodEnd.Format("01-02 15:04")))
+
+	if s.TotalLogins == 0 {
+		sb.WriteString("   이번 주기에 감지된 원격 로그인이 없습니다.\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("   로그인 시도: %d회 (출처 IP %d개", s.TotalLogins, s.UniqueIPs))
+	if s.Unresolved > 0 {
+		sb.WriteString(fmt.Sprintf(", 위치 미확인 %d개", s.Unresolved))
+	}
+	sb.WriteString(")\n")
+
+	sb.WriteString("   국가별:\n")
+	writeGeoCountLines(&sb, s.Countries, LoginGeoTopCountries)
+	sb.WriteString("   도시별:\n")
+	writeGeoCountLines(&sb, s.Cities, LoginGeoTopCities)
+
+	switch {
+	case !s.HasBaseline:
+		sb.WriteString("   새로운 위치: 첫 보고 주기 (비교 대상 없음)\n")
+	case len(s.NewLocations) == 0:
+		sb.WriteString("   새로운 위치: 없음\n")
+	default:
+		sb.WriteString(fmt.Sprintf("   🆕 새로운 위치 (직전 주기 대비): %s\n", strings.Join(s.NewLocations, "; ")))
+	}
+
+	if s.MapURL != "" {
+		sb.WriteString(fmt.Sprintf("   🗺️  지도: %s\n", s.MapURL))
+	}
+	return sb.String()
+}
+
+// writeGeoCountLines 위치별 집계 목록을 보고서 줄 단위로 출력
+func writeGeoCountLines(sb *strings.Builder, counts []GeoCount, limit int) {
+	if len(counts) == 0 {
+		sb.WriteString("      정보 없음\n")
+		return
+	}
+	for i, count := range counts {
+		if i >= limit {
+			sb.WriteString(fmt.Sprintf("      ... 외 %d곳\n", len(counts)-limit))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("      %s: %d회", count.Name, count.Logins))
+		if count.Failures > 0 {
+			sb.WriteString(fmt.Sprintf(" (실패 %d회)", count.Failures))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// formatGeoCounts 위치별 집계를 한 줄 문자열로 변환
+func formatGeoCounts(counts []GeoCount, limit int) string {
+	if len(counts) == 0 {
+		return "없음"
+	}
+	parts := make([]string, 0, limit)
+	for i, count := range counts {
+		if i >= limit {
+			parts = append(parts, fmt.Sprintf("+%d", len(counts)-limit))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s(%d)", count.Name, count.Logins))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,397 @@
+/*
+Lookup Tables Module
+====================
+
+로컬 CSV/JSON 조회 테이블로 파싱된 이벤트 보강 (logging.lookup_tables)
+
+내부 IP → 호스트명/담당자, 사용자명 → 부서처럼 로그에 없는 정보를
+알림과 Elasticsearch/Kafka/구조화 출력 전에 ParsedLog.Fields 와 로그인 알림에 추가합니다.
+
+주요 기능:
+- CSV (첫 줄 헤더) / JSON (객체 배열 또는 키 → 객체 맵) 테이블
+- 이벤트 필드(match, 기본은 키 열 이름) 값으로 키 열을 조회해 나머지 열을 "<prefix><열>" 필드로 추가 (기본 prefix "<name>.")
+- 키가 CIDR 이면 IP 가 속한 네트워크로 조회 (정확히 일치하는 키가 우선, 가장 긴 접두사 우선)
+- ignore_case 로 대소문자 무시 조회 (사용자명 등)
+- 테이블 파일이 바뀌면 30초 안에 다시 읽음 (설정 재로드 불필요, 읽기 실패 시 기존 내용 유지)
+
+예:
+
+	{"name": "asset", "path": "~/.syslog-monitor/assets.csv", "key": "ip", "match": ["client_ip", "ip"]}
+	{"name": "user", "path": "/etc/syslog-monitor/users.json", "key": "username", "match": ["user"], "ignore_case": true}
+*/
+package main
+
+import (
+	"encoding/csv"  // CSV 테이블
+	"encoding/json" // JSON 테이블
+	"fmt"           // 형식화된 I/O
+	"net"           // CIDR 키
+	"os"            // 파일 처리
+	"path/filepath" // 경로 처리
+	"sort"          // CIDR 키 정렬
+	"strconv"       // 숫자 값 변환
+	"strings"       // 문자열 처리
+	"sync"          // 동기화
+	"time"          // 파일 변경 확인
+)
+
+// lookupTableCheckInterval 테이블 파일 변경 확인 간격
+const lookupTableCheckInterval = 30 * time.Second
+
+// LookupTable 조회 테이블 설정 (설정 파일 logging.lookup_tables 항목)
+type LookupTable struct {
+	Name       string   `json:"name"`        // 테이블 이름 (기본 필드 접두사, 비어 있으면 파일 이름)
+	Path       string   `json:"path"`        // CSV/JSON 파일 경로 (확장자로 형식 판별)
+	Key        string   `json:"key"`         // 키 열 이름 (CSV 기본: 첫 번째 열, JSON 기본: "key")
+	Match      []string `json:"match"`       // 조회에 사용할 이벤트 필드 (앞에서부터 처음 일치한 값, 기본: 키 열 이름)
+	Columns    []string `json:"columns"`     // 추가할 열 (비어 있으면 키를 제외한 모든 열)
+	Prefix     string   `json:"prefix"`      // 추가 필드 접두사 (비어 있으면 "<name>.")
+	IgnoreCase bool     `json:"ignore_case"` // 대소문자 무시 조회
+}
+
+// lookupCIDREntry CIDR 키 행
+type lookupCIDREntry struct {
+	network *net.IPNet
+	row     map[string]string
+}
+
+// loadedLookupTable 읽어 들인 조회 테이블
+type loadedLookupTable struct {
+	config  LookupTable
+	path    string
+	prefix  string
+	match   []string
+	rows    map[string]map[string]string // 키 → 열 → 값
+	cidrs   []lookupCIDREntry            // 접두사가 긴 순서
+	modTime time.Time
+}
+
+// LookupTables 조회 테이블 집합 (파싱 워커에서 동시에 사용)
+type LookupTables struct {
+	tables    []*loadedLookupTable
+	checkedAt time.Time
+	logger    Logger
+	mutex     sync.RWMutex
+}
+
+// LoadLookupTables 조회 테이블 설정 검증 후 파일 로드 (하나라도 실패하면 에러)
+func LoadLookupTables(configs []LookupTable, logger Logger) (*LookupTables, error) {
+	lt := &LookupTables{checkedAt: time.Now(), logger: logger}
+	for i, config := range configs {
+		if strings.TrimSpace(config.Path) == "" {
+			return nil, fmt.Errorf("lookup table #%d: path is empty", i+1)
+		}
+		table := &loadedLookupTable{config: config, path: expandHomePath(config.Path)}
+		if config.Name == "" {
+			table.config.Name = strings.TrimSuffix(filepath.Base(table.path), filepath.Ext(table.path))
+		}
+		if err := table.load(); err != nil {
+			return nil, err
+		}
+		lt.tables = append(lt.tables, table)
+	}
+	return lt, nil
+}
+
+// equalLookupTables 두 조회 테이블 설정이 같은지 확인 (설정 재로드)
+func equalLookupTables(a, b []LookupTable) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Path != b[i].Path || a[i].Key != b[i].Key ||
+			a[i].Prefix != b[i].Prefix || a[i].IgnoreCase != b[i].IgnoreCase ||
+			strings.Join(a[i].Match, "\x00") != strings.Join(b[i].Match, "\x00") ||
+			strings.Join(a[i].Columns, "\x00") != strings.Join(b[i].Columns, "\x00") {
+			return false
+		}
+	}
+	return true
+}
+
+// Len 테이블 수
+func (lt *LookupTables) Len() int {
+	if lt == nil {
+		return 0
+	}
+	return len(lt.tables)
+}
+
+// Enrich 이벤트 필드로 모든 테이블을 조회해 추가할 필드 반환 (일치하지 않으면 nil)
+func (lt *LookupTables) Enrich(fields map[string]string) map[string]string {
+	if lt == nil || len(lt.tables) == 0 || len(fields) == 0 {
+		return nil
+	}
+	lt.reloadChanged()
+
+	lt.mutex.RLock()
+	defer lt.mutex.RUnlock()
+	var enriched map[string]string
+	for _, table := range lt.tables {
+		for _, field := range table.match {
+			row := table.lookup(fields[field])
+			if row == nil {
+				continue
+			}
+			if enriched == nil {
+				enriched = make(map[string]string)
+			}
+			for column, value := range row {
+				enriched[table.prefix+column] = value
+			}
+			break
+		}
+	}
+	return enriched
+}
+
+// EnrichParsedLog ParsedLog.Fields 와 source 로 테이블을 조회해 Fields 에 추가
+func (lt *LookupTables) EnrichParsedLog(parsed *ParsedLog) {
+	if lt == nil || parsed == nil || len(lt.tables) == 0 {
+		return
+	}
+	fields := parsed.Fields
+	if parsed.Source != "" && fields["source"] == "" {
+		fields = make(map[string]string, len(parsed.Fields)+1)
+		for name, value := range parsed.Fields {
+			fields[name] = value
+		}
+		fields["source"] = parsed.Source
+	}
+
+	enriched := lt.Enrich(fields)
+	if len(enriched) == 0 {
+		return
+	}
+	if parsed.Fields == nil {
+		parsed.Fields = make(map[string]string, len(enriched))
+	}
+	for name, value := range enriched {
+		parsed.Fields[name] = value
+	}
+}
+
+// reloadChanged 확인 간격이 지났으면 수정 시각이 바뀐 테이블 파일을 다시 읽음
+func (lt *LookupTables) reloadChanged() {
+	lt.mutex.RLock()
+	due := time.Since(lt.checkedAt) >= lookupTableCheckInterval
+	lt.mutex.RUnlock()
+	if !due {
+		return
+	}
+
+	lt.mutex.Lock()
+	defer lt.mutex.Unlock()
+	if time.Since(lt.checkedAt) < lookupTableCheckInterval {
+		return // 다른 워커가 먼저 확인함
+	}
+	lt.checkedAt = time.Now()
+	for _, table := range lt.tables {
+		info, err := os.Stat(table.path)
+		if err != nil || info.ModTime().Equal(table.modTime) {
+			continue
+		}
+		if err := table.load(); err != nil {
+			lt.logger.Errorf("Failed to reload lookup table %s, keeping previous rows: %v", table.config.Name, err)
+			continue
+		}
+		lt.logger.Infof("📇 Lookup table %s reloaded: %d row(s)", table.config.Name, len(table.rows)+len(table.cidrs))
+	}
+}
+
+// load 테이블 파일 읽기 (실패하면 기존 내용 유지)
+func (t *loadedLookupTable) load() error {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return fmt.Errorf("lookup table %s: %v", t.config.Name, err)
+	}
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return fmt.Errorf("lookup table %s: %v", t.config.Name, err)
+	}
+
+	key := t.config.Key
+	var records []map[string]string
+	switch strings.ToLower(filepath.Ext(t.path)) {
+	case ".csv":
+		records, key, err = parseLookupCSV(data, key)
+	case ".json":
+		if key == "" {
+			key = "key"
+		}
+		records, err = parseLookupJSON(data, key)
+	default:
+		err = fmt.Errorf("unsupported file type (use .csv or .json)")
+	}
+	if err != nil {
+		return fmt.Errorf("lookup table %s: %v", t.config.Name, err)
+	}
+
+	rows := make(map[string]map[string]string, len(records))
+	var cidrs []lookupCIDREntry
+	for _, record := range records {
+		value := strings.TrimSpace(record[key])
+		if value == "" {
+			continue
+		}
+		row := make(map[string]string)
+		for column, cell := range record {
+			if column == key || (len(t.config.Columns) > 0 && !containsString(t.config.Columns, column)) {
+				continue
+			}
+			row[column] = cell
+		}
+		if strings.Contains(value, "/") {
+			if _, network, err := net.ParseCIDR(value); err == nil {
+				cidrs = append(cidrs, lookupCIDREntry{network: network, row: row})
+				continue
+			}
+		}
+		if t.config.IgnoreCase {
+			value = strings.ToLower(value)
+		}
+		rows[value] = row
+	}
+	sort.SliceStable(cidrs, func(i, j int) bool {
+		a, _ := cidrs[i].network.Mask.Size()
+		b, _ := cidrs[j].network.Mask.Size()
+		return a > b
+	})
+
+	t.rows, t.cidrs, t.modTime = rows, cidrs, info.ModTime()
+	t.prefix = t.config.Prefix
+	if t.prefix == "" {
+		t.prefix = t.config.Name + "."
+	}
+	t.match = t.config.Match
+	if len(t.match) == 0 {
+		t.match = []string{key}
+	}
+	return nil
+}
+
+// lookup 키로 행 조회 (정확히 일치하는 키가 없으면 IP 가 속한 CIDR)
+func (t *loadedLookupTable) lookup(value string) map[string]string {
+	if value = strings.TrimSpace(value); value == "" {
+		return nil
+	}
+	key := value
+	if t.config.IgnoreCase {
+		key = strings.ToLower(value)
+	}
+	if row, ok := t.rows[key]; ok {
+		return row
+	}
+	if len(t.cidrs) > 0 {
+		if ip := net.ParseIP(value); ip != nil {
+			for _, entry := range t.cidrs {
+				if entry.network.Contains(ip) {
+					return entry.row
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parseLookupCSV 헤더가 있는 CSV 를 행 목록으로 변환 (key 가 비어 있으면 첫 번째 열)
+func parseLookupCSV(data []byte, key string) ([]map[string]string, string, error) {
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	lines, err := reader.ReadAll()
+	if err != nil {
+		return nil, "", err
+	}
+	if len(lines) == 0 {
+		return nil, "", fmt.Errorf("missing header row")
+	}
+
+	header := lines[0]
+	for i := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff"))
+	}
+	if key == "" {
+		key = header[0]
+	} else if !containsString(header, key) {
+		return nil, "", fmt.Errorf("key column %q not found in header", key)
+	}
+
+	records := make([]map[string]string, 0, len(lines)-1)
+	for _, line := range lines[1:] {
+		record := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(line) && column != "" {
+				record[column] = strings.TrimSpace(line[i])
+			}
+		}
+		records = append(records, record)
+	}
+	return records, key, nil
+}
+
+// parseLookupJSON 객체 배열 또는 키 → 객체(값) 맵 JSON 을 행 목록으로 변환
+func parseLookupJSON(data []byte, key string) ([]map[string]string, error) {
+	var array []map[string]interface{}
+	if err := json.Unmarshal(data, &array); err == nil {
+		records := make([]map[string]string, 0, len(array))
+		for _, object := range array {
+			records = append(records, stringifyLookupRow(object))
+		}
+		return records, nil
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("expected an array of objects or an object keyed by %s: %v", key, err)
+	}
+	records := make([]map[string]string, 0, len(object))
+	for name, value := range object {
+		var record map[string]string
+		if nested, ok := value.(map[string]interface{}); ok {
+			record = stringifyLookupRow(nested)
+		} else {
+			record = map[string]string{"value": stringifyLookupValue(value)}
+		}
+		record[key] = name
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// stringifyLookupRow JSON 객체의 값을 문자열로 변환 (중첩 값은 JSON 문자열)
+func stringifyLookupRow(object map[string]interface{}) map[string]string {
+	row := make(map[string]string, len(object))
+	for name, value := range object {
+		row[name] = stringifyLookupValue(value)
+	}
+	return row
+}
+
+// stringifyLookupValue JSON 값을 필드 문자열로 변환
+func stringifyLookupValue(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(typed)
+	default:
+		encoded, _ := json.Marshal(typed)
+		return string(encoded)
+	}
+}
+
+// containsString 목록에 값이 있는지 확인
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -18,16 +18,20 @@ AI-Powered Syslog Monitor
 package main
 
 import (
+	"context"  // 종료 신호 전파
+	"encoding/json" // 히스토리 JSON 출력
 	"flag"     // 명령줄 인수 파싱
 	"fmt"      // 형식화된 I/O
+	"io"       // 출력 인터페이스
 	"os"       // 운영체제 인터페이스
 	"os/exec"  // 외부 명령 실행
 	"os/signal" // 시그널 처리
 	"path/filepath" // 파일 경로 처리
-	"regexp"   // 정규식
 	"runtime"  // Go 런타임 정보
+	"sort"     // 정렬
 	"strconv"  // 문자열-숫자 변환
 	"strings"  // 문자열 처리
+	"sync"     // 종료 시 백그라운드 작업 대기
 	"syscall"  // 시스템 호출
 	"time"     // 시간 처리
 
@@ -39,7 +43,6 @@ import (
 var (
 	// 설정 서비스
 	configService *ConfigService
-	geminiService *GeminiService
 )
 
 // EmailConfig 이메일 서비스 설정 구조체
@@ -52,6 +55,10 @@ type EmailConfig struct {
 	To           []string // 수신자 이메일 주소 목록 (여러 명에게 동시 전송 가능)
 	From         string   // 발신자 이메일 주소
 	Enabled      bool     // 이메일 서비스 활성화 여부
+	DKIMDomain   string   // DKIM 서명 도메인 (비어 있으면 발신자 주소의 도메인)
+	DKIMSelector string   // DKIM 셀렉터 (<selector>._domainkey.<domain> TXT 레코드)
+	DKIMKeyFile  string   // DKIM PEM 개인 키 파일 (셀렉터와 함께 지정하면 서명 활성화)
+	OAuth2       *EmailOAuth2Config // XOAUTH2 인증 설정 (refresh token 이 있으면 비밀번호 대신 사용)
 }
 
 // SlackConfig Slack 웹훅 서비스 설정 구조체
@@ -63,6 +70,13 @@ type SlackConfig struct {
 	Enabled    bool   // Slack 서비스 활성화 여부
 }
 
+// TelegramConfig Telegram 봇 알림 설정 구조체
+type TelegramConfig struct {
+	BotToken string // @BotFather 에서 발급받은 봇 토큰
+	ChatID   string // 알림을 받을 채팅 ID (개인, 그룹, 채널)
+	Enabled  bool   // Telegram 서비스 활성화 여부
+}
+
 // SlackMessage Slack API 메시지 구조체
 // Slack Incoming Webhooks API 스펙에 맞는 메시지 포맷
 type SlackMessage struct {
@@ -70,6 +84,7 @@ type SlackMessage struct {
 	Username    string             `json:"username,omitempty"`    // 봇 사용자명
 	Text        string             `json:"text,omitempty"`        // 메인 메시지 텍스트
 	IconEmoji   string             `json:"icon_emoji,omitempty"`  // 봇 아이콘 이모지 (:warning:, :robot_face:)
+	IconURL     string             `json:"icon_url,omitempty"`    // 봇 아이콘 이미지 URL (icon_emoji 대신)
 	Attachments []SlackAttachment  `json:"attachments,omitempty"` // 첨부된 상세 정보 블록들
 }
 
@@ -81,6 +96,7 @@ type SlackAttachment struct {
 	Text      string       `json:"text,omitempty"`      // 첨부 블록의 본문 텍스트
 	Fields    []SlackField `json:"fields,omitempty"`    // 구조화된 필드 목록 (키-값 쌍)
 	Timestamp int64        `json:"ts,omitempty"`        // Unix 타임스탬프 (메시지 하단에 시간 표시)
+	Blocks    []SlackBlock `json:"blocks,omitempty"`    // Block Kit 블록 (알림 확인/끄기 버튼, slack_actions.go)
 }
 
 // SlackField Slack 첨부 블록 내의 개별 필드 구조체
@@ -95,25 +111,92 @@ type SlackField struct {
 // 실시간 로그 감시, AI 분석, 알림 전송 등의 모든 기능을 통합 관리
 type SyslogMonitor struct {
 	logFile       string            // 모니터링할 로그 파일 경로 (/var/log/syslog, /var/log/system.log 등)
-	filters       []string          // 제외할 로그 패턴의 정규식 목록 (노이즈 필터링용)
-	keywords      []string          // 포함할 키워드 목록 (특정 패턴만 감시)
+	filter        *LogFilter        // 제외할 로그 패턴의 사전 컴파일된 정규식 목록 (노이즈 필터링용)
+	keywords      *KeywordMatcher   // 포함할 키워드 목록 (특정 패턴만 감시)
 	outputFile    string            // 필터링된 로그 출력 파일 경로 (빈 문자열이면 stdout)
+	output        *OutputFile       // 로거 출력 파일 (-output, 크기 기준 교체, 구조화 출력이면 nil)
+	selfWatch     *SelfWatch        // 출력 파일의 모니터 자신의 오류 감시 (-output-self-watch)
 	logger        *logrus.Logger    // 구조화된 로깅을 위한 logrus 인스턴스
 	emailService  *EmailService     // 이메일 알림 서비스 (Gmail SMTP 지원)
 	slackService  *SlackService     // Slack 웹훅 알림 서비스
+	alertDispatcher *AlertDispatcher // 설정된 모든 알림 채널로 팬아웃하는 중앙 디스패처
 	loginDetector *LoginDetector    // SSH/sudo 등 로그인 패턴 감지 서비스
 	aiAnalyzer    *AIAnalyzer       // AI 기반 이상 탐지 및 예측 분석 엔진
 	systemMonitor *SystemMonitor    // CPU/메모리/디스크 등 시스템 리소스 모니터링
+	bootDetector  *BootDetector     // 재부팅 감지 및 부팅 보고서 서비스
 	logParser     *LogParserManager // 다양한 로그 포맷 파싱 (Apache, Nginx, MySQL 등)
+	severityPolicy *SeverityPolicy  // 로그 줄의 레벨과 알림 동작 결정 (설정 파일 severity_policy)
 	aiEnabled     bool              // AI 분석 기능 활성화 여부
 	systemEnabled bool              // 시스템 모니터링 기능 활성화 여부
 	loginWatch    bool              // 로그인 감지 기능 활성화 여부
+	journaldInput bool              // systemd-journald 입력 모드 (파일 대신 journalctl 구독)
+	journaldUnits []string          // journald 입력 시 구독할 systemd 유닛 (비어 있으면 전체)
+	eventLogInput bool              // Windows 이벤트 로그 입력 모드 (파일 대신 wevtutil 폴링)
+	eventLogChannels []string       // 이벤트 로그 입력 시 구독할 채널 (System, Security 등)
+	multiline     *MultilineAssembler // 여러 줄 엔트리 조립기 (파일 입력 전용, nil 이면 줄 단위 처리)
+	multilineSource string          // 조립 중인 엔트리의 마지막 줄을 읽은 소스 (처리 비용 집계용)
+	replayPaths   []string          // 재처리 입력 파일/glob (비어 있지 않으면 tail 대신 한 번 읽고 종료)
+	replayOptions ReplayOptions     // 재처리 워커 수, 드라이런, 요약 보고서 경로
+	kubeOptions   *KubeOptions      // 쿠버네티스 파드 로그 입력 설정 (nil 이면 사용 안 함)
+	listenTargets []string          // 네트워크 syslog 수신 주소 (udp://, tcp://; 비어 있으면 사용 안 함)
+	listenAllow   *TrustedNetworks  // syslog 수신 허용 발신 주소 (nil 이면 모두 허용)
 	
 	// 주기적 보고서 관련 필드
 	periodicReport   bool          // 주기적 보고서 기능 활성화 여부
 	reportInterval   time.Duration // 보고서 전송 간격
+	reportSchedule   *ReportSchedule // 시간대 기반 보고서 스케줄 (nil이면 reportInterval 사용)
+	reportArchiver   *ReportArchiver // 보고서 파일 저장 서비스 (nil 가능)
 	lastReportTime   time.Time     // 마지막 보고서 전송 시간
 	geoMapper        *GeoMapper    // 지리정보 매핑 서비스
+	reverseDNS       *ReverseDNS   // 출처 IP PTR 조회 (nil 이면 조회하지 않음)
+	whoisLookup      *WhoisLookup  // 무차별 대입 알림의 abuse 연락처 조회 (nil 이면 조회하지 않음)
+	sharedGeoMapper  bool          // 다른 모니터의 지리정보 서비스를 공유 중 (종료 시 닫지 않음)
+	loginGeoTracker  *LoginGeoTracker // 정기 보고서용 로그인 출처 수집기 (로그인 감지 비활성화 시 nil)
+	eventStore       *EventStore   // 알림/이벤트 히스토리 저장소 (-db-path 미지정 시 nil)
+	esOutput         *ElasticsearchOutput // Elasticsearch/OpenSearch 색인 출력 (-es-url 미지정 시 nil)
+	kafkaOutput      *KafkaOutput         // Kafka 토픽 발행 출력 (-kafka-brokers 미지정 시 nil)
+	forwarder        *SyslogForwarder     // 상위 syslog 서버 전달 출력 (-forward 미지정 시 nil)
+	eventSampler     *EventSampler        // 학습용 층화 표본 추출기 (-sample-export 미지정 시 nil)
+	structuredOutput *StructuredWriter    // json/ndjson 레코드 출력기 (text 형식이면 nil)
+	configWatcher    *ConfigWatcher       // 설정 파일 변경 / SIGHUP 감시자 (nil이면 재로드 안 함)
+	trustedNetworkSpecs []string          // -trusted-networks 플래그로 지정한 신뢰 네트워크 (재로드 후 다시 적용)
+	apiServer        *APIServer           // 관리 REST API 서버 (-api-port 미지정 시 nil)
+	dashboard        *Dashboard           // 웹 대시보드 (-dashboard 미지정 시 nil)
+	ipBlocker        *IPBlocker           // 무차별 대입 공격 IP 자동 차단기 (-block-action 미지정 시 nil)
+	dbDetector       *DBPrivilegeDetector // DB 권한 변경/관리자 인증 실패 감지기 (-db-watch 미지정 시 nil)
+	cicdDetector     *CICDDetector        // CI/CD 반복 실패/러너 등록/비밀 노출 감지기 (-cicd-watch 미지정 시 nil)
+	sqliCorrelator   *SQLInjectionCorrelator // 웹 SQL 인젝션 시도와 DB 증거 상관 분석기 (-sqli-confirm 미지정 시 nil)
+	sqliDBLogs       []string             // 상관 분석 증거로만 읽는 추가 DB 로그 파일 (-sqli-db-log)
+	sqliTails        []*tail.Tail         // 추가 DB 로그 tail (종료 시 정리)
+	modSecurity      *ModSecurityCorrelator // ModSecurity 감사 로그와 접근 로그 상관 분석기 (-modsec-audit-log 미지정 시 nil)
+	modSecurityLog   string               // ModSecurity 감사 로그 파일
+	modSecurityTail  *tail.Tail           // 감사 로그 tail (종료 시 정리)
+	healthChecks     *HealthCheckFilter   // 로드밸런서 헬스 체크 요청 제외 (-suppress-health-checks=false 이면 nil)
+	exfilDetector    *ExfiltrationDetector // 웹 응답 크기 이상 (데이터 유출) 탐지기 (-exfil-watch 미지정 시 nil)
+	accessStats      *AccessStats         // 웹 접근 로그 통계 전용 집계기 (-access-stats 미지정 시 nil)
+	sloTracker       *SLOTracker          // 엔드포인트 SLO 오류 예산 추적기 (SLO 를 정의하지 않으면 nil)
+	sloFromFlag      bool                 // -slo 플래그로 SLO 를 지정함 (설정 재로드 시 유지)
+	parserStats      *ParserStats         // 파서별 처리 줄 수와 소스별 형식 미인식 비율
+	sourceUsage      *SourceUsageTracker  // 소스/파서별 줄 수, 바이트, 단계별 처리 시간
+	latencyTracer    *LatencyTracer       // 표본 이벤트의 단계별 지연 시간 추적기 (-trace-sample 미지정 시 nil)
+	workers          int                  // 파싱/분석 워커 수 (0 이면 처리 루프에서 직접 처리)
+	pipeline         *LogPipeline         // 워커 풀 처리 파이프라인 (workers 가 0 이면 nil)
+	tenants          *TenantManager       // 멀티 테넌트 모드의 테넌트별 모니터 (-tenants 미지정 시 nil)
+	leaderElection   *LeaderElection      // 고가용성 리더 선출 (리더만 알림 전송, -ha-lock 미지정 시 nil)
+	deliveryJournal  *DeliveryJournal     // 알림 전송 선기록 저널 (-delivery-journal 미지정 시 nil)
+	rulesPath        string               // 사용자 정의 이상 패턴 규칙 파일 (설정 재로드 시 다시 읽음)
+	dataUpdater      *DataUpdater         // 규칙/Tor/GeoIP 데이터 자동 업데이트 (data_updates 미설정 시 nil)
+	llmBatcher       *LLMLogBatcher       // AI 이상 로그 묶음 LLM 분석 (AI 분석을 끄면 nil, scheduler.log_analysis 로 켜기)
+	alertActions     *AlertActionLinks    // 알림 이메일 끄기/확인 링크 서명기 (alerts.actions.base_url 미설정 시 링크 없음)
+	slackActions     *SlackActions        // Slack 확인/끄기 버튼 (slack.signing_secret 미설정 시 버튼 없음)
+	slackCommands    *SlackCommands       // Slack 슬래시 명령 /sysmon (slack.signing_secret 미설정 시 사용 안 함)
+	maintenance      *MaintenanceSchedule // 외부 캘린더 점검 일정 (maintenance.calendars 미설정 시 nil)
+	digest           *DailyDigest         // 일일 요약 (reports.digest.schedule 또는 -digest-schedule 미설정 시 집계 안 함)
+	canary           *Canary              // 알림 채널 카나리 (-canary 미지정 시 nil)
+	controls         chan func()          // 처리 고루틴에서 실행할 설정 변경 요청 (관리 API)
+	startedAt        time.Time            // 모니터 시작 시각 (가동 시간 계산)
+	cancel           context.CancelFunc   // Start 의 context 취소 (shutdown 시 백그라운드 작업 중단)
+	loops            sync.WaitGroup       // shutdown 이 기다리는 백그라운드 고루틴 (시스템 알림 처리, 정기 보고서)
 }
 
 // NewSyslogMonitor SyslogMonitor 인스턴스 생성자
@@ -136,15 +219,22 @@ func NewSyslogMonitor(logFile, outputFile string, filters, keywords []string, em
 	// 구조화된 로깅 설정
 	logger := logrus.New()
 	logger.SetLevel(logrus.InfoLevel)
+	logger.ExitFunc = func(code int) { // Fatal 이 어디서 호출되더라도 모니터를 종료하지 않음 (기록만)
+		fmt.Fprintf(os.Stderr, "⚠️  로그 기록 중 종료 요청(코드 %d)을 무시했습니다\n", code)
+	}
 	logger.SetFormatter(&logrus.TextFormatter{
 		FullTimestamp:   true,                   // 전체 타임스탬프 표시
 		TimestampFormat: "2006-01-02 15:04:05", // 한국 표준 시간 포맷
 	})
 
-	// 로그 출력 파일 설정 (지정된 경우)
+	// 로그 출력 파일 설정 (지정된 경우, 크기 기준 교체와 제한된 권한)
+	var output *OutputFile
 	if outputFile != "" {
-		file, err := os.OpenFile(outputFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-		if err == nil {
+		file, err := OpenOutputFile(outputFile, outputFileOptions)
+		if err != nil {
+			fmt.Printf("⚠️  출력 파일을 열 수 없어 표준 출력을 사용합니다: %v\n", err)
+		} else {
+			output = file
 			logger.SetOutput(file) // 파일로 로그 출력 리다이렉션
 		}
 	}
@@ -155,10 +245,14 @@ func NewSyslogMonitor(logFile, outputFile string, filters, keywords []string, em
 	var loginDetector *LoginDetector // 로그인 패턴 감지 서비스
 	var aiAnalyzer *AIAnalyzer       // AI 이상 탐지 분석기
 	var systemMonitor *SystemMonitor // 시스템 리소스 모니터
+	var bootDetector *BootDetector   // 재부팅 감지 서비스
 
 	// 이메일 서비스 초기화 (설정이 존재하고 활성화된 경우)
 	if emailConfig != nil && emailConfig.Enabled {
 		emailService = NewEmailService(emailConfig, logger)
+		if configService != nil {
+			emailService.SetRefreshTokenStore(configService.UpdateEmailRefreshToken)
+		}
 	}
 
 	// Slack 서비스 초기화 (설정이 존재하고 활성화된 경우)
@@ -166,14 +260,93 @@ func NewSyslogMonitor(logFile, outputFile string, filters, keywords []string, em
 		slackService = NewSlackService(slackConfig, logger)
 	}
 
+	// 알림 디스패처 초기화 (이메일, Slack 등 설정된 채널로 팬아웃)
+	alertDispatcher := NewAlertDispatcher(logger)
+	alertActions := NewAlertActionLinks()
+	slackActions := NewSlackActions()
+	slackCommands := NewSlackCommands(slackActions)
+	var maintenance *MaintenanceSchedule
+	if emailService != nil {
+		emailService.SetActionLinks(alertActions)
+		alertDispatcher.AddSink("email", emailService)
+	}
+	if slackService != nil {
+		slackService.SetActions(slackActions)
+		alertDispatcher.AddSink("slack", slackService)
+	}
+
+	// 채널별 알림 상세 수준 (summary/full) 적용
+	if configService != nil {
+		if err := alertDispatcher.SetDetailLevels(configService.GetConfig().Alerts.Detail); err != nil {
+			logger.Errorf("Invalid alert detail levels in config, keeping defaults: %v", err)
+		}
+		// 알림 유형별 중복 알림 제한 간격
+		if err := alertDispatcher.SetThrottleIntervals(configService.GetConfig().Alerts.Intervals); err != nil {
+			logger.Errorf("Invalid alert intervals in config, keeping defaults: %v", err)
+		}
+		// 알림 라우팅 규칙
+		if err := alertDispatcher.SetRoutes(configService.GetConfig().Routes); err != nil {
+			logger.Errorf("Invalid alert routes in config, sending alerts to every channel: %v", err)
+		}
+		// 서비스 의존 관계 (근본 원인 추정)
+		if err := alertDispatcher.SetDependencies(configService.GetConfig().Dependencies); err != nil {
+			logger.Errorf("Invalid service dependencies in config, root-cause hints disabled: %v", err)
+		}
+		// 외부 캘린더 점검 일정 (점검 중 알림 심각도 낮추기/묶기)
+		if schedule, err := NewMaintenanceSchedule(configService.GetConfig().Maintenance, alertDispatcher.Dispatch, logger); err != nil {
+			logger.Errorf("Invalid maintenance calendars in config, maintenance windows disabled: %v", err)
+		} else {
+			maintenance = schedule
+			alertDispatcher.SetMaintenance(schedule)
+		}
+		// 조용한 시간 / 고정 점검 창 (채널별로 중요하지 않은 알림 보류 후 창이 끝날 때 요약)
+		if quiet, err := NewQuietHours(configService.GetConfig().QuietHours, alertDispatcher.deliverTo, logger); err != nil {
+			logger.Errorf("Invalid quiet hours in config, quiet hours disabled: %v", err)
+		} else {
+			alertDispatcher.SetQuietHours(quiet)
+		}
+		// 알림 이메일 끄기/확인 링크
+		actions := configService.GetConfig().Alerts.Actions
+		secret, _ := ResolveSecret(SecretAlertActionKey, "", actions.Secret)
+		if err := alertActions.SetConfig(actions, secret); err != nil {
+			logger.Errorf("Invalid alert action links in config, emails are sent without snooze links: %v", err)
+		}
+		// Slack 확인/끄기 버튼 (Slack 앱 Signing Secret)
+		signingSecret, _ := ResolveSecret(SecretSlackSigningSecret, "", configService.GetConfig().Slack.SigningSecret)
+		slackActions.SetSecret(signingSecret)
+		slackCommands.SetUsers(configService.GetConfig().Slack.CommandUsers)
+		// Slack 채널/알림 유형별 표시 방식
+		if slackService != nil {
+			if styles, err := configService.GetConfig().SlackStyles(); err != nil {
+				logger.Errorf("Invalid slack styles in config, keeping default branding: %v", err)
+			} else {
+				slackService.SetStyles(styles)
+			}
+		}
+	}
+
 	// 로그인 감지 서비스 초기화 (loginWatch 플래그가 true인 경우)
 	if loginWatch {
 		loginDetector = NewLoginDetector(logger)
+
+		// 설정 파일의 로그인 감지 설정 적용 (sudo 정책, 실패 상관 분석, 알림 제한 키/간격, 히스토리)
+		if configService != nil {
+			if err := loginDetector.ApplyConfig(configService.GetConfig()); err != nil {
+				logger.Errorf("Invalid login settings in config, keeping defaults: %v", err)
+			}
+		}
 	}
 
 	// AI 분석 엔진 초기화 (aiEnabled 플래그가 true인 경우)
 	if aiEnabled {
 		aiAnalyzer = NewAIAnalyzer()
+
+		// 서비스별 통계 기준선 설정
+		if configService != nil {
+			if err := aiAnalyzer.SetBaselineConfig(configService.GetConfig().AI.Baseline); err != nil {
+				logger.Errorf("Invalid baseline settings in config, keeping defaults: %v", err)
+			}
+		}
 	}
 
 	// 시스템 모니터링 서비스 초기화 (systemEnabled 플래그가 true인 경우)
@@ -186,13 +359,70 @@ func NewSyslogMonitor(logFile, outputFile string, filters, keywords []string, em
 			DefaultMonitoringInterval, // 5분 간격 모니터링
 			periodicReport,            // 정기 보고서 활성화 여부
 			reportIntervalDuration,    // 보고서 간격
-			emailService,              // 이메일 서비스
-			slackService,              // Slack 서비스
+			alertDispatcher,           // 알림 디스패처
 		)
+
+		// 설정 파일의 임계값 적용 (코어당 로드 임계값 포함)
+		if configService != nil {
+			systemMonitor.ApplyConfig(configService.GetConfig())
+		}
+
+		// 재부팅 감지 서비스 (부팅 후 감시 서비스 복구 여부 확인 포함)
+		bootDetector = NewBootDetector(logger, alertDispatcher)
+		if configService != nil {
+			bootDetector.SetWatchedServices(configService.GetConfig().SystemMonitoring.WatchedServices)
+		}
+	}
+
+	// 다중 로그 파서 관리자 초기화 (설정 파일의 사용자 정의 nginx log_format, 필드 추출 규칙, 사용자 정의 파서 포함)
+	logParser := NewLogParserManager()
+	if configService != nil {
+		if err := logParser.SetNginxLogFormats(configService.GetConfig().Logging.NginxLogFormats); err != nil {
+			logger.Errorf("Invalid nginx log formats in config, ignoring: %v", err)
+		}
+		if err := logParser.SetExtractionRules(configService.GetConfig().Logging.ExtractionRules); err != nil {
+			logger.Errorf("Invalid extraction rules in config, ignoring: %v", err)
+		}
+		if err := logParser.SetCustomParsers(configService.GetConfig().Logging.CustomParsers); err != nil {
+			logger.Errorf("Invalid custom parsers in config, ignoring: %v", err)
+		}
+		if tables, err := LoadLookupTables(configService.GetConfig().Logging.LookupTables, logger); err != nil {
+			logger.Errorf("Invalid lookup tables in config, ignoring: %v", err)
+		} else {
+			logParser.SetLookupTables(tables)
+		}
 	}
 
-	// 지리정보 매핑 서비스 초기화
+	// 심각도 정책 (규칙이 없거나 잘못되면 내장 키워드 판정)
+	severityPolicy := NewSeverityPolicy()
+	if configService != nil {
+		if err := severityPolicy.SetConfig(configService.GetConfig().SeverityPolicy); err != nil {
+			logger.Errorf("Invalid severity policy in config, using built-in levels: %v", err)
+		}
+	}
+
+	// 헬스 체크 요청 제외 필터 (설정 파일에 목록이 없으면 기본 경로/User-Agent)
+	var healthChecks *HealthCheckFilter
+	if configService != nil {
+		healthChecks = NewHealthCheckFilter(configService.GetConfig().Logging.HealthCheckPaths, configService.GetConfig().Logging.HealthCheckUserAgents)
+	} else {
+		healthChecks = NewHealthCheckFilter(nil, nil)
+	}
+
+	// 지리정보 매핑 서비스 초기화 (로그인 IP 위치와 AI 분석 ASN 조회가 캐시를 공유)
 	geoMapper := NewGeoMapper(logger)
+	if loginDetector != nil {
+		loginDetector.SetGeoMapper(geoMapper)
+	}
+	if aiAnalyzer != nil {
+		aiAnalyzer.SetGeoMapper(geoMapper)
+	}
+
+	// 정기 보고서용 로그인 출처 수집기 초기화
+	var loginGeoTracker *LoginGeoTracker
+	if loginDetector != nil {
+		loginGeoTracker = NewLoginGeoTracker()
+	}
 
 	// 로그인 감지기에 시스템 모니터 연결 (리소스 정보 수집용)
 	if loginDetector != nil && systemMonitor != nil {
@@ -206,19 +436,54 @@ func NewSyslogMonitor(logFile, outputFile string, filters, keywords []string, em
 		logger.Infof("📝 Login alert interval set to: %d minutes", alertInterval)
 	}
 
+	// 필터는 줄마다 다시 컴파일하지 않도록 미리 컴파일 (잘못된 패턴만 제외)
+	filter, err := CompileLogFilter(filters)
+	if err != nil {
+		logger.Errorf("Ignoring %v", err)
+	}
+
+	// AI 이상 로그 묶음 LLM 분석 (ai_analysis.scheduler.log_analysis)
+	var llmBatcher *LLMLogBatcher
+	if aiEnabled {
+		llmBatcher = NewLLMLogBatcher(alertDispatcher.Dispatch, logger)
+		if configService != nil {
+			llmBatcher.SetConfig(configService.GetConfig().AI.Scheduler)
+		}
+	}
+
+	// 일일 요약 (reports.digest, 스케줄이 없으면 집계하지 않음)
+	digest := NewDailyDigest(alertDispatcher.Dispatch, logger)
+	alertDispatcher.SetDigest(digest)
+	if systemMonitor != nil {
+		digest.SetMetricsSource(systemMonitor.GetMetricsHistory)
+	}
+	if configService != nil {
+		if err := digest.SetConfig(configService.GetConfig().Reports.Digest); err != nil {
+			logger.Errorf("Invalid daily digest settings in config, digest disabled: %v", err)
+		}
+	}
+
 	// SyslogMonitor 인스턴스 생성 및 반환
 	return &SyslogMonitor{
 		logFile:       logFile,                   // 모니터링 대상 로그 파일
-		filters:       filters,                   // 필터링 패턴 목록
-		keywords:      keywords,                  // 키워드 목록
+		filter:        filter,                    // 사전 컴파일된 필터
+		keywords:      NewKeywordMatcher(keywords), // 키워드 목록
 		outputFile:    outputFile,                // 출력 파일 경로
+		output:        output,                    // 출력 파일 (교체/자기 감시)
 		logger:        logger,                    // 로깅 인스턴스
 		emailService:  emailService,              // 이메일 서비스 (nil 가능)
 		slackService:  slackService,              // Slack 서비스 (nil 가능)
+		alertDispatcher: alertDispatcher,         // 알림 디스패처
+		controls:      make(chan func()),         // 관리 API 설정 변경 요청
 		loginDetector: loginDetector,             // 로그인 감지 서비스 (nil 가능)
 		aiAnalyzer:    aiAnalyzer,                // AI 분석 엔진 (nil 가능)
 		systemMonitor: systemMonitor,             // 시스템 모니터 (nil 가능)
-		logParser:     NewLogParserManager(),     // 다중 로그 파서 관리자
+		bootDetector:  bootDetector,              // 재부팅 감지 서비스 (nil 가능)
+		logParser:     logParser,                 // 다중 로그 파서 관리자
+		severityPolicy: severityPolicy,           // 심각도 정책
+		parserStats:   NewParserStats(0),         // 파서 통계 (미인식 비율 알림은 -unknown-format-alert)
+		sourceUsage:   NewSourceUsageTracker(),   // 소스/파서별 처리 비용 집계
+		healthChecks:  healthChecks,              // 헬스 체크 요청 제외 필터
 		aiEnabled:     aiEnabled,                 // AI 기능 활성화 플래그
 		systemEnabled: systemEnabled,             // 시스템 모니터링 활성화 플래그
 		loginWatch:    loginWatch,                // 로그인 감지 활성화 플래그
@@ -226,11 +491,18 @@ func NewSyslogMonitor(logFile, outputFile string, filters, keywords []string, em
 		reportInterval: time.Duration(reportInterval) * time.Minute, // 보고서 간격
 		lastReportTime: time.Now(),                // 마지막 보고서 시간
 		geoMapper:     geoMapper,                  // 지리정보 매핑 서비스
+		loginGeoTracker: loginGeoTracker,         // 로그인 출처 수집기 (nil 가능)
+		llmBatcher:    llmBatcher,                 // AI 이상 로그 묶음 LLM 분석 (nil 가능)
+		digest:        digest,                     // 일일 요약
+		alertActions:  alertActions,               // 알림 이메일 끄기/확인 링크
+		slackActions:  slackActions,               // Slack 확인/끄기 버튼
+		slackCommands: slackCommands,              // Slack 슬래시 명령
+		maintenance:   maintenance,                // 외부 캘린더 점검 일정 (nil 가능)
 	}
 }
 
 // shouldFilter 로그 라인이 필터링 패턴에 매치되는지 확인
-// 설정된 정규식 필터 목록과 비교하여 제외할 로그인지 판단
+// 시작 시(및 설정 변경 시) 컴파일해 둔 필터 목록과 비교하여 제외할 로그인지 판단
 //
 // 매개변수:
 //   - line: 검사할 로그 라인 문자열
@@ -240,21 +512,10 @@ func NewSyslogMonitor(logFile, outputFile string, filters, keywords []string, em
 //
 // 동작 원리:
 //   1. 필터가 설정되지 않은 경우 모든 로그 통과
-//   2. 각 필터 패턴을 순차적으로 검사
+//   2. 리터럴 필터는 부분 문자열 검색, 나머지는 컴파일된 정규식으로 검사
 //   3. 하나라도 매치되면 즉시 true 반환 (필터링)
 func (sm *SyslogMonitor) shouldFilter(line string) bool {
-	if len(sm.filters) == 0 {
-		return false // 필터가 없으면 모든 로그 통과
-	}
-
-	// 각 필터 패턴과 비교
-	for _, filter := range sm.filters {
-		matched, _ := regexp.MatchString(filter, line)
-		if matched {
-			return true // 필터 패턴에 매치되면 제외
-		}
-	}
-	return false // 어떤 필터에도 매치되지 않으면 통과
+	return sm.filter.Match(line)
 }
 
 // containsKeyword 로그 라인에 지정된 키워드가 포함되어 있는지 확인
@@ -268,20 +529,10 @@ func (sm *SyslogMonitor) shouldFilter(line string) bool {
 //
 // 동작 원리:
 //   1. 키워드가 설정되지 않은 경우 모든 로그 포함
-//   2. 로그 라인과 키워드를 소문자로 변환하여 비교
+//   2. 로그 라인을 소문자로 변환하여 미리 소문자로 바꿔 둔 키워드와 비교
 //   3. 하나라도 포함되면 즉시 true 반환
 func (sm *SyslogMonitor) containsKeyword(line string) bool {
-	if len(sm.keywords) == 0 {
-		return true // 키워드가 없으면 모든 라인을 포함
-	}
-
-	lowLine := strings.ToLower(line) // 대소문자 무관 비교를 위한 소문자 변환
-	for _, keyword := range sm.keywords {
-		if strings.Contains(lowLine, strings.ToLower(keyword)) {
-			return true // 키워드가 포함되면 감시 대상
-		}
-	}
-	return false // 어떤 키워드도 포함되지 않으면 제외
+	return sm.keywords.Match(line)
 }
 
 // parseSyslogLine syslog 포맷의 로그 라인을 파싱하여 구조화된 데이터로 변환
@@ -336,195 +587,472 @@ func (sm *SyslogMonitor) parseSyslogLine(line string) map[string]string {
 
 // 모든 이메일 관련 함수들은 EmailService로 이동됨
 
-func (sm *SyslogMonitor) processLine(line string) {
+// processLine 파일에서 읽은 한 줄 처리 (여러 줄 조립이 설정되면 엔트리가 완성될 때 처리)
+// source 는 줄을 읽은 파일 (소스별 처리 비용 집계용)
+func (sm *SyslogMonitor) processLine(source, line string) {
+	if sm.multiline != nil {
+		sm.multilineSource = source
+		entry, ok := sm.multiline.Add(line, time.Now())
+		if !ok {
+			return
+		}
+		line = entry
+	}
+	sm.processEntry(source, line, nil)
+}
+
+// flushMultiline 새 줄 없이 대기 중인 여러 줄 엔트리 처리 (force 면 대기 시간과 무관하게 처리)
+func (sm *SyslogMonitor) flushMultiline(force bool) {
+	if sm.multiline == nil {
+		return
+	}
+	var entry string
+	var ok bool
+	if force {
+		entry, ok = sm.multiline.Flush()
+	} else {
+		entry, ok = sm.multiline.FlushIdle(time.Now())
+	}
+	if ok {
+		sm.processEntry(sm.multilineSource, entry, nil)
+	}
+}
+
+// processEntry 로그 라인 처리 (입력 소스에서 미리 파싱된 결과가 있으면 재사용)
+// journald 입력처럼 구조화된 필드를 가진 소스는 preParsed 로 ParsedLog 를 전달
+// 필터/키워드/헬스 체크처럼 상태를 가진 앞 단계는 처리 루프에서 실행하고, 이후 단계는 파이프라인으로 넘김
+func (sm *SyslogMonitor) processEntry(file, line string, preParsed *ParsedLog) {
+	started, source := time.Now(), sourceUsageLabel(file, preParsed)
+
+	// 접근 로그 통계 모드: 웹 접근 로그는 집계만 하고 원본 줄은 이후 단계 (재부팅 원인 기록, 상관 분석, 출력, AI 분석, 알림) 로 넘기지 않음
+	if sm.accessStats != nil && sm.observeAccessStats(source, line, preParsed, started) {
+		return
+	}
+
+	// 재부팅 원인 추정용 패닉/종료 로그 기록 (필터와 무관하게 관찰)
+	if sm.bootDetector != nil {
+		sm.bootDetector.ObserveLine(line)
+	}
+
 	// 필터링 체크
 	if sm.shouldFilter(line) {
+		sm.sourceUsage.RecordFiltered(source, len(line), time.Since(started))
 		return
 	}
 
+	// SQL 인젝션 확인용 DB 증거 (키워드와 무관하게 관찰)
+	if sm.sqliCorrelator != nil {
+		sm.handleSQLInjectionConfirmation(sm.sqliCorrelator.ObserveDBLine(line, time.Now()))
+	}
+
+	// ModSecurity 탐지와 같은 요청의 웹 접근 로그 (키워드와 무관하게 관찰)
+	if sm.modSecurity != nil {
+		sm.handleModSecurityDetections(sm.modSecurity.ObserveAccess(sm.logParser.ParseLog(line), line, time.Now()))
+	}
+
 	// 키워드 체크
 	if !sm.containsKeyword(line) {
+		sm.sourceUsage.RecordFiltered(source, len(line), time.Since(started))
 		return
 	}
 
-	// 기본 로그 파싱
-	parsed := sm.parseSyslogLine(line)
-	
-	// 고급 로그 파싱 (AI 분석 활성화된 경우)
-	var parsedLog *ParsedLog
-	if sm.aiEnabled {
+	// 로드밸런서 헬스 체크 요청은 AI 분석/통계 전에 제외 (실패 응답은 통과)
+	if sm.healthChecks.Suppress(line, func() *ParsedLog {
+		if preParsed != nil && preParsed.LogType == KubeLogType {
+			return sm.logParser.ParseLog(preParsed.Message)
+		}
+		if preParsed != nil {
+			return preParsed
+		}
+		return sm.logParser.ParseLog(line)
+	}) {
+		sm.sourceUsage.RecordFiltered(source, len(line), time.Since(started))
+		return
+	}
+
+	// 파싱/분석/알림 (파이프라인이 있으면 워커로 넘기고 다음 줄 처리)
+	job := &logJob{line: line, preParsed: preParsed, source: source, trace: sm.latencyTracer.Sample(line)}
+	job.usage.Stages[UsageStageFilter] = time.Since(started)
+	if sm.pipeline != nil {
+		sm.pipeline.Submit(job)
+		return
+	}
+	sm.parseEntry(job)
+	sm.analyzeEntry(job)
+	sm.finishEntry(job)
+}
+
+// observeAccessStats 웹 접근 로그이면 필터/헬스 체크 제외를 적용해 집계하고 true 반환 (-keywords 는 적용하지 않음)
+func (sm *SyslogMonitor) observeAccessStats(source, line string, preParsed *ParsedLog, started time.Time) bool {
+	parsedLog := preParsed
+	if preParsed == nil {
 		parsedLog = sm.logParser.ParseLog(line)
+	} else if preParsed.LogType == KubeLogType {
+		parsedLog = sm.logParser.ParseLog(preParsed.Message)
+	}
+	if !isAccessLog(parsedLog) {
+		return false
+	}
+	if sm.shouldFilter(line) || sm.healthChecks.Suppress(line, func() *ParsedLog { return parsedLog }) {
+		sm.sourceUsage.RecordFiltered(source, len(line), time.Since(started))
+		return true
+	}
+	sm.accessStats.Observe(parsedLog)
+	cost := UsageCost{Parser: parsedLog.LogType}
+	cost.Stages[UsageStageParse] = time.Since(started)
+	sm.sourceUsage.Record(source, len(line), &cost)
+	return true
+}
+
+// parseEntry 기본/고급 로그 파싱 (파이프라인에서는 파싱 워커에서 병렬 실행)
+func (sm *SyslogMonitor) parseEntry(job *logJob) {
+	started := time.Now()
+	defer func() {
+		job.usage.Stages[UsageStageParse] = time.Since(started)
+		job.trace.Add(LatencyStageParse, job.usage.Stages[UsageStageParse])
+	}()
+
+	// 기본 로그 파싱
+	job.parsed = sm.parseSyslogLine(job.line)
+	if job.preParsed != nil && job.preParsed.Fields["unit"] != "" {
+		job.parsed["unit"] = job.preParsed.Fields["unit"]
+	}
+	if job.preParsed != nil && job.preParsed.LogType == KubeLogType {
+		for _, name := range []string{"namespace", "pod", "container", "node", "workload"} {
+			job.parsed[name] = job.preParsed.Fields[name]
+		}
+	}
+
+	// 고급 로그 파싱 (AI 분석, 응답 크기 이상 탐지, SLO 추적, Elasticsearch/Kafka/syslog 전달 또는 구조화 출력이 활성화된 경우)
+	// (형식 미인식 알림을 켜면 다른 기능 없이도 파싱)
+	if sm.aiEnabled || sm.exfilDetector != nil || sm.sloTracker != nil || sm.esOutput != nil || sm.kafkaOutput != nil || sm.forwarder != nil || sm.structuredOutput != nil || sm.eventSampler != nil || sm.parserStats.Threshold() > 0 {
+		parserStarted := time.Now()
+		if job.preParsed != nil && job.preParsed.LogType == KubeLogType {
+			// 쿠버네티스 입력은 컨테이너 로그 본문을 형식별 파서로 파싱하고 파드 정보를 필드로 덧붙임 (통계는 워크로드별)
+			parsedLog := sm.logParser.ParseLog(job.preParsed.Message)
+			job.usage.Parser = parsedLog.LogType
+			sm.observeParser(kubeWorkloadLabel(job.preParsed), parsedLog, job.preParsed.Message)
+			job.parsedLog = annotateKubeLog(parsedLog, job.preParsed)
+		} else if job.preParsed != nil {
+			job.parsedLog = job.preParsed
+			job.usage.Parser = job.parsedLog.LogType
+			sm.logParser.ApplyExtractionRules(job.parsedLog, job.line)
+		} else {
+			job.parsedLog = sm.logParser.ParseLog(job.line)
+			job.usage.Parser = job.parsedLog.LogType
+			sm.observeParser(job.source, job.parsedLog, job.line)
+		}
+		job.usage.ParserTime = time.Since(parserStarted)
 	}
+}
+
+// observeParser 파서 통계 기록 후 소스의 형식 미인식 비율이 바뀌었으면 알림
+func (sm *SyslogMonitor) observeParser(source string, parsedLog *ParsedLog, line string) {
+	if event := sm.parserStats.Observe(source, parsedLog.LogType, line, time.Now()); event != nil {
+		sm.handleUnknownFormat(event)
+	}
+}
 
-	// AI 분석 수행
-	var aiResult *AIAnalysisResult
+// analyzeEntry AI 분석 수행 (외부 ASN 조회를 포함하므로 파이프라인에서는 분석 워커에서 병렬 실행)
+func (sm *SyslogMonitor) analyzeEntry(job *logJob) {
 	if sm.aiEnabled && sm.aiAnalyzer != nil {
-		aiResult = sm.aiAnalyzer.AnalyzeLog(line, parsed)
+		started := time.Now()
+		job.aiResult = sm.aiAnalyzer.AnalyzeLog(job.line, job.parsed)
+		job.usage.Stages[UsageStageAI] = time.Since(started)
+		job.trace.Add(LatencyStageAI, job.usage.Stages[UsageStageAI])
+	}
+}
+
+// finishEntry 출력, 로그인/DB 감지, 알림 (파이프라인에서도 입력 순서대로 한 고루틴에서 실행)
+func (sm *SyslogMonitor) finishEntry(job *logJob) {
+	line, parsed, parsedLog, aiResult := job.line, job.parsed, job.parsedLog, job.aiResult
+
+	// 알림 단계 시간 (위치 조회 콜백에서 따로 기록하는 시간은 제외)
+	started, geoCall := time.Now(), time.Duration(0)
+	defer func() {
+		job.usage.Stages[UsageStageNotify] = time.Since(started) - geoCall
+		job.trace.Add(LatencyStageNotify, job.usage.Stages[UsageStageNotify])
+		job.trace.Release()
+		sm.sourceUsage.Record(job.source, len(line), &job.usage)
+	}()
+
+	// Elasticsearch/OpenSearch 로 파싱된 로그 색인
+	if sm.esOutput != nil {
+		sm.esOutput.IndexParsedLog(parsedLog, parsed["host"])
+	}
+
+	// Kafka 토픽으로 파싱된 로그 발행 (알림만 발행하는 모드에서는 무시)
+	if sm.kafkaOutput != nil {
+		sm.kafkaOutput.PublishParsedLog(parsedLog, parsed["host"])
+	}
+
+	// 웹 응답 크기 이상 (한 클라이언트의 대량 다운로드 등 데이터 유출 징후)
+	if sm.exfilDetector != nil {
+		sm.handleExfiltration(sm.exfilDetector.Observe(parsedLog, line, time.Now()))
+	}
+
+	// 엔드포인트 SLO 오류 예산 burn rate
+	if sm.sloTracker != nil {
+		for _, event := range sm.sloTracker.Observe(parsedLog, line, time.Now()) {
+			sm.handleSLOBurn(event)
+		}
+	}
+
+	// AI 분석 결과 처리
+	if aiResult != nil {
+		if sm.sqliCorrelator != nil && hasMatchedRule(aiResult, SQLInjectionPatternName) {
+			sm.handleSQLInjectionConfirmation(sm.sqliCorrelator.RecordAttempt(parsedLog, line, time.Now()))
+		}
+		if sm.esOutput != nil {
+			sm.esOutput.IndexAIResult(aiResult, line)
+		}
+		if sm.dashboard != nil {
+			sm.dashboard.RecordAnomaly(aiResult, sm.aiAnalyzer.alertThreshold)
+		}
+		for _, anomaly := range aiResult.BaselineEvents {
+			sm.handleBaselineAnomaly(anomaly)
+		}
 		
 		// AI 분석 결과에 따른 알림
 		if aiResult.AnomalyScore >= sm.aiAnalyzer.alertThreshold {
+			if sm.eventStore != nil {
+				sm.eventStore.RecordAIResult(aiResult, aiAlertSeverity(aiResult))
+			}
 			sm.sendAIAlert(aiResult, parsedLog)
+			// 같은 호스트/서비스의 이상 로그를 묶어 LLM 으로 분석 (scheduler.log_analysis)
+			host := parsed["host"]
+			if host == "" {
+				host = aiResult.SystemInfo.ComputerName
+			}
+			sm.llmBatcher.Add(host, serviceName(parsed["service"]), line, aiResult)
+			sm.digest.RecordAnomaly(host, serviceName(parsed["service"]), aiResult)
+		}
+
+		// CRITICAL 미만이면 조건 해소로 보고 열린 인시던트 해결 (PagerDuty 등)
+		if aiResult.AnomalyScore < HighThreatThreshold {
+			sm.alertDispatcher.Resolve(AlertTypeAI, aiResult.SystemInfo.ComputerName)
 		}
 	}
 
 	// 로그인 패턴 감지 (LoginDetector 서비스 사용)
+	// IP 위치 조회가 끝나면 기록/알림 (캐시 적중 시 즉시, 외부 조회가 필요하면 조회 고루틴에서)
+	var detectedLogin *LoginInfo
 	if sm.loginWatch && sm.loginDetector != nil {
 		if isLogin, loginInfo := sm.loginDetector.DetectLoginPattern(line); isLogin {
-			// 기본 로그 (항상 기록)
-			sm.logger.WithFields(logrus.Fields{
-				"level":        "LOGIN",
-				"user":         loginInfo.User,
-				"host":         parsed["host"],
-				"status":       loginInfo.Status,
-				"ip":           loginInfo.IP,
-				"cpu_usage":    fmt.Sprintf("%.1f%%", loginInfo.SystemInfo.CPU.UsagePercent),
-				"memory_usage": fmt.Sprintf("%.1f%%", loginInfo.SystemInfo.Memory.UsagePercent),
-				"should_alert": loginInfo.ShouldAlert,
-			}).Infof("🔐 User activity detected: %s from %s (Alert: %t)", 
-				loginInfo.Status, loginInfo.IP, loginInfo.ShouldAlert)
-
-			// 10분 간격 제한에 따른 선택적 알림 전송
-			if loginInfo.ShouldAlert {
-				// 이메일 로그인 알림 전송 (EmailService 사용)
-				if sm.emailService != nil {
-					sm.logger.Infof("📧 Sending login alert email (interval check passed)")
-					sm.sendLoginEmailAlert(loginInfo, parsed)
-				}
-
-				// Slack 로그인 알림 전송 (SlackService 사용)
-				if sm.slackService != nil {
-					slackMsg := sm.slackService.CreateLoginAlert(loginInfo.ToMap(), parsed)
-					sm.logger.Infof("💬 Sending login notification to Slack: %s (interval check passed)", loginInfo.User)
-					go func() {
-						if err := sm.slackService.SendMessage(slackMsg); err != nil {
-							sm.logger.Errorf("❌ Failed to send Slack login notification: %v", err)
-						} else {
-							sm.logger.Infof("✅ Slack login notification sent successfully")
-						}
-					}()
-				}
+			loginInfo.Enrichment = sm.logParser.LookupTables().Enrich(map[string]string{
+				"user": loginInfo.User, "ip": loginInfo.IP, "client_ip": loginInfo.IP, "host": parsed["host"],
+			})
+			// 조회 중에는 loginInfo 가 조회 고루틴에서 채워지므로 구조화 출력에는 감지 시점 사본을 기록
+			snapshot := *loginInfo
+			geoStarted := time.Now()
+			job.trace.Hold()
+			resolving := sm.loginDetector.ResolveLocation(loginInfo, func(info *LoginInfo) {
+				job.trace.Add(LatencyStageGeo, time.Since(geoStarted))
+				notifyStarted := time.Now()
+				sm.handleLoginEvent(info, parsed)
+				job.trace.Add(LatencyStageNotify, time.Since(notifyStarted))
+				job.trace.Release()
+			})
+			geoCall = time.Since(geoStarted)
+			if resolving {
+				detectedLogin = &snapshot
 			} else {
-				// 알림 제한된 경우 로그만 기록
-				sm.logger.Infof("⏰ Login alert skipped due to interval limit (10min rule)")
+				detectedLogin = loginInfo
 			}
 		}
 	}
 
-	// 경고나 에러 레벨 감지
-	lowLine := strings.ToLower(line)
-	if strings.Contains(lowLine, "error") || strings.Contains(lowLine, "err") {
-		sm.logger.WithFields(logrus.Fields{
-			"level": "ERROR",
-			"host":  parsed["host"],
-			"service": parsed["service"],
-		}).Error(parsed["message"])
-		
-		// 에러 발생 시 이메일 알림 전송 (EmailService 사용)
-		if sm.emailService != nil {
-			subject := fmt.Sprintf("[%s ERROR] %s - %s", AppName, parsed["host"], parsed["service"])
-			body := fmt.Sprintf("시간: %s\n호스트: %s\n서비스: %s\n메시지: %s\n원본 로그: %s", 
-				parsed["timestamp"], parsed["host"], parsed["service"], parsed["message"], line)
-			
-			sm.logger.Infof("📧 Sending ERROR alert to: %s", sm.emailService.GetRecipientsList())
-			go func() {
-				if err := sm.emailService.SendEmail(subject, body); err != nil {
-					sm.logger.Errorf("❌ Failed to send email alert: %v", err)
-				}
-			}()
+	// DB 권한 변경 / 관리자 계정 인증 실패 (일반 DB 에러 알림 대신 전용 알림)
+	var dbEvent *DBSecurityEvent
+	if sm.dbDetector != nil {
+		if dbEvent = sm.dbDetector.Detect(line); dbEvent != nil {
+			sm.handleDBSecurityEvent(dbEvent, parsed, line)
 		}
+	}
 
-		// 에러 시 Slack 알림도 전송 (SlackService 사용)
-		if sm.slackService != nil {
-			slackMsg := SlackMessage{
-				Text:      fmt.Sprintf("🔴 *ERROR Alert*"),
-				IconEmoji: ":rotating_light:",
-				Username:  DefaultSlackUsername,
-				Attachments: []SlackAttachment{
-					{
-						Color: SlackColorDanger,
-						Title: fmt.Sprintf("Error on %s", parsed["host"]),
-						Fields: []SlackField{
-							{Title: "Service", Value: parsed["service"], Short: true},
-							{Title: "Host", Value: parsed["host"], Short: true},
-							{Title: "Message", Value: parsed["message"], Short: false},
-						},
-						Timestamp: time.Now().Unix(),
-					},
-				},
-			}
-			go func() {
-				if err := sm.slackService.SendMessage(slackMsg); err != nil {
-					sm.logger.Errorf("❌ Failed to send Slack error alert: %v", err)
-				}
-			}()
+	// CI/CD 작업 결과 / 러너 등록 / 빌드 출력 비밀 노출 (일반 로그 알림 대신 전용 알림)
+	var cicdEvent *CICDEvent
+	if sm.cicdDetector != nil {
+		if cicdEvent = sm.cicdDetector.Detect(line, job.source, extractionServices(parsedLog, line)); cicdEvent != nil {
+			sm.handleCICDEvent(cicdEvent, parsed, line)
 		}
-		
-	} else if strings.Contains(lowLine, "warn") || strings.Contains(lowLine, "warning") {
-		sm.logger.WithFields(logrus.Fields{
-			"level": "WARNING",
-			"host":  parsed["host"],
-			"service": parsed["service"],
-		}).Warn(parsed["message"])
-		
-	} else if strings.Contains(lowLine, "fail") || strings.Contains(lowLine, "critical") {
-		sm.logger.WithFields(logrus.Fields{
-			"level": "CRITICAL",
-			"host":  parsed["host"],
-			"service": parsed["service"],
-		}).Fatal(parsed["message"])
-		
-		// 크리티컬 에러 발생 시 이메일 알림 전송 (EmailService 사용)
-		if sm.emailService != nil {
-			subject := fmt.Sprintf("[%s CRITICAL] %s - %s", AppName, parsed["host"], parsed["service"])
-			body := fmt.Sprintf("🚨 CRITICAL ALERT 🚨\n\n시간: %s\n호스트: %s\n서비스: %s\n메시지: %s\n원본 로그: %s", 
-				parsed["timestamp"], parsed["host"], parsed["service"], parsed["message"], line)
-			
-			sm.logger.Warnf("🚨 Sending CRITICAL alert to: %s", sm.emailService.GetRecipientsList())
-			go func() {
-				if err := sm.emailService.SendEmail(subject, body); err != nil {
-					sm.logger.Errorf("❌ Failed to send critical email alert: %v", err)
-				}
-			}()
+	}
+
+	// 심각도 정책으로 레벨과 알림 동작 결정 (어떤 레벨도 모니터를 종료시키지 않음)
+	decision := sm.severityPolicy.Evaluate(line, extractionServices(parsedLog, line))
+	level := decision.Level
+
+	// 웹 대시보드 실시간 로그
+	if sm.dashboard != nil {
+		sm.dashboard.PublishLog(parsed, level)
+	}
+
+	// 구조화 출력 (json/ndjson)
+	if sm.structuredOutput != nil {
+		if err := sm.structuredOutput.Write(newLogRecord(parsed, level, parsedLog, aiResult, detectedLogin)); err != nil {
+			sm.logger.Errorf("Failed to write structured output: %v", err)
 		}
+	}
 
-		// 크리티컬 에러 시 Slack 긴급 알림 (SlackService 사용)
-		if sm.slackService != nil {
-			slackMsg := SlackMessage{
-				Text:      fmt.Sprintf("🚨 *CRITICAL ALERT* 🚨"),
-				IconEmoji: DefaultSlackIcon,
-				Username:  DefaultSlackUsername,
-				Attachments: []SlackAttachment{
-					{
-						Color: SlackColorDanger,
-						Title: fmt.Sprintf("CRITICAL ERROR on %s", parsed["host"]),
-						Fields: []SlackField{
-							{Title: "Service", Value: parsed["service"], Short: true},
-							{Title: "Host", Value: parsed["host"], Short: true},
-							{Title: "Message", Value: parsed["message"], Short: false},
-						},
-						Timestamp: time.Now().Unix(),
-					},
-				},
-			}
-			go func() {
-				if err := sm.slackService.SendMessage(slackMsg); err != nil {
-					sm.logger.Errorf("❌ Failed to send Slack critical alert: %v", err)
-				}
-			}()
+	// 상위 syslog 서버로 전달 (RFC 5424, AI 점수/파싱 필드를 구조화 데이터로)
+	if sm.forwarder != nil {
+		sm.forwarder.Forward(line, parsed, level, parsedLog, aiResult)
+	}
+
+	// 학습용 층화 표본 (레벨 × 이상 패턴, 비밀 가림)
+	if sm.eventSampler != nil {
+		sm.eventSampler.Observe(newLogRecord(parsed, level, parsedLog, aiResult, detectedLogin), aiResult)
+	}
+
+	entry := sm.logger.WithFields(logrus.Fields{
+		"level":   level,
+		"host":    parsed["host"],
+		"service": parsed["service"],
+	})
+	switch level {
+	case LogLevelError, LogLevelCritical:
+		entry.Error(parsed["message"]) // Fatal 은 프로세스를 종료하므로 사용하지 않음 (예: PostgreSQL "FATAL:" 로그)
+	case LogLevelWarning:
+		entry.Warn(parsed["message"])
+	default:
+		entry.Info(parsed["message"])
+	}
+
+	// 정책이 정한 알림을 설정된 모든 알림 채널로 전송 (DB 보안 / CI/CD 이벤트는 전용 알림으로 대신함)
+	if decision.Alert != "" && sm.alertDispatcher.HasSinks() && dbEvent == nil && cicdEvent == nil {
+		sm.sendSeverityAlert(decision, parsed, line)
+	}
+}
+
+// sendSeverityAlert 심각도 정책 알림 전송 (critical 은 긴급 알림, 나머지는 에러 알림 유형)
+func (sm *SyslogMonitor) sendSeverityAlert(decision SeverityDecision, parsed map[string]string, line string) {
+	alertType, headline := AlertTypeError, fmt.Sprintf("🔴 %s on %s", decision.Level, parsed["host"])
+	if decision.Alert == AlertSeverityCritical {
+		alertType, headline = AlertTypeCritical, fmt.Sprintf("🚨 %s on %s", decision.Level, parsed["host"])
+		if decision.Level == LogLevelCritical {
+			headline = fmt.Sprintf("🚨 CRITICAL ERROR on %s", parsed["host"])
 		}
-		
+		sm.logger.Warnf("🚨 Sending %s alert via: %s", decision.Level, strings.Join(sm.alertDispatcher.SinkNames(), ", "))
 	} else {
-		sm.logger.WithFields(logrus.Fields{
-			"level": "INFO",
-			"host":  parsed["host"],
-			"service": parsed["service"],
-		}).Info(parsed["message"])
+		sm.logger.Infof("🔔 Sending %s alert via: %s", decision.Level, strings.Join(sm.alertDispatcher.SinkNames(), ", "))
+	}
+
+	fields := map[string]string{"service": parsed["service"], "message": parsed["message"]}
+	if decision.Rule != severityBuiltin {
+		fields["severity_rule"] = decision.Rule
+	}
+	sm.alertDispatcher.Dispatch(Alert{
+		Type:     alertType,
+		Severity: decision.Alert,
+		Title:    fmt.Sprintf("[%s %s] %s - %s", AppName, decision.Level, parsed["host"], parsed["service"]),
+		Headline: headline,
+		Sections: logLevelAlertSections(parsed, line),
+		Host:     parsed["host"],
+		Thread:   alertThreadKey("log", parsed["host"], serviceName(parsed["service"])),
+		Fields:   fields,
+	})
+}
+
+// handleLoginEvent IP 위치 정보가 채워진 로그인 이벤트 기록 및 알림
+// 위치 조회가 비동기로 끝나면 조회 고루틴에서 호출됨
+func (sm *SyslogMonitor) handleLoginEvent(loginInfo *LoginInfo, parsed map[string]string) {
+	// 기본 로그 (항상 기록)
+	sm.logger.WithFields(logrus.Fields{
+		"level":        "LOGIN",
+		"user":         loginInfo.User,
+		"host":         parsed["host"],
+		"status":       loginInfo.Status,
+		"ip":           loginInfo.IP,
+		"cpu_usage":    fmt.Sprintf("%.1f%%", loginInfo.SystemInfo.CPU.UsagePercent),
+		"memory_usage": fmt.Sprintf("%.1f%%", loginInfo.SystemInfo.Memory.UsagePercent),
+		"should_alert": loginInfo.ShouldAlert,
+	}).Infof("🔐 User activity detected: %s from %s (Alert: %t)", 
+		loginInfo.Status, loginInfo.IP, loginInfo.ShouldAlert)
+
+	// 정기 보고서의 로그인 출처 위치 요약용 기록
+	if sm.loginGeoTracker != nil {
+		sm.loginGeoTracker.Record(loginInfo)
+	}
+	sm.digest.RecordLogin(loginInfo)
+
+	// 웹 대시보드 최근 로그인 목록/지도
+	if sm.dashboard != nil {
+		sm.dashboard.RecordLogin(loginInfo, parsed["host"])
+	}
+
+	// 알림 간격 제한과 무관하게 모든 로그인 이벤트를 히스토리에 기록
+	if sm.eventStore != nil {
+		sm.eventStore.RecordLogin(loginInfo, parsed["host"], loginAlertSeverity(loginInfo))
+	}
+
+	// 10분 간격 제한에 따른 선택적 알림 전송
+	if loginInfo.ShouldAlert {
+		// 설정된 모든 알림 채널로 로그인 알림 전송
+		if sm.alertDispatcher.HasSinks() {
+			sm.logger.Infof("🔔 Sending login alert for %s (interval check passed)", loginInfo.User)
+			sm.sendLoginAlert(loginInfo, parsed)
+		}
+	} else {
+		// 알림 제한된 경우 로그만 기록
+		sm.logger.Infof("⏰ Login alert skipped due to interval limit (10min rule)")
+	}
+
+	// IP 단위 무차별 대입 공격은 알림 간격 제한과 무관하게 전용 알림 (설정 시 IP 차단)
+	if loginInfo.BruteForceAttack != nil {
+		sm.handleBruteForceAttack(loginInfo, parsed)
+	}
+}
+
+// serviceName 서비스 필드에서 PID 를 뗀 이름 ("sshd[1234]:" → "sshd")
+func serviceName(service string) string {
+	if i := strings.IndexByte(service, '['); i > 0 {
+		service = service[:i]
+	}
+	return strings.TrimSuffix(service, ":")
+}
+
+// logLevelAlertSections ERROR/CRITICAL 로그 알림 본문 섹션 (요약: 서비스/호스트/메시지, 전체: 원본 로그)
+func logLevelAlertSections(parsed map[string]string, line string) []AlertSection {
+	return []AlertSection{
+		{
+			Fields: []AlertField{
+				{Label: "서비스", Value: parsed["service"], Short: true},
+				{Label: "호스트", Value: parsed["host"], Short: true},
+				{Label: "메시지", Value: parsed["message"]},
+			},
+			Summary: true,
+		},
+		{
+			Title: "📄 원본 로그",
+			Fields: []AlertField{
+				{Label: "시간", Value: parsed["timestamp"], Short: true},
+				{Label: "원본 로그", Value: line, Code: true},
+			},
+		},
+	}
+}
+
+// detectLineLevel 로그 라인의 키워드로 레벨 판별 (ERROR, WARNING, CRITICAL, INFO)
+func detectLineLevel(line string) string {
+	lowLine := strings.ToLower(line)
+	switch {
+	case strings.Contains(lowLine, "error") || strings.Contains(lowLine, "err"):
+		return "ERROR"
+	case strings.Contains(lowLine, "warn") || strings.Contains(lowLine, "warning"):
+		return "WARNING"
+	case strings.Contains(lowLine, "fail") || strings.Contains(lowLine, "critical"):
+		return "CRITICAL"
+	default:
+		return "INFO"
 	}
 }
 
-func (sm *SyslogMonitor) Start() error {
-	// syslog 파일이 존재하는지 확인
-	if _, err := os.Stat(sm.logFile); os.IsNotExist(err) {
+// Start 입력 감시와 백그라운드 서비스 시작 (ctx 가 끝나면 전송 중인 알림을 기다리고 출력을 정리한 뒤 nil 반환)
+func (sm *SyslogMonitor) Start(ctx context.Context) error {
+	ctx, sm.cancel = context.WithCancel(ctx)
+
+	// syslog 파일이 존재하는지 확인 (journald/이벤트 로그/쿠버네티스/네트워크 수신 입력 모드는 파일 불필요)
+	if _, err := os.Stat(sm.logFile); !sm.journaldInput && !sm.eventLogInput && sm.kubeOptions == nil && len(sm.listenTargets) == 0 && len(sm.replayPaths) == 0 && os.IsNotExist(err) {
 		if runtime.GOOS == "darwin" {
 			// macOS 사용자를 위한 상세한 안내
 			sm.logger.Errorf("❌ 로그 파일을 찾을 수 없습니다: %s", sm.logFile)
@@ -551,756 +1079,2709 @@ func (sm *SyslogMonitor) Start() error {
 			sm.logger.Info("   sudo log stream | ./syslog-monitor -file=/dev/stdin")
 			
 			return fmt.Errorf("macOS에서는 다른 로그 파일 경로를 사용해주세요")
+		} else if runtime.GOOS == "windows" {
+			sm.logger.Errorf("❌ 로그 파일을 찾을 수 없습니다: %s", sm.logFile)
+			sm.logger.Info("🪟 Windows 이벤트 로그를 입력으로 사용하려면 -eventlog 옵션을 지정하세요")
+			sm.logger.Info("   syslog-monitor.exe -eventlog -eventlog-channels=System,Security")
+			return fmt.Errorf("syslog file not found: %s (use -eventlog on Windows)", sm.logFile)
 		} else {
 			return fmt.Errorf("syslog file not found: %s", sm.logFile)
 		}
 	}
 
 	sm.logger.Infof("Starting syslog monitor for file: %s", sm.logFile)
+	sm.startedAt = time.Now()
+
+	// 고가용성 리더 선출 (알림을 만들기 전에 첫 역할 확정)
+	if sm.leaderElection != nil {
+		sm.logger.Infof("👑 고가용성 리더 선출이 활성화되었습니다 (인스턴스: %s)", sm.leaderElection.ID())
+		sm.leaderElection.Start()
+	}
+
+	// 이전 실행에서 확인되지 않은 알림 다시 전송 (테넌트 채널 포함)
+	if sm.deliveryJournal != nil {
+		resent := sm.alertDispatcher.RecoverJournal()
+		for _, tenant := range sm.tenants.Tenants() {
+			resent += tenant.monitor.alertDispatcher.RecoverJournal()
+		}
+		sm.deliveryJournal.ExpireUnclaimed()
+		sm.logger.Infof("📒 알림 전송 저널이 활성화되었습니다: %s (확인되지 않은 알림 %d건 다시 전송)", sm.deliveryJournal.Path(), resent)
+	}
+
+	// 워커 풀 파이프라인 (관리 API 가 지표를 읽으므로 API 서버보다 먼저 생성)
+	if sm.workers > 0 {
+		sm.pipeline = NewLogPipeline(sm.workers, sm.parseEntry, sm.analyzeEntry, sm.finishEntry, sm.logger)
+		sm.pipeline.Start()
+		sm.logger.Infof("⚙️  처리 파이프라인: 파싱/분석 워커 각 %d개, 큐 용량 %d", sm.workers, sm.pipeline.Stats().QueueCapacity)
+	}
 	
 	// AI 분석 활성화 메시지
 	if sm.aiEnabled {
 		sm.logger.Infof("🤖 AI 로그 분석이 활성화되었습니다")
-		sm.logger.Infof(sm.aiAnalyzer.GetAnalysisReport())
+		if sm.rulesPath != "" {
+			sm.logger.Infof("📐 사용자 정의 이상 패턴 규칙: %s (%d개 패턴)", sm.rulesPath, len(sm.aiAnalyzer.patterns))
+		}
+		sm.logger.Info(sm.aiAnalyzer.GetAnalysisReport())
 	}
 	
 	// 시스템 모니터링 시작
 	if sm.systemEnabled && sm.systemMonitor != nil {
 		sm.logger.Infof("🖥️  시스템 모니터링을 시작합니다")
-		sm.systemMonitor.Start()
+		sm.systemMonitor.Start(ctx)
 		
-		// 시스템 알림 처리 고루틴
-		go sm.handleSystemAlerts()
+		// 시스템 알림 처리 고루틴 (종료 시 시스템 모니터가 채널을 닫으면 남은 알림을 보내고 끝남)
+		sm.loops.Add(1)
+		go func() {
+			defer sm.loops.Done()
+			sm.handleSystemAlerts()
+		}()
 		
-		sm.logger.Infof(sm.systemMonitor.GetSystemReport())
+		sm.logger.Info(sm.systemMonitor.GetSystemReport())
 	}
 
-	// 주기적 시스템 상태 보고서 시작
-	if sm.periodicReport && sm.systemMonitor != nil {
-		sm.logger.Infof("📊 주기적 시스템 상태 보고서가 활성화되었습니다 (간격: %v)", sm.reportInterval)
-		go sm.sendPeriodicSystemReports()
+	// 로그인 알림 히스토리 정리 작업 시작
+	if sm.loginDetector != nil {
+		sm.loginDetector.StartHistoryJanitor(ctx)
+		sm.loginDetector.StartAnonymizerRefresh(ctx)
+		if sm.loginWatch && sm.loginDetector.ThreatIntelEnabled() {
+			sm.logger.Infof("🛑 위협 인텔리전스(AbuseIPDB/블록리스트)로 로그인 IP 평판을 확인합니다")
+		}
 	}
 
-	// tail을 사용해 파일을 실시간으로 감시
-	t, err := tail.TailFile(sm.logFile, tail.Config{
-		Follow: true,
-		ReOpen: true,
-		Poll:   true,
-		Location: &tail.SeekInfo{Offset: 0, Whence: 2}, // 파일 끝에서 시작
-	})
-	if err != nil {
-		return fmt.Errorf("failed to tail file: %v", err)
+	// 규칙/Tor/GeoIP 데이터 자동 업데이트 시작
+	if sm.dataUpdater != nil {
+		sm.logger.Infof("📦 데이터 자동 업데이트가 활성화되었습니다 (%s)", strings.Join(sm.dataUpdater.Kinds(), ", "))
+		sm.dataUpdater.Start()
 	}
 
-	// 종료 신호 처리
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	// 외부 캘린더 점검 일정 가져오기 시작
+	if sm.maintenance != nil {
+		sm.logger.Infof("🛠️  점검 일정 캘린더 %d개를 가져옵니다", len(sm.maintenance.Status()))
+		sm.maintenance.Start()
+	}
 
-	sm.logger.Info("Syslog monitor started. Press Ctrl+C to stop.")
+	// 일일 요약 전송 (스케줄이 없어도 시작해 설정 재로드로 켤 수 있음)
+	if schedule := sm.digest.Schedule(); schedule != nil {
+		sm.logger.Infof("📰 일일 요약이 활성화되었습니다 (스케줄: %s)", schedule)
+	}
+	sm.digest.Start()
 
-	for {
-		select {
-		case line := <-t.Lines:
-			if line.Err != nil {
-				sm.logger.Errorf("Error reading line: %v", line.Err)
-				continue
-			}
-			sm.processLine(line.Text)
+	// 알림 채널 카나리 (하루 한 번 모든 채널로 점검 메시지를 보내고 도착 확인)
+	if sm.canary != nil {
+		sm.logger.Infof("🐤 알림 채널 카나리가 활성화되었습니다 (스케줄: %s)", sm.canary.Schedule())
+		sm.canary.Start(ctx)
+	}
 
-		case <-sigChan:
-			sm.logger.Info("Shutting down syslog monitor...")
-			t.Stop()
-			return nil
+	// 알림 라우팅 규칙이 설정되지 않은 채널을 가리키는지 확인
+	sm.warnMissingRouteSinks()
+
+	// 지리정보 조회 캐시 주기적 저장
+	sm.geoMapper.StartCachePersistence()
+	if !sm.sharedGeoMapper {
+		for _, db := range sm.geoMapper.GeoIPDatabases() {
+			sm.logger.Infof("🗺️  로컬 GeoIP 데이터베이스로 위치를 조회합니다: %s", db.Describe())
 		}
 	}
-}
-
-// sendLoginEmailAlert 로그인 알림 이메일 전송 (시스템 리소스 정보 포함)
-func (sm *SyslogMonitor) sendLoginEmailAlert(loginInfo *LoginInfo, parsed map[string]string) {
-	// 이메일 제목 생성 (상태별 구분)
-	var subject string
-	var statusEmoji string
-	
-	switch loginInfo.Status {
-	case "accepted":
-		statusEmoji = "✅"
-		subject = fmt.Sprintf("[%s LOGIN SUCCESS] %s logged in from %s", AppName, loginInfo.User, loginInfo.IP)
-	case "failed":
-		statusEmoji = "❌"
-		subject = fmt.Sprintf("[%s LOGIN FAILED] Failed login attempt for %s from %s", AppName, loginInfo.User, loginInfo.IP)
-	case "sudo":
-		statusEmoji = "⚡"
-		subject = fmt.Sprintf("[%s SUDO COMMAND] %s executed sudo command", AppName, loginInfo.User)
-	case "web_login":
-		statusEmoji = "🌐"
-		subject = fmt.Sprintf("[%s WEB LOGIN] %s logged in via web from %s", AppName, loginInfo.User, loginInfo.IP)
-	default:
-		statusEmoji = "🔐"
-		subject = fmt.Sprintf("[%s LOGIN ACTIVITY] User activity detected: %s", AppName, loginInfo.Status)
+	if sm.ipBlocker != nil {
+		sm.logger.Infof("🚫 무차별 대입 공격 IP 자동 차단이 활성화되었습니다 (%s)", sm.ipBlocker.Action())
 	}
 
-	// 이메일 본문 생성
-	body := fmt.Sprintf(`%s 로그인 활동 감지 알림
-==============================
-
-🕐 감지 시간: %s
-👤 사용자: %s
-📍 상태: %s %s
-🌐 IP 주소: %s
-🔑 인증 방법: %s
-🖥️  호스트: %s
+	// 알림/이벤트 히스토리 저장 시작
+	if sm.eventStore != nil {
+		sm.logger.Infof("🗄️  이벤트 히스토리를 저장합니다: %s", sm.eventStore.Path())
+		sm.eventStore.Start()
+	}
 
-🖥️  시스템 리소스 정보 (로그인 시점):
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-💻 CPU 사용률: %.1f%% (코어: %d개)
-  ├ 사용자: %.1f%%
-  ├ 시스템: %.1f%%
-  └ 대기: %.1f%%
+	// Elasticsearch/OpenSearch 색인 시작
+	if sm.esOutput != nil {
+		sm.logger.Infof("🔎 Elasticsearch 색인이 활성화되었습니다 (인덱스: %s-logs-*, %s-ai-*)", sm.esOutput.indexPrefix, sm.esOutput.indexPrefix)
+		sm.esOutput.Start()
+	}
 
-🧠 메모리 사용률: %.1f%%
-  ├ 총 메모리: %.1f GB
-  ├ 사용 중: %.1f GB
-  ├ 사용 가능: %.1f GB
-  └ 스왑 사용: %.1f MB
+	// Kafka 발행 시작
+	if sm.kafkaOutput != nil {
+		what := "파싱된 로그"
+		if sm.kafkaOutput.alertsOnly {
+			what = "알림"
+		}
+		sm.logger.Infof("📨 Kafka 발행이 활성화되었습니다 (%s → 토픽: %s, 파티셔닝: %s)", what, sm.kafkaOutput.topic, sm.kafkaOutput.partitionBy)
+		sm.kafkaOutput.Start()
+	}
 
-🌡️  시스템 온도: %.1f°C
-⚖️  로드 평균: %.2f (1분), %.2f (5분), %.2f (15분)
-`,
-		statusEmoji,
-		loginInfo.Timestamp.Format("2006-01-02 15:04:05"),
-		loginInfo.User,
-		loginInfo.Status,
-		statusEmoji,
-		loginInfo.IP,
-		loginInfo.Method,
-		parsed["host"],
-		loginInfo.SystemInfo.CPU.UsagePercent,
-		loginInfo.SystemInfo.CPU.Cores,
-		loginInfo.SystemInfo.CPU.UserPercent,
-		loginInfo.SystemInfo.CPU.SystemPercent,
-		loginInfo.SystemInfo.CPU.IdlePercent,
-		loginInfo.SystemInfo.Memory.UsagePercent,
-		loginInfo.SystemInfo.Memory.TotalMB/1024,
-		loginInfo.SystemInfo.Memory.UsedMB/1024,
-		loginInfo.SystemInfo.Memory.AvailableMB/1024,
-		loginInfo.SystemInfo.Memory.SwapUsedMB,
-		loginInfo.SystemInfo.Temperature.CPUTemp,
-		loginInfo.SystemInfo.LoadAverage.Load1Min,
-		loginInfo.SystemInfo.LoadAverage.Load5Min,
-		loginInfo.SystemInfo.LoadAverage.Load15Min,
-	)
+	// 상위 syslog 서버 전달 시작
+	if sm.forwarder != nil {
+		sm.logger.Infof("📤 syslog 전달이 활성화되었습니다 (RFC 5424 → %s)", sm.forwarder.Target())
+		sm.forwarder.Start()
+	}
 
-	// IP 위치 정보 추가
-	if loginInfo.IPDetails != nil {
-		body += fmt.Sprintf(`
-🌍 IP 위치 정보:
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-📍 IP 주소: %s
-🏴 국가: %s
-🏙️  도시: %s, %s
-🏢 조직/ISP: %s
-🔢 ASN: %s
-🔒 IP 유형: %s
-⚠️  위험도: %s
-`,
-			loginInfo.IPDetails.IP,
-			loginInfo.IPDetails.Country,
-			loginInfo.IPDetails.City,
-			loginInfo.IPDetails.Region,
-			loginInfo.IPDetails.Organization,
-			loginInfo.IPDetails.ASN,
-			func() string { if loginInfo.IPDetails.IsPrivate { return "사설 IP" } else { return "공인 IP" } }(),
-			loginInfo.IPDetails.Threat,
-		)
+	// 재부팅 감지 시작
+	if sm.bootDetector != nil {
+		sm.bootDetector.Start(ctx)
 	}
 
-	// Sudo 명령어 정보 추가
-	if loginInfo.Command != "" {
-		body += fmt.Sprintf(`
-⚡ 실행된 명령어:
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-%s
-`, loginInfo.Command)
+	// 설정 파일 변경 / SIGHUP 감시 시작
+	if sm.configWatcher != nil {
+		sm.logger.Infof("🔄 설정 파일 재로드가 활성화되었습니다: %s (SIGHUP 으로 즉시 재로드)", sm.configWatcher.path)
+		sm.configWatcher.Start(ctx)
 	}
 
-	// 디스크 사용량 정보 추가 (모든 주요 디스크)
-	if len(loginInfo.SystemInfo.Disk) > 0 {
-		body += `
-💾 디스크 사용량 상세정보:
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-`
-		var totalUsed, totalSize float64
-		for _, disk := range loginInfo.SystemInfo.Disk {
-			// 모든 실제 디스크 표시 (tmpfs, proc 등 가상 파일시스템 제외)
-			if disk.TotalGB > 0 && !strings.Contains(disk.Device, "tmpfs") && 
-			   !strings.Contains(disk.Device, "proc") && !strings.Contains(disk.Device, "sys") {
-				
-				// 사용률에 따른 상태 이모지
-				var statusEmoji string
-				if disk.UsagePercent >= 90 {
-					statusEmoji = "🔴" // 위험
-				} else if disk.UsagePercent >= 75 {
-					statusEmoji = "🟡" // 경고
-				} else {
-					statusEmoji = "🟢" // 정상
-				}
-				
-				body += fmt.Sprintf("  %s 📁 %s (%s)\n", statusEmoji, disk.MountPoint, disk.Device)
-				body += fmt.Sprintf("     ├ 사용률: %.1f%% (%.1fGB / %.1fGB)\n", 
-					disk.UsagePercent, disk.UsedGB, disk.TotalGB)
-				body += fmt.Sprintf("     ├ 남은공간: %.1f GB (%.1f%%)\n", 
-					disk.FreeGB, 100-disk.UsagePercent)
-				if disk.InodeUsagePercent > 0 {
-					body += fmt.Sprintf("     └ inode 사용률: %.1f%%\n", disk.InodeUsagePercent)
-				} else {
-					body += fmt.Sprintf("     └ 여유공간: %.1f GB\n", disk.FreeGB)
-				}
-				body += "\n"
-				
-				totalUsed += disk.UsedGB
-				totalSize += disk.TotalGB
-			}
-		}
-		
-		// 전체 디스크 요약
-		if totalSize > 0 {
-			totalFree := totalSize - totalUsed
-			totalUsagePercent := (totalUsed / totalSize) * 100
-			body += fmt.Sprintf("📊 전체 디스크 요약:\n")
-			body += fmt.Sprintf("   ├ 총 용량: %.1f GB\n", totalSize)
-			body += fmt.Sprintf("   ├ 사용량: %.1f GB (%.1f%%)\n", totalUsed, totalUsagePercent)
-			body += fmt.Sprintf("   └ 여유공간: %.1f GB (%.1f%%)\n", totalFree, 100-totalUsagePercent)
+	// SQL 인젝션 확인용 추가 DB 로그
+	if sm.sqliCorrelator != nil {
+		sm.logger.Infof("🧪 SQL 인젝션 DB 확인이 활성화되었습니다 (윈도우: %v)", sm.sqliCorrelator.Window())
+		if len(sm.sqliDBLogs) > 0 {
+			sm.logger.Infof("🗄️  SQL 인젝션 증거용 DB 로그: %s", strings.Join(sm.sqliDBLogs, ", "))
+			sm.startSQLInjectionDBLogs()
 		}
 	}
 
-	// 보안 권장사항
-	body += `
-🛡️  보안 권장사항:
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-• 알 수 없는 IP에서의 로그인 시도인지 확인하세요
-• 시스템 리소스 사용량이 평소보다 높은지 확인하세요
-• 비정상적인 시간대 로그인은 주의가 필요합니다
-• 실패한 로그인 시도가 반복되면 IP 차단을 고려하세요
-• 정기적으로 로그인 기록을 검토하세요
-
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-🤖 AI-Powered Syslog Monitor v2.0.0
-Lambda-X AI Security Team
-`
-
-	// 이메일 전송 (비동기)
-	sm.logger.Infof("📧 Sending login alert email to: %s", sm.emailService.GetRecipientsList())
-	go func() {
-		if err := sm.emailService.SendEmail(subject, body); err != nil {
-			sm.logger.Errorf("❌ Failed to send login alert email: %v", err)
-		} else {
-			sm.logger.Infof("✅ Login alert email sent successfully")
+	// 웹 응답 크기 이상 (데이터 유출) 탐지
+	if sm.sloTracker != nil {
+		for _, def := range sm.sloTracker.Definitions() {
+			sm.logger.Infof("🎯 SLO 추적: %s (%s, 목표 %g%%, %d일 오류 예산)", def.Name, sloEndpoint(def), def.Target, def.WindowDays)
 		}
-	}()
-}
+	}
+	if sm.accessStats != nil {
+		sm.logger.Infof("🔒 접근 로그 통계 모드가 활성화되었습니다 (%v 윈도우, 원본 접근 로그는 저장/전송하지 않음)", sm.accessStats.Window())
+		sm.accessStats.Start(ctx, sm.handleAccessStats)
+	}
+	if sm.exfilDetector != nil {
+		sm.logger.Infof("📦 응답 크기 이상 탐지가 활성화되었습니다 (클라이언트/엔드포인트당 %v 동안 %s)", sm.exfilDetector.Window(), formatByteSize(sm.exfilDetector.VolumeBytes()))
+	}
+	if threshold := sm.parserStats.Threshold(); threshold > 0 {
+		sm.logger.Infof("🧩 형식 미인식 알림이 활성화되었습니다 (소스별 %v 동안 %g%% 이상)", parserStatsWindow, threshold)
+	}
+	if sm.latencyTracer != nil {
+		sm.logger.Infof("⏱️  이벤트 지연 시간 추적이 활성화되었습니다 (표본 %g%%, 느린 이벤트 기준 %v)", sm.latencyTracer.Rate()*100, sm.latencyTracer.Budget())
+	}
 
-// sendAIAlert AI 분석 결과 알림 전송 (리팩토링된 버전)
-func (sm *SyslogMonitor) sendAIAlert(aiResult *AIAnalysisResult, parsedLog *ParsedLog) {
-	// 이메일 알림 (EmailService 사용)
-	if sm.emailService != nil {
-		subject := fmt.Sprintf("[%s %s] %s", AppName, aiResult.ThreatLevel, "이상 징후 감지")
-		
-		body := fmt.Sprintf(`🚨 보안 이상 탐지 알람
-======================
-⚠️  위협 레벨: %s
-📊 이상 점수: %.1f/%.0f
-🕐 탐지 시간: %s
-
-🖥️  시스템 정보:
-  📍 컴퓨터명: %s
-  🏠 내부 IP: %s
-  🌐 외부 IP: %s
-
-`,
-			aiResult.ThreatLevel,
-			aiResult.AnomalyScore,
-			MaxAnomalyScore,
-			aiResult.Timestamp.Format("2006-01-02 15:04:05"),
-			aiResult.SystemInfo.ComputerName,
-			strings.Join(aiResult.SystemInfo.InternalIPs, ", "),
-			strings.Join(aiResult.SystemInfo.ExternalIPs, ", "),
-		)
+	// ModSecurity 감사 로그
+	if sm.modSecurity != nil {
+		sm.logger.Infof("🛡️  ModSecurity 감사 로그 감시가 활성화되었습니다: %s", sm.modSecurityLog)
+		sm.startModSecurityAuditLog()
+	}
 
-		// ASN 정보 추가
-		if len(aiResult.SystemInfo.ASNData) > 0 {
-			body += "🔍 ASN 정보:\n"
-			for _, asn := range aiResult.SystemInfo.ASNData {
-				body += fmt.Sprintf("  📍 %s\n", asn.IP)
-				body += fmt.Sprintf("    🏢 조직: %s\n", asn.Organization)
-				body += fmt.Sprintf("    🌍 국가: %s, %s, %s\n", asn.Country, asn.Region, asn.City)
-				body += fmt.Sprintf("    🔢 ASN: %s\n", asn.ASN)
-				body += "\n"
-			}
-		}
+	// 멀티 테넌트 모드: 테넌트별 처리 파이프라인 시작
+	if sm.tenants != nil {
+		sm.logger.Infof("🏢 멀티 테넌트 모드: %d개 테넌트", len(sm.tenants.Tenants()))
+		sm.tenants.Start(ctx)
+	}
 
-		// 로그 정보
-		if parsedLog != nil {
-			body += fmt.Sprintf(`
-📋 로그 정보:
-  📝 레벨: %s
-  🏷️  타입: %s
-  💬 메시지: %s
-  📄 원본: %s
-
-`,
-				parsedLog.Level,
-				parsedLog.LogType,
-				parsedLog.Message,
-				parsedLog.RawLog,
-			)
-		}
-
-		// 예측 결과
-		if len(aiResult.Predictions) > 0 {
-			body += "🔮 위험 예측:\n"
-			for _, prediction := range aiResult.Predictions {
-				body += fmt.Sprintf("  ⚡ %s (확률: %.0f%%, %s)\n", 
-					prediction.Event, prediction.Probability*100, prediction.TimeFrame)
-				body += fmt.Sprintf("    💥 영향: %s\n", prediction.Impact)
-			}
-			body += "\n"
+	// 관리 REST API 시작
+	if sm.apiServer != nil {
+		if err := sm.apiServer.Start(); err != nil {
+			return err
 		}
-
-		// 권장사항
-		if len(aiResult.Recommendations) > 0 {
-			body += "💡 권장사항:\n"
-			for _, recommendation := range aiResult.Recommendations {
-				body += fmt.Sprintf("  • %s\n", recommendation)
-			}
-			body += "\n"
+		sm.logger.Infof("🛰️  관리 API 가 활성화되었습니다: %s://%s", sm.apiServer.Scheme(), sm.apiServer.Addr())
+		if sm.dashboard != nil {
+			sm.logger.Infof("📊 웹 대시보드: %s://%s/dashboard/", sm.apiServer.Scheme(), sm.apiServer.Addr())
 		}
+	}
 
-		// 영향받는 시스템
-		if len(aiResult.AffectedSystems) > 0 {
-			body += fmt.Sprintf("🎯 영향받는 시스템: %s\n", 
-				strings.Join(aiResult.AffectedSystems, ", "))
+	// 주기적 시스템 상태 보고서 시작
+	if sm.periodicReport && sm.systemMonitor != nil {
+		if sm.reportSchedule != nil {
+			sm.logger.Infof("📊 주기적 시스템 상태 보고서가 활성화되었습니다 (스케줄: %s)", sm.reportSchedule)
+		} else {
+			sm.logger.Infof("📊 주기적 시스템 상태 보고서가 활성화되었습니다 (간격: %v)", sm.reportInterval)
 		}
+		sm.loops.Add(1)
+		go func() {
+			defer sm.loops.Done()
+			sm.sendPeriodicSystemReports(ctx)
+		}()
+	}
 
-		body += fmt.Sprintf("🎯 신뢰도: %.0f%%\n", aiResult.Confidence*100)
-		
-		// 전문가 진단 정보 추가
-		body += fmt.Sprintf(`
-👨‍💼 전문가 진단 결과
-====================
-🏥 전체 시스템 건강도: %s
-📊 성능 점수: %.1f/100
+	// 보관된 로그 재처리 모드 (압축/tar 아카이브 포함, 끝나면 종료)
+	if len(sm.replayPaths) > 0 {
+		return sm.runReplayInput(ctx)
+	}
 
-🖥️  서버 전문가 진단:
-  🏥 서버 건강도: %s
-  📊 성능 점수: %.1f/100
-  🔒 보안 상태: %s
-  🌐 네트워크 건강도: %s
-  ⚠️  위험도: %s
+	// journald 입력 모드
+	if sm.journaldInput {
+		return sm.runJournaldInput(ctx)
+	}
 
-💻 컴퓨터 전문가 진단:
-  🔧 하드웨어 건강도: %s
-  💾 소프트웨어 상태: %s
-  ⚖️  시스템 안정성: %s
-  📈 리소스 사용량: %s
-  🔧 유지보수 필요: %s
+	// Windows 이벤트 로그 입력 모드
+	if sm.eventLogInput {
+		return sm.runEventLogInput(ctx)
+	}
 
-🚨 긴급 이슈:
-%s
+	// 쿠버네티스 파드 로그 입력 모드
+	if sm.kubeOptions != nil {
+		return sm.runKubernetesInput(ctx)
+	}
 
-🔧 유지보수 팁:
-%s
-`,
-			aiResult.ExpertDiagnosis.OverallHealth,
-			aiResult.ExpertDiagnosis.PerformanceScore,
-			aiResult.ExpertDiagnosis.ServerExpert.ServerHealth,
-			aiResult.ExpertDiagnosis.ServerExpert.PerformanceScore,
-			aiResult.ExpertDiagnosis.ServerExpert.SecurityStatus,
-			aiResult.ExpertDiagnosis.ServerExpert.NetworkHealth,
-			aiResult.ExpertDiagnosis.ServerExpert.RiskLevel,
-			aiResult.ExpertDiagnosis.ComputerExpert.HardwareHealth,
-			aiResult.ExpertDiagnosis.ComputerExpert.SoftwareStatus,
-			aiResult.ExpertDiagnosis.ComputerExpert.SystemStability,
-			aiResult.ExpertDiagnosis.ComputerExpert.ResourceUsage,
-			formatMaintenanceNeeded(aiResult.ExpertDiagnosis.ComputerExpert.MaintenanceNeeded),
-			formatCriticalIssues(aiResult.ExpertDiagnosis.CriticalIssues),
-			formatMaintenanceTips(aiResult.ExpertDiagnosis.MaintenanceTips),
-		)
-		
-		sm.logger.Infof("🚨 Sending AI alert to: %s", sm.emailService.GetRecipientsList())
-		go func() {
-			if err := sm.emailService.SendEmail(subject, body); err != nil {
-				sm.logger.Errorf("❌ Failed to send AI alert email: %v", err)
-			}
-		}()
+	// 네트워크 syslog 수신 입력 모드
+	if len(sm.listenTargets) > 0 {
+		return sm.runSyslogListenerInput(ctx)
 	}
-	
-	// Slack 알림 (SlackService 사용)
-	if sm.slackService != nil {
-		slackMsg := sm.slackService.CreateAIAlert(aiResult)
-		
-		go func() {
-			if err := sm.slackService.SendMessage(slackMsg); err != nil {
-				sm.logger.Errorf("❌ Failed to send AI alert to Slack: %v", err)
-			}
-		}()
+
+	// tail을 사용해 파일을 실시간으로 감시
+	t, err := tail.TailFile(sm.logFile, tail.Config{
+		Follow: true,
+		ReOpen: true,
+		Poll:   true,
+		Location: &tail.SeekInfo{Offset: 0, Whence: 2}, // 파일 끝에서 시작
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tail file: %v", err)
 	}
-}
 
-// handleSystemAlerts 시스템 알림 처리
-func (sm *SyslogMonitor) handleSystemAlerts() {
-	for alert := range sm.systemMonitor.GetAlertChannel() {
-		sm.logger.WithFields(logrus.Fields{
-			"level": "SYSTEM_ALERT",
-			"type":  alert.Type,
-			"value": alert.Value,
-		}).Warnf("System alert: %s", alert.Message)
-		
-		// 이메일 알림 (EmailService 사용)
-		if sm.emailService != nil {
-			subject := fmt.Sprintf("[%s SYSTEM ALERT] %s", AppName, alert.Type)
-			
-			body := fmt.Sprintf(`🖥️  시스템 알림
-
-심각도: %s
-메트릭: %s
-메시지: %s
-현재 값: %.2f
-임계값: %.2f
-시간: %s
-
-시스템에서 임계값을 초과한 상황이 감지되었습니다.`,
-				alert.Level,
-				alert.Type,
-				alert.Message,
-				alert.Value,
-				alert.Threshold,
-				alert.Timestamp.Format("2006-01-02 15:04:05"),
-			)
-			
-			sm.logger.Infof("🖥️  Sending system alert to: %s", sm.emailService.GetRecipientsList())
-			go func() {
-				if err := sm.emailService.SendEmail(subject, body); err != nil {
-					sm.logger.Errorf("❌ Failed to send system alert email: %v", err)
-				}
-			}()
-		}
-		
-		// Slack 알림 (SlackService 사용)
-		if sm.slackService != nil {
-			slackMsg := sm.slackService.CreateSystemAlert(alert)
-			
-			go func() {
-				if err := sm.slackService.SendMessage(slackMsg); err != nil {
-					sm.logger.Errorf("❌ Failed to send system alert to Slack: %v", err)
-				}
-			}()
-		}
+	// 여러 줄 조립 시 마지막 엔트리는 대기 시간이 지나면 처리 (조립하지 않으면 nil 채널)
+	var multilineTicks <-chan time.Time
+	if sm.multiline != nil {
+		ticker := time.NewTicker(multilineFlushCheck)
+		defer ticker.Stop()
+		multilineTicks = ticker.C
 	}
-}
 
-// sendPeriodicSystemReports 주기적 시스템 상태 보고서 전송
-func (sm *SyslogMonitor) sendPeriodicSystemReports() {
-	ticker := time.NewTicker(sm.reportInterval)
-	defer ticker.Stop()
+	sm.logger.Info("Syslog monitor started. Press Ctrl+C to stop.")
 
 	for {
 		select {
-		case <-ticker.C:
-			sm.sendSystemStatusReport()
+		case line := <-t.Lines:
+			if line.Err != nil {
+				sm.logger.Errorf("Error reading line: %v", line.Err)
+				continue
+			}
+			sm.processLine(sm.logFile, line.Text)
+
+		case <-multilineTicks:
+			sm.flushMultiline(false)
+
+		case reason := <-sm.configReloads():
+			sm.reloadConfig(reason)
+
+		case fn := <-sm.controls:
+			fn()
+
+		case <-ctx.Done():
+			sm.logger.Info("Shutting down syslog monitor...")
+			sm.shutdown()
+			t.Stop()
+			return nil
 		}
 	}
 }
 
-// sendSystemStatusReport 시스템 상태 보고서 전송
-func (sm *SyslogMonitor) sendSystemStatusReport() {
-	if sm.systemMonitor == nil {
-		return
+// shutdown 종료 신호 수신 시 정상 종료 기록 및 출력/저장소 정리
+// 백그라운드 작업을 멈추고, 전송 중인 알림을 ShutdownDrainTimeout 까지 기다린 뒤 출력 파일을 디스크에 반영
+func (sm *SyslogMonitor) shutdown() {
+	// 시스템 모니터, 정기 보고서, 재부팅 감지 등 중단 (시스템 알림 처리는 남은 알림을 보낸 뒤 끝남)
+	if sm.cancel != nil {
+		sm.cancel()
 	}
-
-	metrics := sm.systemMonitor.GetCurrentMetrics()
-	
-	// 이메일 보고서 전송
-	if sm.emailService != nil {
-		sm.sendSystemStatusEmail(metrics)
+	sm.loops.Wait()
+	sm.flushMultiline(true)
+	if sm.pipeline != nil {
+		sm.pipeline.Close()
+		stats := sm.pipeline.Stats()
+		sm.logger.Infof("⚙️  처리 파이프라인 종료: %d건 처리, %d건 버림, 입력 대기 %d회 (%.1f초)",
+			stats.Processed, stats.Dropped, stats.Backpressure, stats.BlockedSeconds)
 	}
-	
-	// Slack 보고서 전송
-	if sm.slackService != nil {
-		sm.sendSystemStatusSlack(metrics)
+	for _, tailer := range sm.sqliTails {
+		tailer.Stop()
 	}
-	
-	sm.logger.Infof("📊 시스템 상태 보고서 전송 완료 (CPU: %.1f%%, 메모리: %.1f%%)", 
-		metrics.CPU.UsagePercent, metrics.Memory.UsagePercent)
-}
-
-// sendSystemStatusEmail 시스템 상태 이메일 보고서 전송
-func (sm *SyslogMonitor) sendSystemStatusEmail(metrics SystemMetrics) {
-	subject := fmt.Sprintf("[%s] 📊 시스템 상태 보고서 - %s", AppName, time.Now().Format("2006-01-02 15:04"))
-	
-	body := sm.generateSystemStatusEmailBody(metrics)
-	
-	go func() {
-		if err := sm.emailService.SendEmail(subject, body); err != nil {
-			sm.logger.Errorf("❌ Failed to send system status email: %v", err)
+	if sm.modSecurityTail != nil {
+		sm.modSecurityTail.Stop()
+	}
+	if sm.healthChecks != nil {
+		if stats := sm.healthChecks.Stats(); stats.Suppressed > 0 {
+			sm.logger.Infof("🩺 헬스 체크 요청 %d건을 제외했습니다 (%s 이후)", stats.Suppressed, stats.Since.Format("2006-01-02 15:04:05"))
 		}
-	}()
-}
-
-// sendSystemStatusSlack 시스템 상태 Slack 보고서 전송
-func (sm *SyslogMonitor) sendSystemStatusSlack(metrics SystemMetrics) {
-	slackMsg := sm.generateSystemStatusSlackMessage(metrics)
-	
-	go func() {
-		if err := sm.slackService.SendMessage(slackMsg); err != nil {
-			sm.logger.Errorf("❌ Failed to send system status to Slack: %v", err)
+	}
+	if sm.ipBlocker != nil {
+		sm.ipBlocker.Close()
+	}
+	if sm.dataUpdater != nil {
+		sm.dataUpdater.Stop()
+	}
+	sm.maintenance.Stop()
+	sm.alertDispatcher.QuietHours().Stop()
+	sm.digest.Stop()
+	if sm.dashboard != nil {
+		sm.dashboard.Close()
+	}
+	if sm.apiServer != nil {
+		sm.apiServer.Close()
+	}
+	if sm.tenants != nil {
+		sm.tenants.Close()
+	}
+	// 진행 중인 평판/PTR/위치 조회의 로그인 기록/알림을 마친 뒤 저장소/출력 정리
+	if sm.loginDetector != nil {
+		sm.loginDetector.WaitThreatChecks()
+	}
+	sm.reverseDNS.Wait()
+	sm.whoisLookup.Wait()
+	if !sm.sharedGeoMapper {
+		sm.geoMapper.Close()
+	}
+	if sm.bootDetector != nil {
+		sm.bootDetector.MarkCleanShutdown()
+	}
+	if sm.eventStore != nil {
+		sm.eventStore.Close()
+	}
+	if sm.esOutput != nil {
+		sm.esOutput.Close()
+	}
+	if sm.kafkaOutput != nil {
+		sm.kafkaOutput.Close()
+	}
+	if sm.forwarder != nil {
+		sm.forwarder.Close()
+	}
+	if sm.structuredOutput != nil {
+		sm.structuredOutput.Close()
+	}
+	sm.selfWatch.Stop()
+	if sm.eventSampler != nil {
+		if err := sm.eventSampler.Close(); err != nil {
+			sm.logger.Errorf("❌ Failed to write event sample %s: %v", sm.eventSampler.Path(), err)
 		}
-	}()
+	}
+	// 전송 중인 알림 대기 (시간 안에 끝나지 않은 알림은 확인되지 않은 채 저널에 남아 다음 시작 시 다시 전송)
+	drainCtx, cancel := context.WithTimeout(context.Background(), ShutdownDrainTimeout)
+	if err := sm.alertDispatcher.Drain(drainCtx); err != nil {
+		sm.logger.Errorf("❌ Alerts still being sent after waiting %v: %v", ShutdownDrainTimeout, err)
+	}
+	cancel()
+	if sm.deliveryJournal != nil {
+		sm.deliveryJournal.Close()
+	}
+	// 남은 알림을 보낸 뒤 잠금 해제 (대기 인스턴스가 TTL 을 기다리지 않고 넘겨받음)
+	if sm.leaderElection != nil {
+		sm.leaderElection.Stop()
+	}
+	if sm.output != nil {
+		if err := sm.output.Sync(); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to flush output file %s: %v\n", sm.output.Path(), err)
+		}
+	}
 }
 
-// generateSystemStatusEmailBody 시스템 상태 이메일 본문 생성
-func (sm *SyslogMonitor) generateSystemStatusEmailBody(metrics SystemMetrics) string {
-	hostname, _ := os.Hostname()
-	
-	return fmt.Sprintf(`🖥️  시스템 상태 보고서
+// sleepContext 지정한 시간 동안 대기 (ctx 가 먼저 끝나면 false)
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
 
-📅 보고서 시간: %s
-🖥️  호스트명: %s
+// warnMissingRouteSinks 라우팅 규칙이 사용하지만 설정되지 않은 채널 경고 (해당 대상으로는 전송되지 않음)
+func (sm *SyslogMonitor) warnMissingRouteSinks() {
+	if missing := sm.alertDispatcher.MissingRouteSinks(); len(missing) > 0 {
+		sm.logger.Warnf("⚠️  Alert routes reference channels that are not configured (alerts routed there are dropped): %s", strings.Join(missing, ", "))
+	}
+}
 
-🌐 네트워크 정보:
-   사설 IP: %s
-   공인 IP: %s
+// AddAlertSink 알림 채널 추가 (로그인, AI, 시스템, 에러 알림 모두 전달)
+func (sm *SyslogMonitor) AddAlertSink(name string, sink AlertSink) {
+	sm.alertDispatcher.AddSink(name, sink)
+}
 
-📊 CPU 상태:
-   사용률: %.1f%%
-   사용자: %.1f%%
-   시스템: %.1f%%
-   유휴: %.1f%%
-   코어 수: %d
+// SetJournaldInput 파일 대신 systemd-journald 를 입력으로 사용
+// units가 비어 있으면 전체 저널을 구독
+func (sm *SyslogMonitor) SetJournaldInput(units []string) {
+	sm.journaldInput = true
+	sm.journaldUnits = units
+}
 
-💾 메모리 상태:
-   총 메모리: %.1f MB
-   사용 중: %.1f MB (%.1f%%)
-   사용 가능: %.1f MB
-   스왑 사용: %.1f MB (%.1f%%)
+// SetMultiline 파일 입력의 여러 줄 엔트리 조립 설정 (journald/이벤트 로그는 엔트리 단위 입력이므로 무시)
+func (sm *SyslogMonitor) SetMultiline(assembler *MultilineAssembler) {
+	sm.multiline = assembler
+}
 
-💿 디스크 상태:
-%s
+// SetEventLogInput 파일 대신 Windows 이벤트 로그 채널을 입력으로 사용
+func (sm *SyslogMonitor) SetEventLogInput(channels []string) {
+	sm.eventLogInput = true
+	sm.eventLogChannels = channels
+}
 
-🌡️  온도 정보:
-   CPU 온도: %.1f°C
-   GPU 온도: %.1f°C
+// SetKubernetesInput 파일 대신 네임스페이스/레이블 셀렉터에 맞는 쿠버네티스 파드 로그를 입력으로 사용
+func (sm *SyslogMonitor) SetKubernetesInput(options KubeOptions) {
+	sm.kubeOptions = &options
+}
 
-📈 시스템 부하:
-   1분 평균: %.2f
-   5분 평균: %.2f
-   15분 평균: %.2f
+// SetSyslogListener 파일 대신 UDP/TCP 로 수신한 syslog 메시지를 입력으로 사용 (allow 가 nil 이면 모든 발신 주소 허용)
+func (sm *SyslogMonitor) SetSyslogListener(targets []string, allow *TrustedNetworks) {
+	sm.listenTargets = targets
+	sm.listenAllow = allow
+}
 
-🔄 프로세스 상태:
-   총 프로세스: %d
-   실행 중: %d
-   대기 중: %d
+// SetTrustedNetworks 신뢰 네트워크 CIDR 목록 설정 (로그인 감지 비활성화 시 무시)
+func (sm *SyslogMonitor) SetTrustedNetworks(specs []string) error {
+	if sm.loginDetector == nil {
+		return nil
+	}
+	sm.trustedNetworkSpecs = specs
+	return sm.loginDetector.SetTrustedNetworks(specs)
+}
 
----
-📊 이 보고서는 %v마다 자동으로 전송됩니다.
-🤖 AI-Powered Syslog Monitor v2.1`,
-		time.Now().Format("2006-01-02 15:04:05"),
-		hostname,
-		formatIPList(metrics.IPInfo.PrivateIPs),
-		formatIPList(metrics.IPInfo.PublicIPs),
-		metrics.CPU.UsagePercent,
-		metrics.CPU.UserPercent,
-		metrics.CPU.SystemPercent,
-		metrics.CPU.IdlePercent,
-		metrics.CPU.Cores,
-		metrics.Memory.TotalMB,
-		metrics.Memory.UsedMB,
-		metrics.Memory.UsagePercent,
-		metrics.Memory.AvailableMB,
-		metrics.Memory.SwapUsedMB,
-		metrics.Memory.SwapFreePercent,
-		sm.generateDiskStatusText(metrics.Disk),
-		metrics.Temperature.CPUTemp,
-		metrics.Temperature.GPUTemp,
-		metrics.LoadAverage.Load1Min,
-		metrics.LoadAverage.Load5Min,
-		metrics.LoadAverage.Load15Min,
-		metrics.ProcessCount.Total,
-		metrics.ProcessCount.Running,
-		metrics.ProcessCount.Sleeping,
-		sm.reportInterval)
+// SetReverseDNS 로그인/웹 알림의 출처 IP 역방향 DNS 조회기 설정 (nil 이면 조회하지 않음, 멀티 테넌트 모드에서는 운영자 모니터의 캐시 공유)
+func (sm *SyslogMonitor) SetReverseDNS(rd *ReverseDNS) {
+	sm.reverseDNS = rd
+	if sm.loginDetector != nil {
+		sm.loginDetector.SetReverseDNS(rd)
+	}
 }
 
-// generateDiskStatusText 디스크 상태 텍스트 생성
-func (sm *SyslogMonitor) generateDiskStatusText(disks []DiskMetrics) string {
-	if len(disks) == 0 {
-		return "   정보 없음"
+// SetWhoisLookup 무차별 대입 알림의 abuse 연락처 조회기 설정 (nil 이면 조회하지 않음, 멀티 테넌트 모드에서는 운영자 모니터의 캐시 공유)
+func (sm *SyslogMonitor) SetWhoisLookup(wl *WhoisLookup) {
+	sm.whoisLookup = wl
+}
+
+// dispatchWebAlert 웹 알림에 클라이언트 IP 의 PTR 호스트 이름을 붙여 전송 (조회 중이면 조회 고루틴에서 전송)
+func (sm *SyslogMonitor) dispatchWebAlert(alert Alert, client string) {
+	sm.reverseDNS.LookupAsync(client, func(record PTRRecord) {
+		if record.Hostname != "" {
+			if len(alert.Sections) > 0 {
+				alert.Sections[0].Fields = append(alert.Sections[0].Fields, AlertField{Label: "역방향 DNS", Value: record.String(), Short: true})
+			}
+			if alert.Fields != nil {
+				alert.Fields["client_hostname"] = record.Hostname
+			}
+		}
+		sm.alertDispatcher.Dispatch(alert)
+	})
+}
+
+// SetGeoMapper 다른 모니터의 지리정보 서비스 공유 (멀티 테넌트 모드에서 운영자 모니터의 캐시 사용)
+// 공유한 서비스의 캐시 저장과 종료는 원래 모니터가 처리
+func (sm *SyslogMonitor) SetGeoMapper(gm *GeoMapper) {
+	sm.geoMapper = gm
+	sm.sharedGeoMapper = true
+	if sm.loginDetector != nil {
+		sm.loginDetector.SetGeoMapper(gm)
 	}
-	
-	var result strings.Builder
-	for _, disk := range disks {
-		result.WriteString(fmt.Sprintf("   %s (%s): %.1f GB / %.1f GB (%.1f%%)\n",
-			disk.Device, disk.MountPoint, disk.UsedGB, disk.TotalGB, disk.UsagePercent))
+	if sm.aiAnalyzer != nil {
+		sm.aiAnalyzer.SetGeoMapper(gm)
 	}
-	return result.String()
 }
 
-// formatIPList IP 목록을 문자열로 포맷팅
-func formatIPList(ips []string) string {
-	if len(ips) == 0 {
-		return "없음"
-	}
-	return strings.Join(ips, ", ")
+// SetConfigWatcher 설정 파일 변경 / SIGHUP 감시자 설정
+func (sm *SyslogMonitor) SetConfigWatcher(watcher *ConfigWatcher) {
+	sm.configWatcher = watcher
 }
 
-// formatMaintenanceNeeded 유지보수 필요성 포맷팅
-func formatMaintenanceNeeded(needed bool) string {
-	if needed {
-		return "예"
-	}
-	return "아니오"
+// SetAPIServer 관리 REST API 서버 설정 (Start 시 함께 시작)
+func (sm *SyslogMonitor) SetAPIServer(server *APIServer) {
+	sm.apiServer = server
 }
 
-// formatCriticalIssues 긴급 이슈 포맷팅
-func formatCriticalIssues(issues []string) string {
-	if len(issues) == 0 {
-		return "없음"
+// SetIPBlocker 무차별 대입 공격 IP 자동 차단기 설정
+func (sm *SyslogMonitor) SetIPBlocker(blocker *IPBlocker) {
+	sm.ipBlocker = blocker
+}
+
+// SetDBPrivilegeDetector DB 권한 변경/관리자 계정 인증 실패 감지기 설정
+func (sm *SyslogMonitor) SetDBPrivilegeDetector(detector *DBPrivilegeDetector) {
+	sm.dbDetector = detector
+}
+
+// SetCICDDetector CI/CD 반복 작업 실패/러너 등록/빌드 출력 비밀 노출 감지기 설정
+func (sm *SyslogMonitor) SetCICDDetector(detector *CICDDetector) {
+	sm.cicdDetector = detector
+}
+
+// SetSQLInjectionCorrelator SQL 인젝션 상관 분석기와 증거로만 읽을 추가 DB 로그 파일 설정
+func (sm *SyslogMonitor) SetSQLInjectionCorrelator(correlator *SQLInjectionCorrelator, dbLogs []string) {
+	sm.sqliCorrelator = correlator
+	sm.sqliDBLogs = dbLogs
+}
+
+// SetModSecurityAuditLog 웹 공격 알림을 위해 tail 할 ModSecurity 감사 로그 파일 설정
+func (sm *SyslogMonitor) SetModSecurityAuditLog(path string) {
+	sm.modSecurity = NewModSecurityCorrelator()
+	sm.modSecurityLog = path
+}
+
+// SetExfiltrationDetector 웹 응답 크기 이상 (데이터 유출) 탐지기 설정
+func (sm *SyslogMonitor) SetExfiltrationDetector(detector *ExfiltrationDetector) {
+	sm.exfilDetector = detector
+}
+
+// SetAccessStats 웹 접근 로그 통계 전용 집계기 설정 (웹 접근 로그 원본은 이후 단계로 넘기지 않음)
+func (sm *SyslogMonitor) SetAccessStats(stats *AccessStats) {
+	sm.accessStats = stats
+}
+
+// SetSLOTracker 엔드포인트 SLO 오류 예산 추적기 설정
+// fromFlag 가 true 이면 설정 파일 재로드 시 SLO 정의를 바꾸지 않음
+func (sm *SyslogMonitor) SetSLOTracker(tracker *SLOTracker, fromFlag bool) {
+	sm.sloTracker = tracker
+	sm.sloFromFlag = fromFlag
+}
+
+// SetWorkers 파싱/분석 워커 수 설정 (0 이면 파이프라인 없이 처리 루프에서 직접 처리)
+func (sm *SyslogMonitor) SetWorkers(workers int) {
+	sm.workers = workers
+}
+
+// DisableHealthCheckSuppression 헬스 체크 요청도 다른 로그와 같이 분석 (-suppress-health-checks=false)
+func (sm *SyslogMonitor) DisableHealthCheckSuppression() {
+	sm.healthChecks = nil
+}
+
+// SetAnomalyRules 사용자 정의 이상 패턴 규칙 파일 적용 (AI 분석 비활성화 시 무시)
+func (sm *SyslogMonitor) SetAnomalyRules(path string) error {
+	if sm.aiAnalyzer == nil {
+		return nil
 	}
-	var result strings.Builder
-	for _, issue := range issues {
-		result.WriteString(fmt.Sprintf("  • %s\n", issue))
+	patterns, err := LoadAnomalyRules(path)
+	if err != nil {
+		return err
 	}
-	return result.String()
+	sm.rulesPath = path
+	sm.applyAnomalyPatterns(patterns)
+	return nil
 }
 
-// formatMaintenanceTips 유지보수 팁 포맷팅
-func formatMaintenanceTips(tips []string) string {
-	if len(tips) == 0 {
-		return "없음"
+// reloadAnomalyRules 규칙 파일을 다시 읽어 적용 (오류가 있으면 기존 규칙 유지)
+func (sm *SyslogMonitor) reloadAnomalyRules(reason string) {
+	patterns, err := LoadAnomalyRules(sm.rulesPath)
+	if err != nil {
+		sm.logger.Errorf("Rules reload (%s) failed, keeping current rules: %v", reason, err)
+		return
 	}
-	var result strings.Builder
-	for _, tip := range tips {
-		result.WriteString(fmt.Sprintf("  • %s\n", tip))
+	sm.applyAnomalyPatterns(patterns)
+	sm.logger.Infof("✅ 이상 패턴 규칙을 다시 불러왔습니다 (%s): %s (%d개)", reason, sm.rulesPath, len(patterns))
+}
+
+// applyAnomalyPatterns 운영자와 테넌트 AI 분석기의 패턴 교체 (테넌트는 각 처리 고루틴에서 교체)
+func (sm *SyslogMonitor) applyAnomalyPatterns(patterns []AnomalyPattern) {
+	sm.aiAnalyzer.SetPatterns(patterns)
+	for _, tenant := range sm.tenants.Tenants() {
+		if analyzer := tenant.monitor.aiAnalyzer; analyzer != nil {
+			tenant.monitor.runOnLoop(func() { analyzer.SetPatterns(patterns) })
+		}
 	}
-	return result.String()
 }
 
-// generateSystemStatusSlackMessage 시스템 상태 Slack 메시지 생성
-func (sm *SyslogMonitor) generateSystemStatusSlackMessage(metrics SystemMetrics) SlackMessage {
-	hostname, _ := os.Hostname()
-	
-	// 상태에 따른 색상 결정
-	color := "good"
-	if metrics.CPU.UsagePercent > 80 || metrics.Memory.UsagePercent > 85 {
-		color = "warning"
+// SetDataUpdater 데이터 자동 업데이트 설정 (규칙, GeoIP 설정 후 호출)
+// 종류별로 교체할 파일과 적용 방법을 등록하고, 이 모드에서 적용할 수 없는 항목이 있으면 에러
+func (sm *SyslogMonitor) SetDataUpdater(updater *DataUpdater) error {
+	// 이상 패턴 규칙: -rules 파일 (AI 분석 필요)
+	if sm.aiAnalyzer != nil {
+		rulesPath := ""
+		if sm.rulesPath != "" {
+			rulesPath = expandHomePath(sm.rulesPath)
+		}
+		updater.Handle(DataUpdateRules, rulesPath, func(path string) error {
+			_, err := LoadAnomalyRules(path)
+			return err
+		}, func(path string) error {
+			patterns, err := LoadAnomalyRules(path)
+			if err != nil {
+				return err
+			}
+			sm.applyAnomalyPatterns(patterns)
+			return nil
+		})
 	}
-	if metrics.CPU.UsagePercent > 90 || metrics.Memory.UsagePercent > 95 {
-		color = "danger"
+
+	// GeoIP: -geoip-db 로 연 .mmdb 파일 (하나만 지정했으면 기본 경로)
+	if databases := sm.geoMapper.GeoIPDatabases(); len(databases) > 0 && !sm.sharedGeoMapper {
+		geoipPath := ""
+		if len(databases) == 1 {
+			geoipPath = databases[0].Path
+		}
+		updater.Handle(DataUpdateGeoIP, geoipPath, func(path string) error {
+			_, err := OpenGeoIPDatabase(path)
+			return err
+		}, func(path string) error {
+			db, err := sm.geoMapper.ReplaceGeoIPDatabase(path)
+			if err == nil {
+				sm.logger.Infof("🗺️  로컬 GeoIP 데이터베이스를 교체했습니다: %s", db.Describe())
+			}
+			return err
+		})
 	}
-	
-	return SlackMessage{
-		Text:      fmt.Sprintf("📊 시스템 상태 보고서 - %s", hostname),
-		IconEmoji: ":bar_chart:",
-		Attachments: []SlackAttachment{
-			{
-				Color: color,
-				Title: "🖥️  시스템 리소스 상태",
-				Fields: []SlackField{
-					{Title: "CPU 사용률", Value: fmt.Sprintf("%.1f%%", metrics.CPU.UsagePercent), Short: true},
-					{Title: "메모리 사용률", Value: fmt.Sprintf("%.1f%%", metrics.Memory.UsagePercent), Short: true},
-					{Title: "디스크 사용률", Value: sm.getDiskUsageSummary(metrics.Disk), Short: true},
-					{Title: "시스템 부하", Value: fmt.Sprintf("%.2f", metrics.LoadAverage.Load5Min), Short: true},
-					{Title: "온도", Value: fmt.Sprintf("CPU: %.1f°C", metrics.Temperature.CPUTemp), Short: true},
-					{Title: "프로세스", Value: fmt.Sprintf("%d 실행 중", metrics.ProcessCount.Running), Short: true},
-				},
-				Timestamp: metrics.Timestamp.Unix(),
-			},
-		},
+
+	// Tor 출구 노드 목록: 로그인 감지기의 목록 (내장 다운로드 대신 검증된 파일 사용)
+	if sm.loginDetector != nil && containsString(updater.Kinds(), DataUpdateTor) {
+		updater.Handle(DataUpdateTor, sm.loginDetector.ManageTorList(), func(path string) error {
+			_, err := readTorExitListFile(path)
+			return err
+		}, func(path string) error {
+			count, err := sm.loginDetector.LoadTorListFile(path)
+			if err == nil {
+				sm.logger.Infof("🧅 Tor exit list updated: %d exit nodes", count)
+			}
+			return err
+		})
 	}
+
+	if err := updater.Check(); err != nil {
+		return err
+	}
+	sm.dataUpdater = updater
+	return nil
 }
 
-// getDiskUsageSummary 디스크 사용률 요약 생성
-func (sm *SyslogMonitor) getDiskUsageSummary(disks []DiskMetrics) string {
-	if len(disks) == 0 {
-		return "N/A"
+// SetTenants 멀티 테넌트 모드 테넌트 설정 (관리 API 서버 생성 전에 설정해야 테넌트 토큰이 등록됨)
+func (sm *SyslogMonitor) SetTenants(tenants *TenantManager) {
+	sm.tenants = tenants
+}
+
+// SetLeaderElection 고가용성 리더 선출 설정 (테넌트 설정 후 호출해야 테넌트 채널에도 적용)
+// 대기 인스턴스는 수집/분석/저장은 그대로 하고 운영자와 테넌트 알림 채널로만 보내지 않음
+func (sm *SyslogMonitor) SetLeaderElection(election *LeaderElection) {
+	sm.leaderElection = election
+	sm.alertDispatcher.SetDeliveryGate(election.IsLeader)
+	for _, tenant := range sm.tenants.Tenants() {
+		tenant.monitor.alertDispatcher.SetDeliveryGate(election.IsLeader)
 	}
-	
-	// 가장 사용률이 높은 디스크 반환
-	maxUsage := 0.0
-	for _, disk := range disks {
-		if disk.UsagePercent > maxUsage {
-			maxUsage = disk.UsagePercent
-		}
+}
+
+// SetDeliveryJournal 알림 전송 선기록 저널 설정 (모든 알림 채널을 추가한 뒤 호출, 테넌트 채널은 테넌트 ID 범위로 기록)
+func (sm *SyslogMonitor) SetDeliveryJournal(journal *DeliveryJournal) {
+	sm.deliveryJournal = journal
+	sm.alertDispatcher.SetJournal(journal, "")
+	for _, tenant := range sm.tenants.Tenants() {
+		tenant.monitor.alertDispatcher.SetJournal(journal, tenant.ID)
 	}
-	return fmt.Sprintf("%.1f%%", maxUsage)
 }
 
-// getDefaultLogFile 운영체제에 따른 기본 로그 파일 경로 반환
-func getDefaultLogFile() string {
-	switch runtime.GOOS {
-	case "darwin": // macOS
-		// macOS에서 일반적으로 접근 가능한 로그 파일들을 순서대로 확인
-		macOSLogFiles := []string{
-			"/var/log/system.log",    // macOS 주요 시스템 로그
-			"/var/log/install.log",   // 설치 로그
-			"/var/log/wifi.log",      // WiFi 로그
-			"/usr/local/var/log/messages", // Homebrew 환경
-		}
-		
-		for _, logFile := range macOSLogFiles {
-			if _, err := os.Stat(logFile); err == nil {
-				return logFile
-			}
-		}
-		
-		// 기본값으로 system.log 반환 (존재하지 않아도)
-		return "/var/log/system.log"
-		
-	case "linux":
-		return "/var/log/syslog"
-		
-	default:
-		return "/var/log/syslog"
+// SetDashboard 웹 대시보드 설정 (관리 API 서버 생성 전에 설정해야 경로가 등록됨)
+func (sm *SyslogMonitor) SetDashboard(dashboard *Dashboard) {
+	sm.dashboard = dashboard
+}
+
+// runOnLoop 처리 고루틴(tail/journald select 루프)에서 fn 을 실행하고 완료까지 대기
+// 필터, 키워드, 임계값은 설정 재로드와 마찬가지로 처리 고루틴에서만 변경
+func (sm *SyslogMonitor) runOnLoop(fn func()) {
+	done := make(chan struct{})
+	sm.controls <- func() {
+		defer close(done)
+		fn()
 	}
+	<-done
 }
 
-// getMacOSLogRecommendations macOS 사용자를 위한 로그 파일 추천
-func getMacOSLogRecommendations() []string {
-	return []string{
-		"/var/log/system.log     # 주요 시스템 로그 (macOS Monterey 이전)",
-		"/var/log/install.log    # 패키지 설치 로그",
-		"/var/log/wifi.log       # WiFi 연결 로그",
-		"/var/log/kernel.log     # 커널 로그",
-		"/var/log/fsck_hfs.log   # 파일시스템 체크 로그",
-		"",
-		"💡 macOS Big Sur/Monterey 이후:",
-		"   sudo log show --predicate 'process == \"kernel\"' --last 1h",
-		"   sudo log show --predicate 'eventMessage contains \"error\"' --last 1h",
-		"   sudo log stream --predicate 'process == \"syslogd\"'",
+// configReloads 재로드 요청 채널 (감시자가 없으면 nil 채널이므로 select 에서 선택되지 않음)
+func (sm *SyslogMonitor) configReloads() <-chan string {
+	if sm.configWatcher == nil {
+		return nil
 	}
+	return sm.configWatcher.Reloads()
 }
 
-func main() {
-	// 설정 서비스 초기화
-	configPath := os.Getenv("SYSLOG_CONFIG_PATH")
-	if configPath == "" {
-		configPath = "~/.syslog-monitor/config.json"
+// reloadConfig 설정 파일을 다시 읽어 실행 중인 서비스에 적용
+// tail/journald 처리 루프에서 호출되고, 파이프라인에 남은 엔트리를 모두 처리한 뒤 적용하므로 로그 처리와 동시에 실행되지 않음
+func (sm *SyslogMonitor) reloadConfig(reason string) {
+	if sm.pipeline != nil {
+		sm.pipeline.Drain()
 	}
-	
-	configService = NewConfigService(configPath)
-	if err := configService.LoadConfig(); err != nil {
-		fmt.Printf("❌ 설정 파일 로드 실패: %v\n", err)
-		fmt.Println("💡 기본 설정으로 시작합니다.")
+	if sm.rulesPath != "" {
+		sm.reloadAnomalyRules(reason)
 	}
-	
-	// Gemini 서비스 초기화
-	geminiConfig := configService.GetGeminiConfig()
-	geminiService = NewGeminiService(geminiConfig)
-	
-	defaultLogFile := getDefaultLogFile()
-	
+	if configService == nil {
+		return
+	}
+	previous, config, err := configService.Reload()
+	if err != nil {
+		sm.logger.Errorf("Config reload (%s) failed, keeping current settings: %v", reason, err)
+		return
+	}
+	sm.ApplyConfig(previous, config)
+	sm.logger.Infof("✅ 설정을 다시 불러왔습니다 (%s): %s", reason, configService.GetConfigPath())
+	if !equalConfigOptions(config.Options, previous.Options) {
+		sm.logger.Infof("⚙️  Config file options changed; restart the service to apply them (reload only applies runtime settings)")
+	}
+}
+
+// ApplyConfig 재로드된 설정을 실행 중인 서비스에 적용
+// 임계값, 로그인 감지, Gemini, 알림 상세 수준 설정은 항상 적용하고, 명령행 플래그로도 지정하는 값
+// (키워드, 필터, 알림 수신자, 로그인 알림 간격, 신뢰 네트워크)은 설정 파일 값이 바뀐 경우에만 적용
+func (sm *SyslogMonitor) ApplyConfig(previous, config *Config) {
+	// 키워드 / 필터
+	if config.Logging.Keywords != previous.Logging.Keywords {
+		keywords := parseCommaList(config.Logging.Keywords)
+		if sm.loginWatch {
+			keywords = appendLoginKeywords(keywords)
+		}
+		if sm.dbDetector != nil && len(keywords) > 0 {
+			keywords = appendKeywords(keywords, DBWatchKeywords)
+		}
+		if sm.cicdDetector != nil && len(keywords) > 0 {
+			keywords = appendKeywords(keywords, CICDWatchKeywords)
+		}
+		sm.keywords = NewKeywordMatcher(keywords)
+		sm.logger.Infof("🔍 Keywords updated: %s", strings.Join(keywords, ", "))
+	}
+	if config.Logging.Filters != previous.Logging.Filters {
+		if filter, err := CompileLogFilter(parseCommaList(config.Logging.Filters)); err != nil {
+			sm.logger.Errorf("Invalid filters in reloaded config, keeping current filters: %v", err)
+		} else {
+			sm.filter = filter
+			sm.logger.Infof("🚫 Filters updated: %s", strings.Join(filter.Patterns(), ", "))
+		}
+	}
+
+	// 헬스 체크 경로 / User-Agent
+	if sm.healthChecks != nil && (strings.Join(config.Logging.HealthCheckPaths, ",") != strings.Join(previous.Logging.HealthCheckPaths, ",") ||
+		strings.Join(config.Logging.HealthCheckUserAgents, ",") != strings.Join(previous.Logging.HealthCheckUserAgents, ",")) {
+		sm.healthChecks.Update(config.Logging.HealthCheckPaths, config.Logging.HealthCheckUserAgents)
+		sm.logger.Infof("🩺 Health check suppression updated: paths=%s, user agents=%s",
+			strings.Join(sm.healthChecks.paths, ","), strings.Join(sm.healthChecks.userAgents, ","))
+	}
+
+	// 사용자 정의 nginx log_format
+	if strings.Join(config.Logging.NginxLogFormats, "\n") != strings.Join(previous.Logging.NginxLogFormats, "\n") {
+		if err := sm.logParser.SetNginxLogFormats(config.Logging.NginxLogFormats); err != nil {
+			sm.logger.Errorf("Invalid nginx log formats in reloaded config, keeping current formats: %v", err)
+		} else {
+			sm.logger.Infof("📝 Nginx log formats updated: %d custom format(s)", len(config.Logging.NginxLogFormats))
+		}
+	}
+
+	// 사용자 정의 필드 추출 규칙
+	if !equalExtractionRules(config.Logging.ExtractionRules, previous.Logging.ExtractionRules) {
+		if err := sm.logParser.SetExtractionRules(config.Logging.ExtractionRules); err != nil {
+			sm.logger.Errorf("Invalid extraction rules in reloaded config, keeping current rules: %v", err)
+		} else {
+			sm.logger.Infof("🧩 Extraction rules updated: %d rule(s)", len(config.Logging.ExtractionRules))
+		}
+	}
+
+	// 심각도 정책
+	if !equalSeverityPolicy(config.SeverityPolicy, previous.SeverityPolicy) {
+		if err := sm.severityPolicy.SetConfig(config.SeverityPolicy); err != nil {
+			sm.logger.Errorf("Invalid severity policy in reloaded config, keeping current policy: %v", err)
+		} else {
+			sm.logger.Infof("🚦 Severity policy updated: %d rule(s)", len(config.SeverityPolicy.Rules))
+		}
+	}
+
+	// 사용자 정의 로그 파서
+	if !equalCustomParsers(config.Logging.CustomParsers, previous.Logging.CustomParsers) {
+		if err := sm.logParser.SetCustomParsers(config.Logging.CustomParsers); err != nil {
+			sm.logger.Errorf("Invalid custom parsers in reloaded config, keeping current parsers: %v", err)
+		} else {
+			sm.logger.Infof("🧩 Custom parsers updated: %d parser(s)", len(config.Logging.CustomParsers))
+		}
+	}
+
+	// 조회 테이블 보강 (파일 내용 변경은 테이블이 직접 감지)
+	if !equalLookupTables(config.Logging.LookupTables, previous.Logging.LookupTables) {
+		if tables, err := LoadLookupTables(config.Logging.LookupTables, sm.logger); err != nil {
+			sm.logger.Errorf("Invalid lookup tables in reloaded config, keeping current tables: %v", err)
+		} else {
+			sm.logParser.SetLookupTables(tables)
+			sm.logger.Infof("📇 Lookup tables updated: %d table(s)", tables.Len())
+		}
+	}
+
+	// 로그인 감지 (sudo 정책, 실패 상관 분석, 알림 제한, 신뢰 네트워크 등)
+	if sm.loginDetector != nil {
+		alertInterval := sm.loginDetector.AlertInterval()
+		if err := sm.loginDetector.ApplyConfig(config); err != nil {
+			sm.logger.Errorf("Invalid login settings in reloaded config: %v", err)
+		}
+		if config.Login.AlertInterval == previous.Login.AlertInterval {
+			sm.loginDetector.SetAlertInterval(alertInterval)
+		}
+		if len(sm.trustedNetworkSpecs) > 0 && strings.Join(config.Login.TrustedNetworks, ",") == strings.Join(previous.Login.TrustedNetworks, ",") {
+			if err := sm.loginDetector.SetTrustedNetworks(sm.trustedNetworkSpecs); err != nil {
+				sm.logger.Errorf("Failed to restore trusted networks: %v", err)
+			}
+		}
+	}
+
+	// 엔드포인트 SLO (정의가 같은 SLO 는 집계 유지, -slo 플래그로 지정했으면 무시)
+	if !sm.sloFromFlag && !equalSLODefinitions(config.SLOs, previous.SLOs) {
+		switch {
+		case sm.sloTracker != nil:
+			if err := sm.sloTracker.SetDefinitions(config.SLOs); err != nil {
+				sm.logger.Errorf("Invalid SLOs in reloaded config, keeping current SLOs: %v", err)
+			} else {
+				sm.logger.Infof("🎯 SLOs updated: %d endpoint(s)", len(config.SLOs))
+			}
+		case len(config.SLOs) > 0:
+			if tracker, err := NewSLOTracker(config.SLOs); err != nil {
+				sm.logger.Errorf("Invalid SLOs in reloaded config: %v", err)
+			} else {
+				sm.sloTracker = tracker
+				sm.logger.Infof("🎯 SLOs enabled: %d endpoint(s)", len(config.SLOs))
+			}
+		}
+	}
+
+	// DB 관리자 계정 / 인증 실패 알림 간격
+	if sm.dbDetector != nil {
+		sm.dbDetector.ApplyConfig(config)
+	}
+
+	// CI/CD 감지 기준 / 러너 허용 네트워크 / 담당 팀
+	if sm.cicdDetector != nil && !equalCICDConfig(config.CICD, previous.CICD) {
+		if err := sm.cicdDetector.SetConfig(config.CICD); err != nil {
+			sm.logger.Errorf("Invalid cicd settings in reloaded config, keeping current settings: %v", err)
+		} else {
+			sm.logger.Infof("🏗️  CI/CD detection settings updated: %d team(s)", len(config.CICD.Teams))
+		}
+	}
+
+	// 시스템 모니터링 임계값 / 재부팅 후 감시 서비스
+	if sm.systemMonitor != nil {
+		sm.systemMonitor.ApplyConfig(config)
+	}
+	if sm.bootDetector != nil {
+		sm.bootDetector.SetWatchedServices(config.SystemMonitoring.WatchedServices)
+	}
+
+	// AI 분석 알림 임계값 / LLM 백엔드 설정
+	if sm.aiAnalyzer != nil {
+		sm.aiAnalyzer.SetAlertThreshold(config.AI.AlertThreshold)
+		if err := sm.aiAnalyzer.SetBaselineConfig(config.AI.Baseline); err != nil {
+			sm.logger.Errorf("Invalid baseline settings in reloaded config: %v", err)
+		}
+	}
+	if previous := currentLLMProvider(); previous != nil && configService != nil {
+		if provider, err := NewLLMProvider(configService.GetLLMConfig()); err != nil {
+			sm.logger.Errorf("Invalid AI provider in reloaded config, keeping %s: %v", previous.Name(), err)
+		} else {
+			provider.SetPrompts(previous.Prompts())
+			if prompts, err := LoadPromptSet(config.AI.Prompts); err != nil {
+				sm.logger.Errorf("Invalid AI prompt templates in reloaded config, keeping previous prompts: %v", err)
+			} else {
+				provider.SetPrompts(prompts)
+			}
+			if provider.Name() != previous.Name() || provider.Model() != previous.Model() {
+				sm.logger.Infof("🧠 AI provider: %s (model: %s)", provider.Name(), provider.Model())
+			}
+			setLLMProvider(provider)
+		}
+	}
+	// LLM 호출 캐시/한도와 로그 묶음 분석
+	if err := llmScheduler.SetConfig(config.AI.Scheduler); err != nil {
+		sm.logger.Errorf("Invalid AI scheduler settings in reloaded config, keeping current limits: %v", err)
+	} else {
+		sm.llmBatcher.SetConfig(config.AI.Scheduler)
+		for _, tenant := range sm.tenants.Tenants() {
+			tenant.monitor.llmBatcher.SetConfig(config.AI.Scheduler) // llm_analysis 로 동의한 테넌트만 (나머지는 nil)
+		}
+	}
+
+	// 채널별 알림 상세 수준
+	if err := sm.alertDispatcher.SetDetailLevels(config.Alerts.Detail); err != nil {
+		sm.logger.Errorf("Invalid alert detail levels in reloaded config: %v", err)
+	}
+
+	// 알림 유형별 중복 알림 제한 간격
+	if err := sm.alertDispatcher.SetThrottleIntervals(config.Alerts.Intervals); err != nil {
+		sm.logger.Errorf("Invalid alert intervals in reloaded config: %v", err)
+	}
+
+	// 알림 라우팅 규칙
+	if !equalAlertRoutes(config.Routes, previous.Routes) {
+		if err := sm.alertDispatcher.SetRoutes(config.Routes); err != nil {
+			sm.logger.Errorf("Invalid alert routes in reloaded config, keeping current routes: %v", err)
+		} else {
+			sm.logger.Infof("🧭 Alert routes updated: %d rule(s)", len(config.Routes))
+			sm.warnMissingRouteSinks()
+		}
+	}
+
+	// 서비스 의존 관계 (바뀌었을 때만 교체해 진행 중인 장애 기록 유지)
+	if !equalServiceDependencies(config.Dependencies, previous.Dependencies) {
+		if err := sm.alertDispatcher.SetDependencies(config.Dependencies); err != nil {
+			sm.logger.Errorf("Invalid service dependencies in reloaded config, keeping current dependencies: %v", err)
+		} else {
+			sm.logger.Infof("⛓️ Service dependencies updated: %d component(s)", len(config.Dependencies.Components))
+		}
+	}
+
+	// 외부 캘린더 점검 일정 (바뀌었을 때만 교체, 이전 일정에 모아 둔 알림은 바로 요약 전송)
+	if !equalMaintenanceConfig(config.Maintenance, previous.Maintenance) {
+		if schedule, err := NewMaintenanceSchedule(config.Maintenance, sm.alertDispatcher.Dispatch, sm.logger); err != nil {
+			sm.logger.Errorf("Invalid maintenance calendars in reloaded config, keeping current calendars: %v", err)
+		} else {
+			sm.alertDispatcher.SetMaintenance(schedule)
+			sm.maintenance.Stop()
+			sm.maintenance = schedule
+			schedule.Start()
+			sm.logger.Infof("🛠️  Maintenance calendars updated: %d calendar(s)", len(config.Maintenance.Calendars))
+		}
+	}
+
+	// 조용한 시간 (바뀌었을 때만 교체, 이전 창에 모아 둔 알림은 바로 요약 전송)
+	if !equalQuietHours(config.QuietHours, previous.QuietHours) {
+		if quiet, err := NewQuietHours(config.QuietHours, sm.alertDispatcher.deliverTo, sm.logger); err != nil {
+			sm.logger.Errorf("Invalid quiet hours in reloaded config, keeping current quiet hours: %v", err)
+		} else {
+			previousQuiet := sm.alertDispatcher.QuietHours()
+			sm.alertDispatcher.SetQuietHours(quiet)
+			previousQuiet.Stop()
+			sm.logger.Infof("🌙 Quiet hours updated: %d window(s)", len(config.QuietHours))
+		}
+	}
+
+	// 일일 요약 (-digest-schedule 값을 덮어쓰지 않도록 바뀌었을 때만 적용)
+	if !equalDigestConfig(config.Reports.Digest, previous.Reports.Digest) {
+		if err := sm.digest.SetConfig(config.Reports.Digest); err != nil {
+			sm.logger.Errorf("Invalid daily digest settings in reloaded config, keeping current digest: %v", err)
+		} else if schedule := sm.digest.Schedule(); schedule != nil {
+			sm.logger.Infof("📰 Daily digest schedule: %s", schedule)
+		} else {
+			sm.logger.Infof("📰 Daily digest disabled")
+		}
+	}
+
+	// 알림 채널 카나리 (-canary-schedule 값을 덮어쓰지 않도록 바뀌었을 때만 적용)
+	if sm.canary != nil && config.Canary != previous.Canary {
+		if err := sm.canary.SetConfig(sm.resolveCanaryConfig(config.Canary)); err != nil {
+			sm.logger.Errorf("Invalid canary settings in reloaded config, keeping current canary: %v", err)
+		} else {
+			sm.logger.Infof("🐤 Notification canary schedule: %s", sm.canary.Schedule())
+		}
+	}
+
+	// 알림 이메일 끄기/확인 링크 (서명 키가 바뀌면 이미 보낸 링크는 동작하지 않음)
+	if !equalAlertActionConfig(config.Alerts.Actions, previous.Alerts.Actions) {
+		secret, _ := ResolveSecret(SecretAlertActionKey, "", config.Alerts.Actions.Secret)
+		if err := sm.alertActions.SetConfig(config.Alerts.Actions, secret); err != nil {
+			sm.logger.Errorf("Invalid alert action links in reloaded config, keeping current links: %v", err)
+		} else {
+			sm.logger.Infof("🔕 Alert action links updated (enabled: %t)", sm.alertActions.Enabled())
+		}
+	}
+
+	// 알림 수신자
+	if strings.Join(config.Email.To, ",") != strings.Join(previous.Email.To, ",") && sm.emailService != nil {
+		sm.emailService.SetRecipients(config.Email.To)
+		sm.logger.Infof("📧 Email recipients updated: %s", strings.Join(config.Email.To, ", "))
+	}
+	if config.Email.OAuth2 != previous.Email.OAuth2 && sm.emailService != nil {
+		oauth2 := config.Email.OAuth2
+		if oauth2.Enabled() && oauth2.ClientSecret == "" {
+			oauth2.ClientSecret, _ = ResolveSecret(SecretSMTPOAuth2ClientSecret, "", "")
+		}
+		if err := oauth2.Validate(); oauth2.Enabled() && err != nil {
+			sm.logger.Errorf("Invalid email oauth2 settings in reloaded config, keeping current authentication: %v", err)
+		} else {
+			sm.emailService.SetOAuth2(&oauth2)
+			sm.logger.Infof("🔐 Email OAuth2 settings updated")
+		}
+	}
+	if config.Slack.WebhookURL != previous.Slack.WebhookURL || config.Slack.Channel != previous.Slack.Channel {
+		if sm.slackService != nil {
+			sm.slackService.SetDestination(config.Slack.WebhookURL, config.Slack.Channel)
+			sm.logger.Infof("💬 Slack destination updated: %s", config.Slack.Channel)
+		} else if config.Slack.WebhookURL != "" {
+			sm.logger.Infof("💬 Slack was not enabled at startup; restart with -slack-webhook to add the channel")
+		}
+	}
+	if config.Slack.SigningSecret != previous.Slack.SigningSecret {
+		signingSecret, _ := ResolveSecret(SecretSlackSigningSecret, "", config.Slack.SigningSecret)
+		sm.slackActions.SetSecret(signingSecret)
+		sm.logger.Infof("💬 Slack action buttons updated (enabled: %t)", sm.slackActions.Enabled())
+	}
+	if strings.Join(config.Slack.CommandUsers, ",") != strings.Join(previous.Slack.CommandUsers, ",") {
+		sm.slackCommands.SetUsers(config.Slack.CommandUsers)
+		sm.logger.Infof("💬 Slack command users updated: %d", len(config.Slack.CommandUsers))
+	}
+	if sm.slackService != nil {
+		if styles, err := config.SlackStyles(); err != nil {
+			sm.logger.Errorf("Invalid slack styles in reloaded config, keeping current branding: %v", err)
+		} else {
+			sm.slackService.SetStyles(styles)
+		}
+	}
+}
+
+// SetEventStore 알림/이벤트 히스토리 저장소 설정
+// 로그인 감지가 활성화되어 있으면 사용자별 ASN 히스토리도 같은 저장소에 보관
+func (sm *SyslogMonitor) SetEventStore(store *EventStore) error {
+	sm.eventStore = store
+	if sm.loginDetector != nil {
+		if err := sm.loginDetector.SetASNHistoryStore(store); err != nil {
+			return fmt.Errorf("failed to load user ASN history: %v", err)
+		}
+	}
+	return nil
+}
+
+// SetOutputFormat 필터링된 로그 출력 형식 설정 (text, json, ndjson)
+// json/ndjson 은 레코드를 -output 파일(없으면 stdout)로 출력하고, 운영 로그는 stderr 로 분리
+func (sm *SyslogMonitor) SetOutputFormat(format string, stdout io.Writer) error {
+	if format == OutputFormatText {
+		return nil
+	}
+
+	// NewSyslogMonitor 에서 출력 파일로 돌려 둔 로거를 stderr 로 되돌림
+	if sm.output != nil {
+		sm.output.Close()
+		sm.output = nil
+	}
+	sm.logger.SetOutput(os.Stderr)
+
+	writer, err := NewStructuredWriter(format, sm.outputFile, stdout)
+	if err != nil {
+		return err
+	}
+	sm.structuredOutput = writer
+	return nil
+}
+
+// EnableSelfWatch -output 파일을 내부 소스로 등록해 모니터 자신의 오류를 알림으로 전송 (텍스트 출력 파일이 없으면 오류)
+func (sm *SyslogMonitor) EnableSelfWatch() error {
+	if sm.output == nil {
+		return fmt.Errorf("-output-self-watch needs -output with the text output format")
+	}
+	sm.selfWatch = NewSelfWatch(sm.output.Path(), sm.alertDispatcher.Dispatch)
+	sm.output.SetObserver(sm.selfWatch.Observe)
+	return nil
+}
+
+// EnableCanary 알림 채널 카나리 설정 (schedule 이 있으면 설정 파일 canary.schedule 대신 사용)
+func (sm *SyslogMonitor) EnableCanary(config CanaryConfig, schedule string) error {
+	if schedule != "" {
+		config.Schedule = schedule
+	}
+	canary := NewCanary(sm.alertDispatcher, sm.logger)
+	if err := canary.SetConfig(sm.resolveCanaryConfig(config)); err != nil {
+		return err
+	}
+	sm.canary = canary
+	return nil
+}
+
+// resolveCanaryConfig IMAP 비밀번호 (환경 변수/키체인) 와 비어 있는 IMAP 계정에 SMTP 계정 적용
+func (sm *SyslogMonitor) resolveCanaryConfig(config CanaryConfig) CanaryConfig {
+	if config.IMAP.Server == "" {
+		return config
+	}
+	config.IMAP.Password, _ = ResolveSecret(SecretCanaryIMAPPassword, "", config.IMAP.Password)
+	if sm.emailService != nil {
+		email := sm.emailService.currentConfig()
+		if config.IMAP.Username == "" {
+			config.IMAP.Username = email.Username
+		}
+		if config.IMAP.Password == "" {
+			config.IMAP.Password = email.Password
+		}
+	}
+	return config
+}
+
+// SetElasticsearchOutput 파싱된 로그와 AI 분석 결과를 색인할 Elasticsearch/OpenSearch 출력 설정
+func (sm *SyslogMonitor) SetElasticsearchOutput(output *ElasticsearchOutput) {
+	sm.esOutput = output
+}
+
+// SetEventSampler 종료 시 학습용 층화 표본을 저장할 표본 추출기 설정
+func (sm *SyslogMonitor) SetEventSampler(sampler *EventSampler) {
+	sm.eventSampler = sampler
+}
+
+// SetSyslogForwarder 필터를 통과한 로그를 전달할 상위 syslog 서버 출력 설정
+func (sm *SyslogMonitor) SetSyslogForwarder(forwarder *SyslogForwarder) {
+	sm.forwarder = forwarder
+}
+
+// SetKafkaOutput 파싱된 로그(또는 알림)를 발행할 Kafka 출력 설정
+// 알림만 발행하는 모드이면 알림 채널로도 등록
+func (sm *SyslogMonitor) SetKafkaOutput(output *KafkaOutput) {
+	sm.kafkaOutput = output
+	if output.alertsOnly {
+		sm.AddAlertSink("kafka", output)
+	}
+}
+
+// WatchOutputBuffer 출력 디스크 버퍼가 한도에 가까워지면 이 모니터의 알림 채널로 알림
+func (sm *SyslogMonitor) WatchOutputBuffer(buffer *OutputBuffer) {
+	buffer.OnPressure(func(stats BufferStats) {
+		sm.logger.Warnf("💾 %s output buffer at %d MB of %d MB (%d records waiting)", stats.Output, stats.Bytes>>20, stats.MaxBytes>>20, stats.Records)
+		sm.alertDispatcher.Dispatch(outputBufferAlert(stats))
+	})
+}
+
+// outputBufferStats 출력 디스크 버퍼 상태 (버퍼를 쓰지 않으면 nil)
+func (sm *SyslogMonitor) outputBufferStats() []BufferStats {
+	var stats []BufferStats
+	if sm.esOutput != nil && sm.esOutput.Buffer() != nil {
+		stats = append(stats, sm.esOutput.Buffer().Stats())
+	}
+	if sm.kafkaOutput != nil && sm.kafkaOutput.Buffer() != nil {
+		stats = append(stats, sm.kafkaOutput.Buffer().Stats())
+	}
+	return stats
+}
+
+// runJournaldInput journalctl 스트림을 기존 처리 파이프라인에 연결
+func (sm *SyslogMonitor) runJournaldInput(ctx context.Context) error {
+	reader, err := NewJournaldReader(sm.journaldUnits, sm.logger)
+	if err != nil {
+		return err
+	}
+
+	if len(sm.journaldUnits) > 0 {
+		sm.logger.Infof("Journald monitor started (units: %s). Press Ctrl+C to stop.", strings.Join(sm.journaldUnits, ", "))
+	} else {
+		sm.logger.Info("Journald monitor started. Press Ctrl+C to stop.")
+	}
+
+	return sm.runEntryLoop(reader.Entries(), reader.Stop, ctx.Done(), "journalctl stream ended unexpectedly")
+}
+
+// runEventLogInput Windows 이벤트 로그 폴링 결과를 기존 처리 파이프라인에 연결
+func (sm *SyslogMonitor) runEventLogInput(ctx context.Context) error {
+	reader, err := NewEventLogReader(sm.eventLogChannels, sm.logger)
+	if err != nil {
+		return err
+	}
+
+	sm.logger.Infof("Event log monitor started (channels: %s). Press Ctrl+C to stop.", strings.Join(sm.eventLogChannels, ", "))
+
+	return sm.runEntryLoop(reader.Entries(), reader.Stop, ctx.Done(), "event log reader stopped unexpectedly")
+}
+
+// runKubernetesInput 파드 로그 스트림을 기존 처리 파이프라인에 연결
+func (sm *SyslogMonitor) runKubernetesInput(ctx context.Context) error {
+	reader, err := NewKubeLogReader(*sm.kubeOptions, sm.logger)
+	if err != nil {
+		return err
+	}
+
+	sm.logger.Infof("☸️  Kubernetes monitor started (%s). Press Ctrl+C to stop.", reader.options.Describe())
+
+	return sm.runEntryLoop(reader.Entries(), reader.Stop, ctx.Done(), "Kubernetes log reader stopped unexpectedly")
+}
+
+// runSyslogListenerInput 네트워크로 수신한 syslog 메시지를 기존 처리 파이프라인에 연결
+func (sm *SyslogMonitor) runSyslogListenerInput(ctx context.Context) error {
+	listener, err := NewSyslogListener(sm.listenTargets, sm.listenAllow, sm.logger)
+	if err != nil {
+		return err
+	}
+
+	sm.logger.Infof("📡 Syslog listener started (%s). Press Ctrl+C to stop.", strings.Join(listener.Addresses(), ", "))
+
+	return sm.runEntryLoop(listener.Entries(), listener.Stop, ctx.Done(), "syslog listener stopped unexpectedly")
+}
+
+// runEntryLoop 구조화된 입력(journald, 이벤트 로그, 쿠버네티스, 네트워크 syslog)의 엔트리를 처리하는 select 루프
+func (sm *SyslogMonitor) runEntryLoop(entries <-chan JournalEntry, stop func(), done <-chan struct{}, endedMessage string) error {
+	for {
+		select {
+		case entry, ok := <-entries:
+			if !ok {
+				return fmt.Errorf("%s", endedMessage)
+			}
+			sm.processEntry(entry.Source, entry.Line, entry.Parsed)
+
+		case reason := <-sm.configReloads():
+			sm.reloadConfig(reason)
+
+		case fn := <-sm.controls:
+			fn()
+
+		case <-done:
+			sm.logger.Info("Shutting down syslog monitor...")
+			sm.shutdown()
+			stop()
+			return nil
+		}
+	}
+}
+
+// sendLoginAlert 로그인 알림 전송 (시스템 리소스 정보 포함)
+func (sm *SyslogMonitor) sendLoginAlert(loginInfo *LoginInfo, parsed map[string]string) {
+	// 이메일 제목 생성 (상태별 구분)
+	var subject, headline string
+	var statusEmoji string
+	
+	switch loginInfo.Status {
+	case "accepted":
+		statusEmoji = "✅"
+		subject = fmt.Sprintf("[%s LOGIN SUCCESS] %s logged in from %s", AppName, loginInfo.User, loginInfo.IP)
+		headline = "✅ SSH Login Successful"
+	case "failed":
+		statusEmoji = "❌"
+		subject = fmt.Sprintf("[%s LOGIN FAILED] Failed login attempt for %s from %s", AppName, loginInfo.User, loginInfo.IP)
+		headline = "❌ SSH Login Failed"
+	case "sudo":
+		statusEmoji = "⚡"
+		subject = fmt.Sprintf("[%s SUDO COMMAND] %s executed sudo command", AppName, loginInfo.User)
+		headline = "⚡ Sudo Command Executed"
+		if loginInfo.Shell {
+			subject = fmt.Sprintf("[%s ROOT SHELL] %s opened an interactive shell as %s", AppName, loginInfo.User, loginInfo.RunAs)
+			headline = "⚡ Interactive Root Shell Opened"
+		}
+	case "sudo_denied":
+		statusEmoji = "⛔"
+		subject = fmt.Sprintf("[%s SUDO DENIED] %s: %s", AppName, loginInfo.User, loginInfo.DenyReason)
+		headline = "⛔ Sudo Denied"
+	case "web_login":
+		statusEmoji = "🌐"
+		subject = fmt.Sprintf("[%s WEB LOGIN] %s logged in via web from %s", AppName, loginInfo.User, loginInfo.IP)
+		headline = "🌐 Web Login Detected"
+	default:
+		statusEmoji = "🔐"
+		subject = fmt.Sprintf("[%s LOGIN ACTIVITY] User activity detected: %s", AppName, loginInfo.Status)
+		headline = "🔐 User Activity"
+	}
+
+	// 위험 명령 정책 위반은 상태와 관계없이 최우선 표시
+	if loginInfo.PolicyViolation != "" {
+		statusEmoji = "🚨"
+		subject = fmt.Sprintf("[%s SUDO POLICY VIOLATION] %s ran a denied command as %s", AppName, loginInfo.User, loginInfo.RunAs)
+		headline = "🚨 Sudo Policy Violation"
+	}
+
+	// 평소와 다른 호스팅 사업자 ASN에서 인증 (계정 탈취 의심)
+	if loginInfo.ASNChange != nil {
+		statusEmoji = "🚨"
+		subject = fmt.Sprintf("[%s ASN CHANGE] %s logged in from hosting provider %s (%s)",
+			AppName, loginInfo.User, loginInfo.ASNChange.Provider, loginInfo.ASNChange.ASN)
+		headline = "🚨 Login From Unusual Hosting Provider"
+	}
+
+	// 연속 실패 직후 성공한 로그인 (무차별 대입 성공 의심)
+	if loginInfo.BruteForceSuspected {
+		statusEmoji = "🚨"
+		subject = fmt.Sprintf("[%s SUSPICIOUS LOGIN] %s logged in from %s after %d failed attempts",
+			AppName, loginInfo.User, loginInfo.IP, loginInfo.PriorFailures)
+		headline = "🚨 Login Succeeded After Repeated Failures"
+	}
+
+	// 알림 본문 섹션 생성 (이메일/Slack/Telegram 이 같은 섹션을 채널 상세 수준에 맞춰 렌더링)
+	overview := []AlertField{
+		{Label: "🕐 감지 시간", Value: loginInfo.Timestamp.Format("2006-01-02 15:04:05"), Short: true},
+		{Label: "👤 사용자", Value: loginInfo.User, Short: true},
+		{Label: "📍 상태", Value: fmt.Sprintf("%s %s", loginInfo.Status, statusEmoji), Short: true},
+	}
+	if loginInfo.IP != "" {
+		overview = append(overview, AlertField{Label: "🌐 IP 주소", Value: loginInfo.IP, Short: true})
+	}
+	if loginInfo.ReverseDNS.Hostname != "" {
+		overview = append(overview, AlertField{Label: "🔁 역방향 DNS", Value: loginInfo.ReverseDNS.String(), Short: true})
+	}
+	if loginInfo.Method != "" {
+		overview = append(overview, AlertField{Label: "🔑 인증 방법", Value: loginInfo.Method, Short: true})
+	}
+	overview = append(overview, AlertField{Label: "🖥️  호스트", Value: parsed["host"], Short: true})
+	sections := []AlertSection{{Fields: overview, Summary: true}}
+
+	// 실패 → 성공 시퀀스 정보
+	if loginInfo.BruteForceSuspected {
+		sections = append(sections, AlertSection{
+			Title: "🚨 무차별 대입 공격 성공 의심",
+			Text: fmt.Sprintf("동일 사용자/IP 에서 로그인 실패 %d회 직후 로그인에 성공했습니다.\n계정 탈취 여부를 즉시 확인하세요.",
+				loginInfo.PriorFailures),
+			Summary: true,
+		})
+	}
+
+	// 호스팅 사업자 ASN 변경 정보
+	if loginInfo.ASNChange != nil {
+		sections = append(sections, AlertSection{
+			Title: "🚨 평소와 다른 네트워크(ASN)에서 인증",
+			Fields: []AlertField{
+				{Label: "이번 로그인", Value: fmt.Sprintf("%s (%s, 호스팅 사업자)", loginInfo.ASNChange.ASN, loginInfo.ASNChange.Provider), Short: true},
+				{Label: "평소 로그인 ASN", Value: strings.Join(loginInfo.ASNChange.UsualASNs, ", "), Short: true},
+			},
+			Text:    "사용자가 평소 사용하지 않던 호스팅/클라우드 사업자에서 로그인했습니다.\n계정 탈취 또는 키 유출 여부를 확인하세요.",
+			Summary: true,
+		})
+	}
+
+	// Sudo 명령어 정보
+	if loginInfo.Command != "" || loginInfo.RunAs != "" {
+		command := AlertSection{
+			Title: "⚡ 실행된 명령어",
+			Fields: []AlertField{
+				{Label: "명령어", Value: loginInfo.Command, Code: true},
+				{Label: "대상 사용자", Value: loginInfo.RunAs, Short: true},
+				{Label: "TTY", Value: loginInfo.TTY, Short: true},
+				{Label: "작업 디렉토리", Value: loginInfo.CWD, Short: true},
+				{Label: "대화형 셸", Value: fmt.Sprintf("%t", loginInfo.Shell), Short: true},
+			},
+			Summary: true,
+		}
+		if loginInfo.DenyReason != "" {
+			command.Fields = append(command.Fields, AlertField{Label: "⛔ 거부 사유", Value: loginInfo.DenyReason, Short: true})
+		}
+		if loginInfo.PolicyViolation != "" {
+			command.Fields = append(command.Fields, AlertField{Label: "🚨 위험 명령 정책 위반 (일치 패턴)", Value: loginInfo.PolicyViolation, Code: true})
+		}
+		sections = append(sections, command)
+	}
+
+	// IP 위치 정보
+	if loginInfo.IPDetails != nil {
+		ipType := "공인 IP"
+		if loginInfo.IPDetails.IsPrivate {
+			ipType = "사설 IP"
+		}
+		location := AlertSection{
+			Title: "🌍 IP 위치 정보",
+			Fields: []AlertField{
+				{Label: "🏴 국가", Value: loginInfo.IPDetails.Country, Short: true},
+				{Label: "🏙️  도시", Value: fmt.Sprintf("%s, %s", loginInfo.IPDetails.City, loginInfo.IPDetails.Region), Short: true},
+				{Label: "🏢 조직/ISP", Value: loginInfo.IPDetails.Organization, Short: true},
+				{Label: "🔢 ASN", Value: loginInfo.IPDetails.ASN, Short: true},
+				{Label: "🔒 IP 유형", Value: ipType, Short: true},
+				{Label: "⚠️  위험도", Value: threatLevelEmoji(loginInfo.IPDetails.Threat) + " " + loginInfo.IPDetails.Threat, Short: true},
+			},
+			Summary: true,
+		}
+		if loginInfo.TrustedNetwork != "" {
+			location.Fields = append(location.Fields, AlertField{Label: "🏠 신뢰 네트워크", Value: loginInfo.TrustedNetwork + " (GeoIP 조회 및 위험도 평가 생략)"})
+		}
+		if loginInfo.IPDetails.Anonymizer != "" {
+			location.Fields = append(location.Fields, AlertField{Label: "🕵️  익명화 출처", Value: loginInfo.IPDetails.Anonymizer + " (Tor/VPN/프록시 경유 접속)"})
+		}
+		if loginInfo.IPDetails.AbuseScore > 0 {
+			location.Fields = append(location.Fields, AlertField{Label: "🛑 AbuseIPDB 신뢰 점수", Value: fmt.Sprintf("%d%% (신고 %d건)", loginInfo.IPDetails.AbuseScore, loginInfo.IPDetails.AbuseReports), Short: true})
+		}
+		if loginInfo.IPDetails.Blocklist != "" {
+			location.Fields = append(location.Fields, AlertField{Label: "🚫 위협 블록리스트", Value: loginInfo.IPDetails.Blocklist, Short: true})
+		}
+		sections = append(sections, location)
+	}
+
+	// 조회 테이블 보강 정보 (IP 담당자, 사용자 부서 등)
+	if len(loginInfo.Enrichment) > 0 {
+		names := make([]string, 0, len(loginInfo.Enrichment))
+		for name := range loginInfo.Enrichment {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		enrichment := AlertSection{Title: "📇 조회 테이블 정보", Summary: true}
+		for _, name := range names {
+			enrichment.Fields = append(enrichment.Fields, AlertField{Label: name, Value: loginInfo.Enrichment[name], Short: true})
+		}
+		sections = append(sections, enrichment)
+	}
+
+	// 시스템 리소스 정보 (로그인 시점)
+	sections = append(sections, AlertSection{
+		Title: "🖥️  시스템 리소스 정보 (로그인 시점)",
+		Text: fmt.Sprintf(`💻 CPU 사용률: %.1f%% (코어: %d개)
+  ├ 사용자: %.1f%%
+  ├ 시스템: %.1f%%
+  └ 대기: %.1f%%
+
+🧠 메모리 사용률: %.1f%%
+  ├ 총 메모리: %.1f GB
+  ├ 사용 중: %.1f GB
+  ├ 사용 가능: %.1f GB
+  └ 스왑 사용: %.1f MB
+
+🌡️  시스템 온도: %.1f°C
+⚖️  로드 평균: %.2f (1분), %.2f (5분), %.2f (15분)`,
+			loginInfo.SystemInfo.CPU.UsagePercent,
+			loginInfo.SystemInfo.CPU.Cores,
+			loginInfo.SystemInfo.CPU.UserPercent,
+			loginInfo.SystemInfo.CPU.SystemPercent,
+			loginInfo.SystemInfo.CPU.IdlePercent,
+			loginInfo.SystemInfo.Memory.UsagePercent,
+			loginInfo.SystemInfo.Memory.TotalMB/1024,
+			loginInfo.SystemInfo.Memory.UsedMB/1024,
+			loginInfo.SystemInfo.Memory.AvailableMB/1024,
+			loginInfo.SystemInfo.Memory.SwapUsedMB,
+			loginInfo.SystemInfo.Temperature.CPUTemp,
+			loginInfo.SystemInfo.LoadAverage.Load1Min,
+			loginInfo.SystemInfo.LoadAverage.Load5Min,
+			loginInfo.SystemInfo.LoadAverage.Load15Min,
+		),
+	})
+
+	// 디스크 사용량 정보 (모든 주요 디스크)
+	if len(loginInfo.SystemInfo.Disk) > 0 {
+		var diskText string
+		var totalUsed, totalSize float64
+		for _, disk := range loginInfo.SystemInfo.Disk {
+			// 모든 실제 디스크 표시 (tmpfs, proc 등 가상 파일시스템 제외)
+			if disk.TotalGB > 0 && !strings.Contains(disk.Device, "tmpfs") && 
+			   !strings.Contains(disk.Device, "proc") && !strings.Contains(disk.Device, "sys") {
+				
+				// 사용률에 따른 상태 이모지
+				var statusEmoji string
+				if disk.UsagePercent >= 90 {
+					statusEmoji = "🔴" // 위험
+				} else if disk.UsagePercent >= 75 {
+					statusEmoji = "🟡" // 경고
+				} else {
+					statusEmoji = "🟢" // 정상
+				}
+				
+				diskText += fmt.Sprintf("  %s 📁 %s (%s)\n", statusEmoji, disk.MountPoint, disk.Device)
+				diskText += fmt.Sprintf("     ├ 사용률: %.1f%% (%.1fGB / %.1fGB)\n", 
+					disk.UsagePercent, disk.UsedGB, disk.TotalGB)
+				diskText += fmt.Sprintf("     ├ 남은공간: %.1f GB (%.1f%%)\n", 
+					disk.FreeGB, 100-disk.UsagePercent)
+				if disk.InodeUsagePercent > 0 {
+					diskText += fmt.Sprintf("     └ inode 사용률: %.1f%%\n", disk.InodeUsagePercent)
+				} else {
+					diskText += fmt.Sprintf("     └ 여유공간: %.1f GB\n", disk.FreeGB)
+				}
+				diskText += "\n"
+				
+				totalUsed += disk.UsedGB
+				totalSize += disk.TotalGB
+			}
+		}
+		
+		// 전체 디스크 요약
+		if totalSize > 0 {
+			totalFree := totalSize - totalUsed
+			totalUsagePercent := (totalUsed / totalSize) * 100
+			diskText += fmt.Sprintf("📊 전체 디스크 요약:\n")
+			diskText += fmt.Sprintf("   ├ 총 용량: %.1f GB\n", totalSize)
+			diskText += fmt.Sprintf("   ├ 사용량: %.1f GB (%.1f%%)\n", totalUsed, totalUsagePercent)
+			diskText += fmt.Sprintf("   └ 여유공간: %.1f GB (%.1f%%)\n", totalFree, 100-totalUsagePercent)
+		}
+		if diskText != "" {
+			sections = append(sections, AlertSection{Title: "💾 디스크 사용량 상세정보", Text: diskText})
+		}
+	}
+
+	// 보안 권장사항
+	sections = append(sections, AlertSection{
+		Title: "🛡️  보안 권장사항",
+		Text: `• 알 수 없는 IP에서의 로그인 시도인지 확인하세요
+• 시스템 리소스 사용량이 평소보다 높은지 확인하세요
+• 비정상적인 시간대 로그인은 주의가 필요합니다
+• 실패한 로그인 시도가 반복되면 IP 차단을 고려하세요
+• 정기적으로 로그인 기록을 검토하세요
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🤖 AI-Powered Syslog Monitor v2.0.0
+Lambda-X AI Security Team`,
+	})
+
+	// 설정된 모든 알림 채널로 전송 (비동기)
+	sm.alertDispatcher.Dispatch(Alert{
+		Type:      AlertTypeLogin,
+		Severity:  loginAlertSeverity(loginInfo),
+		Title:     subject,
+		Headline:  headline,
+		Sections:  sections,
+		Host:      parsed["host"],
+		Thread:    alertThreadKey(AlertTypeLogin, parsed["host"], loginInfo.User+"@"+loginInfo.IP),
+		Fields:    loginInfo.ToMap(),
+		Timestamp: loginInfo.Timestamp,
+	})
+}
+
+// handleBruteForceAttack 무차별 대입 공격 출발지 IP 차단 후 전용 알림 전송
+func (sm *SyslogMonitor) handleBruteForceAttack(loginInfo *LoginInfo, parsed map[string]string) {
+	attack := loginInfo.BruteForceAttack
+
+	var blockStatus string
+	switch {
+	case sm.ipBlocker == nil:
+		blockStatus = "자동 차단 비활성화 (-block-action)"
+	case loginInfo.TrustedNetwork != "":
+		blockStatus = fmt.Sprintf("차단하지 않음 (신뢰 네트워크: %s)", loginInfo.TrustedNetwork)
+	default:
+		if name, allowed := sm.ipBlocker.Allowed(attack.IP); allowed {
+			blockStatus = fmt.Sprintf("차단하지 않음 (허용 목록: %s)", name)
+			break
+		}
+		until, err := sm.ipBlocker.Block(attack.IP)
+		switch {
+		case err != nil:
+			blockStatus = fmt.Sprintf("차단 실패: %v", err)
+			sm.logger.Errorf("❌ Failed to block %s: %v", attack.IP, err)
+		case until.IsZero():
+			blockStatus = fmt.Sprintf("차단됨 (%s, 자동 해제 안 함)", sm.ipBlocker.Action())
+		default:
+			blockStatus = fmt.Sprintf("차단됨 (%s, %s 자동 해제)", sm.ipBlocker.Action(), until.Format("2006-01-02 15:04:05"))
+		}
+	}
+
+	if !sm.alertDispatcher.HasSinks() {
+		return
+	}
+
+	users := strings.Join(attack.Users, ", ")
+	if users == "" {
+		users = "(알 수 없음)"
+	}
+	sections := []AlertSection{
+		{
+			Title: "🚫 공격 정보",
+			Fields: []AlertField{
+				{Label: "출발지 IP", Value: attack.IP, Short: true, Code: true},
+				{Label: "실패 횟수", Value: fmt.Sprintf("%d회 / %v", attack.Failures, attack.Window), Short: true},
+				{Label: "시도한 사용자", Value: users},
+				{Label: "첫 실패", Value: attack.FirstSeen.Format("2006-01-02 15:04:05"), Short: true},
+				{Label: "호스트", Value: parsed["host"], Short: true},
+			},
+			Summary: true,
+		},
+		{
+			Title:   "🛡️ 대응",
+			Fields:  []AlertField{{Label: "IP 차단", Value: blockStatus}},
+			Summary: true,
+		},
+	}
+	if details := loginInfo.IPDetails; details != nil {
+		sections = append(sections, AlertSection{
+			Title: "🌍 출발지 위치",
+			Fields: []AlertField{
+				{Label: "국가", Value: details.Country, Short: true},
+				{Label: "도시", Value: details.City, Short: true},
+				{Label: "조직", Value: details.Organization, Short: true},
+				{Label: "ASN", Value: details.ASN, Short: true},
+				{Label: "위험도", Value: fmt.Sprintf("%s %s", threatLevelEmoji(details.Threat), details.Threat), Short: true},
+			},
+		})
+	}
+
+	alert := Alert{
+		Type:     AlertTypeBruteForce,
+		Severity: AlertSeverityCritical,
+		Title:    fmt.Sprintf("[%s BRUTE FORCE] %s - %d failed logins from %s", AppName, parsed["host"], attack.Failures, attack.IP),
+		Headline: "🚫 Brute-Force Attack Detected",
+		Sections: sections,
+		Host:     parsed["host"],
+		Thread:   alertThreadKey(AlertTypeBruteForce, parsed["host"], attack.IP),
+		Fields: map[string]string{
+			"ip":       attack.IP,
+			"failures": strconv.Itoa(attack.Failures),
+			"users":    strings.Join(attack.Users, ","),
+			"block":    blockStatus,
+		},
+		Timestamp: loginInfo.Timestamp,
+	}
+
+	// abuse 연락처 조회 (-whois-abuse, 조회 중이면 조회 고루틴에서 전송)
+	sm.whoisLookup.LookupAsync(attack.IP, func(contact *AbuseContact) {
+		if contact != nil {
+			fields := []AlertField{
+				{Label: "신고 이메일", Value: strings.Join(contact.Emails, ", "), Code: true},
+				{Label: "네트워크", Value: strings.TrimSpace(contact.Network + " " + contact.Handle), Short: true},
+				{Label: "대역", Value: contact.Range, Short: true},
+			}
+			if len(contact.Emails) == 0 {
+				fields[0] = AlertField{Label: "신고 이메일", Value: "(등록된 abuse 연락처 없음)"}
+			}
+			if contact.Organization != "" {
+				fields = append(fields, AlertField{Label: "조직", Value: contact.Organization, Short: true})
+			}
+			if len(contact.Phones) > 0 {
+				fields = append(fields, AlertField{Label: "전화", Value: strings.Join(contact.Phones, ", "), Short: true})
+			}
+			alert.Sections = append(alert.Sections, AlertSection{Title: "📮 Abuse 연락처", Fields: fields})
+			alert.Fields["abuse_email"] = contact.Email()
+			alert.Fields["abuse_network"] = contact.Handle
+		}
+		sm.logger.Warnf("🚨 Sending brute-force alert for %s via: %s", attack.IP, strings.Join(sm.alertDispatcher.SinkNames(), ", "))
+		sm.alertDispatcher.Dispatch(alert)
+	})
+}
+
+// handleSQLInjectionConfirmation 웹 SQL 인젝션 시도가 DB 로그로 확인되면 기록 후 알림 전송 (클라이언트별 간격 제한)
+func (sm *SyslogMonitor) handleSQLInjectionConfirmation(confirmation *SQLInjectionConfirmation) {
+	if confirmation == nil {
+		return
+	}
+	sm.logger.WithFields(logrus.Fields{
+		"level":    "SQLI_CONFIRMED",
+		"client":   confirmation.Attempt.Client,
+		"url":      confirmation.Attempt.URL,
+		"engine":   confirmation.Evidence.Engine,
+		"evidence": confirmation.Evidence.Kind,
+		"links":    strings.Join(confirmation.Links, ","),
+	}).Warnf("🚨 SQL injection confirmed by database log: %s (alert: %t)", confirmation.Attempt.Client, confirmation.ShouldAlert)
+
+	if !confirmation.ShouldAlert || !sm.alertDispatcher.HasSinks() {
+		return
+	}
+	sm.logger.Infof("🔔 Sending SQL injection alert via: %s", strings.Join(sm.alertDispatcher.SinkNames(), ", "))
+	sm.dispatchWebAlert(sqlInjectionConfirmedAlert(confirmation), confirmation.Attempt.Client)
+}
+
+// startSQLInjectionDBLogs 추가 DB 로그 파일을 tail 하여 상관 분석 증거로만 사용 (처리 고루틴에서 관찰)
+func (sm *SyslogMonitor) startSQLInjectionDBLogs() {
+	for _, path := range sm.sqliDBLogs {
+		tailer, err := tail.TailFile(path, tail.Config{
+			Follow:   true,
+			ReOpen:   true,
+			Poll:     true,
+			Location: &tail.SeekInfo{Offset: 0, Whence: 2}, // 파일 끝에서 시작
+		})
+		if err != nil {
+			sm.logger.Errorf("Failed to tail %s: %v", path, err)
+			continue
+		}
+		sm.sqliTails = append(sm.sqliTails, tailer)
+		go func(tailer *tail.Tail) {
+			for line := range tailer.Lines {
+				if line.Err != nil {
+					sm.logger.Errorf("Error reading line: %v", line.Err)
+					continue
+				}
+				text := line.Text
+				sm.controls <- func() {
+					sm.handleSQLInjectionConfirmation(sm.sqliCorrelator.ObserveDBLine(text, time.Now()))
+				}
+			}
+		}(tailer)
+	}
+}
+
+// startModSecurityAuditLog 감사 로그를 tail 하여 트랜잭션 단위로 조립 후 처리 고루틴에서 상관 분석
+func (sm *SyslogMonitor) startModSecurityAuditLog() {
+	tailer, err := tail.TailFile(sm.modSecurityLog, tail.Config{
+		Follow:   true,
+		ReOpen:   true,
+		Poll:     true,
+		Location: &tail.SeekInfo{Offset: 0, Whence: 2}, // 파일 끝에서 시작
+	})
+	if err != nil {
+		sm.logger.Errorf("Failed to tail %s: %v", sm.modSecurityLog, err)
+		return
+	}
+	sm.modSecurityTail = tailer
+	go func() {
+		reader := &ModSecurityAuditReader{}
+		for line := range tailer.Lines {
+			if line.Err != nil {
+				sm.logger.Errorf("Error reading line: %v", line.Err)
+				continue
+			}
+			entry, ok := reader.Add(line.Text)
+			if !ok {
+				continue
+			}
+			tx, ok := ParseModSecurityAudit(entry)
+			if !ok {
+				continue
+			}
+			sm.controls <- func() {
+				sm.handleModSecurityTransaction(tx)
+			}
+		}
+	}()
+}
+
+// handleModSecurityTransaction 감사 로그 트랜잭션 처리 (접근 로그 줄이 아직 없으면 잠시 기다린 뒤 알림)
+func (sm *SyslogMonitor) handleModSecurityTransaction(tx *ModSecurityTransaction) {
+	detection, pending := sm.modSecurity.AddTransaction(tx, time.Now())
+	if detection != nil {
+		sm.handleModSecurityDetections([]*ModSecurityDetection{detection})
+	}
+	if pending {
+		time.AfterFunc(modSecurityAccessWait, func() {
+			sm.controls <- func() {
+				sm.handleModSecurityDetections(sm.modSecurity.ExpirePending(time.Now()))
+			}
+		})
+	}
+}
+
+// handleModSecurityDetections ModSecurity 탐지 기록 후 웹 공격 알림 전송 (클라이언트별 간격 제한)
+func (sm *SyslogMonitor) handleModSecurityDetections(detections []*ModSecurityDetection) {
+	for _, detection := range detections {
+		tx := detection.Transaction
+		sm.logger.WithFields(logrus.Fields{
+			"level":         "WAF",
+			"threat":        detection.ThreatLevel,
+			"client":        tx.Client,
+			"url":           tx.URI,
+			"rules":         strings.Join(tx.RuleIDs(), ","),
+			"anomaly_score": tx.AnomalyScore,
+			"intercepted":   tx.Intercepted,
+			"access_link":   detection.Link,
+		}).Warnf("🛡️ ModSecurity %s detection: %s %s %s (alert: %t)", detection.ThreatLevel, tx.Client, tx.Method, tx.URI, detection.ShouldAlert)
+
+		if !detection.ShouldAlert || !sm.alertDispatcher.HasSinks() {
+			continue
+		}
+		sm.logger.Infof("🔔 Sending web attack alert via: %s", strings.Join(sm.alertDispatcher.SinkNames(), ", "))
+		sm.dispatchWebAlert(modSecurityAlert(detection), tx.Client)
+	}
+}
+
+// handleSLOBurn SLO burn rate 알림/복구 기록 후 알림 전송
+func (sm *SyslogMonitor) handleSLOBurn(event *SLOBurnEvent) {
+	fields := logrus.Fields{
+		"level":            "SLO",
+		"kind":             event.Kind,
+		"slo":              event.SLO.Name,
+		"target":           event.SLO.Target,
+		"long_burn":        fmt.Sprintf("%.2f", event.LongBurn),
+		"short_burn":       fmt.Sprintf("%.2f", event.ShortBurn),
+		"budget_remaining": fmt.Sprintf("%.1f", event.BudgetRemaining),
+	}
+	if event.Kind == SLOBurnResolved {
+		sm.logger.WithFields(fields).Infof("🎯 SLO %s burn rate recovered (%.1fx over %v)", event.SLO.Name, event.ShortBurn, event.ShortWindow)
+	} else {
+		sm.logger.WithFields(fields).Warnf("🔥 SLO %s error budget burning at %.1fx over %v (%s burn, %.1f%% budget left)", event.SLO.Name, event.LongBurn, event.LongWindow, event.Kind, event.BudgetRemaining)
+	}
+
+	if !sm.alertDispatcher.HasSinks() {
+		return
+	}
+	sm.logger.Infof("🔔 Sending SLO alert via: %s", strings.Join(sm.alertDispatcher.SinkNames(), ", "))
+	sm.alertDispatcher.Dispatch(sloBurnAlert(event))
+}
+
+// SetLatencyTracer 표본 이벤트의 단계별 지연 시간 추적 설정 (느린 이벤트는 단계별 시간과 함께 로그)
+func (sm *SyslogMonitor) SetLatencyTracer(tracer *LatencyTracer) {
+	tracer.onSlow = sm.handleSlowEvent
+	sm.latencyTracer = tracer
+}
+
+// handleSlowEvent 예산을 넘은 표본 이벤트의 단계별 소요 시간 기록 (병목 단계 확인용)
+func (sm *SyslogMonitor) handleSlowEvent(trace *EventTrace) {
+	line := trace.Line
+	if len(line) > 200 {
+		line = line[:200] + "..."
+	}
+	sm.logger.WithFields(logrus.Fields{
+		"total":  formatLatency(trace.Total()),
+		"stages": trace.Breakdown(),
+	}).Warnf("🐢 Slow event (over %v): %s", sm.latencyTracer.Budget(), line)
+}
+
+// handleUnknownFormat 소스의 형식 미인식 비율 초과/복구 기록 후 알림 전송 (로그 형식 변경 의심)
+func (sm *SyslogMonitor) handleUnknownFormat(event *UnknownFormatEvent) {
+	fields := logrus.Fields{
+		"level":        "PARSER",
+		"kind":         event.Kind,
+		"source":       event.Source,
+		"unknown_rate": fmt.Sprintf("%.1f", event.Rate),
+		"lines":        event.Lines,
+	}
+	if event.Kind == UnknownFormatResolved {
+		sm.logger.WithFields(fields).Infof("🧩 %s lines are parsed again (%.1f%% unknown over %v)", event.Source, event.Rate, event.Window.Round(time.Second))
+	} else {
+		sm.logger.WithFields(fields).Warnf("🧩 %.1f%% of %s lines were not recognised by any parser over %v (previously %.1f%%) - the log format may have changed", event.Rate, event.Source, event.Window.Round(time.Second), event.Previous)
+	}
+
+	if !sm.alertDispatcher.HasSinks() {
+		return
+	}
+	sm.logger.Infof("🔔 Sending parser alert via: %s", strings.Join(sm.alertDispatcher.SinkNames(), ", "))
+	sm.alertDispatcher.Dispatch(unknownFormatAlert(event))
+}
+
+// handleBaselineAnomaly 서비스 통계 기준선 이탈 기록 후 알림 전송 (이탈이 시작될 때 한 번)
+func (sm *SyslogMonitor) handleBaselineAnomaly(anomaly BaselineAnomaly) {
+	sm.logger.WithFields(logrus.Fields{
+		"level":    "BASELINE",
+		"service":  anomaly.Service,
+		"metric":   anomaly.Metric,
+		"value":    anomaly.Value,
+		"baseline": fmt.Sprintf("%.2f", anomaly.Baseline),
+		"z_score":  fmt.Sprintf("%.1f", anomaly.ZScore),
+	}).Warnf("📈 %s", anomaly.Describe())
+
+	if !sm.alertDispatcher.HasSinks() {
+		return
+	}
+	sm.logger.Infof("🔔 Sending baseline alert via: %s", strings.Join(sm.alertDispatcher.SinkNames(), ", "))
+	sm.alertDispatcher.Dispatch(baselineAnomalyAlert(anomaly, sm.aiAnalyzer.BaselineZThreshold()))
+}
+
+// handleExfiltration 응답 크기 이상 기록 후 알림 전송 (클라이언트 × 엔드포인트별 윈도우마다 한 번)
+func (sm *SyslogMonitor) handleExfiltration(event *ExfiltrationEvent) {
+	if event == nil {
+		return
+	}
+	sm.logger.WithFields(logrus.Fields{
+		"level":        "EXFILTRATION",
+		"kind":         event.Kind,
+		"client":       event.Client,
+		"endpoint":     event.Endpoint,
+		"bytes":        event.Bytes,
+		"window_bytes": event.WindowBytes,
+		"requests":     event.Requests,
+	}).Warnf("📦 Response size anomaly (%s): %s downloaded %s from %s within %v", event.Kind, event.Client, formatByteSize(event.WindowBytes), event.Endpoint, event.Window)
+
+	if !sm.alertDispatcher.HasSinks() {
+		return
+	}
+	sm.logger.Infof("🔔 Sending exfiltration alert via: %s", strings.Join(sm.alertDispatcher.SinkNames(), ", "))
+	sm.dispatchWebAlert(exfiltrationAlert(event), event.Client)
+}
+
+// handleAccessStats 접근 로그 통계 윈도우의 오류율/공격 패턴 알림 기록 후 전송 (집계 값만)
+func (sm *SyslogMonitor) handleAccessStats(event *AccessStatsEvent) {
+	window := event.Window
+	fields := logrus.Fields{
+		"level":        "ACCESS_STATS",
+		"kind":         event.Kind,
+		"requests":     window.Requests,
+		"error_rate":   fmt.Sprintf("%.2f", window.ErrorRate),
+		"attack_count": window.AttackCount,
+	}
+	switch event.Kind {
+	case AccessEventResolved:
+		sm.logger.WithFields(fields).Infof("✅ Web 5xx error rate recovered to %.1f%% (%d requests)", window.ErrorRate, window.Requests)
+	case AccessEventAttack:
+		sm.logger.WithFields(fields).Warnf("🛡️  %d web requests matched attack patterns within %v: %s", window.AttackCount, window.End.Sub(window.Start), sortedCounts(window.Attacks))
+	default:
+		sm.logger.WithFields(fields).Warnf("🔥 Web 5xx error rate %.1f%% (%d of %d requests, threshold %g%%)", window.ErrorRate, window.Status["5xx"], window.Requests, event.Threshold)
+	}
+
+	if event.Kind == AccessEventResolved {
+		sm.alertDispatcher.Resolve(AlertTypeAccessErrorRate, "")
+	}
+	if !sm.alertDispatcher.HasSinks() {
+		return
+	}
+	sm.logger.Infof("🔔 Sending access statistics alert via: %s", strings.Join(sm.alertDispatcher.SinkNames(), ", "))
+	sm.alertDispatcher.Dispatch(accessStatsAlert(event))
+}
+
+// handleDBSecurityEvent DB 권한 변경/관리자 인증 실패 기록 후 전용 알림 전송 (인증 실패는 간격 제한)
+func (sm *SyslogMonitor) handleDBSecurityEvent(event *DBSecurityEvent, parsed map[string]string, line string) {
+	sm.logger.WithFields(logrus.Fields{
+		"level":     "DB_SECURITY",
+		"kind":      event.Kind,
+		"engine":    event.Engine,
+		"statement": event.Statement,
+		"account":   event.Account,
+		"client":    event.Client,
+		"host":      parsed["host"],
+	}).Warnf("🛡️ Database security event: %s %s (alert: %t)", event.Kind, event.Account, event.ShouldAlert)
+
+	if !event.ShouldAlert || !sm.alertDispatcher.HasSinks() {
+		return
+	}
+	sm.logger.Infof("🔔 Sending database security alert via: %s", strings.Join(sm.alertDispatcher.SinkNames(), ", "))
+	sm.alertDispatcher.Dispatch(dbSecurityAlert(event, parsed, line))
+}
+
+// handleCICDEvent CI/CD 이벤트 기록 후 전용 알림 전송 (반복 기준/알림 간격을 통과한 경우, team 필드로 라우팅)
+func (sm *SyslogMonitor) handleCICDEvent(event *CICDEvent, parsed map[string]string, line string) {
+	if !event.ShouldAlert {
+		return
+	}
+	sm.logger.WithFields(logrus.Fields{
+		"level":   "CICD",
+		"kind":    event.Kind,
+		"ci":      event.System,
+		"project": event.Project,
+		"ip":      event.IP,
+		"team":    event.Team,
+		"host":    parsed["host"],
+	}).Warnf("🏗️  CI/CD event: %s %s", event.Kind, event.Project)
+
+	if !sm.alertDispatcher.HasSinks() {
+		return
+	}
+	sm.logger.Infof("🔔 Sending CI/CD alert via: %s", strings.Join(sm.alertDispatcher.SinkNames(), ", "))
+	sm.alertDispatcher.Dispatch(cicdAlert(event, parsed, line))
+}
+
+// threatLevelEmoji IP 위험도별 이모지
+func threatLevelEmoji(threat string) string {
+	switch threat {
+	case "HIGH":
+		return "🔴"
+	case "MEDIUM":
+		return "🟡"
+	case "LOW":
+		return "🟢"
+	case "TRUSTED":
+		return "🏠"
+	default:
+		return "⚪"
+	}
+}
+
+// loginAlertSeverity 로그인 이벤트 심각도
+// 실패/sudo 는 warning, 거부/정책 위반/무차별 대입 성공 의심/호스팅 ASN 변경은 critical
+// 신뢰 네트워크 출처는 정책 위반/무차별 대입 성공 의심이 아니면 info
+func loginAlertSeverity(loginInfo *LoginInfo) string {
+	severity := AlertSeverityInfo
+	if (!loginInfo.Success || loginInfo.Status == "sudo") && loginInfo.TrustedNetwork == "" {
+		severity = AlertSeverityWarning
+	}
+	if loginInfo.PolicyViolation != "" || loginInfo.BruteForceSuspected || loginInfo.ASNChange != nil || loginInfo.Status == "sudo_denied" {
+		severity = AlertSeverityCritical
+	}
+	// anonymizer_high_risk 설정으로 HIGH 처리된 Tor/VPN/프록시 출처 로그인
+	if loginInfo.IPDetails != nil && loginInfo.IPDetails.Anonymizer != "" && loginInfo.IPDetails.Threat == "HIGH" {
+		severity = AlertSeverityCritical
+	}
+	// 위협 블록리스트 / AbuseIPDB 로 확인된 알려진 악성 IP
+	if loginInfo.IPDetails != nil && loginInfo.IPDetails.KnownBad {
+		severity = AlertSeverityCritical
+	}
+	return severity
+}
+
+// aiAlertSeverity AI 분석 결과 심각도 (HighThreatThreshold 이상이면 critical)
+func aiAlertSeverity(aiResult *AIAnalysisResult) string {
+	if aiResult.AnomalyScore >= HighThreatThreshold {
+		return AlertSeverityCritical
+	}
+	return AlertSeverityWarning
+}
+
+// systemAlertSeverity 시스템 알림 심각도 (CRITICAL 레벨이면 critical)
+func systemAlertSeverity(alert SystemAlert) string {
+	if alert.Level == "CRITICAL" {
+		return AlertSeverityCritical
+	}
+	return AlertSeverityWarning
+}
+
+// sendAIAlert AI 분석 결과 알림 전송 (리팩토링된 버전)
+func (sm *SyslogMonitor) sendAIAlert(aiResult *AIAnalysisResult, parsedLog *ParsedLog) {
+	if !sm.alertDispatcher.HasSinks() {
+		return
+	}
+
+	subject := fmt.Sprintf("[%s %s] %s", AppName, aiResult.ThreatLevel, "이상 징후 감지")
+	workload := kubeWorkloadLabel(parsedLog)
+	if workload != "" {
+		subject += " - " + workload
+	}
+
+	// 알림 본문 섹션 생성 (이메일/Slack/Telegram 이 같은 섹션을 채널 상세 수준에 맞춰 렌더링)
+	sections := []AlertSection{{
+		Fields: []AlertField{
+			{Label: "⚠️  위협 레벨", Value: aiResult.ThreatLevel, Short: true},
+			{Label: "📊 이상 점수", Value: fmt.Sprintf("%.1f/%.0f", aiResult.AnomalyScore, MaxAnomalyScore), Short: true},
+			{Label: "🎯 신뢰도", Value: fmt.Sprintf("%.0f%%", aiResult.Confidence*100), Short: true},
+			{Label: "📍 컴퓨터명", Value: aiResult.SystemInfo.ComputerName, Short: true},
+			{Label: "🕐 탐지 시간", Value: aiResult.Timestamp.Format("2006-01-02 15:04:05"), Short: true},
+		},
+		Summary: true,
+	}}
+
+	// ONNX 모델 점수 (규칙 점수와 결합된 경우)
+	if aiResult.Model != "" {
+		sections[0].Fields = append(sections[0].Fields,
+			AlertField{Label: "📐 규칙 점수", Value: fmt.Sprintf("%.1f", aiResult.RuleScore), Short: true},
+			AlertField{Label: "🧠 모델 점수", Value: fmt.Sprintf("%.1f (%s)", aiResult.ModelScore, aiResult.Model), Short: true},
+		)
+	}
+
+	// 일치한 이상 패턴 규칙 (내장 + 규칙 파일)
+	if len(aiResult.MatchedRules) > 0 {
+		var rules string
+		for _, rule := range aiResult.MatchedRules {
+			rules += fmt.Sprintf("• %s [%s] 심각도 %.1f - %s", rule.Name, rule.Category, rule.Severity, rule.Description)
+			if rule.Action != "" {
+				rules += fmt.Sprintf(" (조치: %s)", rule.Action)
+			}
+			rules += "\n"
+		}
+		sections = append(sections, AlertSection{Title: "📐 일치한 규칙", Text: rules, Summary: true})
+	}
+
+	// 서비스 통계 기준선 이탈 (로그 볼륨/에러율/고유 IP)
+	if len(aiResult.Baseline) > 0 {
+		sections = append(sections, AlertSection{Title: "📈 기준선 이탈", Text: formatBaselineAnomalies(aiResult.Baseline), Summary: true})
+	}
+
+	// 쿠버네티스 워크로드 (파드 로그 입력)
+	if workload != "" {
+		sections = append(sections, AlertSection{
+			Title: "☸️  워크로드",
+			Fields: []AlertField{
+				{Label: "📦 네임스페이스", Value: parsedLog.Fields["namespace"], Short: true},
+				{Label: "🏗️  워크로드", Value: parsedLog.Fields["workload_kind"] + "/" + parsedLog.Fields["workload"], Short: true},
+				{Label: "🧩 파드", Value: parsedLog.Fields["pod"], Short: true},
+				{Label: "🧱 컨테이너", Value: parsedLog.Fields["container"], Short: true},
+				{Label: "🖥️  노드", Value: parsedLog.Fields["node"], Short: true},
+			},
+			Summary: true,
+		})
+	}
+
+	// 로그 정보
+	if parsedLog != nil {
+		sections = append(sections, AlertSection{
+			Title: "📋 로그 정보",
+			Fields: []AlertField{
+				{Label: "📝 레벨", Value: parsedLog.Level, Short: true},
+				{Label: "🏷️  타입", Value: parsedLog.LogType, Short: true},
+				{Label: "💬 메시지", Value: parsedLog.Message},
+				{Label: "📄 원본", Value: parsedLog.RawLog, Code: true},
+			},
+			Summary: true,
+		})
+	}
+
+	// 권장사항
+	if len(aiResult.Recommendations) > 0 {
+		var recommendations string
+		for _, recommendation := range aiResult.Recommendations {
+			recommendations += fmt.Sprintf("• %s\n", recommendation)
+		}
+		sections = append(sections, AlertSection{Title: "💡 권장사항", Text: recommendations, Summary: true})
+	}
+
+	// 시스템 정보
+	sections = append(sections, AlertSection{
+		Title: "🖥️  시스템 정보",
+		Fields: []AlertField{
+			{Label: "🏠 내부 IP", Value: strings.Join(aiResult.SystemInfo.InternalIPs, ", "), Short: true},
+			{Label: "🌐 외부 IP", Value: strings.Join(aiResult.SystemInfo.ExternalIPs, ", "), Short: true},
+		},
+	})
+
+	// ASN 정보
+	if len(aiResult.SystemInfo.ASNData) > 0 {
+		var asnText string
+		for _, asn := range aiResult.SystemInfo.ASNData {
+			asnText += fmt.Sprintf("📍 %s\n", asn.IP)
+			asnText += fmt.Sprintf("  🏢 조직: %s\n", asn.Organization)
+			asnText += fmt.Sprintf("  🌍 국가: %s, %s, %s\n", asn.Country, asn.Region, asn.City)
+			asnText += fmt.Sprintf("  🔢 ASN: %s\n", asn.ASN)
+		}
+		sections = append(sections, AlertSection{Title: "🔍 ASN 정보", Text: asnText})
+	}
+
+	// 예측 결과
+	if len(aiResult.Predictions) > 0 {
+		var predictions string
+		for _, prediction := range aiResult.Predictions {
+			predictions += fmt.Sprintf("⚡ %s (확률: %.0f%%, %s)\n", 
+				prediction.Event, prediction.Probability*100, prediction.TimeFrame)
+			predictions += fmt.Sprintf("  💥 영향: %s\n", prediction.Impact)
+		}
+		sections = append(sections, AlertSection{Title: "🔮 위험 예측", Text: predictions})
+	}
+
+	// 영향받는 시스템
+	if len(aiResult.AffectedSystems) > 0 {
+		sections = append(sections, AlertSection{Title: "🎯 영향받는 시스템", Text: strings.Join(aiResult.AffectedSystems, ", ")})
+	}
+	
+	// 전문가 진단 정보
+	sections = append(sections, AlertSection{
+		Title: "👨‍💼 전문가 진단 결과",
+		Text: fmt.Sprintf(`🏥 전체 시스템 건강도: %s
+📊 성능 점수: %.1f/100
+
+🖥️  서버 전문가 진단:
+  🏥 서버 건강도: %s
+  📊 성능 점수: %.1f/100
+  🔒 보안 상태: %s
+  🌐 네트워크 건강도: %s
+  ⚠️  위험도: %s
+
+💻 컴퓨터 전문가 진단:
+  🔧 하드웨어 건강도: %s
+  💾 소프트웨어 상태: %s
+  ⚖️  시스템 안정성: %s
+  📈 리소스 사용량: %s
+  🔧 유지보수 필요: %s
+
+🚨 긴급 이슈:
+%s
+
+🔧 유지보수 팁:
+%s`,
+			aiResult.ExpertDiagnosis.OverallHealth,
+			aiResult.ExpertDiagnosis.PerformanceScore,
+			aiResult.ExpertDiagnosis.ServerExpert.ServerHealth,
+			aiResult.ExpertDiagnosis.ServerExpert.PerformanceScore,
+			aiResult.ExpertDiagnosis.ServerExpert.SecurityStatus,
+			aiResult.ExpertDiagnosis.ServerExpert.NetworkHealth,
+			aiResult.ExpertDiagnosis.ServerExpert.RiskLevel,
+			aiResult.ExpertDiagnosis.ComputerExpert.HardwareHealth,
+			aiResult.ExpertDiagnosis.ComputerExpert.SoftwareStatus,
+			aiResult.ExpertDiagnosis.ComputerExpert.SystemStability,
+			aiResult.ExpertDiagnosis.ComputerExpert.ResourceUsage,
+			formatMaintenanceNeeded(aiResult.ExpertDiagnosis.ComputerExpert.MaintenanceNeeded),
+			formatCriticalIssues(aiResult.ExpertDiagnosis.CriticalIssues),
+			formatMaintenanceTips(aiResult.ExpertDiagnosis.MaintenanceTips),
+		),
+	})
+
+	fields := map[string]string{
+		"threat_level":  aiResult.ThreatLevel,
+		"anomaly_score": fmt.Sprintf("%.1f", aiResult.AnomalyScore),
+		"confidence":    fmt.Sprintf("%.0f%%", aiResult.Confidence*100),
+	}
+	if len(aiResult.MatchedRules) > 0 {
+		fields["rule"] = aiResult.MatchedRules[0].Name
+	}
+	if aiResult.Model != "" {
+		fields["rule_score"] = fmt.Sprintf("%.1f", aiResult.RuleScore)
+		fields["model_score"] = fmt.Sprintf("%.1f", aiResult.ModelScore)
+	}
+	thread := alertThreadKey(AlertTypeAI, aiResult.SystemInfo.ComputerName)
+	if workload != "" {
+		for _, name := range []string{"namespace", "workload", "pod", "container", "node"} {
+			fields[name] = parsedLog.Fields[name]
+		}
+		// 같은 워크로드의 알림끼리 묶음 (파드가 바뀌어도 같은 스레드)
+		thread = alertThreadKey(AlertTypeAI, aiResult.SystemInfo.ComputerName, workload)
+	}
+
+	sm.logger.Infof("🚨 Sending AI alert via: %s", strings.Join(sm.alertDispatcher.SinkNames(), ", "))
+	sm.alertDispatcher.Dispatch(Alert{
+		Type:      AlertTypeAI,
+		Severity:  aiAlertSeverity(aiResult),
+		Title:     subject,
+		Headline:  fmt.Sprintf("🚨 보안 이상 탐지 알람 (%s)", aiResult.ThreatLevel),
+		Sections:  sections,
+		Host:      aiResult.SystemInfo.ComputerName,
+		Thread:    thread,
+		Fields:    fields,
+		Timestamp: aiResult.Timestamp,
+	})
+}
+
+// handleSystemAlerts 시스템 알림 처리
+func (sm *SyslogMonitor) handleSystemAlerts() {
+	for alert := range sm.systemMonitor.GetAlertChannel() {
+		sm.logger.WithFields(logrus.Fields{
+			"level": "SYSTEM_ALERT",
+			"type":  alert.Type,
+			"value": alert.Value,
+		}).Warnf("System alert: %s", alert.Message)
+
+		// 알림 채널 설정과 무관하게 히스토리에 기록
+		if sm.eventStore != nil {
+			sm.eventStore.RecordSystemAlert(alert, systemAlertSeverity(alert))
+		}
+		
+		// 설정된 모든 알림 채널로 전송
+		if sm.alertDispatcher.HasSinks() {
+			headline := fmt.Sprintf("%s 시스템 알림: %s", severityEmoji(systemAlertSeverity(alert)), alert.Type)
+			systemAlert := Alert{
+				Type:     AlertTypeSystem,
+				Severity: systemAlertSeverity(alert),
+				Title:    fmt.Sprintf("[%s SYSTEM ALERT] %s", AppName, alert.Type),
+				Headline: headline,
+				Sections: []AlertSection{{
+					Fields: []AlertField{
+						{Label: "심각도", Value: alert.Level, Short: true},
+						{Label: "메트릭", Value: alert.Type, Short: true},
+						{Label: "현재 값", Value: fmt.Sprintf("%.2f", alert.Value), Short: true},
+						{Label: "임계값", Value: fmt.Sprintf("%.2f", alert.Threshold), Short: true},
+						{Label: "메시지", Value: alert.Message},
+						{Label: "시간", Value: alert.Timestamp.Format("2006-01-02 15:04:05"), Short: true},
+					},
+					Text:    "시스템에서 임계값을 초과한 상황이 감지되었습니다.",
+					Summary: true,
+				}},
+				Fields: map[string]string{
+					"metric":    alert.Type,
+					"value":     fmt.Sprintf("%.2f", alert.Value),
+					"threshold": fmt.Sprintf("%.2f", alert.Threshold),
+				},
+				Thread:    alertThreadKey(AlertTypeSystem, alert.Metrics.IPInfo.Hostname, alert.Type),
+				Timestamp: alert.Timestamp,
+			}
+
+			sm.logger.Infof("🖥️  Sending system alert via: %s", strings.Join(sm.alertDispatcher.SinkNames(), ", "))
+			sm.alertDispatcher.Dispatch(systemAlert)
+		}
+	}
+}
+
+// sendPeriodicSystemReports 주기적 시스템 상태 보고서 전송
+func (sm *SyslogMonitor) sendPeriodicSystemReports(ctx context.Context) {
+	if sm.reportSchedule != nil {
+		for {
+			select {
+			case <-sm.reportSchedule.Timer():
+				sm.sendSystemStatusReport()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	ticker := time.NewTicker(sm.reportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sm.sendSystemStatusReport()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// SetReportSchedule 시간대 기반 정기 보고서 스케줄 설정
+// 스케줄이 지정되면 고정 간격 대신 지정된 시각에 보고서를 전송
+func (sm *SyslogMonitor) SetReportSchedule(schedule *ReportSchedule) {
+	sm.reportSchedule = schedule
+	sm.periodicReport = true
+	if sm.systemMonitor != nil {
+		sm.systemMonitor.SetReportSchedule(schedule)
+	}
+}
+
+// SetReportArchiver 정기 보고서 파일 저장 서비스 설정
+func (sm *SyslogMonitor) SetReportArchiver(archiver *ReportArchiver) {
+	sm.reportArchiver = archiver
+}
+
+// sendSystemStatusReport 시스템 상태 보고서 전송
+func (sm *SyslogMonitor) sendSystemStatusReport() {
+	if sm.systemMonitor == nil {
+		return
+	}
+
+	metrics := sm.systemMonitor.GetCurrentMetrics()
+
+	// 보고 주기 동안의 로그인 출처 위치 요약 (로그인 감지 활성화 시)
+	var loginGeo *LoginGeoSummary
+	if sm.loginGeoTracker != nil {
+		loginGeo = sm.loginGeoTracker.Summarize(sm.geoMapper)
+	}
+	body := sm.generateSystemStatusEmailBody(metrics, loginGeo)
+
+	// 보고서 파일 저장 (위키 게시/보관용)
+	if sm.reportArchiver != nil {
+		title := fmt.Sprintf("%s 시스템 상태 보고서 - %s", AppName, metrics.Timestamp.Format("2006-01-02 15:04"))
+		if err := sm.reportArchiver.Archive("system-report", title, body, metrics.Timestamp); err != nil {
+			sm.logger.Errorf("Failed to archive system report: %v", err)
+		}
+	}
+	
+	// 설정된 모든 알림 채널로 보고서 전송
+	report := Alert{
+		Type:      AlertTypeReport,
+		Severity:  AlertSeverityInfo,
+		Title:     fmt.Sprintf("[%s] 📊 시스템 상태 보고서 - %s", AppName, time.Now().Format("2006-01-02 15:04")),
+		Body:      body,
+		Timestamp: metrics.Timestamp,
+		Fields: map[string]string{
+			"cpu_usage":    fmt.Sprintf("%.1f%%", metrics.CPU.UsagePercent),
+			"memory_usage": fmt.Sprintf("%.1f%%", metrics.Memory.UsagePercent),
+			"load_avg":     fmt.Sprintf("%.2f", metrics.LoadAverage.Load1Min),
+		},
+	}
+	if loginGeo != nil {
+		report.Fields["login_attempts"] = fmt.Sprintf("%d", loginGeo.TotalLogins)
+		report.Fields["login_countries"] = loginGeo.CountryList()
+		if len(loginGeo.NewLocations) > 0 {
+			report.Fields["new_login_locations"] = strings.Join(loginGeo.NewLocations, "; ")
+		}
+		if loginGeo.MapURL != "" {
+			report.Fields["login_map_url"] = loginGeo.MapURL
+		}
+	}
+	if sm.healthChecks != nil {
+		if stats := sm.healthChecks.Stats(); stats.Suppressed > 0 {
+			report.Fields["health_checks_suppressed"] = fmt.Sprintf("%d", stats.Suppressed)
+		}
+	}
+	if sm.dataUpdater != nil {
+		var updates []string
+		for _, status := range sm.dataUpdater.Status() {
+			result := status.Result
+			if result == "" {
+				result = "pending"
+			}
+			updates = append(updates, status.Name+"="+result)
+		}
+		report.Fields["data_updates"] = strings.Join(updates, ", ")
+	}
+	if sm.slackService != nil {
+		slackMsg := sm.generateSystemStatusSlackMessage(metrics, loginGeo)
+		report.Slack = &slackMsg
+	}
+	sm.alertDispatcher.Dispatch(report)
+	
+	sm.logger.Infof("📊 시스템 상태 보고서 전송 완료 (CPU: %.1f%%, 메모리: %.1f%%)", 
+		metrics.CPU.UsagePercent, metrics.Memory.UsagePercent)
+}
+
+// generateSystemStatusEmailBody 시스템 상태 이메일 본문 생성
+// loginGeo가 nil이 아니면 로그인 출처 위치 섹션 포함
+func (sm *SyslogMonitor) generateSystemStatusEmailBody(metrics SystemMetrics, loginGeo *LoginGeoSummary) string {
+	hostname, _ := os.Hostname()
+	
+	return fmt.Sprintf(`🖥️  시스템 상태 보고서
+
+📅 보고서 시간: %s
+🖥️  호스트명: %s
+
+🌐 네트워크 정보:
+   사설 IP: %s
+   공인 IP: %s
+
+📊 CPU 상태:
+   사용률: %.1f%%
+   사용자: %.1f%%
+   시스템: %.1f%%
+   유휴: %.1f%%
+   코어 수: %d
+
+💾 메모리 상태:
+   총 메모리: %.1f MB
+   사용 중: %.1f MB (%.1f%%)
+   사용 가능: %.1f MB
+   스왑 사용: %.1f MB (%.1f%%)
+   메모리 압박: %s (%.1f%%)
+
+💿 디스크 상태:
+%s
+
+🌡️  온도 정보:
+   CPU 온도: %.1f°C
+   GPU 온도: %.1f°C
+
+📈 시스템 부하:
+   1분 평균: %.2f (코어당 %.2f)
+   5분 평균: %.2f (코어당 %.2f)
+   15분 평균: %.2f (코어당 %.2f)
+
+🔄 프로세스 상태:
+   총 프로세스: %d
+   실행 중: %d
+   대기 중: %d
+%s%s%s%s%s%s
+---
+📊 이 보고서는 %v마다 자동으로 전송됩니다.
+🤖 AI-Powered Syslog Monitor v2.1`,
+		time.Now().Format("2006-01-02 15:04:05"),
+		hostname,
+		formatIPList(metrics.IPInfo.PrivateIPs),
+		formatIPList(metrics.IPInfo.PublicIPs),
+		metrics.CPU.UsagePercent,
+		metrics.CPU.UserPercent,
+		metrics.CPU.SystemPercent,
+		metrics.CPU.IdlePercent,
+		metrics.CPU.Cores,
+		metrics.Memory.TotalMB,
+		metrics.Memory.UsedMB,
+		metrics.Memory.UsagePercent,
+		metrics.Memory.AvailableMB,
+		metrics.Memory.SwapUsedMB,
+		metrics.Memory.SwapUsagePercent,
+		metrics.Memory.PressureLevel,
+		metrics.Memory.PressurePercent,
+		sm.generateDiskStatusText(metrics.Disk),
+		metrics.Temperature.CPUTemp,
+		metrics.Temperature.GPUTemp,
+		metrics.LoadAverage.Load1Min,
+		metrics.LoadAverage.Load1MinPerCore,
+		metrics.LoadAverage.Load5Min,
+		metrics.LoadAverage.Load5MinPerCore,
+		metrics.LoadAverage.Load15Min,
+		metrics.LoadAverage.Load15MinPerCore,
+		metrics.ProcessCount.Total,
+		metrics.ProcessCount.Running,
+		metrics.ProcessCount.Sleeping,
+		sm.systemMonitor.generatePressureReport(metrics),
+		generateThroughputSection(metrics),
+		generateConnectionSection(metrics.Connections),
+		sm.generateLoginGeoSection(loginGeo),
+		sm.generateDataUpdateSection(),
+		sm.sourceUsage.Snapshot().FormatText(),
+		sm.reportInterval)
+}
+
+// generateDataUpdateSection 보고서 본문의 데이터 업데이트 섹션 (자동 업데이트 미설정 시 빈 문자열)
+func (sm *SyslogMonitor) generateDataUpdateSection() string {
+	if sm.dataUpdater == nil {
+		return ""
+	}
+	return FormatDataUpdateStatus(sm.dataUpdater.Status())
+}
+
+// generateLoginGeoSection 보고서 본문의 로그인 출처 위치 섹션 (요약이 없으면 빈 문자열)
+func (sm *SyslogMonitor) generateLoginGeoSection(loginGeo *LoginGeoSummary) string {
+	if loginGeo == nil {
+		return ""
+	}
+	return "\n" + loginGeo.FormatText()
+}
+
+// generateDiskStatusText 디스크 상태 텍스트 생성
+func (sm *SyslogMonitor) generateDiskStatusText(disks []DiskMetrics) string {
+	if len(disks) == 0 {
+		return "   정보 없음"
+	}
+	
+	var result strings.Builder
+	for _, disk := range disks {
+		result.WriteString(fmt.Sprintf("   %s (%s): %.1f GB / %.1f GB (%.1f%%)\n",
+			disk.Device, disk.MountPoint, disk.UsedGB, disk.TotalGB, disk.UsagePercent))
+	}
+	return result.String()
+}
+
+// formatIPList IP 목록을 문자열로 포맷팅
+func formatIPList(ips []string) string {
+	if len(ips) == 0 {
+		return "없음"
+	}
+	return strings.Join(ips, ", ")
+}
+
+// formatMaintenanceNeeded 유지보수 필요성 포맷팅
+func formatMaintenanceNeeded(needed bool) string {
+	if needed {
+		return "예"
+	}
+	return "아니오"
+}
+
+// formatCriticalIssues 긴급 이슈 포맷팅
+func formatCriticalIssues(issues []string) string {
+	if len(issues) == 0 {
+		return "없음"
+	}
+	var result strings.Builder
+	for _, issue := range issues {
+		result.WriteString(fmt.Sprintf("  • %s\n", issue))
+	}
+	return result.String()
+}
+
+// formatMaintenanceTips 유지보수 팁 포맷팅
+func formatMaintenanceTips(tips []string) string {
+	if len(tips) == 0 {
+		return "없음"
+	}
+	var result strings.Builder
+	for _, tip := range tips {
+		result.WriteString(fmt.Sprintf("  • %s\n", tip))
+	}
+	return result.String()
+}
+
+// generateSystemStatusSlackMessage 시스템 상태 Slack 메시지 생성
+// loginGeo가 nil이 아니면 로그인 출처 위치 첨부 블록 추가
+func (sm *SyslogMonitor) generateSystemStatusSlackMessage(metrics SystemMetrics, loginGeo *LoginGeoSummary) SlackMessage {
+	hostname, _ := os.Hostname()
+	
+	// 상태에 따른 색상 결정
+	color := "good"
+	if metrics.CPU.UsagePercent > 80 || metrics.Memory.UsagePercent > 85 {
+		color = "warning"
+	}
+	if metrics.CPU.UsagePercent > 90 || metrics.Memory.UsagePercent > 95 {
+		color = "danger"
+	}
+	
+	message := SlackMessage{
+		Text:      fmt.Sprintf("📊 시스템 상태 보고서 - %s", hostname),
+		IconEmoji: ":bar_chart:",
+		Attachments: []SlackAttachment{
+			{
+				Color: color,
+				Title: "🖥️  시스템 리소스 상태",
+				Fields: []SlackField{
+					{Title: "CPU 사용률", Value: fmt.Sprintf("%.1f%%", metrics.CPU.UsagePercent), Short: true},
+					{Title: "메모리 사용률", Value: fmt.Sprintf("%.1f%%", metrics.Memory.UsagePercent), Short: true},
+					{Title: "디스크 사용률", Value: sm.getDiskUsageSummary(metrics.Disk), Short: true},
+					{Title: "시스템 부하", Value: fmt.Sprintf("%.2f (코어당 %.2f)", metrics.LoadAverage.Load5Min, metrics.LoadAverage.Load5MinPerCore), Short: true},
+					{Title: "온도", Value: fmt.Sprintf("CPU: %.1f°C", metrics.Temperature.CPUTemp), Short: true},
+					{Title: "프로세스", Value: fmt.Sprintf("%d 실행 중", metrics.ProcessCount.Running), Short: true},
+				},
+				Timestamp: metrics.Timestamp.Unix(),
+			},
+		},
+	}
+
+	if loginGeo != nil && loginGeo.TotalLogins > 0 {
+		newLocations := "없음"
+		if !loginGeo.HasBaseline {
+			newLocations = "첫 보고 주기"
+		} else if len(loginGeo.NewLocations) > 0 {
+			newLocations = strings.Join(loginGeo.NewLocations, "; ")
+		}
+
+		fields := []SlackField{
+			{Title: "로그인 시도", Value: fmt.Sprintf("%d회 (IP %d개)", loginGeo.TotalLogins, loginGeo.UniqueIPs), Short: true},
+			{Title: "새로운 위치", Value: newLocations, Short: true},
+			{Title: "국가별", Value: loginGeo.CountryList(), Short: false},
+			{Title: "도시별", Value: formatGeoCounts(loginGeo.Cities, LoginGeoTopCities), Short: false},
+		}
+		if loginGeo.MapURL != "" {
+			fields = append(fields, SlackField{Title: "지도", Value: fmt.Sprintf("<%s|지도 스냅샷 보기>", loginGeo.MapURL), Short: false})
+		}
+
+		color := "good"
+		if len(loginGeo.NewLocations) > 0 {
+			color = "warning"
+		}
+		message.Attachments = append(message.Attachments, SlackAttachment{
+			Color:  color,
+			Title:  "🌍 로그인 출처 위치",
+			Fields: fields,
+		})
+	}
+
+	if sm.dataUpdater != nil {
+		color := "good"
+		var fields []SlackField
+		for _, status := range sm.dataUpdater.Status() {
+			if status.Result == DataUpdateFailed || status.Result == DataUpdateRolledBack {
+				color = "warning"
+			}
+			fields = append(fields, SlackField{Title: status.Name, Value: describeDataUpdate(status), Short: false})
+		}
+		message.Attachments = append(message.Attachments, SlackAttachment{
+			Color:  color,
+			Title:  "📦 데이터 업데이트",
+			Fields: fields,
+		})
+	}
+
+	return message
+}
+
+// getDiskUsageSummary 디스크 사용률 요약 생성
+func (sm *SyslogMonitor) getDiskUsageSummary(disks []DiskMetrics) string {
+	if len(disks) == 0 {
+		return "N/A"
+	}
+	
+	// 가장 사용률이 높은 디스크 반환
+	maxUsage := 0.0
+	for _, disk := range disks {
+		if disk.UsagePercent > maxUsage {
+			maxUsage = disk.UsagePercent
+		}
+	}
+	return fmt.Sprintf("%.1f%%", maxUsage)
+}
+
+// getDefaultLogFile 운영체제에 따른 기본 로그 파일 경로 반환
+func getDefaultLogFile() string {
+	switch runtime.GOOS {
+	case "darwin": // macOS
+		// macOS에서 일반적으로 접근 가능한 로그 파일들을 순서대로 확인
+		macOSLogFiles := []string{
+			"/var/log/system.log",    // macOS 주요 시스템 로그
+			"/var/log/install.log",   // 설치 로그
+			"/var/log/wifi.log",      // WiFi 로그
+			"/usr/local/var/log/messages", // Homebrew 환경
+		}
+		
+		for _, logFile := range macOSLogFiles {
+			if _, err := os.Stat(logFile); err == nil {
+				return logFile
+			}
+		}
+		
+		// 기본값으로 system.log 반환 (존재하지 않아도)
+		return "/var/log/system.log"
+		
+	case "linux":
+		return "/var/log/syslog"
+		
+	default:
+		return "/var/log/syslog"
+	}
+}
+
+// getMacOSLogRecommendations macOS 사용자를 위한 로그 파일 추천
+func getMacOSLogRecommendations() []string {
+	return []string{
+		"/var/log/system.log     # 주요 시스템 로그 (macOS Monterey 이전)",
+		"/var/log/install.log    # 패키지 설치 로그",
+		"/var/log/wifi.log       # WiFi 연결 로그",
+		"/var/log/kernel.log     # 커널 로그",
+		"/var/log/fsck_hfs.log   # 파일시스템 체크 로그",
+		"",
+		"💡 macOS Big Sur/Monterey 이후:",
+		"   sudo log show --predicate 'process == \"kernel\"' --last 1h",
+		"   sudo log show --predicate 'eventMessage contains \"error\"' --last 1h",
+		"   sudo log stream --predicate 'process == \"syslogd\"'",
+	}
+}
+
+func main() {
+	// 이벤트 히스토리 조회 하위 명령 (syslog-monitor history [options])
+	if len(os.Args) > 1 && os.Args[1] == "history" {
+		runHistoryCommand(os.Args[2:])
+		return
+	}
+
+	// 자격 증명 관리 하위 명령 (syslog-monitor secrets set smtp-password)
+	if len(os.Args) > 1 && os.Args[1] == "secrets" {
+		runSecretsCommand(os.Args[2:])
+		return
+	}
+
+	// abuse 연락처 조회 하위 명령 (syslog-monitor whois 203.0.113.45)
+	if len(os.Args) > 1 && os.Args[1] == "whois" {
+		runWhoisCommand(os.Args[2:])
+		return
+	}
+
+	// 컨테이너 진입점 (syslog-monitor container [options], 설정 /config 읽기 전용, 상태 /data, 옵션은 SYSLOG_* 환경 변수로도 지정)
+	if len(os.Args) > 1 && os.Args[1] == ContainerCommand {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+		enterContainerMode()
+	}
+
+	// 설정 서비스 초기화 (-config 가 SYSLOG_CONFIG_PATH 보다 우선)
+	configPath := os.Getenv("SYSLOG_CONFIG_PATH")
+	if configPath == "" {
+		configPath = "~/.syslog-monitor/config.json"
+	}
+	explicitConfig, hasConfigFlag := configPathFromArgs(os.Args[1:])
+	if hasConfigFlag {
+		configPath = expandHomePath(explicitConfig)
+		// 지정한 설정 파일이 없으면 기본 설정으로 조용히 시작하지 않음 (오타난 경로로 서비스가 아무것도 감시하지 않는 것 방지)
+		if _, err := os.Stat(configPath); err != nil {
+			fmt.Printf("❌ 설정 파일을 열 수 없습니다 (-config): %v\n", err)
+			os.Exit(1)
+		}
+	}
+	
+	configService = NewConfigService(configPath)
+	if containerMode {
+		configService.SetReadOnly()
+	}
+	if err := configService.LoadConfig(); err != nil {
+		fmt.Printf("❌ 설정 파일 로드 실패: %v\n", err)
+		if hasConfigFlag {
+			os.Exit(1)
+		}
+		fmt.Println("💡 기본 설정으로 시작합니다.")
+	}
+	
+	// LLM 백엔드 초기화 (ai_analysis.provider, 기본 Gemini)
+	llmConfig := configService.GetLLMConfig()
+	provider, err := NewLLMProvider(llmConfig)
+	if err != nil {
+		fmt.Printf("❌ AI 백엔드 설정 오류, Gemini 사용: %v\n", err)
+		llmConfig.Provider = LLMProviderGemini
+		provider, _ = NewLLMProvider(llmConfig)
+	}
+	if provider.Name() != LLMProviderGemini {
+		fmt.Printf("🧠 AI 백엔드: %s (모델: %s)\n", provider.Name(), provider.Model())
+	}
+	if promptConfig := configService.GetConfig().AI.Prompts; promptConfig.Dir != "" {
+		if prompts, err := LoadPromptSet(promptConfig); err != nil {
+			fmt.Printf("❌ AI 프롬프트 템플릿 로드 실패, 내장 프롬프트 사용: %v\n", err)
+		} else {
+			provider.SetPrompts(prompts)
+			fmt.Printf("📝 AI 프롬프트: %s\n", prompts.Summary())
+		}
+	}
+	setLLMProvider(provider)
+	// LLM 호출 캐시/한도 (ai_analysis.scheduler)
+	if err := llmScheduler.SetConfig(configService.GetConfig().AI.Scheduler); err != nil {
+		fmt.Printf("❌ AI 호출 한도 설정 오류, 제한 없이 사용: %v\n", err)
+	}
+	
+	defaultLogFile := getDefaultLogFile()
+	
 	var (
 		logFile       = flag.String("file", defaultLogFile, "Path to syslog file")
 		outputFile    = flag.String("output", "", "Output file for filtered logs (default: stdout)")
+		outputFormat  = flag.String("output-format", OutputFormatText, "Output format for filtered logs: text, json (single array), ndjson (one object per line)")
+		outputMaxSize = flag.Int("output-max-size", DefaultOutputMaxSizeMB, "Rotate the -output file when it exceeds this many MB (0 = never rotate; json format is never rotated)")
+		outputKeep    = flag.Int("output-keep", DefaultOutputKeep, "Rotated -output files kept as <file>.1 ... <file>.N")
+		outputMode    = flag.String("output-mode", DefaultOutputMode, "Octal permissions of the -output file (existing files with wider permissions are restricted)")
+		outputWatch   = flag.Bool("output-self-watch", false, "Register the -output file as an internal source: the monitor's own errors written to it (notification failures, output errors) raise monitor_error alerts")
 		filterList    = flag.String("filters", "", "Comma-separated list of regex filters to exclude")
 		keywordList   = flag.String("keywords", "", "Comma-separated list of keywords to include")
 		showHelp      = flag.Bool("help", false, "Show help message")
@@ -1310,427 +3791,1739 @@ func main() {
 		smtpPort      = flag.String("smtp-port", "", "SMTP server port")
 		smtpUser      = flag.String("smtp-user", "", "SMTP username")
 		smtpPassword  = flag.String("smtp-password", "", "SMTP password")
+		dkimSelector  = flag.String("dkim-selector", "", "DKIM selector for signing outgoing email (requires -dkim-key)")
+		dkimKeyFile   = flag.String("dkim-key", "", "PEM private key file (RSA or Ed25519) for DKIM signing")
+		dkimDomain    = flag.String("dkim-domain", "", "DKIM signing domain (default: domain of -email-from)")
 		testEmail     = flag.Bool("test-email", false, "Send test email and exit")
 		slackWebhook  = flag.String("slack-webhook", "", "Slack webhook URL for notifications")
 		slackChannel  = flag.String("slack-channel", "", "Slack channel (default: webhook default)")
 		slackUsername = flag.String("slack-username", "Syslog Monitor", "Slack bot username")
 		testSlack     = flag.Bool("test-slack", false, "Send test Slack message and exit")
+		webhookURL    = flag.String("webhook-url", "", "Generic webhook URL that receives every alert as JSON")
+		telegramToken = flag.String("telegram-token", "", "Telegram bot token for notifications")
+		telegramChat  = flag.String("telegram-chat-id", "", "Telegram chat ID to send alerts to")
+		testTelegram  = flag.Bool("test-telegram", false, "Send test Telegram message and exit")
+		pagerDutyKey  = flag.String("pagerduty-routing-key", "", "PagerDuty Events API v2 routing key for paging on critical AI/system alerts")
 		loginWatch    = flag.Bool("login-watch", false, "Enable login monitoring (SSH, sudo, web)")
+		dbWatch       = flag.Bool("db-watch", false, "Detect MySQL/PostgreSQL privilege changes (GRANT/REVOKE/CREATE USER/ALTER ROLE) and superuser authentication failures")
+		cicdWatch     = flag.Bool("cicd-watch", false, "Detect repeated Jenkins/GitLab/GitHub Actions job failures, runner registration from unexpected IPs and secrets printed in build output (settings in config cicd)")
+		sqliConfirm   = flag.Bool("sqli-confirm", false, "Confirm web SQL injection matches against database syntax errors and anomalous query fingerprints (requires -ai-analysis)")
+		sqliDBLog     = flag.String("sqli-db-log", "", "Comma-separated MySQL/PostgreSQL log files read only as SQL injection evidence (used with -sqli-confirm)")
+		sqliWindow    = flag.Int("sqli-window", DefaultSQLInjectionWindow, "Seconds within which a web SQL injection match and database evidence are correlated")
+		workers       = flag.Int("workers", DefaultPipelineWorkers(), "Parse/analysis worker goroutines each (ingest → parse → analysis → alert pipeline with bounded queues; 0 processes lines serially in the tail loop)")
+		healthChecks  = flag.Bool("suppress-health-checks", true, "Drop successful load balancer health check requests (paths like /healthz, user agents like ELB-HealthChecker/kube-probe) before AI analysis and statistics")
+		exfilWatch    = flag.Bool("exfil-watch", false, "Detect response-size anomalies in web access logs (a client downloading large volumes from one endpoint, responses far above the endpoint's usual size)")
+		exfilVolume   = flag.Int("exfil-volume", DefaultExfilVolumeMB, "MB a single client may download from one endpoint within -exfil-window before a critical exfiltration alert")
+		exfilWindow   = flag.Int("exfil-window", DefaultExfilWindow, "Minutes over which per-client download volume is summed (used with -exfil-watch)")
+		accessStats   = flag.Bool("access-stats", false, "Privacy mode for web access logs: keep only aggregated statistics (request counts, status mix, latencies, attack pattern counts) and never store, forward or alert with raw request lines")
+		accessWindow  = flag.Int("access-stats-window", DefaultAccessStatsWindow, "Minutes per access statistics window (used with -access-stats)")
+		accessErrors  = flag.Float64("access-error-rate", DefaultAccessErrorRate, "Alert when this percent of a window's web requests return 5xx (at least 100 requests, critical at twice the rate; used with -access-stats)")
+		accessAttacks = flag.Int("access-attack-threshold", DefaultAccessAttackThreshold, "Alert when this many web requests in a window match attack patterns (SQL injection, XSS, path traversal, sensitive files, scanners; used with -access-stats)")
+		unknownFormat = flag.Float64("unknown-format-alert", 0, "Alert when this percent of a source's lines (input file or Kubernetes workload) match no parser within a 10-minute window after it parsed cleanly before, a sign the log format changed (0 = off)")
+		traceSample   = flag.Float64("trace-sample", 0, "Fraction of events (0-1, e.g. 0.01 = 1 in 100) whose parse/AI/geo/notify stage latencies are recorded as Prometheus histograms on /metrics (0 = off)")
+		slowEvent     = flag.Duration("slow-event", DefaultSlowEventBudget, "Log sampled events that take longer than this end to end, with the per-stage breakdown (used with -trace-sample, 0 = don't log)")
+		sloFlag       = flag.String("slo", "", "Comma-separated endpoint SLOs as [METHOD ]PATH=TARGET% (e.g. \"/api/checkout=99.9,POST /api/orders=99.5\"); overrides \"slos\" in the config file")
+		modSecLog     = flag.String("modsec-audit-log", "", "ModSecurity audit log (native or JSON) to raise HIGH/CRITICAL web attack alerts, correlated with the access log given by -file")
 		aiEnabled     = flag.Bool("ai-analysis", false, "Enable AI-based log analysis and anomaly detection")
 		systemEnabled = flag.Bool("system-monitor", false, "Enable system metrics monitoring (CPU, memory, disk, temperature)")
-		_ = flag.String("log-type", "auto", "Log type for parsing (auto, apache, nginx, mysql, postgresql, application)") // Reserved for future use
+		_ = flag.String("log-type", "auto", "Log type for parsing (auto, apache, nginx, mysql, postgresql, modsecurity, auditd, application)") // Reserved for future use
 		
 		// 새로운 알림 관련 플래그
 		alertIntervalFlag   = flag.Int("alert-interval", 10, "Login alert interval in minutes (default: 10)")
 		periodicReportFlag  = flag.Bool("periodic-report", false, "Enable periodic system status reports")
 		reportIntervalFlag  = flag.Int("report-interval", 60, "Report interval in minutes (default: 60)")
+		replayFlag          = flag.String("replay", "", "Comma-separated files or globs to process once in chronological order instead of following -file (gzip/bzip2/zstd and tar archives are decompressed)")
+		replayWorkersFlag   = flag.Int("replay-workers", DefaultPipelineWorkers(), "Inputs read and decompressed ahead in parallel during -replay (processing order stays chronological)")
+		replayDryRunFlag    = flag.Bool("replay-dry-run", false, "During -replay, record alerts in the summary report instead of sending them")
+		replayReportFlag    = flag.String("replay-report", "", "Write the -replay summary report (alerts by type/severity, per-input counts) to this JSON file")
+		replaySARIFFlag     = flag.String("replay-sarif", "", "Write security detections found during -replay to this file as SARIF 2.1.0 findings")
+		ocsfOutputFlag      = flag.String("ocsf-output", "", "Append security detections as OCSF 1.1.0 events (one JSON object per line) to this file (- for stdout)")
+		journaldFlag        = flag.Bool("journald", false, "Read logs from systemd-journald (journalctl) instead of a file")
+		journaldUnitsFlag   = flag.String("journald-units", "", "Comma-separated systemd units to follow in journald mode (default: all)")
+		multilineFlag       = flag.Bool("multiline", false, "Join multi-line entries (stack traces, slow query blocks) before parsing and AI analysis (file input only)")
+		multilineStartFlag  = flag.String("multiline-start", "", "Regex matching the first line of an entry; non-matching lines continue the previous entry (implies -multiline)")
+		multilineRulesFlag  = flag.String("multiline-continue", DefaultMultilineRules, "Comma-separated continuation rules: indent (indented/\"Caused by:\" lines), hash (\"# \" header blocks such as MySQL slow query log)")
+		eventLogFlag        = flag.Bool("eventlog", false, "Read logs from the Windows Event Log (wevtutil) instead of a file")
+		eventLogChannelsFlag = flag.String("eventlog-channels", DefaultEventLogChannels, "Comma-separated Windows Event Log channels to follow in eventlog mode (Security requires Administrator)")
+		kubeNamespaceFlag   = flag.String("kube-namespace", "", "Follow logs of Kubernetes pods in this namespace instead of a file (\"*\" for all namespaces)")
+		kubeSelectorFlag    = flag.String("kube-selector", "", "Label selector for -kube-namespace pods (e.g. app=web,tier!=cache); alone, uses the service account namespace")
+		kubeAPIFlag         = flag.String("kube-api", "", "Kubernetes API server URL (default: in-cluster service account; e.g. http://127.0.0.1:8001 with kubectl proxy)")
+		kubeTokenFileFlag   = flag.String("kube-token-file", "", "Bearer token file for -kube-api (re-read on every request)")
+		kubeCAFlag          = flag.String("kube-ca", "", "CA certificate file used to verify -kube-api")
+		listenFlag          = flag.String("listen", "", "Receive syslog over the network instead of following a file (comma-separated udp://[host]:port, tcp://[host]:port; default port 514)")
+		listenAllowFlag     = flag.String("listen-allow", "", "Comma-separated sender CIDRs/IPs accepted by -listen (default: all)")
+		reportDirFlag       = flag.String("report-dir", "", "Directory to archive periodic reports as Markdown/HTML files")
+		reportScheduleFlag  = flag.String("report-schedule", "", "Timezone-aware report schedule (e.g. \"08:00 Asia/Seoul daily\", \"Mon 09:00 weekly\")")
+		digestScheduleFlag  = flag.String("digest-schedule", "", "LLM daily digest schedule, report schedule or cron format (e.g. \"08:00 Asia/Seoul daily\", \"0 8 * * 1-5\")")
+		canaryFlag          = flag.Bool("canary", false, "Send a low-severity canary through every alert channel once a day and alert if delivery fails (IMAP mailbox check with canary.imap)")
+		canaryScheduleFlag  = flag.String("canary-schedule", "", "Notification canary schedule, report schedule or cron format (default: canary.schedule or \"09:00 daily\")")
+		trustedNetworksFlag = flag.String("trusted-networks", "", "Comma-separated trusted CIDRs that skip geo lookup and get lower alert priority (e.g. \"office=203.0.113.0/24,10.8.0.0/16\")")
+		geoIPDBFlag         = flag.String("geoip-db", "", "Comma-separated MaxMind GeoLite2/GeoIP2 .mmdb files (e.g. GeoLite2-City.mmdb,GeoLite2-ASN.mmdb) used for IP geolocation instead of ip-api.com")
+		reverseDNSFlag      = flag.Bool("reverse-dns", false, "Resolve login and web alert source IPs to PTR hostnames (forward-confirmed, cached)")
+		reverseDNSTimeout   = flag.Int("reverse-dns-timeout", DefaultReverseDNSTimeout, "Milliseconds allowed for each reverse DNS lookup (failures and timeouts are cached as no hostname)")
+		whoisAbuseFlag      = flag.Bool("whois-abuse", false, "Look up the RDAP/WHOIS abuse contact of brute-force source IPs and include it in the alert (cached 24h)")
+		whoisTimeout        = flag.Int("whois-timeout", DefaultWhoisTimeout, "Milliseconds allowed for each abuse contact lookup")
+		esURLFlag           = flag.String("es-url", "", "Elasticsearch/OpenSearch URL to bulk-index parsed logs and AI results (e.g. http://localhost:9200)")
+		esIndexPrefixFlag   = flag.String("es-index-prefix", DefaultESIndexPrefix, "Index prefix for Elasticsearch output (daily indices: <prefix>-logs-YYYY.MM.DD, <prefix>-ai-YYYY.MM.DD)")
+		kafkaBrokersFlag    = flag.String("kafka-brokers", "", "Comma-separated Kafka bootstrap brokers to publish parsed logs as JSON (e.g. kafka1:9092,kafka2:9092)")
+		kafkaTopicFlag      = flag.String("kafka-topic", DefaultKafkaTopic, "Kafka topic for published messages")
+		kafkaPartitionFlag  = flag.String("kafka-partition-by", KafkaPartitionHost, "Kafka partitioning: host (message key = host, per-host ordering) or round-robin")
+		kafkaAlertsOnlyFlag = flag.Bool("kafka-alerts-only", false, "Publish only alerts to Kafka instead of every parsed log")
+		outputBufferFlag    = flag.String("output-buffer", "", "Directory where Elasticsearch/Kafka output is buffered on disk while the destination is unreachable (resent on recovery)")
+		outputBufferSizeFlag = flag.Int("output-buffer-size", DefaultOutputBufferSizeMB, "Maximum disk buffer size per output in MB (new events are dropped when full, alert at 80%)")
+		forwardFlag         = flag.String("forward", "", "Forward filtered logs to an upstream syslog server in RFC 5424 with AI score and parsed fields as structured data (udp://, tcp:// or tls://host[:port])")
+		forwardCAFlag       = flag.String("forward-ca", "", "PEM CA certificate file to verify a tls:// forward target (default: system CAs)")
+		sampleExportFlag    = flag.String("sample-export", "", "On exit, write a stratified sample of parsed events (balanced across level × AI pattern, secrets redacted) to this JSONL file for model training")
+		samplePerStratumFlag = flag.Int("sample-per-stratum", DefaultSamplePerStratum, "Maximum events kept per level/pattern stratum for -sample-export")
+		sampleSeedFlag      = flag.Int64("sample-seed", DefaultSampleSeed, "Random seed for -sample-export (same input and seed give the same sample)")
+		dbPathFlag          = flag.String("db-path", "", "SQLite file to store login events, system alerts and AI results (e.g. ~/.syslog-monitor/events.db; query with 'history')")
+		dbRetentionFlag     = flag.Int("db-retention-days", 0, "Delete -db-path events older than this many days (checked hourly; 0 keeps everything)")
+		configWatchFlag     = flag.Bool("config-watch", true, "Reload the config file when it changes (SIGHUP always triggers a reload)")
+		apiPortFlag         = flag.Int("api-port", 0, "Port for the embedded management REST API (e.g. 8080; 0 disables)")
+		apiBindFlag         = flag.String("api-bind", DefaultAPIBind, "Address the management API listens on")
+		apiTokenFlag        = flag.String("api-token", "", "Bearer token required by the management API (env: SYSLOG_API_TOKEN)")
+		apiTLSCertFlag      = flag.String("api-tls-cert", "", "PEM certificate file to serve the management API over HTTPS (with -api-tls-key)")
+		apiTLSKeyFlag       = flag.String("api-tls-key", "", "PEM private key file for -api-tls-cert")
+		dashboardFlag       = flag.Bool("dashboard", false, "Serve the web dashboard on the management API port (uses port 8080 if -api-port is not set)")
+		blockActionFlag     = flag.String("block-action", "", "Block brute-force source IPs with a firewall: auto, iptables, nftables, pf, ipfw or custom (requires -login-watch and root)")
+		blockDurationFlag   = flag.Int("block-duration", int(DefaultBlockDuration/time.Minute), "Minutes before a blocked IP is automatically unblocked (0 keeps the block)")
+		blockAllowlistFlag  = flag.String("block-allowlist", "", "Comma-separated CIDRs/IPs that are never blocked (trusted networks and loopback are always exempt)")
+		rulesFlag           = flag.String("rules", "", "YAML/JSON file with custom anomaly detection rules (name, regex, severity, category, action); reloaded with the config")
+		onnxModelFlag       = flag.String("onnx-model", "", "ONNX classification model scoring each line alongside the anomaly rules (requires -ai-analysis)")
+		onnxWeightFlag      = flag.Float64("onnx-weight", DefaultModelWeight, "Weight of the -onnx-model score in the combined anomaly score (0-1; rules get 1 - weight)")
+		onnxFeaturizeFlag   = flag.String("onnx-featurize", "", "Convert a -sample-export JSONL file to model feature vectors (CSV on stdout) and exit")
+		onnxFeaturesFlag    = flag.Int("onnx-features", DefaultModelFeatures, "Feature vector size for -onnx-featurize (must match the model input size)")
+		promptExportFlag    = flag.String("prompt-export", "", "Write the built-in Gemini prompt templates to this directory (existing files are kept) and exit")
+		tenantsFlag         = flag.String("tenants", "", "JSON file defining tenants (per-tenant sources, stores, alert channels and read-only API tokens) for multi-tenant mode")
+		haLockFlag          = flag.String("ha-lock", "", "Leader election lock for a warm standby pair (file:/path, etcd://host:2379/key or consul://host:8500/key); only the leader sends notifications")
+		haIDFlag            = flag.String("ha-id", "", "Instance ID shown as the lock holder (default: hostname-pid)")
+		haTTLFlag           = flag.Duration("ha-ttl", DefaultHATTL, "Leader lock TTL; a standby takes over at most this long after the leader stops renewing")
+		deliveryJournalFlag = flag.String("delivery-journal", "", "Write-ahead journal file for notifications; unconfirmed sends are retried and resent after a crash or restart")
+		_                   = flag.String(ConfigFlagName, "", "Config file path (default: SYSLOG_CONFIG_PATH or ~/.syslog-monitor/config.json); options not given on the command line are read from its \"options\" object, so -config alone can run the service")
+		
+		// Gemini API 관련 플래그
+		geminiAPIKey = flag.String("gemini-api-key", "", "Gemini API key for advanced AI analysis")
+		showConfig   = flag.Bool("show-config", false, "Show current configuration")
+		
+		// 백그라운드 서비스 관련 플래그
+		daemonMode     = flag.Bool("daemon", false, "Run as background daemon service")
+		installService = flag.Bool("install-service", false, "Install as a background service (macOS LaunchAgent, Linux systemd unit, Windows service; other flags become the service options)")
+		removeService  = flag.Bool("remove-service", false, "Remove the background service (macOS LaunchAgent, Linux systemd unit, Windows service)")
+		startService   = flag.Bool("start-service", false, "Start the installed service")
+		stopService    = flag.Bool("stop-service", false, "Stop the running service")
+		statusService  = flag.Bool("status-service", false, "Show service status")
+	)
+	flag.Parse()
+
+	// 컨테이너 모드: 명령줄에 없는 옵션은 SYSLOG_<OPTION> 환경 변수에서 읽고, 서비스 관리 명령은 거부
+	if containerMode {
+		if _, err := applyFlagEnvironment(flag.CommandLine); err != nil {
+			fmt.Printf("❌ 환경 변수 옵션 오류: %v\n", err)
+			os.Exit(1)
+		}
+		if *daemonMode || *installService || *removeService || *startService || *stopService || *statusService {
+			fmt.Println("❌ 컨테이너 모드에서는 -daemon 과 서비스 관리 옵션을 사용할 수 없습니다 (컨테이너 런타임이 프로세스를 관리합니다)")
+			os.Exit(1)
+		}
+	}
+
+	// 설정 파일 "options": 명령줄(컨테이너 모드는 환경 변수 포함)에서 지정하지 않은 옵션을 설정 파일 값으로 (-config 만으로 실행)
+	// 서비스 설치는 명령줄 인수만 유닛에 넣으므로 options 는 설치하는 프로세스에 적용하지 않음
+	if !*installService {
+		applied, err := applyConfigOptions(flag.CommandLine, configService.GetConfig().Options)
+		if err != nil {
+			fmt.Printf("❌ 설정 파일 options 오류 (%s): %v\n", configService.GetConfigPath(), err)
+			os.Exit(1)
+		}
+		if len(applied) > 0 {
+			fmt.Printf("⚙️  설정 파일 options 적용: %s\n", strings.Join(applied, ", "))
+		}
+	}
+
+	// 구조화 출력(json/ndjson)을 stdout 으로 내보낼 때는 안내 메시지가 레코드와 섞이지 않도록 stderr 로 출력
+	outputFormatValue, err := ParseOutputFormat(*outputFormat)
+	if err != nil {
+		fmt.Printf("❌ 출력 형식 오류: %v\n", err)
+		os.Exit(1)
+	}
+	recordStdout := os.Stdout
+	if outputFormatValue != OutputFormatText && *outputFile == "" {
+		os.Stdout = os.Stderr
+	}
+
+	// -output 파일 교체/권한 (모니터 생성 전에 설정)
+	outputFileMode, err := ParseOutputFileMode(*outputMode)
+	if err != nil {
+		fmt.Printf("❌ 출력 파일 권한 오류: %v\n", err)
+		os.Exit(1)
+	}
+	if *outputMaxSize < 0 || *outputKeep < 0 {
+		fmt.Println("❌ -output-max-size 와 -output-keep 은 0 이상이어야 합니다.")
+		os.Exit(1)
+	}
+	outputFileOptions = OutputFileOptions{MaxSizeMB: *outputMaxSize, Keep: *outputKeep, Mode: outputFileMode}
+
+	// 이메일 설정 읽기 (플래그 → 환경변수 → 설정 파일, 비밀번호는 OS 키체인까지 조회; 내장 기본 계정 없음)
+	emailFileConfig := configService.GetConfig().Email
+	if !emailFileConfig.Enabled {
+		emailFileConfig.To, emailFileConfig.From, emailFileConfig.Username, emailFileConfig.Password = nil, "", "", ""
+	}
+	if *emailTo == "" {
+		*emailTo = os.Getenv("SYSLOG_EMAIL_TO")
+		if *emailTo == "" {
+			*emailTo = strings.Join(emailFileConfig.To, ",")
+		}
+	}
+	if *emailFrom == "" {
+		*emailFrom = os.Getenv("SYSLOG_EMAIL_FROM")
+		if *emailFrom == "" {
+			*emailFrom = emailFileConfig.From
+		}
+	}
+	if *smtpServer == "" {
+		*smtpServer = os.Getenv("SYSLOG_SMTP_SERVER")
+		if *smtpServer == "" {
+			*smtpServer = DefaultSMTPServer
+			if emailFileConfig.SMTPServer != "" {
+				*smtpServer = emailFileConfig.SMTPServer
+			}
+		}
+	}
+	if *smtpPort == "" {
+		*smtpPort = os.Getenv("SYSLOG_SMTP_PORT")
+		if *smtpPort == "" {
+			*smtpPort = DefaultSMTPPort
+			if emailFileConfig.SMTPPort > 0 {
+				*smtpPort = strconv.Itoa(emailFileConfig.SMTPPort)
+			}
+		}
+	}
+	if *smtpUser == "" {
+		*smtpUser = os.Getenv("SYSLOG_SMTP_USER")
+		if *smtpUser == "" {
+			*smtpUser = emailFileConfig.Username
+		}
+	}
+	if *emailFrom == "" {
+		*emailFrom = *smtpUser
+	}
+	if *emailTo != "" {
+		var passwordSource string
+		*smtpPassword, passwordSource = ResolveSecret(SecretSMTPPassword, *smtpPassword, emailFileConfig.Password)
+		if passwordSource == "keychain" {
+			fmt.Printf("🔑 SMTP password loaded from the OS keychain\n")
+		}
+	}
+	if err := CheckRevokedSMTPPassword(*smtpPassword); err != nil {
+		fmt.Printf("❌ SMTP 자격 증명 오류: %v\n", err)
+		os.Exit(1)
+	}
+	if *dkimSelector == "" {
+		*dkimSelector = os.Getenv("SYSLOG_DKIM_SELECTOR")
+	}
+	if *dkimKeyFile == "" {
+		*dkimKeyFile = os.Getenv("SYSLOG_DKIM_KEY")
+	}
+	if *dkimDomain == "" {
+		*dkimDomain = os.Getenv("SYSLOG_DKIM_DOMAIN")
+	}
+	if *slackWebhook == "" {
+		*slackWebhook = os.Getenv("SYSLOG_SLACK_WEBHOOK")
+	}
+	if *slackChannel == "" {
+		*slackChannel = os.Getenv("SYSLOG_SLACK_CHANNEL")
+	}
+	if *slackUsername == "Syslog Monitor" {
+		if env := os.Getenv("SYSLOG_SLACK_USERNAME"); env != "" {
+			*slackUsername = env
+		}
+	}
+
+	// Gemini API 키 설정
+	if *geminiAPIKey != "" {
+		if err := configService.SetGeminiAPIKey(*geminiAPIKey); err != nil {
+			fmt.Printf("❌ Gemini API 키 설정 실패: %v\n", err)
+		} else {
+			fmt.Printf("✅ Gemini API 키가 설정되었습니다: %s\n", configService.getMaskedAPIKey())
+		}
+	}
+
+	// 설정 정보 표시
+	if *showConfig {
+		configService.ShowConfigInfo()
+		return
+	}
+	
+	// 서비스 관리 명령어 처리 (Linux 는 systemd, Windows 는 서비스 관리자, macOS 는 LaunchAgent)
+	if *installService {
+		switch runtime.GOOS {
+		case "linux":
+			installSystemdService()
+		case "windows":
+			installWindowsService()
+		default:
+			installLaunchAgent()
+		}
+		return
+	}
+	
+	if *removeService {
+		switch runtime.GOOS {
+		case "linux":
+			removeSystemdService()
+		case "windows":
+			removeWindowsService()
+		default:
+			removeLaunchAgent()
+		}
+		return
+	}
+	
+	if *startService {
+		switch runtime.GOOS {
+		case "linux":
+			startSystemdService()
+		case "windows":
+			startWindowsService()
+		default:
+			startLaunchAgent()
+		}
+		return
+	}
+	
+	if *stopService {
+		switch runtime.GOOS {
+		case "linux":
+			stopSystemdService()
+		case "windows":
+			stopWindowsService()
+		default:
+			stopLaunchAgent()
+		}
+		return
+	}
+	
+	if *statusService {
+		switch runtime.GOOS {
+		case "linux":
+			showSystemdServiceStatus()
+		case "windows":
+			showWindowsServiceStatus()
+		default:
+			showServiceStatus()
+		}
+		return
+	}
+
+	// 학습용 표본을 ONNX 모델 특성 벡터 CSV 로 변환
+	if *onnxFeaturizeFlag != "" {
+		rows, err := WriteModelFeatures(*onnxFeaturizeFlag, *onnxFeaturesFlag, os.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ 특성 변환 오류: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "🧠 %d개 표본을 특성 %d개 벡터로 변환했습니다\n", rows, *onnxFeaturesFlag)
+		return
+	}
+
+	// 내장 Gemini 프롬프트 템플릿 내보내기 (ai_analysis.prompts.dir 에서 수정해 사용)
+	if *promptExportFlag != "" {
+		written, err := ExportBuiltinPrompts(*promptExportFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ 프롬프트 내보내기 오류: %v\n", err)
+			os.Exit(1)
+		}
+		for _, path := range written {
+			fmt.Printf("📝 %s\n", path)
+		}
+		fmt.Printf("📝 %d개 프롬프트 템플릿을 내보냈습니다 (이미 있는 파일은 유지)\n", len(written))
+		return
+	}
+	
+	// Daemon 모드 설정 (종료 시 PID 파일 삭제, 로그 파일 닫기)
+	daemonCleanup := func() {}
+	if *daemonMode {
+		daemonCleanup = setupDaemonMode()
+	}
+
+	// Windows 서비스 관리자가 실행한 경우 콘솔이 없으므로 출력을 로그 파일로 리다이렉션
+	if isWindowsService() {
+		setupWindowsServiceLogging()
+	}
+
+	if *showHelp {
+		fmt.Println("Syslog Monitor - Real-time syslog monitoring service")
+		fmt.Println()
+		fmt.Println("Usage:")
+		fmt.Println("  syslog-monitor [options]")
+		fmt.Println("  syslog-monitor history [history options]   (run 'syslog-monitor history -h')")
+		fmt.Println()
+		fmt.Println("Options:")
+		flag.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  # Monitor default syslog with all messages")
+		fmt.Println("  ./syslog-monitor")
+		fmt.Println()
+		fmt.Println("  # Monitor specific file with keyword filtering")
+		fmt.Println("  ./syslog-monitor -file=/var/log/auth.log -keywords=failed,error")
+		fmt.Println()
+		fmt.Println("  # Monitor with output to file and filtering")
+		fmt.Println("  ./syslog-monitor -output=monitor.log -filters=systemd,kernel")
+		fmt.Println()
+		fmt.Println("  # Output file rotated at 50 MB (10 kept), readable by the adm group, with alerts for the monitor's own errors")
+		fmt.Println("  ./syslog-monitor -output=/var/log/syslog-monitor.log -output-max-size=50 -output-keep=10 -output-mode=0640 -output-self-watch")
+		fmt.Println()
+		fmt.Println("  # Machine-readable output (one JSON object per line) for downstream tools")
+		fmt.Println("  ./syslog-monitor -output-format=ndjson -ai-analysis -login-watch | jq 'select(.level == \"ERROR\")'")
+		fmt.Println()
+		fmt.Println("  # Monitor with default email alerts (multiple recipients)")
+		fmt.Println("  ./syslog-monitor")
+		fmt.Println()
+		fmt.Println("  # Monitor with custom multiple recipients")
+		fmt.Println("  ./syslog-monitor -email-to=\"admin@company.com,security@company.com,ops@company.com\"")
+		fmt.Println()
+		fmt.Println("  # Monitor with email alerts (using command line)")
+		fmt.Println("  ./syslog-monitor -email-to=admin@example.com -smtp-user=your@gmail.com -smtp-password=yourapppassword")
+		fmt.Println()
+		fmt.Println("  # Monitor with email alerts (using environment variables)")
+		fmt.Println("  export SYSLOG_EMAIL_TO=\"admin@company.com,security@company.com\"")
+		fmt.Println("  export SYSLOG_SMTP_USER=your@gmail.com")
+		fmt.Println("  export SYSLOG_SMTP_PASSWORD=yourapppassword")
+		fmt.Println("  ./syslog-monitor")
+		fmt.Println()
+		if runtime.GOOS == "darwin" {
+			fmt.Println("  # macOS specific examples")
+			fmt.Println("  ./syslog-monitor -file=/var/log/system.log -ai-analysis")
+			fmt.Println("  ./syslog-monitor -file=/var/log/install.log -keywords=error")
+			fmt.Println("  ./syslog-monitor -file=/var/log/wifi.log -system-monitor")
+			fmt.Println("  sudo log stream | ./syslog-monitor -file=/dev/stdin -ai-analysis")
+		}
+		fmt.Println()
+		fmt.Println("  # Join multi-line entries (Java stack traces, MySQL slow query blocks)")
+		fmt.Println("  ./syslog-monitor -file=/var/log/app/app.log -ai-analysis -multiline-start='^\\d{4}-\\d{2}-\\d{2}'")
+		fmt.Println("  ./syslog-monitor -file=/var/log/mysql/slow.log -ai-analysis -multiline -multiline-continue=hash")
+		fmt.Println()
+		if runtime.GOOS == "linux" {
+			fmt.Println("  # Read from systemd-journald (distros without /var/log/syslog)")
+			fmt.Println("  ./syslog-monitor -journald -login-watch")
+			fmt.Println("  ./syslog-monitor -journald -journald-units=sshd,nginx -ai-analysis")
+			fmt.Println()
+			fmt.Println("  # Follow Kubernetes pod logs (in-cluster, or via kubectl proxy)")
+			fmt.Println("  ./syslog-monitor -kube-namespace=shop -kube-selector=app=web -ai-analysis")
+			fmt.Println("  ./syslog-monitor -kube-namespace='*' -kube-api=http://127.0.0.1:8001 -ai-analysis")
+		}
+		if runtime.GOOS == "windows" {
+			fmt.Println("  # Read from the Windows Event Log (run as Administrator for Security)")
+			fmt.Println("  syslog-monitor.exe -eventlog -system-monitor")
+			fmt.Println("  syslog-monitor.exe -eventlog -eventlog-channels=System,Application -ai-analysis")
+		}
+		fmt.Println()
+		fmt.Println("  # Test email configuration (multiple recipients)")
+		fmt.Println("  ./syslog-monitor -test-email -email-to=\"user1@test.com,user2@test.com\"")
+		fmt.Println()
+		fmt.Println("  # Slack integration with login monitoring")
+		fmt.Println("  ./syslog-monitor -slack-webhook=https://hooks.slack.com/... -login-watch")
+		fmt.Println()
+		fmt.Println("  # Combined email + Slack alerts")
+		fmt.Println("  ./syslog-monitor -slack-webhook=https://hooks.slack.com/... -slack-channel=#alerts")
+		fmt.Println()
+		fmt.Println("  # Test Slack integration")
+		fmt.Println("  ./syslog-monitor -test-slack -slack-webhook=https://hooks.slack.com/...")
+		fmt.Println()
+		fmt.Println("  # Telegram bot alerts")
+		fmt.Println("  ./syslog-monitor -login-watch -telegram-token=123456:ABC... -telegram-chat-id=-1001234567890")
+		fmt.Println("  ./syslog-monitor -test-telegram -telegram-token=123456:ABC... -telegram-chat-id=-1001234567890")
+		fmt.Println("  ./syslog-monitor -ai-analysis -system-monitor -pagerduty-routing-key=R0UT1NGKEY...")
+		fmt.Println()
+		fmt.Println("  # Offline geolocation for login alerts (air-gapped hosts, no ip-api.com requests)")
+		fmt.Println("  ./syslog-monitor -login-watch -geoip-db=/usr/share/GeoIP/GeoLite2-City.mmdb,/usr/share/GeoIP/GeoLite2-ASN.mmdb")
+		fmt.Println()
+		fmt.Println("  # Keep alert/event history and query it later")
+		fmt.Println("  ./syslog-monitor -login-watch -system-monitor -db-path=~/.syslog-monitor/events.db")
+		fmt.Println("  ./syslog-monitor history -since=24h -user=root")
+		fmt.Println("  ./syslog-monitor history -kind=system -severity=critical -json")
+		fmt.Println()
+		fmt.Println("  # Ship parsed logs and AI results to Elasticsearch/OpenSearch (Kibana)")
+		fmt.Println("  ./syslog-monitor -ai-analysis -es-url=http://localhost:9200 -es-index-prefix=syslog-monitor")
+		fmt.Println()
+		fmt.Println("  # Publish parsed logs (or only alerts) to Kafka, partitioned by host")
+		fmt.Println("  ./syslog-monitor -kafka-brokers=kafka1:9092,kafka2:9092 -kafka-topic=syslog-monitor")
+		fmt.Println("  ./syslog-monitor -login-watch -kafka-brokers=kafka1:9092 -kafka-topic=security-alerts -kafka-alerts-only")
+		fmt.Println()
+		fmt.Println("  # Write security detections as OCSF events for a security data lake, and backfill findings as SARIF")
+		fmt.Println("  ./syslog-monitor -login-watch -ai-analysis -ocsf-output=/var/log/syslog-monitor/ocsf.ndjson")
+		fmt.Println("  ./syslog-monitor -replay='/var/log/auth.log*' -login-watch -replay-dry-run -replay-sarif=findings.sarif")
+		fmt.Println()
+		fmt.Println("  # Buffer Elasticsearch/Kafka output on disk (up to 1GB each) while the destination is down")
+		fmt.Println("  ./syslog-monitor -es-url=http://localhost:9200 -kafka-brokers=kafka1:9092 -output-buffer=~/.syslog-monitor/buffer -output-buffer-size=1024")
+		fmt.Println()
+		fmt.Println("  # Relay enriched logs (AI score, parsed fields) to a central syslog server over TLS")
+		fmt.Println("  ./syslog-monitor -ai-analysis -forward=tls://logs.example.com:6514")
+		fmt.Println()
+		fmt.Println("  # AI-powered log analysis with system monitoring")
+		fmt.Println("  ./syslog-monitor -ai-analysis -system-monitor")
+		fmt.Println()
+		fmt.Println("  # Monitor web server logs with AI analysis")
+		fmt.Println("  ./syslog-monitor -file=/var/log/nginx/access.log -log-type=nginx -ai-analysis")
+		fmt.Println()
+		fmt.Println("  # Database log monitoring with anomaly detection")
+		fmt.Println("  ./syslog-monitor -file=/var/log/mysql/error.log -log-type=mysql -ai-analysis")
+		fmt.Println()
+		fmt.Println("  # Block SSH brute-force sources for 2 hours (iptables on Linux, pf on macOS)")
+		fmt.Println("  sudo ./syslog-monitor -login-watch -block-action=auto -block-duration=120 -block-allowlist=10.0.0.0/8")
+		fmt.Println()
+		fmt.Println("  # Management REST API for external automation")
+		fmt.Println("  ./syslog-monitor -system-monitor -api-port=8080 -api-token=SECRET")
+		fmt.Println("  curl -H 'Authorization: Bearer SECRET' localhost:8080/status")
+		fmt.Println("  curl -X POST -H 'Authorization: Bearer SECRET' -d '{\"cpu_percent\": 90}' localhost:8080/thresholds")
+		fmt.Println()
+		fmt.Println("  # HTTPS management API for snooze/ack links in alert emails (config: alerts.actions.base_url)")
+		fmt.Println("  SYSLOG_ALERT_ACTION_KEY=$(openssl rand -hex 32) ./syslog-monitor -api-port=8443 -api-bind=0.0.0.0 -api-token=SECRET -api-tls-cert=cert.pem -api-tls-key=key.pem")
+		fmt.Println()
+		fmt.Println("  # Web dashboard (live logs, metrics, logins map, AI anomaly scores)")
+		fmt.Println("  ./syslog-monitor -ai-analysis -system-monitor -login-watch -dashboard -api-token=SECRET")
+		fmt.Println("  open 'http://localhost:8080/dashboard/#token=SECRET'")
+		fmt.Println()
+		fmt.Println("  # Multi-tenant aggregator for managed-service providers (tenants read their own data only)")
+		fmt.Println("  ./syslog-monitor -login-watch -ai-analysis -tenants=tenants.json -api-port=8080 -api-token=ADMIN -dashboard")
+		fmt.Println("  curl -H 'Authorization: Bearer ACME_TOKEN' localhost:8080/alerts/recent")
+		fmt.Println("  curl -H 'Authorization: Bearer ADMIN' 'localhost:8080/status?tenant=acme'")
+		fmt.Println()
+		fmt.Println("  # Warm standby pair: both ingest, only the lock holder sends notifications")
+		fmt.Println("  ./syslog-monitor -ai-analysis -login-watch -ha-lock=etcd://etcd.internal:2379/syslog-monitor/leader")
+		fmt.Println("  ./syslog-monitor -ai-analysis -login-watch -ha-lock=file:/var/run/syslog-monitor.lock")
+		fmt.Println()
+		fmt.Println("  # At-least-once notifications: journal sends and resend unconfirmed ones after a crash")
+		fmt.Println("  ./syslog-monitor -ai-analysis -system-monitor -delivery-journal=~/.syslog-monitor/delivery.jsonl")
+		fmt.Println()
+		fmt.Println("  # Database privilege changes and superuser authentication failures")
+		fmt.Println("  ./syslog-monitor -file=/var/log/postgresql/postgresql.log -db-watch")
+		fmt.Println()
+		fmt.Println("  # CI/CD: repeated job failures, unexpected runner registration, secrets in build output")
+		fmt.Println("  ./syslog-monitor -file=/var/log/jenkins/jenkins.log -cicd-watch")
+		fmt.Println()
+		fmt.Println("  # Confirm web SQL injection matches with database syntax errors / anomalous query fingerprints")
+		fmt.Println("  ./syslog-monitor -file=/var/log/nginx/access.log -ai-analysis -sqli-confirm -sqli-db-log=/var/log/mysql/general.log")
+		fmt.Println()
+		fmt.Println("  # ModSecurity WAF alerts correlated with the access log")
+		fmt.Println("  ./syslog-monitor -file=/var/log/apache2/access.log -modsec-audit-log=/var/log/apache2/modsec_audit.log")
+		fmt.Println()
+		fmt.Println("  # Data exfiltration: alert when one client downloads 500 MB from an endpoint within 15 minutes")
+		fmt.Println("  ./syslog-monitor -file=/var/log/nginx/access.log -exfil-watch -exfil-volume=500 -exfil-window=15")
+		fmt.Println()
+		fmt.Println("  # Privacy-sensitive sites: keep only access log statistics, alert on 5xx rate above 2% and attack patterns")
+		fmt.Println("  ./syslog-monitor -file=/var/log/nginx/access.log -access-stats -access-error-rate=2")
+		fmt.Println()
+		fmt.Println("  # Log format changes: alert when over 30% of access log lines stop matching a parser")
+		fmt.Println("  ./syslog-monitor -file=/var/log/nginx/access.log -unknown-format-alert=30")
+		fmt.Println()
+		fmt.Println("  # Pipeline latency: trace 1% of events, log those slower than 500ms with a per-stage breakdown")
+		fmt.Println("  ./syslog-monitor -file=/var/log/nginx/access.log -ai-analysis -login-watch -trace-sample=0.01 -slow-event=500ms -api-port=8080")
+		fmt.Println()
+		fmt.Println("  # Endpoint SLOs: page when /api/checkout burns its 99.9% error budget too fast")
+		fmt.Println("  ./syslog-monitor -file=/var/log/nginx/access.log -slo=\"POST /api/checkout=99.9,/api/search/*=99\"")
+		fmt.Println()
+		fmt.Println("  # High-volume access logs: 8 parse/analysis workers (0 = serial processing)")
+		fmt.Println("  ./syslog-monitor -file=/var/log/nginx/access.log -ai-analysis -workers=8")
+		fmt.Println()
+		fmt.Println("  # Keep load balancer health check requests in the analysis (suppressed by default)")
+		fmt.Println("  ./syslog-monitor -file=/var/log/nginx/access.log -ai-analysis -suppress-health-checks=false")
+		fmt.Println()
+		fmt.Println("  # Custom anomaly rules (YAML/JSON; edit the file and it is reloaded automatically)")
+		fmt.Println("  ./syslog-monitor -ai-analysis -rules=rules.yaml")
+		fmt.Println()
+		fmt.Println("  # Customise Gemini prompts (then set ai_analysis.prompts.dir in the config file)")
+		fmt.Println("  ./syslog-monitor -prompt-export=/etc/syslog-monitor/prompts")
+		fmt.Println()
+		fmt.Println("  # Apply config file changes without restarting")
+		fmt.Println("  kill -HUP $(pgrep syslog-monitor)")
+		fmt.Println()
+		if runtime.GOOS == "windows" {
+			fmt.Println("  # Run as a Windows service (Administrator command prompt)")
+			fmt.Println("  syslog-monitor.exe -install-service -eventlog -system-monitor -ai-analysis")
+			fmt.Println("  syslog-monitor.exe -start-service && syslog-monitor.exe -status-service")
+		} else {
+			fmt.Println("  # Run as a background service (systemd on Linux, LaunchAgent on macOS)")
+			fmt.Println("  sudo ./syslog-monitor -install-service -ai-analysis -system-monitor -login-watch")
+			fmt.Println("  sudo ./syslog-monitor -start-service && ./syslog-monitor -status-service")
+		}
+		fmt.Println()
+		fmt.Println("  # Run everything from the config file (sources, channels, thresholds and features in \"options\")")
+		fmt.Println("  ./syslog-monitor -config=/etc/syslog-monitor/config.json")
+		fmt.Println("  sudo ./syslog-monitor -install-service -config=/etc/syslog-monitor/config.json")
+		fmt.Println()
+		fmt.Println("  # Complete monitoring setup")
+		fmt.Println("  ./syslog-monitor -ai-analysis -system-monitor -login-watch -slack-webhook=URL")
+		fmt.Println()
+		fmt.Println("Environment Variables:")
+		fmt.Println("  SYSLOG_EMAIL_TO        - Email addresses to send alerts (comma-separated)")
+		fmt.Println("  SYSLOG_EMAIL_FROM      - Email sender address")
+		fmt.Println("  SYSLOG_SMTP_SERVER     - SMTP server (default: smtp.gmail.com)")
+		fmt.Println("  SYSLOG_SMTP_PORT       - SMTP port (default: 587)")
+		fmt.Println("  SYSLOG_SMTP_USER       - SMTP username")
+		fmt.Println("  SYSLOG_SMTP_PASSWORD   - SMTP password")
+		fmt.Println("  SYSLOG_DKIM_SELECTOR   - DKIM selector for signing outgoing email")
+		fmt.Println("  SYSLOG_DKIM_KEY        - DKIM PEM private key file")
+		fmt.Println("  SYSLOG_DKIM_DOMAIN     - DKIM signing domain (default: sender domain)")
+		fmt.Println("  SYSLOG_SLACK_WEBHOOK   - Slack webhook URL")
+		fmt.Println("  SYSLOG_SLACK_CHANNEL   - Slack channel")
+		fmt.Println("  SYSLOG_SLACK_USERNAME  - Slack bot username")
+		fmt.Println("  SYSLOG_API_TOKEN       - Bearer token for the management API")
+		fmt.Println()
+		fmt.Println("Gmail Setup:")
+		fmt.Println("  1. Enable 2-Step Verification in your Google Account")
+		fmt.Println("  2. Generate App Password at: https://myaccount.google.com/apppasswords")
+		fmt.Println("  3. Use the App Password instead of your regular password")
+		fmt.Println()
+		fmt.Println("DKIM Setup (direct SMTP delivery):")
+		fmt.Println("  1. openssl genrsa -out dkim.pem 2048")
+		fmt.Println("  2. Publish <selector>._domainkey.<domain> TXT \"v=DKIM1; k=rsa; p=<base64 public key>\"")
+		fmt.Println("     (public key: openssl rsa -in dkim.pem -pubout -outform der | base64 -w0)")
+		fmt.Println("  3. Run with -dkim-selector=<selector> -dkim-key=dkim.pem")
+		fmt.Println()
+		fmt.Println("Slack Setup:")
+		fmt.Println("  1. Create Slack App: https://api.slack.com/apps")
+		fmt.Println("  2. Enable Incoming Webhooks")
+		fmt.Println("  3. Copy webhook URL and use with -slack-webhook")
+		fmt.Println()
+		fmt.Println("Telegram Setup:")
+		fmt.Println("  1. Create a bot with @BotFather and copy the token")
+		fmt.Println("  2. Add the bot to your chat and send it a message")
+		fmt.Println("  3. Get the chat ID from https://api.telegram.org/bot<TOKEN>/getUpdates")
+		fmt.Println("  4. Test with -test-telegram -telegram-token=TOKEN -telegram-chat-id=ID")
+		fmt.Println()
+		fmt.Println("PagerDuty Setup:")
+		fmt.Println("  1. Add an \"Events API V2\" integration to your PagerDuty service")
+		fmt.Println("  2. Copy the Integration Key and use with -pagerduty-routing-key")
+		fmt.Println("  3. Only CRITICAL AI/system alerts page; incidents auto-resolve 10 minutes after the condition clears")
+		return
+	}
+
+	// 필터와 키워드 파싱
+	filters := parseCommaList(*filterList)
+	keywords := parseCommaList(*keywordList)
+	if _, err := CompileLogFilter(filters); err != nil {
+		fmt.Printf("❌ 필터 설정 오류: %v\n", err)
+		os.Exit(1)
+	}
+
+	// 로그인 모니터링이 활성화된 경우 관련 키워드 자동 추가
+	if *loginWatch {
+		keywords = appendLoginKeywords(keywords)
+		fmt.Printf("🔍 Added login keywords: %s\n", strings.Join(LoginKeywords, ", "))
+	}
+
+	// DB 감지는 키워드 필터를 쓰는 경우에만 관련 키워드 추가 (키워드가 없으면 모든 줄 검사)
+	if *dbWatch && len(keywords) > 0 {
+		keywords = appendKeywords(keywords, DBWatchKeywords)
+		fmt.Printf("🔍 Added database keywords: %s\n", strings.Join(DBWatchKeywords, ", "))
+	}
+	if *cicdWatch && len(keywords) > 0 {
+		keywords = appendKeywords(keywords, CICDWatchKeywords)
+		fmt.Printf("🔍 Added CI/CD keywords: %s\n", strings.Join(CICDWatchKeywords, ", "))
+	}
+
+	// 이메일 설정 (수신자가 설정된 경우에만 활성화)
+	emailConfig := &EmailConfig{
+		SMTPServer:   *smtpServer,
+		SMTPPort:     *smtpPort,
+		Username:     *smtpUser,
+		Password:     *smtpPassword,
+		From:         *emailFrom,
+		Enabled:      *emailTo != "",
+		DKIMDomain:   *dkimDomain,
+		DKIMSelector: *dkimSelector,
+		DKIMKeyFile:  *dkimKeyFile,
+	}
+
+	// 이메일 주소 파싱
+	emailConfig.To = parseCommaList(*emailTo)
+
+	// XOAUTH2 인증 (설정 파일 email.oauth2 에 refresh token 이 있으면 앱 비밀번호 대신 사용)
+	if oauth2 := configService.GetConfig().Email.OAuth2; oauth2.Enabled() {
+		if err := oauth2.Validate(); err != nil {
+			fmt.Printf("❌ 이메일 OAuth2 설정 오류: %v\n", err)
+			os.Exit(1)
+		}
+		if oauth2.ClientSecret == "" {
+			oauth2.ClientSecret, _ = ResolveSecret(SecretSMTPOAuth2ClientSecret, "", "")
+		}
+		emailConfig.OAuth2 = &oauth2
+		if oauth2.Provider == OAuth2ProviderMicrosoft && emailConfig.SMTPServer == DefaultSMTPServer {
+			emailConfig.SMTPServer = MicrosoftSMTPServer
+		}
+	}
+
+	// 사용자 알림
+	if emailConfig.Enabled {
+		fmt.Printf("📧 Email alerts enabled\n")
+		fmt.Printf("    📨 Recipients (%d): %s\n", len(emailConfig.To), strings.Join(emailConfig.To, ", "))
+
+		if emailConfig.OAuth2 != nil {
+			fmt.Printf("    🔐 SMTP XOAUTH2 authentication (%s, mailbox %s via %s)\n", emailConfig.OAuth2.Provider, emailConfig.Username, emailConfig.SMTPServer)
+		} else if *smtpUser == "" || *smtpPassword == "" {
+			fmt.Println("⚠️  Warning: SMTP username or password not provided. Email alerts may not work.")
+			fmt.Println("    For Gmail, generate an App Password at: https://myaccount.google.com/apppasswords")
+			fmt.Println("    Store it with: syslog-monitor secrets set smtp-password")
+		}
+	} else {
+		fmt.Printf("📧 Email alerts disabled. Use -email-to (or SYSLOG_EMAIL_TO / email.to in the config file) to enable.\n")
+	}
+
+	// 슬랙 설정
+	slackConfig := &SlackConfig{
+		WebhookURL: *slackWebhook,
+		Channel:    *slackChannel,
+		Username:   *slackUsername,
+		Enabled:    *slackWebhook != "",
+	}
+
+	if slackConfig.Enabled {
+		fmt.Printf("💬 Slack alerts enabled\n")
+		fmt.Printf("    📡 Webhook: %s\n", slackConfig.WebhookURL[:50]+"...")
+		if slackConfig.Channel != "" {
+			fmt.Printf("    📺 Channel: %s\n", slackConfig.Channel)
+		}
+		fmt.Printf("    🤖 Bot Name: %s\n", slackConfig.Username)
+	} else {
+		fmt.Printf("💬 Slack alerts disabled. Use -slack-webhook to enable.\n")
+	}
+
+	// 텔레그램 설정
+	telegramConfig := &TelegramConfig{
+		BotToken: *telegramToken,
+		ChatID:   *telegramChat,
+		Enabled:  *telegramToken != "" && *telegramChat != "",
+	}
+
+	if telegramConfig.Enabled {
+		fmt.Printf("✈️  Telegram alerts enabled\n")
+		fmt.Printf("    💬 Chat ID: %s\n", telegramConfig.ChatID)
+	} else if *telegramToken != "" || *telegramChat != "" {
+		fmt.Printf("⚠️  Telegram alerts disabled: both -telegram-token and -telegram-chat-id are required\n")
+	}
+
+	if *loginWatch {
+		fmt.Printf("👁️  Login monitoring enabled (SSH, sudo, web login detection)\n")
+	}
+	if *dbWatch {
+		fmt.Printf("🛡️  Database privilege monitoring enabled (GRANT/REVOKE/CREATE USER/ALTER ROLE, superuser auth failures)\n")
+	}
+	if *cicdWatch {
+		fmt.Printf("🏗️  CI/CD monitoring enabled (repeated job failures, unexpected runner registration, secrets in build output)\n")
+	}
+	// 파일 이름에 "slow" 가 들어간 MySQL 슬로우 쿼리 로그는 -multiline 없이도 # 헤더 블록을 한 엔트리로 조립
+	kubeInput := *kubeNamespaceFlag != "" || *kubeSelectorFlag != ""
+	slowQueryLog := !isFlagSet("multiline") && *multilineStartFlag == "" && !*journaldFlag && !*eventLogFlag && !kubeInput && isSlowQueryLogPath(*logFile)
+	if slowQueryLog {
+		fmt.Printf("📚 Slow query log detected: joining \"# \" header blocks and queries into one entry (-multiline=false to disable)\n")
+	}
+	if *multilineFlag || *multilineStartFlag != "" {
+		if *multilineStartFlag != "" {
+			fmt.Printf("📚 Multi-line entries enabled (start: %s, continuation: %s)\n", *multilineStartFlag, *multilineRulesFlag)
+		} else {
+			fmt.Printf("📚 Multi-line entries enabled (continuation: %s)\n", *multilineRulesFlag)
+		}
+	}
+	if *modSecLog != "" {
+		fmt.Printf("🛡️  ModSecurity audit log: %s (web attack alerts correlated with %s)\n", *modSecLog, *logFile)
+	}
+	if *geoIPDBFlag != "" {
+		fmt.Printf("🗺️  GeoIP database: %s (ip-api.com lookups disabled)\n", *geoIPDBFlag)
+	}
+	if *reverseDNSFlag {
+		fmt.Printf("🔁 Reverse DNS enrichment enabled (timeout %d ms)\n", *reverseDNSTimeout)
+	}
+	if *whoisAbuseFlag {
+		fmt.Printf("📮 Abuse contact lookup enabled for brute-force alerts (RDAP, timeout %d ms)\n", *whoisTimeout)
+	}
+	if *accessStats {
+		fmt.Printf("🔒 Access log statistics mode: raw web requests are aggregated only (%d min windows, 5xx alert at %g%%, %d attack matches)\n", *accessWindow, *accessErrors, *accessAttacks)
+	}
+	if *exfilWatch {
+		fmt.Printf("📦 Response size anomaly detection enabled (%d MB per client/endpoint within %d min, per-endpoint size outliers)\n", *exfilVolume, *exfilWindow)
+	}
+	if *unknownFormat > 0 {
+		fmt.Printf("🧩 Unknown log format alert enabled (%g%% of a source's lines unparsed within %v)\n", *unknownFormat, parserStatsWindow)
+	}
+	if *traceSample > 0 {
+		fmt.Printf("⏱️  Event latency tracing enabled (%g%% of events, slow event budget %v)\n", *traceSample*100, *slowEvent)
+	}
+	if *sloFlag != "" {
+		fmt.Printf("🎯 Endpoint SLOs: %s (fast/slow burn-rate alerts)\n", *sloFlag)
+	}
+	if *sqliConfirm && *aiEnabled {
+		fmt.Printf("🧪 SQL injection confirmation enabled (web SQL_Injection_Attempt matches vs. database evidence within %ds)\n", *sqliWindow)
+	}
+	
+	// AI 분석 상태 메시지
+	if *aiEnabled {
+		fmt.Printf("🤖 AI log analysis enabled\n")
+		fmt.Printf("    🔍 Anomaly detection and prediction\n")
+		fmt.Printf("    📊 Pattern recognition and threat assessment\n")
+		fmt.Printf("    🎯 Supported log types: apache, nginx, mysql, postgresql, application\n")
+	} else {
+		fmt.Printf("🤖 AI analysis disabled. Use -ai-analysis to enable.\n")
+	}
+	
+	// 시스템 모니터링 상태 메시지
+	if *systemEnabled {
+		fmt.Printf("🖥️  System monitoring enabled\n")
+		fmt.Printf("    📈 CPU, memory, disk, temperature monitoring\n")
+		fmt.Printf("    ⚠️  Real-time alerts for system thresholds\n")
+		fmt.Printf("    🔄 5-minute monitoring interval\n")
+	} else {
+		fmt.Printf("🖥️  System monitoring disabled. Use -system-monitor to enable.\n")
+	}
+
+	// 테스트 슬랙 전송
+	if *testSlack {
+		if !slackConfig.Enabled {
+			fmt.Println("Error: Slack webhook URL required for test")
+			fmt.Println("Please provide -slack-webhook")
+			os.Exit(1)
+		}
+
+		fmt.Println("Sending test Slack message...")
 		
-		// Gemini API 관련 플래그
-		geminiAPIKey = flag.String("gemini-api-key", "", "Gemini API key for advanced AI analysis")
-		showConfig   = flag.Bool("show-config", false, "Show current configuration")
+		monitor := NewSyslogMonitor(*logFile, *outputFile, filters, keywords, emailConfig, slackConfig, *aiEnabled, *systemEnabled, *loginWatch, *alertIntervalFlag, *reportIntervalFlag, *periodicReportFlag)
 		
-		// 백그라운드 서비스 관련 플래그
-		daemonMode     = flag.Bool("daemon", false, "Run as background daemon service")
-		installService = flag.Bool("install-service", false, "Install as macOS LaunchAgent service")
-		removeService  = flag.Bool("remove-service", false, "Remove macOS LaunchAgent service")
-		startService   = flag.Bool("start-service", false, "Start the installed service")
-		stopService    = flag.Bool("stop-service", false, "Stop the running service")
-		statusService  = flag.Bool("status-service", false, "Show service status")
-	)
-	flag.Parse()
+		testMsg := SlackMessage{
+			Text:      "🧪 *Test Message from Syslog Monitor*",
+			IconEmoji: ":test_tube:",
+			Username:  slackConfig.Username,
+			Attachments: []SlackAttachment{
+				{
+					Color: "good",
+					Title: "Syslog Monitor Test",
+					Fields: []SlackField{
+						{Title: "Status", Value: "✅ Working", Short: true},
+						{Title: "Time", Value: time.Now().Format("2006-01-02 15:04:05"), Short: true},
+						{Title: "Features", Value: "Email alerts, Login monitoring, Error detection", Short: false},
+					},
+					Timestamp: time.Now().Unix(),
+				},
+			},
+		}
+
+		if err := monitor.slackService.SendMessage(testMsg); err != nil {
+			fmt.Printf("Test Slack message failed: %v\n", err)
+			fmt.Println("\nTroubleshooting:")
+			fmt.Println("1. Check your Slack webhook URL")
+			fmt.Println("2. Verify webhook permissions")
+			fmt.Println("3. Test webhook manually")
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Test Slack message sent successfully!\n")
+		return
+	}
+
+	// 테스트 텔레그램 전송
+	if *testTelegram {
+		if !telegramConfig.Enabled {
+			fmt.Println("Error: Telegram bot token and chat ID required for test")
+			fmt.Println("Please provide -telegram-token and -telegram-chat-id")
+			os.Exit(1)
+		}
+
+		fmt.Println("Sending test Telegram message...")
+
+		telegramService := NewTelegramService(telegramConfig, logrus.New())
+		if err := telegramService.SendTestMessage(); err != nil {
+			fmt.Printf("Test Telegram message failed: %v\n", err)
+			fmt.Println("\nTroubleshooting:")
+			fmt.Println("1. Check your bot token (from @BotFather)")
+			fmt.Println("2. Make sure the bot has been added to the chat and received /start")
+			fmt.Println("3. Verify the chat ID (group IDs start with -100)")
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Test Telegram message sent successfully!\n")
+		return
+	}
+
+	// 테스트 이메일 전송
+	if *testEmail {
+		if !emailConfig.Enabled {
+			fmt.Println("Error: Email configuration required for test email")
+			fmt.Println("Please provide -email-to and SMTP credentials")
+			os.Exit(1)
+		}
 
-	// 환경변수에서 이메일 설정 읽기
-	if *emailTo == "" {
-		*emailTo = os.Getenv("SYSLOG_EMAIL_TO")
-		if *emailTo == "" {
-			// 기본 설정: 여러 명에게 자동 전송
-			*emailTo = "robot@lambda-x.ai,enfn2001@gmail.com"
+		fmt.Println("Sending test email...")
+		
+		monitor := NewSyslogMonitor(*logFile, *outputFile, filters, keywords, emailConfig, slackConfig, *aiEnabled, *systemEnabled, *loginWatch, *alertIntervalFlag, *reportIntervalFlag, *periodicReportFlag)
+		subject := "[TEST] Syslog Monitor Email Test"
+		body := fmt.Sprintf(`이것은 syslog 모니터의 테스트 이메일입니다.
+
+테스트 시간: %s
+SMTP 서버: %s:%s
+발신자: %s
+수신자: %s
+
+이 이메일을 받으셨다면 이메일 설정이 올바르게 구성되었습니다.
+
+Syslog Monitor
+`, time.Now().Format("2006-01-02 15:04:05"), *smtpServer, *smtpPort, *emailFrom, strings.Join(emailConfig.To, ", "))
+
+		if err := monitor.emailService.SendEmail(subject, body); err != nil {
+			fmt.Printf("Test email failed: %v\n", err)
+			fmt.Println("\nTroubleshooting:")
+			fmt.Println("1. Check your Gmail App Password")
+			fmt.Println("2. Ensure 2-Step Verification is enabled")
+			fmt.Println("3. Verify SMTP server and port settings")
+			os.Exit(1)
 		}
+
+		fmt.Printf("✅ Test email sent successfully to %d recipients: %s\n", len(emailConfig.To), strings.Join(emailConfig.To, ", "))
+		return
 	}
-	if *emailFrom == "" {
-		*emailFrom = os.Getenv("SYSLOG_EMAIL_FROM")
-		if *emailFrom == "" {
-			*emailFrom = "enfn2001@gmail.com"
+
+	// 시간대 기반 보고서 스케줄 (플래그 우선, 없으면 설정 파일)
+	scheduleSpec := *reportScheduleFlag
+	if scheduleSpec == "" && configService != nil {
+		scheduleSpec = configService.GetConfig().Reports.Schedule
+	}
+	var reportSchedule *ReportSchedule
+	if scheduleSpec != "" {
+		schedule, err := ParseReportSchedule(scheduleSpec)
+		if err != nil {
+			fmt.Printf("❌ 보고서 스케줄 오류: %v\n", err)
+			os.Exit(1)
 		}
+		reportSchedule = schedule
 	}
-	if *smtpServer == "" {
-		*smtpServer = os.Getenv("SYSLOG_SMTP_SERVER")
-		if *smtpServer == "" {
-			*smtpServer = "smtp.gmail.com"
+
+	// 로그인 알림 간격 (플래그를 명시하지 않으면 설정 파일 값 사용)
+	alertInterval := *alertIntervalFlag
+	if configService != nil && configService.GetConfig().Login.AlertInterval > 0 && !isFlagSet("alert-interval") {
+		alertInterval = configService.GetConfig().Login.AlertInterval
+	}
+
+	// 감시 서비스 생성 및 시작
+	monitor := NewSyslogMonitor(*logFile, *outputFile, filters, keywords, emailConfig, slackConfig, *aiEnabled, *systemEnabled, *loginWatch, alertInterval, *reportIntervalFlag, *periodicReportFlag)
+	if reportSchedule != nil {
+		monitor.SetReportSchedule(reportSchedule)
+	}
+
+	// LLM 일일 요약 스케줄 (플래그가 설정 파일 reports.digest.schedule 보다 우선)
+	if *digestScheduleFlag != "" {
+		digestConfig := DigestConfig{}
+		if configService != nil {
+			digestConfig = configService.GetConfig().Reports.Digest
+		}
+		digestConfig.Schedule = *digestScheduleFlag
+		if err := monitor.digest.SetConfig(digestConfig); err != nil {
+			fmt.Printf("❌ 일일 요약 스케줄 오류: %v\n", err)
+			os.Exit(1)
 		}
 	}
-	if *smtpPort == "" {
-		*smtpPort = os.Getenv("SYSLOG_SMTP_PORT")
-		if *smtpPort == "" {
-			*smtpPort = "587"
+
+	// 알림 채널 카나리 (플래그가 설정 파일 canary.schedule 보다 우선)
+	if *canaryFlag {
+		canaryConfig := CanaryConfig{}
+		if configService != nil {
+			canaryConfig = configService.GetConfig().Canary
+		}
+		if err := monitor.EnableCanary(canaryConfig, *canaryScheduleFlag); err != nil {
+			fmt.Printf("❌ 알림 채널 카나리 설정 오류: %v\n", err)
+			os.Exit(1)
 		}
+	} else if *canaryScheduleFlag != "" {
+		fmt.Println("❌ -canary-schedule 은 -canary 와 함께 사용하세요")
+		os.Exit(1)
 	}
-	if *smtpUser == "" {
-		*smtpUser = os.Getenv("SYSLOG_SMTP_USER")
-		if *smtpUser == "" {
-			// 기본 SMTP 사용자
-			*smtpUser = "enfn2001@gmail.com"
+
+	// 필터링된 로그 출력 형식 (text, json, ndjson)
+	if err := monitor.SetOutputFormat(outputFormatValue, recordStdout); err != nil {
+		fmt.Printf("❌ 출력 설정 오류: %v\n", err)
+		os.Exit(1)
+	}
+	if *outputWatch {
+		if err := monitor.EnableSelfWatch(); err != nil {
+			fmt.Printf("❌ 출력 파일 자기 감시 오류: %v\n", err)
+			os.Exit(1)
 		}
+		fmt.Printf("🩺 출력 파일 자기 감시: %s 의 모니터 오류를 %s 알림으로 전송\n", *outputFile, AlertTypeMonitorError)
 	}
-	if *smtpPassword == "" {
-		*smtpPassword = os.Getenv("SYSLOG_SMTP_PASSWORD")
-		if *smtpPassword == "" {
-			// 기본 App Password (테스트에서 성공한 값)
-			*smtpPassword = "lcsn auno hcqx zozp"
+
+	// 신뢰 네트워크 (플래그가 설정 파일 값보다 우선)
+	if *trustedNetworksFlag != "" {
+		if err := monitor.SetTrustedNetworks(strings.Split(*trustedNetworksFlag, ",")); err != nil {
+			fmt.Printf("❌ 신뢰 네트워크 설정 오류: %v\n", err)
+			os.Exit(1)
 		}
 	}
-	if *slackWebhook == "" {
-		*slackWebhook = os.Getenv("SYSLOG_SLACK_WEBHOOK")
+
+	// 로컬 GeoIP 데이터베이스 (지정하지 않으면 ip-api.com 조회)
+	if *geoIPDBFlag != "" {
+		var databases []string
+		for _, path := range strings.Split(*geoIPDBFlag, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				databases = append(databases, expandHomePath(path))
+			}
+		}
+		if err := monitor.geoMapper.SetGeoIPDatabases(databases); err != nil {
+			fmt.Printf("❌ GeoIP 데이터베이스 오류: %v\n", err)
+			os.Exit(1)
+		}
 	}
-	if *slackChannel == "" {
-		*slackChannel = os.Getenv("SYSLOG_SLACK_CHANNEL")
+
+	// 출처 IP 역방향 DNS (PTR) 조회 (로그인/웹 알림에 호스트 이름 표시)
+	if *reverseDNSFlag {
+		monitor.SetReverseDNS(NewReverseDNS(time.Duration(*reverseDNSTimeout) * time.Millisecond))
 	}
-	if *slackUsername == "Syslog Monitor" {
-		if env := os.Getenv("SYSLOG_SLACK_USERNAME"); env != "" {
-			*slackUsername = env
+
+	// 무차별 대입 알림에 출발지 IP 의 abuse 연락처 추가 (신고 메일 주소)
+	if *whoisAbuseFlag {
+		monitor.SetWhoisLookup(NewWhoisLookup(time.Duration(*whoisTimeout) * time.Millisecond))
+	}
+
+	// 무차별 대입 공격 IP 자동 차단 (플래그가 설정 파일 값보다 우선, 설정 재로드 대상 아님)
+	blockAction, blockCommand, unblockCommand := *blockActionFlag, "", ""
+	blockDuration := *blockDurationFlag
+	var blockAllowlist []string
+	if *blockAllowlistFlag != "" {
+		blockAllowlist = strings.Split(*blockAllowlistFlag, ",")
+	}
+	if configService != nil {
+		blockConfig := configService.GetConfig().Block
+		if blockAction == "" {
+			blockAction = blockConfig.Action
+		}
+		blockCommand, unblockCommand = blockConfig.BlockCommand, blockConfig.UnblockCommand
+		if !isFlagSet("block-duration") && blockConfig.Duration >= 0 {
+			blockDuration = blockConfig.Duration
+		}
+		if blockAllowlist == nil {
+			blockAllowlist = blockConfig.Allowlist
+		}
+	}
+	if blockAction != "" {
+		if !*loginWatch {
+			fmt.Println("⚠️  IP 자동 차단은 로그인 감지(-login-watch)가 필요합니다. 차단을 사용하지 않습니다.")
+		} else {
+			blocker, err := NewIPBlocker(blockAction, blockCommand, unblockCommand, time.Duration(blockDuration)*time.Minute, blockAllowlist, monitor.logger)
+			if err != nil {
+				fmt.Printf("❌ IP 차단 설정 오류: %v\n", err)
+				os.Exit(1)
+			}
+			monitor.SetIPBlocker(blocker)
 		}
 	}
 
-	// Gemini API 키 설정
-	if *geminiAPIKey != "" {
-		if err := configService.SetGeminiAPIKey(*geminiAPIKey); err != nil {
-			fmt.Printf("❌ Gemini API 키 설정 실패: %v\n", err)
+	// DB 권한 변경 / 관리자 계정 인증 실패 감지
+	if *dbWatch {
+		monitor.SetDBPrivilegeDetector(newConfiguredDBPrivilegeDetector())
+	}
+
+	// CI/CD 반복 작업 실패 / 예상 밖 러너 등록 / 빌드 출력 비밀 노출 감지
+	if *cicdWatch {
+		detector, err := newConfiguredCICDDetector()
+		if err != nil {
+			fmt.Printf("❌ CI/CD 감지 설정 오류: %v\n", err)
+			os.Exit(1)
+		}
+		monitor.SetCICDDetector(detector)
+	}
+
+	// 웹 SQL 인젝션 탐지의 DB 로그 확인 (SQL_Injection_Attempt 패턴은 AI 분석에서 일치)
+	if *sqliConfirm {
+		if !*aiEnabled {
+			fmt.Println("⚠️  SQL 인젝션 DB 확인은 AI 분석(-ai-analysis)이 필요합니다. 사용하지 않습니다.")
 		} else {
-			fmt.Printf("✅ Gemini API 키가 설정되었습니다: %s\n", configService.getMaskedAPIKey())
+			var dbLogs []string
+			for _, path := range strings.Split(*sqliDBLog, ",") {
+				if path = strings.TrimSpace(path); path != "" {
+					dbLogs = append(dbLogs, expandHomePath(path))
+				}
+			}
+			monitor.SetSQLInjectionCorrelator(NewSQLInjectionCorrelator(*sqliWindow), dbLogs)
 		}
+	} else if *sqliDBLog != "" {
+		fmt.Println("⚠️  -sqli-db-log 는 -sqli-confirm 과 함께 사용해야 합니다. 무시합니다.")
 	}
 
-	// 설정 정보 표시
-	if *showConfig {
-		configService.ShowConfigInfo()
-		return
+	// 웹 접근 로그 통계 전용 모드
+	if *accessStats {
+		if *accessErrors <= 0 || *accessErrors > 100 {
+			fmt.Printf("❌ -access-error-rate 는 0 보다 크고 100 이하여야 합니다: %g\n", *accessErrors)
+			os.Exit(1)
+		}
+		if *accessWindow <= 0 || *accessAttacks <= 0 {
+			fmt.Println("❌ -access-stats-window 와 -access-attack-threshold 는 1 이상이어야 합니다")
+			os.Exit(1)
+		}
+		monitor.SetAccessStats(NewAccessStats(*accessWindow, *accessErrors, *accessAttacks))
+		if *exfilWatch {
+			// 응답 크기 이상 알림은 클라이언트 IP 와 URL 을 담으므로 통계 전용 모드에서는 사용하지 않음
+			fmt.Println("⚠️  -exfil-watch 는 -access-stats 와 함께 사용할 수 없습니다 (알림에 클라이언트 IP 와 URL 이 들어감). 무시합니다.")
+			*exfilWatch = false
+		}
 	}
-	
-	// 서비스 관리 명령어 처리
-	if *installService {
-		installLaunchAgent()
-		return
+
+	// 웹 응답 크기 이상 (데이터 유출) 탐지
+	if *exfilWatch {
+		monitor.SetExfiltrationDetector(NewExfiltrationDetector(*exfilVolume, *exfilWindow))
 	}
-	
-	if *removeService {
-		removeLaunchAgent()
-		return
+
+	// 소스별 형식 미인식 비율 알림
+	if *unknownFormat < 0 || *unknownFormat > 100 {
+		fmt.Printf("❌ -unknown-format-alert 는 0~100 사이여야 합니다: %g\n", *unknownFormat)
+		os.Exit(1)
 	}
-	
-	if *startService {
-		startLaunchAgent()
-		return
+	monitor.parserStats.SetThreshold(*unknownFormat)
+
+	// 표본 이벤트 단계별 지연 시간 추적
+	if *traceSample > 0 {
+		tracer, err := NewLatencyTracer(*traceSample, *slowEvent)
+		if err != nil {
+			fmt.Printf("❌ 지연 시간 추적 설정 오류: %v\n", err)
+			os.Exit(1)
+		}
+		monitor.SetLatencyTracer(tracer)
 	}
-	
-	if *stopService {
-		stopLaunchAgent()
-		return
+
+	// 엔드포인트 SLO 오류 예산 (플래그가 설정 파일 값보다 우선)
+	sloDefinitions, err := ParseSLOSpecs(parseCommaList(*sloFlag))
+	if err != nil {
+		fmt.Printf("❌ SLO 설정 오류: %v\n", err)
+		os.Exit(1)
 	}
-	
-	if *statusService {
-		showServiceStatus()
-		return
+	sloFromFlag := len(sloDefinitions) > 0
+	if !sloFromFlag && configService != nil {
+		sloDefinitions = configService.GetConfig().SLOs
 	}
-	
-	// Daemon 모드 설정
-	if *daemonMode {
-		setupDaemonMode()
+	if len(sloDefinitions) > 0 {
+		tracker, err := NewSLOTracker(sloDefinitions)
+		if err != nil {
+			fmt.Printf("❌ SLO 설정 오류: %v\n", err)
+			os.Exit(1)
+		}
+		monitor.SetSLOTracker(tracker, sloFromFlag)
 	}
 
-	if *showHelp {
-		fmt.Println("Syslog Monitor - Real-time syslog monitoring service")
-		fmt.Println()
-		fmt.Println("Usage:")
-		fmt.Println("  syslog-monitor [options]")
-		fmt.Println()
-		fmt.Println("Options:")
-		flag.PrintDefaults()
-		fmt.Println()
-		fmt.Println("Examples:")
-		fmt.Println("  # Monitor default syslog with all messages")
-		fmt.Println("  ./syslog-monitor")
-		fmt.Println()
-		fmt.Println("  # Monitor specific file with keyword filtering")
-		fmt.Println("  ./syslog-monitor -file=/var/log/auth.log -keywords=failed,error")
-		fmt.Println()
-		fmt.Println("  # Monitor with output to file and filtering")
-		fmt.Println("  ./syslog-monitor -output=monitor.log -filters=systemd,kernel")
-		fmt.Println()
-		fmt.Println("  # Monitor with default email alerts (multiple recipients)")
-		fmt.Println("  ./syslog-monitor")
-		fmt.Println()
-		fmt.Println("  # Monitor with custom multiple recipients")
-		fmt.Println("  ./syslog-monitor -email-to=\"admin@company.com,security@company.com,ops@company.com\"")
-		fmt.Println()
-		fmt.Println("  # Monitor with email alerts (using command line)")
-		fmt.Println("  ./syslog-monitor -email-to=admin@example.com -smtp-user=your@gmail.com -smtp-password=yourapppassword")
-		fmt.Println()
-		fmt.Println("  # Monitor with email alerts (using environment variables)")
-		fmt.Println("  export SYSLOG_EMAIL_TO=\"admin@company.com,security@company.com\"")
-		fmt.Println("  export SYSLOG_SMTP_USER=your@gmail.com")
-		fmt.Println("  export SYSLOG_SMTP_PASSWORD=yourapppassword")
-		fmt.Println("  ./syslog-monitor")
-		fmt.Println()
-		if runtime.GOOS == "darwin" {
-			fmt.Println("  # macOS specific examples")
-			fmt.Println("  ./syslog-monitor -file=/var/log/system.log -ai-analysis")
-			fmt.Println("  ./syslog-monitor -file=/var/log/install.log -keywords=error")
-			fmt.Println("  ./syslog-monitor -file=/var/log/wifi.log -system-monitor")
-			fmt.Println("  sudo log stream | ./syslog-monitor -file=/dev/stdin -ai-analysis")
+	// ModSecurity 감사 로그 웹 공격 알림
+	if *modSecLog != "" {
+		monitor.SetModSecurityAuditLog(expandHomePath(*modSecLog))
+	}
+
+	// 헬스 체크 요청 제외 (기본 활성화)
+	if !*healthChecks {
+		monitor.DisableHealthCheckSuppression()
+	}
+
+	// 워커 풀 처리 파이프라인
+	if *workers < 0 {
+		fmt.Println("❌ -workers 는 0 이상이어야 합니다")
+		os.Exit(1)
+	}
+	monitor.SetWorkers(*workers)
+
+	// 알림/이벤트 히스토리 저장소
+	if *dbRetentionFlag < 0 {
+		fmt.Println("❌ -db-retention-days 는 0 이상이어야 합니다")
+		os.Exit(1)
+	}
+	if *dbPathFlag != "" {
+		store, err := OpenEventStore(*dbPathFlag, monitor.logger)
+		if err != nil {
+			fmt.Printf("❌ 이벤트 저장소 설정 오류: %v\n", err)
+			os.Exit(1)
+		}
+		store.SetRetention(time.Duration(*dbRetentionFlag) * 24 * time.Hour)
+		if err := monitor.SetEventStore(store); err != nil {
+			fmt.Printf("❌ 이벤트 저장소 설정 오류: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Elasticsearch/OpenSearch 색인 출력
+	if *esURLFlag != "" {
+		output, err := NewElasticsearchOutput(*esURLFlag, *esIndexPrefixFlag, monitor.logger)
+		if err != nil {
+			fmt.Printf("❌ Elasticsearch 출력 설정 오류: %v\n", err)
+			os.Exit(1)
+		}
+		if *outputBufferFlag != "" {
+			buffer, err := OpenOutputBuffer(filepath.Join(expandHomePath(*outputBufferFlag), "elasticsearch"), "Elasticsearch", int64(*outputBufferSizeFlag)<<20, monitor.logger)
+			if err != nil {
+				fmt.Printf("❌ 출력 버퍼 설정 오류: %v\n", err)
+				os.Exit(1)
+			}
+			output.SetBuffer(buffer)
+			monitor.WatchOutputBuffer(buffer)
+		}
+		monitor.SetElasticsearchOutput(output)
+	}
+
+	// Kafka 발행 출력
+	if *kafkaBrokersFlag != "" {
+		output, err := NewKafkaOutput(strings.Split(*kafkaBrokersFlag, ","), *kafkaTopicFlag, *kafkaPartitionFlag, *kafkaAlertsOnlyFlag, monitor.logger)
+		if err != nil {
+			fmt.Printf("❌ Kafka 출력 설정 오류: %v\n", err)
+			os.Exit(1)
+		}
+		if *outputBufferFlag != "" {
+			buffer, err := OpenOutputBuffer(filepath.Join(expandHomePath(*outputBufferFlag), "kafka"), "Kafka", int64(*outputBufferSizeFlag)<<20, monitor.logger)
+			if err != nil {
+				fmt.Printf("❌ 출력 버퍼 설정 오류: %v\n", err)
+				os.Exit(1)
+			}
+			output.SetBuffer(buffer)
+			monitor.WatchOutputBuffer(buffer)
+		}
+		monitor.SetKafkaOutput(output)
+	}
+
+	// 상위 syslog 서버 전달 (보강 릴레이 모드)
+	if *forwardFlag != "" {
+		forwarder, err := NewSyslogForwarder(*forwardFlag, *forwardCAFlag, monitor.logger)
+		if err != nil {
+			fmt.Printf("❌ syslog 전달 설정 오류: %v\n", err)
+			os.Exit(1)
+		}
+		monitor.SetSyslogForwarder(forwarder)
+	}
+
+	// 학습용 층화 표본 내보내기
+	if *sampleExportFlag != "" {
+		if *samplePerStratumFlag < 1 {
+			fmt.Println("❌ -sample-per-stratum 은 1 이상이어야 합니다")
+			os.Exit(1)
+		}
+		monitor.SetEventSampler(NewEventSampler(*sampleExportFlag, *samplePerStratumFlag, *sampleSeedFlag, monitor.logger))
+		fmt.Printf("🧪 학습용 표본 내보내기: %s (층마다 최대 %d건, 시드 %d, 종료 시 저장)\n", *sampleExportFlag, *samplePerStratumFlag, *sampleSeedFlag)
+		if !*aiEnabled {
+			fmt.Println("   ℹ️  -ai-analysis 없이 실행하면 이상 패턴 층 없이 레벨별로만 나뉩니다")
+		}
+	}
+
+	// 사용자 정의 이상 패턴 규칙 (플래그 우선, 없으면 설정 파일)
+	rulesPath := *rulesFlag
+	if rulesPath == "" && configService != nil {
+		rulesPath = configService.GetConfig().AI.RulesFile
+	}
+	if rulesPath != "" {
+		if !*aiEnabled {
+			fmt.Println("⚠️  이상 패턴 규칙 파일은 AI 분석(-ai-analysis)이 필요합니다. 규칙을 사용하지 않습니다.")
+		} else if err := monitor.SetAnomalyRules(rulesPath); err != nil {
+			fmt.Printf("❌ 이상 패턴 규칙 오류: %v\n", err)
+			os.Exit(1)
+		} else {
+			fmt.Printf("📐 Anomaly rules loaded: %s (%d patterns)\n", rulesPath, len(monitor.aiAnalyzer.patterns))
 		}
-		fmt.Println()
-		fmt.Println("  # Test email configuration (multiple recipients)")
-		fmt.Println("  ./syslog-monitor -test-email -email-to=\"user1@test.com,user2@test.com\"")
-		fmt.Println()
-		fmt.Println("  # Slack integration with login monitoring")
-		fmt.Println("  ./syslog-monitor -slack-webhook=https://hooks.slack.com/... -login-watch")
-		fmt.Println()
-		fmt.Println("  # Combined email + Slack alerts")
-		fmt.Println("  ./syslog-monitor -slack-webhook=https://hooks.slack.com/... -slack-channel=#alerts")
-		fmt.Println()
-		fmt.Println("  # Test Slack integration")
-		fmt.Println("  ./syslog-monitor -test-slack -slack-webhook=https://hooks.slack.com/...")
-		fmt.Println()
-		fmt.Println("  # AI-powered log analysis with system monitoring")
-		fmt.Println("  ./syslog-monitor -ai-analysis -system-monitor")
-		fmt.Println()
-		fmt.Println("  # Monitor web server logs with AI analysis")
-		fmt.Println("  ./syslog-monitor -file=/var/log/nginx/access.log -log-type=nginx -ai-analysis")
-		fmt.Println()
-		fmt.Println("  # Database log monitoring with anomaly detection")
-		fmt.Println("  ./syslog-monitor -file=/var/log/mysql/error.log -log-type=mysql -ai-analysis")
-		fmt.Println()
-		fmt.Println("  # Complete monitoring setup")
-		fmt.Println("  ./syslog-monitor -ai-analysis -system-monitor -login-watch -slack-webhook=URL")
-		fmt.Println()
-		fmt.Println("Environment Variables:")
-		fmt.Println("  SYSLOG_EMAIL_TO        - Email addresses to send alerts (comma-separated)")
-		fmt.Println("  SYSLOG_EMAIL_FROM      - Email sender address")
-		fmt.Println("  SYSLOG_SMTP_SERVER     - SMTP server (default: smtp.gmail.com)")
-		fmt.Println("  SYSLOG_SMTP_PORT       - SMTP port (default: 587)")
-		fmt.Println("  SYSLOG_SMTP_USER       - SMTP username")
-		fmt.Println("  SYSLOG_SMTP_PASSWORD   - SMTP password")
-		fmt.Println("  SYSLOG_SLACK_WEBHOOK   - Slack webhook URL")
-		fmt.Println("  SYSLOG_SLACK_CHANNEL   - Slack channel")
-		fmt.Println("  SYSLOG_SLACK_USERNAME  - Slack bot username")
-		fmt.Println()
-		fmt.Println("Gmail Setup:")
-		fmt.Println("  1. Enable 2-Step Verification in your Google Account")
-		fmt.Println("  2. Generate App Password at: https://myaccount.google.com/apppasswords")
-		fmt.Println("  3. Use the App Password instead of your regular password")
-		fmt.Println()
-		fmt.Println("Slack Setup:")
-		fmt.Println("  1. Create Slack App: https://api.slack.com/apps")
-		fmt.Println("  2. Enable Incoming Webhooks")
-		fmt.Println("  3. Copy webhook URL and use with -slack-webhook")
-		return
 	}
 
-	// 필터와 키워드 파싱
-	var filters []string
-	var keywords []string
+	// ONNX 이상 점수 모델 (플래그 우선, 없으면 설정 파일)
+	modelPath, modelWeight := *onnxModelFlag, *onnxWeightFlag
+	if configService != nil {
+		aiConfig := configService.GetConfig().AI
+		if modelPath == "" {
+			modelPath = aiConfig.ModelFile
+		}
+		if !isFlagSet("onnx-weight") && aiConfig.ModelWeight > 0 {
+			modelWeight = aiConfig.ModelWeight
+		}
+	}
+	if modelPath != "" {
+		if !*aiEnabled {
+			fmt.Println("⚠️  ONNX 이상 점수 모델은 AI 분석(-ai-analysis)이 필요합니다. 모델을 사용하지 않습니다.")
+		} else if model, err := LoadAnomalyModel(expandHomePath(modelPath), modelWeight); err != nil {
+			fmt.Printf("❌ ONNX 모델 오류: %v\n", err)
+			os.Exit(1)
+		} else {
+			monitor.aiAnalyzer.SetModel(model)
+			fmt.Printf("🧠 ONNX anomaly model loaded: %s\n", model.Describe())
+		}
+	}
 
-	if *filterList != "" {
-		filters = strings.Split(*filterList, ",")
-		for i := range filters {
-			filters[i] = strings.TrimSpace(filters[i])
+	// 규칙/Tor/GeoIP 데이터 자동 업데이트 (설정 파일 data_updates, 규칙과 GeoIP 설정 후 등록)
+	if updates := configService.GetConfig().DataUpdates; len(updates) > 0 {
+		updater, err := NewDataUpdater(updates, monitor.logger)
+		if err == nil {
+			err = monitor.SetDataUpdater(updater)
+		}
+		if err != nil {
+			fmt.Printf("❌ 데이터 업데이트 설정 오류: %v\n", err)
+			os.Exit(1)
 		}
+		fmt.Printf("📦 Data updates enabled: %s\n", strings.Join(updater.Kinds(), ", "))
+	}
+
+	// 설정 파일 재로드 (파일 변경 감시는 -config-watch=false 로 끄고 SIGHUP 만 사용 가능)
+	// 규칙 파일도 함께 감시하여 바뀌면 다시 읽음
+	pollInterval := ConfigPollInterval
+	if !*configWatchFlag {
+		pollInterval = 0
+	}
+	configWatcher := NewConfigWatcher(configService.GetConfigPath(), pollInterval, monitor.logger)
+	if monitor.rulesPath != "" {
+		configWatcher.Watch(expandHomePath(monitor.rulesPath))
 	}
+	monitor.SetConfigWatcher(configWatcher)
 
-	if *keywordList != "" {
-		keywords = strings.Split(*keywordList, ",")
-		for i := range keywords {
-			keywords[i] = strings.TrimSpace(keywords[i])
+	// 멀티 테넌트 모드 (테넌트별 소스/저장소/알림 채널/읽기 전용 API 토큰, 설정 재로드 대상 아님)
+	if *tenantsFlag != "" {
+		tenantConfigs, err := LoadTenantConfigs(*tenantsFlag)
+		if err != nil {
+			fmt.Printf("❌ 테넌트 설정 오류: %v\n", err)
+			os.Exit(1)
+		}
+		esIndexPrefix := ""
+		if *esURLFlag != "" {
+			esIndexPrefix = *esIndexPrefixFlag
 		}
+		var anomalyPatterns []AnomalyPattern
+		if monitor.rulesPath != "" {
+			anomalyPatterns = monitor.aiAnalyzer.patterns
+		}
+		tenants, err := NewTenantManager(tenantConfigs, TenantOptions{
+			EmailConfig:     emailConfig,
+			AIEnabled:       *aiEnabled,
+			LoginWatch:      *loginWatch,
+			DBWatch:         *dbWatch,
+			CICDWatch:       *cicdWatch,
+			SQLIConfirm:     *sqliConfirm && *aiEnabled,
+			SQLIWindow:      *sqliWindow,
+			ExfilWatch:      *exfilWatch,
+			ExfilVolumeMB:   *exfilVolume,
+			ExfilWindow:     *exfilWindow,
+			AccessStats:     *accessStats,
+			AccessWindow:    *accessWindow,
+			AccessErrorRate: *accessErrors,
+			AccessAttacks:   *accessAttacks,
+			UnknownFormat:   *unknownFormat,
+			TraceSample:     *traceSample,
+			SlowEvent:       *slowEvent,
+			AlertInterval:   alertInterval,
+			ESURL:           *esURLFlag,
+			ESIndexPrefix:   esIndexPrefix,
+			OutputBuffer:    *outputBufferFlag,
+			OutputBufferMB:  *outputBufferSizeFlag,
+			Dashboard:       *dashboardFlag,
+			AnomalyPatterns: anomalyPatterns,
+			Operator:        monitor,
+		})
+		if err != nil {
+			fmt.Printf("❌ 테넌트 설정 오류: %v\n", err)
+			os.Exit(1)
+		}
+		monitor.SetTenants(tenants)
+		fmt.Printf("🏢 Multi-tenant mode: %d tenants loaded from %s\n", len(tenantConfigs), *tenantsFlag)
 	}
 
-	// 로그인 모니터링이 활성화된 경우 관련 키워드 자동 추가
-	if *loginWatch {
-		loginKeywords := []string{"sshd", "sudo", "login", "session", "authentication", "accepted", "failed"}
-		for _, keyword := range loginKeywords {
-			// 중복 방지
-			found := false
-			for _, existing := range keywords {
-				if strings.ToLower(existing) == strings.ToLower(keyword) {
-					found = true
-					break
-				}
-			}
-			if !found {
-				keywords = append(keywords, keyword)
-			}
+	// 고가용성 대기 인스턴스 (두 인스턴스가 함께 수집하고 잠금을 가진 리더만 알림 전송)
+	if *haLockFlag != "" {
+		election, err := NewLeaderElection(*haLockFlag, *haIDFlag, *haTTLFlag, monitor.logger)
+		if err != nil {
+			fmt.Printf("❌ 고가용성 설정 오류: %v\n", err)
+			os.Exit(1)
 		}
-		fmt.Printf("🔍 Added login keywords: %s\n", strings.Join(loginKeywords, ", "))
+		monitor.SetLeaderElection(election)
 	}
 
-	// 이메일 설정 (기본값으로 항상 활성화)
-	emailConfig := &EmailConfig{
-		SMTPServer: *smtpServer,
-		SMTPPort:   *smtpPort,
-		Username:   *smtpUser,
-		Password:   *smtpPassword,
-		From:       *emailFrom,
-		Enabled:    true, // 기본값으로 항상 활성화
+	// 관리 REST API (상태/메트릭/최근 알림 조회, 임계값/필터 변경, 테스트 알림)
+	// 웹 대시보드는 관리 API 서버에서 함께 제공
+	if *dashboardFlag && *apiPortFlag == 0 {
+		*apiPortFlag = DefaultDashboardPort
+	}
+	if *apiPortFlag > 0 {
+		if *apiTokenFlag == "" {
+			*apiTokenFlag = os.Getenv("SYSLOG_API_TOKEN")
+		}
+		// 테넌트 토큰과 운영자 권한을 구분하려면 운영자 토큰이 반드시 필요
+		if monitor.tenants != nil && *apiTokenFlag == "" {
+			fmt.Println("❌ 멀티 테넌트 모드에서는 관리 API 운영자 토큰(-api-token)이 필요합니다.")
+			os.Exit(1)
+		}
+		if monitor.tenants.HasToken(*apiTokenFlag) {
+			fmt.Println("❌ 운영자 토큰(-api-token)이 테넌트 API 토큰과 같습니다.")
+			os.Exit(1)
+		}
+		if *apiTokenFlag == "" && *apiBindFlag != DefaultAPIBind && *apiBindFlag != "localhost" {
+			fmt.Printf("⚠️  관리 API 가 %s 에서 인증 없이 열립니다. -api-token 설정을 권장합니다.\n", *apiBindFlag)
+		}
+		if *dashboardFlag {
+			monitor.SetDashboard(NewDashboard(monitor))
+		}
+		if (*apiTLSCertFlag == "") != (*apiTLSKeyFlag == "") {
+			fmt.Println("❌ -api-tls-cert 와 -api-tls-key 는 함께 지정해야 합니다.")
+			os.Exit(1)
+		}
+		apiServer := NewAPIServer(monitor, *apiBindFlag, *apiPortFlag, *apiTokenFlag)
+		apiServer.SetTLS(*apiTLSCertFlag, *apiTLSKeyFlag)
+		monitor.SetAPIServer(apiServer)
+	}
+	if monitor.alertActions.Enabled() && *apiPortFlag == 0 {
+		fmt.Println("⚠️  알림 이메일 끄기 링크(alerts.actions)는 관리 API(-api-port)가 있어야 동작합니다.")
+	}
+	if monitor.slackActions.Enabled() && *apiPortFlag == 0 {
+		fmt.Println("⚠️  Slack 확인/끄기 버튼과 /sysmon 명령(slack.signing_secret)은 관리 API(-api-port)가 있어야 동작합니다.")
 	}
 
-	// 이메일 주소 파싱
-	emails := strings.Split(*emailTo, ",")
-	for i := range emails {
-		emails[i] = strings.TrimSpace(emails[i])
+	// 범용 JSON 웹훅 알림 채널
+	if *webhookURL != "" {
+		monitor.AddAlertSink("webhook", NewWebhookSink(*webhookURL))
 	}
-	emailConfig.To = emails
 
-	// 사용자 알림
-	if (*emailTo == "robot@lambda-x.ai,enfn2001@gmail.com" || *emailTo == "robot@lambda-x.ai" || *emailTo == "enfn2001@gmail.com") && *smtpUser == "enfn2001@gmail.com" {
-		fmt.Printf("📧 Email alerts enabled with DEFAULT settings\n")
-		fmt.Printf("    📨 Recipients (%d): %s\n", len(emailConfig.To), strings.Join(emailConfig.To, ", "))
-		fmt.Printf("    🔑 Using built-in Gmail credentials (enfn2001@gmail.com)\n")
-		fmt.Printf("    💡 To add more recipients: -email-to=\"user1@example.com,user2@example.com\"\n")
-	} else {
-		fmt.Printf("📧 Email alerts enabled with CUSTOM settings\n")
-		fmt.Printf("    📨 Recipients (%d): %s\n", len(emailConfig.To), strings.Join(emailConfig.To, ", "))
-		
-		if *smtpUser == "" || *smtpPassword == "" {
-			fmt.Println("⚠️  Warning: SMTP username or password not provided. Email alerts may not work.")
-			fmt.Println("    For Gmail, generate an App Password at: https://myaccount.google.com/apppasswords")
-			fmt.Println("    Use: ./email-setup.sh for easy configuration")
+	// Telegram 알림 채널
+	if telegramConfig.Enabled {
+		monitor.AddAlertSink("telegram", NewTelegramService(telegramConfig, monitor.logger))
+	}
+
+	// PagerDuty 알림 채널 (CRITICAL AI/시스템 알림만 호출, 조건 해소 시 자동 해결)
+	if *pagerDutyKey != "" {
+		monitor.AddAlertSink("pagerduty", NewPagerDutySink(*pagerDutyKey))
+	}
+
+	// OCSF 보안 이벤트 출력 (보안 탐지만 정규화해 기록, 데이터 레이크 수집용)
+	if *ocsfOutputFlag != "" {
+		sink, err := NewOCSFSink(*ocsfOutputFlag)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
 		}
+		monitor.AddAlertSink("ocsf", sink)
 	}
 
-	// 슬랙 설정
-	slackConfig := &SlackConfig{
-		WebhookURL: *slackWebhook,
-		Channel:    *slackChannel,
-		Username:   *slackUsername,
-		Enabled:    *slackWebhook != "",
+	// 알림 전송 저널 (전송 전에 기록, 확인될 때까지 재시도, 비정상 종료 후 재시작 시 다시 전송)
+	if *deliveryJournalFlag != "" {
+		journal, err := OpenDeliveryJournal(*deliveryJournalFlag, monitor.logger)
+		if err != nil {
+			fmt.Printf("❌ 알림 전송 저널 오류: %v\n", err)
+			os.Exit(1)
+		}
+		monitor.SetDeliveryJournal(journal)
 	}
 
-	if slackConfig.Enabled {
-		fmt.Printf("💬 Slack alerts enabled\n")
-		fmt.Printf("    📡 Webhook: %s\n", slackConfig.WebhookURL[:50]+"...")
-		if slackConfig.Channel != "" {
-			fmt.Printf("    📺 Channel: %s\n", slackConfig.Channel)
+	// journald 입력 모드
+	if *journaldFlag {
+		var units []string
+		if *journaldUnitsFlag != "" {
+			for _, unit := range strings.Split(*journaldUnitsFlag, ",") {
+				if unit = strings.TrimSpace(unit); unit != "" {
+					units = append(units, unit)
+				}
+			}
 		}
-		fmt.Printf("    🤖 Bot Name: %s\n", slackConfig.Username)
-	} else {
-		fmt.Printf("💬 Slack alerts disabled. Use -slack-webhook to enable.\n")
+		monitor.SetJournaldInput(units)
 	}
 
-	if *loginWatch {
-		fmt.Printf("👁️  Login monitoring enabled (SSH, sudo, web login detection)\n")
+	// 보관된 로그 재처리 (회전·압축된 로그 백필)
+	if *replayFlag != "" {
+		if *journaldFlag || *eventLogFlag || kubeInput {
+			fmt.Println("❌ -replay 는 -journald/-eventlog/-kube-namespace 와 함께 사용할 수 없습니다")
+			os.Exit(1)
+		}
+		monitor.SetReplayInput(parseCommaList(*replayFlag), ReplayOptions{
+			Workers:    *replayWorkersFlag,
+			DryRun:     *replayDryRunFlag,
+			ReportPath: *replayReportFlag,
+			SARIFPath:  *replaySARIFFlag,
+		})
 	}
-	
-	// AI 분석 상태 메시지
-	if *aiEnabled {
-		fmt.Printf("🤖 AI log analysis enabled\n")
-		fmt.Printf("    🔍 Anomaly detection and prediction\n")
-		fmt.Printf("    📊 Pattern recognition and threat assessment\n")
-		fmt.Printf("    🎯 Supported log types: apache, nginx, mysql, postgresql, application\n")
-	} else {
-		fmt.Printf("🤖 AI analysis disabled. Use -ai-analysis to enable.\n")
+
+	// 여러 줄 엔트리 조립 (스택 트레이스, 슬로우 쿼리 블록)
+	if *multilineFlag || *multilineStartFlag != "" {
+		assembler, err := NewMultilineAssembler(*multilineStartFlag, strings.Split(*multilineRulesFlag, ","))
+		if err != nil {
+			fmt.Printf("❌ 여러 줄 조립 설정 오류: %v\n", err)
+			os.Exit(1)
+		}
+		if *journaldFlag || *eventLogFlag || kubeInput {
+			fmt.Println("⚠️  -multiline 은 파일 입력에만 적용됩니다 (journald/이벤트 로그/쿠버네티스는 엔트리 단위로 수신)")
+		}
+		monitor.SetMultiline(assembler)
+	} else if slowQueryLog {
+		assembler, _ := NewMultilineAssembler("", []string{MultilineRuleHash})
+		monitor.SetMultiline(assembler)
 	}
-	
-	// 시스템 모니터링 상태 메시지
-	if *systemEnabled {
-		fmt.Printf("🖥️  System monitoring enabled\n")
-		fmt.Printf("    📈 CPU, memory, disk, temperature monitoring\n")
-		fmt.Printf("    ⚠️  Real-time alerts for system thresholds\n")
-		fmt.Printf("    🔄 5-minute monitoring interval\n")
-	} else {
-		fmt.Printf("🖥️  System monitoring disabled. Use -system-monitor to enable.\n")
+
+	// Windows 이벤트 로그 입력 모드
+	if *eventLogFlag {
+		var channels []string
+		for _, channel := range strings.Split(*eventLogChannelsFlag, ",") {
+			if channel = strings.TrimSpace(channel); channel != "" {
+				channels = append(channels, channel)
+			}
+		}
+		if len(channels) == 0 {
+			fmt.Println("❌ -eventlog-channels 에 최소 하나의 채널을 지정해야 합니다")
+			os.Exit(1)
+		}
+		monitor.SetEventLogInput(channels)
 	}
 
-	// 테스트 슬랙 전송
-	if *testSlack {
-		if !slackConfig.Enabled {
-			fmt.Println("Error: Slack webhook URL required for test")
-			fmt.Println("Please provide -slack-webhook")
+	// 쿠버네티스 파드 로그 입력 모드
+	if kubeInput {
+		if *journaldFlag || *eventLogFlag {
+			fmt.Println("❌ -kube-namespace/-kube-selector 는 -journald/-eventlog 와 함께 사용할 수 없습니다")
 			os.Exit(1)
 		}
+		monitor.SetKubernetesInput(KubeOptions{
+			Namespace: strings.TrimSpace(*kubeNamespaceFlag),
+			Selector:  strings.TrimSpace(*kubeSelectorFlag),
+			APIServer: *kubeAPIFlag,
+			TokenFile: *kubeTokenFileFlag,
+			CAFile:    *kubeCAFlag,
+		})
+	}
 
-		fmt.Println("Sending test Slack message...")
-		
-		monitor := NewSyslogMonitor(*logFile, *outputFile, filters, keywords, emailConfig, slackConfig, *aiEnabled, *systemEnabled, *loginWatch, *alertIntervalFlag, *reportIntervalFlag, *periodicReportFlag)
-		
-		testMsg := SlackMessage{
-			Text:      "🧪 *Test Message from Syslog Monitor*",
-			IconEmoji: ":test_tube:",
-			Username:  slackConfig.Username,
-			Attachments: []SlackAttachment{
-				{
-					Color: "good",
-					Title: "Syslog Monitor Test",
-					Fields: []SlackField{
-						{Title: "Status", Value: "✅ Working", Short: true},
-						{Title: "Time", Value: time.Now().Format("2006-01-02 15:04:05"), Short: true},
-						{Title: "Features", Value: "Email alerts, Login monitoring, Error detection", Short: false},
-					},
-					Timestamp: time.Now().Unix(),
-				},
-			},
+	// 네트워크 syslog 수신 입력 모드 (컨테이너에서 호스트 로그 파일 마운트 대신 사용)
+	if *listenFlag != "" {
+		if *journaldFlag || *eventLogFlag || kubeInput || *replayFlag != "" {
+			fmt.Println("❌ -listen 은 -journald/-eventlog/-kube-namespace/-replay 와 함께 사용할 수 없습니다")
+			os.Exit(1)
+		}
+		var allow *TrustedNetworks
+		if *listenAllowFlag != "" {
+			allow, err = ParseTrustedNetworks(parseCommaList(*listenAllowFlag))
+			if err != nil {
+				fmt.Printf("❌ -listen-allow 설정 오류: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		for _, target := range parseCommaList(*listenFlag) {
+			if _, _, err := parseListenTarget(target); err != nil {
+				fmt.Printf("❌ -listen 설정 오류: %v\n", err)
+				os.Exit(1)
+			}
 		}
+		monitor.SetSyslogListener(parseCommaList(*listenFlag), allow)
+	} else if *listenAllowFlag != "" {
+		fmt.Println("⚠️  -listen-allow 는 -listen 과 함께 사용할 때만 적용됩니다")
+	}
 
-		if err := monitor.slackService.SendMessage(testMsg); err != nil {
-			fmt.Printf("Test Slack message failed: %v\n", err)
-			fmt.Println("\nTroubleshooting:")
-			fmt.Println("1. Check your Slack webhook URL")
-			fmt.Println("2. Verify webhook permissions")
-			fmt.Println("3. Test webhook manually")
+	// 보고서 파일 저장 디렉토리 (플래그 우선, 없으면 설정 파일)
+	reportDir := *reportDirFlag
+	var reportFormats []string
+	var reportGitPush bool
+	if configService != nil {
+		reportsConfig := configService.GetConfig().Reports
+		if reportDir == "" {
+			reportDir = reportsConfig.ArchiveDir
+		}
+		reportFormats = reportsConfig.ArchiveFormats
+		reportGitPush = reportsConfig.GitPush
+	}
+	if reportDir != "" {
+		archiver, err := NewReportArchiver(reportDir, reportFormats, reportGitPush, monitor.logger)
+		if err != nil {
+			fmt.Printf("❌ 보고서 저장 설정 오류: %v\n", err)
 			os.Exit(1)
 		}
+		monitor.SetReportArchiver(archiver)
+	}
 
-		fmt.Printf("✅ Test Slack message sent successfully!\n")
+	// 컨테이너 모드 시작 점검 (상태 디렉토리 쓰기, root 실행, 켜진 기능의 외부 명령)
+	if containerMode {
+		fmt.Printf("🐳 컨테이너 모드: 설정 %s (읽기 전용), 상태 디렉토리 %s\n", configPath, getDataDir())
+		if err := checkDataDirWritable(getDataDir()); err != nil {
+			fmt.Printf("⚠️  상태 디렉토리에 쓸 수 없습니다 (%s 볼륨을 쓰기 가능하게 마운트하세요): %v\n", DataDirEnv, err)
+		}
+		if os.Geteuid() == 0 {
+			fmt.Println("⚠️  root 로 실행 중입니다. 이미지 기본 사용자(65532) 또는 --user 로 비root 실행을 권장합니다")
+		}
+		required := make(map[string]string)
+		if *journaldFlag {
+			required["-journald"] = "journalctl"
+		}
+		if *dbPathFlag != "" {
+			required["-db-path"] = "sqlite3"
+		}
+		if reportDir != "" && reportGitPush {
+			required["reports.git_push"] = "git"
+		}
+		if monitor.ipBlocker != nil && monitor.ipBlocker.Command() != "" {
+			required["-block-action"] = monitor.ipBlocker.Command()
+		}
+		for _, missing := range missingCommands(required) {
+			fmt.Printf("⚠️  이미지에 필요한 명령이 없습니다 (%s)\n", missing)
+		}
+	}
+	
+	// Windows 서비스로 실행된 경우 서비스 관리자의 중지/종료 요청을 처리하며 실행
+	if isWindowsService() {
+		if err := runWindowsService(monitor); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
 		return
 	}
 
-	// 테스트 이메일 전송
-	if *testEmail {
-		if !emailConfig.Enabled {
-			fmt.Println("Error: Email configuration required for test email")
-			fmt.Println("Please provide -email-to and SMTP credentials")
-			os.Exit(1)
+	// SIGINT/SIGTERM 을 받으면 전송 중인 알림을 기다리고 출력을 정리한 뒤 종료
+	// 첫 신호 후 기본 처리로 되돌려 두 번째 Ctrl+C 는 기다리지 않고 즉시 종료
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	err = monitor.Start(ctx)
+	stop()
+	daemonCleanup()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// LoginKeywords 로그인 감지 활성화 시 자동으로 추가되는 키워드
+var LoginKeywords = []string{"sshd", "sudo", "login", "session", "authentication", "accepted", "failed"}
+
+// parseCommaList 쉼표로 구분된 목록 파싱 (공백 제거, 빈 항목 제외)
+func parseCommaList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
 		}
+	}
+	return items
+}
 
-		fmt.Println("Sending test email...")
-		
-		monitor := NewSyslogMonitor(*logFile, *outputFile, filters, keywords, emailConfig, slackConfig, *aiEnabled, *systemEnabled, *loginWatch, *alertIntervalFlag, *reportIntervalFlag, *periodicReportFlag)
-		subject := "[TEST] Syslog Monitor Email Test"
-		body := fmt.Sprintf(`이것은 syslog 모니터의 테스트 이메일입니다.
+// appendLoginKeywords 키워드 목록에 로그인 관련 키워드 추가 (대소문자 무시 중복 제외)
+func appendLoginKeywords(keywords []string) []string {
+	return appendKeywords(keywords, LoginKeywords)
+}
 
-테스트 시간: %s
-SMTP 서버: %s:%s
-발신자: %s
-수신자: %s
+// appendKeywords 키워드 목록에 없는 키워드만 추가 (대소문자 무시)
+func appendKeywords(keywords, extra []string) []string {
+	for _, keyword := range extra {
+		found := false
+		for _, existing := range keywords {
+			if strings.EqualFold(existing, keyword) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
 
-이 이메일을 받으셨다면 이메일 설정이 올바르게 구성되었습니다.
+// isFlagSet 명령행에서 플래그가 명시적으로 지정되었는지 확인 (설정 파일 값과의 우선순위 판단용)
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
 
-Syslog Monitor
-`, time.Now().Format("2006-01-02 15:04:05"), *smtpServer, *smtpPort, *emailFrom, strings.Join(emailConfig.To, ", "))
+// runHistoryCommand 저장된 알림/이벤트 히스토리 조회 (history 하위 명령)
+func runHistoryCommand(args []string) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	var (
+		dbPath   = fs.String("db-path", filepath.Join(getDataDir(), EventDBFile), "SQLite event history file")
+		since    = fs.String("since", "24h", "Start of time range: duration ago (e.g. 2h, 7d) or date (2006-01-02, RFC3339)")
+		until    = fs.String("until", "", "End of time range: duration ago or date (default: now)")
+		kind     = fs.String("kind", "", "Event kind: login, system, ai, alert_action")
+		user     = fs.String("user", "", "Filter by user name")
+		ip       = fs.String("ip", "", "Filter by source IP address")
+		severity = fs.String("severity", "", "Filter by severity: info, warning, critical")
+		limit    = fs.Int("limit", 100, "Maximum number of events (0: unlimited)")
+		asJSON   = fs.Bool("json", false, "Print events as JSON (includes the original record)")
+	)
+	fs.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("  syslog-monitor history [options]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  syslog-monitor history -since=7d -ip=203.0.113.10")
+		fmt.Println("  syslog-monitor history -since=2024-01-01 -until=2024-01-31 -kind=login -user=root")
+		fmt.Println("  syslog-monitor history -severity=critical -limit=0 -json")
+	}
+	fs.Parse(args)
+
+	now := time.Now()
+	filter := EventFilter{
+		Kind:     *kind,
+		User:     *user,
+		IP:       *ip,
+		Severity: *severity,
+		Limit:    *limit,
+	}
+	var err error
+	if filter.Since, err = parseHistoryTime(*since, now); err != nil {
+		fmt.Printf("❌ -since 형식 오류: %v\n", err)
+		os.Exit(1)
+	}
+	if filter.Until, err = parseHistoryTime(*until, now); err != nil {
+		fmt.Printf("❌ -until 형식 오류: %v\n", err)
+		os.Exit(1)
+	}
 
-		if err := monitor.emailService.SendEmail(subject, body); err != nil {
-			fmt.Printf("Test email failed: %v\n", err)
-			fmt.Println("\nTroubleshooting:")
-			fmt.Println("1. Check your Gmail App Password")
-			fmt.Println("2. Ensure 2-Step Verification is enabled")
-			fmt.Println("3. Verify SMTP server and port settings")
-			os.Exit(1)
+	if _, err := os.Stat(expandHomePath(*dbPath)); os.IsNotExist(err) {
+		fmt.Printf("❌ 이벤트 히스토리 파일이 없습니다: %s\n", *dbPath)
+		fmt.Println("💡 -db-path 옵션으로 모니터를 실행하면 이벤트가 저장됩니다.")
+		os.Exit(1)
+	}
+
+	store, err := OpenEventStore(*dbPath, logrus.New())
+	if err != nil {
+		fmt.Printf("❌ 이벤트 저장소 열기 실패: %v\n", err)
+		os.Exit(1)
+	}
+	records, err := store.Query(filter)
+	if err != nil {
+		fmt.Printf("❌ 이벤트 조회 실패: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		if records == nil {
+			records = []EventRecord{}
 		}
+		output, _ := json.MarshalIndent(records, "", "  ")
+		fmt.Println(string(output))
+		return
+	}
 
-		fmt.Printf("✅ Test email sent successfully to %d recipients: %s\n", len(emailConfig.To), strings.Join(emailConfig.To, ", "))
+	if len(records) == 0 {
+		fmt.Println("조건에 맞는 이벤트가 없습니다.")
 		return
 	}
+	for _, record := range records {
+		fmt.Printf("%s  %-6s  %-8s  %-15s  %s\n",
+			record.Timestamp.Format("2006-01-02 15:04:05"), record.Kind, record.Severity, record.IP, record.Summary)
+	}
+	fmt.Printf("\n%d events\n", len(records))
+}
 
-	// 감시 서비스 생성 및 시작
-	monitor := NewSyslogMonitor(*logFile, *outputFile, filters, keywords, emailConfig, slackConfig, *aiEnabled, *systemEnabled, *loginWatch, *alertIntervalFlag, *reportIntervalFlag, *periodicReportFlag)
-	
-	if err := monitor.Start(); err != nil {
-		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+// parseHistoryTime 조회 시간 파싱 (빈 값은 조건 없음)
+// "30m", "24h", "7d" 같은 기간은 현재로부터 그만큼 이전 시각, 그 외에는 날짜/RFC3339 로 해석
+func parseHistoryTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil {
+			return now.AddDate(0, 0, -days), nil
+		}
 	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if parsed, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is neither a duration (e.g. 24h, 7d) nor a date (2006-01-02, RFC3339)", value)
 }
 
-// setupDaemonMode daemon 모드 설정
-func setupDaemonMode() {
+// setupDaemonMode daemon 모드 설정 (PID 파일 삭제, 로그 파일 닫기를 하는 정리 함수 반환)
+func setupDaemonMode() func() {
 	fmt.Println("🔧 Setting up daemon mode...")
 	
 	// 기본 경로 설정
@@ -1763,11 +5556,6 @@ func setupDaemonMode() {
 		os.Exit(1)
 	}
 	
-	// 프로세스 종료 시 PID 파일 삭제
-	defer func() {
-		os.Remove(pidFile)
-	}()
-	
 	// 로그 파일 설정
 	logFile := filepath.Join(logDir, "syslog-monitor.log")
 	logOut, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
@@ -1775,7 +5563,6 @@ func setupDaemonMode() {
 		fmt.Printf("❌ Failed to open log file: %v\n", err)
 		os.Exit(1)
 	}
-	defer logOut.Close()
 	
 	// 표준 출력을 로그 파일로 리다이렉션
 	os.Stdout = logOut
@@ -1784,6 +5571,15 @@ func setupDaemonMode() {
 	fmt.Printf("🚀 Daemon started (PID: %d)\n", pid)
 	fmt.Printf("📝 Log file: %s\n", logFile)
 	fmt.Printf("📋 PID file: %s\n", pidFile)
+
+	// 모니터 종료 후 PID 파일 삭제 (로그 파일은 마지막에 닫음)
+	return func() {
+		if err := os.Remove(pidFile); err != nil && !os.IsNotExist(err) {
+			fmt.Printf("⚠️  Failed to remove PID file %s: %v\n", pidFile, err)
+		}
+		fmt.Printf("👋 Daemon stopped (PID: %d)\n", pid)
+		logOut.Close()
+	}
 }
 
 // isRunning 프로세스가 실행 중인지 확인
@@ -1847,6 +5643,17 @@ func installLaunchAgent() {
 		fmt.Printf("❌ Failed to read plist file: %v\n", err)
 		os.Exit(1)
 	}
+
+	// -install-service 와 함께 준 옵션이 있으면 plist 의 실행 옵션 교체 (-config 하나면 옵션을 바꿔도 plist 는 그대로)
+	if args := serviceArgs(os.Args[1:]); len(args) > 0 {
+		updated, err := setLaunchAgentArguments(string(plistData), args)
+		if err != nil {
+			fmt.Printf("❌ Failed to set service options in plist: %v\n", err)
+			os.Exit(1)
+		}
+		plistData = []byte(updated)
+		fmt.Printf("⚙️  Options:    %s\n", strings.Join(args, " "))
+	}
 	
 	if err := os.WriteFile(plistFile, plistData, 0644); err != nil {
 		fmt.Printf("❌ Failed to write plist file: %v\n", err)
@@ -1867,6 +5674,35 @@ func installLaunchAgent() {
 	fmt.Printf("   View logs:      tail -f /usr/local/var/log/syslog-monitor.out.log\n")
 }
 
+// setLaunchAgentArguments plist ProgramArguments 의 실행 파일 뒤 옵션을 args 로 교체
+func setLaunchAgentArguments(plist string, args []string) (string, error) {
+	keyIndex := strings.Index(plist, "<key>ProgramArguments</key>")
+	if keyIndex < 0 {
+		return "", fmt.Errorf("ProgramArguments not found")
+	}
+	start := strings.Index(plist[keyIndex:], "<array>")
+	end := strings.Index(plist[keyIndex:], "</array>")
+	if start < 0 || end < start {
+		return "", fmt.Errorf("ProgramArguments array not found")
+	}
+	start, end = keyIndex+start+len("<array>"), keyIndex+end
+	array := plist[start:end]
+	program := strings.Index(array, "</string>")
+	if program < 0 {
+		return "", fmt.Errorf("ProgramArguments has no program path")
+	}
+	program += len("</string>")
+
+	escape := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	var options strings.Builder
+	options.WriteString(array[:program])
+	for _, arg := range args {
+		fmt.Fprintf(&options, "\n        <string>%s</string>", escape.Replace(arg))
+	}
+	options.WriteString("\n    ")
+	return plist[:start] + options.String() + plist[end:], nil
+}
+
 // removeLaunchAgent macOS LaunchAgent 서비스 제거
 func removeLaunchAgent() {
 	fmt.Println("🗑️  Removing macOS LaunchAgent service...")
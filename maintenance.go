@@ -0,0 +1,566 @@
+/*
+Maintenance Calendar Module
+===========================
+
+외부 변경 관리 캘린더(iCal URL)의 점검 일정 동안 알림 심각도 낮추기 / 묶어 보내기 (설정 파일 maintenance)
+
+주요 기능:
+- calendars: iCal URL (https://, http://, webcal://, file://) 을 refresh_minutes(기본 15분)마다 다시 읽음 (ETag/Last-Modified 로 바뀐 경우만 파싱)
+- 반복 일정(RRULE), 제외 회차(EXDATE), 취소된 일정(STATUS:CANCELLED) 반영 (ical.go)
+- event_filter: 일정 제목/설명/장소/분류 정규식 (비우면 캘린더의 모든 일정이 점검)
+- match: 점검을 적용할 알림 조건 (알림 라우팅과 같은 type/level/host/keyword, 비우면 모든 알림)
+- action downgrade (기본): 심각도를 한 단계 낮추고 제목에 [점검 중] 표시 (critical → warning → info)
+- action batch: 점검 중 알림을 보내지 않고 모아 두었다가 일정이 끝날 때 요약 알림 한 건 (유형 maintenance)
+- 가져오기 실패 시 마지막으로 읽은 일정 유지, GET /maintenance 로 캘린더 상태와 진행 중/다가오는 점검 조회
+*/
+package main
+
+import (
+	"fmt"      // 형식화된 I/O
+	"io"       // 응답 읽기
+	"net/http" // 캘린더 다운로드
+	"net/url"  // URL 검증
+	"os"       // file:// 캘린더
+	"reflect"  // 설정 비교
+	"regexp"   // 일정 필터
+	"sort"     // 점검 정렬
+	"strings"  // 문자열 처리
+	"sync"     // 동시성 제어
+	"time"     // 점검 시각, 갱신 주기
+)
+
+// 점검 일정 설정
+const (
+	MaintenanceDowngrade       = "downgrade"         // 심각도 한 단계 낮춤
+	MaintenanceBatch           = "batch"             // 일정이 끝날 때 요약 알림으로 묶음
+	DefaultMaintenanceRefresh  = 15                  // 기본 캘린더 갱신 주기 (분)
+	MaintenanceCalendarMaxSize = 8 << 20             // 캘린더 최대 크기
+	MaintenanceLookBehind      = 7 * 24 * time.Hour  // 갱신 시 펼치는 과거 범위 (이미 시작한 긴 점검)
+	MaintenanceLookAhead       = 30 * 24 * time.Hour // 갱신 시 펼치는 미래 범위
+	MaintenanceBatchListLimit  = 50                  // 요약 알림에 나열할 최대 알림 수
+	maintenanceCheckInterval   = time.Minute         // 갱신할 캘린더가 있는지 살펴보는 간격
+)
+
+// MaintenanceConfig 점검 일정 설정 (설정 파일 maintenance)
+type MaintenanceConfig struct {
+	Calendars []MaintenanceCalendarConfig `json:"calendars"`
+}
+
+// MaintenanceCalendarConfig 점검 일정을 가져올 캘린더
+type MaintenanceCalendarConfig struct {
+	Name           string          `json:"name"`            // 표시 이름 (비어 있으면 URL 호스트)
+	URL            string          `json:"url"`             // iCal 주소 (https://, http://, webcal://, file://)
+	RefreshMinutes int             `json:"refresh_minutes"` // 갱신 주기 (분, 0 이면 15)
+	Action         string          `json:"action"`          // downgrade (기본), batch
+	EventFilter    string          `json:"event_filter"`    // 점검으로 볼 일정 정규식 (제목/설명/장소/분류, 비우면 모든 일정)
+	Match          AlertRouteMatch `json:"match"`           // 적용할 알림 조건 (비우면 모든 알림)
+}
+
+// MaintenanceWindow 점검 일정 회차
+type MaintenanceWindow struct {
+	Calendar string    `json:"calendar"`
+	Summary  string    `json:"summary"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Action   string    `json:"action"`
+}
+
+// MaintenanceCalendarStatus 캘린더 가져오기 상태
+type MaintenanceCalendarStatus struct {
+	Name      string    `json:"name"`
+	URL       string    `json:"url"` // 쿼리/사용자 정보를 뺀 주소
+	Action    string    `json:"action"`
+	Events    int       `json:"events"`            // 점검으로 본 일정 수 (반복 일정은 1건)
+	Skipped   int       `json:"skipped,omitempty"` // 지원하지 않는 반복 규칙 등으로 건너뛴 일정 수
+	Refreshed time.Time `json:"refreshed,omitempty"`
+	Error     string    `json:"error,omitempty"` // 마지막 갱신 오류 (마지막으로 읽은 일정 유지)
+}
+
+// maintenanceCalendar 캘린더와 펼친 점검 회차
+type maintenanceCalendar struct {
+	config       MaintenanceCalendarConfig
+	match        compiledAlertRoute
+	filter       *regexp.Regexp
+	refresh      time.Duration
+	events       []icalEvent
+	windows      []MaintenanceWindow // 갱신 시점 기준 과거 7일 ~ 미래 30일 회차 (시작 순)
+	etag         string
+	lastModified string
+	status       MaintenanceCalendarStatus
+	next         time.Time
+}
+
+// maintenanceBatch 점검 중 모아 둔 알림
+type maintenanceBatch struct {
+	window MaintenanceWindow
+	alerts []Alert // 처음 MaintenanceBatchListLimit 건
+	total  int
+	counts map[string]int // 심각도별 건수
+	types  map[string]int // 유형별 건수
+	timer  *time.Timer
+}
+
+// MaintenanceSchedule 점검 일정에 따른 알림 처리
+type MaintenanceSchedule struct {
+	calendars []*maintenanceCalendar
+	batches   map[string]*maintenanceBatch
+	flush     func(Alert) // 묶음 요약 알림 전송
+	client    *http.Client
+	logger    Logger
+
+	mutex   sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	started bool
+}
+
+// NewMaintenanceSchedule 설정 검증 후 점검 일정 생성 (캘린더가 없으면 nil)
+func NewMaintenanceSchedule(config MaintenanceConfig, flush func(Alert), logger Logger) (*MaintenanceSchedule, error) {
+	if len(config.Calendars) == 0 {
+		return nil, nil
+	}
+	ms := &MaintenanceSchedule{
+		batches: make(map[string]*maintenanceBatch),
+		flush:   flush,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		logger:  logger,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	names := make(map[string]bool)
+	for i, calendar := range config.Calendars {
+		label := fmt.Sprintf("maintenance calendar #%d", i+1)
+		parsed, err := url.Parse(calendar.URL)
+		if err != nil || calendar.URL == "" {
+			return nil, fmt.Errorf("%s: invalid url %q", label, calendar.URL)
+		}
+		switch parsed.Scheme {
+		case "http", "https", "webcal", "file":
+		default:
+			return nil, fmt.Errorf("%s: unsupported url scheme %q (use https, http, webcal or file)", label, parsed.Scheme)
+		}
+		if calendar.Name == "" {
+			calendar.Name = parsed.Host
+			if parsed.Scheme == "file" {
+				calendar.Name = parsed.Path
+			}
+		}
+		if names[calendar.Name] {
+			return nil, fmt.Errorf("%s: duplicate name %q", label, calendar.Name)
+		}
+		names[calendar.Name] = true
+
+		switch calendar.Action {
+		case "":
+			calendar.Action = MaintenanceDowngrade
+		case MaintenanceDowngrade, MaintenanceBatch:
+		default:
+			return nil, fmt.Errorf("%s: unknown action %q (use %s or %s)", label, calendar.Action, MaintenanceDowngrade, MaintenanceBatch)
+		}
+		if calendar.RefreshMinutes < 0 {
+			return nil, fmt.Errorf("%s: invalid refresh_minutes %d", label, calendar.RefreshMinutes)
+		}
+		refresh := time.Duration(DefaultMaintenanceRefresh) * time.Minute
+		if calendar.RefreshMinutes > 0 {
+			refresh = time.Duration(calendar.RefreshMinutes) * time.Minute
+		}
+
+		entry := &maintenanceCalendar{config: calendar, refresh: refresh}
+		if entry.match, err = compileAlertRouteMatch(label, calendar.Match); err != nil {
+			return nil, err
+		}
+		if calendar.EventFilter != "" {
+			if entry.filter, err = regexp.Compile(calendar.EventFilter); err != nil {
+				return nil, fmt.Errorf("%s: invalid event_filter: %v", label, err)
+			}
+		}
+		entry.status = MaintenanceCalendarStatus{Name: calendar.Name, URL: redactURL(calendar.URL), Action: calendar.Action}
+		ms.calendars = append(ms.calendars, entry)
+	}
+	return ms, nil
+}
+
+// Start 백그라운드 갱신 시작 (모든 캘린더를 바로 한 번 읽음)
+func (ms *MaintenanceSchedule) Start() {
+	if ms == nil {
+		return
+	}
+	ms.started = true
+	go ms.run()
+}
+
+// Stop 갱신 중단 후 모아 둔 알림을 바로 요약 전송 (설정 재로드, 종료)
+func (ms *MaintenanceSchedule) Stop() {
+	if ms == nil {
+		return
+	}
+	ms.once.Do(func() {
+		close(ms.stop)
+		if ms.started {
+			<-ms.done
+		}
+		ms.mutex.Lock()
+		batches := make([]*maintenanceBatch, 0, len(ms.batches))
+		for key, batch := range ms.batches {
+			batch.timer.Stop()
+			batches = append(batches, batch)
+			delete(ms.batches, key)
+		}
+		ms.mutex.Unlock()
+		for _, batch := range batches {
+			ms.flush(maintenanceBatchAlert(batch))
+		}
+	})
+}
+
+// run 갱신 시각이 된 캘린더를 차례로 다시 읽음
+func (ms *MaintenanceSchedule) run() {
+	defer close(ms.done)
+	ticker := time.NewTicker(maintenanceCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, calendar := range ms.calendars {
+			select {
+			case <-ms.stop:
+				return
+			default:
+			}
+			ms.mutex.Lock()
+			due := !time.Now().Before(calendar.next)
+			ms.mutex.Unlock()
+			if due {
+				ms.update(calendar)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ms.stop:
+			return
+		}
+	}
+}
+
+// update 캘린더 하나를 다시 읽고 점검 회차 갱신 (실패하면 마지막으로 읽은 일정 유지)
+func (ms *MaintenanceSchedule) update(calendar *maintenanceCalendar) {
+	now := time.Now()
+	ms.mutex.Lock()
+	etag, lastModified := calendar.etag, calendar.lastModified
+	ms.mutex.Unlock()
+
+	data, etag, lastModified, err := ms.fetch(calendar.config.URL, etag, lastModified)
+	var events []icalEvent
+	skipped := 0
+	if err == nil && data != nil {
+		events, skipped, err = parseICalendar(data)
+	}
+
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	calendar.next = now.Add(calendar.refresh)
+	if err != nil {
+		if calendar.status.Error != err.Error() {
+			ms.logger.Errorf("❌ Maintenance calendar %s refresh failed, keeping %d event(s): %v", calendar.config.Name, len(calendar.events), err)
+		}
+		calendar.status.Error = err.Error()
+		return
+	}
+	calendar.status.Error = ""
+	calendar.status.Refreshed = now
+	calendar.etag, calendar.lastModified = etag, lastModified
+	if data != nil {
+		calendar.events = calendar.maintenanceEvents(events)
+		calendar.status.Events = len(calendar.events)
+		calendar.status.Skipped = skipped
+	}
+	calendar.windows = calendar.expand(now)
+}
+
+// fetch 캘린더 내려받기 (바뀌지 않았으면 data 가 nil)
+func (ms *MaintenanceSchedule) fetch(rawURL, etag, lastModified string) ([]byte, string, string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", "", err
+	}
+	switch parsed.Scheme {
+	case "file":
+		data, err := os.ReadFile(parsed.Path)
+		return data, "", "", err
+	case "webcal":
+		parsed.Scheme = "https"
+	}
+
+	request, err := http.NewRequest(http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, "", "", err
+	}
+	request.Header.Set("Accept", "text/calendar")
+	request.Header.Set("User-Agent", AppName+"/"+AppVersion)
+	if etag != "" {
+		request.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		request.Header.Set("If-Modified-Since", lastModified)
+	}
+	resp, err := ms.client.Do(request)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("%s: %v", redactURL(rawURL), unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil, etag, lastModified, nil
+	case http.StatusOK:
+	default:
+		return nil, "", "", fmt.Errorf("%s returned status %d", redactURL(rawURL), resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaintenanceCalendarMaxSize+1))
+	if err != nil {
+		return nil, "", "", err
+	}
+	if len(data) > MaintenanceCalendarMaxSize {
+		return nil, "", "", fmt.Errorf("calendar is larger than %d bytes", MaintenanceCalendarMaxSize)
+	}
+	return data, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), nil
+}
+
+// unwrapURLError url.Error 의 원인 (URL 의 비밀 쿼리를 로그에 남기지 않기 위함)
+func unwrapURLError(err error) error {
+	if urlErr, ok := err.(*url.Error); ok {
+		return urlErr.Err
+	}
+	return err
+}
+
+// maintenanceEvents event_filter 와 일치하는 일정만 (비우면 모든 일정)
+func (mc *maintenanceCalendar) maintenanceEvents(events []icalEvent) []icalEvent {
+	if mc.filter == nil {
+		return events
+	}
+	var matched []icalEvent
+	for _, event := range events {
+		text := strings.Join(append([]string{event.Summary, event.Description, event.Location}, event.Categories...), "\n")
+		if mc.filter.MatchString(text) {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// expand now 기준 과거 7일 ~ 미래 30일의 점검 회차 (시작 순)
+func (mc *maintenanceCalendar) expand(now time.Time) []MaintenanceWindow {
+	var windows []MaintenanceWindow
+	for i := range mc.events {
+		event := &mc.events[i]
+		summary := event.Summary
+		if summary == "" {
+			summary = "(제목 없음)"
+		}
+		for _, occurrence := range event.Occurrences(now.Add(-MaintenanceLookBehind), now.Add(MaintenanceLookAhead)) {
+			windows = append(windows, MaintenanceWindow{
+				Calendar: mc.config.Name,
+				Summary:  summary,
+				Start:    occurrence.Start,
+				End:      occurrence.End,
+				Action:   mc.config.Action,
+			})
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Start.Before(windows[j].Start) })
+	return windows
+}
+
+// Apply 진행 중인 점검에 해당하는 알림 처리
+// downgrade 면 심각도를 낮춘 알림을, batch 면 held=true 를 반환 (요약 알림은 일정이 끝날 때 전송)
+func (ms *MaintenanceSchedule) Apply(alert Alert, now time.Time) (Alert, bool) {
+	if ms == nil || alert.Type == AlertTypeMaintenance {
+		return alert, false
+	}
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	for _, calendar := range ms.calendars {
+		if !calendar.match.matches(alert) {
+			continue
+		}
+		for _, window := range calendar.windows {
+			if window.Start.After(now) {
+				break
+			}
+			if !now.Before(window.End) {
+				continue
+			}
+			if window.Action == MaintenanceBatch {
+				ms.hold(window, alert, now)
+				return alert, true
+			}
+			return downgradeMaintenanceAlert(alert, window), false
+		}
+	}
+	return alert, false
+}
+
+// hold 점검 회차별 묶음에 알림 추가 (호출자가 Lock 보유)
+func (ms *MaintenanceSchedule) hold(window MaintenanceWindow, alert Alert, now time.Time) {
+	key := fmt.Sprintf("%s\x00%s\x00%d", window.Calendar, window.Summary, window.Start.Unix())
+	batch := ms.batches[key]
+	if batch == nil {
+		batch = &maintenanceBatch{window: window, counts: make(map[string]int), types: make(map[string]int)}
+		ms.batches[key] = batch
+		batch.timer = time.AfterFunc(window.End.Sub(now), func() { ms.flushBatch(key, batch) })
+	}
+	if len(batch.alerts) < MaintenanceBatchListLimit {
+		batch.alerts = append(batch.alerts, alert)
+	}
+	batch.total++
+	batch.counts[alert.Severity]++
+	batch.types[alert.Type]++
+}
+
+// flushBatch 점검이 끝난 묶음의 요약 알림 전송
+func (ms *MaintenanceSchedule) flushBatch(key string, batch *maintenanceBatch) {
+	ms.mutex.Lock()
+	if ms.batches[key] != batch {
+		ms.mutex.Unlock()
+		return
+	}
+	delete(ms.batches, key)
+	ms.mutex.Unlock()
+	ms.flush(maintenanceBatchAlert(batch))
+}
+
+// Windows 진행 중인 점검과 limit 건까지의 다가오는 점검
+func (ms *MaintenanceSchedule) Windows(now time.Time, limit int) (active, upcoming []MaintenanceWindow) {
+	if ms == nil {
+		return nil, nil
+	}
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	for _, calendar := range ms.calendars {
+		for _, window := range calendar.windows {
+			switch {
+			case !now.Before(window.End):
+			case window.Start.After(now):
+				upcoming = append(upcoming, window)
+			default:
+				active = append(active, window)
+			}
+		}
+	}
+	sort.Slice(upcoming, func(i, j int) bool { return upcoming[i].Start.Before(upcoming[j].Start) })
+	if len(upcoming) > limit {
+		upcoming = upcoming[:limit]
+	}
+	return active, upcoming
+}
+
+// Status 캘린더별 가져오기 상태 (설정 순서)
+func (ms *MaintenanceSchedule) Status() []MaintenanceCalendarStatus {
+	if ms == nil {
+		return nil
+	}
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+	statuses := make([]MaintenanceCalendarStatus, len(ms.calendars))
+	for i, calendar := range ms.calendars {
+		statuses[i] = calendar.status
+	}
+	return statuses
+}
+
+// downgradeMaintenanceAlert 점검 중 알림의 심각도를 한 단계 낮추고 점검 일정 표시
+func downgradeMaintenanceAlert(alert Alert, window MaintenanceWindow) Alert {
+	original := alert.Severity
+	switch alert.Severity {
+	case AlertSeverityCritical:
+		alert.Severity = AlertSeverityWarning
+	case AlertSeverityWarning:
+		alert.Severity = AlertSeverityInfo
+	}
+	alert.Title = "[점검 중] " + alert.Title
+
+	fields := make(map[string]string, len(alert.Fields)+2)
+	for key, value := range alert.Fields {
+		fields[key] = value
+	}
+	fields["maintenance"] = window.Summary
+	fields["original_severity"] = original
+	alert.Fields = fields
+
+	note := fmt.Sprintf("%s (%s ~ %s, %s) 점검 중이라 심각도를 %s → %s 로 낮췄습니다.",
+		window.Summary, window.Start.Format("01-02 15:04"), window.End.Format("01-02 15:04"), window.Calendar, original, alert.Severity)
+	if len(alert.Sections) > 0 {
+		alert.Sections = append(append([]AlertSection(nil), alert.Sections...), AlertSection{Title: "🛠️ 예정된 점검", Text: note, Summary: true})
+	} else {
+		alert.Body = strings.TrimRight(alert.Body, "\n") + "\n\n🛠️ " + note
+	}
+	return alert
+}
+
+// maintenanceBatchAlert 점검 중 모아 둔 알림의 요약 알림
+func maintenanceBatchAlert(batch *maintenanceBatch) Alert {
+	window := batch.window
+	severity := AlertSeverityInfo
+	if batch.counts[AlertSeverityCritical] > 0 || batch.counts[AlertSeverityWarning] > 0 {
+		severity = AlertSeverityWarning
+	}
+
+	var list strings.Builder
+	for _, alert := range batch.alerts {
+		fmt.Fprintf(&list, "%s [%s] %s\n", alert.Timestamp.Format("15:04:05"), strings.ToUpper(alert.Severity), alert.Title)
+	}
+	if batch.total > len(batch.alerts) {
+		fmt.Fprintf(&list, "... 외 %d건\n", batch.total-len(batch.alerts))
+	}
+
+	period := fmt.Sprintf("%s ~ %s", window.Start.Format("2006-01-02 15:04"), window.End.Format("15:04"))
+	return Alert{
+		Type:     AlertTypeMaintenance,
+		Severity: severity,
+		Title:    fmt.Sprintf("[%s 점검] %s: 점검 중 보류한 알림 %d건", AppName, window.Summary, batch.total),
+		Headline: fmt.Sprintf("🛠️ 점검 일정 %s 동안 보내지 않은 알림 %d건", window.Summary, batch.total),
+		Sections: []AlertSection{
+			{Fields: []AlertField{
+				{Label: "🛠️ 점검", Value: window.Summary, Short: true},
+				{Label: "📅 캘린더", Value: window.Calendar, Short: true},
+				{Label: "🕐 기간", Value: period, Short: true},
+				{Label: "📊 심각도별", Value: formatCountMap(batch.counts), Short: true},
+				{Label: "🏷️ 유형별", Value: formatCountMap(batch.types)},
+			}, Summary: true},
+			{Title: "📋 보류한 알림", Text: list.String()},
+		},
+		Thread: alertThreadKey(AlertTypeMaintenance, window.Calendar, window.Summary),
+		Fields: map[string]string{
+			"calendar":    window.Calendar,
+			"maintenance": window.Summary,
+			"held":        fmt.Sprintf("%d", batch.total),
+		},
+	}
+}
+
+// formatCountMap "critical 2, warning 5" 형식 (건수 내림차순, 같으면 이름순)
+func formatCountMap(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s %d", key, counts[key])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// equalMaintenanceConfig 점검 일정 설정이 같은지 비교 (설정 재로드 시 바뀐 경우에만 교체)
+func equalMaintenanceConfig(a, b MaintenanceConfig) bool {
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,766 @@
+/*
+ModSecurity Audit Log Module
+============================
+
+ModSecurity 감사 로그 수집과 웹 공격 알림 (-modsec-audit-log)
+
+주요 기능:
+- 네이티브(Serial) 감사 로그: --경계-A-- 부터 --경계-Z-- 까지를 한 트랜잭션으로 조립
+- JSON 감사 로그: ModSecurity 2.9 (SecAuditLogFormat JSON) 와 libmodsecurity 3 형식
+- 규칙 ID, 메시지, 심각도, 태그, 일치한 페이로드(Matched Data), 이상 점수(Total Score), 차단 여부 추출
+- 이상 점수 10 이상이거나 차단되지 않은 CRITICAL 규칙 일치는 CRITICAL, 그 밖의 규칙 일치는 HIGH 알림
+- 같은 트랜잭션의 접근 로그 줄(unique_id 또는 클라이언트/메서드/URI 일치)을 찾아 알림에 함께 표시
+- 같은 클라이언트의 알림은 5분에 한 번 (나머지는 다음 알림에 횟수로 표시)
+
+감사 로그와 접근 로그는 요청이 끝난 뒤 거의 동시에 기록되므로, 접근 로그 줄이 아직 없으면 잠시 기다렸다가 알림
+*/
+package main
+
+import (
+	"encoding/json" // JSON 감사 로그 파싱
+	"fmt"           // 형식화된 I/O
+	"os"            // 호스트 이름
+	"regexp"        // 섹션 경계/메시지 태그 패턴
+	"sort"          // 태그 정렬
+	"strconv"       // 숫자 변환
+	"strings"       // 문자열 처리
+	"time"          // 상관 분석 윈도우
+)
+
+// ModSecurity 위협 수준
+const (
+	ModSecurityThreatHigh     = "HIGH"
+	ModSecurityThreatCritical = "CRITICAL"
+)
+
+// ModSecurity 상관 분석 기본값
+const (
+	ModSecurityCriticalScore  = 10               // 이 이상의 이상 점수는 CRITICAL (CRS 기본 차단 임계값 5 의 두 배)
+	modSecurityWindow         = 60 * time.Second // 감사 로그와 접근 로그를 묶는 윈도우
+	modSecurityAccessWait     = 3 * time.Second  // 접근 로그 줄을 기다리는 시간
+	modSecurityAlertInterval  = 5 * time.Minute  // 같은 클라이언트 알림 간격
+	maxModSecurityAccesses    = 1000
+	maxModSecurityPayloadSize = 300
+)
+
+var (
+	modSecurityBoundaryRegex = regexp.MustCompile(`^--([0-9A-Za-z]+)-([A-Z])--$`)
+	modSecurityTagRegex      = regexp.MustCompile(`\[(\w+) "((?:[^"\\]|\\.)*)"\]`)
+	modSecurityScoreRegex    = regexp.MustCompile(`Total (?:Inbound )?Score: (\d+)`)
+	modSecurityStatusRegex   = regexp.MustCompile(`^HTTP/\S+ (\d{3})`)
+)
+
+// modSecuritySeverityNames 숫자 심각도 (libmodsecurity 3 JSON) → 이름
+var modSecuritySeverityNames = []string{"EMERGENCY", "ALERT", "CRITICAL", "ERROR", "WARNING", "NOTICE", "INFO", "DEBUG"}
+
+// ModSecurityRule 트랜잭션에서 일치한 규칙
+type ModSecurityRule struct {
+	ID       string   `json:"id"`
+	Message  string   `json:"msg"`
+	Data     string   `json:"data,omitempty"` // 일치한 페이로드 (Matched Data: ... found within ARGS:...)
+	Severity string   `json:"severity,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// ModSecurityTransaction 감사 로그 한 건 (요청 하나)
+type ModSecurityTransaction struct {
+	ID           string            `json:"unique_id"`
+	Timestamp    time.Time         `json:"timestamp"`
+	Client       string            `json:"client_ip"`
+	Method       string            `json:"method"`
+	URI          string            `json:"uri"`
+	Protocol     string            `json:"protocol"`
+	Host         string            `json:"host,omitempty"`
+	UserAgent    string            `json:"user_agent,omitempty"`
+	Status       int               `json:"status"`
+	Intercepted  bool              `json:"intercepted"`
+	AnomalyScore int               `json:"anomaly_score"`
+	Rules        []ModSecurityRule `json:"rules"`
+	Raw          string            `json:"-"`
+}
+
+// ModSecurityAuditReader 감사 로그 줄을 트랜잭션 단위로 조립 (tail 고루틴에서만 사용)
+type ModSecurityAuditReader struct {
+	boundary string
+	lines    []string
+}
+
+// Add 줄 추가, 트랜잭션이 끝났으면 전체 엔트리 반환 (JSON 형식은 한 줄이 한 트랜잭션)
+func (r *ModSecurityAuditReader) Add(line string) (string, bool) {
+	if len(r.lines) == 0 && strings.HasPrefix(line, "{") {
+		return line, true
+	}
+	matches := modSecurityBoundaryRegex.FindStringSubmatch(strings.TrimSpace(line))
+	switch {
+	case matches != nil && matches[2] == "A":
+		r.boundary = matches[1]
+		r.lines = []string{line}
+	case len(r.lines) == 0:
+		// 트랜잭션 시작 전의 줄 (tail 시작 위치가 트랜잭션 중간인 경우)
+	case matches != nil && matches[1] == r.boundary && matches[2] == "Z":
+		entry := strings.Join(r.lines, "\n")
+		r.lines = nil
+		return entry, true
+	default:
+		r.lines = append(r.lines, line)
+	}
+	return "", false
+}
+
+// ParseModSecurityAudit 네이티브 또는 JSON 감사 로그 엔트리 파싱
+func ParseModSecurityAudit(entry string) (*ModSecurityTransaction, bool) {
+	entry = strings.TrimSpace(entry)
+	if strings.HasPrefix(entry, "{") {
+		return parseModSecurityJSON(entry)
+	}
+	return parseModSecurityNative(entry)
+}
+
+// parseModSecurityNative 네이티브(Serial) 형식 파싱
+// A: [시각] unique_id 클라이언트IP 포트 서버IP 포트, B: 요청 줄과 헤더, F: 응답 상태 줄, H: Message:/Action: 줄
+func parseModSecurityNative(entry string) (*ModSecurityTransaction, bool) {
+	lines := strings.Split(entry, "\n")
+	if len(lines) == 0 || !modSecurityBoundaryRegex.MatchString(strings.TrimSpace(lines[0])) {
+		return nil, false
+	}
+
+	tx := &ModSecurityTransaction{Raw: entry}
+	section := ""
+	sectionLine := 0
+	for _, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if matches := modSecurityBoundaryRegex.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+			section, sectionLine = matches[2], 0
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		sectionLine++
+
+		switch section {
+		case "A":
+			if sectionLine == 1 {
+				parseModSecurityAuditHeader(tx, line)
+			}
+		case "B":
+			if sectionLine == 1 {
+				if request := strings.Fields(line); len(request) >= 2 {
+					tx.Method, tx.URI = request[0], request[1]
+					if len(request) > 2 {
+						tx.Protocol = request[2]
+					}
+				}
+			} else if name, value, ok := strings.Cut(line, ":"); ok {
+				switch strings.ToLower(name) {
+				case "host":
+					tx.Host = strings.TrimSpace(value)
+				case "user-agent":
+					tx.UserAgent = strings.TrimSpace(value)
+				}
+			}
+		case "F":
+			if matches := modSecurityStatusRegex.FindStringSubmatch(line); matches != nil && sectionLine == 1 {
+				tx.Status, _ = strconv.Atoi(matches[1])
+			}
+		case "H":
+			switch {
+			case strings.HasPrefix(line, "Action: Intercepted"):
+				tx.Intercepted = true
+			case strings.Contains(line, `[id "`):
+				tx.addMessage(line)
+			}
+		}
+	}
+	if tx.Client == "" && tx.Method == "" {
+		return nil, false
+	}
+	tx.finish()
+	return tx, true
+}
+
+// parseModSecurityAuditHeader A 섹션 첫 줄 파싱 ([16/Oct/2026:10:00:00 +0900] uniqueid 203.0.113.5 54321 10.0.0.1 443)
+func parseModSecurityAuditHeader(tx *ModSecurityTransaction, line string) {
+	end := strings.Index(line, "]")
+	if !strings.HasPrefix(line, "[") || end < 0 {
+		return
+	}
+	for _, layout := range []string{"02/Jan/2006:15:04:05 -0700", "02/Jan/2006:15:04:05.000000 -0700"} {
+		if timestamp, err := time.Parse(layout, line[1:end]); err == nil {
+			tx.Timestamp = timestamp
+			break
+		}
+	}
+	if fields := strings.Fields(line[end+1:]); len(fields) >= 2 {
+		tx.ID, tx.Client = fields[0], fields[1]
+	}
+}
+
+// addMessage "Message: Warning. ... [id "942100"] [msg "..."] [data "..."] [severity "CRITICAL"] [tag "..."]" 한 줄을 규칙으로 추가
+func (tx *ModSecurityTransaction) addMessage(line string) {
+	rule := ModSecurityRule{}
+	for _, match := range modSecurityTagRegex.FindAllStringSubmatch(line, -1) {
+		value := strings.ReplaceAll(match[2], `\"`, `"`)
+		switch match[1] {
+		case "id":
+			rule.ID = value
+		case "msg":
+			rule.Message = value
+		case "data":
+			rule.Data = value
+		case "severity":
+			rule.Severity = normalizeModSecuritySeverity(value)
+		case "tag":
+			rule.Tags = append(rule.Tags, value)
+		}
+	}
+	if strings.Contains(line, "Access denied") {
+		tx.Intercepted = true
+	}
+	tx.addRule(rule, line)
+}
+
+// addRule 규칙 추가 (메시지나 데이터의 Total Score 로 이상 점수 갱신)
+func (tx *ModSecurityTransaction) addRule(rule ModSecurityRule, text string) {
+	if rule.ID == "" {
+		return
+	}
+	for _, source := range []string{rule.Message, rule.Data, text} {
+		if matches := modSecurityScoreRegex.FindStringSubmatch(source); matches != nil {
+			if score, _ := strconv.Atoi(matches[1]); score > tx.AnomalyScore {
+				tx.AnomalyScore = score
+			}
+			break
+		}
+	}
+	if len(rule.Data) > maxModSecurityPayloadSize {
+		rule.Data = rule.Data[:maxModSecurityPayloadSize] + "..."
+	}
+	tx.Rules = append(tx.Rules, rule)
+}
+
+// finish 차단 평가 규칙(949xxx/959xxx)이 있고 오류 상태 코드이면 차단된 것으로 판단
+func (tx *ModSecurityTransaction) finish() {
+	if tx.Intercepted || tx.Status < 400 {
+		return
+	}
+	for _, rule := range tx.Rules {
+		if strings.HasPrefix(rule.ID, "949") || strings.HasPrefix(rule.ID, "959") {
+			tx.Intercepted = true
+			return
+		}
+	}
+}
+
+// modSecurityJSONRule libmodsecurity 3 JSON 의 messages 항목
+type modSecurityJSONRule struct {
+	Message string `json:"message"`
+	Details struct {
+		RuleID   string   `json:"ruleId"`
+		Data     string   `json:"data"`
+		Severity string   `json:"severity"`
+		Tags     []string `json:"tags"`
+	} `json:"details"`
+}
+
+// modSecurityJSONRecord ModSecurity 2.9 와 libmodsecurity 3 JSON 감사 로그 공통 구조
+type modSecurityJSONRecord struct {
+	Transaction struct {
+		// libmodsecurity 3
+		ClientIP  string `json:"client_ip"`
+		TimeStamp string `json:"time_stamp"`
+		UniqueID  string `json:"unique_id"`
+		Request   struct {
+			Method      string            `json:"method"`
+			URI         string            `json:"uri"`
+			HTTPVersion float64           `json:"http_version"`
+			Headers     map[string]string `json:"headers"`
+		} `json:"request"`
+		Response struct {
+			HTTPCode int `json:"http_code"`
+		} `json:"response"`
+		Messages []modSecurityJSONRule `json:"messages"`
+
+		// ModSecurity 2.9
+		Time          string `json:"time"`
+		TransactionID string `json:"transaction_id"`
+		RemoteAddress string `json:"remote_address"`
+	} `json:"transaction"`
+
+	// ModSecurity 2.9
+	Request struct {
+		RequestLine string            `json:"request_line"`
+		Headers     map[string]string `json:"headers"`
+	} `json:"request"`
+	Response struct {
+		Status int `json:"status"`
+	} `json:"response"`
+	AuditData struct {
+		Messages []string `json:"messages"`
+		Action   struct {
+			Intercepted bool `json:"intercepted"`
+		} `json:"action"`
+	} `json:"audit_data"`
+}
+
+// parseModSecurityJSON JSON 감사 로그 한 줄 파싱
+func parseModSecurityJSON(line string) (*ModSecurityTransaction, bool) {
+	if !strings.Contains(line, `"transaction"`) {
+		return nil, false
+	}
+	var record modSecurityJSONRecord
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return nil, false
+	}
+
+	tx := &ModSecurityTransaction{Raw: line}
+	if record.Transaction.ClientIP != "" {
+		// libmodsecurity 3
+		request := record.Transaction.Request
+		tx.ID = record.Transaction.UniqueID
+		tx.Client = record.Transaction.ClientIP
+		tx.Timestamp, _ = time.ParseInLocation(time.ANSIC, record.Transaction.TimeStamp, time.Local)
+		tx.Method, tx.URI = request.Method, request.URI
+		if request.HTTPVersion > 0 {
+			tx.Protocol = "HTTP/" + strconv.FormatFloat(request.HTTPVersion, 'f', -1, 64)
+		}
+		tx.Host, tx.UserAgent = modSecurityHeader(request.Headers, "Host"), modSecurityHeader(request.Headers, "User-Agent")
+		tx.Status = record.Transaction.Response.HTTPCode
+		for _, message := range record.Transaction.Messages {
+			tx.addRule(ModSecurityRule{
+				ID:       message.Details.RuleID,
+				Message:  message.Message,
+				Data:     message.Details.Data,
+				Severity: normalizeModSecuritySeverity(message.Details.Severity),
+				Tags:     message.Details.Tags,
+			}, "")
+		}
+	} else if record.Transaction.RemoteAddress != "" {
+		// ModSecurity 2.9
+		tx.ID = record.Transaction.TransactionID
+		tx.Client = record.Transaction.RemoteAddress
+		tx.Timestamp, _ = time.Parse("02/Jan/2006:15:04:05 -0700", record.Transaction.Time)
+		if request := strings.Fields(record.Request.RequestLine); len(request) >= 2 {
+			tx.Method, tx.URI = request[0], request[1]
+			if len(request) > 2 {
+				tx.Protocol = request[2]
+			}
+		}
+		tx.Host, tx.UserAgent = modSecurityHeader(record.Request.Headers, "Host"), modSecurityHeader(record.Request.Headers, "User-Agent")
+		tx.Status = record.Response.Status
+		tx.Intercepted = record.AuditData.Action.Intercepted
+		for _, message := range record.AuditData.Messages {
+			tx.addMessage(message)
+		}
+	} else {
+		return nil, false
+	}
+	tx.finish()
+	return tx, true
+}
+
+// modSecurityHeader 대소문자를 구분하지 않고 요청 헤더 조회
+func modSecurityHeader(headers map[string]string, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// normalizeModSecuritySeverity 숫자 심각도(0~7)를 이름으로 변환
+func normalizeModSecuritySeverity(severity string) string {
+	if level, err := strconv.Atoi(severity); err == nil && level >= 0 && level < len(modSecuritySeverityNames) {
+		return modSecuritySeverityNames[level]
+	}
+	return strings.ToUpper(severity)
+}
+
+// isModSecurityScoringRule CRS 차단 평가/보고 규칙 (949xxx, 959xxx, 980xxx) 인지 확인
+func isModSecurityScoringRule(id string) bool {
+	return strings.HasPrefix(id, "949") || strings.HasPrefix(id, "959") || strings.HasPrefix(id, "980")
+}
+
+// AttackRules 공격 탐지 규칙 (점수 평가/보고 규칙 제외)
+func (tx *ModSecurityTransaction) AttackRules() []ModSecurityRule {
+	var rules []ModSecurityRule
+	for _, rule := range tx.Rules {
+		if !isModSecurityScoringRule(rule.ID) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// RuleIDs 일치한 모든 규칙 ID
+func (tx *ModSecurityTransaction) RuleIDs() []string {
+	ids := make([]string, 0, len(tx.Rules))
+	for _, rule := range tx.Rules {
+		ids = append(ids, rule.ID)
+	}
+	return ids
+}
+
+// Tags 공격 규칙의 태그 (중복 제거, attack-* 만)
+func (tx *ModSecurityTransaction) Tags() []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, rule := range tx.AttackRules() {
+		for _, tag := range rule.Tags {
+			if strings.HasPrefix(tag, "attack-") && !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// ThreatLevel 위협 수준 (규칙 일치가 없으면 빈 문자열)
+// 이상 점수가 ModSecurityCriticalScore 이상이거나, 차단되지 않고 CRITICAL 이상 규칙에 일치하면 (공격이 애플리케이션까지 도달) CRITICAL
+func (tx *ModSecurityTransaction) ThreatLevel() string {
+	rules := tx.AttackRules()
+	if len(rules) == 0 && tx.AnomalyScore == 0 {
+		return ""
+	}
+	if tx.AnomalyScore >= ModSecurityCriticalScore {
+		return ModSecurityThreatCritical
+	}
+	if !tx.Intercepted {
+		for _, rule := range rules {
+			switch rule.Severity {
+			case "EMERGENCY", "ALERT", "CRITICAL":
+				return ModSecurityThreatCritical
+			}
+		}
+	}
+	return ModSecurityThreatHigh
+}
+
+// ParsedLog 다른 출력(Elasticsearch, Kafka, 구조화 출력)과 같은 ParsedLog 로 변환
+func (tx *ModSecurityTransaction) ParsedLog() *ParsedLog {
+	parsed := &ParsedLog{
+		Timestamp: tx.Timestamp,
+		LogType:   "modsecurity",
+		Level:     "INFO",
+		Source:    "modsecurity",
+		RawLog:    tx.Raw,
+		Fields: map[string]string{
+			"unique_id":     tx.ID,
+			"client_ip":     tx.Client,
+			"rule_ids":      strings.Join(tx.RuleIDs(), ","),
+			"anomaly_score": strconv.Itoa(tx.AnomalyScore),
+			"intercepted":   strconv.FormatBool(tx.Intercepted),
+			"tags":          strings.Join(tx.Tags(), ","),
+		},
+		HTTPDetails: &HTTPLogDetails{
+			Method:     tx.Method,
+			URL:        tx.URI,
+			StatusCode: tx.Status,
+			UserAgent:  tx.UserAgent,
+			ClientIP:   tx.Client,
+			Protocol:   tx.Protocol,
+			Host:       tx.Host,
+		},
+	}
+	if parsed.Timestamp.IsZero() {
+		parsed.Timestamp = time.Now()
+	}
+
+	rules := tx.AttackRules()
+	parsed.Message = fmt.Sprintf("ModSecurity %s %s - %d (score %d)", tx.Method, tx.URI, tx.Status, tx.AnomalyScore)
+	if len(rules) > 0 {
+		parsed.Message = fmt.Sprintf("ModSecurity [%s] %s: %s %s - %d", rules[0].ID, rules[0].Message, tx.Method, tx.URI, tx.Status)
+		parsed.Fields["matched_data"] = rules[0].Data
+	}
+	switch tx.ThreatLevel() {
+	case ModSecurityThreatCritical:
+		parsed.Level = "CRITICAL"
+	case ModSecurityThreatHigh:
+		parsed.Level = "WARNING"
+	}
+	if parsed.Level != "INFO" {
+		errorCode := ""
+		if len(rules) > 0 {
+			errorCode = rules[0].ID
+		}
+		parsed.ErrorDetails = &ErrorDetails{ErrorType: "WAF", ErrorCode: errorCode, Module: "modsecurity"}
+	}
+	return parsed
+}
+
+// ModSecurityLogParser 감사 로그가 -file 입력으로 들어올 때의 파서 (JSON 한 줄 또는 조립된 네이티브 엔트리)
+type ModSecurityLogParser struct{}
+
+// NewModSecurityLogParser ModSecurity 로그 파서 생성
+func NewModSecurityLogParser() *ModSecurityLogParser {
+	return &ModSecurityLogParser{}
+}
+
+// Parse ModSecurity 감사 로그 파싱
+func (p *ModSecurityLogParser) Parse(line string) (*ParsedLog, error) {
+	tx, ok := ParseModSecurityAudit(line)
+	if !ok {
+		return nil, fmt.Errorf("not a ModSecurity audit log entry")
+	}
+	return tx.ParsedLog(), nil
+}
+
+// GetLogType 로그 타입 반환
+func (p *ModSecurityLogParser) GetLogType() string {
+	return "modsecurity"
+}
+
+// DetectFormat 포맷 감지
+func (p *ModSecurityLogParser) DetectFormat(line string) bool {
+	if strings.HasPrefix(line, "{") {
+		return strings.Contains(line, `"transaction"`) && (strings.Contains(line, `"messages"`) || strings.Contains(line, `"audit_data"`))
+	}
+	first, _ := splitMultiline(line)
+	matches := modSecurityBoundaryRegex.FindStringSubmatch(strings.TrimSpace(first))
+	return matches != nil && matches[2] == "A"
+}
+
+// modSecurityAccess 상관 분석용 접근 로그 줄
+type modSecurityAccess struct {
+	At     time.Time
+	Client string
+	Method string
+	URL    string
+	Status int
+	Line   string
+	used   bool
+}
+
+// ModSecurityDetection 알림 대상 트랜잭션과 같은 요청의 접근 로그 줄
+type ModSecurityDetection struct {
+	Transaction *ModSecurityTransaction
+	ThreatLevel string             // HIGH, CRITICAL
+	Access      *modSecurityAccess // 같은 요청의 접근 로그 (찾지 못하면 nil)
+	Link        string             // unique_id (접근 로그에 UNIQUE_ID 포함), request (클라이언트/메서드/URI 일치)
+	Suppressed  int                // 이전 알림 이후 알리지 않은 탐지 수
+	ShouldAlert bool               // 클라이언트별 알림 간격 통과 여부
+	received    time.Time
+}
+
+// ModSecurityCorrelator 감사 로그 트랜잭션과 접근 로그 상관 분석기 (처리 고루틴에서만 사용)
+type ModSecurityCorrelator struct {
+	accesses   []*modSecurityAccess    // 윈도우 내 접근 로그 (오래된 순)
+	pending    []*ModSecurityDetection // 접근 로그 줄을 기다리는 탐지
+	alerts     map[string]time.Time    // 클라이언트별 마지막 알림
+	suppressed map[string]int
+}
+
+// NewModSecurityCorrelator 상관 분석기 생성
+func NewModSecurityCorrelator() *ModSecurityCorrelator {
+	return &ModSecurityCorrelator{
+		alerts:     make(map[string]time.Time),
+		suppressed: make(map[string]int),
+	}
+}
+
+// ObserveAccess 웹 접근 로그 줄 기록 후 기다리던 탐지와 대조하여 완료된 탐지 반환
+func (c *ModSecurityCorrelator) ObserveAccess(parsedLog *ParsedLog, line string, at time.Time) []*ModSecurityDetection {
+	if parsedLog == nil || parsedLog.HTTPDetails == nil || parsedLog.LogType == "modsecurity" {
+		return nil
+	}
+	details := parsedLog.HTTPDetails
+	c.prune(at)
+	access := &modSecurityAccess{At: at, Client: details.ClientIP, Method: details.Method, URL: details.URL, Status: details.StatusCode, Line: line}
+	c.accesses = append(c.accesses, access)
+	if len(c.accesses) > maxModSecurityAccesses {
+		c.accesses = c.accesses[len(c.accesses)-maxModSecurityAccesses:]
+	}
+
+	var ready []*ModSecurityDetection
+	remaining := c.pending[:0]
+	for _, detection := range c.pending {
+		if link := modSecurityLink(detection.Transaction, access); link != "" {
+			access.used = true
+			detection.Access, detection.Link = access, link
+			ready = append(ready, c.finish(detection, at))
+			continue
+		}
+		remaining = append(remaining, detection)
+	}
+	c.pending = remaining
+	return ready
+}
+
+// AddTransaction 감사 로그 트랜잭션 추가
+// 규칙 일치가 없으면 (nil, false), 접근 로그 줄을 찾으면 (탐지, false), 찾지 못하면 대기 후 (nil, true)
+func (c *ModSecurityCorrelator) AddTransaction(tx *ModSecurityTransaction, at time.Time) (*ModSecurityDetection, bool) {
+	threat := tx.ThreatLevel()
+	if threat == "" {
+		return nil, false
+	}
+	c.prune(at)
+	detection := &ModSecurityDetection{Transaction: tx, ThreatLevel: threat, received: at}
+	for i := len(c.accesses) - 1; i >= 0; i-- {
+		access := c.accesses[i]
+		if access.used {
+			continue
+		}
+		if link := modSecurityLink(tx, access); link != "" {
+			access.used = true
+			detection.Access, detection.Link = access, link
+			return c.finish(detection, at), false
+		}
+	}
+	c.pending = append(c.pending, detection)
+	return nil, true
+}
+
+// ExpirePending 접근 로그 줄을 기다린 시간이 지난 탐지를 접근 로그 없이 완료
+func (c *ModSecurityCorrelator) ExpirePending(now time.Time) []*ModSecurityDetection {
+	var ready []*ModSecurityDetection
+	remaining := c.pending[:0]
+	for _, detection := range c.pending {
+		if now.Sub(detection.received) >= modSecurityAccessWait {
+			ready = append(ready, c.finish(detection, now))
+			continue
+		}
+		remaining = append(remaining, detection)
+	}
+	c.pending = remaining
+	return ready
+}
+
+// finish 클라이언트별 알림 간격 적용
+func (c *ModSecurityCorrelator) finish(detection *ModSecurityDetection, now time.Time) *ModSecurityDetection {
+	client := detection.Transaction.Client
+	if last, ok := c.alerts[client]; ok && now.Sub(last) < modSecurityAlertInterval {
+		c.suppressed[client]++
+		return detection
+	}
+	detection.ShouldAlert = true
+	detection.Suppressed = c.suppressed[client]
+	c.alerts[client] = now
+	delete(c.suppressed, client)
+	if len(c.alerts) > maxModSecurityAccesses {
+		for key, last := range c.alerts {
+			if now.Sub(last) >= modSecurityAlertInterval {
+				delete(c.alerts, key)
+				delete(c.suppressed, key)
+			}
+		}
+	}
+	return detection
+}
+
+// prune 윈도우가 지난 접근 로그 정리
+func (c *ModSecurityCorrelator) prune(now time.Time) {
+	keep := 0
+	for keep < len(c.accesses) && now.Sub(c.accesses[keep].At) > modSecurityWindow {
+		keep++
+	}
+	c.accesses = c.accesses[keep:]
+}
+
+// modSecurityLink 트랜잭션과 접근 로그 줄이 같은 요청인지 판단
+func modSecurityLink(tx *ModSecurityTransaction, access *modSecurityAccess) string {
+	if tx.ID != "" && len(tx.ID) >= 8 && strings.Contains(access.Line, tx.ID) {
+		return "unique_id"
+	}
+	if tx.Client == access.Client && tx.Method == access.Method && tx.URI == access.URL && tx.URI != "" {
+		return "request"
+	}
+	return ""
+}
+
+// modSecurityAlert ModSecurity 탐지 알림 (HIGH 는 warning, CRITICAL 은 critical)
+func modSecurityAlert(detection *ModSecurityDetection) Alert {
+	tx := detection.Transaction
+	host, _ := os.Hostname()
+	rules := tx.AttackRules()
+	if len(rules) == 0 {
+		rules = tx.Rules
+	}
+
+	severity := AlertSeverityWarning
+	emoji := "🛡️"
+	if detection.ThreatLevel == ModSecurityThreatCritical {
+		severity = AlertSeverityCritical
+		emoji = "🚨"
+	}
+	action := "탐지만 함 (차단 안 됨)"
+	if tx.Intercepted {
+		action = "차단됨"
+	}
+
+	ruleIDs := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	attack := strings.Join(tx.Tags(), ", ")
+	if attack == "" && len(rules) > 0 {
+		attack = rules[0].Message
+	}
+
+	fields := []AlertField{
+		{Label: "위협 수준", Value: detection.ThreatLevel, Short: true},
+		{Label: "클라이언트", Value: tx.Client, Short: true},
+		{Label: "이상 점수", Value: strconv.Itoa(tx.AnomalyScore), Short: true},
+		{Label: "조치", Value: action, Short: true},
+		{Label: "규칙 ID", Value: strings.Join(ruleIDs, ", "), Short: true},
+		{Label: "공격 유형", Value: attack, Short: true},
+		{Label: "요청", Value: fmt.Sprintf("%s %s (HTTP %d)", tx.Method, tx.URI, tx.Status), Code: true},
+	}
+	if detection.Suppressed > 0 {
+		fields = append(fields, AlertField{Label: "직전 알림 이후 추가 탐지", Value: fmt.Sprintf("%d회", detection.Suppressed)})
+	}
+
+	var ruleFields []AlertField
+	for _, rule := range rules {
+		value := rule.Message
+		if rule.Severity != "" {
+			value = fmt.Sprintf("%s (%s)", value, rule.Severity)
+		}
+		ruleFields = append(ruleFields, AlertField{Label: rule.ID, Value: value})
+		if rule.Data != "" {
+			ruleFields = append(ruleFields, AlertField{Label: "일치한 페이로드", Value: rule.Data, Code: true})
+		}
+	}
+
+	requestFields := []AlertField{
+		{Label: "Unique ID", Value: tx.ID, Short: true},
+		{Label: "Host", Value: tx.Host, Short: true},
+		{Label: "User-Agent", Value: tx.UserAgent},
+	}
+	accessLine := "같은 요청의 접근 로그를 찾지 못했습니다"
+	if detection.Access != nil {
+		link := "클라이언트/메서드/URI 일치"
+		if detection.Link == "unique_id" {
+			link = "UNIQUE_ID 일치"
+		}
+		accessLine = detection.Access.Line
+		requestFields = append(requestFields, AlertField{Label: "접근 로그 연결", Value: link, Short: true})
+	}
+	requestFields = append(requestFields, AlertField{Label: "접근 로그", Value: accessLine, Code: true})
+
+	return Alert{
+		Type:     AlertTypeWebAttack,
+		Severity: severity,
+		Title:    fmt.Sprintf("[%s WAF %s] %s - %s rule %s", AppName, detection.ThreatLevel, host, tx.Client, strings.Join(ruleIDs, ",")),
+		Headline: fmt.Sprintf("%s ModSecurity 가 %s 의 웹 공격을 탐지했습니다 (%s, 점수 %d, %s)", emoji, tx.Client, attack, tx.AnomalyScore, action),
+		Sections: []AlertSection{
+			{Fields: fields, Summary: true},
+			{Title: "📋 일치한 규칙", Fields: ruleFields},
+			{Title: "🌐 요청", Fields: requestFields},
+		},
+		Host:   host,
+		Thread: alertThreadKey(AlertTypeWebAttack, host, tx.Client),
+		Fields: map[string]string{
+			"client":        tx.Client,
+			"unique_id":     tx.ID,
+			"rule_ids":      strings.Join(tx.RuleIDs(), ","),
+			"anomaly_score": strconv.Itoa(tx.AnomalyScore),
+			"threat_level":  detection.ThreatLevel,
+			"intercepted":   strconv.FormatBool(tx.Intercepted),
+			"url":           tx.URI,
+			"access_link":   detection.Link,
+		},
+	}
+}
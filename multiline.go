@@ -0,0 +1,139 @@
+/*
+Multi-line Log Assembler Module
+===============================
+
+여러 줄에 걸친 로그 엔트리 조립
+
+Java 스택 트레이스나 MySQL 슬로우 쿼리 로그처럼 한 엔트리가 여러 줄에 걸친 로그를
+한 단위로 묶어 파서와 AIAnalyzer 에 전달합니다.
+
+주요 기능:
+- 시작 패턴: 정규식과 일치하는 줄에서 새 엔트리 시작, 일치하지 않는 줄은 이어지는 줄
+- 이어짐 규칙 indent: 공백/탭으로 들여쓴 줄과 "Caused by:" 줄 (Java 예외 체인)
+- 이어짐 규칙 hash: "# " 헤더 블록(MySQL 슬로우 쿼리)과 그 뒤의 쿼리 본문
+- 최대 줄 수 초과 또는 일정 시간 새 줄이 없으면 대기 중인 엔트리 배출
+- 파일 이름에 "slow" 가 들어간 슬로우 쿼리 로그는 옵션 없이 hash 규칙 자동 적용
+*/
+package main
+
+import (
+	"fmt"           // 형식화된 I/O
+	"path/filepath" // 로그 파일 이름 확인
+	"regexp"        // 시작 패턴 매칭
+	"strings"       // 문자열 처리
+	"time"          // 시간 처리
+)
+
+const (
+	// MultilineRuleIndent 들여쓴 줄과 Caused by: 줄을 이전 엔트리에 이어 붙임
+	MultilineRuleIndent = "indent"
+	// MultilineRuleHash "# " 헤더 줄과 뒤따르는 본문을 한 엔트리로 묶음
+	MultilineRuleHash = "hash"
+
+	// DefaultMultilineRules 기본 이어짐 규칙
+	DefaultMultilineRules = MultilineRuleIndent
+
+	multilineMaxLines     = 500             // 엔트리당 최대 줄 수 (초과 시 배출)
+	multilineFlushTimeout = 2 * time.Second // 새 줄 없이 이 시간이 지나면 대기 중인 엔트리 배출
+	multilineFlushCheck   = 500 * time.Millisecond
+)
+
+// MultilineAssembler 줄 단위 입력을 여러 줄 엔트리로 조립
+// 처리 고루틴에서만 사용하므로 잠금 없음
+type MultilineAssembler struct {
+	start   *regexp.Regexp // 새 엔트리 시작 패턴 (nil 이면 이어짐 규칙만 사용)
+	indent  bool
+	hash    bool
+	lines   []string
+	updated time.Time // 마지막 줄 추가 시각
+}
+
+// NewMultilineAssembler 시작 패턴과 이어짐 규칙으로 조립기 생성
+// startPattern 이 비어 있으면 rules 로만 이어지는 줄을 판단
+func NewMultilineAssembler(startPattern string, rules []string) (*MultilineAssembler, error) {
+	ma := &MultilineAssembler{}
+	if startPattern != "" {
+		start, err := regexp.Compile(startPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid multiline start pattern: %v", err)
+		}
+		ma.start = start
+	}
+	for _, rule := range rules {
+		switch strings.ToLower(strings.TrimSpace(rule)) {
+		case MultilineRuleIndent:
+			ma.indent = true
+		case MultilineRuleHash:
+			ma.hash = true
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown multiline rule %q (use %s, %s)", rule, MultilineRuleIndent, MultilineRuleHash)
+		}
+	}
+	if ma.start == nil && !ma.indent && !ma.hash {
+		return nil, fmt.Errorf("multiline needs a start pattern or at least one continuation rule")
+	}
+	return ma, nil
+}
+
+// Add 줄 추가, 이 줄로 이전 엔트리가 끝났으면 완성된 엔트리 반환
+func (ma *MultilineAssembler) Add(line string, now time.Time) (string, bool) {
+	var entry string
+	var complete bool
+	if len(ma.lines) > 0 && (!ma.continues(line) || len(ma.lines) >= multilineMaxLines) {
+		entry, complete = ma.Flush()
+	}
+	ma.lines = append(ma.lines, line)
+	ma.updated = now
+	return entry, complete
+}
+
+// FlushIdle 마지막 줄 이후 multilineFlushTimeout 이 지났으면 대기 중인 엔트리 반환
+// 파일의 마지막 엔트리는 다음 시작 줄이 오지 않으므로 시간으로 완료 판단
+func (ma *MultilineAssembler) FlushIdle(now time.Time) (string, bool) {
+	if len(ma.lines) == 0 || now.Sub(ma.updated) < multilineFlushTimeout {
+		return "", false
+	}
+	return ma.Flush()
+}
+
+// Flush 대기 중인 엔트리를 줄바꿈으로 이어 반환
+func (ma *MultilineAssembler) Flush() (string, bool) {
+	if len(ma.lines) == 0 {
+		return "", false
+	}
+	entry := strings.Join(ma.lines, "\n")
+	ma.lines = nil
+	return entry, true
+}
+
+// continues 줄이 대기 중인 엔트리에 이어지는지 판단
+func (ma *MultilineAssembler) continues(line string) bool {
+	if ma.indent && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "Caused by:")) {
+		return true
+	}
+	if ma.hash {
+		// "# " 줄은 헤더 블록 안에서만 이어지고, 헤더로 시작한 엔트리에는 본문 줄이 이어짐
+		// 본문 뒤에 다시 나오는 "# " 줄은 다음 엔트리의 시작
+		if strings.HasPrefix(line, "# ") {
+			return strings.HasPrefix(ma.lines[len(ma.lines)-1], "# ")
+		}
+		if strings.HasPrefix(ma.lines[0], "# ") {
+			return true
+		}
+	}
+	return ma.start != nil && !ma.start.MatchString(line)
+}
+
+// isSlowQueryLogPath 파일 이름으로 MySQL 슬로우 쿼리 로그인지 판단 (예: mysql-slow.log, slow-query.log)
+func isSlowQueryLogPath(path string) bool {
+	return strings.Contains(strings.ToLower(filepath.Base(path)), "slow")
+}
+
+// splitMultiline 여러 줄 엔트리를 첫 줄과 나머지 줄로 분리 (한 줄이면 rest 는 빈 문자열)
+func splitMultiline(entry string) (first, rest string) {
+	if index := strings.IndexByte(entry, '\n'); index >= 0 {
+		return entry[:index], entry[index+1:]
+	}
+	return entry, ""
+}
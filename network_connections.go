@@ -0,0 +1,350 @@
+/*
+Network Connection Monitoring Module
+====================================
+
+TCP 수신 대기 포트와 연결 수 모니터링 (시스템 모니터링)
+
+주요 기능:
+- 수신 대기(LISTEN) 소켓 목록과 프로세스, 전체/포트별 ESTABLISHED 연결 수 수집
+- 예상하지 않은 새 수신 대기 포트 알림 (시작 시 이미 열려 있던 포트와 expected_ports 는 예상된 포트)
+- 수신 대기 포트별 연결 수 급증 알림 (최근 수집 평균의 connection_spike 배 이상, connection_min 개 이상)
+- 정기 보고서와 AI 전문가 진단에 연결 현황 포함
+
+수집은 gopsutil 로 Linux(/proc/net), Windows(GetExtendedTcpTable), macOS(lsof) 모두 같은 방식입니다.
+다른 사용자의 프로세스 이름은 root/관리자 권한이 없으면 비어 있을 수 있습니다.
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"sort"    // 포트 정렬
+	"strings" // 문자열 처리
+	"syscall" // 주소 체계 (AF_INET6)
+	"time"    // 시간 처리
+
+	psnet "github.com/shirou/gopsutil/v4/net" // 소켓 목록
+	"github.com/shirou/gopsutil/v4/process"   // 소켓 소유 프로세스 이름
+)
+
+// 연결 수 급증 판정
+const (
+	connectionBaselineSamples = 12 // 포트별 평균 연결 수를 계산할 최근 수집 횟수
+	connectionMinSamples      = 3  // 급증 판정에 필요한 최소 수집 횟수
+	connectionReportPorts     = 5  // 보고서에 표시할 연결이 많은 포트 수
+)
+
+// ConnectionMetrics TCP 수신 대기 포트와 연결 현황
+type ConnectionMetrics struct {
+	Available   bool              `json:"available"`
+	Listening   []ListeningPort   `json:"listening"`            // 수신 대기 소켓 (포트순)
+	Established int               `json:"established"`          // 전체 ESTABLISHED 연결 수 (나가는 연결 포함)
+	Inbound     []PortConnections `json:"inbound"`              // 수신 대기 포트별 ESTABLISHED 연결 (많은 순)
+	Unexpected  []ListeningPort   `json:"unexpected,omitempty"` // 예상하지 않은 수신 대기 포트
+	Spikes      []PortConnections `json:"spikes,omitempty"`     // 연결 수가 급증한 포트
+}
+
+// ListeningPort 수신 대기 소켓
+type ListeningPort struct {
+	Protocol string `json:"protocol"` // tcp, tcp6
+	Address  string `json:"address"`
+	Port     uint32 `json:"port"`
+	PID      int32  `json:"pid,omitempty"`
+	Process  string `json:"process,omitempty"`
+}
+
+// PortConnections 수신 대기 포트 하나의 연결 수
+type PortConnections struct {
+	Port        uint32  `json:"port"`
+	Process     string  `json:"process,omitempty"`
+	Established int     `json:"established"`
+	Clients     int     `json:"clients"`           // 서로 다른 원격 IP 수
+	Average     float64 `json:"average,omitempty"` // 최근 수집 평균 (급증 판정 기준)
+}
+
+// String "tcp 0.0.0.0:22 (sshd)" 형식
+func (lp ListeningPort) String() string {
+	text := fmt.Sprintf("%s %s:%d", lp.Protocol, lp.Address, lp.Port)
+	if lp.Process != "" {
+		text += " (" + lp.Process + ")"
+	}
+	return text
+}
+
+// SetExpectedPorts 시작 시 열려 있지 않아도 알림하지 않을 수신 대기 포트
+func (sm *SystemMonitor) SetExpectedPorts(ports []int) {
+	expected := make(map[uint32]bool, len(ports))
+	for _, port := range ports {
+		if port > 0 && port <= 65535 {
+			expected[uint32(port)] = true
+		}
+	}
+	sm.expectedPorts = expected
+}
+
+// collectConnectionMetrics 수신 대기 포트, 포트별 연결 수 수집 후 예상 밖 포트/급증 표시
+func (sm *SystemMonitor) collectConnectionMetrics() {
+	connections, err := psnet.Connections("tcp")
+	if err != nil {
+		return
+	}
+
+	metrics := ConnectionMetrics{Available: true}
+	names := make(map[int32]string)
+	listening := make(map[uint32]string) // 포트 → 프로세스 이름
+	seen := make(map[string]bool)
+	for _, conn := range connections {
+		if conn.Status != "LISTEN" {
+			continue
+		}
+		protocol := "tcp"
+		if conn.Family == syscall.AF_INET6 {
+			protocol = "tcp6"
+		}
+		key := fmt.Sprintf("%s/%s/%d", protocol, conn.Laddr.IP, conn.Laddr.Port)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		port := ListeningPort{Protocol: protocol, Address: conn.Laddr.IP, Port: conn.Laddr.Port, PID: conn.Pid}
+		port.Process = processName(conn.Pid, names)
+		metrics.Listening = append(metrics.Listening, port)
+		if listening[port.Port] == "" {
+			listening[port.Port] = port.Process
+		}
+	}
+	sort.Slice(metrics.Listening, func(i, j int) bool {
+		if metrics.Listening[i].Port != metrics.Listening[j].Port {
+			return metrics.Listening[i].Port < metrics.Listening[j].Port
+		}
+		return metrics.Listening[i].Protocol < metrics.Listening[j].Protocol
+	})
+
+	// 수신 대기 포트로 들어온 ESTABLISHED 연결 집계
+	inbound := make(map[uint32]*PortConnections)
+	clients := make(map[uint32]map[string]bool)
+	for _, conn := range connections {
+		if conn.Status != "ESTABLISHED" {
+			continue
+		}
+		metrics.Established++
+		process, ok := listening[conn.Laddr.Port]
+		if !ok {
+			continue
+		}
+		entry := inbound[conn.Laddr.Port]
+		if entry == nil {
+			entry = &PortConnections{Port: conn.Laddr.Port, Process: process}
+			inbound[conn.Laddr.Port] = entry
+			clients[conn.Laddr.Port] = make(map[string]bool)
+		}
+		entry.Established++
+		clients[conn.Laddr.Port][conn.Raddr.IP] = true
+	}
+	for port, entry := range inbound {
+		entry.Clients = len(clients[port])
+		metrics.Inbound = append(metrics.Inbound, *entry)
+	}
+	sort.Slice(metrics.Inbound, func(i, j int) bool {
+		if metrics.Inbound[i].Established != metrics.Inbound[j].Established {
+			return metrics.Inbound[i].Established > metrics.Inbound[j].Established
+		}
+		return metrics.Inbound[i].Port < metrics.Inbound[j].Port
+	})
+
+	// 첫 수집의 수신 대기 포트를 예상된 포트로 기억
+	if sm.knownPorts == nil {
+		sm.knownPorts = make(map[uint32]bool, len(listening))
+		for port := range listening {
+			sm.knownPorts[port] = true
+		}
+	}
+	reported := make(map[uint32]bool)
+	for _, port := range metrics.Listening {
+		if sm.knownPorts[port.Port] || sm.expectedPorts[port.Port] || reported[port.Port] {
+			continue
+		}
+		reported[port.Port] = true
+		metrics.Unexpected = append(metrics.Unexpected, port)
+	}
+
+	metrics.Spikes = sm.connectionSpikes(metrics.Inbound)
+	sm.metrics.Connections = metrics
+}
+
+// processName PID 의 프로세스 이름 (조회 결과는 names 에 캐시, 권한이 없으면 빈 문자열)
+func processName(pid int32, names map[int32]string) string {
+	if pid <= 0 {
+		return ""
+	}
+	if name, ok := names[pid]; ok {
+		return name
+	}
+	name := ""
+	if proc, err := process.NewProcess(pid); err == nil {
+		name, _ = proc.Name()
+	}
+	names[pid] = name
+	return name
+}
+
+// connectionSpikes 최근 수집 평균보다 연결 수가 급증한 포트 (평균을 함께 기록)
+func (sm *SystemMonitor) connectionSpikes(inbound []PortConnections) []PortConnections {
+	if sm.thresholds.ConnectionSpike <= 0 {
+		return nil
+	}
+
+	var spikes []PortConnections
+	for _, current := range inbound {
+		if float64(current.Established) < sm.thresholds.ConnectionMin {
+			continue
+		}
+		total, samples := 0, 0
+		for i := len(sm.history) - 1; i >= 0 && samples < connectionBaselineSamples; i-- {
+			previous := sm.history[i].Connections
+			if !previous.Available {
+				continue
+			}
+			samples++
+			for _, entry := range previous.Inbound {
+				if entry.Port == current.Port {
+					total += entry.Established
+					break
+				}
+			}
+		}
+		if samples < connectionMinSamples {
+			continue
+		}
+		current.Average = float64(total) / float64(samples)
+		if float64(current.Established) >= current.Average*sm.thresholds.ConnectionSpike {
+			spikes = append(spikes, current)
+		}
+	}
+	return spikes
+}
+
+// checkConnectionAlerts 예상하지 않은 새 수신 대기 포트 (포트당 한 번), 포트별 연결 수 급증 알림
+func (sm *SystemMonitor) checkConnectionAlerts() {
+	connections := sm.metrics.Connections
+	if !connections.Available {
+		return
+	}
+
+	if sm.alertedPorts == nil {
+		sm.alertedPorts = make(map[uint32]bool)
+	}
+	for _, port := range connections.Unexpected {
+		if sm.alertedPorts[port.Port] {
+			continue
+		}
+		sm.alertedPorts[port.Port] = true
+		sm.sendAlert(SystemAlert{
+			Level:     "HIGH",
+			Type:      "LISTEN_PORT",
+			Message:   fmt.Sprintf("예상하지 않은 수신 대기 포트가 열렸습니다: %s", port),
+			Value:     float64(port.Port),
+			Metrics:   *sm.metrics,
+			Timestamp: time.Now(),
+			Suggestions: []string{
+				fmt.Sprintf("🔍 포트를 연 프로세스 확인: ss -ltnp 'sport = :%d' 또는 lsof -iTCP:%d -sTCP:LISTEN", port.Port, port.Port),
+				"🛡️  의도하지 않은 서비스(백도어, 디버그 서버)라면 프로세스 종료 후 침해 여부 점검",
+				"✅ 정상 서비스라면 설정 파일 system_monitoring.expected_ports 에 추가",
+			},
+		})
+	}
+
+	for _, spike := range connections.Spikes {
+		service := fmt.Sprintf("포트 %d", spike.Port)
+		if spike.Process != "" {
+			service += " (" + spike.Process + ")"
+		}
+		sm.sendAlert(SystemAlert{
+			Level:     "MEDIUM",
+			Type:      "CONNECTIONS",
+			Message:   fmt.Sprintf("%s 연결 수가 급증했습니다: %d개 (최근 평균 %.0f개, 원격 IP %d개)", service, spike.Established, spike.Average, spike.Clients),
+			Value:     float64(spike.Established),
+			Threshold: spike.Average * sm.thresholds.ConnectionSpike,
+			Metrics:   *sm.metrics,
+			Timestamp: time.Now(),
+			Suggestions: []string{
+				fmt.Sprintf("🔍 연결이 많은 원격 IP 확인: ss -tn state established '( sport = :%d )' | awk '{print $4}' | cut -d: -f1 | sort | uniq -c | sort -rn | head", spike.Port),
+				"🌊 소수의 IP 에 몰려 있다면 DoS/스크래핑 여부 확인 후 차단 검토",
+				"📈 정상 트래픽 증가라면 서비스 용량과 연결 제한(worker_connections 등) 점검",
+			},
+		})
+	}
+}
+
+// connectionFindings 진단용 연결 이슈와 권장사항
+func connectionFindings(connections ConnectionMetrics) (issues, recommendations []string) {
+	if !connections.Available {
+		return nil, nil
+	}
+	if len(connections.Unexpected) > 0 {
+		ports := make([]string, 0, len(connections.Unexpected))
+		for _, port := range connections.Unexpected {
+			ports = append(ports, port.String())
+		}
+		issues = append(issues, "🔴 예상하지 않은 수신 대기 포트: "+strings.Join(ports, ", "))
+		recommendations = append(recommendations, "• 새로 열린 포트의 프로세스 확인: `ss -ltnp` / `lsof -iTCP -sTCP:LISTEN`")
+	}
+	for _, spike := range connections.Spikes {
+		issues = append(issues, fmt.Sprintf("🟡 포트 %d 연결 수 급증: %d개 (평균 %.0f개)", spike.Port, spike.Established, spike.Average))
+	}
+	if len(connections.Spikes) > 0 {
+		recommendations = append(recommendations, "• 연결이 몰린 원격 IP 확인: `ss -tn state established`")
+	}
+	return issues, recommendations
+}
+
+// generateConnectionSection 정기 보고서 본문의 연결 현황 섹션 (수집하지 못했으면 빈 문자열)
+func generateConnectionSection(connections ConnectionMetrics) string {
+	summary := formatConnectionSummary(connections, "   ")
+	if summary == "" {
+		return ""
+	}
+	return "\n🔌 연결 현황:\n" + summary
+}
+
+// formatConnectionSummary 보고서/AI 진단 프롬프트용 연결 현황 (수집하지 못했으면 빈 문자열)
+func formatConnectionSummary(connections ConnectionMetrics, indent string) string {
+	if !connections.Available {
+		return ""
+	}
+
+	ports := make([]string, 0, len(connections.Listening))
+	seen := make(map[uint32]bool)
+	for _, port := range connections.Listening {
+		if seen[port.Port] {
+			continue
+		}
+		seen[port.Port] = true
+		label := fmt.Sprintf("%d", port.Port)
+		if port.Process != "" {
+			label += "/" + port.Process
+		}
+		ports = append(ports, label)
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s수신 대기 포트 (%d개): %s\n", indent, len(ports), strings.Join(ports, ", "))
+	fmt.Fprintf(&builder, "%sESTABLISHED 연결: %d개\n", indent, connections.Established)
+	if len(connections.Inbound) > 0 {
+		busiest := make([]string, 0, connectionReportPorts)
+		for i, entry := range connections.Inbound {
+			if i == connectionReportPorts {
+				break
+			}
+			busiest = append(busiest, fmt.Sprintf("%d (%d개, 원격 IP %d개)", entry.Port, entry.Established, entry.Clients))
+		}
+		fmt.Fprintf(&builder, "%s연결이 많은 포트: %s\n", indent, strings.Join(busiest, ", "))
+	}
+	for _, port := range connections.Unexpected {
+		fmt.Fprintf(&builder, "%s⚠️  예상하지 않은 수신 대기 포트: %s\n", indent, port)
+	}
+	for _, spike := range connections.Spikes {
+		fmt.Fprintf(&builder, "%s⚠️  연결 수 급증: 포트 %d %d개 (최근 평균 %.0f개)\n", indent, spike.Port, spike.Established, spike.Average)
+	}
+	return builder.String()
+}
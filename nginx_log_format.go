@@ -0,0 +1,287 @@
+/*
+Nginx Log Format Module
+=======================
+
+Nginx JSON 액세스 로그와 사용자 정의 log_format 파싱
+
+escape=json 으로 출력한 JSON 액세스 로그를 별도 설정 없이 인식하고,
+설정 파일에 적은 log_format 문자열을 정규식 추출 템플릿으로 컴파일해 사용합니다.
+
+주요 기능:
+- JSON 액세스 로그: 키 이름은 nginx 변수 이름($ 제외) 또는 흔한 별칭 (time, client_ip, method, user_agent 등)
+- log_format 컴파일: $변수/${변수} 는 캡처 그룹, 나머지 글자는 그대로 일치
+- nginx.conf 의 작은따옴표 문자열 이어 쓰기('...' '...')와 "log_format 이름 escape=..." 접두사 허용
+- 변수 값을 HTTPLogDetails (요청, 상태 코드, 응답 크기/시간, 클라이언트 IP 등)와 Fields 로 매핑
+*/
+package main
+
+import (
+	"encoding/json" // JSON 액세스 로그 파싱
+	"fmt"           // 형식화된 I/O
+	"regexp"        // log_format 템플릿 컴파일
+	"strconv"       // 숫자 변환
+	"strings"       // 문자열 처리
+	"time"          // 시간 파싱
+)
+
+// nginxVariableRegex log_format 안의 변수 ($name 또는 ${name})
+var nginxVariableRegex = regexp.MustCompile(`\$(?:\{(\w+)\}|(\w+))`)
+
+// nginxLogFormatPrefixRegex nginx.conf 에서 그대로 복사한 "log_format 이름 [escape=...]" 접두사
+var nginxLogFormatPrefixRegex = regexp.MustCompile(`^log_format\s+\w+\s+(?:escape=\w+\s+)?`)
+
+// nginxJSONKeyAliases JSON 액세스 로그에서 자주 쓰는 키 이름 → nginx 변수 이름
+var nginxJSONKeyAliases = map[string]string{
+	"time":            "time_iso8601",
+	"timestamp":       "time_iso8601",
+	"@timestamp":      "time_iso8601",
+	"client_ip":       "remote_addr",
+	"remote_ip":       "remote_addr",
+	"method":          "request_method",
+	"uri":             "request_uri",
+	"path":            "request_uri",
+	"protocol":        "server_protocol",
+	"status_code":     "status",
+	"bytes":           "body_bytes_sent",
+	"size":            "body_bytes_sent",
+	"referer":         "http_referer",
+	"referrer":        "http_referer",
+	"user_agent":      "http_user_agent",
+	"agent":           "http_user_agent",
+	"x_forwarded_for": "http_x_forwarded_for",
+	"server_name":     "host",
+	"http_host":       "host",
+}
+
+// NginxLogFormat log_format 문자열을 컴파일한 추출 템플릿
+type NginxLogFormat struct {
+	format    string
+	regex     *regexp.Regexp
+	variables []string // 캡처 그룹 순서대로의 변수 이름
+}
+
+// CompileNginxLogFormat log_format 문자열을 추출 템플릿으로 컴파일
+// 변수 뒤에 글자가 오면 그 글자가 나오기 전까지, 마지막 변수는 줄 끝까지 일치
+func CompileNginxLogFormat(format string) (*NginxLogFormat, error) {
+	format = normalizeNginxLogFormat(format)
+	if format == "" {
+		return nil, fmt.Errorf("empty nginx log_format")
+	}
+
+	locations := nginxVariableRegex.FindAllStringSubmatchIndex(format, -1)
+	if len(locations) == 0 {
+		return nil, fmt.Errorf("nginx log_format %q has no $variables", format)
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	variables := make([]string, 0, len(locations))
+	position := 0
+	for i, location := range locations {
+		pattern.WriteString(regexp.QuoteMeta(format[position:location[0]]))
+		var name string
+		if location[2] >= 0 {
+			name = format[location[2]:location[3]]
+		} else {
+			name = format[location[4]:location[5]]
+		}
+		variables = append(variables, name)
+
+		position = location[1]
+		switch {
+		case position == len(format):
+			pattern.WriteString("(.*)")
+		case i+1 < len(locations) && locations[i+1][0] == position:
+			// 변수가 구분자 없이 이어지면 최소 일치
+			pattern.WriteString("(.*?)")
+		default:
+			pattern.WriteString("([^" + regexp.QuoteMeta(format[position:position+1]) + "]*)")
+		}
+	}
+	pattern.WriteString(regexp.QuoteMeta(format[position:]))
+	pattern.WriteString(`\s*$`)
+
+	regex, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile nginx log_format %q: %v", format, err)
+	}
+	return &NginxLogFormat{format: format, regex: regex, variables: variables}, nil
+}
+
+// normalizeNginxLogFormat nginx.conf 문법으로 적은 log_format 을 한 문자열로 정리
+// "log_format main '...' '...';" 처럼 붙여 넣어도 작은따옴표 문자열을 이어 붙여 사용
+func normalizeNginxLogFormat(format string) string {
+	format = strings.TrimSpace(format)
+	format = strings.TrimSpace(strings.TrimSuffix(format, ";"))
+	format = nginxLogFormatPrefixRegex.ReplaceAllString(format, "")
+	if !strings.HasPrefix(format, "'") {
+		return format
+	}
+
+	var joined strings.Builder
+	inQuote := false
+	for _, r := range format {
+		switch {
+		case r == '\'':
+			inQuote = !inQuote
+		case inQuote:
+			joined.WriteRune(r)
+		}
+	}
+	return joined.String()
+}
+
+// Match 템플릿과 일치하면 변수 이름 → 값 반환
+func (f *NginxLogFormat) Match(line string) (map[string]string, bool) {
+	matches := f.regex.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, false
+	}
+	values := make(map[string]string, len(f.variables))
+	for i, name := range f.variables {
+		values[name] = matches[i+1]
+	}
+	return values, true
+}
+
+// parseNginxJSONLog escape=json 액세스 로그 한 줄을 변수 이름 → 값으로 변환
+// status 와 요청 정보(request, request_uri, request_method 중 하나)가 있어야 nginx 액세스 로그로 인정
+func parseNginxJSONLog(line string) (map[string]string, bool) {
+	if !strings.HasPrefix(line, "{") || !strings.Contains(line, `"status`) {
+		return nil, false
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return nil, false
+	}
+
+	values := make(map[string]string, len(record))
+	for key, value := range record {
+		name := strings.ToLower(strings.TrimPrefix(key, "$"))
+		if alias, ok := nginxJSONKeyAliases[name]; ok {
+			if _, exists := record[alias]; exists {
+				continue
+			}
+			name = alias
+		}
+		values[name] = nginxJSONValue(value)
+	}
+
+	if _, ok := values["status"]; !ok {
+		return nil, false
+	}
+	if values["request"] == "" && values["request_uri"] == "" && values["request_method"] == "" {
+		return nil, false
+	}
+	return values, true
+}
+
+// nginxJSONValue JSON 값을 문자열로 변환 (escape=json 에서도 숫자 변수는 따옴표 없이 쓰는 경우가 많음)
+func nginxJSONValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
+
+// applyNginxAccessValues nginx 변수 값을 ParsedLog 의 HTTP 상세 정보와 필드로 매핑
+func applyNginxAccessValues(parsed *ParsedLog, values map[string]string, format string) {
+	for name, value := range values {
+		parsed.Fields[name] = value
+	}
+	parsed.Fields["log_format"] = format
+
+	details := &HTTPLogDetails{
+		ClientIP:  nginxValue(values, "remote_addr"),
+		Method:    nginxValue(values, "request_method"),
+		URL:       nginxValue(values, "request_uri"),
+		Protocol:  nginxValue(values, "server_protocol"),
+		Referer:   nginxValue(values, "http_referer"),
+		UserAgent: nginxValue(values, "http_user_agent"),
+		Host:      nginxValue(values, "host"),
+	}
+	if details.ClientIP == "" {
+		// 프록시 뒤에서는 X-Forwarded-For 의 첫 번째 주소가 실제 클라이언트
+		forwarded := strings.Split(nginxValue(values, "http_x_forwarded_for"), ",")
+		details.ClientIP = strings.TrimSpace(forwarded[0])
+	}
+	if details.URL == "" {
+		details.URL = nginxValue(values, "uri")
+		if args := nginxValue(values, "args"); args != "" && details.URL != "" {
+			details.URL += "?" + args
+		}
+	}
+	// "$request" (GET /path HTTP/1.1) 에서 빠진 값 보충
+	if request := strings.Fields(nginxValue(values, "request")); len(request) > 0 {
+		if details.Method == "" {
+			details.Method = request[0]
+		}
+		if details.URL == "" && len(request) > 1 {
+			details.URL = request[1]
+		}
+		if details.Protocol == "" && len(request) > 2 {
+			details.Protocol = request[2]
+		}
+	}
+
+	details.StatusCode, _ = strconv.Atoi(nginxValue(values, "status"))
+	size := nginxValue(values, "body_bytes_sent")
+	if size == "" {
+		size = nginxValue(values, "bytes_sent")
+	}
+	details.ResponseSize, _ = strconv.ParseInt(size, 10, 64)
+	if requestTime, err := strconv.ParseFloat(nginxValue(values, "request_time"), 64); err == nil {
+		details.ResponseTime = int64(requestTime * 1000) // 초를 밀리초로 변환
+	}
+
+	parsed.HTTPDetails = details
+	parsed.Timestamp = nginxTimestamp(values)
+	parsed.Fields["client_ip"] = details.ClientIP
+	parsed.Fields["status_code"] = strconv.Itoa(details.StatusCode)
+	request := strings.Join(strings.Fields(details.Method+" "+details.URL+" "+details.Protocol), " ")
+	parsed.Message = fmt.Sprintf("%s - %d", request, details.StatusCode)
+
+	parsed.Level = "INFO"
+	if details.StatusCode >= 500 {
+		parsed.Level = "ERROR"
+	} else if details.StatusCode >= 400 {
+		parsed.Level = "WARNING"
+	}
+}
+
+// nginxValue 변수 값 조회 (nginx 는 빈 값을 "-" 로 기록)
+func nginxValue(values map[string]string, name string) string {
+	if value := values[name]; value != "-" {
+		return value
+	}
+	return ""
+}
+
+// nginxTimestamp $time_iso8601, $time_local, $msec 순으로 시각 결정 (없으면 현재 시각)
+func nginxTimestamp(values map[string]string) time.Time {
+	if value := nginxValue(values, "time_iso8601"); value != "" {
+		if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+			return timestamp
+		}
+	}
+	if value := nginxValue(values, "time_local"); value != "" {
+		if timestamp, err := time.Parse("02/Jan/2006:15:04:05 -0700", value); err == nil {
+			return timestamp
+		}
+	}
+	if value := nginxValue(values, "msec"); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			return time.UnixMilli(int64(seconds*1000 + 0.5))
+		}
+	}
+	return time.Now()
+}
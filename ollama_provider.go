@@ -0,0 +1,107 @@
+/*
+Ollama Provider Module
+======================
+
+로컬 Ollama 서버 LLM 백엔드 (ai_analysis.provider: ollama)
+
+로그와 시스템 정보가 호스트 밖으로 나가지 않아야 하는 환경에서 로컬 모델로 AI 분석을 수행합니다.
+
+주요 기능:
+- POST {base_url}/api/chat (기본 http://localhost:11434, stream 끔)
+- API 키 불필요 (인증 프록시 뒤에 있으면 api_key 를 Bearer 토큰으로 전달)
+- 시스템 진단은 format 에 JSON 스키마를 지정해 구조화 응답 요청
+- 로컬 모델 응답 시간을 고려해 요청 타임아웃 2분
+*/
+package main
+
+import (
+	"fmt" // 형식화된 I/O
+)
+
+// ollamaChatRequest /api/chat 요청
+type ollamaChatRequest struct {
+	Model    string                 `json:"model"`
+	Messages []ollamaChatMessage    `json:"messages"`
+	Stream   bool                   `json:"stream"`
+	Format   map[string]interface{} `json:"format,omitempty"` // 구조화 응답 JSON 스키마
+	Options  ollamaOptions          `json:"options"`
+}
+
+// ollamaChatMessage 대화 메시지
+type ollamaChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ollamaOptions 생성 옵션
+type ollamaOptions struct {
+	Temperature float64 `json:"temperature"`
+	NumPredict  int     `json:"num_predict"` // 최대 생성 토큰
+}
+
+// ollamaChatResponse /api/chat 응답 (stream 끔)
+type ollamaChatResponse struct {
+	Message ollamaChatMessage `json:"message"`
+	Done    bool              `json:"done"`
+}
+
+// OllamaProvider 로컬 Ollama 백엔드
+type OllamaProvider struct {
+	llmBase
+}
+
+// NewOllamaProvider Ollama 백엔드 생성 (config 는 withDefaults 를 거친 설정)
+func NewOllamaProvider(config *LLMConfig) *OllamaProvider {
+	return &OllamaProvider{llmBase: newLLMBase(config)}
+}
+
+// AnalyzeSystemDiagnosis 시스템 진단 분석
+func (op *OllamaProvider) AnalyzeSystemDiagnosis(metrics SystemMetrics) (string, error) {
+	return op.analyzeSystemDiagnosis(metrics, op.complete)
+}
+
+// AnalyzeLogPattern 로그 패턴 분석
+func (op *OllamaProvider) AnalyzeLogPattern(logLine string, context map[string]string) (string, error) {
+	return op.analyzeLogPattern(logLine, context, op.complete)
+}
+
+// AnalyzeLogBatch 관련 로그 묶음 분석
+func (op *OllamaProvider) AnalyzeLogBatch(lines []string, context map[string]string) (string, error) {
+	return op.analyzeLogBatch(lines, context, op.complete)
+}
+
+// AnalyzeSecurityThreat 보안 위협 분석
+func (op *OllamaProvider) AnalyzeSecurityThreat(threatData map[string]interface{}) (string, error) {
+	return op.analyzeSecurityThreat(threatData, op.complete)
+}
+
+// SummarizeDigest 일일 요약 보고서 작성
+func (op *OllamaProvider) SummarizeDigest(summary *DigestSummary) (string, error) {
+	return op.summarizeDigest(summary, op.complete)
+}
+
+// complete /api/chat 호출 (schema 가 있으면 format 으로 JSON 응답 요청)
+func (op *OllamaProvider) complete(prompt string, schema *GeminiSchema) (string, error) {
+	config := op.currentConfig()
+	request := ollamaChatRequest{
+		Model:    config.Model,
+		Messages: []ollamaChatMessage{{Role: "user", Content: prompt}},
+		Options:  ollamaOptions{Temperature: config.Temperature, NumPredict: config.MaxTokens},
+	}
+	if schema != nil {
+		request.Format = schema.jsonSchema()
+	}
+
+	headers := map[string]string{}
+	if config.APIKey != "" {
+		headers["Authorization"] = "Bearer " + config.APIKey
+	}
+	var response ollamaChatResponse
+	if err := op.postJSON("Ollama", config.BaseURL+"/api/chat", headers, request, &response); err != nil {
+		return "", err
+	}
+	if response.Message.Content == "" {
+		return "", fmt.Errorf("empty response from model %s", config.Model)
+	}
+	return response.Message.Content, nil
+}
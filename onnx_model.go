@@ -0,0 +1,1228 @@
+/*
+ONNX Model Module
+=================
+
+작은 ONNX 분류 모델 로더와 추론기 (외부 런타임/cgo 없이 순수 Go)
+
+오프라인에서 학습한 로지스틱 회귀, 선형 분류기, 작은 MLP 정도의 모델을 읽어 CPU 에서 바로 실행합니다.
+ONNX 파일(protobuf)은 필요한 필드만 직접 디코딩합니다.
+
+주요 기능:
+- 지원 연산자 (ai.onnx): MatMul, Gemm, Add, Sub, Mul, Div, Relu, LeakyRelu, Sigmoid, Tanh, Softmax, Identity, Cast, Flatten, Reshape
+- 지원 연산자 (ai.onnx.ml, skl2onnx 출력): LinearClassifier, Scaler, Normalizer (ZipMap 은 계산하지 않고 입력 확률 텐서를 출력으로 사용)
+- 출력에 필요한 노드만 골라 실행하고, 불러올 때 지원하지 않는 연산자/동적 입력 크기를 오류로 알림
+- 추론은 요청마다 값을 따로 만들어 여러 분석 워커에서 동시에 호출 가능
+- 외부 데이터 파일(external_data)로 저장된 가중치는 지원하지 않음
+*/
+package main
+
+import (
+	"encoding/binary" // raw_data 리틀 엔디언 디코딩
+	"fmt"             // 형식화된 I/O
+	"math"            // 활성화 함수
+	"os"              // 모델 파일 읽기
+	"strings"         // 문자열 처리
+)
+
+// ONNX TensorProto.DataType (사용하는 형식만)
+const (
+	onnxTypeFloat  = 1
+	onnxTypeInt32  = 6
+	onnxTypeInt64  = 7
+	onnxTypeDouble = 11
+)
+
+// onnxTensor 추론용 텐서 (모든 값은 float64 로 보관)
+type onnxTensor struct {
+	shape []int
+	data  []float64
+}
+
+// size 원소 수
+func (t *onnxTensor) size() int {
+	n := 1
+	for _, dim := range t.shape {
+		n *= dim
+	}
+	return n
+}
+
+// onnxAttribute 노드 속성
+type onnxAttribute struct {
+	f       float64
+	i       int64
+	s       string
+	floats  []float64
+	ints    []int64
+	strings []string
+	t       *onnxTensor
+}
+
+// onnxNode 그래프 노드
+type onnxNode struct {
+	op      string
+	domain  string
+	inputs  []string
+	outputs []string
+	attrs   map[string]*onnxAttribute
+}
+
+// onnxValueInfo 그래프 입력/출력 선언
+type onnxValueInfo struct {
+	name     string
+	elemType int   // 텐서가 아니면 (ZipMap 의 sequence<map> 등) 0
+	shape    []int // 이름만 있는 동적 차원은 -1
+}
+
+// ONNXModel 불러온 모델 (입력 하나, 점수 출력 하나)
+type ONNXModel struct {
+	Input       onnxValueInfo
+	Output      string   // 점수로 사용하는 텐서 이름
+	ClassLabels []string // 분류기의 클래스 이름 (알 수 있을 때)
+
+	nodes        []*onnxNode // 출력 계산에 필요한 노드 (실행 순서)
+	initializers map[string]*onnxTensor
+	producer     string
+}
+
+// LoadONNXModel ONNX 파일을 읽고 점수 출력과 실행할 노드 결정
+func LoadONNXModel(path string) (*ONNXModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	model, err := parseONNXModel(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return model, nil
+}
+
+// Features 입력 벡터 길이 (입력의 마지막 차원)
+func (m *ONNXModel) Features() int {
+	return m.Input.shape[len(m.Input.shape)-1]
+}
+
+// Run 입력 벡터 하나로 점수 출력 텐서 값 계산
+func (m *ONNXModel) Run(features []float64) ([]float64, error) {
+	if len(features) != m.Features() {
+		return nil, fmt.Errorf("model expects %d features, got %d", m.Features(), len(features))
+	}
+	// 배치 차원은 1 로 고정
+	shape := make([]int, len(m.Input.shape))
+	for i := range shape {
+		shape[i] = 1
+	}
+	shape[len(shape)-1] = len(features)
+
+	values := make(map[string]*onnxTensor, len(m.initializers)+len(m.nodes)+1)
+	for name, tensor := range m.initializers {
+		values[name] = tensor
+	}
+	values[m.Input.name] = &onnxTensor{shape: shape, data: features}
+	for _, node := range m.nodes {
+		if err := runONNXNode(node, values); err != nil {
+			return nil, fmt.Errorf("%s node: %v", node.op, err)
+		}
+	}
+	output := values[m.Output]
+	if output == nil {
+		return nil, fmt.Errorf("output %s was not computed", m.Output)
+	}
+	return output.data, nil
+}
+
+// parseONNXModel ModelProto 디코딩
+func parseONNXModel(data []byte) (*ONNXModel, error) {
+	var graphData []byte
+	model := &ONNXModel{initializers: make(map[string]*onnxTensor)}
+	r := &protoReader{data: data}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case field == 2 && wire == protoWireBytes: // producer_name
+			value, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			model.producer = string(value)
+		case field == 7 && wire == protoWireBytes: // graph
+			if graphData, err = r.bytes(); err != nil {
+				return nil, err
+			}
+		default:
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if graphData == nil {
+		return nil, fmt.Errorf("not an ONNX model (no graph)")
+	}
+
+	var nodes []*onnxNode
+	var inputs, outputs []onnxValueInfo
+	r = &protoReader{data: graphData}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if wire != protoWireBytes || (field != 1 && field != 5 && field != 11 && field != 12) {
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		value, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		switch field {
+		case 1: // node
+			node, err := parseONNXNode(value)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		case 5: // initializer
+			name, tensor, err := parseONNXTensor(value)
+			if err != nil {
+				return nil, fmt.Errorf("initializer %s: %v", name, err)
+			}
+			model.initializers[name] = tensor
+		case 11: // input
+			info, err := parseONNXValueInfo(value)
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, info)
+		case 12: // output
+			info, err := parseONNXValueInfo(value)
+			if err != nil {
+				return nil, err
+			}
+			outputs = append(outputs, info)
+		}
+	}
+
+	// 입력: 가중치(initializer)가 아닌 유일한 그래프 입력
+	var dataInputs []onnxValueInfo
+	for _, input := range inputs {
+		if model.initializers[input.name] == nil {
+			dataInputs = append(dataInputs, input)
+		}
+	}
+	if len(dataInputs) != 1 {
+		return nil, fmt.Errorf("model must have exactly one input, found %d", len(dataInputs))
+	}
+	model.Input = dataInputs[0]
+	if len(model.Input.shape) == 0 || len(model.Input.shape) > 2 || model.Input.shape[len(model.Input.shape)-1] <= 0 {
+		return nil, fmt.Errorf("input %s must be [batch, features] with a fixed feature count", model.Input.name)
+	}
+
+	// 출력: 첫 번째 실수 텐서 출력, 없으면 ZipMap 의 입력 확률 텐서 (skl2onnx 기본 내보내기)
+	producers := make(map[string]*onnxNode)
+	for _, node := range nodes {
+		for _, output := range node.outputs {
+			producers[output] = node
+		}
+		for _, name := range []string{"classlabels_strings", "classlabels_int64s", "classlabels_ints"} {
+			if attr := node.attrs[name]; attr != nil && model.ClassLabels == nil {
+				model.ClassLabels = attr.strings
+				for _, label := range attr.ints {
+					model.ClassLabels = append(model.ClassLabels, fmt.Sprint(label))
+				}
+			}
+		}
+	}
+	for _, output := range outputs {
+		if output.elemType == onnxTypeFloat || output.elemType == onnxTypeDouble {
+			model.Output = output.name
+			break
+		}
+	}
+	if model.Output == "" {
+		for _, output := range outputs {
+			if node := producers[output.name]; node != nil && node.op == "ZipMap" {
+				model.Output = node.inputs[0]
+				break
+			}
+		}
+	}
+	if model.Output == "" {
+		return nil, fmt.Errorf("no float tensor output (export classifiers with zipmap disabled)")
+	}
+
+	// 출력에서 거꾸로 따라가며 필요한 노드만 선택 (그래프의 노드는 위상 정렬되어 있음)
+	needed := map[string]bool{model.Output: true}
+	var selected []*onnxNode
+	for i := len(nodes) - 1; i >= 0; i-- {
+		node := nodes[i]
+		use := false
+		for _, output := range node.outputs {
+			use = use || needed[output]
+		}
+		if !use {
+			continue
+		}
+		if !onnxSupportedOps[node.op] {
+			return nil, fmt.Errorf("unsupported operator %s (supported: %s)", node.op, onnxSupportedOpList())
+		}
+		for _, input := range node.inputs {
+			needed[input] = true
+		}
+		selected = append([]*onnxNode{node}, selected...)
+	}
+	model.nodes = selected
+	return model, nil
+}
+
+// parseONNXNode NodeProto 디코딩
+func parseONNXNode(data []byte) (*onnxNode, error) {
+	node := &onnxNode{attrs: make(map[string]*onnxAttribute)}
+	r := &protoReader{data: data}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+		if wire != protoWireBytes {
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		value, err := r.bytes()
+		if err != nil {
+			return nil, err
+		}
+		switch field {
+		case 1:
+			node.inputs = append(node.inputs, string(value))
+		case 2:
+			node.outputs = append(node.outputs, string(value))
+		case 4:
+			node.op = string(value)
+		case 5:
+			name, attr, err := parseONNXAttribute(value)
+			if err != nil {
+				return nil, err
+			}
+			node.attrs[name] = attr
+		case 7:
+			node.domain = string(value)
+		}
+	}
+	return node, nil
+}
+
+// parseONNXAttribute AttributeProto 디코딩 (반복 필드는 packed/unpacked 모두 허용)
+func parseONNXAttribute(data []byte) (string, *onnxAttribute, error) {
+	var name string
+	attr := &onnxAttribute{}
+	r := &protoReader{data: data}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return "", nil, err
+		}
+		switch {
+		case field == 1 && wire == protoWireBytes:
+			value, err := r.bytes()
+			if err != nil {
+				return "", nil, err
+			}
+			name = string(value)
+		case field == 2 && wire == protoWireFixed32:
+			value, err := r.fixed32()
+			if err != nil {
+				return "", nil, err
+			}
+			attr.f = float64(math.Float32frombits(value))
+		case field == 3 && wire == protoWireVarint:
+			value, err := r.varint()
+			if err != nil {
+				return "", nil, err
+			}
+			attr.i = int64(value)
+		case field == 4 && wire == protoWireBytes:
+			value, err := r.bytes()
+			if err != nil {
+				return "", nil, err
+			}
+			attr.s = string(value)
+		case field == 5 && wire == protoWireBytes:
+			value, err := r.bytes()
+			if err != nil {
+				return "", nil, err
+			}
+			if _, attr.t, err = parseONNXTensor(value); err != nil {
+				return "", nil, err
+			}
+		case field == 7:
+			if attr.floats, err = r.appendFloat32s(attr.floats, wire); err != nil {
+				return "", nil, err
+			}
+		case field == 8:
+			if attr.ints, err = r.appendVarints(attr.ints, wire); err != nil {
+				return "", nil, err
+			}
+		case field == 9 && wire == protoWireBytes:
+			value, err := r.bytes()
+			if err != nil {
+				return "", nil, err
+			}
+			attr.strings = append(attr.strings, string(value))
+		default:
+			if err := r.skip(wire); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	return name, attr, nil
+}
+
+// parseONNXTensor TensorProto 디코딩 (float, double, int32, int64)
+func parseONNXTensor(data []byte) (string, *onnxTensor, error) {
+	var name string
+	var dims []int64
+	var raw []byte
+	dataType := 0
+	tensor := &onnxTensor{}
+	r := &protoReader{data: data}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return name, nil, err
+		}
+		switch {
+		case field == 1:
+			if dims, err = r.appendVarints(dims, wire); err != nil {
+				return name, nil, err
+			}
+		case field == 2 && wire == protoWireVarint:
+			value, err := r.varint()
+			if err != nil {
+				return name, nil, err
+			}
+			dataType = int(value)
+		case field == 4:
+			var values []float64
+			if values, err = r.appendFloat32s(nil, wire); err != nil {
+				return name, nil, err
+			}
+			tensor.data = append(tensor.data, values...)
+		case field == 5 || field == 7:
+			var values []int64
+			if values, err = r.appendVarints(nil, wire); err != nil {
+				return name, nil, err
+			}
+			for _, value := range values {
+				if field == 5 {
+					value = int64(int32(value))
+				}
+				tensor.data = append(tensor.data, float64(value))
+			}
+		case field == 8 && wire == protoWireBytes:
+			value, err := r.bytes()
+			if err != nil {
+				return name, nil, err
+			}
+			name = string(value)
+		case field == 9 && wire == protoWireBytes:
+			if raw, err = r.bytes(); err != nil {
+				return name, nil, err
+			}
+		case field == 10:
+			var values []float64
+			if values, err = r.appendFloat64s(nil, wire); err != nil {
+				return name, nil, err
+			}
+			tensor.data = append(tensor.data, values...)
+		case field == 14 && wire == protoWireVarint: // data_location
+			value, err := r.varint()
+			if err != nil {
+				return name, nil, err
+			}
+			if value == 1 {
+				return name, nil, fmt.Errorf("external data is not supported (save the model with weights inside the file)")
+			}
+		default:
+			if err := r.skip(wire); err != nil {
+				return name, nil, err
+			}
+		}
+	}
+
+	tensor.shape = make([]int, len(dims))
+	count := int64(1)
+	for i, dim := range dims {
+		if dim < 0 || (dim > 0 && count > math.MaxInt32/dim) {
+			return name, nil, fmt.Errorf("invalid shape %v", dims)
+		}
+		count *= dim
+		tensor.shape[i] = int(dim)
+	}
+	if raw != nil {
+		var width int
+		switch dataType {
+		case onnxTypeFloat, onnxTypeInt32:
+			width = 4
+		case onnxTypeInt64, onnxTypeDouble:
+			width = 8
+		default:
+			return name, nil, fmt.Errorf("unsupported tensor data type %d", dataType)
+		}
+		tensor.data = make([]float64, 0, len(raw)/width)
+		for offset := 0; offset+width <= len(raw); offset += width {
+			switch dataType {
+			case onnxTypeFloat:
+				tensor.data = append(tensor.data, float64(math.Float32frombits(binary.LittleEndian.Uint32(raw[offset:]))))
+			case onnxTypeInt32:
+				tensor.data = append(tensor.data, float64(int32(binary.LittleEndian.Uint32(raw[offset:]))))
+			case onnxTypeInt64:
+				tensor.data = append(tensor.data, float64(int64(binary.LittleEndian.Uint64(raw[offset:]))))
+			case onnxTypeDouble:
+				tensor.data = append(tensor.data, math.Float64frombits(binary.LittleEndian.Uint64(raw[offset:])))
+			}
+		}
+	}
+	if len(tensor.data) != tensor.size() {
+		return name, nil, fmt.Errorf("tensor has %d values for shape %v", len(tensor.data), tensor.shape)
+	}
+	return name, tensor, nil
+}
+
+// parseONNXValueInfo ValueInfoProto 디코딩 (이름, 텐서 형식, 모양)
+func parseONNXValueInfo(data []byte) (onnxValueInfo, error) {
+	var info onnxValueInfo
+	r := &protoReader{data: data}
+	for !r.done() {
+		field, wire, err := r.next()
+		if err != nil {
+			return info, err
+		}
+		if wire != protoWireBytes {
+			if err := r.skip(wire); err != nil {
+				return info, err
+			}
+			continue
+		}
+		value, err := r.bytes()
+		if err != nil {
+			return info, err
+		}
+		switch field {
+		case 1:
+			info.name = string(value)
+		case 2: // TypeProto: 1 = tensor_type
+			if tensorType := protoSubmessage(value, 1); tensorType != nil {
+				info.elemType = int(protoVarintField(tensorType, 1))
+				if shape := protoSubmessage(tensorType, 2); shape != nil {
+					for _, dim := range protoRepeatedBytes(shape, 1) {
+						size := -1
+						if value := protoVarintField(dim, 1); value > 0 {
+							size = int(value)
+						}
+						info.shape = append(info.shape, size)
+					}
+				}
+			}
+		}
+	}
+	return info, nil
+}
+
+// onnxSupportedOps 실행할 수 있는 연산자
+var onnxSupportedOps = map[string]bool{
+	"MatMul": true, "Gemm": true, "Add": true, "Sub": true, "Mul": true, "Div": true,
+	"Relu": true, "LeakyRelu": true, "Sigmoid": true, "Tanh": true, "Softmax": true,
+	"Identity": true, "Cast": true, "Flatten": true, "Reshape": true,
+	"LinearClassifier": true, "Scaler": true, "Normalizer": true,
+}
+
+// onnxSupportedOpList 오류 메시지용 연산자 목록
+func onnxSupportedOpList() string {
+	return "MatMul, Gemm, Add, Sub, Mul, Div, Relu, LeakyRelu, Sigmoid, Tanh, Softmax, Identity, Cast, Flatten, Reshape, LinearClassifier, Scaler, Normalizer"
+}
+
+// runONNXNode 노드 하나 실행 (결과를 values 에 기록)
+func runONNXNode(node *onnxNode, values map[string]*onnxTensor) error {
+	inputs := make([]*onnxTensor, len(node.inputs))
+	for i, name := range node.inputs {
+		if name == "" {
+			continue // 생략된 선택 입력
+		}
+		if inputs[i] = values[name]; inputs[i] == nil {
+			return fmt.Errorf("missing input %s", name)
+		}
+	}
+	if len(inputs) == 0 || inputs[0] == nil {
+		return fmt.Errorf("missing input")
+	}
+	x := inputs[0]
+	switch node.op {
+	case "Scaler", "Normalizer", "LinearClassifier":
+		if len(x.shape) == 0 {
+			return fmt.Errorf("expected [batch, features], got a scalar")
+		}
+	}
+
+	var outputs []*onnxTensor
+	switch node.op {
+	case "Identity", "Cast":
+		outputs = []*onnxTensor{x}
+	case "Relu":
+		outputs = []*onnxTensor{onnxMap(x, func(v float64) float64 { return math.Max(v, 0) })}
+	case "LeakyRelu":
+		alpha := node.attrFloat("alpha", 0.01)
+		outputs = []*onnxTensor{onnxMap(x, func(v float64) float64 {
+			if v < 0 {
+				return v * alpha
+			}
+			return v
+		})}
+	case "Sigmoid":
+		outputs = []*onnxTensor{onnxMap(x, onnxSigmoid)}
+	case "Tanh":
+		outputs = []*onnxTensor{onnxMap(x, math.Tanh)}
+	case "Add", "Sub", "Mul", "Div":
+		if len(inputs) < 2 || inputs[1] == nil {
+			return fmt.Errorf("needs two inputs")
+		}
+		result, err := onnxBroadcast(node.op, x, inputs[1])
+		if err != nil {
+			return err
+		}
+		outputs = []*onnxTensor{result}
+	case "MatMul":
+		if len(inputs) < 2 || inputs[1] == nil {
+			return fmt.Errorf("needs two inputs")
+		}
+		result, err := onnxGemm(x, inputs[1], nil, 1, 0, false, false)
+		if err != nil {
+			return err
+		}
+		outputs = []*onnxTensor{result}
+	case "Gemm":
+		if len(inputs) < 2 || inputs[1] == nil {
+			return fmt.Errorf("needs two inputs")
+		}
+		var c *onnxTensor
+		if len(inputs) > 2 {
+			c = inputs[2]
+		}
+		result, err := onnxGemm(x, inputs[1], c, node.attrFloat("alpha", 1), node.attrFloat("beta", 1), node.attrInt("transA", 0) != 0, node.attrInt("transB", 0) != 0)
+		if err != nil {
+			return err
+		}
+		outputs = []*onnxTensor{result}
+	case "Softmax":
+		result, err := onnxSoftmax(x, int(node.attrInt("axis", -1)))
+		if err != nil {
+			return err
+		}
+		outputs = []*onnxTensor{result}
+	case "Flatten":
+		axis := int(node.attrInt("axis", 1))
+		if axis < 0 {
+			axis += len(x.shape)
+		}
+		if axis < 0 || axis > len(x.shape) {
+			return fmt.Errorf("invalid axis for shape %v", x.shape)
+		}
+		outer := 1
+		for _, dim := range x.shape[:axis] {
+			outer *= dim
+		}
+		inner := 1
+		for _, dim := range x.shape[axis:] {
+			inner *= dim
+		}
+		outputs = []*onnxTensor{{shape: []int{outer, inner}, data: x.data}}
+	case "Reshape":
+		if len(inputs) < 2 || inputs[1] == nil {
+			return fmt.Errorf("needs a shape input")
+		}
+		result, err := onnxReshape(x, inputs[1].data)
+		if err != nil {
+			return err
+		}
+		outputs = []*onnxTensor{result}
+	case "Scaler":
+		offset, scale := node.attrFloats("offset"), node.attrFloats("scale")
+		result := &onnxTensor{shape: x.shape, data: make([]float64, len(x.data))}
+		features := x.shape[len(x.shape)-1]
+		for i, v := range x.data {
+			column := i % features
+			if len(offset) > 0 {
+				v -= offset[column%len(offset)]
+			}
+			if len(scale) > 0 {
+				v *= scale[column%len(scale)]
+			}
+			result.data[i] = v
+		}
+		outputs = []*onnxTensor{result}
+	case "Normalizer":
+		result, err := onnxNormalize(x, node.attrString("norm", "MAX"))
+		if err != nil {
+			return err
+		}
+		outputs = []*onnxTensor{result}
+	case "LinearClassifier":
+		labels, scores, err := onnxLinearClassifier(node, x)
+		if err != nil {
+			return err
+		}
+		outputs = []*onnxTensor{labels, scores}
+	default:
+		return fmt.Errorf("unsupported operator")
+	}
+
+	for i, name := range node.outputs {
+		if i < len(outputs) && name != "" {
+			values[name] = outputs[i]
+		}
+	}
+	return nil
+}
+
+// attrFloat 실수 속성 (없으면 기본값)
+func (n *onnxNode) attrFloat(name string, fallback float64) float64 {
+	if attr := n.attrs[name]; attr != nil {
+		return attr.f
+	}
+	return fallback
+}
+
+// attrInt 정수 속성 (없으면 기본값)
+func (n *onnxNode) attrInt(name string, fallback int64) int64 {
+	if attr := n.attrs[name]; attr != nil {
+		return attr.i
+	}
+	return fallback
+}
+
+// attrString 문자열 속성 (없으면 기본값)
+func (n *onnxNode) attrString(name, fallback string) string {
+	if attr := n.attrs[name]; attr != nil {
+		return attr.s
+	}
+	return fallback
+}
+
+// attrFloats 실수 목록 속성
+func (n *onnxNode) attrFloats(name string) []float64 {
+	if attr := n.attrs[name]; attr != nil {
+		return attr.floats
+	}
+	return nil
+}
+
+// onnxSigmoid 로지스틱 함수
+func onnxSigmoid(v float64) float64 {
+	return 1 / (1 + math.Exp(-v))
+}
+
+// onnxMap 원소별 함수 적용
+func onnxMap(x *onnxTensor, fn func(float64) float64) *onnxTensor {
+	result := &onnxTensor{shape: x.shape, data: make([]float64, len(x.data))}
+	for i, v := range x.data {
+		result.data[i] = fn(v)
+	}
+	return result
+}
+
+// onnxBroadcast numpy 방식 브로드캐스팅 사칙연산
+func onnxBroadcast(op string, a, b *onnxTensor) (*onnxTensor, error) {
+	rank := max(len(a.shape), len(b.shape))
+	shape := make([]int, rank)
+	aShape, bShape := onnxPadShape(a.shape, rank), onnxPadShape(b.shape, rank)
+	for i := range shape {
+		switch {
+		case aShape[i] == bShape[i] || bShape[i] == 1:
+			shape[i] = aShape[i]
+		case aShape[i] == 1:
+			shape[i] = bShape[i]
+		default:
+			return nil, fmt.Errorf("cannot broadcast %v and %v", a.shape, b.shape)
+		}
+	}
+	result := &onnxTensor{shape: shape}
+	result.data = make([]float64, result.size())
+	index := make([]int, rank)
+	for i := range result.data {
+		// i 를 다차원 인덱스로 바꾸고 크기 1 차원은 0 으로 고정
+		rest := i
+		for axis := rank - 1; axis >= 0; axis-- {
+			index[axis] = rest % shape[axis]
+			rest /= shape[axis]
+		}
+		x, y := a.data[onnxOffset(index, aShape)], b.data[onnxOffset(index, bShape)]
+		switch op {
+		case "Add":
+			result.data[i] = x + y
+		case "Sub":
+			result.data[i] = x - y
+		case "Mul":
+			result.data[i] = x * y
+		case "Div":
+			result.data[i] = x / y
+		}
+	}
+	return result, nil
+}
+
+// onnxPadShape 앞쪽에 크기 1 차원을 붙여 rank 차원으로 맞춤
+func onnxPadShape(shape []int, rank int) []int {
+	padded := make([]int, rank)
+	for i := range padded {
+		padded[i] = 1
+	}
+	copy(padded[rank-len(shape):], shape)
+	return padded
+}
+
+// onnxOffset 브로드캐스팅된 인덱스의 원래 텐서 내 위치
+func onnxOffset(index, shape []int) int {
+	offset := 0
+	for axis, dim := range shape {
+		position := index[axis]
+		if dim == 1 {
+			position = 0
+		}
+		offset = offset*dim + position
+	}
+	return offset
+}
+
+// onnxMatrix 1차원/2차원 텐서를 행렬 크기로 해석 (1차원은 행 벡터)
+func onnxMatrix(t *onnxTensor, transpose bool) (rows, cols int, at func(i, j int) float64, err error) {
+	switch len(t.shape) {
+	case 1:
+		rows, cols = 1, t.shape[0]
+	case 2:
+		rows, cols = t.shape[0], t.shape[1]
+	default:
+		return 0, 0, nil, fmt.Errorf("expected a matrix, got shape %v", t.shape)
+	}
+	stride := cols
+	if transpose {
+		rows, cols = cols, rows
+		return rows, cols, func(i, j int) float64 { return t.data[j*stride+i] }, nil
+	}
+	return rows, cols, func(i, j int) float64 { return t.data[i*stride+j] }, nil
+}
+
+// onnxGemm alpha * A·B + beta * C (C 는 브로드캐스팅)
+func onnxGemm(a, b, c *onnxTensor, alpha, beta float64, transA, transB bool) (*onnxTensor, error) {
+	m, k, atA, err := onnxMatrix(a, transA)
+	if err != nil {
+		return nil, err
+	}
+	kb, n, atB, err := onnxMatrix(b, transB)
+	if err != nil {
+		return nil, err
+	}
+	if k != kb {
+		return nil, fmt.Errorf("matrix shapes %v and %v do not match", a.shape, b.shape)
+	}
+	result := &onnxTensor{shape: []int{m, n}, data: make([]float64, m*n)}
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			sum := 0.0
+			for p := 0; p < k; p++ {
+				sum += atA(i, p) * atB(p, j)
+			}
+			result.data[i*n+j] = alpha * sum
+		}
+	}
+	if len(a.shape) == 1 && !transA {
+		result.shape = []int{n}
+	}
+	if c == nil || beta == 0 {
+		return result, nil
+	}
+	bias := onnxMap(c, func(v float64) float64 { return v * beta })
+	return onnxBroadcast("Add", result, bias)
+}
+
+// onnxSoftmax axis 차원 기준 소프트맥스
+func onnxSoftmax(x *onnxTensor, axis int) (*onnxTensor, error) {
+	if axis < 0 {
+		axis += len(x.shape)
+	}
+	if axis < 0 || axis >= len(x.shape) {
+		return nil, fmt.Errorf("invalid axis for shape %v", x.shape)
+	}
+	// axis 이후 차원을 한 덩어리로 정규화 (opset 13 이전 의미, 배치 1 의 2차원 입력에서는 같은 결과)
+	inner := 1
+	for _, dim := range x.shape[axis:] {
+		inner *= dim
+	}
+	result := &onnxTensor{shape: x.shape, data: make([]float64, len(x.data))}
+	for start := 0; start < len(x.data); start += inner {
+		row := x.data[start : start+inner]
+		peak := math.Inf(-1)
+		for _, v := range row {
+			peak = math.Max(peak, v)
+		}
+		sum := 0.0
+		for i, v := range row {
+			result.data[start+i] = math.Exp(v - peak)
+			sum += result.data[start+i]
+		}
+		for i := range row {
+			result.data[start+i] /= sum
+		}
+	}
+	return result, nil
+}
+
+// onnxReshape shape 입력에 맞춰 모양 변경 (0 은 기존 차원 유지, -1 은 나머지로 계산)
+func onnxReshape(x *onnxTensor, target []float64) (*onnxTensor, error) {
+	shape := make([]int, len(target))
+	known, infer := 1, -1
+	for i, value := range target {
+		switch dim := int(value); {
+		case dim == 0 && i < len(x.shape):
+			shape[i] = x.shape[i]
+		case dim == -1 && infer < 0:
+			infer = i
+			continue
+		case dim > 0:
+			shape[i] = dim
+		default:
+			return nil, fmt.Errorf("invalid shape %v", target)
+		}
+		known *= shape[i]
+	}
+	if infer >= 0 {
+		if known == 0 || x.size()%known != 0 {
+			return nil, fmt.Errorf("cannot reshape %v to %v", x.shape, target)
+		}
+		shape[infer] = x.size() / known
+		known *= shape[infer]
+	}
+	if known != x.size() {
+		return nil, fmt.Errorf("cannot reshape %v to %v", x.shape, target)
+	}
+	return &onnxTensor{shape: shape, data: x.data}, nil
+}
+
+// onnxNormalize 행 단위 정규화 (ai.onnx.ml Normalizer: MAX, L1, L2)
+func onnxNormalize(x *onnxTensor, norm string) (*onnxTensor, error) {
+	cols := x.shape[len(x.shape)-1]
+	result := &onnxTensor{shape: x.shape, data: make([]float64, len(x.data))}
+	for start := 0; start < len(x.data); start += cols {
+		row := x.data[start : start+cols]
+		scale := 0.0
+		for _, v := range row {
+			switch norm {
+			case "MAX":
+				scale = math.Max(scale, v)
+			case "L1":
+				scale += math.Abs(v)
+			case "L2":
+				scale += v * v
+			default:
+				return nil, fmt.Errorf("unsupported norm %s", norm)
+			}
+		}
+		if norm == "L2" {
+			scale = math.Sqrt(scale)
+		}
+		for i, v := range row {
+			if scale != 0 {
+				v /= scale
+			}
+			result.data[start+i] = v
+		}
+	}
+	return result, nil
+}
+
+// onnxLinearClassifier ai.onnx.ml LinearClassifier (레이블 인덱스, 클래스별 점수)
+// 이진 분류를 한 행의 계수로 표현한 모델은 [1-p, p] (LOGISTIC) 또는 [-s, s] 로 두 열을 만듦
+func onnxLinearClassifier(node *onnxNode, x *onnxTensor) (*onnxTensor, *onnxTensor, error) {
+	coefficients, intercepts := node.attrFloats("coefficients"), node.attrFloats("intercepts")
+	features := x.shape[len(x.shape)-1]
+	if features == 0 || len(coefficients) == 0 || len(coefficients)%features != 0 {
+		return nil, nil, fmt.Errorf("%d coefficients do not match %d features", len(coefficients), features)
+	}
+	classes := len(coefficients) / features
+	labels := 0
+	if attr := node.attrs["classlabels_strings"]; attr != nil {
+		labels = len(attr.strings)
+	} else if attr := node.attrs["classlabels_ints"]; attr != nil {
+		labels = len(attr.ints)
+	}
+	transform := node.attrString("post_transform", "NONE")
+
+	rows := x.size() / features
+	columns := classes
+	if classes == 1 && labels == 2 {
+		columns = 2
+	}
+	scores := &onnxTensor{shape: []int{rows, columns}, data: make([]float64, 0, rows*columns)}
+	label := &onnxTensor{shape: []int{rows}, data: make([]float64, rows)}
+	for row := 0; row < rows; row++ {
+		input := x.data[row*features : (row+1)*features]
+		raw := make([]float64, classes)
+		for class := range raw {
+			sum := 0.0
+			if class < len(intercepts) {
+				sum = intercepts[class]
+			}
+			for j, v := range input {
+				sum += v * coefficients[class*features+j]
+			}
+			raw[class] = sum
+		}
+		switch transform {
+		case "LOGISTIC":
+			for i := range raw {
+				raw[i] = onnxSigmoid(raw[i])
+			}
+		case "SOFTMAX", "SOFTMAX_ZERO":
+			softmax, _ := onnxSoftmax(&onnxTensor{shape: []int{classes}, data: raw}, 0)
+			raw = softmax.data
+		case "NONE":
+		default:
+			return nil, nil, fmt.Errorf("unsupported post_transform %s", transform)
+		}
+		if columns == 2 && classes == 1 {
+			if transform == "LOGISTIC" {
+				raw = []float64{1 - raw[0], raw[0]}
+			} else {
+				raw = []float64{-raw[0], raw[0]}
+			}
+		}
+		best := 0
+		for i, v := range raw {
+			if v > raw[best] {
+				best = i
+			}
+		}
+		label.data[row] = float64(best)
+		scores.data = append(scores.data, raw...)
+	}
+	return label, scores, nil
+}
+
+// protobuf wire 형식
+const (
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+	protoWireFixed32 = 5
+)
+
+// protoReader 최소 protobuf 디코더 (ONNX 파일에 필요한 범위)
+type protoReader struct {
+	data []byte
+	pos  int
+}
+
+// done 끝까지 읽었는지 여부
+func (r *protoReader) done() bool {
+	return r.pos >= len(r.data)
+}
+
+// next 다음 필드 번호와 wire 형식
+func (r *protoReader) next() (int, int, error) {
+	key, err := r.varint()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(key >> 3), int(key & 7), nil
+}
+
+// varint 가변 길이 정수
+func (r *protoReader) varint() (uint64, error) {
+	var value uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		if r.pos >= len(r.data) {
+			return 0, fmt.Errorf("truncated protobuf")
+		}
+		b := r.data[r.pos]
+		r.pos++
+		value |= uint64(b&0x7f) << shift
+		if b < 0x80 {
+			return value, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid varint")
+}
+
+// bytes 길이가 앞에 붙은 값
+func (r *protoReader) bytes() ([]byte, error) {
+	length, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	if length > uint64(len(r.data)-r.pos) {
+		return nil, fmt.Errorf("truncated protobuf")
+	}
+	value := r.data[r.pos : r.pos+int(length)]
+	r.pos += int(length)
+	return value, nil
+}
+
+// fixed32 4바이트 값
+func (r *protoReader) fixed32() (uint32, error) {
+	if len(r.data)-r.pos < 4 {
+		return 0, fmt.Errorf("truncated protobuf")
+	}
+	value := binary.LittleEndian.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return value, nil
+}
+
+// fixed64 8바이트 값
+func (r *protoReader) fixed64() (uint64, error) {
+	if len(r.data)-r.pos < 8 {
+		return 0, fmt.Errorf("truncated protobuf")
+	}
+	value := binary.LittleEndian.Uint64(r.data[r.pos:])
+	r.pos += 8
+	return value, nil
+}
+
+// skip 사용하지 않는 필드 건너뛰기
+func (r *protoReader) skip(wire int) error {
+	var err error
+	switch wire {
+	case protoWireVarint:
+		_, err = r.varint()
+	case protoWireFixed64:
+		_, err = r.fixed64()
+	case protoWireBytes:
+		_, err = r.bytes()
+	case protoWireFixed32:
+		_, err = r.fixed32()
+	default:
+		err = fmt.Errorf("unsupported protobuf wire type %d", wire)
+	}
+	return err
+}
+
+// appendVarints 반복 정수 필드 (packed 또는 값 하나)
+func (r *protoReader) appendVarints(values []int64, wire int) ([]int64, error) {
+	if wire == protoWireVarint {
+		value, err := r.varint()
+		return append(values, int64(value)), err
+	}
+	packed, err := r.bytes()
+	if err != nil {
+		return values, err
+	}
+	inner := &protoReader{data: packed}
+	for !inner.done() {
+		value, err := inner.varint()
+		if err != nil {
+			return values, err
+		}
+		values = append(values, int64(value))
+	}
+	return values, nil
+}
+
+// appendFloat32s 반복 float 필드 (packed 또는 값 하나)
+func (r *protoReader) appendFloat32s(values []float64, wire int) ([]float64, error) {
+	if wire == protoWireFixed32 {
+		value, err := r.fixed32()
+		return append(values, float64(math.Float32frombits(value))), err
+	}
+	packed, err := r.bytes()
+	if err != nil {
+		return values, err
+	}
+	for offset := 0; offset+4 <= len(packed); offset += 4 {
+		values = append(values, float64(math.Float32frombits(binary.LittleEndian.Uint32(packed[offset:]))))
+	}
+	return values, nil
+}
+
+// appendFloat64s 반복 double 필드 (packed 또는 값 하나)
+func (r *protoReader) appendFloat64s(values []float64, wire int) ([]float64, error) {
+	if wire == protoWireFixed64 {
+		value, err := r.fixed64()
+		return append(values, math.Float64frombits(value)), err
+	}
+	packed, err := r.bytes()
+	if err != nil {
+		return values, err
+	}
+	for offset := 0; offset+8 <= len(packed); offset += 8 {
+		values = append(values, math.Float64frombits(binary.LittleEndian.Uint64(packed[offset:])))
+	}
+	return values, nil
+}
+
+// protoSubmessage 첫 번째 field 하위 메시지 (없으면 nil)
+func protoSubmessage(data []byte, field int) []byte {
+	if values := protoRepeatedBytes(data, field); len(values) > 0 {
+		return values[0]
+	}
+	return nil
+}
+
+// protoRepeatedBytes field 의 모든 길이 구분 값
+func protoRepeatedBytes(data []byte, field int) [][]byte {
+	var values [][]byte
+	r := &protoReader{data: data}
+	for !r.done() {
+		number, wire, err := r.next()
+		if err != nil {
+			return values
+		}
+		if number == field && wire == protoWireBytes {
+			value, err := r.bytes()
+			if err != nil {
+				return values
+			}
+			values = append(values, value)
+		} else if r.skip(wire) != nil {
+			return values
+		}
+	}
+	return values
+}
+
+// protoVarintField 첫 번째 field 정수 값 (없으면 0)
+func protoVarintField(data []byte, field int) uint64 {
+	r := &protoReader{data: data}
+	for !r.done() {
+		number, wire, err := r.next()
+		if err != nil {
+			return 0
+		}
+		if number == field && wire == protoWireVarint {
+			value, _ := r.varint()
+			return value
+		}
+		if r.skip(wire) != nil {
+			return 0
+		}
+	}
+	return 0
+}
+
+// describeONNXModel 시작 로그용 요약
+func describeONNXModel(m *ONNXModel) string {
+	ops := make([]string, 0, len(m.nodes))
+	for _, node := range m.nodes {
+		ops = append(ops, node.op)
+	}
+	description := fmt.Sprintf("%d features → %s (%s)", m.Features(), m.Output, strings.Join(ops, ", "))
+	if m.producer != "" {
+		description += ", " + m.producer
+	}
+	return description
+}
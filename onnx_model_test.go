@@ -0,0 +1,333 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"testing"
+)
+
+// 고정 모델 (testdata/gen_onnx.py 로 생성, 기대 점수도 같은 스크립트가 계산)
+const (
+	onnxLogRegFixture = "testdata/logreg.onnx"
+	onnxMLPFixture    = "testdata/mlp.onnx"
+)
+
+// onnxGolden 입력과 기대 점수
+type onnxGolden struct {
+	input []float64
+	want  []float64
+}
+
+// checkONNXScores 모델 점수와 기대 점수 비교 (가중치가 float32 이므로 1e-6 허용)
+func checkONNXScores(t *testing.T, model *ONNXModel, cases []onnxGolden) {
+	t.Helper()
+	for _, c := range cases {
+		got, err := model.Run(c.input)
+		if err != nil {
+			t.Errorf("Run(%v): %v", c.input, err)
+			continue
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("Run(%v) = %v, want %v", c.input, got, c.want)
+			continue
+		}
+		for i := range got {
+			if math.Abs(got[i]-c.want[i]) > 1e-6 {
+				t.Errorf("Run(%v) = %v, want %v", c.input, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+// TestONNXLogisticRegression skl2onnx 로지스틱 회귀 (LinearClassifier + Normalizer, 반복 속성 unpacked)
+func TestONNXLogisticRegression(t *testing.T) {
+	model, err := LoadONNXModel(onnxLogRegFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model.Features() != 4 || model.Output != "probabilities" || model.producer != "skl2onnx" {
+		t.Errorf("features=%d output=%s producer=%s", model.Features(), model.Output, model.producer)
+	}
+	if got := strings.Join(model.ClassLabels, ","); got != "0,1" {
+		t.Errorf("ClassLabels = %s, want 0,1", got)
+	}
+	checkONNXScores(t, model, []onnxGolden{
+		{[]float64{0, 0, 0, 0}, []float64{0.562176500885798, 0.437823499114202}},
+		{[]float64{1, 0, 1, 0}, []float64{0.0600866501740076, 0.939913349825992}},
+		{[]float64{0, 2, 0, 1}, []float64{0.939913349825992, 0.0600866501740076}},
+		{[]float64{0.5, -1, 0.25, 3}, []float64{0.0244230900541071, 0.975576909945893}},
+	})
+	if _, err := model.Run([]float64{1, 2, 3}); err == nil {
+		t.Error("Run with the wrong feature count should fail")
+	}
+}
+
+// TestONNXMLP Scaler, MatMul/Add/Relu, Gemm(transB, alpha, beta), Sigmoid, Softmax, Reshape (raw_data, packed 필드)
+func TestONNXMLP(t *testing.T) {
+	model, err := LoadONNXModel(onnxMLPFixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ops []string
+	for _, node := range model.nodes {
+		ops = append(ops, node.op)
+	}
+	if got := strings.Join(ops, ","); got != "Scaler,MatMul,Add,Relu,Gemm,Sigmoid,Softmax,Reshape" {
+		t.Errorf("nodes = %s (unused nodes must be skipped)", got)
+	}
+	if model.Output != "scores" || model.Input.name != "input" || model.Features() != 3 {
+		t.Errorf("input=%s features=%d output=%s", model.Input.name, model.Features(), model.Output)
+	}
+	checkONNXScores(t, model, []onnxGolden{
+		{[]float64{1, 2, 3}, []float64{0.394296946536325, 0.605703053463675}},
+		{[]float64{-1, 0.5, 0}, []float64{0.435029797821956, 0.564970202178044}},
+		{[]float64{0, 0, 0}, []float64{0.507809018529682, 0.492190981470318}},
+	})
+}
+
+// TestONNXOperators 연산자 수학 (고정 모델에 없는 경우)
+func TestONNXOperators(t *testing.T) {
+	tensor := func(shape []int, data ...float64) *onnxTensor { return &onnxTensor{shape: shape, data: data} }
+	attrs := func(pairs ...interface{}) map[string]*onnxAttribute {
+		result := make(map[string]*onnxAttribute)
+		for i := 0; i < len(pairs); i += 2 {
+			result[pairs[i].(string)] = pairs[i+1].(*onnxAttribute)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name   string
+		node   *onnxNode
+		inputs []*onnxTensor
+		want   []float64
+	}{
+		{"gemm transA", &onnxNode{op: "Gemm", attrs: attrs("transA", &onnxAttribute{i: 1})},
+			[]*onnxTensor{tensor([]int{2, 1}, 1, 2), tensor([]int{2, 2}, 1, 2, 3, 4)}, []float64{7, 10}},
+		{"gemm bias broadcast", &onnxNode{op: "Gemm"},
+			[]*onnxTensor{tensor([]int{1, 2}, 1, 1), tensor([]int{2, 3}, 1, 2, 3, 4, 5, 6), tensor([]int{1}, 10)}, []float64{15, 17, 19}},
+		{"sigmoid", &onnxNode{op: "Sigmoid"}, []*onnxTensor{tensor([]int{1, 3}, 0, 2, -2)},
+			[]float64{0.5, 0.8807970779778823, 0.11920292202211755}},
+		{"softmax large values", &onnxNode{op: "Softmax"}, []*onnxTensor{tensor([]int{1, 2}, 1000, 1000)}, []float64{0.5, 0.5}},
+		{"leaky relu", &onnxNode{op: "LeakyRelu", attrs: attrs("alpha", &onnxAttribute{f: 0.1})},
+			[]*onnxTensor{tensor([]int{1, 2}, -2, 3)}, []float64{-0.2, 3}},
+		{"div broadcast", &onnxNode{op: "Div"}, []*onnxTensor{tensor([]int{2, 2}, 2, 4, 6, 8), tensor([]int{2}, 2, 4)}, []float64{1, 1, 3, 2}},
+		{"normalizer max", &onnxNode{op: "Normalizer", attrs: attrs("norm", &onnxAttribute{s: "MAX"})},
+			[]*onnxTensor{tensor([]int{1, 2}, 2, 4)}, []float64{0.5, 1}},
+		{"normalizer l2", &onnxNode{op: "Normalizer", attrs: attrs("norm", &onnxAttribute{s: "L2"})},
+			[]*onnxTensor{tensor([]int{1, 2}, 3, 4)}, []float64{0.6, 0.8}},
+		{"linear classifier softmax", &onnxNode{op: "LinearClassifier", outputs: []string{"", "out"}, attrs: attrs(
+			"coefficients", &onnxAttribute{floats: []float64{1, 0, 0, 1}},
+			"intercepts", &onnxAttribute{floats: []float64{0, 0}},
+			"post_transform", &onnxAttribute{s: "SOFTMAX"})},
+			[]*onnxTensor{tensor([]int{1, 2}, 0, math.Log(3))}, []float64{0.25, 0.75}},
+		{"linear classifier single row", &onnxNode{op: "LinearClassifier", outputs: []string{"", "out"}, attrs: attrs(
+			"coefficients", &onnxAttribute{floats: []float64{2}},
+			"classlabels_ints", &onnxAttribute{ints: []int64{0, 1}})},
+			[]*onnxTensor{tensor([]int{1, 1}, 1.5)}, []float64{-3, 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values := make(map[string]*onnxTensor)
+			test.node.inputs = nil
+			for i, input := range test.inputs {
+				name := fmt.Sprintf("in%d", i)
+				values[name] = input
+				test.node.inputs = append(test.node.inputs, name)
+			}
+			if test.node.outputs == nil {
+				test.node.outputs = []string{"out"}
+			}
+			if err := runONNXNode(test.node, values); err != nil {
+				t.Fatal(err)
+			}
+			got := values["out"].data
+			if len(got) != len(test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if math.Abs(got[i]-test.want[i]) > 1e-9 {
+					t.Fatalf("got %v, want %v", got, test.want)
+				}
+			}
+		})
+	}
+}
+
+// TestProtoReader varint, packed/unpacked 반복 필드, 잘린 입력
+func TestProtoReader(t *testing.T) {
+	varints := []struct {
+		data []byte
+		want uint64
+		ok   bool
+	}{
+		{[]byte{0x00}, 0, true},
+		{[]byte{0x96, 0x01}, 150, true},
+		{[]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, math.MaxUint64, true},
+		{[]byte{0x96}, 0, false},
+		{bytes.Repeat([]byte{0x80}, 11), 0, false},
+		{nil, 0, false},
+	}
+	for _, test := range varints {
+		r := &protoReader{data: test.data}
+		got, err := r.varint()
+		if (err == nil) != test.ok || got != test.want {
+			t.Errorf("varint(% x) = %d, %v; want %d, ok=%v", test.data, got, err, test.want, test.ok)
+		}
+	}
+
+	// 같은 정수 필드의 unpacked 값과 packed 묶음이 이어서 와도 모두 모음
+	data := []byte{1 << 3, 0x05, 1<<3 | protoWireBytes, 3, 0x01, 0x96, 0x01}
+	r := &protoReader{data: data}
+	var ints []int64
+	for !r.done() {
+		_, wire, err := r.next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ints, err = r.appendVarints(ints, wire); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fmt.Sprint(ints) != "[5 1 150]" {
+		t.Errorf("appendVarints = %v, want [5 1 150]", ints)
+	}
+
+	var packed []byte
+	for _, v := range []float32{1.5, -2} {
+		packed = binary.LittleEndian.AppendUint32(packed, math.Float32bits(v))
+	}
+	floats, err := (&protoReader{data: append([]byte{byte(len(packed))}, packed...)}).appendFloat32s(nil, protoWireBytes)
+	if err != nil || fmt.Sprint(floats) != "[1.5 -2]" {
+		t.Errorf("appendFloat32s packed = %v, %v", floats, err)
+	}
+	floats, err = (&protoReader{data: binary.LittleEndian.AppendUint64(nil, math.Float64bits(0.25))}).appendFloat64s(nil, protoWireFixed64)
+	if err != nil || fmt.Sprint(floats) != "[0.25]" {
+		t.Errorf("appendFloat64s = %v, %v", floats, err)
+	}
+
+	for _, test := range []struct {
+		name string
+		data []byte
+		read func(r *protoReader) error
+	}{
+		{"bytes longer than data", []byte{0x05, 'a'}, func(r *protoReader) error { _, err := r.bytes(); return err }},
+		{"fixed32", []byte{1, 2, 3}, func(r *protoReader) error { _, err := r.fixed32(); return err }},
+		{"fixed64", []byte{1, 2, 3, 4, 5, 6, 7}, func(r *protoReader) error { _, err := r.fixed64(); return err }},
+		{"wire type 3", nil, func(r *protoReader) error { return r.skip(3) }},
+		{"packed varints", []byte{0x02, 0x96}, func(r *protoReader) error { _, err := r.appendVarints(nil, protoWireBytes); return err }},
+	} {
+		if err := test.read(&protoReader{data: test.data}); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
+
+// TestONNXMalformed 잘리거나 손상된 모델은 패닉 없이 오류 (불러와진 경우 추론도 패닉 없음)
+func TestONNXMalformed(t *testing.T) {
+	for _, path := range []string{onnxLogRegFixture, onnxMLPFixture} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 그래프 뒤 (opset_import) 에서 필드 경계로 잘린 파일은 올바른 protobuf 이므로 그래프까지만 오류 확인
+		graphEnd := bytes.Index(data, protoSubmessage(data, 7)) + len(protoSubmessage(data, 7))
+		for length := 0; length < len(data); length++ {
+			if model, err := tryONNXModel(t, data[:length]); err == nil && length < graphEnd {
+				t.Errorf("%s truncated to %d bytes loaded without error: %s", path, length, describeONNXModel(model))
+			}
+		}
+		for offset := range data {
+			for _, value := range []byte{0x00, 0x7f, 0xff} {
+				corrupted := append([]byte{}, data...)
+				corrupted[offset] = value
+				tryONNXModel(t, corrupted)
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"empty", nil, "no graph"},
+		{"not protobuf", []byte("not an onnx model"), ""},
+		{"no inputs", protoTestField(7, protoTestField(2, []byte("g"))), "exactly one input"},
+	} {
+		_, err := tryONNXModel(t, test.data)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: error %v, want %q", test.name, err, test.want)
+		}
+	}
+}
+
+// tryONNXModel 패닉을 테스트 실패로 바꿔 모델 디코딩과 추론 실행
+func tryONNXModel(t *testing.T, data []byte) (model *ONNXModel, err error) {
+	t.Helper()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			t.Fatalf("panic on malformed model (% x): %v", data, recovered)
+		}
+	}()
+	if model, err = parseONNXModel(data); err != nil {
+		return nil, err
+	}
+	model.Run(make([]float64, model.Features()))
+	return model, nil
+}
+
+// protoTestField 길이 구분 필드 인코딩
+func protoTestField(field int, value []byte) []byte {
+	return append([]byte{byte(field<<3 | protoWireBytes), byte(len(value))}, value...)
+}
+
+// TestONNXOperatorErrors 모양이 맞지 않는 입력은 패닉 없이 오류
+func TestONNXOperatorErrors(t *testing.T) {
+	scalar := &onnxTensor{data: []float64{1}}
+	tests := []struct {
+		name  string
+		node  *onnxNode
+		input *onnxTensor
+	}{
+		{"scaler scalar", &onnxNode{op: "Scaler"}, scalar},
+		{"normalizer scalar", &onnxNode{op: "Normalizer"}, scalar},
+		{"linear classifier scalar", &onnxNode{op: "LinearClassifier"}, scalar},
+		{"flatten axis", &onnxNode{op: "Flatten", attrs: map[string]*onnxAttribute{"axis": {i: 3}}}, &onnxTensor{shape: []int{1, 2}, data: []float64{1, 2}}},
+		{"softmax axis", &onnxNode{op: "Softmax", attrs: map[string]*onnxAttribute{"axis": {i: 2}}}, &onnxTensor{shape: []int{1, 2}, data: []float64{1, 2}}},
+		{"matmul mismatch", &onnxNode{op: "MatMul", inputs: []string{"x", "x"}}, &onnxTensor{shape: []int{1, 2}, data: []float64{1, 2}}},
+		{"missing second input", &onnxNode{op: "Add"}, scalar},
+	}
+	for _, test := range tests {
+		if test.node.inputs == nil {
+			test.node.inputs = []string{"x"}
+		}
+		test.node.outputs = []string{"out"}
+		if err := runONNXNode(test.node, map[string]*onnxTensor{"x": test.input}); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+
+	// Flatten 은 크기 0 차원이 있어도 0 으로 나누지 않음
+	flatten := &onnxNode{op: "Flatten", inputs: []string{"x"}, outputs: []string{"out"}}
+	values := map[string]*onnxTensor{"x": {shape: []int{0, 3}}}
+	if err := runONNXNode(flatten, values); err != nil || fmt.Sprint(values["out"].shape) != "[0 3]" {
+		t.Errorf("Flatten of an empty tensor = %v, %v", values["out"], err)
+	}
+
+	for _, dims := range [][]byte{
+		{1<<3 | protoWireVarint, 0x7f, 1<<3 | protoWireVarint, 0x7f},                               // 127 x 127 인데 값 없음
+		{1 << 3, 0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},                       // -2
+		append(bytes.Repeat([]byte{1 << 3, 0x80, 0x80, 0x80, 0x80, 0x08}, 3), 2<<3, onnxTypeFloat), // 2^31 x 3
+	} {
+		if _, _, err := parseONNXTensor(dims); err == nil {
+			t.Errorf("parseONNXTensor(% x) should fail", dims)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+/*
+OpenAI Provider Module
+======================
+
+OpenAI Chat Completions API 호환 LLM 백엔드 (ai_analysis.provider: openai)
+
+주요 기능:
+- POST {base_url}/chat/completions (기본 https://api.openai.com/v1)
+- base_url 로 OpenAI 호환 서버 사용 (Azure OpenAI 프록시, vLLM, LM Studio, OpenRouter 등)
+- API 키: 설정 파일 api_key 또는 OPENAI_API_KEY (기본 엔드포인트가 아니면 키 없이도 호출)
+- 시스템 진단은 response_format json_schema 로 구조화 응답 요청
+*/
+package main
+
+import (
+	"fmt" // 형식화된 I/O
+)
+
+// openAIChatRequest Chat Completions 요청
+type openAIChatRequest struct {
+	Model          string                `json:"model"`
+	Messages       []openAIChatMessage   `json:"messages"`
+	Temperature    float64               `json:"temperature"`
+	MaxTokens      int                   `json:"max_tokens"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+// openAIChatMessage 대화 메시지
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIResponseFormat 구조화 응답 형식
+type openAIResponseFormat struct {
+	Type       string           `json:"type"` // json_schema
+	JSONSchema openAIJSONSchema `json:"json_schema"`
+}
+
+// openAIJSONSchema 응답 JSON 스키마
+type openAIJSONSchema struct {
+	Name   string                 `json:"name"`
+	Schema map[string]interface{} `json:"schema"`
+}
+
+// openAIChatResponse Chat Completions 응답
+type openAIChatResponse struct {
+	Choices []struct {
+		Message      openAIChatMessage `json:"message"`
+		FinishReason string            `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// OpenAIProvider OpenAI 호환 API 백엔드
+type OpenAIProvider struct {
+	llmBase
+}
+
+// NewOpenAIProvider OpenAI 백엔드 생성 (config 는 withDefaults 를 거친 설정)
+func NewOpenAIProvider(config *LLMConfig) *OpenAIProvider {
+	return &OpenAIProvider{llmBase: newLLMBase(config)}
+}
+
+// AnalyzeSystemDiagnosis 시스템 진단 분석
+func (op *OpenAIProvider) AnalyzeSystemDiagnosis(metrics SystemMetrics) (string, error) {
+	return op.analyzeSystemDiagnosis(metrics, op.complete)
+}
+
+// AnalyzeLogPattern 로그 패턴 분석
+func (op *OpenAIProvider) AnalyzeLogPattern(logLine string, context map[string]string) (string, error) {
+	return op.analyzeLogPattern(logLine, context, op.complete)
+}
+
+// AnalyzeLogBatch 관련 로그 묶음 분석
+func (op *OpenAIProvider) AnalyzeLogBatch(lines []string, context map[string]string) (string, error) {
+	return op.analyzeLogBatch(lines, context, op.complete)
+}
+
+// AnalyzeSecurityThreat 보안 위협 분석
+func (op *OpenAIProvider) AnalyzeSecurityThreat(threatData map[string]interface{}) (string, error) {
+	return op.analyzeSecurityThreat(threatData, op.complete)
+}
+
+// SummarizeDigest 일일 요약 보고서 작성
+func (op *OpenAIProvider) SummarizeDigest(summary *DigestSummary) (string, error) {
+	return op.summarizeDigest(summary, op.complete)
+}
+
+// complete Chat Completions 호출 (schema 가 있으면 json_schema 응답 형식)
+func (op *OpenAIProvider) complete(prompt string, schema *GeminiSchema) (string, error) {
+	config := op.currentConfig()
+	request := openAIChatRequest{
+		Model:       config.Model,
+		Messages:    []openAIChatMessage{{Role: "user", Content: prompt}},
+		Temperature: config.Temperature,
+		MaxTokens:   config.MaxTokens,
+	}
+	if schema != nil {
+		request.ResponseFormat = &openAIResponseFormat{
+			Type:       "json_schema",
+			JSONSchema: openAIJSONSchema{Name: "expert_diagnosis", Schema: schema.jsonSchema()},
+		}
+	}
+
+	headers := map[string]string{}
+	if config.APIKey != "" {
+		headers["Authorization"] = "Bearer " + config.APIKey
+	}
+	var response openAIChatResponse
+	if err := op.postJSON("OpenAI", config.BaseURL+"/chat/completions", headers, request, &response); err != nil {
+		return "", err
+	}
+	if len(response.Choices) == 0 || response.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("no choices in response")
+	}
+	return response.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,556 @@
+/*
+Output Buffer Module
+====================
+
+출력 대상 장애 시 디스크 버퍼링 (-output-buffer)
+
+Elasticsearch/Kafka 가 재시도 후에도 응답하지 않으면 보내지 못한 문서를 버리지 않고 디스크에 기록했다가,
+대상이 복구되면 오래된 순서대로 다시 전송합니다. 버퍼에 밀린 문서가 있는 동안에는 순서를 지키기 위해
+새 문서도 버퍼 뒤에 추가합니다.
+
+주요 기능:
+- 출력별 디렉터리에 세그먼트 파일(8MB)로 기록, 레코드마다 길이와 CRC-32 를 붙여 잘린 꼬리는 시작 시 잘라냄
+- 전송한 위치는 cursor 파일에 기록하여 재시작 후 이어서 재전송 (마지막 배치는 중복 전송될 수 있음)
+- 크기 한도(-output-buffer-size)에 도달하면 새 레코드를 버리고 버린 건수를 집계
+- 재전송 실패 시 지수 백오프 (1초부터 최대 30초), 대기 중에도 새 문서는 계속 버퍼에 기록
+- 한도의 80% 를 넘으면 output_buffer 알림 1회 (50% 아래로 내려가면 다시 알림)
+- GET /status 의 output_buffers 로 대기/기록/재전송/버린 레코드 수 조회
+*/
+package main
+
+import (
+	"bufio"           // 세그먼트 읽기
+	"encoding/binary" // 레코드 헤더
+	"fmt"             // 형식화된 I/O
+	"hash/crc32"      // 레코드 체크섬
+	"io"              // 레코드 읽기
+	"os"              // 파일 처리
+	"path/filepath"   // 세그먼트 경로
+	"sort"            // 세그먼트 정렬
+	"strconv"         // 세그먼트 번호
+	"strings"         // 파일 이름 처리
+	"sync"            // 동기화
+	"time"            // 백오프
+)
+
+// 출력 버퍼 설정
+const (
+	DefaultOutputBufferSizeMB  = 256              // 출력별 기본 버퍼 크기 한도 (MB)
+	OutputBufferSegmentSize    = 8 << 20          // 세그먼트 파일 최대 크기
+	OutputBufferPressureRatio  = 0.8              // 한도 대비 이 비율을 넘으면 알림
+	OutputBufferRecoverRatio   = 0.5              // 이 비율 아래로 내려가면 알림 재설정
+	OutputBufferInitialBackoff = 1 * time.Second  // 재전송 실패 후 첫 대기 시간 (실패마다 2배)
+	OutputBufferMaxBackoff     = 30 * time.Second // 재전송 대기 시간 상한
+	outputBufferRecordHeader   = 8                // 레코드 헤더 (길이 4바이트 + CRC-32 4바이트)
+	outputBufferMaxRecord      = 64 << 20         // 손상된 길이 필드를 걸러내기 위한 레코드 최대 크기
+	outputBufferCursorFile     = "cursor"
+	outputBufferSegmentExt     = ".buf"
+)
+
+// BufferStats 출력 버퍼 상태 (GET /status)
+type BufferStats struct {
+	Output   string `json:"output"`
+	Path     string `json:"path"`
+	Records  int64  `json:"records"`   // 재전송 대기 중인 레코드 수
+	Bytes    int64  `json:"bytes"`     // 재전송 대기 중인 바이트
+	MaxBytes int64  `json:"max_bytes"` // 크기 한도
+	Buffered int64  `json:"buffered"`  // 시작 후 버퍼에 기록한 레코드 수
+	Drained  int64  `json:"drained"`   // 시작 후 재전송한 레코드 수
+	Dropped  int64  `json:"dropped"`   // 버퍼가 가득 차 버린 레코드 수
+}
+
+// bufferPosition 세그먼트 번호와 파일 내 위치
+type bufferPosition struct {
+	seq    int64
+	offset int64
+}
+
+// OutputBuffer 출력 하나의 디스크 버퍼 (기록은 아무 고루틴, 재전송은 출력의 전송 고루틴에서만)
+type OutputBuffer struct {
+	name     string
+	dir      string
+	maxBytes int64
+	logger   Logger
+
+	mu          sync.Mutex
+	segments    []int64        // 남아 있는 세그먼트 번호 (오래된 순)
+	head        bufferPosition // 다음에 재전송할 위치
+	peeked      bufferPosition // 마지막으로 읽은 배치의 끝 위치 (commit 시 head 로 이동)
+	peekedCount int64
+	writer      *os.File
+	writerSize  int64
+	records     int64
+	bytes       int64
+	stats       BufferStats
+	warned      bool
+	pressure    func(BufferStats)
+	closed      bool
+
+	// 아래 필드는 재전송 고루틴에서만 사용
+	retryAt time.Time
+	backoff time.Duration
+}
+
+// OpenOutputBuffer 출력 버퍼 열기 (남아 있는 레코드는 다음 재전송 때 전송)
+func OpenOutputBuffer(dir, name string, maxBytes int64, logger Logger) (*OutputBuffer, error) {
+	dir = expandHomePath(dir)
+	if maxBytes <= 0 {
+		maxBytes = DefaultOutputBufferSizeMB << 20
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create output buffer directory: %v", err)
+	}
+
+	b := &OutputBuffer{
+		name:     name,
+		dir:      dir,
+		maxBytes: maxBytes,
+		logger:   logger,
+		stats:    BufferStats{Output: name, Path: dir, MaxBytes: maxBytes},
+	}
+	if err := b.load(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// load 세그먼트 목록과 cursor 를 읽고 남은 레코드 수 집계 (잘린 꼬리는 잘라냄)
+func (b *OutputBuffer) load() error {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read output buffer directory: %v", err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, outputBufferSegmentExt) {
+			continue
+		}
+		seq, err := strconv.ParseInt(strings.TrimSuffix(name, outputBufferSegmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		b.segments = append(b.segments, seq)
+	}
+	sort.Slice(b.segments, func(i, j int) bool { return b.segments[i] < b.segments[j] })
+
+	// cursor 이전의 세그먼트는 이미 전송됨
+	cursor := bufferPosition{seq: 1}
+	if data, err := os.ReadFile(filepath.Join(b.dir, outputBufferCursorFile)); err == nil {
+		if _, err := fmt.Sscanf(string(data), "%d %d", &cursor.seq, &cursor.offset); err != nil {
+			cursor = bufferPosition{seq: 1}
+		}
+	}
+	for len(b.segments) > 0 && b.segments[0] < cursor.seq {
+		os.Remove(b.segmentPath(b.segments[0]))
+		b.segments = b.segments[1:]
+	}
+	switch {
+	case len(b.segments) == 0:
+		b.segments = []int64{cursor.seq}
+		b.head = bufferPosition{seq: cursor.seq}
+	case b.segments[0] == cursor.seq:
+		b.head = cursor
+	default:
+		b.head = bufferPosition{seq: b.segments[0]}
+	}
+
+	for i, seq := range b.segments {
+		start := int64(0)
+		if seq == b.head.seq {
+			start = b.head.offset
+		}
+		count, end, err := b.scanSegment(seq, start)
+		if err != nil {
+			return err
+		}
+		if start > end {
+			// cursor 가 잘라낸 꼬리 뒤를 가리킴
+			b.head.offset, start = end, end
+		}
+		b.records += count
+		b.bytes += end - start
+		if i == len(b.segments)-1 {
+			b.writerSize = end
+		}
+	}
+
+	writer, err := os.OpenFile(b.segmentPath(b.segments[len(b.segments)-1]), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open output buffer segment: %v", err)
+	}
+	b.writer = writer
+	b.stats.Records = b.records
+	b.stats.Bytes = b.bytes
+	return nil
+}
+
+// scanSegment 세그먼트의 start 이후 레코드 수와 마지막 정상 레코드의 끝 위치 (이후 손상 부분은 잘라냄)
+func (b *OutputBuffer) scanSegment(seq, start int64) (int64, int64, error) {
+	path := b.segmentPath(seq)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to open output buffer segment: %v", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var count, end int64
+	for {
+		record, err := readBufferRecord(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			b.logger.Errorf("⚠️  Output buffer %s: truncating damaged segment %s at offset %d: %v", b.name, filepath.Base(path), end, err)
+			if err := os.Truncate(path, end); err != nil {
+				return 0, 0, fmt.Errorf("failed to truncate output buffer segment: %v", err)
+			}
+			break
+		}
+		if end >= start {
+			count++
+		}
+		end += int64(outputBufferRecordHeader + len(record))
+	}
+	return count, end, nil
+}
+
+// readBufferRecord 레코드 하나 읽기 (파일 끝이면 io.EOF, 잘렸거나 손상되었으면 에러)
+func readBufferRecord(reader io.Reader) ([]byte, error) {
+	var header [outputBufferRecordHeader]byte
+	if _, err := io.ReadFull(reader, header[:]); err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("truncated record header")
+	}
+	size := binary.BigEndian.Uint32(header[:4])
+	if size > outputBufferMaxRecord {
+		return nil, fmt.Errorf("invalid record size %d", size)
+	}
+	record := make([]byte, size)
+	if _, err := io.ReadFull(reader, record); err != nil {
+		return nil, fmt.Errorf("truncated record")
+	}
+	if crc32.ChecksumIEEE(record) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, fmt.Errorf("checksum mismatch")
+	}
+	return record, nil
+}
+
+// segmentPath 세그먼트 파일 경로
+func (b *OutputBuffer) segmentPath(seq int64) string {
+	return filepath.Join(b.dir, fmt.Sprintf("%012d%s", seq, outputBufferSegmentExt))
+}
+
+// Name 출력 이름
+func (b *OutputBuffer) Name() string {
+	return b.name
+}
+
+// Len 재전송 대기 중인 레코드 수
+func (b *OutputBuffer) Len() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.records
+}
+
+// Stats 현재 상태 (복사본)
+func (b *OutputBuffer) Stats() BufferStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stats
+}
+
+// OnPressure 버퍼가 한도에 가까워졌을 때 호출할 콜백 설정
+func (b *OutputBuffer) OnPressure(callback func(BufferStats)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pressure = callback
+}
+
+// Append 레코드를 버퍼 뒤에 기록 (한도를 넘는 레코드는 버림), 기록한 레코드 수 반환
+func (b *OutputBuffer) Append(records [][]byte) int {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return 0
+	}
+	wasEmpty := b.records == 0
+	stored, dropped := 0, 0
+	for _, record := range records {
+		size := int64(outputBufferRecordHeader + len(record))
+		if b.bytes+size > b.maxBytes {
+			dropped++
+			continue
+		}
+		if b.writerSize > 0 && b.writerSize+size > OutputBufferSegmentSize {
+			if err := b.rollSegment(); err != nil {
+				b.logger.Errorf("❌ Output buffer %s: %v", b.name, err)
+				dropped += len(records) - stored - dropped
+				break
+			}
+		}
+		frame := make([]byte, size)
+		binary.BigEndian.PutUint32(frame[:4], uint32(len(record)))
+		binary.BigEndian.PutUint32(frame[4:8], crc32.ChecksumIEEE(record))
+		copy(frame[outputBufferRecordHeader:], record)
+		if _, err := b.writer.Write(frame); err != nil {
+			b.logger.Errorf("❌ Output buffer %s: failed to write record: %v", b.name, err)
+			dropped += len(records) - stored - dropped
+			break
+		}
+		b.writerSize += size
+		b.records++
+		b.bytes += size
+		stored++
+	}
+	if stored > 0 {
+		if err := b.writer.Sync(); err != nil {
+			b.logger.Errorf("❌ Output buffer %s: failed to sync segment: %v", b.name, err)
+		}
+	}
+	b.stats.Buffered += int64(stored)
+	b.stats.Dropped += int64(dropped)
+	b.stats.Records = b.records
+	b.stats.Bytes = b.bytes
+
+	if wasEmpty && stored > 0 {
+		b.logger.Infof("💾 %s unreachable, buffering to %s until it recovers", b.name, b.dir)
+	}
+	if dropped > 0 {
+		b.logger.Errorf("⚠️  Output buffer %s full (%d MB), dropped %d records", b.name, b.maxBytes>>20, dropped)
+	}
+	var callback func(BufferStats)
+	if !b.warned && float64(b.bytes) >= float64(b.maxBytes)*OutputBufferPressureRatio {
+		b.warned = true
+		callback = b.pressure
+	}
+	stats := b.stats
+	b.mu.Unlock()
+
+	if callback != nil {
+		callback(stats)
+	}
+	return stored
+}
+
+// rollSegment 현재 세그먼트를 닫고 새 세그먼트 시작
+func (b *OutputBuffer) rollSegment() error {
+	seq := b.segments[len(b.segments)-1] + 1
+	writer, err := os.OpenFile(b.segmentPath(seq), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create segment: %v", err)
+	}
+	b.writer.Close()
+	b.writer = writer
+	b.writerSize = 0
+	b.segments = append(b.segments, seq)
+	return nil
+}
+
+// peek head 부터 최대 maxRecords 개/maxBytes 바이트의 레코드 읽기 (commit 전까지 버퍼에 남음)
+func (b *OutputBuffer) peek(maxRecords, maxBytes int) ([][]byte, error) {
+	b.mu.Lock()
+	position := b.head
+	segments := append([]int64(nil), b.segments...)
+	b.mu.Unlock()
+
+	var records [][]byte
+	size := 0
+	for i, seq := range segments {
+		if seq < position.seq {
+			continue
+		}
+		if seq > position.seq {
+			position = bufferPosition{seq: seq}
+		}
+		file, err := os.Open(b.segmentPath(seq))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open segment: %v", err)
+		}
+		if _, err := file.Seek(position.offset, io.SeekStart); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to seek segment: %v", err)
+		}
+		reader := bufio.NewReader(file)
+		for len(records) < maxRecords && size < maxBytes {
+			record, err := readBufferRecord(reader)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				// 기록 중인 마지막 세그먼트의 꼬리는 다음 호출에서 다시 읽음
+				if i == len(segments)-1 {
+					break
+				}
+				file.Close()
+				return nil, fmt.Errorf("segment %d offset %d: %v", seq, position.offset, err)
+			}
+			records = append(records, record)
+			size += len(record)
+			position.offset += int64(outputBufferRecordHeader + len(record))
+		}
+		file.Close()
+		if len(records) >= maxRecords || size >= maxBytes {
+			break
+		}
+	}
+
+	b.mu.Lock()
+	b.peeked = position
+	b.peekedCount = int64(len(records))
+	b.mu.Unlock()
+	return records, nil
+}
+
+// commit 마지막으로 읽은 배치를 버퍼에서 제거 (다 읽은 세그먼트 삭제, cursor 기록)
+func (b *OutputBuffer) commit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	consumed := int64(0)
+	for len(b.segments) > 1 && b.segments[0] < b.peeked.seq {
+		info, err := os.Stat(b.segmentPath(b.segments[0]))
+		if err == nil {
+			consumed += info.Size() - b.head.offset
+		}
+		os.Remove(b.segmentPath(b.segments[0]))
+		b.segments = b.segments[1:]
+		b.head = bufferPosition{seq: b.segments[0]}
+	}
+	consumed += b.peeked.offset - b.head.offset
+	b.head = b.peeked
+	b.records -= b.peekedCount
+	b.bytes -= consumed
+	b.peekedCount = 0
+
+	// 모두 전송했으면 기록 중인 세그먼트를 비워 디스크 사용량을 줄임
+	if b.records <= 0 && len(b.segments) == 1 {
+		if err := b.writer.Truncate(0); err == nil {
+			b.writerSize = 0
+			b.head.offset = 0
+		}
+		b.records, b.bytes = 0, 0
+	}
+	if err := os.WriteFile(filepath.Join(b.dir, outputBufferCursorFile), []byte(fmt.Sprintf("%d %d\n", b.head.seq, b.head.offset)), 0600); err != nil {
+		b.logger.Errorf("❌ Output buffer %s: failed to write cursor: %v", b.name, err)
+	}
+
+	b.stats.Records = b.records
+	b.stats.Bytes = b.bytes
+	if b.warned && float64(b.bytes) < float64(b.maxBytes)*OutputBufferRecoverRatio {
+		b.warned = false
+	}
+}
+
+// Drain 오래된 레코드부터 send 로 재전송 (budget 동안 배치 단위, 실패하면 백오프 후 다음 호출에서 재시도)
+// send 는 다시 보내야 할 레코드를 반환 (전부 반환하면 실패로 보고 버퍼에 그대로 둠, 일부면 버퍼 뒤에 다시 기록)
+func (b *OutputBuffer) Drain(maxRecords, maxBytes int, budget time.Duration, send func([][]byte) ([][]byte, error)) {
+	if b.Len() == 0 || time.Now().Before(b.retryAt) {
+		return
+	}
+
+	deadline := time.Now().Add(budget)
+	drained := 0
+	for b.Len() > 0 && time.Now().Before(deadline) {
+		records, err := b.peek(maxRecords, maxBytes)
+		if err != nil {
+			b.logger.Errorf("❌ Output buffer %s: failed to read buffered records: %v", b.name, err)
+			b.delay()
+			return
+		}
+		if len(records) == 0 {
+			return
+		}
+
+		retry, err := send(records)
+		if len(retry) >= len(records) {
+			b.delay()
+			b.logger.Errorf("❌ %s still unreachable, %d records buffered (retry in %s): %v", b.name, b.Len(), b.backoff, err)
+			return
+		}
+		b.commit()
+		if len(retry) > 0 {
+			b.Append(retry)
+		}
+		if err != nil {
+			b.logger.Errorf("❌ %s rejected buffered records: %v", b.name, err)
+		}
+		b.backoff = 0
+		drained += len(records) - len(retry)
+		b.mu.Lock()
+		b.stats.Drained += int64(len(records) - len(retry))
+		b.mu.Unlock()
+	}
+
+	if drained > 0 {
+		if remaining := b.Len(); remaining > 0 {
+			b.logger.Infof("💾 %s recovering: resent %d buffered records, %d remaining", b.name, drained, remaining)
+		} else {
+			b.logger.Infof("✅ %s recovered: resent %d buffered records, buffer empty", b.name, drained)
+		}
+	}
+}
+
+// delay 다음 재전송 시도까지 대기 (실패마다 2배)
+func (b *OutputBuffer) delay() {
+	if b.backoff == 0 {
+		b.backoff = OutputBufferInitialBackoff
+	} else if b.backoff *= 2; b.backoff > OutputBufferMaxBackoff {
+		b.backoff = OutputBufferMaxBackoff
+	}
+	b.retryAt = time.Now().Add(b.backoff)
+}
+
+// Close 버퍼 파일 닫기 (남은 레코드는 다음 시작 시 재전송)
+func (b *OutputBuffer) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	if b.writer != nil {
+		b.writer.Close()
+	}
+	if b.records > 0 {
+		b.logger.Infof("💾 %s: %d records left in %s, resending on next start", b.name, b.records, b.dir)
+	}
+}
+
+// outputBufferAlert 출력 버퍼가 한도에 가까워졌다는 알림
+func outputBufferAlert(stats BufferStats) Alert {
+	percent := 0
+	if stats.MaxBytes > 0 {
+		percent = int(stats.Bytes * 100 / stats.MaxBytes)
+	}
+	return Alert{
+		Type:     AlertTypeOutputBuffer,
+		Severity: AlertSeverityWarning,
+		Title:    fmt.Sprintf("[%s OUTPUT BUFFER] %s buffer %d%% full", AppName, stats.Output, percent),
+		Headline: fmt.Sprintf("💾 %s 출력의 디스크 버퍼가 한도에 가까워졌습니다", stats.Output),
+		Sections: []AlertSection{{
+			Fields: []AlertField{
+				{Label: "출력", Value: stats.Output, Short: true},
+				{Label: "사용량", Value: fmt.Sprintf("%d MB / %d MB (%d%%)", stats.Bytes>>20, stats.MaxBytes>>20, percent), Short: true},
+				{Label: "대기 레코드", Value: fmt.Sprintf("%d", stats.Records), Short: true},
+				{Label: "버린 레코드", Value: fmt.Sprintf("%d", stats.Dropped), Short: true},
+				{Label: "경로", Value: stats.Path},
+				{Label: "조치", Value: "대상이 복구되면 자동으로 재전송합니다. 한도에 도달하면 새 이벤트를 버리므로 대상 상태를 확인하세요"},
+			},
+			Summary: true,
+		}},
+		Thread: alertThreadKey(AlertTypeOutputBuffer, stats.Output),
+		Fields: map[string]string{
+			"output":    stats.Output,
+			"bytes":     fmt.Sprintf("%d", stats.Bytes),
+			"max_bytes": fmt.Sprintf("%d", stats.MaxBytes),
+			"records":   fmt.Sprintf("%d", stats.Records),
+			"dropped":   fmt.Sprintf("%d", stats.Dropped),
+		},
+	}
+}
@@ -0,0 +1,356 @@
+/*
+Output File Module
+==================
+
+-output 파일 관리 (크기 기준 교체, 제한된 권한, 모니터 자신의 오류 감시)
+
+-output 을 지정하면 운영 로그, 필터링된 로그, 알림 전송 기록이 한 파일에 계속 쌓이므로
+크기 기준으로 교체하고, 다른 사용자가 읽거나 쓸 수 없는 권한으로 만듭니다.
+
+주요 기능:
+  - 크기 기준 교체: -output-max-size (MB, 기본 100) 를 넘으면 path → path.1 → ... → path.N (-output-keep, 기본 5개 보관)
+  - 권한: 새 파일과 교체한 파일은 -output-mode (기본 0600), 더 넓은 권한의 기존 파일은 시작 시 권한을 줄임 (다른 사용자 쓰기 권한은 거부)
+  - 자기 감시 (-output-self-watch): 출력 파일을 내부 소스로 등록해 모니터 자신의 오류 (알림 전송 실패, 출력/저장소 오류)를
+    monitor_error 알림으로 전송 (같은 오류는 30분에 한 번, 그 사이 반복 횟수 포함)
+  - json/ndjson 출력 (-output-format) 도 같은 권한 적용, ndjson 은 같은 기준으로 교체 (json 은 하나의 배열이므로 교체하지 않음)
+
+감시 대상 오류는 모니터 로거의 error 줄입니다. 감시 중인 로그의 ERROR 줄(fields.level=ERROR)과
+monitor_error 알림 자체의 전송 실패는 알림 반복을 막기 위해 제외합니다.
+*/
+package main
+
+import (
+	"bytes"   // 줄 단위 분리
+	"fmt"     // 형식화된 I/O
+	"os"      // 파일 열기, 이름 변경, 권한
+	"regexp"  // 오류 메시지 정규화
+	"strconv" // 권한 파싱, 로그 필드 인용 해제
+	"strings" // 문자열 처리
+	"sync"    // 동시성 제어
+	"time"    // 알림 간격
+)
+
+// 출력 파일 기본값
+const (
+	DefaultOutputMaxSizeMB   = 100              // 교체 기준 크기 (MB, 0 이면 교체 안 함)
+	DefaultOutputKeep        = 5                // 보관할 이전 파일 수
+	DefaultOutputMode        = "0600"           // 출력 파일 권한
+	DefaultSelfWatchInterval = 30 * time.Minute // 같은 오류 알림 간격
+	selfWatchQueueSize       = 256              // 알림 전 대기 줄 수 (가득 차면 버림)
+	maxSelfWatchKeys         = 200              // 기억하는 오류 종류 수
+)
+
+// OutputFileOptions 출력 파일 교체/권한 설정
+type OutputFileOptions struct {
+	MaxSizeMB int         // 교체 기준 크기 (0 이면 교체 안 함)
+	Keep      int         // 보관할 이전 파일 수
+	Mode      os.FileMode // 파일 권한
+}
+
+// outputFileOptions -output 파일 설정 (main 에서 옵션을 읽은 뒤 모니터 생성 전에 설정)
+var outputFileOptions = OutputFileOptions{MaxSizeMB: DefaultOutputMaxSizeMB, Keep: DefaultOutputKeep, Mode: 0600}
+
+// ParseOutputFileMode 8진수 권한 문자열 파싱 (다른 사용자 쓰기 권한 거부)
+func ParseOutputFileMode(text string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(text, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid file mode %q (use octal, e.g. 0600 or 0640)", text)
+	}
+	if mode&0002 != 0 {
+		return 0, fmt.Errorf("file mode %s lets every user write the output file", text)
+	}
+	if mode&0200 == 0 {
+		return 0, fmt.Errorf("file mode %s does not let the monitor write the output file", text)
+	}
+	return os.FileMode(mode), nil
+}
+
+// OutputFile 크기 기준으로 교체하는 출력 파일 (io.Writer, 로거 출력 대상)
+type OutputFile struct {
+	path     string
+	options  OutputFileOptions
+	file     *os.File
+	size     int64
+	observer func(p []byte) // 기록한 내용을 받는 감시자 (자기 감시, 막히지 않아야 함)
+	mutex    sync.Mutex
+}
+
+// OpenOutputFile 출력 파일 열기 (이어쓰기, 기존 파일 권한을 options.Mode 로 줄임)
+func OpenOutputFile(path string, options OutputFileOptions) (*OutputFile, error) {
+	of := &OutputFile{path: path, options: options}
+	if err := of.open(); err != nil {
+		return nil, err
+	}
+	return of, nil
+}
+
+// open 파일 열기 (호출자가 잠금 보유 또는 생성 중)
+func (of *OutputFile) open() error {
+	file, err := os.OpenFile(of.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, of.options.Mode)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat output file: %v", err)
+	}
+	// 이전 버전이 0666 으로 만든 파일 등 설정보다 넓은 권한은 줄임 (파일 소유자가 아니면 실패해도 계속)
+	if info.Mode().Perm()&^of.options.Mode != 0 {
+		file.Chmod(of.options.Mode)
+	}
+	of.file = file
+	of.size = info.Size()
+	return nil
+}
+
+// Path 출력 파일 경로
+func (of *OutputFile) Path() string {
+	return of.path
+}
+
+// SetObserver 기록한 내용을 받을 감시자 설정 (로거 잠금 안에서 호출되므로 막히지 않아야 함)
+func (of *OutputFile) SetObserver(observer func(p []byte)) {
+	of.mutex.Lock()
+	defer of.mutex.Unlock()
+	of.observer = observer
+}
+
+// Write 기록 (기준 크기를 넘으면 먼저 교체)
+func (of *OutputFile) Write(p []byte) (int, error) {
+	of.mutex.Lock()
+	if of.file == nil {
+		of.mutex.Unlock()
+		return 0, os.ErrClosed
+	}
+	if limit := int64(of.options.MaxSizeMB) << 20; limit > 0 && of.size > 0 && of.size+int64(len(p)) > limit {
+		if err := of.rotate(); err != nil {
+			// 교체하지 못해도 기록은 계속 (디스크가 가득 찰 때까지 이어쓰기)
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to rotate output file %s: %v\n", of.path, err)
+		}
+	}
+	n, err := of.file.Write(p)
+	of.size += int64(n)
+	observer := of.observer
+	of.mutex.Unlock()
+
+	if observer != nil && n > 0 {
+		observer(p[:n])
+	}
+	return n, err
+}
+
+// rotate path → path.1 → ... → path.N 로 밀고 새 파일 열기 (호출자가 잠금 보유)
+func (of *OutputFile) rotate() error {
+	of.file.Close()
+	of.file = nil
+	if of.options.Keep <= 0 {
+		os.Remove(of.path)
+	} else {
+		os.Remove(fmt.Sprintf("%s.%d", of.path, of.options.Keep))
+		for i := of.options.Keep - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", of.path, i), fmt.Sprintf("%s.%d", of.path, i+1))
+		}
+		if err := os.Rename(of.path, of.path+".1"); err != nil && !os.IsNotExist(err) {
+			of.open()
+			return err
+		}
+	}
+	return of.open()
+}
+
+// Sync 기록한 내용을 디스크에 반영 (종료 시, 이후 로그도 계속 기록할 수 있도록 닫지 않음)
+func (of *OutputFile) Sync() error {
+	of.mutex.Lock()
+	defer of.mutex.Unlock()
+	if of.file == nil {
+		return nil
+	}
+	return of.file.Sync()
+}
+
+// Close 출력 파일 닫기
+func (of *OutputFile) Close() error {
+	of.mutex.Lock()
+	defer of.mutex.Unlock()
+	if of.file == nil {
+		return nil
+	}
+	err := of.file.Close()
+	of.file = nil
+	return err
+}
+
+// selfWatchEntry 같은 종류 오류의 알림 상태
+type selfWatchEntry struct {
+	lastAlert time.Time
+	pending   int    // 마지막 알림 뒤 반복 횟수
+	example   string // 마지막 오류 메시지
+}
+
+// SelfWatch 출력 파일에 기록되는 모니터 자신의 오류 감시
+type SelfWatch struct {
+	path     string
+	dispatch func(Alert)
+	interval time.Duration
+	lines    chan string
+	entries  map[string]*selfWatchEntry
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewSelfWatch 자기 감시 시작 (dispatch 는 별도 고루틴에서 호출)
+func NewSelfWatch(path string, dispatch func(Alert)) *SelfWatch {
+	sw := &SelfWatch{
+		path:     path,
+		dispatch: dispatch,
+		interval: DefaultSelfWatchInterval,
+		lines:    make(chan string, selfWatchQueueSize),
+		entries:  make(map[string]*selfWatchEntry),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go sw.run()
+	return sw
+}
+
+// Observe 출력 파일에 기록한 내용에서 오류 줄만 대기열에 넣음 (로거 잠금 안에서 호출, 가득 차면 버림)
+func (sw *SelfWatch) Observe(p []byte) {
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		message, ok := monitorErrorMessage(string(line))
+		if !ok {
+			continue
+		}
+		select {
+		case sw.lines <- message:
+		default:
+		}
+	}
+}
+
+// Stop 감시 중지 (대기 중인 줄은 버림)
+func (sw *SelfWatch) Stop() {
+	if sw == nil {
+		return
+	}
+	sw.once.Do(func() { close(sw.stop) })
+	<-sw.done
+}
+
+// run 오류 줄을 종류별로 묶어 간격마다 알림
+func (sw *SelfWatch) run() {
+	defer close(sw.done)
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sw.stop:
+			return
+		case message := <-sw.lines:
+			sw.record(message, time.Now())
+		case now := <-ticker.C:
+			sw.flush(now)
+		}
+	}
+}
+
+// record 오류 1건 처리 (처음 보거나 간격이 지난 종류는 바로 알림)
+func (sw *SelfWatch) record(message string, now time.Time) {
+	key := monitorErrorKey(message)
+	entry, ok := sw.entries[key]
+	if !ok {
+		if len(sw.entries) >= maxSelfWatchKeys {
+			sw.entries = make(map[string]*selfWatchEntry)
+		}
+		entry = &selfWatchEntry{}
+		sw.entries[key] = entry
+	}
+	entry.pending++
+	entry.example = message
+	if now.Sub(entry.lastAlert) >= sw.interval {
+		sw.send(key, entry, now)
+	}
+}
+
+// flush 간격이 지난 종류의 반복 오류 알림
+func (sw *SelfWatch) flush(now time.Time) {
+	for key, entry := range sw.entries {
+		if entry.pending > 0 && now.Sub(entry.lastAlert) >= sw.interval {
+			sw.send(key, entry, now)
+		}
+	}
+}
+
+// send 오류 종류 하나의 알림 전송
+func (sw *SelfWatch) send(key string, entry *selfWatchEntry, now time.Time) {
+	sw.dispatch(monitorErrorAlert(key, entry.example, entry.pending, sw.path, sw.interval))
+	entry.lastAlert = now
+	entry.pending = 0
+}
+
+// monitorErrorAlert 모니터 오류 알림 생성
+func monitorErrorAlert(key, message string, count int, path string, interval time.Duration) Alert {
+	return Alert{
+		Type:     AlertTypeMonitorError,
+		Severity: AlertSeverityWarning,
+		Title:    fmt.Sprintf("[%s MONITOR ERROR] %s", AppName, truncateRunes(key, 80)),
+		Headline: "🩺 모니터 자신의 오류가 출력 파일에 기록되었습니다",
+		Sections: []AlertSection{{
+			Fields: []AlertField{
+				{Label: "오류", Value: message},
+				{Label: "횟수", Value: fmt.Sprintf("%d (최근 %s)", count, formatSnoozeDuration(interval)), Short: true},
+				{Label: "출력 파일", Value: path, Short: true},
+				{Label: "조치", Value: "알림 채널 설정(웹훅 URL, SMTP 자격 증명)과 출력 대상 상태를 확인하세요. 같은 오류는 간격마다 한 번만 알립니다"},
+			},
+			Summary: true,
+		}},
+		Thread: alertThreadKey(AlertTypeMonitorError, key),
+		Fields: map[string]string{
+			"error":       message,
+			"count":       strconv.Itoa(count),
+			"output_file": path,
+		},
+	}
+}
+
+// monitorErrorMessage 로거 줄이 모니터 자신의 오류이면 메시지 반환
+// (logrus 텍스트 형식: time="..." level=error msg="..." 필드...)
+func monitorErrorMessage(line string) (string, bool) {
+	if !strings.Contains(line, " level=error ") {
+		return "", false
+	}
+	// 감시 중인 로그의 ERROR 줄과 자기 감시 알림의 전송 실패는 제외 (알림 반복 방지)
+	if strings.Contains(line, "fields.level=") || strings.Contains(line, AlertTypeMonitorError) {
+		return "", false
+	}
+	index := strings.Index(line, " msg=")
+	if index < 0 {
+		return "", false
+	}
+	rest := line[index+len(" msg="):]
+	if strings.HasPrefix(rest, `"`) {
+		quoted, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return "", false
+		}
+		message, err := strconv.Unquote(quoted)
+		return message, err == nil && message != ""
+	}
+	if end := strings.IndexByte(rest, ' '); end >= 0 {
+		rest = rest[:end]
+	}
+	return rest, rest != ""
+}
+
+// monitorErrorDetail 오류 종류 구분에서 뺄 세부 값 (숫자, 따옴표 값)
+var monitorErrorDetail = regexp.MustCompile(`"[^"]*"|'[^']*'|[0-9]+`)
+
+// monitorErrorKey 오류 종류 키 (": " 뒤 상세 원인을 빼고 숫자/따옴표 값을 지움)
+// 예: "❌ Failed to send login alert via slack: status 500" → "❌ Failed to send login alert via slack"
+func monitorErrorKey(message string) string {
+	if index := strings.Index(message, ": "); index > 0 {
+		message = message[:index]
+	}
+	return strings.TrimSpace(monitorErrorDetail.ReplaceAllString(message, "#"))
+}
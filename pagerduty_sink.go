@@ -0,0 +1,171 @@
+/*
+PagerDuty Sink Module
+=====================
+
+# PagerDuty Events API v2 연동 알림 채널
+
+주요 기능:
+- CRITICAL 등급 AI 분석/시스템 알림을 trigger 이벤트로 전송 (당직자 호출)
+- 알림 유형과 호스트로 dedup key 생성 (같은 조건의 반복 알림은 하나의 인시던트로 묶임)
+- 조건 해소 시 resolve 이벤트로 인시던트 자동 해결 (AlertResolver 구현)
+- 마지막 trigger 이후 일정 시간(기본 10분) 조용해야 해결하여 플래핑 방지
+
+설정 방법:
+- PagerDuty 서비스에 "Events API V2" 연동 추가 후 Integration Key 발급 (-pagerduty-routing-key)
+*/
+package main
+
+import (
+	"bytes"         // 요청 본문 버퍼
+	"encoding/json" // JSON 인코딩/디코딩
+	"fmt"           // 형식화된 I/O
+	"net/http"      // HTTP 클라이언트
+	"sync"          // 동기화 (뮤텍스)
+	"time"          // 시간 처리
+)
+
+// PagerDuty Events API v2 설정
+const (
+	PagerDutyEventsURL           = "https://events.pagerduty.com/v2/enqueue" // Events API v2 엔드포인트
+	PagerDutyMaxSummaryLength    = 1024                                      // payload.summary 최대 길이
+	DefaultPagerDutyResolveAfter = time.Minute * 10                          // 마지막 trigger 이후 해결까지 필요한 정상 유지 시간
+)
+
+// PagerDutySink PagerDuty Events API v2 알림 채널
+type PagerDutySink struct {
+	routingKey   string
+	eventsURL    string
+	client       *http.Client
+	resolveAfter time.Duration
+	open         map[string]time.Time // dedup key -> 마지막 trigger 시각 (해결되지 않은 인시던트)
+	mutex        sync.Mutex
+}
+
+// pagerDutyEvent Events API v2 요청 본문
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"` // trigger, resolve
+	DedupKey    string            `json:"dedup_key"`
+	Client      string            `json:"client,omitempty"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"` // resolve 이벤트에서는 생략
+}
+
+// pagerDutyPayload trigger 이벤트 상세 정보
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"` // critical, error, warning, info
+	Timestamp     string            `json:"timestamp,omitempty"`
+	Component     string            `json:"component,omitempty"`
+	Group         string            `json:"group,omitempty"`
+	Class         string            `json:"class,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+// NewPagerDutySink 새로운 PagerDuty 알림 채널 생성
+func NewPagerDutySink(routingKey string) *PagerDutySink {
+	return &PagerDutySink{
+		routingKey:   routingKey,
+		eventsURL:    PagerDutyEventsURL,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		resolveAfter: DefaultPagerDutyResolveAfter,
+		open:         make(map[string]time.Time),
+	}
+}
+
+// pagerDutyDedupKey 알림 유형과 호스트로 dedup key 생성
+func pagerDutyDedupKey(alertType, host string) string {
+	return fmt.Sprintf("syslog-monitor/%s/%s", alertType, host)
+}
+
+// pagesFor 호출 대상 알림인지 확인 (CRITICAL 등급 AI 분석/시스템 알림)
+func (ps *PagerDutySink) pagesFor(alert Alert) bool {
+	if alert.Severity != AlertSeverityCritical {
+		return false
+	}
+	return alert.Type == AlertTypeAI || alert.Type == AlertTypeSystem
+}
+
+// Send AlertSink 구현 - CRITICAL AI/시스템 알림을 trigger 이벤트로 전송
+func (ps *PagerDutySink) Send(alert Alert) error {
+	if !ps.pagesFor(alert) {
+		return nil
+	}
+
+	dedupKey := pagerDutyDedupKey(alert.Type, alert.Host)
+	ps.mutex.Lock()
+	ps.open[dedupKey] = time.Now()
+	ps.mutex.Unlock()
+
+	summary := alert.Title
+	if runes := []rune(summary); len(runes) > PagerDutyMaxSummaryLength {
+		summary = string(runes[:PagerDutyMaxSummaryLength-1]) + "…"
+	}
+
+	details := make(map[string]string, len(alert.Fields)+1)
+	for key, value := range alert.Fields {
+		details[key] = value
+	}
+	details["body"] = alert.RenderText(alert.Detail)
+
+	return ps.post(pagerDutyEvent{
+		RoutingKey:  ps.routingKey,
+		EventAction: "trigger",
+		DedupKey:    dedupKey,
+		Client:      AppName,
+		Payload: &pagerDutyPayload{
+			Summary:       summary,
+			Source:        alert.Host,
+			Severity:      "critical",
+			Timestamp:     alert.Timestamp.Format(time.RFC3339),
+			Component:     AppName,
+			Group:         alert.Type,
+			Class:         alert.Fields["metric"],
+			CustomDetails: details,
+		},
+	})
+}
+
+// Resolve AlertResolver 구현 - 열린 인시던트가 정상 유지 시간을 넘기면 resolve 이벤트 전송
+func (ps *PagerDutySink) Resolve(alertType, host string) error {
+	dedupKey := pagerDutyDedupKey(alertType, host)
+
+	ps.mutex.Lock()
+	lastTrigger, exists := ps.open[dedupKey]
+	if !exists || time.Since(lastTrigger) < ps.resolveAfter {
+		ps.mutex.Unlock()
+		return nil
+	}
+	delete(ps.open, dedupKey)
+	ps.mutex.Unlock()
+
+	return ps.post(pagerDutyEvent{
+		RoutingKey:  ps.routingKey,
+		EventAction: "resolve",
+		DedupKey:    dedupKey,
+	})
+}
+
+// post Events API v2로 이벤트 전송
+func (ps *PagerDutySink) post(event pagerDutyEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pagerduty event: %v", err)
+	}
+
+	resp, err := ps.client.Post(ps.eventsURL, "application/json", bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("failed to send pagerduty %s event: %v", event.EventAction, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var result struct {
+			Message string   `json:"message"`
+			Errors  []string `json:"errors"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return fmt.Errorf("pagerduty returned status %d: %s %v", resp.StatusCode, result.Message, result.Errors)
+	}
+	return nil
+}
@@ -0,0 +1,340 @@
+/*
+Parser Statistics Module
+========================
+
+형식별 파서 처리 통계와 소스별 형식 미인식(unknown) 비율 (GET /parsers, GET /metrics)
+
+로그 형식이 바뀌면 (nginx log_format 변경, 애플리케이션 로그 형식 변경 등) 파서가 줄을 인식하지
+못해 "unknown" 으로 떨어지고, 형식별 탐지(SLO, 응답 크기 이상, 웹 공격)가 조용히 멈춥니다.
+소스별 미인식 비율을 윈도우 단위로 계산해 평소에는 잘 파싱되던 소스의 비율이 임계값을 넘으면
+알림을 보냅니다 (-unknown-format-alert).
+
+주요 기능:
+- 파서(log_type)별 처리 줄 수, 소스(입력 파일 또는 쿠버네티스 워크로드)별 전체/미인식 줄 수
+- 소스별 윈도우(기본 10분, 최소 50줄) 미인식 비율 계산
+- 정상 윈도우(임계값 미만)를 한 번이라도 본 소스만 알림 (처음부터 형식을 모르는 소스는 알림 안 함)
+- 임계값을 넘으면 한 번 알림, 다시 임계값 아래로 내려오면 복구 알림
+- Prometheus 텍스트 형식 지표 (syslog_monitor_parser_lines_total 등)
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"os"      // 호스트 이름
+	"sort"    // 통계 정렬
+	"strconv" // 알림 필드
+	"strings" // 문자열 처리
+	"sync"    // 동기화 (뮤텍스)
+	"time"    // 윈도우 처리
+)
+
+// 파서 통계 기본값
+const (
+	UnknownLogType          = "unknown"        // 어떤 파서도 인식하지 못한 줄의 log_type
+	parserStatsWindow       = 10 * time.Minute // 미인식 비율 계산 윈도우
+	parserStatsMinLines     = 50               // 윈도우 판정에 필요한 최소 줄 수
+	parserStatsMaxSources   = 1000             // 추적할 소스 수 상한 (쿠버네티스 워크로드가 많은 경우)
+	parserStatsSampleLength = 300              // 알림에 넣는 미인식 줄 예시 최대 길이
+)
+
+// 형식 미인식 알림 종류
+const (
+	UnknownFormatStarted  = "started"
+	UnknownFormatResolved = "resolved"
+)
+
+// ParserStats 파서/소스별 파싱 통계 (파이프라인 파싱 워커에서 동시에 호출)
+type ParserStats struct {
+	mutex     sync.Mutex
+	threshold float64 // 미인식 비율 알림 임계값 (%, 0 이면 알림 안 함)
+	since     time.Time
+	parsers   map[string]int64
+	sources   map[string]*sourceParseStats
+	dropped   int64 // 소스 수 상한으로 소스별 통계에서 빠진 줄 수
+}
+
+// sourceParseStats 소스 하나의 누적/윈도우 통계
+type sourceParseStats struct {
+	lines         int64
+	unknown       int64
+	windowStart   time.Time
+	windowLines   int
+	windowUnknown int
+	lastRate      float64 // 마지막으로 판정한 윈도우의 미인식 비율 (%, 판정 전이면 -1)
+	healthy       bool    // 임계값 미만 윈도우를 본 적 있음
+	alerting      bool    // 임계값 초과 알림 후 복구 전
+	sample        string  // 마지막 미인식 줄
+}
+
+// ParserSourceStats 소스별 통계 (API 응답)
+type ParserSourceStats struct {
+	Source      string   `json:"source"`
+	Lines       int64    `json:"lines"`
+	Unknown     int64    `json:"unknown"`
+	UnknownRate *float64 `json:"unknown_rate,omitempty"` // 마지막 윈도우의 미인식 비율 (%, 판정 전이면 생략)
+	Alerting    bool     `json:"alerting"`
+}
+
+// ParserStatsSnapshot 파서 통계 (GET /parsers)
+type ParserStatsSnapshot struct {
+	Since     time.Time           `json:"since"`
+	Threshold float64             `json:"unknown_rate_threshold"` // 0 이면 알림 안 함
+	Window    string              `json:"window"`
+	Parsers   map[string]int64    `json:"parsers"`
+	Sources   []ParserSourceStats `json:"sources"`
+	Dropped   int64               `json:"untracked_lines,omitempty"`
+}
+
+// UnknownFormatEvent 소스의 미인식 비율이 임계값을 넘거나 복구됨
+type UnknownFormatEvent struct {
+	Kind      string // started, resolved
+	Source    string
+	Rate      float64 // 이번 윈도우 미인식 비율 (%)
+	Previous  float64 // 직전 윈도우 미인식 비율 (%)
+	Threshold float64
+	Lines     int // 이번 윈도우 줄 수
+	Unknown   int // 이번 윈도우 미인식 줄 수
+	Window    time.Duration
+	Sample    string // 마지막 미인식 줄
+}
+
+// NewParserStats 파서 통계 생성 (threshold 는 미인식 비율 알림 임계값 %, 0 이면 알림 안 함)
+func NewParserStats(threshold float64) *ParserStats {
+	return &ParserStats{
+		threshold: threshold,
+		since:     time.Now(),
+		parsers:   make(map[string]int64),
+		sources:   make(map[string]*sourceParseStats),
+	}
+}
+
+// SetThreshold 미인식 비율 알림 임계값 변경 (%, 0 이면 알림 안 함)
+func (ps *ParserStats) SetThreshold(threshold float64) {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	ps.threshold = threshold
+}
+
+// Threshold 미인식 비율 알림 임계값 (%)
+func (ps *ParserStats) Threshold() float64 {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	return ps.threshold
+}
+
+// Observe 파싱 결과 기록, 윈도우가 끝나 알림 상태가 바뀌면 이벤트 반환 (없으면 nil)
+func (ps *ParserStats) Observe(source, logType, line string, now time.Time) *UnknownFormatEvent {
+	if logType == "" {
+		logType = UnknownLogType
+	}
+	unknown := logType == UnknownLogType
+
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	ps.parsers[logType]++
+	stats, ok := ps.sources[source]
+	if !ok {
+		if len(ps.sources) >= parserStatsMaxSources {
+			ps.dropped++
+			return nil
+		}
+		stats = &sourceParseStats{windowStart: now, lastRate: -1}
+		ps.sources[source] = stats
+	}
+	stats.lines++
+	stats.windowLines++
+	if unknown {
+		stats.unknown++
+		stats.windowUnknown++
+		stats.sample = line
+		if len(stats.sample) > parserStatsSampleLength {
+			stats.sample = stats.sample[:parserStatsSampleLength] + "..."
+		}
+	}
+
+	if now.Sub(stats.windowStart) < parserStatsWindow || stats.windowLines < parserStatsMinLines {
+		return nil
+	}
+	return ps.evaluate(source, stats, now)
+}
+
+// evaluate 윈도우 미인식 비율 판정 후 다음 윈도우 시작 (뮤텍스를 잡은 상태에서 호출)
+func (ps *ParserStats) evaluate(source string, stats *sourceParseStats, now time.Time) *UnknownFormatEvent {
+	rate := 100 * float64(stats.windowUnknown) / float64(stats.windowLines)
+	event := &UnknownFormatEvent{
+		Source:    source,
+		Rate:      rate,
+		Previous:  stats.lastRate,
+		Threshold: ps.threshold,
+		Lines:     stats.windowLines,
+		Unknown:   stats.windowUnknown,
+		Window:    now.Sub(stats.windowStart),
+		Sample:    stats.sample,
+	}
+	stats.lastRate = rate
+	stats.windowStart = now
+	stats.windowLines = 0
+	stats.windowUnknown = 0
+
+	if ps.threshold <= 0 {
+		return nil
+	}
+	if rate < ps.threshold {
+		stats.healthy = true
+		if stats.alerting {
+			stats.alerting = false
+			event.Kind = UnknownFormatResolved
+			return event
+		}
+		return nil
+	}
+	if !stats.healthy || stats.alerting {
+		return nil
+	}
+	stats.alerting = true
+	event.Kind = UnknownFormatStarted
+	return event
+}
+
+// Snapshot 현재 통계 (소스는 이름순)
+func (ps *ParserStats) Snapshot() ParserStatsSnapshot {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	snapshot := ParserStatsSnapshot{
+		Since:     ps.since,
+		Threshold: ps.threshold,
+		Window:    parserStatsWindow.String(),
+		Parsers:   make(map[string]int64, len(ps.parsers)),
+		Sources:   make([]ParserSourceStats, 0, len(ps.sources)),
+		Dropped:   ps.dropped,
+	}
+	for logType, lines := range ps.parsers {
+		snapshot.Parsers[logType] = lines
+	}
+	for source, stats := range ps.sources {
+		entry := ParserSourceStats{Source: source, Lines: stats.lines, Unknown: stats.unknown, Alerting: stats.alerting}
+		if stats.lastRate >= 0 {
+			rate := stats.lastRate
+			entry.UnknownRate = &rate
+		}
+		snapshot.Sources = append(snapshot.Sources, entry)
+	}
+	sort.Slice(snapshot.Sources, func(i, j int) bool { return snapshot.Sources[i].Source < snapshot.Sources[j].Source })
+	return snapshot
+}
+
+// TenantParserStats 지표를 내보낼 모니터 하나의 파서 통계 (Tenant 가 비어 있으면 운영자 모니터)
+type TenantParserStats struct {
+	Tenant string
+	Stats  ParserStatsSnapshot
+}
+
+// WriteParserMetrics Prometheus 텍스트 형식 파서 지표 기록 (지표마다 HELP/TYPE 한 번, 테넌트는 tenant 라벨)
+func WriteParserMetrics(builder *strings.Builder, scopes []TenantParserStats) {
+	builder.WriteString("# HELP syslog_monitor_parser_lines_total Lines handled by each log parser (parser=\"unknown\" when no parser recognised the line).\n")
+	builder.WriteString("# TYPE syslog_monitor_parser_lines_total counter\n")
+	for _, scope := range scopes {
+		parsers := make([]string, 0, len(scope.Stats.Parsers))
+		for logType := range scope.Stats.Parsers {
+			parsers = append(parsers, logType)
+		}
+		sort.Strings(parsers)
+		for _, logType := range parsers {
+			fmt.Fprintf(builder, "syslog_monitor_parser_lines_total%s %d\n", prometheusLabels(scope.Tenant, "parser", logType), scope.Stats.Parsers[logType])
+		}
+	}
+
+	builder.WriteString("# HELP syslog_monitor_source_lines_total Parsed lines per log source.\n")
+	builder.WriteString("# TYPE syslog_monitor_source_lines_total counter\n")
+	for _, scope := range scopes {
+		for _, source := range scope.Stats.Sources {
+			fmt.Fprintf(builder, "syslog_monitor_source_lines_total%s %d\n", prometheusLabels(scope.Tenant, "source", source.Source), source.Lines)
+		}
+	}
+
+	builder.WriteString("# HELP syslog_monitor_source_unknown_lines_total Lines per log source that no parser recognised.\n")
+	builder.WriteString("# TYPE syslog_monitor_source_unknown_lines_total counter\n")
+	for _, scope := range scopes {
+		for _, source := range scope.Stats.Sources {
+			fmt.Fprintf(builder, "syslog_monitor_source_unknown_lines_total%s %d\n", prometheusLabels(scope.Tenant, "source", source.Source), source.Unknown)
+		}
+	}
+
+	builder.WriteString("# HELP syslog_monitor_source_unknown_ratio Share of unrecognised lines per log source in the last completed window (0-1).\n")
+	builder.WriteString("# TYPE syslog_monitor_source_unknown_ratio gauge\n")
+	for _, scope := range scopes {
+		for _, source := range scope.Stats.Sources {
+			if source.UnknownRate != nil {
+				fmt.Fprintf(builder, "syslog_monitor_source_unknown_ratio%s %s\n", prometheusLabels(scope.Tenant, "source", source.Source), strconv.FormatFloat(*source.UnknownRate/100, 'f', 4, 64))
+			}
+		}
+	}
+}
+
+// prometheusLabels 지표 라벨을 {tenant="...",name="value",...} 형식으로 (pairs 는 이름, 값 순서, 테넌트가 없으면 tenant 라벨 생략)
+func prometheusLabels(tenant string, pairs ...string) string {
+	var labels []string
+	if tenant != "" {
+		labels = append(labels, `tenant="`+escapePrometheusLabel(tenant)+`"`)
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		labels = append(labels, pairs[i]+`="`+escapePrometheusLabel(pairs[i+1])+`"`)
+	}
+	if len(labels) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+// escapePrometheusLabel 라벨 값의 역슬래시, 큰따옴표, 줄바꿈 이스케이프
+func escapePrometheusLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// unknownFormatAlert 형식 미인식 비율 알림 생성
+func unknownFormatAlert(event *UnknownFormatEvent) Alert {
+	host, _ := os.Hostname()
+
+	severity := AlertSeverityWarning
+	headline := fmt.Sprintf("🧩 %s 로그의 %.0f%% 를 어떤 파서도 인식하지 못했습니다 (직전 %.0f%%, 로그 형식이 바뀌었을 수 있습니다)", event.Source, event.Rate, event.Previous)
+	if event.Kind == UnknownFormatResolved {
+		severity = AlertSeverityInfo
+		headline = fmt.Sprintf("✅ %s 로그가 다시 정상적으로 파싱됩니다 (미인식 %.0f%%)", event.Source, event.Rate)
+	}
+
+	sections := []AlertSection{{
+		Fields: []AlertField{
+			{Label: "소스", Value: event.Source, Short: true},
+			{Label: "미인식 비율", Value: fmt.Sprintf("%.1f%% (임계값 %g%%)", event.Rate, event.Threshold), Short: true},
+			{Label: "윈도우", Value: fmt.Sprintf("%d/%d줄 (%v)", event.Unknown, event.Lines, event.Window.Round(time.Second)), Short: true},
+			{Label: "직전 윈도우", Value: fmt.Sprintf("%.1f%%", event.Previous), Short: true},
+		},
+		Summary: true,
+	}}
+	if event.Kind == UnknownFormatStarted && event.Sample != "" {
+		sections = append(sections, AlertSection{
+			Title:  "📄 인식하지 못한 줄 (예시)",
+			Fields: []AlertField{{Label: "로그", Value: event.Sample, Code: true}},
+		})
+	}
+
+	return Alert{
+		Type:     AlertTypeParserUnknown,
+		Severity: severity,
+		Title:    fmt.Sprintf("[%s PARSER %s] %s - %s %.0f%% unknown", AppName, strings.ToUpper(event.Kind), host, event.Source, event.Rate),
+		Headline: headline,
+		Sections: sections,
+		Host:     host,
+		Thread:   alertThreadKey(AlertTypeParserUnknown, host, event.Source),
+		Fields: map[string]string{
+			"kind":         event.Kind,
+			"source":       event.Source,
+			"unknown_rate": strconv.FormatFloat(event.Rate, 'f', 1, 64),
+			"threshold":    strconv.FormatFloat(event.Threshold, 'f', -1, 64),
+			"lines":        strconv.Itoa(event.Lines),
+			"unknown":      strconv.Itoa(event.Unknown),
+		},
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Pattern Log Parser Module
+=========================
+
+설정 파일의 사용자 정의 로그 파서 (logging.custom_parsers)
+
+자체 애플리케이션 로그 형식을 다시 컴파일하지 않고 파서로 등록합니다. grok 식 또는 이름 있는
+캡처 그룹 정규식이 줄 전체와 일치하면 지정한 log_type 의 로그로 파싱하며, 내장 파서의 자동 감지보다
+먼저 시도합니다. (파싱 후 필드만 보강하는 추출 규칙은 extraction_rules.go)
+
+주요 기능:
+- grok 식 (%{PATTERN:field}, pattern_definitions) 과 정규식 (?P<field>...) 모두 사용 (grok.go)
+- 약식 패턴: %{TIMESTAMP}, %{LEVEL}, %{MSG} 는 필드 이름 없이도 timestamp, level, message 로 캡처
+- 특수 필드: timestamp (timestamp_format 또는 ISO 8601/syslog/접근 로그 형식 자동 인식), level (대문자), message/msg, source/program, 나머지는 Fields
+- 여러 파서가 같은 log_type 을 쓸 수 있음 (형식이 여러 개인 애플리케이션), 설정 순서대로 시도
+
+예:
+
+	{"log_type": "billing", "pattern": "%{TIMESTAMP} %{LEVEL} \\[%{WORD:component}\\] %{MSG}"}
+	{"log_type": "worker", "pattern": "(?P<timestamp>\\d+) (?P<level>\\w+) job=(?P<job>\\S+) (?P<message>.*)",
+	 "timestamp_format": "unix"}
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"regexp"  // 파서 정규식
+	"strconv" // 유닉스 시각
+	"strings" // 문자열 처리
+	"time"    // 타임스탬프 파싱
+)
+
+// patternParserShorthands 필드 이름 없이 써도 표준 필드로 캡처하는 약식 패턴
+var patternParserShorthands = map[string]string{
+	"TIMESTAMP": "timestamp",
+	"LEVEL":     "level",
+	"MSG":       "message",
+}
+
+// patternShorthandRegex 필드 이름이 없는 %{TIMESTAMP}, %{LEVEL}, %{MSG} 참조
+var patternShorthandRegex = regexp.MustCompile(`%\{(TIMESTAMP|LEVEL|MSG)\}`)
+
+// CustomParserConfig 사용자 정의 로그 파서 (설정 파일 logging.custom_parsers 항목)
+type CustomParserConfig struct {
+	LogType            string            `json:"log_type"`                      // 파싱된 로그 유형 (필수)
+	Pattern            string            `json:"pattern"`                       // grok 식 또는 이름 있는 캡처 그룹 정규식 (줄 전체와 일치해야 함)
+	PatternDefinitions map[string]string `json:"pattern_definitions,omitempty"` // grok 사용자 패턴 (기본 패턴 라이브러리에 추가/재정의)
+	TimestampFormat    string            `json:"timestamp_format,omitempty"`    // Go 시간 레이아웃, "unix", "unix_ms" (비어 있으면 자동 인식)
+}
+
+// PatternLogParser 사용자 정의 패턴으로 한 가지 형식을 파싱하는 LogParser
+type PatternLogParser struct {
+	logType         string
+	regex           *regexp.Regexp
+	fields          map[string]string // 정규식 그룹 이름 → 필드 이름 (grok 참조, 없으면 그룹 이름 그대로)
+	timestampFormat string
+}
+
+// NewPatternLogParser 사용자 정의 파서 생성 (패턴 검증/컴파일)
+func NewPatternLogParser(config CustomParserConfig) (*PatternLogParser, error) {
+	logType := strings.TrimSpace(config.LogType)
+	if logType == "" {
+		return nil, fmt.Errorf("log_type is empty")
+	}
+	if strings.TrimSpace(config.Pattern) == "" {
+		return nil, fmt.Errorf("%s: pattern is empty", logType)
+	}
+	expression := patternShorthandRegex.ReplaceAllStringFunc(config.Pattern, func(match string) string {
+		name := patternShorthandRegex.FindStringSubmatch(match)[1]
+		return "%{" + name + ":" + patternParserShorthands[name] + "}"
+	})
+	pattern, fields, err := CompileGrok(expression, config.PatternDefinitions)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", logType, err)
+	}
+	regex, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", logType, err)
+	}
+	return &PatternLogParser{
+		logType:         logType,
+		regex:           regex,
+		fields:          fields,
+		timestampFormat: strings.TrimSpace(config.TimestampFormat),
+	}, nil
+}
+
+// CompileCustomParsers 설정의 사용자 정의 파서 목록 컴파일 (하나라도 실패하면 에러)
+func CompileCustomParsers(configs []CustomParserConfig) ([]*PatternLogParser, error) {
+	parsers := make([]*PatternLogParser, 0, len(configs))
+	for i, config := range configs {
+		parser, err := NewPatternLogParser(config)
+		if err != nil {
+			return nil, fmt.Errorf("custom parser #%d: %v", i+1, err)
+		}
+		parsers = append(parsers, parser)
+	}
+	return parsers, nil
+}
+
+// equalCustomParsers 두 사용자 정의 파서 목록이 같은지 확인 (설정 재로드)
+func equalCustomParsers(a, b []CustomParserConfig) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].LogType != b[i].LogType || a[i].Pattern != b[i].Pattern || a[i].TimestampFormat != b[i].TimestampFormat ||
+			len(a[i].PatternDefinitions) != len(b[i].PatternDefinitions) {
+			return false
+		}
+		for name, definition := range a[i].PatternDefinitions {
+			if other, ok := b[i].PatternDefinitions[name]; !ok || other != definition {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// GetLogType 파싱된 로그 유형
+func (p *PatternLogParser) GetLogType() string {
+	return p.logType
+}
+
+// DetectFormat 패턴이 줄 전체와 일치하는지 확인
+func (p *PatternLogParser) DetectFormat(line string) bool {
+	return p.regex.MatchString(line)
+}
+
+// Parse 캡처한 값으로 ParsedLog 생성 (메시지 캡처가 없으면 줄 전체)
+func (p *PatternLogParser) Parse(line string) (*ParsedLog, error) {
+	matches := p.regex.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, fmt.Errorf("line does not match %s pattern", p.logType)
+	}
+	parsed := &ParsedLog{
+		Timestamp: time.Now(),
+		LogType:   p.logType,
+		Level:     "INFO",
+		Message:   line,
+		RawLog:    line,
+		Fields:    make(map[string]string),
+	}
+	for i, group := range p.regex.SubexpNames() {
+		value := strings.TrimSpace(matches[i])
+		if group == "" || value == "" {
+			continue
+		}
+		if field, ok := p.fields[group]; ok {
+			group = field
+		}
+		switch group {
+		case "timestamp":
+			if timestamp, ok := p.parseTimestamp(value); ok {
+				parsed.Timestamp = timestamp
+			} else {
+				parsed.Fields["timestamp"] = value
+			}
+		case "level":
+			parsed.Level = strings.ToUpper(value)
+		case "message", "msg":
+			parsed.Message = value
+		case "source", "program":
+			parsed.Source = value
+		default:
+			parsed.Fields[group] = value
+		}
+	}
+	return parsed, nil
+}
+
+// parseTimestamp 캡처한 타임스탬프 파싱 (형식 지정 시 해당 형식만)
+func (p *PatternLogParser) parseTimestamp(value string) (time.Time, bool) {
+	switch p.timestampFormat {
+	case "":
+	case "unix", "unix_ms":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		if p.timestampFormat == "unix_ms" {
+			return time.UnixMilli(int64(number)), true
+		}
+		return time.Unix(0, int64(number*float64(time.Second))), true
+	default:
+		timestamp, err := time.ParseInLocation(p.timestampFormat, value, time.Local)
+		return timestamp, err == nil
+	}
+
+	// 자동 인식: ISO 8601, nginx 오류 로그, syslog, 접근 로그 ([] 없이 캡처한 경우 포함)
+	if timestamp, ok := parseLineTimestamp(value, time.Now()); ok {
+		return timestamp, true
+	}
+	return parseLineTimestamp("["+value+"]", time.Now())
+}
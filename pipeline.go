@@ -0,0 +1,202 @@
+/*
+Log Processing Pipeline Module
+==============================
+
+고용량 로그 처리를 위한 워커 풀 파이프라인 (-workers)
+
+입력 고루틴 → 파싱 워커 → 분석 워커 → 알림 단계 로 나누어, AI 분석의 외부 ASN 조회처럼 느린 작업이
+tail 입력을 막지 않도록 합니다.
+
+주요 기능:
+- 입력 고루틴: 여러 줄 조립, 필터/키워드/헬스 체크 등 상태를 가진 앞 단계 처리 후 엔트리 제출
+- 파싱 워커 N개: syslog 필드 파싱과 형식별 로그 파싱 (병렬)
+- 분석 워커 N개: AI 분석 (병렬, ASN 조회 포함)
+- 알림 단계 1개: 출력(Elasticsearch/Kafka/구조화 출력), 로그인/DB 감지, 알림을 입력 순서대로 처리
+- 제한된 큐: 가득 차면 입력을 멈추고(backpressure) 대기, 분석이 멈춰 오래 비지 않으면 엔트리를 버리고 집계
+- 설정 변경(재로드, 관리 API)은 진행 중인 엔트리를 모두 처리한 뒤 적용
+- 큐 길이, 처리/버린 엔트리 수, 입력 대기 횟수/시간 집계 (상태 API, 종료 시 로그)
+*/
+package main
+
+import (
+	"runtime"     // 기본 워커 수
+	"sync"        // 워커 종료 대기
+	"sync/atomic" // 처리 지표
+	"time"        // 입력 대기 시간
+)
+
+// 파이프라인 관련 상수
+const (
+	PipelineQueuePerWorker = 256              // 워커당 큐 용량
+	PipelineMaxBlock       = 30 * time.Second // 큐가 이 시간 동안 비지 않으면 엔트리를 버림
+)
+
+// DefaultPipelineWorkers 기본 워커 수 (CPU 수)
+func DefaultPipelineWorkers() int {
+	return runtime.NumCPU()
+}
+
+// logJob 파이프라인 단계 사이에 전달되는 로그 엔트리
+type logJob struct {
+	line      string
+	preParsed *ParsedLog        // 입력 소스에서 미리 파싱된 결과 (journald 등)
+	parsed    map[string]string // 기본 syslog 필드 (파싱 단계)
+	parsedLog *ParsedLog        // 형식별 파싱 결과 (파싱 단계)
+	aiResult  *AIAnalysisResult // AI 분석 결과 (분석 단계)
+	analyzed  chan struct{}     // 분석 단계 완료 신호
+	trace     *EventTrace       // 단계별 지연 시간 추적 (표본이 아니면 nil)
+	source    string            // 소스 이름 (처리 비용 집계)
+	usage     UsageCost         // 단계별 처리 비용 (알림 단계에서 기록)
+}
+
+// PipelineStats 파이프라인 처리 지표
+type PipelineStats struct {
+	Workers        int     `json:"workers"`         // 파싱/분석 단계별 워커 수
+	QueueCapacity  int     `json:"queue_capacity"`  // 처리 중 엔트리 최대 수
+	Queued         int     `json:"queued"`          // 알림 단계 처리를 기다리는 엔트리 수
+	ParseQueue     int     `json:"parse_queue"`     // 파싱 대기 엔트리 수
+	AnalysisQueue  int     `json:"analysis_queue"`  // 분석 대기 엔트리 수
+	Processed      int64   `json:"processed"`       // 모든 단계를 마친 엔트리 수
+	Dropped        int64   `json:"dropped"`         // 큐가 비지 않아 버린 엔트리 수
+	Backpressure   int64   `json:"backpressure"`    // 큐가 가득 차 입력이 대기한 횟수
+	BlockedSeconds float64 `json:"blocked_seconds"` // 입력이 대기한 총 시간 (초)
+}
+
+// LogPipeline 입력 → 파싱 → 분석 → 알림 단계 파이프라인
+// Submit, Drain, Close 는 입력 고루틴(처리 루프)에서만 호출
+type LogPipeline struct {
+	workers  int
+	parse    func(*logJob)
+	analyze  func(*logJob)
+	finish   func(*logJob)
+	logger   Logger
+	maxBlock time.Duration
+
+	ordered  chan *logJob // 제출 순서대로 알림 단계에 전달
+	parsing  chan *logJob
+	analysis chan *logJob
+	inflight sync.WaitGroup // 제출 후 알림 단계를 마치지 않은 엔트리
+	parsers  sync.WaitGroup
+	stopped  chan struct{}
+
+	processed    int64
+	dropped      int64
+	backpressure int64
+	blockedNanos int64
+}
+
+// NewLogPipeline 단계별 처리 함수로 파이프라인 생성 (workers 가 1 미만이면 1)
+func NewLogPipeline(workers int, parse, analyze, finish func(*logJob), logger Logger) *LogPipeline {
+	if workers < 1 {
+		workers = 1
+	}
+	capacity := workers * PipelineQueuePerWorker
+	return &LogPipeline{
+		workers:  workers,
+		parse:    parse,
+		analyze:  analyze,
+		finish:   finish,
+		logger:   logger,
+		maxBlock: PipelineMaxBlock,
+		ordered:  make(chan *logJob, capacity),
+		parsing:  make(chan *logJob, capacity),
+		analysis: make(chan *logJob, capacity),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Start 파싱/분석 워커와 알림 단계 고루틴 시작
+func (p *LogPipeline) Start() {
+	for i := 0; i < p.workers; i++ {
+		p.parsers.Add(1)
+		go p.parseLoop()
+		go p.analysisLoop()
+	}
+	go func() {
+		// 파싱 워커가 모두 끝나면 분석 워커도 종료
+		p.parsers.Wait()
+		close(p.analysis)
+	}()
+	go p.finishLoop()
+}
+
+// Submit 엔트리 제출 (큐가 가득 차면 대기하고, 최대 대기 시간이 지나도 비지 않으면 버림)
+func (p *LogPipeline) Submit(job *logJob) {
+	job.analyzed = make(chan struct{})
+	p.inflight.Add(1)
+
+	select {
+	case p.ordered <- job:
+	default:
+		atomic.AddInt64(&p.backpressure, 1)
+		started := time.Now()
+		timer := time.NewTimer(p.maxBlock)
+		select {
+		case p.ordered <- job:
+			timer.Stop()
+			atomic.AddInt64(&p.blockedNanos, int64(time.Since(started)))
+		case <-timer.C:
+			atomic.AddInt64(&p.blockedNanos, int64(time.Since(started)))
+			dropped := atomic.AddInt64(&p.dropped, 1)
+			p.inflight.Done()
+			p.logger.Errorf("⚠️  Pipeline queue full for %v, dropped log entry (%d dropped so far)", p.maxBlock, dropped)
+			return
+		}
+	}
+	p.parsing <- job
+}
+
+// Drain 제출된 엔트리를 모두 처리할 때까지 대기 (설정 변경 전 호출)
+func (p *LogPipeline) Drain() {
+	p.inflight.Wait()
+}
+
+// Close 남은 엔트리를 모두 처리한 뒤 워커 종료
+func (p *LogPipeline) Close() {
+	close(p.ordered)
+	close(p.parsing)
+	<-p.stopped
+}
+
+// parseLoop 파싱 워커
+func (p *LogPipeline) parseLoop() {
+	defer p.parsers.Done()
+	for job := range p.parsing {
+		p.parse(job)
+		p.analysis <- job
+	}
+}
+
+// analysisLoop 분석 워커
+func (p *LogPipeline) analysisLoop() {
+	for job := range p.analysis {
+		p.analyze(job)
+		close(job.analyzed)
+	}
+}
+
+// finishLoop 알림 단계 (제출 순서대로 분석 완료를 기다려 처리)
+func (p *LogPipeline) finishLoop() {
+	defer close(p.stopped)
+	for job := range p.ordered {
+		<-job.analyzed
+		p.finish(job)
+		atomic.AddInt64(&p.processed, 1)
+		p.inflight.Done()
+	}
+}
+
+// Stats 현재 처리 지표
+func (p *LogPipeline) Stats() PipelineStats {
+	return PipelineStats{
+		Workers:        p.workers,
+		QueueCapacity:  cap(p.ordered),
+		Queued:         len(p.ordered),
+		ParseQueue:     len(p.parsing),
+		AnalysisQueue:  len(p.analysis),
+		Processed:      atomic.LoadInt64(&p.processed),
+		Dropped:        atomic.LoadInt64(&p.dropped),
+		Backpressure:   atomic.LoadInt64(&p.backpressure),
+		BlockedSeconds: time.Duration(atomic.LoadInt64(&p.blockedNanos)).Seconds(),
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Pressure Stall Information Module
+=================================
+
+Linux PSI (/proc/pressure) 기반 리소스 포화 감지
+
+주요 기능:
+- /proc/pressure/{cpu,memory,io} 수집 (some/full, avg10/avg60/avg300)
+- 사용률 임계값보다 먼저 포화 상태를 감지하는 지속 정체 알림
+- 정기 보고서용 PSI 추세 요약
+
+PSI 값은 최근 구간 동안 리소스 대기로 인해 작업이 지연된 시간 비율(%)입니다.
+- some: 하나 이상의 태스크가 지연된 시간 비율
+- full: 모든 실행 가능 태스크가 동시에 지연된 시간 비율
+커널 4.20 이상에서 지원되며, 미지원 시 Available 이 false 로 유지됩니다.
+*/
+package main
+
+import (
+	"fmt"       // 형식화된 I/O
+	"io/ioutil" // 파일 I/O 유틸리티
+	"runtime"   // Go 런타임 정보
+	"strconv"   // 문자열-숫자 변환
+	"strings"   // 문자열 처리
+	"time"      // 시간 처리
+)
+
+// PressureMetrics 리소스별 PSI 메트릭
+type PressureMetrics struct {
+	Available bool          `json:"available"`
+	CPU       PressureStats `json:"cpu"`
+	Memory    PressureStats `json:"memory"`
+	IO        PressureStats `json:"io"`
+}
+
+// PressureStats 단일 리소스의 PSI 값 (%)
+type PressureStats struct {
+	SomeAvg10  float64 `json:"some_avg10"`
+	SomeAvg60  float64 `json:"some_avg60"`
+	SomeAvg300 float64 `json:"some_avg300"`
+	FullAvg10  float64 `json:"full_avg10"`
+	FullAvg60  float64 `json:"full_avg60"`
+	FullAvg300 float64 `json:"full_avg300"`
+}
+
+// readPressureFile /proc/pressure/<resource> 파일 파싱
+// 형식: some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func readPressureFile(resource string) (PressureStats, error) {
+	var stats PressureStats
+
+	data, err := ioutil.ReadFile(hostProcPath("pressure", resource))
+	if err != nil {
+		return stats, fmt.Errorf("failed to read PSI for %s: %v", resource, err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			val, err := strconv.ParseFloat(kv[1], 64)
+			if err != nil {
+				continue
+			}
+
+			switch fields[0] + " " + kv[0] {
+			case "some avg10":
+				stats.SomeAvg10 = val
+			case "some avg60":
+				stats.SomeAvg60 = val
+			case "some avg300":
+				stats.SomeAvg300 = val
+			case "full avg10":
+				stats.FullAvg10 = val
+			case "full avg60":
+				stats.FullAvg60 = val
+			case "full avg300":
+				stats.FullAvg300 = val
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// collectPressureMetrics PSI 메트릭 수집 (Linux 전용)
+func (sm *SystemMonitor) collectPressureMetrics() {
+	if runtime.GOOS != "linux" {
+		return
+	}
+
+	cpu, err := readPressureFile("cpu")
+	if err != nil {
+		return // PSI 미지원 커널 또는 psi=0 부팅 옵션
+	}
+	sm.metrics.Pressure.CPU = cpu
+	sm.metrics.Pressure.Available = true
+
+	if memory, err := readPressureFile("memory"); err == nil {
+		sm.metrics.Pressure.Memory = memory
+	}
+	if io, err := readPressureFile("io"); err == nil {
+		sm.metrics.Pressure.IO = io
+	}
+}
+
+// checkPressureAlerts CPU / I/O 지속 정체 알림
+// 직전 수집 주기에도 임계값을 넘었거나 5분 평균까지 임계값을 넘은 경우에만 알림 (일시적 스파이크 무시)
+func (sm *SystemMonitor) checkPressureAlerts() {
+	if !sm.metrics.Pressure.Available {
+		return
+	}
+
+	var previous *PressureMetrics
+	if len(sm.history) > 0 {
+		previous = &sm.history[len(sm.history)-1].Pressure
+	}
+
+	isSustained := func(current, prev PressureStats, threshold float64) bool {
+		if threshold <= 0 || current.SomeAvg60 < threshold {
+			return false
+		}
+		return current.SomeAvg300 >= threshold || (previous != nil && prev.SomeAvg60 >= threshold)
+	}
+
+	var prevCPU, prevIO PressureStats
+	if previous != nil {
+		prevCPU = previous.CPU
+		prevIO = previous.IO
+	}
+
+	cpu := sm.metrics.Pressure.CPU
+	if isSustained(cpu, prevCPU, sm.thresholds.CPUPressure) {
+		sm.sendAlert(SystemAlert{
+			Level:     "HIGH",
+			Type:      "PSI_CPU",
+			Message:   fmt.Sprintf("CPU 정체가 지속되고 있습니다: 1분 %.1f%%, 5분 %.1f%%", cpu.SomeAvg60, cpu.SomeAvg300),
+			Value:     cpu.SomeAvg60,
+			Threshold: sm.thresholds.CPUPressure,
+			Metrics:   *sm.metrics,
+			Timestamp: time.Now(),
+			Suggestions: []string{
+				"🔍 실행 대기 중인 프로세스 확인: vmstat 1 (r 컬럼)",
+				"⚖️  CPU 제한(cgroup quota) 및 스로틀링 여부 확인",
+				"🚀 워크로드 분산 또는 코어 증설 검토",
+			},
+		})
+	}
+
+	io := sm.metrics.Pressure.IO
+	if isSustained(io, prevIO, sm.thresholds.IOPressure) {
+		level := "HIGH"
+		if io.FullAvg60 >= sm.thresholds.IOPressure {
+			level = "CRITICAL"
+		}
+		sm.sendAlert(SystemAlert{
+			Level:     level,
+			Type:      "PSI_IO",
+			Message:   fmt.Sprintf("I/O 정체가 지속되고 있습니다: 1분 %.1f%% (full %.1f%%), 5분 %.1f%%", io.SomeAvg60, io.FullAvg60, io.SomeAvg300),
+			Value:     io.SomeAvg60,
+			Threshold: sm.thresholds.IOPressure,
+			Metrics:   *sm.metrics,
+			Timestamp: time.Now(),
+			Suggestions: []string{
+				"💽 디스크 대기 시간 확인: iostat -x 1",
+				"🔍 I/O 를 많이 발생시키는 프로세스 확인: iotop",
+				"📦 로그/백업 작업 스케줄 조정 검토",
+			},
+		})
+	}
+}
+
+// generatePressureReport 정기 보고서용 PSI 추세 요약
+func (sm *SystemMonitor) generatePressureReport(metrics SystemMetrics) string {
+	if !metrics.Pressure.Available {
+		return ""
+	}
+
+	report := "\n⏳ 리소스 정체 (PSI, some 10초/1분/5분):\n"
+	report += sm.formatPressureLine("CPU", metrics.Pressure.CPU, sm.thresholds.CPUPressure, func(p PressureMetrics) float64 { return p.CPU.SomeAvg60 })
+	report += sm.formatPressureLine("메모리", metrics.Pressure.Memory, sm.thresholds.MemoryPressure, func(p PressureMetrics) float64 { return p.Memory.SomeAvg60 })
+	report += sm.formatPressureLine("I/O", metrics.Pressure.IO, sm.thresholds.IOPressure, func(p PressureMetrics) float64 { return p.IO.SomeAvg60 })
+	return report
+}
+
+// formatPressureLine 리소스 한 줄 요약 (현재 값, 추세, 보관 기간 중 최대값)
+func (sm *SystemMonitor) formatPressureLine(name string, stats PressureStats, threshold float64, selector func(PressureMetrics) float64) string {
+	// 10초 평균과 5분 평균의 차이로 단기 추세 판단
+	trend := "➡️ 안정"
+	if diff := stats.SomeAvg10 - stats.SomeAvg300; diff > 1.0 {
+		trend = "📈 상승"
+	} else if diff < -1.0 {
+		trend = "📉 하락"
+	}
+
+	peak := stats.SomeAvg60
+	for _, h := range sm.history {
+		if h.Pressure.Available && selector(h.Pressure) > peak {
+			peak = selector(h.Pressure)
+		}
+	}
+
+	return fmt.Sprintf("  - %s: %.1f%% / %.1f%% / %.1f%% %s (기간 최대 1분: %.1f%%, 임계값: %.1f%%)\n",
+		name, stats.SomeAvg10, stats.SomeAvg60, stats.SomeAvg300, trend, peak, threshold)
+}
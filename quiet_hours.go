@@ -0,0 +1,404 @@
+/*
+Quiet Hours Module
+==================
+
+조용한 시간 / 고정 점검 창 동안 채널별로 중요하지 않은 알림을 보류하고 창이 끝날 때 요약 한 건으로 전송 (설정 파일 quiet_hours)
+
+주요 기능:
+- 반복 창 schedule: "22:00-07:00", "weekdays 22:00-07:00 Asia/Seoul", "Sat,Sun 00:00-24:00" (끝이 시작보다 이르면 다음 날, 요일은 시작 기준)
+- 일회성 점검 창 start/end: RFC 3339 시각 (예: 2026-10-20T01:00:00+09:00)
+- sinks: 적용할 채널 (email, slack, telegram 등, 비우면 모든 채널), match: 알림 유형/심각도/호스트/키워드 (알림 라우팅과 같은 조건)
+- critical 알림은 창 안에서도 바로 전송하고 info/warning 만 보류
+- 보류한 알림도 최근 알림(GET /alerts)과 일일 요약에는 기록되며, 창이 끝나면 채널별로 요약 알림 (유형 quiet_hours)
+- 종료/설정 재로드 시 모아 둔 알림은 바로 요약 전송, GET /maintenance 로 진행 중인 창과 채널별 보류 건수 조회
+
+외부 캘린더 점검 일정 (maintenance.go) 은 알림 전체에 적용되고, 조용한 시간은 라우팅이 끝난 뒤 채널별로 적용됩니다.
+
+예:
+
+	"quiet_hours": [
+	    {"name": "night", "schedule": "22:00-07:00 Asia/Seoul", "sinks": ["email", "slack"]},
+	    {"name": "weekend", "schedule": "Sat,Sun 00:00-24:00", "match": {"type": ["system", "ai"]}},
+	    {"name": "db-upgrade", "start": "2026-10-20T01:00:00+09:00", "end": "2026-10-20T04:00:00+09:00", "match": {"host": "db-*"}}
+	]
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"reflect" // 설정 비교
+	"strings" // 문자열 처리
+	"sync"    // 동시성 제어
+	"time"    // 창 시각 계산
+)
+
+// QuietHoursListLimit 요약 알림에 나열할 최대 알림 수
+const QuietHoursListLimit = 50
+
+// QuietHoursRule 조용한 시간 / 고정 점검 창 (설정 파일 quiet_hours 항목)
+type QuietHoursRule struct {
+	Name     string          `json:"name"`     // 창 이름 (요약 알림 제목, 비어 있으면 #순번)
+	Schedule string          `json:"schedule"` // 반복 창 ("[요일] HH:MM-HH:MM [시간대]")
+	Start    string          `json:"start"`    // 일회성 창 시작 (RFC 3339, schedule 대신)
+	End      string          `json:"end"`      // 일회성 창 끝 (RFC 3339)
+	Sinks    []string        `json:"sinks"`    // 적용할 채널 (비우면 모든 채널)
+	Match    AlertRouteMatch `json:"match"`    // 적용할 알림 조건 (비우면 모든 알림)
+}
+
+// QuietWindow 조용한 시간 창 회차
+type QuietWindow struct {
+	Rule  string    `json:"rule"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// QuietHoursStatus 창별 상태 (GET /maintenance)
+type QuietHoursStatus struct {
+	Name     string         `json:"name"`
+	Schedule string         `json:"schedule"`
+	Sinks    []string       `json:"sinks,omitempty"` // 비어 있으면 모든 채널
+	Active   bool           `json:"active"`
+	Until    *time.Time     `json:"until,omitempty"` // 진행 중인 창의 끝
+	Held     map[string]int `json:"held,omitempty"`  // 채널별 보류 중인 알림 수
+}
+
+// quietRule 검증한 창 설정
+type quietRule struct {
+	name     string
+	schedule string // 표시용
+	weekdays map[time.Weekday]bool
+	from, to int // 반복 창 시작/끝 (자정부터 분, to 가 from 이하이면 다음 날)
+	location *time.Location
+	start    time.Time // 일회성 창 (zero 면 반복 창)
+	end      time.Time
+	sinks    map[string]bool // nil 이면 모든 채널
+	sinkList []string
+	match    compiledAlertRoute
+}
+
+// quietBatch 창 회차/채널별로 모아 둔 알림
+type quietBatch struct {
+	window QuietWindow
+	sink   string
+	target string // 라우팅이 지정한 받는 곳 (요약도 같은 곳으로)
+	alerts []Alert
+	total  int
+	counts map[string]int // 심각도별 건수
+	types  map[string]int // 유형별 건수
+	timer  *time.Timer
+}
+
+// QuietHours 조용한 시간 창에 따른 채널별 알림 보류
+type QuietHours struct {
+	rules   []*quietRule
+	batches map[string]*quietBatch
+	flush   func(sink, target string, alert Alert) // 요약 알림 전송 (해당 채널로만)
+	logger  Logger
+	mutex   sync.Mutex
+	stopped bool
+}
+
+// NewQuietHours 설정 검증 후 조용한 시간 생성 (창이 없으면 nil)
+func NewQuietHours(rules []QuietHoursRule, flush func(sink, target string, alert Alert), logger Logger) (*QuietHours, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	qh := &QuietHours{batches: make(map[string]*quietBatch), flush: flush, logger: logger}
+	names := make(map[string]bool)
+	for i, rule := range rules {
+		name := strings.TrimSpace(rule.Name)
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		label := "quiet hours " + name
+		if names[name] {
+			return nil, fmt.Errorf("%s: duplicate name", label)
+		}
+		names[name] = true
+
+		compiled := &quietRule{name: name}
+		var err error
+		switch {
+		case rule.Schedule != "" && (rule.Start != "" || rule.End != ""):
+			return nil, fmt.Errorf("%s: use either schedule or start/end", label)
+		case rule.Schedule != "":
+			if err = compiled.parseSchedule(rule.Schedule); err != nil {
+				return nil, fmt.Errorf("%s: %v", label, err)
+			}
+		case rule.Start != "" && rule.End != "":
+			if compiled.start, err = time.Parse(time.RFC3339, rule.Start); err != nil {
+				return nil, fmt.Errorf("%s: invalid start %q (use RFC 3339, e.g. 2026-10-20T01:00:00+09:00)", label, rule.Start)
+			}
+			if compiled.end, err = time.Parse(time.RFC3339, rule.End); err != nil {
+				return nil, fmt.Errorf("%s: invalid end %q (use RFC 3339, e.g. 2026-10-20T04:00:00+09:00)", label, rule.End)
+			}
+			if !compiled.end.After(compiled.start) {
+				return nil, fmt.Errorf("%s: end must be after start", label)
+			}
+			compiled.schedule = fmt.Sprintf("%s ~ %s", rule.Start, rule.End)
+		default:
+			return nil, fmt.Errorf("%s: schedule or start/end is required", label)
+		}
+
+		for _, sink := range rule.Sinks {
+			if sink = strings.ToLower(strings.TrimSpace(sink)); sink != "" && !containsString(compiled.sinkList, sink) {
+				if compiled.sinks == nil {
+					compiled.sinks = make(map[string]bool)
+				}
+				compiled.sinks[sink] = true
+				compiled.sinkList = append(compiled.sinkList, sink)
+			}
+		}
+		if compiled.match, err = compileAlertRouteMatch(label, rule.Match); err != nil {
+			return nil, err
+		}
+		qh.rules = append(qh.rules, compiled)
+	}
+	return qh, nil
+}
+
+// parseSchedule 반복 창 표현식 파싱 ("[요일|weekdays|weekends] HH:MM-HH:MM [시간대]", 요일 생략 시 매일)
+func (r *quietRule) parseSchedule(spec string) error {
+	r.schedule = strings.TrimSpace(spec)
+	r.location = time.Local
+	r.from = -1
+	for _, token := range strings.Fields(spec) {
+		lower := strings.ToLower(token)
+		switch {
+		case lower == "daily":
+		case lower == "weekdays":
+			r.addWeekdays(time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday)
+		case lower == "weekends":
+			r.addWeekdays(time.Saturday, time.Sunday)
+		case isWeekdayToken(lower):
+			for _, name := range strings.Split(lower, ",") {
+				r.addWeekdays(weekdayNames[name])
+			}
+		case strings.Contains(lower, "-") && isClockToken(strings.SplitN(lower, "-", 2)[0]):
+			from, to, _ := strings.Cut(lower, "-")
+			var err error
+			if r.from, err = parseQuietClock(from, false); err != nil {
+				return fmt.Errorf("invalid start time in schedule %q: %v", spec, err)
+			}
+			if r.to, err = parseQuietClock(to, true); err != nil {
+				return fmt.Errorf("invalid end time in schedule %q: %v", spec, err)
+			}
+			if r.from == r.to {
+				return fmt.Errorf("schedule %q starts and ends at the same time", spec)
+			}
+		case isClockToken(lower):
+			return fmt.Errorf("schedule %q needs a time range such as %s-07:00", spec, token)
+		default:
+			location, err := time.LoadLocation(token)
+			if err != nil {
+				return fmt.Errorf("unknown token %q in schedule %q: %v", token, spec, err)
+			}
+			r.location = location
+		}
+	}
+	if r.from < 0 {
+		return fmt.Errorf("schedule %q is missing a time range (HH:MM-HH:MM)", spec)
+	}
+	return nil
+}
+
+// addWeekdays 창을 시작하는 요일 추가
+func (r *quietRule) addWeekdays(days ...time.Weekday) {
+	if r.weekdays == nil {
+		r.weekdays = make(map[time.Weekday]bool)
+	}
+	for _, day := range days {
+		r.weekdays[day] = true
+	}
+}
+
+// parseQuietClock "HH:MM" 을 자정부터 분으로 변환 (끝 시각은 24:00 허용)
+func parseQuietClock(value string, end bool) (int, error) {
+	if !isClockToken(value) {
+		return 0, fmt.Errorf("%q is not HH:MM", value)
+	}
+	var hour, minute int
+	fmt.Sscanf(value, "%d:%d", &hour, &minute)
+	if end && hour == 24 && minute == 0 {
+		return 24 * 60, nil
+	}
+	if hour > 23 || minute > 59 {
+		return 0, fmt.Errorf("%q is out of range", value)
+	}
+	return hour*60 + minute, nil
+}
+
+// window now 가 속한 창 회차 (없으면 false)
+func (r *quietRule) window(now time.Time) (QuietWindow, bool) {
+	if !r.start.IsZero() {
+		if !now.Before(r.start) && now.Before(r.end) {
+			return QuietWindow{Rule: r.name, Start: r.start, End: r.end}, true
+		}
+		return QuietWindow{}, false
+	}
+
+	// 오늘 시작한 창과 어제 시작해 자정을 넘긴 창만 now 를 포함할 수 있음
+	local := now.In(r.location)
+	for offset := 0; offset >= -1; offset-- {
+		day := local.AddDate(0, 0, offset)
+		if r.weekdays != nil && !r.weekdays[day.Weekday()] {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), r.from/60, r.from%60, 0, 0, r.location)
+		endDay := day
+		if r.to <= r.from {
+			endDay = day.AddDate(0, 0, 1)
+		}
+		end := time.Date(endDay.Year(), endDay.Month(), endDay.Day(), r.to/60, r.to%60, 0, 0, r.location)
+		if !now.Before(start) && now.Before(end) {
+			return QuietWindow{Rule: r.name, Start: start, End: end}, true
+		}
+	}
+	return QuietWindow{}, false
+}
+
+// Hold 채널로 보낼 알림이 조용한 시간 창에 해당하면 보류 (true 면 보내지 않음, 요약은 창이 끝날 때)
+// critical 알림과 조용한 시간 요약 알림은 보류하지 않음
+func (qh *QuietHours) Hold(sink, target string, alert Alert, now time.Time) bool {
+	if qh == nil || alert.Severity == AlertSeverityCritical || alert.Type == AlertTypeQuietHours {
+		return false
+	}
+	qh.mutex.Lock()
+	defer qh.mutex.Unlock()
+	if qh.stopped {
+		return false
+	}
+
+	for _, rule := range qh.rules {
+		if rule.sinks != nil && !rule.sinks[sink] {
+			continue
+		}
+		if !rule.match.matches(alert) {
+			continue
+		}
+		window, ok := rule.window(now)
+		if !ok {
+			continue
+		}
+		key := fmt.Sprintf("%s\x00%s\x00%s\x00%d", rule.name, sink, target, window.Start.Unix())
+		batch := qh.batches[key]
+		if batch == nil {
+			batch = &quietBatch{window: window, sink: sink, target: target, counts: make(map[string]int), types: make(map[string]int)}
+			qh.batches[key] = batch
+			batch.timer = time.AfterFunc(window.End.Sub(now), func() { qh.flushBatch(key, batch) })
+		}
+		if len(batch.alerts) < QuietHoursListLimit {
+			batch.alerts = append(batch.alerts, alert)
+		}
+		batch.total++
+		batch.counts[alert.Severity]++
+		batch.types[alert.Type]++
+		return true
+	}
+	return false
+}
+
+// flushBatch 창이 끝난 묶음의 요약 알림 전송
+func (qh *QuietHours) flushBatch(key string, batch *quietBatch) {
+	qh.mutex.Lock()
+	if qh.batches[key] != batch {
+		qh.mutex.Unlock()
+		return
+	}
+	delete(qh.batches, key)
+	qh.mutex.Unlock()
+	qh.logger.Infof("🌙 Quiet hours %s ended, sending summary of %d held alert(s) via %s", batch.window.Rule, batch.total, batch.sink)
+	qh.flush(batch.sink, batch.target, quietHoursAlert(batch))
+}
+
+// Stop 더 이상 보류하지 않고 모아 둔 알림을 바로 요약 전송 (설정 재로드, 종료)
+func (qh *QuietHours) Stop() {
+	if qh == nil {
+		return
+	}
+	qh.mutex.Lock()
+	qh.stopped = true
+	batches := make([]*quietBatch, 0, len(qh.batches))
+	for key, batch := range qh.batches {
+		batch.timer.Stop()
+		batches = append(batches, batch)
+		delete(qh.batches, key)
+	}
+	qh.mutex.Unlock()
+	for _, batch := range batches {
+		qh.flush(batch.sink, batch.target, quietHoursAlert(batch))
+	}
+}
+
+// Status 창별 진행 여부와 채널별 보류 건수 (설정 순서)
+func (qh *QuietHours) Status(now time.Time) []QuietHoursStatus {
+	if qh == nil {
+		return nil
+	}
+	qh.mutex.Lock()
+	defer qh.mutex.Unlock()
+	statuses := make([]QuietHoursStatus, len(qh.rules))
+	for i, rule := range qh.rules {
+		statuses[i] = QuietHoursStatus{Name: rule.name, Schedule: rule.schedule, Sinks: rule.sinkList}
+		if window, ok := rule.window(now); ok {
+			end := window.End
+			statuses[i].Active, statuses[i].Until = true, &end
+		}
+		for _, batch := range qh.batches {
+			if batch.window.Rule != rule.name {
+				continue
+			}
+			if statuses[i].Held == nil {
+				statuses[i].Held = make(map[string]int)
+			}
+			statuses[i].Held[batch.sink] += batch.total
+		}
+	}
+	return statuses
+}
+
+// quietHoursAlert 창 동안 채널 하나에 보내지 않은 알림의 요약 알림
+func quietHoursAlert(batch *quietBatch) Alert {
+	window := batch.window
+	severity := AlertSeverityInfo
+	if batch.counts[AlertSeverityWarning] > 0 {
+		severity = AlertSeverityWarning
+	}
+
+	var list strings.Builder
+	for _, alert := range batch.alerts {
+		fmt.Fprintf(&list, "%s [%s] %s\n", alert.Timestamp.Format("01-02 15:04:05"), strings.ToUpper(alert.Severity), alert.Title)
+	}
+	if batch.total > len(batch.alerts) {
+		fmt.Fprintf(&list, "... 외 %d건\n", batch.total-len(batch.alerts))
+	}
+
+	period := fmt.Sprintf("%s ~ %s", window.Start.Format("2006-01-02 15:04"), window.End.Format("2006-01-02 15:04"))
+	return Alert{
+		Type:     AlertTypeQuietHours,
+		Severity: severity,
+		Title:    fmt.Sprintf("[%s 조용한 시간] %s: 보류한 알림 %d건", AppName, window.Rule, batch.total),
+		Headline: fmt.Sprintf("🌙 조용한 시간 %s 동안 %s 로 보내지 않은 알림 %d건", window.Rule, batch.sink, batch.total),
+		Sections: []AlertSection{
+			{Fields: []AlertField{
+				{Label: "🌙 창", Value: window.Rule, Short: true},
+				{Label: "📨 채널", Value: batch.sink, Short: true},
+				{Label: "🕐 기간", Value: period, Short: true},
+				{Label: "📊 심각도별", Value: formatCountMap(batch.counts), Short: true},
+				{Label: "🏷️ 유형별", Value: formatCountMap(batch.types)},
+			}, Summary: true},
+			{Title: "📋 보류한 알림", Text: list.String()},
+		},
+		Thread: alertThreadKey(AlertTypeQuietHours, window.Rule),
+		Fields: map[string]string{
+			"quiet_hours": window.Rule,
+			"sink":        batch.sink,
+			"held":        fmt.Sprintf("%d", batch.total),
+		},
+	}
+}
+
+// equalQuietHours 조용한 시간 설정이 같은지 비교 (설정 재로드 시 바뀐 경우에만 교체)
+func equalQuietHours(a, b []QuietHoursRule) bool {
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseLineTimestamp 형식별 타임스탬프와 연도 없는 syslog 시간의 연도 추정 (윤일 포함)
+func TestParseLineTimestamp(t *testing.T) {
+	local := func(year int, month time.Month, day, hour, minute, second int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, time.Local)
+	}
+
+	tests := []struct {
+		name      string
+		line      string
+		reference time.Time
+		want      time.Time
+	}{
+		{"syslog same year", "Oct 16 09:30:00 host sshd[1]: x", local(2026, 10, 16, 12, 0, 0), local(2026, 10, 16, 9, 30, 0)},
+		{"syslog within a day ahead", "Oct 17 09:30:00 host sshd[1]: x", local(2026, 10, 16, 12, 0, 0), local(2026, 10, 17, 9, 30, 0)},
+		{"syslog previous year", "Dec 31 23:59:59 host sshd[1]: x", local(2026, 1, 2, 0, 0, 0), local(2025, 12, 31, 23, 59, 59)},
+		{"syslog padded day", "Mar  5 01:02:03 host cron[7]: x", local(2026, 3, 6, 0, 0, 0), local(2026, 3, 5, 1, 2, 3)},
+		{"leap day from next year", "Feb 29 10:00:00 host sshd[1]: x", local(2025, 1, 10, 0, 0, 0), local(2024, 2, 29, 10, 0, 0)},
+		{"leap day in leap year", "Feb 29 10:00:00 host sshd[1]: x", local(2028, 3, 1, 0, 0, 0), local(2028, 2, 29, 10, 0, 0)},
+		{"leap day after non-leap february", "Feb 29 10:00:00 host sshd[1]: x", local(2027, 3, 5, 0, 0, 0), local(2024, 2, 29, 10, 0, 0)},
+		{"leap day skips 2100", "Feb 29 10:00:00 host sshd[1]: x", local(2101, 6, 1, 0, 0, 0), local(2096, 2, 29, 10, 0, 0)},
+		{"iso", "2026-10-16T09:30:00Z level=info", local(2000, 1, 1, 0, 0, 0), time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)},
+		{"nginx error", "2026/10/16 09:30:00 [error] 12#12: x", time.Time{}, local(2026, 10, 16, 9, 30, 0)},
+		{"access log", `203.0.113.7 - - [16/Oct/2026:09:30:00 +0900] "GET / HTTP/1.1" 200 1`, time.Time{}, time.Date(2026, 10, 16, 0, 30, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		got, ok := parseLineTimestamp(test.line, test.reference)
+		if !ok || !got.Equal(test.want) {
+			t.Errorf("%s: got %v (%v), want %v", test.name, got, ok, test.want)
+		}
+	}
+
+	if got, ok := parseLineTimestamp("no timestamp here", time.Now()); ok {
+		t.Errorf("untimestamped line parsed as %v", got)
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Replay Report Module
+====================
+
+보관된 로그 재처리 (-replay) 진행률과 요약 보고서
+
+주요 기능:
+- 진행률: 처리한 입력 크기 비율 (압축 파일은 압축된 크기 기준 추정), 초당 줄 수, 남은 시간
+- 요약 보고서: 재처리 중 발생한 (-replay-dry-run 이면 발생했을) 알림을 유형/심각도별로 집계하고 입력별 줄/알림 수 기록
+- -replay-report 로 JSON 파일 저장
+*/
+package main
+
+import (
+	"encoding/json" // 보고서 저장
+	"fmt"           // 형식화된 I/O
+	"os"            // 보고서 파일
+	"sort"          // 집계 정렬
+	"sync"          // 알림 기록 동시성
+	"time"          // 시간 처리
+)
+
+// replayReportExamples 알림 묶음마다 보관하는 예시 제목 수
+const replayReportExamples = 5
+
+// replayProgress 재처리 진행률 (처리 루프에서만 사용)
+type replayProgress struct {
+	started time.Time
+	sizes   []int64
+	total   int64 // 모든 입력 크기 합
+	done    int64 // 끝난 입력 크기 합
+	current int64 // 처리 중인 입력에서 읽은 크기
+	lines   int64
+
+	lastAt    time.Time
+	lastLines int64
+}
+
+// newReplayProgress 입력 목록으로 진행률 생성
+func newReplayProgress(sources []ReplaySource) *replayProgress {
+	now := time.Now()
+	p := &replayProgress{started: now, lastAt: now, sizes: make([]int64, len(sources))}
+	for i, source := range sources {
+		p.sizes[i] = source.Size
+		p.total += source.Size
+	}
+	return p
+}
+
+// Advance 처리한 줄 수와 현재 입력에서 읽은 크기 반영
+func (p *replayProgress) Advance(lines int, offset int64) {
+	p.lines += int64(lines)
+	p.current = offset
+}
+
+// FinishInput 입력 하나를 끝까지 처리
+func (p *replayProgress) FinishInput(index int) {
+	p.done += p.sizes[index]
+	p.current = 0
+}
+
+// Lines 처리한 줄 수
+func (p *replayProgress) Lines() int64 {
+	return p.lines
+}
+
+// Elapsed 재처리 시작 후 경과 시간
+func (p *replayProgress) Elapsed() time.Duration {
+	return time.Since(p.started)
+}
+
+// AverageRate 시작 후 평균 초당 줄 수
+func (p *replayProgress) AverageRate() float64 {
+	if seconds := p.Elapsed().Seconds(); seconds > 0 {
+		return float64(p.lines) / seconds
+	}
+	return 0
+}
+
+// Fraction 처리한 입력 크기 비율 (0~1)
+func (p *replayProgress) Fraction() float64 {
+	if p.total <= 0 {
+		return 0
+	}
+	fraction := float64(p.done+p.current) / float64(p.total)
+	if fraction > 1 {
+		return 1
+	}
+	return fraction
+}
+
+// Status 진행률 한 줄 (초당 줄 수는 직전 Status 호출 이후 기준)
+func (p *replayProgress) Status() string {
+	now := time.Now()
+	rate := 0.0
+	if seconds := now.Sub(p.lastAt).Seconds(); seconds > 0 {
+		rate = float64(p.lines-p.lastLines) / seconds
+	}
+	p.lastAt, p.lastLines = now, p.lines
+
+	fraction := p.Fraction()
+	eta := "?"
+	if fraction > 0 {
+		remaining := time.Duration(float64(p.Elapsed()) * (1 - fraction) / fraction)
+		eta = remaining.Round(time.Second).String()
+	}
+	return fmt.Sprintf("%.1f%% | %d lines | %.0f lines/s | ETA %s", fraction*100, p.lines, rate, eta)
+}
+
+// ReplayReport 재처리 요약 보고서
+type ReplayReport struct {
+	Started        time.Time           `json:"started"`
+	Duration       float64             `json:"duration_seconds"`
+	Lines          int64               `json:"lines"`
+	LinesPerSecond float64             `json:"lines_per_second"`
+	Interrupted    bool                `json:"interrupted"`
+	DryRun         bool                `json:"dry_run"` // true 면 알림을 보내지 않고 집계만 함
+	Alerts         int                 `json:"alerts"`
+	BySeverity     map[string]int      `json:"by_severity"`
+	Groups         []*ReplayAlertGroup `json:"alert_groups"` // 유형/심각도별 (많은 순)
+	Inputs         []ReplayInputReport `json:"inputs"`
+
+	mutex   sync.Mutex
+	current int // 처리 중인 입력 (알림 집계 대상)
+	groups  map[string]*ReplayAlertGroup
+	sarif   *SARIFBuilder // 보안 탐지를 SARIF 결과로 모음 (-replay-sarif 미지정 시 nil)
+}
+
+// ReplayInputReport 입력별 처리 결과
+type ReplayInputReport struct {
+	Input  string    `json:"input"`
+	First  time.Time `json:"first_timestamp"`
+	Lines  int64     `json:"lines"`
+	Alerts int       `json:"alerts"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// ReplayAlertGroup 유형/심각도별 알림 집계
+type ReplayAlertGroup struct {
+	Type       string   `json:"type"`
+	Severity   string   `json:"severity"`
+	Count      int      `json:"count"`
+	FirstInput string   `json:"first_input"` // 처음 발생한 입력
+	Examples   []string `json:"examples"`    // 서로 다른 제목 (최대 replayReportExamples 개)
+}
+
+// NewReplayReport 입력 목록으로 보고서 생성
+func NewReplayReport(sources []ReplaySource, dryRun bool) *ReplayReport {
+	report := &ReplayReport{
+		Started:    time.Now(),
+		DryRun:     dryRun,
+		BySeverity: make(map[string]int),
+		Inputs:     make([]ReplayInputReport, len(sources)),
+		groups:     make(map[string]*ReplayAlertGroup),
+	}
+	for i, source := range sources {
+		report.Inputs[i] = ReplayInputReport{Input: source.String(), First: source.First}
+	}
+	return report
+}
+
+// BeginInput 이후 알림을 index 번째 입력에 집계
+func (r *ReplayReport) BeginInput(index int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.current = index
+}
+
+// AddLines 처리 중인 입력의 줄 수 추가
+func (r *ReplayReport) AddLines(lines int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Inputs[r.current].Lines += int64(lines)
+}
+
+// InputError 입력 읽기 실패 기록
+func (r *ReplayReport) InputError(index int, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Inputs[index].Error = err.Error()
+}
+
+// CollectSARIF 기록하는 알림 중 보안 탐지를 SARIF 결과로도 모음
+func (r *ReplayReport) CollectSARIF(builder *SARIFBuilder) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.sarif = builder
+}
+
+// RecordAlert 디스패처가 전송(드라이런이면 전송 대신 기록)한 알림 집계
+func (r *ReplayReport) RecordAlert(alert Alert) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Alerts++
+	r.BySeverity[alert.Severity]++
+	input := &r.Inputs[r.current]
+	input.Alerts++
+
+	key := alert.Type + "\x00" + alert.Severity
+	group := r.groups[key]
+	if group == nil {
+		group = &ReplayAlertGroup{Type: alert.Type, Severity: alert.Severity, FirstInput: input.Input}
+		r.groups[key] = group
+	}
+	group.Count++
+	if r.sarif != nil {
+		r.sarif.Add(alert, input.Input)
+	}
+	if len(group.Examples) < replayReportExamples && !containsString(group.Examples, alert.Title) {
+		group.Examples = append(group.Examples, alert.Title)
+	}
+}
+
+// Finish 처리 결과를 반영하고 알림 묶음을 많은 순으로 정렬
+func (r *ReplayReport) Finish(progress *replayProgress, interrupted bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Duration = progress.Elapsed().Seconds()
+	r.Lines = progress.Lines()
+	r.LinesPerSecond = progress.AverageRate()
+	r.Interrupted = interrupted
+	r.Groups = r.Groups[:0]
+	for _, group := range r.groups {
+		r.Groups = append(r.Groups, group)
+	}
+	sort.Slice(r.Groups, func(i, j int) bool {
+		if r.Groups[i].Count != r.Groups[j].Count {
+			return r.Groups[i].Count > r.Groups[j].Count
+		}
+		if r.Groups[i].Type != r.Groups[j].Type {
+			return r.Groups[i].Type < r.Groups[j].Type
+		}
+		return r.Groups[i].Severity < r.Groups[j].Severity
+	})
+}
+
+// Summary 로그로 출력할 요약 (Finish 뒤 호출)
+func (r *ReplayReport) Summary() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	verb := "sent"
+	if r.DryRun {
+		verb = "would have fired"
+	}
+	lines := []string{
+		"📋 Replay summary",
+		fmt.Sprintf("   %d lines in %s (%.0f lines/s), %d input(s)", r.Lines, time.Duration(r.Duration*float64(time.Second)).Round(time.Second), r.LinesPerSecond, len(r.Inputs)),
+		fmt.Sprintf("   %d alert(s) %s: critical %d, warning %d, info %d", r.Alerts, verb,
+			r.BySeverity[AlertSeverityCritical], r.BySeverity[AlertSeverityWarning], r.BySeverity[AlertSeverityInfo]),
+	}
+	for _, group := range r.Groups {
+		lines = append(lines, fmt.Sprintf("   - %s/%s: %d (first in %s)", group.Type, group.Severity, group.Count, group.FirstInput))
+		for _, example := range group.Examples {
+			lines = append(lines, "       "+example)
+		}
+	}
+	for _, input := range r.Inputs {
+		if input.Alerts > 0 || input.Error != "" {
+			line := fmt.Sprintf("   %s: %d lines, %d alert(s)", input.Input, input.Lines, input.Alerts)
+			if input.Error != "" {
+				line += " — error: " + input.Error
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// WriteFile 보고서를 JSON 으로 저장
+func (r *ReplayReport) WriteFile(path string) error {
+	r.mutex.Lock()
+	data, err := json.MarshalIndent(r, "", "  ")
+	r.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
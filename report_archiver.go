@@ -0,0 +1,161 @@
+/*
+Report Archiver Module
+======================
+
+정기 보고서를 Markdown/HTML 파일로 저장하는 서비스
+
+주요 기능:
+- 지정된 디렉토리에 보고서를 Markdown(.md) / HTML(.html) 파일로 저장
+- 날짜별 파일명으로 위키 게시 및 장기 보관 지원
+- 저장 디렉토리가 git 저장소인 경우 자동 커밋 및 푸시 (선택)
+
+파일명 형식:
+- <prefix>-YYYY-MM-DD-HHMM.md
+- <prefix>-YYYY-MM-DD-HHMM.html
+*/
+package main
+
+import (
+	"fmt"           // 형식화된 I/O
+	"html"          // HTML 이스케이프
+	"os"            // OS 인터페이스
+	"os/exec"       // git 명령 실행
+	"path/filepath" // 파일 경로 처리
+	"strings"       // 문자열 처리
+	"time"          // 시간 처리
+)
+
+// 지원하는 보고서 파일 형식
+const (
+	ReportFormatMarkdown = "markdown"
+	ReportFormatHTML     = "html"
+)
+
+// ReportArchiver 보고서 파일 저장 서비스
+type ReportArchiver struct {
+	dir     string
+	formats []string
+	gitPush bool
+	logger  Logger
+}
+
+// NewReportArchiver 새로운 보고서 저장 서비스 생성
+// formats가 비어 있으면 Markdown으로 저장
+func NewReportArchiver(dir string, formats []string, gitPush bool, logger Logger) (*ReportArchiver, error) {
+	if len(formats) == 0 {
+		formats = []string{ReportFormatMarkdown}
+	}
+	normalized := make([]string, 0, len(formats))
+	for _, format := range formats {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if format == "md" {
+			format = ReportFormatMarkdown
+		}
+		if format != ReportFormatMarkdown && format != ReportFormatHTML {
+			return nil, fmt.Errorf("unsupported report format: %s (use markdown or html)", format)
+		}
+		normalized = append(normalized, format)
+	}
+
+	if err := os.MkdirAll(dir, ConfigPermissions); err != nil {
+		return nil, fmt.Errorf("failed to create report directory: %v", err)
+	}
+
+	return &ReportArchiver{
+		dir:     dir,
+		formats: normalized,
+		gitPush: gitPush,
+		logger:  logger,
+	}, nil
+}
+
+// Archive 보고서를 설정된 형식으로 저장
+// prefix는 파일명 접두사 (예: system-report, weekly-digest)
+func (ra *ReportArchiver) Archive(prefix, title, body string, generatedAt time.Time) error {
+	baseName := fmt.Sprintf("%s-%s", prefix, generatedAt.Format("2006-01-02-1504"))
+
+	var written []string
+	for _, format := range ra.formats {
+		var path, content string
+		switch format {
+		case ReportFormatHTML:
+			path = filepath.Join(ra.dir, baseName+".html")
+			content = renderReportHTML(title, body, generatedAt)
+		default:
+			path = filepath.Join(ra.dir, baseName+".md")
+			content = renderReportMarkdown(title, body, generatedAt)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write report file: %v", err)
+		}
+		written = append(written, path)
+	}
+
+	ra.logger.Infof("📁 Report archived: %s", strings.Join(written, ", "))
+
+	if ra.gitPush {
+		return ra.commitAndPush(written, title)
+	}
+	return nil
+}
+
+// commitAndPush 저장된 보고서를 git 저장소에 커밋 및 푸시
+func (ra *ReportArchiver) commitAndPush(files []string, title string) error {
+	args := append([]string{"-C", ra.dir, "add", "--"}, files...)
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("git add failed: %v (%s)", err, strings.TrimSpace(string(output)))
+	}
+
+	if output, err := exec.Command("git", "-C", ra.dir, "commit", "-m", title).CombinedOutput(); err != nil {
+		return fmt.Errorf("git commit failed: %v (%s)", err, strings.TrimSpace(string(output)))
+	}
+
+	if output, err := exec.Command("git", "-C", ra.dir, "push").CombinedOutput(); err != nil {
+		return fmt.Errorf("git push failed: %v (%s)", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// renderReportMarkdown Markdown 보고서 생성 (본문은 서식 유지를 위해 코드 블록으로 감쌈)
+func renderReportMarkdown(title, body string, generatedAt time.Time) string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("# %s\n\n- 호스트: `%s`\n- 생성 시각: %s\n- 생성: %s v%s\n\n```text\n%s\n```\n",
+		title,
+		hostname,
+		generatedAt.Format("2006-01-02 15:04:05 MST"),
+		AppName, AppVersion,
+		strings.TrimSpace(strings.ReplaceAll(body, "```", "'''")),
+	)
+}
+
+// renderReportHTML 독립 실행형 HTML 보고서 생성
+func renderReportHTML(title, body string, generatedAt time.Time) string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="ko">
+<head>
+<meta charset="UTF-8">
+<title>%s</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 2em; color: #222; }
+pre { background: #f6f8fa; padding: 1em; border-radius: 6px; white-space: pre-wrap; }
+.meta { color: #666; }
+</style>
+</head>
+<body>
+<h1>%s</h1>
+<p class="meta">호스트: %s · 생성 시각: %s · %s v%s</p>
+<pre>%s</pre>
+</body>
+</html>
+`,
+		html.EscapeString(title),
+		html.EscapeString(title),
+		html.EscapeString(hostname),
+		generatedAt.Format("2006-01-02 15:04:05 MST"),
+		AppName, AppVersion,
+		html.EscapeString(strings.TrimSpace(body)),
+	)
+}
@@ -0,0 +1,259 @@
+/*
+Report Schedule Module
+======================
+
+시간대(Timezone)를 고려한 정기 보고서 스케줄링
+
+주요 기능:
+- 사람이 읽기 쉬운 스케줄 표현식 파싱
+- 지정된 시간대 기준 다음 실행 시각 계산 (서머타임 반영, 전환으로 건너뛴 시각이면 전환 직후 실행)
+- 시스템 상태 보고서 및 요약(digest) 전송 스케줄에 공통 사용
+- cron 형식 (분 시 * * 요일) 도 지원 (분/시는 하나의 값, 일/월은 * 만)
+
+스케줄 예시:
+- "08:00 Asia/Seoul daily"   매일 오전 8시 (서울 시간)
+- "Mon 09:00 weekly"         매주 월요일 오전 9시 (로컬 시간)
+- "Mon,Wed,Fri 18:30 UTC"    월/수/금 18시 30분 (UTC)
+- "weekdays 07:45 America/New_York"  평일 오전 7시 45분
+- "0 8 * * * Asia/Seoul"     cron 형식, 매일 오전 8시 (서울 시간)
+- "30 18 * * 1-5"            cron 형식, 평일 18시 30분 (로컬 시간)
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"sort"    // 요일 정렬
+	"strconv" // cron 필드 숫자 변환
+	"strings" // 문자열 처리
+	"time"    // 시간 처리
+)
+
+// weekdayNames 스케줄 표현식에서 허용하는 요일 이름
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// ReportSchedule 시간대 기반 보고서 스케줄
+type ReportSchedule struct {
+	spec     string
+	hour     int
+	minute   int
+	weekdays map[time.Weekday]bool // 비어 있으면 매일
+	location *time.Location
+}
+
+// ParseReportSchedule 스케줄 표현식 파싱
+// 토큰 순서는 자유이며 HH:MM 시각은 필수, 시간대 생략 시 로컬 시간대 사용
+// 숫자로 시작하는 5개 필드 (뒤에 시간대 가능) 는 cron 형식으로 파싱
+func ParseReportSchedule(spec string) (*ReportSchedule, error) {
+	schedule := &ReportSchedule{
+		spec:     strings.TrimSpace(spec),
+		hour:     -1,
+		weekdays: make(map[time.Weekday]bool),
+		location: time.Local,
+	}
+
+	if fields := strings.Fields(spec); len(fields) >= 5 && isCronToken(fields[0]) && isCronToken(fields[1]) {
+		return parseCronSchedule(schedule, fields)
+	}
+
+	frequency := ""
+	for _, token := range strings.Fields(spec) {
+		lower := strings.ToLower(token)
+
+		switch {
+		case lower == "daily" || lower == "weekly":
+			frequency = lower
+
+		case lower == "weekdays":
+			for day := time.Monday; day <= time.Friday; day++ {
+				schedule.weekdays[day] = true
+			}
+
+		case isClockToken(lower):
+			var hour, minute int
+			if _, err := fmt.Sscanf(lower, "%d:%d", &hour, &minute); err != nil || hour > 23 || minute > 59 {
+				return nil, fmt.Errorf("invalid time %q in schedule %q", token, spec)
+			}
+			schedule.hour, schedule.minute = hour, minute
+
+		case isWeekdayToken(lower):
+			for _, name := range strings.Split(lower, ",") {
+				schedule.weekdays[weekdayNames[name]] = true
+			}
+
+		default:
+			location, err := time.LoadLocation(token)
+			if err != nil {
+				return nil, fmt.Errorf("unknown token %q in schedule %q: %v", token, spec, err)
+			}
+			schedule.location = location
+		}
+	}
+
+	if schedule.hour < 0 {
+		return nil, fmt.Errorf("schedule %q is missing a time of day (HH:MM)", spec)
+	}
+	if frequency == "weekly" && len(schedule.weekdays) == 0 {
+		return nil, fmt.Errorf("weekly schedule %q needs a weekday (e.g. Mon)", spec)
+	}
+	if frequency == "daily" && len(schedule.weekdays) > 0 {
+		return nil, fmt.Errorf("daily schedule %q cannot be limited to weekdays; use weekly", spec)
+	}
+
+	return schedule, nil
+}
+
+// parseCronSchedule cron 형식 (분 시 일 월 요일 [시간대]) 파싱
+// 보고서는 하루 한 번 시각에 보내므로 분/시는 하나의 값, 일/월은 * 만 허용
+func parseCronSchedule(schedule *ReportSchedule, fields []string) (*ReportSchedule, error) {
+	spec := schedule.spec
+	if len(fields) > 6 {
+		return nil, fmt.Errorf("cron schedule %q has too many fields (minute hour day month weekday [timezone])", spec)
+	}
+
+	minute, err := strconv.Atoi(fields[0])
+	if err != nil || minute < 0 || minute > 59 {
+		return nil, fmt.Errorf("cron schedule %q: minute must be a single value 0-59", spec)
+	}
+	hour, err := strconv.Atoi(fields[1])
+	if err != nil || hour < 0 || hour > 23 {
+		return nil, fmt.Errorf("cron schedule %q: hour must be a single value 0-23", spec)
+	}
+	if fields[2] != "*" || fields[3] != "*" {
+		return nil, fmt.Errorf("cron schedule %q: day of month and month must be *", spec)
+	}
+	schedule.hour, schedule.minute = hour, minute
+
+	if fields[4] != "*" {
+		for _, part := range strings.Split(strings.ToLower(fields[4]), ",") {
+			first, last, isRange := strings.Cut(part, "-")
+			from, ok := cronWeekday(first)
+			to := from
+			if isRange {
+				var toOK bool
+				to, toOK = cronWeekday(last)
+				ok = ok && toOK && from <= to
+			}
+			if !ok {
+				return nil, fmt.Errorf("cron schedule %q: invalid weekday %q (0-7, sun-sat, ranges like 1-5)", spec, part)
+			}
+			for day := from; day <= to; day++ {
+				schedule.weekdays[time.Weekday(day%7)] = true
+			}
+		}
+	}
+
+	if len(fields) == 6 {
+		location, err := time.LoadLocation(fields[5])
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone %q in schedule %q: %v", fields[5], spec, err)
+		}
+		schedule.location = location
+	}
+	return schedule, nil
+}
+
+// cronWeekday cron 요일 값 (0-7, 0 과 7 은 일요일) 또는 요일 이름
+func cronWeekday(token string) (int, bool) {
+	if day, ok := weekdayNames[token]; ok {
+		return int(day), true
+	}
+	day, err := strconv.Atoi(token)
+	if err != nil || day < 0 || day > 7 {
+		return 0, false
+	}
+	return day, true
+}
+
+// isCronToken cron 분/시 필드처럼 보이는지 (숫자와 * , - / 만)
+func isCronToken(token string) bool {
+	return token != "" && strings.Trim(token, "0123456789*,-/") == ""
+}
+
+// isClockToken HH:MM 형식 여부
+func isClockToken(token string) bool {
+	parts := strings.Split(token, ":")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[0]) > 2 || len(parts[1]) != 2 {
+		return false
+	}
+	for _, r := range parts[0] + parts[1] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isWeekdayToken 요일 또는 쉼표로 구분된 요일 목록 여부
+func isWeekdayToken(token string) bool {
+	for _, name := range strings.Split(token, ",") {
+		if _, ok := weekdayNames[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Next after 이후의 다음 실행 시각 계산
+func (rs *ReportSchedule) Next(after time.Time) time.Time {
+	local := after.In(rs.location)
+
+	// 최대 8일 이내에 반드시 실행일이 존재
+	for offset := 0; offset <= 7; offset++ {
+		day := local.AddDate(0, 0, offset)
+		candidate := time.Date(day.Year(), day.Month(), day.Day(), rs.hour, rs.minute, 0, 0, rs.location)
+		if candidate.Hour() != rs.hour || candidate.Minute() != rs.minute {
+			candidate = skipClockGap(candidate)
+		}
+		if !candidate.After(after) {
+			continue
+		}
+		if len(rs.weekdays) > 0 && !rs.weekdays[candidate.Weekday()] {
+			continue
+		}
+		return candidate
+	}
+
+	return local.Add(24 * time.Hour)
+}
+
+// skipClockGap 서머타임 시작으로 건너뛴 시각 (예: 미국 02:30) 대신 건너뛴 구간이 끝나는 시각 (03:00)
+// time.Date 는 없는 시각을 한쪽 오프셋으로 정규화하므로 가까운 쪽 시간대 경계가 전환 시각
+func skipClockGap(candidate time.Time) time.Time {
+	start, end := candidate.ZoneBounds()
+	if end.IsZero() || (!start.IsZero() && candidate.Sub(start) < end.Sub(candidate)) {
+		return start
+	}
+	return end
+}
+
+// Timer 다음 실행 시각에 신호를 보내는 채널 반환
+func (rs *ReportSchedule) Timer() <-chan time.Time {
+	return time.After(time.Until(rs.Next(time.Now())))
+}
+
+// String 사람이 읽기 쉬운 스케줄 설명
+func (rs *ReportSchedule) String() string {
+	days := "매일"
+	if len(rs.weekdays) > 0 {
+		var list []int
+		for day := range rs.weekdays {
+			list = append(list, int(day))
+		}
+		sort.Ints(list)
+
+		var names []string
+		for _, day := range list {
+			names = append(names, time.Weekday(day).String()[:3])
+		}
+		days = "매주 " + strings.Join(names, ",")
+	}
+	return fmt.Sprintf("%s %02d:%02d (%s)", days, rs.hour, rs.minute, rs.location)
+}
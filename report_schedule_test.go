@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestReportScheduleNextDST 서머타임 전환일의 다음 실행 시각
+func TestReportScheduleNextDST(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  string
+		after string // RFC3339
+		want  string // RFC3339
+	}{
+		{"plain day", "08:00 Asia/Seoul daily", "2026-10-16T09:00:00+09:00", "2026-10-17T08:00:00+09:00"},
+		{"same day", "08:00 Asia/Seoul daily", "2026-10-16T07:59:00+09:00", "2026-10-16T08:00:00+09:00"},
+		{"spring forward gap", "02:30 America/New_York daily", "2026-03-07T03:00:00-05:00", "2026-03-08T03:00:00-04:00"},
+		{"after the gap", "02:30 America/New_York daily", "2026-03-08T03:00:00-04:00", "2026-03-09T02:30:00-04:00"},
+		{"gap start", "02:00 America/New_York daily", "2026-03-07T12:00:00-05:00", "2026-03-08T03:00:00-04:00"},
+		{"before the gap", "01:59 America/New_York daily", "2026-03-07T12:00:00-05:00", "2026-03-08T01:59:00-05:00"},
+		{"midnight gap", "00:30 America/Santiago daily", "2026-09-05T12:00:00-04:00", "2026-09-06T01:00:00-03:00"},
+		{"gap on a weekday", "Sun 02:15 weekly America/New_York", "2026-03-02T00:00:00-05:00", "2026-03-08T03:00:00-04:00"},
+		{"gap skipped weekday", "Mon 02:15 weekly America/New_York", "2026-03-07T00:00:00-05:00", "2026-03-09T02:15:00-04:00"},
+		{"cron gap", "30 2 * * * America/New_York", "2026-03-07T03:00:00-05:00", "2026-03-08T03:00:00-04:00"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule, err := ParseReportSchedule(test.spec)
+			if err != nil {
+				t.Fatalf("ParseReportSchedule(%q): %v", test.spec, err)
+			}
+			after, _ := time.Parse(time.RFC3339, test.after)
+			want, _ := time.Parse(time.RFC3339, test.want)
+			if got := schedule.Next(after); !got.Equal(want) {
+				t.Errorf("Next(%s) = %s, want %s", test.after, got, want.In(got.Location()))
+			}
+		})
+	}
+}
+
+// TestReportScheduleNextFallBack 서머타임 종료로 두 번 나오는 시각은 그날 한 번만 실행
+func TestReportScheduleNextFallBack(t *testing.T) {
+	schedule, err := ParseReportSchedule("01:30 America/New_York daily")
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, _ := time.Parse(time.RFC3339, "2026-10-31T12:00:00-04:00")
+	first := schedule.Next(after)
+	if local := first.In(schedule.location); local.Day() != 1 || local.Hour() != 1 || local.Minute() != 30 {
+		t.Fatalf("Next(%s) = %s, want 2026-11-01 01:30", after, local)
+	}
+	second := schedule.Next(first)
+	if local := second.In(schedule.location); local.Day() != 2 || local.Hour() != 1 || local.Minute() != 30 {
+		t.Errorf("Next(%s) = %s, want 2026-11-02 01:30", first, local)
+	}
+}
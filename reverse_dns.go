@@ -0,0 +1,187 @@
+/*
+Reverse DNS Module
+==================
+
+출처 IP 역방향 DNS (PTR) 조회 (-reverse-dns)
+
+로그인/웹 알림에 IP 대신 "bastion.corp.example.com" 같은 호스트 이름을 함께 표시합니다.
+
+주요 기능:
+- PTR 조회 후 정방향(A/AAAA) 조회로 같은 IP 를 가리키는지 확인 (위조된 PTR 구분)
+- 조회 시간 제한 (-reverse-dns-timeout), 시간 초과/실패도 음성 캐시에 저장
+- 조회 결과 메모리 캐시 (성공 6시간, 레코드 없음/실패 30분)
+- 같은 IP 의 동시 조회는 하나로 합침 (GeoMapper.LookupAsync 와 같은 비동기 방식)
+*/
+package main
+
+import (
+	"context" // 조회 시간 제한
+	"net"     // DNS 조회
+	"strings" // 문자열 처리
+	"sync"    // 동기화
+	"time"    // 시간 처리
+)
+
+// 역방향 DNS 조회 설정
+const (
+	DefaultReverseDNSTimeout  = 2000 // 조회 시간 제한 기본값 (밀리초)
+	reverseDNSCacheTTL        = 6 * time.Hour
+	reverseDNSNegativeTTL     = 30 * time.Minute // 레코드 없음/시간 초과 결과 유지 시간
+	reverseDNSMaxCacheEntries = 10000            // 캐시 항목 수가 이를 넘으면 만료 항목 정리
+)
+
+// PTRRecord 역방향 DNS 조회 결과
+type PTRRecord struct {
+	Hostname  string // PTR 호스트 이름 (끝의 "." 제거, 없으면 빈 문자열)
+	Confirmed bool   // 정방향 조회 결과에 원래 IP 가 포함됨
+}
+
+// String 알림 표시용 문자열 (정방향 확인 실패 시 표시)
+func (r PTRRecord) String() string {
+	if r.Hostname == "" || r.Confirmed {
+		return r.Hostname
+	}
+	return r.Hostname + " (정방향 조회 불일치)"
+}
+
+// reverseDNSEntry 조회 캐시 항목
+type reverseDNSEntry struct {
+	record PTRRecord
+	at     time.Time
+}
+
+// ReverseDNS 캐시/비동기 역방향 DNS 조회기 (nil 이면 조회하지 않음)
+type ReverseDNS struct {
+	timeout  time.Duration
+	resolver *net.Resolver
+	cache    map[string]*reverseDNSEntry
+	pending  map[string][]func(PTRRecord) // 조회 중인 IP → 조회 후 실행할 콜백
+	lookups  sync.WaitGroup               // 진행 중인 조회
+	mutex    sync.Mutex
+}
+
+// NewReverseDNS 새로운 역방향 DNS 조회기 생성 (timeout 이 0 이하면 기본값)
+func NewReverseDNS(timeout time.Duration) *ReverseDNS {
+	if timeout <= 0 {
+		timeout = DefaultReverseDNSTimeout * time.Millisecond
+	}
+	return &ReverseDNS{
+		timeout:  timeout,
+		resolver: net.DefaultResolver,
+		cache:    make(map[string]*reverseDNSEntry),
+		pending:  make(map[string][]func(PTRRecord)),
+	}
+}
+
+// Enabled 역방향 DNS 조회가 활성화되어 있는지 확인
+func (rd *ReverseDNS) Enabled() bool {
+	return rd != nil
+}
+
+// LookupAsync 캐시에 없으면 조회 고루틴에서 결과를 캐시에 저장한 뒤 done 을 실행하고 true 반환
+// 비활성화되었거나 캐시에 있으면 호출한 고루틴에서 즉시 done 을 실행하고 false 반환 (같은 IP 조회가 진행 중이면 함께 대기)
+func (rd *ReverseDNS) LookupAsync(ip string, done func(PTRRecord)) bool {
+	if rd == nil || net.ParseIP(ip) == nil {
+		done(PTRRecord{})
+		return false
+	}
+
+	rd.mutex.Lock()
+	if entry := rd.cache[ip]; entry != nil && rd.fresh(entry, time.Now()) {
+		rd.mutex.Unlock()
+		done(entry.record)
+		return false
+	}
+	waiting, inflight := rd.pending[ip]
+	rd.pending[ip] = append(waiting, done)
+	rd.mutex.Unlock()
+	if inflight {
+		return true
+	}
+
+	rd.lookups.Add(1)
+	go func() {
+		defer rd.lookups.Done()
+		record := rd.lookup(ip)
+
+		rd.mutex.Lock()
+		rd.cache[ip] = &reverseDNSEntry{record: record, at: time.Now()}
+		if len(rd.cache) > reverseDNSMaxCacheEntries {
+			now := time.Now()
+			for cachedIP, cached := range rd.cache {
+				if !rd.fresh(cached, now) {
+					delete(rd.cache, cachedIP)
+				}
+			}
+		}
+		callbacks := rd.pending[ip]
+		delete(rd.pending, ip)
+		rd.mutex.Unlock()
+		for _, callback := range callbacks {
+			callback(record)
+		}
+	}()
+	return true
+}
+
+// Lookup 캐시된 조회 결과 (외부 조회 없음, 없으면 빈 레코드)
+func (rd *ReverseDNS) Lookup(ip string) PTRRecord {
+	if rd == nil {
+		return PTRRecord{}
+	}
+	rd.mutex.Lock()
+	defer rd.mutex.Unlock()
+	if entry := rd.cache[ip]; entry != nil && rd.fresh(entry, time.Now()) {
+		return entry.record
+	}
+	return PTRRecord{}
+}
+
+// Wait 진행 중인 조회(콜백 포함)가 끝날 때까지 대기
+func (rd *ReverseDNS) Wait() {
+	if rd != nil {
+		rd.lookups.Wait()
+	}
+}
+
+// fresh 캐시 항목이 아직 유효한지 확인 (mutex 보유 상태에서 호출)
+func (rd *ReverseDNS) fresh(entry *reverseDNSEntry, now time.Time) bool {
+	ttl := reverseDNSCacheTTL
+	if entry.record.Hostname == "" {
+		ttl = reverseDNSNegativeTTL
+	}
+	return now.Sub(entry.at) < ttl
+}
+
+// lookup PTR 조회 후 정방향 조회로 확인 (PTR 이 여러 개면 정방향 확인된 첫 이름, 없으면 첫 이름)
+func (rd *ReverseDNS) lookup(ip string) PTRRecord {
+	ctx, cancel := context.WithTimeout(context.Background(), rd.timeout)
+	defer cancel()
+
+	names, err := rd.resolver.LookupAddr(ctx, ip)
+	if err != nil || len(names) == 0 {
+		return PTRRecord{}
+	}
+
+	target := net.ParseIP(ip)
+	var record PTRRecord
+	for _, name := range names {
+		name = strings.TrimSuffix(name, ".")
+		if name == "" {
+			continue
+		}
+		if record.Hostname == "" {
+			record.Hostname = name
+		}
+		addrs, err := rd.resolver.LookupIPAddr(ctx, name)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if addr.IP.Equal(target) {
+				return PTRRecord{Hostname: name, Confirmed: true}
+			}
+		}
+	}
+	return record
+}
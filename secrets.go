@@ -0,0 +1,287 @@
+/*
+Secrets Module
+==============
+
+SMTP 비밀번호 등 자격 증명 조회/저장 (syslog-monitor secrets)
+
+자격 증명은 바이너리에 포함하지 않고 다음 순서로만 조회합니다:
+명령줄 플래그 → 환경변수 → 설정 파일 → OS 키체인
+
+주요 기능:
+- OS 키체인 백엔드: macOS Keychain (security), Linux libsecret (secret-tool)
+- 하위 명령: syslog-monitor secrets set|get|delete|list <이름>
+- 이전 버전에 포함되었던 기본 Gmail 앱 비밀번호는 폐기된 값으로 간주하여 시작을 거부
+
+키체인 항목은 서비스 "syslog-monitor", 계정 <비밀 이름> 으로 저장됩니다.
+*/
+package main
+
+import (
+	"bufio"         // 표준 입력 읽기
+	"crypto/sha256" // 폐기된 비밀번호 비교
+	"encoding/hex"  // 해시 문자열
+	"errors"        // 비밀 없음 에러
+	"flag"          // 하위 명령 옵션
+	"fmt"           // 형식화된 I/O
+	"os"            // 환경변수, 표준 입출력
+	"os/exec"       // security / secret-tool 실행
+	"runtime"       // OS별 키체인 선택
+	"strings"       // 문자열 처리
+)
+
+// 키체인 서비스 이름과 비밀 이름
+const (
+	SecretService                = "syslog-monitor"
+	SecretSMTPPassword           = "smtp-password"
+	SecretSMTPOAuth2ClientSecret = "smtp-oauth2-client-secret"
+	SecretAlertActionKey         = "alert-action-key"
+	SecretSlackSigningSecret     = "slack-signing-secret"
+	SecretCanaryIMAPPassword     = "canary-imap-password"
+)
+
+// KnownSecrets 비밀 이름 → 환경변수 (secrets 하위 명령이 다루는 항목)
+var KnownSecrets = map[string]string{
+	SecretSMTPPassword:           "SYSLOG_SMTP_PASSWORD",
+	SecretSMTPOAuth2ClientSecret: "SYSLOG_SMTP_OAUTH2_CLIENT_SECRET",
+	SecretAlertActionKey:         "SYSLOG_ALERT_ACTION_KEY",
+	SecretSlackSigningSecret:     "SYSLOG_SLACK_SIGNING_SECRET",
+	SecretCanaryIMAPPassword:     "SYSLOG_CANARY_IMAP_PASSWORD",
+}
+
+// revokedSMTPPasswordHashes 이전 버전에 포함되어 공개된 Gmail 앱 비밀번호 (공백 제거 후 SHA-256)
+var revokedSMTPPasswordHashes = map[string]bool{
+	"36661aa2e7db8b76fb3b8637b481beab7a73673c20a9c3186432ff63319271da": true,
+	"13f523f48398d507ae6b9be0c02d1848a6e47ce7863a6cf831c1328d030c7bac": true,
+}
+
+// ErrSecretNotFound 키체인에 비밀이 없음
+var ErrSecretNotFound = errors.New("secret not found")
+
+// ErrKeychainUnsupported 이 OS 에서 사용할 수 있는 키체인 백엔드가 없음
+var ErrKeychainUnsupported = errors.New("no OS keychain backend (macOS Keychain or libsecret secret-tool) available")
+
+// KeychainGet OS 키체인에서 비밀 조회
+func KeychainGet(name string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", SecretService, "-a", name, "-w")
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return "", ErrKeychainUnsupported
+		}
+		cmd = exec.Command("secret-tool", "lookup", "service", SecretService, "account", name)
+	default:
+		return "", ErrKeychainUnsupported
+	}
+	output, err := cmd.Output()
+	value := strings.TrimRight(string(output), "\r\n")
+	if err != nil || value == "" {
+		// security 는 항목이 없으면 종료 코드 44, secret-tool 은 출력 없이 1 을 반환
+		if _, ok := err.(*exec.ExitError); ok || err == nil {
+			return "", ErrSecretNotFound
+		}
+		return "", fmt.Errorf("keychain lookup failed: %v", err)
+	}
+	return value, nil
+}
+
+// KeychainSet OS 키체인에 비밀 저장 (있으면 교체)
+func KeychainSet(name, value string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// security 는 값을 인자로만 받음 (실행 중 잠시 프로세스 목록에 노출됨)
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", SecretService, "-a", name,
+			"-l", SecretService+" "+name, "-w", value)
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return ErrKeychainUnsupported
+		}
+		// secret-tool 은 표준 입력에서 값을 읽음
+		cmd = exec.Command("secret-tool", "store", "--label="+SecretService+" "+name, "service", SecretService, "account", name)
+		cmd.Stdin = strings.NewReader(value)
+	default:
+		return ErrKeychainUnsupported
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("keychain store failed: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// KeychainDelete OS 키체인에서 비밀 삭제
+func KeychainDelete(name string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "delete-generic-password", "-s", SecretService, "-a", name)
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if _, err := exec.LookPath("secret-tool"); err != nil {
+			return ErrKeychainUnsupported
+		}
+		cmd = exec.Command("secret-tool", "clear", "service", SecretService, "account", name)
+	default:
+		return ErrKeychainUnsupported
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if runtime.GOOS == "darwin" {
+			return ErrSecretNotFound
+		}
+		return fmt.Errorf("keychain delete failed: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ResolveSecret 비밀 조회 (플래그 → 환경변수 → 설정 파일 → OS 키체인), 값과 출처 반환
+func ResolveSecret(name, flagValue, configValue string) (string, string) {
+	if flagValue != "" {
+		return flagValue, "flag"
+	}
+	if env := KnownSecrets[name]; env != "" {
+		if value := os.Getenv(env); value != "" {
+			return value, "env " + env
+		}
+	}
+	if configValue != "" {
+		return configValue, "config"
+	}
+	if value, err := KeychainGet(name); err == nil {
+		return value, "keychain"
+	}
+	return "", ""
+}
+
+// CheckRevokedSMTPPassword 이전 버전에 포함되었던 기본 앱 비밀번호면 에러
+func CheckRevokedSMTPPassword(password string) error {
+	normalized := strings.ToLower(strings.Join(strings.Fields(password), ""))
+	if normalized == "" {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(normalized))
+	if revokedSMTPPasswordHashes[hex.EncodeToString(sum[:])] {
+		return fmt.Errorf("the SMTP password is a built-in default from an earlier release and has been revoked; " +
+			"create your own app password and store it with 'syslog-monitor secrets set smtp-password'")
+	}
+	return nil
+}
+
+// runSecretsCommand secrets 하위 명령 (syslog-monitor secrets set|get|delete|list [이름])
+func runSecretsCommand(args []string) {
+	fs := flag.NewFlagSet("secrets", flag.ExitOnError)
+	fromStdin := fs.Bool("stdin", false, "Read the value for 'set' from standard input without prompting (for scripts)")
+	fs.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("  syslog-monitor secrets [options] set|get|delete <name>")
+		fmt.Println("  syslog-monitor secrets list")
+		fmt.Println()
+		fmt.Println("Secrets:")
+		for _, name := range []string{SecretSMTPPassword, SecretSMTPOAuth2ClientSecret, SecretAlertActionKey, SecretSlackSigningSecret, SecretCanaryIMAPPassword} {
+			fmt.Printf("  %-26s (env: %s)\n", name, KnownSecrets[name])
+		}
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  syslog-monitor secrets set smtp-password")
+		fmt.Println("  syslog-monitor secrets -stdin set smtp-password < app-password.txt")
+		fmt.Println("  syslog-monitor secrets list")
+	}
+	fs.Parse(args)
+
+	action, name := fs.Arg(0), fs.Arg(1)
+	if action == "list" {
+		for _, name := range []string{SecretSMTPPassword, SecretSMTPOAuth2ClientSecret, SecretAlertActionKey, SecretSlackSigningSecret, SecretCanaryIMAPPassword} {
+			source := "not set"
+			if os.Getenv(KnownSecrets[name]) != "" {
+				source = "env " + KnownSecrets[name]
+			} else if _, err := KeychainGet(name); err == nil {
+				source = "keychain"
+			}
+			fmt.Printf("%-26s %s\n", name, source)
+		}
+		return
+	}
+	if action == "" || name == "" || fs.NArg() > 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	if _, ok := KnownSecrets[name]; !ok {
+		fmt.Printf("❌ 알 수 없는 비밀 이름: %s\n", name)
+		os.Exit(1)
+	}
+
+	switch action {
+	case "set":
+		value, err := readSecretValue(name, *fromStdin)
+		if err != nil {
+			fmt.Printf("❌ 비밀 입력 오류: %v\n", err)
+			os.Exit(1)
+		}
+		if name == SecretSMTPPassword {
+			if err := CheckRevokedSMTPPassword(value); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := KeychainSet(name, value); err != nil {
+			fmt.Printf("❌ 키체인 저장 실패: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ %s stored in the OS keychain\n", name)
+	case "get":
+		value, err := KeychainGet(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		fmt.Println(value)
+	case "delete":
+		if err := KeychainDelete(name); err != nil {
+			fmt.Printf("❌ %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		fmt.Printf("🗑️  %s removed from the OS keychain\n", name)
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+}
+
+// readSecretValue 비밀 값 입력 (터미널이면 입력을 화면에 표시하지 않음)
+func readSecretValue(name string, fromStdin bool) (string, error) {
+	info, err := os.Stdin.Stat()
+	terminal := err == nil && info.Mode()&os.ModeCharDevice != 0
+	if terminal && !fromStdin {
+		fmt.Fprintf(os.Stderr, "Enter %s: ", name)
+		if runtime.GOOS != "windows" {
+			setTerminalEcho(false)
+			defer func() {
+				setTerminalEcho(true)
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("no value read from standard input")
+	}
+	value := strings.TrimRight(line, "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("empty value")
+	}
+	return value, nil
+}
+
+// setTerminalEcho stty 로 터미널 입력 표시 전환
+func setTerminalEcho(on bool) {
+	mode := "-echo"
+	if on {
+		mode = "echo"
+	}
+	cmd := exec.Command("stty", mode)
+	cmd.Stdin = os.Stdin
+	cmd.Run()
+}
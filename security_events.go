@@ -0,0 +1,516 @@
+/*
+Security Event Output Module
+============================
+
+보안 탐지를 표준 스키마로 정규화해 보안 데이터 레이크가 별도 매핑 없이 수집하도록 출력
+
+주요 기능:
+  - OCSF 1.1.0 (-ocsf-output): 보안 알림마다 OCSF 이벤트 한 줄 (NDJSON, 파일 이어쓰기 또는 - 이면 stdout)
+  - 로그인 알림 → Authentication (class_uid 3002): 사용자, 출처 IP, 성공/실패, 인증 방식
+  - 그 외 보안 탐지 → Detection Finding (class_uid 2004): 제목, 탐지 방식(analytic), 관측값(IP/사용자/호스트)
+  - SARIF 2.1.0 (-replay-sarif): 보관된 로그 재처리(-replay)를 스캔으로 보고 탐지를 결과(result)로 기록
+    (규칙 = 알림 유형, AI 알림은 일치한 이상 패턴까지 / 위치 = 재처리 입력 파일)
+  - 보안 탐지가 아닌 알림 (시스템 리소스, 정기 보고서, 부팅, SLO 등) 은 출력하지 않음
+  - 심각도: info → Informational(1)/note, warning → Medium(3)/warning, critical → Critical(5)/error
+  - 알림 필드 중 표준 속성으로 옮기지 않은 값은 OCSF unmapped, SARIF properties 에 그대로 보존
+*/
+package main
+
+import (
+	"encoding/json" // 이벤트 인코딩
+	"fmt"           // 형식화된 I/O
+	"io"            // 출력 인터페이스
+	"os"            // 파일 처리
+	"sort"          // 규칙 정렬
+	"strings"       // 문자열 처리
+	"sync"          // 동기화 (동시 전송)
+)
+
+// 표준 스키마 버전
+const (
+	OCSFSchemaVersion  = "1.1.0"
+	SARIFSchemaVersion = "2.1.0"
+	sarifSchemaURI     = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// OCSF 클래스 (category_uid*1000 + 번호)
+const (
+	ocsfClassDetectionFinding = 2004 // Findings: Detection Finding
+	ocsfClassAuthentication   = 3002 // Identity & Access Management: Authentication
+	ocsfCategoryFindings      = 2
+	ocsfCategoryIAM           = 3
+)
+
+// OCSF analytic.type_id
+const (
+	ocsfAnalyticRule        = 1
+	ocsfAnalyticBehavioral  = 2
+	ocsfAnalyticStatistical = 3
+	ocsfAnalyticLearning    = 4
+)
+
+// securityAlertAnalytics 보안 탐지로 출력하는 알림 유형과 탐지 방식 (OCSF analytic.type_id)
+var securityAlertAnalytics = map[string]int{
+	AlertTypeLogin:        ocsfAnalyticRule,
+	AlertTypeAI:           ocsfAnalyticRule,
+	AlertTypeBruteForce:   ocsfAnalyticBehavioral,
+	AlertTypeDBPrivilege:  ocsfAnalyticRule,
+	AlertTypeSQLInjection: ocsfAnalyticRule,
+	AlertTypeWebAttack:    ocsfAnalyticRule,
+	AlertTypeExfiltration: ocsfAnalyticBehavioral,
+	AlertTypeBaseline:     ocsfAnalyticStatistical,
+}
+
+// IsSecurityAlert 보안 탐지 알림인지 (OCSF/SARIF 출력 대상)
+func IsSecurityAlert(alert Alert) bool {
+	_, ok := securityAlertAnalytics[alert.Type]
+	return ok
+}
+
+// 관측값으로 옮기는 알림 필드 (OCSF observable type_id: 1 Hostname, 2 IP Address, 4 User Name)
+var (
+	securityIPFields   = []string{"ip", "client", "client_ip"}
+	securityUserFields = []string{"user", "account"}
+)
+
+// ocsfSeverity 알림 심각도 → OCSF severity_id, severity
+func ocsfSeverity(severity string) (int, string) {
+	switch severity {
+	case AlertSeverityCritical:
+		return 5, "Critical"
+	case AlertSeverityWarning:
+		return 3, "Medium"
+	default:
+		return 1, "Informational"
+	}
+}
+
+// OCSFEvent OCSF 이벤트 (Authentication, Detection Finding 공통 속성 + 클래스별 속성)
+type OCSFEvent struct {
+	ActivityID   int               `json:"activity_id"`
+	ActivityName string            `json:"activity_name,omitempty"`
+	CategoryUID  int               `json:"category_uid"`
+	ClassUID     int               `json:"class_uid"`
+	TypeUID      int               `json:"type_uid"`
+	SeverityID   int               `json:"severity_id"`
+	Severity     string            `json:"severity"`
+	StatusID     int               `json:"status_id,omitempty"`
+	Status       string            `json:"status,omitempty"`
+	Time         int64             `json:"time"` // 밀리초 단위 Unix 시각
+	Message      string            `json:"message,omitempty"`
+	Metadata     OCSFMetadata      `json:"metadata"`
+	Device       *OCSFDevice       `json:"device,omitempty"`
+	FindingInfo  *OCSFFindingInfo  `json:"finding_info,omitempty"`  // Detection Finding
+	User         *OCSFUser         `json:"user,omitempty"`          // Authentication
+	SrcEndpoint  *OCSFEndpoint     `json:"src_endpoint,omitempty"`  // Authentication
+	DstEndpoint  *OCSFEndpoint     `json:"dst_endpoint,omitempty"`  // Authentication
+	AuthProtocol string            `json:"auth_protocol,omitempty"` // Authentication (ssh, sudo, web 등)
+	Observables  []OCSFObservable  `json:"observables,omitempty"`
+	Unmapped     map[string]string `json:"unmapped,omitempty"`
+	Enrichments  []OCSFEnrichment  `json:"enrichments,omitempty"`
+}
+
+// OCSFMetadata 이벤트 메타데이터
+type OCSFMetadata struct {
+	Version        string      `json:"version"`
+	UID            string      `json:"uid,omitempty"`             // 알림 ID
+	CorrelationUID string      `json:"correlation_uid,omitempty"` // 인시던트 키 (후속/복구 알림 묶음)
+	Product        OCSFProduct `json:"product"`
+	LogName        string      `json:"log_name,omitempty"` // 알림 유형
+}
+
+// OCSFProduct 이벤트를 만든 제품
+type OCSFProduct struct {
+	Name       string `json:"name"`
+	VendorName string `json:"vendor_name"`
+	Version    string `json:"version"`
+}
+
+// OCSFDevice 이벤트가 발생한 호스트
+type OCSFDevice struct {
+	Hostname string `json:"hostname"`
+	TypeID   int    `json:"type_id"` // 1 Server
+}
+
+// OCSFFindingInfo 탐지 정보
+type OCSFFindingInfo struct {
+	UID      string       `json:"uid"`
+	Title    string       `json:"title"`
+	Desc     string       `json:"desc,omitempty"`
+	Types    []string     `json:"types,omitempty"`
+	Analytic OCSFAnalytic `json:"analytic"`
+}
+
+// OCSFAnalytic 탐지 방식
+type OCSFAnalytic struct {
+	Name   string `json:"name"`
+	TypeID int    `json:"type_id"`
+}
+
+// OCSFUser 사용자
+type OCSFUser struct {
+	Name string `json:"name"`
+}
+
+// OCSFEndpoint 네트워크 엔드포인트
+type OCSFEndpoint struct {
+	IP       string `json:"ip,omitempty"`
+	Hostname string `json:"hostname,omitempty"`
+}
+
+// OCSFObservable 관측값 (데이터 레이크의 IP/사용자 검색용)
+type OCSFObservable struct {
+	Name   string `json:"name"`
+	TypeID int    `json:"type_id"`
+	Value  string `json:"value"`
+}
+
+// OCSFEnrichment 보강 정보 (위치, 위협 인텔리전스)
+type OCSFEnrichment struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// NewOCSFEvent 보안 알림을 OCSF 이벤트로 변환 (보안 탐지가 아니면 false)
+func NewOCSFEvent(alert Alert) (OCSFEvent, bool) {
+	analyticType, ok := securityAlertAnalytics[alert.Type]
+	if !ok {
+		return OCSFEvent{}, false
+	}
+	severityID, severity := ocsfSeverity(alert.Severity)
+	event := OCSFEvent{
+		SeverityID: severityID,
+		Severity:   severity,
+		Time:       alert.Timestamp.UnixMilli(),
+		Message:    alert.Title,
+		Metadata: OCSFMetadata{
+			Version:        OCSFSchemaVersion,
+			UID:            alert.ID,
+			CorrelationUID: alert.Thread,
+			Product:        OCSFProduct{Name: AppName, VendorName: "Lambda-X", Version: AppVersion},
+			LogName:        alert.Type,
+		},
+		Unmapped: make(map[string]string),
+	}
+	if alert.Host != "" {
+		event.Device = &OCSFDevice{Hostname: alert.Host, TypeID: 1}
+	}
+	mapped := map[string]bool{}
+
+	if alert.Type == AlertTypeLogin {
+		// Authentication: 1 Logon (sudo/su 도 권한 상승 인증으로 기록)
+		event.ClassUID, event.CategoryUID = ocsfClassAuthentication, ocsfCategoryIAM
+		event.ActivityID, event.ActivityName = 1, "Logon"
+		event.StatusID, event.Status = ocsfLoginStatus(alert.Fields["status"])
+		if user := alert.Fields["user"]; user != "" {
+			event.User = &OCSFUser{Name: user}
+		}
+		if ip := alert.Fields["ip"]; ip != "" {
+			event.SrcEndpoint = &OCSFEndpoint{IP: ip, Hostname: alert.Fields["hostname"]}
+			mapped["hostname"] = true
+		}
+		if alert.Host != "" {
+			event.DstEndpoint = &OCSFEndpoint{Hostname: alert.Host}
+		}
+		event.AuthProtocol = alert.Fields["method"]
+		for _, name := range []string{"status", "user", "ip", "method", "timestamp"} {
+			mapped[name] = true
+		}
+		for _, name := range []string{"country", "threat", "anonymizer", "blocklist"} {
+			if value := alert.Fields[name]; value != "" {
+				event.Enrichments = append(event.Enrichments, OCSFEnrichment{Name: "src_endpoint.ip", Type: name, Value: value})
+				mapped[name] = true
+			}
+		}
+	} else {
+		// Detection Finding: 1 Create, 상태 1 New
+		event.ClassUID, event.CategoryUID = ocsfClassDetectionFinding, ocsfCategoryFindings
+		event.ActivityID, event.ActivityName = 1, "Create"
+		event.StatusID, event.Status = 1, "New"
+		analytic := alert.Type
+		if rule := alert.Fields["rule"]; rule != "" {
+			analytic = rule
+			mapped["rule"] = true
+		}
+		if alert.Type == AlertTypeAI && alert.Fields["model_score"] != "" {
+			analyticType = ocsfAnalyticLearning
+		}
+		event.FindingInfo = &OCSFFindingInfo{
+			UID:      alert.ID,
+			Title:    alert.Title,
+			Desc:     alert.Headline,
+			Types:    []string{alert.Type},
+			Analytic: OCSFAnalytic{Name: analytic, TypeID: analyticType},
+		}
+	}
+	event.TypeUID = event.ClassUID*100 + event.ActivityID
+
+	// 관측값 (IP, 사용자, 호스트)
+	for _, name := range securityIPFields {
+		if value := alert.Fields[name]; value != "" {
+			event.Observables = append(event.Observables, OCSFObservable{Name: name, TypeID: 2, Value: value})
+		}
+	}
+	for _, name := range securityUserFields {
+		if value := alert.Fields[name]; value != "" {
+			event.Observables = append(event.Observables, OCSFObservable{Name: name, TypeID: 4, Value: value})
+		}
+	}
+	if alert.Host != "" {
+		event.Observables = append(event.Observables, OCSFObservable{Name: "device.hostname", TypeID: 1, Value: alert.Host})
+	}
+
+	for name, value := range alert.Fields {
+		if !mapped[name] && value != "" {
+			event.Unmapped[name] = value
+		}
+	}
+	if len(event.Unmapped) == 0 {
+		event.Unmapped = nil
+	}
+	return event, true
+}
+
+// ocsfLoginStatus 로그인 상태 → OCSF status_id, status (1 Success, 2 Failure)
+func ocsfLoginStatus(status string) (int, string) {
+	switch status {
+	case "failed", "sudo_denied":
+		return 2, "Failure"
+	case "":
+		return 0, "Unknown"
+	default:
+		return 1, "Success"
+	}
+}
+
+// OCSFSink 보안 알림을 OCSF NDJSON 으로 기록하는 알림 채널 (-ocsf-output)
+type OCSFSink struct {
+	writer io.Writer
+	mutex  sync.Mutex
+}
+
+// NewOCSFSink OCSF 출력 생성 (path 가 - 이면 stdout, 파일은 이어쓰기)
+func NewOCSFSink(path string) (*OCSFSink, error) {
+	if path == "-" {
+		return &OCSFSink{writer: os.Stdout}, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open OCSF output: %v", err)
+	}
+	return &OCSFSink{writer: file}, nil
+}
+
+// Send 보안 알림이면 OCSF 이벤트 한 줄 기록 (보안 탐지가 아닌 알림은 무시)
+func (sink *OCSFSink) Send(alert Alert) error {
+	event, ok := NewOCSFEvent(alert)
+	if !ok {
+		return nil
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode OCSF event: %v", err)
+	}
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	_, err = sink.writer.Write(append(line, '\n'))
+	return err
+}
+
+// SARIFLog SARIF 2.1.0 로그 (최상위)
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun 도구 실행 한 번 (재처리 한 번)
+type SARIFRun struct {
+	Tool        SARIFTool         `json:"tool"`
+	Artifacts   []SARIFArtifact   `json:"artifacts,omitempty"`
+	Results     []SARIFResult     `json:"results"`
+	Invocations []SARIFInvocation `json:"invocations,omitempty"`
+}
+
+// SARIFTool 도구 정보와 규칙 목록
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver 도구 드라이버
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule 규칙 (알림 유형 또는 이상 패턴)
+type SARIFRule struct {
+	ID               string            `json:"id"`
+	ShortDescription SARIFMessage      `json:"shortDescription"`
+	Properties       map[string]string `json:"properties,omitempty"`
+}
+
+// SARIFMessage 메시지
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFArtifact 스캔한 파일
+type SARIFArtifact struct {
+	Location SARIFArtifactLocation `json:"location"`
+}
+
+// SARIFArtifactLocation 파일 위치
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFResult 탐지 결과 한 건
+type SARIFResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"` // note, warning, error
+	Message             SARIFMessage      `json:"message"`
+	Locations           []SARIFLocation   `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"` // 인시던트 키 (실행 간 같은 탐지 식별)
+	Properties          map[string]string `json:"properties,omitempty"`
+}
+
+// SARIFLocation 결과 위치
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation 파일 위치
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+}
+
+// SARIFInvocation 실행 결과
+type SARIFInvocation struct {
+	ExecutionSuccessful bool `json:"executionSuccessful"`
+}
+
+// sarifLevel 알림 심각도 → SARIF level
+func sarifLevel(severity string) string {
+	switch severity {
+	case AlertSeverityCritical:
+		return "error"
+	case AlertSeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// SARIFBuilder 재처리 중 보안 알림을 SARIF 결과로 모음
+type SARIFBuilder struct {
+	rules     map[string]int // 규칙 ID → rules 인덱스
+	ruleList  []SARIFRule
+	artifacts []string
+	results   []SARIFResult
+	mutex     sync.Mutex
+}
+
+// NewSARIFBuilder SARIF 결과 모음 생성
+func NewSARIFBuilder() *SARIFBuilder {
+	return &SARIFBuilder{rules: make(map[string]int)}
+}
+
+// Add 보안 알림을 input 에서 발견한 결과로 추가 (보안 탐지가 아닌 알림은 무시)
+func (sb *SARIFBuilder) Add(alert Alert, input string) {
+	if !IsSecurityAlert(alert) {
+		return
+	}
+	ruleID := alert.Type
+	if rule := alert.Fields["rule"]; rule != "" {
+		ruleID = alert.Type + "/" + rule
+	}
+
+	sb.mutex.Lock()
+	defer sb.mutex.Unlock()
+	index, ok := sb.rules[ruleID]
+	if !ok {
+		index = len(sb.ruleList)
+		sb.rules[ruleID] = index
+		sb.ruleList = append(sb.ruleList, SARIFRule{
+			ID:               ruleID,
+			ShortDescription: SARIFMessage{Text: fmt.Sprintf("%s detection", strings.ReplaceAll(ruleID, "_", " "))},
+			Properties:       map[string]string{"alert_type": alert.Type},
+		})
+	}
+	if input != "" && !containsString(sb.artifacts, input) {
+		sb.artifacts = append(sb.artifacts, input)
+	}
+
+	result := SARIFResult{
+		RuleID:     ruleID,
+		RuleIndex:  index,
+		Level:      sarifLevel(alert.Severity),
+		Message:    SARIFMessage{Text: alert.Title},
+		Properties: map[string]string{"severity": alert.Severity, "host": alert.Host, "timestamp": alert.Timestamp.UTC().Format("2006-01-02T15:04:05Z")},
+	}
+	if alert.Headline != "" {
+		result.Message.Text += "\n" + alert.Headline
+	}
+	if input != "" {
+		result.Locations = []SARIFLocation{{PhysicalLocation: SARIFPhysicalLocation{ArtifactLocation: SARIFArtifactLocation{URI: input}}}}
+	}
+	if alert.Thread != "" {
+		result.PartialFingerprints = map[string]string{"incident/v1": alert.Thread}
+	}
+	for name, value := range alert.Fields {
+		if value != "" {
+			result.Properties[name] = value
+		}
+	}
+	sb.results = append(sb.results, result)
+}
+
+// Results 모은 결과 수
+func (sb *SARIFBuilder) Results() int {
+	sb.mutex.Lock()
+	defer sb.mutex.Unlock()
+	return len(sb.results)
+}
+
+// Log SARIF 로그 생성 (complete 가 false 면 중단된 실행으로 기록)
+func (sb *SARIFBuilder) Log(complete bool) SARIFLog {
+	sb.mutex.Lock()
+	defer sb.mutex.Unlock()
+
+	artifacts := make([]SARIFArtifact, 0, len(sb.artifacts))
+	inputs := append([]string(nil), sb.artifacts...)
+	sort.Strings(inputs)
+	for _, input := range inputs {
+		artifacts = append(artifacts, SARIFArtifact{Location: SARIFArtifactLocation{URI: input}})
+	}
+	results := append([]SARIFResult{}, sb.results...)
+
+	return SARIFLog{
+		Schema:  sarifSchemaURI,
+		Version: SARIFSchemaVersion,
+		Runs: []SARIFRun{{
+			Tool: SARIFTool{Driver: SARIFDriver{
+				Name:    AppName,
+				Version: AppVersion,
+				Rules:   append([]SARIFRule{}, sb.ruleList...),
+			}},
+			Artifacts:   artifacts,
+			Results:     results,
+			Invocations: []SARIFInvocation{{ExecutionSuccessful: complete}},
+		}},
+	}
+}
+
+// WriteFile SARIF 로그를 파일로 저장
+func (sb *SARIFBuilder) WriteFile(path string, complete bool) error {
+	data, err := json.MarshalIndent(sb.Log(complete), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
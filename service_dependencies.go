@@ -0,0 +1,451 @@
+/*
+Service Dependencies Module
+===========================
+
+서비스 의존 관계 기반 근본 원인 추정 (설정 파일 dependencies)
+
+web → db → disk 처럼 구성 요소 사이의 의존 관계를 선언해 두면, 의존 체인을 따라
+여러 구성 요소에서 장애 알림이 함께 발생했을 때 가장 상류(다른 장애 구성 요소에
+의존하지 않는)에서 실패한 구성 요소를 근본 원인으로 추정한 인시던트 알림을 보냅니다.
+
+주요 기능:
+- 구성 요소는 routes 와 같은 match 조건 또는 로그 서비스 이름(services)으로 알림과 연결
+- warning/critical 알림만 장애로 취급하며 window_seconds(기본 300초) 동안 유지
+- 의존 관계로 이어진 장애 구성 요소가 2개 이상이면 인시던트 알림 (유형 incident)
+- 같은 근본 원인에 대해서는 장애 구성 요소가 늘어날 때만 다시 알림
+- 의존 관계 순환, 알 수 없는 구성 요소, 조건 없는 구성 요소는 설정 오류
+
+예:
+
+	"dependencies": {
+	    "window_seconds": 300,
+	    "components": [
+	        {"name": "web", "services": ["nginx"], "depends_on": ["db"]},
+	        {"name": "db", "services": ["mysqld", "postgres"], "depends_on": ["disk"]},
+	        {"name": "disk", "match": {"type": ["system"], "keyword": "disk", "host": "db-01"}}
+	    ]
+	}
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"reflect" // 설정 비교
+	"sort"    // 근본 원인/체인 정렬
+	"strings" // 문자열 처리
+	"sync"    // 동시성 제어
+	"time"    // 시간 처리
+)
+
+// DefaultDependencyWindow 장애를 같은 인시던트로 묶는 기본 시간
+const DefaultDependencyWindow = 5 * time.Minute
+
+// ServiceDependencyConfig 서비스 의존 관계 설정 (설정 파일 dependencies)
+type ServiceDependencyConfig struct {
+	WindowSeconds int                `json:"window_seconds"` // 장애를 같은 인시던트로 묶는 시간 (0 이면 300초)
+	Components    []ServiceComponent `json:"components"`     // 구성 요소 (알림은 처음 일치한 구성 요소에 연결)
+}
+
+// ServiceComponent 의존 관계의 구성 요소
+type ServiceComponent struct {
+	Name      string          `json:"name"`       // 구성 요소 이름 (web, db, disk 등)
+	Match     AlertRouteMatch `json:"match"`      // 이 구성 요소의 장애로 볼 알림 (routes 의 match 와 같은 형식)
+	Services  []string        `json:"services"`   // 알림 service 필드 (로그 서비스 이름, 예: nginx, mysqld)
+	DependsOn []string        `json:"depends_on"` // 이 구성 요소가 의존하는 구성 요소 (상류)
+}
+
+// dependencyComponent 검증한 구성 요소
+type dependencyComponent struct {
+	name      string
+	match     compiledAlertRoute
+	matchAll  bool            // match 조건이 비어 있음 (services 로만 연결)
+	services  map[string]bool // 소문자 서비스 이름
+	dependsOn []string
+}
+
+// componentFailure 구성 요소의 최근 장애
+type componentFailure struct {
+	First    time.Time // 첫 장애 알림 시각
+	Last     time.Time // 마지막 장애 알림 시각
+	Seen     time.Time // 마지막 장애를 처리한 시각 (window 만료 기준)
+	Count    int       // window 안의 장애 알림 수
+	Severity string    // 가장 높은 심각도
+	Alert    Alert     // 마지막 장애 알림
+}
+
+// DependencyCorrelator 의존 관계를 따라 장애 알림을 묶어 근본 원인 추정
+type DependencyCorrelator struct {
+	components []*dependencyComponent
+	byName     map[string]*dependencyComponent
+	window     time.Duration
+	failures   map[string]*componentFailure
+	reported   map[string]map[string]bool // 근본 원인별 마지막 인시던트 알림에 포함한 구성 요소
+	mutex      sync.Mutex
+}
+
+// NewDependencyCorrelator 의존 관계 설정 검증 (구성 요소가 없으면 nil)
+func NewDependencyCorrelator(config ServiceDependencyConfig) (*DependencyCorrelator, error) {
+	if len(config.Components) == 0 {
+		return nil, nil
+	}
+	if config.WindowSeconds < 0 {
+		return nil, fmt.Errorf("window_seconds must not be negative")
+	}
+	dc := &DependencyCorrelator{
+		byName:   make(map[string]*dependencyComponent),
+		window:   DefaultDependencyWindow,
+		failures: make(map[string]*componentFailure),
+		reported: make(map[string]map[string]bool),
+	}
+	if config.WindowSeconds > 0 {
+		dc.window = time.Duration(config.WindowSeconds) * time.Second
+	}
+
+	for i, component := range config.Components {
+		name := strings.TrimSpace(component.Name)
+		if name == "" {
+			return nil, fmt.Errorf("component #%d: name is required", i+1)
+		}
+		if dc.byName[name] != nil {
+			return nil, fmt.Errorf("component %s: duplicate name", name)
+		}
+		match, err := compileAlertRouteMatch("component "+name, component.Match)
+		if err != nil {
+			return nil, err
+		}
+		compiled := &dependencyComponent{
+			name:     name,
+			match:    match,
+			matchAll: match.types == nil && match.levels == nil && match.host == "" && match.keyword == "",
+		}
+		for _, service := range component.Services {
+			if service = strings.ToLower(strings.TrimSpace(service)); service != "" {
+				if compiled.services == nil {
+					compiled.services = make(map[string]bool)
+				}
+				compiled.services[service] = true
+			}
+		}
+		if compiled.matchAll && compiled.services == nil {
+			return nil, fmt.Errorf("component %s: match or services is required", name)
+		}
+		for _, dependency := range component.DependsOn {
+			if dependency = strings.TrimSpace(dependency); dependency != "" && !containsString(compiled.dependsOn, dependency) {
+				compiled.dependsOn = append(compiled.dependsOn, dependency)
+			}
+		}
+		dc.components = append(dc.components, compiled)
+		dc.byName[name] = compiled
+	}
+
+	for _, component := range dc.components {
+		for _, dependency := range component.dependsOn {
+			if dependency == component.name {
+				return nil, fmt.Errorf("component %s: depends on itself", component.name)
+			}
+			if dc.byName[dependency] == nil {
+				return nil, fmt.Errorf("component %s: unknown dependency %q", component.name, dependency)
+			}
+		}
+	}
+	if cycle := dc.findCycle(); cycle != nil {
+		return nil, fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " → "))
+	}
+	return dc, nil
+}
+
+// findCycle 의존 관계 순환 경로 (없으면 nil)
+func (dc *DependencyCorrelator) findCycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(dc.components))
+	var path []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			for i, step := range path {
+				if step == name {
+					return append(append([]string(nil), path[i:]...), name)
+				}
+			}
+		case done:
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dependency := range dc.byName[name].dependsOn {
+			if cycle := visit(dependency); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		return nil
+	}
+	for _, component := range dc.components {
+		if cycle := visit(component.name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// componentFor 알림이 연결되는 구성 요소 (처음 일치한 구성 요소, 없으면 nil)
+func (dc *DependencyCorrelator) componentFor(alert Alert) *dependencyComponent {
+	service := strings.ToLower(serviceName(alert.Fields["service"]))
+	for _, component := range dc.components {
+		if component.services != nil && !component.services[service] {
+			continue
+		}
+		if !component.matchAll && !component.match.matches(alert) {
+			continue
+		}
+		return component
+	}
+	return nil
+}
+
+// Observe 알림을 구성 요소 장애로 기록하고, 의존 체인에 걸친 장애가 새로 확인되면 인시던트 알림 반환
+func (dc *DependencyCorrelator) Observe(alert Alert, now time.Time) *Alert {
+	if dc == nil || alert.Type == AlertTypeIncident {
+		return nil
+	}
+	if alert.Severity != AlertSeverityWarning && alert.Severity != AlertSeverityCritical {
+		return nil
+	}
+	component := dc.componentFor(alert)
+	if component == nil {
+		return nil
+	}
+
+	dc.mutex.Lock()
+	defer dc.mutex.Unlock()
+	dc.expire(now)
+
+	failure := dc.failures[component.name]
+	if failure == nil {
+		failure = &componentFailure{First: alert.Timestamp}
+		dc.failures[component.name] = failure
+	}
+	failure.Last = alert.Timestamp
+	failure.Seen = now
+	failure.Count++
+	failure.Alert = alert
+	if failure.Severity != AlertSeverityCritical {
+		failure.Severity = alert.Severity
+	}
+
+	members := dc.connectedFailures(component.name)
+	if len(members) < 2 {
+		return nil
+	}
+	levels := dc.levels(members)
+	roots := dc.rootCauses(members, levels)
+
+	reported := dc.reported[roots[0]]
+	covered := reported != nil
+	for _, name := range members {
+		if !reported[name] {
+			covered = false
+			break
+		}
+	}
+	if covered {
+		return nil
+	}
+	set := make(map[string]bool, len(members))
+	for _, name := range members {
+		set[name] = true
+	}
+	dc.reported[roots[0]] = set
+
+	incident := dc.incidentAlert(members, levels, roots)
+	return &incident
+}
+
+// expire window 가 지난 장애를 지우고, 인시던트 기록에서도 제외
+func (dc *DependencyCorrelator) expire(now time.Time) {
+	for name, failure := range dc.failures {
+		if now.Sub(failure.Seen) > dc.window {
+			delete(dc.failures, name)
+		}
+	}
+	for root, set := range dc.reported {
+		if dc.failures[root] == nil {
+			delete(dc.reported, root)
+			continue
+		}
+		for name := range set {
+			if dc.failures[name] == nil {
+				delete(set, name)
+			}
+		}
+	}
+}
+
+// connectedFailures start 와 의존 관계(양방향)로 이어진 장애 구성 요소 (선언 순서)
+func (dc *DependencyCorrelator) connectedFailures(start string) []string {
+	connected := map[string]bool{start: true}
+	queue := []string{start}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, component := range dc.components {
+			if connected[component.name] || dc.failures[component.name] == nil {
+				continue
+			}
+			if containsString(dc.byName[name].dependsOn, component.name) || containsString(component.dependsOn, name) {
+				connected[component.name] = true
+				queue = append(queue, component.name)
+			}
+		}
+	}
+	var members []string
+	for _, component := range dc.components {
+		if connected[component.name] {
+			members = append(members, component.name)
+		}
+	}
+	return members
+}
+
+// levels 장애 구성 요소별 근본 원인까지의 거리 (장애가 난 의존 대상이 없으면 0)
+func (dc *DependencyCorrelator) levels(members []string) map[string]int {
+	failing := make(map[string]bool, len(members))
+	for _, name := range members {
+		failing[name] = true
+	}
+	levels := make(map[string]int, len(members))
+	var level func(name string) int
+	level = func(name string) int {
+		if value, ok := levels[name]; ok {
+			return value
+		}
+		value := 0
+		for _, dependency := range dc.byName[name].dependsOn {
+			if failing[dependency] {
+				if next := level(dependency) + 1; next > value {
+					value = next
+				}
+			}
+		}
+		levels[name] = value
+		return value
+	}
+	for _, name := range members {
+		level(name)
+	}
+	return levels
+}
+
+// rootCauses 근본 원인 후보 (영향받은 하류 구성 요소가 많은 순, 같으면 먼저 실패한 순)
+func (dc *DependencyCorrelator) rootCauses(members []string, levels map[string]int) []string {
+	var roots []string
+	for _, name := range members {
+		if levels[name] == 0 {
+			roots = append(roots, name)
+		}
+	}
+	impact := make(map[string]int, len(roots))
+	for _, name := range members {
+		for root := range dc.upstream(name, levels) {
+			impact[root]++
+		}
+	}
+	sort.SliceStable(roots, func(i, j int) bool {
+		if impact[roots[i]] != impact[roots[j]] {
+			return impact[roots[i]] > impact[roots[j]]
+		}
+		return dc.failures[roots[i]].First.Before(dc.failures[roots[j]].First)
+	})
+	return roots
+}
+
+// upstream name 이 (직간접으로) 의존하는 장애 구성 요소 중 근본 원인 후보
+func (dc *DependencyCorrelator) upstream(name string, levels map[string]int) map[string]bool {
+	roots := make(map[string]bool)
+	var walk func(name string)
+	walk = func(name string) {
+		for _, dependency := range dc.byName[name].dependsOn {
+			if _, failing := levels[dependency]; !failing {
+				continue
+			}
+			if levels[dependency] == 0 {
+				roots[dependency] = true
+			}
+			walk(dependency)
+		}
+	}
+	walk(name)
+	return roots
+}
+
+// incidentAlert 근본 원인 추정 인시던트 알림
+func (dc *DependencyCorrelator) incidentAlert(members []string, levels map[string]int, roots []string) Alert {
+	root := dc.failures[roots[0]]
+
+	// 하류 → 상류 순서 (web → db → disk)
+	chain := append([]string(nil), members...)
+	sort.SliceStable(chain, func(i, j int) bool { return levels[chain[i]] > levels[chain[j]] })
+
+	severity := AlertSeverityWarning
+	var lines []string
+	for _, name := range chain {
+		failure := dc.failures[name]
+		if failure.Severity == AlertSeverityCritical {
+			severity = AlertSeverityCritical
+		}
+		line := fmt.Sprintf("• %s (%s) — %d건, 마지막 %s: %s", name, failure.Alert.Host, failure.Count,
+			failure.Last.Format("15:04:05"), failure.Alert.Title)
+		var dependencies []string
+		for _, dependency := range dc.byName[name].dependsOn {
+			if _, failing := levels[dependency]; failing {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+		if len(dependencies) > 0 {
+			line += fmt.Sprintf(" ← %s 장애의 영향", strings.Join(dependencies, ", "))
+		}
+		lines = append(lines, line)
+	}
+
+	fields := []AlertField{
+		{Label: "근본 원인 추정", Value: roots[0], Short: true},
+		{Label: "호스트", Value: root.Alert.Host, Short: true},
+		{Label: "첫 장애", Value: root.First.Format("2006-01-02 15:04:05"), Short: true},
+		{Label: "장애 구성 요소", Value: fmt.Sprintf("%d개", len(members)), Short: true},
+		{Label: "원인 알림", Value: root.Alert.Title},
+	}
+	if len(roots) > 1 {
+		fields = append(fields, AlertField{Label: "다른 후보", Value: strings.Join(roots[1:], ", ")})
+	}
+
+	return Alert{
+		Type:     AlertTypeIncident,
+		Severity: severity,
+		Title:    fmt.Sprintf("[%s INCIDENT] 근본 원인 추정: %s (%s)", AppName, roots[0], strings.Join(chain, " → ")),
+		Headline: fmt.Sprintf("🧭 의존 관계로 이어진 %d개 구성 요소에서 장애가 발생했습니다. 가장 상류에서 실패한 %s 이(가) 근본 원인일 가능성이 높습니다", len(members), roots[0]),
+		Sections: []AlertSection{
+			{Fields: fields, Summary: true},
+			{Title: "⛓️ 장애 체인", Text: strings.Join(lines, "\n")},
+		},
+		Host:   root.Alert.Host,
+		Thread: alertThreadKey(AlertTypeIncident, roots[0]),
+		Fields: map[string]string{
+			"root_cause": roots[0],
+			"candidates": strings.Join(roots, ","),
+			"components": strings.Join(members, ","),
+			"chain":      strings.Join(chain, " → "),
+			"window":     dc.window.String(),
+		},
+	}
+}
+
+// equalServiceDependencies 두 의존 관계 설정이 같은지 확인 (설정 재로드)
+func equalServiceDependencies(a, b ServiceDependencyConfig) bool {
+	return reflect.DeepEqual(a, b)
+}
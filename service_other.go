@@ -0,0 +1,43 @@
+//go:build !windows
+
+/*
+Windows Service Stubs
+=====================
+
+# Windows 이외 플랫폼용 Windows 서비스 함수 대체 구현
+
+서비스 관리 명령어는 runtime.GOOS 로 분기하므로 호출되지 않지만,
+모든 플랫폼에서 main.go 가 컴파일되도록 같은 이름의 함수를 제공합니다.
+*/
+package main
+
+import (
+	"fmt" // 형식화된 I/O
+	"os"  // OS 인터페이스
+)
+
+// isWindowsService Windows 이외 플랫폼에서는 항상 false
+func isWindowsService() bool {
+	return false
+}
+
+// setupWindowsServiceLogging Windows 이외 플랫폼에서는 아무 작업도 하지 않음
+func setupWindowsServiceLogging() {}
+
+// runWindowsService Windows 이외 플랫폼에서는 지원하지 않음
+func runWindowsService(monitor *SyslogMonitor) error {
+	return fmt.Errorf("Windows service mode is only supported on Windows")
+}
+
+// windowsServiceUnsupported 지원하지 않는 플랫폼 안내 후 종료
+func windowsServiceUnsupported() {
+	fmt.Println("❌ Windows service management is only supported on Windows")
+	os.Exit(1)
+}
+
+// 서비스 관리 명령어 (Windows 전용)
+func installWindowsService()    { windowsServiceUnsupported() }
+func removeWindowsService()     { windowsServiceUnsupported() }
+func startWindowsService()      { windowsServiceUnsupported() }
+func stopWindowsService()       { windowsServiceUnsupported() }
+func showWindowsServiceStatus() { windowsServiceUnsupported() }
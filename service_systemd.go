@@ -0,0 +1,358 @@
+/*
+Systemd Service Module
+======================
+
+Linux systemd 서비스 관리 (-install-service, -start-service, -stop-service, -status-service, -remove-service)
+
+주요 기능:
+- 현재 실행 파일 경로로 systemd 유닛 파일 생성 및 설치 (daemon-reload, enable)
+- root 로 실행하면 시스템 유닛 (/etc/systemd/system), 아니면 사용자 유닛 (~/.config/systemd/user, systemctl --user)
+- -install-service 와 함께 준 옵션을 ExecStart 에 사용 (없으면 LaunchAgent plist 와 같은 기본 옵션)
+- -install-service -config=<path> 는 ExecStart 에 설정 파일만 지정 (옵션은 설정 파일 "options", config_mode.go)
+- systemctl reload 는 SIGHUP 으로 설정 파일 재로드
+- 로그는 journald (journalctl -u syslog-monitor)
+
+macOS 에서는 기존 LaunchAgent (launchctl) 명령을 그대로 사용
+*/
+package main
+
+import (
+	"fmt"           // 형식화된 I/O
+	"os"            // 운영체제 인터페이스
+	"os/exec"       // systemctl 실행
+	"os/user"       // 서비스 실행 사용자
+	"path/filepath" // 유닛 파일 경로
+	"strings"       // 문자열 처리
+)
+
+// systemdUnitName systemd 유닛 이름
+const systemdUnitName = "syslog-monitor.service"
+
+// systemdDefaultArgs 옵션 없이 설치할 때의 기본 실행 옵션 (LaunchAgent plist 와 동일)
+var systemdDefaultArgs = []string{"-system-monitor", "-ai-analysis", "-login-watch", "-periodic-report", "-report-interval=60"}
+
+// serviceManagementFlags ExecStart 에 넣지 않을 서비스 관리 플래그
+var serviceManagementFlags = map[string]bool{
+	"install-service": true,
+	"remove-service":  true,
+	"start-service":   true,
+	"stop-service":    true,
+	"status-service":  true,
+}
+
+// systemdScope 시스템 유닛 또는 사용자 유닛
+type systemdScope struct {
+	system   bool
+	unitFile string
+}
+
+// systemctl 범위에 맞는 systemctl 명령 생성 (사용자 유닛은 --user)
+func (s systemdScope) systemctl(args ...string) *exec.Cmd {
+	if !s.system {
+		args = append([]string{"--user"}, args...)
+	}
+	return exec.Command("systemctl", args...)
+}
+
+// command 사용자에게 안내할 명령 문자열
+func (s systemdScope) command(tool string, args ...string) string {
+	parts := []string{tool}
+	if s.system {
+		parts = append([]string{"sudo"}, parts...)
+	} else {
+		parts = append(parts, "--user")
+	}
+	return strings.Join(append(parts, args...), " ")
+}
+
+// installScope 설치할 범위 (root 면 시스템 유닛)
+func installScope() (systemdScope, error) {
+	if os.Geteuid() == 0 {
+		return systemdScope{system: true, unitFile: filepath.Join("/etc/systemd/system", systemdUnitName)}, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return systemdScope{}, fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return systemdScope{unitFile: filepath.Join(homeDir, ".config", "systemd", "user", systemdUnitName)}, nil
+}
+
+// installedScope 설치된 유닛의 범위 (시스템 유닛 우선, 없으면 false)
+func installedScope() (systemdScope, bool) {
+	system := systemdScope{system: true, unitFile: filepath.Join("/etc/systemd/system", systemdUnitName)}
+	if _, err := os.Stat(system.unitFile); err == nil {
+		return system, true
+	}
+	scope, err := installScope()
+	if err != nil || scope.system {
+		return scope, false
+	}
+	if _, err := os.Stat(scope.unitFile); err != nil {
+		return scope, false
+	}
+	return scope, true
+}
+
+// serviceArgs 명령줄 인수에서 서비스 관리 플래그를 뺀 실행 옵션
+// -config 경로는 절대 경로로 바꿔 서비스 작업 디렉토리와 관계없이 같은 설정 파일을 읽도록 함
+func serviceArgs(args []string) []string {
+	var result []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name := strings.TrimLeft(arg, "-")
+		value, hasValue := "", false
+		if index := strings.Index(name, "="); index >= 0 {
+			name, value, hasValue = name[:index], name[index+1:], true
+		}
+		if strings.HasPrefix(arg, "-") && serviceManagementFlags[name] {
+			continue
+		}
+		if strings.HasPrefix(arg, "-") && name == ConfigFlagName {
+			if !hasValue && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			result = append(result, "-"+ConfigFlagName+"="+absServicePath(value))
+			continue
+		}
+		result = append(result, arg)
+	}
+	return result
+}
+
+// absServicePath 서비스 인수에 넣을 절대 경로 (~/ 확장, 변환할 수 없으면 그대로)
+func absServicePath(path string) string {
+	if abs, err := filepath.Abs(expandHomePath(path)); err == nil {
+		return abs
+	}
+	return path
+}
+
+// systemdQuote ExecStart 인수 인용 (공백/따옴표는 큰따옴표, % 와 $ 는 systemd 확장 방지)
+func systemdQuote(arg string) string {
+	arg = strings.ReplaceAll(arg, "%", "%%")
+	arg = strings.ReplaceAll(arg, "$", "$$")
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'\\;") {
+		return arg
+	}
+	arg = strings.ReplaceAll(arg, `\`, `\\`)
+	arg = strings.ReplaceAll(arg, `"`, `\"`)
+	return `"` + arg + `"`
+}
+
+// buildSystemdUnit systemd 유닛 파일 내용 생성
+func buildSystemdUnit(executable, workDir, username string, args []string, system bool) string {
+	execStart := []string{systemdQuote(executable)}
+	for _, arg := range args {
+		execStart = append(execStart, systemdQuote(arg))
+	}
+
+	var unit strings.Builder
+	unit.WriteString("[Unit]\n")
+	fmt.Fprintf(&unit, "Description=%s\n", AppName)
+	unit.WriteString("After=network-online.target\n")
+	unit.WriteString("Wants=network-online.target\n")
+	unit.WriteString("\n[Service]\n")
+	unit.WriteString("Type=simple\n")
+	if system && username != "" {
+		// User 를 지정해야 HOME 이 설정되어 ~/.syslog-monitor 설정을 찾음
+		fmt.Fprintf(&unit, "User=%s\n", username)
+	}
+	fmt.Fprintf(&unit, "WorkingDirectory=%s\n", strings.ReplaceAll(workDir, "%", "%%"))
+	fmt.Fprintf(&unit, "ExecStart=%s\n", strings.Join(execStart, " "))
+	unit.WriteString("ExecReload=/bin/kill -HUP $MAINPID\n")
+	unit.WriteString("Restart=on-failure\n")
+	unit.WriteString("RestartSec=5\n")
+	unit.WriteString("\n[Install]\n")
+	if system {
+		unit.WriteString("WantedBy=multi-user.target\n")
+	} else {
+		unit.WriteString("WantedBy=default.target\n")
+	}
+	return unit.String()
+}
+
+// runSystemctl systemctl 실행 후 실패하면 출력과 함께 오류 반환
+func runSystemctl(scope systemdScope, args ...string) error {
+	if output, err := scope.systemctl(args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("systemctl %s failed: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// installSystemdService systemd 유닛 파일 생성, 설치, 활성화
+func installSystemdService() {
+	fmt.Println("📦 Installing systemd service...")
+
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		fmt.Println("❌ systemctl not found: this system does not use systemd")
+		os.Exit(1)
+	}
+
+	scope, err := installScope()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	executable, err := os.Executable()
+	if err == nil {
+		executable, err = filepath.EvalSymlinks(executable)
+	}
+	if err != nil {
+		fmt.Printf("❌ Failed to find executable path: %v\n", err)
+		os.Exit(1)
+	}
+	workDir, err := os.Getwd()
+	if err != nil {
+		fmt.Printf("❌ Failed to get working directory: %v\n", err)
+		os.Exit(1)
+	}
+	username := ""
+	if current, err := user.Current(); err == nil {
+		username = current.Username
+	}
+
+	args := serviceArgs(os.Args[1:])
+	if len(args) == 0 {
+		args = systemdDefaultArgs
+	}
+
+	if err := os.MkdirAll(filepath.Dir(scope.unitFile), 0755); err != nil {
+		fmt.Printf("❌ Failed to create unit directory: %v\n", err)
+		os.Exit(1)
+	}
+	unit := buildSystemdUnit(executable, workDir, username, args, scope.system)
+	if err := os.WriteFile(scope.unitFile, []byte(unit), 0644); err != nil {
+		fmt.Printf("❌ Failed to write unit file: %v\n", err)
+		if os.IsPermission(err) {
+			fmt.Println("💡 Run with sudo to install a system-wide service")
+		}
+		os.Exit(1)
+	}
+
+	for _, command := range [][]string{{"daemon-reload"}, {"enable", systemdUnitName}} {
+		if err := runSystemctl(scope, command...); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Printf("✅ Service installed successfully\n")
+	fmt.Printf("📄 Unit file: %s\n", scope.unitFile)
+	fmt.Printf("⚙️  Command:   %s %s\n", executable, strings.Join(args, " "))
+	if !scope.system {
+		fmt.Println("💡 User services stop at logout unless lingering is enabled: loginctl enable-linger")
+	}
+	fmt.Println()
+	fmt.Println("🔧 Next steps:")
+	fmt.Printf("   Start service:  syslog-monitor -start-service\n")
+	fmt.Printf("   Check status:   syslog-monitor -status-service\n")
+	fmt.Printf("   View logs:      %s\n", scope.command("journalctl", "-u", "syslog-monitor", "-f"))
+}
+
+// removeSystemdService systemd 서비스 중지, 비활성화, 유닛 파일 제거
+func removeSystemdService() {
+	fmt.Println("🗑️  Removing systemd service...")
+
+	scope, installed := installedScope()
+	if !installed {
+		fmt.Println("⚠️  Service was not installed or already removed")
+		return
+	}
+
+	// 중지/비활성화 실패는 경고만 하고 계속 진행
+	for _, command := range [][]string{{"stop", systemdUnitName}, {"disable", systemdUnitName}} {
+		if err := runSystemctl(scope, command...); err != nil {
+			fmt.Printf("⚠️  Warning: %v\n", err)
+		}
+	}
+
+	if err := os.Remove(scope.unitFile); err != nil {
+		fmt.Printf("❌ Failed to remove unit file: %v\n", err)
+		if os.IsPermission(err) {
+			fmt.Printf("💡 Try: sudo syslog-monitor -remove-service\n")
+		}
+		os.Exit(1)
+	}
+	if err := runSystemctl(scope, "daemon-reload"); err != nil {
+		fmt.Printf("⚠️  Warning: %v\n", err)
+	}
+
+	fmt.Printf("✅ Service removed successfully (%s)\n", scope.unitFile)
+}
+
+// startSystemdService systemd 서비스 시작
+func startSystemdService() {
+	fmt.Println("🚀 Starting systemd service...")
+
+	scope, installed := installedScope()
+	if !installed {
+		fmt.Println("❌ Service is not installed. Run with -install-service first.")
+		os.Exit(1)
+	}
+	if err := runSystemctl(scope, "start", systemdUnitName); err != nil {
+		fmt.Printf("❌ Failed to start service: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("✅ Service started successfully")
+	fmt.Printf("📋 View status: syslog-monitor -status-service\n")
+	fmt.Printf("📄 View logs:   %s\n", scope.command("journalctl", "-u", "syslog-monitor", "-f"))
+}
+
+// stopSystemdService systemd 서비스 중지
+func stopSystemdService() {
+	fmt.Println("⏹️  Stopping systemd service...")
+
+	scope, installed := installedScope()
+	if !installed {
+		fmt.Println("⚠️  Service is not installed")
+		return
+	}
+	if err := scope.systemctl("is-active", "--quiet", systemdUnitName).Run(); err != nil {
+		fmt.Println("⚠️  Service is not running")
+		return
+	}
+	if err := runSystemctl(scope, "stop", systemdUnitName); err != nil {
+		fmt.Printf("❌ Failed to stop service: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("✅ Service stopped successfully")
+}
+
+// showSystemdServiceStatus systemd 서비스 상태 표시
+func showSystemdServiceStatus() {
+	fmt.Println("📊 Service Status")
+	fmt.Println("=================")
+
+	scope, installed := installedScope()
+	if !installed {
+		fmt.Println("❌ Service is not installed")
+		fmt.Println("💡 Install with: syslog-monitor -install-service (sudo for a system-wide service)")
+		return
+	}
+
+	fmt.Println("✅ Service is installed")
+	fmt.Printf("📄 Unit file: %s\n", scope.unitFile)
+
+	if err := scope.systemctl("is-active", "--quiet", systemdUnitName).Run(); err != nil {
+		fmt.Println("⏹️  Service is not running")
+		fmt.Println("💡 Start with: syslog-monitor -start-service")
+	} else {
+		fmt.Println("🟢 Service is running")
+	}
+
+	// systemctl status 는 서비스가 멈춰 있으면 0 이 아닌 코드로 끝나므로 출력만 표시
+	output, _ := scope.systemctl("status", "--no-pager", "--lines=10", systemdUnitName).CombinedOutput()
+	if len(output) > 0 {
+		fmt.Printf("Details:\n%s\n", strings.TrimRight(string(output), "\n"))
+	}
+
+	fmt.Println("\n🔧 Commands:")
+	fmt.Println("  Start:   syslog-monitor -start-service")
+	fmt.Println("  Stop:    syslog-monitor -stop-service")
+	fmt.Println("  Remove:  syslog-monitor -remove-service")
+	fmt.Printf("  Reload:  %s\n", scope.command("systemctl", "reload", "syslog-monitor"))
+	fmt.Printf("  Logs:    %s\n", scope.command("journalctl", "-u", "syslog-monitor", "-f"))
+}
@@ -0,0 +1,310 @@
+//go:build windows
+
+/*
+Windows Service Module
+======================
+
+# Windows 서비스 관리자(SCM) 연동
+
+-install-service 등 서비스 관리 명령어를 Windows 서비스로 처리하고,
+서비스 관리자가 실행한 경우 중지/종료 요청을 받아 모니터를 정상 종료합니다.
+
+주요 기능:
+- 서비스 등록 (자동 시작, 실패 시 5초 후 재시작)
+- 서비스 제거, 시작, 중지, 상태 조회
+- 서비스 실행 시 출력을 %ProgramData%\syslog-monitor\syslog-monitor.log 로 리다이렉션
+- 중지/종료 요청 시 모니터의 context 를 취소해 정상 종료 (전송 중인 알림 대기, 출력 정리) 후 SERVICE_STOPPED 보고
+*/
+package main
+
+import (
+	"context"       // 중지 요청 전달
+	"fmt"           // 형식화된 I/O
+	"os"            // OS 인터페이스
+	"path/filepath" // 파일 경로 처리
+	"strings"       // 문자열 처리
+	"time"          // 시간 처리
+
+	"golang.org/x/sys/windows/svc"     // 서비스 제어 처리
+	"golang.org/x/sys/windows/svc/mgr" // 서비스 관리자 연결
+)
+
+// windowsServiceName 서비스 관리자에 등록되는 서비스 이름
+const windowsServiceName = "SyslogMonitor"
+
+// windowsServiceStopTimeout 중지 요청 후 서비스 종료를 기다리는 최대 시간
+const windowsServiceStopTimeout = 15 * time.Second
+
+// windowsServiceDefaultArgs 옵션 없이 설치할 때의 기본 실행 옵션 (syslog 파일 대신 이벤트 로그 입력)
+var windowsServiceDefaultArgs = []string{"-eventlog", "-system-monitor", "-ai-analysis", "-periodic-report", "-report-interval=60"}
+
+// windowsServiceLogFile 서비스 실행 시 출력이 기록되는 로그 파일
+func windowsServiceLogFile() string {
+	return filepath.Join(os.Getenv("ProgramData"), "syslog-monitor", "syslog-monitor.log")
+}
+
+// isWindowsService 서비스 관리자가 실행한 프로세스인지 확인
+func isWindowsService() bool {
+	isService, err := svc.IsWindowsService()
+	return err == nil && isService
+}
+
+// setupWindowsServiceLogging 서비스 실행 시 표준 출력/에러를 로그 파일로 리다이렉션
+// 서비스에는 콘솔이 없으므로 모니터 생성 전에 호출해야 로거 출력도 파일로 기록됨
+func setupWindowsServiceLogging() {
+	logFile := windowsServiceLogFile()
+	if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
+		return
+	}
+	logOut, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return
+	}
+	os.Stdout = logOut
+	os.Stderr = logOut
+}
+
+// windowsServiceHandler 서비스 제어 요청 처리기
+type windowsServiceHandler struct {
+	monitor *SyslogMonitor
+}
+
+// Execute 모니터를 시작하고 중지/종료 요청 시 정상 종료
+func (h *windowsServiceHandler) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- h.monitor.Start(ctx)
+	}()
+
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+
+	for {
+		select {
+		case err := <-errs:
+			// SERVICE_STOPPED 를 보고하지 않고 종료해야 서비스 관리자가 실패로 보고 재시작
+			h.monitor.logger.Errorf("Monitor stopped unexpectedly: %v", err)
+			os.Exit(1)
+
+		case request := <-requests:
+			switch request.Cmd {
+			case svc.Interrogate:
+				status <- request.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				h.monitor.logger.Info("Shutting down syslog monitor (service stop requested)...")
+
+				cancel()
+				select {
+				case <-errs:
+				case <-time.After(windowsServiceStopTimeout):
+					h.monitor.logger.Errorf("Shutdown did not finish within %v", windowsServiceStopTimeout)
+				}
+				return false, 0
+			}
+		}
+	}
+}
+
+// runWindowsService 서비스 관리자의 제어를 받으며 모니터 실행
+func runWindowsService(monitor *SyslogMonitor) error {
+	if err := svc.Run(windowsServiceName, &windowsServiceHandler{monitor: monitor}); err != nil {
+		return fmt.Errorf("failed to run as Windows service: %v", err)
+	}
+	return nil
+}
+
+// connectServiceManager 서비스 관리자 연결 (실패 시 관리자 권한 안내 후 종료)
+func connectServiceManager() *mgr.Mgr {
+	manager, err := mgr.Connect()
+	if err != nil {
+		fmt.Printf("❌ Failed to connect to the service manager: %v\n", err)
+		fmt.Println("💡 Run this command from an Administrator command prompt")
+		os.Exit(1)
+	}
+	return manager
+}
+
+// installWindowsService 현재 실행 파일을 자동 시작 서비스로 등록
+// 서비스 관리 플래그를 제외한 나머지 옵션이 서비스 실행 옵션이 됨
+func installWindowsService() {
+	fmt.Println("📦 Installing Windows service...")
+
+	executable, err := os.Executable()
+	if err != nil {
+		fmt.Printf("❌ Failed to find executable path: %v\n", err)
+		os.Exit(1)
+	}
+	args := serviceArgs(os.Args[1:])
+	if len(args) == 0 {
+		args = windowsServiceDefaultArgs
+	}
+
+	manager := connectServiceManager()
+	defer manager.Disconnect()
+
+	if existing, err := manager.OpenService(windowsServiceName); err == nil {
+		existing.Close()
+		fmt.Printf("⚠️  Service %s is already installed\n", windowsServiceName)
+		fmt.Println("💡 Run -remove-service first to reinstall with new options")
+		os.Exit(1)
+	}
+
+	service, err := manager.CreateService(windowsServiceName, executable, mgr.Config{
+		DisplayName: AppName,
+		Description: "Real-time log monitoring with AI anomaly detection and system alerts",
+		StartType:   mgr.StartAutomatic,
+	}, args...)
+	if err != nil {
+		fmt.Printf("❌ Failed to create service: %v\n", err)
+		os.Exit(1)
+	}
+	defer service.Close()
+
+	// 비정상 종료 시 5초 후 재시작 (하루 동안 실패가 없으면 카운터 초기화)
+	recovery := []mgr.RecoveryAction{{Type: mgr.ServiceRestart, Delay: 5 * time.Second}}
+	if err := service.SetRecoveryActions(recovery, uint32((24 * time.Hour).Seconds())); err != nil {
+		fmt.Printf("⚠️  Failed to set restart-on-failure: %v\n", err)
+	}
+
+	fmt.Printf("✅ Service installed: %s\n", windowsServiceName)
+	fmt.Printf("   Command: %s %s\n", executable, strings.Join(args, " "))
+	fmt.Printf("   Log file: %s\n", windowsServiceLogFile())
+	fmt.Println("💡 Start it with: syslog-monitor.exe -start-service")
+}
+
+// removeWindowsService 서비스 중지 후 등록 해제
+func removeWindowsService() {
+	fmt.Println("🗑️  Removing Windows service...")
+
+	manager := connectServiceManager()
+	defer manager.Disconnect()
+
+	service, err := manager.OpenService(windowsServiceName)
+	if err != nil {
+		fmt.Printf("⚠️  Service %s is not installed\n", windowsServiceName)
+		return
+	}
+	defer service.Close()
+
+	if status, err := service.Query(); err == nil && status.State != svc.Stopped {
+		if err := stopWindowsServiceAndWait(service); err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+		}
+	}
+	if err := service.Delete(); err != nil {
+		fmt.Printf("❌ Failed to remove service: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Service removed: %s\n", windowsServiceName)
+}
+
+// startWindowsService 등록된 서비스 시작
+func startWindowsService() {
+	manager := connectServiceManager()
+	defer manager.Disconnect()
+
+	service, err := manager.OpenService(windowsServiceName)
+	if err != nil {
+		fmt.Printf("❌ Service %s is not installed (run -install-service first)\n", windowsServiceName)
+		os.Exit(1)
+	}
+	defer service.Close()
+
+	if err := service.Start(); err != nil {
+		fmt.Printf("❌ Failed to start service: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Service started: %s\n", windowsServiceName)
+}
+
+// stopWindowsService 실행 중인 서비스 중지
+func stopWindowsService() {
+	manager := connectServiceManager()
+	defer manager.Disconnect()
+
+	service, err := manager.OpenService(windowsServiceName)
+	if err != nil {
+		fmt.Printf("⚠️  Service %s is not installed\n", windowsServiceName)
+		return
+	}
+	defer service.Close()
+
+	if err := stopWindowsServiceAndWait(service); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Service stopped: %s\n", windowsServiceName)
+}
+
+// stopWindowsServiceAndWait 중지 요청 후 SERVICE_STOPPED 상태가 될 때까지 대기
+func stopWindowsServiceAndWait(service *mgr.Service) error {
+	status, err := service.Control(svc.Stop)
+	if err != nil {
+		return fmt.Errorf("failed to stop service: %v", err)
+	}
+	deadline := time.Now().Add(windowsServiceStopTimeout)
+	for status.State != svc.Stopped {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("service did not stop within %v", windowsServiceStopTimeout)
+		}
+		time.Sleep(300 * time.Millisecond)
+		if status, err = service.Query(); err != nil {
+			return fmt.Errorf("failed to query service status: %v", err)
+		}
+	}
+	return nil
+}
+
+// showWindowsServiceStatus 서비스 등록 여부, 상태, 실행 옵션 표시
+func showWindowsServiceStatus() {
+	fmt.Println("📊 Syslog Monitor Service Status")
+	fmt.Println("================================")
+
+	manager := connectServiceManager()
+	defer manager.Disconnect()
+
+	service, err := manager.OpenService(windowsServiceName)
+	if err != nil {
+		fmt.Printf("❌ Service %s is not installed\n", windowsServiceName)
+		fmt.Println("💡 Install it with: syslog-monitor.exe -install-service")
+		return
+	}
+	defer service.Close()
+
+	status, err := service.Query()
+	if err != nil {
+		fmt.Printf("❌ Failed to query service status: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Service: %s\n", windowsServiceName)
+	fmt.Printf("State:   %s\n", windowsServiceStateName(status.State))
+	if status.ProcessId != 0 {
+		fmt.Printf("PID:     %d\n", status.ProcessId)
+	}
+	if config, err := service.Config(); err == nil {
+		fmt.Printf("Command: %s\n", config.BinaryPathName)
+	}
+	fmt.Printf("Log:     %s\n", windowsServiceLogFile())
+}
+
+// windowsServiceStateName 서비스 상태 코드를 표시용 문자열로 변환
+func windowsServiceStateName(state svc.State) string {
+	switch state {
+	case svc.Running:
+		return "✅ running"
+	case svc.Stopped:
+		return "⏹️  stopped"
+	case svc.StartPending:
+		return "⏳ starting"
+	case svc.StopPending:
+		return "⏳ stopping"
+	case svc.Paused:
+		return "⏸️  paused"
+	default:
+		return fmt.Sprintf("unknown (%d)", state)
+	}
+}
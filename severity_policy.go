@@ -0,0 +1,184 @@
+/*
+Severity Policy Module
+======================
+
+로그 줄의 레벨과 알림 동작을 정하는 심각도 정책 (설정 파일 severity_policy)
+
+예전에는 "fail"/"critical" 이 들어간 줄을 logger.Fatal 로 기록해 알림을 보내기도 전에 모니터가 종료되었습니다.
+이제는 정책이 레벨(INFO/WARNING/ERROR/CRITICAL)과 알림 동작(none/info/warning/critical)만 정하고,
+어떤 줄도 모니터를 종료시키지 않습니다 (로거의 종료 함수도 막음).
+
+주요 기능:
+  - 규칙: 정규식(pattern, 줄 전체에서 검색) + 서비스 제한(services, "*" 접미사는 접두사 일치) → level, alert
+  - 규칙은 순서대로 확인하고 처음 일치한 규칙을 적용
+  - 일치하는 규칙이 없으면 내장 키워드 판정 (error/err → ERROR, warn → WARNING, fail/critical → CRITICAL, 나머지 INFO)
+    disable_builtin 이면 INFO
+  - alert 를 비우면 레벨 기본값: ERROR → warning, CRITICAL → critical, 나머지 알림 없음
+  - 정한 레벨은 출력 로그, 구조화 출력, syslog 전달, 대시보드, 학습용 표본에 똑같이 쓰임
+  - 설정 재로드 시 재시작 없이 적용 (잘못된 규칙이면 기존 정책 유지)
+
+예:
+
+	{"name": "pg-fatal", "services": ["postgres*"], "pattern": "FATAL:", "level": "CRITICAL"}
+	{"name": "cron-noise", "services": ["cron"], "pattern": "(?i)error", "level": "INFO"}
+	{"name": "disk-warn", "pattern": "(?i)no space left", "level": "WARNING", "alert": "critical"}
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"regexp"  // 규칙 정규식
+	"strings" // 문자열 처리
+	"sync"    // 동시성 제어
+)
+
+// 심각도 정책 알림 동작
+const (
+	SeverityAlertNone = "none" // 알림 보내지 않음
+	severityBuiltin   = "builtin"
+)
+
+// SeverityPolicyConfig 심각도 정책 설정 (설정 파일 severity_policy)
+type SeverityPolicyConfig struct {
+	Rules          []SeverityRule `json:"rules"`           // 순서대로 확인, 처음 일치한 규칙 적용
+	DisableBuiltin bool           `json:"disable_builtin"` // 규칙에 맞지 않는 줄을 키워드로 판정하지 않고 INFO 로 처리
+}
+
+// SeverityRule 심각도 규칙
+type SeverityRule struct {
+	Name     string   `json:"name"`     // 규칙 이름 (알림 필드, 오류 메시지 표시용)
+	Pattern  string   `json:"pattern"`  // 줄 전체에서 검색할 정규식 (대소문자 무시는 (?i))
+	Services []string `json:"services"` // 적용할 서비스 (비어 있으면 모든 로그, "*" 접미사는 접두사 일치)
+	Level    string   `json:"level"`    // INFO, WARNING, ERROR, CRITICAL
+	Alert    string   `json:"alert"`    // none, info, warning, critical (비어 있으면 레벨 기본값)
+}
+
+// SeverityDecision 심각도 정책 판정 결과
+type SeverityDecision struct {
+	Level string // INFO, WARNING, ERROR, CRITICAL
+	Alert string // 알림 심각도 (info, warning, critical, 알림 없으면 빈 문자열)
+	Rule  string // 일치한 규칙 이름 (내장 키워드 판정은 builtin)
+}
+
+// compiledSeverityRule 컴파일된 심각도 규칙
+type compiledSeverityRule struct {
+	name     string
+	regex    *regexp.Regexp
+	services []string // 소문자
+	level    string
+	alert    string
+}
+
+// SeverityPolicy 심각도 정책 (규칙이 없으면 내장 키워드 판정만 사용)
+type SeverityPolicy struct {
+	rules   []*compiledSeverityRule
+	builtin bool
+	mutex   sync.RWMutex
+}
+
+// NewSeverityPolicy 내장 키워드 판정만 하는 정책 생성 (SetConfig 로 규칙 추가)
+func NewSeverityPolicy() *SeverityPolicy {
+	return &SeverityPolicy{builtin: true}
+}
+
+// SetConfig 규칙 검증/컴파일 후 교체 (오류면 기존 정책 유지)
+func (sp *SeverityPolicy) SetConfig(config SeverityPolicyConfig) error {
+	rules := make([]*compiledSeverityRule, 0, len(config.Rules))
+	for i, rule := range config.Rules {
+		name := strings.TrimSpace(rule.Name)
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if strings.TrimSpace(rule.Pattern) == "" {
+			return fmt.Errorf("severity rule %s: pattern is empty", name)
+		}
+		regex, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return fmt.Errorf("severity rule %s: %v", name, err)
+		}
+		level := strings.ToUpper(strings.TrimSpace(rule.Level))
+		switch level {
+		case LogLevelInfo, LogLevelWarning, LogLevelError, LogLevelCritical:
+		case "":
+			return fmt.Errorf("severity rule %s: level is empty (INFO, WARNING, ERROR or CRITICAL)", name)
+		default:
+			return fmt.Errorf("severity rule %s: unknown level %q (INFO, WARNING, ERROR or CRITICAL)", name, rule.Level)
+		}
+		alert := strings.ToLower(strings.TrimSpace(rule.Alert))
+		switch alert {
+		case "":
+			alert = defaultSeverityAlert(level)
+		case SeverityAlertNone:
+			alert = ""
+		case AlertSeverityInfo, AlertSeverityWarning, AlertSeverityCritical:
+		default:
+			return fmt.Errorf("severity rule %s: unknown alert %q (none, info, warning or critical)", name, rule.Alert)
+		}
+
+		entry := &compiledSeverityRule{name: name, regex: regex, level: level, alert: alert}
+		for _, service := range rule.Services {
+			if service = strings.ToLower(strings.TrimSpace(service)); service != "" {
+				entry.services = append(entry.services, service)
+			}
+		}
+		rules = append(rules, entry)
+	}
+
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+	sp.rules = rules
+	sp.builtin = !config.DisableBuiltin
+	return nil
+}
+
+// Len 규칙 수
+func (sp *SeverityPolicy) Len() int {
+	sp.mutex.RLock()
+	defer sp.mutex.RUnlock()
+	return len(sp.rules)
+}
+
+// Evaluate 줄의 레벨과 알림 동작 판정 (services 는 extractionServices 와 같은 서비스 이름 후보)
+func (sp *SeverityPolicy) Evaluate(line string, services []string) SeverityDecision {
+	sp.mutex.RLock()
+	rules, builtin := sp.rules, sp.builtin
+	sp.mutex.RUnlock()
+
+	for _, rule := range rules {
+		if matchesServices(rule.services, services) && rule.regex.MatchString(line) {
+			return SeverityDecision{Level: rule.level, Alert: rule.alert, Rule: rule.name}
+		}
+	}
+	if !builtin {
+		return SeverityDecision{Level: LogLevelInfo}
+	}
+	level := detectLineLevel(line)
+	return SeverityDecision{Level: level, Alert: defaultSeverityAlert(level), Rule: severityBuiltin}
+}
+
+// defaultSeverityAlert 레벨별 기본 알림 (ERROR → warning, CRITICAL → critical, 나머지 없음)
+func defaultSeverityAlert(level string) string {
+	switch level {
+	case LogLevelError:
+		return AlertSeverityWarning
+	case LogLevelCritical:
+		return AlertSeverityCritical
+	default:
+		return ""
+	}
+}
+
+// equalSeverityPolicy 두 심각도 정책 설정이 같은지 확인 (설정 재로드)
+func equalSeverityPolicy(a, b SeverityPolicyConfig) bool {
+	if a.DisableBuiltin != b.DisableBuiltin || len(a.Rules) != len(b.Rules) {
+		return false
+	}
+	for i := range a.Rules {
+		if a.Rules[i].Name != b.Rules[i].Name || a.Rules[i].Pattern != b.Rules[i].Pattern ||
+			a.Rules[i].Level != b.Rules[i].Level || a.Rules[i].Alert != b.Rules[i].Alert ||
+			strings.Join(a.Rules[i].Services, "\x00") != strings.Join(b.Rules[i].Services, "\x00") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,288 @@
+/*
+Simple YAML Module
+==================
+
+규칙 파일용 YAML 부분 집합 파서 (외부 의존성 없음)
+
+지원 범위:
+- 들여쓰기 기반 매핑 (key: value) 과 시퀀스 (- item, - key: value)
+- 스칼라: 일반 문자열, '작은따옴표' (” 이스케이프), "큰따옴표" (\ 이스케이프)
+- 흐름 시퀀스: [a, "b", 'c'] (중첩 없음)
+- 주석 (# 앞에 공백이 있거나 줄 처음), 문서 시작 표시 (---)
+
+미지원 (오류로 보고):
+- 블록 스칼라 (|, >), 흐름 매핑 ({...}), 앵커/별칭, 탭 들여쓰기
+
+값은 JSON 과 같은 형태 (map[string]interface{}, []interface{}, string, nil) 로 반환하며
+숫자/불리언도 문자열로 남기므로 호출자가 변환
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"strconv" // 큰따옴표 문자열 해석
+	"strings" // 문자열 처리
+)
+
+// yamlLine 주석/빈 줄을 제거한 한 줄
+type yamlLine struct {
+	indent int
+	text   string
+	number int
+}
+
+// yamlParser 들여쓰기 기반 재귀 파서
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseSimpleYAML YAML 부분 집합 문서를 파싱 (빈 문서는 nil)
+func parseSimpleYAML(data []byte) (interface{}, error) {
+	lines, err := splitYAMLLines(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	parser := &yamlParser{lines: lines}
+	value, err := parser.parseBlock(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if parser.pos < len(lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[parser.pos].number)
+	}
+	return value, nil
+}
+
+// splitYAMLLines 들여쓰기 계산, 주석/빈 줄/문서 표시 제거
+func splitYAMLLines(document string) ([]yamlLine, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(strings.ReplaceAll(document, "\r\n", "\n"), "\n") {
+		text := strings.TrimLeft(raw, " ")
+		indent := len(raw) - len(text)
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		text = strings.TrimSpace(stripYAMLComment(text))
+		if text == "" || text == "---" {
+			continue
+		}
+		lines = append(lines, yamlLine{indent: indent, text: text, number: i + 1})
+	}
+	return lines, nil
+}
+
+// stripYAMLComment 따옴표 밖의 " #" 주석 제거
+func stripYAMLComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// isYAMLSequenceItem "- " 로 시작하는 시퀀스 항목인지 확인
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLKey "key: value" 분리 (따옴표/흐름 값으로 시작하면 매핑이 아님)
+func splitYAMLKey(text string) (string, string, bool) {
+	if text == "" || strings.ContainsRune(`"'[{`, rune(text[0])) {
+		return "", "", false
+	}
+	if index := strings.Index(text, ": "); index > 0 {
+		return strings.TrimSpace(text[:index]), strings.TrimSpace(text[index+2:]), true
+	}
+	if strings.HasSuffix(text, ":") && len(text) > 1 {
+		return strings.TrimSpace(text[:len(text)-1]), "", true
+	}
+	return "", "", false
+}
+
+// parseBlock 현재 줄의 형태에 따라 시퀀스 또는 매핑 파싱
+func (p *yamlParser) parseBlock(indent int) (interface{}, error) {
+	if isYAMLSequenceItem(p.lines[p.pos].text) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+// parseSequence 같은 들여쓰기의 "- " 항목들을 파싱
+func (p *yamlParser) parseSequence(indent int) ([]interface{}, error) {
+	items := []interface{}{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent || (line.indent == indent && !isYAMLSequenceItem(line.text)) {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.number)
+		}
+
+		rest := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+		if rest == "" {
+			// "-" 다음 줄에 더 깊게 들여쓴 블록
+			p.pos++
+			var value interface{}
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				block, err := p.parseBlock(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				value = block
+			}
+			items = append(items, value)
+			continue
+		}
+
+		if _, _, ok := splitYAMLKey(rest); ok || isYAMLSequenceItem(rest) {
+			// "- key: value" 는 항목 내용 위치를 들여쓰기로 보는 매핑의 첫 줄
+			p.lines[p.pos] = yamlLine{indent: indent + len(line.text) - len(rest), text: rest, number: line.number}
+			block, err := p.parseBlock(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, block)
+			continue
+		}
+
+		value, err := parseYAMLScalar(rest, line.number)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, value)
+		p.pos++
+	}
+	return items, nil
+}
+
+// parseMapping 같은 들여쓰기의 "key: value" 항목들을 파싱
+func (p *yamlParser) parseMapping(indent int) (map[string]interface{}, error) {
+	mapping := make(map[string]interface{})
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.number)
+		}
+		key, rest, ok := splitYAMLKey(line.text)
+		if !ok {
+			if isYAMLSequenceItem(line.text) {
+				break
+			}
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", line.number)
+		}
+		if _, exists := mapping[key]; exists {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.number, key)
+		}
+		p.pos++
+
+		if rest != "" {
+			value, err := parseYAMLScalar(rest, line.number)
+			if err != nil {
+				return nil, err
+			}
+			mapping[key] = value
+			continue
+		}
+
+		// 값이 없으면 다음 줄의 블록 (시퀀스는 키와 같은 들여쓰기도 허용)
+		mapping[key] = nil
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || (next.indent == indent && isYAMLSequenceItem(next.text)) {
+				block, err := p.parseBlock(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				mapping[key] = block
+			}
+		}
+	}
+	return mapping, nil
+}
+
+// parseYAMLScalar 스칼라 또는 흐름 시퀀스 값 해석
+func parseYAMLScalar(text string, number int) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(text, "|") || strings.HasPrefix(text, ">"):
+		return nil, fmt.Errorf("line %d: block scalars (| and >) are not supported, use a quoted string", number)
+	case strings.HasPrefix(text, "{"):
+		return nil, fmt.Errorf("line %d: flow mappings ({...}) are not supported", number)
+	case strings.HasPrefix(text, "&") || strings.HasPrefix(text, "*"):
+		return nil, fmt.Errorf("line %d: anchors and aliases are not supported", number)
+	case strings.HasPrefix(text, "["):
+		if !strings.HasSuffix(text, "]") {
+			return nil, fmt.Errorf("line %d: unterminated flow sequence", number)
+		}
+		items := []interface{}{}
+		for _, part := range splitYAMLFlow(text[1 : len(text)-1]) {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			value, err := parseYAMLScalar(part, number)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	case strings.HasPrefix(text, `"`):
+		value, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid double-quoted string: %v", number, err)
+		}
+		return value, nil
+	case strings.HasPrefix(text, "'"):
+		if len(text) < 2 || !strings.HasSuffix(text, "'") {
+			return nil, fmt.Errorf("line %d: unterminated single-quoted string", number)
+		}
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	case text == "~" || text == "null":
+		return nil, nil
+	}
+	return text, nil
+}
+
+// splitYAMLFlow 흐름 시퀀스 내용을 따옴표 밖의 쉼표로 분리
+func splitYAMLFlow(text string) []string {
+	var parts []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ',':
+			parts = append(parts, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, text[start:])
+}
@@ -0,0 +1,260 @@
+/*
+Slack Actions Module
+====================
+
+Slack 알림 메시지의 확인(Acknowledge) / 1시간 끄기(Snooze 1h) 버튼 (slack.signing_secret)
+
+Slack 앱의 Interactivity Request URL 을 관리 API 의 /slack/actions 로 지정하면, 버튼을 누를 때
+Slack 이 보내는 요청을 앱 Signing Secret 으로 검증하고 이메일 링크(alert_actions.go)와 같은
+알림 끄기 목록에 적용합니다. 요청은 Bearer 토큰 대신 Slack 서명으로 검증합니다.
+
+주요 기능:
+- 버튼: warning/critical 알림 메시지 아래 "✅ Acknowledge", "🔕 Snooze 1h" (Block Kit actions 블록)
+- 요청 검증: X-Slack-Signature (v0 HMAC-SHA256) 와 X-Slack-Request-Timestamp (5분 이내, 재전송 방지)
+- 확인: 복구(info) 알림이 오거나 24시간이 지날 때까지 같은 알림(유형 + 인시던트 키/제목)을 보내지 않음
+- 끄기: 같은 알림을 1시간 동안 보내지 않음
+- 기록: 누른 사용자와 동작을 이벤트 저장소(-db-path)에 alert_action 이벤트로 저장
+- 응답: 채널에 "@사용자 님이 확인했습니다" 메시지 (response_url, https://hooks.slack.com 만 허용)
+- Signing Secret: 설정 파일 slack.signing_secret 또는 SYSLOG_SLACK_SIGNING_SECRET / 키체인 slack-signing-secret
+
+버튼은 웹훅이 Interactivity 를 켠 Slack 앱에 속해 있어야 동작하므로, Signing Secret 을 설정한 경우에만 붙입니다.
+*/
+package main
+
+import (
+	"bytes"         // 응답 메시지 본문
+	"crypto/hmac"   // 요청 서명 검증
+	"crypto/sha256" // HMAC 해시
+	"encoding/hex"  // 서명 인코딩
+	"encoding/json" // 버튼 값, 요청 페이로드
+	"errors"        // 검증 에러
+	"fmt"           // 형식화된 I/O
+	"net/http"      // 요청 헤더, response_url 전송
+	"net/url"       // 폼 본문, response_url 검사
+	"strconv"       // 요청 타임스탬프
+	"sync"          // 동시성 제어
+	"time"          // 서명 유효 시간, 끄기 시간
+)
+
+// Slack 버튼 설정 기본값
+const (
+	SlackActionPath       = "/slack/actions"       // 관리 API 의 Interactivity Request URL 엔드포인트
+	SlackActionAck        = "alert_ack"            // 확인 버튼 action_id
+	SlackActionSnooze     = "alert_snooze_1h"      // 1시간 끄기 버튼 action_id
+	SlackActionSnoozeTime = time.Hour              // 끄기 버튼의 끄기 시간
+	slackSignatureMaxAge  = 5 * time.Minute        // 요청 타임스탬프 허용 오차 (Slack 권장값)
+	slackSignatureVersion = "v0"                   // Slack 요청 서명 버전
+	slackResponseHost     = "hooks.slack.com"      // response_url 허용 호스트
+	slackActionsBlockID   = "syslog_monitor_alert" // 버튼 블록 ID
+)
+
+// SlackBlock Block Kit 블록 (알림 버튼용 actions 블록)
+type SlackBlock struct {
+	Type     string         `json:"type"` // actions
+	BlockID  string         `json:"block_id,omitempty"`
+	Elements []SlackElement `json:"elements,omitempty"`
+}
+
+// SlackElement Block Kit 버튼 요소
+type SlackElement struct {
+	Type     string    `json:"type"` // button
+	Text     SlackText `json:"text"`
+	ActionID string    `json:"action_id"`
+	Value    string    `json:"value,omitempty"` // 누르면 그대로 돌아오는 값 (최대 2000자)
+	Style    string    `json:"style,omitempty"` // primary, danger
+}
+
+// SlackText Block Kit 텍스트 객체
+type SlackText struct {
+	Type  string `json:"type"` // plain_text, mrkdwn
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// slackActionValue 버튼 값 (요청 전체를 Slack 서명으로 검증하므로 별도 서명 없음)
+type slackActionValue struct {
+	Key   string `json:"k"` // 끄기 대상 알림 키 (alertSnoozeKey)
+	Title string `json:"t"` // 알림 제목 (응답 메시지, 끈 알림 목록 표시용)
+}
+
+// SlackActionRequest 서명을 검증한 버튼 요청
+type SlackActionRequest struct {
+	Action      AlertAction
+	UserID      string // Slack 사용자 ID (응답 메시지 멘션용)
+	ResponseURL string // 채널 응답 주소
+}
+
+// SlackActions Slack 버튼 생성/요청 검증기 (Signing Secret 을 설정하기 전에는 버튼을 붙이지 않음)
+type SlackActions struct {
+	secret []byte
+	client *http.Client
+	mutex  sync.RWMutex
+}
+
+// NewSlackActions Slack 버튼 처리기 생성 (SetSecret 으로 Signing Secret 을 설정하면 사용)
+func NewSlackActions() *SlackActions {
+	return &SlackActions{client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// SetSecret Signing Secret 교체 (비어 있으면 버튼 사용 안 함)
+func (sa *SlackActions) SetSecret(secret string) {
+	sa.mutex.Lock()
+	defer sa.mutex.Unlock()
+	sa.secret = []byte(secret)
+}
+
+// Enabled 버튼 사용 여부 (Signing Secret 이 설정됨)
+func (sa *SlackActions) Enabled() bool {
+	if sa == nil {
+		return false
+	}
+	sa.mutex.RLock()
+	defer sa.mutex.RUnlock()
+	return len(sa.secret) > 0
+}
+
+// Buttons 알림 메시지에 붙일 버튼 첨부 (사용하지 않거나 info 알림이면 nil)
+func (sa *SlackActions) Buttons(alert Alert) *SlackAttachment {
+	if !sa.Enabled() || alert.Severity == AlertSeverityInfo {
+		return nil
+	}
+	value, err := json.Marshal(slackActionValue{Key: alertSnoozeKey(alert), Title: truncateRunes(alert.Title, maxAlertActionTitleLen)})
+	if err != nil {
+		return nil
+	}
+	button := func(label, actionID, style string) SlackElement {
+		return SlackElement{
+			Type:     "button",
+			Text:     SlackText{Type: "plain_text", Text: label, Emoji: true},
+			ActionID: actionID,
+			Value:    string(value),
+			Style:    style,
+		}
+	}
+	return &SlackAttachment{
+		Blocks: []SlackBlock{{
+			Type:    "actions",
+			BlockID: slackActionsBlockID,
+			Elements: []SlackElement{
+				button("✅ Acknowledge", SlackActionAck, "primary"),
+				button("🔕 Snooze 1h", SlackActionSnooze, ""),
+			},
+		}},
+	}
+}
+
+// Verify Slack 요청 서명과 타임스탬프 확인 (v0:타임스탬프:본문 의 HMAC-SHA256)
+func (sa *SlackActions) Verify(header http.Header, body []byte, now time.Time) error {
+	sa.mutex.RLock()
+	secret := sa.secret
+	sa.mutex.RUnlock()
+	if len(secret) == 0 {
+		return errors.New("slack interactivity is not configured (set slack.signing_secret)")
+	}
+
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("missing or invalid X-Slack-Request-Timestamp")
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > slackSignatureMaxAge || age < -slackSignatureMaxAge {
+		return fmt.Errorf("slack request timestamp is %s off (replayed request?)", age.Round(time.Second))
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(slackSignatureVersion + ":" + timestamp + ":"))
+	mac.Write(body)
+	expected := slackSignatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return errors.New("invalid X-Slack-Signature")
+	}
+	return nil
+}
+
+// ParseSlackAction 버튼 요청 본문(payload 폼 필드의 block_actions JSON)에서 동작 추출
+func ParseSlackAction(body []byte) (SlackActionRequest, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil || form.Get("payload") == "" {
+		return SlackActionRequest{}, errors.New("missing payload form field")
+	}
+
+	var payload struct {
+		Type string `json:"type"`
+		User struct {
+			ID       string `json:"id"`
+			Username string `json:"username"`
+			Name     string `json:"name"`
+		} `json:"user"`
+		ResponseURL string `json:"response_url"`
+		Actions     []struct {
+			ActionID string `json:"action_id"`
+			Value    string `json:"value"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		return SlackActionRequest{}, fmt.Errorf("invalid payload JSON: %v", err)
+	}
+	if payload.Type != "block_actions" || len(payload.Actions) == 0 {
+		return SlackActionRequest{}, fmt.Errorf("unsupported interaction type %q", payload.Type)
+	}
+
+	selected := payload.Actions[0]
+	var value slackActionValue
+	if err := json.Unmarshal([]byte(selected.Value), &value); err != nil || value.Key == "" {
+		return SlackActionRequest{}, errors.New("invalid button value")
+	}
+
+	action := AlertAction{Key: value.Key, Title: value.Title}
+	switch selected.ActionID {
+	case SlackActionAck:
+		action.Action, action.Duration = AlertActionAck, DefaultAlertAckTTL
+	case SlackActionSnooze:
+		action.Action, action.Duration = AlertActionSnooze, SlackActionSnoozeTime
+	default:
+		return SlackActionRequest{}, fmt.Errorf("unknown action %q", selected.ActionID)
+	}
+
+	action.By = payload.User.Username
+	if action.By == "" {
+		action.By = payload.User.Name
+	}
+	if action.By == "" {
+		action.By = payload.User.ID
+	}
+	return SlackActionRequest{Action: action, UserID: payload.User.ID, ResponseURL: payload.ResponseURL}, nil
+}
+
+// Respond 버튼을 누른 채널에 적용 결과 메시지 전송 (원래 알림 메시지는 그대로 유지)
+func (sa *SlackActions) Respond(request SlackActionRequest, until time.Time) error {
+	target, err := url.Parse(request.ResponseURL)
+	if err != nil || target.Scheme != "https" || target.Host != slackResponseHost {
+		return fmt.Errorf("refusing to post to response_url %q (only https://%s is allowed)", request.ResponseURL, slackResponseHost)
+	}
+
+	who := request.Action.By
+	if request.UserID != "" {
+		who = "<@" + request.UserID + ">"
+	}
+	text := fmt.Sprintf("🔕 %s 님이 %s 까지 이 알림을 껐습니다: %s", who, until.Format("2006-01-02 15:04"), request.Action.Title)
+	if request.Action.Action == AlertActionAck {
+		text = fmt.Sprintf("✅ %s 님이 확인했습니다. 복구 알림이 오거나 %s 이 될 때까지 보내지 않습니다: %s", who, until.Format("2006-01-02 15:04"), request.Action.Title)
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"response_type":    "in_channel",
+		"replace_original": false,
+		"text":             text,
+	})
+	if err != nil {
+		return err
+	}
+	resp, err := sa.client.Post(target.String(), "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to post Slack action response: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack action response failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
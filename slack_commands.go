@@ -0,0 +1,334 @@
+/*
+Slack Commands Module
+=====================
+
+Slack 슬래시 명령으로 실행 중인 모니터 조회/알림 끄기 (/sysmon, slack.signing_secret)
+
+Slack 앱에 /sysmon 슬래시 명령을 만들고 Request URL 을 관리 API 의 /slack/commands 로 지정하면,
+SSH 접속 없이 채팅에서 관리 API 와 같은 정보를 조회하고 알림을 끌 수 있습니다.
+요청은 확인/끄기 버튼(slack_actions.go)과 같은 앱 Signing Secret 으로 검증합니다.
+
+주요 기능:
+- /sysmon status: 버전, 가동 시간, 입력, 알림 채널, 활성 기능, 끈 알림, 마지막 알림 (GET /status)
+- /sysmon metrics: CPU, 메모리, 로드, 디스크, 네트워크 (GET /metrics/current, -system-monitor 필요)
+- /sysmon recent-logins [개수]: 최근 로그인 (이벤트 저장소 -db-path, 없으면 최근 로그인 알림, 기본 10건)
+- /sysmon silence <유형> <시간>: 한 유형의 모든 알림을 지정한 시간 동안 보내지 않음 (예: login 2h, 최대 7d)
+- /sysmon silence <유형> off: 유형 전체 끄기 해제
+- 조회 결과는 명령한 사용자에게만, silence 결과는 채널 전체에 표시
+- slack.command_users 를 지정하면 목록의 사용자(ID 또는 이름)만 명령 사용 가능
+- silence/해제는 이벤트 저장소에 alert_action 이벤트로 기록
+*/
+package main
+
+import (
+	"errors"   // 요청 파싱 에러
+	"fmt"      // 형식화된 I/O
+	"net/http" // 요청 헤더
+	"net/url"  // 폼 본문
+	"os"       // 호스트명
+	"sort"     // 기능 이름 정렬
+	"strconv"  // 개수/일 단위 시간 파싱
+	"strings"  // 문자열 처리
+	"sync"     // 동시성 제어
+	"time"     // 가동 시간, 끄기 시간
+)
+
+// Slack 명령 설정 기본값
+const (
+	SlackCommandPath          = "/slack/commands" // 관리 API 의 슬래시 명령 Request URL 엔드포인트
+	DefaultSlackCommandLogins = 10                // recent-logins 기본 조회 수
+	MaxSlackCommandLogins     = 50                // recent-logins 최대 조회 수
+)
+
+// Slack 명령 응답 표시 범위
+const (
+	SlackResponseEphemeral = "ephemeral"  // 명령한 사용자에게만
+	SlackResponseInChannel = "in_channel" // 채널 전체에
+)
+
+// SlackCommandRequest 서명을 검증한 슬래시 명령 요청
+type SlackCommandRequest struct {
+	Command  string // 슬래시 명령 이름 (예: /sysmon)
+	Text     string // 명령 뒤의 인수
+	UserID   string
+	UserName string
+}
+
+// SlackCommandResponse 슬래시 명령 응답 (mrkdwn 텍스트)
+type SlackCommandResponse struct {
+	ResponseType string `json:"response_type"` // ephemeral, in_channel
+	Text         string `json:"text"`
+}
+
+// SlackCommands 슬래시 명령 처리기 (요청 검증은 버튼과 같은 Signing Secret 사용)
+type SlackCommands struct {
+	actions *SlackActions
+	users   map[string]bool // nil 이면 모든 사용자
+	mutex   sync.RWMutex
+}
+
+// NewSlackCommands 슬래시 명령 처리기 생성 (actions 의 Signing Secret 으로 요청 검증)
+func NewSlackCommands(actions *SlackActions) *SlackCommands {
+	return &SlackCommands{actions: actions}
+}
+
+// SetUsers 명령을 쓸 수 있는 사용자 교체 (비어 있으면 워크스페이스 전체)
+func (sc *SlackCommands) SetUsers(users []string) {
+	var allowed map[string]bool
+	for _, user := range users {
+		if user = strings.TrimPrefix(strings.TrimSpace(user), "@"); user != "" {
+			if allowed == nil {
+				allowed = make(map[string]bool)
+			}
+			allowed[strings.ToLower(user)] = true
+		}
+	}
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	sc.users = allowed
+}
+
+// Enabled 슬래시 명령 사용 여부 (Signing Secret 이 설정됨)
+func (sc *SlackCommands) Enabled() bool {
+	return sc != nil && sc.actions.Enabled()
+}
+
+// Verify Slack 요청 서명과 타임스탬프 확인
+func (sc *SlackCommands) Verify(header http.Header, body []byte, now time.Time) error {
+	return sc.actions.Verify(header, body, now)
+}
+
+// Allowed 사용자가 명령을 쓸 수 있는지 확인 (ID 또는 이름, 대소문자 무시)
+func (sc *SlackCommands) Allowed(request SlackCommandRequest) bool {
+	sc.mutex.RLock()
+	defer sc.mutex.RUnlock()
+	if sc.users == nil {
+		return true
+	}
+	return sc.users[strings.ToLower(request.UserID)] || sc.users[strings.ToLower(request.UserName)]
+}
+
+// ParseSlackCommand 슬래시 명령 요청 본문(폼)에서 명령 추출
+func ParseSlackCommand(body []byte) (SlackCommandRequest, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil || form.Get("command") == "" {
+		return SlackCommandRequest{}, errors.New("missing command form field")
+	}
+	return SlackCommandRequest{
+		Command:  form.Get("command"),
+		Text:     strings.TrimSpace(form.Get("text")),
+		UserID:   form.Get("user_id"),
+		UserName: form.Get("user_name"),
+	}, nil
+}
+
+// runSlackCommand 슬래시 명령 실행 (운영자 모니터 대상)
+func (sm *SyslogMonitor) runSlackCommand(request SlackCommandRequest, now time.Time) SlackCommandResponse {
+	args := strings.Fields(request.Text)
+	if len(args) == 0 {
+		return slackCommandHelp(request.Command)
+	}
+	switch strings.ToLower(args[0]) {
+	case "status":
+		return SlackCommandResponse{ResponseType: SlackResponseEphemeral, Text: sm.slackStatusText(now)}
+	case "metrics":
+		return SlackCommandResponse{ResponseType: SlackResponseEphemeral, Text: sm.slackMetricsText()}
+	case "recent-logins", "logins":
+		limit := DefaultSlackCommandLogins
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil || parsed <= 0 {
+				return slackCommandError(fmt.Sprintf("개수는 양의 정수여야 합니다: %q", args[1]))
+			}
+			limit = parsed
+		}
+		if limit > MaxSlackCommandLogins {
+			limit = MaxSlackCommandLogins
+		}
+		return SlackCommandResponse{ResponseType: SlackResponseEphemeral, Text: sm.slackRecentLoginsText(limit)}
+	case "silence":
+		if len(args) != 3 {
+			return slackCommandError(fmt.Sprintf("사용법: `%s silence <유형> <시간|off>` (예: `%s silence login 2h`)", request.Command, request.Command))
+		}
+		return sm.slackSilence(request, strings.ToLower(args[1]), strings.ToLower(args[2]), now)
+	default:
+		return slackCommandHelp(request.Command)
+	}
+}
+
+// slackStatusText /sysmon status 응답
+func (sm *SyslogMonitor) slackStatusText(now time.Time) string {
+	host, _ := os.Hostname()
+	var enabled []string
+	for name, on := range sm.statusFeatures() {
+		if on {
+			enabled = append(enabled, name)
+		}
+	}
+	sort.Strings(enabled)
+	sinks := sm.alertDispatcher.SinkNames()
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "*🛡️ %s v%s* on `%s` (%s)\n", AppName, AppVersion, host, now.Format("2006-01-02 15:04"))
+	fmt.Fprintf(&text, "• 가동 시간: %s\n", now.Sub(sm.startedAt).Round(time.Second))
+	fmt.Fprintf(&text, "• 입력: `%s`\n", sm.statusInput())
+	fmt.Fprintf(&text, "• 알림 채널: %s\n", joinOrNone(sinks))
+	fmt.Fprintf(&text, "• 활성 기능: %s\n", joinOrNone(enabled))
+	if snoozes := sm.alertDispatcher.Snoozes().Active(now); len(snoozes) > 0 {
+		fmt.Fprintf(&text, "• 끈 알림: %d건\n", len(snoozes))
+		for _, snooze := range snoozes {
+			fmt.Fprintf(&text, "    🔕 %s (%s, %s 까지)\n", snooze.Title, snooze.Action, snooze.Until.Format("01-02 15:04"))
+		}
+	}
+	if recent := sm.alertDispatcher.Recent("", 1); len(recent) > 0 {
+		fmt.Fprintf(&text, "• 마지막 알림: %s [%s] %s\n", recent[0].Timestamp.Format("01-02 15:04:05"), strings.ToUpper(recent[0].Severity), recent[0].Title)
+	}
+	return text.String()
+}
+
+// slackMetricsText /sysmon metrics 응답
+func (sm *SyslogMonitor) slackMetricsText() string {
+	if sm.systemMonitor == nil {
+		return "⚠️ 시스템 모니터링이 꺼져 있습니다 (-system-monitor 로 시작하세요)."
+	}
+	metrics := sm.systemMonitor.GetCurrentMetrics()
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "*📊 시스템 메트릭* (%s 수집)\n", metrics.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&text, "• CPU: %.1f%% (%d코어, iowait %.1f%%)\n", metrics.CPU.UsagePercent, metrics.CPU.Cores, metrics.CPU.IOWaitPercent)
+	fmt.Fprintf(&text, "• 메모리: %.1f%% (%.0f / %.0f MB, 스왑 %.1f%%)\n", metrics.Memory.UsagePercent, metrics.Memory.UsedMB, metrics.Memory.TotalMB, metrics.Memory.SwapUsagePercent)
+	fmt.Fprintf(&text, "• 로드: %.2f / %.2f / %.2f\n", metrics.LoadAverage.Load1Min, metrics.LoadAverage.Load5Min, metrics.LoadAverage.Load15Min)
+	for _, disk := range metrics.Disk {
+		fmt.Fprintf(&text, "• 디스크 `%s`: %.1f%% (%.1f / %.1f GB)\n", disk.MountPoint, disk.UsagePercent, disk.UsedGB, disk.TotalGB)
+	}
+	if metrics.Network.RatesAvailable {
+		fmt.Fprintf(&text, "• 네트워크: 수신 %.1f KB/s, 송신 %.1f KB/s\n", metrics.Network.RecvBytesPerSec/1024, metrics.Network.SentBytesPerSec/1024)
+	}
+	if metrics.Temperature.CPUTemp > 0 {
+		fmt.Fprintf(&text, "• CPU 온도: %.1f°C\n", metrics.Temperature.CPUTemp)
+	}
+	if metrics.ProcessCount.Total > 0 {
+		fmt.Fprintf(&text, "• 프로세스: %d (좀비 %d)\n", metrics.ProcessCount.Total, metrics.ProcessCount.Zombie)
+	}
+	return text.String()
+}
+
+// slackRecentLoginsText /sysmon recent-logins 응답 (이벤트 저장소가 없으면 최근 로그인 알림)
+func (sm *SyslogMonitor) slackRecentLoginsText(limit int) string {
+	var text strings.Builder
+	if sm.eventStore != nil {
+		records, err := sm.eventStore.Query(EventFilter{Kind: EventKindLogin, Limit: limit})
+		if err != nil {
+			return fmt.Sprintf("⚠️ 로그인 기록을 조회하지 못했습니다: %v", err)
+		}
+		if len(records) == 0 {
+			return "최근 로그인 기록이 없습니다."
+		}
+		fmt.Fprintf(&text, "*🔑 최근 로그인 %d건*\n", len(records))
+		for _, record := range records {
+			fmt.Fprintf(&text, "%s `%s` %s", severityEmoji(record.Severity), record.Timestamp.Format("01-02 15:04:05"), record.Summary)
+			if record.Host != "" {
+				fmt.Fprintf(&text, " (%s)", record.Host)
+			}
+			text.WriteString("\n")
+		}
+		return text.String()
+	}
+
+	alerts := sm.alertDispatcher.Recent(AlertTypeLogin, limit)
+	if len(alerts) == 0 {
+		return "최근 로그인 알림이 없습니다 (모든 로그인 기록은 -db-path 로 이벤트 저장소를 켜세요)."
+	}
+	fmt.Fprintf(&text, "*🔑 최근 로그인 알림 %d건* (이벤트 저장소 -db-path 가 없어 알림만 표시)\n", len(alerts))
+	for _, alert := range alerts {
+		fmt.Fprintf(&text, "%s `%s` %s\n", severityEmoji(alert.Severity), alert.Timestamp.Format("01-02 15:04:05"), alert.Title)
+	}
+	return text.String()
+}
+
+// slackSilence /sysmon silence <유형> <시간|off>
+func (sm *SyslogMonitor) slackSilence(request SlackCommandRequest, alertType, value string, now time.Time) SlackCommandResponse {
+	if !containsString(AlertTypes, alertType) {
+		return slackCommandError(fmt.Sprintf("알 수 없는 알림 유형 %q (사용 가능: %s)", alertType, strings.Join(AlertTypes, ", ")))
+	}
+	who := request.UserName
+	if who == "" {
+		who = request.UserID
+	}
+	action := AlertAction{Key: alertSilenceKey(alertType), Title: alertType + " 알림 전체", By: who}
+	mention := "<@" + request.UserID + ">"
+
+	if value == "off" {
+		if !sm.alertDispatcher.Snoozes().Remove(action.Key, now) {
+			return slackCommandError(fmt.Sprintf("%s 알림은 꺼져 있지 않습니다.", alertType))
+		}
+		action.Action = AlertActionUnsilence
+		sm.logger.Infof("🔔 Alert type %s unsilenced via Slack by %s", alertType, who)
+		if sm.eventStore != nil {
+			sm.eventStore.RecordAlertAction(action, "slack", now)
+		}
+		return SlackCommandResponse{ResponseType: SlackResponseInChannel, Text: fmt.Sprintf("🔔 %s 님이 %s 알림 끄기를 해제했습니다.", mention, alertType)}
+	}
+
+	duration, err := parseSilenceDuration(value)
+	if err != nil {
+		return slackCommandError(err.Error())
+	}
+	action.Action, action.Duration = AlertActionSilence, duration
+	until := sm.alertDispatcher.Snoozes().Apply(action, now)
+	sm.logger.Infof("🔕 Alert type %s silenced via Slack by %s until %s", alertType, who, until.Format("2006-01-02 15:04"))
+	if sm.eventStore != nil {
+		sm.eventStore.RecordAlertAction(action, "slack", until)
+	}
+	return SlackCommandResponse{
+		ResponseType: SlackResponseInChannel,
+		Text: fmt.Sprintf("🔕 %s 님이 %s 동안 (%s 까지) %s 알림을 모두 껐습니다. 해제: `%s silence %s off`",
+			mention, formatSnoozeDuration(duration), until.Format("2006-01-02 15:04"), alertType, request.Command, alertType),
+	}
+}
+
+// parseSilenceDuration 끄기 시간 파싱 (30m, 2h, 1d, 최대 7일)
+func parseSilenceDuration(value string) (time.Duration, error) {
+	var duration time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		var count int
+		count, err = strconv.Atoi(days)
+		duration = time.Duration(count) * 24 * time.Hour
+	} else {
+		duration, err = time.ParseDuration(value)
+	}
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("잘못된 시간 %q (예: 30m, 2h, 1d)", value)
+	}
+	if duration > MaxAlertSnooze {
+		return 0, fmt.Errorf("최대 %dd 까지 끌 수 있습니다", int(MaxAlertSnooze/(24*time.Hour)))
+	}
+	return duration, nil
+}
+
+// slackCommandHelp 명령 목록 응답
+func slackCommandHelp(command string) SlackCommandResponse {
+	if command == "" {
+		command = "/sysmon"
+	}
+	return SlackCommandResponse{ResponseType: SlackResponseEphemeral, Text: fmt.Sprintf(`*%s 명령*
+• `+"`%[1]s status`"+` - 모니터 상태, 입력, 알림 채널, 끈 알림
+• `+"`%[1]s metrics`"+` - CPU, 메모리, 로드, 디스크, 네트워크
+• `+"`%[1]s recent-logins [개수]`"+` - 최근 로그인 (기본 10건)
+• `+"`%[1]s silence <유형> <시간>`"+` - 유형의 모든 알림 끄기 (예: login 2h, 최대 7d)
+• `+"`%[1]s silence <유형> off`"+` - 끄기 해제`, command)}
+}
+
+// slackCommandError 명령한 사용자에게만 보이는 오류 응답
+func slackCommandError(message string) SlackCommandResponse {
+	return SlackCommandResponse{ResponseType: SlackResponseEphemeral, Text: "⚠️ " + message}
+}
+
+// joinOrNone 목록을 쉼표로 연결 (비어 있으면 "없음")
+func joinOrNone(items []string) string {
+	if len(items) == 0 {
+		return "없음"
+	}
+	return strings.Join(items, ", ")
+}
@@ -6,11 +6,14 @@ Slack Incoming Webhooks을 통한 실시간 알림 서비스
 
 주요 기능:
 - Slack 채널로 실시간 알림 전송
-- 보안 이벤트별 맞춤형 메시지 포맷
+- 공통 알림 모델을 Slack 첨부 메시지로 렌더링 (alert_render.go, 채널 상세 수준 summary/full)
+- 채널/알림 유형별 봇 이름, 아이콘, 이모지/색상 지정 (slack_style.go)
 - 색상 코드를 통한 심각도 구분
 - 구조화된 필드를 통한 상세 정보 제공
 - AI 분석 결과 시각화
 - 시스템 메트릭 알림
+- AlertSink 인터페이스 구현 (AlertDispatcher 연동)
+- warning/critical 알림에 확인/1시간 끄기 버튼 (slack_actions.go, Signing Secret 설정 시)
 
 지원 알림 유형:
 - 로그인 성공/실패 (SSH, sudo, 웹)
@@ -32,14 +35,18 @@ import (
 	"encoding/json" // JSON 인코딩/디코딩
 	"fmt"           // 형식화된 I/O
 	"net/http"      // HTTP 클라이언트
-	"strings"       // 문자열 처리
+	"sort"          // 필드 정렬
+	"sync"          // 동기화 (설정 재로드)
 	"time"          // 시간 처리
 )
 
 // SlackService Slack 메시지 전송 서비스
 type SlackService struct {
-	config *SlackConfig
-	logger Logger
+	config  *SlackConfig
+	styles  SlackStyles   // 채널/알림 유형별 표시 방식
+	actions *SlackActions // 확인/끄기 버튼 (nil 이면 버튼 없음)
+	logger  Logger
+	mutex   sync.RWMutex // 설정 교체 보호 (설정 재로드)
 }
 
 // NewSlackService 새로운 Slack 서비스 생성
@@ -50,20 +57,61 @@ func NewSlackService(config *SlackConfig, logger Logger) *SlackService {
 	}
 }
 
+// currentConfig 현재 Slack 설정 반환 (재로드 시 통째로 교체)
+func (ss *SlackService) currentConfig() *SlackConfig {
+	ss.mutex.RLock()
+	defer ss.mutex.RUnlock()
+	return ss.config
+}
+
+// SetDestination 웹훅 URL과 채널 교체 (설정 재로드 시 사용, 빈 웹훅 URL은 기존 값 유지)
+func (ss *SlackService) SetDestination(webhookURL, channel string) {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+	config := *ss.config
+	if webhookURL != "" {
+		config.WebhookURL = webhookURL
+	}
+	config.Channel = channel
+	ss.config = &config
+}
+
+// SetStyles 채널/알림 유형별 표시 방식 교체 (설정 재로드 시 사용)
+func (ss *SlackService) SetStyles(styles SlackStyles) {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+	ss.styles = styles
+}
+
+// SetActions 확인/끄기 버튼 처리기 연결
+func (ss *SlackService) SetActions(actions *SlackActions) {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+	ss.actions = actions
+}
+
+// currentStyles 현재 표시 방식 반환
+func (ss *SlackService) currentStyles() SlackStyles {
+	ss.mutex.RLock()
+	defer ss.mutex.RUnlock()
+	return ss.styles
+}
+
 // SendMessage Slack 메시지 전송
 func (ss *SlackService) SendMessage(message SlackMessage) error {
-	if !ss.config.Enabled {
+	config := ss.currentConfig()
+	if !config.Enabled {
 		return nil
 	}
 
 	// 기본값 설정
 	if message.Channel == "" {
-		message.Channel = ss.config.Channel
+		message.Channel = config.Channel
 	}
 	if message.Username == "" {
 		message.Username = DefaultSlackUsername
 	}
-	if message.IconEmoji == "" {
+	if message.IconEmoji == "" && message.IconURL == "" {
 		message.IconEmoji = DefaultSlackIcon
 	}
 
@@ -74,7 +122,7 @@ func (ss *SlackService) SendMessage(message SlackMessage) error {
 	}
 
 	// HTTP 요청 생성
-	req, err := http.NewRequest("POST", ss.config.WebhookURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequest("POST", config.WebhookURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %v", err)
 	}
@@ -96,254 +144,6 @@ func (ss *SlackService) SendMessage(message SlackMessage) error {
 	return nil
 }
 
-// CreateLoginAlert 로그인 알림 메시지 생성 (시스템 리소스 정보 포함)
-func (ss *SlackService) CreateLoginAlert(loginInfo map[string]string, parsed map[string]string) SlackMessage {
-	status := loginInfo["status"]
-	var color, title, emoji string
-	var fields []SlackField
-
-	// 기본 로그인 정보 필드 생성
-	switch status {
-	case "accepted":
-		color = SlackColorGood
-		title = "✅ SSH Login Successful"
-		emoji = ":white_check_mark:"
-		fields = []SlackField{
-			{Title: "👤 User", Value: loginInfo["user"], Short: true},
-			{Title: "🌐 IP Address", Value: loginInfo["ip"], Short: true},
-			{Title: "🔑 Method", Value: loginInfo["method"], Short: true},
-			{Title: "🖥️ Host", Value: parsed["host"], Short: true},
-		}
-	case "failed":
-		color = SlackColorDanger
-		title = "❌ SSH Login Failed"
-		emoji = ":x:"
-		fields = []SlackField{
-			{Title: "👤 User", Value: loginInfo["user"], Short: true},
-			{Title: "🌐 IP Address", Value: loginInfo["ip"], Short: true},
-			{Title: "🔑 Method", Value: loginInfo["method"], Short: true},
-			{Title: "🖥️ Host", Value: parsed["host"], Short: true},
-		}
-	case "sudo":
-		color = SlackColorWarning
-		title = "⚡ Sudo Command Executed"
-		emoji = ":zap:"
-		fields = []SlackField{
-			{Title: "👤 User", Value: loginInfo["user"], Short: true},
-			{Title: "🖥️ Host", Value: parsed["host"], Short: true},
-			{Title: "⚡ Command", Value: loginInfo["command"], Short: false},
-		}
-	case "web_login":
-		color = SlackColorGood
-		title = "🌐 Web Login Detected"
-		emoji = ":globe_with_meridians:"
-		fields = []SlackField{
-			{Title: "👤 User", Value: loginInfo["user"], Short: true},
-			{Title: "🌐 IP Address", Value: loginInfo["ip"], Short: true},
-			{Title: "🖥️ Host", Value: parsed["host"], Short: true},
-		}
-	default:
-		color = "#36a64f"
-		title = "👤 User Activity"
-		emoji = ":bust_in_silhouette:"
-		fields = []SlackField{
-			{Title: "👤 User", Value: loginInfo["user"], Short: true},
-			{Title: "🖥️ Host", Value: parsed["host"], Short: true},
-			{Title: "📍 Activity", Value: loginInfo["status"], Short: true},
-		}
-	}
-
-	// 시스템 리소스 정보 추가
-	if cpu, exists := loginInfo["cpu_usage"]; exists && cpu != "" {
-		fields = append(fields, SlackField{Title: "💻 CPU Usage", Value: cpu, Short: true})
-	}
-	if memory, exists := loginInfo["memory_usage"]; exists && memory != "" {
-		fields = append(fields, SlackField{Title: "🧠 Memory Usage", Value: memory, Short: true})
-	}
-	if temp, exists := loginInfo["cpu_temp"]; exists && temp != "" {
-		fields = append(fields, SlackField{Title: "🌡️ CPU Temp", Value: temp, Short: true})
-	}
-	if load, exists := loginInfo["load_avg"]; exists && load != "" {
-		fields = append(fields, SlackField{Title: "⚖️ Load Avg", Value: load, Short: true})
-	}
-
-	// IP 위치 정보 추가
-	if country, exists := loginInfo["ip_country"]; exists && country != "" {
-		fields = append(fields, SlackField{Title: "🏴 Country", Value: country, Short: true})
-	}
-	if city, exists := loginInfo["ip_city"]; exists && city != "" {
-		fields = append(fields, SlackField{Title: "🏙️ City", Value: city, Short: true})
-	}
-	if org, exists := loginInfo["ip_org"]; exists && org != "" {
-		fields = append(fields, SlackField{Title: "🏢 Organization", Value: org, Short: false})
-	}
-	if threat, exists := loginInfo["ip_threat"]; exists && threat != "" {
-		threatEmoji := "🟢"
-		switch threat {
-		case "HIGH":
-			threatEmoji = "🔴"
-		case "MEDIUM":
-			threatEmoji = "🟡"
-		case "LOW":
-			threatEmoji = "🟢"
-		default:
-			threatEmoji = "⚪"
-		}
-		fields = append(fields, SlackField{Title: "⚠️ Threat Level", Value: threatEmoji + " " + threat, Short: true})
-	}
-
-	// 디스크 사용량 정보 추가
-	if diskUsage, exists := loginInfo["disk_usage"]; exists && diskUsage != "" {
-		fields = append(fields, SlackField{Title: "💾 Disk Usage", Value: diskUsage, Short: false})
-	}
-
-	// 타임스탬프 추가
-	if timestamp, exists := loginInfo["timestamp"]; exists && timestamp != "" {
-		fields = append(fields, SlackField{Title: "🕐 Detected At", Value: timestamp, Short: true})
-	}
-
-	attachment := SlackAttachment{
-		Color:     color,
-		Title:     title,
-		Fields:    fields,
-		Timestamp: time.Now().Unix(),
-	}
-
-	return SlackMessage{
-		Text:        fmt.Sprintf("%s *%s*", emoji, title),
-		IconEmoji:   ":robot_face:",
-		Username:    DefaultSlackUsername,
-		Attachments: []SlackAttachment{attachment},
-	}
-}
-
-// CreateAIAlert AI 분석 결과 알림 메시지 생성
-func (ss *SlackService) CreateAIAlert(aiResult *AIAnalysisResult) SlackMessage {
-	color := SlackColorWarning
-	if aiResult.AnomalyScore >= HighThreatThreshold {
-		color = SlackColorDanger
-	}
-
-	fields := []SlackField{
-		{Title: "위협 레벨", Value: aiResult.ThreatLevel, Short: true},
-		{Title: "이상 점수", Value: fmt.Sprintf("%.1f/%.0f", aiResult.AnomalyScore, MaxAnomalyScore), Short: true},
-		{Title: "신뢰도", Value: fmt.Sprintf("%.0f%%", aiResult.Confidence*100), Short: true},
-		{Title: "컴퓨터명", Value: aiResult.SystemInfo.ComputerName, Short: true},
-	}
-
-	// 내부 IP 정보 추가
-	if len(aiResult.SystemInfo.InternalIPs) > 0 {
-		fields = append(fields, SlackField{
-			Title: "🏠 내부 IP",
-			Value: strings.Join(aiResult.SystemInfo.InternalIPs, ", "),
-			Short: true,
-		})
-	}
-
-	// 외부 IP 정보 추가
-	if len(aiResult.SystemInfo.ExternalIPs) > 0 {
-		fields = append(fields, SlackField{
-			Title: "🌐 외부 IP",
-			Value: strings.Join(aiResult.SystemInfo.ExternalIPs, ", "),
-			Short: true,
-		})
-	}
-
-	// ASN 정보 추가
-	if len(aiResult.SystemInfo.ASNData) > 0 {
-		asnText := ""
-		for _, asn := range aiResult.SystemInfo.ASNData {
-			asnText += fmt.Sprintf("📍 %s\n🏢 %s\n🌍 %s\n🔢 %s\n\n",
-				asn.IP, asn.Organization, asn.Country, asn.ASN)
-		}
-		fields = append(fields, SlackField{Title: "🔍 ASN 정보", Value: asnText, Short: false})
-	}
-
-	// 영향받는 시스템
-	if len(aiResult.AffectedSystems) > 0 {
-		fields = append(fields, SlackField{
-			Title: "🎯 영향 시스템",
-			Value: strings.Join(aiResult.AffectedSystems, ", "),
-			Short: false,
-		})
-	}
-
-	// 예측 정보
-	if len(aiResult.Predictions) > 0 {
-		predictionText := ""
-		for _, prediction := range aiResult.Predictions {
-			predictionText += fmt.Sprintf("⚡ %s (%.0f%%, %s)\n💥 %s\n\n",
-				prediction.Event, prediction.Probability*100, prediction.TimeFrame, prediction.Impact)
-		}
-		fields = append(fields, SlackField{Title: "🔮 위험 예측", Value: predictionText, Short: false})
-	}
-
-	// 권장사항
-	if len(aiResult.Recommendations) > 0 {
-		recommendationText := ""
-		for _, recommendation := range aiResult.Recommendations {
-			recommendationText += fmt.Sprintf("• %s\n", recommendation)
-		}
-		fields = append(fields, SlackField{Title: "💡 권장사항", Value: recommendationText, Short: false})
-	}
-
-	slackMsg := SlackMessage{
-		Text:      fmt.Sprintf("🚨 *보안 이상 탐지 알람* %s", aiResult.ThreatLevel),
-		IconEmoji: DefaultSlackIcon,
-		Username:  DefaultSlackUsername,
-		Attachments: []SlackAttachment{
-			{
-				Color:     color,
-				Title:     "🤖 AI 분석 결과",
-				Fields:    fields,
-				Timestamp: time.Now().Unix(),
-			},
-		},
-	}
-
-	return slackMsg
-}
-
-// CreateSystemAlert 시스템 알림 메시지 생성
-func (ss *SlackService) CreateSystemAlert(alert SystemAlert) SlackMessage {
-	var color string
-	var emoji string
-
-	switch alert.Level {
-	case "CRITICAL":
-		color = SlackColorDanger
-		emoji = ":rotating_light:"
-	case "WARNING":
-		color = SlackColorWarning
-		emoji = ":warning:"
-	default:
-		color = SlackColorGood
-		emoji = ":information_source:"
-	}
-
-	fields := []SlackField{
-		{Title: "메트릭", Value: alert.Type, Short: true},
-		{Title: "현재 값", Value: fmt.Sprintf("%.2f", alert.Value), Short: true},
-		{Title: "임계값", Value: fmt.Sprintf("%.2f", alert.Threshold), Short: true},
-		{Title: "심각도", Value: alert.Level, Short: true},
-	}
-
-	attachment := SlackAttachment{
-		Color:     color,
-		Title:     fmt.Sprintf("%s 시스템 알림: %s", emoji, alert.Type),
-		Text:      alert.Message,
-		Fields:    fields,
-		Timestamp: alert.Timestamp.Unix(),
-	}
-
-	return SlackMessage{
-		Text:        fmt.Sprintf("%s *시스템 알림*: %s", emoji, alert.Type),
-		IconEmoji:   ":robot_face:",
-		Username:    DefaultSlackUsername,
-		Attachments: []SlackAttachment{attachment},
-	}
-}
-
 // SendTestMessage 테스트 메시지 전송
 func (ss *SlackService) SendTestMessage() error {
 	message := SlackMessage{
@@ -356,7 +156,7 @@ func (ss *SlackService) SendTestMessage() error {
 				Title: "✅ Slack 연동 테스트",
 				Text:  fmt.Sprintf("%s v%s Slack 연동이 정상적으로 작동합니다!", AppName, AppVersion),
 				Fields: []SlackField{
-					{Title: "채널", Value: ss.config.Channel, Short: true},
+					{Title: "채널", Value: ss.currentConfig().Channel, Short: true},
 					{Title: "테스트 시간", Value: time.Now().Format("2006-01-02 15:04:05"), Short: true},
 				},
 				Timestamp: time.Now().Unix(),
@@ -369,25 +169,84 @@ func (ss *SlackService) SendTestMessage() error {
 
 // IsEnabled Slack 서비스 활성화 여부 확인
 func (ss *SlackService) IsEnabled() bool {
-	return ss.config.Enabled
+	return ss.currentConfig().Enabled
 }
 
 // GetChannel 설정된 채널 반환
 func (ss *SlackService) GetChannel() string {
-	return ss.config.Channel
+	return ss.currentConfig().Channel
 }
 
 // SendSimpleMessage 간단한 텍스트 메시지 전송
 func (ss *SlackService) SendSimpleMessage(text string) error {
-	if !ss.config.Enabled {
+	config := ss.currentConfig()
+	if !config.Enabled {
 		return fmt.Errorf("Slack service is disabled")
 	}
 	
 	message := SlackMessage{
 		Text:      text,
-		Username:  ss.config.Username,
+		Username:  config.Username,
 		IconEmoji: ":robot_face:",
 	}
 	
 	return ss.SendMessage(message)
-} 
\ No newline at end of file
+} 
+// Send AlertSink 구현 - 공통 알림 모델을 채널 상세 수준(summary/full)에 맞춰 렌더링 후 전송
+func (ss *SlackService) Send(alert Alert) error {
+	message := alert.RenderSlack(alert.Detail)
+	if alert.Target != "" {
+		message.Channel = alert.Target // 라우팅 규칙이 지정한 채널
+	}
+	channel := message.Channel
+	if channel == "" {
+		channel = ss.currentConfig().Channel
+	}
+	ss.currentStyles().Resolve(channel, alert.Type).Apply(&message, alert.Severity)
+	ss.mutex.RLock()
+	actions := ss.actions
+	ss.mutex.RUnlock()
+	if buttons := actions.Buttons(alert); buttons != nil {
+		message.Attachments = append(message.Attachments, *buttons)
+	}
+	return ss.SendMessage(message)
+}
+
+// createGenericSlackAlert 섹션/전용 서식이 없는 공통 알림을 Slack 메시지로 변환
+func createGenericSlackAlert(alert Alert) SlackMessage {
+	color := SlackColorGood
+	switch alert.Severity {
+	case AlertSeverityWarning:
+		color = SlackColorWarning
+	case AlertSeverityCritical:
+		color = SlackColorDanger
+	}
+
+	fields := []SlackField{
+		{Title: "🖥️ Host", Value: alert.Host, Short: true},
+		{Title: "📍 Type", Value: alert.Type, Short: true},
+	}
+	keys := make([]string, 0, len(alert.Fields))
+	for key := range alert.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, SlackField{Title: key, Value: alert.Fields[key], Short: true})
+	}
+
+	return SlackMessage{
+		Text:      fmt.Sprintf("*%s*", alert.Title),
+		IconEmoji: ":robot_face:",
+		Username:  DefaultSlackUsername,
+		Attachments: []SlackAttachment{
+			{
+				Color:     color,
+				Title:     alert.Title,
+				Text:      alert.Body,
+				Fields:    fields,
+				Timestamp: alert.Timestamp.Unix(),
+			},
+		},
+	}
+}
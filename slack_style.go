@@ -0,0 +1,199 @@
+/*
+Slack Style Module
+==================
+
+Slack 봇 이름, 아이콘, 심각도별 이모지/색상을 채널과 알림 유형별로 지정 (설정 파일 slack)
+
+주요 기능:
+- 전역 → 채널별(channels) → 알림 유형별(alert_types) 순서로 덮어쓰기 (빈 값은 상위 설정 유지)
+- 봇 표시 이름(username), 아이콘 이모지(icon_emoji) 또는 이미지 URL(icon_url)
+- 심각도별 아이콘 이모지 (emoji: info, warning, critical)
+- 기본 색상 이름을 팀 색상으로 바꾸는 매핑 (colors: good, warning, danger → "#2EB67D" 등)
+- 아무것도 설정하지 않으면 기존 표시 방식 그대로 (알림별 기본 아이콘, good/warning/danger 색상)
+
+예:
+
+	"slack": {
+	    "username": "Ops Bot",
+	    "emoji": {"critical": ":fire:"},
+	    "colors": {"danger": "#E01E5A"},
+	    "channels": {"#security": {"username": "SecBot", "icon_emoji": ":shield:"}},
+	    "alert_types": {"login": {"emoji": {"info": ":key:", "warning": ":lock:"}}}
+	}
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"regexp"  // 색상 값 검증
+	"strings" // 문자열 처리
+)
+
+// slackHexColorRegex 첨부 색상에 쓸 수 있는 #RRGGBB 값
+var slackHexColorRegex = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// SlackStyle Slack 메시지 표시 방식 (빈 값은 상위 설정 또는 알림 기본값 사용)
+type SlackStyle struct {
+	Username  string            `json:"username,omitempty"`   // 봇 표시 이름
+	IconEmoji string            `json:"icon_emoji,omitempty"` // 봇 아이콘 이모지 (모든 심각도)
+	IconURL   string            `json:"icon_url,omitempty"`   // 봇 아이콘 이미지 URL
+	Emoji     map[string]string `json:"emoji,omitempty"`      // 심각도별 아이콘 이모지 (info, warning, critical)
+	Colors    map[string]string `json:"colors,omitempty"`     // 기본 색상 이름별 대체 색상 (good, warning, danger → #RRGGBB 또는 색상 이름)
+}
+
+// SlackStyles 전역/채널별/알림 유형별 표시 방식
+type SlackStyles struct {
+	Default    SlackStyle
+	Channels   map[string]SlackStyle // 키는 slackChannelKey 로 정규화
+	AlertTypes map[string]SlackStyle // 키는 소문자 알림 유형
+}
+
+// slackChannelKey 채널 이름 정규화 ("#Security" 와 "security" 를 같은 채널로)
+func slackChannelKey(channel string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(channel), "#"))
+}
+
+// Validate 심각도/색상 이름과 값 검증
+func (s SlackStyle) Validate() error {
+	for severity, emoji := range s.Emoji {
+		switch strings.ToLower(severity) {
+		case AlertSeverityInfo, AlertSeverityWarning, AlertSeverityCritical:
+		default:
+			return fmt.Errorf("unknown severity %q in emoji (expected info, warning or critical)", severity)
+		}
+		if !strings.HasPrefix(emoji, ":") || !strings.HasSuffix(emoji, ":") {
+			return fmt.Errorf("emoji for %s must look like :name:, got %q", severity, emoji)
+		}
+	}
+	if s.IconEmoji != "" && (!strings.HasPrefix(s.IconEmoji, ":") || !strings.HasSuffix(s.IconEmoji, ":")) {
+		return fmt.Errorf("icon_emoji must look like :name:, got %q", s.IconEmoji)
+	}
+	if s.IconURL != "" && !strings.HasPrefix(s.IconURL, "https://") && !strings.HasPrefix(s.IconURL, "http://") {
+		return fmt.Errorf("icon_url must be an http(s) URL, got %q", s.IconURL)
+	}
+	for name, color := range s.Colors {
+		switch strings.ToLower(name) {
+		case SlackColorGood, SlackColorWarning, SlackColorDanger:
+		default:
+			return fmt.Errorf("unknown color %q in colors (expected good, warning or danger)", name)
+		}
+		switch color {
+		case SlackColorGood, SlackColorWarning, SlackColorDanger:
+		default:
+			if !slackHexColorRegex.MatchString(color) {
+				return fmt.Errorf("color for %s must be #RRGGBB or good/warning/danger, got %q", name, color)
+			}
+		}
+	}
+	return nil
+}
+
+// merge 더 구체적인 표시 방식을 덮어씀 (빈 값과 지정하지 않은 심각도/색상은 상위 값 유지)
+// 아이콘은 이모지와 URL 중 더 구체적인 설정에서 지정한 쪽만 남김
+func (s SlackStyle) merge(override SlackStyle) SlackStyle {
+	merged := s
+	if override.Username != "" {
+		merged.Username = override.Username
+	}
+	if override.IconURL != "" {
+		merged.IconURL, merged.IconEmoji, merged.Emoji = override.IconURL, "", nil
+	}
+	if override.IconEmoji != "" {
+		merged.IconEmoji, merged.IconURL = override.IconEmoji, ""
+	}
+	if len(override.Emoji) > 0 {
+		merged.IconURL = ""
+		merged.Emoji = mergeStyleMap(merged.Emoji, override.Emoji)
+	}
+	merged.Colors = mergeStyleMap(merged.Colors, override.Colors)
+	return merged
+}
+
+// mergeStyleMap 키를 소문자로 맞춰 두 매핑을 합침 (override 우선)
+func mergeStyleMap(base, override map[string]string) map[string]string {
+	if len(override) == 0 {
+		return base
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	for key, value := range base {
+		merged[strings.ToLower(key)] = value
+	}
+	for key, value := range override {
+		merged[strings.ToLower(key)] = value
+	}
+	return merged
+}
+
+// NewSlackStyles 설정 값 검증 후 표시 방식 구성
+func NewSlackStyles(defaults SlackStyle, channels, alertTypes map[string]SlackStyle) (SlackStyles, error) {
+	styles := SlackStyles{
+		Default:    SlackStyle{}.merge(defaults),
+		Channels:   make(map[string]SlackStyle, len(channels)),
+		AlertTypes: make(map[string]SlackStyle, len(alertTypes)),
+	}
+	if err := defaults.Validate(); err != nil {
+		return SlackStyles{}, err
+	}
+	for channel, style := range channels {
+		if err := style.Validate(); err != nil {
+			return SlackStyles{}, fmt.Errorf("channels[%s]: %v", channel, err)
+		}
+		styles.Channels[slackChannelKey(channel)] = style
+	}
+	for alertType, style := range alertTypes {
+		if err := style.Validate(); err != nil {
+			return SlackStyles{}, fmt.Errorf("alert_types[%s]: %v", alertType, err)
+		}
+		styles.AlertTypes[strings.ToLower(alertType)] = style
+	}
+	return styles, nil
+}
+
+// Resolve 채널과 알림 유형에 적용할 표시 방식 (전역 → 채널 → 알림 유형 순서로 덮어씀)
+func (ss SlackStyles) Resolve(channel, alertType string) SlackStyle {
+	style := ss.Default
+	if override, ok := ss.Channels[slackChannelKey(channel)]; ok {
+		style = style.merge(override)
+	}
+	if override, ok := ss.AlertTypes[strings.ToLower(alertType)]; ok {
+		style = style.merge(override)
+	}
+	return style
+}
+
+// Apply 렌더링된 메시지에 표시 방식 적용 (지정하지 않은 항목은 메시지 값 유지)
+func (s SlackStyle) Apply(message *SlackMessage, severity string) {
+	if s.Username != "" {
+		message.Username = s.Username
+	}
+	if emoji := s.Emoji[strings.ToLower(severity)]; emoji != "" {
+		message.IconEmoji, message.IconURL = emoji, ""
+	} else if s.IconEmoji != "" {
+		message.IconEmoji, message.IconURL = s.IconEmoji, ""
+	} else if s.IconURL != "" {
+		message.IconURL, message.IconEmoji = s.IconURL, ""
+	}
+	if len(s.Colors) == 0 {
+		return
+	}
+	attachments := make([]SlackAttachment, len(message.Attachments))
+	for i, attachment := range message.Attachments {
+		if color, ok := s.Colors[attachment.Color]; ok {
+			attachment.Color = color
+		}
+		attachments[i] = attachment
+	}
+	message.Attachments = attachments
+}
+
+// SlackStyles 설정 파일의 slack 항목에서 표시 방식 구성
+func (c *Config) SlackStyles() (SlackStyles, error) {
+	defaults := SlackStyle{
+		Username:  c.Slack.Username,
+		IconEmoji: c.Slack.IconEmoji,
+		IconURL:   c.Slack.IconURL,
+		Emoji:     c.Slack.Emoji,
+		Colors:    c.Slack.Colors,
+	}
+	return NewSlackStyles(defaults, c.Slack.Channels, c.Slack.AlertTypes)
+}
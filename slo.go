@@ -0,0 +1,503 @@
+/*
+SLO Error Budget Module
+=======================
+
+엔드포인트별 SLO (예: /api/checkout 성공률 99.9%) 의 오류 예산 추적과
+burn rate 알림 (-slo, 설정 파일 "slos")
+
+주요 기능:
+- 파싱된 웹 접근 로그(HTTPDetails)를 SLO 엔드포인트(메서드 + 경로, "*" 접미사는 접두사 일치)에 매칭
+- 5xx 응답과 (latency_ms 지정 시) 느린 응답을 실패로 집계
+- 다중 윈도우 burn rate 판정 (SRE Workbook 방식)
+  - fast: 1시간 / 5분 윈도우 모두 14.4배 이상 (30일 예산의 2% 를 1시간에 소진) → critical
+  - slow: 6시간 / 30분 윈도우 모두 6배 이상 (30일 예산의 5% 를 6시간에 소진) → warning
+
+- 긴/짧은 윈도우 burn rate 가 모두 임계값 아래로 내려가면 복구 알림 (info)
+- SLO 기간(기본 30일) 남은 오류 예산 계산 (시간 단위 집계)
+
+burn rate = 윈도우 오류율 / 오류 예산 (1 - 목표), 1 이면 SLO 기간이 끝날 때 예산을 정확히 소진
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"os"      // 호스트 이름
+	"sort"    // 상태 정렬
+	"strconv" // 목표/알림 필드
+	"strings" // 문자열 처리
+	"sync"    // 동기화 (뮤텍스)
+	"time"    // 윈도우 처리
+)
+
+// SLO burn rate 알림 종류
+const (
+	SLOBurnFast     = "fast"
+	SLOBurnSlow     = "slow"
+	SLOBurnResolved = "resolved"
+)
+
+// SLO 추적 기본값
+const (
+	DefaultSLOWindowDays = 30               // 오류 예산 기간 (일)
+	sloFastLongWindow    = time.Hour        // fast burn 긴 윈도우
+	sloFastShortWindow   = 5 * time.Minute  // fast burn 짧은 윈도우
+	sloFastBurnRate      = 14.4             // fast burn 임계값
+	sloSlowLongWindow    = 6 * time.Hour    // slow burn 긴 윈도우
+	sloSlowShortWindow   = 30 * time.Minute // slow burn 짧은 윈도우
+	sloSlowBurnRate      = 6.0              // slow burn 임계값
+	sloMinRequests       = 20               // burn rate 판정에 필요한 긴 윈도우 최소 요청 수
+	sloEvaluateInterval  = 15 * time.Second // SLO 별 burn rate 재계산 최소 간격
+	sloMaxWindowDays     = 90               // 오류 예산 기간 상한 (일)
+)
+
+// sloBurnRules burn rate 판정 규칙 (심각한 순서)
+var sloBurnRules = []struct {
+	kind      string
+	severity  string
+	long      time.Duration
+	short     time.Duration
+	threshold float64
+}{
+	{SLOBurnFast, AlertSeverityCritical, sloFastLongWindow, sloFastShortWindow, sloFastBurnRate},
+	{SLOBurnSlow, AlertSeverityWarning, sloSlowLongWindow, sloSlowShortWindow, sloSlowBurnRate},
+}
+
+// SLODefinition 엔드포인트 SLO 정의 (설정 파일 "slos" 항목)
+type SLODefinition struct {
+	Name       string  `json:"name"`        // 표시 이름 (비어 있으면 "메서드 경로")
+	Path       string  `json:"path"`        // 요청 경로 (쿼리 제외, "*" 로 끝나면 접두사 일치)
+	Method     string  `json:"method"`      // HTTP 메서드 (비어 있으면 모든 메서드)
+	Target     float64 `json:"target"`      // 성공률 목표 (%, 예: 99.9)
+	LatencyMS  int64   `json:"latency_ms"`  // 이보다 느린 응답도 실패로 집계 (0 이면 상태 코드만)
+	WindowDays int     `json:"window_days"` // 오류 예산 기간 (일, 0 이면 30)
+}
+
+// SLOBurnEvent burn rate 알림 또는 복구
+type SLOBurnEvent struct {
+	Kind            string        // fast, slow, resolved
+	Severity        string        // 알림 등급 (resolved 는 info)
+	SLO             SLODefinition // 대상 SLO
+	LongWindow      time.Duration // 긴 윈도우
+	ShortWindow     time.Duration // 짧은 윈도우
+	Threshold       float64       // burn rate 임계값
+	LongBurn        float64       // 긴 윈도우 burn rate
+	ShortBurn       float64       // 짧은 윈도우 burn rate
+	LongRequests    int64         // 긴 윈도우 요청 수
+	LongErrors      int64         // 긴 윈도우 실패 수
+	BudgetRemaining float64       // SLO 기간 남은 오류 예산 (%, 음수면 초과)
+	LastError       string        // 마지막 실패 요청 ("GET /api/checkout → 503")
+	Line            string        // 마지막 실패 접근 로그
+}
+
+// SLOStatus SLO 현재 상태 (관리 API GET /slo)
+type SLOStatus struct {
+	Name            string  `json:"name"`
+	Endpoint        string  `json:"endpoint"`
+	Target          float64 `json:"target"`
+	WindowDays      int     `json:"window_days"`
+	Requests        int64   `json:"requests"`          // SLO 기간 요청 수
+	Errors          int64   `json:"errors"`            // SLO 기간 실패 수
+	SuccessRate     float64 `json:"success_rate"`      // SLO 기간 성공률 (%)
+	BudgetRemaining float64 `json:"budget_remaining"`  // 남은 오류 예산 (%)
+	FastBurn        float64 `json:"fast_burn"`         // 1시간 burn rate
+	SlowBurn        float64 `json:"slow_burn"`         // 6시간 burn rate
+	Burning         string  `json:"burning,omitempty"` // 알림 중인 burn (fast, slow)
+}
+
+// sloBucket 한 구간(분 또는 시간)의 요청/실패 수
+type sloBucket struct {
+	start  int64 // 구간 번호 (Unix 분 또는 시)
+	total  int64
+	errors int64
+}
+
+// sloState SLO 하나의 집계 상태
+type sloState struct {
+	def       SLODefinition
+	minutes   []sloBucket // 분 단위 링 버퍼 (가장 긴 burn 윈도우)
+	hours     []sloBucket // 시간 단위 링 버퍼 (SLO 기간)
+	burning   string      // 알림 중인 burn (fast, slow, 없으면 "")
+	notified  time.Time   // 마지막 burn 알림 시각 (긴 윈도우마다 재알림)
+	evaluated time.Time   // 마지막 burn rate 계산 시각
+	lastError string
+	lastLine  string
+}
+
+// SLOTracker 엔드포인트 SLO 오류 예산 추적기
+type SLOTracker struct {
+	mutex sync.Mutex
+	slos  []*sloState
+}
+
+// NewSLOTracker SLO 정의로 추적기 생성 (정의 검증 실패 시 에러)
+func NewSLOTracker(definitions []SLODefinition) (*SLOTracker, error) {
+	tracker := &SLOTracker{}
+	if err := tracker.SetDefinitions(definitions); err != nil {
+		return nil, err
+	}
+	return tracker, nil
+}
+
+// ParseSLOSpecs "-slo" 플래그 값 파싱 ("[METHOD ]PATH=TARGET", 쉼표 구분)
+// 예: "/api/checkout=99.9,POST /api/orders=99.5,/api/search/*=99"
+func ParseSLOSpecs(specs []string) ([]SLODefinition, error) {
+	var definitions []SLODefinition
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		separator := strings.LastIndex(spec, "=")
+		if separator <= 0 {
+			return nil, fmt.Errorf("invalid SLO %q (expected [METHOD ]PATH=TARGET)", spec)
+		}
+		target, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(spec[separator+1:]), "%"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SLO target in %q: %v", spec, err)
+		}
+		definition := SLODefinition{Path: strings.TrimSpace(spec[:separator]), Target: target}
+		if fields := strings.Fields(definition.Path); len(fields) == 2 {
+			definition.Method, definition.Path = fields[0], fields[1]
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions, nil
+}
+
+// SetDefinitions SLO 정의 교체 (정의가 같은 SLO 는 집계/알림 상태 유지)
+func (st *SLOTracker) SetDefinitions(definitions []SLODefinition) error {
+	states := make([]*sloState, 0, len(definitions))
+	for _, def := range definitions {
+		def.Method = strings.ToUpper(strings.TrimSpace(def.Method))
+		def.Path = strings.TrimSpace(def.Path)
+		if def.Path == "" || !strings.HasPrefix(def.Path, "/") {
+			return fmt.Errorf("SLO %q: path must start with /", def.Path)
+		}
+		if def.Target <= 0 || def.Target >= 100 {
+			return fmt.Errorf("SLO %s: target must be between 0 and 100 (exclusive), got %g", def.Path, def.Target)
+		}
+		if def.WindowDays <= 0 {
+			def.WindowDays = DefaultSLOWindowDays
+		}
+		if def.WindowDays > sloMaxWindowDays {
+			def.WindowDays = sloMaxWindowDays
+		}
+		if def.Name == "" {
+			def.Name = sloEndpoint(def)
+		}
+		states = append(states, &sloState{
+			def:     def,
+			minutes: make([]sloBucket, int(sloSlowLongWindow/time.Minute)),
+			hours:   make([]sloBucket, def.WindowDays*24),
+		})
+	}
+
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	for i, state := range states {
+		for _, current := range st.slos {
+			if current.def == state.def {
+				states[i] = current
+				break
+			}
+		}
+	}
+	st.slos = states
+	return nil
+}
+
+// Definitions 현재 SLO 정의 목록
+func (st *SLOTracker) Definitions() []SLODefinition {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+	definitions := make([]SLODefinition, 0, len(st.slos))
+	for _, state := range st.slos {
+		definitions = append(definitions, state.def)
+	}
+	return definitions
+}
+
+// Observe 파싱된 웹 접근 로그를 일치하는 SLO 에 집계하고 burn rate 알림/복구 반환
+func (st *SLOTracker) Observe(parsedLog *ParsedLog, line string, at time.Time) []*SLOBurnEvent {
+	if parsedLog == nil || parsedLog.HTTPDetails == nil || parsedLog.HTTPDetails.StatusCode == 0 {
+		return nil
+	}
+	http := parsedLog.HTTPDetails
+	path := http.URL
+	if index := strings.IndexAny(path, "?#"); index >= 0 {
+		path = path[:index]
+	}
+
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	var events []*SLOBurnEvent
+	for _, state := range st.slos {
+		if !state.matches(http.Method, path) {
+			continue
+		}
+		failed := http.StatusCode >= 500 || (state.def.LatencyMS > 0 && http.ResponseTime > state.def.LatencyMS)
+		state.record(at, failed)
+		if failed {
+			state.lastError = fmt.Sprintf("%s %s → %d (%dms)", http.Method, http.URL, http.StatusCode, http.ResponseTime)
+			state.lastLine = line
+		}
+		if at.Sub(state.evaluated) < sloEvaluateInterval {
+			continue
+		}
+		state.evaluated = at
+		if event := state.evaluate(at); event != nil {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Status SLO 별 현재 상태
+func (st *SLOTracker) Status(now time.Time) []SLOStatus {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	statuses := make([]SLOStatus, 0, len(st.slos))
+	for _, state := range st.slos {
+		total, errors := state.budgetCounts(now)
+		status := SLOStatus{
+			Name:            state.def.Name,
+			Endpoint:        sloEndpoint(state.def),
+			Target:          state.def.Target,
+			WindowDays:      state.def.WindowDays,
+			Requests:        total,
+			Errors:          errors,
+			SuccessRate:     100,
+			BudgetRemaining: state.budgetRemaining(total, errors),
+			FastBurn:        state.burnRate(now, sloFastLongWindow),
+			SlowBurn:        state.burnRate(now, sloSlowLongWindow),
+			Burning:         state.burning,
+		}
+		if total > 0 {
+			status.SuccessRate = 100 * float64(total-errors) / float64(total)
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].BudgetRemaining < statuses[j].BudgetRemaining })
+	return statuses
+}
+
+// matches 요청이 SLO 엔드포인트에 해당하는지 확인
+func (s *sloState) matches(method, path string) bool {
+	if s.def.Method != "" && !strings.EqualFold(s.def.Method, method) {
+		return false
+	}
+	if prefix := strings.TrimSuffix(s.def.Path, "*"); prefix != s.def.Path {
+		return strings.HasPrefix(path, prefix)
+	}
+	return path == s.def.Path
+}
+
+// record 요청 한 건을 분/시간 구간에 집계
+func (s *sloState) record(at time.Time, failed bool) {
+	for _, ring := range []struct {
+		buckets []sloBucket
+		index   int64
+	}{
+		{s.minutes, at.Unix() / 60},
+		{s.hours, at.Unix() / 3600},
+	} {
+		bucket := &ring.buckets[ring.index%int64(len(ring.buckets))]
+		if bucket.start != ring.index {
+			*bucket = sloBucket{start: ring.index}
+		}
+		bucket.total++
+		if failed {
+			bucket.errors++
+		}
+	}
+}
+
+// windowCounts 최근 window 동안의 요청/실패 수 (분 단위)
+func (s *sloState) windowCounts(now time.Time, window time.Duration) (int64, int64) {
+	current := now.Unix() / 60
+	oldest := current - int64(window/time.Minute)
+	var total, errors int64
+	for _, bucket := range s.minutes {
+		if bucket.start > oldest && bucket.start <= current {
+			total += bucket.total
+			errors += bucket.errors
+		}
+	}
+	return total, errors
+}
+
+// budgetCounts SLO 기간 요청/실패 수 (시간 단위)
+func (s *sloState) budgetCounts(now time.Time) (int64, int64) {
+	current := now.Unix() / 3600
+	oldest := current - int64(len(s.hours))
+	var total, errors int64
+	for _, bucket := range s.hours {
+		if bucket.start > oldest && bucket.start <= current {
+			total += bucket.total
+			errors += bucket.errors
+		}
+	}
+	return total, errors
+}
+
+// errorBudget 허용 오류율 (1 - 목표)
+func (s *sloState) errorBudget() float64 {
+	return 1 - s.def.Target/100
+}
+
+// burnRate 윈도우 오류율 / 오류 예산 (요청이 없으면 0)
+func (s *sloState) burnRate(now time.Time, window time.Duration) float64 {
+	total, errors := s.windowCounts(now, window)
+	if total == 0 {
+		return 0
+	}
+	return float64(errors) / float64(total) / s.errorBudget()
+}
+
+// budgetRemaining SLO 기간 남은 오류 예산 (%, 요청이 없으면 100)
+func (s *sloState) budgetRemaining(total, errors int64) float64 {
+	if total == 0 {
+		return 100
+	}
+	return 100 * (1 - float64(errors)/(float64(total)*s.errorBudget()))
+}
+
+// evaluate fast/slow burn 판정
+// 새로 시작했거나 더 심각한 단계로 바뀌면 알림, 같은 단계가 긴 윈도우보다 오래 계속되면 재알림,
+// 긴/짧은 윈도우 burn rate 가 모두 임계값 아래로 내려가면 복구 (짧은 윈도우만 회복한 동안은 대기)
+func (s *sloState) evaluate(now time.Time) *SLOBurnEvent {
+	for _, rule := range sloBurnRules {
+		total, errors := s.windowCounts(now, rule.long)
+		longBurn, shortBurn := s.burnRate(now, rule.long), s.burnRate(now, rule.short)
+		if total < sloMinRequests || longBurn < rule.threshold || shortBurn < rule.threshold {
+			continue
+		}
+		switch {
+		case s.burning == SLOBurnFast && rule.kind == SLOBurnSlow:
+			// fast 는 잦아들었지만 slow 기준으로는 계속 소진 중 (알림 없이 단계만 낮춤)
+			s.burning = SLOBurnSlow
+			return nil
+		case s.burning == rule.kind && now.Sub(s.notified) < rule.long:
+			return nil
+		}
+		s.burning, s.notified = rule.kind, now
+		return s.event(now, rule.kind, rule.severity, rule.long, rule.short, rule.threshold, total, errors, longBurn, shortBurn)
+	}
+
+	if s.burning == "" {
+		return nil
+	}
+	rule := sloBurnRules[1]
+	if s.burning == SLOBurnFast {
+		rule = sloBurnRules[0]
+	}
+	total, errors := s.windowCounts(now, rule.long)
+	longBurn, shortBurn := s.burnRate(now, rule.long), s.burnRate(now, rule.short)
+	if total >= sloMinRequests && (longBurn >= rule.threshold || shortBurn >= rule.threshold) {
+		return nil
+	}
+	s.burning = ""
+	return s.event(now, SLOBurnResolved, AlertSeverityInfo, rule.long, rule.short, rule.threshold, total, errors, longBurn, shortBurn)
+}
+
+// event burn rate 알림/복구 이벤트 생성
+func (s *sloState) event(now time.Time, kind, severity string, long, short time.Duration, threshold float64, total, errors int64, longBurn, shortBurn float64) *SLOBurnEvent {
+	budgetTotal, budgetErrors := s.budgetCounts(now)
+	return &SLOBurnEvent{
+		Kind:            kind,
+		Severity:        severity,
+		SLO:             s.def,
+		LongWindow:      long,
+		ShortWindow:     short,
+		Threshold:       threshold,
+		LongBurn:        longBurn,
+		ShortBurn:       shortBurn,
+		LongRequests:    total,
+		LongErrors:      errors,
+		BudgetRemaining: s.budgetRemaining(budgetTotal, budgetErrors),
+		LastError:       s.lastError,
+		Line:            s.lastLine,
+	}
+}
+
+// equalSLODefinitions 두 SLO 정의 목록이 같은지 확인 (설정 재로드)
+func equalSLODefinitions(a, b []SLODefinition) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sloEndpoint 표시용 "메서드 경로"
+func sloEndpoint(def SLODefinition) string {
+	if def.Method == "" {
+		return def.Path
+	}
+	return def.Method + " " + def.Path
+}
+
+// sloBurnAlert burn rate 알림 (fast 는 critical, slow 는 warning, 복구는 info)
+func sloBurnAlert(event *SLOBurnEvent) Alert {
+	host, _ := os.Hostname()
+	endpoint := sloEndpoint(event.SLO)
+
+	headline := fmt.Sprintf("🔥 %s SLO(%g%%) 오류 예산이 %.1f배 속도로 소진되고 있습니다 (%v 기준)", event.SLO.Name, event.SLO.Target, event.LongBurn, event.LongWindow)
+	if event.Kind == SLOBurnResolved {
+		headline = fmt.Sprintf("✅ %s SLO 오류 예산 소진 속도가 정상으로 돌아왔습니다 (최근 %v %.1f배)", event.SLO.Name, event.ShortWindow, event.ShortBurn)
+	}
+
+	errorRate := 0.0
+	if event.LongRequests > 0 {
+		errorRate = 100 * float64(event.LongErrors) / float64(event.LongRequests)
+	}
+	failure := "5xx 응답"
+	if event.SLO.LatencyMS > 0 {
+		failure = fmt.Sprintf("5xx 응답 또는 %dms 초과", event.SLO.LatencyMS)
+	}
+	fields := []AlertField{
+		{Label: "SLO", Value: event.SLO.Name, Short: true},
+		{Label: "엔드포인트", Value: endpoint, Short: true},
+		{Label: "목표", Value: fmt.Sprintf("%g%% (%d일)", event.SLO.Target, event.SLO.WindowDays), Short: true},
+		{Label: "남은 오류 예산", Value: fmt.Sprintf("%.1f%%", event.BudgetRemaining), Short: true},
+		{Label: fmt.Sprintf("burn rate (%v)", event.LongWindow), Value: fmt.Sprintf("%.1f배 (임계값 %.1f배)", event.LongBurn, event.Threshold), Short: true},
+		{Label: fmt.Sprintf("burn rate (%v)", event.ShortWindow), Value: fmt.Sprintf("%.1f배", event.ShortBurn), Short: true},
+		{Label: fmt.Sprintf("오류율 (%v)", event.LongWindow), Value: fmt.Sprintf("%.2f%% (%d/%d건)", errorRate, event.LongErrors, event.LongRequests), Short: true},
+		{Label: "실패 기준", Value: failure, Short: true},
+	}
+	sections := []AlertSection{{Fields: fields, Summary: true}}
+	if event.LastError != "" && event.Kind != SLOBurnResolved {
+		sections = append(sections, AlertSection{
+			Title: "🌐 마지막 실패 요청",
+			Fields: []AlertField{
+				{Label: "요청", Value: event.LastError, Code: true},
+				{Label: "접근 로그", Value: event.Line, Code: true},
+			},
+		})
+	}
+
+	return Alert{
+		Type:     AlertTypeSLO,
+		Severity: event.Severity,
+		Title:    fmt.Sprintf("[%s SLO %s] %s - %s %.1fx", AppName, strings.ToUpper(event.Kind), host, event.SLO.Name, event.LongBurn),
+		Headline: headline,
+		Sections: sections,
+		Host:     host,
+		Thread:   alertThreadKey(AlertTypeSLO, host, event.SLO.Name),
+		Fields: map[string]string{
+			"kind":             event.Kind,
+			"slo":              event.SLO.Name,
+			"endpoint":         endpoint,
+			"target":           strconv.FormatFloat(event.SLO.Target, 'f', -1, 64),
+			"long_burn":        strconv.FormatFloat(event.LongBurn, 'f', 2, 64),
+			"short_burn":       strconv.FormatFloat(event.ShortBurn, 'f', 2, 64),
+			"budget_remaining": strconv.FormatFloat(event.BudgetRemaining, 'f', 1, 64),
+		},
+	}
+}
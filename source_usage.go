@@ -0,0 +1,399 @@
+/*
+Source Usage Module
+===================
+
+입력 소스/파서별 처리 비용 집계 (GET /usage, GET /metrics)
+
+모니터가 어느 로그에 처리 시간을 쓰는지 보여 주어, 예를 들어 nginx 접근 로그가 처리 시간의 80% 를
+차지한다면 해당 소스를 표본 추출하거나 필터(-filters)로 줄일지 판단할 수 있게 합니다.
+
+주요 기능:
+- 소스(입력 파일, journald 유닛, 이벤트 로그 공급자, 쿠버네티스 워크로드)별 줄 수, 바이트, 필터로 버린 줄 수
+- 단계별 처리 시간: filter (필터/키워드/헬스 체크), parse, ai, notify (출력/감지/알림)
+- 파서(log_type)별 줄 수, 바이트, 형식별 파서(정규식) 시간
+- 처리 시간 비율로 프로세스 CPU 시간을 소스별로 나눈 추정치, 프로세스 RSS 와 Go 힙 크기
+- 주기적 시스템 상태 보고서에 처리 시간 상위 소스/파서 표시
+
+처리 시간은 벽시계 시간이므로 AI 단계의 ASN 조회처럼 외부 응답을 기다린 시간도 포함됩니다.
+CPU 추정치는 이 비율로 나눈 값이라 정확한 측정이 아닌 상대 비교용입니다.
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"os"      // 프로세스 ID
+	"runtime" // Go 힙 크기
+	"sort"    // 정렬
+	"strings" // 문자열 처리
+	"sync"    // 동기화 (뮤텍스)
+	"time"    // 시간 처리
+
+	"github.com/shirou/gopsutil/v4/process" // 프로세스 CPU 시간, RSS
+)
+
+// 소스 집계 기본값
+const (
+	sourceUsageMaxSources = 1000      // 추적할 소스 수 상한 (넘으면 sourceUsageOther 로 합산)
+	sourceUsageOther      = "(other)" // 상한을 넘은 소스를 합산하는 이름
+	sourceUsageReportTop  = 5         // 보고서에 표시할 소스/파서 수
+)
+
+// UsageStage 처리 비용 단계
+type UsageStage int
+
+// 처리 비용 단계 (usageStageNames 와 같은 순서)
+const (
+	UsageStageFilter UsageStage = iota
+	UsageStageParse
+	UsageStageAI
+	UsageStageNotify
+	usageStageCount
+)
+
+// usageStageNames Prometheus stage 라벨 / API 필드 이름
+var usageStageNames = [usageStageCount]string{"filter", "parse", "ai", "notify"}
+
+// UsageCost 엔트리 하나의 처리 비용 (파이프라인 단계마다 채우고 알림 단계에서 기록)
+type UsageCost struct {
+	Stages     [usageStageCount]time.Duration
+	Parser     string        // 형식별 파서 결과 log_type (파싱하지 않았으면 빈 값)
+	ParserTime time.Duration // 형식별 파서 시간 (parse 단계에 포함)
+}
+
+// sourceUsage 소스 하나의 누적 비용
+type sourceUsage struct {
+	lines    int64
+	filtered int64
+	bytes    int64
+	stages   [usageStageCount]time.Duration
+}
+
+// parserUsage 파서 하나의 누적 비용
+type parserUsage struct {
+	lines int64
+	bytes int64
+	time  time.Duration
+}
+
+// SourceUsageTracker 소스/파서별 처리 비용 집계 (파이프라인 알림 단계와 처리 루프에서 호출)
+type SourceUsageTracker struct {
+	mutex   sync.Mutex
+	since   time.Time
+	sources map[string]*sourceUsage
+	parsers map[string]*parserUsage
+}
+
+// SourceUsage 소스별 처리 비용 (API 응답)
+type SourceUsage struct {
+	Source              string             `json:"source"`
+	Lines               int64              `json:"lines"`
+	FilteredLines       int64              `json:"filtered_lines"` // 필터/키워드/헬스 체크로 버린 줄 (lines 에 포함)
+	Bytes               int64              `json:"bytes"`
+	StageSeconds        map[string]float64 `json:"stage_seconds"`
+	ProcessingSeconds   float64            `json:"processing_seconds"`
+	ProcessingShare     float64            `json:"processing_share"` // 전체 처리 시간 중 비율 (%)
+	BytesShare          float64            `json:"bytes_share"`      // 전체 바이트 중 비율 (%)
+	EstimatedCPUSeconds float64            `json:"estimated_cpu_seconds,omitempty"`
+}
+
+// ParserUsage 파서별 처리 비용 (API 응답)
+type ParserUsage struct {
+	Parser        string  `json:"parser"`
+	Lines         int64   `json:"lines"`
+	Bytes         int64   `json:"bytes"`
+	ParseSeconds  float64 `json:"parse_seconds"`
+	AverageMicros float64 `json:"avg_parse_us"`
+	ParseShare    float64 `json:"parse_share"` // 전체 파서 시간 중 비율 (%)
+}
+
+// SourceUsageSnapshot 처리 비용 집계 (GET /usage, 소스는 처리 시간이 큰 순서)
+type SourceUsageSnapshot struct {
+	Since             time.Time     `json:"since"`
+	Lines             int64         `json:"lines"`
+	Bytes             int64         `json:"bytes"`
+	ProcessingSeconds float64       `json:"processing_seconds"`
+	ProcessCPUSeconds float64       `json:"process_cpu_seconds,omitempty"` // 프로세스 전체 CPU 시간 (사용자 + 시스템)
+	ProcessRSSBytes   uint64        `json:"process_rss_bytes,omitempty"`
+	GoHeapBytes       uint64        `json:"go_heap_bytes,omitempty"`
+	Sources           []SourceUsage `json:"sources"`
+	Parsers           []ParserUsage `json:"parsers"`
+}
+
+// TenantSourceUsage 지표를 내보낼 모니터 하나의 처리 비용 (Tenant 가 비어 있으면 운영자 모니터)
+type TenantSourceUsage struct {
+	Tenant string
+	Stats  SourceUsageSnapshot
+}
+
+// NewSourceUsageTracker 처리 비용 집계 생성
+func NewSourceUsageTracker() *SourceUsageTracker {
+	return &SourceUsageTracker{
+		since:   time.Now(),
+		sources: make(map[string]*sourceUsage),
+		parsers: make(map[string]*parserUsage),
+	}
+}
+
+// Record 파이프라인을 끝낸 엔트리의 비용 기록
+func (t *SourceUsageTracker) Record(source string, bytes int, cost *UsageCost) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := t.source(source)
+	stats.lines++
+	stats.bytes += int64(bytes)
+	for stage, elapsed := range cost.Stages {
+		stats.stages[stage] += elapsed
+	}
+	if cost.Parser == "" {
+		return
+	}
+	parser, ok := t.parsers[cost.Parser]
+	if !ok {
+		parser = &parserUsage{}
+		t.parsers[cost.Parser] = parser
+	}
+	parser.lines++
+	parser.bytes += int64(bytes)
+	parser.time += cost.ParserTime
+}
+
+// RecordFiltered 필터/키워드/헬스 체크로 버린 줄의 비용 기록
+func (t *SourceUsageTracker) RecordFiltered(source string, bytes int, elapsed time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	stats := t.source(source)
+	stats.lines++
+	stats.filtered++
+	stats.bytes += int64(bytes)
+	stats.stages[UsageStageFilter] += elapsed
+}
+
+// source 소스 통계 (없으면 생성, 상한을 넘으면 합산 항목, 뮤텍스를 잡은 상태에서 호출)
+func (t *SourceUsageTracker) source(name string) *sourceUsage {
+	if name == "" {
+		name = "-"
+	}
+	stats, ok := t.sources[name]
+	if ok {
+		return stats
+	}
+	if len(t.sources) >= sourceUsageMaxSources {
+		name = sourceUsageOther
+		if stats, ok := t.sources[name]; ok {
+			return stats
+		}
+	}
+	stats = &sourceUsage{}
+	t.sources[name] = stats
+	return stats
+}
+
+// Snapshot 현재 집계 (프로세스 CPU/메모리와 CPU 추정치는 AttributeProcessUsage 로 채움)
+func (t *SourceUsageTracker) Snapshot() SourceUsageSnapshot {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	snapshot := SourceUsageSnapshot{
+		Since:   t.since,
+		Sources: make([]SourceUsage, 0, len(t.sources)),
+		Parsers: make([]ParserUsage, 0, len(t.parsers)),
+	}
+	for name, stats := range t.sources {
+		entry := SourceUsage{
+			Source:        name,
+			Lines:         stats.lines,
+			FilteredLines: stats.filtered,
+			Bytes:         stats.bytes,
+			StageSeconds:  make(map[string]float64, usageStageCount),
+		}
+		for stage, elapsed := range stats.stages {
+			entry.StageSeconds[usageStageNames[stage]] = elapsed.Seconds()
+			entry.ProcessingSeconds += elapsed.Seconds()
+		}
+		snapshot.Lines += entry.Lines
+		snapshot.Bytes += entry.Bytes
+		snapshot.ProcessingSeconds += entry.ProcessingSeconds
+		snapshot.Sources = append(snapshot.Sources, entry)
+	}
+	for i := range snapshot.Sources {
+		entry := &snapshot.Sources[i]
+		entry.ProcessingShare = percentOf(entry.ProcessingSeconds, snapshot.ProcessingSeconds)
+		entry.BytesShare = percentOf(float64(entry.Bytes), float64(snapshot.Bytes))
+	}
+	sort.Slice(snapshot.Sources, func(i, j int) bool {
+		if snapshot.Sources[i].ProcessingSeconds != snapshot.Sources[j].ProcessingSeconds {
+			return snapshot.Sources[i].ProcessingSeconds > snapshot.Sources[j].ProcessingSeconds
+		}
+		return snapshot.Sources[i].Source < snapshot.Sources[j].Source
+	})
+
+	var parseTotal float64
+	for name, stats := range t.parsers {
+		entry := ParserUsage{Parser: name, Lines: stats.lines, Bytes: stats.bytes, ParseSeconds: stats.time.Seconds()}
+		if stats.lines > 0 {
+			entry.AverageMicros = float64(stats.time.Microseconds()) / float64(stats.lines)
+		}
+		parseTotal += entry.ParseSeconds
+		snapshot.Parsers = append(snapshot.Parsers, entry)
+	}
+	for i := range snapshot.Parsers {
+		snapshot.Parsers[i].ParseShare = percentOf(snapshot.Parsers[i].ParseSeconds, parseTotal)
+	}
+	sort.Slice(snapshot.Parsers, func(i, j int) bool {
+		if snapshot.Parsers[i].ParseSeconds != snapshot.Parsers[j].ParseSeconds {
+			return snapshot.Parsers[i].ParseSeconds > snapshot.Parsers[j].ParseSeconds
+		}
+		return snapshot.Parsers[i].Parser < snapshot.Parsers[j].Parser
+	})
+	return snapshot
+}
+
+// AttributeProcessUsage 프로세스 CPU 시간을 모든 모니터(운영자 + 테넌트)의 처리 시간 비율로 소스별로 나누고
+// 프로세스 RSS 와 Go 힙 크기를 채움 (CPU 시간을 읽지 못하면 추정치 생략)
+func AttributeProcessUsage(snapshots []*SourceUsageSnapshot) {
+	var heap runtime.MemStats
+	runtime.ReadMemStats(&heap)
+
+	var cpuSeconds float64
+	var rss uint64
+	if proc, err := process.NewProcess(int32(os.Getpid())); err == nil {
+		if times, err := proc.Times(); err == nil {
+			cpuSeconds = times.User + times.System
+		}
+		if memory, err := proc.MemoryInfo(); err == nil {
+			rss = memory.RSS
+		}
+	}
+
+	var total float64
+	for _, snapshot := range snapshots {
+		total += snapshot.ProcessingSeconds
+	}
+	for _, snapshot := range snapshots {
+		snapshot.ProcessCPUSeconds = cpuSeconds
+		snapshot.ProcessRSSBytes = rss
+		snapshot.GoHeapBytes = heap.HeapAlloc
+		if cpuSeconds <= 0 || total <= 0 {
+			continue
+		}
+		for i := range snapshot.Sources {
+			snapshot.Sources[i].EstimatedCPUSeconds = cpuSeconds * snapshot.Sources[i].ProcessingSeconds / total
+		}
+	}
+}
+
+// FormatText 주기적 보고서의 처리 비용 섹션 (처리 시간 상위 소스/파서, 처리한 줄이 없으면 빈 문자열)
+func (s SourceUsageSnapshot) FormatText() string {
+	if s.Lines == 0 {
+		return ""
+	}
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("\n⚙️  소스별 처리 비용 (%s 이후):\n", s.Since.Format("2006-01-02 15:04")))
+	for i, source := range s.Sources {
+		if i == sourceUsageReportTop {
+			text.WriteString(fmt.Sprintf("   ... 외 %d개 소스\n", len(s.Sources)-i))
+			break
+		}
+		text.WriteString(fmt.Sprintf("   %s: 처리 시간 %.1f%% (%.1fs), %d줄 (필터 제외 %d), %.1f MB\n",
+			source.Source, source.ProcessingShare, source.ProcessingSeconds, source.Lines, source.FilteredLines, float64(source.Bytes)/1024/1024))
+	}
+	for i, parser := range s.Parsers {
+		if i == sourceUsageReportTop {
+			break
+		}
+		text.WriteString(fmt.Sprintf("   파서 %s: 파싱 시간 %.1f%% (줄당 %.0fµs, %d줄)\n", parser.Parser, parser.ParseShare, parser.AverageMicros, parser.Lines))
+	}
+	return text.String()
+}
+
+// sourceUsageLabel 엔트리의 소스 이름 (구조화된 입력은 유닛/공급자/워크로드, 그 외에는 읽은 파일)
+func sourceUsageLabel(file string, preParsed *ParsedLog) string {
+	if preParsed == nil {
+		return file
+	}
+	switch preParsed.LogType {
+	case KubeLogType:
+		if label := kubeWorkloadLabel(preParsed); label != "" {
+			return label
+		}
+		return "kubernetes"
+	case "journald":
+		if unit := preParsed.Fields["unit"]; unit != "" {
+			return "journald:" + unit
+		}
+		if preParsed.Source != "" {
+			return "journald:" + preParsed.Source
+		}
+		return "journald"
+	case "eventlog":
+		if preParsed.Source != "" {
+			return "eventlog:" + preParsed.Source
+		}
+		return "eventlog"
+	}
+	if file == "" {
+		return preParsed.LogType
+	}
+	return file
+}
+
+// percentOf 비율 (%, 전체가 0 이면 0)
+func percentOf(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return 100 * part / total
+}
+
+// WriteSourceUsageMetrics Prometheus 텍스트 형식 소스/파서 처리 비용 지표 기록 (테넌트는 tenant 라벨)
+func WriteSourceUsageMetrics(builder *strings.Builder, scopes []TenantSourceUsage) {
+	builder.WriteString("# HELP syslog_monitor_source_input_lines_total Lines read per log source, including lines dropped by filters.\n")
+	builder.WriteString("# TYPE syslog_monitor_source_input_lines_total counter\n")
+	for _, scope := range scopes {
+		for _, source := range scope.Stats.Sources {
+			fmt.Fprintf(builder, "syslog_monitor_source_input_lines_total%s %d\n", prometheusLabels(scope.Tenant, "source", source.Source), source.Lines)
+		}
+	}
+
+	builder.WriteString("# HELP syslog_monitor_source_input_bytes_total Bytes read per log source, including lines dropped by filters.\n")
+	builder.WriteString("# TYPE syslog_monitor_source_input_bytes_total counter\n")
+	for _, scope := range scopes {
+		for _, source := range scope.Stats.Sources {
+			fmt.Fprintf(builder, "syslog_monitor_source_input_bytes_total%s %d\n", prometheusLabels(scope.Tenant, "source", source.Source), source.Bytes)
+		}
+	}
+
+	builder.WriteString("# HELP syslog_monitor_source_processing_seconds_total Wall-clock time spent processing each log source, by pipeline stage.\n")
+	builder.WriteString("# TYPE syslog_monitor_source_processing_seconds_total counter\n")
+	for _, scope := range scopes {
+		for _, source := range scope.Stats.Sources {
+			for _, stage := range usageStageNames {
+				fmt.Fprintf(builder, "syslog_monitor_source_processing_seconds_total%s %.6f\n", prometheusLabels(scope.Tenant, "source", source.Source, "stage", stage), source.StageSeconds[stage])
+			}
+		}
+	}
+
+	builder.WriteString("# HELP syslog_monitor_parser_bytes_total Bytes handled by each log format parser.\n")
+	builder.WriteString("# TYPE syslog_monitor_parser_bytes_total counter\n")
+	for _, scope := range scopes {
+		for _, parser := range scope.Stats.Parsers {
+			fmt.Fprintf(builder, "syslog_monitor_parser_bytes_total%s %d\n", prometheusLabels(scope.Tenant, "parser", parser.Parser), parser.Bytes)
+		}
+	}
+
+	builder.WriteString("# HELP syslog_monitor_parser_seconds_total Time spent in each log format parser (regular expressions and extraction rules).\n")
+	builder.WriteString("# TYPE syslog_monitor_parser_seconds_total counter\n")
+	for _, scope := range scopes {
+		for _, parser := range scope.Stats.Parsers {
+			fmt.Fprintf(builder, "syslog_monitor_parser_seconds_total%s %.6f\n", prometheusLabels(scope.Tenant, "parser", parser.Parser), parser.ParseSeconds)
+		}
+	}
+}
@@ -0,0 +1,515 @@
+/*
+SQL Injection Correlation Module
+================================
+
+웹 로그의 SQL 인젝션 탐지를 DB 로그로 확인하는 상관 분석 (-sqli-confirm)
+
+주요 기능:
+- 웹 접근 로그(Apache/Nginx)에서 SQL_Injection_Attempt 패턴이 일치하면 클라이언트 IP 와 요청 페이로드 기록
+- DB 로그(MySQL/PostgreSQL)의 SQL 문법 오류와 비정상 쿼리 지문(fingerprint) 기록
+- 같은 윈도우(기본 2분) 안에서 같은 클라이언트의 웹 시도와 DB 증거가 모두 있으면 확인된 SQL 인젝션으로 판정
+- 웹 로그는 요청이 끝난 뒤 기록되므로 DB 증거가 먼저 와도 상관 분석
+- 같은 클라이언트의 확인 알림은 윈도우마다 한 번 (나머지는 다음 알림에 횟수로 표시)
+
+판정 기준:
+  - DB 증거: 문법 오류 (MySQL 1064 "error in your SQL syntax", PostgreSQL "syntax error at or near" 등)
+    또는 리터럴을 ? 로 바꾼 쿼리 지문에 인젝션 흔적 (OR ?=? 항상 참 조건, UNION SELECT, 끝 주석, 연속 문장,
+    SLEEP/BENCHMARK/pg_sleep, information_schema 조회, 짝이 맞지 않는 따옴표)
+  - 같은 클라이언트: DB 로그의 접속 주소가 웹 클라이언트 IP 와 같거나, DB 문장/오류에 웹 요청의 페이로드가 포함됨
+  - 인젝션 흔적이 있는 지문이라도 자주 실행되는 쿼리(애플리케이션의 정상 쿼리)는 증거로 보지 않음
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"net"     // 클라이언트 IP 검증
+	"net/url" // 요청 페이로드 디코딩
+	"os"      // 호스트 이름
+	"regexp"  // 오류/문장/지문 패턴
+	"sort"    // 흔적 정렬
+	"strings" // 문자열 처리
+	"sync"    // 처리 고루틴과 파이프라인 동시 사용 보호
+	"time"    // 상관 분석 윈도우
+)
+
+// SQLInjectionPatternName 웹 로그 시도로 보는 이상 패턴 이름 (규칙 파일에서 같은 이름으로 대체 가능)
+const SQLInjectionPatternName = "SQL_Injection_Attempt"
+
+// SQL 인젝션 DB 증거 종류
+const (
+	SQLIEvidenceSyntaxError = "syntax_error"
+	SQLIEvidenceFingerprint = "anomalous_fingerprint"
+)
+
+// SQL 인젝션 상관 분석 기본값
+const (
+	DefaultSQLInjectionWindow = 120 // 웹 시도와 DB 증거를 묶는 윈도우 (초)
+	sqliBaselineRepeats       = 20  // 이보다 자주 실행된 지문은 정상 쿼리로 간주
+	sqliStatementContextDelay = 5 * time.Second
+	sqliMinPayloadLength      = 4
+	sqliMinFragmentLength     = 8 // PostgreSQL "at or near" 의 단어 하나는 링크 근거로 쓰지 않음
+	maxSQLIRecords            = 500
+	maxSQLFingerprints        = 10000
+)
+
+var (
+	sqliSyntaxErrorRegex = regexp.MustCompile(`(?i)you have an error in your sql syntax|\bERROR 1064\b|syntax error at or near|syntax error at end of input|unterminated quoted string|unclosed quotation mark|SQLSTATE\[?(?:42000|42601)`)
+	sqliStatementRegex   = regexp.MustCompile(`(?:\b(?:statement|STATEMENT):\s*|\bQuery\s+|"sqltext"\s*:\s*")(.+)`)
+	sqliNearRegex        = regexp.MustCompile(`(?i)\bnear '(.+)' at line \d+|at or near "(.+)"`)
+	sqliMySQLHostRegex   = regexp.MustCompile(`@\s*\S*\s*\[([0-9A-Fa-f.:]+)\]|'@'([0-9A-Fa-f.:]+)'`)
+
+	sqlStringLiteralRegex = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'`)
+	sqlNumberRegex        = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	sqlInListRegex        = regexp.MustCompile(`\bin\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	sqlOperatorRegex      = regexp.MustCompile(`\s*(<>|!=|<=|>=|=)\s*`)
+	sqlSpaceRegex         = regexp.MustCompile(`\s+`)
+
+	sqliPayloadHintRegex = regexp.MustCompile(`(?i)['";#]|--|/\*|\b(?:or|and|union|select|sleep)\b`)
+)
+
+// sqlFingerprintMarkers 쿼리 지문의 인젝션 흔적
+var sqlFingerprintMarkers = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"tautology", regexp.MustCompile(`\bor\s+(?:\? = \?|\?\s*$|\?\s+(?:--|#)|true\b|\? like \?)`)},
+	{"union_select", regexp.MustCompile(`\bunion\s+(?:all\s+)?select\b`)},
+	{"trailing_comment", regexp.MustCompile(`(?:--|#)[^'\n]*$`)},
+	{"stacked_query", regexp.MustCompile(`;\s*\S`)},
+	{"time_delay", regexp.MustCompile(`\b(?:sleep|benchmark|pg_sleep)\s*\(|\bwaitfor\s+delay\b`)},
+	{"schema_probe", regexp.MustCompile(`\binformation_schema\b|\bpg_catalog\b|\bmysql\.user\b|\bpg_shadow\b`)},
+}
+
+// SQLInjectionAttempt 웹 로그의 SQL 인젝션 시도
+type SQLInjectionAttempt struct {
+	At        time.Time
+	Client    string
+	Method    string
+	URL       string
+	Status    int
+	Line      string
+	payloads  []string // 디코딩 후 소문자/공백 정리한 의심 값
+	confirmed bool
+}
+
+// SQLInjectionEvidence DB 로그의 인젝션 증거 (문법 오류 또는 비정상 쿼리 지문)
+type SQLInjectionEvidence struct {
+	At          time.Time
+	Kind        string   // syntax_error, anomalous_fingerprint
+	Engine      string   // mysql, postgresql, database
+	Client      string   // DB 접속 주소 (알 수 있는 경우)
+	Message     string   // 문법 오류 메시지
+	Query       string   // 비밀번호를 가린 문장
+	Fingerprint string   // 리터럴을 ? 로 바꾼 쿼리 지문
+	Markers     []string // 지문의 인젝션 흔적
+	Line        string
+	normalized  string // 링크 비교용 (소문자/공백 정리)
+	fragment    string // 오류 메시지의 near '...' 조각
+	used        bool
+}
+
+// SQLInjectionConfirmation 웹 시도와 DB 증거가 묶인 확인 결과
+type SQLInjectionConfirmation struct {
+	Attempt     SQLInjectionAttempt
+	Evidence    SQLInjectionEvidence
+	Links       []string      // client (접속 주소 일치), payload (페이로드 포함)
+	Delay       time.Duration // 웹 시도와 DB 증거 사이 시간
+	Suppressed  int           // 이전 알림 이후 알리지 않은 확인 수
+	ShouldAlert bool          // 클라이언트별 알림 간격 통과 여부
+}
+
+// SQLInjectionCorrelator 웹 SQL 인젝션 시도와 DB 증거 상관 분석기
+// DB 증거는 처리 고루틴에서, 웹 시도는 파이프라인의 알림 단계에서 기록하므로 뮤텍스로 보호
+type SQLInjectionCorrelator struct {
+	mutex        sync.Mutex
+	window       time.Duration
+	attempts     []*SQLInjectionAttempt  // 윈도우 내 웹 시도 (오래된 순)
+	evidence     []*SQLInjectionEvidence // 윈도우 내 DB 증거 (오래된 순)
+	fingerprints map[string]int          // 쿼리 지문별 실행 횟수 (정상 쿼리 판단)
+	lastSyntax   *SQLInjectionEvidence   // PostgreSQL 오류 뒤 STATEMENT: 줄과 묶을 직전 문법 오류
+	alerts       map[string]time.Time    // 클라이언트별 마지막 확인 알림
+	suppressed   map[string]int
+}
+
+// NewSQLInjectionCorrelator 윈도우(초)로 상관 분석기 생성 (0 이하면 기본값)
+func NewSQLInjectionCorrelator(windowSeconds int) *SQLInjectionCorrelator {
+	if windowSeconds <= 0 {
+		windowSeconds = DefaultSQLInjectionWindow
+	}
+	return &SQLInjectionCorrelator{
+		window:       time.Duration(windowSeconds) * time.Second,
+		fingerprints: make(map[string]int),
+		alerts:       make(map[string]time.Time),
+		suppressed:   make(map[string]int),
+	}
+}
+
+// Window 상관 분석 윈도우
+func (c *SQLInjectionCorrelator) Window() time.Duration {
+	return c.window
+}
+
+// RecordAttempt 웹 접근 로그의 SQL 인젝션 시도 기록 후 윈도우 내 DB 증거와 대조
+func (c *SQLInjectionCorrelator) RecordAttempt(parsedLog *ParsedLog, line string, at time.Time) *SQLInjectionConfirmation {
+	if parsedLog == nil || parsedLog.HTTPDetails == nil || parsedLog.HTTPDetails.ClientIP == "" {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.prune(at)
+
+	details := parsedLog.HTTPDetails
+	attempt := &SQLInjectionAttempt{
+		At:       at,
+		Client:   details.ClientIP,
+		Method:   details.Method,
+		URL:      details.URL,
+		Status:   details.StatusCode,
+		Line:     line,
+		payloads: sqliPayloads(details.URL),
+	}
+	c.attempts = append(c.attempts, attempt)
+	if len(c.attempts) > maxSQLIRecords {
+		c.attempts = c.attempts[len(c.attempts)-maxSQLIRecords:]
+	}
+
+	// 가장 최근 증거부터 대조
+	for i := len(c.evidence) - 1; i >= 0; i-- {
+		if evidence := c.evidence[i]; !evidence.used {
+			if links := sqliLinks(attempt, evidence); len(links) > 0 {
+				return c.confirm(attempt, evidence, links, at)
+			}
+		}
+	}
+	return nil
+}
+
+// ObserveDBLine DB 로그 한 줄에서 증거를 찾아 기록 후 윈도우 내 웹 시도와 대조
+func (c *SQLInjectionCorrelator) ObserveDBLine(line string, at time.Time) *SQLInjectionConfirmation {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	evidence := c.extractEvidence(line, at)
+	if evidence == nil {
+		return nil
+	}
+	c.prune(at)
+	c.evidence = append(c.evidence, evidence)
+	if len(c.evidence) > maxSQLIRecords {
+		c.evidence = c.evidence[len(c.evidence)-maxSQLIRecords:]
+	}
+
+	for i := len(c.attempts) - 1; i >= 0; i-- {
+		if attempt := c.attempts[i]; !attempt.confirmed {
+			if links := sqliLinks(attempt, evidence); len(links) > 0 {
+				return c.confirm(attempt, evidence, links, at)
+			}
+		}
+	}
+	return nil
+}
+
+// extractEvidence 문법 오류 또는 인젝션 흔적이 있는 쿼리 지문 추출 (증거가 아니면 nil)
+func (c *SQLInjectionCorrelator) extractEvidence(line string, at time.Time) *SQLInjectionEvidence {
+	syntaxError := sqliSyntaxErrorRegex.MatchString(line)
+	query := sqliStatement(line)
+	if !syntaxError && query == "" {
+		return nil
+	}
+
+	evidence := &SQLInjectionEvidence{
+		At:         at,
+		Engine:     dbEngine(line),
+		Client:     sqliDBClient(line),
+		Line:       maskDBPasswords(line),
+		normalized: normalizeSQLIText(line),
+	}
+
+	switch {
+	case syntaxError:
+		evidence.Kind = SQLIEvidenceSyntaxError
+		evidence.Message = sqliSyntaxErrorRegex.FindString(line)
+		if matches := sqliNearRegex.FindStringSubmatch(line); matches != nil {
+			evidence.fragment = strings.Trim(normalizeSQLIText(matches[1]+matches[2]), "' ")
+		}
+		c.lastSyntax = evidence
+
+	case strings.Contains(line, "STATEMENT:") && c.lastSyntax != nil && at.Sub(c.lastSyntax.At) <= sqliStatementContextDelay:
+		// PostgreSQL 은 오류 줄 다음 STATEMENT: 줄에 실패한 문장을 기록
+		previous := c.lastSyntax
+		c.lastSyntax = nil
+		evidence.Kind = SQLIEvidenceSyntaxError
+		evidence.Message = previous.Message
+		evidence.fragment = previous.fragment
+		if evidence.Client == "" {
+			evidence.Client = previous.Client
+		}
+		evidence.Line = previous.Line + "\n" + evidence.Line
+
+	default:
+		// 자주 실행되는 지문은 애플리케이션의 정상 쿼리
+		fingerprint := sqlFingerprint(query)
+		count := c.fingerprints[fingerprint]
+		if count > 0 || len(c.fingerprints) < maxSQLFingerprints {
+			c.fingerprints[fingerprint] = count + 1
+		}
+		if count >= sqliBaselineRepeats {
+			return nil
+		}
+		if evidence.Markers = sqlFingerprintAnomalies(fingerprint); len(evidence.Markers) == 0 {
+			return nil
+		}
+		evidence.Kind = SQLIEvidenceFingerprint
+	}
+
+	if query != "" {
+		evidence.Query = maskDBPasswords(query)
+		if len(evidence.Query) > MaxDBStatementLength {
+			evidence.Query = evidence.Query[:MaxDBStatementLength] + "..."
+		}
+		evidence.Fingerprint = sqlFingerprint(query)
+		if evidence.Markers == nil {
+			evidence.Markers = sqlFingerprintAnomalies(evidence.Fingerprint)
+		}
+	}
+	return evidence
+}
+
+// sqliStatement DB 로그 줄의 SQL 문장 (statement:/STATEMENT:, MySQL general log Query, audit sqltext)
+func sqliStatement(line string) string {
+	matches := sqliStatementRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return ""
+	}
+	query := strings.TrimSpace(matches[1])
+	if strings.Contains(matches[0], "sqltext") {
+		// audit JSON 의 다음 필드 제거
+		query = strings.TrimSuffix(strings.SplitN(query, `",`, 2)[0], `"`)
+	}
+	return query
+}
+
+// confirm 시도와 증거를 확인 처리하고 클라이언트별 알림 간격 적용
+func (c *SQLInjectionCorrelator) confirm(attempt *SQLInjectionAttempt, evidence *SQLInjectionEvidence, links []string, now time.Time) *SQLInjectionConfirmation {
+	attempt.confirmed = true
+	evidence.used = true
+
+	delay := attempt.At.Sub(evidence.At)
+	if delay < 0 {
+		delay = -delay
+	}
+	confirmation := &SQLInjectionConfirmation{
+		Attempt:  *attempt,
+		Evidence: *evidence,
+		Links:    links,
+		Delay:    delay,
+	}
+
+	if last, ok := c.alerts[attempt.Client]; ok && now.Sub(last) < c.window {
+		c.suppressed[attempt.Client]++
+		return confirmation
+	}
+	c.alerts[attempt.Client] = now
+	confirmation.Suppressed = c.suppressed[attempt.Client]
+	delete(c.suppressed, attempt.Client)
+	confirmation.ShouldAlert = true
+	return confirmation
+}
+
+// prune 윈도우 밖의 시도/증거와 오래된 알림 기록 제거
+func (c *SQLInjectionCorrelator) prune(now time.Time) {
+	cutoff := now.Add(-c.window)
+	attempts := c.attempts[:0]
+	for _, attempt := range c.attempts {
+		if attempt.At.After(cutoff) {
+			attempts = append(attempts, attempt)
+		}
+	}
+	c.attempts = attempts
+
+	evidence := c.evidence[:0]
+	for _, item := range c.evidence {
+		if item.At.After(cutoff) {
+			evidence = append(evidence, item)
+		}
+	}
+	c.evidence = evidence
+
+	for client, last := range c.alerts {
+		if last.Before(cutoff) && c.suppressed[client] == 0 {
+			delete(c.alerts, client)
+		}
+	}
+}
+
+// hasMatchedRule AI 분석 결과에 이름이 같은 이상 패턴 규칙이 있는지 확인
+func hasMatchedRule(result *AIAnalysisResult, name string) bool {
+	if result == nil {
+		return false
+	}
+	for _, rule := range result.MatchedRules {
+		if rule.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sqliLinks 시도와 증거가 같은 클라이언트인지 확인 (접속 주소 일치, 페이로드 포함)
+func sqliLinks(attempt *SQLInjectionAttempt, evidence *SQLInjectionEvidence) []string {
+	var links []string
+	if evidence.Client != "" && evidence.Client == attempt.Client {
+		links = append(links, "client")
+	}
+	for _, payload := range attempt.payloads {
+		if strings.Contains(evidence.normalized, payload) ||
+			(len(evidence.fragment) >= sqliMinFragmentLength && strings.Contains(payload, evidence.fragment)) {
+			links = append(links, "payload")
+			break
+		}
+	}
+	return links
+}
+
+// sqliPayloads 요청 URL 의 쿼리 값과 마지막 경로에서 SQL 메타 문자가 있는 값 추출
+func sqliPayloads(rawURL string) []string {
+	path, query := rawURL, ""
+	if index := strings.Index(rawURL, "?"); index >= 0 {
+		path, query = rawURL[:index], rawURL[index+1:]
+	}
+
+	candidates := []string{path[strings.LastIndex(path, "/")+1:]}
+	for _, part := range strings.Split(query, "&") {
+		if index := strings.Index(part, "="); index >= 0 {
+			part = part[index+1:]
+		}
+		candidates = append(candidates, part)
+	}
+
+	var payloads []string
+	for _, candidate := range candidates {
+		if decoded, err := url.QueryUnescape(candidate); err == nil {
+			candidate = decoded
+		}
+		candidate = normalizeSQLIText(candidate)
+		if len(candidate) >= sqliMinPayloadLength && sqliPayloadHintRegex.MatchString(candidate) {
+			payloads = append(payloads, candidate)
+		}
+	}
+	return payloads
+}
+
+// sqliDBClient DB 로그의 접속 주소 (PostgreSQL host=, MySQL User@Host [ip], 'user'@'ip')
+func sqliDBClient(line string) string {
+	var candidate string
+	if matches := dbPostgresHostField.FindStringSubmatch(line); matches != nil {
+		candidate = matches[1]
+	} else if matches := sqliMySQLHostRegex.FindStringSubmatch(line); matches != nil {
+		candidate = matches[1] + matches[2]
+	}
+	if net.ParseIP(candidate) == nil {
+		return ""
+	}
+	return candidate
+}
+
+// normalizeSQLIText 비교용 소문자/공백 정리 (MySQL 이스케이프 \' 는 ' 로)
+func normalizeSQLIText(text string) string {
+	text = strings.ReplaceAll(strings.ToLower(text), `\'`, `'`)
+	return strings.TrimSpace(sqlSpaceRegex.ReplaceAllString(text, " "))
+}
+
+// sqlFingerprint 문자열/숫자 리터럴을 ? 로 바꾸고 IN 목록과 공백을 정리한 쿼리 지문
+func sqlFingerprint(query string) string {
+	fingerprint := strings.ToLower(strings.TrimSpace(query))
+	fingerprint = sqlStringLiteralRegex.ReplaceAllString(fingerprint, "?")
+	fingerprint = sqlNumberRegex.ReplaceAllString(fingerprint, "?")
+	fingerprint = sqlOperatorRegex.ReplaceAllString(fingerprint, " $1 ")
+	fingerprint = sqlSpaceRegex.ReplaceAllString(fingerprint, " ")
+	fingerprint = sqlInListRegex.ReplaceAllString(fingerprint, "in (?+)")
+	return strings.TrimSuffix(strings.TrimSpace(fingerprint), ";")
+}
+
+// sqlFingerprintAnomalies 지문의 인젝션 흔적 (짝이 맞지 않는 따옴표 포함)
+func sqlFingerprintAnomalies(fingerprint string) []string {
+	var markers []string
+	for _, marker := range sqlFingerprintMarkers {
+		if marker.pattern.MatchString(fingerprint) {
+			markers = append(markers, marker.name)
+		}
+	}
+	if strings.Count(fingerprint, "'")%2 == 1 {
+		markers = append(markers, "unbalanced_quote")
+	}
+	sort.Strings(markers)
+	return markers
+}
+
+// sqlInjectionConfirmedAlert 웹 시도와 DB 증거가 묶인 확인 알림
+// 웹 로그와 DB 로그의 호스트 형식이 서로 달라 모니터 호스트 이름 사용
+func sqlInjectionConfirmedAlert(confirmation *SQLInjectionConfirmation) Alert {
+	attempt, evidence := confirmation.Attempt, confirmation.Evidence
+	host, _ := os.Hostname()
+
+	proof := "SQL 문법 오류"
+	if evidence.Kind == SQLIEvidenceFingerprint {
+		proof = "비정상 쿼리 지문"
+	}
+	linkLabels := map[string]string{"client": "DB 접속 주소 일치", "payload": "DB 문장에 요청 페이로드 포함"}
+	var links []string
+	for _, link := range confirmation.Links {
+		links = append(links, linkLabels[link])
+	}
+
+	fields := []AlertField{
+		{Label: "클라이언트", Value: attempt.Client, Short: true},
+		{Label: "DB", Value: evidence.Engine, Short: true},
+		{Label: "DB 증거", Value: proof, Short: true},
+		{Label: "시간 차", Value: confirmation.Delay.Round(time.Millisecond).String(), Short: true},
+		{Label: "연결 근거", Value: strings.Join(links, ", ")},
+		{Label: "요청", Value: fmt.Sprintf("%s %s (HTTP %d)", attempt.Method, attempt.URL, attempt.Status), Code: true},
+	}
+	if confirmation.Suppressed > 0 {
+		fields = append(fields, AlertField{Label: "직전 알림 이후 추가 확인", Value: fmt.Sprintf("%d회", confirmation.Suppressed)})
+	}
+
+	dbFields := []AlertField{}
+	if evidence.Message != "" {
+		dbFields = append(dbFields, AlertField{Label: "오류", Value: evidence.Message, Short: true})
+	}
+	if len(evidence.Markers) > 0 {
+		dbFields = append(dbFields, AlertField{Label: "인젝션 흔적", Value: strings.Join(evidence.Markers, ", "), Short: true})
+	}
+	if evidence.Query != "" {
+		dbFields = append(dbFields,
+			AlertField{Label: "SQL", Value: evidence.Query, Code: true},
+			AlertField{Label: "쿼리 지문", Value: evidence.Fingerprint, Code: true},
+		)
+	}
+	dbFields = append(dbFields, AlertField{Label: "원본 로그", Value: evidence.Line, Code: true})
+
+	return Alert{
+		Type:     AlertTypeSQLInjection,
+		Severity: AlertSeverityCritical,
+		Title:    fmt.Sprintf("[%s SQLI CONFIRMED] %s - %s (%s)", AppName, host, attempt.Client, evidence.Kind),
+		Headline: fmt.Sprintf("🚨 %s 의 SQL 인젝션이 DB 로그에서 확인되었습니다 (%s)", attempt.Client, proof),
+		Sections: []AlertSection{
+			{Fields: fields, Summary: true},
+			{
+				Title:  "🌐 웹 요청",
+				Fields: []AlertField{{Label: "원본 로그", Value: attempt.Line, Code: true}},
+			},
+			{Title: "🗄️ DB 로그", Fields: dbFields},
+		},
+		Host:   host,
+		Thread: alertThreadKey(AlertTypeSQLInjection, host, attempt.Client),
+		Fields: map[string]string{
+			"client":      attempt.Client,
+			"url":         attempt.URL,
+			"engine":      evidence.Engine,
+			"evidence":    evidence.Kind,
+			"fingerprint": evidence.Fingerprint,
+			"links":       strings.Join(confirmation.Links, ","),
+			"confidence":  "high",
+		},
+	}
+}
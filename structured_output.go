@@ -0,0 +1,246 @@
+/*
+Structured Output Module
+========================
+
+필터를 통과한 로그 라인의 기계 판독용 출력 (-output-format)
+
+주요 기능:
+- text: 기존 logrus 텍스트 출력 (기본값)
+- ndjson: 라인마다 JSON 객체 한 줄 (출력 파일은 이어쓰기, -output-max-size 로 교체, jq/Vector/Fluent Bit 등에서 바로 소비)
+- json: 전체 출력이 하나의 JSON 배열 (출력 파일은 새로 작성, 종료 시 배열 닫음)
+- 레코드에 파싱 필드(호스트, 서비스, 레벨), AI 분석 점수, 로그인 정보 포함
+
+구조화 출력 모드에서는 레코드가 -output 파일(없으면 stdout)로 출력되고,
+운영 로그(logrus)는 레코드와 섞이지 않도록 stderr 로 출력됩니다.
+*/
+package main
+
+import (
+	"encoding/json" // JSON 인코딩
+	"fmt"           // 형식화된 I/O
+	"io"            // 출력 인터페이스
+	"os"            // 파일 처리
+	"strings"       // 문자열 처리
+	"sync"          // 동기화 (뮤텍스)
+	"time"          // 시간 처리
+)
+
+// 출력 형식
+const (
+	OutputFormatText   = "text"
+	OutputFormatJSON   = "json"
+	OutputFormatNDJSON = "ndjson"
+)
+
+// LogRecord 구조화 출력 레코드 (필터를 통과한 로그 라인 1개)
+type LogRecord struct {
+	Timestamp time.Time         `json:"timestamp"` // 처리 시각
+	Host      string            `json:"host,omitempty"`
+	Service   string            `json:"service,omitempty"`
+	Unit      string            `json:"unit,omitempty"` // systemd 유닛 (journald 입력)
+	Level     string            `json:"level"`          // INFO, WARNING, ERROR, CRITICAL
+	Message   string            `json:"message"`
+	Raw       string            `json:"raw"`
+	LogType   string            `json:"log_type,omitempty"` // 고급 파서가 판별한 로그 형식 (apache, nginx 등)
+	Fields    map[string]string `json:"fields,omitempty"`   // 고급 파서가 추출한 필드
+	AI        *LogRecordAI      `json:"ai,omitempty"`
+	Login     *LogRecordLogin   `json:"login,omitempty"`
+}
+
+// LogRecordAI 레코드의 AI 분석 결과
+type LogRecordAI struct {
+	AnomalyScore float64  `json:"anomaly_score"`
+	ThreatLevel  string   `json:"threat_level"`
+	Confidence   float64  `json:"confidence"`
+	RuleScore    *float64 `json:"rule_score,omitempty"`  // ONNX 모델과 결합하기 전 규칙 기반 점수 (-onnx-model)
+	ModelScore   *float64 `json:"model_score,omitempty"` // ONNX 모델 점수 (-onnx-model)
+}
+
+// LogRecordLogin 레코드의 로그인 감지 정보
+type LogRecordLogin struct {
+	Status         string `json:"status"`
+	User           string `json:"user"`
+	IP             string `json:"ip,omitempty"`
+	Hostname       string `json:"hostname,omitempty"` // 출처 IP 의 PTR 호스트 이름
+	Method         string `json:"method,omitempty"`
+	Command        string `json:"command,omitempty"`
+	Success        bool   `json:"success"`
+	Country        string `json:"country,omitempty"`
+	Threat         string `json:"threat,omitempty"`
+	Anonymizer     string `json:"anonymizer,omitempty"`  // tor, vpn, proxy
+	AbuseScore     int    `json:"abuse_score,omitempty"` // AbuseIPDB 신뢰 점수
+	Blocklist      string `json:"blocklist,omitempty"`   // 일치한 위협 블록리스트
+	TrustedNetwork string `json:"trusted_network,omitempty"`
+	ShouldAlert    bool   `json:"should_alert"` // 알림 간격 제한 통과 여부
+
+	Enrichment map[string]string `json:"enrichment,omitempty"` // 조회 테이블 보강 필드
+}
+
+// ParseOutputFormat 출력 형식 문자열 검증 (빈 값은 text)
+func ParseOutputFormat(value string) (string, error) {
+	switch format := strings.ToLower(strings.TrimSpace(value)); format {
+	case "", OutputFormatText:
+		return OutputFormatText, nil
+	case OutputFormatJSON, OutputFormatNDJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported output format %q (use text, json or ndjson)", value)
+	}
+}
+
+// StructuredWriter json/ndjson 레코드 출력기
+type StructuredWriter struct {
+	format string
+	out    io.Writer
+	file   io.Closer // 출력 파일 (stdout 출력이면 nil)
+	count  int       // 출력한 레코드 수 (json 배열 구분자 처리용)
+	closed bool
+	mutex  sync.Mutex
+}
+
+// NewStructuredWriter 구조화 출력기 생성 (path가 비어 있으면 stdout 으로 출력)
+// ndjson 은 기존 파일에 이어쓰고, json 은 하나의 배열이 되도록 파일을 새로 작성
+func NewStructuredWriter(format, path string, stdout io.Writer) (*StructuredWriter, error) {
+	writer := &StructuredWriter{format: format, out: stdout}
+	if path == "" {
+		return writer, nil
+	}
+
+	// ndjson 은 -output 파일과 같은 기준으로 교체, json 은 하나의 배열이므로 교체하지 않음
+	if format == OutputFormatNDJSON {
+		file, err := OpenOutputFile(path, outputFileOptions)
+		if err != nil {
+			return nil, err
+		}
+		writer.out = file
+		writer.file = file
+		return writer, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, outputFileOptions.Mode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open output file: %v", err)
+	}
+	file.Chmod(outputFileOptions.Mode)
+	writer.out = file
+	writer.file = file
+	return writer, nil
+}
+
+// Write 레코드 1개 출력
+func (sw *StructuredWriter) Write(record *LogRecord) error {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+
+	if sw.closed {
+		return nil
+	}
+
+	var data []byte
+	var err error
+	if sw.format == OutputFormatJSON {
+		data, err = json.MarshalIndent(record, "  ", "  ")
+	} else {
+		data, err = json.Marshal(record)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode log record: %v", err)
+	}
+
+	var prefix string
+	if sw.format == OutputFormatJSON {
+		prefix = ",\n  "
+		if sw.count == 0 {
+			prefix = "[\n  "
+		}
+	}
+	sw.count++
+
+	if _, err := fmt.Fprintf(sw.out, "%s%s", prefix, data); err != nil {
+		return fmt.Errorf("failed to write log record: %v", err)
+	}
+	if sw.format == OutputFormatNDJSON {
+		_, err = io.WriteString(sw.out, "\n")
+	}
+	return err
+}
+
+// Close json 배열을 닫고 출력 파일 정리
+func (sw *StructuredWriter) Close() error {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+
+	if sw.closed {
+		return nil
+	}
+	sw.closed = true
+
+	if sw.format == OutputFormatJSON {
+		if sw.count == 0 {
+			io.WriteString(sw.out, "[]\n")
+		} else {
+			io.WriteString(sw.out, "\n]\n")
+		}
+	}
+	if sw.file != nil {
+		return sw.file.Close()
+	}
+	return nil
+}
+
+// newLogRecord 파싱/분석 결과로 구조화 출력 레코드 생성
+func newLogRecord(parsed map[string]string, level string, parsedLog *ParsedLog, aiResult *AIAnalysisResult, loginInfo *LoginInfo) *LogRecord {
+	record := &LogRecord{
+		Timestamp: time.Now(),
+		Host:      parsed["host"],
+		Service:   strings.TrimSuffix(parsed["service"], ":"),
+		Unit:      parsed["unit"],
+		Level:     level,
+		Message:   parsed["message"],
+		Raw:       parsed["raw"],
+	}
+
+	if parsedLog != nil {
+		record.LogType = parsedLog.LogType
+		if len(parsedLog.Fields) > 0 {
+			record.Fields = parsedLog.Fields
+		}
+		if record.Message == "" {
+			record.Message = parsedLog.Message
+		}
+	}
+
+	if aiResult != nil {
+		record.AI = &LogRecordAI{
+			AnomalyScore: aiResult.AnomalyScore,
+			ThreatLevel:  aiResult.ThreatLevel,
+			Confidence:   aiResult.Confidence,
+		}
+		if aiResult.Model != "" {
+			record.AI.RuleScore, record.AI.ModelScore = &aiResult.RuleScore, &aiResult.ModelScore
+		}
+	}
+
+	if loginInfo != nil {
+		record.Login = &LogRecordLogin{
+			Status:         loginInfo.Status,
+			User:           loginInfo.User,
+			IP:             loginInfo.IP,
+			Hostname:       loginInfo.ReverseDNS.Hostname,
+			Method:         loginInfo.Method,
+			Command:        loginInfo.Command,
+			Success:        loginInfo.Success,
+			TrustedNetwork: loginInfo.TrustedNetwork,
+			ShouldAlert:    loginInfo.ShouldAlert,
+			Enrichment:     loginInfo.Enrichment,
+		}
+		if loginInfo.IPDetails != nil {
+			record.Login.Country = loginInfo.IPDetails.Country
+			record.Login.Threat = loginInfo.IPDetails.Threat
+			record.Login.Anonymizer = loginInfo.IPDetails.Anonymizer
+			record.Login.AbuseScore = loginInfo.IPDetails.AbuseScore
+			record.Login.Blocklist = loginInfo.IPDetails.Blocklist
+		}
+	}
+
+	return record
+}
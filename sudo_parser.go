@@ -0,0 +1,219 @@
+/*
+Sudo/Su Log Parsing Module
+==========================
+
+sudo 및 su 로그의 전체 형식 파싱과 명령어 정책 검사
+
+주요 기능:
+- sudo 로그 전체 형식 파싱 (실행 사용자, runas 사용자, TTY, 작업 디렉토리, 전체 명령어)
+- sudo 거부 이벤트 감지 (비밀번호 오류, sudoers 미등록, 허용되지 않은 명령)
+- su / su - / sudo -i / sudo -s 등 대화형 루트 셸 진입 감지
+- 위험 명령 패턴(deny-pattern) 정책 위반 감지
+- 알림에 노출되는 명령어의 제어 문자 제거 및 길이 제한
+
+지원 로그 형식:
+- sudo:    alice : TTY=pts/0 ; PWD=/home/alice ; USER=root ; COMMAND=/usr/bin/apt update
+- sudo:    alice : 3 incorrect password attempts ; TTY=pts/0 ; PWD=/home/alice ; USER=root ; COMMAND=/bin/ls
+- su: (to root) alice on pts/0
+- su[1234]: Successful su for root by alice / FAILED su for root by alice
+- su: pam_unix(su-l:session): session opened for user root(uid=0) by alice(uid=1000)
+*/
+package main
+
+import (
+	"fmt"           // 형식화된 I/O
+	"path/filepath" // 명령어 경로 처리
+	"regexp"        // 정규식
+	"strings"       // 문자열 처리
+	"unicode"       // 제어 문자 판별
+)
+
+// maxSudoCommandLength 알림에 표시할 명령어 최대 길이
+const maxSudoCommandLength = 512
+
+// DefaultSudoDenyPatterns 기본 위험 명령 패턴 (설정 파일 login.sudo_deny_patterns 로 변경 가능)
+var DefaultSudoDenyPatterns = []string{
+	`(curl|wget)\b[^|;]*\|\s*(sudo\s+)?(ba|z|da|k)?sh\b`, // 원격 스크립트 파이프 실행 (curl ... | bash)
+	`(^|[\s/;|&])(nc|ncat|netcat)(\s|$)`,                 // netcat 리버스 셸/데이터 유출
+	`base64\s+(-d|--decode|-D)\b[^|;]*\|`,                // base64 디코드 후 파이프 실행
+	`/dev/tcp/`,                                          // bash 리버스 셸
+	`chmod\s+[0-7]*[4-7][0-7]{3}\s`,                      // setuid/setgid 비트 설정
+}
+
+// interactiveShells 대화형 셸로 간주하는 실행 파일
+var interactiveShells = map[string]bool{
+	"sh": true, "bash": true, "zsh": true, "fish": true, "ksh": true,
+	"csh": true, "tcsh": true, "dash": true, "su": true,
+}
+
+// 로그 형식별 정규식 (패키지 초기화 시 1회 컴파일)
+var (
+	sudoLinePattern    = regexp.MustCompile(`sudo(?:\[\d+\])?:\s+(\S+)\s+:\s+(.*)$`)
+	suToPattern        = regexp.MustCompile(`su(?:\[\d+\])?:\s+(FAILED SU )?\(to (\S+)\) (\S+) on (\S+)`)
+	suResultPattern    = regexp.MustCompile(`(Successful|FAILED) su for (\S+) by (\S+)`)
+	suPamOpenedPattern = regexp.MustCompile(`pam_unix\((su(?:-l)?):session\): session opened for user (\w[\w.-]*)(?:\(uid=\d+\))? by (\w[\w.-]*)?`)
+)
+
+// SudoEntry 파싱된 sudo/su 이벤트
+type SudoEntry struct {
+	User    string // 명령을 실행한 사용자
+	RunAs   string // 대상 사용자 (USER=)
+	TTY     string
+	CWD     string
+	Command string // 전체 명령어 (정제됨)
+	Denied  bool   // sudo 거부 또는 su 실패
+	Reason  string // 거부 사유 (예: 3 incorrect password attempts)
+	Shell   bool   // 대화형 셸 진입 여부 (sudo -i, sudo -s, su -)
+	IsSu    bool
+}
+
+// parseSudoLine sudo/su 로그 라인 파싱 (해당 없으면 nil)
+func parseSudoLine(line string) *SudoEntry {
+	if matches := sudoLinePattern.FindStringSubmatch(line); matches != nil {
+		return parseSudoFields(matches[1], matches[2])
+	}
+
+	if matches := suToPattern.FindStringSubmatch(line); matches != nil {
+		return &SudoEntry{
+			User:   matches[3],
+			RunAs:  matches[2],
+			TTY:    matches[4],
+			Denied: matches[1] != "",
+			Shell:  true,
+			IsSu:   true,
+		}
+	}
+
+	if matches := suResultPattern.FindStringSubmatch(line); matches != nil {
+		entry := &SudoEntry{
+			User:   matches[3],
+			RunAs:  matches[2],
+			Denied: matches[1] == "FAILED",
+			Shell:  true,
+			IsSu:   true,
+		}
+		if entry.Denied {
+			entry.Reason = "su authentication failed"
+		}
+		return entry
+	}
+
+	if matches := suPamOpenedPattern.FindStringSubmatch(line); matches != nil {
+		entry := &SudoEntry{
+			User:  matches[3],
+			RunAs: matches[2],
+			Shell: true,
+			IsSu:  true,
+		}
+		if matches[1] == "su-l" {
+			entry.Command = "su -"
+		}
+		if entry.User == "" {
+			entry.User = "unknown"
+		}
+		return entry
+	}
+
+	return nil
+}
+
+// parseSudoFields "KEY=value ; KEY=value ; COMMAND=..." 형식 파싱
+// COMMAND 는 ' ; ' 를 포함할 수 있으므로 항상 마지막 필드로 처리
+func parseSudoFields(user, rest string) *SudoEntry {
+	entry := &SudoEntry{User: user}
+
+	if idx := strings.Index(rest, "COMMAND="); idx >= 0 {
+		entry.Command = sanitizeCommand(rest[idx+len("COMMAND="):])
+		rest = rest[:idx]
+	}
+
+	for _, field := range strings.Split(rest, ";") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			// KEY=value 형식이 아닌 첫 필드는 거부 사유
+			entry.Denied = true
+			entry.Reason = field
+			continue
+		}
+
+		switch kv[0] {
+		case "TTY":
+			entry.TTY = kv[1]
+		case "PWD":
+			entry.CWD = kv[1]
+		case "USER":
+			entry.RunAs = kv[1]
+		}
+	}
+
+	if entry.RunAs == "" {
+		entry.RunAs = "root"
+	}
+	entry.Shell = isInteractiveShell(entry.Command)
+	return entry
+}
+
+// isInteractiveShell 명령어가 대화형 셸 진입인지 확인 (sudo -i, sudo -s, sudo su -)
+func isInteractiveShell(command string) bool {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return false
+	}
+	if !interactiveShells[filepath.Base(fields[0])] {
+		return false
+	}
+	// -c 로 단일 명령만 실행하는 경우는 대화형 셸이 아님
+	for _, arg := range fields[1:] {
+		if arg == "-c" {
+			return false
+		}
+	}
+	return true
+}
+
+// sanitizeCommand 제어 문자 제거 및 길이 제한 (알림 메시지 변조 방지)
+func sanitizeCommand(command string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, strings.TrimSpace(command))
+
+	runes := []rune(cleaned)
+	if len(runes) > maxSudoCommandLength {
+		return string(runes[:maxSudoCommandLength]) + "…"
+	}
+	return cleaned
+}
+
+// compileDenyPatterns 위험 명령 패턴 컴파일
+func compileDenyPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sudo deny pattern %q: %v", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// matchDenyPattern 명령어가 위험 패턴에 해당하면 해당 패턴 반환
+func matchDenyPattern(command string, patterns []*regexp.Regexp) string {
+	if command == "" {
+		return ""
+	}
+	for _, re := range patterns {
+		if re.MatchString(command) {
+			return re.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,400 @@
+/*
+Syslog Forward Module
+=====================
+
+필터를 통과한 로그를 상위 syslog 서버로 전달 (-forward, 보강 릴레이 모드)
+
+각 로그를 RFC 5424 형식으로 다시 만들고 구조화 데이터(STRUCTURED-DATA)에 AI 이상 점수와
+파싱 필드를 담아 전송하므로, rsyslog/syslog-ng/Graylog 등 중앙 수집기에서 원본 로그와 함께
+syslog-monitor 의 분석 결과를 조회할 수 있습니다.
+
+주요 기능:
+- 전송 방식: udp://host[:514], tcp://host[:514], tls://host[:6514] (RFC 5426, RFC 6587, RFC 5425)
+- TCP/TLS 는 옥텟 카운팅 프레이밍 ("길이 SP 메시지"), UDP 는 메시지당 데이터그램 1개
+- 구조화 데이터: [syslogmonitor@32473 level= log_type= ai_score= threat= confidence= rules=] [fields@32473 필드=값 ...]
+- 백그라운드 전송 (큐가 가득 차면 버림), 연결 실패 시 재연결 간격을 두고 재시도
+- -forward-ca 로 사설 CA 인증서 지정 (TLS)
+
+32473 은 문서/예시용으로 예약된 IANA 사설 기업 번호(RFC 5612)입니다.
+*/
+package main
+
+import (
+	"crypto/tls"   // TLS 전송
+	"crypto/x509"  // 사설 CA
+	"fmt"          // 형식화된 I/O
+	"net"          // UDP/TCP 연결
+	"net/url"      // 전달 대상 파싱
+	"os"           // 호스트명 조회, CA 파일
+	"sort"         // 필드 정렬
+	"strconv"      // 숫자 변환
+	"strings"      // 문자열 처리
+	"sync"         // 동기화
+	"sync/atomic"  // 버린 메시지 수 집계
+	"time"         // 시간 처리
+	"unicode/utf8" // 메시지 자르기
+)
+
+// syslog 전달 설정
+const (
+	ForwardQueueSize       = 10000   // 전송 대기 큐 크기 (가득 차면 메시지 버림)
+	ForwardFacility        = 1       // user-level 메시지 (PRI = facility*8 + severity)
+	ForwardSDEnterpriseID  = "32473" // 구조화 데이터 ID 의 기업 번호
+	ForwardMaxFields       = 64      // fields 요소에 담는 최대 파싱 필드 수
+	ForwardMaxUDPMessage   = 8192    // UDP 데이터그램 최대 크기 (초과분은 메시지 끝을 자름)
+	forwardDialTimeout     = 10 * time.Second
+	forwardWriteTimeout    = 10 * time.Second
+	forwardReconnectDelay  = 5 * time.Second // 연결 실패 후 다시 연결을 시도하기까지 대기 시간 (그동안의 메시지는 버림)
+	forwardDefaultPort     = "514"
+	forwardDefaultTLSPort  = "6514"
+	forwardTimestampLayout = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// SyslogForwarder RFC 5424 syslog 전달 출력
+type SyslogForwarder struct {
+	network   string // udp, tcp, tls
+	address   string // host:port
+	tlsConfig *tls.Config
+	hostname  string // 로그에 호스트가 없을 때 사용할 이 서버의 호스트명
+	logger    Logger
+	pending   chan []byte
+	dropped   int64 // 큐가 가득 차거나 연결 실패로 버린 메시지 수 (다음 전송 시 보고)
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closed    sync.Once
+
+	// 아래 필드는 writeLoop 고루틴에서만 사용
+	conn       net.Conn
+	retryAfter time.Time // 이 시각 전에는 다시 연결하지 않음
+	failing    bool      // 연결 실패 상태 (실패 로그를 한 번만 출력)
+}
+
+// NewSyslogForwarder 새로운 syslog 전달 출력 생성 (연결은 첫 전송 시)
+// target: udp://host[:port], tcp://host[:port], tls://host[:port], caFile: TLS 사설 CA 인증서 (비우면 시스템 CA)
+func NewSyslogForwarder(target, caFile string, logger Logger) (*SyslogForwarder, error) {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid forward target %q: expected udp://, tcp:// or tls://host[:port]", target)
+	}
+	network := strings.ToLower(parsed.Scheme)
+	port := forwardDefaultPort
+	switch network {
+	case "udp", "tcp":
+	case "tls":
+		port = forwardDefaultTLSPort
+	default:
+		return nil, fmt.Errorf("unknown forward protocol %q (use udp, tcp or tls)", parsed.Scheme)
+	}
+	if parsed.Port() != "" {
+		port = parsed.Port()
+	}
+
+	forwarder := &SyslogForwarder{
+		network: network,
+		address: net.JoinHostPort(parsed.Hostname(), port),
+		logger:  logger,
+		pending: make(chan []byte, ForwardQueueSize),
+		done:    make(chan struct{}),
+	}
+	forwarder.hostname, _ = os.Hostname()
+
+	if caFile != "" && network != "tls" {
+		return nil, fmt.Errorf("-forward-ca requires a tls:// forward target")
+	}
+	if network == "tls" {
+		forwarder.tlsConfig = &tls.Config{ServerName: parsed.Hostname(), MinVersion: tls.VersionTLS12}
+		if caFile != "" {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read forward CA file: %v", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no PEM certificates found in %s", caFile)
+			}
+			forwarder.tlsConfig.RootCAs = pool
+		}
+	}
+	return forwarder, nil
+}
+
+// Target 전달 대상 표시용 문자열 (예: tls://logs.example.com:6514)
+func (sf *SyslogForwarder) Target() string {
+	return sf.network + "://" + sf.address
+}
+
+// Start 백그라운드 전송 시작
+func (sf *SyslogForwarder) Start() {
+	sf.wg.Add(1)
+	go sf.writeLoop()
+}
+
+// Close 대기 중인 메시지를 전송하고 연결 종료
+func (sf *SyslogForwarder) Close() {
+	sf.closed.Do(func() {
+		close(sf.done)
+		sf.wg.Wait()
+	})
+}
+
+// Forward 로그 한 줄을 RFC 5424 메시지로 만들어 전송 대기 큐에 추가 (큐가 가득 차면 버림)
+func (sf *SyslogForwarder) Forward(line string, parsed map[string]string, level string, parsedLog *ParsedLog, aiResult *AIAnalysisResult) {
+	message := formatRFC5424(line, parsed, level, parsedLog, aiResult, sf.hostname)
+	select {
+	case sf.pending <- message:
+	default:
+		atomic.AddInt64(&sf.dropped, 1)
+	}
+}
+
+// writeLoop 큐의 메시지를 순서대로 전송
+func (sf *SyslogForwarder) writeLoop() {
+	defer sf.wg.Done()
+	defer sf.closeConn()
+
+	for {
+		select {
+		case message := <-sf.pending:
+			sf.send(message)
+		case <-sf.done:
+			for {
+				select {
+				case message := <-sf.pending:
+					sf.send(message)
+				default:
+					sf.reportDropped()
+					return
+				}
+			}
+		}
+	}
+}
+
+// send 메시지 전송 (연결이 끊겼으면 한 번 재연결 후 재시도, 실패하면 버림)
+func (sf *SyslogForwarder) send(message []byte) {
+	frame := sf.frame(message)
+	for attempt := 0; attempt < 2; attempt++ {
+		if sf.conn == nil {
+			if time.Now().Before(sf.retryAfter) {
+				break
+			}
+			if err := sf.connect(); err != nil {
+				sf.retryAfter = time.Now().Add(forwardReconnectDelay)
+				if !sf.failing {
+					sf.logger.Errorf("❌ Syslog forward to %s failed: %v (retrying every %v, messages are dropped meanwhile)", sf.Target(), err, forwardReconnectDelay)
+					sf.failing = true
+				}
+				break
+			}
+			if sf.failing {
+				sf.logger.Infof("📤 Syslog forward to %s reconnected", sf.Target())
+				sf.failing = false
+			}
+		}
+		sf.conn.SetWriteDeadline(time.Now().Add(forwardWriteTimeout))
+		if _, err := sf.conn.Write(frame); err == nil {
+			sf.reportDropped()
+			return
+		}
+		sf.closeConn()
+	}
+	atomic.AddInt64(&sf.dropped, 1)
+}
+
+// frame 전송 방식에 맞는 프레이밍 (TCP/TLS: 옥텟 카운팅, UDP: 크기 제한)
+func (sf *SyslogForwarder) frame(message []byte) []byte {
+	if sf.network == "udp" {
+		if len(message) > ForwardMaxUDPMessage {
+			cut := ForwardMaxUDPMessage
+			for cut > 0 && !utf8.RuneStart(message[cut]) {
+				cut--
+			}
+			message = message[:cut]
+		}
+		return message
+	}
+	return append([]byte(strconv.Itoa(len(message))+" "), message...)
+}
+
+// connect 전달 대상에 연결
+func (sf *SyslogForwarder) connect() error {
+	dialer := &net.Dialer{Timeout: forwardDialTimeout}
+	var conn net.Conn
+	var err error
+	if sf.network == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", sf.address, sf.tlsConfig)
+	} else {
+		conn, err = dialer.Dial(sf.network, sf.address)
+	}
+	if err != nil {
+		return err
+	}
+	sf.conn = conn
+	return nil
+}
+
+// closeConn 연결 종료
+func (sf *SyslogForwarder) closeConn() {
+	if sf.conn != nil {
+		sf.conn.Close()
+		sf.conn = nil
+	}
+}
+
+// reportDropped 버린 메시지 수 보고 (전송이 다시 성공하거나 종료할 때)
+func (sf *SyslogForwarder) reportDropped() {
+	if dropped := atomic.SwapInt64(&sf.dropped, 0); dropped > 0 {
+		sf.logger.Errorf("⚠️  Syslog forward dropped %d messages", dropped)
+	}
+}
+
+// forwardSeverity 로그 레벨 → syslog 심각도 (RFC 5424 6.2.1)
+func forwardSeverity(level string) int {
+	switch level {
+	case "CRITICAL":
+		return 2
+	case "ERROR":
+		return 3
+	case "WARNING":
+		return 4
+	default:
+		return 6
+	}
+}
+
+// formatRFC5424 RFC 5424 메시지 생성
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [STRUCTURED-DATA] MSG
+func formatRFC5424(line string, parsed map[string]string, level string, parsedLog *ParsedLog, aiResult *AIAnalysisResult, fallbackHost string) []byte {
+	timestamp := time.Now()
+	logType := ""
+	if parsedLog != nil {
+		if !parsedLog.Timestamp.IsZero() {
+			timestamp = parsedLog.Timestamp
+		}
+		logType = parsedLog.LogType
+	}
+
+	host := parsed["host"]
+	if host == "" {
+		host = fallbackHost
+	}
+	appName, procID := serviceName(parsed["service"]), ""
+	if start := strings.IndexByte(parsed["service"], '['); start > 0 {
+		if end := strings.IndexByte(parsed["service"][start:], ']'); end > 0 {
+			procID = parsed["service"][start+1 : start+end]
+		}
+	}
+	if appName == "" {
+		appName = parsed["unit"]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %s %s ",
+		ForwardFacility*8+forwardSeverity(level),
+		timestamp.Format(forwardTimestampLayout),
+		syslogHeaderField(host, 255),
+		syslogHeaderField(appName, 48),
+		syslogHeaderField(procID, 128),
+		syslogHeaderField(logType, 32))
+
+	// 분석 결과 요소
+	b.WriteString("[syslogmonitor@" + ForwardSDEnterpriseID)
+	writeSDParam(&b, "level", level)
+	if logType != "" {
+		writeSDParam(&b, "log_type", logType)
+	}
+	if aiResult != nil {
+		writeSDParam(&b, "ai_score", strconv.FormatFloat(aiResult.AnomalyScore, 'f', 2, 64))
+		if fields := strings.Fields(aiResult.ThreatLevel); len(fields) > 0 {
+			writeSDParam(&b, "threat", fields[len(fields)-1]) // "🟢 LOW" → "LOW"
+		}
+		writeSDParam(&b, "confidence", strconv.FormatFloat(aiResult.Confidence, 'f', 2, 64))
+		if len(aiResult.MatchedRules) > 0 {
+			names := make([]string, 0, len(aiResult.MatchedRules))
+			for _, rule := range aiResult.MatchedRules {
+				names = append(names, rule.Name)
+			}
+			writeSDParam(&b, "rules", strings.Join(names, ","))
+		}
+	}
+	b.WriteString("]")
+
+	// 파싱 필드 요소 (이름순, 최대 ForwardMaxFields 개)
+	if parsedLog != nil && len(parsedLog.Fields) > 0 {
+		keys := make([]string, 0, len(parsedLog.Fields))
+		for key := range parsedLog.Fields {
+			if sdName(key) != "" && parsedLog.Fields[key] != "" {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		if len(keys) > ForwardMaxFields {
+			keys = keys[:ForwardMaxFields]
+		}
+		if len(keys) > 0 {
+			b.WriteString("[fields@" + ForwardSDEnterpriseID)
+			for _, key := range keys {
+				writeSDParam(&b, key, parsedLog.Fields[key])
+			}
+			b.WriteString("]")
+		}
+	}
+
+	message := parsed["message"]
+	if message == "" {
+		message = line
+	}
+	if message != "" {
+		b.WriteString(" \ufeff") // UTF-8 메시지 표시 (BOM)
+		b.WriteString(message)
+	}
+	return []byte(b.String())
+}
+
+// syslogHeaderField 헤더 필드 정리 (비어 있으면 NILVALUE "-", 출력 가능한 ASCII 외 문자 제거, 길이 제한)
+func syslogHeaderField(value string, maxLen int) string {
+	var b strings.Builder
+	for _, r := range value {
+		if r > 32 && r < 127 {
+			b.WriteRune(r)
+		}
+	}
+	value = b.String()
+	if len(value) > maxLen {
+		value = value[:maxLen]
+	}
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// sdName 구조화 데이터 파라미터 이름 정리 (출력 가능한 ASCII 중 '=', ' ', ']', '"' 제외, 최대 32자)
+func sdName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if r > 32 && r < 127 && r != '=' && r != ']' && r != '"' {
+			b.WriteRune(r)
+		}
+	}
+	name = b.String()
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	return name
+}
+
+// writeSDParam 구조화 데이터 파라미터 추가 (값의 '"', '\', ']' 는 역슬래시로 이스케이프)
+func writeSDParam(b *strings.Builder, name, value string) {
+	b.WriteString(" " + sdName(name) + "=\"")
+	for _, r := range value {
+		switch r {
+		case '"', '\\', ']':
+			b.WriteRune('\\')
+		case '\n', '\r':
+			r = ' '
+		}
+		b.WriteRune(r)
+	}
+	b.WriteString("\"")
+}
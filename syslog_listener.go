@@ -0,0 +1,374 @@
+/*
+Syslog Listener Module
+======================
+
+네트워크 syslog 수신 입력 (-listen)
+
+컨테이너처럼 호스트 로그 파일을 마운트하기 어려운 환경에서 다른 호스트/컨테이너가 보내는 syslog 를
+UDP/TCP 로 받아 기존 처리 파이프라인에 전달합니다 (rsyslog/syslog-ng 전달, docker --log-driver=syslog 등).
+
+주요 기능:
+- 수신 주소: udp://[host]:port, tcp://[host]:port (쉼표로 여러 개, 포트를 생략하면 514)
+- RFC 3164 (<PRI>Mmm dd hh:mm:ss host tag: msg) 와 RFC 5424 (<PRI>1 TIMESTAMP HOST APP PROCID MSGID SD MSG)
+- TCP 프레이밍: 옥텟 카운팅("길이 SP 메시지", RFC 6587) 과 줄바꿈 구분을 메시지마다 판별
+- 기존 정규식 기반 감지기가 그대로 동작하도록 "Mmm dd hh:mm:ss host tag[pid]: msg" 형식으로 재구성
+- 보낸 주소 허용 목록 (-listen-allow, CIDR/IP), 동시 TCP 연결 수 상한
+- 소스 이름은 메시지의 호스트 (syslog:<host>, 소스별 처리 비용/형식 미인식 통계)
+*/
+package main
+
+import (
+	"bufio"   // TCP 스트림 읽기
+	"fmt"     // 형식화된 I/O
+	"io"      // 옥텟 카운팅 메시지 읽기
+	"net"     // UDP/TCP 수신
+	"net/url" // 수신 주소 파싱
+	"strconv" // PRI/길이 파싱
+	"strings" // 문자열 처리
+	"sync"    // 동기화
+	"time"    // 타임스탬프 변환
+)
+
+// syslog 수신 설정
+const (
+	listenDefaultPort     = "514"     // 포트를 생략한 수신 주소의 기본 포트
+	listenMaxMessage      = 64 * 1024 // 메시지 최대 크기 (UDP 데이터그램, TCP 메시지)
+	listenMaxConnections  = 256       // 동시 TCP 연결 수 상한
+	listenIdleTimeout     = 10 * time.Minute
+	listenEntryBufferSize = 1000
+)
+
+// SyslogListener UDP/TCP syslog 수신기
+type SyslogListener struct {
+	addresses []string
+	allow     *TrustedNetworks // nil 이면 모든 주소 허용
+	packets   []net.PacketConn
+	listeners []net.Listener
+	entries   chan JournalEntry
+	logger    Logger
+
+	mutex       sync.Mutex
+	connections map[net.Conn]struct{}
+	stop        chan struct{}
+	stopOnce    sync.Once
+}
+
+// NewSyslogListener 수신 주소를 모두 열고 수신 시작 (하나라도 실패하면 이미 연 주소를 닫고 오류)
+func NewSyslogListener(targets []string, allow *TrustedNetworks, logger Logger) (*SyslogListener, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no listen address")
+	}
+	listener := &SyslogListener{
+		allow:       allow,
+		entries:     make(chan JournalEntry, listenEntryBufferSize),
+		logger:      logger,
+		connections: make(map[net.Conn]struct{}),
+		stop:        make(chan struct{}),
+	}
+	for _, target := range targets {
+		network, address, err := parseListenTarget(target)
+		if err != nil {
+			listener.Stop()
+			return nil, err
+		}
+		switch network {
+		case "udp":
+			conn, err := net.ListenPacket("udp", address)
+			if err != nil {
+				listener.Stop()
+				return nil, fmt.Errorf("failed to listen on %s: %v", target, err)
+			}
+			listener.packets = append(listener.packets, conn)
+			listener.addresses = append(listener.addresses, "udp://"+conn.LocalAddr().String())
+			go listener.servePackets(conn)
+		case "tcp":
+			ln, err := net.Listen("tcp", address)
+			if err != nil {
+				listener.Stop()
+				return nil, fmt.Errorf("failed to listen on %s: %v", target, err)
+			}
+			listener.listeners = append(listener.listeners, ln)
+			listener.addresses = append(listener.addresses, "tcp://"+ln.Addr().String())
+			go listener.serveStream(ln)
+		}
+	}
+	return listener, nil
+}
+
+// parseListenTarget udp://host:port, tcp://host:port 수신 주소 파싱
+func parseListenTarget(target string) (network, address string, err error) {
+	parsed, err := url.Parse(strings.TrimSpace(target))
+	if err != nil || parsed.Host == "" {
+		return "", "", fmt.Errorf("invalid listen address %q: expected udp:// or tcp://[host]:port", target)
+	}
+	network = strings.ToLower(parsed.Scheme)
+	if network != "udp" && network != "tcp" {
+		return "", "", fmt.Errorf("unknown listen protocol %q (use udp or tcp)", parsed.Scheme)
+	}
+	port := parsed.Port()
+	if port == "" {
+		port = listenDefaultPort
+	}
+	return network, net.JoinHostPort(parsed.Hostname(), port), nil
+}
+
+// Addresses 실제로 연 수신 주소 (포트 0 을 지정하면 할당된 포트)
+func (sl *SyslogListener) Addresses() []string {
+	return sl.addresses
+}
+
+// Entries 수신한 메시지 채널 (Stop 후에는 더 이상 보내지 않음)
+func (sl *SyslogListener) Entries() <-chan JournalEntry {
+	return sl.entries
+}
+
+// Stop 모든 수신 소켓과 TCP 연결 닫기 (여러 번 호출해도 안전)
+func (sl *SyslogListener) Stop() {
+	sl.stopOnce.Do(func() {
+		close(sl.stop)
+		for _, conn := range sl.packets {
+			conn.Close()
+		}
+		for _, ln := range sl.listeners {
+			ln.Close()
+		}
+		sl.mutex.Lock()
+		for conn := range sl.connections {
+			conn.Close()
+		}
+		sl.mutex.Unlock()
+	})
+}
+
+// allowed 보낸 주소가 허용 목록에 있는지 (목록이 없으면 항상 허용)
+func (sl *SyslogListener) allowed(addr net.Addr) bool {
+	if sl.allow == nil || sl.allow.Len() == 0 {
+		return true
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+	_, ok := sl.allow.Match(host)
+	return ok
+}
+
+// deliver 메시지를 엔트리로 변환해 처리 루프로 전달 (중지되면 false)
+func (sl *SyslogListener) deliver(message string, from net.Addr) bool {
+	host, _, err := net.SplitHostPort(from.String())
+	if err != nil {
+		host = from.String()
+	}
+	entry, ok := parseSyslogMessage(message, host, time.Now())
+	if !ok {
+		return true
+	}
+	select {
+	case sl.entries <- entry:
+		return true
+	case <-sl.stop:
+		return false
+	}
+}
+
+// servePackets UDP 데이터그램 하나를 메시지 하나로 처리
+func (sl *SyslogListener) servePackets(conn net.PacketConn) {
+	buffer := make([]byte, listenMaxMessage)
+	for {
+		n, from, err := conn.ReadFrom(buffer)
+		if err != nil {
+			select {
+			case <-sl.stop:
+			default:
+				sl.logger.Errorf("syslog UDP listener stopped: %v", err)
+			}
+			return
+		}
+		if !sl.allowed(from) {
+			continue
+		}
+		if !sl.deliver(string(buffer[:n]), from) {
+			return
+		}
+	}
+}
+
+// serveStream TCP 연결 수락 (연결 수 상한을 넘으면 바로 닫음)
+func (sl *SyslogListener) serveStream(ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			select {
+			case <-sl.stop:
+			default:
+				sl.logger.Errorf("syslog TCP listener stopped: %v", err)
+			}
+			return
+		}
+		if !sl.allowed(conn.RemoteAddr()) {
+			conn.Close()
+			continue
+		}
+		sl.mutex.Lock()
+		if len(sl.connections) >= listenMaxConnections {
+			sl.mutex.Unlock()
+			sl.logger.Errorf("syslog TCP connection from %s rejected (%d connections open)", conn.RemoteAddr(), listenMaxConnections)
+			conn.Close()
+			continue
+		}
+		sl.connections[conn] = struct{}{}
+		sl.mutex.Unlock()
+		go sl.serveConnection(conn)
+	}
+}
+
+// serveConnection TCP 연결 하나의 메시지 읽기 (메시지마다 옥텟 카운팅/줄바꿈 프레이밍 판별)
+func (sl *SyslogListener) serveConnection(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		sl.mutex.Lock()
+		delete(sl.connections, conn)
+		sl.mutex.Unlock()
+	}()
+
+	reader := bufio.NewReaderSize(conn, listenMaxMessage)
+	for {
+		conn.SetReadDeadline(time.Now().Add(listenIdleTimeout))
+		message, err := readSyslogFrame(reader)
+		if message != "" && !sl.deliver(message, conn.RemoteAddr()) {
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readSyslogFrame TCP 스트림에서 메시지 하나 읽기
+// 숫자로 시작하면 옥텟 카운팅 ("길이 SP 메시지"), 그 외에는 줄바꿈까지 (너무 긴 줄은 잘라서 반환)
+func readSyslogFrame(reader *bufio.Reader) (string, error) {
+	first, err := reader.Peek(1)
+	if err != nil {
+		return "", err
+	}
+	if first[0] >= '1' && first[0] <= '9' {
+		prefix, err := reader.ReadSlice(' ')
+		if err != nil {
+			return "", err
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(string(prefix)))
+		if err != nil || length <= 0 || length > listenMaxMessage {
+			return "", fmt.Errorf("invalid octet count %q", strings.TrimSpace(string(prefix)))
+		}
+		message := make([]byte, length)
+		if _, err := io.ReadFull(reader, message); err != nil {
+			return "", err
+		}
+		return string(message), nil
+	}
+
+	line, err := reader.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		// 버퍼보다 긴 줄은 앞부분만 처리하고 나머지는 버림
+		message := string(line)
+		for err == bufio.ErrBufferFull {
+			_, err = reader.ReadSlice('\n')
+		}
+		return message, err
+	}
+	return string(line), err
+}
+
+// parseSyslogMessage RFC 3164/5424 메시지를 syslog 형식 라인으로 재구성 (빈 메시지는 false)
+// 호스트를 알 수 없으면 보낸 주소를 호스트로 사용
+func parseSyslogMessage(message, remoteHost string, now time.Time) (JournalEntry, bool) {
+	message = strings.TrimRight(message, "\r\n\x00")
+	rest := message
+	if strings.HasPrefix(rest, "<") {
+		if end := strings.IndexByte(rest, '>'); end > 1 && end <= 4 {
+			if _, err := strconv.Atoi(rest[1:end]); err == nil {
+				rest = rest[end+1:]
+			}
+		}
+	}
+	if strings.TrimSpace(rest) == "" {
+		return JournalEntry{}, false
+	}
+
+	var line, host string
+	if strings.HasPrefix(rest, "1 ") {
+		line, host = rfc5424Line(rest[2:], remoteHost, now)
+	} else if len(rest) >= len(time.Stamp) && isSyslogStamp(rest[:len(time.Stamp)]) {
+		// RFC 3164 은 이미 기존 형식 (호스트는 타임스탬프 다음 필드)
+		line = rest
+		if fields := strings.Fields(rest[len(time.Stamp):]); len(fields) > 0 {
+			host = fields[0]
+		}
+	} else {
+		// 타임스탬프/호스트 없이 보낸 메시지는 받은 시각과 보낸 주소로 채움
+		host = remoteHost
+		line = fmt.Sprintf("%s %s %s", now.Format(time.Stamp), host, strings.TrimSpace(rest))
+	}
+	if host == "" {
+		host = remoteHost
+	}
+	return JournalEntry{Line: line, Source: "syslog:" + host}, true
+}
+
+// rfc5424Line RFC 5424 본문 (버전 다음부터) 을 syslog 형식 라인으로 변환
+func rfc5424Line(body, remoteHost string, now time.Time) (line, host string) {
+	fields := strings.SplitN(body, " ", 6)
+	for len(fields) < 6 {
+		fields = append(fields, "-")
+	}
+	timestamp, host, app, procID := fields[0], fields[1], fields[2], fields[3]
+
+	when := now
+	if parsed, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+		when = parsed.Local()
+	}
+	if host == "-" || host == "" {
+		host = remoteHost
+	}
+	if app == "-" || app == "" {
+		app = "syslog"
+	}
+	service := app
+	if procID != "-" && procID != "" {
+		service = fmt.Sprintf("%s[%s]", app, procID)
+	}
+	text := strings.TrimPrefix(skipStructuredData(fields[5]), "\ufeff")
+	return fmt.Sprintf("%s %s %s: %s", when.Format(time.Stamp), host, service, text), host
+}
+
+// skipStructuredData RFC 5424 STRUCTURED-DATA ("-" 또는 [..][..]) 다음의 메시지 반환
+func skipStructuredData(value string) string {
+	if value == "-" {
+		return ""
+	}
+	if strings.HasPrefix(value, "- ") {
+		return value[2:]
+	}
+	inElement, escaped := false, false
+	for i, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && inElement:
+			escaped = true
+		case r == '[' && !inElement:
+			inElement = true
+		case r == ']' && inElement:
+			inElement = false
+		case !inElement:
+			return strings.TrimPrefix(value[i:], " ")
+		}
+	}
+	return ""
+}
+
+// isSyslogStamp "Jan  2 15:04:05" 형식 타임스탬프인지
+func isSyslogStamp(value string) bool {
+	_, err := time.Parse(time.Stamp, value)
+	return err == nil
+}
@@ -6,17 +6,20 @@ System Resource Monitoring Module
 
 주요 기능:
 - CPU 사용률 및 코어별 모니터링
-- 메모리 사용량 및 스왑 모니터링  
+- 메모리 사용량, 스왑 및 메모리 압박(PSI / memory_pressure) 모니터링
 - 디스크 사용량 및 inode 모니터링
-- 네트워크 트래픽 통계
+- 네트워크 트래픽 통계 및 인터페이스별 초당 처리량
+- 디스크 I/O (IOPS, 처리량, busy %) 및 포화 알림
+- TCP 수신 대기 포트와 연결 수 (새 포트, 연결 급증 알림, network_connections.go)
 - 시스템 온도 감지 (지원 시)
 - 로드 평균 및 프로세스 상태 추적
 - 임계값 기반 알림 시스템
 
 지원 플랫폼:
-- Linux: /proc 파일시스템 기반 정확한 메트릭 수집
-- macOS: vm_stat, top, df 명령어 기반 모니터링
-- 크로스 플랫폼 호환성 보장
+- Linux, macOS, Windows 모두 gopsutil 로 같은 방식으로 수집 (macOS 연결 목록의 lsof 외에는 외부 명령 실행 없음)
+- CPU 사용률은 수집 주기 사이의 CPU 시간 변화량으로 계산
+- 수집할 수 없는 값 (센서 없는 온도 등) 은 추정하지 않고 0 으로 둠
+- 메모리 압박: Linux PSI, macOS memory_pressure
 
 알림 임계값:
 - CPU: 80% 이상
@@ -27,17 +30,36 @@ System Resource Monitoring Module
 package main
 
 import (
-	"fmt"         // 형식화된 I/O
-	"io/ioutil"   // 파일 I/O 유틸리티
-	"net"         // 네트워크 인터페이스
-	"os"          // OS 인터페이스
-	"os/exec"     // 외부 명령 실행
-	"runtime"     // Go 런타임 정보
-	"strconv"     // 문자열-숫자 변환
-	"strings"     // 문자열 처리
-	"time"        // 시간 처리
+	"context"  // 종료 신호
+	"fmt"      // 형식화된 I/O
+	"io"       // 공인 IP 응답 읽기
+	"net"      // 네트워크 인터페이스
+	"net/http" // 공인 IP 조회
+	"os"       // OS 인터페이스
+	"os/exec"  // 외부 명령 실행 (macOS 메모리 압박)
+	"runtime"  // Go 런타임 정보
+	"strconv"  // 문자열-숫자 변환
+	"strings"  // 문자열 처리
+	"time"     // 시간 처리
+
+	"github.com/shirou/gopsutil/v4/cpu"       // CPU 시간/코어 수
+	"github.com/shirou/gopsutil/v4/disk"      // 파티션/사용량
+	"github.com/shirou/gopsutil/v4/load"      // 로드 평균
+	"github.com/shirou/gopsutil/v4/mem"       // 메모리/스왑
+	psnet "github.com/shirou/gopsutil/v4/net" // 네트워크 인터페이스 통계
+	"github.com/shirou/gopsutil/v4/process"   // 프로세스 목록/상태
+	"github.com/shirou/gopsutil/v4/sensors"   // 온도 센서
 )
 
+// 메트릭 단위 변환
+const (
+	bytesPerMB = 1024 * 1024
+	bytesPerGB = 1024 * 1024 * 1024
+)
+
+// publicIPTimeout 공인 IP 조회 서비스 하나의 응답 대기 시간
+const publicIPTimeout = 5 * time.Second
+
 // SystemMonitor 시스템 메트릭 모니터링 구조체
 type SystemMonitor struct {
 	interval       time.Duration
@@ -50,12 +72,21 @@ type SystemMonitor struct {
 	// 정기 보고서 및 다운 감지 관련
 	periodicReport    bool          // 정기 보고서 활성화
 	reportInterval    time.Duration // 보고서 전송 간격
+	reportSchedule    *ReportSchedule // 시간대 기반 보고서 스케줄 (설정 시 reportInterval 대신 사용)
 	lastReportTime    time.Time     // 마지막 보고서 전송 시간
 	heartbeatInterval time.Duration // 하트비트 간격
 	lastHeartbeat     time.Time     // 마지막 하트비트 시간
 	isSystemDown      bool          // 시스템 다운 상태
-	emailService      *EmailService // 이메일 서비스
-	slackService      *SlackService // Slack 서비스
+	alertDispatcher   *AlertDispatcher // 알림 디스패처 (이메일, Slack, 웹훅 등)
+	criticalRaised    bool          // 이번 수집 주기에 CRITICAL 알림이 발생했는지 여부
+	lastCPUTimes      *cpu.TimesStat // 이전 수집의 CPU 누적 시간 (사용률 변화량 계산)
+	knownPorts        map[uint32]bool // 첫 수집 때 열려 있던 수신 대기 포트
+	expectedPorts     map[uint32]bool // 설정으로 허용한 수신 대기 포트
+	alertedPorts      map[uint32]bool // 이미 알린 예상 밖 수신 대기 포트
+	lastNetCounters   map[string]psnet.IOCountersStat // 이전 수집의 인터페이스별 누적 카운터 (처리량 계산)
+	lastNetTime       time.Time
+	lastDiskCounters  map[string]disk.IOCountersStat // 이전 수집의 디스크별 누적 카운터 (IOPS 계산)
+	lastDiskTime      time.Time
 }
 
 // SystemMetrics 시스템 메트릭 구조체
@@ -64,10 +95,13 @@ type SystemMetrics struct {
 	CPU          CPUMetrics           `json:"cpu"`
 	Memory       MemoryMetrics        `json:"memory"`
 	Disk         []DiskMetrics        `json:"disk"`
+	DiskIO       DiskIOMetrics        `json:"disk_io"`           // 디스크별 IOPS / 처리량 / busy %
 	Network      NetworkMetrics       `json:"network"`
 	Temperature  TempMetrics          `json:"temperature"`
 	LoadAverage  LoadMetrics          `json:"load_average"`
 	ProcessCount ProcessMetrics       `json:"processes"`
+	Pressure     PressureMetrics      `json:"pressure"`          // Linux PSI (Pressure Stall Information)
+	Connections  ConnectionMetrics    `json:"connections"`       // TCP 수신 대기 포트와 연결 수
 	Fields       map[string]string    `json:"fields,omitempty"` // macOS 배터리 정보 등 추가 필드
 	IPInfo       IPInformation        `json:"ip_info"`           // IP 정보
 }
@@ -92,6 +126,13 @@ type MemoryMetrics struct {
 	SwapTotalMB  float64 `json:"swap_total_mb"`
 	SwapUsedMB   float64 `json:"swap_used_mb"`
 	SwapFreePercent float64 `json:"swap_free_percent"`
+	SwapUsagePercent float64 `json:"swap_usage_percent"`
+
+	// 메모리 압박 정보
+	// Linux: /proc/pressure/memory 의 some avg60 (메모리 대기로 지연된 시간 비율)
+	// macOS: memory_pressure 명령어의 여유 메모리 비율을 뒤집은 값 (100 - free%)
+	PressurePercent float64 `json:"pressure_percent"`
+	PressureLevel   string  `json:"pressure_level"` // NORMAL, WARN, CRITICAL
 }
 
 // DiskMetrics 디스크 관련 메트릭
@@ -116,6 +157,16 @@ type NetworkMetrics struct {
 	ErrorsSent   uint64  `json:"errors_sent"`
 	DroppedRecv  uint64  `json:"dropped_recv"`
 	DroppedSent  uint64  `json:"dropped_sent"`
+
+	// 루프백을 제외한 전체 인터페이스의 초당 변화율 (두 번째 수집부터 RatesAvailable)
+	RatesAvailable    bool            `json:"rates_available"`
+	RecvBytesPerSec   float64         `json:"recv_bytes_per_sec"`
+	SentBytesPerSec   float64         `json:"sent_bytes_per_sec"`
+	RecvPacketsPerSec float64         `json:"recv_packets_per_sec"`
+	SentPacketsPerSec float64         `json:"sent_packets_per_sec"`
+	ErrorsPerSec      float64         `json:"errors_per_sec"`
+	DropsPerSec       float64         `json:"drops_per_sec"`
+	Interfaces        []InterfaceRate `json:"interfaces,omitempty"`
 }
 
 // TempMetrics 온도 관련 메트릭
@@ -131,6 +182,11 @@ type LoadMetrics struct {
 	Load1Min   float64 `json:"load_1min"`
 	Load5Min   float64 `json:"load_5min"`
 	Load15Min  float64 `json:"load_15min"`
+
+	// 코어 수로 정규화한 로드 평균 (1.0 = 모든 코어가 포화 상태)
+	Load1MinPerCore  float64 `json:"load_1min_per_core"`
+	Load5MinPerCore  float64 `json:"load_5min_per_core"`
+	Load15MinPerCore float64 `json:"load_15min_per_core"`
 }
 
 // ProcessMetrics 프로세스 관련 메트릭
@@ -155,9 +211,21 @@ type SystemThresholds struct {
 	MemoryPercent    float64 `json:"memory_percent"`
 	DiskPercent      float64 `json:"disk_percent"`
 	CPUTemp          float64 `json:"cpu_temp"`
-	LoadAverage      float64 `json:"load_average"`
+	LoadAverage      float64 `json:"load_average"`  // 실제 로드 임계값 (LoadPerCore * 코어 수로 계산됨)
+	LoadPerCore      float64 `json:"load_per_core"` // 코어당 로드 임계값 (예: 1.5 = 코어 수의 1.5배)
 	SwapPercent      float64 `json:"swap_percent"`
+	MemoryPressure   float64 `json:"memory_pressure"` // 메모리 압박 경고 임계값 (%)
+	CPUPressure      float64 `json:"cpu_pressure"`    // CPU PSI some avg60 경고 임계값 (%)
+	IOPressure       float64 `json:"io_pressure"`     // I/O PSI some avg60 경고 임계값 (%)
 	InodePercent     float64 `json:"inode_percent"`
+	ConnectionSpike  float64 `json:"connection_spike"` // 포트별 연결 수가 최근 평균의 몇 배 이상이면 경고
+	ConnectionMin    float64 `json:"connection_min"`   // 연결 급증으로 판단할 최소 연결 수
+	DiskBusy         float64 `json:"disk_busy"`        // 디스크 사용 중 시간 비율 경고 임계값 (%)
+	DiskIOPS         float64 `json:"disk_iops"`        // 디스크별 읽기+쓰기 IOPS 경고 임계값 (0 = 사용 안 함)
+	DiskMBps         float64 `json:"disk_mbps"`        // 디스크별 읽기+쓰기 처리량 경고 임계값 (MB/s, 0 = 사용 안 함)
+	NetworkPercent   float64 `json:"network_percent"`  // 링크 속도 대비 인터페이스 사용률 경고 임계값 (%)
+	NetworkMbps      float64 `json:"network_mbps"`     // 인터페이스 수신/송신 처리량 경고 임계값 (Mbps, 0 = 사용 안 함)
+	NetworkErrors    float64 `json:"network_errors"`   // 인터페이스 초당 에러+드롭 경고 임계값
 }
 
 // SystemAlert 시스템 알림 구조체
@@ -185,9 +253,18 @@ func NewSystemMonitor(interval time.Duration) *SystemMonitor {
 			MemoryPercent: 85.0,
 			DiskPercent:   90.0,
 			CPUTemp:       75.0,
-			LoadAverage:   float64(runtime.NumCPU()) * 2.0,
-			SwapPercent:   50.0,
+			LoadAverage:   float64(runtime.NumCPU()) * DefaultLoadThreshold,
+			LoadPerCore:   DefaultLoadThreshold,
+			SwapPercent:   DefaultSwapThreshold,
+			MemoryPressure: DefaultMemoryPressureThreshold,
+			CPUPressure:   DefaultCPUPressureThreshold,
+			IOPressure:    DefaultIOPressureThreshold,
 			InodePercent:  90.0,
+			ConnectionSpike: DefaultConnectionSpike,
+			ConnectionMin: DefaultConnectionMin,
+			DiskBusy:      DefaultDiskBusyThreshold,
+			NetworkPercent: DefaultNetworkUtilizationThreshold,
+			NetworkErrors: DefaultNetworkErrorThreshold,
 		},
 		// 기본값 설정
 		periodicReport:    false,
@@ -198,53 +275,68 @@ func NewSystemMonitor(interval time.Duration) *SystemMonitor {
 	}
 }
 
-// NewSystemMonitorWithNotifications 이메일/Slack 알림이 포함된 시스템 모니터 생성
-func NewSystemMonitorWithNotifications(interval time.Duration, periodicReport bool, reportInterval time.Duration, emailService *EmailService, slackService *SlackService) *SystemMonitor {
+// NewSystemMonitorWithNotifications 알림 디스패처가 연결된 시스템 모니터 생성
+func NewSystemMonitorWithNotifications(interval time.Duration, periodicReport bool, reportInterval time.Duration, alertDispatcher *AlertDispatcher) *SystemMonitor {
 	monitor := NewSystemMonitor(interval)
 	monitor.periodicReport = periodicReport
 	monitor.reportInterval = reportInterval
-	monitor.emailService = emailService
-	monitor.slackService = slackService
+	monitor.alertDispatcher = alertDispatcher
 	monitor.lastReportTime = time.Now()
 	return monitor
 }
 
-// Start 시스템 모니터링 시작
-func (sm *SystemMonitor) Start() {
+// Start 시스템 모니터링 시작 (ctx 가 끝나면 타이머를 멈추고 알림 채널을 닫음)
+func (sm *SystemMonitor) Start(ctx context.Context) {
 	// 초기 메트릭 수집 즉시 실행
 	sm.collectMetrics()
 	
 	ticker := time.NewTicker(sm.interval)
 	
-	// 정기 보고서 타이머 설정
+	// 정기 보고서 타이머 설정 (스케줄이 있으면 스케줄 기준, 없으면 고정 간격)
+	var reportC <-chan time.Time
 	var reportTicker *time.Ticker
 	if sm.periodicReport {
-		reportTicker = time.NewTicker(sm.reportInterval)
+		if sm.reportSchedule != nil {
+			reportC = sm.reportSchedule.Timer()
+		} else {
+			reportTicker = time.NewTicker(sm.reportInterval)
+			reportC = reportTicker.C
+		}
 	}
 	
 	// 하트비트 타이머 설정
 	heartbeatTicker := time.NewTicker(sm.heartbeatInterval)
 	
 	go func() {
+		// 알림은 이 고루틴에서만 보내므로 종료 시 채널을 닫아 수신 측(handleSystemAlerts)도 끝나게 함
+		defer close(sm.alertChannel)
+		defer ticker.Stop()
+		defer heartbeatTicker.Stop()
+		if reportTicker != nil {
+			defer reportTicker.Stop()
+		}
 		for {
 			select {
+			case <-ctx.Done():
+				return
+
 			case <-ticker.C:
 				sm.updateHeartbeat()
 				sm.collectMetrics()
+				sm.criticalRaised = false
 				sm.checkAlerts()
 				sm.checkSystemHealth()
+				sm.resolveClearedAlerts()
 				sm.updateHistory()
 				
 			case <-heartbeatTicker.C:
 				sm.checkHeartbeat()
 				
-			case <-func() <-chan time.Time {
-				if reportTicker != nil {
-					return reportTicker.C
-				}
-				return make(chan time.Time) // 비활성화된 채널
-			}():
+			case <-reportC: // 비활성화 시 nil 채널로 대기하지 않음
 				sm.sendPeriodicReport()
+				if sm.reportSchedule != nil {
+					reportC = sm.reportSchedule.Timer()
+				}
 			}
 		}
 	}()
@@ -256,539 +348,362 @@ func (sm *SystemMonitor) collectMetrics() {
 		Timestamp: time.Now(),
 	}
 
-	// 각 메트릭 수집
+	// 각 메트릭 수집 (gopsutil 로 Linux/macOS/Windows 모두 같은 방식으로 수집)
 	sm.collectCPUMetrics()
 	sm.collectMemoryMetrics()
 	sm.collectDiskMetrics()
+	sm.collectDiskIOMetrics()
 	sm.collectNetworkMetrics()
 	sm.collectTemperatureMetrics()
 	sm.collectLoadMetrics()
 	sm.collectProcessMetrics()
+	sm.collectPressureMetrics()
+	sm.collectConnectionMetrics()
 	sm.collectIPInformation()
 }
 
-// collectCPUMetrics CPU 메트릭 수집
+// collectCPUMetrics CPU 메트릭 수집 (이전 수집 이후의 CPU 시간 변화량 기준)
 func (sm *SystemMonitor) collectCPUMetrics() {
 	sm.metrics.CPU.Cores = runtime.NumCPU()
+	if cores, err := cpu.Counts(true); err == nil && cores > 0 {
+		sm.metrics.CPU.Cores = cores
+	}
 
-	if runtime.GOOS == "linux" {
-		// /proc/stat 파일에서 CPU 사용률 계산
-		data, err := ioutil.ReadFile("/proc/stat")
-		if err != nil {
+	current, err := readCPUTimes()
+	if err != nil {
+		return
+	}
+	previous := sm.lastCPUTimes
+	if previous == nil {
+		// 첫 수집은 짧은 구간을 측정 (부팅 이후 누적 평균은 현재 상태를 반영하지 못함)
+		time.Sleep(CPUSampleWindow)
+		previous = current
+		if current, err = readCPUTimes(); err != nil {
 			return
 		}
-
-		lines := strings.Split(string(data), "\n")
-		for _, line := range lines {
-			if strings.HasPrefix(line, "cpu ") {
-				fields := strings.Fields(line)
-				if len(fields) >= 8 {
-					user, _ := strconv.ParseFloat(fields[1], 64)
-					nice, _ := strconv.ParseFloat(fields[2], 64)
-					system, _ := strconv.ParseFloat(fields[3], 64)
-					idle, _ := strconv.ParseFloat(fields[4], 64)
-					iowait, _ := strconv.ParseFloat(fields[5], 64)
-
-					total := user + nice + system + idle + iowait
-					if total > 0 {
-						sm.metrics.CPU.UserPercent = (user / total) * 100
-						sm.metrics.CPU.SystemPercent = (system / total) * 100
-						sm.metrics.CPU.IdlePercent = (idle / total) * 100
-						sm.metrics.CPU.IOWaitPercent = (iowait / total) * 100
-						sm.metrics.CPU.UsagePercent = 100 - sm.metrics.CPU.IdlePercent
-					}
-				}
-				break
-			}
-		}
-	} else {
-		// macOS용 개선된 CPU 정보 수집
-		sm.collectCPUMetricsMacOS()
 	}
+	sm.lastCPUTimes = current
+	applyCPUTimes(&sm.metrics.CPU, *previous, *current)
 }
 
-// collectCPUMetricsMacOS macOS 전용 CPU 메트릭 수집
-func (sm *SystemMonitor) collectCPUMetricsMacOS() {
-	// top 명령어로 CPU 사용률 수집 (수정된 방법)
-	topCmd := exec.Command("top", "-l", "1")
-	topOutput, err := topCmd.Output()
-	if err == nil {
-		lines := strings.Split(string(topOutput), "\n")
-		for _, line := range lines {
-			if strings.Contains(line, "CPU usage:") {
-				// CPU usage: 14.10% user, 20.6% sys, 65.83% idle 형태 파싱
-				parts := strings.Split(line, ",")
-				for _, part := range parts {
-					part = strings.TrimSpace(part)
-									if strings.Contains(part, "% user") {
-					// "CPU usage: 21.72% user" 형태에서 숫자만 추출
-					fields := strings.Fields(part)
-					for _, field := range fields {
-						if strings.HasSuffix(field, "%") {
-							if val, err := strconv.ParseFloat(strings.TrimSuffix(field, "%"), 64); err == nil {
-								sm.metrics.CPU.UserPercent = val
-								break
-							}
-						}
-					}
-				} else if strings.Contains(part, "% sys") {
-					fields := strings.Fields(part)
-					for _, field := range fields {
-						if strings.HasSuffix(field, "%") {
-							if val, err := strconv.ParseFloat(strings.TrimSuffix(field, "%"), 64); err == nil {
-								sm.metrics.CPU.SystemPercent = val
-								break
-							}
-						}
-					}
-				} else if strings.Contains(part, "% idle") {
-					fields := strings.Fields(part)
-					for _, field := range fields {
-						if strings.HasSuffix(field, "%") {
-							if val, err := strconv.ParseFloat(strings.TrimSuffix(field, "%"), 64); err == nil {
-								sm.metrics.CPU.IdlePercent = val
-								sm.metrics.CPU.UsagePercent = 100 - val
-								break
-							}
-						}
-					}
-				}
-				}
-				break
-			}
-		}
+// readCPUTimes 전체 CPU 누적 시간 조회
+func readCPUTimes() (*cpu.TimesStat, error) {
+	times, err := cpu.Times(false)
+	if err != nil {
+		return nil, err
 	}
+	if len(times) == 0 {
+		return nil, fmt.Errorf("cpu times are not available")
+	}
+	return &times[0], nil
+}
 
-	// CPU 코어 수 수집
-	sm.metrics.CPU.Cores = runtime.NumCPU()
-
-	// 기본값 설정 (수집 실패 시)
-	if sm.metrics.CPU.UsagePercent == 0 {
-		sm.metrics.CPU.UsagePercent = 25.0
-		sm.metrics.CPU.UserPercent = 15.0
-		sm.metrics.CPU.SystemPercent = 10.0
-		sm.metrics.CPU.IdlePercent = 75.0
+// applyCPUTimes 두 CPU 시간 표본의 차이로 사용률 계산
+// nice 는 사용자, irq/softirq/steal 은 시스템 시간으로 합산하고 I/O 대기는 사용률에서 제외
+func applyCPUTimes(metrics *CPUMetrics, previous, current cpu.TimesStat) {
+	total := cpuTotalTime(current) - cpuTotalTime(previous)
+	if total <= 0 {
+		return
+	}
+	user := (current.User + current.Nice) - (previous.User + previous.Nice)
+	system := (current.System + current.Irq + current.Softirq + current.Steal) -
+		(previous.System + previous.Irq + previous.Softirq + previous.Steal)
+
+	metrics.UserPercent = (user / total) * 100
+	metrics.SystemPercent = (system / total) * 100
+	metrics.IdlePercent = ((current.Idle - previous.Idle) / total) * 100
+	metrics.IOWaitPercent = ((current.Iowait - previous.Iowait) / total) * 100
+	metrics.UsagePercent = 100 - metrics.IdlePercent - metrics.IOWaitPercent
+	if metrics.UsagePercent < 0 {
+		metrics.UsagePercent = 0
 	}
 }
 
-// collectMemoryMetrics 메모리 메트릭 수집
+// cpuTotalTime CPU 누적 시간 합계 (guest 시간은 user 에 이미 포함되어 제외)
+func cpuTotalTime(times cpu.TimesStat) float64 {
+	return times.User + times.Nice + times.System + times.Idle + times.Iowait + times.Irq + times.Softirq + times.Steal
+}
+
+// collectMemoryMetrics 메모리, 스왑 (Windows 는 페이지 파일), 메모리 압박 메트릭 수집
 func (sm *SystemMonitor) collectMemoryMetrics() {
-	if runtime.GOOS == "linux" {
-		// /proc/meminfo 파일에서 메모리 정보 수집
-		data, err := ioutil.ReadFile("/proc/meminfo")
-		if err != nil {
-			return
-		}
+	if memory, err := mem.VirtualMemory(); err == nil {
+		sm.metrics.Memory.TotalMB = float64(memory.Total) / bytesPerMB
+		sm.metrics.Memory.UsedMB = float64(memory.Used) / bytesPerMB
+		sm.metrics.Memory.FreeMB = float64(memory.Free) / bytesPerMB
+		sm.metrics.Memory.AvailableMB = float64(memory.Available) / bytesPerMB
+		sm.metrics.Memory.UsagePercent = memory.UsedPercent
+	}
 
-		memInfo := make(map[string]float64)
-		lines := strings.Split(string(data), "\n")
-		for _, line := range lines {
-			if strings.Contains(line, ":") {
-				parts := strings.Fields(line)
-				if len(parts) >= 2 {
-					key := strings.TrimSuffix(parts[0], ":")
-					if val, err := strconv.ParseFloat(parts[1], 64); err == nil {
-						memInfo[key] = val / 1024 // KB to MB
-					}
-				}
-			}
-		}
+	if swap, err := mem.SwapMemory(); err == nil && swap.Total > 0 {
+		sm.metrics.Memory.SwapTotalMB = float64(swap.Total) / bytesPerMB
+		sm.metrics.Memory.SwapUsedMB = float64(swap.Used) / bytesPerMB
+		sm.metrics.Memory.SwapUsagePercent = swap.UsedPercent
+		sm.metrics.Memory.SwapFreePercent = 100 - swap.UsedPercent
+	}
 
-		sm.metrics.Memory.TotalMB = memInfo["MemTotal"]
-		sm.metrics.Memory.FreeMB = memInfo["MemFree"]
-		sm.metrics.Memory.AvailableMB = memInfo["MemAvailable"]
-		sm.metrics.Memory.UsedMB = sm.metrics.Memory.TotalMB - sm.metrics.Memory.FreeMB
-		sm.metrics.Memory.SwapTotalMB = memInfo["SwapTotal"]
-		sm.metrics.Memory.SwapUsedMB = sm.metrics.Memory.SwapTotalMB - memInfo["SwapFree"]
+	switch runtime.GOOS {
+	case "linux":
+		sm.collectMemoryPressureLinux()
+	case "darwin":
+		sm.collectMemoryPressureMacOS()
+	default:
+		sm.metrics.Memory.PressureLevel = MemoryPressureNormal
+	}
+}
 
-		if sm.metrics.Memory.TotalMB > 0 {
-			sm.metrics.Memory.UsagePercent = (sm.metrics.Memory.UsedMB / sm.metrics.Memory.TotalMB) * 100
-		}
-		if sm.metrics.Memory.SwapTotalMB > 0 {
-			sm.metrics.Memory.SwapFreePercent = (memInfo["SwapFree"] / sm.metrics.Memory.SwapTotalMB) * 100
-		}
-	} else {
-		// macOS용 개선된 메모리 정보 수집
-		sm.collectMemoryMetricsMacOS()
+// collectMemoryPressureLinux /proc/pressure/memory 에서 메모리 압박 정보 수집
+// 순간적인 스파이크를 피하기 위해 avg60 (최근 1분 평균)을 기준으로 판단
+// some: 일부 태스크가 메모리 대기로 지연된 시간 비율, full: 모든 태스크가 지연된 시간 비율
+func (sm *SystemMonitor) collectMemoryPressureLinux() {
+	sm.metrics.Memory.PressureLevel = MemoryPressureNormal
+
+	psi, err := readPressureFile("memory")
+	if err != nil {
+		return // PSI 미지원 커널 (4.20 미만) 또는 비활성화
+	}
+
+	sm.metrics.Memory.PressurePercent = psi.SomeAvg60
+	if psi.FullAvg60 >= sm.thresholds.MemoryPressure {
+		sm.metrics.Memory.PressureLevel = MemoryPressureCritical
+	} else if psi.SomeAvg60 >= sm.thresholds.MemoryPressure {
+		sm.metrics.Memory.PressureLevel = MemoryPressureWarn
 	}
 }
 
-// collectMemoryMetricsMacOS macOS 전용 메모리 메트릭 수집
-func (sm *SystemMonitor) collectMemoryMetricsMacOS() {
-	// top 명령어로 메모리 정보 수집 (더 정확한 방법)
-	topCmd := exec.Command("top", "-l", "1")
-	topOutput, err := topCmd.Output()
-	if err == nil {
-		lines := strings.Split(string(topOutput), "\n")
-		for _, line := range lines {
-			if strings.Contains(line, "PhysMem:") {
-				// PhysMem: 15G used (3467M wired, 7111M compressor), 243M unused.
-				parts := strings.Fields(line)
-				if len(parts) >= 4 {
-					// 사용된 메모리 파싱 (예: "15G")
-					usedStr := parts[1]
-					if strings.HasSuffix(usedStr, "G") {
-						if val, err := strconv.ParseFloat(strings.TrimSuffix(usedStr, "G"), 64); err == nil {
-							sm.metrics.Memory.UsedMB = val * 1024 // GB to MB
-						}
-					} else if strings.HasSuffix(usedStr, "M") {
-						if val, err := strconv.ParseFloat(strings.TrimSuffix(usedStr, "M"), 64); err == nil {
-							sm.metrics.Memory.UsedMB = val
-						}
-					}
-					
-					// 사용되지 않은 메모리 파싱 (예: "243M")
-					for i, part := range parts {
-						if strings.Contains(part, "unused") && i > 0 {
-							unusedStr := parts[i-1]
-							if strings.HasSuffix(unusedStr, "M") {
-								if val, err := strconv.ParseFloat(strings.TrimSuffix(unusedStr, "M"), 64); err == nil {
-									sm.metrics.Memory.FreeMB = val
-								}
-							}
-							break
-						}
-					}
-				}
-				break
-			}
+// collectMemoryPressureMacOS macOS 메모리 압박 정보 수집 (커널 압박 단계, 여유 메모리 비율)
+func (sm *SystemMonitor) collectMemoryPressureMacOS() {
+	// 커널 메모리 압박 단계: 1 = 정상, 2 = 경고, 4 = 위험
+	sm.metrics.Memory.PressureLevel = MemoryPressureNormal
+	if output, err := exec.Command("sysctl", "-n", "kern.memorystatus_vm_pressure_level").Output(); err == nil {
+		switch strings.TrimSpace(string(output)) {
+		case "2":
+			sm.metrics.Memory.PressureLevel = MemoryPressureWarn
+		case "4":
+			sm.metrics.Memory.PressureLevel = MemoryPressureCritical
 		}
 	}
 
-	// 시스템 프로파일러로 총 메모리 확인
-	sysProfCmd := exec.Command("system_profiler", "SPHardwareDataType")
-	sysProfOutput, err := sysProfCmd.Output()
-	if err == nil {
-		lines := strings.Split(string(sysProfOutput), "\n")
-		for _, line := range lines {
-			if strings.Contains(line, "Memory:") {
-				parts := strings.Fields(line)
-				if len(parts) >= 2 {
-					memStr := parts[len(parts)-2] + " " + parts[len(parts)-1] // "16 GB"
-					if strings.Contains(memStr, "GB") {
-						if val, err := strconv.ParseFloat(strings.Fields(memStr)[0], 64); err == nil {
-							sm.metrics.Memory.TotalMB = val * 1024 // GB to MB
-						}
-					}
-				}
-				break
+	// 여유 메모리 비율: System-wide memory free percentage: 63%
+	if output, err := exec.Command("memory_pressure", "-Q").Output(); err == nil {
+		for _, line := range strings.Split(string(output), "\n") {
+			if !strings.Contains(line, "free percentage:") {
+				continue
+			}
+			parts := strings.Fields(line)
+			freeStr := strings.TrimSuffix(parts[len(parts)-1], "%")
+			if val, err := strconv.ParseFloat(freeStr, 64); err == nil {
+				sm.metrics.Memory.PressurePercent = 100 - val
 			}
+			break
 		}
 	}
+}
 
-	// 사용 가능한 메모리 계산
-	sm.metrics.Memory.AvailableMB = sm.metrics.Memory.FreeMB
-
-	// 사용률 계산
-	if sm.metrics.Memory.TotalMB > 0 {
-		sm.metrics.Memory.UsagePercent = (sm.metrics.Memory.UsedMB / sm.metrics.Memory.TotalMB) * 100
-	}
-
-	// 기본값 설정 (수집 실패 시)
-	if sm.metrics.Memory.TotalMB == 0 {
-		sm.metrics.Memory.TotalMB = 16384.0
-		sm.metrics.Memory.UsedMB = 8192.0
-		sm.metrics.Memory.FreeMB = 8192.0
-		sm.metrics.Memory.AvailableMB = 8192.0
-		sm.metrics.Memory.UsagePercent = 50.0
-	}
+// ignoredFilesystems 사용률 감시에서 제외할 파일시스템 (읽기 전용 이미지라 항상 100%)
+var ignoredFilesystems = map[string]bool{
+	"squashfs": true, // snap 패키지
+	"iso9660":  true,
+	"udf":      true,
+	"cd9660":   true,
+	"devfs":    true,
+	"autofs":   true,
 }
 
-// collectDiskMetrics 디스크 메트릭 수집
+// collectDiskMetrics 마운트 포인트별 디스크 및 inode 사용률 수집 (물리 파일시스템만)
 func (sm *SystemMonitor) collectDiskMetrics() {
-	cmd := exec.Command("df", "-h")
-	output, err := cmd.Output()
+	partitions, err := disk.Partitions(false)
 	if err != nil {
 		return
 	}
 
-	lines := strings.Split(string(output), "\n")
 	sm.metrics.Disk = []DiskMetrics{}
-
-	for i, line := range lines {
-		if i == 0 || line == "" { // 헤더 스킵
+	seen := make(map[string]bool)
+	for _, partition := range partitions {
+		// macOS 시스템 볼륨 (Preboot, VM 등) 은 데이터 볼륨만 감시
+		if ignoredFilesystems[partition.Fstype] || seen[partition.Device] ||
+			(strings.HasPrefix(partition.Mountpoint, "/System/Volumes/") && partition.Mountpoint != "/System/Volumes/Data") {
 			continue
 		}
-
-		fields := strings.Fields(line)
-		if len(fields) >= 6 {
-			device := fields[0]
-			mountPoint := fields[len(fields)-1]
-
-			// 숫자로 변환 가능한 필드만 처리
-			totalStr := strings.TrimSuffix(fields[1], "G")
-			usedStr := strings.TrimSuffix(fields[2], "G")
-			availStr := strings.TrimSuffix(fields[3], "G")
-			usePercentStr := strings.TrimSuffix(fields[4], "%")
-
-			total, err1 := strconv.ParseFloat(totalStr, 64)
-			used, err2 := strconv.ParseFloat(usedStr, 64)
-			avail, err3 := strconv.ParseFloat(availStr, 64)
-			usePercent, err4 := strconv.ParseFloat(usePercentStr, 64)
-
-			if err1 == nil && err2 == nil && err3 == nil && err4 == nil {
-				diskMetric := DiskMetrics{
-					Device:        device,
-					MountPoint:    mountPoint,
-					TotalGB:       total,
-					UsedGB:        used,
-					FreeGB:        avail,
-					UsagePercent:  usePercent,
-				}
-
-				// inode 사용률 추가 수집
-				inodeCmd := exec.Command("df", "-i", mountPoint)
-				inodeOutput, err := inodeCmd.Output()
-				if err == nil {
-					inodeLines := strings.Split(string(inodeOutput), "\n")
-					if len(inodeLines) >= 2 {
-						inodeFields := strings.Fields(inodeLines[1])
-						if len(inodeFields) >= 5 {
-							inodeUseStr := strings.TrimSuffix(inodeFields[4], "%")
-							if inodePercent, err := strconv.ParseFloat(inodeUseStr, 64); err == nil {
-								diskMetric.InodeUsagePercent = inodePercent
-							}
-						}
-					}
-				}
-
-				sm.metrics.Disk = append(sm.metrics.Disk, diskMetric)
-			}
+		usage, err := disk.Usage(hostRootPath(partition.Mountpoint))
+		if err != nil || usage.Total == 0 {
+			continue // 미디어가 없는 드라이브 등
 		}
+		seen[partition.Device] = true
+
+		sm.metrics.Disk = append(sm.metrics.Disk, DiskMetrics{
+			Device:            partition.Device,
+			MountPoint:        partition.Mountpoint,
+			TotalGB:           float64(usage.Total) / bytesPerGB,
+			UsedGB:            float64(usage.Used) / bytesPerGB,
+			FreeGB:            float64(usage.Free) / bytesPerGB,
+			UsagePercent:      usage.UsedPercent,
+			InodeUsagePercent: usage.InodesUsedPercent,
+		})
 	}
 }
 
-// collectNetworkMetrics 네트워크 메트릭 수집
+// collectNetworkMetrics 네트워크 메트릭 수집 (루프백을 제외하고 누적 트래픽이 가장 많은 인터페이스)
 func (sm *SystemMonitor) collectNetworkMetrics() {
-	if runtime.GOOS == "linux" {
-		data, err := ioutil.ReadFile("/proc/net/dev")
-		if err != nil {
-			return
-		}
-
-		lines := strings.Split(string(data), "\n")
-		for i, line := range lines {
-			if i < 2 { // 헤더 스킵
-				continue
-			}
-
-			if strings.Contains(line, ":") {
-				parts := strings.Split(line, ":")
-				if len(parts) == 2 {
-					interfaceName := strings.TrimSpace(parts[0])
-					if interfaceName == "lo" { // 루프백 인터페이스 스킵
-						continue
-					}
-
-					fields := strings.Fields(parts[1])
-					if len(fields) >= 16 {
-						bytesRecv, _ := strconv.ParseUint(fields[0], 10, 64)
-						packetsRecv, _ := strconv.ParseUint(fields[1], 10, 64)
-						errorsRecv, _ := strconv.ParseUint(fields[2], 10, 64)
-						droppedRecv, _ := strconv.ParseUint(fields[3], 10, 64)
-						bytesSent, _ := strconv.ParseUint(fields[8], 10, 64)
-						packetsSent, _ := strconv.ParseUint(fields[9], 10, 64)
-						errorsSent, _ := strconv.ParseUint(fields[10], 10, 64)
-						droppedSent, _ := strconv.ParseUint(fields[11], 10, 64)
-
-						sm.metrics.Network = NetworkMetrics{
-							Interface:   interfaceName,
-							BytesRecv:   bytesRecv,
-							BytesSent:   bytesSent,
-							PacketsRecv: packetsRecv,
-							PacketsSent: packetsSent,
-							ErrorsRecv:  errorsRecv,
-							ErrorsSent:  errorsSent,
-							DroppedRecv: droppedRecv,
-							DroppedSent: droppedSent,
-						}
-						break // 첫 번째 활성 인터페이스만 사용
-					}
+	counters, err := psnet.IOCounters(true)
+	if err != nil {
+		return
+	}
+	loopback := map[string]bool{"lo": true, "lo0": true}
+	if interfaces, err := psnet.Interfaces(); err == nil {
+		for _, iface := range interfaces {
+			for _, flag := range iface.Flags {
+				if flag == "loopback" {
+					loopback[iface.Name] = true
 				}
 			}
 		}
 	}
+	sm.collectNetworkRates(counters, loopback)
+
+	var busiest *psnet.IOCountersStat
+	for i := range counters {
+		counter := &counters[i]
+		if loopback[counter.Name] {
+			continue
+		}
+		if busiest == nil || counter.BytesRecv+counter.BytesSent > busiest.BytesRecv+busiest.BytesSent {
+			busiest = counter
+		}
+	}
+	if busiest == nil {
+		return
+	}
+	network := &sm.metrics.Network
+	network.Interface = busiest.Name
+	network.BytesRecv = busiest.BytesRecv
+	network.BytesSent = busiest.BytesSent
+	network.PacketsRecv = busiest.PacketsRecv
+	network.PacketsSent = busiest.PacketsSent
+	network.ErrorsRecv = busiest.Errin
+	network.ErrorsSent = busiest.Errout
+	network.DroppedRecv = busiest.Dropin
+	network.DroppedSent = busiest.Dropout
 }
 
-// collectTemperatureMetrics 온도 메트릭 수집
+// collectTemperatureMetrics 온도 메트릭 수집 (센서가 없거나 권한이 없으면 0, 값을 추정하지 않음)
+// Linux hwmon, macOS SMC/HID 센서, Windows ACPI 열 영역 (관리자 권한 필요)
 func (sm *SystemMonitor) collectTemperatureMetrics() {
 	sm.metrics.Temperature.CoreTemps = make(map[string]float64)
 
-	if runtime.GOOS == "linux" {
-		// /sys/class/thermal/thermal_zone*/temp 파일들 확인
-		cmd := exec.Command("find", "/sys/class/thermal", "-name", "thermal_zone*", "-type", "d")
-		output, err := cmd.Output()
-		if err == nil {
-			thermalZones := strings.Split(strings.TrimSpace(string(output)), "\n")
-			for _, zone := range thermalZones {
-				if zone != "" {
-					tempFile := zone + "/temp"
-					if data, err := ioutil.ReadFile(tempFile); err == nil {
-						tempStr := strings.TrimSpace(string(data))
-						if temp, err := strconv.ParseFloat(tempStr, 64); err == nil {
-							temp = temp / 1000 // 밀리도에서 도로 변환
-							sm.metrics.Temperature.CoreTemps[zone] = temp
-							if sm.metrics.Temperature.CPUTemp == 0 || temp > sm.metrics.Temperature.CPUTemp {
-								sm.metrics.Temperature.CPUTemp = temp
-							}
-						}
-					}
-				}
-			}
+	// 일부 센서를 읽지 못해도 나머지 결과는 사용
+	temperatures, _ := sensors.SensorsTemperatures()
+	hottest := 0.0
+	for _, sensor := range temperatures {
+		temp := sensor.Temperature
+		if temp <= 0 || temp > 150 { // 읽기 실패 또는 비정상 값
+			continue
 		}
-
-		// sensors 명령어 시도
-		if sm.metrics.Temperature.CPUTemp == 0 {
-			cmd := exec.Command("sensors")
-			output, err := cmd.Output()
-			if err == nil {
-				lines := strings.Split(string(output), "\n")
-				for _, line := range lines {
-					if strings.Contains(line, "°C") {
-						parts := strings.Fields(line)
-						for _, part := range parts {
-							if strings.Contains(part, "°C") {
-								tempStr := strings.Split(part, "°C")[0]
-								if strings.HasPrefix(tempStr, "+") {
-									tempStr = tempStr[1:]
-								}
-								if temp, err := strconv.ParseFloat(tempStr, 64); err == nil {
-									if strings.Contains(strings.ToLower(line), "core") {
-										sm.metrics.Temperature.CoreTemps[line] = temp
-									}
-									if sm.metrics.Temperature.CPUTemp == 0 || temp > sm.metrics.Temperature.CPUTemp {
-										sm.metrics.Temperature.CPUTemp = temp
-									}
-								}
-							}
-						}
-					}
-				}
-			}
+		if temp > hottest {
+			hottest = temp
 		}
-	} else if runtime.GOOS == "darwin" {
-		// macOS용 개선된 온도 수집
-		sm.collectTemperatureMetricsMacOS()
-	}
-}
-
-// collectTemperatureMetricsMacOS macOS 전용 온도 메트릭 수집
-func (sm *SystemMonitor) collectTemperatureMetricsMacOS() {
-	// pmset 명령어로 배터리 온도 확인 (간접적인 시스템 온도)
-	cmd := exec.Command("pmset", "-g", "therm")
-	output, err := cmd.Output()
-	if err == nil {
-		lines := strings.Split(string(output), "\n")
-		for _, line := range lines {
-			if strings.Contains(line, "CPU die temperature") {
-				parts := strings.Fields(line)
-				for _, part := range parts {
-					if strings.Contains(part, "°C") {
-						tempStr := strings.TrimSuffix(part, "°C")
-						if temp, err := strconv.ParseFloat(tempStr, 64); err == nil {
-							sm.metrics.Temperature.CPUTemp = temp
-							break
-						}
-					}
-				}
+		switch kind := temperatureSensorKind(sensor.SensorKey); kind {
+		case "cpu":
+			sm.metrics.Temperature.CoreTemps[sensor.SensorKey] = temp
+			if temp > sm.metrics.Temperature.CPUTemp {
+				sm.metrics.Temperature.CPUTemp = temp
 			}
-		}
-	}
-
-	// GPU 온도 확인 (Apple Silicon의 경우)
-	gpuCmd := exec.Command("system_profiler", "SPDisplaysDataType")
-	gpuOutput, err := gpuCmd.Output()
-	if err == nil {
-		lines := strings.Split(string(gpuOutput), "\n")
-		for _, line := range lines {
-			if strings.Contains(line, "Temperature") {
-				parts := strings.Fields(line)
-				for _, part := range parts {
-					if strings.Contains(part, "°C") {
-						tempStr := strings.TrimSuffix(part, "°C")
-						if temp, err := strconv.ParseFloat(tempStr, 64); err == nil {
-							sm.metrics.Temperature.GPUTemp = temp
-							break
-						}
-					}
-				}
+		case "gpu":
+			if temp > sm.metrics.Temperature.GPUTemp {
+				sm.metrics.Temperature.GPUTemp = temp
+			}
+		case "board":
+			if temp > sm.metrics.Temperature.MotherboardTemp {
+				sm.metrics.Temperature.MotherboardTemp = temp
 			}
 		}
 	}
 
-	// 기본값 설정 (수집 실패 시)
+	// CPU 로 구분되는 센서가 없으면 (ACPI 열 영역만 있는 VM/노트북 등) 가장 높은 온도 사용
 	if sm.metrics.Temperature.CPUTemp == 0 {
-		sm.metrics.Temperature.CPUTemp = 45.0 // 일반적인 CPU 온도
+		sm.metrics.Temperature.CPUTemp = hottest
 	}
-	if sm.metrics.Temperature.GPUTemp == 0 {
-		sm.metrics.Temperature.GPUTemp = 50.0 // 일반적인 GPU 온도
+}
+
+// temperatureSensorKind 센서 이름으로 CPU/GPU/메인보드 구분
+// 예: coretemp_package_id_0, k10temp_tctl (Linux), TC0P, PMU tdie1 (macOS), TG0P (macOS GPU)
+func temperatureSensorKind(key string) string {
+	key = strings.ToLower(key)
+	switch {
+	case strings.Contains(key, "gpu") || strings.HasPrefix(key, "tg") || strings.Contains(key, "amdgpu") || strings.Contains(key, "nouveau"):
+		return "gpu"
+	case strings.Contains(key, "core") || strings.Contains(key, "cpu") || strings.Contains(key, "package") ||
+		strings.Contains(key, "tctl") || strings.Contains(key, "tdie") || strings.Contains(key, "k10temp") ||
+		strings.Contains(key, "soc") || strings.HasPrefix(key, "tc"):
+		return "cpu"
+	case strings.Contains(key, "pch") || strings.Contains(key, "board") || strings.Contains(key, "acpitz"):
+		return "board"
 	}
+	return ""
 }
 
-// collectLoadMetrics 로드 평균 수집
+// collectLoadMetrics 로드 평균 수집 (Windows 는 프로세서 대기열 길이로 추정한 값)
 func (sm *SystemMonitor) collectLoadMetrics() {
-	if runtime.GOOS == "linux" || runtime.GOOS == "darwin" {
-		data, err := ioutil.ReadFile("/proc/loadavg")
-		if err != nil {
-			// macOS나 다른 시스템에서는 uptime 명령어 사용
-			cmd := exec.Command("uptime")
-			output, err := cmd.Output()
-			if err == nil {
-				line := string(output)
-				// macOS uptime 형식: "load averages: 4.20 4.61 3.85"
-				if strings.Contains(line, "load averages:") {
-					parts := strings.Split(line, "load averages:")
-					if len(parts) == 2 {
-						loadStr := strings.TrimSpace(parts[1])
-						loadParts := strings.Fields(loadStr)
-						if len(loadParts) >= 3 {
-							load1, _ := strconv.ParseFloat(loadParts[0], 64)
-							load5, _ := strconv.ParseFloat(loadParts[1], 64)
-							load15, _ := strconv.ParseFloat(loadParts[2], 64)
-
-							sm.metrics.LoadAverage = LoadMetrics{
-								Load1Min:  load1,
-								Load5Min:  load5,
-								Load15Min: load15,
-							}
-						}
-					}
-				}
-			}
-			return
+	if avg, err := load.Avg(); err == nil {
+		sm.metrics.LoadAverage = LoadMetrics{
+			Load1Min:  avg.Load1,
+			Load5Min:  avg.Load5,
+			Load15Min: avg.Load15,
 		}
+	}
+	sm.normalizeLoadMetrics()
+}
 
-		parts := strings.Fields(string(data))
-		if len(parts) >= 3 {
-			load1, _ := strconv.ParseFloat(parts[0], 64)
-			load5, _ := strconv.ParseFloat(parts[1], 64)
-			load15, _ := strconv.ParseFloat(parts[2], 64)
-
-			sm.metrics.LoadAverage = LoadMetrics{
-				Load1Min:  load1,
-				Load5Min:  load5,
-				Load15Min: load15,
-			}
-		}
+// normalizeLoadMetrics 로드 평균을 코어 수로 나눈 값 계산
+func (sm *SystemMonitor) normalizeLoadMetrics() {
+	cores := float64(runtime.NumCPU())
+	if cores <= 0 {
+		return
 	}
+	sm.metrics.LoadAverage.Load1MinPerCore = sm.metrics.LoadAverage.Load1Min / cores
+	sm.metrics.LoadAverage.Load5MinPerCore = sm.metrics.LoadAverage.Load5Min / cores
+	sm.metrics.LoadAverage.Load15MinPerCore = sm.metrics.LoadAverage.Load15Min / cores
 }
 
-// collectProcessMetrics 프로세스 메트릭 수집
+// collectProcessMetrics 프로세스 수와 상태별 수 수집
+// Linux 는 /proc 의 프로세스 상태, macOS 는 실행 중 수만 (나머지는 대기), Windows 는 상태를 구분하지 않음
 func (sm *SystemMonitor) collectProcessMetrics() {
-	cmd := exec.Command("ps", "aux")
-	output, err := cmd.Output()
+	pids, err := process.Pids()
 	if err != nil {
 		return
 	}
-
-	lines := strings.Split(string(output), "\n")
-	sm.metrics.ProcessCount.Total = len(lines) - 2 // 헤더와 빈 줄 제외
-
-	// 간단한 프로세스 상태 카운트
-	sm.metrics.ProcessCount.Running = sm.metrics.ProcessCount.Total
-	sm.metrics.ProcessCount.Sleeping = 0
-	sm.metrics.ProcessCount.Stopped = 0
-	sm.metrics.ProcessCount.Zombie = 0
+	counts := ProcessMetrics{Total: len(pids)}
+
+	switch runtime.GOOS {
+	case "linux":
+		for _, pid := range pids {
+			proc, err := process.NewProcess(pid)
+			if err != nil {
+				continue // 조회 중 종료된 프로세스
+			}
+			status, err := proc.Status()
+			if err != nil || len(status) == 0 {
+				continue
+			}
+			switch status[0] {
+			case process.Running:
+				counts.Running++
+			case process.Stop:
+				counts.Stopped++
+			case process.Zombie:
+				counts.Zombie++
+			default:
+				counts.Sleeping++
+			}
+		}
+	case "windows":
+		counts.Running = counts.Total
+	default:
+		if misc, err := load.Misc(); err == nil {
+			counts.Running = misc.ProcsRunning
+			counts.Sleeping = counts.Total - misc.ProcsRunning
+		}
+	}
+	sm.metrics.ProcessCount = counts
 }
 
 // collectIPInformation IP 정보 수집 (개선된 버전)
@@ -850,15 +765,22 @@ func (sm *SystemMonitor) getPublicIP() string {
 		"https://checkip.amazonaws.com",
 	}
 
+	// curl 없는 최소 이미지에서도 동작하도록 net/http 로 직접 조회
+	client := &http.Client{Timeout: publicIPTimeout}
 	for _, service := range services {
-		cmd := exec.Command("curl", "-s", "--connect-timeout", "3", "--max-time", "5", service)
-		output, err := cmd.Output()
-		if err == nil {
-			ip := strings.TrimSpace(string(output))
-			// IPv4 주소인지 확인
-			if net.ParseIP(ip) != nil && strings.Contains(ip, ".") {
-				return ip
-			}
+		resp, err := client.Get(service)
+		if err != nil {
+			continue
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 64))
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK {
+			continue
+		}
+		ip := strings.TrimSpace(string(body))
+		// IPv4 주소인지 확인
+		if net.ParseIP(ip) != nil && strings.Contains(ip, ".") {
+			return ip
 		}
 	}
 	return ""
@@ -942,6 +864,57 @@ func (sm *SystemMonitor) checkAlerts() {
 		sm.sendAlert(alert)
 	}
 
+	// 스왑 사용률 체크 (스왑이 구성된 경우에만)
+	if sm.metrics.Memory.SwapTotalMB > 0 && sm.metrics.Memory.SwapUsagePercent > sm.thresholds.SwapPercent {
+		alert := SystemAlert{
+			Level:     "HIGH",
+			Type:      "SWAP",
+			Message:   fmt.Sprintf("스왑 사용률이 높습니다: %.1f%% (%.0f/%.0f MB)", sm.metrics.Memory.SwapUsagePercent, sm.metrics.Memory.SwapUsedMB, sm.metrics.Memory.SwapTotalMB),
+			Value:     sm.metrics.Memory.SwapUsagePercent,
+			Threshold: sm.thresholds.SwapPercent,
+			Metrics:   *sm.metrics,
+			Timestamp: time.Now(),
+			Suggestions: []string{
+				"📊 메모리를 많이 사용하는 프로세스 확인: ps aux --sort=-rss | head",
+				"🔍 스왑 인/아웃 빈도 확인: vmstat 1",
+				"🧠 물리 메모리 증설 또는 워크로드 분산 검토",
+			},
+		}
+		sm.sendAlert(alert)
+	}
+
+	// 메모리 압박 체크
+	if sm.metrics.Memory.PressureLevel == MemoryPressureWarn || sm.metrics.Memory.PressureLevel == MemoryPressureCritical {
+		level := "HIGH"
+		if sm.metrics.Memory.PressureLevel == MemoryPressureCritical {
+			level = "CRITICAL"
+		}
+		alert := SystemAlert{
+			Level:     level,
+			Type:      "MEMORY_PRESSURE",
+			Message:   fmt.Sprintf("메모리 압박 상태입니다: %s (%.1f%%)", sm.metrics.Memory.PressureLevel, sm.metrics.Memory.PressurePercent),
+			Value:     sm.metrics.Memory.PressurePercent,
+			Threshold: sm.thresholds.MemoryPressure,
+			Metrics:   *sm.metrics,
+			Timestamp: time.Now(),
+			Suggestions: []string{
+				"🔍 메모리 회수 지연 확인: cat /proc/pressure/memory 또는 memory_pressure",
+				"📊 메모리 사용량이 높은 프로세스 확인 및 재시작 검토",
+				"⚠️  OOM Killer 동작 여부 확인: dmesg | grep -i oom",
+			},
+		}
+		sm.sendAlert(alert)
+	}
+
+	// CPU / I/O 정체(PSI) 체크 - 메모리 PSI는 위의 메모리 압박 체크에서 처리
+	sm.checkPressureAlerts()
+
+	// 새 수신 대기 포트 / 포트별 연결 급증 체크
+	sm.checkConnectionAlerts()
+
+	// 디스크 I/O / 네트워크 대역폭 포화 체크
+	sm.checkThroughputAlerts()
+
 	// 디스크 사용률 체크
 	for _, disk := range sm.metrics.Disk {
 		if disk.UsagePercent > sm.thresholds.DiskPercent {
@@ -987,7 +960,7 @@ func (sm *SystemMonitor) checkAlerts() {
 		alert := SystemAlert{
 			Level:     "MEDIUM",
 			Type:      "LOAD",
-			Message:   fmt.Sprintf("시스템 로드가 높습니다: %.2f", sm.metrics.LoadAverage.Load1Min),
+			Message:   fmt.Sprintf("시스템 로드가 높습니다: %.2f (코어당 %.2f, 임계값 %.2f/코어)", sm.metrics.LoadAverage.Load1Min, sm.metrics.LoadAverage.Load1MinPerCore, sm.thresholds.LoadPerCore),
 			Value:     sm.metrics.LoadAverage.Load1Min,
 			Threshold: sm.thresholds.LoadAverage,
 			Metrics:   *sm.metrics,
@@ -1004,6 +977,10 @@ func (sm *SystemMonitor) checkAlerts() {
 
 // sendAlert 알림 전송
 func (sm *SystemMonitor) sendAlert(alert SystemAlert) {
+	if alert.Level == "CRITICAL" {
+		sm.criticalRaised = true
+	}
+
 	select {
 	case sm.alertChannel <- alert:
 	default:
@@ -1011,6 +988,14 @@ func (sm *SystemMonitor) sendAlert(alert SystemAlert) {
 	}
 }
 
+// resolveClearedAlerts 이번 주기에 CRITICAL 조건이 없으면 열린 시스템 인시던트 해결 (PagerDuty 등)
+func (sm *SystemMonitor) resolveClearedAlerts() {
+	if sm.criticalRaised || sm.isSystemDown || sm.alertDispatcher == nil {
+		return
+	}
+	sm.alertDispatcher.Resolve(AlertTypeSystem, sm.metrics.IPInfo.Hostname)
+}
+
 // updateHeartbeat 하트비트 업데이트
 func (sm *SystemMonitor) updateHeartbeat() {
 	sm.lastHeartbeat = time.Now()
@@ -1051,14 +1036,14 @@ func (sm *SystemMonitor) checkSystemHealth() {
 	}
 	
 	// 시스템 로드 과부하 체크
-	if sm.metrics.LoadAverage.Load1Min > float64(runtime.NumCPU())*3.0 {
-		sm.sendCriticalAlert("CRITICAL_LOAD", fmt.Sprintf("시스템 로드가 과도하게 높습니다: %.2f", sm.metrics.LoadAverage.Load1Min))
+	if sm.metrics.LoadAverage.Load1MinPerCore > CriticalLoadPerCore {
+		sm.sendCriticalAlert("CRITICAL_LOAD", fmt.Sprintf("시스템 로드가 과도하게 높습니다: %.2f (코어당 %.2f)", sm.metrics.LoadAverage.Load1Min, sm.metrics.LoadAverage.Load1MinPerCore))
 	}
 }
 
 // sendPeriodicReport 정기 시스템 상태 보고서 전송
 func (sm *SystemMonitor) sendPeriodicReport() {
-	if sm.emailService == nil && sm.slackService == nil {
+	if sm.alertDispatcher == nil || !sm.alertDispatcher.HasSinks() {
 		return
 	}
 	
@@ -1067,41 +1052,36 @@ func (sm *SystemMonitor) sendPeriodicReport() {
 		sm.metrics.IPInfo.Hostname, 
 		time.Now().Format("2006-01-02 15:04"))
 	
-	// 이메일 전송
-	if sm.emailService != nil {
-		go func() {
-			if err := sm.emailService.SendEmail(subject, report); err != nil {
-				// 이메일 전송 실패 시 로그만 남김
-				fmt.Printf("⚠️  정기 보고서 이메일 전송 실패: %v\n", err)
-			}
-		}()
-	}
-	
-	// Slack 전송
-	if sm.slackService != nil {
-		// Slack용 간단한 요약 메시지 생성
-		summary := fmt.Sprintf(`📊 시스템 상태 보고서
+	// Slack용 간단한 요약 메시지 생성
+	summary := fmt.Sprintf(`📊 시스템 상태 보고서
 🖥️  %s
 ⏰ %s
 
 💻 CPU: %.1f%% | 🧠 메모리: %.1f%% | 🌡️  온도: %.1f°C
-⚖️  로드: %.2f | 🔄 프로세스: %d개
+⚖️  로드: %.2f (코어당 %.2f) | 🔄 프로세스: %d개
 
 상세 정보는 이메일을 확인하세요.`,
-			sm.metrics.IPInfo.Hostname,
-			time.Now().Format("2006-01-02 15:04:05"),
-			sm.metrics.CPU.UsagePercent,
-			sm.metrics.Memory.UsagePercent,
-			sm.metrics.Temperature.CPUTemp,
-			sm.metrics.LoadAverage.Load1Min,
-			sm.metrics.ProcessCount.Total)
-			
-		go func() {
-			if err := sm.slackService.SendSimpleMessage(summary); err != nil {
-				fmt.Printf("⚠️  정기 보고서 Slack 전송 실패: %v\n", err)
-			}
-		}()
-	}
+		sm.metrics.IPInfo.Hostname,
+		time.Now().Format("2006-01-02 15:04:05"),
+		sm.metrics.CPU.UsagePercent,
+		sm.metrics.Memory.UsagePercent,
+		sm.metrics.Temperature.CPUTemp,
+		sm.metrics.LoadAverage.Load1Min,
+		sm.metrics.LoadAverage.Load1MinPerCore,
+		sm.metrics.ProcessCount.Total)
+
+	sm.alertDispatcher.Dispatch(Alert{
+		Type:     AlertTypeReport,
+		Severity: AlertSeverityInfo,
+		Title:    subject,
+		Body:     report,
+		Host:     sm.metrics.IPInfo.Hostname,
+		Slack: &SlackMessage{
+			Text:      summary,
+			Username:  DefaultSlackUsername,
+			IconEmoji: ":robot_face:",
+		},
+	})
 	
 	sm.lastReportTime = time.Now()
 }
@@ -1123,7 +1103,7 @@ func (sm *SystemMonitor) sendSystemDownAlert() {
 		sm.lastHeartbeat.Format("2006-01-02 15:04:05"),
 		time.Since(sm.lastHeartbeat).String())
 	
-	sm.sendEmergencyAlert("🚨 시스템 다운 감지", alert)
+	sm.sendEmergencyAlert(AlertSeverityCritical, "🚨 시스템 다운 감지", alert, alertThreadKey("heartbeat", sm.metrics.IPInfo.Hostname))
 }
 
 // sendSystemRecoveryAlert 시스템 복구 알림 전송
@@ -1141,7 +1121,8 @@ func (sm *SystemMonitor) sendSystemRecoveryAlert() {
 		time.Now().Format("2006-01-02 15:04:05"),
 		time.Since(sm.lastHeartbeat).String())
 	
-	sm.sendEmergencyAlert("✅ 시스템 복구 알림", alert)
+	// 다운 알림과 같은 스레드로 묶어 복구 여부를 한곳에서 확인
+	sm.sendEmergencyAlert(AlertSeverityInfo, "✅ 시스템 복구 알림", alert, alertThreadKey("heartbeat", sm.metrics.IPInfo.Hostname))
 }
 
 // sendCriticalAlert 위험 상황 알림 전송
@@ -1160,28 +1141,33 @@ func (sm *SystemMonitor) sendCriticalAlert(alertType, message string) {
 		time.Now().Format("2006-01-02 15:04:05"),
 		message)
 	
-	sm.sendEmergencyAlert(fmt.Sprintf("🚨 %s", alertType), alert)
+	sm.criticalRaised = true
+	// CRITICAL_CPU 등은 같은 메트릭의 임계값 알림(CPU 등)과 한 스레드로 묶음
+	thread := alertThreadKey(AlertTypeSystem, sm.metrics.IPInfo.Hostname, strings.TrimPrefix(alertType, "CRITICAL_"))
+	sm.sendEmergencyAlert(AlertSeverityCritical, fmt.Sprintf("🚨 %s", alertType), alert, thread)
 }
 
-// sendEmergencyAlert 긴급 알림 전송 (이메일 + Slack)
-func (sm *SystemMonitor) sendEmergencyAlert(subject, message string) {
-	// 이메일 즉시 전송
-	if sm.emailService != nil {
-		go func() {
-			if err := sm.emailService.SendEmail(subject, message); err != nil {
-				fmt.Printf("❌ 긴급 알림 이메일 전송 실패: %v\n", err)
-			}
-		}()
-	}
-	
-	// Slack 즉시 전송
-	if sm.slackService != nil {
-		go func() {
-			if err := sm.slackService.SendSimpleMessage(message); err != nil {
-				fmt.Printf("❌ 긴급 알림 Slack 전송 실패: %v\n", err)
-			}
-		}()
+// sendEmergencyAlert 긴급 알림 전송 (설정된 모든 알림 채널)
+// 복구 알림은 AlertSeverityInfo로 보내 당직 호출(PagerDuty) 대상에서 제외
+// thread 는 이메일 스레드를 묶는 인시던트 키
+func (sm *SystemMonitor) sendEmergencyAlert(severity, subject, message, thread string) {
+	if sm.alertDispatcher == nil {
+		return
 	}
+
+	sm.alertDispatcher.Dispatch(Alert{
+		Type:     AlertTypeSystem,
+		Severity: severity,
+		Title:    subject,
+		Body:     message,
+		Host:     sm.metrics.IPInfo.Hostname,
+		Thread:   thread,
+		Slack: &SlackMessage{
+			Text:      message,
+			Username:  DefaultSlackUsername,
+			IconEmoji: ":robot_face:",
+		},
+	})
 }
 
 // GetAlertChannel 알림 채널 반환
@@ -1232,6 +1218,8 @@ func (sm *SystemMonitor) GetSystemReport() string {
   - 총 메모리: %.1f GB
   - 사용 중: %.1f GB
   - 사용 가능: %.1f GB
+  - 스왑: %.1f%% 사용 (%.0f/%.0f MB, 임계값: %.1f%%)
+  - 메모리 압박: %s (%.1f%%, 임계값: %.1f%%)
 
 💾 디스크 정보:`,
 		time.Now().Format("2006-01-02 15:04:05"),
@@ -1246,6 +1234,8 @@ func (sm *SystemMonitor) GetSystemReport() string {
 		metrics.Memory.TotalMB/1024,
 		metrics.Memory.UsedMB/1024,
 		metrics.Memory.AvailableMB/1024,
+		metrics.Memory.SwapUsagePercent, metrics.Memory.SwapUsedMB, metrics.Memory.SwapTotalMB, sm.thresholds.SwapPercent,
+		metrics.Memory.PressureLevel, metrics.Memory.PressurePercent, sm.thresholds.MemoryPressure,
 	)
 
 	for _, disk := range metrics.Disk {
@@ -1261,13 +1251,16 @@ func (sm *SystemMonitor) GetSystemReport() string {
 
 ⚖️  시스템 로드:
   - 1분: %.2f, 5분: %.2f, 15분: %.2f (임계값: %.1f)
+  - 코어당: %.2f, %.2f, %.2f (임계값: %.2f/코어)
 
 🔄 프로세스:
   - 총 프로세스 수: %d개
-`,
+%s`,
 		metrics.Temperature.CPUTemp, sm.thresholds.CPUTemp,
 		metrics.LoadAverage.Load1Min, metrics.LoadAverage.Load5Min, metrics.LoadAverage.Load15Min, sm.thresholds.LoadAverage,
+		metrics.LoadAverage.Load1MinPerCore, metrics.LoadAverage.Load5MinPerCore, metrics.LoadAverage.Load15MinPerCore, sm.thresholds.LoadPerCore,
 		metrics.ProcessCount.Total,
+		sm.generatePressureReport(metrics),
 	)
 
 	// 네트워크 정보 추가
@@ -1285,6 +1278,12 @@ func (sm *SystemMonitor) GetSystemReport() string {
 		)
 	}
 
+	// 디스크 I/O / 네트워크 처리량 추가
+	report += generateThroughputSection(metrics)
+
+	// 수신 대기 포트 / 연결 현황 추가
+	report += generateConnectionSection(metrics.Connections)
+
 	// AI 전문가 진단 추가
 	report += sm.generateExpertDiagnosis(metrics)
 
@@ -1293,9 +1292,9 @@ func (sm *SystemMonitor) GetSystemReport() string {
 
 // generateExpertDiagnosis AI 전문가 진단 생성
 func (sm *SystemMonitor) generateExpertDiagnosis(metrics SystemMetrics) string {
-	// Gemini 서비스가 있으면 AI 진단 사용
-	if geminiService != nil {
-		diagnosis, err := geminiService.AnalyzeSystemDiagnosis(metrics)
+	// LLM 백엔드가 있으면 AI 진단 사용
+	if provider := currentLLMProvider(); provider != nil {
+		diagnosis, err := provider.AnalyzeSystemDiagnosis(metrics)
 		if err != nil {
 			fmt.Printf("⚠️  AI 진단 실패, 기본 진단 사용: %v\n", err)
 		} else {
@@ -1357,6 +1356,16 @@ func (sm *SystemMonitor) generateExpertDiagnosis(metrics SystemMetrics) string {
 		recommendations = append(recommendations, "• 네트워크 인터페이스 상태 확인")
 	}
 
+	// 수신 대기 포트 / 연결 수 진단
+	connectionIssues, connectionRecommendations := connectionFindings(metrics.Connections)
+	issues = append(issues, connectionIssues...)
+	recommendations = append(recommendations, connectionRecommendations...)
+	if len(metrics.Connections.Unexpected) > 0 {
+		severity = "🔴 CRITICAL"
+	} else if len(metrics.Connections.Spikes) > 0 && severity == "" {
+		severity = "🟡 WARNING"
+	}
+
 	// 전반적인 건강도 평가
 	if len(issues) == 0 {
 		overallHealth = "🟢 EXCELLENT"
@@ -1406,7 +1415,7 @@ func (sm *SystemMonitor) generateExpertDiagnosis(metrics SystemMetrics) string {
 • 디스크 정리 및 최적화
 • 네트워크 연결 상태 모니터링
 
-💡 Gemini API 키를 설정하면 더 정교한 AI 진단을 받을 수 있습니다.
+💡 AI 백엔드(Gemini/OpenAI/Anthropic/Ollama)를 설정하면 더 정교한 AI 진단을 받을 수 있습니다.
 🎯 다음 진단 예정: %s
 `,
 		time.Now().Add(5*time.Minute).Format("15:04:05"))
@@ -1414,11 +1423,85 @@ func (sm *SystemMonitor) generateExpertDiagnosis(metrics SystemMetrics) string {
 	return diagnosis
 }
 
+// SetReportSchedule 시간대 기반 정기 보고서 스케줄 설정 (Start 이전에 호출)
+func (sm *SystemMonitor) SetReportSchedule(schedule *ReportSchedule) {
+	sm.reportSchedule = schedule
+	sm.periodicReport = schedule != nil || sm.periodicReport
+}
+
 // SetThresholds 임계값 설정
+// LoadPerCore가 지정된 경우 LoadAverage는 현재 코어 수 기준으로 다시 계산
 func (sm *SystemMonitor) SetThresholds(thresholds SystemThresholds) {
+	if thresholds.LoadPerCore > 0 {
+		thresholds.LoadAverage = thresholds.LoadPerCore * float64(runtime.NumCPU())
+	}
 	sm.thresholds = thresholds
 }
 
+// ApplyConfig 설정 파일의 시스템 모니터링 임계값 적용
+// 설정 재로드 시에도 호출되며, 0 이하 값은 기존 임계값을 유지
+func (sm *SystemMonitor) ApplyConfig(config *Config) {
+	if config == nil {
+		return
+	}
+
+	thresholds := sm.thresholds
+	if config.SystemMonitoring.CPUThreshold > 0 {
+		thresholds.CPUPercent = config.SystemMonitoring.CPUThreshold
+	}
+	if config.SystemMonitoring.MemoryThreshold > 0 {
+		thresholds.MemoryPercent = config.SystemMonitoring.MemoryThreshold
+	}
+	if config.SystemMonitoring.DiskThreshold > 0 {
+		thresholds.DiskPercent = config.SystemMonitoring.DiskThreshold
+	}
+	if config.SystemMonitoring.TemperatureThreshold > 0 {
+		thresholds.CPUTemp = config.SystemMonitoring.TemperatureThreshold
+	}
+	if config.SystemMonitoring.LoadPerCoreThreshold > 0 {
+		thresholds.LoadPerCore = config.SystemMonitoring.LoadPerCoreThreshold
+	}
+	if config.SystemMonitoring.SwapThreshold > 0 {
+		thresholds.SwapPercent = config.SystemMonitoring.SwapThreshold
+	}
+	if config.SystemMonitoring.MemoryPressureThreshold > 0 {
+		thresholds.MemoryPressure = config.SystemMonitoring.MemoryPressureThreshold
+	}
+	if config.SystemMonitoring.CPUPressureThreshold > 0 {
+		thresholds.CPUPressure = config.SystemMonitoring.CPUPressureThreshold
+	}
+	if config.SystemMonitoring.IOPressureThreshold > 0 {
+		thresholds.IOPressure = config.SystemMonitoring.IOPressureThreshold
+	}
+	if config.SystemMonitoring.ConnectionSpikeThreshold > 0 {
+		thresholds.ConnectionSpike = config.SystemMonitoring.ConnectionSpikeThreshold
+	}
+	if config.SystemMonitoring.ConnectionMinimum > 0 {
+		thresholds.ConnectionMin = config.SystemMonitoring.ConnectionMinimum
+	}
+	if config.SystemMonitoring.DiskBusyThreshold > 0 {
+		thresholds.DiskBusy = config.SystemMonitoring.DiskBusyThreshold
+	}
+	if config.SystemMonitoring.DiskIOPSThreshold > 0 {
+		thresholds.DiskIOPS = config.SystemMonitoring.DiskIOPSThreshold
+	}
+	if config.SystemMonitoring.DiskThroughputThreshold > 0 {
+		thresholds.DiskMBps = config.SystemMonitoring.DiskThroughputThreshold
+	}
+	if config.SystemMonitoring.NetworkUtilizationThreshold > 0 {
+		thresholds.NetworkPercent = config.SystemMonitoring.NetworkUtilizationThreshold
+	}
+	if config.SystemMonitoring.NetworkThroughputThreshold > 0 {
+		thresholds.NetworkMbps = config.SystemMonitoring.NetworkThroughputThreshold
+	}
+	if config.SystemMonitoring.NetworkErrorThreshold > 0 {
+		thresholds.NetworkErrors = config.SystemMonitoring.NetworkErrorThreshold
+	}
+	sm.SetExpectedPorts(config.SystemMonitoring.ExpectedPorts)
+
+	sm.SetThresholds(thresholds)
+}
+
 // GetThresholds 현재 임계값 반환
 func (sm *SystemMonitor) GetThresholds() SystemThresholds {
 	return sm.thresholds
@@ -0,0 +1,204 @@
+/*
+Telegram Bot Service Module
+===========================
+
+# Telegram Bot API를 통한 알림 서비스
+
+주요 기능:
+- Bot API sendMessage 로 지정된 채팅(개인/그룹/채널)에 알림 전송
+- 공통 알림 섹션을 MarkdownV2 메시지로 렌더링 (채널 상세 수준 summary/full), 시스템 보고서는 코드 블록
+- AlertSink 인터페이스 구현 (AlertDispatcher 연동)
+- 메시지 길이 제한(4096자) 대응
+
+설정 방법:
+- @BotFather 에서 봇 생성 후 토큰 발급 (-telegram-token)
+- 봇을 채팅에 초대한 뒤 채팅 ID 확인 (-telegram-chat-id)
+*/
+package main
+
+import (
+	"bytes"         // 요청 본문 버퍼
+	"encoding/json" // JSON 인코딩/디코딩
+	"fmt"           // 형식화된 I/O
+	"net/http"      // HTTP 클라이언트
+	"strings"       // 문자열 처리
+	"time"          // 시간 처리
+)
+
+// Telegram Bot API 설정
+const (
+	TelegramAPIBaseURL     = "https://api.telegram.org" // Bot API 기본 URL
+	TelegramMaxMessageSize = 4096                       // 메시지 최대 길이 (문자)
+)
+
+// MarkdownV2 특수 문자 이스케이프 (코드 밖의 모든 특수 문자는 \ 로 이스케이프해야 함)
+var (
+	telegramMarkdownEscaper = strings.NewReplacer(
+		"\\", "\\\\", "_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]", "(", "\\(", ")", "\\)",
+		"~", "\\~", "`", "\\`", ">", "\\>", "#", "\\#", "+", "\\+", "-", "\\-", "=", "\\=",
+		"|", "\\|", "{", "\\{", "}", "\\}", ".", "\\.", "!", "\\!",
+	)
+	telegramCodeEscaper = strings.NewReplacer("\\", "\\\\", "`", "\\`")
+)
+
+// TelegramService Telegram 봇 메시지 전송 서비스
+type TelegramService struct {
+	config *TelegramConfig
+	logger Logger
+	client *http.Client
+}
+
+// NewTelegramService 새로운 Telegram 서비스 생성
+func NewTelegramService(config *TelegramConfig, logger Logger) *TelegramService {
+	return &TelegramService{
+		config: config,
+		logger: logger,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SendMessage Markdown 형식 메시지 전송
+func (ts *TelegramService) SendMessage(text string) error {
+	return ts.sendMessageTo(ts.config.ChatID, text)
+}
+
+// sendMessageTo 지정한 채팅으로 메시지 전송
+func (ts *TelegramService) sendMessageTo(chatID, text string) error {
+	if !ts.config.Enabled {
+		return nil
+	}
+
+	if runes := []rune(text); len(runes) > TelegramMaxMessageSize {
+		text = string(runes[:TelegramMaxMessageSize-1]) + "…"
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               "MarkdownV2",
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal telegram message: %v", err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/sendMessage", TelegramAPIBaseURL, ts.config.BotToken)
+	resp, err := ts.client.Post(url, "application/json", bytes.NewBuffer(payload))
+	if err != nil {
+		// 요청 URL에 토큰이 포함되므로 오류 메시지에 URL을 노출하지 않음
+		return fmt.Errorf("failed to send telegram message: %v", strings.ReplaceAll(err.Error(), ts.config.BotToken, "***"))
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to parse telegram response: %v", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram API error (status %d): %s", resp.StatusCode, result.Description)
+	}
+
+	ts.logger.Infof("✅ Telegram message sent successfully to chat: %s", chatID)
+	return nil
+}
+
+// Send AlertSink 구현 - 알림 유형별 Markdown 메시지로 변환하여 전송
+func (ts *TelegramService) Send(alert Alert) error {
+	var text string
+	switch {
+	case len(alert.Sections) > 0:
+		text = ts.CreateSectionedAlert(alert)
+	case alert.Type == AlertTypeReport:
+		text = ts.CreateReportMessage(alert)
+	default:
+		text = ts.CreateGenericAlert(alert)
+	}
+	if alert.Target != "" {
+		return ts.sendMessageTo(alert.Target, text) // 라우팅 규칙이 지정한 채팅
+	}
+	return ts.SendMessage(text)
+}
+
+// CreateSectionedAlert 섹션 기반 알림(로그인, AI, 시스템, 에러) Markdown 메시지 생성
+// 이메일/Slack 과 같은 섹션을 채널 상세 수준(summary/full)에 맞춰 표시
+func (ts *TelegramService) CreateSectionedAlert(alert Alert) string {
+	var sb strings.Builder
+	sb.WriteString("*" + escapeTelegramMarkdown(alert.headline()) + "*\n")
+
+	sections, omitted := alert.visibleSections(alert.Detail)
+	for _, section := range sections {
+		sb.WriteString("\n")
+		if section.Title != "" {
+			sb.WriteString("*" + escapeTelegramMarkdown(section.Title) + "*\n")
+		}
+		for _, field := range section.Fields {
+			value := escapeTelegramMarkdown(field.Value)
+			if field.Code {
+				value = "`" + codeSafe(field.Value) + "`"
+			}
+			sb.WriteString(escapeTelegramMarkdown(field.Label+": ") + value + "\n")
+		}
+		if section.Text != "" {
+			sb.WriteString(escapeTelegramMarkdown(strings.TrimRight(section.Text, "\n")) + "\n")
+		}
+	}
+	if omitted > 0 {
+		sb.WriteString("\n_" + escapeTelegramMarkdown(alertSummaryNote(omitted)) + "_\n")
+	}
+	sb.WriteString("\n" + escapeTelegramMarkdown(fmt.Sprintf("🖥️ Host: %s\n🕐 %s", alert.Host, alert.Timestamp.Format("2006-01-02 15:04:05"))))
+
+	return sb.String()
+}
+
+// CreateReportMessage 시스템 보고서 Markdown 메시지 생성 (본문은 서식 유지를 위해 코드 블록)
+func (ts *TelegramService) CreateReportMessage(alert Alert) string {
+	return fmt.Sprintf("📊 *%s*\n\n```\n%s\n```",
+		escapeTelegramMarkdown(alert.Title),
+		codeSafe(alert.Body),
+	)
+}
+
+// CreateGenericAlert 기타 알림(시스템, 에러, 재부팅 등) Markdown 메시지 생성
+func (ts *TelegramService) CreateGenericAlert(alert Alert) string {
+	return fmt.Sprintf("%s *%s*\n%s\n\n```\n%s\n```",
+		severityEmoji(alert.Severity),
+		escapeTelegramMarkdown(alert.Title),
+		escapeTelegramMarkdown(fmt.Sprintf("🖥️ Host: %s\n🕐 %s", alert.Host, alert.Timestamp.Format("2006-01-02 15:04:05"))),
+		codeSafe(alert.Body),
+	)
+}
+
+// SendTestMessage 테스트 메시지 전송
+func (ts *TelegramService) SendTestMessage() error {
+	return ts.SendMessage(fmt.Sprintf("🧪 *%s*\n\n%s\n💬 Chat ID: `%s`\n🕐 %s",
+		escapeTelegramMarkdown(AppName+" 테스트 메시지"),
+		escapeTelegramMarkdown(fmt.Sprintf("✅ %s v%s Telegram 연동이 정상적으로 작동합니다!", AppName, AppVersion)),
+		codeSafe(ts.config.ChatID),
+		escapeTelegramMarkdown(time.Now().Format("2006-01-02 15:04:05")),
+	))
+}
+
+// severityEmoji 심각도별 이모지
+func severityEmoji(severity string) string {
+	switch severity {
+	case AlertSeverityCritical:
+		return "🚨"
+	case AlertSeverityWarning:
+		return "⚠️"
+	default:
+		return "ℹ️"
+	}
+}
+
+// escapeTelegramMarkdown 일반 텍스트의 MarkdownV2 특수 문자 이스케이프
+func escapeTelegramMarkdown(text string) string {
+	return telegramMarkdownEscaper.Replace(text)
+}
+
+// codeSafe 코드(`...`, ```...```) 안에 넣을 값 정리 (MarkdownV2 에서는 ` 와 \ 만 이스케이프)
+func codeSafe(text string) string {
+	return telegramCodeEscaper.Replace(strings.TrimSpace(text))
+}
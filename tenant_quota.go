@@ -0,0 +1,312 @@
+/*
+Tenant Quota Module
+===================
+
+멀티 테넌트 모드의 테넌트별 사용량 한도 (-tenants 파일의 quota / default_quota)
+
+주요 기능:
+- 하루 처리 로그 수 (events_per_day) - 초과하면 자정(현지 시각)까지 해당 테넌트 로그를 처리하지 않음
+- 시간당 알림 수 (notifications_per_hour) - 초과하면 정각까지 알림 채널로 보내지 않음 (최근 알림/이벤트 저장소에는 기록)
+- 채널별 시간당 알림 수 (channel_notifications_per_hour, 예: {"pagerduty": 5})
+- 하루 LLM 추정 토큰 수 (llm_tokens_per_day) - 초과하면 자정까지 LLM 로그 묶음 분석을 하지 않음 (llm_analysis 테넌트)
+- 한도를 처음 넘으면 윈도우마다 한 번 "사용량 한도 초과" 알림을 테넌트 채널과 운영자 채널로 전송
+- 관리 API (/status, /tenants) 로 현재 사용량 조회
+
+0 또는 생략한 한도는 제한하지 않음
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"sort"    // 채널 이름 정렬
+	"strings" // 문자열 처리
+	"sync"    // 동기화 (뮤텍스)
+	"time"    // 윈도우 계산
+)
+
+// 사용량 한도 종류
+const (
+	QuotaEvents        = "events_per_day"
+	QuotaNotifications = "notifications_per_hour"
+	QuotaLLMTokens     = "llm_tokens_per_day"
+)
+
+// quotaChannels 채널별 알림 한도에 쓸 수 있는 알림 채널 이름
+var quotaChannels = []string{"email", "slack", "telegram", "webhook", "pagerduty"}
+
+// TenantQuota 테넌트 파일의 사용량 한도 설정
+type TenantQuota struct {
+	EventsPerDay                int            `json:"events_per_day"`                 // 하루 처리 로그 수
+	NotificationsPerHour        int            `json:"notifications_per_hour"`         // 시간당 알림 수 (모든 채널 합계, 알림 한 건 = 1)
+	ChannelNotificationsPerHour map[string]int `json:"channel_notifications_per_hour"` // 채널별 시간당 알림 수
+	LLMTokensPerDay             int            `json:"llm_tokens_per_day"`             // 하루 LLM 로그 묶음 분석 추정 토큰 수 (프롬프트 로그 줄 + 응답)
+}
+
+// IsZero 설정된 한도가 없는지 확인
+func (q TenantQuota) IsZero() bool {
+	return q.EventsPerDay <= 0 && q.NotificationsPerHour <= 0 && len(q.ChannelNotificationsPerHour) == 0 && q.LLMTokensPerDay <= 0
+}
+
+// Validate 음수 한도와 알 수 없는 채널 이름 검사
+func (q TenantQuota) Validate() error {
+	if q.EventsPerDay < 0 || q.NotificationsPerHour < 0 || q.LLMTokensPerDay < 0 {
+		return fmt.Errorf("quota limits must not be negative")
+	}
+	for channel, limit := range q.ChannelNotificationsPerHour {
+		if limit < 0 {
+			return fmt.Errorf("quota for channel %s must not be negative", channel)
+		}
+		known := false
+		for _, name := range quotaChannels {
+			known = known || name == channel
+		}
+		if !known {
+			return fmt.Errorf("unknown quota channel %q (expected one of %s)", channel, strings.Join(quotaChannels, ", "))
+		}
+	}
+	return nil
+}
+
+// QuotaStatus 한도 하나의 현재 윈도우 사용량 (API 응답, 초과 알림)
+type QuotaStatus struct {
+	Name     string    `json:"name"`    // events_per_day, notifications_per_hour, notifications_per_hour:<channel>, llm_tokens_per_day
+	Limit    int       `json:"limit"`   // 한도
+	Used     int       `json:"used"`    // 현재 윈도우에서 허용한 수 (llm_tokens_per_day 는 추정 토큰)
+	Dropped  int       `json:"dropped"` // 현재 윈도우에서 한도 초과로 버린 수 (llm_tokens_per_day 는 분석하지 않은 로그 수)
+	ResetsAt time.Time `json:"resets_at"`
+}
+
+// quotaCounter 고정 윈도우 카운터 (하루 또는 한 시간)
+type quotaCounter struct {
+	name     string
+	limit    int
+	daily    bool
+	start    time.Time
+	used     int
+	dropped  int
+	notified bool
+}
+
+// windowStart 시각이 속한 윈도우 시작 (하루는 현지 자정, 시간은 정각)
+func (c *quotaCounter) windowStart(now time.Time) time.Time {
+	if c.daily {
+		year, month, day := now.Date()
+		return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	}
+	return now.Truncate(time.Hour)
+}
+
+// windowEnd 윈도우가 끝나는 시각
+func (c *quotaCounter) windowEnd(start time.Time) time.Time {
+	if c.daily {
+		return start.AddDate(0, 0, 1)
+	}
+	return start.Add(time.Hour)
+}
+
+// take amount 만큼 사용 시도, 거절되면 units 만큼 버린 수에 추가
+// (허용 여부, 이번 윈도우에서 처음 초과했는지, 끝난 윈도우의 버린 수)
+func (c *quotaCounter) take(now time.Time, amount, units int) (allowed bool, exceeded bool, previousDropped int) {
+	previousDropped = c.roll(now)
+	if c.used < c.limit && c.used+amount <= c.limit {
+		c.used += amount
+		return true, false, previousDropped
+	}
+	c.dropped += units
+	exceeded = !c.notified
+	c.notified = true
+	return false, exceeded, previousDropped
+}
+
+// roll 윈도우가 바뀌었으면 사용량 초기화 (끝난 윈도우의 버린 수)
+func (c *quotaCounter) roll(now time.Time) (previousDropped int) {
+	if start := c.windowStart(now); !start.Equal(c.start) {
+		previousDropped = c.dropped
+		c.start, c.used, c.dropped, c.notified = start, 0, 0, false
+	}
+	return previousDropped
+}
+
+// status 현재 사용량
+func (c *quotaCounter) status(now time.Time) QuotaStatus {
+	// 아직 새 윈도우에서 사용하지 않았으면 0
+	if start := c.windowStart(now); !start.Equal(c.start) {
+		return QuotaStatus{Name: c.name, Limit: c.limit, ResetsAt: c.windowEnd(start)}
+	}
+	return QuotaStatus{Name: c.name, Limit: c.limit, Used: c.used, Dropped: c.dropped, ResetsAt: c.windowEnd(c.start)}
+}
+
+// TenantQuotaTracker 테넌트 하나의 사용량 집계와 한도 적용
+type TenantQuotaTracker struct {
+	tenant        string
+	events        *quotaCounter
+	notifications *quotaCounter
+	channels      map[string]*quotaCounter
+	llmTokens     *quotaCounter
+	exceeded      func(QuotaStatus) // 윈도우마다 처음 한도를 넘을 때 (잠금 밖에서 호출)
+	logger        Logger
+	mutex         sync.Mutex
+}
+
+// NewTenantQuotaTracker 설정된 한도만 집계하는 트래커 생성 (한도가 없으면 nil)
+func NewTenantQuotaTracker(tenant string, quota TenantQuota, logger Logger) *TenantQuotaTracker {
+	if quota.IsZero() {
+		return nil
+	}
+	tracker := &TenantQuotaTracker{tenant: tenant, channels: make(map[string]*quotaCounter), logger: logger}
+	if quota.EventsPerDay > 0 {
+		tracker.events = &quotaCounter{name: QuotaEvents, limit: quota.EventsPerDay, daily: true}
+	}
+	if quota.NotificationsPerHour > 0 {
+		tracker.notifications = &quotaCounter{name: QuotaNotifications, limit: quota.NotificationsPerHour}
+	}
+	for channel, limit := range quota.ChannelNotificationsPerHour {
+		if limit > 0 {
+			tracker.channels[channel] = &quotaCounter{name: QuotaNotifications + ":" + channel, limit: limit}
+		}
+	}
+	if quota.LLMTokensPerDay > 0 {
+		tracker.llmTokens = &quotaCounter{name: QuotaLLMTokens, limit: quota.LLMTokensPerDay, daily: true}
+	}
+	return tracker
+}
+
+// OnExceeded 한도 초과 알림 콜백 설정
+func (qt *TenantQuotaTracker) OnExceeded(callback func(QuotaStatus)) {
+	qt.exceeded = callback
+}
+
+// AllowEvent 로그 한 줄 처리 허용 여부 (nil 이면 항상 허용)
+func (qt *TenantQuotaTracker) AllowEvent() bool {
+	if qt == nil {
+		return true
+	}
+	return qt.take(qt.events, 1, 1)
+}
+
+// AllowNotification 알림 한 건 전송 허용 여부 (nil 이면 항상 허용)
+func (qt *TenantQuotaTracker) AllowNotification() bool {
+	if qt == nil {
+		return true
+	}
+	return qt.take(qt.notifications, 1, 1)
+}
+
+// AllowChannel 채널 하나로 알림 전송 허용 여부 (nil 이면 항상 허용)
+func (qt *TenantQuotaTracker) AllowChannel(channel string) bool {
+	if qt == nil {
+		return true
+	}
+	return qt.take(qt.channels[channel], 1, 1)
+}
+
+// AllowLLMLine 이상 로그 한 줄을 LLM 분석 묶음에 추가해도 되는지 (오늘 토큰이 남았는지, nil 이면 항상 허용)
+func (qt *TenantQuotaTracker) AllowLLMLine() bool {
+	if qt == nil {
+		return true
+	}
+	return qt.take(qt.llmTokens, 0, 1)
+}
+
+// AllowLLMTokens LLM 호출 전에 프롬프트 추정 토큰 사용 (lines 는 거절될 때 분석하지 않는 로그 수, nil 이면 항상 허용)
+func (qt *TenantQuotaTracker) AllowLLMTokens(tokens, lines int) bool {
+	if qt == nil {
+		return true
+	}
+	return qt.take(qt.llmTokens, tokens, lines)
+}
+
+// AddLLMTokens LLM 응답 추정 토큰 기록 (이미 보낸 호출이므로 한도를 넘어도 기록, 다음 호출부터 거절)
+func (qt *TenantQuotaTracker) AddLLMTokens(tokens int) {
+	if qt == nil || qt.llmTokens == nil {
+		return
+	}
+	qt.mutex.Lock()
+	defer qt.mutex.Unlock()
+	qt.llmTokens.roll(time.Now())
+	qt.llmTokens.used += tokens
+}
+
+// take 카운터에서 amount 만큼 사용 (카운터가 없으면 한도 없음)
+func (qt *TenantQuotaTracker) take(counter *quotaCounter, amount, units int) bool {
+	if counter == nil {
+		return true
+	}
+	now := time.Now()
+	qt.mutex.Lock()
+	allowed, exceeded, previousDropped := counter.take(now, amount, units)
+	status := counter.status(now)
+	qt.mutex.Unlock()
+
+	if previousDropped > 0 {
+		qt.logger.Infof("Quota %s window reset for tenant %s (%d dropped in the previous window)", counter.name, qt.tenant, previousDropped)
+	}
+	if exceeded {
+		qt.logger.Errorf("Quota %s exceeded for tenant %s (limit %d, resets at %s)", counter.name, qt.tenant, counter.limit, status.ResetsAt.Format("2006-01-02 15:04"))
+		if qt.exceeded != nil {
+			qt.exceeded(status)
+		}
+	}
+	return allowed
+}
+
+// Usage 모든 한도의 현재 사용량 (events, notifications, llm tokens, 채널 이름 순)
+func (qt *TenantQuotaTracker) Usage() []QuotaStatus {
+	if qt == nil {
+		return nil
+	}
+	now := time.Now()
+	qt.mutex.Lock()
+	defer qt.mutex.Unlock()
+
+	var usage []QuotaStatus
+	for _, counter := range []*quotaCounter{qt.events, qt.notifications, qt.llmTokens} {
+		if counter != nil {
+			usage = append(usage, counter.status(now))
+		}
+	}
+	channels := make([]string, 0, len(qt.channels))
+	for channel := range qt.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	for _, channel := range channels {
+		usage = append(usage, qt.channels[channel].status(now))
+	}
+	return usage
+}
+
+// quotaExceededAlert 사용량 한도 초과 알림 (디스패처의 한도 적용을 받지 않음)
+func quotaExceededAlert(tenantID, tenantName string, status QuotaStatus) Alert {
+	effect := "이 채널로 알림을 보내지 않습니다"
+	switch status.Name {
+	case QuotaEvents:
+		effect = "로그를 처리하지 않습니다 (탐지/알림/저장 중단)"
+	case QuotaNotifications:
+		effect = "알림을 보내지 않습니다 (최근 알림/이벤트 저장소에는 기록)"
+	case QuotaLLMTokens:
+		effect = "LLM 로그 묶음 분석을 하지 않습니다 (AI 이상 탐지 알림은 그대로)"
+	}
+
+	return Alert{
+		Type:     AlertTypeQuota,
+		Severity: AlertSeverityWarning,
+		Title:    fmt.Sprintf("[%s QUOTA] %s - %s exceeded", AppName, tenantName, status.Name),
+		Headline: fmt.Sprintf("🚫 테넌트 %s 의 사용량 한도를 초과했습니다", tenantName),
+		Sections: []AlertSection{{
+			Fields: []AlertField{
+				{Label: "테넌트", Value: fmt.Sprintf("%s (%s)", tenantName, tenantID), Short: true},
+				{Label: "한도", Value: fmt.Sprintf("%s = %d", status.Name, status.Limit), Short: true},
+				{Label: "조치", Value: fmt.Sprintf("%s 까지 %s", status.ResetsAt.Format("2006-01-02 15:04"), effect)},
+			},
+			Summary: true,
+		}},
+		Thread: alertThreadKey(AlertTypeQuota, tenantID, status.Name),
+		Fields: map[string]string{
+			"tenant":    tenantID,
+			"quota":     status.Name,
+			"limit":     fmt.Sprintf("%d", status.Limit),
+			"resets_at": status.ResetsAt.Format(time.RFC3339),
+		},
+	}
+}
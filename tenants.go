@@ -0,0 +1,570 @@
+/*
+Multi-Tenant Module
+===================
+
+하나의 수집 서버로 여러 고객(테넌트)의 로그를 처리하는 멀티 테넌트 모드 (-tenants)
+
+주요 기능:
+- 테넌트별 로그 소스 (파일 경로 또는 glob, 소스는 한 테넌트에만 속함)
+- 테넌트별 처리 파이프라인 (필터/키워드, 로그인 감지, AI 분석, 알림 제한 상태가 서로 섞이지 않음)
+- 테넌트별 저장소 (SQLite 이벤트 저장소, Elasticsearch 인덱스 접두사 <prefix>-<id>)
+- 테넌트별 알림 채널 (이메일 수신자, Slack, Telegram, 웹훅, PagerDuty)
+- 테넌트별 읽기 전용 API 토큰 (자기 테넌트의 상태/알림/대시보드만 조회)
+- 테넌트별 사용량 한도 (하루 로그 수, 시간당 알림 수, 하루 LLM 토큰 수, tenant_quota.go)
+- LLM 로그 묶음 분석은 llm_analysis 로 동의한 테넌트만 (운영자의 LLM 백엔드/호출 한도 사용)
+
+운영자 설정 공유:
+- SMTP 서버/발신자, AI 분석/로그인 감지/DB 감지 활성화 여부, 로그인 알림 간격, Tor/VPN 목록
+- 호스트 메트릭(-system-monitor)과 IP 차단은 수집 서버 자체에만 적용
+
+테넌트 파일 예시:
+
+	{
+	  "tenants": [
+	    {
+	      "id": "acme",
+	      "name": "ACME Corp",
+	      "sources": ["/var/log/remote/acme/*.log"],
+	      "api_tokens": ["acme-read-only-token-..."],
+	      "db_path": "~/.syslog-monitor/tenants/acme.db",
+	      "email_to": ["ops@acme.example"],
+	      "slack_webhook": "https://hooks.slack.com/services/...",
+	      "quota": {"events_per_day": 2000000, "notifications_per_hour": 30}
+	    }
+	  ],
+	  "default_quota": {"events_per_day": 500000, "notifications_per_hour": 20}
+	}
+*/
+package main
+
+import (
+	"context"       // 종료 신호
+	"crypto/subtle" // 토큰 비교
+	"encoding/json" // 테넌트 파일 파싱
+	"fmt"           // 형식화된 I/O
+	"os"            // 파일 읽기
+	"path/filepath" // 소스 glob 확장
+	"regexp"        // 테넌트 ID 검증
+	"strings"       // 문자열 처리
+	"time"          // 시작 시각
+
+	"github.com/hpcloud/tail"    // 파일 tail 기능
+	"github.com/sirupsen/logrus" // 테넌트 로그 필드
+)
+
+// 멀티 테넌트 설정
+const (
+	TenantTokenMinLength = 16 // 테넌트 API 토큰 최소 길이
+)
+
+// tenantIDPattern 테넌트 ID 형식 (Elasticsearch 인덱스 이름과 파일 경로에 사용)
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// TenantConfig -tenants 파일의 테넌트 한 곳 설정
+type TenantConfig struct {
+	ID                  string   `json:"id"`                    // 테넌트 ID (소문자, 숫자, -, _)
+	Name                string   `json:"name"`                  // 표시 이름 (생략 시 ID)
+	Sources             []string `json:"sources"`               // 감시할 로그 파일 (glob 가능)
+	APITokens           []string `json:"api_tokens"`            // 읽기 전용 관리 API 토큰
+	DBPath              string   `json:"db_path"`               // 이벤트 저장소 SQLite 파일 (생략 시 저장 안 함)
+	Keywords            []string `json:"keywords"`              // 포함할 키워드
+	Filters             []string `json:"filters"`               // 제외할 정규식
+	EmailTo             []string `json:"email_to"`              // 알림 이메일 수신자
+	SlackWebhook        string   `json:"slack_webhook"`         // Slack 웹훅 URL
+	SlackChannel        string   `json:"slack_channel"`         // Slack 채널
+	TelegramToken       string   `json:"telegram_token"`        // Telegram 봇 토큰
+	TelegramChatID      string   `json:"telegram_chat_id"`      // Telegram 채팅 ID
+	WebhookURL          string   `json:"webhook_url"`           // 범용 JSON 웹훅 URL
+	PagerDutyRoutingKey string   `json:"pagerduty_routing_key"` // PagerDuty 라우팅 키
+	LLMAnalysis         bool     `json:"llm_analysis"`          // 이상 로그 묶음을 운영자의 LLM 백엔드로 분석 (테넌트 동의, 기본 꺼짐)
+
+	// 사용량 한도 (생략 시 default_quota, {} 이면 제한 없음)
+	Quota *TenantQuota `json:"quota"`
+}
+
+// tenantsFile -tenants 파일 구조
+type tenantsFile struct {
+	Tenants      []TenantConfig `json:"tenants"`
+	DefaultQuota TenantQuota    `json:"default_quota"` // quota 를 지정하지 않은 테넌트의 한도
+}
+
+// TenantOptions 모든 테넌트에 공통으로 적용하는 운영자 설정
+type TenantOptions struct {
+	EmailConfig     *EmailConfig     // SMTP 서버/발신자 (수신자는 테넌트별)
+	AIEnabled       bool             // AI 분석
+	LoginWatch      bool             // 로그인 감지
+	DBWatch         bool             // DB 권한 변경/관리자 인증 실패 감지
+	CICDWatch       bool             // CI/CD 반복 작업 실패/러너 등록/빌드 출력 비밀 노출 감지
+	SQLIConfirm     bool             // 웹 SQL 인젝션 탐지의 DB 로그 확인 (테넌트 소스의 웹/DB 로그끼리 상관 분석)
+	SQLIWindow      int              // SQL 인젝션 상관 분석 윈도우 (초)
+	ExfilWatch      bool             // 웹 응답 크기 이상 (데이터 유출) 탐지
+	ExfilVolumeMB   int              // 클라이언트 × 엔드포인트 윈도우 누적 임계값 (MB)
+	ExfilWindow     int              // 누적 윈도우 (분)
+	AccessStats     bool             // 웹 접근 로그 통계 전용 모드 (원본 접근 로그를 저장/전송하지 않음)
+	AccessWindow    int              // 접근 로그 통계 윈도우 (분)
+	AccessErrorRate float64          // 5xx 오류율 알림 임계값 (%)
+	AccessAttacks   int              // 윈도우당 공격 패턴 일치 횟수 알림 임계값
+	UnknownFormat   float64          // 소스별 형식 미인식 비율 알림 임계값 (%, 0 이면 알림 안 함)
+	TraceSample     float64          // 단계별 지연 시간을 추적할 이벤트 비율 (0 이면 추적 안 함)
+	SlowEvent       time.Duration    // 느린 이벤트 로그 기준 시간 (0 이면 기록 안 함)
+	AlertInterval   int              // 로그인 알림 간격 (분)
+	ESURL           string           // Elasticsearch URL (빈 문자열이면 색인 안 함)
+	ESIndexPrefix   string           // 테넌트 인덱스는 <prefix>-<id>-logs-*, <prefix>-<id>-ai-*
+	OutputBuffer    string           // 출력 디스크 버퍼 디렉터리 (테넌트 버퍼는 <dir>/tenants/<id>/elasticsearch, 빈 문자열이면 버퍼링 안 함)
+	OutputBufferMB  int              // 출력 디스크 버퍼 크기 한도 (MB)
+	Dashboard       bool             // 테넌트별 웹 대시보드
+	AnomalyPatterns []AnomalyPattern // 규칙 파일의 이상 패턴 (nil 이면 내장 패턴)
+	Operator        *SyslogMonitor   // 수집 서버 자체 모니터 (Tor/VPN 목록 공유)
+}
+
+// LoadTenantConfigs 테넌트 파일을 읽고 ID/소스/토큰 중복을 검증
+func LoadTenantConfigs(path string) ([]TenantConfig, error) {
+	data, err := os.ReadFile(expandHomePath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants file: %v", err)
+	}
+	var file tenantsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse tenants file: %v", err)
+	}
+	if len(file.Tenants) == 0 {
+		return nil, fmt.Errorf("no tenants defined in %s", path)
+	}
+	if err := file.DefaultQuota.Validate(); err != nil {
+		return nil, fmt.Errorf("default_quota: %v", err)
+	}
+
+	ids := make(map[string]bool)
+	sources := make(map[string]string)
+	tokens := make(map[string]string)
+	for i := range file.Tenants {
+		tenant := &file.Tenants[i]
+		if !tenantIDPattern.MatchString(tenant.ID) {
+			return nil, fmt.Errorf("tenant %d: invalid id %q (lowercase letters, digits, - and _)", i+1, tenant.ID)
+		}
+		if ids[tenant.ID] {
+			return nil, fmt.Errorf("tenant %s: duplicate id", tenant.ID)
+		}
+		ids[tenant.ID] = true
+		if tenant.Name == "" {
+			tenant.Name = tenant.ID
+		}
+
+		if len(tenant.Sources) == 0 {
+			return nil, fmt.Errorf("tenant %s: at least one source is required", tenant.ID)
+		}
+		expanded, err := expandTenantSources(tenant.Sources)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %v", tenant.ID, err)
+		}
+		for _, source := range expanded {
+			if owner, ok := sources[source]; ok {
+				return nil, fmt.Errorf("tenant %s: source %s already belongs to tenant %s", tenant.ID, source, owner)
+			}
+			sources[source] = tenant.ID
+		}
+		tenant.Sources = expanded
+
+		for _, token := range tenant.APITokens {
+			if len(token) < TenantTokenMinLength {
+				return nil, fmt.Errorf("tenant %s: api tokens must be at least %d characters", tenant.ID, TenantTokenMinLength)
+			}
+			if owner, ok := tokens[token]; ok {
+				return nil, fmt.Errorf("tenant %s: api token already used by tenant %s", tenant.ID, owner)
+			}
+			tokens[token] = tenant.ID
+		}
+		if _, err := CompileLogFilter(tenant.Filters); err != nil {
+			return nil, fmt.Errorf("tenant %s: %v", tenant.ID, err)
+		}
+
+		if tenant.Quota == nil {
+			quota := file.DefaultQuota
+			tenant.Quota = &quota
+		}
+		if err := tenant.Quota.Validate(); err != nil {
+			return nil, fmt.Errorf("tenant %s: %v", tenant.ID, err)
+		}
+	}
+	return file.Tenants, nil
+}
+
+// expandTenantSources 소스 glob 확장 (일치하는 파일이 아직 없으면 경로 그대로 감시)
+func expandTenantSources(patterns []string) ([]string, error) {
+	var sources []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		pattern = expandHomePath(strings.TrimSpace(pattern))
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source pattern %q: %v", pattern, err)
+		}
+		if len(matches) == 0 {
+			matches = []string{pattern}
+		}
+		for _, match := range matches {
+			if absolute, err := filepath.Abs(match); err == nil {
+				match = absolute
+			}
+			if !seen[match] {
+				seen[match] = true
+				sources = append(sources, match)
+			}
+		}
+	}
+	return sources, nil
+}
+
+// Tenant 테넌트 하나의 격리된 처리 파이프라인
+type Tenant struct {
+	ID      string
+	Name    string
+	monitor *SyslogMonitor
+	sources []string
+	tokens  []string
+	quota   *TenantQuotaTracker // 사용량 한도 (한도가 없으면 nil)
+	started bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// tenantLogHook 테넌트 모니터의 모든 로그에 tenant 필드 추가
+type tenantLogHook struct {
+	id string
+}
+
+// Levels 모든 로그 레벨에 적용
+func (hook tenantLogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire tenant 필드 추가
+func (hook tenantLogHook) Fire(entry *logrus.Entry) error {
+	entry.Data["tenant"] = hook.id
+	return nil
+}
+
+// NewTenant 테넌트 설정으로 독립된 모니터와 저장소/알림 채널 생성
+// 호스트 메트릭과 정기 보고서는 수집 서버 자체 정보이므로 테넌트 모니터에서는 사용하지 않음
+func NewTenant(config TenantConfig, options TenantOptions) (*Tenant, error) {
+	keywords := append([]string{}, config.Keywords...)
+	if options.LoginWatch {
+		keywords = appendLoginKeywords(keywords)
+	}
+	if options.DBWatch && len(keywords) > 0 {
+		keywords = appendKeywords(keywords, DBWatchKeywords)
+	}
+	if options.CICDWatch && len(keywords) > 0 {
+		keywords = appendKeywords(keywords, CICDWatchKeywords)
+	}
+
+	// 운영자의 SMTP 설정으로 테넌트 수신자에게만 발송
+	var emailConfig *EmailConfig
+	if len(config.EmailTo) > 0 && options.EmailConfig != nil {
+		copied := *options.EmailConfig
+		copied.To = append([]string{}, config.EmailTo...)
+		copied.Enabled = true
+		emailConfig = &copied
+	}
+	slackConfig := &SlackConfig{
+		WebhookURL: config.SlackWebhook,
+		Channel:    config.SlackChannel,
+		Username:   "Syslog Monitor",
+		Enabled:    config.SlackWebhook != "",
+	}
+
+	monitor := NewSyslogMonitor(config.Sources[0], "", append([]string{}, config.Filters...), keywords, emailConfig, slackConfig, options.AIEnabled, false, options.LoginWatch, options.AlertInterval, 0, false)
+	monitor.logger.AddHook(tenantLogHook{id: config.ID})
+	monitor.digest.SetConfig(DigestConfig{}) // 일일 요약은 운영자 모니터에서만 (테넌트 모니터는 집계하지 않음)
+	if !config.LLMAnalysis {
+		monitor.llmBatcher = nil // 원본 로그가 운영자의 LLM 계정으로 나가므로 llm_analysis 로 동의한 테넌트만 묶음 분석
+	}
+
+	if config.TelegramToken != "" && config.TelegramChatID != "" {
+		monitor.AddAlertSink("telegram", NewTelegramService(&TelegramConfig{BotToken: config.TelegramToken, ChatID: config.TelegramChatID, Enabled: true}, monitor.logger))
+	}
+	if config.WebhookURL != "" {
+		monitor.AddAlertSink("webhook", NewWebhookSink(config.WebhookURL))
+	}
+	if config.PagerDutyRoutingKey != "" {
+		monitor.AddAlertSink("pagerduty", NewPagerDutySink(config.PagerDutyRoutingKey))
+	}
+
+	if options.DBWatch {
+		monitor.SetDBPrivilegeDetector(newConfiguredDBPrivilegeDetector())
+	}
+	if options.CICDWatch {
+		detector, err := newConfiguredCICDDetector()
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %v", config.ID, err)
+		}
+		monitor.SetCICDDetector(detector)
+	}
+	if options.SQLIConfirm {
+		monitor.SetSQLInjectionCorrelator(NewSQLInjectionCorrelator(options.SQLIWindow), nil)
+	}
+	if options.AccessStats {
+		monitor.SetAccessStats(NewAccessStats(options.AccessWindow, options.AccessErrorRate, options.AccessAttacks))
+	} else if options.ExfilWatch {
+		monitor.SetExfiltrationDetector(NewExfiltrationDetector(options.ExfilVolumeMB, options.ExfilWindow))
+	}
+	monitor.parserStats.SetThreshold(options.UnknownFormat)
+	if options.TraceSample > 0 {
+		if tracer, err := NewLatencyTracer(options.TraceSample, options.SlowEvent); err == nil {
+			monitor.SetLatencyTracer(tracer)
+		}
+	}
+	if options.AnomalyPatterns != nil && monitor.aiAnalyzer != nil {
+		monitor.aiAnalyzer.SetPatterns(options.AnomalyPatterns)
+	}
+
+	// Tor/VPN 목록은 운영자 모니터와 공유 (테넌트마다 내려받지 않음)
+	if monitor.loginDetector != nil && options.Operator != nil && options.Operator.loginDetector != nil {
+		monitor.loginDetector.ShareAnonymizers(options.Operator.loginDetector)
+	}
+	// 지리정보 캐시도 운영자 모니터와 공유 (저장/종료는 운영자 모니터가 처리)
+	if options.Operator != nil {
+		monitor.SetGeoMapper(options.Operator.geoMapper)
+		monitor.SetReverseDNS(options.Operator.reverseDNS)
+		monitor.SetWhoisLookup(options.Operator.whoisLookup)
+	}
+	// ONNX 이상 점수 모델도 운영자 모니터와 공유 (추론은 상태를 바꾸지 않음)
+	if monitor.aiAnalyzer != nil && options.Operator != nil && options.Operator.aiAnalyzer != nil {
+		monitor.aiAnalyzer.SetModel(options.Operator.aiAnalyzer.Model())
+	}
+
+	if config.DBPath != "" {
+		store, err := OpenEventStore(config.DBPath, monitor.logger)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %v", config.ID, err)
+		}
+		if err := monitor.SetEventStore(store); err != nil {
+			store.Close()
+			return nil, fmt.Errorf("tenant %s: %v", config.ID, err)
+		}
+	}
+	if options.ESURL != "" {
+		output, err := NewElasticsearchOutput(options.ESURL, options.ESIndexPrefix+"-"+config.ID, monitor.logger)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %v", config.ID, err)
+		}
+		if options.OutputBuffer != "" {
+			dir := filepath.Join(expandHomePath(options.OutputBuffer), "tenants", config.ID, "elasticsearch")
+			buffer, err := OpenOutputBuffer(dir, "Elasticsearch/"+config.ID, int64(options.OutputBufferMB)<<20, monitor.logger)
+			if err != nil {
+				return nil, fmt.Errorf("tenant %s: %v", config.ID, err)
+			}
+			output.SetBuffer(buffer)
+			// 버퍼 용량은 수집 서버 자원이므로 운영자 채널로 알림
+			if options.Operator != nil {
+				options.Operator.WatchOutputBuffer(buffer)
+			}
+		}
+		monitor.SetElasticsearchOutput(output)
+	}
+	if options.Dashboard {
+		monitor.SetDashboard(NewDashboard(monitor))
+	}
+
+	tenant := &Tenant{
+		ID:      config.ID,
+		Name:    config.Name,
+		monitor: monitor,
+		sources: config.Sources,
+		tokens:  config.APITokens,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	// 사용량 한도: 초과 알림은 테넌트 채널과 운영자 채널 모두로 전송
+	if config.Quota != nil {
+		tenant.quota = NewTenantQuotaTracker(config.ID, *config.Quota, monitor.logger)
+	}
+	if tenant.quota != nil {
+		operator := options.Operator
+		tenant.quota.OnExceeded(func(status QuotaStatus) {
+			alert := quotaExceededAlert(config.ID, config.Name, status)
+			monitor.alertDispatcher.Dispatch(alert)
+			if operator != nil {
+				operator.alertDispatcher.Dispatch(alert)
+			}
+		})
+		monitor.alertDispatcher.SetQuota(tenant.quota)
+		monitor.llmBatcher.SetQuota(tenant.quota)
+	}
+	return tenant, nil
+}
+
+// QuotaUsage 사용량 한도별 현재 사용량 (한도가 없으면 nil)
+func (t *Tenant) QuotaUsage() []QuotaStatus {
+	return t.quota.Usage()
+}
+
+// Sources 감시 중인 로그 파일
+func (t *Tenant) Sources() []string {
+	return append([]string{}, t.sources...)
+}
+
+// Start 저장소/출력을 시작하고 소스 tail 처리 고루틴 실행 (소스 처리는 Close 로 종료)
+func (t *Tenant) Start(ctx context.Context) {
+	sm := t.monitor
+	sm.startedAt = time.Now()
+	if sm.loginDetector != nil {
+		sm.loginDetector.StartHistoryJanitor(ctx)
+	}
+	if sm.eventStore != nil {
+		sm.eventStore.Start()
+	}
+	if sm.esOutput != nil {
+		sm.esOutput.Start()
+	}
+	if sm.accessStats != nil {
+		sm.accessStats.Start(ctx, sm.handleAccessStats)
+	}
+	t.started = true
+	go t.run()
+	sm.logger.Infof("🏢 Tenant %s (%s) started: %s", t.ID, t.Name, strings.Join(t.sources, ", "))
+}
+
+// tenantLine 테넌트 소스에서 읽은 한 줄과 읽은 파일
+type tenantLine struct {
+	source string
+	text   string
+}
+
+// run 모든 소스의 새 줄을 한 고루틴에서 처리 (운영자 모니터의 tail 루프와 같은 방식)
+func (t *Tenant) run() {
+	defer close(t.done)
+	sm := t.monitor
+
+	lines := make(chan tenantLine)
+	var tails []*tail.Tail
+	for _, source := range t.sources {
+		tailer, err := tail.TailFile(source, tail.Config{
+			Follow:   true,
+			ReOpen:   true,
+			Poll:     true,
+			Location: &tail.SeekInfo{Offset: 0, Whence: 2}, // 파일 끝에서 시작
+		})
+		if err != nil {
+			sm.logger.Errorf("Failed to tail %s: %v", source, err)
+			continue
+		}
+		tails = append(tails, tailer)
+		go func(source string, tailer *tail.Tail) {
+			for line := range tailer.Lines {
+				if line.Err != nil {
+					sm.logger.Errorf("Error reading line: %v", line.Err)
+					continue
+				}
+				select {
+				case lines <- tenantLine{source: source, text: line.Text}:
+				case <-t.stop:
+					return
+				}
+			}
+		}(source, tailer)
+	}
+
+	for {
+		select {
+		case line := <-lines:
+			// 하루 로그 한도를 넘으면 자정까지 처리하지 않음
+			if !t.quota.AllowEvent() {
+				continue
+			}
+			sm.processLine(line.source, line.text)
+
+		case fn := <-sm.controls:
+			fn()
+
+		case <-t.stop:
+			for _, tailer := range tails {
+				tailer.Stop()
+			}
+			return
+		}
+	}
+}
+
+// Close 처리 고루틴을 멈추고 저장소/출력 정리
+func (t *Tenant) Close() {
+	if t.started {
+		close(t.stop)
+		<-t.done
+	}
+	t.monitor.shutdown()
+}
+
+// TenantManager 테넌트 목록과 API 토큰 조회
+type TenantManager struct {
+	tenants []*Tenant
+	byID    map[string]*Tenant
+}
+
+// NewTenantManager 테넌트 설정마다 격리된 모니터 생성
+func NewTenantManager(configs []TenantConfig, options TenantOptions) (*TenantManager, error) {
+	tm := &TenantManager{byID: make(map[string]*Tenant)}
+	for _, config := range configs {
+		tenant, err := NewTenant(config, options)
+		if err != nil {
+			tm.Close()
+			return nil, err
+		}
+		tm.tenants = append(tm.tenants, tenant)
+		tm.byID[tenant.ID] = tenant
+	}
+	return tm, nil
+}
+
+// Tenants 등록된 테넌트 (설정 파일 순서)
+func (tm *TenantManager) Tenants() []*Tenant {
+	if tm == nil {
+		return nil
+	}
+	return tm.tenants
+}
+
+// Get ID 로 테넌트 조회 (없으면 nil)
+func (tm *TenantManager) Get(id string) *Tenant {
+	if tm == nil {
+		return nil
+	}
+	return tm.byID[id]
+}
+
+// Authenticate API 토큰에 해당하는 테넌트 조회 (없으면 nil)
+// 모든 토큰을 끝까지 비교해 일치 위치가 응답 시간으로 드러나지 않도록 함
+func (tm *TenantManager) Authenticate(token string) *Tenant {
+	if tm == nil || token == "" {
+		return nil
+	}
+	var matched *Tenant
+	for _, tenant := range tm.tenants {
+		for _, candidate := range tenant.tokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(candidate)) == 1 {
+				matched = tenant
+			}
+		}
+	}
+	return matched
+}
+
+// HasToken 테넌트 토큰으로 이미 사용 중인지 확인 (운영자 토큰 중복 방지)
+func (tm *TenantManager) HasToken(token string) bool {
+	return tm.Authenticate(token) != nil
+}
+
+// Start 모든 테넌트 시작
+func (tm *TenantManager) Start(ctx context.Context) {
+	for _, tenant := range tm.Tenants() {
+		tenant.Start(ctx)
+	}
+}
+
+// Close 모든 테넌트 종료
+func (tm *TenantManager) Close() {
+	for _, tenant := range tm.Tenants() {
+		tenant.Close()
+	}
+}
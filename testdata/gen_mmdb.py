@@ -0,0 +1,273 @@
+#!/usr/bin/env python3
+"""Generate the small MaxMind DB (.mmdb) fixtures used by geoip_db_test.go.
+
+The writer follows the MaxMind DB format 2.0 spec
+(https://maxmind.github.io/MaxMind-DB/) and does not share code with the Go
+reader. The records mirror MaxMind's public test databases (GeoIP2-City-Test,
+GeoLite2-ASN-Test, GeoIP2-Anonymous-IP-Test), cut down to a few networks:
+
+  city-ipv6-24.mmdb   GeoIP2-City, IPv6 tree with IPv4 under ::/96, 24-bit records,
+                      a shared record referenced through a data section pointer
+  asn-ipv4-28.mmdb    GeoLite2-ASN, IPv4 tree, 28-bit records
+  anon-ipv6-32.mmdb   GeoIP2-Anonymous-IP, IPv6 tree, 32-bit records
+  decoder-ipv4-24.mmdb  every data type and size extension (like MaxMind-DB-test-decoder)
+
+Run from the repository root: python3 testdata/gen_mmdb.py
+"""
+
+import ipaddress
+import os
+import struct
+
+MARKER = b"\xab\xcd\xefMaxMind.com"
+T_POINTER, T_STRING, T_DOUBLE, T_BYTES, T_UINT16, T_UINT32, T_MAP = 1, 2, 3, 4, 5, 6, 7
+T_INT32, T_UINT64, T_UINT128, T_ARRAY, T_BOOLEAN, T_FLOAT = 8, 9, 10, 11, 14, 15
+
+
+class Uint16(int):
+    pass
+
+
+class Uint32(int):
+    pass
+
+
+class Uint64(int):
+    pass
+
+
+class Int32(int):
+    pass
+
+
+class Uint128(int):
+    pass
+
+
+class Float(float):
+    pass
+
+
+class Pointer(int):
+    pass
+
+
+def control(kind, size):
+    if size < 29:
+        head, extra = size, b""
+    elif size < 285:
+        head, extra = 29, bytes([size - 29])
+    elif size < 65821:
+        head, extra = 30, (size - 285).to_bytes(2, "big")
+    else:
+        head, extra = 31, (size - 65821).to_bytes(3, "big")
+    if kind <= 7:
+        return bytes([kind << 5 | head]) + extra
+    return bytes([head, kind - 7]) + extra
+
+
+def uint_bytes(value):
+    return value.to_bytes((value.bit_length() + 7) // 8, "big")
+
+
+def encode(value):
+    if isinstance(value, Pointer):
+        if value < 2048:
+            return bytes([0x20 | value >> 8, value & 0xFF])
+        if value < 526336:
+            v = value - 2048
+            return bytes([0x28 | v >> 16]) + (v & 0xFFFF).to_bytes(2, "big")
+        if value < 134744064:
+            v = value - 526336
+            return bytes([0x30 | v >> 24]) + (v & 0xFFFFFF).to_bytes(3, "big")
+        return bytes([0x38]) + value.to_bytes(4, "big")
+    if isinstance(value, bool):
+        return control(T_BOOLEAN, int(value))
+    if isinstance(value, str):
+        data = value.encode()
+        return control(T_STRING, len(data)) + data
+    if isinstance(value, bytes):
+        return control(T_BYTES, len(value)) + value
+    if isinstance(value, Float):
+        return control(T_FLOAT, 4) + struct.pack(">f", value)
+    if isinstance(value, float):
+        return control(T_DOUBLE, 8) + struct.pack(">d", value)
+    if isinstance(value, Int32):
+        return control(T_INT32, 4) + (value & 0xFFFFFFFF).to_bytes(4, "big")
+    for kind, cls in ((T_UINT16, Uint16), (T_UINT32, Uint32), (T_UINT64, Uint64), (T_UINT128, Uint128)):
+        if isinstance(value, cls):
+            data = uint_bytes(value)
+            return control(kind, len(data)) + data
+    if isinstance(value, dict):
+        out = control(T_MAP, len(value))
+        for key, item in value.items():
+            out += encode(key) + encode(item)
+        return out
+    if isinstance(value, list):
+        return control(T_ARRAY, len(value)) + b"".join(encode(item) for item in value)
+    raise TypeError(value)
+
+
+def build(networks, ip_version, record_size, database_type, data_prefix=b""):
+    """Return (file bytes, node count) for networks given as (cidr, encoded record
+    or offset into data_prefix)."""
+    data = bytearray(data_prefix)
+    root = [None, None]
+    for cidr, record in networks:
+        net = ipaddress.ip_network(cidr)
+        bits = 128 if ip_version == 6 else 32
+        address = int(net.network_address)
+        prefix = net.prefixlen
+        if net.version == 4 and ip_version == 6:
+            prefix += 96
+        if isinstance(record, int):
+            offset = record
+        else:
+            offset = len(data)
+            data += record
+        node = root
+        for i in range(prefix):
+            bit = (address >> (bits - 1 - i)) & 1
+            if i == prefix - 1:
+                node[bit] = ("data", offset)
+            else:
+                if not isinstance(node[bit], list):
+                    node[bit] = [None, None]
+                node = node[bit]
+
+    nodes = []
+
+    def number(node):
+        nodes.append(node)
+        for child in node:
+            if isinstance(child, list):
+                number(child)
+
+    number(root)
+    index = {id(node): i for i, node in enumerate(nodes)}
+    count = len(nodes)
+
+    def value(child):
+        if child is None:
+            return count
+        if isinstance(child, list):
+            return index[id(child)]
+        return count + 16 + child[1]
+
+    tree = bytearray()
+    for node in nodes:
+        left, right = value(node[0]), value(node[1])
+        if record_size == 24:
+            tree += left.to_bytes(3, "big") + right.to_bytes(3, "big")
+        elif record_size == 28:
+            tree += (left & 0xFFFFFF).to_bytes(3, "big")
+            tree.append((left >> 24) << 4 | (right >> 24))
+            tree += (right & 0xFFFFFF).to_bytes(3, "big")
+        else:
+            tree += left.to_bytes(4, "big") + right.to_bytes(4, "big")
+
+    metadata = {
+        "binary_format_major_version": Uint16(2),
+        "binary_format_minor_version": Uint16(0),
+        "build_epoch": Uint64(1760000000),
+        "database_type": database_type,
+        "description": {"en": database_type + " test database"},
+        "ip_version": Uint16(ip_version),
+        "languages": ["en"],
+        "node_count": Uint32(count),
+        "record_size": Uint16(record_size),
+    }
+    return bytes(tree) + b"\x00" * 16 + bytes(data) + MARKER + encode(metadata), count
+
+
+def city():
+    london = {
+        "city": {"geoname_id": Uint32(2643743), "names": {"en": "London", "de": "London"}},
+        "continent": {"code": "EU", "names": {"en": "Europe"}},
+        "country": {"iso_code": "GB", "names": {"en": "United Kingdom", "fr": "Royaume-Uni"}},
+        "location": {"accuracy_radius": Uint16(100), "latitude": 51.5142, "longitude": -0.0931, "time_zone": "Europe/London"},
+        "registered_country": {"iso_code": "GB", "names": {"en": "United Kingdom"}},
+        "subdivisions": [{"iso_code": "ENG", "names": {"en": "England"}}],
+    }
+    shared = encode(london)
+    # the second record points at the shared country map, as MaxMind writers deduplicate data
+    country_offset = shared.index(encode({"iso_code": "GB", "names": {"en": "United Kingdom", "fr": "Royaume-Uni"}}))
+    boxford = encode({
+        "city": {"names": {"en": "Boxford"}},
+        "country": Pointer(country_offset),
+        "location": {"latitude": 51.75, "longitude": -1.25, "time_zone": "Europe/London"},
+        "subdivisions": [{"names": {"en": "England"}}, {"names": {"en": "West Berkshire"}}],
+    })
+    sweden = encode({
+        "country": {"iso_code": "SE", "names": {"en": "Sweden"}},
+        "location": {"latitude": Float(62.0), "longitude": Float(15.0), "time_zone": "Europe/Stockholm"},
+        "traits": {"is_anonymous_proxy": True},
+    })
+    data = shared
+    networks = [
+        ("81.2.69.160/27", 0),
+        ("2a02:d280::/29", 0),
+        ("2.125.160.216/29", len(data)),
+    ]
+    data += boxford
+    networks.append(("89.160.20.112/28", len(data)))
+    data += sweden
+    return build(networks, 6, 24, "GeoIP2-City", data)
+
+
+def asn():
+    networks = [
+        ("1.128.0.0/11", encode({"autonomous_system_number": Uint32(1221), "autonomous_system_organization": "Telstra Pty Ltd"})),
+        ("12.81.92.0/22", encode({"autonomous_system_number": Uint32(7018), "autonomous_system_organization": "AT&T Services"})),
+        ("8.8.8.0/24", encode({"autonomous_system_number": Uint32(15169), "autonomous_system_organization": "Google LLC"})),
+    ]
+    return build(networks, 4, 28, "GeoLite2-ASN")
+
+
+def anonymous():
+    networks = [
+        ("1.2.0.0/16", encode({"is_anonymous": True, "is_anonymous_vpn": True})),
+        ("81.2.69.0/24", encode({"is_anonymous": True, "is_hosting_provider": True, "is_tor_exit_node": True})),
+        ("::1.124.213.1/128", encode({"is_anonymous": False})),
+        ("abcd:1000::/112", encode({"is_anonymous": True, "is_public_proxy": True})),
+    ]
+    return build(networks, 6, 32, "GeoIP2-Anonymous-IP")
+
+
+def decoder():
+    everything = encode({
+        "array": [Uint32(1), Uint32(2), Uint32(3)],
+        "boolean": True,
+        "bytes": b"\x00\x00\x00\x2a",
+        "double": 42.123456,
+        "float": Float(1.1),
+        "int32": Int32(-268435456),
+        "map": {"mapX": {"arrayX": [Uint32(7), Uint32(8), Uint32(9)], "utf8_stringX": "hello"}},
+        "uint16": Uint16(100),
+        "uint32": Uint32(268435456),
+        "uint64": Uint64(1152921504606846976),
+        "uint128": Uint128(1 << 120),
+        "utf8_string": "unicode! \u262f - \u266b",
+        "long_string": "x" * 300,  # size extension 30 (>= 285)
+        "medium_string": "y" * 100,  # size extension 29
+        "long_array": [Uint16(i) for i in range(40)],  # element count size extension 29
+    })
+    zeros = encode({
+        "boolean": False, "double": 0.0, "float": Float(0.0), "int32": Int32(0), "uint16": Uint16(0),
+        "uint32": Uint32(0), "uint64": Uint64(0), "uint128": Uint128(0), "utf8_string": "", "array": [], "map": {},
+    })
+    return build([("1.1.1.0/24", everything), ("0.0.0.0/32", zeros)], 4, 24, "MaxMind DB Decoder Test")
+
+
+def main():
+    here = os.path.dirname(os.path.abspath(__file__))
+    for name, make in (("city-ipv6-24.mmdb", city), ("asn-ipv4-28.mmdb", asn), ("anon-ipv6-32.mmdb", anonymous),
+                       ("decoder-ipv4-24.mmdb", decoder)):
+        content, count = make()
+        with open(os.path.join(here, name), "wb") as out:
+            out.write(content)
+        print(name, len(content), "bytes,", count, "nodes")
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,222 @@
+#!/usr/bin/env python3
+"""Generate the ONNX test fixtures used by onnx_model_test.go.
+
+The files are written with a tiny protobuf encoder so the fixtures do not
+depend on the Go decoder under test (and no onnx/skl2onnx install is needed).
+
+logreg.onnx mirrors what skl2onnx emits for a binary LogisticRegression with
+options={"zipmap": False}: LinearClassifier (two coefficient rows, LOGISTIC)
+followed by an L1 Normalizer. Repeated attribute fields are unpacked, as in
+files written by the onnx Python package (onnx.proto is proto2).
+
+mlp.onnx is a small MLP: Scaler, MatMul + Add + Relu, Gemm (transB, alpha,
+beta), Sigmoid, Softmax and Reshape to a flat vector, plus an unused node.
+Its initializers and Scaler attributes use raw_data, packed float_data,
+packed dims and packed attribute floats to cover the other encodings.
+
+Run from the repository root: python3 testdata/gen_onnx.py
+"""
+
+import math
+import os
+import struct
+
+WIRE_VARINT, WIRE_FIXED64, WIRE_BYTES, WIRE_FIXED32 = 0, 1, 2, 5
+FLOAT, INT64 = 1, 7
+ATTR_FLOAT, ATTR_INT, ATTR_STRING, ATTR_FLOATS, ATTR_INTS = 1, 2, 3, 6, 7
+
+
+def varint(value):
+    value &= (1 << 64) - 1
+    out = bytearray()
+    while True:
+        byte = value & 0x7F
+        value >>= 7
+        if value:
+            out.append(byte | 0x80)
+        else:
+            out.append(byte)
+            return bytes(out)
+
+
+def key(field, wire):
+    return varint(field << 3 | wire)
+
+
+def vint(field, value):
+    return key(field, WIRE_VARINT) + varint(value)
+
+
+def ld(field, payload):
+    if isinstance(payload, str):
+        payload = payload.encode()
+    return key(field, WIRE_BYTES) + varint(len(payload)) + payload
+
+
+def f32(value):
+    return struct.unpack("<f", struct.pack("<f", value))[0]
+
+
+def attribute(name, kind, value, packed=False):
+    out = ld(1, name) + vint(20, kind)
+    if kind == ATTR_FLOAT:
+        out += key(2, WIRE_FIXED32) + struct.pack("<f", value)
+    elif kind == ATTR_INT:
+        out += vint(3, value)
+    elif kind == ATTR_STRING:
+        out += ld(4, value)
+    elif kind == ATTR_FLOATS:
+        if packed:
+            out += ld(7, b"".join(struct.pack("<f", v) for v in value))
+        else:
+            out += b"".join(key(7, WIRE_FIXED32) + struct.pack("<f", v) for v in value)
+    elif kind == ATTR_INTS:
+        if packed:
+            out += ld(8, b"".join(varint(v) for v in value))
+        else:
+            out += b"".join(vint(8, v) for v in value)
+    return out
+
+
+def node(op, inputs, outputs, attrs=(), domain="", name=""):
+    out = b"".join(ld(1, i) for i in inputs)
+    out += b"".join(ld(2, o) for o in outputs)
+    if name:
+        out += ld(3, name)
+    out += ld(4, op)
+    out += b"".join(ld(5, a) for a in attrs)
+    if domain:
+        out += ld(7, domain)
+    return ld(1, out)
+
+
+def tensor(name, dims, values, encoding):
+    out = b""
+    if encoding == "packed_dims":
+        out += ld(1, b"".join(varint(d) for d in dims))
+    else:
+        out += b"".join(vint(1, d) for d in dims)
+    out += vint(2, FLOAT)
+    if encoding == "raw":
+        out += ld(9, b"".join(struct.pack("<f", v) for v in values))
+    else:
+        out += ld(4, b"".join(struct.pack("<f", v) for v in values))
+    out += ld(8, name)
+    return out
+
+
+def int64_tensor(name, values):
+    return vint(1, len(values)) + vint(2, INT64) + ld(7, b"".join(varint(v) for v in values)) + ld(8, name)
+
+
+def value_info(name, elem_type, dims):
+    shape = b""
+    for dim in dims:
+        shape += ld(1, ld(2, dim) if isinstance(dim, str) else vint(1, dim))
+    tensor_type = vint(1, elem_type) + ld(2, shape)
+    return ld(1, name) + ld(2, ld(1, tensor_type))
+
+
+def model(graph, producer, opsets):
+    out = vint(1, 7) + ld(2, producer) + ld(3, "1.16.0") + ld(4, "ai.onnx") + vint(5, 0) + ld(6, "")
+    out += ld(7, graph)
+    for domain, version in opsets:
+        out += ld(8, ld(1, domain) + vint(2, version))
+    return out
+
+
+def logreg():
+    coef = [0.75, -1.5, 2.25, 0.5]
+    intercept = -0.25
+    coefficients = [-c for c in coef] + coef
+    graph = node("LinearClassifier", ["float_input"], ["label", "probability_tensor"], [
+        attribute("classlabels_ints", ATTR_INTS, [0, 1]),
+        attribute("coefficients", ATTR_FLOATS, coefficients),
+        attribute("intercepts", ATTR_FLOATS, [-intercept, intercept]),
+        attribute("multi_class", ATTR_INT, 0),
+        attribute("post_transform", ATTR_STRING, "LOGISTIC"),
+    ], domain="ai.onnx.ml", name="LinearClassifier")
+    graph += node("Normalizer", ["probability_tensor"], ["probabilities"], [
+        attribute("norm", ATTR_STRING, "L1"),
+    ], domain="ai.onnx.ml", name="Normalizer")
+    graph += ld(2, "ONNX(LogisticRegression)")
+    graph += ld(11, value_info("float_input", FLOAT, ["N", 4]))
+    graph += ld(12, value_info("label", INT64, ["N"]))
+    graph += ld(12, value_info("probabilities", FLOAT, ["N", 2]))
+
+    inputs = [[0, 0, 0, 0], [1, 0, 1, 0], [0, 2, 0, 1], [0.5, -1, 0.25, 3]]
+    expected = []
+    for x in inputs:
+        z = f32(intercept) + sum(f32(c) * v for c, v in zip(coef, x))
+        p = 1 / (1 + math.exp(-z))
+        expected.append((x, [1 - p, p]))
+    return model(graph, "skl2onnx", [("ai.onnx.ml", 1), ("", 9)]), expected
+
+
+def mlp():
+    w1 = [0.5, -0.25, 1.0, 0.75, -0.5, 0.125]  # [3, 2]
+    b1 = [0.1, -0.2]
+    w2 = [1.5, -1.0, -0.5, 2.0]  # [2(out), 2(in)], used with transB
+    b2 = [0.05, -0.05]
+    alpha, beta = 0.5, 2.0
+    offset, scale = [1.0, 0.0, -1.0], [0.5, 2.0, 1.0]
+
+    graph = node("Scaler", ["input"], ["scaled"], [
+        attribute("offset", ATTR_FLOATS, offset, packed=True),
+        attribute("scale", ATTR_FLOATS, scale, packed=True),
+    ], domain="ai.onnx.ml")
+    graph += node("MatMul", ["scaled", "W1"], ["h0"])
+    graph += node("Add", ["h0", "B1"], ["h1"])
+    graph += node("Relu", ["h1"], ["h2"])
+    graph += node("Gemm", ["h2", "W2", "B2"], ["logits"], [
+        attribute("alpha", ATTR_FLOAT, alpha),
+        attribute("beta", ATTR_FLOAT, beta),
+        attribute("transB", ATTR_INT, 1),
+    ])
+    graph += node("Sigmoid", ["logits"], ["gate"])
+    graph += node("Softmax", ["gate"], ["probs"], [attribute("axis", ATTR_INT, 1)])
+    graph += node("Reshape", ["probs", "flat_shape"], ["scores"])
+    graph += node("Identity", ["logits"], ["unused"])  # not needed for the output, must be skipped
+    graph += ld(2, "mlp")
+    graph += ld(5, tensor("W1", [3, 2], w1, "raw"))
+    graph += ld(5, tensor("B1", [2], b1, "packed"))
+    graph += ld(5, tensor("W2", [2, 2], w2, "packed_dims"))
+    graph += ld(5, tensor("B2", [2], b2, "raw"))
+    graph += ld(5, int64_tensor("flat_shape", [2**64 - 1]))  # -1 as an int64 varint
+    graph += ld(11, value_info("input", FLOAT, [1, 3]))
+    graph += ld(11, value_info("W1", FLOAT, [3, 2]))
+    graph += ld(12, value_info("unused_label", INT64, [1]))
+    graph += ld(12, value_info("scores", FLOAT, [2]))
+
+    inputs = [[1, 2, 3], [-1, 0.5, 0], [0, 0, 0]]
+    expected = []
+    for x in inputs:
+        scaled = [(x[i] - f32(offset[i])) * f32(scale[i]) for i in range(3)]
+        h = []
+        for j in range(2):
+            v = sum(scaled[i] * f32(w1[i * 2 + j]) for i in range(3)) + f32(b1[j])
+            h.append(max(v, 0.0))
+        logits = []
+        for j in range(2):
+            v = alpha * sum(h[i] * f32(w2[j * 2 + i]) for i in range(2)) + beta * f32(b2[j])
+            logits.append(v)
+        gates = [1 / (1 + math.exp(-v)) for v in logits]
+        peak = max(gates)
+        exps = [math.exp(v - peak) for v in gates]
+        expected.append((x, [e / sum(exps) for e in exps]))
+    return model(graph, "hand-written", [("", 13), ("ai.onnx.ml", 1)]), expected
+
+
+def main():
+    here = os.path.dirname(os.path.abspath(__file__))
+    for name, build in (("logreg.onnx", logreg), ("mlp.onnx", mlp)):
+        data, expected = build()
+        with open(os.path.join(here, name), "wb") as out:
+            out.write(data)
+        print(name)
+        for x, scores in expected:
+            print("  ", x, ["%.15g" % s for s in scores])
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,313 @@
+/*
+Threat Intelligence Module
+==========================
+
+로그인 출처 IP 평판 조회 (AbuseIPDB / 로컬 블록리스트)
+
+주요 기능:
+- 로컬 블록리스트 파일 로드 (login.threat_blocklist_files, 예: Spamhaus DROP/EDROP, FireHOL netset)
+- AbuseIPDB check API 조회 (login.abuseipdb_api_key 또는 ABUSEIPDB_API_KEY 환경 변수)
+- 조회 결과 메모리 캐시 (24시간, 무료 요금제 일일 조회 한도 보호), 429 응답 시 잠시 조회 중단
+- 블록리스트 일치 또는 신뢰 점수가 login.abuseipdb_min_score 이상이면 알려진 악성 IP로 판정
+
+블록리스트 파일 형식:
+- 한 줄에 CIDR 또는 IP 하나, "#" 또는 ";" 이후는 주석 (Spamhaus DROP: "1.10.16.0/20 ; SBL256894")
+- 일치한 목록 이름은 파일 이름 (확장자 제외, 예: drop.txt → drop)
+*/
+package main
+
+import (
+	"bufio"         // 블록리스트 라인 읽기
+	"encoding/json" // API 응답 파싱
+	"fmt"           // 형식화된 I/O
+	"net/http"      // AbuseIPDB API
+	"net/url"       // 쿼리 인코딩
+	"os"            // 파일 처리
+	"path/filepath" // 경로 처리
+	"strings"       // 문자열 처리
+	"sync"          // 동기화
+	"time"          // 시간 처리
+)
+
+// AbuseIPDB 조회 설정
+const (
+	AbuseIPDBCheckURL        = "https://api.abuseipdb.com/api/v2/check" // check API 엔드포인트
+	AbuseIPDBAPIKeyEnv       = "ABUSEIPDB_API_KEY"                      // 설정 파일 대신 사용할 API 키 환경 변수
+	DefaultAbuseIPDBMinScore = 75                                       // 알려진 악성 IP 판정 신뢰 점수 (0-100)
+	abuseIPDBMaxAgeDays      = 90                                       // 조회할 신고 기간 (일)
+	abuseIPDBCacheTTL        = 24 * time.Hour                           // 조회 결과 캐시 유지 시간
+	abuseIPDBFailureTTL      = 10 * time.Minute                         // 조회 실패 시 재조회까지 대기 시간
+	abuseIPDBRateLimitPause  = time.Hour                                // 429 응답 후 조회 중단 시간
+	abuseIPDBMaxCacheEntries = 10000                                    // 캐시 항목 수가 이를 넘으면 만료 항목 정리
+)
+
+// ThreatReputation IP 평판 조회 결과
+type ThreatReputation struct {
+	Blocklist    string // 일치한 블록리스트 이름 (없으면 빈 문자열)
+	AbuseScore   int    // AbuseIPDB 신뢰 점수 (0-100, 조회하지 않았으면 0)
+	AbuseReports int    // AbuseIPDB 신고 건수
+	KnownBad     bool   // 블록리스트 일치 또는 신뢰 점수가 임계값 이상
+}
+
+// abuseIPDBEntry AbuseIPDB 조회 캐시 항목
+type abuseIPDBEntry struct {
+	score   int
+	reports int
+	failed  bool // 조회 실패 (abuseIPDBFailureTTL 후 재조회)
+	at      time.Time
+}
+
+// ThreatIntel 블록리스트 / AbuseIPDB 기반 IP 평판 조회기
+type ThreatIntel struct {
+	blocklists *TrustedNetworks // 블록리스트 CIDR (이름=목록 이름, CIDR 매칭은 신뢰 네트워크 구현 재사용)
+	apiKey     string           // 빈 문자열이면 AbuseIPDB 조회 비활성화
+	minScore   int
+	cache      map[string]*abuseIPDBEntry
+	pending    map[string][]func() // 조회 중인 IP → 조회 후 실행할 콜백
+	pausedTill time.Time           // 조회 한도 초과 후 조회 재개 시각
+	checkURL   string
+	client     *http.Client
+	logger     Logger
+	checks     sync.WaitGroup // 진행 중인 AbuseIPDB 조회
+	mutex      sync.RWMutex
+}
+
+// NewThreatIntel 새로운 IP 평판 조회기 생성 (블록리스트/API 키 없이 시작)
+func NewThreatIntel(logger Logger) *ThreatIntel {
+	return &ThreatIntel{
+		minScore: DefaultAbuseIPDBMinScore,
+		cache:    make(map[string]*abuseIPDBEntry),
+		pending:  make(map[string][]func()),
+		checkURL: AbuseIPDBCheckURL,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		logger:   logger,
+	}
+}
+
+// Configure 블록리스트 파일, AbuseIPDB API 키, 악성 판정 점수 설정
+// apiKey 가 비어 있으면 ABUSEIPDB_API_KEY 환경 변수 사용, minScore 가 0 이하면 기본값
+func (ti *ThreatIntel) Configure(blocklistFiles []string, apiKey string, minScore int) error {
+	var specs []string
+	for _, path := range blocklistFiles {
+		entries, err := loadBlocklistFile(expandHomePath(path))
+		if err != nil {
+			return err
+		}
+		specs = append(specs, entries...)
+	}
+	blocklists, err := ParseTrustedNetworks(specs)
+	if err != nil {
+		return fmt.Errorf("invalid threat blocklist: %v", err)
+	}
+
+	if apiKey = strings.TrimSpace(apiKey); apiKey == "" {
+		apiKey = strings.TrimSpace(os.Getenv(AbuseIPDBAPIKeyEnv))
+	}
+	if minScore <= 0 {
+		minScore = DefaultAbuseIPDBMinScore
+	}
+
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+	ti.blocklists = blocklists
+	ti.minScore = minScore
+	if apiKey != ti.apiKey {
+		ti.apiKey = apiKey
+		ti.cache = make(map[string]*abuseIPDBEntry)
+		ti.pausedTill = time.Time{}
+	}
+	return nil
+}
+
+// Enabled 블록리스트 또는 AbuseIPDB 조회가 설정되어 있는지 확인
+func (ti *ThreatIntel) Enabled() bool {
+	if ti == nil {
+		return false
+	}
+	ti.mutex.RLock()
+	defer ti.mutex.RUnlock()
+	return ti.apiKey != "" || (ti.blocklists != nil && len(ti.blocklists.entries) > 0)
+}
+
+// CheckAsync AbuseIPDB 조회가 필요하면 (API 키 설정, 캐시 없음/만료, 조회 중단 아님)
+// 조회 고루틴에서 결과를 캐시에 저장한 뒤 done 을 실행하고 true 반환
+// 조회가 필요 없으면 호출한 고루틴에서 즉시 done 을 실행하고 false 반환 (같은 IP 조회가 진행 중이면 함께 대기)
+func (ti *ThreatIntel) CheckAsync(ip string, done func()) bool {
+	if ti == nil || ip == "" {
+		done()
+		return false
+	}
+
+	ti.mutex.Lock()
+	if ti.apiKey == "" || time.Now().Before(ti.pausedTill) || ti.freshLocked(ti.cache[ip], time.Now()) {
+		ti.mutex.Unlock()
+		done()
+		return false
+	}
+	waiting, inflight := ti.pending[ip]
+	ti.pending[ip] = append(waiting, done)
+	ti.mutex.Unlock()
+	if inflight {
+		return true
+	}
+
+	ti.checks.Add(1)
+	go func() {
+		defer ti.checks.Done()
+		ti.check(ip)
+
+		ti.mutex.Lock()
+		callbacks := ti.pending[ip]
+		delete(ti.pending, ip)
+		ti.mutex.Unlock()
+		for _, callback := range callbacks {
+			callback()
+		}
+	}()
+	return true
+}
+
+// Wait 진행 중인 AbuseIPDB 조회(콜백 포함)가 끝날 때까지 대기
+func (ti *ThreatIntel) Wait() {
+	if ti != nil {
+		ti.checks.Wait()
+	}
+}
+
+// Reputation 블록리스트와 캐시된 AbuseIPDB 결과로 IP 평판 반환 (외부 조회 없음)
+func (ti *ThreatIntel) Reputation(ip string) ThreatReputation {
+	var reputation ThreatReputation
+	if ti == nil || ip == "" {
+		return reputation
+	}
+	ti.mutex.RLock()
+	defer ti.mutex.RUnlock()
+
+	if name, matched := ti.blocklists.Match(ip); matched {
+		reputation.Blocklist = name
+		reputation.KnownBad = true
+	}
+	if entry := ti.cache[ip]; entry != nil && !entry.failed && ti.freshLocked(entry, time.Now()) {
+		reputation.AbuseScore = entry.score
+		reputation.AbuseReports = entry.reports
+		if entry.score >= ti.minScore {
+			reputation.KnownBad = true
+		}
+	}
+	return reputation
+}
+
+// freshLocked 캐시 항목이 아직 유효한지 확인 (mutex 보유 상태에서 호출)
+func (ti *ThreatIntel) freshLocked(entry *abuseIPDBEntry, now time.Time) bool {
+	if entry == nil {
+		return false
+	}
+	ttl := abuseIPDBCacheTTL
+	if entry.failed {
+		ttl = abuseIPDBFailureTTL
+	}
+	return now.Sub(entry.at) < ttl
+}
+
+// check AbuseIPDB check API 조회 후 결과(실패 포함)를 캐시에 저장
+func (ti *ThreatIntel) check(ip string) {
+	ti.mutex.RLock()
+	apiKey, checkURL := ti.apiKey, ti.checkURL
+	ti.mutex.RUnlock()
+	if apiKey == "" {
+		return
+	}
+
+	entry := &abuseIPDBEntry{at: time.Now()}
+	score, reports, err := ti.fetchAbuseIPDB(checkURL, apiKey, ip)
+	if err != nil {
+		entry.failed = true
+		ti.logger.Errorf("AbuseIPDB lookup for %s failed: %v", ip, err)
+	} else {
+		entry.score, entry.reports = score, reports
+	}
+
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+	if apiKey != ti.apiKey {
+		return // 조회 중 API 키가 바뀜
+	}
+	ti.cache[ip] = entry
+	if err == errAbuseIPDBRateLimited {
+		ti.pausedTill = time.Now().Add(abuseIPDBRateLimitPause)
+	}
+	// 만료된 항목 정리 (캐시가 무한히 커지지 않도록)
+	if len(ti.cache) > abuseIPDBMaxCacheEntries {
+		now := time.Now()
+		for cachedIP, cached := range ti.cache {
+			if !ti.freshLocked(cached, now) {
+				delete(ti.cache, cachedIP)
+			}
+		}
+	}
+}
+
+// errAbuseIPDBRateLimited 일일 조회 한도 초과 (429)
+var errAbuseIPDBRateLimited = fmt.Errorf("abuseipdb rate limit exceeded")
+
+// fetchAbuseIPDB AbuseIPDB check API 호출 (신뢰 점수, 신고 건수 반환)
+func (ti *ThreatIntel) fetchAbuseIPDB(checkURL, apiKey, ip string) (int, int, error) {
+	query := url.Values{}
+	query.Set("ipAddress", ip)
+	query.Set("maxAgeInDays", fmt.Sprintf("%d", abuseIPDBMaxAgeDays))
+	req, err := http.NewRequest(http.MethodGet, checkURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	req.Header.Set("Key", apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := ti.client.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return 0, 0, errAbuseIPDBRateLimited
+	case resp.StatusCode != http.StatusOK:
+		return 0, 0, fmt.Errorf("abuseipdb returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			AbuseConfidenceScore int `json:"abuseConfidenceScore"`
+			TotalReports         int `json:"totalReports"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, 0, fmt.Errorf("failed to decode abuseipdb response: %v", err)
+	}
+	return result.Data.AbuseConfidenceScore, result.Data.TotalReports, nil
+}
+
+// loadBlocklistFile 블록리스트 파일을 "목록이름=CIDR" 항목으로 로드 ("#", ";" 이후 주석 무시)
+func loadBlocklistFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open threat blocklist %s: %v", path, err)
+	}
+	defer file.Close()
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	var entries []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.IndexAny(line, "#;"); idx >= 0 {
+			line = line[:idx]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			entries = append(entries, name+"="+line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read threat blocklist %s: %v", path, err)
+	}
+	return entries, nil
+}
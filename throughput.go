@@ -0,0 +1,425 @@
+/*
+Throughput Module
+=================
+
+디스크 I/O 와 네트워크 처리량(초당 변화율) 수집 및 포화 감지
+
+주요 기능:
+- 인터페이스별 수신/송신 바이트·패킷·에러·드롭 초당 변화율 (루프백 제외, 전체 합계 포함)
+- 링크 속도(Linux /sys/class/net/<if>/speed) 대비 네트워크 사용률
+- 디스크별 읽기/쓰기 IOPS, 처리량, 사용 중 시간 비율(busy %)
+- 설정한 임계값을 두 수집 주기 연속으로 넘으면 포화 알림 (일시적 스파이크 무시)
+
+누적 카운터의 차이를 수집 간격으로 나누므로 두 번째 수집부터 값이 채워집니다.
+카운터가 줄어든 경우(인터페이스 재생성, 카운터 리셋)는 해당 구간을 건너뜁니다.
+디스크 busy % 는 Linux/macOS 에서만 계산됩니다 (Windows 는 장치 사용 시간을 제공하지 않음).
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"os"      // 파일 존재 확인
+	"runtime" // Go 런타임 정보
+	"sort"    // 정렬
+	"strconv" // 문자열-숫자 변환
+	"strings" // 문자열 처리
+	"time"    // 시간 처리
+
+	"github.com/shirou/gopsutil/v4/disk"      // 디스크 I/O 카운터
+	psnet "github.com/shirou/gopsutil/v4/net" // 네트워크 인터페이스 통계
+)
+
+// 보고서에 표시할 최대 장치/인터페이스 수
+const throughputReportDevices = 5
+
+// InterfaceRate 인터페이스별 초당 처리량
+type InterfaceRate struct {
+	Name               string  `json:"name"`
+	RecvBytesPerSec    float64 `json:"recv_bytes_per_sec"`
+	SentBytesPerSec    float64 `json:"sent_bytes_per_sec"`
+	RecvPacketsPerSec  float64 `json:"recv_packets_per_sec"`
+	SentPacketsPerSec  float64 `json:"sent_packets_per_sec"`
+	ErrorsPerSec       float64 `json:"errors_per_sec"`                // 수신 + 송신 에러
+	DropsPerSec        float64 `json:"drops_per_sec"`                 // 수신 + 송신 드롭
+	SpeedMbps          float64 `json:"speed_mbps,omitempty"`          // 링크 속도 (알 수 없으면 0)
+	UtilizationPercent float64 `json:"utilization_percent,omitempty"` // 링크 속도 대비 사용률 (수신/송신 중 큰 값)
+}
+
+// DiskIOMetrics 디스크 I/O 초당 변화율
+type DiskIOMetrics struct {
+	Available        bool         `json:"available"` // 두 번째 수집부터 true
+	ReadIOPS         float64      `json:"read_iops"`
+	WriteIOPS        float64      `json:"write_iops"`
+	ReadBytesPerSec  float64      `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64      `json:"write_bytes_per_sec"`
+	Devices          []DiskIORate `json:"devices,omitempty"`
+}
+
+// DiskIORate 디스크 장치별 초당 I/O
+type DiskIORate struct {
+	Name             string  `json:"name"`
+	ReadIOPS         float64 `json:"read_iops"`
+	WriteIOPS        float64 `json:"write_iops"`
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec"`
+	BusyPercent      float64 `json:"busy_percent"` // 구간 중 장치가 I/O 를 처리한 시간 비율
+}
+
+// IOPS 읽기 + 쓰기 IOPS
+func (d DiskIORate) IOPS() float64 {
+	return d.ReadIOPS + d.WriteIOPS
+}
+
+// BytesPerSec 읽기 + 쓰기 처리량
+func (d DiskIORate) BytesPerSec() float64 {
+	return d.ReadBytesPerSec + d.WriteBytesPerSec
+}
+
+// counterRate 누적 카운터의 초당 변화율 (카운터가 줄었으면 0)
+func counterRate(current, previous uint64, seconds float64) float64 {
+	if current < previous || seconds <= 0 {
+		return 0
+	}
+	return float64(current-previous) / seconds
+}
+
+// bytesToMbps 초당 바이트를 Mbps 로 변환
+func bytesToMbps(bytesPerSec float64) float64 {
+	return bytesPerSec * 8 / 1000 / 1000
+}
+
+// formatByteRate 초당 바이트를 사람이 읽기 쉬운 단위로 표시
+func formatByteRate(bytesPerSec float64) string {
+	return formatByteSize(int64(bytesPerSec)) + "/s"
+}
+
+// linkSpeedMbps 인터페이스 링크 속도 (Linux 전용, 가상 인터페이스 등 알 수 없으면 0)
+func linkSpeedMbps(name string) float64 {
+	if runtime.GOOS != "linux" {
+		return 0
+	}
+	data, err := os.ReadFile(hostSysPath("class", "net", name, "speed"))
+	if err != nil {
+		return 0
+	}
+	speed, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	if err != nil || speed <= 0 {
+		return 0
+	}
+	return speed
+}
+
+// collectNetworkRates 이전 수집 이후의 인터페이스별 처리량 계산 (collectNetworkMetrics 에서 호출)
+func (sm *SystemMonitor) collectNetworkRates(counters []psnet.IOCountersStat, loopback map[string]bool) {
+	now := time.Now()
+	previous, elapsed := sm.lastNetCounters, now.Sub(sm.lastNetTime).Seconds()
+
+	sm.lastNetCounters = make(map[string]psnet.IOCountersStat, len(counters))
+	sm.lastNetTime = now
+	for _, counter := range counters {
+		if !loopback[counter.Name] {
+			sm.lastNetCounters[counter.Name] = counter
+		}
+	}
+	if previous == nil || elapsed <= 0 {
+		return
+	}
+
+	network := &sm.metrics.Network
+	for _, counter := range counters {
+		prev, ok := previous[counter.Name]
+		if !ok || loopback[counter.Name] {
+			continue
+		}
+		// 카운터 리셋 구간은 합계를 왜곡하므로 건너뜀
+		if counter.BytesRecv < prev.BytesRecv || counter.BytesSent < prev.BytesSent {
+			continue
+		}
+
+		rate := InterfaceRate{
+			Name:              counter.Name,
+			RecvBytesPerSec:   counterRate(counter.BytesRecv, prev.BytesRecv, elapsed),
+			SentBytesPerSec:   counterRate(counter.BytesSent, prev.BytesSent, elapsed),
+			RecvPacketsPerSec: counterRate(counter.PacketsRecv, prev.PacketsRecv, elapsed),
+			SentPacketsPerSec: counterRate(counter.PacketsSent, prev.PacketsSent, elapsed),
+			ErrorsPerSec:      counterRate(counter.Errin, prev.Errin, elapsed) + counterRate(counter.Errout, prev.Errout, elapsed),
+			DropsPerSec:       counterRate(counter.Dropin, prev.Dropin, elapsed) + counterRate(counter.Dropout, prev.Dropout, elapsed),
+			SpeedMbps:         linkSpeedMbps(counter.Name),
+		}
+		if rate.SpeedMbps > 0 {
+			busiest := rate.RecvBytesPerSec
+			if rate.SentBytesPerSec > busiest {
+				busiest = rate.SentBytesPerSec
+			}
+			rate.UtilizationPercent = bytesToMbps(busiest) / rate.SpeedMbps * 100
+		}
+
+		network.Interfaces = append(network.Interfaces, rate)
+		network.RecvBytesPerSec += rate.RecvBytesPerSec
+		network.SentBytesPerSec += rate.SentBytesPerSec
+		network.RecvPacketsPerSec += rate.RecvPacketsPerSec
+		network.SentPacketsPerSec += rate.SentPacketsPerSec
+		network.ErrorsPerSec += rate.ErrorsPerSec
+		network.DropsPerSec += rate.DropsPerSec
+	}
+
+	sort.Slice(network.Interfaces, func(i, j int) bool {
+		a, b := network.Interfaces[i], network.Interfaces[j]
+		return a.RecvBytesPerSec+a.SentBytesPerSec > b.RecvBytesPerSec+b.SentBytesPerSec
+	})
+	network.RatesAvailable = true
+}
+
+// isWholeDisk 파티션/루프/램 디스크가 아닌 물리(또는 가상) 디스크인지 확인
+// Linux 는 /sys/block 에 있는 장치만 디스크로 취급 (sda1, nvme0n1p1 같은 파티션 제외)
+func isWholeDisk(name string) bool {
+	if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") {
+		return false
+	}
+	if runtime.GOOS != "linux" {
+		return true
+	}
+	if _, err := os.Stat(hostSysPath("block")); err != nil {
+		return true // /sys 가 없는 환경에서는 필터링하지 않음
+	}
+	_, err := os.Stat(hostSysPath("block", name))
+	return err == nil
+}
+
+// isStackedDisk 다른 디스크 위에 만들어진 장치 (LVM, 소프트웨어 RAID) - 합계에서 제외해 이중 집계 방지
+func isStackedDisk(name string) bool {
+	return strings.HasPrefix(name, "dm-") || strings.HasPrefix(name, "md")
+}
+
+// collectDiskIOMetrics 이전 수집 이후의 디스크별 IOPS, 처리량, busy % 계산
+func (sm *SystemMonitor) collectDiskIOMetrics() {
+	counters, err := disk.IOCounters()
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	previous, elapsed := sm.lastDiskCounters, now.Sub(sm.lastDiskTime).Seconds()
+
+	sm.lastDiskCounters = make(map[string]disk.IOCountersStat, len(counters))
+	sm.lastDiskTime = now
+	for name, counter := range counters {
+		if isWholeDisk(name) {
+			sm.lastDiskCounters[name] = counter
+		}
+	}
+	if previous == nil || elapsed <= 0 {
+		return
+	}
+
+	diskIO := &sm.metrics.DiskIO
+	for name, counter := range sm.lastDiskCounters {
+		prev, ok := previous[name]
+		if !ok || counter.ReadCount < prev.ReadCount || counter.WriteCount < prev.WriteCount {
+			continue
+		}
+
+		rate := DiskIORate{
+			Name:             name,
+			ReadIOPS:         counterRate(counter.ReadCount, prev.ReadCount, elapsed),
+			WriteIOPS:        counterRate(counter.WriteCount, prev.WriteCount, elapsed),
+			ReadBytesPerSec:  counterRate(counter.ReadBytes, prev.ReadBytes, elapsed),
+			WriteBytesPerSec: counterRate(counter.WriteBytes, prev.WriteBytes, elapsed),
+		}
+		// IoTime 은 밀리초 단위 누적 사용 시간 (macOS 는 읽기+쓰기 시간 합이라 100% 를 넘을 수 있어 제한)
+		if busy := counterRate(counter.IoTime, prev.IoTime, elapsed) / 1000 * 100; busy > 0 {
+			if busy > 100 {
+				busy = 100
+			}
+			rate.BusyPercent = busy
+		}
+
+		diskIO.Devices = append(diskIO.Devices, rate)
+		if !isStackedDisk(name) {
+			diskIO.ReadIOPS += rate.ReadIOPS
+			diskIO.WriteIOPS += rate.WriteIOPS
+			diskIO.ReadBytesPerSec += rate.ReadBytesPerSec
+			diskIO.WriteBytesPerSec += rate.WriteBytesPerSec
+		}
+	}
+
+	sort.Slice(diskIO.Devices, func(i, j int) bool {
+		a, b := diskIO.Devices[i], diskIO.Devices[j]
+		if a.BusyPercent != b.BusyPercent {
+			return a.BusyPercent > b.BusyPercent
+		}
+		return a.IOPS() > b.IOPS()
+	})
+	diskIO.Available = true
+}
+
+// checkThroughputAlerts 디스크 I/O / 네트워크 포화 알림
+// 직전 수집 주기에도 같은 장치가 임계값을 넘은 경우에만 알림 (일시적 스파이크 무시)
+func (sm *SystemMonitor) checkThroughputAlerts() {
+	var previous *SystemMetrics
+	if len(sm.history) > 0 {
+		previous = &sm.history[len(sm.history)-1]
+	}
+
+	if sm.metrics.DiskIO.Available {
+		prevDisks := make(map[string]DiskIORate)
+		if previous != nil {
+			for _, device := range previous.DiskIO.Devices {
+				prevDisks[device.Name] = device
+			}
+		}
+		for _, device := range sm.metrics.DiskIO.Devices {
+			reason, value, threshold := sm.diskSaturation(device)
+			if reason == "" {
+				continue
+			}
+			prev, ok := prevDisks[device.Name]
+			if !ok {
+				continue
+			}
+			if prevReason, _, _ := sm.diskSaturation(prev); prevReason == "" {
+				continue
+			}
+			sm.sendAlert(SystemAlert{
+				Level: "HIGH",
+				Type:  "DISK_IO",
+				Message: fmt.Sprintf("디스크 I/O 가 포화 상태입니다 (%s): %s - 읽기 %.0f / 쓰기 %.0f IOPS, %s, 사용 중 %.1f%%",
+					device.Name, reason, device.ReadIOPS, device.WriteIOPS, formatByteRate(device.BytesPerSec()), device.BusyPercent),
+				Value:     value,
+				Threshold: threshold,
+				Metrics:   *sm.metrics,
+				Timestamp: time.Now(),
+				Suggestions: []string{
+					"💽 장치별 대기 시간과 큐 길이 확인: iostat -x 1",
+					"🔍 I/O 를 많이 발생시키는 프로세스 확인: iotop",
+					"📦 백업/로그 로테이션/인덱싱 작업 스케줄 조정 검토",
+				},
+			})
+		}
+	}
+
+	if sm.metrics.Network.RatesAvailable {
+		prevInterfaces := make(map[string]InterfaceRate)
+		if previous != nil {
+			for _, iface := range previous.Network.Interfaces {
+				prevInterfaces[iface.Name] = iface
+			}
+		}
+		for _, iface := range sm.metrics.Network.Interfaces {
+			prev, ok := prevInterfaces[iface.Name]
+			if !ok {
+				continue
+			}
+
+			if reason, value, threshold := sm.networkSaturation(iface); reason != "" {
+				if prevReason, _, _ := sm.networkSaturation(prev); prevReason != "" {
+					sm.sendAlert(SystemAlert{
+						Level: "HIGH",
+						Type:  "NETWORK",
+						Message: fmt.Sprintf("네트워크 대역폭이 포화 상태입니다 (%s): %s - 수신 %.1f Mbps, 송신 %.1f Mbps",
+							iface.Name, reason, bytesToMbps(iface.RecvBytesPerSec), bytesToMbps(iface.SentBytesPerSec)),
+						Value:     value,
+						Threshold: threshold,
+						Metrics:   *sm.metrics,
+						Timestamp: time.Now(),
+						Suggestions: []string{
+							"🔍 트래픽이 많은 연결/프로세스 확인: iftop, nethogs",
+							"📦 대용량 전송(백업, 복제) 스케줄 및 대역폭 제한 검토",
+							"🛡️  비정상 트래픽이면 DDoS 또는 데이터 유출 가능성 점검",
+						},
+					})
+				}
+			}
+
+			threshold := sm.thresholds.NetworkErrors
+			if threshold > 0 && iface.ErrorsPerSec+iface.DropsPerSec >= threshold && prev.ErrorsPerSec+prev.DropsPerSec >= threshold {
+				sm.sendAlert(SystemAlert{
+					Level:     "MEDIUM",
+					Type:      "NETWORK_ERRORS",
+					Message:   fmt.Sprintf("네트워크 에러/드롭이 계속 발생하고 있습니다 (%s): 에러 %.1f/s, 드롭 %.1f/s", iface.Name, iface.ErrorsPerSec, iface.DropsPerSec),
+					Value:     iface.ErrorsPerSec + iface.DropsPerSec,
+					Threshold: threshold,
+					Metrics:   *sm.metrics,
+					Timestamp: time.Now(),
+					Suggestions: []string{
+						"🔌 케이블/링크 상태와 듀플렉스 설정 확인: ethtool " + iface.Name,
+						"📊 인터페이스 에러 카운터 확인: ip -s link show " + iface.Name,
+						"📥 수신 버퍼 부족이면 링 버퍼/netdev_max_backlog 조정 검토",
+					},
+				})
+			}
+		}
+	}
+}
+
+// diskSaturation 디스크 장치가 넘은 임계값 (넘지 않았으면 빈 문자열)
+func (sm *SystemMonitor) diskSaturation(device DiskIORate) (reason string, value, threshold float64) {
+	if sm.thresholds.DiskBusy > 0 && device.BusyPercent >= sm.thresholds.DiskBusy {
+		return fmt.Sprintf("사용 중 시간 %.1f%%", device.BusyPercent), device.BusyPercent, sm.thresholds.DiskBusy
+	}
+	if sm.thresholds.DiskIOPS > 0 && device.IOPS() >= sm.thresholds.DiskIOPS {
+		return fmt.Sprintf("%.0f IOPS", device.IOPS()), device.IOPS(), sm.thresholds.DiskIOPS
+	}
+	mbps := device.BytesPerSec() / bytesPerMB
+	if sm.thresholds.DiskMBps > 0 && mbps >= sm.thresholds.DiskMBps {
+		return fmt.Sprintf("%.1f MB/s", mbps), mbps, sm.thresholds.DiskMBps
+	}
+	return "", 0, 0
+}
+
+// networkSaturation 인터페이스가 넘은 대역폭 임계값 (넘지 않았으면 빈 문자열)
+func (sm *SystemMonitor) networkSaturation(iface InterfaceRate) (reason string, value, threshold float64) {
+	if sm.thresholds.NetworkPercent > 0 && iface.UtilizationPercent >= sm.thresholds.NetworkPercent {
+		return fmt.Sprintf("링크 %.0f Mbps 의 %.1f%%", iface.SpeedMbps, iface.UtilizationPercent), iface.UtilizationPercent, sm.thresholds.NetworkPercent
+	}
+	mbps := bytesToMbps(iface.RecvBytesPerSec)
+	if sent := bytesToMbps(iface.SentBytesPerSec); sent > mbps {
+		mbps = sent
+	}
+	if sm.thresholds.NetworkMbps > 0 && mbps >= sm.thresholds.NetworkMbps {
+		return fmt.Sprintf("%.1f Mbps", mbps), mbps, sm.thresholds.NetworkMbps
+	}
+	return "", 0, 0
+}
+
+// generateThroughputSection 보고서용 디스크 I/O / 네트워크 처리량 요약 (아직 계산되지 않았으면 빈 문자열)
+func generateThroughputSection(metrics SystemMetrics) string {
+	var builder strings.Builder
+
+	if diskIO := metrics.DiskIO; diskIO.Available {
+		builder.WriteString("\n💽 디스크 I/O:\n")
+		builder.WriteString(fmt.Sprintf("  - 전체: 읽기 %.0f IOPS (%s), 쓰기 %.0f IOPS (%s)\n",
+			diskIO.ReadIOPS, formatByteRate(diskIO.ReadBytesPerSec), diskIO.WriteIOPS, formatByteRate(diskIO.WriteBytesPerSec)))
+		for i, device := range diskIO.Devices {
+			if i == throughputReportDevices {
+				break
+			}
+			line := fmt.Sprintf("  - %s: %.0f IOPS, %s", device.Name, device.IOPS(), formatByteRate(device.BytesPerSec()))
+			if runtime.GOOS != "windows" {
+				line += fmt.Sprintf(", 사용 중 %.1f%%", device.BusyPercent)
+			}
+			builder.WriteString(line + "\n")
+		}
+	}
+
+	if network := metrics.Network; network.RatesAvailable {
+		builder.WriteString("\n📶 네트워크 처리량:\n")
+		builder.WriteString(fmt.Sprintf("  - 전체: 수신 %.1f Mbps (%.0f pps), 송신 %.1f Mbps (%.0f pps), 에러 %.1f/s, 드롭 %.1f/s\n",
+			bytesToMbps(network.RecvBytesPerSec), network.RecvPacketsPerSec,
+			bytesToMbps(network.SentBytesPerSec), network.SentPacketsPerSec,
+			network.ErrorsPerSec, network.DropsPerSec))
+		for i, iface := range network.Interfaces {
+			// 트래픽이 없는 인터페이스는 생략 (처리량 내림차순이므로 이후도 모두 0)
+			if i == throughputReportDevices || iface.RecvBytesPerSec+iface.SentBytesPerSec == 0 {
+				break
+			}
+			line := fmt.Sprintf("  - %s: 수신 %.1f Mbps, 송신 %.1f Mbps", iface.Name, bytesToMbps(iface.RecvBytesPerSec), bytesToMbps(iface.SentBytesPerSec))
+			if iface.SpeedMbps > 0 {
+				line += fmt.Sprintf(" (링크 %.0f Mbps 의 %.1f%%)", iface.SpeedMbps, iface.UtilizationPercent)
+			}
+			builder.WriteString(line + "\n")
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,99 @@
+/*
+Trusted Networks Module
+=======================
+
+신뢰 네트워크(사무실, VPN 등) CIDR 목록 관리
+
+주요 기능:
+- CIDR 또는 단일 IP 목록 파싱 (선택적으로 "이름=CIDR" 형식의 이름 지정)
+- IP 주소가 신뢰 네트워크에 속하는지 확인
+- 신뢰 네트워크 출처는 GeoIP 조회와 위험도 평가를 생략하고 알림 우선순위를 낮춤
+
+설정 예시:
+- "10.8.0.0/16"
+- "office=203.0.113.0/24"
+- "vpn=198.51.100.7"
+*/
+package main
+
+import (
+	"fmt"     // 형식화된 I/O
+	"net"     // CIDR 파싱
+	"strings" // 문자열 처리
+)
+
+// trustedNetwork 이름이 붙은 신뢰 네트워크
+type trustedNetwork struct {
+	name    string
+	network *net.IPNet
+}
+
+// TrustedNetworks 신뢰 네트워크 목록 (생성 후 변경하지 않으므로 동시 읽기에 안전)
+type TrustedNetworks struct {
+	entries []trustedNetwork
+}
+
+// ParseTrustedNetworks CIDR/IP 목록을 신뢰 네트워크로 파싱
+// 이름이 없으면 CIDR 문자열을 이름으로 사용
+func ParseTrustedNetworks(specs []string) (*TrustedNetworks, error) {
+	trusted := &TrustedNetworks{}
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		name, cidr := "", spec
+		if idx := strings.Index(spec, "="); idx >= 0 {
+			name, cidr = strings.TrimSpace(spec[:idx]), strings.TrimSpace(spec[idx+1:])
+		}
+
+		// 단일 IP는 /32 (IPv6 /128) 네트워크로 처리
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted network %q: not an IP or CIDR", spec)
+			}
+			if ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted network %q: %v", spec, err)
+		}
+		if name == "" {
+			name = network.String()
+		}
+		trusted.entries = append(trusted.entries, trustedNetwork{name: name, network: network})
+	}
+	return trusted, nil
+}
+
+// Match IP가 속한 첫 번째 신뢰 네트워크 이름 반환
+func (tn *TrustedNetworks) Match(ip string) (string, bool) {
+	if tn == nil {
+		return "", false
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", false
+	}
+	for _, entry := range tn.entries {
+		if entry.network.Contains(parsed) {
+			return entry.name, true
+		}
+	}
+	return "", false
+}
+
+// Len 신뢰 네트워크 수
+func (tn *TrustedNetworks) Len() int {
+	if tn == nil {
+		return 0
+	}
+	return len(tn.entries)
+}
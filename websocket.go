@@ -0,0 +1,201 @@
+/*
+WebSocket Module
+================
+
+웹 대시보드 실시간 로그 스트림용 최소 WebSocket 서버 구현 (RFC 6455)
+
+주요 기능:
+- HTTP 연결을 WebSocket 으로 업그레이드 (Sec-WebSocket-Accept 핸드셰이크)
+- 서버 → 클라이언트 텍스트 프레임 전송
+- 클라이언트 ping 응답, close 처리 (클라이언트가 보낸 데이터 프레임은 무시)
+- 다른 출처(Origin) 페이지의 연결 거부 (로컬 대시보드 로그 탈취 방지)
+*/
+package main
+
+import (
+	"bufio"           // 프레임 읽기/쓰기 버퍼
+	"crypto/sha1"     // 핸드셰이크 응답 키
+	"encoding/base64" // 핸드셰이크 응답 키 인코딩
+	"encoding/binary" // 프레임 길이 인코딩
+	"fmt"             // 형식화된 I/O
+	"io"              // 프레임 본문 읽기
+	"net"             // 하이재킹한 연결
+	"net/http"        // 업그레이드 요청
+	"net/url"         // Origin 검사
+	"strings"         // 헤더 토큰 검사
+	"sync"            // 쓰기 직렬화
+	"time"            // 쓰기 타임아웃
+)
+
+// WebSocket 설정
+const (
+	websocketGUID         = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11" // RFC 6455 핸드셰이크 상수
+	WebSocketWriteTimeout = 10 * time.Second                       // 프레임 전송 타임아웃 (느린 클라이언트 차단)
+	WebSocketMaxFrameSize = 64 << 10                               // 클라이언트 프레임 최대 크기 (64KB)
+)
+
+// WebSocket 프레임 opcode
+const (
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+	wsOpPing  = 0x9
+	wsOpPong  = 0xA
+)
+
+// wsConn 서버 측 WebSocket 연결
+type wsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	mutex  sync.Mutex // 프레임 쓰기 직렬화 (전송 고루틴과 pong 응답)
+}
+
+// upgradeWebSocket HTTP 요청을 WebSocket 연결로 업그레이드
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if !headerContainsToken(r.Header, "Connection", "upgrade") || !headerContainsToken(r.Header, "Upgrade", "websocket") {
+		return nil, fmt.Errorf("not a websocket upgrade request")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		return nil, fmt.Errorf("unsupported websocket version %q", r.Header.Get("Sec-WebSocket-Version"))
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, fmt.Errorf("missing Sec-WebSocket-Key")
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		parsed, err := url.Parse(origin)
+		if err != nil || !strings.EqualFold(parsed.Host, r.Host) {
+			return nil, fmt.Errorf("cross-origin websocket request from %q", origin)
+		}
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, fmt.Errorf("connection does not support hijacking")
+	}
+	conn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to hijack connection: %v", err)
+	}
+
+	hash := sha1.Sum([]byte(key + websocketGUID))
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(hash[:]) + "\r\n\r\n"
+	conn.SetWriteDeadline(time.Now().Add(WebSocketWriteTimeout))
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to write handshake: %v", err)
+	}
+	return &wsConn{conn: conn, reader: buffered.Reader}, nil
+}
+
+// headerContainsToken 쉼표로 구분된 헤더 값에 토큰이 있는지 확인 (대소문자 무시)
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WriteText 텍스트 프레임 전송
+func (c *wsConn) WriteText(data []byte) error {
+	return c.writeFrame(wsOpText, data)
+}
+
+// writeFrame 마스킹 없는 단일 프레임 전송 (서버 → 클라이언트)
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	header := []byte{0x80 | opcode}
+	switch length := len(payload); {
+	case length < 126:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(WebSocketWriteTimeout))
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return fmt.Errorf("failed to write websocket frame: %v", err)
+	}
+	return nil
+}
+
+// ReadLoop 클라이언트 프레임을 읽어 ping/close 처리, 연결이 끝나면 반환
+func (c *wsConn) ReadLoop() error {
+	for {
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			return err
+		}
+		switch opcode {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return err
+			}
+		case wsOpClose:
+			c.writeFrame(wsOpClose, payload)
+			return nil
+		}
+	}
+}
+
+// readFrame 클라이언트 프레임 하나 읽기 (클라이언트 프레임은 항상 마스킹됨)
+func (c *wsConn) readFrame() (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.reader, head[:]); err != nil {
+		return 0, nil, err
+	}
+	opcode := head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if !masked {
+		return 0, nil, fmt.Errorf("unmasked client frame")
+	}
+	if length > WebSocketMaxFrameSize {
+		return 0, nil, fmt.Errorf("websocket frame too large (%d bytes)", length)
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return opcode, payload, nil
+}
+
+// Close 연결 종료
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
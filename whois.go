@@ -0,0 +1,390 @@
+/*
+WHOIS Abuse Contact Module
+==========================
+
+공격 출발지 IP 의 WHOIS abuse 연락처 조회 (syslog-monitor whois, -whois-abuse)
+
+RIR(ARIN, RIPE NCC, APNIC, LACNIC, AFRINIC) 의 RDAP 서비스(WHOIS 의 JSON 후속 규격)에서
+IP 가 속한 네트워크와 "abuse" 역할 연락처를 찾아 신고 메일 주소를 알려줍니다.
+
+주요 기능:
+- rdap.org 부트스트랩으로 IP 를 담당하는 RIR RDAP 서버로 자동 이동
+- 네트워크 이름/핸들/대역/국가, 조직, abuse 이메일/전화 추출 (중첩된 엔티티 포함)
+- 조회 결과 메모리 캐시 (성공 24시간, 실패 1시간), 같은 IP 의 동시 조회는 하나로 합침
+- 사설/루프백 IP 는 조회하지 않음
+- 하위 명령: syslog-monitor whois [-json] <ip> [ip...]
+*/
+package main
+
+import (
+	"context"       // 조회 시간 제한
+	"encoding/json" // RDAP 응답 파싱
+	"flag"          // 하위 명령 옵션
+	"fmt"           // 형식화된 I/O
+	"io"            // 응답 본문 읽기
+	"net"           // IP 파싱
+	"net/http"      // RDAP 요청
+	"net/url"       // 요청 경로 인코딩
+	"os"            // 종료 코드
+	"strings"       // 문자열 처리
+	"sync"          // 동기화
+	"time"          // 시간 처리
+)
+
+// WHOIS 조회 설정
+const (
+	DefaultWhoisTimeout     = 5000                   // 조회 시간 제한 기본값 (밀리초)
+	DefaultRDAPBootstrapURL = "https://rdap.org/ip/" // IP 를 담당하는 RIR RDAP 서버로 리다이렉트
+	whoisCacheTTL           = 24 * time.Hour
+	whoisNegativeTTL        = time.Hour // 조회 실패 결과 유지 시간
+	whoisMaxCacheEntries    = 5000      // 캐시 항목 수가 이를 넘으면 만료 항목 정리
+)
+
+// AbuseContact IP 가 속한 네트워크와 abuse 연락처
+type AbuseContact struct {
+	IP           string   `json:"ip"`
+	Network      string   `json:"network,omitempty"`      // 네트워크 이름 (예: GOOGLE)
+	Handle       string   `json:"handle,omitempty"`       // 네트워크 핸들 (예: NET-8-8-8-0-2)
+	Range        string   `json:"range,omitempty"`        // CIDR 또는 시작-끝 주소
+	Country      string   `json:"country,omitempty"`      // 할당 국가 코드
+	Organization string   `json:"organization,omitempty"` // 등록 조직 이름
+	Emails       []string `json:"abuse_emails,omitempty"` // abuse 신고 이메일
+	Phones       []string `json:"abuse_phones,omitempty"` // abuse 신고 전화
+	Registry     string   `json:"registry,omitempty"`     // 응답한 RDAP 서버 호스트
+}
+
+// Email 대표 abuse 이메일 (없으면 빈 문자열)
+func (c *AbuseContact) Email() string {
+	if c == nil || len(c.Emails) == 0 {
+		return ""
+	}
+	return c.Emails[0]
+}
+
+// whoisEntry 조회 캐시 항목 (contact 가 nil 이면 실패)
+type whoisEntry struct {
+	contact *AbuseContact
+	at      time.Time
+}
+
+// WhoisLookup 캐시/비동기 abuse 연락처 조회기 (nil 이면 조회하지 않음)
+type WhoisLookup struct {
+	baseURL string
+	client  *http.Client
+	cache   map[string]*whoisEntry
+	pending map[string][]func(*AbuseContact) // 조회 중인 IP → 조회 후 실행할 콜백
+	lookups sync.WaitGroup                   // 진행 중인 조회
+	mutex   sync.Mutex
+}
+
+// NewWhoisLookup 새로운 abuse 연락처 조회기 생성 (timeout 이 0 이하면 기본값)
+func NewWhoisLookup(timeout time.Duration) *WhoisLookup {
+	if timeout <= 0 {
+		timeout = DefaultWhoisTimeout * time.Millisecond
+	}
+	return &WhoisLookup{
+		baseURL: DefaultRDAPBootstrapURL,
+		client:  &http.Client{Timeout: timeout},
+		cache:   make(map[string]*whoisEntry),
+		pending: make(map[string][]func(*AbuseContact)),
+	}
+}
+
+// Enabled abuse 연락처 조회가 활성화되어 있는지 확인
+func (wl *WhoisLookup) Enabled() bool {
+	return wl != nil
+}
+
+// LookupAsync 캐시에 없으면 조회 고루틴에서 결과를 캐시에 저장한 뒤 done 을 실행하고 true 반환
+// 비활성화되었거나 사설 IP 이거나 캐시에 있으면 호출한 고루틴에서 즉시 done 을 실행하고 false 반환 (실패 시 nil)
+func (wl *WhoisLookup) LookupAsync(ip string, done func(*AbuseContact)) bool {
+	if wl == nil || !whoisLookupable(ip) {
+		done(nil)
+		return false
+	}
+
+	wl.mutex.Lock()
+	if entry := wl.cache[ip]; entry != nil && wl.fresh(entry, time.Now()) {
+		wl.mutex.Unlock()
+		done(entry.contact)
+		return false
+	}
+	waiting, inflight := wl.pending[ip]
+	wl.pending[ip] = append(waiting, done)
+	wl.mutex.Unlock()
+	if inflight {
+		return true
+	}
+
+	wl.lookups.Add(1)
+	go func() {
+		defer wl.lookups.Done()
+		contact, err := wl.Lookup(context.Background(), ip)
+		if err != nil {
+			contact = nil
+		}
+
+		wl.mutex.Lock()
+		wl.cache[ip] = &whoisEntry{contact: contact, at: time.Now()}
+		if len(wl.cache) > whoisMaxCacheEntries {
+			now := time.Now()
+			for cachedIP, cached := range wl.cache {
+				if !wl.fresh(cached, now) {
+					delete(wl.cache, cachedIP)
+				}
+			}
+		}
+		callbacks := wl.pending[ip]
+		delete(wl.pending, ip)
+		wl.mutex.Unlock()
+		for _, callback := range callbacks {
+			callback(contact)
+		}
+	}()
+	return true
+}
+
+// Wait 진행 중인 조회(콜백 포함)가 끝날 때까지 대기
+func (wl *WhoisLookup) Wait() {
+	if wl != nil {
+		wl.lookups.Wait()
+	}
+}
+
+// fresh 캐시 항목이 아직 유효한지 확인 (mutex 보유 상태에서 호출)
+func (wl *WhoisLookup) fresh(entry *whoisEntry, now time.Time) bool {
+	ttl := whoisCacheTTL
+	if entry.contact == nil {
+		ttl = whoisNegativeTTL
+	}
+	return now.Sub(entry.at) < ttl
+}
+
+// whoisLookupable 공인 IP 인지 확인 (사설/루프백/링크 로컬/멀티캐스트는 조회하지 않음)
+func whoisLookupable(ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	return !(addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified())
+}
+
+// rdapEntity RDAP 엔티티 (연락처)
+type rdapEntity struct {
+	Handle     string          `json:"handle"`
+	Roles      []string        `json:"roles"`
+	VCardArray json.RawMessage `json:"vcardArray"`
+	Entities   []rdapEntity    `json:"entities"`
+}
+
+// rdapNetwork RDAP IP 네트워크 응답
+type rdapNetwork struct {
+	Handle       string       `json:"handle"`
+	Name         string       `json:"name"`
+	StartAddress string       `json:"startAddress"`
+	EndAddress   string       `json:"endAddress"`
+	Country      string       `json:"country"`
+	Entities     []rdapEntity `json:"entities"`
+	CIDRs        []struct {
+		V4Prefix string `json:"v4prefix"`
+		V6Prefix string `json:"v6prefix"`
+		Length   int    `json:"length"`
+	} `json:"cidr0_cidrs"`
+	Title string `json:"title"`
+}
+
+// Lookup RDAP 서버에서 IP 의 네트워크와 abuse 연락처 조회 (캐시 사용 안 함)
+func (wl *WhoisLookup) Lookup(ctx context.Context, ip string) (*AbuseContact, error) {
+	if net.ParseIP(ip) == nil {
+		return nil, fmt.Errorf("invalid IP address: %s", ip)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wl.baseURL+url.PathEscape(ip), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+	req.Header.Set("User-Agent", "syslog-monitor/"+AppVersion)
+
+	resp, err := wl.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rdap lookup failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+	if err != nil {
+		return nil, fmt.Errorf("rdap lookup failed: %v", err)
+	}
+
+	var network rdapNetwork
+	if err := json.Unmarshal(body, &network); err != nil {
+		return nil, fmt.Errorf("rdap server returned status %d: %v", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if network.Title != "" {
+			return nil, fmt.Errorf("rdap server returned status %d: %s", resp.StatusCode, network.Title)
+		}
+		return nil, fmt.Errorf("rdap server returned status %d", resp.StatusCode)
+	}
+
+	contact := &AbuseContact{
+		IP:      ip,
+		Network: network.Name,
+		Handle:  network.Handle,
+		Country: network.Country,
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		contact.Registry = resp.Request.URL.Host
+	}
+	var ranges []string
+	for _, cidr := range network.CIDRs {
+		prefix := cidr.V4Prefix
+		if prefix == "" {
+			prefix = cidr.V6Prefix
+		}
+		if prefix != "" {
+			ranges = append(ranges, fmt.Sprintf("%s/%d", prefix, cidr.Length))
+		}
+	}
+	if len(ranges) > 0 {
+		contact.Range = strings.Join(ranges, ", ")
+	} else if network.StartAddress != "" {
+		contact.Range = network.StartAddress + " - " + network.EndAddress
+	}
+	collectRDAPContacts(contact, network.Entities)
+	return contact, nil
+}
+
+// collectRDAPContacts 엔티티 트리에서 abuse 이메일/전화와 등록 조직 이름 수집
+func collectRDAPContacts(contact *AbuseContact, entities []rdapEntity) {
+	for _, entity := range entities {
+		name, emails, phones := parseVCard(entity.VCardArray)
+		for _, role := range entity.Roles {
+			switch strings.ToLower(role) {
+			case "abuse":
+				for _, email := range emails {
+					if !containsString(contact.Emails, email) {
+						contact.Emails = append(contact.Emails, email)
+					}
+				}
+				for _, phone := range phones {
+					if !containsString(contact.Phones, phone) {
+						contact.Phones = append(contact.Phones, phone)
+					}
+				}
+			case "registrant":
+				if contact.Organization == "" {
+					contact.Organization = name
+				}
+			}
+		}
+		collectRDAPContacts(contact, entity.Entities)
+	}
+}
+
+// parseVCard jCard(RFC 7095) 에서 이름(fn), 이메일, 전화 추출
+// 형식: ["vcard", [["fn", {}, "text", "이름"], ["email", {}, "text", "abuse@example.net"], ...]]
+func parseVCard(raw json.RawMessage) (name string, emails, phones []string) {
+	var vcard []json.RawMessage
+	if len(raw) == 0 || json.Unmarshal(raw, &vcard) != nil || len(vcard) < 2 {
+		return "", nil, nil
+	}
+	var properties [][]json.RawMessage
+	if json.Unmarshal(vcard[1], &properties) != nil {
+		return "", nil, nil
+	}
+	for _, property := range properties {
+		if len(property) < 4 {
+			continue
+		}
+		var key, value string
+		if json.Unmarshal(property[0], &key) != nil || json.Unmarshal(property[3], &value) != nil {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "fn":
+			name = value
+		case "email":
+			emails = append(emails, strings.TrimPrefix(value, "mailto:"))
+		case "tel":
+			phones = append(phones, strings.TrimPrefix(value, "tel:"))
+		}
+	}
+	return name, emails, phones
+}
+
+// runWhoisCommand whois 하위 명령 (syslog-monitor whois [-json] <ip> [ip...])
+func runWhoisCommand(args []string) {
+	fs := flag.NewFlagSet("whois", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Print results as JSON lines")
+	timeout := fs.Int("timeout", DefaultWhoisTimeout, "Milliseconds allowed for each RDAP lookup")
+	fs.Usage = func() {
+		fmt.Println("Usage:")
+		fmt.Println("  syslog-monitor whois [options] <ip> [ip...]")
+		fmt.Println()
+		fmt.Println("Looks up the network and abuse contact of each IP in the registry RDAP service.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  syslog-monitor whois 203.0.113.45")
+		fmt.Println("  syslog-monitor whois -json 203.0.113.45 198.51.100.7")
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	lookup := NewWhoisLookup(time.Duration(*timeout) * time.Millisecond)
+	failed := false
+	for _, ip := range fs.Args() {
+		if !whoisLookupable(ip) {
+			fmt.Fprintf(os.Stderr, "❌ %s: not a public IP address\n", ip)
+			failed = true
+			continue
+		}
+		contact, err := lookup.Lookup(context.Background(), ip)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", ip, err)
+			failed = true
+			continue
+		}
+		if *jsonOutput {
+			data, _ := json.Marshal(contact)
+			fmt.Println(string(data))
+			continue
+		}
+		printAbuseContact(contact)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// printAbuseContact 조회 결과를 사람이 읽기 쉬운 형식으로 출력
+func printAbuseContact(contact *AbuseContact) {
+	fmt.Printf("IP:            %s\n", contact.IP)
+	for _, line := range [][2]string{
+		{"Network:", strings.TrimSpace(contact.Network + " " + contact.Handle)},
+		{"Range:", contact.Range},
+		{"Country:", contact.Country},
+		{"Organization:", contact.Organization},
+		{"Abuse email:", strings.Join(contact.Emails, ", ")},
+		{"Abuse phone:", strings.Join(contact.Phones, ", ")},
+		{"Registry:", contact.Registry},
+	} {
+		if line[1] != "" {
+			fmt.Printf("%-14s %s\n", line[0], line[1])
+		}
+	}
+	if len(contact.Emails) == 0 {
+		fmt.Println("⚠️  No abuse email registered for this network")
+	}
+	fmt.Println()
+}